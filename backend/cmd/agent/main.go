@@ -0,0 +1,70 @@
+// 工作节点：在 GPU 机器上运行，向后端登记后领取任务并在本机运行 main.py
+//
+// 后端需配置 worker.executor=agent，并开启 worker.grpc_enabled（grpc_addr 监听工作节点可访问的地址）。
+// 工作节点机器上需要完整的项目目录（main.py 及其依赖），内部密钥通过 INTERNAL_API_KEY 环境变量与后端保持一致（不能使用默认密钥）。
+// 后端配置了 grpc_tls_cert/grpc_tls_key 时通过 -tls-ca 指定校验后端证书的 CA 证书。
+//
+//	./agent -server 10.0.0.1:50051 -root /opt/instruct_data_generate -capacity 2 -tls-ca /etc/gen/ca.pem
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"gen-go/internal/agent"
+	"gen-go/internal/utils"
+)
+
+func main() {
+	hostname, _ := os.Hostname()
+
+	server := flag.String("server", "127.0.0.1:50051", "后端工作进程 gRPC 地址（worker.grpc_addr）")
+	root := flag.String("root", ".", "main.py 所在的项目根目录")
+	python := flag.String("python", "python3", "Python 可执行文件")
+	id := flag.String("id", fmt.Sprintf("%s-%d", hostname, os.Getpid()), "工作节点标识")
+	capacity := flag.Int("capacity", 1, "可同时运行的任务数")
+	stopGrace := flag.Duration("stop-grace", 10*time.Second, "停止任务时 SIGTERM 后等待进程退出的时间")
+	useTLS := flag.Bool("tls", false, "使用 TLS 连接后端（指定 -tls-ca 时自动开启）")
+	tlsCA := flag.String("tls-ca", "", "校验后端证书的 CA 证书文件，为空时使用系统根证书")
+	tlsServerName := flag.String("tls-server-name", "", "校验后端证书时使用的服务器名，为空时使用 -server 的主机名")
+	flag.Parse()
+
+	// 工作节点通过网络连接后端，内部密钥是唯一的认证手段
+	if utils.IsDefaultInternalAPIKey() {
+		log.Fatalf("未设置 INTERNAL_API_KEY 环境变量（或仍为默认值），请设置为与后端一致的内部密钥")
+	}
+	if !*useTLS && *tlsCA == "" {
+		log.Printf("警告: 未开启 TLS，内部密钥和任务数据将以明文传输")
+	}
+
+	a, err := agent.New(agent.Options{
+		Server:      *server,
+		ProjectRoot: *root,
+		Python:      *python,
+		AgentID:     *id,
+		Hostname:    hostname,
+		Capacity:    *capacity,
+		StopGrace:   *stopGrace,
+		InternalKey: utils.InternalAPIKey(),
+		TLS:         *useTLS || *tlsCA != "",
+		TLSCA:       *tlsCA,
+		TLSServer:   *tlsServerName,
+	})
+	if err != nil {
+		log.Fatalf("创建工作节点失败: %v", err)
+	}
+
+	// 收到 SIGINT/SIGTERM 后停止领取任务，终止运行中的任务并等待其上报结束
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := a.Run(ctx); err != nil {
+		log.Fatalf("工作节点退出: %v", err)
+	}
+}
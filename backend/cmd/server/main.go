@@ -1,21 +1,49 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"gen-go/internal/config"
 	"gen-go/internal/models"
+	"gen-go/internal/plugin"
 	"gen-go/internal/repository"
 	"gen-go/internal/router"
 	"gen-go/internal/service"
+	"gen-go/internal/tracing"
 	"gen-go/internal/utils"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
+// @title 数据生成任务管理系统 API
+// @version 1.0
+// @description 数据生成任务、数据文件、生成数据审核与报告等接口。统一响应格式为 {code, message, data}。
+// @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description 访问 Token，格式为 "Bearer <access_token>"
+// @securityDefinitions.apikey ReviewToken
+// @in header
+// @name Authorization
+// @description 审阅链接 Token，格式为 "Bearer <token>"（也可通过查询参数 token 传递）
+// @securityDefinitions.apikey InternalAPIKey
+// @in header
+// @name X-Internal-API-Key
+// @description 内部接口密钥（仅供 Python 工作进程调用）
 func main() {
+	// -restore：从备份恢复数据库后退出（需先停止服务），参数为备份文件路径或 backup.dir 下的备份文件名
+	restore := flag.String("restore", "", "从备份恢复数据库后退出（需先停止服务）")
+	flag.Parse()
+
 	// 加载配置（从项目根目录读取）
 	// 注意：start.sh 从项目根目录启动后端，所以使用相对路径 ./config/config.yaml
 	cfg, err := config.LoadConfig("./config/config.yaml")
@@ -23,12 +51,40 @@ func main() {
 		log.Fatalf("加载配置失败: %v", err)
 	}
 
+	if *restore != "" {
+		if err := service.RestoreBackup(cfg, *restore); err != nil {
+			log.Fatalf("恢复数据库失败: %v", err)
+		}
+		return
+	}
+
 	// 初始化日志
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 	logger.SetOutput(os.Stdout)
 	logger.SetLevel(logrus.InfoLevel)
 
+	// 初始化链路追踪（需在数据库和路由之前，以便注册追踪插件和中间件）
+	shutdownTracing, err := tracing.Init(&cfg.Tracing)
+	if err != nil {
+		log.Fatalf("初始化链路追踪失败: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Printf("导出剩余链路追踪数据失败: %v", err)
+		}
+	}()
+	if cfg.Tracing.Enabled {
+		logger.Infof("链路追踪已开启: OTLP %s", cfg.Tracing.Endpoint)
+	}
+
+	// 注册配置中声明的 exec/webhook 插件（Go 插件已在 init 中注册）
+	if err := plugin.RegisterConfigured(cfg.Plugins); err != nil {
+		log.Fatalf("注册插件失败: %v", err)
+	}
+
 	// 初始化数据库
 	if err := models.InitDB(cfg); err != nil {
 		log.Fatalf("初始化数据库失败: %v", err)
@@ -42,11 +98,14 @@ func main() {
 		Password: cfg.Redis.Password,
 	})
 
+	// 启动自检
+	runPreflight(cfg, logger, db, redisClient)
+
 	// 初始化Repository
 	userRepo := repository.NewUserRepository(db)
 	taskRepo := repository.NewTaskRepository(db)
 	fileRepo := repository.NewDataFileRepository(db)
-	_ = repository.NewGeneratedDataRepository(db)
+	generatedDataRepo := repository.NewGeneratedDataRepository(db)
 	modelRepo := repository.NewModelConfigRepository(db)
 
 	// 初始化工具
@@ -55,20 +114,30 @@ func main() {
 		cfg.JWT.Algorithm,
 		cfg.JWT.GetExpireDuration(),
 	)
+	// 密钥存储中的 JWT 密钥轮换后立即用于签发新Token
+	config.OnReload(func(cfg *config.Config) {
+		jwtManager.SetSecretKey(cfg.JWT.SecretKey)
+	})
+	config.StartSecretsRefresh()
 
 	// 初始化Service
-	authService := service.NewAuthService(userRepo, jwtManager, cfg)
+	authService := service.NewAuthService(userRepo, jwtManager, service.NewTokenService(redisClient, cfg), cfg)
 
 	// 初始化管理员账户
 	if err := authService.InitAdmin(); err != nil {
 		logger.Warnf("初始化管理员失败: %v", err)
 	}
 
-	_ = service.NewTaskManager(taskRepo, userRepo, fileRepo, modelRepo, redisClient, cfg)
+	fileJobPool := service.NewJobPool("file_jobs", &cfg.JobPool)
+	modelService := service.NewModelService(modelRepo, repository.NewModelTokenUsageRepository(db), redisClient, service.NewErrorTracker(), cfg)
+	_ = service.NewTaskManager(taskRepo, userRepo, fileRepo, generatedDataRepo, modelRepo, repository.NewPromptRepository(db), repository.NewTaskTypeRepository(db), service.NewFileVersionService(repository.NewDataFileVersionRepository(db), fileRepo), service.NewDedupService(generatedDataRepo, fileRepo, fileJobPool), service.NewGlossaryService(repository.NewGlossaryRepository(db), taskRepo, generatedDataRepo, fileJobPool), service.NewTaggingService(repository.NewTagRuleRepository(db), generatedDataRepo, taskRepo, modelRepo, modelService, fileJobPool), service.NewScoringService(repository.NewScoringRuleRepository(db), repository.NewTaskTypeRepository(db), generatedDataRepo, taskRepo, fileJobPool), service.NewJudgeService(generatedDataRepo, taskRepo, modelRepo, modelService, fileJobPool), service.NewSafetyService(generatedDataRepo, taskRepo, modelRepo, modelService, fileJobPool, cfg), service.NewEmbeddingService(repository.NewEmbeddingRepository(db), fileRepo, generatedDataRepo, taskRepo, modelService, fileJobPool), service.NewWebhookService(repository.NewWebhookRepository(db), taskRepo, cfg), service.NewNotificationService(repository.NewNotificationSubscriptionRepository(db), repository.NewNotificationRepository(db), taskRepo, fileRepo, generatedDataRepo, userRepo, cfg), repository.NewUserSettingsRepository(db), redisClient, cfg)
 
 	// 设置路由
 	r := router.SetupRouter(cfg, jwtManager, logger, db, redisClient)
 
+	// SIGHUP：重新读取配置文件，可热加载的配置立即生效（与 POST /api/admin/config/reload 相同）
+	go watchReloadSignal(logger)
+
 	// 启动服务器
 	addr := cfg.Server.GetAddress()
 	logger.Infof("服务器启动在 %s", addr)
@@ -76,12 +145,56 @@ func main() {
 	if cfg.Server.ProductionMode {
 		logger.Info("生产模式: API文档已禁用")
 	} else {
-		logger.Infof("开发模式: API文档已启用")
-		logger.Infof("管理员账号: %s", cfg.Admin.Username)
-		logger.Infof("管理员密码: %s", cfg.Admin.Password)
+		logger.Infof("开发模式: API文档已启用: http://%s/swagger/index.html（OpenAPI 描述: /openapi.json）", addr)
+		logger.Infof("管理员账号: %s（初始密码见配置文件，首次登录后需修改）", cfg.Admin.Username)
 	}
 
 	if err := r.Run(addr); err != nil {
 		log.Fatalf("启动服务器失败: %v", err)
 	}
 }
+
+// watchReloadSignal 收到 SIGHUP 时重新加载配置
+func watchReloadSignal(logger *logrus.Logger) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	for range signals {
+		result, err := config.ReloadConfig()
+		if err != nil {
+			logger.Errorf("重新加载配置失败: %v", err)
+			continue
+		}
+		logger.Infof("配置已重新加载: 已生效 %v, 需重启生效 %v", result.Applied, result.RequiresRestart)
+	}
+}
+
+// runPreflight 执行启动自检并输出结构化报告，生产模式下开启 preflight_strict 时关键项失败则拒绝启动
+func runPreflight(cfg *config.Config, logger *logrus.Logger, db *gorm.DB, redisClient *redis.Client) {
+	report := service.RunPreflight(cfg, db, redisClient)
+	for _, check := range report.Checks {
+		entry := logger.WithFields(logrus.Fields{
+			"preflight":   check.Name,
+			"status":      check.Status,
+			"hard":        check.Hard,
+			"duration_ms": check.Duration,
+		})
+		switch check.Status {
+		case service.PreflightStatusOK:
+			entry.Info(check.Message)
+		case service.PreflightStatusWarn:
+			entry.Warn(check.Message)
+		default:
+			entry.Error(check.Message)
+		}
+	}
+
+	failures := report.HardFailures()
+	if len(failures) == 0 {
+		logger.Infof("启动自检通过: 共 %d 项", len(report.Checks))
+		return
+	}
+	if cfg.Server.ProductionMode && cfg.Server.PreflightStrict {
+		log.Fatalf("启动自检失败: %d 项关键检查未通过，生产模式下拒绝启动", len(failures))
+	}
+	logger.Warnf("启动自检: %d 项关键检查未通过", len(failures))
+}
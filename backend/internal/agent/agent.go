@@ -0,0 +1,383 @@
+// Package agent 工作节点：向后端登记后轮询领取任务，在本机运行 main.py 并将输出和退出码转发回后端
+// 后端需配置 worker.executor=agent 并开启 worker.grpc_enabled；main.py 通过 --grpc-addr 直接连接后端提交数据
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"gen-go/internal/procgroup"
+	"gen-go/internal/workerrpc"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// outputBatchSize 每次转发的最大输出行数
+	outputBatchSize = 100
+	// outputFlushInterval 输出未攒满一批时的转发间隔
+	outputFlushInterval = 500 * time.Millisecond
+	// rpcTimeout 单次调用后端的超时时间
+	rpcTimeout = 30 * time.Second
+)
+
+// Options 工作节点配置
+type Options struct {
+	Server      string        // 后端工作进程 gRPC 地址（worker.grpc_addr）
+	ProjectRoot string        // main.py 所在的项目根目录
+	Python      string        // Python 可执行文件
+	AgentID     string        // 工作节点标识
+	Hostname    string        // 主机名（展示用）
+	Capacity    int           // 可同时运行的任务数
+	StopGrace   time.Duration // 停止任务时 SIGTERM 后等待退出的时间
+	InternalKey string        // 内部API密钥
+	TLS         bool          // 使用 TLS 连接后端（后端配置了 grpc_tls_cert/grpc_tls_key）
+	TLSCA       string        // 校验后端证书的 CA 证书文件，为空时使用系统根证书
+	TLSServer   string        // 校验后端证书时使用的服务器名，为空时按 Server 的主机名校验
+}
+
+// Agent 工作节点
+type Agent struct {
+	opts         Options
+	client       *workerrpc.Client
+	pollInterval time.Duration
+
+	mu      sync.Mutex
+	running map[string]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// handshake main.py --version 输出的握手信息
+type handshake struct {
+	Type            string `json:"type"`
+	WorkerVersion   string `json:"worker_version"`
+	ProtocolVersion int    `json:"protocol_version"`
+}
+
+// New 创建工作节点并连接后端
+func New(opts Options) (*Agent, error) {
+	if opts.Capacity <= 0 {
+		opts.Capacity = 1
+	}
+	creds := workerrpc.InsecureCredentials()
+	if opts.TLS {
+		var err error
+		if creds, err = workerrpc.ClientTLS(opts.TLSCA, opts.TLSServer); err != nil {
+			return nil, err
+		}
+	}
+	client, err := workerrpc.Dial(opts.Server, opts.InternalKey, creds)
+	if err != nil {
+		return nil, fmt.Errorf("连接后端 %s 失败: %w", opts.Server, err)
+	}
+	return &Agent{
+		opts:         opts,
+		client:       client,
+		pollInterval: 2 * time.Second,
+		running:      make(map[string]context.CancelFunc),
+	}, nil
+}
+
+// Run 登记并轮询领取任务，直到 ctx 取消；取消后终止运行中的任务并等待其上报结束
+func (a *Agent) Run(ctx context.Context) error {
+	defer a.client.Close()
+
+	version, err := a.probe(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("[Agent] main.py 版本 %s，协议 v%d", version.WorkerVersion, version.ProtocolVersion)
+
+	if err := a.register(ctx, version); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(a.pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := a.poll(ctx, version); err != nil {
+			log.Printf("[Agent] 领取任务失败: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("[Agent] 正在退出，等待运行中的任务结束")
+			a.stopAll()
+			a.wg.Wait()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// probe 执行 main.py --version 获取工作进程版本
+func (a *Agent) probe(ctx context.Context) (*handshake, error) {
+	ctx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, a.opts.Python, "main.py", "--version")
+	cmd.Dir = a.opts.ProjectRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("执行 main.py --version 失败: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		var result handshake
+		if json.Unmarshal(scanner.Bytes(), &result) == nil && result.Type == "handshake" {
+			return &result, nil
+		}
+	}
+	return nil, fmt.Errorf("main.py --version 未输出握手信息")
+}
+
+// register 向后端登记，后端不可用时按轮询间隔重试
+func (a *Agent) register(ctx context.Context, version *handshake) error {
+	for {
+		callCtx, cancel := context.WithTimeout(ctx, rpcTimeout)
+		resp, err := a.client.RegisterAgent(callCtx, &workerrpc.RegisterAgentRequest{
+			AgentID:         a.opts.AgentID,
+			Hostname:        a.opts.Hostname,
+			WorkerVersion:   version.WorkerVersion,
+			ProtocolVersion: version.ProtocolVersion,
+			Capacity:        a.opts.Capacity,
+		})
+		cancel()
+
+		switch {
+		case err == nil && !resp.Accepted:
+			return fmt.Errorf("后端拒绝登记: %s", resp.Message)
+		case err == nil:
+			if resp.PollSeconds > 0 {
+				a.pollInterval = time.Duration(resp.PollSeconds) * time.Second
+			}
+			log.Printf("[Agent] 已登记到 %s（工作节点 %s，并发 %d，轮询间隔 %v）", a.opts.Server, a.opts.AgentID, a.opts.Capacity, a.pollInterval)
+			return nil
+		case status.Code(err) == codes.FailedPrecondition || status.Code(err) == codes.Unauthenticated:
+			return fmt.Errorf("登记失败: %w", err)
+		}
+
+		log.Printf("[Agent] 登记失败，%v 后重试: %v", a.pollInterval, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(a.pollInterval):
+		}
+	}
+}
+
+// poll 上报运行中的任务并领取新任务；后端不认识该工作节点（如后端重启）时重新登记
+func (a *Agent) poll(ctx context.Context, version *handshake) error {
+	callCtx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	resp, err := a.client.PullTask(callCtx, &workerrpc.PullTaskRequest{
+		AgentID: a.opts.AgentID,
+		Running: a.runningTasks(),
+	})
+	cancel()
+	if status.Code(err) == codes.NotFound {
+		log.Printf("[Agent] 后端未找到工作节点登记信息，重新登记")
+		return a.register(ctx, version)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, taskID := range resp.Stop {
+		a.stop(taskID)
+	}
+	if resp.Task != nil && ctx.Err() == nil {
+		a.start(resp.Task)
+	}
+	return nil
+}
+
+func (a *Agent) runningTasks() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	taskIDs := make([]string, 0, len(a.running))
+	for taskID := range a.running {
+		taskIDs = append(taskIDs, taskID)
+	}
+	return taskIDs
+}
+
+// stop 终止运行中的任务（向进程组发送 SIGTERM）
+func (a *Agent) stop(taskID string) {
+	a.mu.Lock()
+	cancel, ok := a.running[taskID]
+	a.mu.Unlock()
+	if ok {
+		log.Printf("[Agent] 停止任务 %s", taskID)
+		cancel()
+	}
+}
+
+func (a *Agent) stopAll() {
+	for _, taskID := range a.runningTasks() {
+		a.stop(taskID)
+	}
+}
+
+// start 在后台运行分配的任务
+func (a *Agent) start(task *workerrpc.TaskAssignment) {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.mu.Lock()
+	a.running[task.TaskID] = cancel
+	a.mu.Unlock()
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		defer func() {
+			a.mu.Lock()
+			delete(a.running, task.TaskID)
+			a.mu.Unlock()
+			cancel()
+		}()
+		a.runTask(ctx, cancel, task)
+	}()
+}
+
+// runTask 运行 main.py，转发输出，结束后上报退出码
+func (a *Agent) runTask(ctx context.Context, cancel context.CancelFunc, task *workerrpc.TaskAssignment) {
+	log.Printf("[Agent] 开始运行任务 %s", task.TaskID)
+	returnCode, runErr := a.execute(ctx, cancel, task)
+
+	req := &workerrpc.CompleteTaskRequest{
+		AgentID:    a.opts.AgentID,
+		TaskID:     task.TaskID,
+		ReturnCode: returnCode,
+	}
+	if runErr != nil {
+		req.Error = runErr.Error()
+	}
+	log.Printf("[Agent] 任务 %s 已结束，退出码: %d", task.TaskID, returnCode)
+
+	callCtx, callCancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer callCancel()
+	if err := a.client.CompleteTask(callCtx, req); err != nil {
+		log.Printf("[Agent] 上报任务 %s 结束失败: %v", task.TaskID, err)
+	}
+}
+
+// execute 启动进程并等待结束，返回退出码；进程未能启动时返回错误
+func (a *Agent) execute(ctx context.Context, cancel context.CancelFunc, task *workerrpc.TaskAssignment) (int, error) {
+	cmd := exec.CommandContext(ctx, a.opts.Python, withGRPCAddr(task.Args, a.opts.Server)...)
+	procgroup.Configure(cmd, a.opts.StopGrace)
+	cmd.Dir = a.opts.ProjectRoot
+	cmd.Env = append(os.Environ(), "PYTHONUNBUFFERED=1")
+	// main.py 与工作节点使用相同的方式连接后端
+	if a.opts.TLS {
+		cmd.Env = append(cmd.Env, workerrpc.TLSEnviron(a.opts.TLSCA, a.opts.TLSServer)...)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return -1, fmt.Errorf("创建输出管道失败: %v", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return -1, fmt.Errorf("创建错误管道失败: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return -1, fmt.Errorf("工作节点 %s 启动 main.py 失败: %v", a.opts.AgentID, err)
+	}
+
+	lines := make(chan workerrpc.OutputLine, outputBatchSize)
+	var readers sync.WaitGroup
+	readers.Add(2)
+	go readLines(&readers, "stdout", stdout, lines)
+	go readLines(&readers, "stderr", stderr, lines)
+	go func() {
+		readers.Wait()
+		close(lines)
+	}()
+	a.forwardOutput(task.TaskID, lines, cancel)
+
+	err = cmd.Wait()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return -1, err
+		}
+	}
+	return cmd.ProcessState.ExitCode(), nil
+}
+
+// readLines 按行读取进程输出
+func readLines(wg *sync.WaitGroup, stream string, r io.Reader, lines chan<- workerrpc.OutputLine) {
+	defer wg.Done()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		lines <- workerrpc.OutputLine{Stream: stream, Line: scanner.Text()}
+	}
+}
+
+// forwardOutput 分批转发输出直到进程关闭输出；后端表示任务已停止时终止进程
+func (a *Agent) forwardOutput(taskID string, lines <-chan workerrpc.OutputLine, cancel context.CancelFunc) {
+	ticker := time.NewTicker(outputFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]workerrpc.OutputLine, 0, outputBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, callCancel := context.WithTimeout(context.Background(), rpcTimeout)
+		resp, err := a.client.ReportOutput(ctx, &workerrpc.ReportOutputRequest{
+			AgentID: a.opts.AgentID,
+			TaskID:  taskID,
+			Lines:   batch,
+		})
+		callCancel()
+		if err != nil {
+			log.Printf("[Agent] 转发任务 %s 的输出失败（%d 行）: %v", taskID, len(batch), err)
+		} else if !resp.Running {
+			cancel()
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, line)
+			if len(batch) >= outputBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// withGRPCAddr 将 main.py 的 --grpc-addr 指向工作节点连接的后端地址（后端生成的地址是它自己的监听地址）
+func withGRPCAddr(args []string, server string) []string {
+	result := make([]string, 0, len(args)+2)
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--grpc-addr" {
+			i++
+			continue
+		}
+		result = append(result, args[i])
+	}
+	return append(result, "--grpc-addr", server)
+}
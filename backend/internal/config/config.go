@@ -2,20 +2,41 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
 	"time"
 )
 
 // Config 应用配置结构
 type Config struct {
-	Server      ServerConfig   `mapstructure:"server"`
-	Database    DatabaseConfig `mapstructure:"database"`
-	Redis       RedisConfig    `mapstructure:"redis_service"`
-	JWT         JWTConfig      `mapstructure:"jwt"`
-	Admin       AdminConfig    `mapstructure:"admin"`
-	CORS        CORSConfig     `mapstructure:"cors"`
-	Frontend    FrontendConfig `mapstructure:"frontend"`
-	Model       ModelConfig    `mapstructure:"model_services"`
-	ProjectRoot string         `mapstructure:"project_root"`
+	Server      ServerConfig      `mapstructure:"server"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	Redis       RedisConfig       `mapstructure:"redis_service"`
+	JWT         JWTConfig         `mapstructure:"jwt"`
+	Admin       AdminConfig       `mapstructure:"admin"`
+	Password    PasswordConfig    `mapstructure:"password_policy"`
+	CORS        CORSConfig        `mapstructure:"cors"`
+	Frontend    FrontendConfig    `mapstructure:"frontend"`
+	Model       ModelConfig       `mapstructure:"model_services"`
+	Upload      UploadConfig      `mapstructure:"upload"`
+	VirusScan   VirusScanConfig   `mapstructure:"virus_scan"`
+	Worker      WorkerConfig      `mapstructure:"worker"`
+	JobPool     JobPoolConfig     `mapstructure:"job_pool"`
+	Webhook     WebhookConfig     `mapstructure:"webhook"`
+	Notify      NotifyConfig      `mapstructure:"notification"`
+	Scheduler   SchedulerConfig   `mapstructure:"scheduler"`
+	Storage     StorageConfig     `mapstructure:"storage"`
+	Safety      SafetyConfig      `mapstructure:"safety"`
+	Tracing     TracingConfig     `mapstructure:"tracing"`
+	TaskLog     TaskLogConfig     `mapstructure:"task_log"`
+	Retention   RetentionConfig   `mapstructure:"retention"`
+	Backup      BackupConfig      `mapstructure:"backup"`
+	Import      ImportConfig      `mapstructure:"import"`
+	ObjectStore ObjectStoreConfig `mapstructure:"object_storage"`
+	Cluster     ClusterConfig     `mapstructure:"cluster"`
+	RateLimit   RateLimitConfig   `mapstructure:"rate_limit"`
+	Secrets     SecretsConfig     `mapstructure:"secrets"`
+	Plugins     []PluginConfig    `mapstructure:"plugins"`
+	ProjectRoot string            `mapstructure:"project_root"`
 }
 
 // GetModelServices 获取模型服务地址列表
@@ -27,9 +48,14 @@ func (c *Config) GetModelServices() []string {
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
-	Host           string `mapstructure:"host"`
-	Port           int    `mapstructure:"port"`
-	ProductionMode bool   `mapstructure:"production_mode"`
+	Host            string `mapstructure:"host"`
+	Port            int    `mapstructure:"port"`
+	ProductionMode  bool   `mapstructure:"production_mode"`
+	DefaultTimezone string `mapstructure:"default_timezone"` // 用户未设置时区时的展示时区（IANA 名称，Local 为服务器本地时区）
+	// SSEHistoryLimit 建立进度 SSE 连接时默认回放的历史事件条数（只回放最后 N 条）
+	SSEHistoryLimit int `mapstructure:"sse_history_limit"`
+	// PreflightStrict 生产模式下启动自检存在关键项失败时拒绝启动
+	PreflightStrict bool `mapstructure:"preflight_strict"`
 }
 
 // GetAddress 获取服务器地址
@@ -37,9 +63,27 @@ func (s *ServerConfig) GetAddress() string {
 	return fmt.Sprintf("%s:%d", s.Host, s.Port)
 }
 
+// GetDefaultLocation 获取默认展示时区（配置无效时回退到UTC）
+func (s *ServerConfig) GetDefaultLocation() *time.Location {
+	loc, err := time.LoadLocation(s.DefaultTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// 数据库类型
+const (
+	DatabaseDriverSQLite   = "sqlite"
+	DatabaseDriverPostgres = "postgres"
+)
+
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
-	Path string `mapstructure:"path"`
+	Driver   string   `mapstructure:"driver"`   // sqlite（默认）或 postgres
+	Path     string   `mapstructure:"path"`     // SQLite 数据库文件路径
+	DSN      string   `mapstructure:"dsn"`      // PostgreSQL 主库连接串
+	Replicas []string `mapstructure:"replicas"` // 只读副本连接串，读取量大的查询走副本
 }
 
 // RedisConfig Redis配置
@@ -50,6 +94,18 @@ type RedisConfig struct {
 	Password              string `mapstructure:"password"`
 	MaxWaitTime           int    `mapstructure:"max_wait_time"`
 	DefaultMaxConcurrency int    `mapstructure:"default_max_concurrency"`
+	// 公平调度：模型槽位不足时按各用户已占用槽位数/权重分配，避免单个用户占满模型
+	FairScheduling bool `mapstructure:"fair_scheduling"`
+	// 各角色的调度权重（未配置的角色为 1），权重越大可占用的槽位份额越多
+	RoleWeights map[string]int `mapstructure:"role_weights"`
+}
+
+// GetRoleWeight 获取角色的公平调度权重
+func (r *RedisConfig) GetRoleWeight(role string) int {
+	if weight, ok := r.RoleWeights[role]; ok && weight > 0 {
+		return weight
+	}
+	return 1
 }
 
 // GetAddress 获取Redis地址
@@ -66,7 +122,12 @@ func (r *RedisConfig) GetMaxWaitDuration() time.Duration {
 type JWTConfig struct {
 	SecretKey     string `mapstructure:"secret_key"`
 	Algorithm     string `mapstructure:"algorithm"`
-	ExpireMinutes int    `mapstructure:"expire_minutes"`
+	ExpireMinutes int    `mapstructure:"expire_minutes"` // 访问Token有效期（分钟）
+	// 刷新Token有效期（小时），刷新Token保存在Redis中，登出时吊销
+	RefreshExpireHours int `mapstructure:"refresh_expire_hours"`
+	// 审阅链接Token的默认有效期与最长有效期（小时）
+	ReviewLinkDefaultHours int `mapstructure:"review_link_default_hours"`
+	ReviewLinkMaxHours     int `mapstructure:"review_link_max_hours"`
 }
 
 // GetExpireDuration 获取过期时间
@@ -74,12 +135,27 @@ func (j *JWTConfig) GetExpireDuration() time.Duration {
 	return time.Duration(j.ExpireMinutes) * time.Minute
 }
 
+// GetRefreshExpireDuration 获取刷新Token过期时间
+func (j *JWTConfig) GetRefreshExpireDuration() time.Duration {
+	return time.Duration(j.RefreshExpireHours) * time.Hour
+}
+
 // AdminConfig 管理员配置
 type AdminConfig struct {
 	Username string `mapstructure:"username"`
 	Password string `mapstructure:"password"`
 }
 
+// PasswordConfig 密码策略配置
+type PasswordConfig struct {
+	MinLength        int  `mapstructure:"min_length"`
+	RequireUppercase bool `mapstructure:"require_uppercase"`
+	RequireLowercase bool `mapstructure:"require_lowercase"`
+	RequireDigit     bool `mapstructure:"require_digit"`
+	RequireSymbol    bool `mapstructure:"require_symbol"`
+	BcryptCost       int  `mapstructure:"bcrypt_cost"` // bcrypt 计算强度（4-31），越大越安全也越慢
+}
+
 // CORSConfig CORS配置
 type CORSConfig struct {
 	Origins          []string `mapstructure:"origins"`
@@ -98,4 +174,551 @@ type ModelConfig struct {
 	DefaultServices []string `mapstructure:"default_services"`
 	DefaultModel    string   `mapstructure:"default_model"`
 	DefaultAPIKey   string   `mapstructure:"default_api_key"`
+	// CacheEnabled 开启模型调用代理的响应缓存（相同模型、消息和参数的请求直接返回缓存结果）
+	CacheEnabled bool `mapstructure:"cache_enabled"`
+	// CacheTTLSeconds 缓存结果的保留时间
+	CacheTTLSeconds int `mapstructure:"cache_ttl_seconds"`
+	// CacheMaxTemperature 只缓存温度不超过该值的请求，避免生成多样性的调用返回相同结果
+	CacheMaxTemperature float64 `mapstructure:"cache_max_temperature"`
+	// EmbeddingModel 默认向量模型（模型配置的名称或模型路径），向量接口代理和建立向量索引未指定模型时使用
+	EmbeddingModel string `mapstructure:"embedding_model"`
+}
+
+// GetCacheTTL 获取模型响应缓存的保留时间
+func (m *ModelConfig) GetCacheTTL() time.Duration {
+	return time.Duration(m.CacheTTLSeconds) * time.Second
+}
+
+// UploadConfig 文件上传配置
+type UploadConfig struct {
+	TempDir            string `mapstructure:"temp_dir"`             // 分片上传临时目录
+	StorageDir         string `mapstructure:"storage_dir"`          // 文件存储目录（相对路径相对于项目根目录），分片上传的 JSONL 文件保存在这里，数据库只保存路径
+	ChunkSize          int64  `mapstructure:"chunk_size"`           // 默认分片大小（字节）
+	SessionExpireHours int    `mapstructure:"session_expire_hours"` // 未完成的上传会话保留时间（小时）
+	ValidationMode     string `mapstructure:"validation_mode"`      // 上传时的默认校验模式: none, report, reject, quarantine
+	MaxSizeMB          int    `mapstructure:"max_size_mb"`          // 单个上传文件的大小上限（MB，普通上传和分片上传）
+	MaxBatchFiles      int    `mapstructure:"max_batch_files"`      // 批量上传单次最多登记的文件数（含压缩包中的文件）
+	MaxBatchSizeMB     int    `mapstructure:"max_batch_size_mb"`    // 批量上传的请求大小上限，同时也是压缩包解压后的总大小上限（MB）
+	// AllowedExtensions 允许上传的文件扩展名（带点，不区分大小写），文件开头的内容还需与扩展名一致
+	AllowedExtensions []string `mapstructure:"allowed_extensions"`
+}
+
+// GetSessionExpireDuration 获取上传会话过期时间
+func (u *UploadConfig) GetSessionExpireDuration() time.Duration {
+	return time.Duration(u.SessionExpireHours) * time.Hour
+}
+
+// FileStorageDir 获取文件存储目录的路径
+func (c *Config) FileStorageDir() string {
+	if filepath.IsAbs(c.Upload.StorageDir) {
+		return c.Upload.StorageDir
+	}
+	return filepath.Join(c.ProjectRoot, c.Upload.StorageDir)
+}
+
+// VirusScanConfig 上传文件病毒扫描配置（通过 clamd 的 INSTREAM 命令扫描）
+type VirusScanConfig struct {
+	Enabled        bool   `mapstructure:"enabled"`
+	Address        string `mapstructure:"address"`         // clamd 地址，如 tcp://127.0.0.1:3310 或 unix:///var/run/clamav/clamd.ctl
+	TimeoutSeconds int    `mapstructure:"timeout_seconds"` // 单个文件的扫描超时（秒）
+	FailOpen       bool   `mapstructure:"fail_open"`       // clamd 不可用或扫描出错时是否放行上传（默认拒绝）
+}
+
+// GetTimeout 获取单个文件的扫描超时
+func (v *VirusScanConfig) GetTimeout() time.Duration {
+	return time.Duration(v.TimeoutSeconds) * time.Second
+}
+
+// GetMaxSizeBytes 获取单个上传文件的大小上限（字节）
+func (u *UploadConfig) GetMaxSizeBytes() int64 {
+	return int64(u.MaxSizeMB) * 1024 * 1024
+}
+
+// GetMaxBatchSizeBytes 获取批量上传的大小上限（字节）
+func (u *UploadConfig) GetMaxBatchSizeBytes() int64 {
+	return int64(u.MaxBatchSizeMB) * 1024 * 1024
+}
+
+// ImportConfig 从远程 URL 或 Hugging Face 数据集导入数据文件的配置
+type ImportConfig struct {
+	MaxSizeMB            int    `mapstructure:"max_size_mb"`            // 单次导入下载的总大小上限（MB）
+	MaxRows              int    `mapstructure:"max_rows"`               // 单次导入的最大行数，0 表示不限制
+	TimeoutMinutes       int    `mapstructure:"timeout_minutes"`        // 单次导入（下载和转换）的超时时间（分钟）
+	HuggingFaceEndpoint  string `mapstructure:"huggingface_endpoint"`   // Hugging Face Hub 地址（可配置镜像）
+	HuggingFaceToken     string `mapstructure:"huggingface_token"`      // 访问受限数据集的 Token
+	AllowPrivateNetworks bool   `mapstructure:"allow_private_networks"` // 是否允许从内网和本机地址导入（默认禁止，防止 SSRF）
+}
+
+// GetMaxSizeBytes 获取单次导入的下载大小上限（字节）
+func (i *ImportConfig) GetMaxSizeBytes() int64 {
+	return int64(i.MaxSizeMB) * 1024 * 1024
+}
+
+// GetTimeout 获取单次导入的超时时间
+func (i *ImportConfig) GetTimeout() time.Duration {
+	return time.Duration(i.TimeoutMinutes) * time.Minute
+}
+
+// ObjectStoreConfig S3 兼容对象存储配置（导出数据集直接写入存储桶）
+type ObjectStoreConfig struct {
+	Endpoint             string `mapstructure:"endpoint"`               // 服务地址（不含协议），如 s3.amazonaws.com 或 MinIO 地址，为空表示未配置
+	Region               string `mapstructure:"region"`                 // 区域
+	Bucket               string `mapstructure:"bucket"`                 // 存储桶
+	Prefix               string `mapstructure:"prefix"`                 // 对象键前缀
+	AccessKeyID          string `mapstructure:"access_key_id"`          // 访问密钥 ID
+	SecretAccessKey      string `mapstructure:"secret_access_key"`      // 访问密钥
+	UseSSL               bool   `mapstructure:"use_ssl"`                // 是否使用 HTTPS
+	PresignExpireMinutes int    `mapstructure:"presign_expire_minutes"` // 签名下载链接的有效期（分钟，最长 7 天）
+}
+
+// IsConfigured 是否已配置对象存储
+func (o *ObjectStoreConfig) IsConfigured() bool {
+	return o.Endpoint != "" && o.Bucket != ""
+}
+
+// GetPresignExpire 获取签名下载链接的有效期
+func (o *ObjectStoreConfig) GetPresignExpire() time.Duration {
+	return time.Duration(o.PresignExpireMinutes) * time.Minute
+}
+
+// JobPoolConfig 文件处理作业池配置（校验、去重等）
+type JobPoolConfig struct {
+	Workers  int `mapstructure:"workers"`   // 同时运行的作业数
+	MaxQueue int `mapstructure:"max_queue"` // 池满时最多排队的作业数，超出后直接拒绝
+}
+
+// WebhookConfig Webhook 投递配置
+type WebhookConfig struct {
+	TimeoutSeconds       int  `mapstructure:"timeout_seconds"`        // 单次请求超时时间（秒）
+	MaxRetries           int  `mapstructure:"max_retries"`            // 失败后的最大重试次数
+	RetryIntervalSeconds int  `mapstructure:"retry_interval_seconds"` // 首次重试间隔（秒），之后按指数退避
+	AllowPrivateNetworks bool `mapstructure:"allow_private_networks"` // 是否允许投递到内网和本机地址（默认禁止，防止 SSRF）
+}
+
+// GetTimeout 获取单次投递超时时间
+func (w *WebhookConfig) GetTimeout() time.Duration {
+	return time.Duration(w.TimeoutSeconds) * time.Second
+}
+
+// GetRetryInterval 获取首次重试间隔
+func (w *WebhookConfig) GetRetryInterval() time.Duration {
+	return time.Duration(w.RetryIntervalSeconds) * time.Second
+}
+
+// NotifyConfig 任务通知配置（邮件、Slack、钉钉、企业微信）
+type NotifyConfig struct {
+	SMTP           SMTPConfig `mapstructure:"smtp"`
+	TimeoutSeconds int        `mapstructure:"timeout_seconds"` // 单次发送超时时间（秒）
+	// LinkBaseURL 通知中链接的前端地址，为空时使用 frontend.url
+	LinkBaseURL string `mapstructure:"link_base_url"`
+	// TitleTemplate、BodyTemplate 通知标题和正文模板（Go text/template），可用字段见 README
+	TitleTemplate string `mapstructure:"title_template"`
+	BodyTemplate  string `mapstructure:"body_template"`
+}
+
+// GetTimeout 获取单次发送超时时间
+func (n *NotifyConfig) GetTimeout() time.Duration {
+	return time.Duration(n.TimeoutSeconds) * time.Second
+}
+
+// SMTPConfig 邮件通知使用的 SMTP 服务器，Host 为空时不能订阅邮件通知
+type SMTPConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`    // 发件人地址，为空时使用 username
+	UseTLS   bool   `mapstructure:"use_tls"` // 直接建立 TLS 连接（465 端口）；否则在服务器支持时使用 STARTTLS
+}
+
+// Enabled 是否配置了 SMTP 服务器
+func (s *SMTPConfig) Enabled() bool {
+	return s.Host != ""
+}
+
+// GetAddress 获取 SMTP 服务器地址
+func (s *SMTPConfig) GetAddress() string {
+	return fmt.Sprintf("%s:%d", s.Host, s.Port)
+}
+
+// GetFrom 获取发件人地址
+func (s *SMTPConfig) GetFrom() string {
+	if s.From != "" {
+		return s.From
+	}
+	return s.Username
+}
+
+// 默认通知模板
+const (
+	DefaultNotifyTitleTemplate = "[数据生成] 任务 {{.TaskID}} {{.EventName}}"
+	DefaultNotifyBodyTemplate  = `任务: {{.TaskID}}
+状态: {{.Status}}
+任务类型: {{.TaskType}}
+输入文件: {{.InputFile}}
+生成数据: {{.DataCount}} 条（已确认 {{.ConfirmedCount}} 条）
+开始时间: {{.StartedAt}}
+耗时: {{.Duration}}
+{{- if .ErrorMessage}}
+错误信息: {{.ErrorMessage}}
+{{- end}}
+{{- if .Link}}
+查看和下载: {{.Link}}
+{{- end}}`
+)
+
+// SchedulerConfig 定时任务调度配置
+type SchedulerConfig struct {
+	Enabled             bool `mapstructure:"enabled"`               // 是否启动调度器
+	PollIntervalSeconds int  `mapstructure:"poll_interval_seconds"` // 扫描到期定时任务的间隔（秒）
+}
+
+// GetPollInterval 获取扫描间隔
+func (s *SchedulerConfig) GetPollInterval() time.Duration {
+	return time.Duration(s.PollIntervalSeconds) * time.Second
+}
+
+// StorageConfig 存储占用配置
+type StorageConfig struct {
+	UserQuotaMB int `mapstructure:"user_quota_mb"` // 每个用户的存储配额（MB），0 表示不限制
+	WarnPercent int `mapstructure:"warn_percent"`  // 占用达到配额的该百分比时提示警告
+}
+
+// GetUserQuotaBytes 获取每个用户的存储配额（字节），0 表示不限制
+func (s *StorageConfig) GetUserQuotaBytes() int64 {
+	return int64(s.UserQuotaMB) * 1024 * 1024
+}
+
+// SafetyConfig 生成数据内容安全检查配置
+type SafetyConfig struct {
+	Enabled    bool     `mapstructure:"enabled"`    // 所有任务结束后自动检查；关闭时可在启动任务时通过 safety_check 单独开启
+	Blocklist  []string `mapstructure:"blocklist"`  // 屏蔽词（不区分大小写）
+	Patterns   []string `mapstructure:"patterns"`   // 正则表达式，命中标记为 regex:<序号>
+	ModelID    uint     `mapstructure:"model_id"`   // 审核模型（模型配置ID），0 表示不调用模型
+	Categories []string `mapstructure:"categories"` // 审核模型判定的违规类别
+}
+
+// HasRules 是否配置了任何检查规则
+func (s *SafetyConfig) HasRules() bool {
+	return len(s.Blocklist) > 0 || len(s.Patterns) > 0 || s.ModelID != 0
+}
+
+// PluginConfig 部署插件配置（Go 插件在代码中注册，无需配置）
+// exec 插件启动 command，请求 JSON 写入标准输入、从标准输出读取响应；webhook 插件将请求 JSON POST 到 url
+type PluginConfig struct {
+	Name           string   `mapstructure:"name"`
+	Stage          string   `mapstructure:"stage"`           // preprocess、postprocess、export
+	Type           string   `mapstructure:"type"`            // exec、webhook
+	Command        []string `mapstructure:"command"`         // exec：命令及参数
+	URL            string   `mapstructure:"url"`             // webhook：插件地址
+	Secret         string   `mapstructure:"secret"`          // webhook：请求签名密钥（X-Plugin-Signature），为空时不签名
+	TimeoutSeconds int      `mapstructure:"timeout_seconds"` // 单次调用超时时间
+	TaskTypes      []string `mapstructure:"task_types"`      // preprocess/postprocess：生效的任务类型，为空时对全部类型生效
+	Format         string   `mapstructure:"format"`          // export：导出格式名称（导出接口的 format 参数）
+	Extension      string   `mapstructure:"extension"`       // export：导出文件扩展名
+}
+
+// TracingConfig OpenTelemetry 链路追踪配置
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	Endpoint    string  `mapstructure:"endpoint"`     // OTLP/HTTP 接收地址（host:port），如 localhost:4318
+	Insecure    bool    `mapstructure:"insecure"`     // 使用 HTTP 而不是 HTTPS 连接接收端
+	ServiceName string  `mapstructure:"service_name"` // 上报的服务名
+	SampleRatio float64 `mapstructure:"sample_ratio"` // 新 trace 的采样比例 (0, 1]，已采样的上游请求始终跟随
+}
+
+// TaskLogConfig 任务日志配置
+type TaskLogConfig struct {
+	// Dir 任务日志目录（相对路径相对于项目根目录），每个任务的完整事件写入 <task_id>.events.jsonl，
+	// 工作进程的原始标准输出和错误输出写入 <task_id>.output.log
+	Dir string `mapstructure:"dir"`
+	// OutputSampleEvery 普通输出行每 N 行保留一行在内存历史中并通过 SSE 推送，结构化事件和错误全部保留，1 表示不采样
+	OutputSampleEvery int `mapstructure:"output_sample_every"`
+}
+
+// TaskLogDir 获取任务日志目录的路径
+func (c *Config) TaskLogDir() string {
+	if filepath.IsAbs(c.TaskLog.Dir) {
+		return c.TaskLog.Dir
+	}
+	return filepath.Join(c.ProjectRoot, c.TaskLog.Dir)
+}
+
+// RetentionConfig 数据保留与后台清理配置
+type RetentionConfig struct {
+	Enabled          bool   `mapstructure:"enabled"`            // 是否启动后台清理
+	IntervalMinutes  int    `mapstructure:"interval_minutes"`   // 清理间隔（分钟）
+	TaskDays         int    `mapstructure:"task_days"`          // 已结束任务（含生成数据和日志）的保留天数，0 表示不清理任务
+	ArchiveDir       string `mapstructure:"archive_dir"`        // 删除任务前将生成数据归档为 JSONL 的目录（相对路径相对于项目根目录），为空表示直接删除
+	BatchSize        int    `mapstructure:"batch_size"`         // 每次清理最多处理的任务数
+	ProgressKeyHours int    `mapstructure:"progress_key_hours"` // 任务结束超过该时间（小时）后删除 Redis 中的 task_progress 键
+}
+
+// GetInterval 获取清理间隔
+func (r *RetentionConfig) GetInterval() time.Duration {
+	return time.Duration(r.IntervalMinutes) * time.Minute
+}
+
+// GetProgressKeyTTL 获取任务结束后 task_progress 键的保留时间
+func (r *RetentionConfig) GetProgressKeyTTL() time.Duration {
+	return time.Duration(r.ProgressKeyHours) * time.Hour
+}
+
+// RetentionArchiveDir 获取任务归档目录的路径（未配置时返回空字符串）
+func (c *Config) RetentionArchiveDir() string {
+	if c.Retention.ArchiveDir == "" || filepath.IsAbs(c.Retention.ArchiveDir) {
+		return c.Retention.ArchiveDir
+	}
+	return filepath.Join(c.ProjectRoot, c.Retention.ArchiveDir)
+}
+
+// BackupConfig 数据库备份配置
+type BackupConfig struct {
+	Dir            string `mapstructure:"dir"`             // 备份文件目录（相对路径相对于项目根目录）
+	Keep           int    `mapstructure:"keep"`            // 保留的备份数量，超出后删除最旧的备份
+	PgDumpPath     string `mapstructure:"pg_dump_path"`    // PostgreSQL 备份使用的 pg_dump 可执行文件
+	PgRestorePath  string `mapstructure:"pg_restore_path"` // PostgreSQL 恢复使用的 pg_restore 可执行文件
+	TimeoutMinutes int    `mapstructure:"timeout_minutes"` // 单次备份或恢复的超时时间（分钟）
+}
+
+// GetTimeout 获取单次备份或恢复的超时时间
+func (b *BackupConfig) GetTimeout() time.Duration {
+	return time.Duration(b.TimeoutMinutes) * time.Minute
+}
+
+// BackupDir 获取数据库备份目录（相对路径相对于项目根目录）
+func (c *Config) BackupDir() string {
+	if filepath.IsAbs(c.Backup.Dir) {
+		return c.Backup.Dir
+	}
+	return filepath.Join(c.ProjectRoot, c.Backup.Dir)
+}
+
+// ClusterConfig 多实例部署配置
+// 开启后任务状态和进度事件写入 Redis，任意实例都可以订阅进度和停止任务；工作进程仍由启动任务的实例管理
+type ClusterConfig struct {
+	Enabled            bool   `mapstructure:"enabled"`              // 是否开启多实例模式
+	NodeID             string `mapstructure:"node_id"`              // 实例标识，为空时使用 主机名-进程号
+	HeartbeatSeconds   int    `mapstructure:"heartbeat_seconds"`    // 实例心跳间隔（秒），超过 3 个间隔未更新视为实例已下线
+	EventHistoryLimit  int    `mapstructure:"event_history_limit"`  // Redis 中每个任务保留的最近事件条数
+	FinishedTTLMinutes int    `mapstructure:"finished_ttl_minutes"` // 任务结束后 Redis 中任务状态和事件的保留时间（分钟）
+}
+
+// GetHeartbeatInterval 获取实例心跳间隔
+func (c *ClusterConfig) GetHeartbeatInterval() time.Duration {
+	return time.Duration(c.HeartbeatSeconds) * time.Second
+}
+
+// GetFinishedTTL 获取任务结束后 Redis 中任务状态和事件的保留时间
+func (c *ClusterConfig) GetFinishedTTL() time.Duration {
+	return time.Duration(c.FinishedTTLMinutes) * time.Minute
+}
+
+// 接口限流类别
+const (
+	RateLimitStart     = "start"      // 启动和重新运行任务
+	RateLimitUpload    = "upload"     // 上传和导入数据文件
+	RateLimitExport    = "export"     // 导出和下载生成数据
+	RateLimitModelCall = "model_call" // 模型调用代理
+)
+
+// RateLimitConfig 接口限流配置（Redis 令牌桶，按用户和客户端 IP 分别计数，多个实例共用同一个桶）
+type RateLimitConfig struct {
+	Enabled   bool          `mapstructure:"enabled"`
+	Start     RateLimitRule `mapstructure:"start"`
+	Upload    RateLimitRule `mapstructure:"upload"`
+	Export    RateLimitRule `mapstructure:"export"`
+	ModelCall RateLimitRule `mapstructure:"model_call"` // 工作进程使用内部密钥调用，只按 IP 计数
+}
+
+// RateLimitRule 一类接口的限流规则，每分钟令牌数为 0 表示不限制
+type RateLimitRule struct {
+	UserPerMinute int `mapstructure:"user_per_minute"` // 每个用户每分钟补充的令牌数
+	IPPerMinute   int `mapstructure:"ip_per_minute"`   // 每个客户端 IP 每分钟补充的令牌数
+	Burst         int `mapstructure:"burst"`           // 令牌桶容量（允许的瞬时突发请求数），0 表示等于每分钟令牌数
+}
+
+// Rule 获取接口类别的限流规则（未知类别不限制）
+func (r *RateLimitConfig) Rule(route string) RateLimitRule {
+	switch route {
+	case RateLimitStart:
+		return r.Start
+	case RateLimitUpload:
+		return r.Upload
+	case RateLimitExport:
+		return r.Export
+	case RateLimitModelCall:
+		return r.ModelCall
+	}
+	return RateLimitRule{}
+}
+
+// WorkerConfig Python工作进程配置
+type WorkerConfig struct {
+	// ExtraArgs 允许通过 StartTaskRequest.extra_args 透传给 main.py 的参数白名单
+	ExtraArgs []WorkerArgSpec `mapstructure:"extra_args"`
+	// HandshakeTimeoutSeconds 探测工作进程版本（main.py --version）的超时时间
+	HandshakeTimeoutSeconds int `mapstructure:"handshake_timeout_seconds"`
+	// StopGraceSeconds 停止任务时向工作进程组发送 SIGTERM 后等待退出的时间，超时后发送 SIGKILL
+	StopGraceSeconds int `mapstructure:"stop_grace_seconds"`
+	// HeartbeatWarnSeconds 工作进程超过该时间没有任何输出（进度或心跳）时推送警告事件
+	HeartbeatWarnSeconds int `mapstructure:"heartbeat_warn_seconds"`
+	// StallTimeoutSeconds 工作进程超过该时间没有任何输出时判定为卡死并终止
+	StallTimeoutSeconds int `mapstructure:"stall_timeout_seconds"`
+	// StallAction 卡死后的处理方式：fail（标记失败）或 restart（以相同参数自动重新运行）
+	StallAction string `mapstructure:"stall_action"`
+	// MaxStallRestarts 同一任务因卡死自动重新运行的最大次数
+	MaxStallRestarts int `mapstructure:"max_stall_restarts"`
+	// MaxRetries 任务因瞬时错误（上游 5xx、超时、限流、连接失败）失败后自动重试的最大次数，0 表示不重试
+	MaxRetries int `mapstructure:"max_retries"`
+	// RetryBackoffSeconds 第一次自动重试前的等待时间，之后每次翻倍
+	RetryBackoffSeconds int `mapstructure:"retry_backoff_seconds"`
+	// RetryMaxBackoffSeconds 自动重试等待时间的上限
+	RetryMaxBackoffSeconds int `mapstructure:"retry_max_backoff_seconds"`
+	// DefaultMaxRuntimeMinutes 任务未指定 max_runtime_minutes 时的最长运行时间（分钟），0 表示不限制
+	DefaultMaxRuntimeMinutes int `mapstructure:"default_max_runtime_minutes"`
+	// MaxRuntimeMinutesLimit 任务可指定的 max_runtime_minutes 上限（分钟），0 表示不限制
+	MaxRuntimeMinutesLimit int `mapstructure:"max_runtime_minutes_limit"`
+	// GRPCEnabled 开启工作进程 gRPC 服务（proto/worker.proto），关闭或连接失败时工作进程使用标准输出协议
+	GRPCEnabled bool `mapstructure:"grpc_enabled"`
+	// GRPCAddr 工作进程 gRPC 服务的监听地址
+	GRPCAddr string `mapstructure:"grpc_addr"`
+	// GRPCTLSCert、GRPCTLSKey gRPC 服务的 TLS 证书和私钥（PEM 文件），同时配置时开启 TLS
+	GRPCTLSCert string `mapstructure:"grpc_tls_cert"`
+	GRPCTLSKey  string `mapstructure:"grpc_tls_key"`
+	// GRPCTLSCA 本机工作进程校验服务端证书使用的 CA 证书，为空时使用 grpc_tls_cert（自签名证书）
+	GRPCTLSCA string `mapstructure:"grpc_tls_ca"`
+	// GRPCTLSServerName 本机工作进程校验证书时使用的服务器名，证书不包含 127.0.0.1 时需要设置
+	GRPCTLSServerName string `mapstructure:"grpc_tls_server_name"`
+	// Executor 任务执行方式：local（后端本机启动 main.py）或 agent（由工作节点 cmd/agent 领取并运行，需开启 grpc_enabled）
+	Executor string `mapstructure:"executor"`
+	// AgentPollSeconds 工作节点领取任务的轮询间隔（轮询同时作为心跳）
+	AgentPollSeconds int `mapstructure:"agent_poll_seconds"`
+	// AgentOfflineSeconds 工作节点超过该时间未轮询视为下线，其上运行中的任务按失败处理
+	AgentOfflineSeconds int `mapstructure:"agent_offline_seconds"`
+}
+
+// 任务卡死后的处理方式
+const (
+	StallActionFail    = "fail"
+	StallActionRestart = "restart"
+)
+
+// 任务执行方式
+const (
+	WorkerExecutorLocal = "local"
+	WorkerExecutorAgent = "agent"
+)
+
+// GRPCTLSEnabled gRPC 服务是否开启 TLS
+func (w *WorkerConfig) GRPCTLSEnabled() bool {
+	return w.GRPCTLSCert != "" && w.GRPCTLSKey != ""
+}
+
+// GetGRPCTLSCA 获取本机工作进程校验服务端证书使用的 CA 证书
+func (w *WorkerConfig) GetGRPCTLSCA() string {
+	if w.GRPCTLSCA != "" {
+		return w.GRPCTLSCA
+	}
+	return w.GRPCTLSCert
+}
+
+// GetAgentPollInterval 获取工作节点的轮询间隔
+func (w *WorkerConfig) GetAgentPollInterval() time.Duration {
+	return time.Duration(w.AgentPollSeconds) * time.Second
+}
+
+// GetAgentOfflineTimeout 获取工作节点的下线判定时间
+func (w *WorkerConfig) GetAgentOfflineTimeout() time.Duration {
+	return time.Duration(w.AgentOfflineSeconds) * time.Second
+}
+
+// GetRetryBackoff 获取第 attempt 次自动重试（从 1 开始）前的等待时间
+func (w *WorkerConfig) GetRetryBackoff(attempt int) time.Duration {
+	backoff := time.Duration(w.RetryBackoffSeconds) * time.Second
+	limit := time.Duration(w.RetryMaxBackoffSeconds) * time.Second
+	for i := 1; i < attempt && backoff < limit; i++ {
+		backoff *= 2
+	}
+	if backoff > limit {
+		backoff = limit
+	}
+	return backoff
+}
+
+// GetHeartbeatWarnDuration 获取无输出警告阈值
+func (w *WorkerConfig) GetHeartbeatWarnDuration() time.Duration {
+	return time.Duration(w.HeartbeatWarnSeconds) * time.Second
+}
+
+// GetStallTimeout 获取卡死判定阈值
+func (w *WorkerConfig) GetStallTimeout() time.Duration {
+	return time.Duration(w.StallTimeoutSeconds) * time.Second
+}
+
+// GetStopGracePeriod 获取停止任务的宽限期
+func (w *WorkerConfig) GetStopGracePeriod() time.Duration {
+	return time.Duration(w.StopGraceSeconds) * time.Second
+}
+
+// GetHandshakeTimeout 获取版本探测超时时间
+func (w *WorkerConfig) GetHandshakeTimeout() time.Duration {
+	return time.Duration(w.HandshakeTimeoutSeconds) * time.Second
+}
+
+// WorkerArgSpec 可透传的工作进程参数定义
+type WorkerArgSpec struct {
+	Name    string   `mapstructure:"name"`    // 参数名（不含 --），如 "seed"
+	Type    string   `mapstructure:"type"`    // string, int, float, bool
+	Pattern string   `mapstructure:"pattern"` // string 类型的可选正则校验
+	Choices []string `mapstructure:"choices"` // 可选的取值枚举
+}
+
+// FindExtraArg 根据参数名查找白名单中的参数定义
+func (w *WorkerConfig) FindExtraArg(name string) (*WorkerArgSpec, bool) {
+	for i := range w.ExtraArgs {
+		if w.ExtraArgs[i].Name == name {
+			return &w.ExtraArgs[i], true
+		}
+	}
+	return nil, false
+}
+
+// 密钥存储类型
+const (
+	SecretsProviderVault = "vault"
+	SecretsProviderAWS   = "aws"
+)
+
+// SecretsConfig 从 HashiCorp Vault 或 AWS Secrets Manager 读取敏感配置（代替 config.yaml 中的明文）
+// 启动和重新加载配置时读取，RefreshMinutes 大于 0 时定期重新读取以获取轮换后的密钥
+type SecretsConfig struct {
+	Provider       string             `mapstructure:"provider"`        // 为空表示不使用，vault 或 aws
+	RefreshMinutes int                `mapstructure:"refresh_minutes"` // 定期重新读取的间隔（分钟），0 表示不定期读取
+	Vault          VaultSecretsConfig `mapstructure:"vault"`
+	AWS            AWSSecretsConfig   `mapstructure:"aws"`
+}
+
+// GetRefreshInterval 获取定期重新读取密钥的间隔
+func (s *SecretsConfig) GetRefreshInterval() time.Duration {
+	return time.Duration(s.RefreshMinutes) * time.Minute
+}
+
+// VaultSecretsConfig Vault KV v2 密钥配置（地址和 Token 为空时读取 VAULT_ADDR、VAULT_TOKEN 环境变量）
+type VaultSecretsConfig struct {
+	Address   string `mapstructure:"address"`
+	Token     string `mapstructure:"token"`
+	Namespace string `mapstructure:"namespace"` // Vault 企业版命名空间
+	Mount     string `mapstructure:"mount"`     // KV v2 引擎挂载路径
+	Path      string `mapstructure:"path"`      // 密钥路径
+}
+
+// AWSSecretsConfig AWS Secrets Manager 密钥配置（区域和访问密钥为空时读取 AWS_REGION、AWS_ACCESS_KEY_ID 等标准环境变量）
+type AWSSecretsConfig struct {
+	Region          string `mapstructure:"region"`
+	SecretID        string `mapstructure:"secret_id"` // 密钥名称或 ARN，SecretString 须为 JSON 对象
+	Endpoint        string `mapstructure:"endpoint"`  // 自定义服务地址（如 VPC 终端节点），为空使用默认地址
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+	SessionToken    string `mapstructure:"session_token"`
 }
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix 环境变量前缀：配置项 server.port 对应 GEN_SERVER_PORT，worker.stall_action 对应 GEN_WORKER_STALL_ACTION
+const EnvPrefix = "GEN"
+
+// envSectionAliases 配置段名较长时的环境变量简写（GEN_REDIS_HOST 等同于 GEN_REDIS_SERVICE_HOST）
+var envSectionAliases = map[string]string{
+	"redis_service":  "redis",
+	"model_services": "model",
+}
+
+// bindEnvs 为 Config 的每个配置项绑定环境变量，环境变量优先于配置文件
+// 标量直接绑定；列表使用逗号分隔或 JSON 数组，映射和结构体列表使用 JSON，在 applyCollectionEnvs 中解析
+func bindEnvs(v *viper.Viper) ([]envCollection, error) {
+	var collections []envCollection
+	var walk func(t reflect.Type, path []string) error
+	walk = func(t reflect.Type, path []string) error {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("mapstructure")
+			if tag == "" || tag == "-" {
+				continue
+			}
+			fieldPath := append(append([]string{}, path...), tag)
+
+			switch field.Type.Kind() {
+			case reflect.Struct:
+				if err := walk(field.Type, fieldPath); err != nil {
+					return err
+				}
+				continue
+			case reflect.Slice, reflect.Map:
+				collections = append(collections, envCollection{
+					key:  strings.Join(fieldPath, "."),
+					envs: envNames(fieldPath),
+					typ:  field.Type,
+				})
+				continue
+			}
+
+			args := append([]string{strings.Join(fieldPath, ".")}, envNames(fieldPath)...)
+			if err := v.BindEnv(args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(reflect.TypeOf(Config{}), nil); err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
+
+// envNames 配置项对应的环境变量名（含配置段简写）
+func envNames(path []string) []string {
+	names := []string{EnvPrefix + "_" + strings.ToUpper(strings.Join(path, "_"))}
+	if alias, ok := envSectionAliases[path[0]]; ok && len(path) > 1 {
+		aliased := append([]string{alias}, path[1:]...)
+		names = append(names, EnvPrefix+"_"+strings.ToUpper(strings.Join(aliased, "_")))
+	}
+	return names
+}
+
+// envCollection 列表或映射类型的配置项
+type envCollection struct {
+	key  string
+	envs []string
+	typ  reflect.Type
+}
+
+// applyCollectionEnvs 解析列表和映射类型的环境变量并覆盖配置
+// 列表可写为逗号分隔（GEN_CORS_ORIGINS=http://a,http://b）或 JSON 数组，映射和结构体列表需写为 JSON
+func applyCollectionEnvs(v *viper.Viper, collections []envCollection) error {
+	for _, c := range collections {
+		raw, name := lookupEnv(c.envs)
+		if raw == "" {
+			continue
+		}
+
+		trimmed := strings.TrimSpace(raw)
+		var value interface{}
+		switch {
+		case strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{"):
+			if err := json.Unmarshal([]byte(trimmed), &value); err != nil {
+				return fmt.Errorf("环境变量 %s 不是有效的 JSON: %w", name, err)
+			}
+		case c.typ.Kind() == reflect.Slice && c.typ.Elem().Kind() != reflect.Struct:
+			items := []string{}
+			for _, item := range strings.Split(raw, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			value = items
+		default:
+			return fmt.Errorf("环境变量 %s 需要写为 JSON", name)
+		}
+		v.Set(c.key, value)
+	}
+	return nil
+}
+
+// lookupEnv 返回第一个非空的环境变量值及其名称
+func lookupEnv(names []string) (string, string) {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value, name
+		}
+	}
+	return "", ""
+}
+
+// isConfigFileNotFound 配置文件不存在（只使用环境变量配置）
+func isConfigFileNotFound(err error) bool {
+	var notFound viper.ConfigFileNotFoundError
+	return errors.As(err, &notFound) || errors.Is(err, os.ErrNotExist)
+}
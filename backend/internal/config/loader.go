@@ -2,9 +2,16 @@ package config
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"sync"
+	"text/template"
+	"time"
+
+	"gen-go/internal/utils"
 
 	"github.com/spf13/viper"
 )
@@ -46,12 +53,21 @@ func loadConfigFromFile(configFile string) (*Config, error) {
 		v.AddConfigPath("./config")
 	}
 
-	// 读取环境变量
-	v.AutomaticEnv()
+	// 读取环境变量（GEN_ 前缀，见 env.go），环境变量优先于配置文件
+	collections, err := bindEnvs(v)
+	if err != nil {
+		return nil, fmt.Errorf("绑定环境变量失败: %w", err)
+	}
 
-	// 读取配置文件
+	// 读取配置文件；文件不存在时只使用环境变量（容器部署），必需项缺失由 validateConfig 报错
 	if err := v.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+		if !isConfigFileNotFound(err) {
+			return nil, fmt.Errorf("读取配置文件失败: %w", err)
+		}
+		log.Printf("[Config] 未找到配置文件，仅使用 %s_ 前缀的环境变量配置", EnvPrefix)
+	}
+	if err := applyCollectionEnvs(v, collections); err != nil {
+		return nil, err
 	}
 
 	// 解析配置
@@ -63,6 +79,11 @@ func loadConfigFromFile(configFile string) (*Config, error) {
 	// 设置默认值
 	setDefaults(&cfg)
 
+	// 从密钥存储读取敏感配置（覆盖配置文件中的值）
+	if err := applySecrets(&cfg); err != nil {
+		return nil, err
+	}
+
 	// 验证配置
 	if err := validateConfig(&cfg); err != nil {
 		return nil, fmt.Errorf("配置验证失败: %w", err)
@@ -79,6 +100,15 @@ func setDefaults(cfg *Config) {
 	if cfg.Server.Port == 0 {
 		cfg.Server.Port = 18080
 	}
+	if cfg.Server.DefaultTimezone == "" {
+		cfg.Server.DefaultTimezone = "Local"
+	}
+	if cfg.Server.SSEHistoryLimit <= 0 {
+		cfg.Server.SSEHistoryLimit = 500
+	}
+	if cfg.Database.Driver == "" {
+		cfg.Database.Driver = DatabaseDriverSQLite
+	}
 	if cfg.Database.Path == "" {
 		cfg.Database.Path = "./database/app.db"
 	}
@@ -96,11 +126,26 @@ func setDefaults(cfg *Config) {
 		cfg.JWT.Algorithm = "HS256"
 	}
 	if cfg.JWT.ExpireMinutes == 0 {
-		cfg.JWT.ExpireMinutes = 43200 // 30天
+		cfg.JWT.ExpireMinutes = 30
+	}
+	if cfg.JWT.RefreshExpireHours <= 0 {
+		cfg.JWT.RefreshExpireHours = 720 // 30天
+	}
+	if cfg.JWT.ReviewLinkDefaultHours <= 0 {
+		cfg.JWT.ReviewLinkDefaultHours = 72
+	}
+	if cfg.JWT.ReviewLinkMaxHours <= 0 {
+		cfg.JWT.ReviewLinkMaxHours = 720 // 30天
 	}
 	if cfg.Admin.Username == "" {
 		cfg.Admin.Username = "admin"
 	}
+	if cfg.Password.MinLength <= 0 {
+		cfg.Password.MinLength = 8
+	}
+	if cfg.Password.BcryptCost == 0 {
+		cfg.Password.BcryptCost = 10 // bcrypt.DefaultCost
+	}
 	// CORS Origins 必须从配置文件读取，不设置硬编码默认值
 	// if len(cfg.CORS.Origins) == 0 {
 	// 	cfg.CORS.Origins = []string{"http://localhost:13000"}
@@ -122,6 +167,213 @@ func setDefaults(cfg *Config) {
 	if cfg.Model.DefaultModel == "" {
 		cfg.Model.DefaultModel = "/data/models/Qwen3-32B"
 	}
+	if cfg.Model.CacheTTLSeconds <= 0 {
+		cfg.Model.CacheTTLSeconds = 86400 // 1天
+	}
+	if cfg.Upload.TempDir == "" {
+		cfg.Upload.TempDir = "./data/uploads"
+	}
+	if cfg.Upload.StorageDir == "" {
+		cfg.Upload.StorageDir = "./data/files"
+	}
+	if cfg.Upload.ChunkSize == 0 {
+		cfg.Upload.ChunkSize = 8 * 1024 * 1024 // 8MB
+	}
+	if cfg.Upload.SessionExpireHours == 0 {
+		cfg.Upload.SessionExpireHours = 24
+	}
+	if cfg.Upload.ValidationMode == "" {
+		cfg.Upload.ValidationMode = "report"
+	}
+	if cfg.Upload.MaxSizeMB <= 0 {
+		cfg.Upload.MaxSizeMB = 500
+	}
+	if cfg.Upload.MaxBatchFiles <= 0 {
+		cfg.Upload.MaxBatchFiles = 50
+	}
+	if cfg.Upload.MaxBatchSizeMB <= 0 {
+		cfg.Upload.MaxBatchSizeMB = 1024
+	}
+	if len(cfg.Upload.AllowedExtensions) == 0 {
+		cfg.Upload.AllowedExtensions = []string{".jsonl", ".json", ".csv", ".txt", ".xlsx", ".parquet"}
+	}
+	for i, ext := range cfg.Upload.AllowedExtensions {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		cfg.Upload.AllowedExtensions[i] = ext
+	}
+	if cfg.VirusScan.Address == "" {
+		cfg.VirusScan.Address = "tcp://127.0.0.1:3310"
+	}
+	if cfg.VirusScan.TimeoutSeconds <= 0 {
+		cfg.VirusScan.TimeoutSeconds = 60
+	}
+	if cfg.Import.MaxSizeMB <= 0 {
+		cfg.Import.MaxSizeMB = 1024
+	}
+	if cfg.Import.MaxRows < 0 {
+		cfg.Import.MaxRows = 0
+	}
+	if cfg.Import.TimeoutMinutes <= 0 {
+		cfg.Import.TimeoutMinutes = 30
+	}
+	if cfg.Import.HuggingFaceEndpoint == "" {
+		cfg.Import.HuggingFaceEndpoint = "https://huggingface.co"
+	}
+	if cfg.ObjectStore.PresignExpireMinutes <= 0 {
+		cfg.ObjectStore.PresignExpireMinutes = 24 * 60
+	}
+	if cfg.ObjectStore.PresignExpireMinutes > 7*24*60 {
+		cfg.ObjectStore.PresignExpireMinutes = 7 * 24 * 60 // S3 签名链接最长 7 天
+	}
+	if cfg.JobPool.Workers <= 0 {
+		cfg.JobPool.Workers = 4
+	}
+	if cfg.JobPool.MaxQueue <= 0 {
+		cfg.JobPool.MaxQueue = 64
+	}
+	if cfg.Webhook.TimeoutSeconds == 0 {
+		cfg.Webhook.TimeoutSeconds = 10
+	}
+	if cfg.Webhook.MaxRetries == 0 {
+		cfg.Webhook.MaxRetries = 3
+	}
+	if cfg.Webhook.RetryIntervalSeconds == 0 {
+		cfg.Webhook.RetryIntervalSeconds = 5
+	}
+	if cfg.Notify.TimeoutSeconds <= 0 {
+		cfg.Notify.TimeoutSeconds = 10
+	}
+	if cfg.Notify.SMTP.Port == 0 {
+		cfg.Notify.SMTP.Port = 587
+	}
+	if cfg.Notify.LinkBaseURL == "" {
+		cfg.Notify.LinkBaseURL = cfg.Frontend.URL
+	}
+	if cfg.Notify.TitleTemplate == "" {
+		cfg.Notify.TitleTemplate = DefaultNotifyTitleTemplate
+	}
+	if cfg.Notify.BodyTemplate == "" {
+		cfg.Notify.BodyTemplate = DefaultNotifyBodyTemplate
+	}
+	if cfg.Scheduler.PollIntervalSeconds <= 0 {
+		cfg.Scheduler.PollIntervalSeconds = 30
+	}
+	if cfg.Storage.WarnPercent <= 0 || cfg.Storage.WarnPercent > 100 {
+		cfg.Storage.WarnPercent = 80
+	}
+	if len(cfg.Safety.Categories) == 0 {
+		cfg.Safety.Categories = []string{"violence", "sexual", "hate", "self_harm", "illegal"}
+	}
+	if cfg.Tracing.Endpoint == "" {
+		cfg.Tracing.Endpoint = "localhost:4318"
+	}
+	if cfg.Tracing.ServiceName == "" {
+		cfg.Tracing.ServiceName = "gen-go"
+	}
+	if cfg.Tracing.SampleRatio <= 0 {
+		cfg.Tracing.SampleRatio = 1
+	}
+	if cfg.TaskLog.Dir == "" {
+		cfg.TaskLog.Dir = "log/tasks"
+	}
+	if cfg.TaskLog.OutputSampleEvery <= 0 {
+		cfg.TaskLog.OutputSampleEvery = 10
+	}
+	if cfg.Retention.IntervalMinutes <= 0 {
+		cfg.Retention.IntervalMinutes = 60
+	}
+	if cfg.Retention.BatchSize <= 0 {
+		cfg.Retention.BatchSize = 100
+	}
+	if cfg.Retention.ProgressKeyHours <= 0 {
+		cfg.Retention.ProgressKeyHours = 24
+	}
+	if cfg.Backup.Dir == "" {
+		cfg.Backup.Dir = "backups"
+	}
+	if cfg.Backup.Keep <= 0 {
+		cfg.Backup.Keep = 7
+	}
+	if cfg.Backup.PgDumpPath == "" {
+		cfg.Backup.PgDumpPath = "pg_dump"
+	}
+	if cfg.Backup.PgRestorePath == "" {
+		cfg.Backup.PgRestorePath = "pg_restore"
+	}
+	if cfg.Backup.TimeoutMinutes <= 0 {
+		cfg.Backup.TimeoutMinutes = 30
+	}
+	if cfg.Cluster.NodeID == "" {
+		hostname, _ := os.Hostname()
+		cfg.Cluster.NodeID = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+	if cfg.Cluster.HeartbeatSeconds <= 0 {
+		cfg.Cluster.HeartbeatSeconds = 10
+	}
+	if cfg.Cluster.EventHistoryLimit <= 0 {
+		cfg.Cluster.EventHistoryLimit = 2000
+	}
+	if cfg.Cluster.FinishedTTLMinutes <= 0 {
+		cfg.Cluster.FinishedTTLMinutes = 60
+	}
+	if cfg.Worker.HandshakeTimeoutSeconds == 0 {
+		cfg.Worker.HandshakeTimeoutSeconds = 30
+	}
+	if cfg.Worker.StopGraceSeconds <= 0 {
+		cfg.Worker.StopGraceSeconds = 10
+	}
+	if cfg.Worker.HeartbeatWarnSeconds <= 0 {
+		cfg.Worker.HeartbeatWarnSeconds = 120
+	}
+	if cfg.Worker.StallTimeoutSeconds <= 0 {
+		cfg.Worker.StallTimeoutSeconds = 900
+	}
+	if cfg.Worker.StallAction == "" {
+		cfg.Worker.StallAction = StallActionFail
+	}
+	if cfg.Worker.MaxStallRestarts < 0 {
+		cfg.Worker.MaxStallRestarts = 0
+	}
+	if cfg.Worker.MaxRetries < 0 {
+		cfg.Worker.MaxRetries = 0
+	}
+	if cfg.Worker.RetryBackoffSeconds <= 0 {
+		cfg.Worker.RetryBackoffSeconds = 30
+	}
+	if cfg.Worker.RetryMaxBackoffSeconds <= 0 {
+		cfg.Worker.RetryMaxBackoffSeconds = 600
+	}
+	if cfg.Worker.DefaultMaxRuntimeMinutes < 0 {
+		cfg.Worker.DefaultMaxRuntimeMinutes = 0
+	}
+	if cfg.Worker.MaxRuntimeMinutesLimit < 0 {
+		cfg.Worker.MaxRuntimeMinutesLimit = 0
+	}
+	if cfg.Worker.GRPCAddr == "" {
+		cfg.Worker.GRPCAddr = "127.0.0.1:50051"
+	}
+	if cfg.Worker.Executor == "" {
+		cfg.Worker.Executor = WorkerExecutorLocal
+	}
+	if cfg.Worker.AgentPollSeconds <= 0 {
+		cfg.Worker.AgentPollSeconds = 2
+	}
+	if cfg.Worker.AgentOfflineSeconds <= 0 {
+		cfg.Worker.AgentOfflineSeconds = 30
+	}
+	for i := range cfg.Plugins {
+		if cfg.Plugins[i].TimeoutSeconds <= 0 {
+			cfg.Plugins[i].TimeoutSeconds = 60
+		}
+		if ext := cfg.Plugins[i].Extension; ext == "" {
+			cfg.Plugins[i].Extension = "." + cfg.Plugins[i].Format
+		} else if !strings.HasPrefix(ext, ".") {
+			cfg.Plugins[i].Extension = "." + ext
+		}
+	}
 }
 
 // validateConfig 验证配置
@@ -134,31 +386,137 @@ func validateConfig(cfg *Config) error {
 		return fmt.Errorf("JWT密钥不能为空")
 	}
 
+	if _, err := time.LoadLocation(cfg.Server.DefaultTimezone); err != nil {
+		return fmt.Errorf("无效的默认时区: %s", cfg.Server.DefaultTimezone)
+	}
+
 	if cfg.Admin.Password == "" {
 		return fmt.Errorf("管理员密码不能为空")
 	}
 
-	// 检查数据库目录是否存在
-	dbDir := filepath.Dir(cfg.Database.Path)
-	if _, err := os.Stat(dbDir); os.IsNotExist(err) {
-		if err := os.MkdirAll(dbDir, 0755); err != nil {
-			return fmt.Errorf("创建数据库目录失败: %w", err)
+	if cfg.Worker.StallAction != StallActionFail && cfg.Worker.StallAction != StallActionRestart {
+		return fmt.Errorf("无效的 stall_action: %s（可选 fail、restart）", cfg.Worker.StallAction)
+	}
+
+	switch cfg.Worker.Executor {
+	case WorkerExecutorLocal:
+	case WorkerExecutorAgent:
+		if !cfg.Worker.GRPCEnabled {
+			return fmt.Errorf("executor 为 agent 时需要开启 grpc_enabled（工作节点通过 gRPC 领取任务）")
+		}
+		// gRPC 服务对工作节点开放，内部密钥是唯一的认证手段，不能使用公开的默认值
+		if utils.IsDefaultInternalAPIKey() {
+			return fmt.Errorf("executor 为 agent 时必须通过 INTERNAL_API_KEY 环境变量设置内部密钥，不能使用默认密钥")
+		}
+	default:
+		return fmt.Errorf("无效的 executor: %s（可选 local、agent）", cfg.Worker.Executor)
+	}
+
+	if (cfg.Worker.GRPCTLSCert == "") != (cfg.Worker.GRPCTLSKey == "") {
+		return fmt.Errorf("grpc_tls_cert 和 grpc_tls_key 需要同时配置")
+	}
+
+	if limit := cfg.Worker.MaxRuntimeMinutesLimit; limit > 0 && cfg.Worker.DefaultMaxRuntimeMinutes > limit {
+		return fmt.Errorf("default_max_runtime_minutes (%d) 不能超过 max_runtime_minutes_limit (%d)", cfg.Worker.DefaultMaxRuntimeMinutes, limit)
+	}
+
+	if cfg.Secrets.RefreshMinutes < 0 {
+		return fmt.Errorf("secrets.refresh_minutes 不能为负数")
+	}
+
+	for _, route := range []string{RateLimitStart, RateLimitUpload, RateLimitExport, RateLimitModelCall} {
+		rule := cfg.RateLimit.Rule(route)
+		if rule.UserPerMinute < 0 || rule.IPPerMinute < 0 || rule.Burst < 0 {
+			return fmt.Errorf("rate_limit.%s 的限流参数不能为负数", route)
+		}
+	}
+
+	if cfg.Password.BcryptCost < 4 || cfg.Password.BcryptCost > 31 {
+		return fmt.Errorf("无效的 bcrypt_cost: %d（允许范围 4-31）", cfg.Password.BcryptCost)
+	}
+
+	switch cfg.Database.Driver {
+	case DatabaseDriverSQLite:
+		// 检查数据库目录是否存在
+		dbDir := filepath.Dir(cfg.Database.Path)
+		if _, err := os.Stat(dbDir); os.IsNotExist(err) {
+			if err := os.MkdirAll(dbDir, 0755); err != nil {
+				return fmt.Errorf("创建数据库目录失败: %w", err)
+			}
+		}
+	case DatabaseDriverPostgres:
+		if cfg.Database.DSN == "" {
+			return fmt.Errorf("使用 postgres 时数据库连接串 dsn 不能为空")
+		}
+	default:
+		return fmt.Errorf("不支持的数据库类型: %s", cfg.Database.Driver)
+	}
+
+	if cfg.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("无效的链路追踪采样比例: %v", cfg.Tracing.SampleRatio)
+	}
+
+	for _, pattern := range cfg.Safety.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("无效的内容安全正则表达式 %q: %w", pattern, err)
+		}
+	}
+
+	if err := validatePlugins(cfg.Plugins); err != nil {
+		return err
+	}
+
+	for name, text := range map[string]string{"title_template": cfg.Notify.TitleTemplate, "body_template": cfg.Notify.BodyTemplate} {
+		if _, err := template.New(name).Parse(text); err != nil {
+			return fmt.Errorf("无效的通知模板 notification.%s: %w", name, err)
 		}
 	}
 
 	return nil
 }
 
-// GetConfig 获取全局配置
-func GetConfig() *Config {
-	return globalConfig
+// builtinExportFormats 内置导出格式，插件不能使用这些格式名
+var builtinExportFormats = map[string]bool{
+	"jsonl": true, "csv": true, "sharegpt": true, "openai_chat": true, "alpaca": true, "parquet": true,
 }
 
-// ReloadConfig 重新加载配置
-func ReloadConfig() (*Config, error) {
-	if configPath == "" {
-		return nil, fmt.Errorf("未设置配置文件路径")
+// validatePlugins 校验插件配置
+func validatePlugins(plugins []PluginConfig) error {
+	formats := make(map[string]bool)
+	for i, p := range plugins {
+		if p.Name == "" {
+			return fmt.Errorf("plugins[%d] 缺少 name", i)
+		}
+		switch p.Stage {
+		case "preprocess", "postprocess":
+		case "export":
+			if p.Format == "" {
+				return fmt.Errorf("插件 %s: export 插件需要指定 format", p.Name)
+			}
+			if builtinExportFormats[p.Format] || formats[p.Format] {
+				return fmt.Errorf("插件 %s: 导出格式 %s 已存在", p.Name, p.Format)
+			}
+			formats[p.Format] = true
+		default:
+			return fmt.Errorf("插件 %s: 无效的 stage: %s（可选 preprocess、postprocess、export）", p.Name, p.Stage)
+		}
+		switch p.Type {
+		case "exec":
+			if len(p.Command) == 0 {
+				return fmt.Errorf("插件 %s: exec 插件需要指定 command", p.Name)
+			}
+		case "webhook":
+			if !strings.HasPrefix(p.URL, "http://") && !strings.HasPrefix(p.URL, "https://") {
+				return fmt.Errorf("插件 %s: webhook 插件需要指定 http(s) url", p.Name)
+			}
+		default:
+			return fmt.Errorf("插件 %s: 无效的 type: %s（可选 exec、webhook）", p.Name, p.Type)
+		}
 	}
+	return nil
+}
 
-	return LoadConfig(configPath)
+// GetConfig 获取当前生效的全局配置
+func GetConfig() *Config {
+	return globalConfig.Current()
 }
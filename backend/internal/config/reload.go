@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	reloadMu    sync.Mutex
+	reloadHooks []func(cfg *Config)
+	current     atomic.Pointer[Config] // 最近一次热加载或密钥轮换发布的配置，为空表示仍使用启动时加载的配置
+)
+
+// Current 获取当前生效的配置
+// 启动时加载的全局配置不会被原地修改：热加载和密钥轮换复制一份修改后整体发布，服务持有启动时的 *Config，
+// 每次使用时通过 Current 读取最新发布的配置；其他 Config（如测试中直接构造的）返回自身
+func (c *Config) Current() *Config {
+	if c != nil && c == globalConfig {
+		if cur := current.Load(); cur != nil {
+			return cur
+		}
+	}
+	return c
+}
+
+// publish 发布新的配置并依次执行 OnReload 回调（调用方持有重载锁）
+func publish(cfg *Config) {
+	current.Store(cfg)
+	for _, hook := range reloadHooks {
+		hook(cfg)
+	}
+}
+
+// reloadableSections 整段可热加载的配置（服务在每次使用时读取配置，替换后立即生效）
+var reloadableSections = map[string]bool{
+	"cors":            true,
+	"model_services":  true,
+	"password_policy": true,
+	"import":          true,
+	"rate_limit":      true,
+	"virus_scan":      true,
+}
+
+// ReloadResult 重新加载配置的结果
+type ReloadResult struct {
+	Applied         []string // 已生效的配置段
+	RequiresRestart []string // 有修改但需要重启才能生效的配置段
+}
+
+// OnReload 注册配置重新加载后的回调（如更新限流器的等待时间），回调在持有重载锁时依次执行
+func OnReload(fn func(cfg *Config)) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	reloadHooks = append(reloadHooks, fn)
+}
+
+// ReloadConfig 重新读取配置文件，将可热加载的配置合并到当前配置的副本后整体发布
+// 只在新配置完整通过校验后才发布；每个配置段整体替换，不会修改正在使用的切片或映射
+func ReloadConfig() (*ReloadResult, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	if configPath == "" || globalConfig == nil {
+		return nil, fmt.Errorf("未设置配置文件路径")
+	}
+
+	next, err := loadConfigFromFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *globalConfig.Current()
+	result := applyReloadable(&updated, next)
+	publish(&updated)
+	return result, nil
+}
+
+// applyReloadable 将新配置中可热加载的部分写入 cur，返回生效和需要重启的配置段
+func applyReloadable(cur, next *Config) *ReloadResult {
+	result := &ReloadResult{Applied: []string{}, RequiresRestart: []string{}}
+
+	// 部分字段可热加载的配置段：连接地址、监听端口等仍需重启
+	server := next.Server
+	server.Host, server.Port, server.ProductionMode, server.PreflightStrict = cur.Server.Host, cur.Server.Port, cur.Server.ProductionMode, cur.Server.PreflightStrict
+	applySection(result, "server", &cur.Server, server, next.Server)
+
+	redisCfg := next.Redis
+	redisCfg.Host, redisCfg.Port, redisCfg.DB, redisCfg.Password = cur.Redis.Host, cur.Redis.Port, cur.Redis.DB, cur.Redis.Password
+	applySection(result, "redis_service", &cur.Redis, redisCfg, next.Redis)
+
+	upload := next.Upload
+	upload.TempDir, upload.StorageDir = cur.Upload.TempDir, cur.Upload.StorageDir
+	applySection(result, "upload", &cur.Upload, upload, next.Upload)
+
+	worker := next.Worker
+	worker.GRPCEnabled, worker.GRPCAddr, worker.Executor = cur.Worker.GRPCEnabled, cur.Worker.GRPCAddr, cur.Worker.Executor
+	worker.GRPCTLSCert, worker.GRPCTLSKey, worker.GRPCTLSCA, worker.GRPCTLSServerName = cur.Worker.GRPCTLSCert, cur.Worker.GRPCTLSKey, cur.Worker.GRPCTLSCA, cur.Worker.GRPCTLSServerName
+	applySection(result, "worker", &cur.Worker, worker, next.Worker)
+
+	partial := map[string]bool{"server": true, "redis_service": true, "upload": true, "worker": true}
+	curValue := reflect.ValueOf(cur).Elem()
+	nextValue := reflect.ValueOf(next).Elem()
+	for i := 0; i < curValue.NumField(); i++ {
+		name := curValue.Type().Field(i).Tag.Get("mapstructure")
+		if partial[name] {
+			continue
+		}
+		field, nextField := curValue.Field(i), nextValue.Field(i)
+		if reflect.DeepEqual(field.Interface(), nextField.Interface()) {
+			continue
+		}
+		if reloadableSections[name] {
+			field.Set(nextField)
+			result.Applied = append(result.Applied, name)
+		} else {
+			result.RequiresRestart = append(result.RequiresRestart, name)
+		}
+	}
+	return result
+}
+
+// applySection 用 reloaded（只包含可热加载字段的修改）替换 *section，full 与 reloaded 不同说明还有需要重启的修改
+func applySection[T any](result *ReloadResult, name string, section *T, reloaded, full T) {
+	if !reflect.DeepEqual(*section, reloaded) {
+		*section = reloaded
+		result.Applied = append(result.Applied, name)
+	}
+	if !reflect.DeepEqual(reloaded, full) {
+		result.RequiresRestart = append(result.RequiresRestart, name)
+	}
+}
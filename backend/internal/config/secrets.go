@@ -0,0 +1,172 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gen-go/pkg/secrets"
+)
+
+// SecretRefPrefix 模型配置的 API Key 等字段以该前缀开头时，使用密钥存储中对应键的值（如 secret://openai_api_key）
+const SecretRefPrefix = "secret://"
+
+// secretsFetchTimeout 读取密钥存储的超时时间
+const secretsFetchTimeout = 15 * time.Second
+
+// secretFields 密钥存储中的键与配置项的对应关系，键存在且不为空时覆盖配置文件中的值
+var secretFields = []struct {
+	key   string
+	field func(cfg *Config) *string
+}{
+	{"jwt_secret_key", func(cfg *Config) *string { return &cfg.JWT.SecretKey }},
+	{"admin_password", func(cfg *Config) *string { return &cfg.Admin.Password }},
+	{"database_dsn", func(cfg *Config) *string { return &cfg.Database.DSN }},
+	{"redis_password", func(cfg *Config) *string { return &cfg.Redis.Password }},
+	{"smtp_password", func(cfg *Config) *string { return &cfg.Notify.SMTP.Password }},
+}
+
+var (
+	secretsMu    sync.RWMutex
+	secretValues map[string]string // 最近一次读取的全部键值，供 ResolveSecret 使用
+)
+
+// ResolveSecret 解析 secret:// 引用，返回密钥存储中对应键的值；不是引用或键不存在时原样返回
+func ResolveSecret(value string) string {
+	if !strings.HasPrefix(value, SecretRefPrefix) {
+		return value
+	}
+	key := strings.TrimPrefix(value, SecretRefPrefix)
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	if secret, ok := secretValues[key]; ok {
+		return secret
+	}
+	log.Printf("[Secrets] 密钥存储中不存在键 %s", key)
+	return value
+}
+
+// applySecrets 从密钥存储读取密钥并写入 cfg（在校验配置之前执行，密钥可以不写在配置文件中）
+func applySecrets(cfg *Config) error {
+	if cfg.Secrets.Provider == "" {
+		return nil
+	}
+	values, err := fetchSecrets(cfg.Secrets)
+	if err != nil {
+		return err
+	}
+	applySecretValues(cfg, values)
+	return nil
+}
+
+// fetchSecrets 按配置创建密钥存储并读取全部键值，成功后更新 ResolveSecret 使用的缓存
+func fetchSecrets(sc SecretsConfig) (map[string]string, error) {
+	provider, err := newSecretsProvider(sc)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretsFetchTimeout)
+	defer cancel()
+	values, err := provider.Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("从 %s 读取密钥失败: %w", provider.Name(), err)
+	}
+
+	secretsMu.Lock()
+	secretValues = values
+	secretsMu.Unlock()
+	return values, nil
+}
+
+// newSecretsProvider 创建密钥存储，未配置的连接参数从标准环境变量读取
+func newSecretsProvider(sc SecretsConfig) (secrets.Provider, error) {
+	switch sc.Provider {
+	case SecretsProviderVault:
+		v := sc.Vault
+		return secrets.NewVaultProvider(
+			firstNonEmpty(v.Address, os.Getenv("VAULT_ADDR")),
+			firstNonEmpty(v.Token, os.Getenv("VAULT_TOKEN")),
+			firstNonEmpty(v.Namespace, os.Getenv("VAULT_NAMESPACE")),
+			v.Mount,
+			v.Path,
+		)
+	case SecretsProviderAWS:
+		a := sc.AWS
+		return secrets.NewAWSProvider(
+			a.Endpoint,
+			firstNonEmpty(a.Region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION")),
+			a.SecretID,
+			firstNonEmpty(a.AccessKeyID, os.Getenv("AWS_ACCESS_KEY_ID")),
+			firstNonEmpty(a.SecretAccessKey, os.Getenv("AWS_SECRET_ACCESS_KEY")),
+			firstNonEmpty(a.SessionToken, os.Getenv("AWS_SESSION_TOKEN")),
+		)
+	default:
+		return nil, fmt.Errorf("不支持的密钥存储: %s（可选 vault、aws）", sc.Provider)
+	}
+}
+
+// applySecretValues 将密钥写入对应的配置项，返回值有变化的键
+func applySecretValues(cfg *Config, values map[string]string) []string {
+	var changed []string
+	for _, f := range secretFields {
+		value := values[f.key]
+		if value == "" {
+			continue
+		}
+		if field := f.field(cfg); *field != value {
+			*field = value
+			changed = append(changed, f.key)
+		}
+	}
+	return changed
+}
+
+// StartSecretsRefresh 按 secrets.refresh_minutes 定期重新读取密钥，轮换后的值写入当前配置的副本后发布并执行 OnReload 回调
+// JWT 密钥通过回调立即生效；数据库连接串和 Redis 密码在重启后生效，管理员密码只在创建初始管理员时使用
+func StartSecretsRefresh() {
+	if globalConfig == nil || globalConfig.Secrets.Provider == "" || globalConfig.Secrets.RefreshMinutes <= 0 {
+		return
+	}
+	interval := globalConfig.Secrets.GetRefreshInterval()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			refreshSecrets()
+		}
+	}()
+}
+
+// refreshSecrets 重新读取密钥，读取失败时继续使用当前的值
+func refreshSecrets() {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	updated := *globalConfig.Current()
+	values, err := fetchSecrets(updated.Secrets)
+	if err != nil {
+		log.Printf("[Secrets] 定期读取密钥失败，继续使用当前密钥: %v", err)
+		return
+	}
+	changed := applySecretValues(&updated, values)
+	if len(changed) == 0 {
+		return
+	}
+	log.Printf("[Secrets] 密钥已轮换: %v", changed)
+	publish(&updated)
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
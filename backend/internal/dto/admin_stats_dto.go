@@ -0,0 +1,36 @@
+package dto
+
+// AdminStatsResponse 管理员仪表盘统计
+type AdminStatsResponse struct {
+	ActiveTasks    int64               `json:"active_tasks"`
+	WindowDays     int                 `json:"window_days"`     // tasks_by_status 的统计窗口（天）
+	TasksByStatus  map[string]int64    `json:"tasks_by_status"` // 窗口内开始的任务按状态的数量
+	GeneratedItems int64               `json:"generated_items"`
+	ModelUsage     []ModelUsageStat    `json:"model_usage"`
+	UserUsage      []UserCharStat      `json:"user_usage"`
+	DataFiles      DataFileStorageStat `json:"data_files"`
+	GeneratedAt    string              `json:"generated_at"`
+}
+
+// ModelUsageStat 单个生成模型的使用统计
+type ModelUsageStat struct {
+	Model string `json:"model"`
+	Tasks int64  `json:"tasks"`
+	Items int64  `json:"items"`
+}
+
+// UserCharStat 单个用户的字符消耗
+type UserCharStat struct {
+	UserID      uint   `json:"user_id"`
+	Username    string `json:"username"`
+	Tasks       int64  `json:"tasks"`
+	InputChars  int64  `json:"input_chars"`
+	OutputChars int64  `json:"output_chars"`
+	TotalChars  int64  `json:"total_chars"`
+}
+
+// DataFileStorageStat 数据文件存储占用
+type DataFileStorageStat struct {
+	Files int64 `json:"files"`
+	Bytes int64 `json:"bytes"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import "encoding/json"
+
+// AuditLogResponse 审计日志响应
+type AuditLogResponse struct {
+	ID           uint            `json:"id"`
+	UserID       uint            `json:"user_id"`
+	Username     string          `json:"username"`
+	Action       string          `json:"action"`
+	ResourceType string          `json:"resource_type"`
+	ResourceID   string          `json:"resource_id"`
+	Before       json.RawMessage `json:"before"`
+	After        json.RawMessage `json:"after"`
+	Endpoint     string          `json:"endpoint"`
+	ClientIP     string          `json:"client_ip"`
+	CreatedAt    string          `json:"created_at"`
+}
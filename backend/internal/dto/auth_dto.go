@@ -14,9 +14,21 @@ type LoginRequest struct {
 
 // LoginResponse 登录响应
 type LoginResponse struct {
-	AccessToken string   `json:"access_token"`
-	TokenType   string   `json:"token_type"`
-	User        UserInfo `json:"user"`
+	AccessToken  string   `json:"access_token"`
+	RefreshToken string   `json:"refresh_token,omitempty"` // 刷新Token，为空表示暂不可刷新（需重新登录）
+	TokenType    string   `json:"token_type"`
+	ExpiresIn    int64    `json:"expires_in"` // 访问Token有效期（秒）
+	User         UserInfo `json:"user"`
+}
+
+// RefreshTokenRequest 刷新Token请求
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest 登出请求（可选携带刷新Token以一并吊销）
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
 // UserInfo 用户信息
@@ -25,4 +37,35 @@ type UserInfo struct {
 	Username string `json:"username"`
 	IsActive bool   `json:"is_active"`
 	IsAdmin  bool   `json:"is_admin"`
+	Role     string `json:"role"`     // viewer/reviewer/operator/admin
+	Timezone string `json:"timezone"` // 展示时区，为空表示使用系统默认时区
+
+	MustChangePassword bool `json:"must_change_password"` // 需修改密码后才能使用其他接口
+}
+
+// ChangePasswordRequest 修改密码请求
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// ResetPasswordRequest 管理员重置密码请求
+type ResetPasswordRequest struct {
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// UpdateUserRoleRequest 设置用户角色请求（管理员）
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required"` // viewer/reviewer/operator/admin
+}
+
+// RoleInfo 角色及其权限
+type RoleInfo struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+// UpdateTimezoneRequest 设置展示时区请求
+type UpdateTimezoneRequest struct {
+	Timezone string `json:"timezone"` // IANA 时区名称，如 Asia/Shanghai；为空表示恢复默认
 }
@@ -0,0 +1,9 @@
+package dto
+
+// BackupInfo 数据库备份文件信息
+type BackupInfo struct {
+	Name      string `json:"name"`
+	Driver    string `json:"driver"` // sqlite 或 postgres
+	Size      int64  `json:"size"`   // 字节
+	CreatedAt string `json:"created_at"`
+}
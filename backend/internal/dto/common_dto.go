@@ -2,24 +2,24 @@ package dto
 
 // PaginatedResponse 分页响应
 type PaginatedResponse struct {
-	Items  interface{} `json:"data"`
-	Total  int64       `json:"total"`
-	Page   int         `json:"page"`
-	PerPage int        `json:"per_page"`
+	Items   interface{} `json:"data"`
+	Total   int64       `json:"total"`
+	Page    int         `json:"page"`
+	PerPage int         `json:"per_page"`
 }
 
 // FileListResponse 文件列表响应
 type FileListResponse struct {
-	Success bool                `json:"success"`
-	Files   []DataFileResponse  `json:"files"`
-	Total   int64               `json:"total"`
+	Success bool               `json:"success"`
+	Files   []DataFileResponse `json:"files"`
+	Total   int64              `json:"total"`
 }
 
 // ModelListResponse 模型列表响应
 type ModelListResponse struct {
-	Success bool                 `json:"success"`
+	Success bool                  `json:"success"`
 	Models  []ModelConfigResponse `json:"models"`
-	Total   int64                `json:"total"`
+	Total   int64                 `json:"total"`
 }
 
 // GeneratedDataListResponse 生成数据列表响应
@@ -38,24 +38,31 @@ type ReportListResponse struct {
 
 // Report 报告
 type Report struct {
-	TaskID      string `json:"task_id"`
-	UserID      uint   `json:"user_id"`
-	Status      string `json:"status"`
-	TotalCount  int    `json:"total_count"`
-	ConfirmedCount int `json:"confirmed_count"`
-	CreatedAt   string `json:"created_at"`
+	TaskID         string `json:"task_id"`
+	UserID         uint   `json:"user_id"`
+	Status         string `json:"status"`
+	TotalCount     int    `json:"total_count"`
+	ConfirmedCount int    `json:"confirmed_count"`
+	CreatedAt      string `json:"created_at"`
 }
 
 // ReportDataResponse 报告数据响应
 type ReportDataResponse struct {
-	TaskID      string                   `json:"task_id"`
-	Data        []map[string]interface{} `json:"data"`
-	Total       int                      `json:"total"`
+	TaskID string                   `json:"task_id"`
+	Data   []map[string]interface{} `json:"data"`
+	Total  int                      `json:"total"`
 }
 
 // ConvertFilesResponse 转换文件响应
 type ConvertFilesResponse struct {
 	Success bool               `json:"success"`
 	Files   []DataFileResponse `json:"files"`
+	Errors  []ConvertFileError `json:"errors,omitempty"`
 	Message string             `json:"message"`
 }
+
+// ConvertFileError 单个文件转换失败的原因
+type ConvertFileError struct {
+	FileID uint   `json:"file_id"`
+	Error  string `json:"error"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+// ConfigReloadResponse 重新加载配置的结果
+type ConfigReloadResponse struct {
+	Applied         []string `json:"applied"`          // 已生效的配置段
+	RequiresRestart []string `json:"requires_restart"` // 有修改但需要重启服务才能生效的配置段
+}
@@ -2,21 +2,29 @@ package dto
 
 // DataFileResponse 文件响应
 type DataFileResponse struct {
-	ID          uint   `json:"id"`
-	Filename    string `json:"filename"`
-	FileSize    int    `json:"file_size"`
-	ContentType string `json:"content_type"`
-	UserID      uint   `json:"user_id"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	ID            uint     `json:"id"`
+	Filename      string   `json:"filename"`
+	Description   string   `json:"description"`
+	DisplayPath   string   `json:"display_path"` // 启动任务时使用的输入文件路径（db://file_id/filename）
+	FileSize      int      `json:"file_size"`
+	ContentType   string   `json:"content_type"`
+	UserID        uint     `json:"user_id"`
+	WorkspaceID   *uint    `json:"workspace_id"`             // 所属工作区，为空表示个人文件
+	ConvertedFrom *uint    `json:"converted_from,omitempty"` // 转换来源文件ID
+	FolderPath    string   `json:"folder_path"`              // 所在文件夹，为空表示根目录
+	Tags          []string `json:"tags"`
+	CreatedAt     string   `json:"created_at"`
+	UpdatedAt     string   `json:"updated_at"`
 }
 
 // DataFileContentResponse 文件内容响应
 type DataFileContentResponse struct {
-	ID        uint                   `json:"id"`
-	Filename  string                 `json:"filename"`
-	Content   []map[string]interface{} `json:"content"`
-	Total     int                    `json:"total"`
+	ID       uint                     `json:"id"`
+	Filename string                   `json:"filename"`
+	Content  []map[string]interface{} `json:"content"`
+	Total    int                      `json:"total"`
+	Page     int                      `json:"page,omitempty"`
+	PerPage  int                      `json:"per_page,omitempty"`
 }
 
 // DataFileItem 文件数据项（带索引）
@@ -31,6 +39,49 @@ type DataFileContentEditableResponse struct {
 	Filename   string         `json:"filename"`
 	TotalLines int            `json:"total_lines"`
 	Items      []DataFileItem `json:"items"`
+	Page       int            `json:"page,omitempty"`
+	PerPage    int            `json:"per_page,omitempty"`
+}
+
+// FileContentQuery 文件内容分页查询参数（PerPage 为 0 表示返回全部）
+type FileContentQuery struct {
+	Page    int
+	PerPage int
+	Query   string
+}
+
+// Offset 计算分页偏移量
+func (q FileContentQuery) Offset() int {
+	if q.PerPage <= 0 || q.Page <= 1 {
+		return 0
+	}
+	return (q.Page - 1) * q.PerPage
+}
+
+// FileSampleQuery 文件抽样查询参数
+type FileSampleQuery struct {
+	N        int
+	Strategy string // head、random 或 stratified
+	Seed     *int64 // 为空时随机生成，响应中返回实际使用的种子
+	Field    string // stratified 分层依据的 meta 字段，为空时按整个 meta 分层
+}
+
+// FileSampleStratum 分层抽样中的一层
+type FileSampleStratum struct {
+	Key     string `json:"key"`
+	Total   int    `json:"total"`
+	Sampled int    `json:"sampled"`
+}
+
+// FileSampleResponse 文件抽样响应
+type FileSampleResponse struct {
+	FileID   uint                `json:"file_id"`
+	Filename string              `json:"filename"`
+	Strategy string              `json:"strategy"`
+	Seed     *int64              `json:"seed,omitempty"` // random、stratified 使用的随机种子，传回 seed 参数可复现相同的样本
+	Total    int                 `json:"total"`          // 文件总行数
+	Items    []DataFileItem      `json:"items"`
+	Strata   []FileSampleStratum `json:"strata,omitempty"`
 }
 
 // UpdateFileContentRequest 更新文件内容请求
@@ -50,14 +101,17 @@ type BatchDeleteRequest struct {
 }
 
 // BatchDownloadRequest 批量下载请求
+// format 为所有文件的默认格式（raw、csv、xlsx 或训练框架格式），formats 可按文件ID单独指定
 type BatchDownloadRequest struct {
-	IDs []uint `json:"ids" binding:"required"`
+	IDs     []uint          `json:"ids" binding:"required,min=1"`
+	Format  string          `json:"format"`
+	Formats map[uint]string `json:"formats"`
 }
 
 // BatchConvertRequest 批量转换请求
 type BatchConvertRequest struct {
 	FileIDs      []uint `json:"file_ids" binding:"required"`
-	TargetFormat string `json:"target_format" binding:"required,oneof=jsonl csv"`
+	TargetFormat string `json:"target_format" binding:"omitempty,oneof=jsonl csv"` // 默认 csv
 }
 
 // ConvertFilesRequest 上传并转换请求
@@ -65,3 +119,71 @@ type ConvertFilesRequest struct {
 	Files        []string `json:"files" binding:"required"`
 	TargetFormat string   `json:"target_format" binding:"required,oneof=jsonl csv"`
 }
+
+// FileTaskInfo 使用某个文件作为输入的任务信息
+type FileTaskInfo struct {
+	TaskID         string  `json:"task_id"`
+	Status         string  `json:"status"`
+	TaskType       string  `json:"task_type"`
+	ModelPath      string  `json:"model_path"`
+	DataCount      int64   `json:"data_count"`
+	ConfirmedCount int64   `json:"confirmed_count"`
+	StartedAt      string  `json:"started_at"`
+	FinishedAt     *string `json:"finished_at"`
+}
+
+// FileTaskListResponse 文件关联任务列表响应
+type FileTaskListResponse struct {
+	FileID   uint           `json:"file_id"`
+	Filename string         `json:"filename"`
+	Tasks    []FileTaskInfo `json:"tasks"`
+	Total    int            `json:"total"`
+}
+
+// UpdateDataFileRequest 修改文件信息请求（字段为空时不修改）
+// filename 在文件所有者的文件中必须唯一且不能修改扩展名；tags 会替换文件的全部标签
+type UpdateDataFileRequest struct {
+	Filename    *string   `json:"filename" binding:"omitempty,max=255"`
+	Description *string   `json:"description" binding:"omitempty,max=500"`
+	Tags        *[]string `json:"tags"`
+}
+
+// UpdateFileMetadataRequest 修改文件元数据请求（字段为空时不修改）
+// folder_path 用 "/" 分隔多级文件夹，空字符串表示移到根目录；tags 会替换文件的全部标签
+type UpdateFileMetadataRequest struct {
+	FolderPath *string   `json:"folder_path"`
+	Tags       *[]string `json:"tags"`
+}
+
+// BatchUpdateFileMetadataRequest 批量修改文件元数据请求
+type BatchUpdateFileMetadataRequest struct {
+	FileIDs    []uint   `json:"file_ids" binding:"required,min=1"`
+	FolderPath *string  `json:"folder_path"` // 非空时把文件移到该文件夹（空字符串表示根目录）
+	AddTags    []string `json:"add_tags"`
+	RemoveTags []string `json:"remove_tags"`
+}
+
+// FileMetadataResponse 文件元数据响应
+type FileMetadataResponse struct {
+	FileID     uint     `json:"file_id"`
+	FolderPath string   `json:"folder_path"`
+	Tags       []string `json:"tags"`
+}
+
+// BatchUpdateFileMetadataResponse 批量修改文件元数据响应
+type BatchUpdateFileMetadataResponse struct {
+	Updated int    `json:"updated"`
+	Skipped []uint `json:"skipped"` // 不存在或无权修改的文件ID
+}
+
+// FileTagInfo 标签及使用该标签的文件数
+type FileTagInfo struct {
+	Name      string `json:"name"`
+	FileCount int64  `json:"file_count"`
+}
+
+// FileFolderInfo 文件夹及其中的文件数
+type FileFolderInfo struct {
+	FolderPath string `json:"folder_path"`
+	FileCount  int64  `json:"file_count"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+// DataFileVersionResponse 数据文件版本响应
+type DataFileVersionResponse struct {
+	ID            uint   `json:"id"`
+	FileID        uint   `json:"file_id"`
+	Version       int    `json:"version"` // 在 StartTaskRequest.file_version 中引用
+	FileSize      int    `json:"file_size"`
+	LineCount     int    `json:"line_count"`
+	Action        string `json:"action"`
+	AddedLines    int    `json:"added_lines"`
+	RemovedLines  int    `json:"removed_lines"`
+	ModifiedLines int    `json:"modified_lines"`
+	Summary       string `json:"summary"`
+	RestoredFrom  *int   `json:"restored_from,omitempty"`
+	IsCurrent     bool   `json:"is_current"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// DataFileVersionListResponse 数据文件版本历史
+type DataFileVersionListResponse struct {
+	FileID         uint                      `json:"file_id"`
+	CurrentVersion int                       `json:"current_version"`
+	Versions       []DataFileVersionResponse `json:"versions"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+// 数据文件导入来源
+const (
+	ImportSourceURL         = "url"
+	ImportSourceHuggingFace = "huggingface"
+)
+
+// ImportColumnMapping 外部数据集字段到 meta/turns 结构的映射（为空时按常见字段名自动识别）
+type ImportColumnMapping struct {
+	Meta       string   `json:"meta"`        // 作为 meta_description 的字段
+	MetaFields []string `json:"meta_fields"` // 按原字段名复制到 meta 中的其他字段
+	Human      []string `json:"human"`       // 拼接为 Human 内容的字段（多个字段以空行连接，如 instruction + input）
+	Assistant  string   `json:"assistant"`   // 作为 Assistant 内容的字段
+	Turns      []string `json:"turns"`       // 多轮对话的列，按顺序交替作为 Human、Assistant（如 q1, a1, q2, a2），设置后忽略 human/assistant
+	Messages   string   `json:"messages"`    // 对话数组字段（元素含 role/content 或 from/value），设置后忽略 human/assistant/turns
+}
+
+// ImportDataFileRequest 从远程 URL 或 Hugging Face 数据集导入数据文件请求
+type ImportDataFileRequest struct {
+	Source string `json:"source" binding:"required,oneof=url huggingface"`
+	// source 为 url 时：HTTP(S) 地址
+	URL string `json:"url"`
+	// source 为 huggingface 时：数据集路径（如 tatsu-lab/alpaca）、子集（默认 default）和划分（默认 train）
+	Dataset string `json:"dataset"`
+	Config  string `json:"config"`
+	Split   string `json:"split"`
+	// 源数据格式：jsonl（含 JSON 数组）、csv、parquet，为空时按 URL 后缀判断（Hugging Face 固定为 parquet）
+	Format   string               `json:"format" binding:"omitempty,oneof=jsonl csv parquet"`
+	Mapping  *ImportColumnMapping `json:"mapping"`
+	Filename string               `json:"filename"` // 保存的文件名，为空时按来源生成
+	MaxRows  int                  `json:"max_rows"` // 最多导入的行数，0 表示使用配置上限
+	// 结构校验模式：none, report, reject, quarantine（为空时使用配置默认值）
+	ValidationMode string `json:"validation_mode" binding:"omitempty,oneof=none report reject quarantine"`
+}
+
+// ImportDataFileResult 导入统计
+type ImportDataFileResult struct {
+	Imported      int   `json:"imported"`       // 转换并导入的行数
+	Skipped       int   `json:"skipped"`        // 没有对话内容而跳过的记录数
+	Truncated     bool  `json:"truncated"`      // 是否因达到行数上限而截断
+	DownloadBytes int64 `json:"download_bytes"` // 下载的源数据大小
+}
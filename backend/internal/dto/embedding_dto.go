@@ -0,0 +1,60 @@
+package dto
+
+// EmbeddingProxyRequest 向量接口代理请求（Python 工作进程和内部服务调用）
+type EmbeddingProxyRequest struct {
+	Model string   `json:"model"`                                  // 向量模型的名称或模型路径，为空时使用 model_services.embedding_model
+	Input []string `json:"input" binding:"required,min=1,max=256"` // 待向量化的文本
+}
+
+// EmbeddingProxyResponse 向量接口代理响应，向量与输入顺序一一对应
+type EmbeddingProxyResponse struct {
+	Success      bool        `json:"success"`
+	Model        string      `json:"model,omitempty"`
+	Dim          int         `json:"dim,omitempty"`
+	Embeddings   [][]float32 `json:"embeddings,omitempty"`
+	PromptTokens int         `json:"prompt_tokens,omitempty"`
+	Error        string      `json:"error,omitempty"`
+}
+
+// BuildEmbeddingIndexRequest 为数据文件或任务生成数据建立向量索引请求
+type BuildEmbeddingIndexRequest struct {
+	ModelID uint `json:"model_id"` // 向量模型配置 ID，为空时使用 model_services.embedding_model
+}
+
+// EmbeddingIndexResponse 向量索引进度
+type EmbeddingIndexResponse struct {
+	SourceType string  `json:"source_type"` // file 或 generated
+	FileID     uint    `json:"file_id,omitempty"`
+	TaskID     string  `json:"task_id,omitempty"`
+	ModelID    uint    `json:"model_id"`
+	Status     string  `json:"status"` // running, finished, error
+	Total      int     `json:"total"`
+	Embedded   int     `json:"embedded"` // 本次新向量化的条数
+	Skipped    int     `json:"skipped"`  // 内容未变、沿用已有向量的条数
+	Failed     int     `json:"failed"`
+	Percent    float64 `json:"percent"`
+	Indexed    int64   `json:"indexed"` // 索引中的向量总数
+	Error      string  `json:"error,omitempty"`
+	StartedAt  string  `json:"started_at"`
+	FinishedAt *string `json:"finished_at,omitempty"`
+}
+
+// SemanticSearchHit 语义检索命中的一条数据
+type SemanticSearchHit struct {
+	Type       string  `json:"type"` // file 或 generated
+	FileID     uint    `json:"file_id,omitempty"`
+	Filename   string  `json:"filename,omitempty"`
+	Line       int     `json:"line,omitempty"` // 文件中的条目序号（从1开始）
+	DataID     uint    `json:"data_id,omitempty"`
+	TaskID     string  `json:"task_id,omitempty"`
+	Similarity float64 `json:"similarity"` // 余弦相似度
+	Snippet    string  `json:"snippet"`
+}
+
+// SemanticSearchResponse 语义检索结果
+type SemanticSearchResponse struct {
+	Query   string              `json:"query"`
+	ModelID uint                `json:"model_id"`
+	Engine  string              `json:"engine"` // 向量检索方式：sqlite-vec、pgvector 或 scan
+	Hits    []SemanticSearchHit `json:"hits"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+// ErrorGroup 一组同类错误（按来源和错误类别聚合）
+type ErrorGroup struct {
+	Key       string `json:"key"`   // HTTP 错误为 "METHOD 路由"，模型错误为模型名
+	Class     string `json:"class"` // 错误类别（timeout、connection_refused、http_5xx 等）
+	Status    int    `json:"status,omitempty"`
+	Count     int64  `json:"count"`
+	FirstSeen string `json:"first_seen"`
+	LastSeen  string `json:"last_seen"`
+	Sample    string `json:"sample"` // 最近一次的错误信息
+}
+
+// TaskFailureGroup 按错误类别聚合的任务失败
+type TaskFailureGroup struct {
+	Class     string   `json:"class"`
+	Count     int64    `json:"count"`
+	FirstSeen string   `json:"first_seen"`
+	LastSeen  string   `json:"last_seen"`
+	Sample    string   `json:"sample"`
+	TaskIDs   []string `json:"task_ids"` // 最近失败的任务（最多10个）
+}
+
+// ErrorReportResponse 运维错误汇总
+// HTTP 和模型错误来自本进程内存统计（重启后清零），任务失败来自数据库
+type ErrorReportResponse struct {
+	Since         string             `json:"since"`
+	TrackingSince string             `json:"tracking_since"` // 内存统计的起始时间（进程启动时间）
+	HTTPErrors    []ErrorGroup       `json:"http_errors"`
+	TaskFailures  []TaskFailureGroup `json:"task_failures"`
+	ModelFailures []ErrorGroup       `json:"model_failures"`
+}
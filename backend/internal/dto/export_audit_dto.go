@@ -0,0 +1,26 @@
+package dto
+
+// ExportAuditResponse 导出审计记录响应
+type ExportAuditResponse struct {
+	ID           uint                   `json:"id"`
+	UserID       uint                   `json:"user_id"`
+	Username     string                 `json:"username"`
+	ResourceType string                 `json:"resource_type"`
+	ResourceID   string                 `json:"resource_id"`
+	OwnerID      *uint                  `json:"owner_id,omitempty"`
+	Format       string                 `json:"format"`
+	RowCount     int                    `json:"row_count"`
+	Filters      map[string]interface{} `json:"filters"`
+	Endpoint     string                 `json:"endpoint"`
+	ClientIP     string                 `json:"client_ip"`
+	CreatedAt    string                 `json:"created_at"`
+}
+
+// ExportAuditSummaryResponse 按用户汇总的导出统计
+type ExportAuditSummaryResponse struct {
+	UserID       uint   `json:"user_id"`
+	Username     string `json:"username"`
+	ExportCount  int64  `json:"export_count"`
+	TotalRows    int64  `json:"total_rows"`
+	LastExportAt string `json:"last_export_at"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+// FileColumnInfo 检测到的列（字段）信息
+type FileColumnInfo struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`      // 值类型: string, number, boolean, array, object, mixed（抽样行中出现多种类型）
+	NonEmpty int    `json:"non_empty"` // 抽样行中非空值的行数
+	Example  string `json:"example"`   // 第一个非空值（过长时截断）
+}
+
+// FileInspectResponse 上传文件的列检测结果，用于配置字段映射
+type FileInspectResponse struct {
+	Filename         string                   `json:"filename"`
+	Format           string                   `json:"format"`            // jsonl、csv、xlsx 或 parquet
+	SampledRows      int                      `json:"sampled_rows"`      // 用于检测的行数（最多读取前 200 行）
+	Columns          []FileColumnInfo         `json:"columns"`           // 按列名排序，后续行中新出现的列排在后面
+	Samples          []map[string]interface{} `json:"samples"`           // 前几行原始记录
+	SuggestedMapping ImportColumnMapping      `json:"suggested_mapping"` // 按常见字段名推荐的映射
+	Preview          []interface{}            `json:"preview"`           // 前几行按映射（请求中指定的映射，未指定时为推荐映射）转换后的 meta/turns 结构
+}
+
+// MappedUploadResult 按字段映射转换的统计
+type MappedUploadResult struct {
+	Converted int `json:"converted"` // 转换并保存的行数
+	Skipped   int `json:"skipped"`   // 没有对话内容而跳过的记录数
+}
@@ -2,18 +2,27 @@ package dto
 
 // GeneratedDataResponse 生成数据响应
 type GeneratedDataResponse struct {
-	ID              uint     `json:"id"`
-	TaskID          string   `json:"task_id"`
-	UserID          uint     `json:"user_id"`
-	DataContent     string   `json:"data_content"`
-	ModelScore      *float64 `json:"model_score"`
-	RuleScore       *int     `json:"rule_score"`
-	RetryCount      int      `json:"retry_count"`
-	GenerationModel string   `json:"generation_model"`
-	TaskType        string   `json:"task_type"`
-	IsConfirmed     bool     `json:"is_confirmed"`
-	CreatedAt       string   `json:"created_at"`
-	UpdatedAt       string   `json:"updated_at"`
+	ID              uint          `json:"id"`
+	TaskID          string        `json:"task_id"`
+	UserID          uint          `json:"user_id"`
+	DataContent     string        `json:"data_content"`
+	ModelScore      *float64      `json:"model_score"`
+	RuleScore       *int          `json:"rule_score"`
+	RuleFailures    []RuleFailure `json:"rule_failures,omitempty"`
+	RetryCount      int           `json:"retry_count"`
+	GenerationModel string        `json:"generation_model"`
+	TaskType        string        `json:"task_type"`
+	IsConfirmed     bool          `json:"is_confirmed"`
+	Tags            []string      `json:"tags"`
+	JudgeFeedback   string        `json:"judge_feedback,omitempty"`
+	SafetyFlags     []string      `json:"safety_flags"`
+	Similarity      *float64      `json:"similarity"`
+	ReviewStatus    string        `json:"review_status"`
+	ReviewerID      *uint         `json:"reviewer_id"`
+	ReviewComment   string        `json:"review_comment,omitempty"`
+	ReviewedAt      *string       `json:"reviewed_at"`
+	CreatedAt       string        `json:"created_at"`
+	UpdatedAt       string        `json:"updated_at"`
 }
 
 // UpdateGeneratedDataRequest 更新生成数据请求
@@ -50,10 +59,70 @@ type BatchDeleteGeneratedDataRequest struct {
 	DataIDs []uint `json:"data_ids" binding:"required"`
 }
 
+// DataChangesResponse 生成数据增量同步响应
+// Items 为游标之后新增或修改的数据（客户端按 ID 覆盖），Deleted 为已删除的数据ID；
+// Reset 为 true 时游标已超过删除记录的保留期，客户端应清空本地数据，Items 从头返回
+type DataChangesResponse struct {
+	TaskID     string                  `json:"task_id"`
+	Items      []GeneratedDataResponse `json:"items"`
+	Deleted    []uint                  `json:"deleted"`
+	NextCursor string                  `json:"next_cursor"`
+	HasMore    bool                    `json:"has_more"`
+	Reset      bool                    `json:"reset"`
+}
+
 // ExportRequest 导出请求
 type ExportRequest struct {
 	TaskID    string `json:"task_id"`
 	Confirmed bool   `json:"confirmed"`
-	Format    string `json:"format" binding:"required,oneof=jsonl csv"`
+	Format    string `json:"format" binding:"required,max=50"` // jsonl、csv、sharegpt、openai_chat、alpaca、parquet 或插件注册的格式
 	DataIDs   []uint `json:"data_ids"`
 }
+
+// StratumTarget 分层抽样的目标层
+// Dimension 取值：tag（Value 为 分类:标签）、task_type、score（Value 为 low/medium/high/unscored）
+type StratumTarget struct {
+	Dimension  string  `json:"dimension" binding:"required,oneof=tag task_type score"`
+	Value      string  `json:"value" binding:"required"`
+	Proportion float64 `json:"proportion" binding:"gt=0,lte=1"`
+}
+
+// StratifiedExportRequest 按比例分层抽样导出请求
+// Targets 为各层目标占比（如 40% 计算题、30% 改写），剩余名额由不属于任何目标层的数据补足；
+// Caps 为各层占比上限（如低分数据最多 10%）
+type StratifiedExportRequest struct {
+	TaskID  string          `json:"task_id" binding:"required"`
+	Format  string          `json:"format" binding:"omitempty,max=50"` // 同 ExportRequest.Format
+	Total   int             `json:"total" binding:"omitempty,min=1"`   // 目标条数，不指定时取满足比例的最大条数
+	Seed    int64           `json:"seed"`                              // 随机种子，相同种子和数据得到相同结果
+	Targets []StratumTarget `json:"targets" binding:"dive"`
+	Caps    []StratumTarget `json:"caps" binding:"dive"`
+	// IncludeFlagged 同时导出内容安全检查标记的数据（默认排除）
+	IncludeFlagged bool `json:"include_flagged"`
+}
+
+// StratumResult 分层抽样结果中单个层的请求与实际分布
+type StratumResult struct {
+	Dimension           string  `json:"dimension"`
+	Value               string  `json:"value"`
+	RequestedProportion float64 `json:"requested_proportion"`
+	Available           int     `json:"available"`
+	Selected            int     `json:"selected"`
+	AchievedProportion  float64 `json:"achieved_proportion"`
+}
+
+// ExportManifest 分层抽样导出清单，记录请求比例与实际达到的分布
+type ExportManifest struct {
+	TaskID         string                    `json:"task_id"`
+	Format         string                    `json:"format"`
+	Seed           int64                     `json:"seed"`
+	RequestedTotal int                       `json:"requested_total"`
+	Available      int                       `json:"available"`
+	Exported       int                       `json:"exported"`
+	Truncated      bool                      `json:"truncated"` // 可导出数据超过读取上限，只有前一部分参与抽样
+	Targets        []StratumResult           `json:"targets"`
+	Caps           []StratumResult           `json:"caps"`
+	Distribution   map[string]map[string]int `json:"distribution"` // 维度 -> 取值 -> 条数
+	Warnings       []string                  `json:"warnings,omitempty"`
+	GeneratedAt    string                    `json:"generated_at"`
+}
@@ -0,0 +1,42 @@
+package dto
+
+// GlossaryResponse 术语表响应
+type GlossaryResponse struct {
+	ID          uint                    `json:"id"` // 在 StartTaskRequest.glossary_id 中引用
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	TermCount   int                     `json:"term_count"`
+	Terms       []*GlossaryTermResponse `json:"terms,omitempty"` // 仅详情返回
+	CreatedAt   string                  `json:"created_at"`
+	UpdatedAt   string                  `json:"updated_at"`
+}
+
+// GlossaryTermResponse 术语条目响应
+type GlossaryTermResponse struct {
+	Term       string   `json:"term"`
+	Definition string   `json:"definition"`
+	Preferred  string   `json:"preferred"`
+	Variants   []string `json:"variants"`
+}
+
+// GlossaryCheckRequest 手动执行术语检查请求
+type GlossaryCheckRequest struct {
+	GlossaryID uint `json:"glossary_id" binding:"required"`
+}
+
+// GlossaryCheckResponse 术语检查结果
+type GlossaryCheckResponse struct {
+	TaskID        string `json:"task_id"`
+	GlossaryID    uint   `json:"glossary_id"`
+	Checked       int    `json:"checked"`
+	ViolatingRows int    `json:"violating_rows"`
+	Violations    int    `json:"violations"`
+}
+
+// GlossaryViolationResponse 术语违规记录
+type GlossaryViolationResponse struct {
+	DataID    uint   `json:"data_id"`
+	Term      string `json:"term"`
+	Found     string `json:"found"`
+	Preferred string `json:"preferred"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+// JudgeTaskRequest 手动执行裁判模型评分请求
+type JudgeTaskRequest struct {
+	JudgeModelID uint `json:"judge_model_id" binding:"required"`
+}
+
+// JudgeFeedback 裁判模型对单条数据的结构化点评（序列化后存入 judge_feedback）
+type JudgeFeedback struct {
+	Score      float64  `json:"score"`
+	Strengths  []string `json:"strengths"`
+	Issues     []string `json:"issues"`
+	Summary    string   `json:"summary"`
+	JudgeModel string   `json:"judge_model"`
+}
+
+// JudgeProgressResponse 评分进度
+type JudgeProgressResponse struct {
+	TaskID       string   `json:"task_id"`
+	JudgeModelID uint     `json:"judge_model_id"`
+	Status       string   `json:"status"` // running, finished, error
+	Total        int      `json:"total"`
+	Scored       int      `json:"scored"`
+	Failed       int      `json:"failed"`
+	Percent      float64  `json:"percent"`
+	AverageScore *float64 `json:"average_score,omitempty"`
+	Error        string   `json:"error,omitempty"`
+	StartedAt    string   `json:"started_at"`
+	FinishedAt   *string  `json:"finished_at,omitempty"`
+}
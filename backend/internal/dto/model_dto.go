@@ -2,53 +2,66 @@ package dto
 
 // CreateModelConfigRequest 创建模型配置请求
 type CreateModelConfigRequest struct {
-	Name          string  `json:"name" binding:"required"`
-	APIURL        string  `json:"api_url" binding:"required"`
-	APIKey        string  `json:"api_key"`
-	ModelPath     string  `json:"model_path" binding:"required"`
-	MaxConcurrent int     `json:"max_concurrent"`
-	Temperature   float64 `json:"temperature"`
-	TopP          float64 `json:"top_p"`
-	MaxTokens     int     `json:"max_tokens"`
-	IsVLLM        bool    `json:"is_vllm"`
-	Timeout       int     `json:"timeout"`
-	Description   string  `json:"description"`
-	IsActive      bool    `json:"is_active"`
+	Name   string `json:"name" binding:"required"`
+	APIURL string `json:"api_url" binding:"required"`
+	// Endpoints 同一模型的其他服务地址，与 api_url 组成端点池，代理调用时路由到负载最低的健康端点
+	Endpoints     []string `json:"endpoints"`
+	APIKey        string   `json:"api_key"`
+	ModelPath     string   `json:"model_path" binding:"required"`
+	MaxConcurrent int      `json:"max_concurrent"`
+	RPMLimit      int      `json:"rpm_limit"` // 每分钟请求数上限，0 表示不限制
+	TPMLimit      int      `json:"tpm_limit"` // 每分钟Token数上限，0 表示不限制
+	Temperature   float64  `json:"temperature"`
+	TopP          float64  `json:"top_p"`
+	MaxTokens     int      `json:"max_tokens"`
+	IsVLLM        bool     `json:"is_vllm"`
+	Provider      string   `json:"provider" binding:"omitempty,oneof=openai anthropic gemini"` // 对话接口协议，默认 openai
+	Timeout       int      `json:"timeout"`
+	Description   string   `json:"description"`
+	IsActive      bool     `json:"is_active"`
 }
 
 // UpdateModelConfigRequest 更新模型配置请求
 type UpdateModelConfigRequest struct {
-	Name          *string  `json:"name"`
-	APIURL        *string  `json:"api_url"`
-	APIKey        *string  `json:"api_key"`
-	ModelPath     *string  `json:"model_path"`
-	MaxConcurrent *int     `json:"max_concurrent"`
-	Temperature   *float64 `json:"temperature"`
-	TopP          *float64 `json:"top_p"`
-	MaxTokens     *int     `json:"max_tokens"`
-	IsVLLM        *bool    `json:"is_vllm"`
-	Timeout       *int     `json:"timeout"`
-	Description   *string  `json:"description"`
-	IsActive      *bool    `json:"is_active"`
+	Name          *string   `json:"name"`
+	APIURL        *string   `json:"api_url"`
+	Endpoints     *[]string `json:"endpoints"`
+	APIKey        *string   `json:"api_key"`
+	ModelPath     *string   `json:"model_path"`
+	MaxConcurrent *int      `json:"max_concurrent"`
+	RPMLimit      *int      `json:"rpm_limit"`
+	TPMLimit      *int      `json:"tpm_limit"`
+	Temperature   *float64  `json:"temperature"`
+	TopP          *float64  `json:"top_p"`
+	MaxTokens     *int      `json:"max_tokens"`
+	IsVLLM        *bool     `json:"is_vllm"`
+	Provider      *string   `json:"provider" binding:"omitempty,oneof=openai anthropic gemini"`
+	Timeout       *int      `json:"timeout"`
+	Description   *string   `json:"description"`
+	IsActive      *bool     `json:"is_active"`
 }
 
 // ModelConfigResponse 模型配置响应
 type ModelConfigResponse struct {
-	ID            uint    `json:"id"`
-	Name          string  `json:"name"`
-	APIURL        string  `json:"api_url"`
-	APIKey        string  `json:"api_key"`
-	ModelPath     string  `json:"model_path"`
-	MaxConcurrent int     `json:"max_concurrent"`
-	Temperature   float64 `json:"temperature"`
-	TopP          float64 `json:"top_p"`
-	MaxTokens     int     `json:"max_tokens"`
-	IsVLLM        bool    `json:"is_vllm"`
-	Timeout       int     `json:"timeout"`
-	Description   string  `json:"description"`
-	IsActive      bool    `json:"is_active"`
-	CreatedAt     string  `json:"created_at"`
-	UpdatedAt     string  `json:"updated_at"`
+	ID            uint     `json:"id"`
+	Name          string   `json:"name"`
+	APIURL        string   `json:"api_url"`
+	Endpoints     []string `json:"endpoints"`
+	APIKey        string   `json:"api_key"`
+	ModelPath     string   `json:"model_path"`
+	MaxConcurrent int      `json:"max_concurrent"`
+	RPMLimit      int      `json:"rpm_limit"` // 每分钟请求数上限，0 表示不限制
+	TPMLimit      int      `json:"tpm_limit"` // 每分钟Token数上限，0 表示不限制
+	Temperature   float64  `json:"temperature"`
+	TopP          float64  `json:"top_p"`
+	MaxTokens     int      `json:"max_tokens"`
+	IsVLLM        bool     `json:"is_vllm"`
+	Provider      string   `json:"provider"`
+	Timeout       int      `json:"timeout"`
+	Description   string   `json:"description"`
+	IsActive      bool     `json:"is_active"`
+	CreatedAt     string   `json:"created_at"`
+	UpdatedAt     string   `json:"updated_at"`
 }
 
 // ModelCallRequest 模型调用请求
@@ -97,6 +110,7 @@ type ModelCallProxyRequest struct {
 	TopP        float64   `json:"top_p"`
 	RetryTimes  int       `json:"retry_times"`
 	TaskID      string    `json:"task_id,omitempty"`
+	NoCache     bool      `json:"no_cache,omitempty"` // 跳过响应缓存
 }
 
 // ModelCallProxyResponse 模型调用代理响应（返回给Python后端）
@@ -106,6 +120,22 @@ type ModelCallProxyResponse struct {
 	Error       string `json:"error,omitempty"`
 	InputChars  int    `json:"input_chars,omitempty"`
 	OutputChars int    `json:"output_chars,omitempty"`
+	Cached      bool   `json:"cached,omitempty"` // 结果来自响应缓存
+	// 上游返回的Token用量（上游未返回 usage 或命中缓存时为 0）
+	PromptTokens     int `json:"prompt_tokens,omitempty"`
+	CompletionTokens int `json:"completion_tokens,omitempty"`
+	TotalTokens      int `json:"total_tokens,omitempty"`
+}
+
+// ModelCallBatchRequest 批量模型调用代理请求（Python后端调用Go，减少逐条调用的HTTP开销）
+type ModelCallBatchRequest struct {
+	Requests []ModelCallProxyRequest `json:"requests" binding:"required,min=1,max=256,dive"`
+}
+
+// ModelCallBatchResponse 批量模型调用代理响应，results 与请求顺序一一对应
+type ModelCallBatchResponse struct {
+	Success bool                     `json:"success"`
+	Results []ModelCallProxyResponse `json:"results"`
 }
 
 // VLLMRequest vLLM API请求格式
@@ -122,3 +152,132 @@ type VLLMResponse struct {
 	Choices []Choice `json:"choices"`
 	Usage   Usage    `json:"usage,omitempty"`
 }
+
+// EndpointStatus 模型端点池中单个服务地址的状态
+type EndpointStatus struct {
+	URL                 string  `json:"url"`
+	InFlight            int64   `json:"in_flight"`            // 正在处理的代理请求数
+	AvgLatencyMs        float64 `json:"avg_latency_ms"`       // 最近请求的平均延迟（指数加权）
+	ConsecutiveFailures int64   `json:"consecutive_failures"` // 连续失败次数
+	Healthy             bool    `json:"healthy"`              // 连续失败达到阈值后在冷却期内视为不健康
+	LastError           string  `json:"last_error,omitempty"`
+	LastFailureAt       *int64  `json:"last_failure_at,omitempty"` // 最近一次失败的时间（Unix 毫秒）
+}
+
+// ModelEndpointsResponse 模型端点池状态响应
+type ModelEndpointsResponse struct {
+	ModelID   uint             `json:"model_id"`
+	Name      string           `json:"name"`
+	Endpoints []EndpointStatus `json:"endpoints"`
+}
+
+// ModelTokenUsageSummary 单个模型在统计期间的Token用量汇总
+type ModelTokenUsageSummary struct {
+	Model            string `json:"model"`
+	Calls            int64  `json:"calls"`
+	PromptTokens     int64  `json:"prompt_tokens"`
+	CompletionTokens int64  `json:"completion_tokens"`
+	TotalTokens      int64  `json:"total_tokens"`
+}
+
+// ModelTokenUsageDaily 单个模型单日的Token用量
+type ModelTokenUsageDaily struct {
+	Date             string `json:"date"`
+	Model            string `json:"model"`
+	Calls            int64  `json:"calls"`
+	PromptTokens     int64  `json:"prompt_tokens"`
+	CompletionTokens int64  `json:"completion_tokens"`
+}
+
+// ModelTokenUsageResponse 模型Token用量统计响应
+type ModelTokenUsageResponse struct {
+	Since  string                   `json:"since"` // 统计起始日期（UTC）
+	Models []ModelTokenUsageSummary `json:"models"`
+	Daily  []ModelTokenUsageDaily   `json:"daily"`
+}
+
+// TestModelConnectionRequest 测试模型服务连接请求；指定 model_id 时，未提供的地址、密钥和模型路径使用该模型的配置
+type TestModelConnectionRequest struct {
+	ModelID   *uint  `json:"model_id"`
+	APIURL    string `json:"api_url"`
+	APIKey    string `json:"api_key"`
+	ModelPath string `json:"model_path"`                                                 // 为空时使用服务返回的第一个模型
+	IsVLLM    *bool  `json:"is_vllm"`                                                    // 提供时与检测到的接口类型比较并给出提示
+	Provider  string `json:"provider" binding:"omitempty,oneof=openai anthropic gemini"` // 对话接口协议，默认 openai
+	Timeout   int    `json:"timeout" binding:"omitempty,min=1,max=120"`                  // 每个请求的超时（秒），默认 30
+}
+
+// UpstreamModel 模型服务 /models 接口返回的模型
+type UpstreamModel struct {
+	ID          string `json:"id"`
+	OwnedBy     string `json:"owned_by,omitempty"`
+	MaxModelLen int    `json:"max_model_len,omitempty"` // vLLM 返回的最大上下文长度
+}
+
+// TestModelConnectionResponse 测试模型服务连接结果
+type TestModelConnectionResponse struct {
+	Success             bool            `json:"success"`                  // 对话补全请求是否成功
+	Dialect             string          `json:"dialect"`                  // 接口类型：vllm、openai、anthropic、gemini，无法判断时为 unknown
+	ServerVersion       string          `json:"server_version,omitempty"` // vLLM 的版本号
+	APIURL              string          `json:"api_url"`
+	ModelPath           string          `json:"model_path"` // 实际测试的模型
+	Models              []UpstreamModel `json:"models"`
+	ModelListed         bool            `json:"model_listed"` // 测试的模型是否在服务返回的模型列表中
+	ModelsLatencyMs     int64           `json:"models_latency_ms"`
+	ModelsError         string          `json:"models_error,omitempty"`
+	CompletionLatencyMs int64           `json:"completion_latency_ms"`
+	Reply               string          `json:"reply,omitempty"`
+	Usage               *Usage          `json:"usage,omitempty"`
+	Error               string          `json:"error,omitempty"`
+	Warnings            []string        `json:"warnings,omitempty"`
+}
+
+// DiscoverModelsRequest 从模型服务发现可用模型请求
+type DiscoverModelsRequest struct {
+	APIURL   string `json:"api_url" binding:"required"`
+	APIKey   string `json:"api_key"`
+	Provider string `json:"provider" binding:"omitempty,oneof=openai anthropic gemini"` // 对话接口协议，默认 openai
+	Timeout  int    `json:"timeout" binding:"omitempty,min=1,max=120"`                  // 请求超时（秒），默认 30
+}
+
+// DiscoveredModel 模型服务提供的模型，已有相同地址和模型路径的配置时给出配置 ID 和名称
+type DiscoveredModel struct {
+	UpstreamModel
+	ConfigID   *uint  `json:"config_id,omitempty"`
+	ConfigName string `json:"config_name,omitempty"`
+}
+
+// DiscoverModelsResponse 从模型服务发现可用模型结果
+type DiscoverModelsResponse struct {
+	APIURL        string            `json:"api_url"`
+	Dialect       string            `json:"dialect"`                  // 接口类型：vllm、openai、anthropic、gemini
+	ServerVersion string            `json:"server_version,omitempty"` // vLLM 的版本号
+	LatencyMs     int64             `json:"latency_ms"`
+	Models        []DiscoveredModel `json:"models"`
+}
+
+// ImportModelsRequest 按发现的模型批量创建模型配置请求；未填写的参数使用新增模型的默认值
+type ImportModelsRequest struct {
+	APIURL        string   `json:"api_url" binding:"required"`
+	APIKey        string   `json:"api_key"`
+	Provider      string   `json:"provider" binding:"omitempty,oneof=openai anthropic gemini"` // 对话接口协议，默认 openai
+	ModelIDs      []string `json:"model_ids" binding:"required,min=1,max=100"`                 // 服务 /models 返回的模型 ID，作为模型路径
+	NamePrefix    string   `json:"name_prefix"`                                                // 模型名称为前缀加模型 ID
+	IsVLLM        *bool    `json:"is_vllm"`                                                    // 为空时按检测到的接口类型设置
+	MaxConcurrent int      `json:"max_concurrent"`
+	MaxTokens     int      `json:"max_tokens"`
+	Timeout       int      `json:"timeout"`
+	IsActive      *bool    `json:"is_active"` // 默认启用
+}
+
+// ImportModelSkipped 批量创建时跳过的模型
+type ImportModelSkipped struct {
+	ModelPath string `json:"model_path"`
+	Reason    string `json:"reason"`
+}
+
+// ImportModelsResponse 批量创建模型配置结果
+type ImportModelsResponse struct {
+	Created []ModelConfigResponse `json:"created"`
+	Skipped []ImportModelSkipped  `json:"skipped"`
+}
@@ -0,0 +1,65 @@
+package dto
+
+// CreateNotificationSubscriptionRequest 订阅任务通知请求
+type CreateNotificationSubscriptionRequest struct {
+	// TaskID 只订阅指定任务，为空表示订阅自己的全部任务
+	TaskID  string `json:"task_id" binding:"max=100"`
+	Channel string `json:"channel" binding:"required,oneof=email slack dingtalk wecom"`
+	// Target 邮件渠道为邮箱地址，其他渠道为机器人 Webhook 地址
+	Target string `json:"target" binding:"required,max=500"`
+	// Secret 钉钉机器人的加签密钥（安全设置为“加签”时需要）
+	Secret string `json:"secret" binding:"max=200"`
+	// Events 订阅的事件（task.started、task.finished、task.error、task.stopped），为空表示任务完成或失败
+	Events []string `json:"events"`
+}
+
+// UpdateNotificationSubscriptionRequest 更新通知订阅请求
+type UpdateNotificationSubscriptionRequest struct {
+	Target   *string  `json:"target" binding:"omitempty,max=500"`
+	Secret   *string  `json:"secret" binding:"omitempty,max=200"`
+	Events   []string `json:"events"`
+	IsActive *bool    `json:"is_active"`
+}
+
+// NotificationSubscriptionResponse 通知订阅响应
+type NotificationSubscriptionResponse struct {
+	ID         uint     `json:"id"`
+	TaskID     string   `json:"task_id"`
+	Channel    string   `json:"channel"`
+	Target     string   `json:"target"`
+	HasSecret  bool     `json:"has_secret"`
+	Events     []string `json:"events"`
+	IsActive   bool     `json:"is_active"`
+	LastSentAt *string  `json:"last_sent_at"`
+	LastError  string   `json:"last_error"`
+	CreatedAt  string   `json:"created_at"`
+	UpdatedAt  string   `json:"updated_at"`
+}
+
+// NotificationResponse 站内通知响应
+type NotificationResponse struct {
+	ID        uint    `json:"id"`
+	Type      string  `json:"type"` // task, review, announcement
+	Title     string  `json:"title"`
+	Content   string  `json:"content"`
+	Link      string  `json:"link"`
+	TaskID    string  `json:"task_id"`
+	Read      bool    `json:"read"`
+	ReadAt    *string `json:"read_at"`
+	CreatedAt string  `json:"created_at"`
+}
+
+// MarkNotificationsReadRequest 标记通知已读请求
+type MarkNotificationsReadRequest struct {
+	// IDs 要标记的通知ID，为空表示全部标记为已读
+	IDs []uint `json:"ids"`
+}
+
+// CreateAnnouncementRequest 管理员发布公告请求
+type CreateAnnouncementRequest struct {
+	Title   string `json:"title" binding:"required,max=255"`
+	Content string `json:"content" binding:"max=10000"`
+	Link    string `json:"link" binding:"max=500"`
+	// UserIDs 接收公告的用户，为空表示全部启用的用户
+	UserIDs []uint `json:"user_ids"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+// ExportToStorageRequest 导出任务数据到对象存储请求（过滤参数与 /generated_data/export 相同，通过查询参数传递）
+type ExportToStorageRequest struct {
+	TaskID string `json:"task_id" binding:"required"`
+	Format string `json:"format"` // jsonl（默认）、csv 或训练框架格式（sharegpt/openai_chat/alpaca/parquet）
+}
+
+// StorageObjectResponse 写入对象存储的文件
+type StorageObjectResponse struct {
+	Bucket    string `json:"bucket"`
+	Key       string `json:"key"`
+	Size      int64  `json:"size"`
+	ETag      string `json:"etag"`
+	URL       string `json:"url"` // 签名下载链接
+	ExpiresAt string `json:"expires_at"`
+	RowCount  int    `json:"row_count"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+// CreatePromptRequest 创建提示词请求（同时创建版本1）
+type CreatePromptRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Description   string   `json:"description" binding:"max=500"`
+	Tags          []string `json:"tags"`
+	SpecialPrompt string   `json:"special_prompt"`
+	Directions    string   `json:"directions"`
+	ChangeNote    string   `json:"change_note" binding:"max=500"`
+}
+
+// UpdatePromptRequest 更新提示词元信息请求（内容修改请创建新版本）
+type UpdatePromptRequest struct {
+	Name        *string  `json:"name" binding:"omitempty,min=1,max=100"`
+	Description *string  `json:"description" binding:"omitempty,max=500"`
+	Tags        []string `json:"tags"` // 为 null 时不修改
+}
+
+// CreatePromptVersionRequest 创建提示词新版本请求
+type CreatePromptVersionRequest struct {
+	SpecialPrompt string `json:"special_prompt"`
+	Directions    string `json:"directions"`
+	ChangeNote    string `json:"change_note" binding:"max=500"`
+}
+
+// PromptVersionResponse 提示词版本响应
+type PromptVersionResponse struct {
+	ID            uint   `json:"id"` // 在 StartTaskRequest.prompt_version_id 中引用
+	PromptID      uint   `json:"prompt_id"`
+	Version       int    `json:"version"`
+	SpecialPrompt string `json:"special_prompt"`
+	Directions    string `json:"directions"`
+	ChangeNote    string `json:"change_note"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// PromptResponse 提示词响应
+type PromptResponse struct {
+	ID            uint                   `json:"id"`
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description"`
+	Tags          []string               `json:"tags"`
+	LatestVersion int                    `json:"latest_version"`
+	Latest        *PromptVersionResponse `json:"latest,omitempty"` // 最新版本内容（仅详情和创建时返回）
+	CreatedAt     string                 `json:"created_at"`
+	UpdatedAt     string                 `json:"updated_at"`
+}
@@ -0,0 +1,83 @@
+package dto
+
+// LengthStats 生成数据的字符长度统计（按 data_content 的字符数）
+type LengthStats struct {
+	Min int     `json:"min"`
+	Max int     `json:"max"`
+	Avg float64 `json:"avg"`
+	P50 int     `json:"p50"`
+	P90 int     `json:"p90"`
+	P99 int     `json:"p99"`
+}
+
+// ReportCompareSide 对比中一个任务的汇总统计
+type ReportCompareSide struct {
+	TaskID        string                 `json:"task_id"`
+	Params        map[string]interface{} `json:"params"`
+	Count         int                    `json:"count"`
+	ScoreBuckets  map[string]int         `json:"score_buckets"` // 按 model_score 分段：low/medium/high/unscored
+	AvgModelScore *float64               `json:"avg_model_score"`
+	AvgRuleScore  *float64               `json:"avg_rule_score"`
+	Length        LengthStats            `json:"length"`
+}
+
+// ReportDiffItem 对比中一侧的生成数据
+type ReportDiffItem struct {
+	ID              uint     `json:"id"`
+	ModelScore      *float64 `json:"model_score"`
+	RuleScore       *int     `json:"rule_score"`
+	Length          int      `json:"length"`
+	GenerationModel string   `json:"generation_model"`
+	DataContent     string   `json:"data_content"`
+}
+
+// ReportItemDiff 按源样本对齐的一组生成数据（只在一个任务中出现时另一侧为空）
+type ReportItemDiff struct {
+	SeedHash     string          `json:"seed_hash"`
+	VariantIndex int             `json:"variant_index"`
+	A            *ReportDiffItem `json:"a"`
+	B            *ReportDiffItem `json:"b"`
+	ScoreDelta   *float64        `json:"score_delta"` // B 的 model_score 减去 A 的，任一侧未评分时为空
+	Identical    bool            `json:"identical"`   // 对话内容一致（忽略 meta 和空白差异）
+}
+
+// ReportCompareResponse 两个任务生成数据的对比结果
+// 数据按 seed_hash 和 variant_index 对齐，没有这两个字段的数据无法对齐，计入只在一侧出现
+type ReportCompareResponse struct {
+	TaskA         ReportCompareSide `json:"task_a"`
+	TaskB         ReportCompareSide `json:"task_b"`
+	SameInputFile bool              `json:"same_input_file"`
+	Aligned       int               `json:"aligned"`
+	OnlyInA       int               `json:"only_in_a"`
+	OnlyInB       int               `json:"only_in_b"`
+	Identical     int               `json:"identical"`
+	AvgScoreDelta *float64          `json:"avg_score_delta"` // 两侧均已评分的对齐数据的平均分差（B - A）
+	Diffs         []ReportItemDiff  `json:"diffs"`
+	DiffTotal     int               `json:"diff_total"`
+	Page          int               `json:"page"`
+	PerPage       int               `json:"per_page"`
+}
+
+// HistogramBucket 直方图的一个分段
+type HistogramBucket struct {
+	Label string `json:"label"` // 分段范围，如 "7-8"；未评分为 "unscored"
+	Count int64  `json:"count"`
+}
+
+// ValueCount 按取值统计的条数，Value 为空表示未记录
+type ValueCount struct {
+	Value *int64 `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// TaskStatsResponse 任务生成数据的统计（评分直方图、长度分布、重试次数分布和每轮产出）
+type TaskStatsResponse struct {
+	TaskID              string            `json:"task_id"`
+	Total               int64             `json:"total"`
+	ModelScoreHistogram []HistogramBucket `json:"model_score_histogram"`
+	RuleScoreCounts     []ValueCount      `json:"rule_score_counts"`
+	RetryCounts         []ValueCount      `json:"retry_counts"`
+	RoundYield          []ValueCount      `json:"round_yield"` // 按 meta.round 统计，旧数据未记录轮次时 value 为空
+	AvgTurns            *float64          `json:"avg_turns"`
+	Length              LengthStats       `json:"length"`
+}
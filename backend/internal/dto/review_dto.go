@@ -0,0 +1,82 @@
+package dto
+
+// AssignReviewRequest 分配审核请求
+// 不指定 data_ids 时分配任务中所有尚未分配的数据；指定多个审核人时按数据ID顺序轮流分配
+type AssignReviewRequest struct {
+	TaskID      string `json:"task_id" binding:"required"`
+	DataIDs     []uint `json:"data_ids"`
+	ReviewerIDs []uint `json:"reviewer_ids" binding:"required,min=1"`
+}
+
+// AssignReviewResponse 分配审核结果
+type AssignReviewResponse struct {
+	TaskID      string         `json:"task_id"`
+	Assigned    int64          `json:"assigned"`
+	PerReviewer map[uint]int64 `json:"per_reviewer"`
+}
+
+// SubmitReviewRequest 提交审核结果请求
+type SubmitReviewRequest struct {
+	Status  string `json:"status" binding:"required,oneof=pending approved rejected needs_edit"`
+	Comment string `json:"comment"`
+}
+
+// ReassignReviewRequest 管理员批量转交审核请求
+// 默认只转交未完成（pending、needs_edit）的数据，include_reviewed 为 true 时转交全部
+type ReassignReviewRequest struct {
+	FromReviewerID  uint   `json:"from_reviewer_id" binding:"required"`
+	ToReviewerID    uint   `json:"to_reviewer_id" binding:"required"`
+	TaskID          string `json:"task_id"`
+	IncludeReviewed bool   `json:"include_reviewed"`
+}
+
+// ReviewSettingsRequest 任务审核设置请求
+type ReviewSettingsRequest struct {
+	RequiredReviews int `json:"required_reviews" binding:"required,min=1,max=5"`
+}
+
+// ReviewerProgress 单个审核人的审核进度
+type ReviewerProgress struct {
+	ReviewerID   *uint   `json:"reviewer_id"` // 为空表示未分配
+	ReviewerName string  `json:"reviewer_name"`
+	Total        int64   `json:"total"`
+	Pending      int64   `json:"pending"`
+	Approved     int64   `json:"approved"`
+	Rejected     int64   `json:"rejected"`
+	NeedsEdit    int64   `json:"needs_edit"`
+	Percent      float64 `json:"percent"` // 已审核（非 pending）占比
+}
+
+// ReviewProgressResponse 任务审核进度
+// Total、Reviewed 按数据条数统计；Reviewers 按各审核人的审核记录统计（多人审核时一条数据计入多位审核人）
+type ReviewProgressResponse struct {
+	TaskID          string             `json:"task_id"`
+	RequiredReviews int                `json:"required_reviews"`
+	Total           int64              `json:"total"`
+	Reviewed        int64              `json:"reviewed"`
+	Disputed        int64              `json:"disputed"`
+	Unassigned      int64              `json:"unassigned"`
+	Percent         float64            `json:"percent"`
+	Reviewers       []ReviewerProgress `json:"reviewers"`
+	Agreement       *ReviewAgreement   `json:"agreement,omitempty"`
+}
+
+// ReviewerPairAgreement 两位审核人之间的一致性
+type ReviewerPairAgreement struct {
+	ReviewerA uint     `json:"reviewer_a"`
+	ReviewerB uint     `json:"reviewer_b"`
+	Items     int      `json:"items"`     // 两人都已审核的数据条数
+	Agreement float64  `json:"agreement"` // 结论相同的比例
+	Kappa     *float64 `json:"kappa"`     // Cohen's kappa，无法计算时为空
+}
+
+// ReviewAgreement 任务的审核一致性统计
+// Kappa 将每条数据上任意两位审核人的结论视为一对评分，汇总后计算 Cohen's kappa
+type ReviewAgreement struct {
+	RequiredReviews   int                     `json:"required_reviews"`
+	Items             int                     `json:"items"` // 至少有两份已提交结论的数据条数
+	Pairs             int                     `json:"pairs"`
+	ObservedAgreement float64                 `json:"observed_agreement"`
+	Kappa             *float64                `json:"kappa"`
+	PairwiseKappa     []ReviewerPairAgreement `json:"pairwise"`
+}
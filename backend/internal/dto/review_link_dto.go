@@ -0,0 +1,31 @@
+package dto
+
+// CreateReviewLinkRequest 创建审阅链接请求
+type CreateReviewLinkRequest struct {
+	Name           string `json:"name"`             // 备注（如标注人员名称）
+	ExpiresInHours int    `json:"expires_in_hours"` // 有效期（小时），为空时使用默认值
+}
+
+// ReviewLinkResponse 审阅链接响应
+type ReviewLinkResponse struct {
+	ID         uint    `json:"id"`
+	TaskID     string  `json:"task_id"`
+	Name       string  `json:"name"`
+	ExpiresAt  string  `json:"expires_at"`
+	Revoked    bool    `json:"revoked"`
+	Active     bool    `json:"active"`
+	LastUsedAt *string `json:"last_used_at"`
+	UseCount   int64   `json:"use_count"`
+	Token      string  `json:"token,omitempty"` // 仅在创建时返回
+	CreatedAt  string  `json:"created_at"`
+}
+
+// ReviewLinkActivityResponse 审阅链接访问记录响应
+type ReviewLinkActivityResponse struct {
+	ID        uint   `json:"id"`
+	Action    string `json:"action"`
+	DataID    *uint  `json:"data_id"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	CreatedAt string `json:"created_at"`
+}
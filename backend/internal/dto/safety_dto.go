@@ -0,0 +1,10 @@
+package dto
+
+// SafetyCheckResponse 内容安全检查结果
+type SafetyCheckResponse struct {
+	TaskID      string           `json:"task_id"`
+	Checked     int              `json:"checked"`
+	Flagged     int              `json:"flagged"`
+	ModelErrors int              `json:"model_errors"` // 审核模型调用失败的条数（这些数据只按屏蔽词和正则判定）
+	FlagCounts  map[string]int64 `json:"flag_counts"`
+}
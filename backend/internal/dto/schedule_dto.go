@@ -0,0 +1,44 @@
+package dto
+
+// CreateScheduleRequest 创建定时任务请求
+type CreateScheduleRequest struct {
+	Name     string           `json:"name" binding:"required"`
+	CronExpr string           `json:"cron_expr" binding:"required"` // 5段 cron 表达式: 分 时 日 月 周
+	Timezone string           `json:"timezone"`                     // 为空时使用用户的展示时区
+	Task     StartTaskRequest `json:"task" binding:"required"`      // 每次触发时启动任务的参数
+}
+
+// UpdateScheduleRequest 更新定时任务请求
+type UpdateScheduleRequest struct {
+	Name     *string           `json:"name"`
+	CronExpr *string           `json:"cron_expr"`
+	Timezone *string           `json:"timezone"`
+	Task     *StartTaskRequest `json:"task"`
+	IsActive *bool             `json:"is_active"`
+}
+
+// ScheduleResponse 定时任务响应
+type ScheduleResponse struct {
+	ID         uint                   `json:"id"`
+	Name       string                 `json:"name"`
+	CronExpr   string                 `json:"cron_expr"`
+	Timezone   string                 `json:"timezone"`
+	Task       map[string]interface{} `json:"task"`
+	IsActive   bool                   `json:"is_active"`
+	NextRunAt  *string                `json:"next_run_at"`
+	LastRunAt  *string                `json:"last_run_at"`
+	LastTaskID string                 `json:"last_task_id"`
+	CreatedAt  string                 `json:"created_at"`
+	UpdatedAt  string                 `json:"updated_at"`
+}
+
+// ScheduleRunResponse 定时任务执行记录响应
+type ScheduleRunResponse struct {
+	ID          uint   `json:"id"`
+	ScheduleID  uint   `json:"schedule_id"`
+	Status      string `json:"status"`
+	TaskID      string `json:"task_id"`
+	Message     string `json:"message"`
+	ScheduledAt string `json:"scheduled_at"`
+	CreatedAt   string `json:"created_at"`
+}
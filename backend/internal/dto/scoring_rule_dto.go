@@ -0,0 +1,62 @@
+package dto
+
+// CreateScoringRuleRequest 创建评分规则请求
+type CreateScoringRuleRequest struct {
+	TaskType  string `json:"task_type" binding:"max=50"` // 为空时适用于全部任务类型
+	Name      string `json:"name" binding:"required,max=100"`
+	RuleType  string `json:"rule_type" binding:"required,oneof=length required_regex forbidden_regex json_schema language"`
+	Target    string `json:"target" binding:"omitempty,oneof=assistant human all"` // 默认 assistant
+	Pattern   string `json:"pattern"`                                              // 正则表达式、JSON Schema 或候选语言（| 分隔，如 zh|en）
+	MinLength int    `json:"min_length" binding:"min=0"`
+	MaxLength int    `json:"max_length" binding:"min=0"`
+	Weight    int    `json:"weight" binding:"omitempty,min=1,max=100"` // 默认 1
+	IsActive  *bool  `json:"is_active"`                                // 为空时默认启用
+}
+
+// UpdateScoringRuleRequest 更新评分规则请求
+type UpdateScoringRuleRequest struct {
+	TaskType  *string `json:"task_type" binding:"omitempty,max=50"`
+	Name      *string `json:"name" binding:"omitempty,min=1,max=100"`
+	RuleType  *string `json:"rule_type" binding:"omitempty,oneof=length required_regex forbidden_regex json_schema language"`
+	Target    *string `json:"target" binding:"omitempty,oneof=assistant human all"`
+	Pattern   *string `json:"pattern"`
+	MinLength *int    `json:"min_length" binding:"omitempty,min=0"`
+	MaxLength *int    `json:"max_length" binding:"omitempty,min=0"`
+	Weight    *int    `json:"weight" binding:"omitempty,min=1,max=100"`
+	IsActive  *bool   `json:"is_active"`
+}
+
+// ScoringRuleResponse 评分规则响应
+type ScoringRuleResponse struct {
+	ID        uint   `json:"id"`
+	TaskType  string `json:"task_type"`
+	Name      string `json:"name"`
+	RuleType  string `json:"rule_type"`
+	Target    string `json:"target"`
+	Pattern   string `json:"pattern"`
+	MinLength int    `json:"min_length"`
+	MaxLength int    `json:"max_length"`
+	Weight    int    `json:"weight"`
+	IsActive  bool   `json:"is_active"`
+	CreatedBy uint   `json:"created_by"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// RuleFailure 单条数据未通过的评分规则（序列化后存入 rule_failures）
+type RuleFailure struct {
+	RuleID uint   `json:"rule_id"`
+	Rule   string `json:"rule"`
+	Reason string `json:"reason"`
+}
+
+// RuleScoreTaskResponse 任务规则评分结果
+type RuleScoreTaskResponse struct {
+	TaskID       string         `json:"task_id"`
+	TaskType     string         `json:"task_type"`
+	Rules        int            `json:"rules"`
+	Checked      int            `json:"checked"`
+	Passed       int            `json:"passed"` // 通过全部规则（rule_score 为 10）的条数
+	AverageScore float64        `json:"average_score"`
+	RuleFailures map[string]int `json:"rule_failures"` // 规则名称 -> 未通过的条数
+}
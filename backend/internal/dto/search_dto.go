@@ -0,0 +1,30 @@
+package dto
+
+// 检索范围
+const (
+	SearchScopeAll       = "all"
+	SearchScopeFiles     = "files"
+	SearchScopeGenerated = "generated"
+)
+
+// SearchHit 检索命中的一条记录
+type SearchHit struct {
+	Type     string `json:"type"` // file 或 generated
+	FileID   uint   `json:"file_id,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	Line     int    `json:"line,omitempty"` // 文件中的行号（从1开始）
+	DataID   uint   `json:"data_id,omitempty"`
+	TaskID   string `json:"task_id,omitempty"`
+	Field    string `json:"field"` // 命中的字段：meta、turns[i] 或 raw
+	Role     string `json:"role,omitempty"`
+	Snippet  string `json:"snippet"` // 已转义的摘要，关键字以 <mark></mark> 高亮
+}
+
+// SearchResponse 检索结果
+type SearchResponse struct {
+	Query     string      `json:"query"`
+	Scope     string      `json:"scope"`
+	Engine    string      `json:"engine"` // 生成数据使用的检索方式：fts5 或 like
+	Hits      []SearchHit `json:"hits"`
+	Truncated bool        `json:"truncated"` // 命中数超过 limit，结果被截断
+}
@@ -0,0 +1,38 @@
+package dto
+
+// 存储占用状态
+const (
+	StorageStatusOK       = "ok"
+	StorageStatusWarning  = "warning"
+	StorageStatusExceeded = "exceeded"
+)
+
+// StorageUsageResponse 用户存储占用（字节）
+type StorageUsageResponse struct {
+	UserID             uint    `json:"user_id"`
+	Username           string  `json:"username,omitempty"`
+	FileCount          int64   `json:"file_count"`
+	FileBytes          int64   `json:"file_bytes"`
+	GeneratedDataCount int64   `json:"generated_data_count"`
+	GeneratedDataBytes int64   `json:"generated_data_bytes"`
+	LogCount           int64   `json:"log_count"`
+	LogBytes           int64   `json:"log_bytes"`
+	TotalBytes         int64   `json:"total_bytes"`
+	QuotaBytes         int64   `json:"quota_bytes"`  // 0 表示不限制
+	UsedPercent        float64 `json:"used_percent"` // 未设置配额时为 0
+	Status             string  `json:"status"`       // ok, warning, exceeded
+	Warning            string  `json:"warning,omitempty"`
+}
+
+// StorageSummaryResponse 管理员存储占用汇总
+type StorageSummaryResponse struct {
+	DatabaseBytes      int64                   `json:"database_bytes"` // SQLite 文件实际大小（含 WAL）
+	FileBytes          int64                   `json:"file_bytes"`
+	GeneratedDataBytes int64                   `json:"generated_data_bytes"`
+	LogBytes           int64                   `json:"log_bytes"`
+	TotalBytes         int64                   `json:"total_bytes"`
+	QuotaBytes         int64                   `json:"quota_bytes"`
+	WarningUsers       int                     `json:"warning_users"`  // 接近配额的用户数
+	ExceededUsers      int                     `json:"exceeded_users"` // 超出配额的用户数
+	Users              []*StorageUsageResponse `json:"users"`          // 按占用从大到小排序
+}
@@ -0,0 +1,69 @@
+package dto
+
+import "time"
+
+// CreateTagRuleRequest 创建打标规则请求
+type CreateTagRuleRequest struct {
+	Name      string `json:"name" binding:"required,max=100"`
+	Category  string `json:"category" binding:"required,max=50"`
+	Tag       string `json:"tag" binding:"max=100"`
+	MatchType string `json:"match_type" binding:"required,oneof=keyword regex classifier"`
+	Pattern   string `json:"pattern" binding:"required"`
+	ModelID   *uint  `json:"model_id"`
+	Priority  int    `json:"priority"`
+	IsActive  *bool  `json:"is_active"` // 为空时默认启用
+}
+
+// UpdateTagRuleRequest 更新打标规则请求
+type UpdateTagRuleRequest struct {
+	Name      *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Category  *string `json:"category" binding:"omitempty,min=1,max=50"`
+	Tag       *string `json:"tag" binding:"omitempty,max=100"`
+	MatchType *string `json:"match_type" binding:"omitempty,oneof=keyword regex classifier"`
+	Pattern   *string `json:"pattern" binding:"omitempty,min=1"`
+	ModelID   *uint   `json:"model_id"`
+	Priority  *int    `json:"priority"`
+	IsActive  *bool   `json:"is_active"`
+}
+
+// TagRuleResponse 打标规则响应
+type TagRuleResponse struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Category  string `json:"category"`
+	Tag       string `json:"tag"`
+	MatchType string `json:"match_type"`
+	Pattern   string `json:"pattern"`
+	ModelID   *uint  `json:"model_id"`
+	Priority  int    `json:"priority"`
+	IsActive  bool   `json:"is_active"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// TagTaskResponse 任务打标结果
+type TagTaskResponse struct {
+	TaskID    string           `json:"task_id"`
+	Checked   int              `json:"checked"`
+	Tagged    int              `json:"tagged"`
+	TagCounts map[string]int64 `json:"tag_counts"`
+}
+
+// TagQuota 按标签配额导出：带有该标签的数据最多导出 Limit 条
+type TagQuota struct {
+	Tag   string `json:"tag"`
+	Limit int    `json:"limit"`
+}
+
+// DataFilter 生成数据列表/导出的过滤条件（除配额外均在数据库查询中过滤）
+type DataFilter struct {
+	Tags              []string   // 必须同时带有的标签
+	Quotas            []TagQuota // 导出配额；指定后只导出带有配额标签的数据
+	Confirmed         *bool      // 按是否已确认过滤
+	MinModelScore     *float64   // 模型评分下限（未评分的数据不返回）
+	TaskType          string     // 任务类型
+	DateFrom          *time.Time // 创建时间下限（含）
+	DateTo            *time.Time // 创建时间上限（不含）
+	ExcludeDuplicates bool       // 排除去重标记为重复的数据
+	IncludeFlagged    bool       // 导出时包含内容安全检查标记的数据（默认排除，列表不受影响）
+}
@@ -2,8 +2,10 @@ package dto
 
 // StartTaskRequest 启动任务请求
 type StartTaskRequest struct {
-	InputFile         string   `json:"input_file" binding:"required"`
-	ModelID           *uint    `json:"model_id"`
+	InputFile string `json:"input_file" binding:"required"`
+	ModelID   *uint  `json:"model_id"`
+	// ModelIDs 多模型集成生成：样本按各模型的最大并发数比例分配，与 ModelID 不能同时指定
+	ModelIDs          []uint   `json:"model_ids"`
 	Model             string   `json:"model"`
 	Services          []string `json:"services"`
 	BatchSize         int      `json:"batch_size"`
@@ -15,12 +17,78 @@ type StartTaskRequest struct {
 	RetryTimes        int      `json:"retry_times"`
 	SpecialPrompt     string   `json:"special_prompt"`
 	Directions        string   `json:"directions"`
-	APIKey            string   `json:"api_key"`
-	IsVLLM            bool     `json:"is_vllm"`
-	UseProxy          bool     `json:"use_proxy"`
-	TopP              float64  `json:"top_p"`
-	MaxTokens         int      `json:"max_tokens"`
-	Timeout           int      `json:"timeout"`
+	// PromptVersionID 引用提示词库中的版本，指定后使用该版本的 special_prompt 和 directions
+	PromptVersionID *uint `json:"prompt_version_id"`
+	// FileVersion 使用输入文件的指定版本（见 /data_files/:file_id/versions），默认使用当前版本
+	FileVersion *int    `json:"file_version" binding:"omitempty,min=1"`
+	APIKey      string  `json:"api_key"`
+	IsVLLM      bool    `json:"is_vllm"`
+	UseProxy    bool    `json:"use_proxy"`
+	TopP        float64 `json:"top_p"`
+	MaxTokens   int     `json:"max_tokens"`
+	Timeout     int     `json:"timeout"`
+	// DisableModelCache 该任务的模型调用不使用响应缓存（配置 model_services.cache_enabled 开启时生效）
+	DisableModelCache bool `json:"disable_model_cache"`
+	// MaxRuntimeMinutes 任务最长运行时间（分钟），超时后终止任务并保留已生成的数据，未指定时使用配置 worker.default_max_runtime_minutes
+	MaxRuntimeMinutes int `json:"max_runtime_minutes" binding:"omitempty,min=0"`
+	// DedupAgainstSource 任务完成后将生成数据与输入文件及彼此比较，标记重复数据
+	DedupAgainstSource bool `json:"dedup_against_source"`
+	// MaxSimilarity 任务完成后按向量余弦相似度过滤生成数据：与源样本或已保留数据的相似度超过该值的数据被删除（使用 model_services.embedding_model）
+	MaxSimilarity float64 `json:"max_similarity" binding:"omitempty,gt=0,lte=1"`
+	// GlossaryID 引用术语表，术语说明会注入提示词
+	GlossaryID *uint `json:"glossary_id"`
+	// GlossaryCheck 任务完成后检查生成数据是否使用了术语表中的推荐用法（需同时指定 glossary_id）
+	GlossaryCheck bool `json:"glossary_check"`
+	// SafetyCheck 任务完成后按配置 safety 的规则检查生成数据，命中的数据默认不导出（配置开启自动检查时无需指定）
+	SafetyCheck bool `json:"safety_check"`
+	// JudgeModelID 任务结束后使用该模型为生成数据评分，结果写入 model_score 和 judge_feedback
+	JudgeModelID *uint `json:"judge_model_id"`
+	// RequiredReviews 每条生成数据需要的独立审核次数（大于1时开启多人审核并统计一致性）
+	RequiredReviews int `json:"required_reviews" binding:"omitempty,min=1,max=5"`
+	// ExtraArgs 透传给工作进程的额外参数，参数名必须在配置 worker.extra_args 白名单中
+	ExtraArgs map[string]interface{} `json:"extra_args"`
+	// IdempotencyKey 幂等键（也可通过请求头 Idempotency-Key 传递）：24 小时内使用相同键的重复请求返回第一次创建的任务
+	IdempotencyKey string `json:"idempotency_key" binding:"omitempty,max=128"`
+	// RerunOf 重新运行时的原任务ID（由服务端设置）
+	RerunOf string `json:"-"`
+	// StallRestarts 因卡死自动重新运行的累计次数（由服务端设置）
+	StallRestarts int `json:"-"`
+	// RetryRoot 瞬时错误自动重试时任务链第一次尝试的任务ID（由服务端设置）
+	RetryRoot string `json:"-"`
+	// RetryAttempt 自动重试时本次是第几次尝试（由服务端设置，从 2 开始）
+	RetryAttempt int `json:"-"`
+}
+
+// RerunTaskRequest 重新运行任务请求（未指定的参数沿用原任务）
+type RerunTaskRequest struct {
+	ModelID           *uint `json:"model_id"`
+	DataRounds        *int  `json:"data_rounds" binding:"omitempty,min=1"`
+	VariantsPerSample *int  `json:"variants_per_sample" binding:"omitempty,min=1"`
+}
+
+// ApplyDefaults 为未填写的参数设置默认值
+func (r *StartTaskRequest) ApplyDefaults() {
+	if r.BatchSize == 0 {
+		r.BatchSize = 16
+	}
+	if r.MaxConcurrent == 0 {
+		r.MaxConcurrent = 5
+	}
+	if r.MinScore == 0 {
+		r.MinScore = 10
+	}
+	if r.VariantsPerSample == 0 {
+		r.VariantsPerSample = 3
+	}
+	if r.DataRounds == 0 {
+		r.DataRounds = 3
+	}
+	if r.RetryTimes == 0 {
+		r.RetryTimes = 3
+	}
+	if r.TaskType == "" {
+		r.TaskType = "general"
+	}
 }
 
 // StartTaskResponse 启动任务响应
@@ -28,9 +96,12 @@ type StartTaskResponse struct {
 	Success bool   `json:"success"`
 	TaskID  string `json:"task_id"`
 	Status  string `json:"status"`
+	// Duplicate 幂等键已对应已有任务，返回的是该任务而不是新启动的任务
+	Duplicate bool `json:"duplicate,omitempty"`
 }
 
 // TaskStatusResponse 任务状态响应
+// Deprecated: 使用 TaskOverviewResponse
 type TaskStatusResponse struct {
 	TaskID     string  `json:"task_id"`
 	Status     string  `json:"status"`
@@ -58,13 +129,36 @@ type TaskListResponse struct {
 
 // ProgressEvent 进度事件
 type ProgressEvent struct {
-	Type        string `json:"type"`         // output, heartbeat, finished
-	Line        string `json:"line,omitempty"`
-	ReturnCode  *int   `json:"return_code,omitempty"`
-	Progress    *int   `json:"progress,omitempty"`
-	Total       *int   `json:"total,omitempty"`
-	Percent     float64 `json:"percent,omitempty"`
-	Message     string `json:"message,omitempty"`
+	// ID 任务内单调递增的事件ID（SSE 的 id 字段，断线重连时通过 Last-Event-ID 从该事件之后继续）
+	ID         int64   `json:"id,omitempty"`
+	Type       string  `json:"type"` // output, heartbeat, finished, error，以及结构化进度事件 round_started、batch_completed、sample_generated、score_assigned
+	Line       string  `json:"line,omitempty"`
+	ReturnCode *int    `json:"return_code,omitempty"`
+	Progress   *int    `json:"progress,omitempty"` // 当前轮次已处理的样本数
+	Total      *int    `json:"total,omitempty"`    // 当前轮次的样本总数
+	Percent    float64 `json:"percent,omitempty"`  // 任务整体完成百分比（按轮次和轮内进度计算）
+	Message    string  `json:"message,omitempty"`
+	// 结构化进度事件的字段（schema_version 为工作进程事件的版本）
+	SchemaVersion int      `json:"schema_version,omitempty"`
+	Round         *int     `json:"round,omitempty"`
+	TotalRounds   *int     `json:"total_rounds,omitempty"`
+	Generated     *int     `json:"generated,omitempty"` // 已生成的合格数据条数
+	ModelScore    *float64 `json:"model_score,omitempty"`
+	RuleScore     *float64 `json:"rule_score,omitempty"`
+	Passed        *bool    `json:"passed,omitempty"`
+	// 历史回放被截断时的提示（type=history_omitted）
+	Omitted int    `json:"omitted,omitempty"`
+	LogURL  string `json:"log_url,omitempty"`
+}
+
+// TaskLogPage 任务完整事件日志的一页
+type TaskLogPage struct {
+	TaskID     string           `json:"task_id"`
+	Events     []*ProgressEvent `json:"events"`
+	Offset     int              `json:"offset"`
+	NextOffset int              `json:"next_offset"` // 下一页的 offset（任务运行中时可用来增量拉取新日志）
+	Total      int              `json:"total"`
+	Running    bool             `json:"running"`
 }
 
 // RedisProgressData Redis进度数据
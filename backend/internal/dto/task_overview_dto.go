@@ -0,0 +1,43 @@
+package dto
+
+// 任务概览的数据来源
+const (
+	TaskSourceDatabase = "database" // 任务记录（持久化状态、参数、结果）
+	TaskSourceMemory   = "memory"   // 本进程中运行的任务上下文
+	TaskSourceRedis    = "redis"    // 工作进程写入的实时进度
+)
+
+// TaskLiveProgress 任务实时进度（来自 Redis，任务结束并清理进度后为空）
+type TaskLiveProgress struct {
+	CurrentRound    int64   `json:"current_round"`
+	TotalRounds     int64   `json:"total_rounds"`
+	TotalSamples    int64   `json:"total_samples"`
+	GeneratedCount  int64   `json:"generated_count"`
+	RoundStatus     string  `json:"round_status,omitempty"`
+	ProgressPercent float64 `json:"progress_percent"`
+	InputChars      int64   `json:"input_chars"`
+	OutputChars     int64   `json:"output_chars"`
+}
+
+// TaskOverviewResponse 任务概览
+// 合并数据库、内存和 Redis 三个来源：任务运行中时状态以内存为准、进度以 Redis 为准，结束后以数据库记录为准。
+// 用于替代 /status、/progress_unified 和 /tasks 中形状不一致的字段
+type TaskOverviewResponse struct {
+	TaskID         string                 `json:"task_id"`
+	Status         string                 `json:"status"` // running, finished, error, stopped, partial
+	Finished       bool                   `json:"finished"`
+	ReturnCode     *int                   `json:"return_code,omitempty"`
+	Params         map[string]interface{} `json:"params"`
+	Result         map[string]interface{} `json:"result,omitempty"`
+	ErrorMessage   string                 `json:"error_message,omitempty"`
+	StartedAt      string                 `json:"started_at"`
+	FinishedAt     *string                `json:"finished_at"`
+	RunTime        float64                `json:"run_time"`
+	InputChars     int64                  `json:"input_chars"`
+	OutputChars    int64                  `json:"output_chars"`
+	DataCount      int64                  `json:"data_count"`
+	ConfirmedCount int64                  `json:"confirmed_count"`
+	WorkerVersion  string                 `json:"worker_version,omitempty"`
+	Live           *TaskLiveProgress      `json:"live"`
+	Sources        []string               `json:"sources"`
+}
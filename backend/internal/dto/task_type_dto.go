@@ -0,0 +1,38 @@
+package dto
+
+// CreateTaskTypeRequest 创建任务类型请求
+type CreateTaskTypeRequest struct {
+	Name          string `json:"name" binding:"required,max=50"`
+	Description   string `json:"description" binding:"max=500"`
+	SpecialPrompt string `json:"special_prompt"`
+	Directions    string `json:"directions"`
+	OutputSchema  string `json:"output_schema"` // 回答的 JSON Schema，为空时不检查
+	IsActive      *bool  `json:"is_active"`     // 为空时默认启用
+	// ScoringRules 同时创建的评分规则（task_type 固定为该类型）
+	ScoringRules []CreateScoringRuleRequest `json:"scoring_rules" binding:"omitempty,dive"`
+}
+
+// UpdateTaskTypeRequest 更新任务类型请求（名称不可修改，已有任务按名称引用类型）
+type UpdateTaskTypeRequest struct {
+	Description   *string `json:"description" binding:"omitempty,max=500"`
+	SpecialPrompt *string `json:"special_prompt"`
+	Directions    *string `json:"directions"`
+	OutputSchema  *string `json:"output_schema"`
+	IsActive      *bool   `json:"is_active"`
+}
+
+// TaskTypeResponse 任务类型响应
+type TaskTypeResponse struct {
+	ID            uint                   `json:"id"`
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description"`
+	SpecialPrompt string                 `json:"special_prompt"`
+	Directions    string                 `json:"directions"`
+	OutputSchema  string                 `json:"output_schema"`
+	IsBuiltin     bool                   `json:"is_builtin"`
+	IsActive      bool                   `json:"is_active"`
+	ScoringRules  []*ScoringRuleResponse `json:"scoring_rules"`
+	CreatedBy     uint                   `json:"created_by"`
+	CreatedAt     string                 `json:"created_at"`
+	UpdatedAt     string                 `json:"updated_at"`
+}
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"time"
+)
+
+// DisplayTimeLayout 按用户时区展示时间时使用的格式（仅用于报告中的展示字段）
+const DisplayTimeLayout = "2006-01-02 15:04:05"
+
+// FilenameTimeLayout 生成文件名中的时间格式
+const FilenameTimeLayout = "20060102_150405"
+
+// FormatTime 将时间序列化为 ISO-8601 UTC 字符串（零值返回空字符串）
+// 所有API响应中的时间字段都应通过该函数输出
+func FormatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// FormatTimePtr 序列化可空时间（nil 或零值返回 nil）
+func FormatTimePtr(t *time.Time) *string {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	formatted := FormatTime(*t)
+	return &formatted
+}
+
+// FormatDisplayTime 按用户时区格式化时间，用于报告中的展示字段
+func FormatDisplayTime(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format(DisplayTimeLayout)
+}
+
+// FormatDisplayTimePtr 按用户时区格式化可空时间
+func FormatDisplayTimePtr(t *time.Time, loc *time.Location) *string {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	formatted := FormatDisplayTime(*t, loc)
+	return &formatted
+}
+
+// FilenameTimestamp 生成文件名中使用的时间戳（按用户时区）
+func FilenameTimestamp(t time.Time, loc *time.Location) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format(FilenameTimeLayout)
+}
@@ -0,0 +1,51 @@
+package dto
+
+// InitUploadRequest 初始化分片上传请求
+type InitUploadRequest struct {
+	Filename  string `json:"filename" binding:"required"`
+	TotalSize int64  `json:"total_size" binding:"required,min=1"`
+	ChunkSize int64  `json:"chunk_size"`
+	Checksum  string `json:"checksum"` // 整个文件的SHA-256（十六进制），可选
+	// 结构校验模式：none, report, reject, quarantine（为空时使用配置默认值）
+	ValidationMode string `json:"validation_mode" binding:"omitempty,oneof=none report reject quarantine"`
+}
+
+// InitUploadResponse 初始化分片上传响应
+type InitUploadResponse struct {
+	UploadID    string `json:"upload_id"`
+	ChunkSize   int64  `json:"chunk_size"`
+	TotalChunks int    `json:"total_chunks"`
+	ExpiresAt   string `json:"expires_at"`
+}
+
+// UploadStatusResponse 分片上传状态响应
+type UploadStatusResponse struct {
+	UploadID       string `json:"upload_id"`
+	Filename       string `json:"filename"`
+	Status         string `json:"status"`
+	TotalSize      int64  `json:"total_size"`
+	ChunkSize      int64  `json:"chunk_size"`
+	TotalChunks    int    `json:"total_chunks"`
+	ReceivedChunks []int  `json:"received_chunks"`
+	FileID         *uint  `json:"file_id,omitempty"`
+}
+
+// BulkUploadResult 批量上传中一个文件的结果
+type BulkUploadResult struct {
+	Filename    string                `json:"filename"`
+	Archive     string                `json:"archive,omitempty"` // 来自压缩包时为压缩包文件名
+	Success     bool                  `json:"success"`
+	FileID      uint                  `json:"file_id,omitempty"`
+	DisplayPath string                `json:"display_path,omitempty"`
+	FileSize    int                   `json:"file_size,omitempty"`
+	Validation  *FileValidationReport `json:"validation,omitempty"`
+	Error       string                `json:"error,omitempty"`
+}
+
+// BulkUploadResponse 批量上传响应（每个文件单独登记，部分文件失败不影响其他文件）
+type BulkUploadResponse struct {
+	Total     int                `json:"total"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Results   []BulkUploadResult `json:"results"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+// UserSettingsResponse 用户个人设置
+type UserSettingsResponse struct {
+	DefaultModelID    *uint                  `json:"default_model_id"`    // 启动任务未指定模型时使用的模型
+	DefaultBatchSize  int                    `json:"default_batch_size"`  // 0 表示使用系统默认值
+	DefaultDataRounds int                    `json:"default_data_rounds"` // 0 表示使用系统默认值
+	ExportFormat      string                 `json:"export_format"`       // 为空表示 jsonl
+	UIOptions         map[string]interface{} `json:"ui_options"`
+	UpdatedAt         string                 `json:"updated_at,omitempty"`
+}
+
+// UpdateUserSettingsRequest 更新个人设置请求，只更新提供的字段
+type UpdateUserSettingsRequest struct {
+	DefaultModelID    *uint   `json:"default_model_id"` // 0 表示清除
+	DefaultBatchSize  *int    `json:"default_batch_size" binding:"omitempty,min=0,max=1024"`
+	DefaultDataRounds *int    `json:"default_data_rounds" binding:"omitempty,min=0,max=1000"`
+	ExportFormat      *string `json:"export_format"` // jsonl、csv、sharegpt、openai_chat、alpaca、parquet，空字符串表示清除
+	// UIOptions 前端界面选项，整体替换
+	UIOptions map[string]interface{} `json:"ui_options"`
+}
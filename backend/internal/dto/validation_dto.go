@@ -0,0 +1,28 @@
+package dto
+
+// ValidateFileRequest 文件校验请求
+type ValidateFileRequest struct {
+	Mode string `json:"mode" binding:"omitempty,oneof=report quarantine"` // 默认 report
+}
+
+// ValidationIssue 校验问题
+type ValidationIssue struct {
+	Line    int    `json:"line"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// FileValidationReport 文件校验报告
+type FileValidationReport struct {
+	FileID           uint              `json:"file_id,omitempty"`
+	Mode             string            `json:"mode"`
+	IsValid          bool              `json:"is_valid"`
+	TotalLines       int               `json:"total_lines"`
+	ValidLines       int               `json:"valid_lines"`
+	InvalidLines     int               `json:"invalid_lines"`
+	ErrorCounts      map[string]int    `json:"error_counts"`
+	Errors           []ValidationIssue `json:"errors"`
+	Truncated        bool              `json:"truncated"`
+	QuarantineFileID *uint             `json:"quarantine_file_id,omitempty"`
+	CheckedAt        string            `json:"checked_at,omitempty"`
+}
@@ -0,0 +1,66 @@
+package dto
+
+// CreateWebhookRequest 注册Webhook请求
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required,url"`
+	Events      []string `json:"events"` // 为空表示订阅全部事件
+	Secret      string   `json:"secret"` // 为空时自动生成
+	Description string   `json:"description"`
+}
+
+// UpdateWebhookRequest 更新Webhook请求
+type UpdateWebhookRequest struct {
+	URL         *string  `json:"url" binding:"omitempty,url"`
+	Events      []string `json:"events"`
+	Description *string  `json:"description"`
+	IsActive    *bool    `json:"is_active"`
+}
+
+// WebhookResponse Webhook响应
+type WebhookResponse struct {
+	ID          uint     `json:"id"`
+	URL         string   `json:"url"`
+	Events      []string `json:"events"`
+	Description string   `json:"description"`
+	IsActive    bool     `json:"is_active"`
+	Secret      string   `json:"secret,omitempty"` // 仅在创建时返回
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+// WebhookDeliveryResponse Webhook投递记录响应
+type WebhookDeliveryResponse struct {
+	ID           uint    `json:"id"`
+	WebhookID    uint    `json:"webhook_id"`
+	Event        string  `json:"event"`
+	TaskID       string  `json:"task_id"`
+	Payload      string  `json:"payload"`
+	Success      bool    `json:"success"`
+	StatusCode   int     `json:"status_code"`
+	Attempts     int     `json:"attempts"`
+	ResponseBody string  `json:"response_body"`
+	ErrorMessage string  `json:"error_message"`
+	CreatedAt    string  `json:"created_at"`
+	DeliveredAt  *string `json:"delivered_at"`
+}
+
+// WebhookPayload 投递给回调地址的JSON内容
+type WebhookPayload struct {
+	Event      string             `json:"event"`
+	DeliveryID uint               `json:"delivery_id"`
+	Timestamp  string             `json:"timestamp"`
+	Task       WebhookTaskPayload `json:"task"`
+}
+
+// WebhookTaskPayload 事件中的任务信息
+type WebhookTaskPayload struct {
+	TaskID       string                 `json:"task_id"`
+	UserID       uint                   `json:"user_id"`
+	Status       string                 `json:"status"`
+	Params       map[string]interface{} `json:"params"`
+	ErrorMessage string                 `json:"error_message,omitempty"`
+	StartedAt    string                 `json:"started_at"`
+	FinishedAt   *string                `json:"finished_at"`
+	InputChars   int64                  `json:"input_chars"`
+	OutputChars  int64                  `json:"output_chars"`
+}
@@ -0,0 +1,50 @@
+package dto
+
+// CreateWorkspaceRequest 创建工作区请求
+type CreateWorkspaceRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description"`
+}
+
+// UpdateWorkspaceRequest 更新工作区请求（字段为空时不修改）
+type UpdateWorkspaceRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Description *string `json:"description"`
+}
+
+// AddWorkspaceMemberRequest 添加工作区成员请求
+type AddWorkspaceMemberRequest struct {
+	Username string `json:"username" binding:"required"`
+	Role     string `json:"role"` // viewer/reviewer/operator/admin，默认 viewer
+}
+
+// UpdateWorkspaceMemberRequest 修改成员角色请求
+type UpdateWorkspaceMemberRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// MoveToWorkspaceRequest 将文件或任务移入工作区请求
+type MoveToWorkspaceRequest struct {
+	WorkspaceID *uint `json:"workspace_id"` // 为空表示移出工作区，转为个人资源
+}
+
+// WorkspaceResponse 工作区响应
+type WorkspaceResponse struct {
+	ID          uint                      `json:"id"`
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	OwnerID     uint                      `json:"owner_id"`
+	Role        string                    `json:"role"` // 当前用户在工作区中的角色
+	MemberCount int64                     `json:"member_count"`
+	Members     []WorkspaceMemberResponse `json:"members,omitempty"`
+	CreatedAt   string                    `json:"created_at"`
+	UpdatedAt   string                    `json:"updated_at"`
+}
+
+// WorkspaceMemberResponse 工作区成员响应
+type WorkspaceMemberResponse struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	JoinedAt string `json:"joined_at"`
+}
@@ -4,6 +4,9 @@ import (
 	"net/url"
 	"strconv"
 
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/models"
 	"gen-go/internal/repository"
 	"gen-go/internal/service"
 	"gen-go/internal/utils"
@@ -13,11 +16,14 @@ import (
 
 // AdminHandler 管理员处理器
 type AdminHandler struct {
-	userRepo              *repository.UserRepository
-	taskRepo              *repository.TaskRepository
-	generatedDataRepo     *repository.GeneratedDataRepository
-	generatedDataService  *service.GeneratedDataService
-	modelService          *service.ModelService
+	userRepo             *repository.UserRepository
+	taskRepo             *repository.TaskRepository
+	generatedDataRepo    *repository.GeneratedDataRepository
+	generatedDataService *service.GeneratedDataService
+	modelService         *service.ModelService
+	auditService         *service.ExportAuditService
+	auditLogService      *service.AuditLogService
+	authService          *service.AuthService
 }
 
 // NewAdminHandler 创建管理员处理器
@@ -27,17 +33,32 @@ func NewAdminHandler(
 	generatedDataRepo *repository.GeneratedDataRepository,
 	generatedDataService *service.GeneratedDataService,
 	modelService *service.ModelService,
+	auditService *service.ExportAuditService,
+	auditLogService *service.AuditLogService,
+	authService *service.AuthService,
 ) *AdminHandler {
 	return &AdminHandler{
-		userRepo:              userRepo,
-		taskRepo:              taskRepo,
-		generatedDataRepo:     generatedDataRepo,
-		generatedDataService:  generatedDataService,
-		modelService:          modelService,
+		userRepo:             userRepo,
+		taskRepo:             taskRepo,
+		generatedDataRepo:    generatedDataRepo,
+		generatedDataService: generatedDataService,
+		modelService:         modelService,
+		auditService:         auditService,
+		auditLogService:      auditLogService,
+		authService:          authService,
 	}
 }
 
 // ListUsers 获取所有用户
+// @Summary 获取所有用户
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query string false "页码（默认 1）"
+// @Param per_page query string false "每页条数（默认 20）"
+// @Success 200 {object} utils.PaginationResponse
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/users [get]
 func (h *AdminHandler) ListUsers(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
@@ -48,23 +69,161 @@ func (h *AdminHandler) ListUsers(c *gin.Context) {
 		utils.InternalError(c, err.Error())
 		return
 	}
+	for i := range users {
+		users[i].Role = users[i].EffectiveRole()
+	}
 
 	utils.PaginatedResponse(c, users, total, page, perPage)
 }
 
 // DeleteUser 删除用户
+// @Summary 删除用户
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/users/{id} [delete]
 func (h *AdminHandler) DeleteUser(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 
+	// 删除前记录用户信息作为审计快照
+	before, _ := h.userRepo.GetByID(uint(id))
+
 	if err := h.userRepo.Delete(uint(id)); err != nil {
 		utils.InternalError(c, err.Error())
 		return
 	}
 
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionUserDelete, models.AuditResourceUser, id, before, nil))
+
 	utils.SuccessWithMessage(c, "用户已删除", gin.H{"success": true})
 }
 
+// ListRoles 获取所有角色及其权限
+// @Summary 获取所有角色及其权限
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Router /api/admin/roles [get]
+func (h *AdminHandler) ListRoles(c *gin.Context) {
+	roles := models.Roles()
+	result := make([]dto.RoleInfo, len(roles))
+	for i, role := range roles {
+		permissions := models.RolePermissions(role)
+		names := make([]string, len(permissions))
+		for j, p := range permissions {
+			names[j] = string(p)
+		}
+		result[i] = dto.RoleInfo{Role: role, Permissions: names}
+	}
+
+	utils.SuccessResponse(c, result)
+}
+
+// UpdateUserRole 设置用户角色
+// @Summary 设置用户角色
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param request body dto.UpdateUserRoleRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/users/{id}/role [put]
+func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	var req dto.UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+	if !models.IsValidRole(req.Role) {
+		utils.BadRequest(c, "无效的角色: "+req.Role)
+		return
+	}
+
+	// 避免管理员取消自己的管理员角色导致无人可管理
+	currentUserID, _ := middleware.GetUserID(c)
+	if uint(id) == currentUserID && req.Role != models.RoleAdmin {
+		utils.BadRequest(c, "不能取消自己的管理员角色")
+		return
+	}
+
+	before, err := h.userRepo.GetByID(uint(id))
+	if err != nil {
+		utils.NotFound(c, "用户不存在")
+		return
+	}
+	before.Role = before.EffectiveRole()
+
+	if err := h.userRepo.UpdateRole(uint(id), req.Role); err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	after, err := h.userRepo.GetByID(uint(id))
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+	after.Role = after.EffectiveRole()
+
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionUserRoleUpdate, models.AuditResourceUser, id, before, after))
+
+	utils.SuccessWithMessage(c, "角色已更新", after)
+}
+
+// ResetUserPassword 重置用户密码，用户下次登录后必须修改密码
+// @Summary 重置用户密码，用户下次登录后必须修改密码
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param request body dto.ResetPasswordRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/admin/users/{id}/password [put]
+func (h *AdminHandler) ResetUserPassword(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的用户ID")
+		return
+	}
+
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.authService.ResetPassword(uint(id), req.NewPassword); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionPasswordReset, models.AuditResourceUser, id, nil, nil))
+
+	utils.SuccessWithMessage(c, "密码已重置", nil)
+}
+
 // GetUserReports 获取用户报告
+// @Summary 获取用户报告
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/users/{id}/reports [get]
 func (h *AdminHandler) GetUserReports(c *gin.Context) {
 	// 获取路径参数中的用户ID
 	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -73,6 +232,8 @@ func (h *AdminHandler) GetUserReports(c *gin.Context) {
 		return
 	}
 
+	loc := middleware.GetLocation(c)
+
 	// 获取用户的所有任务（不限制数量）
 	tasks, _, err := h.taskRepo.ListByUserID(uint(userID), 0, 1000)
 	if err != nil {
@@ -99,40 +260,60 @@ func (h *AdminHandler) GetUserReports(c *gin.Context) {
 		}
 
 		reports = append(reports, map[string]interface{}{
-			"id":               task.ID,
-			"task_id":          task.TaskID,
-			"status":           task.Status,
-			"started_at":       task.StartedAt,
-			"finished_at":      task.FinishedAt,
-			"data_count":       int(dataCount),
-			"has_data":         dataCount > 0,
-			"confirmed_count":  int(confirmedCount),
-			"is_fully_reviewed": dataCount > 0 && confirmedCount == dataCount,
-			"input_chars":       task.InputChars,
-			"output_chars":      task.OutputChars,
-			"params":           params,
-			"error_message":    task.ErrorMessage,
+			"id":                  task.ID,
+			"task_id":             task.TaskID,
+			"status":              task.Status,
+			"started_at":          dto.FormatTime(task.StartedAt),
+			"finished_at":         dto.FormatTimePtr(task.FinishedAt),
+			"started_at_display":  dto.FormatDisplayTime(task.StartedAt, loc),
+			"finished_at_display": dto.FormatDisplayTimePtr(task.FinishedAt, loc),
+			"data_count":          int(dataCount),
+			"has_data":            dataCount > 0,
+			"confirmed_count":     int(confirmedCount),
+			"is_fully_reviewed":   dataCount > 0 && confirmedCount == dataCount,
+			"input_chars":         task.InputChars,
+			"output_chars":        task.OutputChars,
+			"params":              params,
+			"error_message":       task.ErrorMessage,
 		})
 	}
 
 	utils.SuccessResponse(c, gin.H{
-		"success": true,
-		"reports": reports,
-		"total":   len(reports),
+		"success":  true,
+		"reports":  reports,
+		"total":    len(reports),
+		"timezone": loc.String(),
 	})
 }
 
 // DownloadUserReport 下载用户报告
+// @Summary 下载用户报告
+// @Tags admin
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param task_id path string true "任务ID"
+// @Param format query string false "导出格式（默认 jsonl）"
+// @Success 200 {file} file
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/users/{id}/reports/{task_id}/download [get]
 func (h *AdminHandler) DownloadUserReport(c *gin.Context) {
 	taskID := c.Param("task_id")
 	format := c.DefaultQuery("format", "jsonl")
 
-	data, filename, err := h.generatedDataService.ExportData(taskID, format)
+	data, filename, rowCount, err := h.generatedDataService.ExportTaskData(taskID, format, nil, middleware.GetLocation(c))
 	if err != nil {
 		utils.InternalError(c, err.Error())
 		return
 	}
 
+	audit := newExportAudit(c, models.ExportResourceGeneratedData, taskID, format, rowCount)
+	if ownerID, err := strconv.ParseUint(c.Param("id"), 10, 32); err == nil {
+		owner := uint(ownerID)
+		audit.OwnerID = &owner
+	}
+	h.auditService.Record(audit)
+
 	// URL 编码文件名以支持中文和特殊字符
 	encodedFilename := url.QueryEscape(filename)
 
@@ -142,6 +323,15 @@ func (h *AdminHandler) DownloadUserReport(c *gin.Context) {
 }
 
 // ListAllTasks 获取所有任务
+// @Summary 获取所有任务
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query string false "页码（默认 1）"
+// @Param per_page query string false "每页条数（默认 20）"
+// @Success 200 {object} utils.PaginationResponse
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/tasks [get]
 func (h *AdminHandler) ListAllTasks(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
@@ -156,15 +346,78 @@ func (h *AdminHandler) ListAllTasks(c *gin.Context) {
 	utils.PaginatedResponse(c, tasks, total, page, perPage)
 }
 
+// ListDeadLetterTasks 获取自动重试次数用尽（dead_letter）的任务及其各次尝试
+// @Summary 获取自动重试次数用尽（dead_letter）的任务及其各次尝试
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query string false "页码（默认 1）"
+// @Param per_page query string false "每页条数（默认 20）"
+// @Success 200 {object} utils.PaginationResponse
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/tasks/dead-letter [get]
+func (h *AdminHandler) ListDeadLetterTasks(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	offset := (page - 1) * perPage
+	tasks, total, err := h.taskRepo.ListByStatus(models.TaskStatusDeadLetter, offset, perPage)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	// 自动重试产生的任务按第一次尝试的任务ID归组
+	roots := make([]string, len(tasks))
+	for i, task := range tasks {
+		roots[i] = task.TaskID
+		if root, ok := task.Params["retry_root"].(string); ok && root != "" {
+			roots[i] = root
+		}
+	}
+	attempts, err := h.taskRepo.ListAttempts(roots)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+	attemptsByRoot := make(map[string][]models.TaskAttempt)
+	for _, attempt := range attempts {
+		attemptsByRoot[attempt.RootTaskID] = append(attemptsByRoot[attempt.RootTaskID], attempt)
+	}
+
+	items := make([]gin.H, len(tasks))
+	for i, task := range tasks {
+		taskAttempts := attemptsByRoot[roots[i]]
+		if taskAttempts == nil {
+			taskAttempts = []models.TaskAttempt{}
+		}
+		items[i] = gin.H{"task": task, "attempts": taskAttempts}
+	}
+
+	utils.PaginatedResponse(c, items, total, page, perPage)
+}
+
 // DeleteTask 删除任务记录
+// @Summary 删除任务记录
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/tasks/{id} [delete]
 func (h *AdminHandler) DeleteTask(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 
+	before, _ := h.taskRepo.GetByID(uint(id))
+
 	if err := h.taskRepo.Delete(uint(id)); err != nil {
 		utils.InternalError(c, err.Error())
 		return
 	}
 
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionTaskDelete, models.AuditResourceTask, id, before, nil))
+
 	utils.SuccessWithMessage(c, "任务已删除", gin.H{"success": true})
 }
 
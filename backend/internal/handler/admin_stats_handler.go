@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"strconv"
+
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminStatsHandler 管理员仪表盘统计处理器
+type AdminStatsHandler struct {
+	statsService *service.AdminStatsService
+}
+
+// NewAdminStatsHandler 创建仪表盘统计处理器
+func NewAdminStatsHandler(statsService *service.AdminStatsService) *AdminStatsHandler {
+	return &AdminStatsHandler{
+		statsService: statsService,
+	}
+}
+
+// GetStats 获取仪表盘统计（days 指定任务状态统计窗口，默认30天）
+// @Summary 获取仪表盘统计（days 指定任务状态统计窗口，默认30天）
+// @Tags admin_stats
+// @Produce json
+// @Security BearerAuth
+// @Param days query string false "统计天数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/stats [get]
+func (h *AdminStatsHandler) GetStats(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(service.DefaultStatsWindowDays)))
+	if err != nil || days <= 0 {
+		utils.BadRequest(c, "无效的 days 参数")
+		return
+	}
+
+	stats, err := h.statsService.GetStats(days)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, stats)
+}
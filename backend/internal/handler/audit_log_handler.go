@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+
+	"gen-go/internal/middleware"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditLogHandler 审计日志处理器（管理员）
+type AuditLogHandler struct {
+	auditLogService *service.AuditLogService
+}
+
+// NewAuditLogHandler 创建审计日志处理器
+func NewAuditLogHandler(auditLogService *service.AuditLogService) *AuditLogHandler {
+	return &AuditLogHandler{
+		auditLogService: auditLogService,
+	}
+}
+
+// ListLogs 分页查询审计日志
+// 支持 user_id、action、resource_type、resource_id、start_date、end_date（YYYY-MM-DD）过滤
+// @Summary 分页查询审计日志
+// @Tags audit_log
+// @Produce json
+// @Security BearerAuth
+// @Param action query string false "操作类型"
+// @Param page query string false "页码（默认 1）"
+// @Param per_page query string false "每页条数（默认 20）"
+// @Param resource_type query string false "资源类型"
+// @Param resource_id query string false "资源ID"
+// @Param user_id query string false "用户ID"
+// @Param start_date query string false "开始日期（YYYY-MM-DD）"
+// @Param end_date query string false "结束日期（YYYY-MM-DD）"
+// @Success 200 {object} utils.PaginationResponse
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/audit [get]
+func (h *AuditLogHandler) ListLogs(c *gin.Context) {
+	// 时间、用户和资源条件与导出审计一致
+	exportFilter, err := parseExportAuditFilter(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+	filter := repository.AuditLogFilter{
+		UserID:       exportFilter.UserID,
+		Action:       c.Query("action"),
+		ResourceType: exportFilter.ResourceType,
+		ResourceID:   exportFilter.ResourceID,
+		StartTime:    exportFilter.StartTime,
+		EndTime:      exportFilter.EndTime,
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	result, err := h.auditLogService.ListLogs(filter, page, perPage)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.PaginatedResponse(c, result.Items, result.Total, result.Page, result.PerPage)
+}
+
+// newAuditLog 根据请求构建审计记录，before/after 为变更前后的对象（为 nil 时不记录快照）
+func newAuditLog(c *gin.Context, action, resourceType string, resourceID interface{}, before, after interface{}) *models.AuditLog {
+	userID, _ := middleware.GetUserID(c)
+	username, _ := middleware.GetUsername(c)
+
+	return &models.AuditLog{
+		UserID:       userID,
+		Username:     username,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   fmt.Sprint(resourceID),
+		Before:       service.AuditSnapshot(before),
+		After:        service.AuditSnapshot(after),
+		Endpoint:     c.Request.Method + " " + c.FullPath(),
+		ClientIP:     c.ClientIP(),
+	}
+}
@@ -93,15 +93,104 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 	utils.SuccessResponse(c, userInfo)
 }
 
+// UpdateTimezone 设置当前用户的展示时区
+// @Summary 设置展示时区
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.UpdateTimezoneRequest true "时区"
+// @Success 200 {object} utils.Response{data=dto.UserInfo}
+// @Router /api/me/timezone [put]
+func (h *AuthHandler) UpdateTimezone(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.UpdateTimezoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	userInfo, err := h.authService.UpdateTimezone(userID, req.Timezone)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "时区已更新", userInfo)
+}
+
+// ChangePassword 修改当前用户密码
+// @Summary 修改密码
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ChangePasswordRequest true "原密码与新密码"
+// @Success 200 {object} utils.Response
+// @Router /api/me/password [post]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.authService.ChangePassword(userID, &req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "密码已修改", nil)
+}
+
+// Refresh 刷新访问Token
+// @Summary 刷新访问Token
+// @Description 使用刷新Token换取新的访问Token，刷新Token同时轮换，旧刷新Token立即失效
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body dto.RefreshTokenRequest true "刷新Token"
+// @Success 200 {object} utils.Response{data=dto.LoginResponse}
+// @Router /api/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		utils.Unauthorized(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, resp)
+}
+
 // Logout 用户登出
 // @Summary 用户登出
+// @Description 吊销当前访问Token；请求体中携带 refresh_token 时一并吊销
 // @Tags 认证
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param request body dto.LogoutRequest false "刷新Token"
 // @Success 200 {object} utils.Response
 // @Router /api/logout [post]
 func (h *AuthHandler) Logout(c *gin.Context) {
-	// JWT是无状态的,登出只需客户端删除Token
+	var req dto.LogoutRequest
+	// 请求体可选
+	_ = c.ShouldBindJSON(&req)
+
+	tokenID, expiresAt := middleware.GetTokenInfo(c)
+	if err := h.authService.Logout(tokenID, expiresAt, req.RefreshToken); err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
 	utils.SuccessWithMessage(c, "登出成功", nil)
 }
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/url"
+
+	"gen-go/internal/models"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BackupHandler 数据库备份处理器（管理员）
+type BackupHandler struct {
+	backupService   *service.BackupService
+	auditLogService *service.AuditLogService
+}
+
+// NewBackupHandler 创建数据库备份处理器
+func NewBackupHandler(backupService *service.BackupService, auditLogService *service.AuditLogService) *BackupHandler {
+	return &BackupHandler{
+		backupService:   backupService,
+		auditLogService: auditLogService,
+	}
+}
+
+// CreateBackup 立即生成一份数据库备份
+// @Summary 立即生成一份数据库备份
+// @Tags backup
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/backup [post]
+func (h *BackupHandler) CreateBackup(c *gin.Context) {
+	backup, err := h.backupService.Create()
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionBackupCreate, models.AuditResourceBackup, backup.Name, nil, backup))
+
+	utils.SuccessWithMessage(c, "备份已完成", backup)
+}
+
+// ListBackups 列出全部数据库备份（最新的在前）
+// @Summary 列出全部数据库备份（最新的在前）
+// @Tags backup
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/backups [get]
+func (h *BackupHandler) ListBackups(c *gin.Context) {
+	backups, err := h.backupService.List()
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, backups)
+}
+
+// DownloadBackup 下载数据库备份文件
+// @Summary 下载数据库备份文件
+// @Tags backup
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param name path string true "备份文件名"
+// @Success 200 {file} file
+// @Failure 404 {object} utils.Response
+// @Router /api/admin/backups/{name}/download [get]
+func (h *BackupHandler) DownloadBackup(c *gin.Context) {
+	name := c.Param("name")
+	path, err := h.backupService.GetPath(name)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionBackupDownload, models.AuditResourceBackup, name, nil, nil))
+
+	c.Header("Content-Disposition", "attachment; filename*=UTF-8''"+url.PathEscape(name))
+	c.Header("Content-Type", "application/octet-stream")
+	c.File(path)
+}
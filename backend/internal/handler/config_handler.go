@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/plugin"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfigHandler 运行时配置处理器（管理员）
+type ConfigHandler struct {
+	auditLogService *service.AuditLogService
+}
+
+// NewConfigHandler 创建运行时配置处理器
+func NewConfigHandler(auditLogService *service.AuditLogService) *ConfigHandler {
+	return &ConfigHandler{auditLogService: auditLogService}
+}
+
+// ReloadConfig 重新读取 config.yaml，可热加载的配置（模型服务、CORS、限流等）立即生效
+// @Summary 重新加载配置文件
+// @Description 重新读取 config.yaml，可热加载的配置（模型服务、CORS、限流等）立即生效；其余配置段的修改在响应中列出，需重启服务
+// @Tags config
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=dto.ConfigReloadResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/admin/config/reload [post]
+func (h *ConfigHandler) ReloadConfig(c *gin.Context) {
+	result, err := config.ReloadConfig()
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	resp := &dto.ConfigReloadResponse{
+		Applied:         result.Applied,
+		RequiresRestart: result.RequiresRestart,
+	}
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionConfigReload, models.AuditResourceConfig, "config.yaml", nil, resp))
+
+	utils.SuccessWithMessage(c, "配置已重新加载", resp)
+}
+
+// ListPlugins 获取已注册的流水线插件（代码注册的 Go 插件和配置 plugins 声明的 exec/webhook 插件）
+// @Summary 获取已注册的流水线插件
+// @Tags config
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Router /api/admin/plugins [get]
+func (h *ConfigHandler) ListPlugins(c *gin.Context) {
+	utils.SuccessResponse(c, plugin.Default().List())
+}
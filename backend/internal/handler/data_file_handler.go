@@ -1,12 +1,22 @@
 package handler
 
 import (
+	"archive/zip"
+	"errors"
+	"fmt"
 	"io"
+	"log"
+	"net/http"
 	"net/url"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"gen-go/internal/dto"
 	"gen-go/internal/middleware"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
 	"gen-go/internal/service"
 	"gen-go/internal/utils"
 
@@ -16,16 +26,44 @@ import (
 // DataFileHandler 数据文件处理器
 type DataFileHandler struct {
 	dataFileService *service.DataFileService
+	versionService  *service.FileVersionService
+	auditService    *service.ExportAuditService
+	auditLogService *service.AuditLogService
 }
 
 // NewDataFileHandler 创建数据文件处理器
-func NewDataFileHandler(dataFileService *service.DataFileService) *DataFileHandler {
+func NewDataFileHandler(dataFileService *service.DataFileService, versionService *service.FileVersionService, auditService *service.ExportAuditService, auditLogService *service.AuditLogService) *DataFileHandler {
 	return &DataFileHandler{
 		dataFileService: dataFileService,
+		versionService:  versionService,
+		auditService:    auditService,
+		auditLogService: auditLogService,
 	}
 }
 
 // UploadFile 上传文件
+// @Summary 上传文件
+// @Tags data_file
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "上传的文件"
+// @Param validation_mode formData string false "结构校验模式"
+// @Param csv_delimiter formData string false "CSV 分隔符: auto（默认）、comma、tab、semicolon"
+// @Param csv_quote formData string false "CSV 引号处理: strict（默认）、lazy、none"
+// @Param csv_encoding formData string false "CSV 编码: auto（默认）、utf-8、gbk、utf-16le、utf-16be"
+// @Param csv_lenient formData boolean false "宽松模式：跳过无法解析的行并在 conversion.csv 报告中列出"
+// @Param text_split formData string false "纯文本切分方式: paragraph（默认，按空行）、line、separator"
+// @Param text_separator formData string false "text_split=separator 时的分隔符（支持 \n、\t 转义）"
+// @Param text_meta formData string false "纯文本每条样本的 meta_description"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 413 {object} utils.Response
+// @Failure 415 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Failure 503 {object} utils.Response
+// @Router /api/data_files/upload [post]
 func (h *DataFileHandler) UploadFile(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 
@@ -50,22 +88,157 @@ func (h *DataFileHandler) UploadFile(c *gin.Context) {
 		return
 	}
 
-	// 上传文件
-	dataFile, err := h.dataFileService.UploadFile(userID, file, content)
+	csvOpts, err := parseCSVOptions(c)
 	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+	textOpts, err := parseTextSplitOptions(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	// 上传文件（validation_mode 为空时使用配置默认值）
+	parseOpts := service.UploadParseOptions{CSV: csvOpts, Text: textOpts}
+	dataFile, report, conversion, err := h.dataFileService.UploadFile(userID, file, content, c.PostForm("validation_mode"), parseOpts)
+	if err != nil {
+		if errors.Is(err, service.ErrValidationRejected) {
+			respondValidationRejected(c, err, report)
+			return
+		}
+		if errors.Is(err, service.ErrUploadInfected) {
+			utils.ErrorResponse(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrJobQueueFull) || errors.Is(err, service.ErrVirusScanUnavailable) {
+			utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error())
+			return
+		}
 		utils.InternalError(c, err.Error())
 		return
 	}
 
 	utils.SuccessWithMessage(c, "文件上传成功", gin.H{
-		"id":          dataFile.ID,
-		"filename":    dataFile.Filename,
+		"id":           dataFile.ID,
+		"filename":     dataFile.Filename,
 		"display_path": h.dataFileService.GetFileDisplayPath(dataFile.ID, dataFile.Filename),
-		"file_size":   dataFile.FileSize,
+		"file_size":    dataFile.FileSize,
+		"validation":   report,
+		"conversion":   conversion,
 	})
 }
 
+// ValidateFile 按 meta/turns 结构校验已上传的文件
+// @Summary 按 meta/turns 结构校验已上传的文件
+// @Tags data_file
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Param request body dto.ValidateFileRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/data_files/{file_id}/validate [post]
+func (h *DataFileHandler) ValidateFile(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	fileID, err := strconv.ParseUint(c.Param("file_id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的文件ID")
+		return
+	}
+
+	var req dto.ValidateFileRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	report, err := h.dataFileService.ValidateFile(uint(fileID), userID, req.Mode)
+	if err != nil {
+		if errors.Is(err, service.ErrJobQueueFull) {
+			utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, report)
+}
+
+// GetFileValidation 获取文件最近一次校验报告
+// @Summary 获取文件最近一次校验报告
+// @Tags data_file
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/data_files/{file_id}/validation [get]
+func (h *DataFileHandler) GetFileValidation(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	fileID, err := strconv.ParseUint(c.Param("file_id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的文件ID")
+		return
+	}
+
+	report, err := h.dataFileService.GetFileValidation(uint(fileID), userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, report)
+}
+
+// respondValidationRejected 返回结构校验未通过的响应（附带校验报告）
+func respondValidationRejected(c *gin.Context, err error, report *dto.FileValidationReport) {
+	c.JSON(http.StatusBadRequest, utils.Response{
+		Code:    http.StatusBadRequest,
+		Message: err.Error(),
+		Data:    report,
+	})
+}
+
+// parseCSVOptions 解析表单中的 CSV 解析选项（csv_delimiter、csv_quote、csv_encoding、csv_lenient）
+func parseCSVOptions(c *gin.Context) (utils.CSVOptions, error) {
+	lenient := false
+	if value := c.PostForm("csv_lenient"); value != "" {
+		var err error
+		if lenient, err = strconv.ParseBool(value); err != nil {
+			return utils.CSVOptions{}, fmt.Errorf("无效的 csv_lenient: %s", value)
+		}
+	}
+	return utils.ParseCSVOptions(c.PostForm("csv_delimiter"), c.PostForm("csv_quote"), c.PostForm("csv_encoding"), lenient)
+}
+
+// parseTextSplitOptions 解析表单中的纯文本切分选项（text_split、text_separator、text_meta）
+func parseTextSplitOptions(c *gin.Context) (utils.TextSplitOptions, error) {
+	return utils.ParseTextSplitOptions(c.PostForm("text_split"), c.PostForm("text_separator"), c.PostForm("text_meta"))
+}
+
 // ListFiles 获取文件列表
+// @Summary 获取文件列表
+// @Tags data_file
+// @Produce json
+// @Security BearerAuth
+// @Param page query string false "页码（默认 1）"
+// @Param per_page query string false "每页条数（默认 20）"
+// @Param workspace_id query string false "工作区ID"
+// @Param folder query string false "文件夹路径（传空值表示根目录）"
+// @Param recursive query boolean false "是否包含子文件夹中的文件"
+// @Param tag query string false "标签（多个用逗号分隔，返回同时带有这些标签的文件）"
+// @Success 200 {object} utils.PaginationResponse
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/data_files [get]
 func (h *DataFileHandler) ListFiles(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 
@@ -79,7 +252,30 @@ func (h *DataFileHandler) ListFiles(c *gin.Context) {
 		perPage = 20
 	}
 
-	result, err := h.dataFileService.ListFiles(userID, page, perPage)
+	workspaceID, err := parseWorkspaceQuery(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	filter := &repository.DataFileFilter{WorkspaceID: workspaceID, Recursive: c.Query("recursive") == "true"}
+	if folder, ok := c.GetQuery("folder"); ok {
+		folder, err = service.NormalizeFolderFilter(folder)
+		if err != nil {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+		filter.Folder = &folder
+	}
+	if tag := c.Query("tag"); tag != "" {
+		filter.Tags, err = service.NormalizeTagFilter(strings.Split(tag, ","))
+		if err != nil {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	result, err := h.dataFileService.ListFiles(userID, filter, page, perPage)
 	if err != nil {
 		utils.InternalError(c, err.Error())
 		return
@@ -89,6 +285,14 @@ func (h *DataFileHandler) ListFiles(c *gin.Context) {
 }
 
 // GetFile 获取文件详情
+// @Summary 获取文件详情
+// @Tags data_file
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/data_files/{file_id} [get]
 func (h *DataFileHandler) GetFile(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 	fileID, _ := strconv.ParseUint(c.Param("file_id"), 10, 32)
@@ -99,31 +303,54 @@ func (h *DataFileHandler) GetFile(c *gin.Context) {
 		return
 	}
 
-	utils.SuccessResponse(c, dto.DataFileResponse{
-		ID:          file.ID,
-		Filename:    file.Filename,
-		FileSize:    file.FileSize,
-		ContentType: file.ContentType,
-		UserID:      file.UserID,
-		CreatedAt:   file.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt:   file.UpdatedAt.Format("2006-01-02 15:04:05"),
-	})
+	tags, err := h.dataFileService.FileTags(file.ID)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, h.dataFileService.NewFileResponse(file, tags))
 }
 
 // DeleteFile 删除文件
+// @Summary 删除文件
+// @Tags data_file
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/data_files/{file_id} [delete]
 func (h *DataFileHandler) DeleteFile(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 	fileID, _ := strconv.ParseUint(c.Param("file_id"), 10, 32)
 
+	// 删除前记录文件元信息作为审计快照
+	before, _ := h.dataFileService.ListFileInfos(userID, []uint{uint(fileID)})
+
 	if err := h.dataFileService.DeleteFile(uint(fileID), userID); err != nil {
 		utils.InternalError(c, err.Error())
 		return
 	}
 
+	if len(before) > 0 {
+		h.auditLogService.Record(newAuditLog(c, models.AuditActionFileDelete, models.ExportResourceDataFile, fileID, before[0], nil))
+	}
+
 	utils.SuccessWithMessage(c, "文件已删除", gin.H{"success": true})
 }
 
 // BatchDeleteFiles 批量删除文件
+// @Summary 批量删除文件
+// @Tags data_file
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.BatchDeleteRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/data_files/batch_delete [post]
 func (h *DataFileHandler) BatchDeleteFiles(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 
@@ -133,25 +360,225 @@ func (h *DataFileHandler) BatchDeleteFiles(c *gin.Context) {
 		return
 	}
 
+	before, _ := h.dataFileService.ListFileInfos(userID, req.IDs)
+
 	if err := h.dataFileService.BatchDeleteFiles(userID, req.IDs); err != nil {
 		utils.InternalError(c, err.Error())
 		return
 	}
 
+	for _, file := range before {
+		h.auditLogService.Record(newAuditLog(c, models.AuditActionFileDelete, models.ExportResourceDataFile, file.ID, file, nil))
+	}
+
 	utils.SuccessWithMessage(c, "批量删除成功", gin.H{"success": true})
 }
 
+// UpdateFile 重命名文件、修改描述和标签
+// @Summary 修改文件信息
+// @Description 文件名在文件所有者的文件中必须唯一且不能修改扩展名，同名时返回 409；tags 会替换文件的全部标签
+// @Tags data_file
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Param request body dto.UpdateDataFileRequest true "文件名、描述和标签"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/data_files/{file_id} [patch]
+func (h *DataFileHandler) UpdateFile(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	fileID, _ := strconv.ParseUint(c.Param("file_id"), 10, 32)
+
+	var req dto.UpdateDataFileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	before, _ := h.dataFileService.ListFileInfos(userID, []uint{uint(fileID)})
+
+	result, err := h.dataFileService.UpdateFile(uint(fileID), userID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrFilenameExists) {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	if len(before) > 0 {
+		h.auditLogService.Record(newAuditLog(c, models.AuditActionFileUpdate, models.ExportResourceDataFile, result.ID, before[0], result))
+	}
+
+	utils.SuccessResponse(c, result)
+}
+
+// UpdateFileMetadata 修改文件的文件夹和标签
+// @Summary 修改文件的文件夹和标签
+// @Tags data_file
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Param request body dto.UpdateFileMetadataRequest true "文件夹和标签"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/data_files/{file_id}/metadata [put]
+func (h *DataFileHandler) UpdateFileMetadata(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	fileID, _ := strconv.ParseUint(c.Param("file_id"), 10, 32)
+
+	var req dto.UpdateFileMetadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.dataFileService.UpdateFileMetadata(uint(fileID), userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
+
+// BatchUpdateFileMetadata 批量移动文件夹或添加、移除标签
+// @Summary 批量修改文件的文件夹和标签
+// @Tags data_file
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.BatchUpdateFileMetadataRequest true "文件ID列表及修改内容"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/data_files/batch_metadata [post]
+func (h *DataFileHandler) BatchUpdateFileMetadata(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.BatchUpdateFileMetadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.dataFileService.BatchUpdateFileMetadata(userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
+
+// ListFileTags 获取文件标签列表
+// @Summary 获取文件标签列表
+// @Description 返回当前用户可查看的文件上使用的标签及各标签的文件数
+// @Tags data_file
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/data_files/tags [get]
+func (h *DataFileHandler) ListFileTags(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	tags, err := h.dataFileService.ListFileTags(userID)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, tags)
+}
+
+// ListFolders 获取文件夹列表
+// @Summary 获取文件夹列表
+// @Description 返回当前用户可查看的文件所在的文件夹及各文件夹中的文件数（不含子文件夹）
+// @Tags data_file
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id query string false "工作区ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/data_files/folders [get]
+func (h *DataFileHandler) ListFolders(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	workspaceID, err := parseWorkspaceQuery(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	folders, err := h.dataFileService.ListFolders(userID, workspaceID)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, folders)
+}
+
 // DownloadFile 下载文件
+// @Summary 下载文件
+// @Tags data_file
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Param format query string false "导出格式"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/data_files/{file_id}/download [get]
 func (h *DataFileHandler) DownloadFile(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 	fileID, _ := strconv.ParseUint(c.Param("file_id"), 10, 32)
 
+	// 指定 Excel 或训练框架格式时转换后下载
+	if format := c.Query("format"); format != "" && format != "raw" {
+		var content []byte
+		var filename string
+		var rowCount int
+		var err error
+		switch {
+		case format == utils.TableFormatXLSX:
+			content, filename, rowCount, err = h.dataFileService.DownloadFileAsXLSX(uint(fileID), userID)
+		case utils.IsTrainerFormat(format):
+			content, filename, rowCount, err = h.dataFileService.DownloadFileAsTrainerFormat(uint(fileID), userID, format)
+		default:
+			utils.BadRequest(c, "不支持的导出格式: "+format)
+			return
+		}
+		if err != nil {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+
+		audit := newExportAudit(c, models.ExportResourceDataFile, strconv.FormatUint(fileID, 10), format, rowCount)
+		audit.OwnerID = &userID
+		h.auditService.Record(audit)
+
+		encodedFilename := url.QueryEscape(filename)
+		c.Header("Content-Disposition", "attachment; filename=\""+filename+"\"; filename*=UTF-8''"+encodedFilename)
+		c.Data(200, "application/octet-stream", content)
+		return
+	}
+
 	file, err := h.dataFileService.GetFile(uint(fileID), userID)
 	if err != nil {
 		utils.NotFound(c, "文件不存在")
 		return
 	}
 
+	audit := newExportAudit(c, models.ExportResourceDataFile, strconv.FormatUint(uint64(file.ID), 10), "raw", utils.CountJSONLLines(file.FileContent))
+	audit.OwnerID = &file.UserID
+	h.auditService.Record(audit)
+
 	// URL 编码文件名以支持中文和特殊字符（使用 QueryEscape，类似 Python 的 quote）
 	encodedFilename := url.QueryEscape(file.Filename)
 
@@ -162,16 +589,28 @@ func (h *DataFileHandler) DownloadFile(c *gin.Context) {
 }
 
 // DownloadFileAsCSV 下载文件为CSV格式
+// @Summary 下载文件为CSV格式
+// @Tags data_file
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Success 200 {file} file
+// @Failure 500 {object} utils.Response
+// @Router /api/data_files/{file_id}/download_csv [get]
 func (h *DataFileHandler) DownloadFileAsCSV(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 	fileID, _ := strconv.ParseUint(c.Param("file_id"), 10, 32)
 
-	content, filename, err := h.dataFileService.DownloadFileAsCSV(uint(fileID), userID)
+	content, filename, rowCount, err := h.dataFileService.DownloadFileAsCSV(uint(fileID), userID)
 	if err != nil {
 		utils.InternalError(c, err.Error())
 		return
 	}
 
+	audit := newExportAudit(c, models.ExportResourceDataFile, strconv.FormatUint(fileID, 10), "csv", rowCount)
+	audit.OwnerID = &userID
+	h.auditService.Record(audit)
+
 	// URL 编码文件名以支持中文和特殊字符
 	encodedFilename := url.QueryEscape(filename)
 
@@ -181,11 +620,22 @@ func (h *DataFileHandler) DownloadFileAsCSV(c *gin.Context) {
 }
 
 // GetFileContent 获取文件内容
+// @Summary 获取文件内容
+// @Tags data_file
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Param q query string false "搜索关键词"
+// @Param page query string false "页码"
+// @Param per_page query string false "每页条数"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/data_files/{file_id}/content [get]
 func (h *DataFileHandler) GetFileContent(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 	fileID, _ := strconv.ParseUint(c.Param("file_id"), 10, 32)
 
-	content, err := h.dataFileService.GetFileContent(uint(fileID), userID)
+	content, err := h.dataFileService.GetFileContent(uint(fileID), userID, parseFileContentQuery(c))
 	if err != nil {
 		utils.InternalError(c, err.Error())
 		return
@@ -194,28 +644,135 @@ func (h *DataFileHandler) GetFileContent(c *gin.Context) {
 	utils.SuccessResponse(c, content)
 }
 
-// GetTaskTypes 获取支持的任务类型列表
-func (h *DataFileHandler) GetTaskTypes(c *gin.Context) {
-	// 返回支持的任务类型（从 Python 版本迁移）
-	taskTypes := []string{
-		"entity_extraction",  // 实体提取
-		"general",           // 通用
-		"question_rewrite",  // 问句改写
-		"calculation",       // 计算
+// SampleFile 抽样预览文件内容
+// @Summary 抽样预览文件内容
+// @Description 启动任务前快速检查数据：head 取前 n 行，random 均匀随机抽取，stratified 按 meta（或 meta 中的 field 字段）分层、各层按行数比例抽取；只解码抽中的行
+// @Tags data_file
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Param n query integer false "抽样条数（默认 20，最多 200）"
+// @Param strategy query string false "抽样策略: head（默认）、random、stratified"
+// @Param seed query integer false "随机种子（random、stratified 使用，相同种子抽到相同的行）"
+// @Param field query string false "分层依据的 meta 字段（stratified 使用，默认按整个 meta 分层）"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/data_files/{file_id}/sample [get]
+func (h *DataFileHandler) SampleFile(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	fileID, _ := strconv.ParseUint(c.Param("file_id"), 10, 32)
+
+	query, err := parseFileSampleQuery(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
 	}
 
-	utils.SuccessResponse(c, gin.H{
-		"success": true,
-		"types":    taskTypes,
-	})
+	result, err := h.dataFileService.SampleFile(uint(fileID), userID, query)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
+
+// maxSampleSize 单次抽样的最大条数
+const maxSampleSize = 200
+
+// parseFileSampleQuery 解析文件抽样查询参数
+func parseFileSampleQuery(c *gin.Context) (dto.FileSampleQuery, error) {
+	query := dto.FileSampleQuery{
+		N:        20,
+		Strategy: c.DefaultQuery("strategy", utils.SampleStrategyHead),
+		Field:    c.Query("field"),
+	}
+
+	if raw := c.Query("n"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxSampleSize {
+			return query, fmt.Errorf("n 必须是 1 到 %d 之间的整数", maxSampleSize)
+		}
+		query.N = n
+	}
+
+	switch query.Strategy {
+	case utils.SampleStrategyHead, utils.SampleStrategyRandom, utils.SampleStrategyStratified:
+	default:
+		return query, fmt.Errorf("不支持的抽样策略: %s", query.Strategy)
+	}
+
+	if raw := c.Query("seed"); raw != "" {
+		seed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return query, fmt.Errorf("seed 必须是整数")
+		}
+		query.Seed = &seed
+	}
+	return query, nil
+}
+
+// ListFileTasks 获取使用该文件作为输入的任务列表
+// @Summary 获取使用该文件作为输入的任务列表
+// @Tags data_file
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/data_files/{file_id}/tasks [get]
+func (h *DataFileHandler) ListFileTasks(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	fileID, _ := strconv.ParseUint(c.Param("file_id"), 10, 32)
+
+	result, err := h.dataFileService.ListFileTasks(uint(fileID), userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
+
+// parseFileContentQuery 解析文件内容分页参数
+// 未传 page/per_page 时返回全部内容（兼容旧版前端）
+func parseFileContentQuery(c *gin.Context) dto.FileContentQuery {
+	query := dto.FileContentQuery{
+		Query: c.Query("q"),
+	}
+
+	if c.Query("page") == "" && c.Query("per_page") == "" {
+		return query
+	}
+
+	query.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	query.PerPage, _ = strconv.Atoi(c.DefaultQuery("per_page", "100"))
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PerPage < 1 || query.PerPage > 1000 {
+		query.PerPage = 100
+	}
+	return query
 }
 
 // GetFileContentEditable 获取文件内容（带索引，用于编辑）
+// @Summary 获取文件内容（带索引，用于编辑）
+// @Tags data_file
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Param q query string false "搜索关键词"
+// @Param page query string false "页码"
+// @Param per_page query string false "每页条数"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/data_files/{file_id}/content/editable [get]
 func (h *DataFileHandler) GetFileContentEditable(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 	fileID, _ := strconv.ParseUint(c.Param("file_id"), 10, 32)
 
-	content, err := h.dataFileService.GetFileContentEditable(uint(fileID), userID)
+	content, err := h.dataFileService.GetFileContentEditable(uint(fileID), userID, parseFileContentQuery(c))
 	if err != nil {
 		utils.InternalError(c, err.Error())
 		return
@@ -225,6 +782,18 @@ func (h *DataFileHandler) GetFileContentEditable(c *gin.Context) {
 }
 
 // UpdateFileContent 更新文件内容
+// @Summary 更新文件内容
+// @Tags data_file
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Param item_index path integer true "数据行序号"
+// @Param request body object true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/data_files/{file_id}/content/{item_index} [put]
 func (h *DataFileHandler) UpdateFileContent(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 	fileID, _ := strconv.ParseUint(c.Param("file_id"), 10, 32)
@@ -245,6 +814,17 @@ func (h *DataFileHandler) UpdateFileContent(c *gin.Context) {
 }
 
 // AddFileContent 添加文件内容
+// @Summary 添加文件内容
+// @Tags data_file
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Param request body dto.AddFileContentRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/data_files/{file_id}/content [post]
 func (h *DataFileHandler) AddFileContent(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 	fileID, _ := strconv.ParseUint(c.Param("file_id"), 10, 32)
@@ -264,6 +844,17 @@ func (h *DataFileHandler) AddFileContent(c *gin.Context) {
 }
 
 // BatchDeleteContent 批量删除文件内容
+// @Summary 批量删除文件内容
+// @Tags data_file
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Param request body object true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/data_files/{file_id}/content/batch [delete]
 func (h *DataFileHandler) BatchDeleteContent(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 	fileID, _ := strconv.ParseUint(c.Param("file_id"), 10, 32)
@@ -283,13 +874,24 @@ func (h *DataFileHandler) BatchDeleteContent(c *gin.Context) {
 	}
 
 	utils.SuccessResponse(c, gin.H{
-		"success": true,
-		"deleted_count": deletedCount,
+		"success":         true,
+		"deleted_count":   deletedCount,
 		"remaining_count": 0, // 将在后续更新
 	})
 }
 
-// BatchDownloadFiles 批量下载文件
+// BatchDownloadFiles 批量下载文件，以 ZIP 流式写入响应
+// 每个文件可以单独指定导出格式；转换失败的文件记录在 ZIP 内的 errors.txt 中
+// @Summary 批量下载文件，以 ZIP 流式写入响应
+// @Tags data_file
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.BatchDownloadRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/data_files/batch_download [post]
 func (h *DataFileHandler) BatchDownloadFiles(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 
@@ -299,21 +901,163 @@ func (h *DataFileHandler) BatchDownloadFiles(c *gin.Context) {
 		return
 	}
 
-	// 简化实现:返回文件列表
-	var files []gin.H
+	// 开始写入 ZIP 后无法再返回错误响应，先校验格式和文件权限
+	formats := make(map[uint]string, len(req.IDs))
+	for _, id := range req.IDs {
+		format := req.Format
+		if f, ok := req.Formats[id]; ok {
+			format = f
+		}
+		if !service.IsDataFileExportFormat(format) {
+			utils.BadRequest(c, "不支持的导出格式: "+format)
+			return
+		}
+		formats[id] = format
+	}
+	if err := h.dataFileService.CheckFilesOwned(userID, req.IDs); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	zipFilename := "data_files_" + dto.FilenameTimestamp(time.Now(), middleware.GetLocation(c)) + ".zip"
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", "attachment; filename=\""+zipFilename+"\"")
+	c.Status(http.StatusOK)
+
+	zipWriter := zip.NewWriter(c.Writer)
+	usedNames := make(map[string]int, len(req.IDs))
+	var failures []string
+
 	for _, id := range req.IDs {
-		file, err := h.dataFileService.GetFile(id, userID)
+		format := formats[id]
+		content, filename, rowCount, err := h.dataFileService.ExportFile(id, userID, format)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("文件 %d: %v", id, err))
+			continue
+		}
+
+		writer, err := zipWriter.Create(uniqueZipEntryName(filename, usedNames))
 		if err == nil {
-			files = append(files, gin.H{
-				"id":           file.ID,
-				"filename":     file.Filename,
-				"display_path": h.dataFileService.GetFileDisplayPath(file.ID, file.Filename),
-			})
+			_, err = writer.Write(content)
 		}
+		if err != nil {
+			// 客户端断开或写入失败，后续文件无法继续写入
+			log.Printf("[DataFile] 批量下载写入ZIP失败: %v", err)
+			return
+		}
+		c.Writer.Flush()
+
+		if format == "" {
+			format = "raw"
+		}
+		audit := newExportAudit(c, models.ExportResourceDataFile, strconv.FormatUint(uint64(id), 10), format, rowCount)
+		audit.OwnerID = &userID
+		h.auditService.Record(audit)
 	}
 
-	utils.SuccessResponse(c, gin.H{
-		"files": files,
-		"message": "请使用单独的下载接口下载每个文件",
-	})
+	if len(failures) > 0 {
+		if writer, err := zipWriter.Create(uniqueZipEntryName("errors.txt", usedNames)); err == nil {
+			io.WriteString(writer, strings.Join(failures, "\n")+"\n")
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		log.Printf("[DataFile] 批量下载关闭ZIP失败: %v", err)
+	}
+}
+
+// uniqueZipEntryName 为重名文件追加序号，避免 ZIP 内条目冲突
+func uniqueZipEntryName(name string, used map[string]int) string {
+	count := used[name]
+	used[name] = count + 1
+	if count == 0 {
+		return name
+	}
+	ext := filepath.Ext(name)
+	return fmt.Sprintf("%s(%d)%s", strings.TrimSuffix(name, ext), count, ext)
+}
+
+// ListVersions 获取文件的版本历史
+// @Summary 获取文件的版本历史
+// @Tags data_file
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/data_files/{file_id}/versions [get]
+func (h *DataFileHandler) ListVersions(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	fileID, _ := strconv.ParseUint(c.Param("file_id"), 10, 32)
+
+	versions, err := h.versionService.ListVersions(uint(fileID), userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, versions)
+}
+
+// DownloadVersion 下载文件的指定版本
+// @Summary 下载文件的指定版本
+// @Tags data_file
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Param version path integer true "版本号"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/data_files/{file_id}/versions/{version}/download [get]
+func (h *DataFileHandler) DownloadVersion(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	fileID, _ := strconv.ParseUint(c.Param("file_id"), 10, 32)
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		utils.BadRequest(c, "无效的版本号")
+		return
+	}
+
+	content, filename, err := h.versionService.GetVersionContent(uint(fileID), userID, version)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	audit := newExportAudit(c, models.ExportResourceDataFile, strconv.FormatUint(fileID, 10), "raw", utils.CountJSONLLines(content))
+	audit.OwnerID = &userID
+	h.auditService.Record(audit)
+
+	encodedFilename := url.QueryEscape(filename)
+	c.Header("Content-Disposition", "attachment; filename=\""+filename+"\"; filename*=UTF-8''"+encodedFilename)
+	c.Data(http.StatusOK, "application/x-jsonlines", content)
+}
+
+// RestoreVersion 将文件恢复到指定版本
+// @Summary 将文件恢复到指定版本
+// @Tags data_file
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Param version path integer true "版本号"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/data_files/{file_id}/versions/{version}/restore [post]
+func (h *DataFileHandler) RestoreVersion(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	fileID, _ := strconv.ParseUint(c.Param("file_id"), 10, 32)
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		utils.BadRequest(c, "无效的版本号")
+		return
+	}
+
+	restored, err := h.versionService.Restore(uint(fileID), userID, version)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "恢复成功", restored)
 }
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DataImportHandler 数据文件导入处理器
+type DataImportHandler struct {
+	importService   *service.DataImportService
+	dataFileService *service.DataFileService
+}
+
+// NewDataImportHandler 创建数据文件导入处理器
+func NewDataImportHandler(importService *service.DataImportService, dataFileService *service.DataFileService) *DataImportHandler {
+	return &DataImportHandler{
+		importService:   importService,
+		dataFileService: dataFileService,
+	}
+}
+
+// ImportFile 从远程 URL 或 Hugging Face 数据集导入数据文件
+// @Summary 从远程 URL 或 Hugging Face 数据集导入数据文件
+// @Tags data_import
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ImportDataFileRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Failure 503 {object} utils.Response
+// @Router /api/data_files/import [post]
+func (h *DataImportHandler) ImportFile(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.ImportDataFileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	dataFile, report, result, err := h.importService.Import(userID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrValidationRejected) {
+			respondValidationRejected(c, err, report)
+			return
+		}
+		if errors.Is(err, service.ErrUploadInfected) {
+			utils.ErrorResponse(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrJobQueueFull) || errors.Is(err, service.ErrVirusScanUnavailable) {
+			utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "文件导入成功", gin.H{
+		"id":           dataFile.ID,
+		"filename":     dataFile.Filename,
+		"display_path": h.dataFileService.GetFileDisplayPath(dataFile.ID, dataFile.Filename),
+		"file_size":    dataFile.FileSize,
+		"validation":   report,
+		"import":       result,
+	})
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"strconv"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmbeddingHandler 向量接口代理与语义检索处理器
+type EmbeddingHandler struct {
+	embeddingService *service.EmbeddingService
+	modelService     *service.ModelService
+}
+
+// NewEmbeddingHandler 创建向量接口代理与语义检索处理器
+func NewEmbeddingHandler(embeddingService *service.EmbeddingService, modelService *service.ModelService) *EmbeddingHandler {
+	return &EmbeddingHandler{
+		embeddingService: embeddingService,
+		modelService:     modelService,
+	}
+}
+
+// Embeddings 向量接口代理（内部接口），向量与输入顺序一一对应
+// @Summary 向量接口代理（内部接口），向量与输入顺序一一对应
+// @Tags embedding
+// @Accept json
+// @Produce json
+// @Security InternalAPIKey
+// @Param request body dto.EmbeddingProxyRequest true "请求参数"
+// @Success 200 {object} dto.EmbeddingProxyResponse
+// @Failure 400 {object} utils.Response
+// @Router /api/embeddings [post]
+func (h *EmbeddingHandler) Embeddings(c *gin.Context) {
+	var req dto.EmbeddingProxyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.modelService.Embed(c.Request.Context(), &req)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(200, resp)
+}
+
+// BuildFileIndex 为数据文件建立向量索引（后台执行）
+// @Summary 为数据文件建立向量索引（后台执行）
+// @Tags embedding
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Param request body dto.BuildEmbeddingIndexRequest false "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/data_files/{file_id}/embeddings [post]
+func (h *EmbeddingHandler) BuildFileIndex(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	fileID, err := strconv.ParseUint(c.Param("file_id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的文件ID")
+		return
+	}
+
+	var req dto.BuildEmbeddingIndexRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	progress, err := h.embeddingService.StartFileIndexForUser(uint(fileID), userID, req.ModelID)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "向量索引已开始", progress)
+}
+
+// GetFileIndex 获取数据文件的向量索引进度
+// @Summary 获取数据文件的向量索引进度
+// @Tags embedding
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/data_files/{file_id}/embeddings [get]
+func (h *EmbeddingHandler) GetFileIndex(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	fileID, err := strconv.ParseUint(c.Param("file_id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的文件ID")
+		return
+	}
+
+	progress, err := h.embeddingService.GetFileIndexForUser(uint(fileID), userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, progress)
+}
+
+// BuildTaskIndex 为任务的生成数据建立向量索引（后台执行）
+// @Summary 为任务的生成数据建立向量索引（后台执行）
+// @Tags embedding
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Param request body dto.BuildEmbeddingIndexRequest false "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/tasks/{task_id}/embeddings [post]
+func (h *EmbeddingHandler) BuildTaskIndex(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.BuildEmbeddingIndexRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	progress, err := h.embeddingService.StartTaskIndexForUser(c.Param("task_id"), userID, req.ModelID)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "向量索引已开始", progress)
+}
+
+// GetTaskIndex 获取任务生成数据的向量索引进度
+// @Summary 获取任务生成数据的向量索引进度
+// @Tags embedding
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{task_id}/embeddings [get]
+func (h *EmbeddingHandler) GetTaskIndex(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	progress, err := h.embeddingService.GetTaskIndexForUser(c.Param("task_id"), userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, progress)
+}
+
+// SemanticSearch 语义检索：在已建立向量索引的数据中查找与查询内容最相近的条目
+// 参数：q 查询内容，model_id 向量模型（默认 model_services.embedding_model），file_id 或 task_id 限定范围，limit 返回条数
+// @Summary 语义检索：在已建立向量索引的数据中查找与查询内容最相近的条目
+// @Tags embedding
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "查询内容"
+// @Param model_id query integer false "向量模型ID"
+// @Param file_id query integer false "限定数据文件"
+// @Param task_id query string false "限定任务的生成数据"
+// @Param limit query integer false "返回条数上限"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/semantic_search [get]
+func (h *EmbeddingHandler) SemanticSearch(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(service.SemanticSearchDefaultLimit)))
+	if limit < 1 || limit > service.SemanticSearchMaxLimit {
+		limit = service.SemanticSearchDefaultLimit
+	}
+	modelID, _ := strconv.ParseUint(c.Query("model_id"), 10, 32)
+	fileID, _ := strconv.ParseUint(c.Query("file_id"), 10, 32)
+
+	result, err := h.embeddingService.SearchForUser(userID, c.Query("q"), uint(modelID), uint(fileID), c.Query("task_id"), limit)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorReportHandler 运维错误汇总处理器
+type ErrorReportHandler struct {
+	errorReportService *service.ErrorReportService
+}
+
+// NewErrorReportHandler 创建运维错误汇总处理器
+func NewErrorReportHandler(errorReportService *service.ErrorReportService) *ErrorReportHandler {
+	return &ErrorReportHandler{errorReportService: errorReportService}
+}
+
+// ListErrors 获取最近的错误汇总（hours 为统计时间范围，默认24小时，最多7天）
+// @Summary 获取最近的错误汇总（hours 为统计时间范围，默认24小时，最多7天）
+// @Tags error_report
+// @Produce json
+// @Security BearerAuth
+// @Param hours query string false "统计最近的小时数（默认 24）"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/errors [get]
+func (h *ErrorReportHandler) ListErrors(c *gin.Context) {
+	hours, _ := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	if hours < 1 || hours > 168 {
+		hours = 24
+	}
+
+	result, err := h.errorReportService.Report(time.Now().Add(-time.Duration(hours) * time.Hour))
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gen-go/internal/middleware"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportAuditHandler 导出审计处理器（管理员报表）
+type ExportAuditHandler struct {
+	auditService *service.ExportAuditService
+}
+
+// NewExportAuditHandler 创建导出审计处理器
+func NewExportAuditHandler(auditService *service.ExportAuditService) *ExportAuditHandler {
+	return &ExportAuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListExports 分页查询导出审计记录
+// 支持 user_id、resource_type、resource_id、start_date、end_date（YYYY-MM-DD）过滤
+// @Summary 分页查询导出审计记录
+// @Tags export_audit
+// @Produce json
+// @Security BearerAuth
+// @Param page query string false "页码（默认 1）"
+// @Param per_page query string false "每页条数（默认 20）"
+// @Param resource_type query string false "资源类型"
+// @Param resource_id query string false "资源ID"
+// @Param user_id query string false "用户ID"
+// @Param start_date query string false "开始日期（YYYY-MM-DD）"
+// @Param end_date query string false "结束日期（YYYY-MM-DD）"
+// @Success 200 {object} utils.PaginationResponse
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/exports [get]
+func (h *ExportAuditHandler) ListExports(c *gin.Context) {
+	filter, err := parseExportAuditFilter(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	result, err := h.auditService.ListAudits(filter, page, perPage)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.PaginatedResponse(c, result.Items, result.Total, result.Page, result.PerPage)
+}
+
+// ExportSummary 按用户汇总导出次数和行数
+// @Summary 按用户汇总导出次数和行数
+// @Tags export_audit
+// @Produce json
+// @Security BearerAuth
+// @Param resource_type query string false "资源类型"
+// @Param resource_id query string false "资源ID"
+// @Param user_id query string false "用户ID"
+// @Param start_date query string false "开始日期（YYYY-MM-DD）"
+// @Param end_date query string false "结束日期（YYYY-MM-DD）"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/exports/summary [get]
+func (h *ExportAuditHandler) ExportSummary(c *gin.Context) {
+	filter, err := parseExportAuditFilter(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	summaries, err := h.auditService.SummarizeByUser(filter)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, summaries)
+}
+
+// parseExportAuditFilter 解析审计查询条件
+func parseExportAuditFilter(c *gin.Context) (repository.ExportAuditFilter, error) {
+	filter := repository.ExportAuditFilter{
+		ResourceType: c.Query("resource_type"),
+		ResourceID:   c.Query("resource_id"),
+	}
+
+	if value := c.Query("user_id"); value != "" {
+		userID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return filter, fmt.Errorf("无效的 user_id 参数")
+		}
+		filter.UserID = uint(userID)
+	}
+
+	// 日期按当前用户的展示时区解释
+	loc := middleware.GetLocation(c)
+
+	if value := c.Query("start_date"); value != "" {
+		start, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			return filter, fmt.Errorf("无效的 start_date 参数")
+		}
+		start = start.UTC()
+		filter.StartTime = &start
+	}
+
+	if value := c.Query("end_date"); value != "" {
+		end, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			return filter, fmt.Errorf("无效的 end_date 参数")
+		}
+		// 包含结束当天
+		end = end.AddDate(0, 0, 1).UTC()
+		filter.EndTime = &end
+	}
+
+	return filter, nil
+}
+
+// newExportAudit 根据请求上下文构建导出审计记录
+func newExportAudit(c *gin.Context, resourceType, resourceID, format string, rowCount int) *models.ExportAudit {
+	userID, _ := middleware.GetUserID(c)
+	username, _ := middleware.GetUsername(c)
+
+	filters := models.JSONMap{}
+	for key, values := range c.Request.URL.Query() {
+		filters[key] = strings.Join(values, ",")
+	}
+
+	return &models.ExportAudit{
+		UserID:       userID,
+		Username:     username,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Format:       format,
+		RowCount:     rowCount,
+		Filters:      filters,
+		Endpoint:     c.Request.Method + " " + c.FullPath(),
+		ClientIP:     c.ClientIP(),
+	}
+}
@@ -3,6 +3,10 @@ package handler
 import (
 	"archive/zip"
 	"bytes"
+	"encoding/json"
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/service"
 	"gen-go/internal/utils"
 	"net/http"
 	"path/filepath"
@@ -13,14 +17,30 @@ import (
 )
 
 type FileConversionHandler struct {
-	// 可以添加 service 依赖
+	conversionService *service.FileConversionService
 }
 
-func NewFileConversionHandler() *FileConversionHandler {
-	return &FileConversionHandler{}
+func NewFileConversionHandler(conversionService *service.FileConversionService) *FileConversionHandler {
+	return &FileConversionHandler{conversionService: conversionService}
 }
 
-// ConvertFilesDirect 直接上传文件并转换格式（CSV<->JSONL）
+// ConvertFilesDirect 直接上传文件并转换格式（CSV/JSON/TXT/Excel/Parquet -> JSONL，JSONL -> target_format）
+// @Summary 直接上传文件并转换格式（CSV/JSON/TXT/Excel/Parquet -> JSONL，JSONL -> target_format）
+// @Tags file_conversion
+// @Accept multipart/form-data
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param csv_delimiter formData string false "CSV 分隔符: auto（默认）、comma、tab、semicolon"
+// @Param csv_quote formData string false "CSV 引号处理: strict（默认）、lazy、none"
+// @Param csv_encoding formData string false "CSV 编码: auto（默认）、utf-8、gbk、utf-16le、utf-16be"
+// @Param csv_lenient formData boolean false "宽松模式：跳过无法解析的行，ZIP 中附带 <文件名>.csv_report.json"
+// @Param text_split formData string false "纯文本切分方式: paragraph（默认，按空行）、line、separator"
+// @Param text_separator formData string false "text_split=separator 时的分隔符（支持 \n、\t 转义）"
+// @Param text_meta formData string false "纯文本每条样本的 meta_description"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/convert_files [post]
 func (h *FileConversionHandler) ConvertFilesDirect(c *gin.Context) {
 	form, err := c.MultipartForm()
 	if err != nil {
@@ -34,6 +54,24 @@ func (h *FileConversionHandler) ConvertFilesDirect(c *gin.Context) {
 		return
 	}
 
+	// JSONL 文件的目标格式：csv（默认）、xlsx、parquet
+	targetFormat := c.DefaultPostForm("target_format", "csv")
+	if targetFormat != "csv" && targetFormat != "xlsx" && targetFormat != "parquet" {
+		utils.BadRequest(c, "不支持的目标格式: "+targetFormat)
+		return
+	}
+
+	csvOpts, err := parseCSVOptions(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+	textOpts, err := parseTextSplitOptions(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
 	// 创建ZIP缓冲区
 	zipBuffer := new(bytes.Buffer)
 	zipWriter := zip.NewWriter(zipBuffer)
@@ -75,41 +113,59 @@ func (h *FileConversionHandler) ConvertFilesDirect(c *gin.Context) {
 			}
 
 			var convertedContent []byte
+			var csvReport *utils.CSVParseReport
 			var newFilename string
 			var conversionType string
 
 			// 判断文件格式并转换
-			if strings.HasSuffix(filename, ".csv") {
-				// CSV -> JSONL
-				convertedContent, err = utils.ConvertCSVToJSONL(content)
-				if err != nil {
-					errors = append(errors, map[string]interface{}{
-						"index":    index,
-						"filename": filename,
-						"error":    err.Error(),
-					})
-					return
-				}
+			lowerName := strings.ToLower(filename)
+			switch {
+			case strings.HasSuffix(lowerName, ".csv"):
+				convertedContent, csvReport, err = utils.ConvertCSVToJSONLWithOptions(content, csvOpts)
 				newFilename = filename[:len(filename)-4] + ".jsonl"
 				conversionType = "csv_to_jsonl"
-			} else if strings.HasSuffix(filename, ".jsonl") {
-				// JSONL -> CSV
-				convertedContent, err = utils.ConvertJSONLToCSV(content)
-				if err != nil {
-					errors = append(errors, map[string]interface{}{
-						"index":    index,
-						"filename": filename,
-						"error":    err.Error(),
-					})
-					return
+			case strings.HasSuffix(lowerName, ".json"):
+				convertedContent, _, _, err = utils.ConvertJSONArrayToJSONL(content)
+				newFilename = filename[:len(filename)-5] + ".jsonl"
+				conversionType = "json_to_jsonl"
+			case strings.HasSuffix(lowerName, ".txt"):
+				convertedContent, _, err = utils.ConvertTextToJSONL(content, textOpts)
+				newFilename = filename[:len(filename)-4] + ".jsonl"
+				conversionType = "txt_to_jsonl"
+			case strings.HasSuffix(lowerName, ".xlsx"):
+				convertedContent, err = utils.ConvertXLSXToJSONL(content)
+				newFilename = filename[:len(filename)-5] + ".jsonl"
+				conversionType = "xlsx_to_jsonl"
+			case strings.HasSuffix(lowerName, ".parquet"):
+				convertedContent, err = utils.ConvertParquetToJSONL(content)
+				newFilename = filename[:len(filename)-8] + ".jsonl"
+				conversionType = "parquet_to_jsonl"
+			case strings.HasSuffix(lowerName, ".jsonl"):
+				// JSONL 按 target_format 转换，默认 CSV
+				base := filename[:len(filename)-6]
+				switch targetFormat {
+				case "xlsx":
+					convertedContent, err = utils.ConvertJSONLToXLSX(content)
+				case "parquet":
+					convertedContent, err = utils.ConvertJSONLToParquet(content)
+				default:
+					convertedContent, err = utils.ConvertJSONLToCSV(content)
 				}
-				newFilename = filename[:len(filename)-6] + ".csv"
-				conversionType = "jsonl_to_csv"
-			} else {
+				newFilename = base + "." + targetFormat
+				conversionType = "jsonl_to_" + targetFormat
+			default:
 				errors = append(errors, map[string]interface{}{
 					"index":    index,
 					"filename": filename,
-					"error":    "不支持的文件格式，仅支持.csv和.jsonl",
+					"error":    "不支持的文件格式，仅支持.csv、.json、.txt、.xlsx、.parquet和.jsonl",
+				})
+				return
+			}
+			if err != nil {
+				errors = append(errors, map[string]interface{}{
+					"index":    index,
+					"filename": filename,
+					"error":    err.Error(),
 				})
 				return
 			}
@@ -134,10 +190,19 @@ func (h *FileConversionHandler) ConvertFilesDirect(c *gin.Context) {
 				return
 			}
 
+			// 宽松模式跳过了行时附带解析报告
+			if csvReport != nil && csvReport.SkippedRows > 0 {
+				if reportJSON, err := json.MarshalIndent(csvReport, "", "  "); err == nil {
+					if writer, err := zipWriter.Create(filename + ".csv_report.json"); err == nil {
+						writer.Write(reportJSON)
+					}
+				}
+			}
+
 			convertedFiles = append(convertedFiles, map[string]string{
-				"original_filename":   filename,
-				"converted_filename":  newFilename,
-				"conversion_type":     conversionType,
+				"original_filename":  filename,
+				"converted_filename": newFilename,
+				"conversion_type":    conversionType,
 			})
 		}()
 	}
@@ -152,8 +217,8 @@ func (h *FileConversionHandler) ConvertFilesDirect(c *gin.Context) {
 	}
 
 	// 生成ZIP文件名
-	timestamp := time.Now().Format("20060102_150405")
-	zipFilename := filepath.Join("converted_files_"+timestamp+".zip")
+	timestamp := dto.FilenameTimestamp(time.Now(), middleware.GetLocation(c))
+	zipFilename := filepath.Join("converted_files_" + timestamp + ".zip")
 
 	// 设置响应头
 	c.Header("Content-Type", "application/zip")
@@ -161,18 +226,30 @@ func (h *FileConversionHandler) ConvertFilesDirect(c *gin.Context) {
 	c.Data(http.StatusOK, "application/zip", zipBuffer.Bytes())
 }
 
-// BatchConvertFiles 批量转换数据库中的文件
+// BatchConvertFiles 批量转换数据库中的文件（CSV<->JSONL），转换结果保存为新文件
+// @Summary 批量转换数据库中的文件（CSV<->JSONL），转换结果保存为新文件
+// @Tags file_conversion
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.BatchConvertRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/data_files/batch_convert [post]
 func (h *FileConversionHandler) BatchConvertFiles(c *gin.Context) {
-	var req struct {
-		FileIDs []uint `json:"file_ids" binding:"required"`
-	}
+	userID, _ := middleware.GetUserID(c)
 
+	var req dto.BatchConvertRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.BadRequest(c, "请提供要转换的文件ID列表")
 		return
 	}
 
-	// TODO: 实现从数据库读取文件并转换的逻辑
-	// 这个需要注入 repository/service 来获取文件内容
-	utils.InternalError(c, "批量转换功能开发中，请使用直接上传转换功能")
+	result, err := h.conversionService.BatchConvert(userID, req.FileIDs, req.TargetFormat)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, result.Message, result)
 }
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FileMappingHandler 字段映射向导处理器
+type FileMappingHandler struct {
+	mappingService  *service.FileMappingService
+	dataFileService *service.DataFileService
+}
+
+// NewFileMappingHandler 创建字段映射向导处理器
+func NewFileMappingHandler(mappingService *service.FileMappingService, dataFileService *service.DataFileService) *FileMappingHandler {
+	return &FileMappingHandler{
+		mappingService:  mappingService,
+		dataFileService: dataFileService,
+	}
+}
+
+// InspectFile 检测上传文件的列
+// @Summary 检测上传文件的列
+// @Description 读取 CSV/JSONL/JSON 数组/Excel/Parquet 文件的前 200 行，返回各列的值类型和示例、推荐的字段映射，以及前几行按映射转换后的预览；不保存文件
+// @Tags data_file
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "上传的文件"
+// @Param mapping formData string false "字段映射（JSON，结构同 dto.ImportColumnMapping），用于预览转换结果"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 413 {object} utils.Response
+// @Failure 415 {object} utils.Response
+// @Router /api/data_files/inspect [post]
+func (h *FileMappingHandler) InspectFile(c *gin.Context) {
+	filename, content, mapping, ok := readMappedUpload(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.mappingService.Inspect(filename, content, mapping)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
+
+// UploadMappedFile 按字段映射转换并上传文件
+// @Summary 按字段映射转换并上传文件
+// @Description 按 mapping 指定的列（meta、meta_fields、human/assistant 一问一答、turns 交替的多轮对话或 messages 对话数组）转换为 meta/turns 结构，保存为 .jsonl 数据文件；未指定映射时按常见字段名自动识别
+// @Tags data_file
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "上传的文件"
+// @Param mapping formData string false "字段映射（JSON，结构同 dto.ImportColumnMapping）"
+// @Param validation_mode formData string false "结构校验模式"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 413 {object} utils.Response
+// @Failure 415 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Failure 503 {object} utils.Response
+// @Router /api/data_files/upload/mapped [post]
+func (h *FileMappingHandler) UploadMappedFile(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	filename, content, mapping, ok := readMappedUpload(c)
+	if !ok {
+		return
+	}
+
+	dataFile, report, result, err := h.mappingService.Upload(userID, filename, content, mapping, c.PostForm("validation_mode"))
+	if err != nil {
+		if errors.Is(err, service.ErrValidationRejected) {
+			respondValidationRejected(c, err, report)
+			return
+		}
+		if errors.Is(err, service.ErrUploadInfected) {
+			utils.ErrorResponse(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrJobQueueFull) || errors.Is(err, service.ErrVirusScanUnavailable) {
+			utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "文件上传成功", gin.H{
+		"id":           dataFile.ID,
+		"filename":     dataFile.Filename,
+		"display_path": h.dataFileService.GetFileDisplayPath(dataFile.ID, dataFile.Filename),
+		"file_size":    dataFile.FileSize,
+		"validation":   report,
+		"conversion":   result,
+	})
+}
+
+// readMappedUpload 读取上传的文件（表单字段 file）和可选的字段映射（表单字段 mapping），失败时已写入错误响应
+func readMappedUpload(c *gin.Context) (string, []byte, *dto.ImportColumnMapping, bool) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		utils.BadRequest(c, "文件上传失败: "+err.Error())
+		return "", nil, nil, false
+	}
+
+	var mapping *dto.ImportColumnMapping
+	if raw := c.PostForm("mapping"); raw != "" {
+		mapping = &dto.ImportColumnMapping{}
+		if err := json.Unmarshal([]byte(raw), mapping); err != nil {
+			utils.BadRequest(c, "无效的字段映射: "+err.Error())
+			return "", nil, nil, false
+		}
+	}
+
+	src, err := header.Open()
+	if err != nil {
+		utils.InternalError(c, "打开文件失败: "+err.Error())
+		return "", nil, nil, false
+	}
+	defer src.Close()
+
+	content, err := io.ReadAll(src)
+	if err != nil {
+		utils.InternalError(c, "读取文件失败: "+err.Error())
+		return "", nil, nil, false
+	}
+	return header.Filename, content, mapping, true
+}
@@ -1,11 +1,16 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"gen-go/internal/dto"
 	"gen-go/internal/middleware"
+	"gen-go/internal/models"
 	"gen-go/internal/service"
 	"gen-go/internal/utils"
 
@@ -15,16 +20,52 @@ import (
 // GeneratedDataHandler 生成数据处理器
 type GeneratedDataHandler struct {
 	generatedDataService *service.GeneratedDataService
+	objectStorageService *service.ObjectStorageService
+	auditService         *service.ExportAuditService
+	settingsService      *service.UserSettingsService
 }
 
 // NewGeneratedDataHandler 创建生成数据处理器
-func NewGeneratedDataHandler(generatedDataService *service.GeneratedDataService) *GeneratedDataHandler {
+func NewGeneratedDataHandler(generatedDataService *service.GeneratedDataService, objectStorageService *service.ObjectStorageService, auditService *service.ExportAuditService, settingsService *service.UserSettingsService) *GeneratedDataHandler {
 	return &GeneratedDataHandler{
 		generatedDataService: generatedDataService,
+		objectStorageService: objectStorageService,
+		auditService:         auditService,
+		settingsService:      settingsService,
 	}
 }
 
+// exportFormat 请求未指定导出格式时使用当前用户个人设置中的导出格式（未设置时为 jsonl）
+func (h *GeneratedDataHandler) exportFormat(c *gin.Context, format string) string {
+	if format != "" {
+		return format
+	}
+	userID, _ := middleware.GetUserID(c)
+	return h.settingsService.ExportFormat(userID)
+}
+
 // ListData 获取生成数据列表
+// @Summary 获取生成数据列表
+// @Tags generated_data
+// @Produce json
+// @Security BearerAuth
+// @Param task_id query string false "任务ID"
+// @Param page query string false "页码（默认 1）"
+// @Param per_page query string false "每页条数（默认 20）"
+// @Param task_type query string false "任务类型"
+// @Param confirmed query string false "是否已确认"
+// @Param min_model_score query string false "模型评分下限"
+// @Param exclude_duplicates query string false "排除重复数据"
+// @Param include_flagged query string false "包含内容安全标记的数据"
+// @Param date_from query string false "开始日期（YYYY-MM-DD）"
+// @Param date_to query string false "结束日期（YYYY-MM-DD）"
+// @Param tags query string false "标签（逗号分隔）"
+// @Param quota query string false "标签导出配额（tag:数量，逗号分隔）"
+// @Success 200 {object} utils.PaginationResponse
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/generated_data [get]
 func (h *GeneratedDataHandler) ListData(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 	taskID := c.Query("task_id")
@@ -36,25 +77,82 @@ func (h *GeneratedDataHandler) ListData(c *gin.Context) {
 		return
 	}
 
-	result, err := h.generatedDataService.ListData(taskID, userID, page, perPage)
+	filter, err := parseDataFilter(c)
 	if err != nil {
-		utils.InternalError(c, err.Error())
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.generatedDataService.ListData(taskID, userID, filter, page, perPage)
+	if err != nil {
+		respondGeneratedDataError(c, err)
 		return
 	}
 
 	utils.PaginatedResponse(c, result.Items, result.Total, result.Page, result.PerPage)
 }
 
+// ListChanges 获取任务在游标之后的数据变更（增量同步）
+// @Summary 获取任务在游标之后的数据变更（增量同步）
+// @Tags generated_data
+// @Produce json
+// @Security BearerAuth
+// @Param task_id query string false "任务ID"
+// @Param limit query string false "返回条数上限"
+// @Param since query string false "增量游标（上次返回的 cursor）"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/generated_data/changes [get]
+func (h *GeneratedDataHandler) ListChanges(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	taskID := c.Query("task_id")
+	if taskID == "" {
+		utils.BadRequest(c, "缺少task_id参数")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(service.DataChangesDefaultLimit)))
+	if limit < 1 || limit > service.DataChangesMaxLimit {
+		limit = service.DataChangesDefaultLimit
+	}
+
+	result, err := h.generatedDataService.ListChanges(taskID, userID, c.Query("since"), limit)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskNotAccessible) {
+			utils.NotFound(c, err.Error())
+			return
+		}
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
+
 // BatchUpdate 批量更新数据
+// @Summary 批量更新数据
+// @Tags generated_data
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.BatchUpdateRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/generated_data/batch_update [post]
 func (h *GeneratedDataHandler) BatchUpdate(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
 	var req dto.BatchUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.BadRequest(c, err.Error())
 		return
 	}
 
-	if err := h.generatedDataService.BatchUpdate(req.Updates); err != nil {
-		utils.InternalError(c, err.Error())
+	if err := h.generatedDataService.BatchUpdate(userID, req.Updates); err != nil {
+		respondGeneratedDataError(c, err)
 		return
 	}
 
@@ -62,15 +160,28 @@ func (h *GeneratedDataHandler) BatchUpdate(c *gin.Context) {
 }
 
 // BatchConfirm 批量确认数据
+// @Summary 批量确认数据
+// @Tags generated_data
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.BatchConfirmRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/generated_data/batch_confirm [post]
 func (h *GeneratedDataHandler) BatchConfirm(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
 	var req dto.BatchConfirmRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.BadRequest(c, err.Error())
 		return
 	}
 
-	if err := h.generatedDataService.BatchConfirm(req.IDs); err != nil {
-		utils.InternalError(c, err.Error())
+	if err := h.generatedDataService.BatchConfirm(req.IDs, userID); err != nil {
+		respondGeneratedDataError(c, err)
 		return
 	}
 
@@ -78,36 +189,189 @@ func (h *GeneratedDataHandler) BatchConfirm(c *gin.Context) {
 }
 
 // ExportData 导出数据
+// @Summary 导出数据
+// @Tags generated_data
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param task_id query string false "任务ID"
+// @Param format query string false "导出格式（默认使用个人设置的导出格式，未设置时为 jsonl）"
+// @Param task_type query string false "任务类型"
+// @Param confirmed query string false "是否已确认"
+// @Param min_model_score query string false "模型评分下限"
+// @Param exclude_duplicates query string false "排除重复数据"
+// @Param include_flagged query string false "包含内容安全标记的数据"
+// @Param date_from query string false "开始日期（YYYY-MM-DD）"
+// @Param date_to query string false "结束日期（YYYY-MM-DD）"
+// @Param tags query string false "标签（逗号分隔）"
+// @Param quota query string false "标签导出配额（tag:数量，逗号分隔）"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/generated_data/export [get]
 func (h *GeneratedDataHandler) ExportData(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
 	taskID := c.Query("task_id")
-	format := c.DefaultQuery("format", "jsonl")
+	format := h.exportFormat(c, c.Query("format"))
 
 	if taskID == "" {
 		utils.BadRequest(c, "缺少task_id参数")
 		return
 	}
 
-	data, filename, err := h.generatedDataService.ExportData(taskID, format)
+	filter, err := parseDataFilter(c)
 	if err != nil {
-		utils.InternalError(c, err.Error())
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	data, filename, rowCount, err := h.generatedDataService.ExportData(taskID, userID, format, filter, middleware.GetLocation(c))
+	if err != nil {
+		respondGeneratedDataError(c, err)
 		return
 	}
 
+	h.auditService.Record(newExportAudit(c, models.ExportResourceGeneratedData, taskID, format, rowCount))
+
 	c.Header("Content-Disposition", "attachment; filename=\""+filename+"\"")
 	c.Data(200, "application/octet-stream", data)
 }
 
+// ExportToStorage 将任务数据导出后直接写入对象存储，返回签名下载链接（过滤参数与 ExportData 相同）
+// @Summary 将任务数据导出后直接写入对象存储，返回签名下载链接（过滤参数与 ExportData 相同）
+// @Tags generated_data
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_type query string false "任务类型"
+// @Param confirmed query string false "是否已确认"
+// @Param min_model_score query string false "模型评分下限"
+// @Param exclude_duplicates query string false "排除重复数据"
+// @Param include_flagged query string false "包含内容安全标记的数据"
+// @Param date_from query string false "开始日期（YYYY-MM-DD）"
+// @Param date_to query string false "结束日期（YYYY-MM-DD）"
+// @Param tags query string false "标签（逗号分隔）"
+// @Param quota query string false "标签导出配额（tag:数量，逗号分隔）"
+// @Param request body dto.ExportToStorageRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/generated_data/export_to_storage [post]
+func (h *GeneratedDataHandler) ExportToStorage(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.ExportToStorageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+	req.Format = h.exportFormat(c, req.Format)
+
+	filter, err := parseDataFilter(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	data, filename, rowCount, err := h.generatedDataService.ExportData(req.TaskID, userID, req.Format, filter, middleware.GetLocation(c))
+	if err != nil {
+		respondGeneratedDataError(c, err)
+		return
+	}
+
+	object, err := h.objectStorageService.Upload(filename, data, "application/octet-stream")
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+	object.RowCount = rowCount
+
+	h.auditService.Record(newExportAudit(c, models.ExportResourceGeneratedData, req.TaskID, req.Format, rowCount))
+
+	utils.SuccessWithMessage(c, "已导出到对象存储", object)
+}
+
+// ExportStratified 按目标比例分层抽样导出数据（ZIP：数据文件 + manifest.json）
+// @Summary 按目标比例分层抽样导出数据（ZIP：数据文件 + manifest.json）
+// @Tags generated_data
+// @Accept json
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param request body dto.StratifiedExportRequest true "请求参数"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/generated_data/export/stratified [post]
+func (h *GeneratedDataHandler) ExportStratified(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.StratifiedExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+	if len(req.Targets) == 0 && len(req.Caps) == 0 {
+		utils.BadRequest(c, "至少需要指定一个目标层或上限层")
+		return
+	}
+
+	data, filename, rowCount, err := h.generatedDataService.ExportStratified(&req, userID, middleware.GetLocation(c))
+	if err != nil {
+		if errors.Is(err, service.ErrTaskNotAccessible) {
+			utils.NotFound(c, err.Error())
+			return
+		}
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	h.auditService.Record(newExportAudit(c, models.ExportResourceGeneratedData, req.TaskID, "stratified", rowCount))
+
+	c.Header("Content-Disposition", "attachment; filename=\""+filename+"\"")
+	c.Data(200, "application/zip", data)
+}
+
 // DownloadTaskData 下载任务数据
+// @Summary 下载任务数据
+// @Tags generated_data
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Param format query string false "导出格式（默认使用个人设置的导出格式，未设置时为 jsonl）"
+// @Param task_type query string false "任务类型"
+// @Param confirmed query string false "是否已确认"
+// @Param min_model_score query string false "模型评分下限"
+// @Param exclude_duplicates query string false "排除重复数据"
+// @Param include_flagged query string false "包含内容安全标记的数据"
+// @Param date_from query string false "开始日期（YYYY-MM-DD）"
+// @Param date_to query string false "结束日期（YYYY-MM-DD）"
+// @Param tags query string false "标签（逗号分隔）"
+// @Param quota query string false "标签导出配额（tag:数量，逗号分隔）"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/generated_data/{task_id}/download [get]
 func (h *GeneratedDataHandler) DownloadTaskData(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
 	taskID := c.Param("task_id")
-	format := c.DefaultQuery("format", "jsonl")
+	format := h.exportFormat(c, c.Query("format"))
 
-	data, filename, err := h.generatedDataService.ExportData(taskID, format)
+	filter, err := parseDataFilter(c)
 	if err != nil {
-		utils.InternalError(c, err.Error())
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	data, filename, rowCount, err := h.generatedDataService.ExportData(taskID, userID, format, filter, middleware.GetLocation(c))
+	if err != nil {
+		respondGeneratedDataError(c, err)
 		return
 	}
 
+	h.auditService.Record(newExportAudit(c, models.ExportResourceGeneratedData, taskID, format, rowCount))
+
 	// URL 编码文件名以支持中文和特殊字符
 	encodedFilename := url.QueryEscape(filename)
 
@@ -117,12 +381,22 @@ func (h *GeneratedDataHandler) DownloadTaskData(c *gin.Context) {
 }
 
 // GetTaskInfo 获取任务数据信息
+// @Summary 获取任务数据信息
+// @Tags generated_data
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/generated_data/{task_id}/info [get]
 func (h *GeneratedDataHandler) GetTaskInfo(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
 	taskID := c.Param("task_id")
 
-	info, err := h.generatedDataService.GetTaskInfo(taskID)
+	info, err := h.generatedDataService.GetTaskInfo(taskID, userID)
 	if err != nil {
-		utils.InternalError(c, err.Error())
+		respondGeneratedDataError(c, err)
 		return
 	}
 
@@ -130,7 +404,20 @@ func (h *GeneratedDataHandler) GetTaskInfo(c *gin.Context) {
 }
 
 // UpdateData 更新单条数据
+// @Summary 更新单条数据
+// @Tags generated_data
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param data_id path integer true "数据ID"
+// @Param request body dto.UpdateGeneratedDataRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/generated_data/{data_id} [put]
 func (h *GeneratedDataHandler) UpdateData(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
 	dataID, _ := strconv.ParseUint(c.Param("data_id"), 10, 32)
 
 	var req dto.UpdateGeneratedDataRequest
@@ -140,8 +427,8 @@ func (h *GeneratedDataHandler) UpdateData(c *gin.Context) {
 	}
 
 	req.ID = uint(dataID)
-	if err := h.generatedDataService.BatchUpdate([]dto.UpdateGeneratedDataRequest{req}); err != nil {
-		utils.InternalError(c, err.Error())
+	if err := h.generatedDataService.BatchUpdate(userID, []dto.UpdateGeneratedDataRequest{req}); err != nil {
+		respondGeneratedDataError(c, err)
 		return
 	}
 
@@ -149,7 +436,19 @@ func (h *GeneratedDataHandler) UpdateData(c *gin.Context) {
 }
 
 // ConfirmData 确认单条数据（支持切换确认状态）
+// @Summary 确认单条数据（支持切换确认状态）
+// @Tags generated_data
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param data_id path integer true "数据ID"
+// @Param request body dto.ConfirmDataRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/generated_data/{data_id}/confirm [post]
 func (h *GeneratedDataHandler) ConfirmData(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
 	dataID, _ := strconv.ParseUint(c.Param("data_id"), 10, 32)
 
 	var req dto.ConfirmDataRequest
@@ -158,8 +457,8 @@ func (h *GeneratedDataHandler) ConfirmData(c *gin.Context) {
 		req.IsConfirmed = true
 	}
 
-	if err := h.generatedDataService.ConfirmData(uint(dataID), req.IsConfirmed); err != nil {
-		utils.InternalError(c, err.Error())
+	if err := h.generatedDataService.ConfirmData(uint(dataID), userID, req.IsConfirmed); err != nil {
+		respondGeneratedDataError(c, err)
 		return
 	}
 
@@ -171,26 +470,51 @@ func (h *GeneratedDataHandler) ConfirmData(c *gin.Context) {
 }
 
 // DeleteBatch 批量删除数据
+// @Summary 批量删除数据
+// @Tags generated_data
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.BatchDeleteGeneratedDataRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/generated_data/batch [delete]
 func (h *GeneratedDataHandler) DeleteBatch(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
 	var req dto.BatchDeleteGeneratedDataRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.BadRequest(c, err.Error())
 		return
 	}
 
-	deletedCount, err := h.generatedDataService.DeleteBatch(req.DataIDs)
+	deletedCount, err := h.generatedDataService.DeleteBatch(req.DataIDs, userID)
 	if err != nil {
-		utils.InternalError(c, err.Error())
+		respondGeneratedDataError(c, err)
 		return
 	}
 
 	utils.SuccessResponse(c, gin.H{
-		"success": true,
+		"success":       true,
 		"deleted_count": deletedCount,
 	})
 }
 
 // AddData 添加单条数据
+// @Summary 添加单条数据
+// @Tags generated_data
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Param request body dto.AddGeneratedDataRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/generated_data/add/{task_id} [post]
 func (h *GeneratedDataHandler) AddData(c *gin.Context) {
 	taskID := c.Param("task_id")
 	userID, _ := middleware.GetUserID(c)
@@ -203,9 +527,109 @@ func (h *GeneratedDataHandler) AddData(c *gin.Context) {
 
 	dataID, err := h.generatedDataService.AddData(taskID, userID, req.Content)
 	if err != nil {
-		utils.InternalError(c, err.Error())
+		respondGeneratedDataError(c, err)
 		return
 	}
 
 	utils.SuccessWithMessage(c, "添加成功", gin.H{"data_id": dataID})
 }
+
+// respondGeneratedDataError 任务不存在或无权访问时返回 404，其余错误返回 500
+func respondGeneratedDataError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrTaskNotAccessible) {
+		utils.NotFound(c, err.Error())
+		return
+	}
+	utils.InternalError(c, err.Error())
+}
+
+// parseDataFilter 解析过滤参数
+// tags=topic:math,difficulty:hard 只返回同时带有这些标签的数据；
+// quota=difficulty:easy=100,difficulty:hard=100 导出时按标签配额截取数据；
+// confirmed、min_model_score、task_type、date_from/date_to（日期按用户时区，date_to 含当天）、exclude_duplicates
+func parseDataFilter(c *gin.Context) (*dto.DataFilter, error) {
+	filter := &dto.DataFilter{
+		TaskType: strings.TrimSpace(c.Query("task_type")),
+	}
+
+	if value := c.Query("confirmed"); value != "" {
+		confirmed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("无效的 confirmed 参数: %s", value)
+		}
+		filter.Confirmed = &confirmed
+	}
+	if value := c.Query("min_model_score"); value != "" {
+		score, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的 min_model_score 参数: %s", value)
+		}
+		filter.MinModelScore = &score
+	}
+	if value := c.Query("exclude_duplicates"); value != "" {
+		exclude, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("无效的 exclude_duplicates 参数: %s", value)
+		}
+		filter.ExcludeDuplicates = exclude
+	}
+	if value := c.Query("include_flagged"); value != "" {
+		include, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("无效的 include_flagged 参数: %s", value)
+		}
+		filter.IncludeFlagged = include
+	}
+
+	loc := middleware.GetLocation(c)
+	var err error
+	if filter.DateFrom, err = parseFilterDate(c.Query("date_from"), loc, false); err != nil {
+		return nil, err
+	}
+	if filter.DateTo, err = parseFilterDate(c.Query("date_to"), loc, true); err != nil {
+		return nil, err
+	}
+
+	for _, tag := range strings.Split(c.Query("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+
+	for _, item := range strings.Split(c.Query("quota"), ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		idx := strings.LastIndex(item, "=")
+		if idx <= 0 {
+			return nil, fmt.Errorf("无效的配额参数: %s", item)
+		}
+		limit, err := strconv.Atoi(item[idx+1:])
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("无效的配额数量: %s", item)
+		}
+		filter.Quotas = append(filter.Quotas, dto.TagQuota{Tag: strings.TrimSpace(item[:idx]), Limit: limit})
+	}
+
+	return filter, nil
+}
+
+// parseFilterDate 解析日期过滤参数，支持 2006-01-02（按用户时区）和 RFC3339
+// endOfDay 为 true 时只有日期的参数取次日零点，使日期上限包含当天
+func parseFilterDate(value string, loc *time.Location, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return nil, fmt.Errorf("无效的日期: %s", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"io"
+	"strconv"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxGlossaryFileSize 术语表文件大小上限
+const maxGlossaryFileSize = 10 * 1024 * 1024
+
+// GlossaryHandler 术语表处理器
+type GlossaryHandler struct {
+	glossaryService *service.GlossaryService
+}
+
+// NewGlossaryHandler 创建术语表处理器
+func NewGlossaryHandler(glossaryService *service.GlossaryService) *GlossaryHandler {
+	return &GlossaryHandler{
+		glossaryService: glossaryService,
+	}
+}
+
+// CreateGlossary 上传术语表（multipart: file, name, description）
+// @Summary 上传术语表（multipart: file, name, description）
+// @Tags glossary
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "上传的文件"
+// @Param name formData string false "name"
+// @Param description formData string false "description"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/glossaries [post]
+func (h *GlossaryHandler) CreateGlossary(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.BadRequest(c, "文件上传失败: "+err.Error())
+		return
+	}
+	if file.Size > maxGlossaryFileSize {
+		utils.BadRequest(c, "术语表文件不能超过10MB")
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		utils.BadRequest(c, "打开文件失败: "+err.Error())
+		return
+	}
+	defer src.Close()
+
+	content := make([]byte, file.Size)
+	_, err = io.ReadFull(src, content)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		utils.BadRequest(c, "读取文件失败: "+err.Error())
+		return
+	}
+
+	glossary, err := h.glossaryService.CreateGlossary(userID, c.PostForm("name"), c.PostForm("description"), file.Filename, content)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "术语表上传成功", glossary)
+}
+
+// ListGlossaries 获取术语表列表
+// @Summary 获取术语表列表
+// @Tags glossary
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/glossaries [get]
+func (h *GlossaryHandler) ListGlossaries(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	glossaries, err := h.glossaryService.ListGlossaries(userID)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, glossaries)
+}
+
+// GetGlossary 获取术语表详情（含全部术语）
+// @Summary 获取术语表详情（含全部术语）
+// @Tags glossary
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/glossaries/{id} [get]
+func (h *GlossaryHandler) GetGlossary(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的术语表ID")
+		return
+	}
+
+	glossary, err := h.glossaryService.GetGlossary(uint(id), userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, glossary)
+}
+
+// DeleteGlossary 删除术语表
+// @Summary 删除术语表
+// @Tags glossary
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/glossaries/{id} [delete]
+func (h *GlossaryHandler) DeleteGlossary(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的术语表ID")
+		return
+	}
+
+	if err := h.glossaryService.DeleteGlossary(uint(id), userID); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "术语表已删除", gin.H{"success": true})
+}
+
+// CheckTask 使用指定术语表检查任务的生成数据
+// @Summary 使用指定术语表检查任务的生成数据
+// @Tags glossary
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Param request body dto.GlossaryCheckRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/tasks/{task_id}/glossary_check [post]
+func (h *GlossaryHandler) CheckTask(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.GlossaryCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.glossaryService.CheckTaskForUser(c.Param("task_id"), req.GlossaryID, userID)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "术语检查完成", result)
+}
+
+// ListViolations 分页获取任务的术语违规记录（每条记录对应一条生成数据中的一处违规）
+// @Summary 分页获取任务的术语违规记录（每条记录对应一条生成数据中的一处违规）
+// @Tags glossary
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Param page query string false "页码（默认 1）"
+// @Param per_page query string false "每页条数（默认 20）"
+// @Success 200 {object} utils.PaginationResponse
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{task_id}/glossary_violations [get]
+func (h *GlossaryHandler) ListViolations(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	result, err := h.glossaryService.ListViolations(c.Param("task_id"), userID, page, perPage)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.PaginatedResponse(c, result.Items, result.Total, result.Page, result.PerPage)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+
+	"gen-go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler 健康检查处理器
+type HealthHandler struct {
+	taskManager *service.TaskManager
+}
+
+// NewHealthHandler 创建健康检查处理器
+func NewHealthHandler(taskManager *service.TaskManager) *HealthHandler {
+	return &HealthHandler{taskManager: taskManager}
+}
+
+// Healthz 健康检查（包含已安装工作进程的版本与协议兼容性）
+// @Summary 健康检查（包含已安装工作进程的版本与协议兼容性）
+// @Tags health
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /healthz [get]
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	worker := gin.H{
+		"backend_protocol_version": service.WorkerProtocolVersion,
+	}
+	status := "ok"
+
+	handshake, err := h.taskManager.WorkerInfo()
+	if err != nil {
+		status = "degraded"
+		worker["compatible"] = false
+		worker["error"] = err.Error()
+	} else {
+		worker["version"] = handshake.WorkerVersion
+		worker["protocol_version"] = handshake.ProtocolVersion
+		worker["compatible"] = handshake.Compatible()
+		if !handshake.Compatible() {
+			status = "degraded"
+		}
+	}
+
+	// 工作节点模式下列出在线的工作节点
+	if agents := h.taskManager.Agents(); agents != nil {
+		worker["agents"] = agents
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": status,
+		"worker": worker,
+	})
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JudgeHandler 裁判模型评分处理器
+type JudgeHandler struct {
+	judgeService *service.JudgeService
+}
+
+// NewJudgeHandler 创建裁判模型评分处理器
+func NewJudgeHandler(judgeService *service.JudgeService) *JudgeHandler {
+	return &JudgeHandler{
+		judgeService: judgeService,
+	}
+}
+
+// StartJudge 使用指定的裁判模型为任务数据评分（后台执行）
+// @Summary 使用指定的裁判模型为任务数据评分（后台执行）
+// @Tags judge
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Param request body dto.JudgeTaskRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/tasks/{task_id}/judge [post]
+func (h *JudgeHandler) StartJudge(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.JudgeTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	progress, err := h.judgeService.StartJudgeForUser(c.Param("task_id"), userID, req.JudgeModelID)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "评分已开始", progress)
+}
+
+// GetProgress 获取任务的评分进度
+// @Summary 获取任务的评分进度
+// @Tags judge
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{task_id}/judge [get]
+func (h *JudgeHandler) GetProgress(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	progress, err := h.judgeService.GetProgressForUser(c.Param("task_id"), userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, progress)
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+
+	"gen-go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsHandler 运行指标处理器
+type MetricsHandler struct {
+	modelService *service.ModelService
+	housekeeping *service.HousekeepingService
+	jobPools     []*service.JobPool
+}
+
+// NewMetricsHandler 创建运行指标处理器
+func NewMetricsHandler(modelService *service.ModelService, housekeeping *service.HousekeepingService, jobPools ...*service.JobPool) *MetricsHandler {
+	return &MetricsHandler{modelService: modelService, housekeeping: housekeeping, jobPools: jobPools}
+}
+
+// Metrics 获取运行指标（作业池的运行数、排队数，模型响应缓存命中率，后台清理统计等）
+// @Summary 获取运行指标（作业池的运行数、排队数，模型响应缓存命中率，后台清理统计等）
+// @Tags metrics
+// @Produce json
+// @Security InternalAPIKey
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /metrics [get]
+func (h *MetricsHandler) Metrics(c *gin.Context) {
+	pools := make([]service.JobPoolStats, 0, len(h.jobPools))
+	for _, pool := range h.jobPools {
+		pools = append(pools, pool.Stats())
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"job_pools":    pools,
+		"model_cache":  h.modelService.CacheStats(),
+		"housekeeping": h.housekeeping.Stats(),
+	})
+}
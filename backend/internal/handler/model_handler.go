@@ -1,9 +1,13 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"strconv"
 
 	"gen-go/internal/dto"
+	"gen-go/internal/models"
 	"gen-go/internal/service"
 	"gen-go/internal/utils"
 
@@ -12,15 +16,26 @@ import (
 
 // ModelHandler 模型处理器
 type ModelHandler struct {
-	modelService *service.ModelService
+	modelService    *service.ModelService
+	auditLogService *service.AuditLogService
 }
 
 // NewModelHandler 创建模型处理器
-func NewModelHandler(modelService *service.ModelService) *ModelHandler {
-	return &ModelHandler{modelService: modelService}
+func NewModelHandler(modelService *service.ModelService, auditLogService *service.AuditLogService) *ModelHandler {
+	return &ModelHandler{
+		modelService:    modelService,
+		auditLogService: auditLogService,
+	}
 }
 
 // GetModels 获取激活的模型列表
+// @Summary 获取激活的模型列表
+// @Tags model
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/models [get]
 func (h *ModelHandler) GetModels(c *gin.Context) {
 	models, err := h.modelService.GetActiveModels()
 	if err != nil {
@@ -36,6 +51,15 @@ func (h *ModelHandler) GetModels(c *gin.Context) {
 }
 
 // GetAllModels 获取所有模型(管理员)
+// @Summary 获取所有模型(管理员)
+// @Tags model
+// @Produce json
+// @Security BearerAuth
+// @Param page query string false "页码（默认 1）"
+// @Param per_page query string false "每页条数（默认 20）"
+// @Success 200 {object} utils.PaginationResponse
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/models [get]
 func (h *ModelHandler) GetAllModels(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
@@ -50,6 +74,16 @@ func (h *ModelHandler) GetAllModels(c *gin.Context) {
 }
 
 // CreateModel 创建模型
+// @Summary 创建模型
+// @Tags model
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateModelConfigRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/models [post]
 func (h *ModelHandler) CreateModel(c *gin.Context) {
 	var req dto.CreateModelConfigRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -63,10 +97,23 @@ func (h *ModelHandler) CreateModel(c *gin.Context) {
 		return
 	}
 
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionModelCreate, models.AuditResourceModel, model.ID, nil, model))
+
 	utils.SuccessWithMessage(c, "模型创建成功", model)
 }
 
 // UpdateModel 更新模型
+// @Summary 更新模型
+// @Tags model
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param request body dto.UpdateModelConfigRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/models/{id} [put]
 func (h *ModelHandler) UpdateModel(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 
@@ -76,27 +123,207 @@ func (h *ModelHandler) UpdateModel(c *gin.Context) {
 		return
 	}
 
+	before, _ := h.modelService.GetModelByID(uint(id))
+
 	if err := h.modelService.UpdateModel(uint(id), &req); err != nil {
 		utils.InternalError(c, err.Error())
 		return
 	}
 
+	after, _ := h.modelService.GetModelByID(uint(id))
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionModelUpdate, models.AuditResourceModel, id, before, after))
+
 	utils.SuccessWithMessage(c, "模型更新成功", gin.H{"success": true})
 }
 
 // DeleteModel 删除模型
+// @Summary 删除模型
+// @Tags model
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/models/{id} [delete]
 func (h *ModelHandler) DeleteModel(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 
+	before, _ := h.modelService.GetModelByID(uint(id))
+
 	if err := h.modelService.DeleteModel(uint(id)); err != nil {
 		utils.InternalError(c, err.Error())
 		return
 	}
 
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionModelDelete, models.AuditResourceModel, id, before, nil))
+
 	utils.SuccessWithMessage(c, "模型删除成功", gin.H{"success": true})
 }
 
+// TestModel 测试模型服务连接(管理员)
+// @Summary 测试模型服务连接：获取模型列表、判断接口类型（vLLM 或 OpenAI）并发送一次很短的补全请求测量延迟
+// @Tags model
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.TestModelConnectionRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/admin/models/test [post]
+func (h *ModelHandler) TestModel(c *gin.Context) {
+	var req dto.TestModelConnectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.modelService.TestConnection(&req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
+
+// DiscoverModels 从模型服务发现可用模型(管理员)
+// @Summary 查询模型服务的 /models 接口列出可用模型，已有相同地址和模型路径的配置时给出配置 ID
+// @Tags model
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.DiscoverModelsRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Failure 502 {object} utils.Response
+// @Router /api/admin/models/discover [post]
+func (h *ModelHandler) DiscoverModels(c *gin.Context) {
+	var req dto.DiscoverModelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.modelService.DiscoverModels(&req)
+	if err != nil {
+		respondModelDiscoveryError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
+
+// ImportModels 按发现的模型批量创建模型配置(管理员)
+// @Summary 按模型服务返回的模型 ID 批量创建模型配置，已配置或名称冲突的模型会被跳过
+// @Tags model
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ImportModelsRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Failure 502 {object} utils.Response
+// @Router /api/admin/models/import [post]
+func (h *ModelHandler) ImportModels(c *gin.Context) {
+	var req dto.ImportModelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.modelService.ImportModels(&req)
+	if err != nil {
+		respondModelDiscoveryError(c, err)
+		return
+	}
+
+	for _, model := range result.Created {
+		h.auditLogService.Record(newAuditLog(c, models.AuditActionModelCreate, models.AuditResourceModel, model.ID, nil, model))
+	}
+
+	utils.SuccessWithMessage(c, fmt.Sprintf("已创建 %d 个模型，跳过 %d 个", len(result.Created), len(result.Skipped)), result)
+}
+
+// respondModelDiscoveryError 模型服务不可达时返回 502，其余错误返回 500
+func respondModelDiscoveryError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrModelDiscoveryFailed) {
+		utils.ErrorResponse(c, http.StatusBadGateway, err.Error())
+		return
+	}
+	utils.InternalError(c, err.Error())
+}
+
+// GetModelEndpoints 获取模型端点池状态（各服务地址的在途请求数、平均延迟和健康状态）
+// @Summary 获取模型端点池状态（各服务地址的在途请求数、平均延迟和健康状态）
+// @Tags model
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/models/{id}/endpoints [get]
+func (h *ModelHandler) GetModelEndpoints(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的模型ID")
+		return
+	}
+
+	if _, err := h.modelService.GetModelByID(uint(id)); err != nil {
+		utils.NotFound(c, "模型不存在")
+		return
+	}
+
+	result, err := h.modelService.GetModelEndpoints(uint(id))
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
+
+// GetTokenUsage 获取各模型最近的Token用量(管理员)，days 默认 30，最大 365
+// @Summary 获取各模型最近的Token用量(管理员)，days 默认 30，最大 365
+// @Tags model
+// @Produce json
+// @Security BearerAuth
+// @Param days query string false "统计天数（默认 30）"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/models/token_usage [get]
+func (h *ModelHandler) GetTokenUsage(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		utils.BadRequest(c, "days 必须在 1-365 之间")
+		return
+	}
+
+	result, err := h.modelService.GetTokenUsage(days)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
+
 // ModelCall 模型调用代理
+// @Summary 模型调用代理
+// @Tags model
+// @Accept json
+// @Produce json
+// @Security InternalAPIKey
+// @Param request body dto.ModelCallProxyRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/model-call [post]
 func (h *ModelHandler) ModelCall(c *gin.Context) {
 	var req dto.ModelCallProxyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -105,7 +332,7 @@ func (h *ModelHandler) ModelCall(c *gin.Context) {
 	}
 
 	// 调用模型服务
-	resp, err := h.modelService.CallModel(&req)
+	resp, err := h.modelService.CallModelContext(c.Request.Context(), &req)
 	if err != nil {
 		utils.InternalError(c, err.Error())
 		return
@@ -114,3 +341,28 @@ func (h *ModelHandler) ModelCall(c *gin.Context) {
 	// 返回响应
 	c.JSON(200, resp)
 }
+
+// ModelCallBatch 批量模型调用代理，各请求并发执行，结果按请求顺序返回
+// @Summary 批量模型调用代理，各请求并发执行，结果按请求顺序返回
+// @Tags model
+// @Accept json
+// @Produce json
+// @Security InternalAPIKey
+// @Param request body dto.ModelCallBatchRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/model-call/batch [post]
+func (h *ModelHandler) ModelCallBatch(c *gin.Context) {
+	var req dto.ModelCallBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	results := h.modelService.CallModelBatch(c.Request.Context(), req.Requests)
+
+	c.JSON(200, dto.ModelCallBatchResponse{
+		Success: true,
+		Results: results,
+	})
+}
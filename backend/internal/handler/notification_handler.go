@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"strconv"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/models"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler 通知处理器（任务通知订阅和站内通知中心）
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+	auditLogService     *service.AuditLogService
+}
+
+// NewNotificationHandler 创建通知处理器
+func NewNotificationHandler(notificationService *service.NotificationService, auditLogService *service.AuditLogService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+		auditLogService:     auditLogService,
+	}
+}
+
+// CreateSubscription 订阅任务通知（task_id 为空时订阅自己的全部任务）
+// @Summary 订阅任务通知（task_id 为空时订阅自己的全部任务）
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateNotificationSubscriptionRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/notification_subscriptions [post]
+func (h *NotificationHandler) CreateSubscription(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.CreateNotificationSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	sub, err := h.notificationService.CreateSubscription(userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "通知订阅创建成功", sub)
+}
+
+// ListSubscriptions 获取当前用户的通知订阅
+// @Summary 获取当前用户的通知订阅
+// @Tags notification
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/notification_subscriptions [get]
+func (h *NotificationHandler) ListSubscriptions(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	subs, err := h.notificationService.ListSubscriptions(userID)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, subs)
+}
+
+// UpdateSubscription 更新通知订阅
+// @Summary 更新通知订阅
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param request body dto.UpdateNotificationSubscriptionRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/notification_subscriptions/{id} [put]
+func (h *NotificationHandler) UpdateSubscription(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的订阅ID")
+		return
+	}
+
+	var req dto.UpdateNotificationSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	sub, err := h.notificationService.UpdateSubscription(uint(id), userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "通知订阅更新成功", sub)
+}
+
+// DeleteSubscription 删除通知订阅
+// @Summary 删除通知订阅
+// @Tags notification
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/notification_subscriptions/{id} [delete]
+func (h *NotificationHandler) DeleteSubscription(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的订阅ID")
+		return
+	}
+
+	if err := h.notificationService.DeleteSubscription(uint(id), userID); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "通知订阅已删除", gin.H{"success": true})
+}
+
+// TestSubscription 发送一条测试通知，检查渠道配置
+// @Summary 发送一条测试通知，检查渠道配置
+// @Tags notification
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/notification_subscriptions/{id}/test [post]
+func (h *NotificationHandler) TestSubscription(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的订阅ID")
+		return
+	}
+
+	if err := h.notificationService.TestSubscription(uint(id), userID); err != nil {
+		utils.BadRequest(c, "发送测试通知失败: "+err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "测试通知已发送", gin.H{"success": true})
+}
+
+// ListNotifications 分页获取当前用户的站内通知（按时间倒序）
+// @Summary 分页获取当前用户的站内通知（按时间倒序）
+// @Tags notification
+// @Produce json
+// @Security BearerAuth
+// @Param unread query string false "只返回未读通知（true/false）"
+// @Param page query string false "页码（默认 1）"
+// @Param per_page query string false "每页条数（默认 20）"
+// @Success 200 {object} utils.PaginationResponse
+// @Failure 500 {object} utils.Response
+// @Router /api/notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+	unreadOnly := c.Query("unread") == "true"
+
+	result, err := h.notificationService.ListNotifications(userID, unreadOnly, page, perPage)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.PaginatedResponse(c, result.Items, result.Total, result.Page, result.PerPage)
+}
+
+// UnreadCount 获取当前用户的未读通知数（前端通知图标轮询此接口）
+// @Summary 获取当前用户的未读通知数（前端通知图标轮询此接口）
+// @Tags notification
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/notifications/unread_count [get]
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	count, err := h.notificationService.UnreadCount(userID)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"unread": count})
+}
+
+// MarkRead 将通知标记为已读（ids 为空时全部标记为已读）
+// @Summary 将通知标记为已读（ids 为空时全部标记为已读）
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.MarkNotificationsReadRequest false "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/notifications/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.MarkNotificationsReadRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	marked, err := h.notificationService.MarkRead(userID, req.IDs)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"marked": marked})
+}
+
+// CreateAnnouncement 发布公告，写入接收用户的站内通知
+// @Summary 发布公告，写入接收用户的站内通知
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateAnnouncementRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/announcements [post]
+func (h *NotificationHandler) CreateAnnouncement(c *gin.Context) {
+	var req dto.CreateAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	recipients, err := h.notificationService.Announce(&req)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionAnnouncementCreate, models.AuditResourceAnnouncement, "", nil, req))
+	utils.SuccessWithMessage(c, "公告已发布", gin.H{"recipients": recipients})
+}
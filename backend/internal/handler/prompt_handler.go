@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"strconv"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromptHandler 提示词库处理器
+type PromptHandler struct {
+	promptService *service.PromptService
+}
+
+// NewPromptHandler 创建提示词库处理器
+func NewPromptHandler(promptService *service.PromptService) *PromptHandler {
+	return &PromptHandler{
+		promptService: promptService,
+	}
+}
+
+// CreatePrompt 创建提示词
+// @Summary 创建提示词
+// @Tags prompt
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreatePromptRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/prompts [post]
+func (h *PromptHandler) CreatePrompt(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.CreatePromptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	prompt, err := h.promptService.CreatePrompt(userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "提示词创建成功", prompt)
+}
+
+// ListPrompts 获取提示词列表（支持 tag 和 keyword 过滤）
+// @Summary 获取提示词列表（支持 tag 和 keyword 过滤）
+// @Tags prompt
+// @Produce json
+// @Security BearerAuth
+// @Param tag query string false "标签"
+// @Param keyword query string false "关键词"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/prompts [get]
+func (h *PromptHandler) ListPrompts(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	prompts, err := h.promptService.ListPrompts(userID, c.Query("tag"), c.Query("keyword"))
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, prompts)
+}
+
+// GetPrompt 获取提示词详情
+// @Summary 获取提示词详情
+// @Tags prompt
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/prompts/{id} [get]
+func (h *PromptHandler) GetPrompt(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的提示词ID")
+		return
+	}
+
+	prompt, err := h.promptService.GetPrompt(uint(id), userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, prompt)
+}
+
+// UpdatePrompt 更新提示词名称、描述和标签
+// @Summary 更新提示词名称、描述和标签
+// @Tags prompt
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param request body dto.UpdatePromptRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/prompts/{id} [put]
+func (h *PromptHandler) UpdatePrompt(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的提示词ID")
+		return
+	}
+
+	var req dto.UpdatePromptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	prompt, err := h.promptService.UpdatePrompt(uint(id), userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "提示词更新成功", prompt)
+}
+
+// DeletePrompt 删除提示词
+// @Summary 删除提示词
+// @Tags prompt
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/prompts/{id} [delete]
+func (h *PromptHandler) DeletePrompt(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的提示词ID")
+		return
+	}
+
+	if err := h.promptService.DeletePrompt(uint(id), userID); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "提示词已删除", gin.H{"success": true})
+}
+
+// CreateVersion 创建提示词新版本
+// @Summary 创建提示词新版本
+// @Tags prompt
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param request body dto.CreatePromptVersionRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/prompts/{id}/versions [post]
+func (h *PromptHandler) CreateVersion(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的提示词ID")
+		return
+	}
+
+	var req dto.CreatePromptVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	version, err := h.promptService.CreateVersion(uint(id), userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "提示词版本创建成功", version)
+}
+
+// ListVersions 获取提示词的版本历史
+// @Summary 获取提示词的版本历史
+// @Tags prompt
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/prompts/{id}/versions [get]
+func (h *PromptHandler) ListVersions(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的提示词ID")
+		return
+	}
+
+	versions, err := h.promptService.ListVersions(uint(id), userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, versions)
+}
+
+// GetVersion 获取提示词的指定版本
+// @Summary 获取提示词的指定版本
+// @Tags prompt
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param version path integer true "版本号"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/prompts/{id}/versions/{version} [get]
+func (h *PromptHandler) GetVersion(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的提示词ID")
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		utils.BadRequest(c, "无效的版本号")
+		return
+	}
+
+	v, err := h.promptService.GetVersion(uint(id), userID, version)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, v)
+}
@@ -1,9 +1,14 @@
 package handler
 
 import (
+	"strconv"
+	"strings"
+
 	"gen-go/internal/dto"
 	"gen-go/internal/middleware"
+	"gen-go/internal/models"
 	"gen-go/internal/repository"
+	"gen-go/internal/service"
 	"gen-go/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -13,38 +18,76 @@ import (
 type ReportHandler struct {
 	generatedDataRepo *repository.GeneratedDataRepository
 	taskRepo          *repository.TaskRepository
+	reviewService     *service.ReviewService
+	reportService     *service.ReportService
+	auditService      *service.ExportAuditService
+	auditLogService   *service.AuditLogService
 }
 
 // NewReportHandler 创建报告处理器
-func NewReportHandler(generatedDataRepo *repository.GeneratedDataRepository, taskRepo *repository.TaskRepository) *ReportHandler {
+func NewReportHandler(generatedDataRepo *repository.GeneratedDataRepository, taskRepo *repository.TaskRepository, reviewService *service.ReviewService, reportService *service.ReportService, auditService *service.ExportAuditService, auditLogService *service.AuditLogService) *ReportHandler {
 	return &ReportHandler{
 		generatedDataRepo: generatedDataRepo,
 		taskRepo:          taskRepo,
+		reviewService:     reviewService,
+		reportService:     reportService,
+		auditService:      auditService,
+		auditLogService:   auditLogService,
 	}
 }
 
 // ListReports 获取报告列表
+// 支持 page/per_page 分页、status（逗号分隔）过滤和 date_from/date_to 开始日期范围（按用户时区，date_to 含当天）；
+// 未传 page/per_page 时返回最近 1000 个任务（兼容旧版前端）
+// @Summary 获取报告列表
+// @Tags report
+// @Produce json
+// @Security BearerAuth
+// @Param page query string false "页码"
+// @Param per_page query string false "每页条数"
+// @Param status query string false "状态（逗号分隔）"
+// @Param date_from query string false "开始日期（YYYY-MM-DD）"
+// @Param date_to query string false "结束日期（YYYY-MM-DD）"
+// @Param workspace_id query string false "工作区ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/reports [get]
 func (h *ReportHandler) ListReports(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
+	loc := middleware.GetLocation(c)
 
-	// 获取用户的所有任务（不限制数量）
-	tasks, _, err := h.taskRepo.ListByUserID(userID, 0, 1000)
+	filter, err := parseReportFilter(c)
 	if err != nil {
-		utils.InternalError(c, err.Error())
+		utils.BadRequest(c, err.Error())
 		return
 	}
 
-	// 构建报告列表
-	reports := make([]map[string]interface{}, 0, len(tasks))
-	for _, task := range tasks {
-		// 获取生成数据条数
-		_, dataCount, err := h.generatedDataRepo.ListByTaskID(task.TaskID, 0, 1)
-		if err != nil {
-			dataCount = 0
+	page, perPage := 1, 1000
+	if c.Query("page") != "" || c.Query("per_page") != "" {
+		page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+		perPage, _ = strconv.Atoi(c.DefaultQuery("per_page", "20"))
+		if page < 1 {
+			page = 1
 		}
+		if perPage < 1 || perPage > 100 {
+			perPage = 20
+		}
+	}
 
-		// 获取已确认数据条数
-		confirmedCount, _ := h.generatedDataRepo.GetConfirmedCount(task.TaskID)
+	// 数据条数和已确认条数在同一查询中按任务聚合
+	rows, total, err := h.taskRepo.ListReports(userID, filter, (page-1)*perPage, perPage)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	// 构建报告列表
+	reports := make([]map[string]interface{}, 0, len(rows))
+	for i := range rows {
+		task := &rows[i].Task
+		dataCount := rows[i].DataCount
+		confirmedCount := rows[i].ConfirmedCount
 
 		// 解析参数
 		var params interface{}
@@ -52,30 +95,139 @@ func (h *ReportHandler) ListReports(c *gin.Context) {
 			params = task.Params
 		}
 
+		// 多人审核的任务附带审核一致性统计
+		agreement, _ := h.reviewService.GetAgreement(task)
+
 		reports = append(reports, map[string]interface{}{
-			"id":               task.ID,
-			"task_id":          task.TaskID,
-			"status":           task.Status,
-			"started_at":       task.StartedAt,
-			"finished_at":      task.FinishedAt,
-			"data_count":       int(dataCount),
-			"has_data":         dataCount > 0,
-			"confirmed_count":  int(confirmedCount),
-			"is_fully_reviewed": dataCount > 0 && confirmedCount == dataCount,
-			"input_chars":       task.InputChars,
-			"output_chars":      task.OutputChars,
-			"params":           params,
-			"error_message":    task.ErrorMessage,
+			"id":                  task.ID,
+			"task_id":             task.TaskID,
+			"user_id":             task.UserID,
+			"workspace_id":        task.WorkspaceID,
+			"status":              task.Status,
+			"started_at":          dto.FormatTime(task.StartedAt),
+			"finished_at":         dto.FormatTimePtr(task.FinishedAt),
+			"started_at_display":  dto.FormatDisplayTime(task.StartedAt, loc),
+			"finished_at_display": dto.FormatDisplayTimePtr(task.FinishedAt, loc),
+			"data_count":          int(dataCount),
+			"has_data":            dataCount > 0,
+			"confirmed_count":     int(confirmedCount),
+			"is_fully_reviewed":   dataCount > 0 && confirmedCount == dataCount,
+			"input_chars":         task.InputChars,
+			"output_chars":        task.OutputChars,
+			"params":              params,
+			"error_message":       task.ErrorMessage,
+			"review_agreement":    agreement,
 		})
 	}
 
 	utils.SuccessResponse(c, gin.H{
-		"success": true,
-		"reports": reports,
+		"success":  true,
+		"reports":  reports,
+		"total":    total,
+		"page":     page,
+		"per_page": perPage,
+		"timezone": loc.String(),
 	})
 }
 
+// parseReportFilter 解析报告列表的过滤参数
+func parseReportFilter(c *gin.Context) (*repository.ReportFilter, error) {
+	workspaceID, err := parseWorkspaceQuery(c)
+	if err != nil {
+		return nil, err
+	}
+	filter := &repository.ReportFilter{WorkspaceID: workspaceID}
+
+	for _, raw := range strings.Split(c.Query("status"), ",") {
+		if raw = strings.TrimSpace(raw); raw != "" {
+			status, err := models.ParseTaskStatus(raw)
+			if err != nil {
+				return nil, err
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	loc := middleware.GetLocation(c)
+	if filter.StartedFrom, err = parseFilterDate(c.Query("date_from"), loc, false); err != nil {
+		return nil, err
+	}
+	if filter.StartedTo, err = parseFilterDate(c.Query("date_to"), loc, true); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// CompareReports 对比两个任务的生成数据（按源样本对齐，返回评分分布、长度统计和逐条差异）
+// @Summary 对比两个任务的生成数据（按源样本对齐，返回评分分布、长度统计和逐条差异）
+// @Tags report
+// @Produce json
+// @Security BearerAuth
+// @Param task_a query string false "对比任务A"
+// @Param task_b query string false "对比任务B"
+// @Param page query string false "页码（默认 1）"
+// @Param per_page query string false "每页条数（默认 50）"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/reports/compare [get]
+func (h *ReportHandler) CompareReports(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	taskA := c.Query("task_a")
+	taskB := c.Query("task_b")
+	if taskA == "" || taskB == "" {
+		utils.BadRequest(c, "必须指定 task_a 和 task_b")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 500 {
+		perPage = 50
+	}
+
+	result, err := h.reportService.Compare(taskA, taskB, userID, page, perPage)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
+
+// GetReportStats 获取任务生成数据的统计（评分直方图、长度分位数、重试次数分布、每轮产出）
+// @Summary 获取任务生成数据的统计（评分直方图、长度分位数、重试次数分布、每轮产出）
+// @Tags report
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/reports/{task_id}/stats [get]
+func (h *ReportHandler) GetReportStats(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	result, err := h.reportService.GetStats(c.Param("task_id"), userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
+
 // GetReportData 获取任务报告数据
+// @Summary 获取任务报告数据
+// @Tags report
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/reports/{task_id}/data [get]
 func (h *ReportHandler) GetReportData(c *gin.Context) {
 	taskID := c.Param("task_id")
 
@@ -101,22 +253,36 @@ func (h *ReportHandler) GetReportData(c *gin.Context) {
 			"generation_model": item.GenerationModel,
 			"task_type":        item.TaskType,
 			"is_confirmed":     item.IsConfirmed,
-			"created_at":       item.CreatedAt,
-			"updated_at":       item.UpdatedAt,
+			"created_at":       dto.FormatTime(item.CreatedAt),
+			"updated_at":       dto.FormatTime(item.UpdatedAt),
 		}
 	}
 
+	// 返回完整数据集，与下载、导出一样记录导出审计
+	h.auditService.Record(newExportAudit(c, models.ExportResourceGeneratedData, taskID, "json", len(data)))
+
 	utils.SuccessResponse(c, dto.ReportDataResponse{
 		TaskID: taskID,
 		Data:   data,
-		Total: int(total),
+		Total:  int(total),
 	})
 }
 
 // DeleteReport 删除报告
+// @Summary 删除报告
+// @Tags report
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/reports/{task_id} [delete]
 func (h *ReportHandler) DeleteReport(c *gin.Context) {
 	taskID := c.Param("task_id")
 
+	// 删除前记录任务信息作为审计快照
+	before, _ := h.taskRepo.GetByTaskID(taskID)
+
 	// 删除任务的所有生成数据
 	if err := h.generatedDataRepo.DeleteByTaskID(taskID); err != nil {
 		utils.InternalError(c, err.Error())
@@ -129,10 +295,20 @@ func (h *ReportHandler) DeleteReport(c *gin.Context) {
 		// 因为主要目的是删除数据
 	}
 
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionReportDelete, models.AuditResourceReport, taskID, before, nil))
+
 	utils.SuccessWithMessage(c, "报告已删除", gin.H{"success": true})
 }
 
 // GetReportDataEditable 获取任务报告数据（可编辑格式）
+// @Summary 获取任务报告数据（可编辑格式）
+// @Tags report
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/reports/{task_id}/data/editable [get]
 func (h *ReportHandler) GetReportDataEditable(c *gin.Context) {
 	taskID := c.Param("task_id")
 
@@ -157,19 +333,28 @@ func (h *ReportHandler) GetReportDataEditable(c *gin.Context) {
 			"id":           item.ID,
 			"data":         dataContent,
 			"is_confirmed": item.IsConfirmed,
-			"created_at":   item.CreatedAt,
-			"updated_at":   item.UpdatedAt,
+			"created_at":   dto.FormatTime(item.CreatedAt),
+			"updated_at":   dto.FormatTime(item.UpdatedAt),
 		}
 	}
 
 	utils.SuccessResponse(c, gin.H{
-		"data":   data,
-		"count":  int(total),
+		"data":    data,
+		"count":   int(total),
 		"success": true,
 	})
 }
 
 // BatchDeleteReports 批量删除报告
+// @Summary 批量删除报告
+// @Tags report
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body object true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/reports/batch_delete [post]
 func (h *ReportHandler) BatchDeleteReports(c *gin.Context) {
 	var req struct {
 		TaskIDs []string `json:"task_ids" binding:"required"`
@@ -180,10 +365,12 @@ func (h *ReportHandler) BatchDeleteReports(c *gin.Context) {
 	}
 
 	for _, taskID := range req.TaskIDs {
+		before, _ := h.taskRepo.GetByTaskID(taskID)
 		// 删除生成数据
 		h.generatedDataRepo.DeleteByTaskID(taskID)
 		// 同时删除任务记录
 		h.taskRepo.DeleteByTaskID(taskID)
+		h.auditLogService.Record(newAuditLog(c, models.AuditActionReportDelete, models.AuditResourceReport, taskID, before, nil))
 	}
 
 	utils.SuccessWithMessage(c, "批量删除成功", gin.H{"success": true})
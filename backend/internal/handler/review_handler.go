@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"strconv"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewHandler 生成数据审核处理器
+type ReviewHandler struct {
+	reviewService *service.ReviewService
+}
+
+// NewReviewHandler 创建审核处理器
+func NewReviewHandler(reviewService *service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+// Assign 将任务数据分配给审核人
+// @Summary 将任务数据分配给审核人
+// @Tags review
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.AssignReviewRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/generated_data/assign [post]
+func (h *ReviewHandler) Assign(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.AssignReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.reviewService.Assign(userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "分配成功", result)
+}
+
+// ListAssigned 获取分配给当前用户的审核数据
+// @Summary 获取分配给当前用户的审核数据
+// @Tags review
+// @Produce json
+// @Security BearerAuth
+// @Param page query string false "页码（默认 1）"
+// @Param per_page query string false "每页条数（默认 20）"
+// @Param task_id query string false "任务ID"
+// @Param status query string false "状态（逗号分隔）"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/reviews/assigned [get]
+func (h *ReviewHandler) ListAssigned(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	result, err := h.reviewService.ListAssigned(userID, c.Query("task_id"), c.Query("status"), page, perPage)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
+
+// Submit 提交单条数据的审核结果
+// @Summary 提交单条数据的审核结果
+// @Tags review
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param data_id path integer true "数据ID"
+// @Param request body dto.SubmitReviewRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/generated_data/{data_id}/review [post]
+func (h *ReviewHandler) Submit(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	dataID, err := strconv.ParseUint(c.Param("data_id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的数据ID")
+		return
+	}
+
+	var req dto.SubmitReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.reviewService.Submit(userID, uint(dataID), &req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "审核已提交", gin.H{"success": true})
+}
+
+// GetProgress 获取任务按审核人统计的审核进度
+// @Summary 获取任务按审核人统计的审核进度
+// @Tags review
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/reports/{task_id}/review_progress [get]
+func (h *ReviewHandler) GetProgress(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	result, err := h.reviewService.GetProgressForUser(c.Param("task_id"), userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
+
+// UpdateSettings 修改任务的审核设置（每条数据需要的独立审核次数）
+// @Summary 修改任务的审核设置（每条数据需要的独立审核次数）
+// @Tags review
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Param request body dto.ReviewSettingsRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/tasks/{task_id}/review_settings [put]
+func (h *ReviewHandler) UpdateSettings(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.ReviewSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.reviewService.UpdateSettings(c.Param("task_id"), userID, &req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "审核设置已保存", gin.H{"required_reviews": req.RequiredReviews})
+}
+
+// Reassign 管理员批量转交审核
+// @Summary 管理员批量转交审核
+// @Tags review
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ReassignReviewRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/admin/reviews/reassign [post]
+func (h *ReviewHandler) Reassign(c *gin.Context) {
+	var req dto.ReassignReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	moved, err := h.reviewService.Reassign(&req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "转交成功", gin.H{"reassigned": moved})
+}
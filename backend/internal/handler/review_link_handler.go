@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/models"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewLinkHandler 审阅链接处理器
+// 包含任务所有者管理链接的接口，以及持审阅 Token 访问的只读+确认接口
+type ReviewLinkHandler struct {
+	reviewLinkService    *service.ReviewLinkService
+	generatedDataService *service.GeneratedDataService
+}
+
+// NewReviewLinkHandler 创建审阅链接处理器
+func NewReviewLinkHandler(reviewLinkService *service.ReviewLinkService, generatedDataService *service.GeneratedDataService) *ReviewLinkHandler {
+	return &ReviewLinkHandler{
+		reviewLinkService:    reviewLinkService,
+		generatedDataService: generatedDataService,
+	}
+}
+
+// CreateLink 为任务创建审阅链接
+// @Summary 为任务创建审阅链接
+// @Tags review_link
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Param request body dto.CreateReviewLinkRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/tasks/{task_id}/review_links [post]
+func (h *ReviewLinkHandler) CreateLink(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	taskID := c.Param("task_id")
+
+	// 请求体可为空（使用默认有效期）
+	var req dto.CreateReviewLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	link, err := h.reviewLinkService.CreateLink(userID, taskID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "审阅链接创建成功", link)
+}
+
+// ListLinks 获取任务的审阅链接
+// @Summary 获取任务的审阅链接
+// @Tags review_link
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/tasks/{task_id}/review_links [get]
+func (h *ReviewLinkHandler) ListLinks(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	links, err := h.reviewLinkService.ListLinks(userID, c.Param("task_id"))
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, links)
+}
+
+// RevokeLink 撤销审阅链接
+// @Summary 撤销审阅链接
+// @Tags review_link
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/review_links/{id} [delete]
+func (h *ReviewLinkHandler) RevokeLink(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的审阅链接ID")
+		return
+	}
+
+	if err := h.reviewLinkService.RevokeLink(uint(id), userID); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "审阅链接已撤销", gin.H{"success": true})
+}
+
+// ListActivities 分页获取审阅链接的访问记录
+// @Summary 分页获取审阅链接的访问记录
+// @Tags review_link
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param page query string false "页码（默认 1）"
+// @Param per_page query string false "每页条数（默认 20）"
+// @Success 200 {object} utils.PaginationResponse
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/review_links/{id}/activities [get]
+func (h *ReviewLinkHandler) ListActivities(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的审阅链接ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	result, err := h.reviewLinkService.ListActivities(uint(id), userID, page, perPage)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.PaginatedResponse(c, result.Items, result.Total, result.Page, result.PerPage)
+}
+
+// ReviewInfo 审阅者获取任务数据概况
+// @Summary 审阅者获取任务数据概况
+// @Tags review_link
+// @Produce json
+// @Security ReviewToken
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/review/info [get]
+func (h *ReviewLinkHandler) ReviewInfo(c *gin.Context) {
+	link := middleware.GetReviewLink(c)
+
+	info, err := h.generatedDataService.GetTaskInfo(link.TaskID, link.UserID)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	h.reviewLinkService.RecordActivity(link, models.ReviewActionView, nil, c.ClientIP(), c.Request.UserAgent())
+	utils.SuccessResponse(c, info)
+}
+
+// ReviewListData 审阅者分页获取任务的生成数据
+// @Summary 审阅者分页获取任务的生成数据
+// @Tags review_link
+// @Produce json
+// @Security ReviewToken
+// @Param page query string false "页码（默认 1）"
+// @Param per_page query string false "每页条数（默认 20）"
+// @Success 200 {object} utils.PaginationResponse
+// @Failure 500 {object} utils.Response
+// @Router /api/review/data [get]
+func (h *ReviewLinkHandler) ReviewListData(c *gin.Context) {
+	link := middleware.GetReviewLink(c)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	result, err := h.generatedDataService.ListData(link.TaskID, link.UserID, nil, page, perPage)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	h.reviewLinkService.RecordActivity(link, models.ReviewActionList, nil, c.ClientIP(), c.Request.UserAgent())
+	utils.PaginatedResponse(c, result.Items, result.Total, result.Page, result.PerPage)
+}
+
+// ReviewConfirmData 审阅者确认或取消确认单条数据
+// @Summary 审阅者确认或取消确认单条数据
+// @Tags review_link
+// @Accept json
+// @Produce json
+// @Security ReviewToken
+// @Param data_id path integer true "数据ID"
+// @Param request body dto.ConfirmDataRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/review/data/{data_id}/confirm [post]
+func (h *ReviewLinkHandler) ReviewConfirmData(c *gin.Context) {
+	link := middleware.GetReviewLink(c)
+
+	dataID, err := strconv.ParseUint(c.Param("data_id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的数据ID")
+		return
+	}
+
+	var req dto.ConfirmDataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		// 请求体为空时默认为确认
+		req.IsConfirmed = true
+	}
+
+	if err := h.reviewLinkService.ConfirmData(link, uint(dataID), req.IsConfirmed); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	id := uint(dataID)
+	action := models.ReviewActionUnconfirm
+	message := "已取消确认"
+	if req.IsConfirmed {
+		action = models.ReviewActionConfirm
+		message = "确认成功"
+	}
+	h.reviewLinkService.RecordActivity(link, action, &id, c.ClientIP(), c.Request.UserAgent())
+	utils.SuccessWithMessage(c, message, gin.H{"success": true})
+}
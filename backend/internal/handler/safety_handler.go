@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"gen-go/internal/middleware"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SafetyHandler 内容安全检查处理器
+type SafetyHandler struct {
+	safetyService *service.SafetyService
+}
+
+// NewSafetyHandler 创建内容安全检查处理器
+func NewSafetyHandler(safetyService *service.SafetyService) *SafetyHandler {
+	return &SafetyHandler{
+		safetyService: safetyService,
+	}
+}
+
+// CheckTask 使用当前配置的规则重新检查任务数据
+// @Summary 使用当前配置的规则重新检查任务数据
+// @Tags safety
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/tasks/{task_id}/safety_check [post]
+func (h *SafetyHandler) CheckTask(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	result, err := h.safetyService.CheckTaskForUser(c.Param("task_id"), userID)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "内容安全检查完成", result)
+}
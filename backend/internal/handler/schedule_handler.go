@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"strconv"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScheduleHandler 定时任务处理器
+type ScheduleHandler struct {
+	schedulerService *service.SchedulerService
+}
+
+// NewScheduleHandler 创建定时任务处理器
+func NewScheduleHandler(schedulerService *service.SchedulerService) *ScheduleHandler {
+	return &ScheduleHandler{
+		schedulerService: schedulerService,
+	}
+}
+
+// CreateSchedule 创建定时任务
+// @Summary 创建定时任务
+// @Tags schedule
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateScheduleRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/schedules [post]
+func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.CreateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	schedule, err := h.schedulerService.CreateSchedule(userID, &req, middleware.GetLocation(c))
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "定时任务创建成功", schedule)
+}
+
+// ListSchedules 获取当前用户的定时任务列表
+// @Summary 获取当前用户的定时任务列表
+// @Tags schedule
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/schedules [get]
+func (h *ScheduleHandler) ListSchedules(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	schedules, err := h.schedulerService.ListSchedules(userID)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, schedules)
+}
+
+// GetSchedule 获取定时任务详情
+// @Summary 获取定时任务详情
+// @Tags schedule
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/schedules/{id} [get]
+func (h *ScheduleHandler) GetSchedule(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的定时任务ID")
+		return
+	}
+
+	schedule, err := h.schedulerService.GetSchedule(uint(id), userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, schedule)
+}
+
+// UpdateSchedule 更新定时任务
+// @Summary 更新定时任务
+// @Tags schedule
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param request body dto.UpdateScheduleRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/schedules/{id} [put]
+func (h *ScheduleHandler) UpdateSchedule(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的定时任务ID")
+		return
+	}
+
+	var req dto.UpdateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	schedule, err := h.schedulerService.UpdateSchedule(uint(id), userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "定时任务更新成功", schedule)
+}
+
+// DeleteSchedule 删除定时任务
+// @Summary 删除定时任务
+// @Tags schedule
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/schedules/{id} [delete]
+func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的定时任务ID")
+		return
+	}
+
+	if err := h.schedulerService.DeleteSchedule(uint(id), userID); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "定时任务已删除", gin.H{"success": true})
+}
+
+// ListRuns 分页获取定时任务的执行记录
+// @Summary 分页获取定时任务的执行记录
+// @Tags schedule
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param page query string false "页码（默认 1）"
+// @Param per_page query string false "每页条数（默认 20）"
+// @Success 200 {object} utils.PaginationResponse
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/schedules/{id}/runs [get]
+func (h *ScheduleHandler) ListRuns(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的定时任务ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	result, err := h.schedulerService.ListRuns(uint(id), userID, page, perPage)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.PaginatedResponse(c, result.Items, result.Total, result.Page, result.PerPage)
+}
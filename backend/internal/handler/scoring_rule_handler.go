@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"strconv"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/models"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScoringRuleHandler 规则评分处理器
+type ScoringRuleHandler struct {
+	scoringService  *service.ScoringService
+	auditLogService *service.AuditLogService
+}
+
+// NewScoringRuleHandler 创建规则评分处理器
+func NewScoringRuleHandler(scoringService *service.ScoringService, auditLogService *service.AuditLogService) *ScoringRuleHandler {
+	return &ScoringRuleHandler{
+		scoringService:  scoringService,
+		auditLogService: auditLogService,
+	}
+}
+
+// CreateRule 创建评分规则（管理员）
+// @Summary 创建评分规则（管理员）
+// @Tags scoring_rule
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateScoringRuleRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/admin/scoring_rules [post]
+func (h *ScoringRuleHandler) CreateRule(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.CreateScoringRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	rule, err := h.scoringService.CreateRule(userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionScoringRuleCreate, models.AuditResourceScoringRule, rule.ID, nil, rule))
+	utils.SuccessWithMessage(c, "评分规则创建成功", rule)
+}
+
+// ListRules 获取评分规则列表（管理员）
+// @Summary 获取评分规则列表（管理员）
+// @Tags scoring_rule
+// @Produce json
+// @Security BearerAuth
+// @Param task_type query string false "任务类型"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/scoring_rules [get]
+func (h *ScoringRuleHandler) ListRules(c *gin.Context) {
+	rules, err := h.scoringService.ListRules(c.Query("task_type"))
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, rules)
+}
+
+// UpdateRule 更新评分规则（管理员）
+// @Summary 更新评分规则（管理员）
+// @Tags scoring_rule
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param request body dto.UpdateScoringRuleRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/admin/scoring_rules/{id} [put]
+func (h *ScoringRuleHandler) UpdateRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的规则ID")
+		return
+	}
+
+	var req dto.UpdateScoringRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	before, err := h.scoringService.GetRule(uint(id))
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	rule, err := h.scoringService.UpdateRule(uint(id), &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionScoringRuleUpdate, models.AuditResourceScoringRule, id, before, rule))
+	utils.SuccessWithMessage(c, "评分规则更新成功", rule)
+}
+
+// DeleteRule 删除评分规则（管理员）
+// @Summary 删除评分规则（管理员）
+// @Tags scoring_rule
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/admin/scoring_rules/{id} [delete]
+func (h *ScoringRuleHandler) DeleteRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的规则ID")
+		return
+	}
+
+	before, err := h.scoringService.GetRule(uint(id))
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	if err := h.scoringService.DeleteRule(uint(id)); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionScoringRuleDelete, models.AuditResourceScoringRule, id, before, nil))
+	utils.SuccessWithMessage(c, "评分规则已删除", gin.H{"success": true})
+}
+
+// ScoreTask 使用任务类型当前启用的规则重新为任务数据评分
+// @Summary 使用任务类型当前启用的规则重新为任务数据评分
+// @Tags scoring_rule
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/tasks/{task_id}/rule_score [post]
+func (h *ScoringRuleHandler) ScoreTask(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	result, err := h.scoringService.ScoreTaskForUser(c.Param("task_id"), userID)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+	if result == nil {
+		utils.BadRequest(c, "该任务类型没有启用的评分规则")
+		return
+	}
+
+	utils.SuccessWithMessage(c, "规则评分完成", result)
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"strconv"
+
+	"gen-go/internal/middleware"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchHandler 全文检索处理器
+type SearchHandler struct {
+	searchService *service.SearchService
+}
+
+// NewSearchHandler 创建全文检索处理器
+func NewSearchHandler(searchService *service.SearchService) *SearchHandler {
+	return &SearchHandler{searchService: searchService}
+}
+
+// Search 在当前用户的数据文件和生成数据中检索关键字
+// 参数：q 关键字，scope 为 files、generated 或 all（默认），limit 最多返回的命中数
+// @Summary 在当前用户的数据文件和生成数据中检索关键字
+// @Tags search
+// @Produce json
+// @Security BearerAuth
+// @Param limit query string false "返回条数上限"
+// @Param q query string false "搜索关键词"
+// @Param scope query string false "检索范围"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(service.SearchDefaultLimit)))
+	if limit < 1 || limit > service.SearchMaxLimit {
+		limit = service.SearchDefaultLimit
+	}
+
+	result, err := h.searchService.Search(userID, c.Query("q"), c.Query("scope"), limit)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"gen-go/internal/middleware"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StorageHandler 存储占用处理器
+type StorageHandler struct {
+	storageService *service.StorageService
+}
+
+// NewStorageHandler 创建存储占用处理器
+func NewStorageHandler(storageService *service.StorageService) *StorageHandler {
+	return &StorageHandler{
+		storageService: storageService,
+	}
+}
+
+// GetMyUsage 获取当前用户的存储占用
+// @Summary 获取当前用户的存储占用
+// @Tags storage
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/me/storage [get]
+func (h *StorageHandler) GetMyUsage(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	usage, err := h.storageService.GetUserUsage(userID)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, usage)
+}
+
+// GetSummary 获取全部用户的存储占用汇总（管理员）
+// @Summary 获取全部用户的存储占用汇总（管理员）
+// @Tags storage
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/storage [get]
+func (h *StorageHandler) GetSummary(c *gin.Context) {
+	summary, err := h.storageService.GetSummary()
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, summary)
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"strconv"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagRuleHandler 自动打标处理器
+type TagRuleHandler struct {
+	taggingService *service.TaggingService
+}
+
+// NewTagRuleHandler 创建自动打标处理器
+func NewTagRuleHandler(taggingService *service.TaggingService) *TagRuleHandler {
+	return &TagRuleHandler{
+		taggingService: taggingService,
+	}
+}
+
+// CreateRule 创建打标规则
+// @Summary 创建打标规则
+// @Tags tag_rule
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateTagRuleRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/tag_rules [post]
+func (h *TagRuleHandler) CreateRule(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.CreateTagRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	rule, err := h.taggingService.CreateRule(userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "打标规则创建成功", rule)
+}
+
+// ListRules 获取打标规则列表
+// @Summary 获取打标规则列表
+// @Tags tag_rule
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/tag_rules [get]
+func (h *TagRuleHandler) ListRules(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	rules, err := h.taggingService.ListRules(userID)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, rules)
+}
+
+// UpdateRule 更新打标规则
+// @Summary 更新打标规则
+// @Tags tag_rule
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param request body dto.UpdateTagRuleRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/tag_rules/{id} [put]
+func (h *TagRuleHandler) UpdateRule(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的规则ID")
+		return
+	}
+
+	var req dto.UpdateTagRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	rule, err := h.taggingService.UpdateRule(uint(id), userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "打标规则更新成功", rule)
+}
+
+// DeleteRule 删除打标规则
+// @Summary 删除打标规则
+// @Tags tag_rule
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tag_rules/{id} [delete]
+func (h *TagRuleHandler) DeleteRule(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的规则ID")
+		return
+	}
+
+	if err := h.taggingService.DeleteRule(uint(id), userID); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "打标规则已删除", gin.H{"success": true})
+}
+
+// TagTask 使用当前启用的规则重新为任务数据打标
+// @Summary 使用当前启用的规则重新为任务数据打标
+// @Tags tag_rule
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/tasks/{task_id}/tag [post]
+func (h *TagRuleHandler) TagTask(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	result, err := h.taggingService.TagTaskForUser(c.Param("task_id"), userID)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+	if result == nil {
+		utils.BadRequest(c, "没有启用的打标规则")
+		return
+	}
+
+	utils.SuccessWithMessage(c, "打标完成", result)
+}
+
+// GetTagCounts 获取任务数据的标签统计
+// @Summary 获取任务数据的标签统计
+// @Tags tag_rule
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{task_id}/tags [get]
+func (h *TagRuleHandler) GetTagCounts(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	counts, err := h.taggingService.GetTagCounts(c.Param("task_id"), userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, counts)
+}
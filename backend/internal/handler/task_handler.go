@@ -1,10 +1,15 @@
 package handler
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
@@ -32,6 +37,18 @@ func NewTaskHandler(taskManager *service.TaskManager, redisClient *redis.Client)
 }
 
 // StartTask 启动任务
+// @Summary 启动任务
+// @Tags task
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Idempotency-Key header string false "幂等键（24 小时内重复请求返回第一次创建的任务）"
+// @Param request body dto.StartTaskRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/start [post]
 func (h *TaskHandler) StartTask(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 
@@ -41,39 +58,48 @@ func (h *TaskHandler) StartTask(c *gin.Context) {
 		return
 	}
 
-	// 设置默认值
-	if req.BatchSize == 0 {
-		req.BatchSize = 16
-	}
-	if req.MaxConcurrent == 0 {
-		req.MaxConcurrent = 5
-	}
-	if req.MinScore == 0 {
-		req.MinScore = 10
-	}
-	if req.VariantsPerSample == 0 {
-		req.VariantsPerSample = 3
-	}
-	if req.DataRounds == 0 {
-		req.DataRounds = 3
-	}
-	if req.RetryTimes == 0 {
-		req.RetryTimes = 3
-	}
-	if req.TaskType == "" {
-		req.TaskType = "general"
+	// 请求头中的幂等键优先于请求体
+	if key := c.GetHeader("Idempotency-Key"); key != "" {
+		if len(key) > 128 {
+			utils.BadRequest(c, "Idempotency-Key 不能超过 128 个字符")
+			return
+		}
+		req.IdempotencyKey = key
 	}
 
+	// 设置默认值
+	req.ApplyDefaults()
+
 	resp, err := h.taskManager.StartTask(userID, &req)
 	if err != nil {
+		if errors.Is(err, service.ErrIdempotencyKeyInUse) {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
 		utils.InternalError(c, err.Error())
 		return
 	}
 
+	if resp.Duplicate {
+		utils.SuccessWithMessage(c, "任务已存在", resp)
+		return
+	}
 	utils.SuccessWithMessage(c, "任务已启动", resp)
 }
 
 // GetProgress 获取任务进度(SSE)
+// 事件带 id 行，断线重连时携带 Last-Event-ID 请求头（或 last_event_id 查询参数）只回放该事件之后的事件
+// @Summary 获取任务进度(SSE)
+// @Tags task
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Param history_limit query string false "回放的历史事件条数"
+// @Param Last-Event-ID header string false "客户端收到的最后一个事件ID"
+// @Param last_event_id query string false "同 Last-Event-ID 请求头"
+// @Success 200 {string} string "SSE 事件流"
+// @Failure 404 {object} utils.Response
+// @Router /api/progress/{task_id} [get]
 func (h *TaskHandler) GetProgress(c *gin.Context) {
 	taskID := c.Param("task_id")
 
@@ -101,17 +127,55 @@ func (h *TaskHandler) GetProgress(c *gin.Context) {
 	fmt.Fprintf(c.Writer, "data: %s\n\n", string(initData))
 	c.Writer.Flush()
 
-	// 先发送历史事件
+	// 历史事件只回放最后 N 条（history_limit 查询参数，0 表示全部），其余提示下载完整日志
 	finishedInHistory := false
 	for _, event := range history {
-		data, _ := json.Marshal(event)
-		fmt.Fprintf(c.Writer, "data: %s\n\n", string(data))
-		c.Writer.Flush()
 		if event.Type == "finished" {
 			finishedInHistory = true
 		}
 	}
 
+	// 断线重连：跳过客户端已收到的事件（ID 超过最新事件说明任务上下文已重建，按新连接处理）
+	lastEventID := parseLastEventID(c)
+	if len(history) > 0 && lastEventID > history[len(history)-1].ID {
+		lastEventID = 0
+	}
+	if lastEventID > 0 {
+		resumed := make([]*dto.ProgressEvent, 0, len(history))
+		for _, event := range history {
+			if event.ID > lastEventID {
+				resumed = append(resumed, event)
+			}
+		}
+		history = resumed
+	}
+
+	limit := h.taskManager.DefaultHistoryLimit()
+	if v, err := strconv.Atoi(c.Query("history_limit")); err == nil && v >= 0 {
+		limit = v
+	}
+	if limit > 0 && len(history) > limit {
+		omitted := len(history) - limit
+		history = history[omitted:]
+		marker := &dto.ProgressEvent{
+			Type:    "history_omitted",
+			Message: fmt.Sprintf("已省略 %d 条较早的事件，请下载完整日志查看", omitted),
+			Omitted: omitted,
+			LogURL:  fmt.Sprintf("/api/progress/%s/log", url.PathEscape(taskID)),
+		}
+		data, _ := json.Marshal(marker)
+		fmt.Fprintf(c.Writer, "data: %s\n\n", string(data))
+	}
+
+	// 先发送历史事件
+	for _, event := range history {
+		writeSSEEvent(c.Writer, event)
+		if event.ID > lastEventID {
+			lastEventID = event.ID
+		}
+	}
+	c.Writer.Flush()
+
 	// 如果历史事件中已经包含 finished，直接返回
 	if finishedInHistory {
 		log.Printf("[GetProgress] 任务 %s 已完成（历史事件中包含 finished）", taskID)
@@ -133,9 +197,15 @@ func (h *TaskHandler) GetProgress(c *gin.Context) {
 				log.Printf("[GetProgress] 进度通道已关闭: %s", taskID)
 				return
 			}
-			data, _ := json.Marshal(event)
-			fmt.Fprintf(c.Writer, "data: %s\n\n", string(data))
+			// 订阅后、读取历史前产生的事件会同时出现在历史和通道中，按ID跳过已发送的事件
+			if event.ID > 0 && event.ID <= lastEventID {
+				continue
+			}
+			writeSSEEvent(c.Writer, event)
 			c.Writer.Flush()
+			if event.ID > lastEventID {
+				lastEventID = event.ID
+			}
 
 			if event.Type == "finished" {
 				return
@@ -144,7 +214,155 @@ func (h *TaskHandler) GetProgress(c *gin.Context) {
 	}
 }
 
+// parseLastEventID 读取客户端收到的最后一个事件ID（Last-Event-ID 请求头优先，其次 last_event_id 查询参数）
+func parseLastEventID(c *gin.Context) int64 {
+	raw := c.GetHeader("Last-Event-ID")
+	if raw == "" {
+		raw = c.Query("last_event_id")
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id < 0 {
+		return 0
+	}
+	return id
+}
+
+// writeSSEEvent 写入一条 SSE 事件，带ID的事件先写 id 行
+func writeSSEEvent(w io.Writer, event *dto.ProgressEvent) {
+	data, _ := json.Marshal(event)
+	if event.ID > 0 {
+		fmt.Fprintf(w, "id: %d\n", event.ID)
+	}
+	fmt.Fprintf(w, "data: %s\n\n", string(data))
+}
+
+// DownloadEventLog 下载任务的完整事件日志（每行一个JSON事件）
+// @Summary 下载任务的完整事件日志（每行一个JSON事件）
+// @Tags task
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {file} file
+// @Failure 404 {object} utils.Response
+// @Router /api/progress/{task_id}/log [get]
+func (h *TaskHandler) DownloadEventLog(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	taskID := c.Param("task_id")
+
+	events, err := h.taskManager.GetEventLog(taskID, userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	var buf bytes.Buffer
+	for _, event := range events {
+		data, _ := json.Marshal(event)
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	filename := taskID + "_events.jsonl"
+	c.Header("Content-Disposition", "attachment; filename*=UTF-8''"+url.PathEscape(filename))
+	c.Data(200, "application/x-ndjson; charset=utf-8", buf.Bytes())
+}
+
+// GetTaskLogs 分页查看任务的完整事件日志（包括 SSE 未推送的输出行）
+// @Summary 分页查看任务的完整事件日志（包括 SSE 未推送的输出行）
+// @Tags task
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Param offset query string false "偏移量（默认 0）"
+// @Param limit query string false "返回条数上限"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{task_id}/logs [get]
+func (h *TaskHandler) GetTaskLogs(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	taskID := c.Param("task_id")
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		utils.BadRequest(c, "offset 必须是非负整数")
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	page, err := h.taskManager.GetTaskLogs(taskID, userID, offset, limit)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+	utils.SuccessResponse(c, page)
+}
+
+// DownloadTaskLog 下载任务运行时工作进程完整的标准输出和错误输出（文本文件）
+// @Summary 下载任务运行时工作进程完整的标准输出和错误输出（文本文件）
+// @Tags task
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {file} file
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{task_id}/logs/download [get]
+func (h *TaskHandler) DownloadTaskLog(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	taskID := c.Param("task_id")
+
+	path, err := h.taskManager.GetTaskOutputLogPath(taskID, userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	filename := taskID + "_output.log"
+	c.Header("Content-Disposition", "attachment; filename*=UTF-8''"+url.PathEscape(filename))
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.File(path)
+}
+
+// RerunTask 以已结束任务的参数重新运行（可覆盖模型和轮数）
+// @Summary 以已结束任务的参数重新运行（可覆盖模型和轮数）
+// @Tags task
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Param request body dto.RerunTaskRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/tasks/{task_id}/rerun [post]
+func (h *TaskHandler) RerunTask(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	taskID := c.Param("task_id")
+
+	// 请求体可为空（完全沿用原任务参数）
+	var req dto.RerunTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.taskManager.RerunTask(userID, taskID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "任务已重新启动", resp)
+}
+
 // StopTask 停止任务
+// @Summary 停止任务
+// @Tags task
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/stop/{task_id} [post]
 func (h *TaskHandler) StopTask(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 	taskID := c.Param("task_id")
@@ -163,6 +381,14 @@ func (h *TaskHandler) StopTask(c *gin.Context) {
 }
 
 // DeleteTask 删除任务
+// @Summary 删除任务
+// @Tags task
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/task/{task_id} [delete]
 func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	userID, _ := middleware.GetUserID(c)
 	taskID := c.Param("task_id")
@@ -178,8 +404,18 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 }
 
 // GetTaskStatus 获取任务状态
+// Deprecated: 使用 GET /tasks/:task_id/overview
+// @Summary 获取任务状态
+// @Tags task
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/status/{task_id} [get]
 func (h *TaskHandler) GetTaskStatus(c *gin.Context) {
 	taskID := c.Param("task_id")
+	setOverviewSuccessor(c, taskID)
 
 	taskCtx, exists := h.taskManager.GetTask(taskID)
 	if !exists {
@@ -187,45 +423,65 @@ func (h *TaskHandler) GetTaskStatus(c *gin.Context) {
 		return
 	}
 
+	state := taskCtx.State()
 	resp := dto.TaskStatusResponse{
-		TaskID:   taskID,
-		Status:   taskCtx.Status,
-		Finished: taskCtx.Finished,
+		TaskID:     taskID,
+		Status:     string(state.Status),
+		Finished:   state.Finished,
+		ReturnCode: state.ReturnCode,
 	}
 
-	if taskCtx.ReturnCode != nil {
-		resp.ReturnCode = taskCtx.ReturnCode
+	utils.SuccessResponse(c, resp)
+}
+
+// GetOverview 获取任务概览（合并数据库、内存和Redis，替代 /status 和 /progress_unified）
+// @Summary 获取任务概览（合并数据库、内存和Redis，替代 /status 和 /progress_unified）
+// @Tags task
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{task_id}/overview [get]
+func (h *TaskHandler) GetOverview(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	overview, err := h.taskManager.GetOverview(c.Param("task_id"), userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
 	}
 
-	utils.SuccessResponse(c, resp)
+	utils.SuccessResponse(c, overview)
+}
+
+// setOverviewSuccessor 为已废弃的任务状态接口添加 Deprecation 响应头，指向任务概览接口
+func setOverviewSuccessor(c *gin.Context, taskID string) {
+	c.Header("Deprecation", "true")
+	c.Header("Link", fmt.Sprintf("</api/tasks/%s/overview>; rel=\"successor-version\"", url.PathEscape(taskID)))
 }
 
 // GetAllTasks 获取所有任务列表（从内存）
+// @Summary 获取所有任务列表（从内存）
+// @Tags task
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Router /api/tasks [get]
 func (h *TaskHandler) GetAllTasks(c *gin.Context) {
 	tasks := h.taskManager.GetAllTasks()
 
 	taskList := make([]dto.TaskInfo, 0, len(tasks))
 	for _, task := range tasks {
-		runTime := float64(0)
-		if task.EndTime != nil && !task.EndTime.IsZero() {
-			runTime = task.EndTime.Sub(task.StartTime).Seconds()
-		} else {
-			runTime = time.Since(task.StartTime).Seconds()
-		}
-
-		info := dto.TaskInfo{
-			TaskID:   task.TaskID,
-			Status:   task.Status,
-			Params:   task.Params,
-			RunTime:  runTime,
-			Finished: task.Finished,
-		}
-
-		if task.ReturnCode != nil {
-			info.ReturnCode = task.ReturnCode
-		}
-
-		taskList = append(taskList, info)
+		state := task.State()
+		taskList = append(taskList, dto.TaskInfo{
+			TaskID:     task.TaskID,
+			Status:     string(state.Status),
+			Params:     task.Params,
+			RunTime:    state.RunTime(task.StartTime).Seconds(),
+			Finished:   state.Finished,
+			ReturnCode: state.ReturnCode,
+		})
 	}
 
 	utils.SuccessResponse(c, dto.TaskListResponse{
@@ -235,11 +491,17 @@ func (h *TaskHandler) GetAllTasks(c *gin.Context) {
 }
 
 // GetActiveTask 获取运行中的任务（从内存）
+// @Summary 获取运行中的任务（从内存）
+// @Tags task
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Router /api/active_task [get]
 func (h *TaskHandler) GetActiveTask(c *gin.Context) {
 	tasks := h.taskManager.GetAllTasks()
 
 	for _, task := range tasks {
-		if !task.Finished {
+		if !task.Finished() {
 			runTime := time.Since(task.StartTime).Seconds()
 			utils.SuccessResponse(c, gin.H{
 				"success":  true,
@@ -259,64 +521,27 @@ func (h *TaskHandler) GetActiveTask(c *gin.Context) {
 
 // GetProgressUnified 获取任务进度（从Redis）
 // 用于前端轮询显示进度条
+// Deprecated: 使用 GET /tasks/:task_id/overview 的 live 字段
+// @Summary 获取任务进度（从Redis）
+// @Tags task
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/progress_unified/{task_id} [get]
 func (h *TaskHandler) GetProgressUnified(c *gin.Context) {
 	taskID := c.Param("task_id")
+	setOverviewSuccessor(c, taskID)
 
-	// 从Redis读取进度
-	ctx := context.Background()
-	redisKey := "task_progress:" + taskID
-
-	// 先尝试从Redis Hash中读取（支持字符数统计）
-	hashData, hashErr := h.redisClient.HGetAll(ctx, redisKey).Result()
-
-	if hashErr == nil && len(hashData) > 0 {
-		// Hash数据存在，使用Hash数据
-		progressData := make(map[string]interface{})
-
-		// 转换所有字段
-		for key, val := range hashData {
-			// 尝试解析为JSON值
-			var jsonVal interface{}
-			if err := json.Unmarshal([]byte(val), &jsonVal); err == nil {
-				progressData[key] = jsonVal
-			} else {
-				// 如果不是JSON，尝试解析为数字
-				if intVal, err := strconv.ParseInt(val, 10, 64); err == nil {
-					progressData[key] = intVal
-				} else {
-					// 否则作为字符串
-					progressData[key] = val
-				}
-			}
-		}
-
-		// 确保有task_id字段
-		if _, ok := progressData["task_id"]; !ok {
-			progressData["task_id"] = taskID
-		}
-
-		// 计算进度百分比
-		progressPercent := 0.0
-		if cp, ok := progressData["completion_percent"].(float64); ok {
-			progressPercent = cp
-		} else {
-			// 回退：使用轮次计算进度
-			if totalRounds, ok := progressData["total_rounds"].(float64); ok && totalRounds > 0 {
-				if currentRound, ok := progressData["current_round"].(float64); ok {
-					progressPercent = (currentRound / totalRounds) * 100
-				}
-			}
-		}
-
-		// 确保进度不超过100%
-		if progressPercent > 100 {
-			progressPercent = 100
-		}
-
-		// 添加进度百分比到响应
-		progressData["progress_percent"] = progressPercent
-		progressData["source"] = "redis"
-
+	progressData, err := h.taskManager.LoadStoredProgress(context.Background(), taskID)
+	if err != nil {
+		log.Printf("[GetProgressUnified] %v", err)
+		utils.InternalError(c, err.Error())
+		return
+	}
+	if progressData != nil {
 		utils.SuccessResponse(c, gin.H{
 			"success":  true,
 			"progress": progressData,
@@ -324,89 +549,43 @@ func (h *TaskHandler) GetProgressUnified(c *gin.Context) {
 		return
 	}
 
-	// 如果Hash不存在，尝试读取字符串格式（兼容旧版本）
-	val, err := h.redisClient.Get(ctx, redisKey).Result()
-	if err != nil {
-		if err == redis.Nil {
-			// Redis中没有进度数据，检查任务是否在内存中
-			taskCtx, exists := h.taskManager.GetTask(taskID)
-			if exists {
-				// 任务在内存中，返回基本信息
-				runTime := time.Since(taskCtx.StartTime).Seconds()
-				// 确定status字段：将Go的状态转换为前端期望的格式
-				status := "running"
-				if taskCtx.Finished {
-					if taskCtx.ReturnCode != nil && *taskCtx.ReturnCode == 0 {
-						status = "completed"
-					} else {
-						status = "failed"
-					}
-				}
-
-				// 从params中获取total_rounds
-				totalRounds := int64(3)
-				if tr, ok := taskCtx.Params["total_rounds"].(float64); ok {
-					totalRounds = int64(tr)
-				}
-
-				utils.SuccessResponse(c, gin.H{
-					"success": true,
-					"progress": gin.H{
-						"task_id":          taskID,
-						"status":           status,
-						"current_round":    0,
-						"total_rounds":     totalRounds,
-						"total_samples":    0,
-						"generated_count":  0,
-						"progress_percent": float64(0),
-						"run_time":         runTime,
-						"source":           "memory",
-					},
-				})
-				return
-			}
-			// 任务不存在
-			utils.NotFound(c, "任务不存在")
-			return
-		}
-		log.Printf("[GetProgressUnified] Redis错误: %v", err)
-		utils.InternalError(c, "读取进度失败")
-		return
-	}
-
-	// 解析JSON（字符串格式）
-	var progressData map[string]interface{}
-	if err := json.Unmarshal([]byte(val), &progressData); err != nil {
-		log.Printf("[GetProgressUnified] 解析进度数据失败: %v", err)
-		utils.InternalError(c, "解析进度数据失败")
+	// Redis中没有进度数据，检查任务是否在内存中
+	taskCtx, exists := h.taskManager.GetTask(taskID)
+	if !exists {
+		utils.NotFound(c, "任务不存在")
 		return
 	}
 
-	// 计算进度百分比
-	// 优先使用 Python 计算的 completion_percent 字段（基于轮次完成比例，更准确）
-	progressPercent := 0.0
-	if cp, ok := progressData["completion_percent"].(float64); ok {
-		progressPercent = cp
-	} else {
-		// 回退：使用轮次计算进度
-		if totalRounds, ok := progressData["total_rounds"].(float64); ok && totalRounds > 0 {
-			if currentRound, ok := progressData["current_round"].(float64); ok {
-				progressPercent = (currentRound / totalRounds) * 100
-			}
+	// 任务在内存中，返回基本信息
+	runTime := time.Since(taskCtx.StartTime).Seconds()
+	// 确定status字段：将Go的状态转换为前端期望的格式
+	status := "running"
+	if state := taskCtx.State(); state.Finished {
+		if state.ReturnCode != nil && *state.ReturnCode == 0 {
+			status = "completed"
+		} else {
+			status = "failed"
 		}
 	}
 
-	// 确保进度不超过100%
-	if progressPercent > 100 {
-		progressPercent = 100
+	// 从params中获取total_rounds
+	totalRounds := int64(3)
+	if tr, ok := taskCtx.Params["total_rounds"].(float64); ok {
+		totalRounds = int64(tr)
 	}
 
-	// 添加进度百分比到响应
-	progressData["progress_percent"] = progressPercent
-	progressData["source"] = "redis"
-
 	utils.SuccessResponse(c, gin.H{
-		"success":  true,
-		"progress": progressData,
+		"success": true,
+		"progress": gin.H{
+			"task_id":          taskID,
+			"status":           status,
+			"current_round":    0,
+			"total_rounds":     totalRounds,
+			"total_samples":    0,
+			"generated_count":  0,
+			"progress_percent": float64(0),
+			"run_time":         runTime,
+			"source":           "memory",
+		},
 	})
 }
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"strconv"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/models"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TaskTypeHandler 任务类型处理器
+type TaskTypeHandler struct {
+	taskTypeService *service.TaskTypeService
+	auditLogService *service.AuditLogService
+}
+
+// NewTaskTypeHandler 创建任务类型处理器
+func NewTaskTypeHandler(taskTypeService *service.TaskTypeService, auditLogService *service.AuditLogService) *TaskTypeHandler {
+	return &TaskTypeHandler{
+		taskTypeService: taskTypeService,
+		auditLogService: auditLogService,
+	}
+}
+
+// ListTaskTypes 获取可用的任务类型列表（types 为名称列表，task_types 为详细信息）
+// @Summary 获取可用的任务类型列表（types 为名称列表，task_types 为详细信息）
+// @Tags task_type
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/task_types [get]
+func (h *TaskTypeHandler) ListTaskTypes(c *gin.Context) {
+	taskTypes, err := h.taskTypeService.List(true)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	names := make([]string, len(taskTypes))
+	for i, taskType := range taskTypes {
+		names[i] = taskType.Name
+	}
+
+	utils.SuccessResponse(c, gin.H{
+		"success":    true,
+		"types":      names,
+		"task_types": taskTypes,
+	})
+}
+
+// AdminListTaskTypes 获取全部任务类型（管理员，包括停用的类型）
+// @Summary 获取全部任务类型（管理员，包括停用的类型）
+// @Tags task_type
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/admin/task_types [get]
+func (h *TaskTypeHandler) AdminListTaskTypes(c *gin.Context) {
+	taskTypes, err := h.taskTypeService.List(false)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, taskTypes)
+}
+
+// GetTaskType 获取任务类型详情（管理员）
+// @Summary 获取任务类型详情（管理员）
+// @Tags task_type
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/admin/task_types/{id} [get]
+func (h *TaskTypeHandler) GetTaskType(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的任务类型ID")
+		return
+	}
+
+	taskType, err := h.taskTypeService.Get(uint(id))
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, taskType)
+}
+
+// CreateTaskType 创建任务类型（管理员）
+// @Summary 创建任务类型（管理员）
+// @Tags task_type
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateTaskTypeRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/admin/task_types [post]
+func (h *TaskTypeHandler) CreateTaskType(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.CreateTaskTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	taskType, err := h.taskTypeService.Create(userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionTaskTypeCreate, models.AuditResourceTaskType, taskType.ID, nil, taskType))
+	utils.SuccessWithMessage(c, "任务类型创建成功", taskType)
+}
+
+// UpdateTaskType 更新任务类型（管理员）
+// @Summary 更新任务类型（管理员）
+// @Tags task_type
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param request body dto.UpdateTaskTypeRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/admin/task_types/{id} [put]
+func (h *TaskTypeHandler) UpdateTaskType(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的任务类型ID")
+		return
+	}
+
+	var req dto.UpdateTaskTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	before, err := h.taskTypeService.Get(uint(id))
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	taskType, err := h.taskTypeService.Update(uint(id), &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionTaskTypeUpdate, models.AuditResourceTaskType, id, before, taskType))
+	utils.SuccessWithMessage(c, "任务类型更新成功", taskType)
+}
+
+// DeleteTaskType 删除任务类型及其专属的评分规则（管理员，内置类型不可删除）
+// @Summary 删除任务类型及其专属的评分规则（管理员，内置类型不可删除）
+// @Tags task_type
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/admin/task_types/{id} [delete]
+func (h *TaskTypeHandler) DeleteTaskType(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的任务类型ID")
+		return
+	}
+
+	before, err := h.taskTypeService.Get(uint(id))
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	if err := h.taskTypeService.Delete(uint(id)); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	h.auditLogService.Record(newAuditLog(c, models.AuditActionTaskTypeDelete, models.AuditResourceTaskType, id, before, nil))
+	utils.SuccessWithMessage(c, "任务类型已删除", gin.H{"success": true})
+}
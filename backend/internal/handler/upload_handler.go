@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UploadHandler 分片上传和批量上传处理器
+type UploadHandler struct {
+	uploadService     *service.ChunkedUploadService
+	bulkUploadService *service.BulkUploadService
+	dataFileService   *service.DataFileService
+}
+
+// NewUploadHandler 创建上传处理器
+func NewUploadHandler(uploadService *service.ChunkedUploadService, bulkUploadService *service.BulkUploadService, dataFileService *service.DataFileService) *UploadHandler {
+	return &UploadHandler{
+		uploadService:     uploadService,
+		bulkUploadService: bulkUploadService,
+		dataFileService:   dataFileService,
+	}
+}
+
+// InitUpload 初始化分片上传
+// @Summary 初始化分片上传
+// @Tags upload
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.InitUploadRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/data_files/upload/init [post]
+func (h *UploadHandler) InitUpload(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.InitUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.uploadService.InitUpload(userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, resp)
+}
+
+// UploadChunk 上传分片（请求体为分片原始字节，index 通过查询参数传递）
+// @Summary 上传分片（请求体为分片原始字节，index 通过查询参数传递）
+// @Tags upload
+// @Produce json
+// @Security BearerAuth
+// @Param upload_id path string true "上传会话ID"
+// @Param index query string false "分片序号"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/data_files/upload/{upload_id}/chunk [put]
+func (h *UploadHandler) UploadChunk(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	uploadID := c.Param("upload_id")
+
+	index, err := strconv.Atoi(c.Query("index"))
+	if err != nil {
+		utils.BadRequest(c, "无效的分片索引")
+		return
+	}
+
+	resp, err := h.uploadService.UploadChunk(userID, uploadID, index, c.Request.Body, c.GetHeader("X-Chunk-SHA256"))
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, resp)
+}
+
+// GetUploadStatus 获取分片上传状态
+// @Summary 获取分片上传状态
+// @Tags upload
+// @Produce json
+// @Security BearerAuth
+// @Param upload_id path string true "上传会话ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/data_files/upload/{upload_id} [get]
+func (h *UploadHandler) GetUploadStatus(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	uploadID := c.Param("upload_id")
+
+	resp, err := h.uploadService.GetUploadStatus(userID, uploadID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, resp)
+}
+
+// CompleteUpload 完成分片上传
+// @Summary 完成分片上传
+// @Tags upload
+// @Produce json
+// @Security BearerAuth
+// @Param upload_id path string true "上传会话ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Failure 503 {object} utils.Response
+// @Router /api/data_files/upload/{upload_id}/complete [post]
+func (h *UploadHandler) CompleteUpload(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	uploadID := c.Param("upload_id")
+
+	dataFile, report, err := h.uploadService.CompleteUpload(userID, uploadID)
+	if err != nil {
+		if errors.Is(err, service.ErrValidationRejected) {
+			respondValidationRejected(c, err, report)
+			return
+		}
+		if errors.Is(err, service.ErrUploadInfected) {
+			utils.ErrorResponse(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrJobQueueFull) || errors.Is(err, service.ErrVirusScanUnavailable) {
+			utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "文件上传成功", gin.H{
+		"id":           dataFile.ID,
+		"filename":     dataFile.Filename,
+		"display_path": h.dataFileService.GetFileDisplayPath(dataFile.ID, dataFile.Filename),
+		"file_size":    dataFile.FileSize,
+		"validation":   report,
+	})
+}
+
+// BulkUpload 批量上传多个文件或压缩包（.zip/.tar.gz 在服务端解压），每个文件单独登记为数据文件并返回逐个文件的结果
+// @Summary 批量上传多个文件或压缩包（.zip/.tar.gz 在服务端解压），每个文件单独登记为数据文件并返回逐个文件的结果
+// @Tags upload
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param files[] formData file true "上传的文件（可重复，支持 .zip/.tar.gz 压缩包）"
+// @Param validation_mode formData string false "结构校验模式"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 413 {object} utils.Response
+// @Router /api/data_files/upload/batch [post]
+func (h *UploadHandler) BulkUpload(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	resp, err := h.bulkUploadService.Upload(userID, middleware.BulkUploadFiles(c), c.PostForm("validation_mode"))
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, fmt.Sprintf("批量上传完成：成功 %d 个，失败 %d 个", resp.Succeeded, resp.Failed), resp)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserSettingsHandler 用户个人设置处理器
+type UserSettingsHandler struct {
+	settingsService *service.UserSettingsService
+}
+
+// NewUserSettingsHandler 创建用户个人设置处理器
+func NewUserSettingsHandler(settingsService *service.UserSettingsService) *UserSettingsHandler {
+	return &UserSettingsHandler{
+		settingsService: settingsService,
+	}
+}
+
+// GetSettings 获取当前用户的个人设置
+// @Summary 获取个人设置（默认模型、批大小、轮数、导出格式和界面选项）
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=dto.UserSettingsResponse}
+// @Failure 500 {object} utils.Response
+// @Router /api/me/settings [get]
+func (h *UserSettingsHandler) GetSettings(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	settings, err := h.settingsService.GetSettings(userID)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, settings)
+}
+
+// UpdateSettings 更新当前用户的个人设置
+// @Summary 更新个人设置（只更新提供的字段）
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.UpdateUserSettingsRequest true "个人设置"
+// @Success 200 {object} utils.Response{data=dto.UserSettingsResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/me/settings [put]
+func (h *UserSettingsHandler) UpdateSettings(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.UpdateUserSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	settings, err := h.settingsService.UpdateSettings(userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "个人设置已更新", settings)
+}
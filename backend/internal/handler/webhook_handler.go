@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"strconv"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler Webhook处理器
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+// NewWebhookHandler 创建Webhook处理器
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhook 注册Webhook（签名密钥仅在创建时返回一次）
+// @Summary 注册Webhook（签名密钥仅在创建时返回一次）
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateWebhookRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Webhook创建成功", webhook)
+}
+
+// ListWebhooks 获取当前用户的Webhook列表
+// @Summary 获取当前用户的Webhook列表
+// @Tags webhook
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	webhooks, err := h.webhookService.ListWebhooks(userID)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, webhooks)
+}
+
+// UpdateWebhook 更新Webhook
+// @Summary 更新Webhook
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param request body dto.UpdateWebhookRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的Webhook ID")
+		return
+	}
+
+	var req dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(uint(id), userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Webhook更新成功", webhook)
+}
+
+// DeleteWebhook 删除Webhook
+// @Summary 删除Webhook
+// @Tags webhook
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的Webhook ID")
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(uint(id), userID); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Webhook已删除", gin.H{"success": true})
+}
+
+// ListDeliveries 分页获取Webhook的投递记录
+// @Summary 分页获取Webhook的投递记录
+// @Tags webhook
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param page query string false "页码（默认 1）"
+// @Param per_page query string false "每页条数（默认 20）"
+// @Success 200 {object} utils.PaginationResponse
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的Webhook ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	result, err := h.webhookService.ListDeliveries(uint(id), userID, page, perPage)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.PaginatedResponse(c, result.Items, result.Total, result.Page, result.PerPage)
+}
+
+// Redeliver 重新投递指定的记录
+// @Summary 重新投递指定的记录
+// @Tags webhook
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param delivery_id path integer true "投递记录ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的Webhook ID")
+		return
+	}
+	deliveryID, err := strconv.ParseUint(c.Param("delivery_id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的投递记录ID")
+		return
+	}
+
+	delivery, err := h.webhookService.Redeliver(uint(id), uint(deliveryID), userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, delivery)
+}
@@ -0,0 +1,347 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/middleware"
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WorkspaceHandler 工作区处理器
+type WorkspaceHandler struct {
+	workspaceService *service.WorkspaceService
+}
+
+// NewWorkspaceHandler 创建工作区处理器
+func NewWorkspaceHandler(workspaceService *service.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		workspaceService: workspaceService,
+	}
+}
+
+// CreateWorkspace 创建工作区
+// @Summary 创建工作区
+// @Tags workspace
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateWorkspaceRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/workspaces [post]
+func (h *WorkspaceHandler) CreateWorkspace(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	workspace, err := h.workspaceService.CreateWorkspace(userID, &req)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "工作区创建成功", workspace)
+}
+
+// ListWorkspaces 获取当前用户所在的工作区
+// @Summary 获取当前用户所在的工作区
+// @Tags workspace
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/workspaces [get]
+func (h *WorkspaceHandler) ListWorkspaces(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	workspaces, err := h.workspaceService.ListWorkspaces(userID)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, workspaces)
+}
+
+// GetWorkspace 获取工作区详情（含成员）
+// @Summary 获取工作区详情（含成员）
+// @Tags workspace
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/workspaces/{id} [get]
+func (h *WorkspaceHandler) GetWorkspace(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的工作区ID")
+		return
+	}
+
+	workspace, err := h.workspaceService.GetWorkspace(uint(id), userID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, workspace)
+}
+
+// UpdateWorkspace 修改工作区名称和描述
+// @Summary 修改工作区名称和描述
+// @Tags workspace
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param request body dto.UpdateWorkspaceRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/workspaces/{id} [put]
+func (h *WorkspaceHandler) UpdateWorkspace(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的工作区ID")
+		return
+	}
+
+	var req dto.UpdateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	workspace, err := h.workspaceService.UpdateWorkspace(uint(id), userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "工作区更新成功", workspace)
+}
+
+// DeleteWorkspace 删除工作区
+// @Summary 删除工作区
+// @Tags workspace
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/workspaces/{id} [delete]
+func (h *WorkspaceHandler) DeleteWorkspace(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的工作区ID")
+		return
+	}
+
+	if err := h.workspaceService.DeleteWorkspace(uint(id), userID); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "工作区已删除", gin.H{"success": true})
+}
+
+// AddMember 添加工作区成员
+// @Summary 添加工作区成员
+// @Tags workspace
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param request body dto.AddWorkspaceMemberRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/workspaces/{id}/members [post]
+func (h *WorkspaceHandler) AddMember(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的工作区ID")
+		return
+	}
+
+	var req dto.AddWorkspaceMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	workspace, err := h.workspaceService.AddMember(uint(id), userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "成员已添加", workspace)
+}
+
+// UpdateMember 修改工作区成员角色
+// @Summary 修改工作区成员角色
+// @Tags workspace
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param user_id path integer true "用户ID"
+// @Param request body dto.UpdateWorkspaceMemberRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/workspaces/{id}/members/{user_id} [put]
+func (h *WorkspaceHandler) UpdateMember(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的工作区ID")
+		return
+	}
+	memberUserID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的用户ID")
+		return
+	}
+
+	var req dto.UpdateWorkspaceMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	workspace, err := h.workspaceService.UpdateMemberRole(uint(id), userID, uint(memberUserID), req.Role)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "成员角色已更新", workspace)
+}
+
+// RemoveMember 移除工作区成员（成员也可以移除自己以退出工作区）
+// @Summary 移除工作区成员（成员也可以移除自己以退出工作区）
+// @Tags workspace
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "ID"
+// @Param user_id path integer true "用户ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/workspaces/{id}/members/{user_id} [delete]
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的工作区ID")
+		return
+	}
+	memberUserID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的用户ID")
+		return
+	}
+
+	if err := h.workspaceService.RemoveMember(uint(id), userID, uint(memberUserID)); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "成员已移除", gin.H{"success": true})
+}
+
+// MoveFile 将数据文件移入或移出工作区
+// @Summary 将数据文件移入或移出工作区
+// @Tags workspace
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param file_id path integer true "文件ID"
+// @Param request body dto.MoveToWorkspaceRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/data_files/{file_id}/workspace [put]
+func (h *WorkspaceHandler) MoveFile(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	fileID, err := strconv.ParseUint(c.Param("file_id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的文件ID")
+		return
+	}
+
+	var req dto.MoveToWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.workspaceService.MoveFile(uint(fileID), userID, req.WorkspaceID); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "文件所属工作区已更新", gin.H{"success": true, "workspace_id": req.WorkspaceID})
+}
+
+// MoveTask 将任务（连同生成数据）移入或移出工作区
+// @Summary 将任务（连同生成数据）移入或移出工作区
+// @Tags workspace
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "任务ID"
+// @Param request body dto.MoveToWorkspaceRequest true "请求参数"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/tasks/{task_id}/workspace [put]
+func (h *WorkspaceHandler) MoveTask(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req dto.MoveToWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.workspaceService.MoveTask(c.Param("task_id"), userID, req.WorkspaceID); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "任务所属工作区已更新", gin.H{"success": true, "workspace_id": req.WorkspaceID})
+}
+
+// parseWorkspaceQuery 解析列表接口的 workspace_id 查询参数（未传时返回 nil，表示不按工作区过滤）
+func parseWorkspaceQuery(c *gin.Context) (*uint, error) {
+	value := c.Query("workspace_id")
+	if value == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 workspace_id 参数")
+	}
+	workspaceID := uint(id)
+	return &workspaceID, nil
+}
@@ -2,14 +2,23 @@ package middleware
 
 import (
 	"strings"
+	"time"
 
 	"gen-go/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+// TokenRevocationChecker 访问Token黑名单查询
+type TokenRevocationChecker interface {
+	IsAccessTokenRevoked(tokenID string) bool
+	// IsIssuedBeforeRevocation 访问Token是否签发于用户吊销全部Token（修改或重置密码）之前
+	IsIssuedBeforeRevocation(userID uint, issuedAt time.Time) bool
+}
+
 // AuthMiddleware JWT认证中间件
-func AuthMiddleware(jwtManager *utils.JWTManager) gin.HandlerFunc {
+// 不带 jti 的旧版Token无法吊销，一律要求重新登录；修改或重置密码前签发的Token同样失效
+func AuthMiddleware(jwtManager *utils.JWTManager, revocation TokenRevocationChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 获取Token
 		authHeader := c.GetHeader("Authorization")
@@ -36,11 +45,24 @@ func AuthMiddleware(jwtManager *utils.JWTManager) gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		var issuedAt time.Time
+		if claims.IssuedAt != nil {
+			issuedAt = claims.IssuedAt.Time
+		}
+		if claims.ID == "" || revocation.IsAccessTokenRevoked(claims.ID) || revocation.IsIssuedBeforeRevocation(claims.UserID, issuedAt) {
+			utils.Unauthorized(c, "Token已失效，请重新登录")
+			c.Abort()
+			return
+		}
 
 		// 将用户信息存入上下文
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("is_admin", claims.IsAdmin)
+		c.Set("token_id", claims.ID)
+		if claims.ExpiresAt != nil {
+			c.Set("token_expires_at", claims.ExpiresAt.Time)
+		}
 
 		c.Next()
 	}
@@ -55,6 +77,13 @@ func GetUserID(c *gin.Context) (uint, bool) {
 	return userID.(uint), true
 }
 
+// GetTokenInfo 从上下文获取当前访问Token的 jti 与过期时间
+func GetTokenInfo(c *gin.Context) (string, time.Time) {
+	tokenID := c.GetString("token_id")
+	expiresAt := c.GetTime("token_expires_at")
+	return tokenID, expiresAt
+}
+
 // GetUsername 从上下文获取用户名
 func GetUsername(c *gin.Context) (string, bool) {
 	username, exists := c.Get("username")
@@ -9,7 +9,8 @@ import (
 // CORS 跨域中间件
 func CORS(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		origins := cfg.CORS.Origins
+		cors := cfg.Current().CORS
+		origins := cors.Origins
 		origin := c.Request.Header.Get("Origin")
 
 		// 检查origin是否在允许列表中
@@ -25,16 +26,16 @@ func CORS(cfg *config.Config) gin.HandlerFunc {
 			c.Header("Access-Control-Allow-Origin", origin)
 		}
 
-		if cfg.CORS.AllowCredentials {
+		if cors.AllowCredentials {
 			c.Header("Access-Control-Allow-Credentials", "true")
 		}
 
-		methods := cfg.CORS.AllowMethods
+		methods := cors.AllowMethods
 		if len(methods) > 0 {
 			c.Header("Access-Control-Allow-Methods", joinStrings(methods, ", "))
 		}
 
-		headers := cfg.CORS.AllowHeaders
+		headers := cors.AllowHeaders
 		if len(headers) > 0 {
 			c.Header("Access-Control-Allow-Headers", joinStrings(headers, ", "))
 		}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"gen-go/internal/service"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorTrackingMiddleware 记录 5xx 响应到错误统计（按路由模板聚合）
+func ErrorTrackingMiddleware(tracker *service.ErrorTracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < 500 {
+			return
+		}
+
+		message := c.GetString(utils.ErrorMessageKey)
+		if message == "" && len(c.Errors) > 0 {
+			message = c.Errors.Last().Error()
+		}
+		tracker.RecordHTTP(c.Request.Method, c.FullPath(), status, message)
+	}
+}
@@ -1,8 +1,6 @@
 package middleware
 
 import (
-	"os"
-
 	"gen-go/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -12,12 +10,8 @@ import (
 // 用于Python子进程调用Go后端API，使用内部密钥认证
 func InternalAPIAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 从环境变量或配置获取内部API密钥
-		internalKey := os.Getenv("INTERNAL_API_KEY")
-		if internalKey == "" {
-			// 默认密钥（生产环境应该从环境变量设置）
-			internalKey = "gen-internal-api-key-2024"
-		}
+		// 从环境变量获取内部API密钥
+		internalKey := utils.InternalAPIKey()
 
 		// 获取请求头中的密钥
 		requestKey := c.GetHeader("X-Internal-API-Key")
@@ -3,6 +3,8 @@ package middleware
 import (
 	"time"
 
+	"gen-go/internal/tracing"
+
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
@@ -36,6 +38,9 @@ func LoggerMiddleware(logger *logrus.Logger) gin.HandlerFunc {
 		if exists {
 			entry = entry.WithField("user_id", userID)
 		}
+		if traceID := tracing.TraceID(c.Request.Context()); traceID != "" {
+			entry = entry.WithField("trace_id", traceID)
+		}
 
 		if c.Writer.Status() >= 500 {
 			entry.Error("HTTP Request")
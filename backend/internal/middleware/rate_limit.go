@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/utils"
+	"gen-go/pkg/redis_limiter"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitMiddleware 按接口类别限流（需在 AuthMiddleware 之后使用，没有登录用户的请求只按 IP 计数）
+// 用户和 IP 的令牌桶都要取到令牌才放行，超过限制返回 429 和 Retry-After；
+// 响应头 X-RateLimit-* 给出剩余额度较少的那个桶。limiter 为 nil（未配置 Redis）或 Redis 出错时放行
+func RateLimitMiddleware(limiter *redis_limiter.TokenBucket, cfg *config.Config, route string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rateLimit := cfg.Current().RateLimit
+		if limiter == nil || !rateLimit.Enabled {
+			c.Next()
+			return
+		}
+
+		rule := rateLimit.Rule(route)
+		type bucket struct {
+			key       string
+			perMinute int
+		}
+		var buckets []bucket
+		if userID, ok := GetUserID(c); ok && rule.UserPerMinute > 0 {
+			buckets = append(buckets, bucket{fmt.Sprintf("%s:user:%d", route, userID), rule.UserPerMinute})
+		}
+		if rule.IPPerMinute > 0 {
+			buckets = append(buckets, bucket{fmt.Sprintf("%s:ip:%s", route, c.ClientIP()), rule.IPPerMinute})
+		}
+
+		var tightest *redis_limiter.TokenResult
+		tightestLimit := 0
+		for _, b := range buckets {
+			result, err := limiter.Take(c.Request.Context(), b.key, b.perMinute, rule.Burst)
+			if err != nil {
+				log.Printf("[RateLimit] 限流检查失败，放行请求: %v", err)
+				c.Next()
+				return
+			}
+			if tightest == nil || !result.Allowed || (tightest.Allowed && result.Remaining < tightest.Remaining) {
+				tightest, tightestLimit = result, b.perMinute
+			}
+			if !result.Allowed {
+				break
+			}
+		}
+		if tightest == nil {
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(tightestLimit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(tightest.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(tightest.Reset)))
+		if !tightest.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(tightest.RetryAfter)))
+			utils.ErrorResponse(c, http.StatusTooManyRequests, "请求过于频繁，请稍后重试")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// ceilSeconds 向上取整的秒数
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
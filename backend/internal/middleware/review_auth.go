@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"strings"
+
+	"gen-go/internal/models"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewLinkAuthorizer 审阅 Token 校验
+type ReviewLinkAuthorizer interface {
+	Authorize(token string) (*models.ReviewLink, error)
+}
+
+// ReviewAuthMiddleware 审阅链接认证中间件
+// Token 可放在 Authorization: Bearer 头或 token 查询参数中（便于直接分享链接）
+func ReviewAuthMiddleware(authorizer ReviewLinkAuthorizer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Query("token")
+		if authHeader := c.GetHeader("Authorization"); authHeader != "" {
+			parts := strings.SplitN(authHeader, " ", 2)
+			if len(parts) != 2 || parts[0] != "Bearer" {
+				utils.Unauthorized(c, "无效的认证格式")
+				c.Abort()
+				return
+			}
+			token = parts[1]
+		}
+		if token == "" {
+			utils.Unauthorized(c, "未认证")
+			c.Abort()
+			return
+		}
+
+		link, err := authorizer.Authorize(token)
+		if err != nil {
+			utils.Unauthorized(c, err.Error())
+			c.Abort()
+			return
+		}
+
+		c.Set("review_link", link)
+		c.Next()
+	}
+}
+
+// GetReviewLink 从上下文获取当前审阅链接
+func GetReviewLink(c *gin.Context) *models.ReviewLink {
+	link, exists := c.Get("review_link")
+	if !exists {
+		return nil
+	}
+	return link.(*models.ReviewLink)
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// passwordChangeAllowedPaths 需修改密码时仍可访问的接口
+var passwordChangeAllowedPaths = map[string]bool{
+	"/api/me":          true,
+	"/api/me/password": true,
+	"/api/logout":      true,
+}
+
+// RoleMiddleware 加载当前用户的角色（需在 AuthMiddleware 之后使用）
+// 角色每次请求从数据库读取，管理员调整角色后立即生效，无需用户重新登录
+func RoleMiddleware(userRepo *repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := GetUserID(c)
+		if !ok {
+			utils.Unauthorized(c, "未认证")
+			c.Abort()
+			return
+		}
+
+		user, err := loadCurrentUser(c, userRepo, userID)
+		if err != nil {
+			utils.Unauthorized(c, "用户不存在")
+			c.Abort()
+			return
+		}
+
+		// 需修改密码的用户（初始管理员、被重置密码的用户）只能访问修改密码相关接口
+		if user.MustChangePassword && !passwordChangeAllowedPaths[c.FullPath()] {
+			utils.Forbidden(c, "请先修改密码")
+			c.Abort()
+			return
+		}
+
+		role := user.EffectiveRole()
+		c.Set("role", role)
+		c.Set("is_admin", role == models.RoleAdmin)
+		c.Next()
+	}
+}
+
+// RequirePermission 接口权限校验中间件（需在 RoleMiddleware 之后使用）
+func RequirePermission(permission models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !models.RoleHasPermission(GetRole(c), permission) {
+			utils.Forbidden(c, "当前角色无权执行该操作")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// GetRole 从上下文获取当前用户角色
+func GetRole(c *gin.Context) string {
+	if role, exists := c.Get("role"); exists {
+		if r, ok := role.(string); ok {
+			return r
+		}
+	}
+	if IsAdmin(c) {
+		return models.RoleAdmin
+	}
+	return models.DefaultRole
+}
+
+// loadCurrentUser 加载当前用户，同一请求内只查询一次数据库，结果保存在上下文中供后续中间件复用
+func loadCurrentUser(c *gin.Context, userRepo *repository.UserRepository, userID uint) (*models.User, error) {
+	if user, ok := GetCurrentUser(c); ok && user.ID == userID {
+		return user, nil
+	}
+	user, err := userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	c.Set("current_user", user)
+	return user, nil
+}
+
+// GetCurrentUser 从上下文获取已加载的当前用户（RoleMiddleware 或 TimezoneMiddleware 之后可用）
+func GetCurrentUser(c *gin.Context) (*models.User, bool) {
+	if user, exists := c.Get("current_user"); exists {
+		if u, ok := user.(*models.User); ok && u != nil {
+			return u, true
+		}
+	}
+	return nil, false
+}
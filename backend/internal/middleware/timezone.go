@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"time"
+
+	"gen-go/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimezoneMiddleware 加载当前用户的展示时区（需在 AuthMiddleware 之后使用）
+// 用户未设置或设置无效时使用默认时区；仅影响导出文件名和报告中的展示时间
+// 复用 RoleMiddleware 已加载的用户，不再重复查询
+func TimezoneMiddleware(userRepo *repository.UserRepository, defaultLoc *time.Location) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		loc := defaultLoc
+		if userID, ok := GetUserID(c); ok {
+			if user, err := loadCurrentUser(c, userRepo, userID); err == nil && user.Timezone != "" {
+				if userLoc, err := time.LoadLocation(user.Timezone); err == nil {
+					loc = userLoc
+				}
+			}
+		}
+
+		c.Set("location", loc)
+		c.Next()
+	}
+}
+
+// GetLocation 从上下文获取用户展示时区（未设置时返回UTC）
+func GetLocation(c *gin.Context) *time.Location {
+	if loc, exists := c.Get("location"); exists {
+		if l, ok := loc.(*time.Location); ok && l != nil {
+			return l
+		}
+	}
+	return time.UTC
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"gen-go/internal/config"
+	"gen-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead multipart 请求中除文件内容外的表单字段和分隔符允许占用的字节数
+const multipartOverhead = 1 << 20
+
+// multipartMemory 解析 multipart 请求时保留在内存中的字节数，超出部分写入临时文件
+const multipartMemory = 32 << 20
+
+// UploadLimitMiddleware 检查 multipart 上传请求（表单字段 file）的大小和文件类型
+// 在处理器把文件读入内存之前执行：超过 upload.max_size_mb 返回 413，扩展名不在白名单或内容与扩展名不符返回 415
+func UploadLimitMiddleware(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		upload := cfg.Current().Upload
+		maxBytes := upload.GetMaxSizeBytes()
+		tooLarge := fmt.Sprintf("文件大小超过上限 %d MB", upload.MaxSizeMB)
+
+		if c.Request.ContentLength > maxBytes+multipartOverhead {
+			utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, tooLarge)
+			c.Abort()
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes+multipartOverhead)
+
+		if err := c.Request.ParseMultipartForm(multipartMemory); err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, tooLarge)
+			} else {
+				utils.BadRequest(c, "解析上传请求失败: "+err.Error())
+			}
+			c.Abort()
+			return
+		}
+
+		files := c.Request.MultipartForm.File["file"]
+		if len(files) == 0 {
+			// 缺少文件时由处理器返回错误
+			c.Next()
+			return
+		}
+		header := files[0]
+		if header.Size > maxBytes {
+			utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, tooLarge)
+			c.Abort()
+			return
+		}
+
+		src, err := header.Open()
+		if err != nil {
+			utils.BadRequest(c, "打开文件失败: "+err.Error())
+			c.Abort()
+			return
+		}
+		head := make([]byte, utils.UploadSniffSize)
+		n, err := io.ReadFull(src, head)
+		src.Close()
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			utils.BadRequest(c, "读取文件失败: "+err.Error())
+			c.Abort()
+			return
+		}
+
+		if err := utils.CheckUploadType(header.Filename, head[:n], upload.AllowedExtensions); err != nil {
+			utils.ErrorResponse(c, http.StatusUnsupportedMediaType, err.Error())
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// BulkUploadLimitMiddleware 检查批量上传请求（表单字段 files[] 或 files）的总大小、文件数和单个文件大小
+// 文件类型在处理每个文件时检查，单个文件类型不符只导致该文件失败，不拒绝整个请求
+func BulkUploadLimitMiddleware(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		upload := cfg.Current().Upload
+		maxBytes := upload.GetMaxBatchSizeBytes()
+		tooLarge := fmt.Sprintf("批量上传的总大小超过上限 %d MB", upload.MaxBatchSizeMB)
+
+		if c.Request.ContentLength > maxBytes+multipartOverhead {
+			utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, tooLarge)
+			c.Abort()
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes+multipartOverhead)
+
+		if err := c.Request.ParseMultipartForm(multipartMemory); err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, tooLarge)
+			} else {
+				utils.BadRequest(c, "解析上传请求失败: "+err.Error())
+			}
+			c.Abort()
+			return
+		}
+
+		files := BulkUploadFiles(c)
+		if len(files) > upload.MaxBatchFiles {
+			utils.BadRequest(c, fmt.Sprintf("单次最多上传 %d 个文件", upload.MaxBatchFiles))
+			c.Abort()
+			return
+		}
+		for _, header := range files {
+			if header.Size > upload.GetMaxSizeBytes() {
+				utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("文件 %s 的大小超过上限 %d MB", header.Filename, upload.MaxSizeMB))
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// BulkUploadFiles 获取批量上传请求中的文件（表单字段 files[] 和 files）
+func BulkUploadFiles(c *gin.Context) []*multipart.FileHeader {
+	if c.Request.MultipartForm == nil {
+		return nil
+	}
+	form := c.Request.MultipartForm.File
+	return append(append([]*multipart.FileHeader(nil), form["files[]"]...), form["files"]...)
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+)
+
+// 审计操作类型
+const (
+	AuditActionUserDelete     = "user.delete"
+	AuditActionUserRoleUpdate = "user.role_update"
+	AuditActionPasswordReset  = "user.password_reset"
+	AuditActionModelCreate    = "model.create"
+	AuditActionModelUpdate    = "model.update"
+	AuditActionModelDelete    = "model.delete"
+	AuditActionTaskDelete     = "task.delete"
+	AuditActionReportDelete   = "report.delete"
+	AuditActionFileDelete     = "file.delete"
+	AuditActionFileUpdate     = "file.update"
+	AuditActionExport         = "export"
+	AuditActionBackupCreate   = "backup.create"
+	AuditActionBackupDownload = "backup.download"
+	AuditActionConfigReload   = "config.reload"
+
+	AuditActionScoringRuleCreate = "scoring_rule.create"
+	AuditActionScoringRuleUpdate = "scoring_rule.update"
+	AuditActionScoringRuleDelete = "scoring_rule.delete"
+
+	AuditActionTaskTypeCreate = "task_type.create"
+	AuditActionTaskTypeUpdate = "task_type.update"
+	AuditActionTaskTypeDelete = "task_type.delete"
+
+	AuditActionAnnouncementCreate = "announcement.create"
+)
+
+// 审计资源类型（导出沿用 ExportResource* 常量）
+const (
+	AuditResourceUser   = "user"
+	AuditResourceModel  = "model_config"
+	AuditResourceTask   = "task"
+	AuditResourceReport = "report"
+	AuditResourceBackup = "backup"
+	AuditResourceConfig = "config"
+
+	AuditResourceScoringRule  = "scoring_rule"
+	AuditResourceTaskType     = "task_type"
+	AuditResourceAnnouncement = "announcement"
+)
+
+// AuditLog 敏感操作审计记录
+// 管理员操作（删除用户/任务/报告、修改模型配置）和用户的关键操作（导出、删除文件）都会记录操作人、来源IP和变更前后快照
+type AuditLog struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	UserID       uint      `gorm:"not null;index" json:"user_id"` // 操作人
+	Username     string    `gorm:"size:50" json:"username"`
+	Action       string    `gorm:"size:50;not null;index" json:"action"`
+	ResourceType string    `gorm:"size:30;not null;index" json:"resource_type"`
+	ResourceID   string    `gorm:"size:100;index" json:"resource_id"`
+	Before       string    `gorm:"type:text" json:"before"` // 变更前快照（JSON，敏感字段已脱敏）
+	After        string    `gorm:"type:text" json:"after"`  // 变更后快照（JSON，敏感字段已脱敏）
+	Endpoint     string    `gorm:"size:255" json:"endpoint"`
+	ClientIP     string    `gorm:"size:64" json:"client_ip"`
+	CreatedAt    time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
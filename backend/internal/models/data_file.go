@@ -6,14 +6,24 @@ import (
 
 // DataFile 数据文件模型
 type DataFile struct {
-	ID          uint      `gorm:"primarykey" json:"id"`
-	Filename    string    `gorm:"size:255;not null" json:"filename"`
-	FileContent []byte    `gorm:"type:blob;not null" json:"-"`
-	FileSize    int       `gorm:"not null" json:"file_size"`
-	ContentType string    `gorm:"size:100;default:'application/x-jsonlines'" json:"content_type"`
-	UserID      uint      `gorm:"not null;index" json:"user_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID              uint       `gorm:"primarykey" json:"id"`
+	Filename        string     `gorm:"size:255;not null" json:"filename"`
+	Description     string     `gorm:"size:500" json:"description"`
+	FileContent     []byte     `gorm:"not null" json:"-"`
+	StoragePath     string     `gorm:"size:255" json:"-"` // 内容保存在文件存储中时的相对路径（此时 FileContent 列为空）
+	FileSize        int        `gorm:"not null" json:"file_size"`
+	ContentType     string     `gorm:"size:100;default:'application/x-jsonlines'" json:"content_type"`
+	UserID          uint       `gorm:"not null;index" json:"user_id"`
+	WorkspaceID     *uint      `gorm:"index" json:"workspace_id"`             // 所属工作区，为空表示个人文件
+	CurrentVersion  int        `gorm:"default:0" json:"current_version"`      // 当前内容的版本号，0 表示尚未产生版本
+	ConvertedFromID *uint      `gorm:"index" json:"converted_from,omitempty"` // 由哪个文件转换生成
+	Snapshot        bool       `gorm:"default:false;index" json:"-"`          // 任务输入快照（历史版本或预处理结果），只供工作进程读取，不出现在文件列表、搜索和存储占用中
+	FolderPath      string     `gorm:"size:255;index" json:"folder_path"`     // 所在文件夹（如 projects/chat），为空表示根目录
+	ScanStatus      string     `gorm:"size:20" json:"scan_status,omitempty"`  // 病毒扫描结果: clean, error（扫描失败但按 fail_open 放行），为空表示未扫描
+	ScanDetail      string     `gorm:"size:255" json:"scan_detail,omitempty"` // 扫描失败的原因
+	ScannedAt       *time.Time `json:"scanned_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 
 	// 关联
 	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
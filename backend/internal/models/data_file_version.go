@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// 文件版本的变更类型
+const (
+	FileVersionActionInitial    = "initial"    // 启用版本管理前已有的内容
+	FileVersionActionUpload     = "upload"     // 上传创建
+	FileVersionActionUpdate     = "update"     // 修改某一项
+	FileVersionActionAdd        = "add"        // 新增内容
+	FileVersionActionDelete     = "delete"     // 批量删除内容
+	FileVersionActionQuarantine = "quarantine" // 校验隔离无效行
+	FileVersionActionRestore    = "restore"    // 恢复到历史版本
+)
+
+// DataFileVersion 数据文件版本（创建后不可修改，文件每次变更生成新版本，任务可引用指定版本以便复现）
+type DataFileVersion struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	FileID        uint      `gorm:"not null;uniqueIndex:idx_file_version" json:"file_id"`
+	Version       int       `gorm:"not null;uniqueIndex:idx_file_version" json:"version"`
+	Content       []byte    `gorm:"not null" json:"-"`
+	StoragePath   string    `gorm:"size:255" json:"-"` // 内容保存在文件存储中时的相对路径（与上传的文件共用同一存储文件）
+	FileSize      int       `gorm:"not null" json:"file_size"`
+	LineCount     int       `gorm:"default:0" json:"line_count"`
+	Action        string    `gorm:"size:20;not null" json:"action"`
+	AddedLines    int       `gorm:"default:0" json:"added_lines"`
+	RemovedLines  int       `gorm:"default:0" json:"removed_lines"`
+	ModifiedLines int       `gorm:"default:0" json:"modified_lines"`
+	Summary       string    `gorm:"size:255" json:"summary"`
+	RestoredFrom  *int      `json:"restored_from,omitempty"` // 恢复操作的来源版本号
+	UserID        uint      `gorm:"not null" json:"user_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (DataFileVersion) TableName() string {
+	return "data_file_versions"
+}
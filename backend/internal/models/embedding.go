@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// 向量的来源类型
+const (
+	EmbeddingSourceFile      = "file"      // 数据文件中的一条数据，SourceID 为文件 ID，ItemIndex 为条目下标
+	EmbeddingSourceGenerated = "generated" // 一条生成数据，SourceID 为生成数据 ID
+)
+
+// Embedding 数据条目的向量（按向量模型分别保存），用于语义检索和语义去重
+type Embedding struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"` // 数据所有者
+	ModelID     uint      `gorm:"not null;uniqueIndex:idx_embedding_item,priority:1" json:"model_id"`
+	SourceType  string    `gorm:"size:20;not null;uniqueIndex:idx_embedding_item,priority:2" json:"source_type"`
+	SourceID    uint      `gorm:"not null;uniqueIndex:idx_embedding_item,priority:3" json:"source_id"`
+	ItemIndex   int       `gorm:"not null;default:0;uniqueIndex:idx_embedding_item,priority:4" json:"item_index"`
+	TaskID      string    `gorm:"size:100;index" json:"task_id"` // 生成数据所属任务
+	ContentHash string    `gorm:"size:64" json:"content_hash"`   // 向量化文本的 sha256，内容未变时重建索引跳过
+	Dim         int       `gorm:"not null" json:"dim"`
+	Vector      []byte    `gorm:"not null" json:"-"` // float32 小端序
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (Embedding) TableName() string {
+	return "embeddings"
+}
+
+// EncodeVector 将向量编码为 float32 小端序字节（sqlite-vec 可直接使用该格式）
+func EncodeVector(vector []float32) []byte {
+	buf := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(v))
+	}
+	return buf
+}
+
+// DecodeVector 解码 EncodeVector 编码的向量
+func DecodeVector(buf []byte) []float32 {
+	vector := make([]float32, len(buf)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[i*4:]))
+	}
+	return vector
+}
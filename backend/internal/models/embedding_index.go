@@ -0,0 +1,49 @@
+package models
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// 向量检索方式
+const (
+	VectorEngineScan      = "scan"       // 分批读取向量在进程内计算相似度
+	VectorEngineSQLiteVec = "sqlite-vec" // SQLite 的 sqlite-vec 扩展（vec_distance_cosine）
+	VectorEnginePGVector  = "pgvector"   // PostgreSQL 的 pgvector 扩展（embedding_vec 列）
+)
+
+// vectorEngine 当前数据库可用的向量检索方式
+var vectorEngine = VectorEngineScan
+
+// VectorEngine 语义检索使用的向量检索方式
+func VectorEngine() string {
+	return vectorEngine
+}
+
+// setupVectorIndex 检测数据库的向量扩展
+// SQLite 需要使用 sqlite_vec 构建标签注册 sqlite-vec；PostgreSQL 需要安装 pgvector（由后端执行 CREATE EXTENSION），
+// 都不可用时回退为进程内计算，不影响启动
+func setupVectorIndex(db *gorm.DB) {
+	switch db.Dialector.Name() {
+	case "sqlite":
+		var version string
+		if err := db.Raw("SELECT vec_version()").Scan(&version).Error; err != nil {
+			return
+		}
+		vectorEngine = VectorEngineSQLiteVec
+		log.Printf("[Embedding] 使用 sqlite-vec %s 检索向量", version)
+	case "postgres":
+		if err := db.Exec("CREATE EXTENSION IF NOT EXISTS vector").Error; err != nil {
+			log.Printf("[Embedding] pgvector 不可用，进程内计算向量相似度: %v", err)
+			return
+		}
+		// 向量维度随模型不同，列不限定维度；blob 列仍然保留，切换数据库或扩展时可以回退
+		if err := db.Exec("ALTER TABLE embeddings ADD COLUMN IF NOT EXISTS embedding_vec vector").Error; err != nil {
+			log.Printf("[Embedding] 创建 embedding_vec 列失败，进程内计算向量相似度: %v", err)
+			return
+		}
+		vectorEngine = VectorEnginePGVector
+		log.Printf("[Embedding] 使用 pgvector 检索向量")
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// 导出审计的资源类型
+const (
+	ExportResourceGeneratedData = "generated_data"
+	ExportResourceDataFile      = "data_file"
+)
+
+// ExportAudit 导出/下载审计记录
+type ExportAudit struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	UserID       uint      `gorm:"not null;index" json:"user_id"`
+	Username     string    `gorm:"size:50" json:"username"`
+	ResourceType string    `gorm:"size:30;not null;index" json:"resource_type"` // generated_data, data_file
+	ResourceID   string    `gorm:"size:100;not null;index" json:"resource_id"`  // 任务ID或文件ID
+	OwnerID      *uint     `gorm:"index" json:"owner_id"`                       // 资源所属用户（管理员代下载时与 UserID 不同）
+	Format       string    `gorm:"size:20" json:"format"`
+	RowCount     int       `json:"row_count"`
+	Filters      JSONMap   `gorm:"type:text" json:"filters"` // 请求中的过滤参数
+	Endpoint     string    `gorm:"size:255" json:"endpoint"`
+	ClientIP     string    `gorm:"size:64" json:"client_ip"`
+	CreatedAt    time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (ExportAudit) TableName() string {
+	return "export_audits"
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileStoreDir 文件存储目录（InitDB 时按 upload.storage_dir 设置）
+// 大文件（分片上传的 JSONL）的内容保存在该目录下，数据库只保存相对路径（StoragePath）；
+// 存储中的文件写入后不再修改，内容变更时新内容写回数据库，原文件仍由历史版本引用
+var fileStoreDir = "./data/files"
+
+// SetFileStoreDir 设置文件存储目录
+func SetFileStoreDir(dir string) {
+	fileStoreDir = dir
+}
+
+// StoredFilePath 获取存储路径对应的本地文件路径
+func StoredFilePath(storagePath string) string {
+	return filepath.Join(fileStoreDir, filepath.FromSlash(storagePath))
+}
+
+// MoveToFileStore 将本地文件移入文件存储，返回存储路径（按随机名称的前两位分目录，如 ab/abcd....jsonl）
+// 与存储目录不在同一文件系统时改为复制，复制完成后删除源文件
+func MoveToFileStore(srcPath string, ext string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	name := hex.EncodeToString(b)
+	storagePath := name[:2] + "/" + name + ext
+
+	dst := StoredFilePath(storagePath)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", fmt.Errorf("创建存储目录失败: %w", err)
+	}
+	if err := os.Rename(srcPath, dst); err == nil {
+		return storagePath, nil
+	}
+	if err := copyLocalFile(srcPath, dst); err != nil {
+		os.Remove(dst)
+		return "", fmt.Errorf("写入文件存储失败: %w", err)
+	}
+	os.Remove(srcPath)
+	return storagePath, nil
+}
+
+// RemoveFromFileStore 删除文件存储中的文件（文件不存在时忽略）
+func RemoveFromFileStore(storagePath string) error {
+	if storagePath == "" || strings.Contains(storagePath, "..") {
+		return nil
+	}
+	if err := os.Remove(StoredFilePath(storagePath)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// openStored 打开文件存储中的文件
+func openStored(storagePath string) (*os.File, error) {
+	if strings.Contains(storagePath, "..") {
+		return nil, fmt.Errorf("无效的存储路径: %s", storagePath)
+	}
+	f, err := os.Open(StoredFilePath(storagePath))
+	if err != nil {
+		return nil, fmt.Errorf("读取文件存储失败: %w", err)
+	}
+	return f, nil
+}
+
+// readStored 读取文件存储中文件的全部内容
+func readStored(storagePath string) ([]byte, error) {
+	f, err := openStored(storagePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// OpenContent 以流的方式读取文件内容（内容在文件存储中时直接读取磁盘文件，不整体载入内存）
+func (f *DataFile) OpenContent() (io.ReadCloser, error) {
+	if f.StoragePath == "" || len(f.FileContent) > 0 {
+		return io.NopCloser(bytes.NewReader(f.FileContent)), nil
+	}
+	return openStored(f.StoragePath)
+}
+
+// LoadContent 内容保存在文件存储中时读取到 FileContent（已读取过时不重复读取）
+func (f *DataFile) LoadContent() error {
+	if f.StoragePath == "" || len(f.FileContent) > 0 {
+		return nil
+	}
+	content, err := readStored(f.StoragePath)
+	if err != nil {
+		return err
+	}
+	f.FileContent = content
+	return nil
+}
+
+// LoadContent 版本内容保存在文件存储中时读取到 Content
+func (v *DataFileVersion) LoadContent() error {
+	if v.StoragePath == "" || len(v.Content) > 0 {
+		return nil
+	}
+	content, err := readStored(v.StoragePath)
+	if err != nil {
+		return err
+	}
+	v.Content = content
+	return nil
+}
+
+// copyLocalFile 复制本地文件
+func copyLocalFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+// 数据文件标签和文件夹的限制
+const (
+	MaxFileTagLength   = 50  // 标签名最大长度（字符）
+	MaxFileTags        = 20  // 单个文件最多的标签数
+	MaxFolderPathBytes = 255 // 文件夹路径最大长度（字节）
+)
+
+// FileTag 数据文件标签（按名称全局唯一，文件和标签多对多关联）
+type FileTag struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Name      string    `gorm:"size:50;not null;uniqueIndex" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (FileTag) TableName() string {
+	return "file_tags"
+}
+
+// DataFileTag 数据文件与标签的关联
+type DataFileTag struct {
+	FileID    uint      `gorm:"primaryKey" json:"file_id"`
+	TagID     uint      `gorm:"primaryKey;index" json:"tag_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (DataFileTag) TableName() string {
+	return "data_file_tags"
+}
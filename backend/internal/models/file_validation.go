@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// FileValidation 数据文件结构校验记录
+type FileValidation struct {
+	ID               uint      `gorm:"primarykey" json:"id"`
+	FileID           uint      `gorm:"not null;index" json:"file_id"`
+	UserID           uint      `gorm:"not null;index" json:"user_id"`
+	Mode             string    `gorm:"size:20" json:"mode"` // report, reject, quarantine
+	IsValid          bool      `json:"is_valid"`
+	TotalLines       int       `json:"total_lines"`
+	ValidLines       int       `json:"valid_lines"`
+	InvalidLines     int       `json:"invalid_lines"`
+	Report           JSONMap   `gorm:"type:text" json:"report"` // error_counts / errors / truncated
+	QuarantineFileID *uint     `json:"quarantine_file_id"`      // 隔离的无效行所在文件
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (FileValidation) TableName() string {
+	return "file_validations"
+}
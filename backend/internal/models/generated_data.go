@@ -1,23 +1,47 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
+// 审核状态
+const (
+	ReviewStatusPending   = "pending"
+	ReviewStatusApproved  = "approved"
+	ReviewStatusRejected  = "rejected"
+	ReviewStatusNeedsEdit = "needs_edit"
+	ReviewStatusDisputed  = "disputed" // 多人审核结论不一致，需任务所有者裁定
+)
+
 // GeneratedData 生成数据模型
 type GeneratedData struct {
-	ID              uint      `gorm:"primarykey" json:"id"`
-	TaskID          string    `gorm:"size:100;not null;index" json:"task_id"`
-	UserID          uint      `gorm:"not null;index" json:"user_id"`
-	DataContent     string    `gorm:"type:text;not null" json:"data_content"`
-	ModelScore      *float64  `json:"model_score"`
-	RuleScore       *int      `json:"rule_score"`
-	RetryCount      int       `gorm:"default:0" json:"retry_count"`
-	GenerationModel string    `gorm:"size:255" json:"generation_model"`
-	TaskType        string    `gorm:"size:50" json:"task_type"`
-	IsConfirmed     bool      `gorm:"default:false" json:"is_confirmed"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID              uint       `gorm:"primarykey" json:"id"`
+	TaskID          string     `gorm:"size:100;not null;index" json:"task_id"`
+	UserID          uint       `gorm:"not null;index" json:"user_id"`
+	DataContent     string     `gorm:"type:text;not null" json:"data_content"`
+	ModelScore      *float64   `json:"model_score"`
+	RuleScore       *int       `json:"rule_score"`
+	RuleFailures    string     `gorm:"type:text" json:"rule_failures"` // 规则评分未通过的规则及原因（JSON）
+	RetryCount      int        `gorm:"default:0" json:"retry_count"`
+	GenerationModel string     `gorm:"size:255" json:"generation_model"`
+	TaskType        string     `gorm:"size:50" json:"task_type"`
+	IsConfirmed     bool       `gorm:"default:false" json:"is_confirmed"`
+	DuplicateOf     *string    `gorm:"size:50;index" json:"duplicate_of"`                  // 去重结果：source:<源文件条目下标> 或 data:<生成数据ID>
+	Similarity      *float64   `json:"similarity"`                                         // 语义相似度过滤：与源样本及已保留数据的最大余弦相似度
+	IdempotencyKey  *string    `gorm:"size:64;uniqueIndex" json:"-"`                       // 幂等键：sha256(task_id|seed_hash|seed_index|variant_index)，工作进程重试写入时去重
+	SeedHash        string     `gorm:"size:64;index" json:"seed_hash"`                     // 种子样本指纹（幂等键组成部分，不写入 data_content）
+	SeedIndex       *int       `json:"seed_index"`                                         // 种子样本在输入中的序号
+	VariantIndex    *int       `json:"variant_index"`                                      // 变体序号
+	Tags            string     `gorm:"size:500" json:"tags"`                               // 自动打标结果，逗号分隔的 分类:标签（如 difficulty:hard）
+	JudgeFeedback   string     `gorm:"type:text" json:"judge_feedback"`                    // 裁判模型评分的结构化点评（JSON）
+	SafetyFlags     string     `gorm:"size:500" json:"safety_flags"`                       // 内容安全检查命中的标记，逗号分隔（blocklist:<屏蔽词>、regex:<规则序号>、model:<类别>），为空表示未命中
+	ReviewStatus    string     `gorm:"size:20;default:pending;index" json:"review_status"` // 审核状态：pending/approved/rejected/needs_edit/disputed
+	ReviewerID      *uint      `gorm:"index" json:"reviewer_id"`                           // 分配的审核人（多人审核时为第一位）
+	ReviewComment   string     `gorm:"type:text" json:"review_comment"`                    // 审核意见
+	ReviewedAt      *time.Time `json:"reviewed_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 
 	// 关联
 	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
@@ -28,3 +52,19 @@ type GeneratedData struct {
 func (GeneratedData) TableName() string {
 	return "generated_data"
 }
+
+// SafetyFlagList 获取内容安全标记列表
+func (d *GeneratedData) SafetyFlagList() []string {
+	if d.SafetyFlags == "" {
+		return []string{}
+	}
+	return strings.Split(d.SafetyFlags, ",")
+}
+
+// TagList 获取标签列表
+func (d *GeneratedData) TagList() []string {
+	if d.Tags == "" {
+		return []string{}
+	}
+	return strings.Split(d.Tags, ",")
+}
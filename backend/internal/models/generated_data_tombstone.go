@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// GeneratedDataTombstone 生成数据删除记录
+// 删除生成数据时写入，供增量同步接口告知客户端哪些数据已被删除；超过保留期的记录会被清理
+type GeneratedDataTombstone struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	TaskID    string    `gorm:"size:100;not null;index:idx_tombstone_task_deleted" json:"task_id"`
+	DataID    uint      `gorm:"not null" json:"data_id"`
+	DeletedAt time.Time `gorm:"not null;index:idx_tombstone_task_deleted;index" json:"deleted_at"`
+}
+
+// TableName 指定表名
+func (GeneratedDataTombstone) TableName() string {
+	return "generated_data_tombstones"
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Glossary 用户上传的术语表
+type Glossary struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"`
+	Name        string    `gorm:"size:100;not null" json:"name"`
+	Description string    `gorm:"size:500" json:"description"`
+	TermCount   int       `gorm:"default:0" json:"term_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Glossary) TableName() string {
+	return "glossaries"
+}
+
+// GlossaryTerm 术语条目
+type GlossaryTerm struct {
+	ID         uint   `gorm:"primarykey" json:"id"`
+	GlossaryID uint   `gorm:"not null;index" json:"glossary_id"`
+	Term       string `gorm:"size:200;not null" json:"term"`
+	Definition string `gorm:"type:text" json:"definition"`
+	Preferred  string `gorm:"size:200" json:"preferred"` // 推荐用法/译法，为空时即为 Term 本身
+	Variants   string `gorm:"size:500" json:"variants"`  // 不应使用的写法，| 分隔
+}
+
+// TableName 指定表名
+func (GlossaryTerm) TableName() string {
+	return "glossary_terms"
+}
+
+// VariantList 获取不应使用的写法列表
+func (t *GlossaryTerm) VariantList() []string {
+	if t.Variants == "" {
+		return []string{}
+	}
+	return strings.Split(t.Variants, "|")
+}
+
+// PreferredOrTerm 获取推荐用法
+func (t *GlossaryTerm) PreferredOrTerm() string {
+	if t.Preferred != "" {
+		return t.Preferred
+	}
+	return t.Term
+}
+
+// GlossaryViolation 术语检查发现的违规（每条生成数据可能有多条）
+type GlossaryViolation struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	TaskID     string    `gorm:"size:100;not null;index" json:"task_id"`
+	DataID     uint      `gorm:"not null;index" json:"data_id"`
+	GlossaryID uint      `gorm:"not null" json:"glossary_id"`
+	Term       string    `gorm:"size:200" json:"term"`
+	Found      string    `gorm:"size:200" json:"found"`     // 数据中出现的不推荐写法
+	Preferred  string    `gorm:"size:200" json:"preferred"` // 应使用的写法
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (GlossaryViolation) TableName() string {
+	return "glossary_violations"
+}
@@ -1,21 +1,33 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
+// 模型服务的对话接口协议
+const (
+	ModelProviderOpenAI    = "openai"    // OpenAI Chat Completions（vLLM 等兼容服务）
+	ModelProviderAnthropic = "anthropic" // Anthropic Messages API
+	ModelProviderGemini    = "gemini"    // Google Gemini generateContent
+)
+
 // ModelConfig 模型配置
 type ModelConfig struct {
 	ID            uint      `gorm:"primarykey" json:"id"`
 	Name          string    `gorm:"uniqueIndex;size:100;not null" json:"name"`
 	APIURL        string    `gorm:"size:255;not null" json:"api_url"`
+	Endpoints     string    `gorm:"type:text" json:"endpoints"` // 同一模型的其他服务地址，逗号分隔（与 APIURL 组成端点池）
 	APIKey        string    `gorm:"size:255;default:'sk-xxxxx'" json:"api_key"`
 	ModelPath     string    `gorm:"size:500;not null" json:"model_path"`
 	MaxConcurrent int       `gorm:"default:16" json:"max_concurrent"`
+	RPMLimit      int       `gorm:"default:0" json:"rpm_limit"` // 每分钟请求数上限，0 表示不限制
+	TPMLimit      int       `gorm:"default:0" json:"tpm_limit"` // 每分钟Token数上限，0 表示不限制
 	Temperature   float64   `gorm:"default:1.0" json:"temperature"`
 	TopP          float64   `gorm:"default:1.0" json:"top_p"`
 	MaxTokens     int       `gorm:"default:2048" json:"max_tokens"`
 	IsVLLM        bool      `gorm:"default:true" json:"is_vllm"`
+	Provider      string    `gorm:"size:20;default:'openai'" json:"provider"` // 对话接口协议：openai、anthropic、gemini
 	Timeout       int       `gorm:"default:600" json:"timeout"`
 	Description   string    `gorm:"type:text" json:"description"`
 	IsActive      bool      `gorm:"default:true" json:"is_active"`
@@ -27,3 +39,18 @@ type ModelConfig struct {
 func (ModelConfig) TableName() string {
 	return "model_configs"
 }
+
+// EndpointList 获取模型的全部服务地址（APIURL 在前，去除重复和空白）
+func (m *ModelConfig) EndpointList() []string {
+	endpoints := []string{}
+	seen := map[string]bool{}
+	for _, url := range append([]string{m.APIURL}, strings.Split(m.Endpoints, ",")...) {
+		url = strings.TrimSpace(url)
+		if url == "" || seen[url] {
+			continue
+		}
+		seen[url] = true
+		endpoints = append(endpoints, url)
+	}
+	return endpoints
+}
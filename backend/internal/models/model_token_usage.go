@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// ModelTokenUsage 模型每日Token用量（按模型调用代理收到的上游 usage 累计）
+type ModelTokenUsage struct {
+	ID               uint      `gorm:"primarykey" json:"id"`
+	Model            string    `gorm:"size:500;not null;uniqueIndex:idx_model_token_usage_model_date" json:"model"`
+	Date             string    `gorm:"size:10;not null;uniqueIndex:idx_model_token_usage_model_date;index" json:"date"` // UTC 日期 YYYY-MM-DD
+	Calls            int64     `gorm:"default:0" json:"calls"`
+	PromptTokens     int64     `gorm:"default:0" json:"prompt_tokens"`
+	CompletionTokens int64     `gorm:"default:0" json:"completion_tokens"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ModelTokenUsage) TableName() string {
+	return "model_token_usage"
+}
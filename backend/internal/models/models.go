@@ -1,45 +1,159 @@
 package models
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"gen-go/internal/config"
 
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+	gormtracing "gorm.io/plugin/opentelemetry/tracing"
 )
 
 // DB 全局数据库实例
 var DB *gorm.DB
 
+// ReplicaResolver 只读副本解析器名称
+// 副本只注册在该名称下（不作为全局解析器），默认所有读写仍走主库；
+// 仓储层对读取量大的查询通过 dbresolver.Use(ReplicaResolver) 显式路由到副本，避免写后读因复制延迟读到旧数据
+const ReplicaResolver = "read_replica"
+
+// replicasEnabled 是否配置了只读副本
+var replicasEnabled bool
+
 // InitDB 初始化数据库
 func InitDB(cfg *config.Config) error {
 	var err error
 
 	// 配置GORM
-	DB, err = gorm.Open(sqlite.Open(cfg.Database.Path), &gorm.Config{
+	// 统一以UTC读写时间（Python工作进程同样写入UTC），展示时再按用户时区转换
+	DB, err = gorm.Open(openDialector(cfg.Database.Driver, primaryDSN(&cfg.Database)), &gorm.Config{
 		Logger:                                   logger.Default.LogMode(logger.Silent), // 使用静默模式
 		DisableForeignKeyConstraintWhenMigrating: true,
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
 	})
 	if err != nil {
 		return err
 	}
 
+	// 注册只读副本
+	if len(cfg.Database.Replicas) > 0 {
+		replicas := make([]gorm.Dialector, 0, len(cfg.Database.Replicas))
+		for _, dsn := range cfg.Database.Replicas {
+			if cfg.Database.Driver == config.DatabaseDriverSQLite {
+				dsn = withUTCLocation(dsn)
+			}
+			replicas = append(replicas, openDialector(cfg.Database.Driver, dsn))
+		}
+		err = DB.Use(dbresolver.Register(dbresolver.Config{
+			Replicas: replicas,
+			Policy:   dbresolver.RandomPolicy{},
+		}, ReplicaResolver))
+		if err != nil {
+			return fmt.Errorf("注册只读副本失败: %w", err)
+		}
+		replicasEnabled = true
+	}
+
+	SetFileStoreDir(cfg.FileStorageDir())
+
+	// 链路追踪：为每次数据库访问创建 span（仓储通过 WithContext 传入上下文时挂在调用方的 trace 下）
+	if cfg.Tracing.Enabled {
+		if err := DB.Use(gormtracing.NewPlugin(gormtracing.WithoutMetrics())); err != nil {
+			return fmt.Errorf("注册数据库链路追踪失败: %w", err)
+		}
+	}
+
 	// 自动迁移数据库表结构
 	if err := AutoMigrate(); err != nil {
 		return err
 	}
 
+	// 内置任务类型
+	seedTaskTypes(DB)
+
+	// 全文检索索引（不可用时回退为 LIKE 查询，不影响启动）
+	setupSearchIndex(DB)
+
+	// 向量检索扩展（不可用时在进程内计算相似度）
+	setupVectorIndex(DB)
+
 	return nil
 }
 
+// openDialector 按数据库类型创建方言
+func openDialector(driver string, dsn string) gorm.Dialector {
+	if driver == config.DatabaseDriverPostgres {
+		return postgres.Open(dsn)
+	}
+	return sqlite.Open(dsn)
+}
+
+// primaryDSN 获取主库连接串
+func primaryDSN(cfg *config.DatabaseConfig) string {
+	if cfg.Driver == config.DatabaseDriverPostgres {
+		return cfg.DSN
+	}
+	return withUTCLocation(cfg.Path)
+}
+
+// withUTCLocation 为SQLite DSN追加 _loc=UTC，使读出的时间统一为UTC
+func withUTCLocation(dsn string) string {
+	if strings.Contains(dsn, "_loc=") {
+		return dsn
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&_loc=UTC"
+	}
+	return dsn + "?_loc=UTC"
+}
+
 // AutoMigrate 自动迁移数据库表
 func AutoMigrate() error {
 	return DB.AutoMigrate(
 		&User{},
+		&UserSettings{},
 		&ModelConfig{},
 		&Task{},
+		&TaskAttempt{},
 		&DataFile{},
+		&DataFileVersion{},
+		&FileTag{},
+		&DataFileTag{},
 		&GeneratedData{},
+		&UploadSession{},
+		&FileValidation{},
+		&ExportAudit{},
+		&AuditLog{},
+		&Workspace{},
+		&WorkspaceMember{},
+		&Webhook{},
+		&WebhookDelivery{},
+		&NotificationSubscription{},
+		&Notification{},
+		&Schedule{},
+		&ScheduleRun{},
+		&ReviewLink{},
+		&ReviewLinkActivity{},
+		&Prompt{},
+		&PromptVersion{},
+		&Glossary{},
+		&GlossaryTerm{},
+		&GlossaryViolation{},
+		&TagRule{},
+		&ScoringRule{},
+		&TaskType{},
+		&ReviewVerdict{},
+		&GeneratedDataTombstone{},
+		&ModelTokenUsage{},
+		&Embedding{},
 	)
 }
 
@@ -47,3 +161,12 @@ func AutoMigrate() error {
 func GetDB() *gorm.DB {
 	return DB
 }
+
+// ReadReplica 将查询路由到只读副本（未配置副本时原样返回，走主库）
+// 仅用于可以容忍复制延迟的读取，如报告、列表和导出
+func ReadReplica(db *gorm.DB) *gorm.DB {
+	if !replicasEnabled {
+		return db
+	}
+	return db.Clauses(dbresolver.Use(ReplicaResolver))
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// 通知渠道
+const (
+	NotifyChannelEmail    = "email"
+	NotifyChannelSlack    = "slack"
+	NotifyChannelDingTalk = "dingtalk"
+	NotifyChannelWeCom    = "wecom"
+)
+
+// NotifyChannels 全部支持的通知渠道
+var NotifyChannels = []string{
+	NotifyChannelEmail,
+	NotifyChannelSlack,
+	NotifyChannelDingTalk,
+	NotifyChannelWeCom,
+}
+
+// NotifyDefaultEvents 未指定事件时订阅的事件（任务完成或失败）
+var NotifyDefaultEvents = []string{
+	WebhookEventTaskFinished,
+	WebhookEventTaskError,
+}
+
+// NotificationSubscription 用户订阅的任务通知
+// TaskID 为空时订阅用户自己的全部任务，否则只订阅指定任务（可以是工作区中其他成员的任务）
+type NotificationSubscription struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	TaskID     string     `gorm:"size:100;index" json:"task_id"`
+	Channel    string     `gorm:"size:20;not null" json:"channel"` // email, slack, dingtalk, wecom
+	Target     string     `gorm:"size:500;not null" json:"target"` // 邮箱地址或机器人 Webhook 地址
+	Secret     string     `gorm:"size:200" json:"-"`               // 钉钉机器人加签密钥
+	Events     string     `gorm:"size:255" json:"events"`          // 订阅的事件，逗号分隔
+	IsActive   bool       `gorm:"default:true" json:"is_active"`
+	LastSentAt *time.Time `json:"last_sent_at"`                // 最近一次发送成功的时间
+	LastError  string     `gorm:"type:text" json:"last_error"` // 最近一次发送失败的原因，成功后清空
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (NotificationSubscription) TableName() string {
+	return "notification_subscriptions"
+}
+
+// EventList 获取订阅的事件列表
+func (s *NotificationSubscription) EventList() []string {
+	if s.Events == "" {
+		return []string{}
+	}
+	return strings.Split(s.Events, ",")
+}
+
+// Subscribes 判断是否订阅了指定事件
+func (s *NotificationSubscription) Subscribes(event string) bool {
+	for _, e := range s.EventList() {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// 站内通知类型
+const (
+	NotificationTypeTask         = "task"         // 任务结束（完成、失败、停止）
+	NotificationTypeReview       = "review"       // 分配或转交了审核数据
+	NotificationTypeAnnouncement = "announcement" // 管理员公告
+)
+
+// Notification 站内通知（通知中心），公告按接收用户各写一条以便分别记录已读状态
+type Notification struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"not null;index:idx_notifications_user_read" json:"user_id"`
+	Type      string     `gorm:"size:20;not null" json:"type"`
+	Title     string     `gorm:"size:255;not null" json:"title"`
+	Content   string     `gorm:"type:text" json:"content"`
+	Link      string     `gorm:"size:500" json:"link"` // 前端页面路径，如 /editor/<task_id>
+	TaskID    string     `gorm:"size:100" json:"task_id"`
+	ReadAt    *time.Time `gorm:"index:idx_notifications_user_read" json:"read_at"`
+	CreatedAt time.Time  `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (Notification) TableName() string {
+	return "notifications"
+}
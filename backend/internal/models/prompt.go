@@ -0,0 +1,48 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Prompt 提示词库中的命名提示词
+// 内容（SpecialPrompt、Directions）保存在不可修改的 PromptVersion 中，每次修改生成新版本
+type Prompt struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	UserID        uint      `gorm:"not null;index" json:"user_id"`
+	Name          string    `gorm:"size:100;not null" json:"name"`
+	Description   string    `gorm:"size:500" json:"description"`
+	Tags          string    `gorm:"size:255" json:"tags"` // 标签，逗号分隔
+	LatestVersion int       `gorm:"default:0" json:"latest_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Prompt) TableName() string {
+	return "prompts"
+}
+
+// TagList 获取标签列表
+func (p *Prompt) TagList() []string {
+	if p.Tags == "" {
+		return []string{}
+	}
+	return strings.Split(p.Tags, ",")
+}
+
+// PromptVersion 提示词版本（创建后不可修改，任务通过版本ID引用以便复现）
+type PromptVersion struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	PromptID      uint      `gorm:"not null;uniqueIndex:idx_prompt_version" json:"prompt_id"`
+	Version       int       `gorm:"not null;uniqueIndex:idx_prompt_version" json:"version"`
+	SpecialPrompt string    `gorm:"type:text" json:"special_prompt"`
+	Directions    string    `gorm:"type:text" json:"directions"`
+	ChangeNote    string    `gorm:"size:500" json:"change_note"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (PromptVersion) TableName() string {
+	return "prompt_versions"
+}
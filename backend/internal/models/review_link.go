@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// 审阅链接访问记录的操作类型
+const (
+	ReviewActionView      = "view"
+	ReviewActionList      = "list"
+	ReviewActionConfirm   = "confirm"
+	ReviewActionUnconfirm = "unconfirm"
+)
+
+// ReviewLink 匿名只读审阅链接
+// 链接中的 Token 只能读取并确认单个任务的生成数据，供外部标注人员免注册审阅
+type ReviewLink struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"` // 创建者（任务所有者）
+	TaskID     string     `gorm:"size:100;not null;index" json:"task_id"`
+	TokenID    string     `gorm:"size:64;not null;uniqueIndex" json:"-"` // Token 的 jti，用于撤销与访问记录
+	Name       string     `gorm:"size:100" json:"name"`                  // 备注（如标注人员名称）
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	Revoked    bool       `gorm:"default:false" json:"revoked"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	UseCount   int64      `gorm:"default:0" json:"use_count"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (ReviewLink) TableName() string {
+	return "review_links"
+}
+
+// IsUsable 判断链接是否仍可使用
+func (l *ReviewLink) IsUsable(now time.Time) bool {
+	return !l.Revoked && now.Before(l.ExpiresAt)
+}
+
+// ReviewLinkActivity 审阅链接访问记录
+type ReviewLinkActivity struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	LinkID    uint      `gorm:"not null;index" json:"link_id"`
+	TaskID    string    `gorm:"size:100;index" json:"task_id"`
+	Action    string    `gorm:"size:20;not null" json:"action"`
+	DataID    *uint     `json:"data_id"`
+	IP        string    `gorm:"size:64" json:"ip"`
+	UserAgent string    `gorm:"size:255" json:"user_agent"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (ReviewLinkActivity) TableName() string {
+	return "review_link_activities"
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// ReviewVerdict 审核人对单条生成数据的独立审核结论
+// 分配审核时为每位审核人创建一条 pending 记录；任务要求多人审核时，各审核人的结论分别保存，用于计算一致性
+type ReviewVerdict struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	TaskID     string     `gorm:"size:100;not null;index" json:"task_id"`
+	DataID     uint       `gorm:"not null;uniqueIndex:idx_review_verdict_data_reviewer" json:"data_id"`
+	ReviewerID uint       `gorm:"not null;index;uniqueIndex:idx_review_verdict_data_reviewer" json:"reviewer_id"`
+	Verdict    string     `gorm:"size:20;not null;default:pending" json:"verdict"` // pending/approved/rejected/needs_edit
+	Comment    string     `gorm:"type:text" json:"comment"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ReviewVerdict) TableName() string {
+	return "review_verdicts"
+}
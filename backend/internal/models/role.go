@@ -0,0 +1,60 @@
+package models
+
+// 用户角色（权限依次递增）
+const (
+	RoleViewer   = "viewer"   // 只读：查看任务、数据文件和报告
+	RoleReviewer = "reviewer" // 审核：在只读基础上可编辑、确认和审核生成数据
+	RoleOperator = "operator" // 操作：可启动任务、管理数据文件、提示词等资源
+	RoleAdmin    = "admin"    // 管理员：用户、模型配置等系统管理
+)
+
+// DefaultRole 新注册用户的默认角色（与引入角色前普通用户的权限一致）
+const DefaultRole = RoleOperator
+
+// Permission 接口权限
+type Permission string
+
+// 接口权限
+const (
+	PermissionView    Permission = "view"    // 查看数据
+	PermissionReview  Permission = "review"  // 编辑、确认和审核生成数据
+	PermissionOperate Permission = "operate" // 启动任务、上传和删除文件等写操作
+	PermissionAdmin   Permission = "admin"   // 系统管理
+)
+
+// roleOrder 角色列表（按权限从低到高）
+var roleOrder = []string{RoleViewer, RoleReviewer, RoleOperator, RoleAdmin}
+
+// rolePermissions 各角色拥有的权限
+var rolePermissions = map[string][]Permission{
+	RoleViewer:   {PermissionView},
+	RoleReviewer: {PermissionView, PermissionReview},
+	RoleOperator: {PermissionView, PermissionReview, PermissionOperate},
+	RoleAdmin:    {PermissionView, PermissionReview, PermissionOperate, PermissionAdmin},
+}
+
+// Roles 返回所有角色（按权限从低到高）
+func Roles() []string {
+	return append([]string(nil), roleOrder...)
+}
+
+// IsValidRole 判断角色是否有效
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// RolePermissions 返回角色拥有的权限
+func RolePermissions(role string) []Permission {
+	return append([]Permission(nil), rolePermissions[role]...)
+}
+
+// RoleHasPermission 判断角色是否拥有指定权限
+func RoleHasPermission(role string, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
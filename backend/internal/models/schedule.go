@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// 定时任务执行结果
+const (
+	ScheduleRunCreated = "created" // 已创建任务
+	ScheduleRunSkipped = "skipped" // 上一次运行仍在进行，跳过
+	ScheduleRunFailed  = "failed"  // 创建任务失败
+)
+
+// Schedule 定时/周期生成任务
+type Schedule struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	CronExpr   string     `gorm:"size:100;not null" json:"cron_expr"`
+	Timezone   string     `gorm:"size:64" json:"timezone"`      // cron 表达式按该时区解释
+	TaskParams JSONMap    `gorm:"type:text" json:"task_params"` // 启动任务的请求参数（StartTaskRequest）
+	IsActive   bool       `gorm:"default:true;index" json:"is_active"`
+	NextRunAt  *time.Time `gorm:"index" json:"next_run_at"`
+	LastRunAt  *time.Time `json:"last_run_at"`
+	LastTaskID string     `gorm:"size:100" json:"last_task_id"` // 最近一次创建的任务ID
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Schedule) TableName() string {
+	return "schedules"
+}
+
+// ScheduleRun 定时任务执行记录
+type ScheduleRun struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	ScheduleID  uint      `gorm:"not null;index" json:"schedule_id"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"`
+	Status      string    `gorm:"size:20;not null" json:"status"` // created, skipped, failed
+	TaskID      string    `gorm:"size:100" json:"task_id"`
+	Message     string    `gorm:"type:text" json:"message"`
+	ScheduledAt time.Time `json:"scheduled_at"` // 计划触发时间
+	CreatedAt   time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (ScheduleRun) TableName() string {
+	return "schedule_runs"
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// 评分规则类型
+const (
+	ScoringRuleLength         = "length"          // 文本长度（字符数）在 [min_length, max_length] 内
+	ScoringRuleRequiredRegex  = "required_regex"  // 必须匹配正则表达式
+	ScoringRuleForbiddenRegex = "forbidden_regex" // 不得匹配正则表达式
+	ScoringRuleJSONSchema     = "json_schema"     // 文本是满足 JSON Schema 的 JSON
+	ScoringRuleLanguage       = "language"        // 主要语言为候选语言之一（| 分隔，如 zh|en）
+)
+
+// 评分规则检查的文本
+const (
+	ScoringTargetAssistant = "assistant" // Assistant 轮次（回答）
+	ScoringTargetHuman     = "human"     // Human 轮次（提问）
+	ScoringTargetAll       = "all"       // 全部轮次
+)
+
+// ScoringRule 生成数据规则评分的规则（管理员按任务类型配置）
+// 任务结束后按任务类型启用的规则检查每条数据，rule_score = 10 × 通过规则的权重和 / 全部规则的权重和
+type ScoringRule struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	TaskType  string    `gorm:"size:50;index" json:"task_type"` // 为空时适用于全部任务类型
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	RuleType  string    `gorm:"size:30;not null" json:"rule_type"`
+	Target    string    `gorm:"size:20;default:assistant" json:"target"`
+	Pattern   string    `gorm:"type:text" json:"pattern"` // 正则表达式、JSON Schema 或候选语言
+	MinLength int       `gorm:"default:0" json:"min_length"`
+	MaxLength int       `gorm:"default:0" json:"max_length"` // 0 表示不限
+	Weight    int       `gorm:"default:1" json:"weight"`
+	IsActive  bool      `gorm:"default:true" json:"is_active"`
+	CreatedBy uint      `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ScoringRule) TableName() string {
+	return "scoring_rules"
+}
+
+// LanguageList 按 | 拆分 language 规则的候选语言
+func (r *ScoringRule) LanguageList() []string {
+	parts := strings.Split(r.Pattern, "|")
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.ToLower(strings.TrimSpace(part)); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
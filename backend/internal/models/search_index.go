@@ -0,0 +1,63 @@
+package models
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// searchFTSEnabled 是否启用了 SQLite FTS5 全文索引
+var searchFTSEnabled bool
+
+// generatedDataFTSStatements 生成数据全文索引：外部内容 FTS5 表 + 同步触发器
+// 使用 trigram 分词以支持中文子串检索；工作进程直接写库时也由触发器同步
+var generatedDataFTSStatements = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS generated_data_fts USING fts5(data_content, content='generated_data', content_rowid='id', tokenize='trigram')`,
+	`CREATE TRIGGER IF NOT EXISTS generated_data_fts_ai AFTER INSERT ON generated_data BEGIN
+		INSERT INTO generated_data_fts(rowid, data_content) VALUES (new.id, new.data_content);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS generated_data_fts_ad AFTER DELETE ON generated_data BEGIN
+		INSERT INTO generated_data_fts(generated_data_fts, rowid, data_content) VALUES ('delete', old.id, old.data_content);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS generated_data_fts_au AFTER UPDATE OF data_content ON generated_data BEGIN
+		INSERT INTO generated_data_fts(generated_data_fts, rowid, data_content) VALUES ('delete', old.id, old.data_content);
+		INSERT INTO generated_data_fts(rowid, data_content) VALUES (new.id, new.data_content);
+	END`,
+}
+
+// SearchFTSEnabled 全文检索是否可以使用 FTS5 索引（否则回退为 LIKE 查询）
+func SearchFTSEnabled() bool {
+	return searchFTSEnabled
+}
+
+// setupSearchIndex 在 SQLite 上创建全文索引
+// 驱动未编译 FTS5（需要 sqlite_fts5 构建标签）或使用 PostgreSQL 时不创建，检索回退为 LIKE
+func setupSearchIndex(db *gorm.DB) {
+	if db.Dialector.Name() != "sqlite" {
+		return
+	}
+
+	var existing int64
+	if err := db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'generated_data_fts'").Scan(&existing).Error; err != nil {
+		log.Printf("[Search] 检查全文索引失败，使用 LIKE 检索: %v", err)
+		return
+	}
+
+	for _, stmt := range generatedDataFTSStatements {
+		if err := db.Exec(stmt).Error; err != nil {
+			log.Printf("[Search] 创建全文索引失败，使用 LIKE 检索: %v", err)
+			return
+		}
+	}
+
+	// 新建索引时为已有数据建立索引
+	if existing == 0 {
+		if err := db.Exec("INSERT INTO generated_data_fts(generated_data_fts) VALUES ('rebuild')").Error; err != nil {
+			log.Printf("[Search] 重建全文索引失败，使用 LIKE 检索: %v", err)
+			return
+		}
+		log.Printf("[Search] 已创建生成数据全文索引")
+	}
+
+	searchFTSEnabled = true
+}
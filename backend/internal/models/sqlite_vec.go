@@ -0,0 +1,12 @@
+//go:build sqlite_vec
+
+package models
+
+import (
+	sqlite_vec "github.com/asg017/sqlite-vec-go-bindings/cgo"
+)
+
+// 使用 sqlite_vec 构建标签时为每个 SQLite 连接注册 sqlite-vec 扩展，语义检索在 SQL 中计算向量距离
+func init() {
+	sqlite_vec.Auto()
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// 打标规则匹配方式
+const (
+	TagMatchKeyword    = "keyword"    // 包含任一关键字（| 分隔，忽略大小写）
+	TagMatchRegex      = "regex"      // 匹配正则表达式
+	TagMatchClassifier = "classifier" // 由裁判模型从候选标签（| 分隔）中选择一个
+)
+
+// TagRule 生成数据自动打标规则
+// 命中后为数据添加 Category:Tag 标签；classifier 规则的标签值由模型选择
+type TagRule struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	Category  string    `gorm:"size:50;not null" json:"category"` // 如 topic、difficulty、domain
+	Tag       string    `gorm:"size:100" json:"tag"`              // keyword/regex 命中时使用的标签值
+	MatchType string    `gorm:"size:20;not null" json:"match_type"`
+	Pattern   string    `gorm:"type:text;not null" json:"pattern"`
+	ModelID   *uint     `json:"model_id"` // classifier 使用的模型配置
+	Priority  int       `gorm:"default:0" json:"priority"`
+	IsActive  bool      `gorm:"default:true" json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (TagRule) TableName() string {
+	return "tag_rules"
+}
+
+// PatternList 按 | 拆分 Pattern（keyword 的关键字或 classifier 的候选标签）
+func (r *TagRule) PatternList() []string {
+	parts := strings.Split(r.Pattern, "|")
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
@@ -11,7 +11,8 @@ type Task struct {
 	ID           uint       `gorm:"primarykey" json:"id"`
 	TaskID       string     `gorm:"uniqueIndex;size:100;not null" json:"task_id"`
 	UserID       uint       `gorm:"not null;index" json:"user_id"`
-	Status       string     `gorm:"size:20;default:'running'" json:"status"` // running, finished, error, stopped
+	WorkspaceID  *uint      `gorm:"index" json:"workspace_id"` // 所属工作区，为空表示个人任务
+	Status       TaskStatus `gorm:"size:20;default:'running';check:chk_tasks_status,status IN ('running','stopped','finished','error','partial','timeout','dead_letter')" json:"status"`
 	Params       JSONMap    `gorm:"type:text" json:"params"`
 	Result       JSONMap    `gorm:"type:text" json:"result"`
 	ErrorMessage string     `gorm:"type:text" json:"error_message"`
@@ -20,6 +21,14 @@ type Task struct {
 	InputChars   int64      `gorm:"default:0" json:"input_chars"`  // 输入字符总数
 	OutputChars  int64      `gorm:"default:0" json:"output_chars"` // 输出字符总数
 
+	// 上游模型返回的Token用量（模型调用代理累计，上游未返回 usage 的调用不计入）
+	PromptTokens     int64 `gorm:"default:0" json:"prompt_tokens"`
+	CompletionTokens int64 `gorm:"default:0" json:"completion_tokens"`
+
+	// 工作进程握手信息（main.py 启动时上报）
+	WorkerVersion  string `gorm:"size:50" json:"worker_version"`
+	WorkerProtocol int    `gorm:"default:0" json:"worker_protocol"`
+
 	// 关联
 	User          User            `gorm:"foreignKey:UserID" json:"user,omitempty"`
 	GeneratedData []GeneratedData `gorm:"foreignKey:TaskID;references:TaskID" json:"generated_data,omitempty"`
@@ -30,6 +39,26 @@ func (Task) TableName() string {
 	return "tasks"
 }
 
+// TaskAttempt 任务的一次执行尝试（瞬时错误自动重试时每次重试都是新任务，同一任务链的尝试共用 RootTaskID）
+type TaskAttempt struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	RootTaskID   string     `gorm:"size:100;not null;index" json:"root_task_id"` // 第一次尝试的任务ID
+	TaskID       string     `gorm:"size:100;not null;index" json:"task_id"`      // 本次尝试的任务ID
+	UserID       uint       `gorm:"not null;index" json:"user_id"`
+	Attempt      int        `gorm:"not null" json:"attempt"`        // 第几次尝试（从 1 开始）
+	Status       TaskStatus `gorm:"size:20;not null" json:"status"` // 本次尝试的结束状态
+	ErrorClass   string     `gorm:"size:50" json:"error_class"`     // 错误类别（timeout、http_5xx 等）
+	ErrorMessage string     `gorm:"type:text" json:"error_message"`
+	NextRetryAt  *time.Time `json:"next_retry_at"` // 计划重试的时间，不再重试时为空
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   time.Time  `json:"finished_at"`
+}
+
+// TableName 指定表名
+func (TaskAttempt) TableName() string {
+	return "task_attempts"
+}
+
 // JSONMap 自定义JSON类型
 type JSONMap map[string]interface{}
 
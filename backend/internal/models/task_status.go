@@ -0,0 +1,126 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TaskStatus 任务状态（内存中的任务上下文与 tasks.status 使用相同的取值）
+type TaskStatus string
+
+// 任务状态
+const (
+	TaskStatusRunning    TaskStatus = "running"
+	TaskStatusStopping   TaskStatus = "stopping" // 已请求停止，正在终止工作进程（只存在于内存中，不写入数据库）
+	TaskStatusStopped    TaskStatus = "stopped"
+	TaskStatusFinished   TaskStatus = "finished"
+	TaskStatusError      TaskStatus = "error"
+	TaskStatusPartial    TaskStatus = "partial"     // 部分完成：任务异常退出，但已有生成数据保存下来
+	TaskStatusTimeout    TaskStatus = "timeout"     // 超时：任务超过最长运行时间（max_runtime_minutes）被终止，已生成的数据会保留
+	TaskStatusDeadLetter TaskStatus = "dead_letter" // 重试用尽：任务因瞬时错误失败且自动重试次数已用完，由管理员排查处理
+)
+
+// ErrInvalidTaskTransition 任务状态转换不合法
+var ErrInvalidTaskTransition = errors.New("任务状态转换不合法")
+
+// persistedTaskStatuses 写入数据库的任务状态（与 Task.Status 上的检查约束 chk_tasks_status 保持一致）
+var persistedTaskStatuses = []TaskStatus{
+	TaskStatusRunning,
+	TaskStatusStopped,
+	TaskStatusFinished,
+	TaskStatusError,
+	TaskStatusPartial,
+	TaskStatusTimeout,
+	TaskStatusDeadLetter,
+}
+
+// taskStatusTransitions 合法的状态转换：运行中只能结束或进入停止流程，停止中只能变为已停止；
+// 失败或超时结束后，按检查点和重试结果细化为 partial、dead_letter
+var taskStatusTransitions = map[TaskStatus][]TaskStatus{
+	TaskStatusRunning:  {TaskStatusStopping, TaskStatusFinished, TaskStatusError, TaskStatusTimeout},
+	TaskStatusStopping: {TaskStatusStopped},
+	TaskStatusError:    {TaskStatusPartial, TaskStatusDeadLetter},
+	TaskStatusTimeout:  {TaskStatusPartial, TaskStatusDeadLetter},
+	TaskStatusPartial:  {TaskStatusDeadLetter},
+}
+
+// FailedTaskStatuses 失败结束的任务状态（含部分完成、超时和重试用尽）
+func FailedTaskStatuses() []TaskStatus {
+	return []TaskStatus{TaskStatusError, TaskStatusPartial, TaskStatusTimeout, TaskStatusDeadLetter}
+}
+
+// ParseTaskStatus 解析任务状态，不是有效的数据库状态时返回错误
+func ParseTaskStatus(s string) (TaskStatus, error) {
+	status := TaskStatus(s)
+	if !status.IsValid() {
+		return "", fmt.Errorf("无效的任务状态: %s", s)
+	}
+	return status, nil
+}
+
+// TaskStatuses 返回所有写入数据库的任务状态
+func TaskStatuses() []TaskStatus {
+	return append([]TaskStatus(nil), persistedTaskStatuses...)
+}
+
+// String 实现 fmt.Stringer
+func (s TaskStatus) String() string {
+	return string(s)
+}
+
+// IsValid 是否为可以写入数据库的任务状态（stopping 只存在于内存中）
+func (s TaskStatus) IsValid() bool {
+	for _, status := range persistedTaskStatuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal 任务是否已结束（运行中和停止中以外的状态）
+func (s TaskStatus) IsTerminal() bool {
+	return s.IsValid() && s != TaskStatusRunning
+}
+
+// CanTransitionTo 判断能否从当前状态直接变为 to
+func (s TaskStatus) CanTransitionTo(to TaskStatus) bool {
+	for _, next := range taskStatusTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CanReach 判断能否经过一次或多次合法转换从当前状态变为 to
+// 数据库只记录最终状态，中间状态可以跳过（如 running 直接变为 partial）
+func (s TaskStatus) CanReach(to TaskStatus) bool {
+	visited := map[TaskStatus]bool{s: true}
+	queue := []TaskStatus{s}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range taskStatusTransitions[current] {
+			if next == to {
+				return true
+			}
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
+// TaskStatusesBefore 返回可以变为 to 的数据库状态（用于条件更新）
+func TaskStatusesBefore(to TaskStatus) []TaskStatus {
+	var from []TaskStatus
+	for _, status := range persistedTaskStatuses {
+		if status.CanReach(to) {
+			from = append(from, status)
+		}
+	}
+	return from
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTaskStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from TaskStatus
+		to   TaskStatus
+		want bool
+	}{
+		{TaskStatusRunning, TaskStatusStopping, true},
+		{TaskStatusRunning, TaskStatusFinished, true},
+		{TaskStatusRunning, TaskStatusError, true},
+		{TaskStatusRunning, TaskStatusTimeout, true},
+		{TaskStatusRunning, TaskStatusStopped, false},
+		{TaskStatusRunning, TaskStatusPartial, false},
+		{TaskStatusStopping, TaskStatusStopped, true},
+		{TaskStatusStopping, TaskStatusFinished, false},
+		{TaskStatusError, TaskStatusPartial, true},
+		{TaskStatusError, TaskStatusDeadLetter, true},
+		{TaskStatusTimeout, TaskStatusPartial, true},
+		{TaskStatusPartial, TaskStatusDeadLetter, true},
+		{TaskStatusPartial, TaskStatusError, false},
+		{TaskStatusFinished, TaskStatusRunning, false},
+		{TaskStatusStopped, TaskStatusRunning, false},
+		{TaskStatusDeadLetter, TaskStatusPartial, false},
+	}
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestTaskStatusesBefore(t *testing.T) {
+	tests := []struct {
+		to   TaskStatus
+		want []TaskStatus
+	}{
+		{TaskStatusFinished, []TaskStatus{TaskStatusRunning}},
+		{TaskStatusStopped, []TaskStatus{TaskStatusRunning}},
+		{TaskStatusPartial, []TaskStatus{TaskStatusRunning, TaskStatusError, TaskStatusTimeout}},
+		{TaskStatusDeadLetter, []TaskStatus{TaskStatusRunning, TaskStatusError, TaskStatusPartial, TaskStatusTimeout}},
+		{TaskStatusRunning, nil},
+	}
+	for _, tt := range tests {
+		if got := TaskStatusesBefore(tt.to); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("TaskStatusesBefore(%s) = %v, want %v", tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestTaskStatusIsTerminal(t *testing.T) {
+	tests := []struct {
+		status TaskStatus
+		want   bool
+	}{
+		{TaskStatusRunning, false},
+		{TaskStatusStopping, false},
+		{TaskStatusStopped, true},
+		{TaskStatusFinished, true},
+		{TaskStatusError, true},
+		{TaskStatusPartial, true},
+		{TaskStatusTimeout, true},
+		{TaskStatusDeadLetter, true},
+		{TaskStatus("unknown"), false},
+	}
+	for _, tt := range tests {
+		if got := tt.status.IsTerminal(); got != tt.want {
+			t.Errorf("%s.IsTerminal() = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestParseTaskStatus(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr bool
+	}{
+		{"running", false},
+		{"dead_letter", false},
+		{"stopping", true},
+		{"", true},
+		{"RUNNING", true},
+	}
+	for _, tt := range tests {
+		_, err := ParseTaskStatus(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTaskStatus(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+	}
+}
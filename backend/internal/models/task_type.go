@@ -0,0 +1,53 @@
+package models
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultTaskType 未指定任务类型时使用的类型（内置，不可删除）
+const DefaultTaskType = "general"
+
+// TaskType 任务类型（管理员维护，启动任务时 task_type 必须是已启用的类型）
+// 未指定提示词的任务使用类型的默认提示词；指定输出格式时任务结束后按 JSON Schema 检查回答并计入规则评分，
+// 类型的其他评分规则见 ScoringRule.TaskType
+type TaskType struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	Name          string    `gorm:"size:50;uniqueIndex;not null" json:"name"`
+	Description   string    `gorm:"size:500" json:"description"`
+	SpecialPrompt string    `gorm:"type:text" json:"special_prompt"` // 默认 special_prompt
+	Directions    string    `gorm:"type:text" json:"directions"`     // 默认 directions
+	OutputSchema  string    `gorm:"type:text" json:"output_schema"`  // 回答的 JSON Schema，为空时不检查
+	IsBuiltin     bool      `gorm:"default:false" json:"is_builtin"`
+	IsActive      bool      `gorm:"default:true" json:"is_active"`
+	CreatedBy     uint      `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (TaskType) TableName() string {
+	return "task_types"
+}
+
+// builtinTaskTypes 内置任务类型（与 Python 工作进程的格式评估器对应）
+var builtinTaskTypes = []TaskType{
+	{Name: "entity_extraction", Description: "实体提取"},
+	{Name: DefaultTaskType, Description: "通用"},
+	{Name: "question_rewrite", Description: "问句改写"},
+	{Name: "calculation", Description: "计算"},
+}
+
+// seedTaskTypes 创建缺失的内置任务类型（已存在的类型保持管理员的修改）
+func seedTaskTypes(db *gorm.DB) {
+	for _, builtin := range builtinTaskTypes {
+		taskType := builtin
+		taskType.IsBuiltin = true
+		taskType.IsActive = true
+		if err := db.Where("name = ?", taskType.Name).FirstOrCreate(&taskType).Error; err != nil {
+			log.Printf("[TaskType] 创建内置任务类型 %s 失败: %v", taskType.Name, err)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// UploadSession 分片上传会话
+type UploadSession struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	UploadID       string    `gorm:"uniqueIndex;size:64;not null" json:"upload_id"`
+	UserID         uint      `gorm:"not null;index" json:"user_id"`
+	Filename       string    `gorm:"size:255;not null" json:"filename"`
+	TotalSize      int64     `gorm:"not null" json:"total_size"`
+	ChunkSize      int64     `gorm:"not null" json:"chunk_size"`
+	TotalChunks    int       `gorm:"not null" json:"total_chunks"`
+	Checksum       string    `gorm:"size:64" json:"checksum"`                   // 整个文件的SHA-256（十六进制）
+	Status         string    `gorm:"size:20;default:'uploading'" json:"status"` // uploading, completed
+	FileID         *uint     `json:"file_id"`                                   // 完成后生成的数据文件ID
+	ValidationMode string    `gorm:"size:20" json:"validation_mode"`            // 完成时使用的结构校验模式（为空则使用配置默认值）
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (UploadSession) TableName() string {
+	return "upload_sessions"
+}
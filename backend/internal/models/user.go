@@ -6,17 +6,21 @@ import (
 
 // User 用户模型
 type User struct {
-	ID           uint      `gorm:"primarykey" json:"id"`
-	Username     string    `gorm:"uniqueIndex;size:50;not null" json:"username"`
-	PasswordHash string    `gorm:"size:255;not null" json:"-"`
-	IsActive     bool      `gorm:"default:true" json:"is_active"`
-	IsAdmin      bool      `gorm:"default:false" json:"is_admin"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                 uint       `gorm:"primarykey" json:"id"`
+	Username           string     `gorm:"uniqueIndex;size:50;not null" json:"username"`
+	PasswordHash       string     `gorm:"size:255;not null" json:"-"`
+	IsActive           bool       `gorm:"default:true" json:"is_active"`
+	IsAdmin            bool       `gorm:"default:false" json:"is_admin"`
+	Role               string     `gorm:"size:20;default:'operator'" json:"role"`    // viewer/reviewer/operator/admin，admin 与 IsAdmin 保持一致
+	Timezone           string     `gorm:"size:64" json:"timezone"`                   // 展示时区（IANA 名称），为空时使用系统默认时区
+	MustChangePassword bool       `gorm:"default:false" json:"must_change_password"` // 首次登录或被管理员重置后需修改密码
+	PasswordChangedAt  *time.Time `json:"password_changed_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
 
 	// 关联
-	Tasks         []Task         `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"tasks,omitempty"`
-	DataFiles     []DataFile     `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"data_files,omitempty"`
+	Tasks         []Task          `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"tasks,omitempty"`
+	DataFiles     []DataFile      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"data_files,omitempty"`
 	GeneratedData []GeneratedData `gorm:"foreignKey:UserID" json:"generated_data,omitempty"`
 }
 
@@ -24,3 +28,14 @@ type User struct {
 func (User) TableName() string {
 	return "users"
 }
+
+// EffectiveRole 获取用户实际角色（IsAdmin 优先；引入角色前的旧数据没有角色时视为默认角色）
+func (u *User) EffectiveRole() string {
+	if u.IsAdmin {
+		return RoleAdmin
+	}
+	if !IsValidRole(u.Role) || u.Role == RoleAdmin {
+		return DefaultRole
+	}
+	return u.Role
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// UserSettings 用户的个人默认设置，启动任务和导出数据时优先于系统默认值
+type UserSettings struct {
+	ID                uint      `gorm:"primarykey" json:"id"`
+	UserID            uint      `gorm:"uniqueIndex;not null" json:"user_id"`
+	DefaultModelID    *uint     `json:"default_model_id"`                     // 启动任务未指定模型时使用的模型
+	DefaultBatchSize  int       `gorm:"default:0" json:"default_batch_size"`  // 0 表示使用系统默认值
+	DefaultDataRounds int       `gorm:"default:0" json:"default_data_rounds"` // 0 表示使用系统默认值
+	ExportFormat      string    `gorm:"size:20" json:"export_format"`         // 导出未指定格式时使用，为空表示 jsonl
+	UIOptions         string    `gorm:"type:text" json:"ui_options"`          // 前端界面选项（JSON 对象，后端不解析）
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (UserSettings) TableName() string {
+	return "user_settings"
+}
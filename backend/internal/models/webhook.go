@@ -0,0 +1,83 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Webhook 事件类型
+const (
+	WebhookEventTaskStarted  = "task.started"
+	WebhookEventTaskFinished = "task.finished"
+	WebhookEventTaskError    = "task.error"
+	WebhookEventTaskStopped  = "task.stopped"
+)
+
+// WebhookEvents 全部支持的事件类型
+var WebhookEvents = []string{
+	WebhookEventTaskStarted,
+	WebhookEventTaskFinished,
+	WebhookEventTaskError,
+	WebhookEventTaskStopped,
+}
+
+// Webhook 用户注册的回调地址
+type Webhook struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"`
+	URL         string    `gorm:"size:500;not null" json:"url"`
+	Secret      string    `gorm:"size:100;not null" json:"-"` // 签名密钥（仅创建时返回）
+	Events      string    `gorm:"size:255" json:"events"`     // 订阅的事件，逗号分隔，为空表示全部
+	Description string    `gorm:"size:255" json:"description"`
+	IsActive    bool      `gorm:"default:true" json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// EventList 获取订阅的事件列表
+func (w *Webhook) EventList() []string {
+	if w.Events == "" {
+		return []string{}
+	}
+	return strings.Split(w.Events, ",")
+}
+
+// Subscribes 判断是否订阅了指定事件
+func (w *Webhook) Subscribes(event string) bool {
+	if w.Events == "" {
+		return true
+	}
+	for _, e := range w.EventList() {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery Webhook 投递记录
+type WebhookDelivery struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	WebhookID    uint       `gorm:"not null;index" json:"webhook_id"`
+	UserID       uint       `gorm:"not null;index" json:"user_id"`
+	Event        string     `gorm:"size:50;not null" json:"event"`
+	TaskID       string     `gorm:"size:100;index" json:"task_id"`
+	Payload      string     `gorm:"type:text" json:"payload"`
+	Success      bool       `gorm:"default:false" json:"success"`
+	StatusCode   int        `json:"status_code"`
+	Attempts     int        `gorm:"default:0" json:"attempts"`
+	ResponseBody string     `gorm:"type:text" json:"response_body"` // 截断后的响应内容
+	ErrorMessage string     `gorm:"type:text" json:"error_message"`
+	CreatedAt    time.Time  `gorm:"index" json:"created_at"`
+	DeliveredAt  *time.Time `json:"delivered_at"`
+}
+
+// TableName 指定表名
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
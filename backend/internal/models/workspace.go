@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+// Workspace 工作区（团队）
+// 数据文件和任务可以归属工作区，工作区成员按成员角色共享查看或修改；生成数据通过所属任务归属工作区
+type Workspace struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	Name        string    `gorm:"size:100;not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	OwnerID     uint      `gorm:"not null;index" json:"owner_id"` // 创建者，不能被移出工作区
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Workspace) TableName() string {
+	return "workspaces"
+}
+
+// WorkspaceMember 工作区成员
+// Role 沿用用户角色：viewer 只读，reviewer 可审核数据，operator 可修改共享资源，admin 还可管理成员
+type WorkspaceMember struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	WorkspaceID uint      `gorm:"not null;uniqueIndex:idx_workspace_member" json:"workspace_id"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_workspace_member;index" json:"user_id"`
+	Role        string    `gorm:"size:20;not null" json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (WorkspaceMember) TableName() string {
+	return "workspace_members"
+}
@@ -0,0 +1,198 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"gen-go/internal/config"
+)
+
+// 外部插件类型
+const (
+	TypeExec    = "exec"    // 启动命令，请求 JSON 写入标准输入，从标准输出读取响应
+	TypeWebhook = "webhook" // POST 请求 JSON 到 url，响应体为结果
+)
+
+// HeaderSignature webhook 插件请求签名：sha256=<HMAC-SHA256(secret, body) 的十六进制>（配置 secret 时发送）
+const HeaderSignature = "X-Plugin-Signature"
+
+// maxResponseBytes 外部插件响应大小上限
+const maxResponseBytes = 512 << 20
+
+// request 发给外部插件的请求
+type request struct {
+	Stage   string                   `json:"stage"`
+	Task    *TaskInfo                `json:"task,omitempty"`
+	Samples []map[string]interface{} `json:"samples,omitempty"`
+	Items   []Item                   `json:"items,omitempty"`
+	Format  string                   `json:"format,omitempty"`
+	Data    []map[string]interface{} `json:"data,omitempty"`
+}
+
+// response 预处理和后处理插件的响应（导出插件直接返回文件内容）
+type response struct {
+	Samples []map[string]interface{} `json:"samples"`
+	Items   []Item                   `json:"items"`
+}
+
+// externalPlugin 通过 exec 或 webhook 调用的插件，按配置的 stage 实现对应的接口
+type externalPlugin struct {
+	cfg    config.PluginConfig
+	client *http.Client
+}
+
+// Name 插件名称
+func (p *externalPlugin) Name() string {
+	return p.cfg.Name
+}
+
+// AppliesTo 未配置 task_types 时对全部任务类型生效
+func (p *externalPlugin) AppliesTo(taskType string) bool {
+	if len(p.cfg.TaskTypes) == 0 {
+		return true
+	}
+	for _, t := range p.cfg.TaskTypes {
+		if t == taskType {
+			return true
+		}
+	}
+	return false
+}
+
+// Preprocess 请求 {"stage": "preprocess", "task": {...}, "samples": [...]}，响应 {"samples": [...]}
+func (p *externalPlugin) Preprocess(ctx context.Context, task TaskInfo, samples []map[string]interface{}) ([]map[string]interface{}, error) {
+	var resp response
+	if err := p.callJSON(ctx, &request{Stage: StagePreprocess, Task: &task, Samples: samples}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Samples, nil
+}
+
+// Postprocess 请求 {"stage": "postprocess", "task": {...}, "items": [{"id", "data"}]}，响应 {"items": [...]}
+func (p *externalPlugin) Postprocess(ctx context.Context, task TaskInfo, items []Item) ([]Item, error) {
+	var resp response
+	if err := p.callJSON(ctx, &request{Stage: StagePostprocess, Task: &task, Items: items}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// Format 导出格式名称
+func (p *externalPlugin) Format() string {
+	return p.cfg.Format
+}
+
+// Extension 导出文件扩展名
+func (p *externalPlugin) Extension() string {
+	return p.cfg.Extension
+}
+
+// Export 请求 {"stage": "export", "format": ..., "data": [...]}，响应内容即导出文件
+func (p *externalPlugin) Export(ctx context.Context, items []map[string]interface{}) ([]byte, error) {
+	return p.call(ctx, &request{Stage: StageExport, Format: p.cfg.Format, Data: items})
+}
+
+// callJSON 调用插件并解析 JSON 响应
+func (p *externalPlugin) callJSON(ctx context.Context, req *request, resp *response) error {
+	out, err := p.call(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(out, resp); err != nil {
+		return fmt.Errorf("插件 %s 返回的不是有效的 JSON: %w", p.cfg.Name, err)
+	}
+	return nil
+}
+
+// call 按插件类型发送请求，返回原始响应
+func (p *externalPlugin) call(ctx context.Context, req *request) ([]byte, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(p.cfg.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	if p.cfg.Type == TypeExec {
+		return p.callExec(ctx, body)
+	}
+	return p.callWebhook(ctx, body)
+}
+
+// callExec 启动命令，请求写入标准输入，退出码非 0 时返回标准错误输出
+func (p *externalPlugin) callExec(ctx context.Context, body []byte) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, p.cfg.Command[0], p.cfg.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(body)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("插件 %s 超时", p.cfg.Name)
+		}
+		return nil, fmt.Errorf("插件 %s 执行失败: %v: %s", p.cfg.Name, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// callWebhook POST 请求到插件地址，非 2xx 响应视为失败
+func (p *externalPlugin) callWebhook(ctx context.Context, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.cfg.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(p.cfg.Secret))
+		mac.Write(body)
+		req.Header.Set(HeaderSignature, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("插件 %s 请求失败: %w", p.cfg.Name, err)
+	}
+	defer resp.Body.Close()
+
+	out, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("插件 %s 读取响应失败: %w", p.cfg.Name, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if len(out) > 512 {
+			out = out[:512]
+		}
+		return nil, fmt.Errorf("插件 %s 返回 HTTP %d: %s", p.cfg.Name, resp.StatusCode, strings.TrimSpace(string(out)))
+	}
+	return out, nil
+}
+
+// RegisterConfigured 将配置 plugins 中声明的 exec/webhook 插件注册到全局注册表（配置已在加载时校验）
+func RegisterConfigured(plugins []config.PluginConfig) error {
+	client := &http.Client{}
+	for _, cfg := range plugins {
+		p := &externalPlugin{cfg: cfg, client: client}
+		switch cfg.Stage {
+		case StagePreprocess:
+			defaultRegistry.RegisterPreprocessor(p, cfg.Type)
+		case StagePostprocess:
+			defaultRegistry.RegisterPostprocessor(p, cfg.Type)
+		case StageExport:
+			if err := defaultRegistry.RegisterExporter(p, cfg.Type); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
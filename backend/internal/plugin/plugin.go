@@ -0,0 +1,67 @@
+// Package plugin 生成流水线的插件扩展点：输入样本预处理、生成数据后处理和自定义导出格式
+//
+// Go 插件在部署自己的源文件（如 cmd/server 下的 plugins_*.go）的 init 中调用 Register* 注册，
+// 无需修改服务层；exec 和 webhook 插件通过配置 plugins 声明，启动时由 RegisterConfigured 注册
+package plugin
+
+import "context"
+
+// 插件阶段
+const (
+	StagePreprocess  = "preprocess"  // 任务启动前处理输入样本
+	StagePostprocess = "postprocess" // 任务结束后处理生成数据
+	StageExport      = "export"      // 自定义导出格式
+)
+
+// TaskInfo 传给插件的任务信息
+type TaskInfo struct {
+	TaskID   string `json:"task_id"`
+	TaskType string `json:"task_type"`
+	UserID   uint   `json:"user_id"`
+}
+
+// Item 一条生成数据
+type Item struct {
+	ID   uint                   `json:"id"`
+	Data map[string]interface{} `json:"data"`
+}
+
+// SamplePreprocessor 输入样本预处理：任务启动前按顺序处理输入文件的全部样本，可修改、删除或新增样本
+type SamplePreprocessor interface {
+	Name() string
+	Preprocess(ctx context.Context, task TaskInfo, samples []map[string]interface{}) ([]map[string]interface{}, error)
+}
+
+// ItemPostprocessor 生成数据后处理：任务结束后按批处理生成数据，
+// 返回的数据按 ID 覆盖原内容，未返回的数据被删除
+type ItemPostprocessor interface {
+	Name() string
+	Postprocess(ctx context.Context, task TaskInfo, items []Item) ([]Item, error)
+}
+
+// Exporter 自定义导出格式，Format 为导出接口的 format 参数
+type Exporter interface {
+	Format() string
+	Extension() string
+	Export(ctx context.Context, items []map[string]interface{}) ([]byte, error)
+}
+
+// TaskTypeFilter 可选接口：预处理和后处理插件实现后只对返回 true 的任务类型生效
+type TaskTypeFilter interface {
+	AppliesTo(taskType string) bool
+}
+
+// Info 已注册插件的描述
+type Info struct {
+	Name   string `json:"name"`
+	Stage  string `json:"stage"`
+	Source string `json:"source"` // go、exec、webhook
+}
+
+// appliesTo 判断插件是否对任务类型生效
+func appliesTo(p interface{}, taskType string) bool {
+	if filter, ok := p.(TaskTypeFilter); ok {
+		return filter.AppliesTo(taskType)
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package plugin
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Registry 插件注册表，预处理和后处理插件按注册顺序依次执行
+type Registry struct {
+	mu             sync.RWMutex
+	preprocessors  []SamplePreprocessor
+	postprocessors []ItemPostprocessor
+	exporters      map[string]Exporter
+	infos          []Info
+}
+
+// NewRegistry 创建插件注册表
+func NewRegistry() *Registry {
+	return &Registry{exporters: make(map[string]Exporter)}
+}
+
+// defaultRegistry 进程内的全局注册表
+var defaultRegistry = NewRegistry()
+
+// Default 获取全局注册表
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// RegisterPreprocessor 在全局注册表中注册输入样本预处理插件
+func RegisterPreprocessor(p SamplePreprocessor) {
+	defaultRegistry.RegisterPreprocessor(p, "go")
+}
+
+// RegisterPostprocessor 在全局注册表中注册生成数据后处理插件
+func RegisterPostprocessor(p ItemPostprocessor) {
+	defaultRegistry.RegisterPostprocessor(p, "go")
+}
+
+// RegisterExporter 在全局注册表中注册导出格式，格式名重复时 panic（在 init 中注册，冲突应在启动时暴露）
+func RegisterExporter(e Exporter) {
+	if err := defaultRegistry.RegisterExporter(e, "go"); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterPreprocessor 注册输入样本预处理插件
+func (r *Registry) RegisterPreprocessor(p SamplePreprocessor, source string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.preprocessors = append(r.preprocessors, p)
+	r.infos = append(r.infos, Info{Name: p.Name(), Stage: StagePreprocess, Source: source})
+}
+
+// RegisterPostprocessor 注册生成数据后处理插件
+func (r *Registry) RegisterPostprocessor(p ItemPostprocessor, source string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.postprocessors = append(r.postprocessors, p)
+	r.infos = append(r.infos, Info{Name: p.Name(), Stage: StagePostprocess, Source: source})
+}
+
+// RegisterExporter 注册导出格式
+func (r *Registry) RegisterExporter(e Exporter, source string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.exporters[e.Format()]; exists {
+		return fmt.Errorf("导出格式 %s 已注册", e.Format())
+	}
+	r.exporters[e.Format()] = e
+	r.infos = append(r.infos, Info{Name: e.Format(), Stage: StageExport, Source: source})
+	return nil
+}
+
+// Preprocessors 获取对任务类型生效的预处理插件
+func (r *Registry) Preprocessors(taskType string) []SamplePreprocessor {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []SamplePreprocessor
+	for _, p := range r.preprocessors {
+		if appliesTo(p, taskType) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// Postprocessors 获取对任务类型生效的后处理插件
+func (r *Registry) Postprocessors(taskType string) []ItemPostprocessor {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []ItemPostprocessor
+	for _, p := range r.postprocessors {
+		if appliesTo(p, taskType) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// Exporter 按格式名获取导出插件
+func (r *Registry) Exporter(format string) (Exporter, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.exporters[format]
+	return e, ok
+}
+
+// List 获取已注册插件列表（按注册顺序）
+func (r *Registry) List() []Info {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]Info(nil), r.infos...)
+}
@@ -0,0 +1,33 @@
+//go:build !windows
+
+// Package procgroup 工作进程的进程组管理（后端本机运行和工作节点运行 main.py 时共用）
+package procgroup
+
+import (
+	"errors"
+	"log"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// Configure 让工作进程成为新进程组的组长，取消时向整个进程组发送 SIGTERM，
+// 宽限期后仍未退出则发送 SIGKILL，避免 main.py 派生的子进程在任务停止后继续调用模型
+func Configure(cmd *exec.Cmd, grace time.Duration) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		pgid := cmd.Process.Pid
+		log.Printf("[Worker] 向进程组 %d 发送 SIGTERM，宽限期 %v", pgid, grace)
+		if err := syscall.Kill(-pgid, syscall.SIGTERM); err != nil && !errors.Is(err, syscall.ESRCH) {
+			return err
+		}
+		time.AfterFunc(grace, func() {
+			if err := syscall.Kill(-pgid, syscall.SIGKILL); err == nil {
+				log.Printf("[Worker] 进程组 %d 宽限期内未退出，已发送 SIGKILL", pgid)
+			}
+		})
+		return nil
+	}
+	// 进程组被强制结束后再关闭输出管道，防止残留子进程持有管道导致 Wait 阻塞
+	cmd.WaitDelay = grace + time.Second
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package procgroup
+
+import (
+	"os/exec"
+	"time"
+)
+
+// Configure Windows 不支持进程组信号，取消时直接结束工作进程
+func Configure(cmd *exec.Cmd, grace time.Duration) {
+	cmd.WaitDelay = grace
+}
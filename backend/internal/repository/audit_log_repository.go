@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"time"
+
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AuditLogFilter 审计日志查询条件
+type AuditLogFilter struct {
+	UserID       uint
+	Action       string
+	ResourceType string
+	ResourceID   string
+	StartTime    *time.Time
+	EndTime      *time.Time
+}
+
+// AuditLogRepository 审计日志数据访问层
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository 创建审计日志Repository
+func NewAuditLogRepository(db *gorm.DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+// Create 创建审计记录
+func (r *AuditLogRepository) Create(entry *models.AuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+// List 按条件分页查询审计记录（按时间倒序）
+func (r *AuditLogRepository) List(filter AuditLogFilter, offset, limit int) ([]models.AuditLog, int64, error) {
+	var entries []models.AuditLog
+	var total int64
+
+	query := models.ReadReplica(r.db).Model(&models.AuditLog{})
+	if filter.UserID > 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.ResourceType != "" {
+		query = query.Where("resource_type = ?", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		query = query.Where("resource_id = ?", filter.ResourceID)
+	}
+	if filter.StartTime != nil {
+		query = query.Where("created_at >= ?", *filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		query = query.Where("created_at < ?", *filter.EndTime)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&entries).Error
+	return entries, total, err
+}
@@ -1,11 +1,27 @@
 package repository
 
 import (
+	"log"
+
 	"gen-go/internal/models"
 
 	"gorm.io/gorm"
 )
 
+// DataFileFilter 文件列表过滤条件（零值字段不过滤）
+type DataFileFilter struct {
+	WorkspaceID *uint
+	Folder      *string  // 只返回该文件夹中的文件（空字符串表示根目录）
+	Recursive   bool     // 同时返回 Folder 子文件夹中的文件
+	Tags        []string // 只返回同时带有这些标签的文件
+}
+
+// FolderCount 文件夹及其中的文件数（不含子文件夹）
+type FolderCount struct {
+	FolderPath string
+	FileCount  int64
+}
+
 // DataFileRepository 数据文件数据访问层
 type DataFileRepository struct {
 	db *gorm.DB
@@ -18,7 +34,9 @@ func NewDataFileRepository(db *gorm.DB) *DataFileRepository {
 
 // Create 创建文件
 func (r *DataFileRepository) Create(file *models.DataFile) error {
-	return r.db.Create(file).Error
+	return withoutStoredContent(file, func() error {
+		return r.db.Create(file).Error
+	})
 }
 
 // GetByID 根据ID获取文件
@@ -28,32 +46,151 @@ func (r *DataFileRepository) GetByID(id uint) (*models.DataFile, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &file, nil
+	return &file, file.LoadContent()
 }
 
-// GetByIDAndUserID 根据ID和用户ID获取文件
+// GetByIDAndUserID 获取用户可查看的文件（本人上传的或所在工作区共享的）
 func (r *DataFileRepository) GetByIDAndUserID(id uint, userID uint) (*models.DataFile, error) {
 	var file models.DataFile
-	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&file).Error
+	err := r.db.Scopes(VisibleTo(userID)).Where("id = ?", id).First(&file).Error
+	if err != nil {
+		return nil, err
+	}
+	return &file, file.LoadContent()
+}
+
+// GetEditableByIDAndUserID 获取用户可修改的文件（本人上传的，或在所属工作区拥有 operate 权限）
+func (r *DataFileRepository) GetEditableByIDAndUserID(id uint, userID uint) (*models.DataFile, error) {
+	var file models.DataFile
+	err := r.db.Scopes(EditableBy(userID)).Where("id = ?", id).First(&file).Error
 	if err != nil {
 		return nil, err
 	}
-	return &file, nil
+	return &file, file.LoadContent()
+}
+
+// UpdateInfo 修改文件的名称、描述等元信息（不修改文件内容）
+func (r *DataFileRepository) UpdateInfo(id uint, updates map[string]interface{}) error {
+	return r.db.Model(&models.DataFile{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// FilenameExists 判断用户是否已有同名文件（excludeID 为要排除的文件）
+func (r *DataFileRepository) FilenameExists(userID uint, filename string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.DataFile{}).
+		Where("user_id = ? AND filename = ? AND id <> ?", userID, filename, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateFolder 批量修改文件所在的文件夹
+func (r *DataFileRepository) UpdateFolder(ids []uint, folderPath string) error {
+	return r.db.Model(&models.DataFile{}).Where("id IN ?", ids).Update("folder_path", folderPath).Error
+}
+
+// ListFolders 获取用户可查看的文件所在的文件夹及文件数（不含根目录，按路径排序）
+func (r *DataFileRepository) ListFolders(userID uint, workspaceID *uint) ([]FolderCount, error) {
+	query := models.ReadReplica(r.db).Model(&models.DataFile{}).Scopes(VisibleTo(userID)).Where("folder_path <> ?", "")
+	if workspaceID != nil {
+		query = query.Where("workspace_id = ?", *workspaceID)
+	}
+
+	var rows []FolderCount
+	err := query.Select("folder_path, COUNT(*) AS file_count").
+		Group("folder_path").
+		Order("folder_path").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// UpdateWorkspace 修改文件所属工作区（nil 表示转为个人文件）
+func (r *DataFileRepository) UpdateWorkspace(id uint, workspaceID *uint) error {
+	return r.db.Model(&models.DataFile{}).Where("id = ?", id).Update("workspace_id", workspaceID).Error
 }
 
 // Update 更新文件
 func (r *DataFileRepository) Update(file *models.DataFile) error {
-	return r.db.Save(file).Error
+	return withoutStoredContent(file, func() error {
+		return r.db.Save(file).Error
+	})
 }
 
-// Delete 删除文件
+// withoutStoredContent 内容保存在文件存储中时，写库期间清空 FileContent，数据库只保存存储路径
+func withoutStoredContent(file *models.DataFile, write func() error) error {
+	if file.StoragePath == "" {
+		return write()
+	}
+	content := file.FileContent
+	file.FileContent = []byte{}
+	defer func() { file.FileContent = content }()
+	return write()
+}
+
+// Delete 删除文件及其全部版本
 func (r *DataFileRepository) Delete(id uint) error {
-	return r.db.Delete(&models.DataFile{}, id).Error
+	return r.DeleteByIDs([]uint{id})
 }
 
-// DeleteByIDs 批量删除文件
+// DeleteByIDs 批量删除文件及其全部版本，不再被引用的存储文件一并删除
 func (r *DataFileRepository) DeleteByIDs(ids []uint) error {
-	return r.db.Delete(&models.DataFile{}, ids).Error
+	var storagePaths []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.DataFile{}).Where("id IN ? AND storage_path <> ''", ids).
+			Pluck("storage_path", &storagePaths).Error; err != nil {
+			return err
+		}
+		var versionPaths []string
+		if err := tx.Model(&models.DataFileVersion{}).Where("file_id IN ? AND storage_path <> ''", ids).
+			Pluck("storage_path", &versionPaths).Error; err != nil {
+			return err
+		}
+		storagePaths = append(storagePaths, versionPaths...)
+
+		if err := tx.Where("file_id IN ?", ids).Delete(&models.DataFileVersion{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("file_id IN ?", ids).Delete(&models.DataFileTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.DataFile{}, ids).Error
+	})
+	if err != nil {
+		return err
+	}
+	r.removeUnreferenced(storagePaths)
+	return nil
+}
+
+// removeUnreferenced 删除已没有文件或版本引用的存储文件（版本快照等可能与其他文件共用同一存储文件）
+func (r *DataFileRepository) removeUnreferenced(storagePaths []string) {
+	seen := make(map[string]bool, len(storagePaths))
+	for _, path := range storagePaths {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		var files, versions int64
+		if err := r.db.Model(&models.DataFile{}).Where("storage_path = ?", path).Count(&files).Error; err != nil {
+			log.Printf("[DataFile] 检查存储文件 %s 的引用失败: %v", path, err)
+			continue
+		}
+		if err := r.db.Model(&models.DataFileVersion{}).Where("storage_path = ?", path).Count(&versions).Error; err != nil {
+			log.Printf("[DataFile] 检查存储文件 %s 的引用失败: %v", path, err)
+			continue
+		}
+		if files+versions > 0 {
+			continue
+		}
+		if err := models.RemoveFromFileStore(path); err != nil {
+			log.Printf("[DataFile] 删除存储文件 %s 失败: %v", path, err)
+		}
+	}
+}
+
+// notSnapshot 排除任务输入快照（快照只供工作进程读取，不出现在文件列表、搜索和统计中）
+func notSnapshot(db *gorm.DB) *gorm.DB {
+	return db.Where("snapshot = ?", false)
 }
 
 // List 获取文件列表
@@ -61,20 +198,42 @@ func (r *DataFileRepository) List(offset, limit int) ([]models.DataFile, int64,
 	var files []models.DataFile
 	var total int64
 
-	if err := r.db.Model(&models.DataFile{}).Count(&total).Error; err != nil {
+	if err := models.ReadReplica(r.db).Model(&models.DataFile{}).Scopes(notSnapshot).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := r.db.Preload("User").Order("created_at DESC").Offset(offset).Limit(limit).Find(&files).Error
+	err := models.ReadReplica(r.db).Scopes(notSnapshot).Preload("User").Order("created_at DESC").Offset(offset).Limit(limit).Find(&files).Error
 	return files, total, err
 }
 
-// ListByUserID 获取用户的文件列表
-func (r *DataFileRepository) ListByUserID(userID uint, offset, limit int) ([]models.DataFile, int64, error) {
+// ListByUserID 获取用户可查看的文件列表（按 filter 过滤）
+func (r *DataFileRepository) ListByUserID(userID uint, filter *DataFileFilter, offset, limit int) ([]models.DataFile, int64, error) {
 	var files []models.DataFile
 	var total int64
 
-	query := r.db.Model(&models.DataFile{}).Where("user_id = ?", userID)
+	query := models.ReadReplica(r.db).Model(&models.DataFile{}).Scopes(VisibleTo(userID), notSnapshot)
+	if filter.WorkspaceID != nil {
+		query = query.Where("workspace_id = ?", *filter.WorkspaceID)
+	}
+	if filter.Folder != nil {
+		folder := *filter.Folder
+		switch {
+		case !filter.Recursive:
+			query = query.Where("folder_path = ?", folder)
+		case folder != "":
+			query = query.Where("(folder_path = ? OR folder_path LIKE ? ESCAPE '\\')", folder, escapeLike(folder)+"/%")
+		}
+	}
+	if len(filter.Tags) > 0 {
+		// 文件需带有全部指定标签
+		tagged := r.db.Table("data_file_tags").
+			Select("data_file_tags.file_id").
+			Joins("JOIN file_tags ON file_tags.id = data_file_tags.tag_id").
+			Where("file_tags.name IN ?", filter.Tags).
+			Group("data_file_tags.file_id").
+			Having("COUNT(DISTINCT file_tags.id) = ?", len(filter.Tags))
+		query = query.Where("id IN (?)", tagged)
+	}
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
@@ -86,6 +245,27 @@ func (r *DataFileRepository) ListByUserID(userID uint, offset, limit int) ([]mod
 // GetByIDs 根据ID列表获取文件
 func (r *DataFileRepository) GetByIDs(ids []uint) ([]models.DataFile, error) {
 	var files []models.DataFile
-	err := r.db.Where("id IN ?", ids).Find(&files).Error
+	if err := r.db.Where("id IN ?", ids).Find(&files).Error; err != nil {
+		return nil, err
+	}
+	for i := range files {
+		if err := files[i].LoadContent(); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// ListInfoByIDsAndUserID 获取ID列表中用户可查看的文件元信息（不加载文件内容）
+func (r *DataFileRepository) ListInfoByIDsAndUserID(ids []uint, userID uint) ([]models.DataFile, error) {
+	var files []models.DataFile
+	err := r.db.Omit("file_content").Scopes(VisibleTo(userID)).Where("id IN ?", ids).Find(&files).Error
 	return files, err
 }
+
+// CountByIDsAndUserID 统计ID列表中用户可查看的文件数量（不加载文件内容）
+func (r *DataFileRepository) CountByIDsAndUserID(ids []uint, userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.DataFile{}).Scopes(VisibleTo(userID)).Where("id IN ?", ids).Count(&count).Error
+	return count, err
+}
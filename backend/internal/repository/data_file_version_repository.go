@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// DataFileVersionRepository 数据文件版本数据访问层
+type DataFileVersionRepository struct {
+	db *gorm.DB
+}
+
+// NewDataFileVersionRepository 创建数据文件版本Repository
+func NewDataFileVersionRepository(db *gorm.DB) *DataFileVersionRepository {
+	return &DataFileVersionRepository{db: db}
+}
+
+// Create 为文件追加新版本（版本号在事务内递增），并更新文件的当前版本号
+func (r *DataFileVersionRepository) Create(version *models.DataFileVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&models.DataFileVersion{}).
+			Where("file_id = ?", version.FileID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		version.Version = latest + 1
+		content := version.Content
+		if version.StoragePath != "" {
+			// 内容在文件存储中，数据库只保存存储路径
+			version.Content = []byte{}
+		}
+		err := tx.Create(version).Error
+		version.Content = content
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.DataFile{}).Where("id = ?", version.FileID).
+			UpdateColumn("current_version", version.Version).Error
+	})
+}
+
+// CountByFileID 统计文件的版本数量
+func (r *DataFileVersionRepository) CountByFileID(fileID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.DataFileVersion{}).Where("file_id = ?", fileID).Count(&count).Error
+	return count, err
+}
+
+// ListByFileID 获取文件的版本历史（新版本在前，不加载内容）
+func (r *DataFileVersionRepository) ListByFileID(fileID uint) ([]models.DataFileVersion, error) {
+	var versions []models.DataFileVersion
+	err := r.db.Omit("content").Where("file_id = ?", fileID).Order("version DESC").Find(&versions).Error
+	return versions, err
+}
+
+// GetByFileIDAndVersion 获取文件的指定版本（含内容）
+func (r *DataFileVersionRepository) GetByFileIDAndVersion(fileID uint, version int) (*models.DataFileVersion, error) {
+	var v models.DataFileVersion
+	err := r.db.Where("file_id = ? AND version = ?", fileID, version).First(&v).Error
+	if err != nil {
+		return nil, err
+	}
+	return &v, v.LoadContent()
+}
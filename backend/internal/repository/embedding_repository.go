@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EmbeddingRepository 数据向量数据访问层
+type EmbeddingRepository struct {
+	db *gorm.DB
+}
+
+// NewEmbeddingRepository 创建数据向量Repository
+func NewEmbeddingRepository(db *gorm.DB) *EmbeddingRepository {
+	return &EmbeddingRepository{db: db}
+}
+
+// EmbeddingKey 数据条目在向量表中的位置
+type EmbeddingKey struct {
+	SourceID  uint
+	ItemIndex int
+}
+
+// EmbeddingFilter 向量检索范围，零值字段不作为条件
+type EmbeddingFilter struct {
+	ModelID    uint
+	Dim        int
+	UserID     uint
+	SourceType string
+	SourceID   uint   // 数据文件 ID（SourceType 为 file 时）
+	TaskID     string // 生成数据所属任务
+}
+
+// EmbeddingMatch 向量检索命中的条目
+type EmbeddingMatch struct {
+	SourceType string
+	SourceID   uint
+	ItemIndex  int
+	TaskID     string
+	Similarity float64 // 余弦相似度
+}
+
+// SaveBatch 写入一批向量，同一模型下相同条目的向量被覆盖；pgvector 可用时同步写入 embedding_vec 列
+func (r *EmbeddingRepository) SaveBatch(rows []models.Embedding) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "model_id"}, {Name: "source_type"}, {Name: "source_id"}, {Name: "item_index"}},
+			DoUpdates: clause.AssignmentColumns([]string{"user_id", "task_id", "content_hash", "dim", "vector", "created_at"}),
+		}).Create(&rows).Error
+		if err != nil {
+			return err
+		}
+		if models.VectorEngine() != models.VectorEnginePGVector {
+			return nil
+		}
+		for _, row := range rows {
+			err := tx.Exec(`UPDATE embeddings SET embedding_vec = CAST(? AS vector)
+				WHERE model_id = ? AND source_type = ? AND source_id = ? AND item_index = ?`,
+				pgVectorLiteral(models.DecodeVector(row.Vector)), row.ModelID, row.SourceType, row.SourceID, row.ItemIndex).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ContentHashes 获取范围内已有向量的内容指纹，用于跳过内容未变的条目
+func (r *EmbeddingRepository) ContentHashes(filter EmbeddingFilter) (map[EmbeddingKey]string, error) {
+	var rows []models.Embedding
+	err := r.db.Model(&models.Embedding{}).Scopes(embeddingScope(filter)).
+		Select("source_id", "item_index", "content_hash").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	hashes := make(map[EmbeddingKey]string, len(rows))
+	for _, row := range rows {
+		hashes[EmbeddingKey{SourceID: row.SourceID, ItemIndex: row.ItemIndex}] = row.ContentHash
+	}
+	return hashes, nil
+}
+
+// DeleteFileItemsFrom 删除文件中下标不小于 count 的条目向量（文件内容变短后清理）
+func (r *EmbeddingRepository) DeleteFileItemsFrom(modelID, fileID uint, count int) error {
+	return r.db.Where("model_id = ? AND source_type = ? AND source_id = ? AND item_index >= ?",
+		modelID, models.EmbeddingSourceFile, fileID, count).Delete(&models.Embedding{}).Error
+}
+
+// DeleteOrphans 删除所属数据文件或生成数据已被删除的向量，返回删除的条数
+func (r *EmbeddingRepository) DeleteOrphans() (int64, error) {
+	files := r.db.Where("source_type = ? AND source_id NOT IN (?)",
+		models.EmbeddingSourceFile, r.db.Model(&models.DataFile{}).Select("id")).Delete(&models.Embedding{})
+	if files.Error != nil {
+		return 0, files.Error
+	}
+	generated := r.db.Where("source_type = ? AND source_id NOT IN (?)",
+		models.EmbeddingSourceGenerated, r.db.Model(&models.GeneratedData{}).Select("id")).Delete(&models.Embedding{})
+	return files.RowsAffected + generated.RowsAffected, generated.Error
+}
+
+// Count 统计范围内的向量数
+func (r *EmbeddingRepository) Count(filter EmbeddingFilter) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Embedding{}).Scopes(embeddingScope(filter)).Count(&count).Error
+	return count, err
+}
+
+// Nearest 在数据库中按余弦距离检索最相近的 limit 个条目（需要 sqlite-vec 或 pgvector）
+func (r *EmbeddingRepository) Nearest(filter EmbeddingFilter, query []float32, limit int) ([]EmbeddingMatch, error) {
+	db := models.ReadReplica(r.db).Model(&models.Embedding{}).Scopes(embeddingScope(filter))
+	var distance string
+	var arg interface{}
+	if models.VectorEngine() == models.VectorEnginePGVector {
+		distance = "(embedding_vec <=> CAST(? AS vector))"
+		arg = pgVectorLiteral(query)
+		db = db.Where("embedding_vec IS NOT NULL")
+	} else {
+		distance = "vec_distance_cosine(vector, ?)"
+		arg = models.EncodeVector(query)
+	}
+
+	var matches []EmbeddingMatch
+	err := db.
+		Select("source_type, source_id, item_index, task_id, 1 - "+distance+" AS similarity", arg).
+		Order(clause.Expr{SQL: distance, Vars: []interface{}{arg}}).
+		Limit(limit).Scan(&matches).Error
+	return matches, err
+}
+
+// ScanVectors 按ID升序分批读取范围内的向量（未启用向量扩展时在进程内计算相似度）
+func (r *EmbeddingRepository) ScanVectors(filter EmbeddingFilter, batchSize int, fn func(batch []models.Embedding) error) error {
+	var batch []models.Embedding
+	return models.ReadReplica(r.db).Scopes(embeddingScope(filter)).
+		Select("id", "source_type", "source_id", "item_index", "task_id", "vector").
+		Order("id ASC").
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+// embeddingScope 将检索范围转换为查询条件
+func embeddingScope(filter EmbeddingFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("model_id = ?", filter.ModelID)
+		if filter.Dim > 0 {
+			db = db.Where("dim = ?", filter.Dim)
+		}
+		if filter.UserID > 0 {
+			db = db.Where("user_id = ?", filter.UserID)
+		}
+		if filter.SourceType != "" {
+			db = db.Where("source_type = ?", filter.SourceType)
+		}
+		if filter.SourceID > 0 {
+			db = db.Where("source_id = ?", filter.SourceID)
+		}
+		if filter.TaskID != "" {
+			db = db.Where("task_id = ?", filter.TaskID)
+		}
+		return db
+	}
+}
+
+// pgVectorLiteral 将向量转换为 pgvector 的文本格式 [1,2,3]
+func pgVectorLiteral(vector []float32) string {
+	parts := make([]string, len(vector))
+	for i, v := range vector {
+		parts[i] = strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"time"
+
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ExportAuditFilter 导出审计查询条件
+type ExportAuditFilter struct {
+	UserID       uint
+	ResourceType string
+	ResourceID   string
+	StartTime    *time.Time
+	EndTime      *time.Time
+}
+
+// ExportAuditSummary 按用户汇总的导出统计
+type ExportAuditSummary struct {
+	UserID       uint      `json:"user_id"`
+	Username     string    `json:"username"`
+	ExportCount  int64     `json:"export_count"`
+	TotalRows    int64     `json:"total_rows"`
+	LastExportAt time.Time `json:"last_export_at"`
+}
+
+// ExportAuditRepository 导出审计数据访问层
+type ExportAuditRepository struct {
+	db *gorm.DB
+}
+
+// NewExportAuditRepository 创建导出审计Repository
+func NewExportAuditRepository(db *gorm.DB) *ExportAuditRepository {
+	return &ExportAuditRepository{db: db}
+}
+
+// Create 创建审计记录
+func (r *ExportAuditRepository) Create(audit *models.ExportAudit) error {
+	return r.db.Create(audit).Error
+}
+
+// List 按条件分页查询审计记录（按时间倒序）
+func (r *ExportAuditRepository) List(filter ExportAuditFilter, offset, limit int) ([]models.ExportAudit, int64, error) {
+	var audits []models.ExportAudit
+	var total int64
+
+	query := r.applyFilter(models.ReadReplica(r.db).Model(&models.ExportAudit{}), filter)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&audits).Error
+	return audits, total, err
+}
+
+// SummarizeByUser 按用户汇总导出次数和行数
+func (r *ExportAuditRepository) SummarizeByUser(filter ExportAuditFilter) ([]ExportAuditSummary, error) {
+	var rows []struct {
+		UserID       uint
+		Username     string
+		ExportCount  int64
+		TotalRows    int64
+		LastExportAt string
+	}
+
+	err := r.applyFilter(models.ReadReplica(r.db).Model(&models.ExportAudit{}), filter).
+		Select("user_id, MAX(username) AS username, COUNT(*) AS export_count, COALESCE(SUM(row_count), 0) AS total_rows, MAX(created_at) AS last_export_at").
+		Group("user_id").
+		Order("export_count DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]ExportAuditSummary, len(rows))
+	for i, row := range rows {
+		summaries[i] = ExportAuditSummary{
+			UserID:       row.UserID,
+			Username:     row.Username,
+			ExportCount:  row.ExportCount,
+			TotalRows:    row.TotalRows,
+			LastExportAt: parseSQLiteTime(row.LastExportAt),
+		}
+	}
+	return summaries, nil
+}
+
+// applyFilter 应用查询条件
+func (r *ExportAuditRepository) applyFilter(query *gorm.DB, filter ExportAuditFilter) *gorm.DB {
+	if filter.UserID > 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.ResourceType != "" {
+		query = query.Where("resource_type = ?", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		query = query.Where("resource_id = ?", filter.ResourceID)
+	}
+	if filter.StartTime != nil {
+		query = query.Where("created_at >= ?", *filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		query = query.Where("created_at < ?", *filter.EndTime)
+	}
+	return query
+}
+
+// parseSQLiteTime 解析SQLite聚合函数返回的时间字符串
+func parseSQLiteTime(value string) time.Time {
+	layouts := []string{
+		"2006-01-02 15:04:05.999999999-07:00",
+		"2006-01-02T15:04:05.999999999-07:00",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02 15:04:05",
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FileTagCount 标签及使用该标签的文件数
+type FileTagCount struct {
+	Name      string
+	FileCount int64
+}
+
+// FileTagRepository 数据文件标签数据访问层
+type FileTagRepository struct {
+	db *gorm.DB
+}
+
+// NewFileTagRepository 创建数据文件标签Repository
+func NewFileTagRepository(db *gorm.DB) *FileTagRepository {
+	return &FileTagRepository{db: db}
+}
+
+// GetOrCreate 按名称获取标签，不存在的标签会被创建
+func (r *FileTagRepository) GetOrCreate(names []string) ([]models.FileTag, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	tags := make([]models.FileTag, len(names))
+	for i, name := range names {
+		tags[i] = models.FileTag{Name: name}
+	}
+	if err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true,
+	}).Create(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	var result []models.FileTag
+	err := r.db.Where("name IN ?", names).Find(&result).Error
+	return result, err
+}
+
+// GetByNames 按名称获取已存在的标签
+func (r *FileTagRepository) GetByNames(names []string) ([]models.FileTag, error) {
+	var tags []models.FileTag
+	if len(names) == 0 {
+		return tags, nil
+	}
+	err := r.db.Where("name IN ?", names).Find(&tags).Error
+	return tags, err
+}
+
+// ReplaceFileTags 替换文件的全部标签
+func (r *FileTagRepository) ReplaceFileTags(fileID uint, tagIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("file_id = ?", fileID).Delete(&models.DataFileTag{}).Error; err != nil {
+			return err
+		}
+		return addFileTags(tx, []uint{fileID}, tagIDs)
+	})
+}
+
+// AddFileTags 为文件批量添加标签（已有的关联跳过）
+func (r *FileTagRepository) AddFileTags(fileIDs []uint, tagIDs []uint) error {
+	return addFileTags(r.db, fileIDs, tagIDs)
+}
+
+// addFileTags 写入文件与标签的关联
+func addFileTags(db *gorm.DB, fileIDs []uint, tagIDs []uint) error {
+	links := make([]models.DataFileTag, 0, len(fileIDs)*len(tagIDs))
+	for _, fileID := range fileIDs {
+		for _, tagID := range tagIDs {
+			links = append(links, models.DataFileTag{FileID: fileID, TagID: tagID})
+		}
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(links, 500).Error
+}
+
+// RemoveFileTags 批量移除文件的标签
+func (r *FileTagRepository) RemoveFileTags(fileIDs []uint, tagIDs []uint) error {
+	if len(fileIDs) == 0 || len(tagIDs) == 0 {
+		return nil
+	}
+	return r.db.Where("file_id IN ? AND tag_id IN ?", fileIDs, tagIDs).Delete(&models.DataFileTag{}).Error
+}
+
+// ListNamesByFileIDs 获取文件的标签名（按名称排序）
+func (r *FileTagRepository) ListNamesByFileIDs(fileIDs []uint) (map[uint][]string, error) {
+	var rows []struct {
+		FileID uint
+		Name   string
+	}
+	names := make(map[uint][]string, len(fileIDs))
+	if len(fileIDs) == 0 {
+		return names, nil
+	}
+	err := r.db.Table("data_file_tags").
+		Select("data_file_tags.file_id, file_tags.name").
+		Joins("JOIN file_tags ON file_tags.id = data_file_tags.tag_id").
+		Where("data_file_tags.file_id IN ?", fileIDs).
+		Order("file_tags.name").
+		Scan(&rows).Error
+	for _, row := range rows {
+		names[row.FileID] = append(names[row.FileID], row.Name)
+	}
+	return names, err
+}
+
+// ListWithCounts 获取用户可查看的文件上使用的标签及文件数（按名称排序）
+func (r *FileTagRepository) ListWithCounts(userID uint) ([]FileTagCount, error) {
+	visibleFiles := r.db.Model(&models.DataFile{}).Select("id").Scopes(VisibleTo(userID))
+
+	var rows []FileTagCount
+	err := models.ReadReplica(r.db).Table("data_file_tags").
+		Select("file_tags.name AS name, COUNT(*) AS file_count").
+		Joins("JOIN file_tags ON file_tags.id = data_file_tags.tag_id").
+		Where("data_file_tags.file_id IN (?)", visibleFiles).
+		Group("file_tags.name").
+		Order("file_tags.name").
+		Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// FileValidationRepository 文件校验记录数据访问层
+type FileValidationRepository struct {
+	db *gorm.DB
+}
+
+// NewFileValidationRepository 创建文件校验记录Repository
+func NewFileValidationRepository(db *gorm.DB) *FileValidationRepository {
+	return &FileValidationRepository{db: db}
+}
+
+// Create 创建校验记录
+func (r *FileValidationRepository) Create(validation *models.FileValidation) error {
+	return r.db.Create(validation).Error
+}
+
+// GetLatestByFileID 获取文件最近一次校验记录
+func (r *FileValidationRepository) GetLatestByFileID(fileID uint) (*models.FileValidation, error) {
+	var validation models.FileValidation
+	err := r.db.Where("file_id = ?", fileID).Order("created_at DESC").First(&validation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &validation, nil
+}
+
+// DeleteByFileID 删除文件的全部校验记录
+func (r *FileValidationRepository) DeleteByFileID(fileID uint) error {
+	return r.db.Where("file_id = ?", fileID).Delete(&models.FileValidation{}).Error
+}
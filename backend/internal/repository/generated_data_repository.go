@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"strings"
+	"time"
+
 	"gen-go/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GeneratedDataRepository 生成数据数据访问层
@@ -22,11 +26,15 @@ func (r *GeneratedDataRepository) Create(data *models.GeneratedData) error {
 }
 
 // CreateBatch 批量创建数据
+// 带幂等键的数据按幂等键去重：重试写入同一条样本时保留已有记录（不覆盖审核修改和确认状态），不会插入重复行
 func (r *GeneratedDataRepository) CreateBatch(dataList []models.GeneratedData) error {
 	if len(dataList) == 0 {
 		return nil
 	}
-	return r.db.Create(&dataList).Error
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "idempotency_key"}},
+		DoNothing: true,
+	}).Create(&dataList).Error
 }
 
 // GetByID 根据ID获取数据
@@ -54,21 +62,77 @@ func (r *GeneratedDataRepository) UpdateBatch(dataList []models.GeneratedData) e
 
 // Delete 删除数据
 func (r *GeneratedDataRepository) Delete(id uint) error {
-	return r.db.Delete(&models.GeneratedData{}, id).Error
+	_, err := r.DeleteByIDs([]uint{id})
+	return err
 }
 
-// DeleteByIDs 批量删除数据
+// DeleteByIDs 批量删除数据（同时写入删除记录，供增量同步使用）
 func (r *GeneratedDataRepository) DeleteByIDs(ids []uint) (int64, error) {
-	result := r.db.Delete(&models.GeneratedData{}, ids)
-	if result.Error != nil {
-		return 0, result.Error
+	var deleted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var tombstones []models.GeneratedDataTombstone
+		if err := tx.Model(&models.GeneratedData{}).Select("id AS data_id", "task_id").Where("id IN ?", ids).Scan(&tombstones).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&models.GeneratedData{}, ids)
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+
+		if len(tombstones) == 0 {
+			return nil
+		}
+		now := time.Now()
+		for i := range tombstones {
+			tombstones[i].DeletedAt = now
+		}
+		return tx.CreateInBatches(tombstones, 500).Error
+	})
+	if err != nil {
+		return 0, err
 	}
-	return result.RowsAffected, nil
+	return deleted, nil
 }
 
-// DeleteByTaskID 根据任务ID删除数据
+// DeleteByTaskID 根据任务ID删除数据（任务整体删除，不写删除记录，并清理该任务已有的删除记录）
 func (r *GeneratedDataRepository) DeleteByTaskID(taskID string) error {
-	return r.db.Where("task_id = ?", taskID).Delete(&models.GeneratedData{}).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("task_id = ?", taskID).Delete(&models.GeneratedDataTombstone{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("task_id = ?", taskID).Delete(&models.GeneratedData{}).Error
+	})
+}
+
+// ListChangedSince 获取任务中在游标之后新增或修改的数据，按 (updated_at, id) 升序
+// 游标为上一批最后一条的 (updated_at, id)，afterID 为 0 时表示从该时间点开始
+func (r *GeneratedDataRepository) ListChangedSince(taskID string, since time.Time, afterID uint, limit int) ([]models.GeneratedData, error) {
+	var dataList []models.GeneratedData
+	err := models.ReadReplica(r.db).
+		Where("task_id = ?", taskID).
+		Where("updated_at > ? OR (updated_at = ? AND id > ?)", since, since, afterID).
+		Order("updated_at ASC, id ASC").
+		Limit(limit).
+		Find(&dataList).Error
+	return dataList, err
+}
+
+// ListDeletedSince 获取任务中在指定时间之后删除的数据ID
+func (r *GeneratedDataRepository) ListDeletedSince(taskID string, since time.Time) ([]uint, error) {
+	var ids []uint
+	err := models.ReadReplica(r.db).Model(&models.GeneratedDataTombstone{}).
+		Where("task_id = ? AND deleted_at > ?", taskID, since).
+		Order("deleted_at ASC, id ASC").
+		Pluck("data_id", &ids).Error
+	return ids, err
+}
+
+// PruneTombstones 清理指定时间之前的删除记录
+func (r *GeneratedDataRepository) PruneTombstones(before time.Time) (int64, error) {
+	result := r.db.Where("deleted_at < ?", before).Delete(&models.GeneratedDataTombstone{})
+	return result.RowsAffected, result.Error
 }
 
 // List 获取数据列表
@@ -76,11 +140,11 @@ func (r *GeneratedDataRepository) List(offset, limit int) ([]models.GeneratedDat
 	var dataList []models.GeneratedData
 	var total int64
 
-	if err := r.db.Model(&models.GeneratedData{}).Count(&total).Error; err != nil {
+	if err := models.ReadReplica(r.db).Model(&models.GeneratedData{}).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := r.db.Preload("User").Order("created_at DESC").Offset(offset).Limit(limit).Find(&dataList).Error
+	err := models.ReadReplica(r.db).Preload("User").Order("created_at DESC").Offset(offset).Limit(limit).Find(&dataList).Error
 	return dataList, total, err
 }
 
@@ -89,7 +153,7 @@ func (r *GeneratedDataRepository) ListByUserID(userID uint, offset, limit int) (
 	var dataList []models.GeneratedData
 	var total int64
 
-	query := r.db.Model(&models.GeneratedData{}).Where("user_id = ?", userID)
+	query := models.ReadReplica(r.db).Model(&models.GeneratedData{}).Where("user_id = ?", userID)
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
@@ -103,7 +167,57 @@ func (r *GeneratedDataRepository) ListByTaskID(taskID string, offset, limit int)
 	var dataList []models.GeneratedData
 	var total int64
 
-	query := r.db.Model(&models.GeneratedData{}).Where("task_id = ?", taskID)
+	query := models.ReadReplica(r.db).Model(&models.GeneratedData{}).Where("task_id = ?", taskID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&dataList).Error
+	return dataList, total, err
+}
+
+// GeneratedDataQuery 生成数据查询条件（零值字段不过滤）
+type GeneratedDataQuery struct {
+	Tags              []string // 必须同时带有的标签
+	Confirmed         *bool
+	MinModelScore     *float64
+	TaskType          string
+	CreatedFrom       *time.Time // 含
+	CreatedTo         *time.Time // 不含
+	ExcludeDuplicates bool
+	ExcludeFlagged    bool // 排除内容安全检查标记的数据
+}
+
+// ListByTaskIDFiltered 按条件获取任务的数据列表
+func (r *GeneratedDataRepository) ListByTaskIDFiltered(taskID string, q *GeneratedDataQuery, offset, limit int) ([]models.GeneratedData, int64, error) {
+	var dataList []models.GeneratedData
+	var total int64
+
+	query := models.ReadReplica(r.db).Model(&models.GeneratedData{}).Where("task_id = ?", taskID)
+	for _, tag := range q.Tags {
+		query = query.Where("(',' || tags || ',') LIKE ?", "%,"+tag+",%")
+	}
+	if q.Confirmed != nil {
+		query = query.Where("is_confirmed = ?", *q.Confirmed)
+	}
+	if q.MinModelScore != nil {
+		query = query.Where("model_score >= ?", *q.MinModelScore)
+	}
+	if q.TaskType != "" {
+		query = query.Where("task_type = ?", q.TaskType)
+	}
+	if q.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *q.CreatedFrom)
+	}
+	if q.CreatedTo != nil {
+		query = query.Where("created_at < ?", *q.CreatedTo)
+	}
+	if q.ExcludeDuplicates {
+		query = query.Where("duplicate_of IS NULL")
+	}
+	if q.ExcludeFlagged {
+		query = query.Where("(safety_flags IS NULL OR safety_flags = '')")
+	}
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
@@ -133,7 +247,297 @@ func (r *GeneratedDataRepository) GetConfirmedCount(taskID string) (int64, error
 	return count, err
 }
 
+// TaskDataCount 任务数据统计
+type TaskDataCount struct {
+	TaskID         string
+	DataCount      int64
+	ConfirmedCount int64
+}
+
+// CountByTaskIDs 按任务ID分组统计数据条数和已确认条数
+func (r *GeneratedDataRepository) CountByTaskIDs(taskIDs []string) (map[string]TaskDataCount, error) {
+	result := make(map[string]TaskDataCount, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []TaskDataCount
+	err := models.ReadReplica(r.db).Model(&models.GeneratedData{}).
+		Select("task_id, COUNT(*) AS data_count, SUM(CASE WHEN is_confirmed THEN 1 ELSE 0 END) AS confirmed_count").
+		Where("task_id IN ?", taskIDs).
+		Group("task_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.TaskID] = row
+	}
+	return result, nil
+}
+
+// CountByGenerationModel 按生成模型统计任务数据条数（多模型集成时用于比较各模型的产出）
+func (r *GeneratedDataRepository) CountByGenerationModel(taskID string) (map[string]int64, error) {
+	var rows []struct {
+		GenerationModel string
+		Count           int64
+	}
+	err := models.ReadReplica(r.db).Model(&models.GeneratedData{}).
+		Select("generation_model, COUNT(*) AS count").
+		Where("task_id = ?", taskID).
+		Group("generation_model").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.GenerationModel] = row.Count
+	}
+	return counts, nil
+}
+
+// ScanForDedup 按ID升序分批读取任务数据（仅ID和内容，用于去重），避免一次性加载全部数据
+func (r *GeneratedDataRepository) ScanForDedup(taskID string, batchSize int, fn func(batch []models.GeneratedData) error) error {
+	var batch []models.GeneratedData
+	return r.db.Select("id", "data_content").Where("task_id = ?", taskID).Order("id ASC").
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+// UpdateDuplicateOf 重写任务数据的去重标记（先清空再写入，保证重复执行结果一致）
+func (r *GeneratedDataRepository) UpdateDuplicateOf(taskID string, marks map[uint]string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.GeneratedData{}).Where("task_id = ?", taskID).Update("duplicate_of", nil).Error; err != nil {
+			return err
+		}
+		for id, duplicateOf := range marks {
+			if err := tx.Model(&models.GeneratedData{}).Where("id = ?", id).Update("duplicate_of", duplicateOf).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateRuleScores 写入规则评分和未通过的规则（failures 为序列化后的 JSON，全部通过时为空）
+func (r *GeneratedDataRepository) UpdateRuleScores(scores map[uint]int, failures map[uint]string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for id, score := range scores {
+			err := tx.Model(&models.GeneratedData{}).Where("id = ?", id).
+				Updates(map[string]interface{}{"rule_score": score, "rule_failures": failures[id]}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateContents 改写数据内容（生成数据后处理插件的结果）
+func (r *GeneratedDataRepository) UpdateContents(contents map[uint]string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for id, content := range contents {
+			if err := tx.Model(&models.GeneratedData{}).Where("id = ?", id).Update("data_content", content).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateSimilarity 写入语义相似度过滤计算的相似度
+func (r *GeneratedDataRepository) UpdateSimilarity(scores map[uint]float64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for id, similarity := range scores {
+			if err := tx.Model(&models.GeneratedData{}).Where("id = ?", id).Update("similarity", similarity).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateTags 重写任务数据的标签（先清空再写入，保证重复打标结果一致）
+func (r *GeneratedDataRepository) UpdateTags(taskID string, tags map[uint]string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.GeneratedData{}).Where("task_id = ?", taskID).Update("tags", "").Error; err != nil {
+			return err
+		}
+		for id, tag := range tags {
+			if err := tx.Model(&models.GeneratedData{}).Where("id = ?", id).Update("tags", tag).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateSafetyFlags 重写任务数据的内容安全标记（先清空再写入，保证重复检查结果一致）
+func (r *GeneratedDataRepository) UpdateSafetyFlags(taskID string, flags map[uint]string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.GeneratedData{}).Where("task_id = ?", taskID).Update("safety_flags", "").Error; err != nil {
+			return err
+		}
+		for id, flag := range flags {
+			if err := tx.Model(&models.GeneratedData{}).Where("id = ?", id).Update("safety_flags", flag).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateJudgeResult 写入裁判模型的评分和点评
+func (r *GeneratedDataRepository) UpdateJudgeResult(id uint, score float64, feedback string) error {
+	return r.db.Model(&models.GeneratedData{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"model_score":    score,
+		"judge_feedback": feedback,
+	}).Error
+}
+
+// CountTags 统计任务数据中每个标签的条数
+func (r *GeneratedDataRepository) CountTags(taskID string) (map[string]int64, error) {
+	var rows []struct {
+		Tags  string
+		Count int64
+	}
+	err := models.ReadReplica(r.db).Model(&models.GeneratedData{}).
+		Select("tags, COUNT(*) AS count").
+		Where("task_id = ? AND tags <> ''", taskID).
+		Group("tags").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64)
+	for _, row := range rows {
+		for _, tag := range strings.Split(row.Tags, ",") {
+			counts[tag] += row.Count
+		}
+	}
+	return counts, nil
+}
+
+// DuplicateCount 任务数据去重统计
+type DuplicateCount struct {
+	SourceDuplicates   int64
+	InternalDuplicates int64
+}
+
+// CountDuplicates 统计任务中与源文件重复、以及彼此重复的数据条数
+func (r *GeneratedDataRepository) CountDuplicates(taskID string) (*DuplicateCount, error) {
+	var count DuplicateCount
+	err := r.db.Model(&models.GeneratedData{}).
+		Select("COALESCE(SUM(CASE WHEN duplicate_of LIKE 'source:%' THEN 1 ELSE 0 END), 0) AS source_duplicates, "+
+			"COALESCE(SUM(CASE WHEN duplicate_of LIKE 'data:%' THEN 1 ELSE 0 END), 0) AS internal_duplicates").
+		Where("task_id = ?", taskID).
+		Scan(&count).Error
+	if err != nil {
+		return nil, err
+	}
+	return &count, nil
+}
+
+// ListTaskIDsByIDs 获取这些数据所属的任务ID（去重）
+func (r *GeneratedDataRepository) ListTaskIDsByIDs(ids []uint) ([]string, error) {
+	var taskIDs []string
+	err := r.db.Model(&models.GeneratedData{}).Where("id IN ?", ids).Distinct().Pluck("task_id", &taskIDs).Error
+	return taskIDs, err
+}
+
 // ConfirmBatch 批量确认数据
 func (r *GeneratedDataRepository) ConfirmBatch(ids []uint) error {
 	return r.db.Model(&models.GeneratedData{}).Where("id IN ?", ids).Update("is_confirmed", true).Error
 }
+
+// ListUnassignedIDs 获取任务中尚未分配审核人的数据ID（按ID升序）
+func (r *GeneratedDataRepository) ListUnassignedIDs(taskID string) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.GeneratedData{}).
+		Where("task_id = ? AND reviewer_id IS NULL", taskID).
+		Order("id ASC").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// AssignReviewer 将数据分配给审核人
+func (r *GeneratedDataRepository) AssignReviewer(ids []uint, reviewerID uint) (int64, error) {
+	result := r.db.Model(&models.GeneratedData{}).Where("id IN ?", ids).Update("reviewer_id", reviewerID)
+	return result.RowsAffected, result.Error
+}
+
+// ReassignReviewer 将审核人名下指定状态的数据批量转给另一审核人（taskID 为空时不限任务）
+func (r *GeneratedDataRepository) ReassignReviewer(fromReviewerID, toReviewerID uint, taskID string, statuses []string) (int64, error) {
+	query := r.db.Model(&models.GeneratedData{}).Where("reviewer_id = ?", fromReviewerID)
+	if taskID != "" {
+		query = query.Where("task_id = ?", taskID)
+	}
+	if len(statuses) > 0 {
+		query = query.Where("review_status IN ?", statuses)
+	}
+	result := query.Update("reviewer_id", toReviewerID)
+	return result.RowsAffected, result.Error
+}
+
+// ListByReviewer 获取分配给审核人的数据（taskID 为空时不过滤；status 按该审核人自己的审核结论过滤）
+func (r *GeneratedDataRepository) ListByReviewer(reviewerID uint, taskID, status string, offset, limit int) ([]models.GeneratedData, int64, error) {
+	var dataList []models.GeneratedData
+	var total int64
+
+	query := models.ReadReplica(r.db).Model(&models.GeneratedData{}).
+		Joins("JOIN review_verdicts ON review_verdicts.data_id = generated_data.id AND review_verdicts.reviewer_id = ?", reviewerID)
+	if taskID != "" {
+		query = query.Where("generated_data.task_id = ?", taskID)
+	}
+	if status != "" {
+		query = query.Where("review_verdicts.verdict = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("generated_data.id ASC").Offset(offset).Limit(limit).Find(&dataList).Error
+	return dataList, total, err
+}
+
+// UpdateReview 写入审核结果（通过时同时确认数据，其他状态取消确认）
+func (r *GeneratedDataRepository) UpdateReview(id uint, status, comment string, reviewedAt time.Time) error {
+	return r.db.Model(&models.GeneratedData{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"review_status":  status,
+		"review_comment": comment,
+		"reviewed_at":    reviewedAt,
+		"is_confirmed":   status == models.ReviewStatusApproved,
+	}).Error
+}
+
+// CountReviewStatus 统计任务数据的审核状态分布，以及尚未分配审核人的条数
+func (r *GeneratedDataRepository) CountReviewStatus(taskID string) (map[string]int64, int64, error) {
+	var rows []struct {
+		ReviewStatus string
+		Count        int64
+	}
+	err := models.ReadReplica(r.db).Model(&models.GeneratedData{}).
+		Select("review_status, COUNT(*) AS count").
+		Where("task_id = ?", taskID).
+		Group("review_status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.ReviewStatus] = row.Count
+	}
+
+	var unassigned int64
+	err = models.ReadReplica(r.db).Model(&models.GeneratedData{}).
+		Where("task_id = ? AND reviewer_id IS NULL", taskID).
+		Count(&unassigned).Error
+	return counts, unassigned, err
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"gen-go/internal/models"
+)
+
+// ModelScoreBucketCount model_score 直方图的分段数（0-10 分，每 1 分一段，10 分计入最后一段）
+const ModelScoreBucketCount = 10
+
+// GroupCount 按某个值分组的条数，Value 为空表示该值为 NULL
+type GroupCount struct {
+	Value *int64
+	Count int64
+}
+
+// LengthSummary 任务数据的字符长度汇总
+type LengthSummary struct {
+	Count int64
+	Min   int64
+	Max   int64
+	Avg   float64
+}
+
+// lengthExpr data_content 的字符长度（SQLite 和 PostgreSQL 的 LENGTH 均按字符计数）
+const lengthExpr = "LENGTH(data_content)"
+
+// modelScoreBucketExpr model_score 所在的分段下标（0 ~ ModelScoreBucketCount-1），未评分时为 NULL
+func modelScoreBucketExpr() string {
+	var builder strings.Builder
+	builder.WriteString("CASE WHEN model_score IS NULL THEN NULL")
+	for i := 1; i < ModelScoreBucketCount; i++ {
+		fmt.Fprintf(&builder, " WHEN model_score < %d THEN %d", i, i-1)
+	}
+	fmt.Fprintf(&builder, " ELSE %d END", ModelScoreBucketCount-1)
+	return builder.String()
+}
+
+// metaRoundExpr 数据 meta.round（生成轮次，从 1 开始）的表达式，旧数据没有该字段时为 NULL
+func (r *GeneratedDataRepository) metaRoundExpr() string {
+	if r.db.Dialector.Name() == "postgres" {
+		return "CAST(CAST(data_content AS json)#>>'{meta,round}' AS integer)"
+	}
+	return "CAST(json_extract(data_content, '$.meta.round') AS INTEGER)"
+}
+
+// turnsCountExpr 数据 turns 数组长度的表达式，没有 turns 时为 NULL
+func (r *GeneratedDataRepository) turnsCountExpr() string {
+	if r.db.Dialector.Name() == "postgres" {
+		return "CASE WHEN json_typeof(CAST(data_content AS json)->'turns') = 'array' THEN json_array_length(CAST(data_content AS json)->'turns') END"
+	}
+	return "CASE WHEN json_valid(data_content) AND json_type(data_content, '$.turns') = 'array' THEN json_array_length(data_content, '$.turns') END"
+}
+
+// countGroupedBy 按表达式分组统计任务数据条数（按值升序，NULL 在前）
+func (r *GeneratedDataRepository) countGroupedBy(taskID, expr string) ([]GroupCount, error) {
+	var rows []GroupCount
+	err := models.ReadReplica(r.db).Model(&models.GeneratedData{}).
+		Select(expr+" AS value, COUNT(*) AS count").
+		Where("task_id = ?", taskID).
+		Group("value").
+		Order("value ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// CountByModelScoreBucket 统计任务数据在各 model_score 分段的条数（Value 为分段下标，NULL 为未评分）
+func (r *GeneratedDataRepository) CountByModelScoreBucket(taskID string) ([]GroupCount, error) {
+	return r.countGroupedBy(taskID, modelScoreBucketExpr())
+}
+
+// CountByRuleScore 统计任务数据各 rule_score 的条数
+func (r *GeneratedDataRepository) CountByRuleScore(taskID string) ([]GroupCount, error) {
+	return r.countGroupedBy(taskID, "rule_score")
+}
+
+// CountByRetryCount 统计任务数据各重试次数的条数
+func (r *GeneratedDataRepository) CountByRetryCount(taskID string) ([]GroupCount, error) {
+	return r.countGroupedBy(taskID, "retry_count")
+}
+
+// CountByRound 统计任务每轮生成的数据条数（按 meta.round）
+func (r *GeneratedDataRepository) CountByRound(taskID string) ([]GroupCount, error) {
+	return r.countGroupedBy(taskID, r.metaRoundExpr())
+}
+
+// AverageTurns 获取任务数据的平均对话轮数（没有 turns 的数据不计入，均没有时返回 nil）
+func (r *GeneratedDataRepository) AverageTurns(taskID string) (*float64, error) {
+	var avg *float64
+	err := models.ReadReplica(r.db).Model(&models.GeneratedData{}).
+		Select("AVG("+r.turnsCountExpr()+")").
+		Where("task_id = ?", taskID).
+		Scan(&avg).Error
+	return avg, err
+}
+
+// SummarizeLength 获取任务数据字符长度的条数、最小值、最大值和平均值
+func (r *GeneratedDataRepository) SummarizeLength(taskID string) (*LengthSummary, error) {
+	var summary LengthSummary
+	err := models.ReadReplica(r.db).Model(&models.GeneratedData{}).
+		Select("COUNT(*) AS count, COALESCE(MIN("+lengthExpr+"), 0) AS min, COALESCE(MAX("+lengthExpr+"), 0) AS max, COALESCE(AVG("+lengthExpr+"), 0) AS avg").
+		Where("task_id = ?", taskID).
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// LengthAtRank 获取任务数据按字符长度升序排列后第 rank 条（从 0 开始）的长度，用于计算分位数
+func (r *GeneratedDataRepository) LengthAtRank(taskID string, rank int) (int64, error) {
+	var length int64
+	err := models.ReadReplica(r.db).Model(&models.GeneratedData{}).
+		Select(lengthExpr).
+		Where("task_id = ?", taskID).
+		Order(lengthExpr + " ASC").
+		Offset(rank).
+		Limit(1).
+		Scan(&length).Error
+	return length, err
+}
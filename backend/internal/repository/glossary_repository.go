@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// GlossaryRepository 术语表数据访问层
+type GlossaryRepository struct {
+	db *gorm.DB
+}
+
+// NewGlossaryRepository 创建术语表Repository
+func NewGlossaryRepository(db *gorm.DB) *GlossaryRepository {
+	return &GlossaryRepository{db: db}
+}
+
+// Create 创建术语表及其全部条目
+func (r *GlossaryRepository) Create(glossary *models.Glossary, terms []models.GlossaryTerm) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		glossary.TermCount = len(terms)
+		if err := tx.Create(glossary).Error; err != nil {
+			return err
+		}
+		if len(terms) == 0 {
+			return nil
+		}
+		for i := range terms {
+			terms[i].GlossaryID = glossary.ID
+		}
+		return tx.CreateInBatches(terms, 200).Error
+	})
+}
+
+// GetByIDAndUserID 根据ID和用户ID获取术语表
+func (r *GlossaryRepository) GetByIDAndUserID(id uint, userID uint) (*models.Glossary, error) {
+	var glossary models.Glossary
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&glossary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &glossary, nil
+}
+
+// ListByUserID 获取用户的术语表
+func (r *GlossaryRepository) ListByUserID(userID uint) ([]models.Glossary, error) {
+	var glossaries []models.Glossary
+	err := r.db.Where("user_id = ?", userID).Order("updated_at DESC").Find(&glossaries).Error
+	return glossaries, err
+}
+
+// Delete 删除术语表及其条目（已有的违规记录保留，作为历史检查结果）
+func (r *GlossaryRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("glossary_id = ?", id).Delete(&models.GlossaryTerm{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Glossary{}, id).Error
+	})
+}
+
+// ListTerms 获取术语表的全部条目
+func (r *GlossaryRepository) ListTerms(glossaryID uint) ([]models.GlossaryTerm, error) {
+	var terms []models.GlossaryTerm
+	err := r.db.Where("glossary_id = ?", glossaryID).Order("id ASC").Find(&terms).Error
+	return terms, err
+}
+
+// ReplaceViolations 重写任务的术语检查结果（先删除再写入，保证重复执行结果一致）
+func (r *GlossaryRepository) ReplaceViolations(taskID string, violations []models.GlossaryViolation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("task_id = ?", taskID).Delete(&models.GlossaryViolation{}).Error; err != nil {
+			return err
+		}
+		if len(violations) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(violations, 200).Error
+	})
+}
+
+// ListViolations 分页获取任务的术语违规记录（按数据ID排序，同一条数据的违规相邻）
+func (r *GlossaryRepository) ListViolations(taskID string, offset, limit int) ([]models.GlossaryViolation, int64, error) {
+	var violations []models.GlossaryViolation
+	var total int64
+
+	query := r.db.Model(&models.GlossaryViolation{}).Where("task_id = ?", taskID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("data_id ASC, id ASC").Offset(offset).Limit(limit).Find(&violations).Error
+	return violations, total, err
+}
+
+// CountViolatingData 统计任务中存在术语违规的数据条数
+func (r *GlossaryRepository) CountViolatingData(taskID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.GlossaryViolation{}).Where("task_id = ?", taskID).
+		Distinct("data_id").Count(&count).Error
+	return count, err
+}
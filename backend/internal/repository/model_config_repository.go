@@ -64,6 +64,16 @@ func (r *ModelConfigRepository) GetByModelPath(modelPath string) (*models.ModelC
 	return &config, nil
 }
 
+// ListByModelPaths 获取模型路径在指定列表中的模型配置
+func (r *ModelConfigRepository) ListByModelPaths(modelPaths []string) ([]models.ModelConfig, error) {
+	var configs []models.ModelConfig
+	if len(modelPaths) == 0 {
+		return configs, nil
+	}
+	err := r.db.Where("model_path IN ?", modelPaths).Find(&configs).Error
+	return configs, err
+}
+
 // GetByName 根据名称获取模型配置
 func (r *ModelConfigRepository) GetByName(name string) (*models.ModelConfig, error) {
 	var config models.ModelConfig
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"time"
+
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ModelTokenUsageRepository 模型Token用量仓库
+type ModelTokenUsageRepository struct {
+	db *gorm.DB
+}
+
+// NewModelTokenUsageRepository 创建模型Token用量仓库
+func NewModelTokenUsageRepository(db *gorm.DB) *ModelTokenUsageRepository {
+	return &ModelTokenUsageRepository{db: db}
+}
+
+// Add 累加模型在指定日期的调用次数和Token数（不存在时创建）
+func (r *ModelTokenUsageRepository) Add(model, date string, calls, promptTokens, completionTokens int64) error {
+	now := time.Now().UTC()
+	usage := &models.ModelTokenUsage{
+		Model:            model,
+		Date:             date,
+		Calls:            calls,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "model"}, {Name: "date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"calls":             gorm.Expr("model_token_usage.calls + ?", calls),
+			"prompt_tokens":     gorm.Expr("model_token_usage.prompt_tokens + ?", promptTokens),
+			"completion_tokens": gorm.Expr("model_token_usage.completion_tokens + ?", completionTokens),
+			"updated_at":        now,
+		}),
+	}).Create(usage).Error
+}
+
+// ListSince 获取指定日期（含）之后的每日用量，按日期和模型排序
+func (r *ModelTokenUsageRepository) ListSince(date string) ([]models.ModelTokenUsage, error) {
+	var usages []models.ModelTokenUsage
+	err := models.ReadReplica(r.db).
+		Where("date >= ?", date).
+		Order("date ASC, model ASC").
+		Find(&usages).Error
+	return usages, err
+}
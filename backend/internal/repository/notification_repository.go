@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"time"
+
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// NotificationRepository 站内通知数据访问层
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository 创建站内通知 Repository
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create 创建通知
+func (r *NotificationRepository) Create(notification *models.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+// CreateBatch 批量创建通知
+func (r *NotificationRepository) CreateBatch(notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(notifications, 500).Error
+}
+
+// ListByUserID 分页获取用户的通知（按时间倒序），unreadOnly 为 true 时只返回未读通知
+func (r *NotificationRepository) ListByUserID(userID uint, unreadOnly bool, offset, limit int) ([]models.Notification, int64, error) {
+	query := r.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []models.Notification
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&notifications).Error
+	return notifications, total, err
+}
+
+// CountUnread 统计用户的未读通知数
+func (r *NotificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+// MarkRead 将用户的指定通知标记为已读，ids 为空时标记全部；返回本次标记的条数
+func (r *NotificationRepository) MarkRead(userID uint, ids []uint) (int64, error) {
+	query := r.db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	result := query.Update("read_at", time.Now().UTC())
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"time"
+
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// NotificationSubscriptionRepository 任务通知订阅数据访问层
+type NotificationSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationSubscriptionRepository 创建通知订阅 Repository
+func NewNotificationSubscriptionRepository(db *gorm.DB) *NotificationSubscriptionRepository {
+	return &NotificationSubscriptionRepository{db: db}
+}
+
+// Create 创建订阅
+func (r *NotificationSubscriptionRepository) Create(sub *models.NotificationSubscription) error {
+	return r.db.Create(sub).Error
+}
+
+// GetByIDAndUserID 根据ID和用户ID获取订阅
+func (r *NotificationSubscriptionRepository) GetByIDAndUserID(id uint, userID uint) (*models.NotificationSubscription, error) {
+	var sub models.NotificationSubscription
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&sub).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// ListByUserID 获取用户的全部订阅
+func (r *NotificationSubscriptionRepository) ListByUserID(userID uint) ([]models.NotificationSubscription, error) {
+	var subs []models.NotificationSubscription
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&subs).Error
+	return subs, err
+}
+
+// ListActiveForTask 获取任务相关的已启用订阅：任务创建者的全局订阅，以及任何用户对该任务的订阅
+func (r *NotificationSubscriptionRepository) ListActiveForTask(ownerID uint, taskID string) ([]models.NotificationSubscription, error) {
+	var subs []models.NotificationSubscription
+	err := r.db.Where("is_active = ?", true).
+		Where("(user_id = ? AND task_id = '') OR task_id = ?", ownerID, taskID).
+		Order("id ASC").
+		Find(&subs).Error
+	return subs, err
+}
+
+// Update 更新订阅
+func (r *NotificationSubscriptionRepository) Update(sub *models.NotificationSubscription) error {
+	return r.db.Save(sub).Error
+}
+
+// UpdateSendResult 记录最近一次发送的结果（errMsg 为空表示成功）
+func (r *NotificationSubscriptionRepository) UpdateSendResult(id uint, errMsg string) error {
+	updates := map[string]interface{}{"last_error": errMsg}
+	if errMsg == "" {
+		updates["last_sent_at"] = time.Now().UTC()
+	}
+	return r.db.Model(&models.NotificationSubscription{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// Delete 删除订阅
+func (r *NotificationSubscriptionRepository) Delete(id uint) error {
+	return r.db.Delete(&models.NotificationSubscription{}, id).Error
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// PromptRepository 提示词库数据访问层
+type PromptRepository struct {
+	db *gorm.DB
+}
+
+// NewPromptRepository 创建提示词Repository
+func NewPromptRepository(db *gorm.DB) *PromptRepository {
+	return &PromptRepository{db: db}
+}
+
+// Create 创建提示词及其第一个版本
+func (r *PromptRepository) Create(prompt *models.Prompt, version *models.PromptVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		prompt.LatestVersion = 1
+		if err := tx.Create(prompt).Error; err != nil {
+			return err
+		}
+		version.PromptID = prompt.ID
+		version.Version = 1
+		return tx.Create(version).Error
+	})
+}
+
+// GetByIDAndUserID 根据ID和用户ID获取提示词
+func (r *PromptRepository) GetByIDAndUserID(id uint, userID uint) (*models.Prompt, error) {
+	var prompt models.Prompt
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&prompt).Error
+	if err != nil {
+		return nil, err
+	}
+	return &prompt, nil
+}
+
+// ListByUserID 获取用户的提示词，可按标签和名称关键字过滤
+func (r *PromptRepository) ListByUserID(userID uint, tag, keyword string) ([]models.Prompt, error) {
+	var prompts []models.Prompt
+	query := r.db.Where("user_id = ?", userID)
+	if tag != "" {
+		query = query.Where("(',' || tags || ',') LIKE ?", "%,"+tag+",%")
+	}
+	if keyword != "" {
+		query = query.Where("name LIKE ? OR description LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+	err := query.Order("updated_at DESC").Find(&prompts).Error
+	return prompts, err
+}
+
+// Update 更新提示词元信息
+func (r *PromptRepository) Update(prompt *models.Prompt) error {
+	return r.db.Save(prompt).Error
+}
+
+// Delete 删除提示词及其全部版本
+func (r *PromptRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("prompt_id = ?", id).Delete(&models.PromptVersion{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Prompt{}, id).Error
+	})
+}
+
+// CreateVersion 为提示词追加新版本（版本号在事务内递增）
+func (r *PromptRepository) CreateVersion(promptID uint, version *models.PromptVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var prompt models.Prompt
+		if err := tx.First(&prompt, promptID).Error; err != nil {
+			return err
+		}
+
+		version.PromptID = promptID
+		version.Version = prompt.LatestVersion + 1
+		if err := tx.Create(version).Error; err != nil {
+			return err
+		}
+		return tx.Model(&prompt).Update("latest_version", version.Version).Error
+	})
+}
+
+// GetVersion 获取提示词的指定版本
+func (r *PromptRepository) GetVersion(promptID uint, version int) (*models.PromptVersion, error) {
+	var v models.PromptVersion
+	err := r.db.Where("prompt_id = ? AND version = ?", promptID, version).First(&v).Error
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// GetVersionByID 根据版本ID获取版本
+func (r *PromptRepository) GetVersionByID(id uint) (*models.PromptVersion, error) {
+	var v models.PromptVersion
+	err := r.db.First(&v, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ListVersions 获取提示词的全部版本（新版本在前）
+func (r *PromptRepository) ListVersions(promptID uint) ([]models.PromptVersion, error) {
+	var versions []models.PromptVersion
+	err := r.db.Where("prompt_id = ?", promptID).Order("version DESC").Find(&versions).Error
+	return versions, err
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"time"
+
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ReviewLinkRepository 审阅链接数据访问层
+type ReviewLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewLinkRepository 创建审阅链接Repository
+func NewReviewLinkRepository(db *gorm.DB) *ReviewLinkRepository {
+	return &ReviewLinkRepository{db: db}
+}
+
+// Create 创建审阅链接
+func (r *ReviewLinkRepository) Create(link *models.ReviewLink) error {
+	return r.db.Create(link).Error
+}
+
+// GetByIDAndUserID 根据ID和创建者获取审阅链接
+func (r *ReviewLinkRepository) GetByIDAndUserID(id uint, userID uint) (*models.ReviewLink, error) {
+	var link models.ReviewLink
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// GetByTokenID 根据 Token 的 jti 获取审阅链接
+func (r *ReviewLinkRepository) GetByTokenID(tokenID string) (*models.ReviewLink, error) {
+	var link models.ReviewLink
+	err := r.db.Where("token_id = ?", tokenID).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// ListByTaskID 获取任务的审阅链接（按创建时间倒序）
+func (r *ReviewLinkRepository) ListByTaskID(taskID string, userID uint) ([]models.ReviewLink, error) {
+	var links []models.ReviewLink
+	err := r.db.Where("task_id = ? AND user_id = ?", taskID, userID).Order("created_at DESC").Find(&links).Error
+	return links, err
+}
+
+// Revoke 撤销审阅链接
+func (r *ReviewLinkRepository) Revoke(id uint) error {
+	return r.db.Model(&models.ReviewLink{}).Where("id = ?", id).Update("revoked", true).Error
+}
+
+// RecordActivity 记录一次访问并更新链接的使用统计
+func (r *ReviewLinkRepository) RecordActivity(activity *models.ReviewLinkActivity) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(activity).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.ReviewLink{}).Where("id = ?", activity.LinkID).Updates(map[string]interface{}{
+			"last_used_at": time.Now().UTC(),
+			"use_count":    gorm.Expr("use_count + 1"),
+		}).Error
+	})
+}
+
+// ListActivities 分页获取审阅链接的访问记录（按时间倒序）
+func (r *ReviewLinkRepository) ListActivities(linkID uint, offset, limit int) ([]models.ReviewLinkActivity, int64, error) {
+	var activities []models.ReviewLinkActivity
+	var total int64
+
+	query := r.db.Model(&models.ReviewLinkActivity{}).Where("link_id = ?", linkID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&activities).Error
+	return activities, total, err
+}
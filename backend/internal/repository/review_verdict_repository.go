@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReviewVerdictRepository 审核结论数据访问层
+type ReviewVerdictRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewVerdictRepository 创建审核结论Repository
+func NewReviewVerdictRepository(db *gorm.DB) *ReviewVerdictRepository {
+	return &ReviewVerdictRepository{db: db}
+}
+
+// CreatePending 批量创建待审核记录（同一审核人对同一数据已有记录时跳过）
+func (r *ReviewVerdictRepository) CreatePending(verdicts []models.ReviewVerdict) (int64, error) {
+	if len(verdicts) == 0 {
+		return 0, nil
+	}
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "data_id"}, {Name: "reviewer_id"}},
+		DoNothing: true,
+	}).CreateInBatches(verdicts, 200)
+	return result.RowsAffected, result.Error
+}
+
+// DeletePendingByDataIDs 删除数据上尚未提交的审核记录（重新分配前清理，已提交的结论保留）
+func (r *ReviewVerdictRepository) DeletePendingByDataIDs(dataIDs []uint) error {
+	return r.db.Where("data_id IN ? AND verdict = ?", dataIDs, models.ReviewStatusPending).
+		Delete(&models.ReviewVerdict{}).Error
+}
+
+// GetByDataIDAndReviewerID 获取审核人对某条数据的审核记录
+func (r *ReviewVerdictRepository) GetByDataIDAndReviewerID(dataID, reviewerID uint) (*models.ReviewVerdict, error) {
+	var verdict models.ReviewVerdict
+	err := r.db.Where("data_id = ? AND reviewer_id = ?", dataID, reviewerID).First(&verdict).Error
+	if err != nil {
+		return nil, err
+	}
+	return &verdict, nil
+}
+
+// Update 更新审核记录
+func (r *ReviewVerdictRepository) Update(verdict *models.ReviewVerdict) error {
+	return r.db.Save(verdict).Error
+}
+
+// ListByDataID 获取数据的全部审核记录
+func (r *ReviewVerdictRepository) ListByDataID(dataID uint) ([]models.ReviewVerdict, error) {
+	var verdicts []models.ReviewVerdict
+	err := r.db.Where("data_id = ?", dataID).Order("id ASC").Find(&verdicts).Error
+	return verdicts, err
+}
+
+// ListSubmittedByTaskID 获取任务中已提交的审核结论（按数据、记录ID排序，用于计算一致性）
+func (r *ReviewVerdictRepository) ListSubmittedByTaskID(taskID string) ([]models.ReviewVerdict, error) {
+	var verdicts []models.ReviewVerdict
+	err := models.ReadReplica(r.db).
+		Select("id", "data_id", "reviewer_id", "verdict").
+		Where("task_id = ? AND verdict <> ?", taskID, models.ReviewStatusPending).
+		Order("data_id ASC, id ASC").
+		Find(&verdicts).Error
+	return verdicts, err
+}
+
+// Reassign 将审核人名下指定状态的记录转给另一审核人（taskID 为空时不限任务）
+// 新审核人对同一数据已有记录时跳过，避免同一人重复审核
+func (r *ReviewVerdictRepository) Reassign(fromReviewerID, toReviewerID uint, taskID string, verdicts []string) (int64, error) {
+	query := r.db.Model(&models.ReviewVerdict{}).
+		Where("reviewer_id = ?", fromReviewerID).
+		Where("data_id NOT IN (?)", r.db.Model(&models.ReviewVerdict{}).Select("data_id").Where("reviewer_id = ?", toReviewerID))
+	if taskID != "" {
+		query = query.Where("task_id = ?", taskID)
+	}
+	if len(verdicts) > 0 {
+		query = query.Where("verdict IN ?", verdicts)
+	}
+	result := query.Update("reviewer_id", toReviewerID)
+	return result.RowsAffected, result.Error
+}
+
+// ReviewerVerdictCount 单个审核人的审核记录统计
+type ReviewerVerdictCount struct {
+	ReviewerID uint
+	Total      int64
+	Approved   int64
+	Rejected   int64
+	NeedsEdit  int64
+}
+
+// CountByReviewer 按审核人统计任务的审核记录
+func (r *ReviewVerdictRepository) CountByReviewer(taskID string) ([]ReviewerVerdictCount, error) {
+	var rows []ReviewerVerdictCount
+	err := models.ReadReplica(r.db).Model(&models.ReviewVerdict{}).
+		Select("reviewer_id, COUNT(*) AS total, "+
+			"SUM(CASE WHEN verdict = ? THEN 1 ELSE 0 END) AS approved, "+
+			"SUM(CASE WHEN verdict = ? THEN 1 ELSE 0 END) AS rejected, "+
+			"SUM(CASE WHEN verdict = ? THEN 1 ELSE 0 END) AS needs_edit",
+			models.ReviewStatusApproved, models.ReviewStatusRejected, models.ReviewStatusNeedsEdit).
+		Where("task_id = ?", taskID).
+		Group("reviewer_id").
+		Order("reviewer_id ASC").
+		Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"time"
+
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ScheduleRepository 定时任务数据访问层
+type ScheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduleRepository 创建定时任务Repository
+func NewScheduleRepository(db *gorm.DB) *ScheduleRepository {
+	return &ScheduleRepository{db: db}
+}
+
+// Create 创建定时任务
+func (r *ScheduleRepository) Create(schedule *models.Schedule) error {
+	return r.db.Create(schedule).Error
+}
+
+// GetByIDAndUserID 根据ID和用户ID获取定时任务
+func (r *ScheduleRepository) GetByIDAndUserID(id uint, userID uint) (*models.Schedule, error) {
+	var schedule models.Schedule
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&schedule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// ListByUserID 获取用户的定时任务
+func (r *ScheduleRepository) ListByUserID(userID uint) ([]models.Schedule, error) {
+	var schedules []models.Schedule
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&schedules).Error
+	return schedules, err
+}
+
+// ListDue 获取已到触发时间的启用中定时任务
+func (r *ScheduleRepository) ListDue(now time.Time) ([]models.Schedule, error) {
+	var schedules []models.Schedule
+	err := r.db.Where("is_active = ? AND next_run_at IS NOT NULL AND next_run_at <= ?", true, now).
+		Order("next_run_at ASC").Find(&schedules).Error
+	return schedules, err
+}
+
+// ClaimDue 认领一次到期的触发：只有 next_run_at 仍为 dueAt 时才推进到 nextRunAt
+// 多个实例同时扫描到同一条到期任务时只有一个能认领成功（RowsAffected 为 1），返回是否认领成功
+func (r *ScheduleRepository) ClaimDue(id uint, dueAt time.Time, now time.Time, nextRunAt *time.Time) (bool, error) {
+	result := r.db.Model(&models.Schedule{}).
+		Where("id = ? AND is_active = ? AND next_run_at = ?", id, true, dueAt).
+		Updates(map[string]interface{}{"last_run_at": now, "next_run_at": nextRunAt})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// UpdateLastTaskID 记录定时任务最近一次创建的任务
+func (r *ScheduleRepository) UpdateLastTaskID(id uint, taskID string) error {
+	return r.db.Model(&models.Schedule{}).Where("id = ?", id).Update("last_task_id", taskID).Error
+}
+
+// Update 更新定时任务
+func (r *ScheduleRepository) Update(schedule *models.Schedule) error {
+	return r.db.Save(schedule).Error
+}
+
+// Delete 删除定时任务及其执行记录
+func (r *ScheduleRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("schedule_id = ?", id).Delete(&models.ScheduleRun{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Schedule{}, id).Error
+	})
+}
+
+// CreateRun 创建执行记录
+func (r *ScheduleRepository) CreateRun(run *models.ScheduleRun) error {
+	return r.db.Create(run).Error
+}
+
+// ListRuns 分页获取执行记录（按时间倒序）
+func (r *ScheduleRepository) ListRuns(scheduleID uint, offset, limit int) ([]models.ScheduleRun, int64, error) {
+	var runs []models.ScheduleRun
+	var total int64
+
+	query := r.db.Model(&models.ScheduleRun{}).Where("schedule_id = ?", scheduleID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&runs).Error
+	return runs, total, err
+}
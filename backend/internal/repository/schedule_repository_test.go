@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gen-go/internal/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestScheduleClaimDueOnce(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_loc=UTC&_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.Schedule{}); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewScheduleRepository(db)
+
+	dueAt := time.Now().UTC().Truncate(time.Second).Add(-time.Minute)
+	schedule := &models.Schedule{UserID: 1, Name: "nightly", CronExpr: "* * * * *", Timezone: "UTC", IsActive: true, NextRunAt: &dueAt}
+	if err := repo.Create(schedule); err != nil {
+		t.Fatal(err)
+	}
+
+	due, err := repo.ListDue(time.Now().UTC())
+	if err != nil || len(due) != 1 {
+		t.Fatalf("ListDue = %d schedules, err %v", len(due), err)
+	}
+
+	// 模拟多个实例同时认领同一次触发
+	now := time.Now().UTC()
+	next := now.Add(time.Hour).Truncate(time.Second)
+	var claimed int32
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := repo.ClaimDue(schedule.ID, *due[0].NextRunAt, now, &next)
+			if err != nil {
+				t.Error(err)
+			}
+			if ok {
+				atomic.AddInt32(&claimed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if claimed != 1 {
+		t.Fatalf("ClaimDue succeeded %d times, want 1", claimed)
+	}
+
+	if due, _ := repo.ListDue(time.Now().UTC()); len(due) != 0 {
+		t.Fatalf("ListDue after claim = %d schedules, want 0", len(due))
+	}
+}
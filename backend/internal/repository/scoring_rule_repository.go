@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ScoringRuleRepository 评分规则数据访问层
+type ScoringRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewScoringRuleRepository 创建评分规则Repository
+func NewScoringRuleRepository(db *gorm.DB) *ScoringRuleRepository {
+	return &ScoringRuleRepository{db: db}
+}
+
+// Create 创建规则
+func (r *ScoringRuleRepository) Create(rule *models.ScoringRule) error {
+	return r.db.Create(rule).Error
+}
+
+// GetByID 根据ID获取规则
+func (r *ScoringRuleRepository) GetByID(id uint) (*models.ScoringRule, error) {
+	var rule models.ScoringRule
+	err := r.db.First(&rule, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// List 获取规则列表，taskType 不为空时只返回该任务类型的规则
+func (r *ScoringRuleRepository) List(taskType string) ([]models.ScoringRule, error) {
+	var rules []models.ScoringRule
+	query := r.db.Order("task_type ASC, id ASC")
+	if taskType != "" {
+		query = query.Where("task_type = ?", taskType)
+	}
+	err := query.Find(&rules).Error
+	return rules, err
+}
+
+// ListActiveForTaskType 获取适用于任务类型的启用规则（包括适用于全部任务类型的规则）
+func (r *ScoringRuleRepository) ListActiveForTaskType(taskType string) ([]models.ScoringRule, error) {
+	var rules []models.ScoringRule
+	err := r.db.Where("is_active = ? AND (task_type = ? OR task_type = '')", true, taskType).
+		Order("id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// Update 更新规则
+func (r *ScoringRuleRepository) Update(rule *models.ScoringRule) error {
+	return r.db.Save(rule).Error
+}
+
+// Delete 删除规则
+func (r *ScoringRuleRepository) Delete(id uint) error {
+	return r.db.Delete(&models.ScoringRule{}, id).Error
+}
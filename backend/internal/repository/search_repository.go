@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"strings"
+
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SearchRepository 全文检索数据访问层
+type SearchRepository struct {
+	db *gorm.DB
+}
+
+// NewSearchRepository 创建全文检索Repository
+func NewSearchRepository(db *gorm.DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// SearchGeneratedDataFTS 通过 FTS5 索引检索用户的生成数据（新数据在前）
+func (r *SearchRepository) SearchGeneratedDataFTS(userID uint, query string, limit int) ([]models.GeneratedData, error) {
+	// 整体作为短语匹配，避免用户输入被解析为 FTS5 查询语法
+	phrase := `"` + strings.ReplaceAll(query, `"`, `""`) + `"`
+
+	var dataList []models.GeneratedData
+	err := models.ReadReplica(r.db).
+		Where("user_id = ? AND id IN (SELECT rowid FROM generated_data_fts WHERE generated_data_fts MATCH ?)", userID, phrase).
+		Order("id DESC").Limit(limit).Find(&dataList).Error
+	return dataList, err
+}
+
+// SearchGeneratedDataLike 通过 LIKE 检索用户的生成数据（新数据在前）
+func (r *SearchRepository) SearchGeneratedDataLike(userID uint, query string, limit int) ([]models.GeneratedData, error) {
+	var dataList []models.GeneratedData
+	err := models.ReadReplica(r.db).
+		Where("user_id = ? AND data_content LIKE ? ESCAPE '\\'", userID, likePattern(query)).
+		Order("id DESC").Limit(limit).Find(&dataList).Error
+	return dataList, err
+}
+
+// SearchDataFiles 获取内容包含关键字的用户文件（新文件在前）
+// 内容保存在文件存储中的文件无法在数据库中匹配，一并返回由调用方逐行检索
+func (r *SearchRepository) SearchDataFiles(userID uint, query string, limit int) ([]models.DataFile, error) {
+	column := "CAST(file_content AS TEXT)"
+	if r.db.Dialector.Name() == "postgres" {
+		column = "convert_from(file_content, 'UTF8')"
+	}
+
+	var files []models.DataFile
+	err := models.ReadReplica(r.db).
+		Scopes(notSnapshot).
+		Where("user_id = ? AND ("+column+" LIKE ? ESCAPE '\\' OR storage_path <> '')", userID, likePattern(query)).
+		Order("created_at DESC").Limit(limit).Find(&files).Error
+	return files, err
+}
+
+// likePattern 转义 LIKE 通配符，生成包含匹配的模式
+func likePattern(query string) string {
+	return "%" + escapeLike(query) + "%"
+}
+
+// escapeLike 转义 LIKE 通配符（配合 ESCAPE '\' 使用）
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"time"
+
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ModelUsage 单个生成模型的使用统计
+type ModelUsage struct {
+	Model string
+	Tasks int64
+	Items int64
+}
+
+// UserCharUsage 单个用户的字符消耗统计
+type UserCharUsage struct {
+	UserID      uint
+	Username    string
+	Tasks       int64
+	InputChars  int64
+	OutputChars int64
+}
+
+// DataFileTotal 数据文件数量和大小合计
+type DataFileTotal struct {
+	Files int64
+	Bytes int64
+}
+
+// StatsRepository 管理员仪表盘统计
+// 所有统计都在数据库中分组聚合，不加载数据行；读取走只读副本
+type StatsRepository struct {
+	db *gorm.DB
+}
+
+// NewStatsRepository 创建统计Repository
+func NewStatsRepository(db *gorm.DB) *StatsRepository {
+	return &StatsRepository{db: db}
+}
+
+// CountTasksByStatus 统计 since 之后开始的任务按状态的数量
+func (r *StatsRepository) CountTasksByStatus(since time.Time) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := models.ReadReplica(r.db).Model(&models.Task{}).
+		Select("status, COUNT(*) AS count").
+		Where("started_at >= ?", since).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// CountActiveTasks 统计正在运行的任务数
+func (r *StatsRepository) CountActiveTasks() (int64, error) {
+	var count int64
+	err := models.ReadReplica(r.db).Model(&models.Task{}).Where("status = ?", models.TaskStatusRunning).Count(&count).Error
+	return count, err
+}
+
+// CountGeneratedData 统计生成数据总条数
+func (r *StatsRepository) CountGeneratedData() (int64, error) {
+	var count int64
+	err := models.ReadReplica(r.db).Model(&models.GeneratedData{}).Count(&count).Error
+	return count, err
+}
+
+// ModelUsage 按生成模型统计数据条数和涉及的任务数（按数据条数倒序）
+func (r *StatsRepository) ModelUsage() ([]ModelUsage, error) {
+	var rows []ModelUsage
+	err := models.ReadReplica(r.db).Model(&models.GeneratedData{}).
+		Select("generation_model AS model, COUNT(DISTINCT task_id) AS tasks, COUNT(*) AS items").
+		Group("generation_model").
+		Order("items DESC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// UserCharUsage 按用户统计任务数和输入/输出字符消耗（按总字符数倒序）
+func (r *StatsRepository) UserCharUsage() ([]UserCharUsage, error) {
+	var rows []UserCharUsage
+	err := models.ReadReplica(r.db).Table("tasks").
+		Select("tasks.user_id, users.username, COUNT(*) AS tasks, " +
+			"COALESCE(SUM(tasks.input_chars), 0) AS input_chars, COALESCE(SUM(tasks.output_chars), 0) AS output_chars").
+		Joins("LEFT JOIN users ON users.id = tasks.user_id").
+		Group("tasks.user_id, users.username").
+		Order("COALESCE(SUM(tasks.input_chars), 0) + COALESCE(SUM(tasks.output_chars), 0) DESC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// DataFileTotals 统计数据文件数量和大小合计
+func (r *StatsRepository) DataFileTotals() (*DataFileTotal, error) {
+	var total DataFileTotal
+	err := models.ReadReplica(r.db).Model(&models.DataFile{}).Scopes(notSnapshot).
+		Select("COUNT(*) AS files, COALESCE(SUM(file_size), 0) AS bytes").
+		Scan(&total).Error
+	if err != nil {
+		return nil, err
+	}
+	return &total, nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// StorageUsage 单个用户的存储占用（字节）
+type StorageUsage struct {
+	UserID             uint   `json:"user_id"`
+	Username           string `json:"username"`
+	FileCount          int64  `json:"file_count"`
+	FileBytes          int64  `json:"file_bytes"`
+	GeneratedDataCount int64  `json:"generated_data_count"`
+	GeneratedDataBytes int64  `json:"generated_data_bytes"`
+	LogCount           int64  `json:"log_count"`
+	LogBytes           int64  `json:"log_bytes"`
+}
+
+// TotalBytes 合计占用
+func (u *StorageUsage) TotalBytes() int64 {
+	return u.FileBytes + u.GeneratedDataBytes + u.LogBytes
+}
+
+// storageRow 分组统计结果
+type storageRow struct {
+	UserID uint
+	Count  int64
+	Bytes  int64
+}
+
+// StorageRepository 存储占用统计
+// 字节数按实际存储的内容长度计算（SQLite 中 TEXT 先转为 BLOB 再取长度，PostgreSQL 使用 octet_length，得到字节数而非字符数）
+type StorageRepository struct {
+	db *gorm.DB
+}
+
+// NewStorageRepository 创建存储统计Repository
+func NewStorageRepository(db *gorm.DB) *StorageRepository {
+	return &StorageRepository{db: db}
+}
+
+// UsageByUserID 统计单个用户的存储占用
+func (r *StorageRepository) UsageByUserID(userID uint) (*StorageUsage, error) {
+	usages, err := r.usage(&userID)
+	if err != nil {
+		return nil, err
+	}
+	if usage, ok := usages[userID]; ok {
+		return usage, nil
+	}
+	return &StorageUsage{UserID: userID}, nil
+}
+
+// UsageAllUsers 统计所有用户的存储占用（没有任何数据的用户也会返回）
+func (r *StorageRepository) UsageAllUsers() ([]*StorageUsage, error) {
+	usages, err := r.usage(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []models.User
+	if err := r.db.Select("id, username").Order("id ASC").Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*StorageUsage, 0, len(users))
+	for _, user := range users {
+		usage, ok := usages[user.ID]
+		if !ok {
+			usage = &StorageUsage{UserID: user.ID}
+		}
+		usage.Username = user.Username
+		result = append(result, usage)
+	}
+	return result, nil
+}
+
+// usage 按用户分组统计文件、生成数据和日志的占用；userID 为 nil 时统计全部用户
+func (r *StorageRepository) usage(userID *uint) (map[uint]*StorageUsage, error) {
+	result := make(map[uint]*StorageUsage)
+	get := func(id uint) *StorageUsage {
+		if _, ok := result[id]; !ok {
+			result[id] = &StorageUsage{UserID: id}
+		}
+		return result[id]
+	}
+
+	// 数据文件
+	files, err := r.groupByUser(&models.DataFile{}, "COALESCE(SUM(file_size), 0)", "snapshot = false", userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range files {
+		u := get(row.UserID)
+		u.FileCount, u.FileBytes = row.Count, row.Bytes
+	}
+
+	// 生成数据
+	data, err := r.groupByUser(&models.GeneratedData{}, "COALESCE(SUM("+r.byteLength("data_content")+"), 0)", "", userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range data {
+		u := get(row.UserID)
+		u.GeneratedDataCount, u.GeneratedDataBytes = row.Count, row.Bytes
+	}
+
+	// 日志类记录：任务错误信息、Webhook 投递记录、定时任务执行记录
+	logSources := []struct {
+		model interface{}
+		bytes string
+		where string
+	}{
+		{&models.Task{}, "COALESCE(SUM(" + r.byteLength("error_message") + "), 0)", "error_message IS NOT NULL AND error_message <> ''"},
+		{&models.WebhookDelivery{}, "COALESCE(SUM(" + r.byteLength("COALESCE(payload, '')") + " + " + r.byteLength("COALESCE(response_body, '')") + " + " + r.byteLength("COALESCE(error_message, '')") + "), 0)", ""},
+		{&models.ScheduleRun{}, "COALESCE(SUM(" + r.byteLength("COALESCE(message, '')") + "), 0)", ""},
+	}
+	for _, source := range logSources {
+		rows, err := r.groupByUser(source.model, source.bytes, source.where, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			u := get(row.UserID)
+			u.LogCount += row.Count
+			u.LogBytes += row.Bytes
+		}
+	}
+
+	return result, nil
+}
+
+// byteLength 返回取文本列字节长度的 SQL 表达式
+func (r *StorageRepository) byteLength(column string) string {
+	if r.db.Dialector.Name() == "postgres" {
+		return "octet_length(" + column + ")"
+	}
+	return "LENGTH(CAST(" + column + " AS BLOB))"
+}
+
+// groupByUser 按 user_id 分组统计记录数和字节数，where 为额外的过滤条件（可为空）
+func (r *StorageRepository) groupByUser(model interface{}, bytesExpr string, where string, userID *uint) ([]storageRow, error) {
+	var rows []storageRow
+	query := r.db.Model(model).Select("user_id, COUNT(*) AS count, " + bytesExpr + " AS bytes")
+	if where != "" {
+		query = query.Where(where)
+	}
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+	err := query.Group("user_id").Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TagRuleRepository 打标规则数据访问层
+type TagRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRuleRepository 创建打标规则Repository
+func NewTagRuleRepository(db *gorm.DB) *TagRuleRepository {
+	return &TagRuleRepository{db: db}
+}
+
+// Create 创建规则
+func (r *TagRuleRepository) Create(rule *models.TagRule) error {
+	return r.db.Create(rule).Error
+}
+
+// GetByIDAndUserID 根据ID和用户ID获取规则
+func (r *TagRuleRepository) GetByIDAndUserID(id uint, userID uint) (*models.TagRule, error) {
+	var rule models.TagRule
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&rule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// ListByUserID 获取用户的全部规则（按优先级降序）
+func (r *TagRuleRepository) ListByUserID(userID uint) ([]models.TagRule, error) {
+	var rules []models.TagRule
+	err := r.db.Where("user_id = ?", userID).Order("priority DESC, id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// ListActiveByUserID 获取用户启用的规则（按优先级降序）
+func (r *TagRuleRepository) ListActiveByUserID(userID uint) ([]models.TagRule, error) {
+	var rules []models.TagRule
+	err := r.db.Where("user_id = ? AND is_active = ?", userID, true).Order("priority DESC, id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// Update 更新规则
+func (r *TagRuleRepository) Update(rule *models.TagRule) error {
+	return r.db.Save(rule).Error
+}
+
+// Delete 删除规则
+func (r *TagRuleRepository) Delete(id uint) error {
+	return r.db.Delete(&models.TagRule{}, id).Error
+}
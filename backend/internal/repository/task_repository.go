@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+	"fmt"
 	"gen-go/internal/models"
 	"time"
 
@@ -17,6 +19,11 @@ func NewTaskRepository(db *gorm.DB) *TaskRepository {
 	return &TaskRepository{db: db}
 }
 
+// WithContext 返回绑定上下文的Repository（数据库访问的 span 挂在 ctx 的 trace 下）
+func (r *TaskRepository) WithContext(ctx context.Context) *TaskRepository {
+	return &TaskRepository{db: r.db.WithContext(ctx)}
+}
+
 // Create 创建任务
 func (r *TaskRepository) Create(task *models.Task) error {
 	return r.db.Create(task).Error
@@ -42,27 +49,68 @@ func (r *TaskRepository) GetByTaskID(taskID string) (*models.Task, error) {
 	return &task, nil
 }
 
+// GetVisibleByTaskID 获取用户可查看的任务（本人创建的或所在工作区共享的）
+func (r *TaskRepository) GetVisibleByTaskID(taskID string, userID uint) (*models.Task, error) {
+	return r.GetAccessibleByTaskID(taskID, userID, models.PermissionView)
+}
+
+// GetEditableByTaskID 获取用户可操作的任务（本人创建的，或在所属工作区拥有 operate 权限）
+func (r *TaskRepository) GetEditableByTaskID(taskID string, userID uint) (*models.Task, error) {
+	return r.GetAccessibleByTaskID(taskID, userID, models.PermissionOperate)
+}
+
+// GetAccessibleByTaskID 获取用户可按指定权限访问的任务
+func (r *TaskRepository) GetAccessibleByTaskID(taskID string, userID uint, permission models.Permission) (*models.Task, error) {
+	var task models.Task
+	err := r.db.Preload("User").Scopes(AccessibleWith(userID, permission)).Where("task_id = ?", taskID).First(&task).Error
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// UpdateWorkspace 修改任务所属工作区（nil 表示转为个人任务）
+func (r *TaskRepository) UpdateWorkspace(taskID string, workspaceID *uint) error {
+	return r.db.Model(&models.Task{}).Where("task_id = ?", taskID).Update("workspace_id", workspaceID).Error
+}
+
 // Update 更新任务
 func (r *TaskRepository) Update(task *models.Task) error {
 	return r.db.Save(task).Error
 }
 
-// UpdateStatus 更新任务状态
-func (r *TaskRepository) UpdateStatus(taskID string, status string) error {
-	return r.db.Model(&models.Task{}).Where("task_id = ?", taskID).Update("status", status).Error
-}
+// UpdateStatus 更新任务状态（任务状态的唯一更新入口）
+// 只有当前状态可以变为 status 时才更新，否则返回 models.ErrInvalidTaskTransition；
+// 结束状态同时记录完成时间（已记录时保留第一次结束的时间），extra 为同时更新的其他字段（如字符数）
+func (r *TaskRepository) UpdateStatus(taskID string, status models.TaskStatus, extra map[string]interface{}) error {
+	if !status.IsValid() {
+		return fmt.Errorf("%w: 状态 %s 不能写入数据库", models.ErrInvalidTaskTransition, status)
+	}
 
-// UpdateStatusWithTime 更新任务状态和完成时间
-func (r *TaskRepository) UpdateStatusWithTime(taskID string, status string) error {
 	updates := map[string]interface{}{
 		"status": status,
 	}
-
-	if status == "finished" || status == "error" || status == "stopped" {
-		updates["finished_at"] = time.Now()
+	for column, value := range extra {
+		updates[column] = value
+	}
+	if status.IsTerminal() {
+		updates["finished_at"] = gorm.Expr("COALESCE(finished_at, ?)", time.Now().UTC())
 	}
 
-	return r.db.Model(&models.Task{}).Where("task_id = ?", taskID).Updates(updates).Error
+	result := r.db.Model(&models.Task{}).
+		Where("task_id = ? AND status IN ?", taskID, models.TaskStatusesBefore(status)).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var task models.Task
+		if err := r.db.Select("status").Where("task_id = ?", taskID).First(&task).Error; err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: 任务 %s 的状态不能从 %s 变为 %s", models.ErrInvalidTaskTransition, taskID, task.Status, status)
+	}
+	return nil
 }
 
 // Delete 删除任务
@@ -80,20 +128,86 @@ func (r *TaskRepository) List(offset, limit int) ([]models.Task, int64, error) {
 	var tasks []models.Task
 	var total int64
 
-	if err := r.db.Model(&models.Task{}).Count(&total).Error; err != nil {
+	if err := models.ReadReplica(r.db).Model(&models.Task{}).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := r.db.Preload("User").Order("started_at DESC").Offset(offset).Limit(limit).Find(&tasks).Error
+	err := models.ReadReplica(r.db).Preload("User").Order("started_at DESC").Offset(offset).Limit(limit).Find(&tasks).Error
 	return tasks, total, err
 }
 
+// ListVisibleByUserID 获取用户可查看的任务列表（含所在工作区共享的任务；workspaceID 非空时只返回该工作区的任务）
+func (r *TaskRepository) ListVisibleByUserID(userID uint, workspaceID *uint, offset, limit int) ([]models.Task, int64, error) {
+	var tasks []models.Task
+	var total int64
+
+	query := models.ReadReplica(r.db).Model(&models.Task{}).Scopes(VisibleTo(userID))
+	if workspaceID != nil {
+		query = query.Where("workspace_id = ?", *workspaceID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User").Order("started_at DESC").Offset(offset).Limit(limit).Find(&tasks).Error
+	return tasks, total, err
+}
+
+// ReportFilter 报告列表过滤条件（零值字段不过滤）
+type ReportFilter struct {
+	WorkspaceID *uint
+	Statuses    []models.TaskStatus
+	StartedFrom *time.Time // 含
+	StartedTo   *time.Time // 不含
+}
+
+// TaskReportRow 报告列表的一行：任务记录及其生成数据统计
+type TaskReportRow struct {
+	models.Task
+	DataCount      int64
+	ConfirmedCount int64
+}
+
+// ListReports 获取用户可查看的任务及其数据条数、已确认条数（按任务聚合后 JOIN，一次查询）
+func (r *TaskRepository) ListReports(userID uint, filter *ReportFilter, offset, limit int) ([]TaskReportRow, int64, error) {
+	var rows []TaskReportRow
+	var total int64
+
+	query := models.ReadReplica(r.db).Model(&models.Task{}).Scopes(VisibleTo(userID))
+	if filter.WorkspaceID != nil {
+		query = query.Where("tasks.workspace_id = ?", *filter.WorkspaceID)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("tasks.status IN ?", filter.Statuses)
+	}
+	if filter.StartedFrom != nil {
+		query = query.Where("tasks.started_at >= ?", *filter.StartedFrom)
+	}
+	if filter.StartedTo != nil {
+		query = query.Where("tasks.started_at < ?", *filter.StartedTo)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Select("tasks.*, COALESCE(data_stats.data_count, 0) AS data_count, COALESCE(data_stats.confirmed_count, 0) AS confirmed_count").
+		Joins("LEFT JOIN (SELECT task_id, COUNT(*) AS data_count, " +
+			"SUM(CASE WHEN is_confirmed THEN 1 ELSE 0 END) AS confirmed_count " +
+			"FROM generated_data GROUP BY task_id) AS data_stats ON data_stats.task_id = tasks.task_id").
+		Order("tasks.started_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, total, err
+}
+
 // ListByUserID 获取用户的任务列表
 func (r *TaskRepository) ListByUserID(userID uint, offset, limit int) ([]models.Task, int64, error) {
 	var tasks []models.Task
 	var total int64
 
-	query := r.db.Model(&models.Task{}).Where("user_id = ?", userID)
+	query := models.ReadReplica(r.db).Model(&models.Task{}).Where("user_id = ?", userID)
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
@@ -109,17 +223,35 @@ func (r *TaskRepository) GetByUserID(userID uint) ([]*models.Task, error) {
 	return tasks, err
 }
 
+// ListByUserIDAndFileID 获取用户可查看的、使用指定输入文件的任务列表（file_id 记录在 Params 中）
+// 使用历史版本或经预处理插件处理的任务读取快照文件，原文件记录在 Params 的 source_file_id 中
+func (r *TaskRepository) ListByUserIDAndFileID(userID uint, fileID uint) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Scopes(VisibleTo(userID)).
+		Where("("+r.paramsIDExpr("file_id")+" = ? OR "+r.paramsIDExpr("source_file_id")+" = ?)", fileID, fileID).
+		Order("started_at DESC").Find(&tasks).Error
+	return tasks, err
+}
+
+// paramsIDExpr 任务参数中整数 ID 字段（如 file_id）的表达式（按数据库方言）
+func (r *TaskRepository) paramsIDExpr(key string) string {
+	if r.db.Dialector.Name() == "postgres" {
+		return "CAST(CAST(params AS json)->>'" + key + "' AS bigint)"
+	}
+	return "json_extract(params, '$." + key + "')"
+}
+
 // GetActiveTasks 获取运行中的任务
 func (r *TaskRepository) GetActiveTasks() ([]models.Task, error) {
 	var tasks []models.Task
-	err := r.db.Where("status = ?", "running").Find(&tasks).Error
+	err := r.db.Where("status = ?", models.TaskStatusRunning).Find(&tasks).Error
 	return tasks, err
 }
 
 // GetActiveTaskByUserID 获取用户的运行中任务
 func (r *TaskRepository) GetActiveTaskByUserID(userID uint) (*models.Task, error) {
 	var task models.Task
-	err := r.db.Where("user_id = ? AND status = ?", userID, "running").First(&task).Error
+	err := r.db.Where("user_id = ? AND status = ?", userID, models.TaskStatusRunning).First(&task).Error
 	if err != nil {
 		return nil, err
 	}
@@ -141,17 +273,129 @@ func (r *TaskRepository) UpdateInputOutputChars(taskID string, inputChars, outpu
 	}).Error
 }
 
-// UpdateStatusWithTimeAndChars 更新任务状态、完成时间和字符数
-func (r *TaskRepository) UpdateStatusWithTimeAndChars(taskID string, status string, inputChars, outputChars int64) error {
-	updates := map[string]interface{}{
-		"status":      status,
-		"input_chars":  inputChars,
-		"output_chars": outputChars,
+// UpdateTokenUsage 更新任务的Token用量
+func (r *TaskRepository) UpdateTokenUsage(taskID string, promptTokens, completionTokens int64) error {
+	return r.db.Model(&models.Task{}).Where("task_id = ?", taskID).Updates(map[string]interface{}{
+		"prompt_tokens":     promptTokens,
+		"completion_tokens": completionTokens,
+	}).Error
+}
+
+// UpdateWorkerInfo 记录任务所用工作进程的版本与协议版本
+func (r *TaskRepository) UpdateWorkerInfo(taskID string, workerVersion string, protocolVersion int) error {
+	return r.db.Model(&models.Task{}).Where("task_id = ?", taskID).Updates(map[string]interface{}{
+		"worker_version":  workerVersion,
+		"worker_protocol": protocolVersion,
+	}).Error
+}
+
+// MergeResult 将字段合并写入任务结果（保留已有字段）
+func (r *TaskRepository) MergeResult(taskID string, values map[string]interface{}) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var task models.Task
+		if err := tx.Select("id", "result").Where("task_id = ?", taskID).First(&task).Error; err != nil {
+			return err
+		}
+
+		result := task.Result
+		if result == nil {
+			result = make(models.JSONMap, len(values))
+		}
+		for k, v := range values {
+			result[k] = v
+		}
+		return tx.Model(&models.Task{}).Where("id = ?", task.ID).Update("result", result).Error
+	})
+}
+
+// MergeParams 将字段合并写入任务参数（保留已有字段）
+func (r *TaskRepository) MergeParams(taskID string, values map[string]interface{}) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var task models.Task
+		if err := tx.Select("id", "params").Where("task_id = ?", taskID).First(&task).Error; err != nil {
+			return err
+		}
+
+		params := task.Params
+		if params == nil {
+			params = make(models.JSONMap, len(values))
+		}
+		for k, v := range values {
+			params[k] = v
+		}
+		return tx.Model(&models.Task{}).Where("id = ?", task.ID).Update("params", params).Error
+	})
+}
+
+// ListFailedSince 获取指定时间之后失败（含部分完成、超时、重试用尽）的任务，按结束时间倒序
+func (r *TaskRepository) ListFailedSince(since time.Time, limit int) ([]models.Task, error) {
+	var tasks []models.Task
+	err := models.ReadReplica(r.db).
+		Select("id", "task_id", "user_id", "status", "error_message", "started_at", "finished_at").
+		Where("status IN ? AND finished_at >= ?", models.FailedTaskStatuses(), since).
+		Order("finished_at DESC").
+		Limit(limit).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// UpdateErrorMessage 更新任务错误信息
+func (r *TaskRepository) UpdateErrorMessage(taskID string, message string) error {
+	return r.db.Model(&models.Task{}).Where("task_id = ?", taskID).Update("error_message", message).Error
+}
+
+// ListEndedBefore 获取指定时间之前开始、且已结束（非 running）的任务，按开始时间升序
+func (r *TaskRepository) ListEndedBefore(before time.Time, limit int) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.
+		Where("status <> ? AND started_at < ?", models.TaskStatusRunning, before).
+		Order("started_at ASC").
+		Limit(limit).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// ListByTaskIDs 根据任务ID列表获取任务（不存在的任务ID不返回）
+func (r *TaskRepository) ListByTaskIDs(taskIDs []string) ([]models.Task, error) {
+	var tasks []models.Task
+	if len(taskIDs) == 0 {
+		return tasks, nil
 	}
+	err := r.db.
+		Select("id", "task_id", "status", "finished_at").
+		Where("task_id IN ?", taskIDs).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// ListByStatus 分页获取指定状态的任务（按结束时间倒序）
+func (r *TaskRepository) ListByStatus(status models.TaskStatus, offset, limit int) ([]models.Task, int64, error) {
+	var tasks []models.Task
+	var total int64
 
-	if status == "finished" || status == "error" || status == "stopped" {
-		updates["finished_at"] = time.Now()
+	query := models.ReadReplica(r.db).Model(&models.Task{}).Where("status = ?", status)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
 
-	return r.db.Model(&models.Task{}).Where("task_id = ?", taskID).Updates(updates).Error
+	err := query.Preload("User").Order("finished_at DESC").Offset(offset).Limit(limit).Find(&tasks).Error
+	return tasks, total, err
+}
+
+// CreateAttempt 记录任务的一次执行尝试
+func (r *TaskRepository) CreateAttempt(attempt *models.TaskAttempt) error {
+	return r.db.Create(attempt).Error
+}
+
+// ListAttempts 获取任务链的全部尝试（按尝试次数升序）
+func (r *TaskRepository) ListAttempts(rootTaskIDs []string) ([]models.TaskAttempt, error) {
+	var attempts []models.TaskAttempt
+	if len(rootTaskIDs) == 0 {
+		return attempts, nil
+	}
+	err := models.ReadReplica(r.db).
+		Where("root_task_id IN ?", rootTaskIDs).
+		Order("root_task_id ASC, attempt ASC").
+		Find(&attempts).Error
+	return attempts, err
 }
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gen-go/internal/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestListByUserIDAndFileIDMatchesSourceFile(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_loc=UTC&_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.Task{}, &models.WorkspaceMember{}); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewTaskRepository(db)
+
+	now := time.Now().UTC()
+	tasks := []*models.Task{
+		{TaskID: "direct", Params: models.JSONMap{"file_id": 1}},
+		{TaskID: "pinned-version", Params: models.JSONMap{"file_id": 2, "source_file_id": 1}},
+		{TaskID: "other-file", Params: models.JSONMap{"file_id": 3}},
+	}
+	for i, task := range tasks {
+		task.UserID = 1
+		task.Status = models.TaskStatusFinished
+		task.StartedAt = now.Add(time.Duration(i) * time.Minute)
+		if err := repo.Create(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := repo.ListByUserIDAndFileID(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].TaskID != "pinned-version" || got[1].TaskID != "direct" {
+		ids := make([]string, len(got))
+		for i, task := range got {
+			ids[i] = task.TaskID
+		}
+		t.Errorf("ListByUserIDAndFileID(1) = %v, want [pinned-version direct]", ids)
+	}
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TaskTypeRepository 任务类型数据访问层
+type TaskTypeRepository struct {
+	db *gorm.DB
+}
+
+// NewTaskTypeRepository 创建任务类型Repository
+func NewTaskTypeRepository(db *gorm.DB) *TaskTypeRepository {
+	return &TaskTypeRepository{db: db}
+}
+
+// Create 创建任务类型及其评分规则
+func (r *TaskTypeRepository) Create(taskType *models.TaskType, rules []*models.ScoringRule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(taskType).Error; err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			if err := tx.Create(rule).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetByID 根据ID获取任务类型
+func (r *TaskTypeRepository) GetByID(id uint) (*models.TaskType, error) {
+	var taskType models.TaskType
+	err := r.db.First(&taskType, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &taskType, nil
+}
+
+// GetByName 根据名称获取任务类型
+func (r *TaskTypeRepository) GetByName(name string) (*models.TaskType, error) {
+	var taskType models.TaskType
+	err := r.db.Where("name = ?", name).First(&taskType).Error
+	if err != nil {
+		return nil, err
+	}
+	return &taskType, nil
+}
+
+// GetActiveByName 根据名称获取启用的任务类型
+func (r *TaskTypeRepository) GetActiveByName(name string) (*models.TaskType, error) {
+	var taskType models.TaskType
+	err := r.db.Where("name = ? AND is_active = ?", name, true).First(&taskType).Error
+	if err != nil {
+		return nil, err
+	}
+	return &taskType, nil
+}
+
+// List 获取任务类型列表，activeOnly 为 true 时只返回启用的类型
+func (r *TaskTypeRepository) List(activeOnly bool) ([]models.TaskType, error) {
+	var taskTypes []models.TaskType
+	query := r.db.Order("name ASC")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Find(&taskTypes).Error
+	return taskTypes, err
+}
+
+// Update 更新任务类型
+func (r *TaskTypeRepository) Update(taskType *models.TaskType) error {
+	return r.db.Save(taskType).Error
+}
+
+// Delete 删除任务类型及其专属的评分规则
+func (r *TaskTypeRepository) Delete(taskType *models.TaskType) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("task_type = ?", taskType.Name).Delete(&models.ScoringRule{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.TaskType{}, taskType.ID).Error
+	})
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"time"
+
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// UploadSessionRepository 分片上传会话数据访问层
+type UploadSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewUploadSessionRepository 创建分片上传会话Repository
+func NewUploadSessionRepository(db *gorm.DB) *UploadSessionRepository {
+	return &UploadSessionRepository{db: db}
+}
+
+// Create 创建上传会话
+func (r *UploadSessionRepository) Create(session *models.UploadSession) error {
+	return r.db.Create(session).Error
+}
+
+// GetByUploadIDAndUserID 根据上传ID和用户ID获取上传会话
+func (r *UploadSessionRepository) GetByUploadIDAndUserID(uploadID string, userID uint) (*models.UploadSession, error) {
+	var session models.UploadSession
+	err := r.db.Where("upload_id = ? AND user_id = ?", uploadID, userID).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Update 更新上传会话
+func (r *UploadSessionRepository) Update(session *models.UploadSession) error {
+	return r.db.Save(session).Error
+}
+
+// ListExpired 获取已过期且未完成的上传会话
+func (r *UploadSessionRepository) ListExpired(now time.Time) ([]models.UploadSession, error) {
+	var sessions []models.UploadSession
+	err := r.db.Where("status = ? AND expires_at < ?", "uploading", now).Find(&sessions).Error
+	return sessions, err
+}
+
+// Delete 删除上传会话
+func (r *UploadSessionRepository) Delete(id uint) error {
+	return r.db.Delete(&models.UploadSession{}, id).Error
+}
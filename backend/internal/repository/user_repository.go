@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"gen-go/internal/models"
 
 	"gorm.io/gorm"
@@ -46,6 +48,24 @@ func (r *UserRepository) Update(user *models.User) error {
 	return r.db.Save(user).Error
 }
 
+// UpdateRole 更新用户角色（同步 is_admin 标记）
+func (r *UserRepository) UpdateRole(id uint, role string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"role":     role,
+		"is_admin": role == models.RoleAdmin,
+	}).Error
+}
+
+// UpdatePassword 更新密码哈希及是否需要修改密码标记
+func (r *UserRepository) UpdatePassword(id uint, passwordHash string, mustChange bool) error {
+	now := time.Now()
+	return r.db.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"password_hash":        passwordHash,
+		"must_change_password": mustChange,
+		"password_changed_at":  &now,
+	}).Error
+}
+
 // Delete 删除用户
 func (r *UserRepository) Delete(id uint) error {
 	return r.db.Delete(&models.User{}, id).Error
@@ -64,6 +84,13 @@ func (r *UserRepository) List(offset, limit int) ([]models.User, int64, error) {
 	return users, total, err
 }
 
+// ListActiveIDs 获取全部启用用户的ID
+func (r *UserRepository) ListActiveIDs() ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.User{}).Where("is_active = ?", true).Order("id ASC").Pluck("id", &ids).Error
+	return ids, err
+}
+
 // ExistsByUsername 检查用户名是否存在
 func (r *UserRepository) ExistsByUsername(username string) (bool, error) {
 	var count int64
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// UserSettingsRepository 用户个人设置数据访问层
+type UserSettingsRepository struct {
+	db *gorm.DB
+}
+
+// NewUserSettingsRepository 创建用户个人设置Repository
+func NewUserSettingsRepository(db *gorm.DB) *UserSettingsRepository {
+	return &UserSettingsRepository{db: db}
+}
+
+// GetByUserID 获取用户的个人设置，未保存过设置时返回 gorm.ErrRecordNotFound
+func (r *UserSettingsRepository) GetByUserID(userID uint) (*models.UserSettings, error) {
+	var settings models.UserSettings
+	err := r.db.Where("user_id = ?", userID).First(&settings).Error
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// Save 保存个人设置（不存在时创建）
+func (r *UserSettingsRepository) Save(settings *models.UserSettings) error {
+	return r.db.Save(settings).Error
+}
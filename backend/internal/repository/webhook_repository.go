@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// WebhookRepository Webhook数据访问层
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository 创建Webhook Repository
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// Create 创建Webhook
+func (r *WebhookRepository) Create(webhook *models.Webhook) error {
+	return r.db.Create(webhook).Error
+}
+
+// GetByIDAndUserID 根据ID和用户ID获取Webhook
+func (r *WebhookRepository) GetByIDAndUserID(id uint, userID uint) (*models.Webhook, error) {
+	var webhook models.Webhook
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&webhook).Error
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// ListByUserID 获取用户的全部Webhook
+func (r *WebhookRepository) ListByUserID(userID uint) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// ListActiveByUserID 获取用户已启用的Webhook
+func (r *WebhookRepository) ListActiveByUserID(userID uint) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	err := r.db.Where("user_id = ? AND is_active = ?", userID, true).Find(&webhooks).Error
+	return webhooks, err
+}
+
+// Update 更新Webhook
+func (r *WebhookRepository) Update(webhook *models.Webhook) error {
+	return r.db.Save(webhook).Error
+}
+
+// Delete 删除Webhook及其投递记录
+func (r *WebhookRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Webhook{}, id).Error
+	})
+}
+
+// CreateDelivery 创建投递记录
+func (r *WebhookRepository) CreateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+// UpdateDelivery 更新投递记录
+func (r *WebhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Save(delivery).Error
+}
+
+// GetDelivery 获取指定Webhook下的投递记录
+func (r *WebhookRepository) GetDelivery(webhookID uint, deliveryID uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.db.Where("id = ? AND webhook_id = ?", deliveryID, webhookID).First(&delivery).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// ListDeliveries 分页获取Webhook的投递记录（按时间倒序）
+func (r *WebhookRepository) ListDeliveries(webhookID uint, offset, limit int) ([]models.WebhookDelivery, int64, error) {
+	var deliveries []models.WebhookDelivery
+	var total int64
+
+	query := r.db.Model(&models.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&deliveries).Error
+	return deliveries, total, err
+}
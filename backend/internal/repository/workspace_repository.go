@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"gen-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// WorkspaceMemberInfo 工作区成员（含用户名）
+type WorkspaceMemberInfo struct {
+	models.WorkspaceMember
+	Username string
+}
+
+// WorkspaceWithRole 用户所在的工作区及其成员角色
+type WorkspaceWithRole struct {
+	models.Workspace
+	Role        string
+	MemberCount int64
+}
+
+// WorkspaceRepository 工作区数据访问层
+type WorkspaceRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkspaceRepository 创建工作区Repository
+func NewWorkspaceRepository(db *gorm.DB) *WorkspaceRepository {
+	return &WorkspaceRepository{db: db}
+}
+
+// AccessibleWith 资源可由用户按指定权限访问：本人创建的，或属于用户在其中拥有该权限的工作区
+// 用于 data_files、tasks 等同时带 user_id 和 workspace_id 的表
+func AccessibleWith(userID uint, permission models.Permission) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(user_id = ? OR workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ? AND role IN ?))",
+			userID, userID, rolesWithPermission(permission))
+	}
+}
+
+// VisibleTo 资源对用户可见：本人创建的，或属于用户所在的工作区
+func VisibleTo(userID uint) func(*gorm.DB) *gorm.DB {
+	return AccessibleWith(userID, models.PermissionView)
+}
+
+// EditableBy 资源可由用户修改：本人创建的，或属于用户在其中拥有 operate 权限的工作区
+func EditableBy(userID uint) func(*gorm.DB) *gorm.DB {
+	return AccessibleWith(userID, models.PermissionOperate)
+}
+
+// rolesWithPermission 拥有指定权限的角色列表
+func rolesWithPermission(permission models.Permission) []string {
+	roles := make([]string, 0, 4)
+	for _, role := range models.Roles() {
+		if models.RoleHasPermission(role, permission) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// Create 创建工作区，创建者自动成为管理员成员
+func (r *WorkspaceRepository) Create(workspace *models.Workspace) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workspace).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      workspace.OwnerID,
+			Role:        models.RoleAdmin,
+		}).Error
+	})
+}
+
+// GetByID 根据ID获取工作区
+func (r *WorkspaceRepository) GetByID(id uint) (*models.Workspace, error) {
+	var workspace models.Workspace
+	err := r.db.First(&workspace, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// Update 更新工作区
+func (r *WorkspaceRepository) Update(workspace *models.Workspace) error {
+	return r.db.Save(workspace).Error
+}
+
+// Delete 删除工作区及其成员，工作区内的文件和任务转回各自创建者的个人资源
+func (r *WorkspaceRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.DataFile{}).Where("workspace_id = ?", id).Update("workspace_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Task{}).Where("workspace_id = ?", id).Update("workspace_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&models.WorkspaceMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Workspace{}, id).Error
+	})
+}
+
+// ListByUserID 获取用户所在的工作区
+func (r *WorkspaceRepository) ListByUserID(userID uint) ([]WorkspaceWithRole, error) {
+	var workspaces []WorkspaceWithRole
+	err := r.db.Table("workspaces").
+		Select("workspaces.*, workspace_members.role AS role, "+
+			"(SELECT COUNT(*) FROM workspace_members m WHERE m.workspace_id = workspaces.id) AS member_count").
+		Joins("JOIN workspace_members ON workspace_members.workspace_id = workspaces.id").
+		Where("workspace_members.user_id = ?", userID).
+		Order("workspaces.id ASC").
+		Scan(&workspaces).Error
+	return workspaces, err
+}
+
+// GetMember 获取工作区成员
+func (r *WorkspaceRepository) GetMember(workspaceID, userID uint) (*models.WorkspaceMember, error) {
+	var member models.WorkspaceMember
+	err := r.db.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// ListMembers 获取工作区成员列表
+func (r *WorkspaceRepository) ListMembers(workspaceID uint) ([]WorkspaceMemberInfo, error) {
+	var members []WorkspaceMemberInfo
+	err := r.db.Table("workspace_members").
+		Select("workspace_members.*, users.username AS username").
+		Joins("LEFT JOIN users ON users.id = workspace_members.user_id").
+		Where("workspace_members.workspace_id = ?", workspaceID).
+		Order("workspace_members.id ASC").
+		Scan(&members).Error
+	return members, err
+}
+
+// AddMember 添加工作区成员
+func (r *WorkspaceRepository) AddMember(member *models.WorkspaceMember) error {
+	return r.db.Create(member).Error
+}
+
+// UpdateMemberRole 修改成员角色
+func (r *WorkspaceRepository) UpdateMemberRole(workspaceID, userID uint, role string) error {
+	return r.db.Model(&models.WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Update("role", role).Error
+}
+
+// RemoveMember 移除工作区成员
+func (r *WorkspaceRepository) RemoveMember(workspaceID, userID uint) error {
+	return r.db.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).Delete(&models.WorkspaceMember{}).Error
+}
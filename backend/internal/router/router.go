@@ -1,16 +1,24 @@
 package router
 
 import (
+	"net/http"
+
+	"gen-go/docs"
 	"gen-go/internal/config"
 	"gen-go/internal/handler"
 	"gen-go/internal/middleware"
+	"gen-go/internal/models"
 	"gen-go/internal/repository"
 	"gen-go/internal/service"
 	"gen-go/internal/utils"
+	"gen-go/pkg/redis_limiter"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
 	"github.com/sirupsen/logrus"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"gorm.io/gorm"
 )
 
@@ -29,8 +37,16 @@ func SetupRouter(
 
 	r := gin.New()
 
+	// 进程内错误统计（5xx 响应、上游模型调用失败）
+	errorTracker := service.NewErrorTracker()
+
 	// 全局中间件
+	if cfg.Tracing.Enabled {
+		// 放在最外层，使请求日志和后续处理都能拿到 trace 上下文
+		r.Use(otelgin.Middleware(cfg.Tracing.ServiceName))
+	}
 	r.Use(middleware.LoggerMiddleware(logger))
+	r.Use(middleware.ErrorTrackingMiddleware(errorTracker))
 	r.Use(gin.Recovery())
 	r.Use(middleware.CORS(cfg))
 
@@ -46,26 +62,174 @@ func SetupRouter(
 	userRepo := repository.NewUserRepository(db)
 	taskRepo := repository.NewTaskRepository(db)
 	fileRepo := repository.NewDataFileRepository(db)
+	fileTagRepo := repository.NewFileTagRepository(db)
+	fileVersionRepo := repository.NewDataFileVersionRepository(db)
 	generatedDataRepo := repository.NewGeneratedDataRepository(db)
 	modelConfigRepo := repository.NewModelConfigRepository(db)
+	modelTokenUsageRepo := repository.NewModelTokenUsageRepository(db)
+	uploadSessionRepo := repository.NewUploadSessionRepository(db)
+	fileValidationRepo := repository.NewFileValidationRepository(db)
+	exportAuditRepo := repository.NewExportAuditRepository(db)
+	auditLogRepo := repository.NewAuditLogRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	notificationSubRepo := repository.NewNotificationSubscriptionRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	scheduleRepo := repository.NewScheduleRepository(db)
+	reviewLinkRepo := repository.NewReviewLinkRepository(db)
+	storageRepo := repository.NewStorageRepository(db)
+	statsRepo := repository.NewStatsRepository(db)
+	promptRepo := repository.NewPromptRepository(db)
+	glossaryRepo := repository.NewGlossaryRepository(db)
+	tagRuleRepo := repository.NewTagRuleRepository(db)
+	scoringRuleRepo := repository.NewScoringRuleRepository(db)
+	taskTypeRepo := repository.NewTaskTypeRepository(db)
+	reviewVerdictRepo := repository.NewReviewVerdictRepository(db)
+	searchRepo := repository.NewSearchRepository(db)
+	workspaceRepo := repository.NewWorkspaceRepository(db)
+	userSettingsRepo := repository.NewUserSettingsRepository(db)
+	embeddingRepo := repository.NewEmbeddingRepository(db)
+
+	// 文件处理作业池（校验、去重、术语检查、打标、向量索引共用）
+	fileJobPool := service.NewJobPool("file_jobs", &cfg.JobPool)
 
 	// 初始化Service
-	authService := service.NewAuthService(userRepo, jwtManager, cfg)
-	taskManager := service.NewTaskManager(taskRepo, userRepo, fileRepo, modelConfigRepo, redisClient, cfg)
-	dataFileService := service.NewDataFileService(fileRepo)
-	modelService := service.NewModelService(modelConfigRepo, redisClient, cfg)
-	generatedDataService := service.NewGeneratedDataService(generatedDataRepo)
-	_ = service.NewFileConversionService()
+	tokenService := service.NewTokenService(redisClient, cfg)
+	authService := service.NewAuthService(userRepo, jwtManager, tokenService, cfg)
+	fileVersionService := service.NewFileVersionService(fileVersionRepo, fileRepo)
+	dedupService := service.NewDedupService(generatedDataRepo, fileRepo, fileJobPool)
+	glossaryService := service.NewGlossaryService(glossaryRepo, taskRepo, generatedDataRepo, fileJobPool)
+	modelService := service.NewModelService(modelConfigRepo, modelTokenUsageRepo, redisClient, errorTracker, cfg)
+	taggingService := service.NewTaggingService(tagRuleRepo, generatedDataRepo, taskRepo, modelConfigRepo, modelService, fileJobPool)
+	scoringService := service.NewScoringService(scoringRuleRepo, taskTypeRepo, generatedDataRepo, taskRepo, fileJobPool)
+	taskTypeService := service.NewTaskTypeService(taskTypeRepo, scoringRuleRepo)
+	safetyService := service.NewSafetyService(generatedDataRepo, taskRepo, modelConfigRepo, modelService, fileJobPool, cfg)
+	webhookService := service.NewWebhookService(webhookRepo, taskRepo, cfg)
+	notificationService := service.NewNotificationService(notificationSubRepo, notificationRepo, taskRepo, fileRepo, generatedDataRepo, userRepo, cfg)
+	reviewService := service.NewReviewService(generatedDataRepo, reviewVerdictRepo, taskRepo, userRepo, notificationService)
+	judgeService := service.NewJudgeService(generatedDataRepo, taskRepo, modelConfigRepo, modelService, fileJobPool)
+	embeddingService := service.NewEmbeddingService(embeddingRepo, fileRepo, generatedDataRepo, taskRepo, modelService, fileJobPool)
+	taskManager := service.NewTaskManager(taskRepo, userRepo, fileRepo, generatedDataRepo, modelConfigRepo, promptRepo, taskTypeRepo, fileVersionService, dedupService, glossaryService, taggingService, scoringService, judgeService, safetyService, embeddingService, webhookService, notificationService, userSettingsRepo, redisClient, cfg)
+	schedulerService := service.NewSchedulerService(scheduleRepo, taskRepo, taskManager, cfg)
+	housekeepingService := service.NewHousekeepingService(taskRepo, generatedDataRepo, embeddingRepo, taskManager, redisClient, cfg)
+	backupService := service.NewBackupService(db, cfg)
+	fileValidationService := service.NewFileValidationService(fileRepo, fileValidationRepo, fileVersionService, fileJobPool, cfg)
+	virusScanService := service.NewVirusScanService(cfg)
+	dataFileService := service.NewDataFileService(fileRepo, fileTagRepo, taskRepo, generatedDataRepo, fileValidationService, fileVersionService, virusScanService)
+	uploadService := service.NewChunkedUploadService(uploadSessionRepo, dataFileService, cfg)
+	bulkUploadService := service.NewBulkUploadService(dataFileService, cfg)
+	dataImportService := service.NewDataImportService(dataFileService, cfg)
+	fileMappingService := service.NewFileMappingService(dataFileService)
+	objectStorageService := service.NewObjectStorageService(cfg)
+	generatedDataService := service.NewGeneratedDataService(generatedDataRepo, taskRepo, reviewService)
+	auditLogService := service.NewAuditLogService(auditLogRepo)
+	exportAuditService := service.NewExportAuditService(exportAuditRepo, auditLogService)
+	storageService := service.NewStorageService(storageRepo, cfg)
+	adminStatsService := service.NewAdminStatsService(statsRepo)
+	promptService := service.NewPromptService(promptRepo)
+	errorReportService := service.NewErrorReportService(errorTracker, taskRepo)
+	searchService := service.NewSearchService(searchRepo)
+	reviewLinkService := service.NewReviewLinkService(reviewLinkRepo, taskRepo, generatedDataRepo, jwtManager, cfg)
+	fileConversionService := service.NewFileConversionService(fileRepo)
+	reportService := service.NewReportService(generatedDataRepo, taskRepo)
+	workspaceService := service.NewWorkspaceService(workspaceRepo, userRepo, fileRepo, taskRepo)
+	userSettingsService := service.NewUserSettingsService(userSettingsRepo, modelConfigRepo)
+
+	// 配置重新加载后更新各服务中按启动配置创建的限流器
+	config.OnReload(modelService.ApplyConfig)
+	config.OnReload(taskManager.ApplyConfig)
 
 	// 初始化Handler
 	authHandler := handler.NewAuthHandler(authService)
 	taskHandler := handler.NewTaskHandler(taskManager, redisClient)
-	dataFileHandler := handler.NewDataFileHandler(dataFileService)
-	modelHandler := handler.NewModelHandler(modelService)
-	generatedDataHandler := handler.NewGeneratedDataHandler(generatedDataService)
-	reportHandler := handler.NewReportHandler(generatedDataRepo, taskRepo)
-	adminHandler := handler.NewAdminHandler(userRepo, taskRepo, generatedDataRepo, generatedDataService, modelService)
-	fileConversionHandler := handler.NewFileConversionHandler()
+	dataFileHandler := handler.NewDataFileHandler(dataFileService, fileVersionService, exportAuditService, auditLogService)
+	modelHandler := handler.NewModelHandler(modelService, auditLogService)
+	generatedDataHandler := handler.NewGeneratedDataHandler(generatedDataService, objectStorageService, exportAuditService, userSettingsService)
+	reportHandler := handler.NewReportHandler(generatedDataRepo, taskRepo, reviewService, reportService, exportAuditService, auditLogService)
+	adminHandler := handler.NewAdminHandler(userRepo, taskRepo, generatedDataRepo, generatedDataService, modelService, exportAuditService, auditLogService, authService)
+	fileConversionHandler := handler.NewFileConversionHandler(fileConversionService)
+	uploadHandler := handler.NewUploadHandler(uploadService, bulkUploadService, dataFileService)
+	dataImportHandler := handler.NewDataImportHandler(dataImportService, dataFileService)
+	fileMappingHandler := handler.NewFileMappingHandler(fileMappingService, dataFileService)
+	exportAuditHandler := handler.NewExportAuditHandler(exportAuditService)
+	auditLogHandler := handler.NewAuditLogHandler(auditLogService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	notificationHandler := handler.NewNotificationHandler(notificationService, auditLogService)
+	scheduleHandler := handler.NewScheduleHandler(schedulerService)
+	reviewLinkHandler := handler.NewReviewLinkHandler(reviewLinkService, generatedDataService)
+	storageHandler := handler.NewStorageHandler(storageService)
+	backupHandler := handler.NewBackupHandler(backupService, auditLogService)
+	configHandler := handler.NewConfigHandler(auditLogService)
+	adminStatsHandler := handler.NewAdminStatsHandler(adminStatsService)
+	promptHandler := handler.NewPromptHandler(promptService)
+	glossaryHandler := handler.NewGlossaryHandler(glossaryService)
+	tagRuleHandler := handler.NewTagRuleHandler(taggingService)
+	scoringRuleHandler := handler.NewScoringRuleHandler(scoringService, auditLogService)
+	taskTypeHandler := handler.NewTaskTypeHandler(taskTypeService, auditLogService)
+	safetyHandler := handler.NewSafetyHandler(safetyService)
+	judgeHandler := handler.NewJudgeHandler(judgeService)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+	healthHandler := handler.NewHealthHandler(taskManager)
+	metricsHandler := handler.NewMetricsHandler(modelService, housekeepingService, fileJobPool)
+	errorReportHandler := handler.NewErrorReportHandler(errorReportService)
+	searchHandler := handler.NewSearchHandler(searchService)
+	workspaceHandler := handler.NewWorkspaceHandler(workspaceService)
+	userSettingsHandler := handler.NewUserSettingsHandler(userSettingsService)
+	embeddingHandler := handler.NewEmbeddingHandler(embeddingService, modelService)
+
+	// 定时任务调度器
+	if cfg.Scheduler.Enabled {
+		schedulerService.Start()
+	}
+
+	// 数据保留与后台清理
+	if cfg.Retention.Enabled {
+		housekeepingService.Start()
+	}
+
+	// 多实例模式：任务状态和进度事件写入 Redis，任意实例都可以订阅进度和停止任务
+	if cfg.Cluster.Enabled {
+		taskManager.UseRegistry(service.NewTaskRegistry(redisClient, cfg))
+	}
+
+	// 工作节点模式：任务进入等待队列，由工作节点（cmd/agent）经 gRPC 领取并运行
+	if cfg.Worker.Executor == config.WorkerExecutorAgent {
+		taskManager.UseAgents(service.NewAgentDispatcher(cfg))
+	}
+
+	// 工作进程 gRPC 服务
+	if cfg.Worker.GRPCEnabled {
+		if err := service.NewWorkerRPCServer(taskManager, fileRepo, cfg).Start(); err != nil {
+			// 工作节点模式下任务只能经 gRPC 领取，服务无法启动时不能继续运行
+			if cfg.Worker.Executor == config.WorkerExecutorAgent {
+				logger.Fatalf("%v，工作节点模式无法运行", err)
+			}
+			logger.Warnf("%v，工作进程将使用标准输出协议", err)
+		}
+	}
+
+	// 健康检查（含工作进程版本）
+	r.GET("/healthz", healthHandler.Healthz)
+	// 运行指标（作业池占用情况），包含内部运行状态，需携带内部API密钥（X-Internal-API-Key）访问
+	r.GET("/metrics", middleware.InternalAPIAuth(), metricsHandler.Metrics)
+
+	// API 文档（生产模式不开放）：Swagger UI 和供生成客户端 SDK 的 OpenAPI 描述
+	// 修改接口注释后在 backend 目录执行 swag init -g cmd/server/main.go -o docs --parseInternal 重新生成
+	if !cfg.Server.ProductionMode {
+		r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+		r.GET("/openapi.json", func(c *gin.Context) {
+			c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(docs.SwaggerInfo.ReadDoc()))
+		})
+	}
+
+	// 接口限流（rate_limit.enabled，令牌桶保存在 Redis 中，未配置 Redis 时不限流）
+	var apiLimiter *redis_limiter.TokenBucket
+	if redisClient != nil {
+		apiLimiter = redis_limiter.NewTokenBucket(redisClient, "rate_limit:")
+	}
+	limitStart := middleware.RateLimitMiddleware(apiLimiter, cfg, config.RateLimitStart)
+	limitUpload := middleware.RateLimitMiddleware(apiLimiter, cfg, config.RateLimitUpload)
+	limitExport := middleware.RateLimitMiddleware(apiLimiter, cfg, config.RateLimitExport)
+	limitModelCall := middleware.RateLimitMiddleware(apiLimiter, cfg, config.RateLimitModelCall)
 
 	// API路由组
 	api := r.Group("/api")
@@ -73,75 +237,222 @@ func SetupRouter(
 		// 公开路由
 		api.POST("/register", authHandler.Register)
 		api.POST("/login", authHandler.Login)
+		api.POST("/refresh", authHandler.Refresh)
 
 		// 内部API（用于Python子进程调用，使用内部密钥认证）
-		api.POST("/model-call", middleware.InternalAPIAuth(), modelHandler.ModelCall)
+		api.POST("/model-call", middleware.InternalAPIAuth(), limitModelCall, modelHandler.ModelCall)
+		api.POST("/model-call/batch", middleware.InternalAPIAuth(), limitModelCall, modelHandler.ModelCallBatch)
+		api.POST("/embeddings", middleware.InternalAPIAuth(), limitModelCall, embeddingHandler.Embeddings)
+
+		// 匿名审阅路由（使用审阅链接Token，仅能访问链接对应任务的生成数据）
+		review := api.Group("/review")
+		review.Use(middleware.ReviewAuthMiddleware(reviewLinkService))
+		{
+			review.GET("/info", reviewLinkHandler.ReviewInfo)
+			review.GET("/data", reviewLinkHandler.ReviewListData)
+			review.POST("/data/:data_id/confirm", reviewLinkHandler.ReviewConfirmData)
+		}
 
 		// 认证路由
 		authorized := api.Group("")
-		authorized.Use(middleware.AuthMiddleware(jwtManager))
+		authorized.Use(middleware.AuthMiddleware(jwtManager, tokenService))
+		authorized.Use(middleware.RoleMiddleware(userRepo))
+		authorized.Use(middleware.TimezoneMiddleware(userRepo, cfg.Server.GetDefaultLocation()))
+
+		// 角色权限：查看类接口所有角色均可访问，写操作按所需权限校验
+		canReview := middleware.RequirePermission(models.PermissionReview)
+		canOperate := middleware.RequirePermission(models.PermissionOperate)
 		{
 			// 用户信息
 			authorized.GET("/me", authHandler.GetMe)
+			authorized.PUT("/me/timezone", authHandler.UpdateTimezone)
+			authorized.POST("/me/password", authHandler.ChangePassword)
+			authorized.GET("/me/storage", storageHandler.GetMyUsage)
+			authorized.GET("/me/settings", userSettingsHandler.GetSettings)
+			authorized.PUT("/me/settings", userSettingsHandler.UpdateSettings)
 			authorized.POST("/logout", authHandler.Logout)
 
 			// 任务类型
-			authorized.GET("/task_types", dataFileHandler.GetTaskTypes)
+			authorized.GET("/task_types", taskTypeHandler.ListTaskTypes)
 
 			// 任务管理
-			authorized.POST("/start", taskHandler.StartTask)
+			authorized.POST("/start", canOperate, limitStart, taskHandler.StartTask)
 			authorized.GET("/progress/:task_id", taskHandler.GetProgress)
+			authorized.GET("/progress/:task_id/log", taskHandler.DownloadEventLog)
 			authorized.GET("/progress_unified/:task_id", taskHandler.GetProgressUnified)
-			authorized.POST("/stop/:task_id", taskHandler.StopTask)
-			authorized.DELETE("/task/:task_id", taskHandler.DeleteTask)
+			authorized.POST("/stop/:task_id", canOperate, taskHandler.StopTask)
+			authorized.DELETE("/task/:task_id", canOperate, taskHandler.DeleteTask)
 			authorized.GET("/status/:task_id", taskHandler.GetTaskStatus)
 			authorized.GET("/tasks", taskHandler.GetAllTasks)
+			authorized.POST("/tasks/:task_id/rerun", canOperate, limitStart, taskHandler.RerunTask)
+			authorized.GET("/tasks/:task_id/overview", taskHandler.GetOverview)
+			authorized.GET("/tasks/:task_id/logs", taskHandler.GetTaskLogs)
+			authorized.GET("/tasks/:task_id/logs/download", taskHandler.DownloadTaskLog)
 			authorized.GET("/active_task", taskHandler.GetActiveTask)
 
+			// 工作区（团队共享数据文件和任务）
+			authorized.POST("/workspaces", canOperate, workspaceHandler.CreateWorkspace)
+			authorized.GET("/workspaces", workspaceHandler.ListWorkspaces)
+			authorized.GET("/workspaces/:id", workspaceHandler.GetWorkspace)
+			authorized.PUT("/workspaces/:id", canOperate, workspaceHandler.UpdateWorkspace)
+			authorized.DELETE("/workspaces/:id", canOperate, workspaceHandler.DeleteWorkspace)
+			authorized.POST("/workspaces/:id/members", canOperate, workspaceHandler.AddMember)
+			authorized.PUT("/workspaces/:id/members/:user_id", canOperate, workspaceHandler.UpdateMember)
+			authorized.DELETE("/workspaces/:id/members/:user_id", workspaceHandler.RemoveMember)
+			authorized.PUT("/data_files/:file_id/workspace", canOperate, workspaceHandler.MoveFile)
+			authorized.PUT("/tasks/:task_id/workspace", canOperate, workspaceHandler.MoveTask)
+
 			// 数据文件管理
 			authorized.GET("/data_files", dataFileHandler.ListFiles)
-			authorized.POST("/data_files/upload", dataFileHandler.UploadFile)
+			authorized.POST("/data_files/upload", canOperate, limitUpload, middleware.UploadLimitMiddleware(cfg), dataFileHandler.UploadFile)
+			authorized.POST("/data_files/upload/batch", canOperate, limitUpload, middleware.BulkUploadLimitMiddleware(cfg), uploadHandler.BulkUpload)
+			authorized.POST("/data_files/upload/mapped", canOperate, limitUpload, middleware.UploadLimitMiddleware(cfg), fileMappingHandler.UploadMappedFile)
+			authorized.POST("/data_files/inspect", canOperate, limitUpload, middleware.UploadLimitMiddleware(cfg), fileMappingHandler.InspectFile)
+			authorized.POST("/data_files/upload/init", canOperate, limitUpload, uploadHandler.InitUpload)
+			authorized.GET("/data_files/upload/:upload_id", uploadHandler.GetUploadStatus)
+			authorized.PUT("/data_files/upload/:upload_id/chunk", canOperate, uploadHandler.UploadChunk)
+			authorized.POST("/data_files/upload/:upload_id/complete", canOperate, uploadHandler.CompleteUpload)
+			authorized.POST("/data_files/import", canOperate, limitUpload, dataImportHandler.ImportFile)
 			authorized.GET("/data_files/:file_id", dataFileHandler.GetFile)
-			authorized.DELETE("/data_files/:file_id", dataFileHandler.DeleteFile)
-			authorized.POST("/data_files/batch_delete", dataFileHandler.BatchDeleteFiles)
+			authorized.PATCH("/data_files/:file_id", canOperate, dataFileHandler.UpdateFile)
+			authorized.DELETE("/data_files/:file_id", canOperate, dataFileHandler.DeleteFile)
+			authorized.POST("/data_files/batch_delete", canOperate, dataFileHandler.BatchDeleteFiles)
+			authorized.POST("/data_files/batch_metadata", canOperate, dataFileHandler.BatchUpdateFileMetadata)
+			authorized.GET("/data_files/tags", dataFileHandler.ListFileTags)
+			authorized.GET("/data_files/folders", dataFileHandler.ListFolders)
+			authorized.PUT("/data_files/:file_id/metadata", canOperate, dataFileHandler.UpdateFileMetadata)
 			authorized.GET("/data_files/:file_id/download", dataFileHandler.DownloadFile)
 			authorized.GET("/data_files/:file_id/download_csv", dataFileHandler.DownloadFileAsCSV)
 			authorized.GET("/data_files/:file_id/content", dataFileHandler.GetFileContent)
+			authorized.GET("/data_files/:file_id/sample", dataFileHandler.SampleFile)
+			authorized.GET("/data_files/:file_id/tasks", dataFileHandler.ListFileTasks)
+			authorized.GET("/data_files/:file_id/versions", dataFileHandler.ListVersions)
+			authorized.GET("/data_files/:file_id/versions/:version/download", dataFileHandler.DownloadVersion)
+			authorized.POST("/data_files/:file_id/versions/:version/restore", canOperate, dataFileHandler.RestoreVersion)
+			authorized.POST("/data_files/:file_id/validate", canOperate, dataFileHandler.ValidateFile)
+			authorized.GET("/data_files/:file_id/validation", dataFileHandler.GetFileValidation)
+			authorized.POST("/data_files/:file_id/embeddings", canOperate, embeddingHandler.BuildFileIndex)
+			authorized.GET("/data_files/:file_id/embeddings", embeddingHandler.GetFileIndex)
 			authorized.GET("/data_files/:file_id/content/editable", dataFileHandler.GetFileContentEditable)
-			authorized.PUT("/data_files/:file_id/content/:item_index", dataFileHandler.UpdateFileContent)
-			authorized.POST("/data_files/:file_id/content", dataFileHandler.AddFileContent)
-			authorized.DELETE("/data_files/:file_id/content/batch", dataFileHandler.BatchDeleteContent)
+			authorized.PUT("/data_files/:file_id/content/:item_index", canOperate, dataFileHandler.UpdateFileContent)
+			authorized.POST("/data_files/:file_id/content", canOperate, dataFileHandler.AddFileContent)
+			authorized.DELETE("/data_files/:file_id/content/batch", canOperate, dataFileHandler.BatchDeleteContent)
 			authorized.POST("/data_files/batch_download", dataFileHandler.BatchDownloadFiles)
 
 			// 文件转换
-			authorized.POST("/data_files/batch_convert", fileConversionHandler.BatchConvertFiles)
+			authorized.POST("/data_files/batch_convert", canOperate, fileConversionHandler.BatchConvertFiles)
 			authorized.POST("/convert_files", fileConversionHandler.ConvertFilesDirect)
 
+			// Webhook管理
+			authorized.POST("/webhooks", canOperate, webhookHandler.CreateWebhook)
+			authorized.GET("/webhooks", webhookHandler.ListWebhooks)
+			authorized.PUT("/webhooks/:id", canOperate, webhookHandler.UpdateWebhook)
+			authorized.DELETE("/webhooks/:id", canOperate, webhookHandler.DeleteWebhook)
+			authorized.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveries)
+			authorized.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", canOperate, webhookHandler.Redeliver)
+
+			// 任务通知订阅（邮件、Slack、钉钉、企业微信）
+			authorized.POST("/notification_subscriptions", notificationHandler.CreateSubscription)
+			authorized.GET("/notification_subscriptions", notificationHandler.ListSubscriptions)
+			authorized.PUT("/notification_subscriptions/:id", notificationHandler.UpdateSubscription)
+			authorized.DELETE("/notification_subscriptions/:id", notificationHandler.DeleteSubscription)
+			authorized.POST("/notification_subscriptions/:id/test", notificationHandler.TestSubscription)
+
+			// 站内通知中心
+			authorized.GET("/notifications", notificationHandler.ListNotifications)
+			authorized.GET("/notifications/unread_count", notificationHandler.UnreadCount)
+			authorized.POST("/notifications/read", notificationHandler.MarkRead)
+
+			// 审阅链接
+			authorized.POST("/tasks/:task_id/review_links", canOperate, reviewLinkHandler.CreateLink)
+			authorized.GET("/tasks/:task_id/review_links", reviewLinkHandler.ListLinks)
+			authorized.DELETE("/review_links/:id", canOperate, reviewLinkHandler.RevokeLink)
+			authorized.GET("/review_links/:id/activities", reviewLinkHandler.ListActivities)
+
+			// 提示词库
+			authorized.POST("/prompts", canOperate, promptHandler.CreatePrompt)
+			authorized.GET("/prompts", promptHandler.ListPrompts)
+			authorized.GET("/prompts/:id", promptHandler.GetPrompt)
+			authorized.PUT("/prompts/:id", canOperate, promptHandler.UpdatePrompt)
+			authorized.DELETE("/prompts/:id", canOperate, promptHandler.DeletePrompt)
+			authorized.POST("/prompts/:id/versions", canOperate, promptHandler.CreateVersion)
+			authorized.GET("/prompts/:id/versions", promptHandler.ListVersions)
+			authorized.GET("/prompts/:id/versions/:version", promptHandler.GetVersion)
+
+			// 全文检索
+			authorized.GET("/search", searchHandler.Search)
+			authorized.GET("/semantic_search", embeddingHandler.SemanticSearch)
+
+			// 术语表
+			authorized.POST("/glossaries", canOperate, glossaryHandler.CreateGlossary)
+			authorized.GET("/glossaries", glossaryHandler.ListGlossaries)
+			authorized.GET("/glossaries/:id", glossaryHandler.GetGlossary)
+			authorized.DELETE("/glossaries/:id", canOperate, glossaryHandler.DeleteGlossary)
+			authorized.POST("/tasks/:task_id/glossary_check", canOperate, glossaryHandler.CheckTask)
+			authorized.GET("/tasks/:task_id/glossary_violations", glossaryHandler.ListViolations)
+			authorized.POST("/tasks/:task_id/safety_check", canOperate, safetyHandler.CheckTask)
+
+			// 自动打标
+			authorized.POST("/tag_rules", canOperate, tagRuleHandler.CreateRule)
+			authorized.GET("/tag_rules", tagRuleHandler.ListRules)
+			authorized.PUT("/tag_rules/:id", canOperate, tagRuleHandler.UpdateRule)
+			authorized.DELETE("/tag_rules/:id", canOperate, tagRuleHandler.DeleteRule)
+			authorized.POST("/tasks/:task_id/tag", canOperate, tagRuleHandler.TagTask)
+			authorized.GET("/tasks/:task_id/tags", tagRuleHandler.GetTagCounts)
+			authorized.POST("/tasks/:task_id/rule_score", canOperate, scoringRuleHandler.ScoreTask)
+
+			// 裁判模型评分
+			authorized.POST("/tasks/:task_id/judge", canOperate, judgeHandler.StartJudge)
+			authorized.GET("/tasks/:task_id/judge", judgeHandler.GetProgress)
+			authorized.POST("/tasks/:task_id/embeddings", canOperate, embeddingHandler.BuildTaskIndex)
+			authorized.GET("/tasks/:task_id/embeddings", embeddingHandler.GetTaskIndex)
+
+			// 定时任务
+			authorized.POST("/schedules", canOperate, scheduleHandler.CreateSchedule)
+			authorized.GET("/schedules", scheduleHandler.ListSchedules)
+			authorized.GET("/schedules/:id", scheduleHandler.GetSchedule)
+			authorized.PUT("/schedules/:id", canOperate, scheduleHandler.UpdateSchedule)
+			authorized.DELETE("/schedules/:id", canOperate, scheduleHandler.DeleteSchedule)
+			authorized.GET("/schedules/:id/runs", scheduleHandler.ListRuns)
+
 			// 模型接口
 			authorized.GET("/models", modelHandler.GetModels)
+			authorized.GET("/models/:id/endpoints", modelHandler.GetModelEndpoints)
 
 			// 生成数据接口
 			authorized.GET("/generated_data", generatedDataHandler.ListData)
-			authorized.POST("/generated_data/batch_update", generatedDataHandler.BatchUpdate)
-			authorized.POST("/generated_data/batch_confirm", generatedDataHandler.BatchConfirm)
-			authorized.GET("/generated_data/export", generatedDataHandler.ExportData)
-			authorized.GET("/generated_data/:task_id/download", generatedDataHandler.DownloadTaskData)
+			authorized.GET("/generated_data/changes", generatedDataHandler.ListChanges)
+			authorized.POST("/generated_data/batch_update", canReview, generatedDataHandler.BatchUpdate)
+			authorized.POST("/generated_data/batch_confirm", canReview, generatedDataHandler.BatchConfirm)
+			authorized.GET("/generated_data/export", limitExport, generatedDataHandler.ExportData)
+			authorized.POST("/generated_data/export/stratified", limitExport, generatedDataHandler.ExportStratified)
+			authorized.POST("/generated_data/export_to_storage", limitExport, generatedDataHandler.ExportToStorage)
+			authorized.GET("/generated_data/:task_id/download", limitExport, generatedDataHandler.DownloadTaskData)
 			authorized.GET("/generated_data/:task_id/info", generatedDataHandler.GetTaskInfo)
-			authorized.GET("/generated_data/:task_id/download_csv", func(c *gin.Context) {
+			authorized.GET("/generated_data/:task_id/download_csv", limitExport, func(c *gin.Context) {
 				c.Request.URL.RawQuery = "format=csv"
 				generatedDataHandler.DownloadTaskData(c)
 			})
-			authorized.POST("/generated_data/add/:task_id", generatedDataHandler.AddData)
-			authorized.PUT("/generated_data/:data_id", generatedDataHandler.UpdateData)
-			authorized.POST("/generated_data/:data_id/confirm", generatedDataHandler.ConfirmData)
-			authorized.DELETE("/generated_data/batch", generatedDataHandler.DeleteBatch)
+			authorized.POST("/generated_data/add/:task_id", canOperate, generatedDataHandler.AddData)
+			authorized.PUT("/generated_data/:data_id", canReview, generatedDataHandler.UpdateData)
+			authorized.POST("/generated_data/:data_id/confirm", canReview, generatedDataHandler.ConfirmData)
+			authorized.DELETE("/generated_data/batch", canOperate, generatedDataHandler.DeleteBatch)
+
+			// 数据审核
+			authorized.POST("/generated_data/assign", canOperate, reviewHandler.Assign)
+			authorized.POST("/generated_data/:data_id/review", canReview, reviewHandler.Submit)
+			authorized.GET("/reviews/assigned", reviewHandler.ListAssigned)
+			authorized.GET("/reports/:task_id/review_progress", reviewHandler.GetProgress)
+			authorized.PUT("/tasks/:task_id/review_settings", canOperate, reviewHandler.UpdateSettings)
 
 			// 报告接口
 			authorized.GET("/reports", reportHandler.ListReports)
+			authorized.GET("/reports/compare", reportHandler.CompareReports)
 			authorized.GET("/reports/:task_id/data", reportHandler.GetReportData)
+			authorized.GET("/reports/:task_id/stats", reportHandler.GetReportStats)
 			authorized.GET("/reports/:task_id/data/editable", reportHandler.GetReportDataEditable)
-			authorized.DELETE("/reports/:task_id", reportHandler.DeleteReport)
-			authorized.POST("/reports/batch_delete", reportHandler.BatchDeleteReports)
+			authorized.DELETE("/reports/:task_id", canOperate, reportHandler.DeleteReport)
+			authorized.POST("/reports/batch_delete", canOperate, reportHandler.BatchDeleteReports)
 
 			// 管理员接口
 			adminGroup := authorized.Group("/admin")
@@ -149,16 +460,57 @@ func SetupRouter(
 			{
 				adminGroup.GET("/users", adminHandler.ListUsers)
 				adminGroup.DELETE("/users/:id", adminHandler.DeleteUser)
+				adminGroup.PUT("/users/:id/role", adminHandler.UpdateUserRole)
+				adminGroup.PUT("/users/:id/password", adminHandler.ResetUserPassword)
+				adminGroup.GET("/roles", adminHandler.ListRoles)
 				adminGroup.GET("/users/:id/reports", adminHandler.GetUserReports)
 				adminGroup.GET("/users/:id/reports/:task_id/download", adminHandler.DownloadUserReport)
 
 				adminGroup.GET("/models", modelHandler.GetAllModels)
+				adminGroup.GET("/models/token_usage", modelHandler.GetTokenUsage)
 				adminGroup.POST("/models", modelHandler.CreateModel)
+				adminGroup.POST("/models/test", modelHandler.TestModel)
+				adminGroup.POST("/models/discover", modelHandler.DiscoverModels)
+				adminGroup.POST("/models/import", modelHandler.ImportModels)
 				adminGroup.PUT("/models/:id", modelHandler.UpdateModel)
 				adminGroup.DELETE("/models/:id", modelHandler.DeleteModel)
 
 				adminGroup.GET("/tasks", adminHandler.ListAllTasks)
+				adminGroup.GET("/tasks/dead-letter", adminHandler.ListDeadLetterTasks)
 				adminGroup.DELETE("/tasks/:id", adminHandler.DeleteTask)
+
+				adminGroup.GET("/exports", exportAuditHandler.ListExports)
+				adminGroup.GET("/exports/summary", exportAuditHandler.ExportSummary)
+
+				adminGroup.GET("/audit", auditLogHandler.ListLogs)
+
+				adminGroup.GET("/storage", storageHandler.GetSummary)
+
+				adminGroup.GET("/stats", adminStatsHandler.GetStats)
+
+				adminGroup.POST("/backup", backupHandler.CreateBackup)
+				adminGroup.GET("/backups", backupHandler.ListBackups)
+				adminGroup.GET("/backups/:name/download", backupHandler.DownloadBackup)
+
+				adminGroup.POST("/config/reload", configHandler.ReloadConfig)
+				adminGroup.GET("/plugins", configHandler.ListPlugins)
+
+				adminGroup.POST("/reviews/reassign", reviewHandler.Reassign)
+
+				adminGroup.GET("/errors", errorReportHandler.ListErrors)
+
+				adminGroup.GET("/scoring_rules", scoringRuleHandler.ListRules)
+				adminGroup.POST("/scoring_rules", scoringRuleHandler.CreateRule)
+				adminGroup.PUT("/scoring_rules/:id", scoringRuleHandler.UpdateRule)
+				adminGroup.DELETE("/scoring_rules/:id", scoringRuleHandler.DeleteRule)
+
+				adminGroup.GET("/task_types", taskTypeHandler.AdminListTaskTypes)
+				adminGroup.POST("/task_types", taskTypeHandler.CreateTaskType)
+				adminGroup.GET("/task_types/:id", taskTypeHandler.GetTaskType)
+				adminGroup.PUT("/task_types/:id", taskTypeHandler.UpdateTaskType)
+				adminGroup.DELETE("/task_types/:id", taskTypeHandler.DeleteTaskType)
+
+				adminGroup.POST("/announcements", notificationHandler.CreateAnnouncement)
 			}
 		}
 	}
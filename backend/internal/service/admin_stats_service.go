@@ -0,0 +1,89 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/repository"
+)
+
+// 仪表盘任务状态统计窗口
+const (
+	DefaultStatsWindowDays = 30
+	MaxStatsWindowDays     = 365
+)
+
+// AdminStatsService 管理员仪表盘统计服务
+type AdminStatsService struct {
+	statsRepo *repository.StatsRepository
+}
+
+// NewAdminStatsService 创建仪表盘统计服务
+func NewAdminStatsService(statsRepo *repository.StatsRepository) *AdminStatsService {
+	return &AdminStatsService{statsRepo: statsRepo}
+}
+
+// GetStats 汇总仪表盘统计，windowDays 为任务状态统计的窗口（天）
+func (s *AdminStatsService) GetStats(windowDays int) (*dto.AdminStatsResponse, error) {
+	if windowDays <= 0 {
+		windowDays = DefaultStatsWindowDays
+	}
+	if windowDays > MaxStatsWindowDays {
+		windowDays = MaxStatsWindowDays
+	}
+
+	now := time.Now()
+	stats := &dto.AdminStatsResponse{
+		WindowDays:  windowDays,
+		ModelUsage:  []dto.ModelUsageStat{},
+		UserUsage:   []dto.UserCharStat{},
+		GeneratedAt: dto.FormatTime(now),
+	}
+
+	var err error
+	if stats.ActiveTasks, err = s.statsRepo.CountActiveTasks(); err != nil {
+		return nil, fmt.Errorf("统计运行中任务失败: %w", err)
+	}
+	if stats.TasksByStatus, err = s.statsRepo.CountTasksByStatus(now.AddDate(0, 0, -windowDays)); err != nil {
+		return nil, fmt.Errorf("统计任务状态失败: %w", err)
+	}
+	if stats.GeneratedItems, err = s.statsRepo.CountGeneratedData(); err != nil {
+		return nil, fmt.Errorf("统计生成数据失败: %w", err)
+	}
+
+	modelUsage, err := s.statsRepo.ModelUsage()
+	if err != nil {
+		return nil, fmt.Errorf("统计模型使用量失败: %w", err)
+	}
+	for _, usage := range modelUsage {
+		stats.ModelUsage = append(stats.ModelUsage, dto.ModelUsageStat{
+			Model: usage.Model,
+			Tasks: usage.Tasks,
+			Items: usage.Items,
+		})
+	}
+
+	userUsage, err := s.statsRepo.UserCharUsage()
+	if err != nil {
+		return nil, fmt.Errorf("统计用户字符消耗失败: %w", err)
+	}
+	for _, usage := range userUsage {
+		stats.UserUsage = append(stats.UserUsage, dto.UserCharStat{
+			UserID:      usage.UserID,
+			Username:    usage.Username,
+			Tasks:       usage.Tasks,
+			InputChars:  usage.InputChars,
+			OutputChars: usage.OutputChars,
+			TotalChars:  usage.InputChars + usage.OutputChars,
+		})
+	}
+
+	files, err := s.statsRepo.DataFileTotals()
+	if err != nil {
+		return nil, fmt.Errorf("统计数据文件占用失败: %w", err)
+	}
+	stats.DataFiles = dto.DataFileStorageStat{Files: files.Files, Bytes: files.Bytes}
+
+	return stats, nil
+}
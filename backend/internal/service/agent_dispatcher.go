@@ -0,0 +1,383 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/workerrpc"
+)
+
+// agentStopWait 请求工作节点停止任务后，等待其上报进程结束的额外时间（在 stop_grace_seconds 之外）
+const agentStopWait = 10 * time.Second
+
+// AgentDispatcher 工作节点调度（worker.executor=agent）
+// 任务不在后端本机运行，而是进入等待队列，由工作节点（cmd/agent）轮询领取后在本机启动 main.py；
+// 进程输出经 gRPC 转发回后端，与本机运行时相同处理，停止任务时在下一次轮询中通知工作节点
+type AgentDispatcher struct {
+	cfg *config.Config
+
+	mu          sync.Mutex
+	agents      map[string]*agentState
+	queue       []*agentAssignment          // 等待领取的任务（先进先出）
+	assignments map[string]*agentAssignment // 等待中和运行中的任务
+	stopCh      chan struct{}
+	stopOnce    sync.Once
+}
+
+// agentState 已登记的工作节点
+type agentState struct {
+	info         workerrpc.RegisterAgentRequest
+	registeredAt time.Time
+	lastSeen     time.Time
+	running      map[string]bool
+}
+
+// agentAssignment 交给工作节点运行的任务
+type agentAssignment struct {
+	taskCtx    *TaskContext
+	args       []string
+	agentID    string // 领取任务的工作节点，等待领取时为空
+	assignedAt time.Time
+	stopping   bool
+	done       chan error // 进程结束时写入一次
+}
+
+// NewAgentDispatcher 创建工作节点调度器
+func NewAgentDispatcher(cfg *config.Config) *AgentDispatcher {
+	return &AgentDispatcher{
+		cfg:         cfg,
+		agents:      make(map[string]*agentState),
+		assignments: make(map[string]*agentAssignment),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start 启动下线检测循环
+func (d *AgentDispatcher) Start() {
+	log.Printf("[AgentDispatcher] 任务由工作节点运行，轮询间隔: %v，下线判定: %v",
+		d.cfg.Current().Worker.GetAgentPollInterval(), d.cfg.Current().Worker.GetAgentOfflineTimeout())
+
+	go func() {
+		ticker := time.NewTicker(d.cfg.Current().Worker.GetAgentPollInterval())
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				d.sweep()
+			case <-d.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止下线检测循环
+func (d *AgentDispatcher) Stop() {
+	d.stopOnce.Do(func() {
+		close(d.stopCh)
+	})
+}
+
+// sweep 移除下线的工作节点并结束其上的任务；等待领取的任务刷新活动时间，避免被看门狗判定为卡死
+func (d *AgentDispatcher) sweep() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	offlineAfter := d.cfg.Current().Worker.GetAgentOfflineTimeout()
+	for id, agent := range d.agents {
+		if time.Since(agent.lastSeen) < offlineAfter {
+			continue
+		}
+		log.Printf("[AgentDispatcher] 工作节点 %s 已 %v 未轮询，判定为下线", id, time.Since(agent.lastSeen).Round(time.Second))
+		delete(d.agents, id)
+		for taskID, assignment := range d.assignments {
+			if assignment.agentID == id {
+				d.finishLocked(taskID, fmt.Errorf("工作节点 %s 已下线", id))
+			}
+		}
+	}
+
+	for _, assignment := range d.queue {
+		assignment.taskCtx.touch()
+	}
+}
+
+// CheckCompatible 返回在线工作节点中协议版本兼容的一个，没有时返回错误
+func (d *AgentDispatcher) CheckCompatible() (*WorkerHandshake, error) {
+	agents := d.onlineAgents()
+	if len(agents) == 0 {
+		return nil, fmt.Errorf("没有在线的工作节点")
+	}
+
+	var first *WorkerHandshake
+	for _, agent := range agents {
+		handshake := &WorkerHandshake{
+			Type:            "handshake",
+			WorkerVersion:   agent.info.WorkerVersion,
+			ProtocolVersion: agent.info.ProtocolVersion,
+		}
+		if handshake.Compatible() {
+			return handshake, nil
+		}
+		if first == nil {
+			first = handshake
+		}
+	}
+	return first, first.CheckCompatible()
+}
+
+// onlineAgents 在线的工作节点（按登记时间排序）
+func (d *AgentDispatcher) onlineAgents() []agentState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	agents := make([]agentState, 0, len(d.agents))
+	for _, agent := range d.agents {
+		agents = append(agents, *agent)
+	}
+	sort.Slice(agents, func(i, j int) bool {
+		return agents[i].registeredAt.Before(agents[j].registeredAt)
+	})
+	return agents
+}
+
+// Submit 将任务加入等待队列，返回等待进程结束的函数（与本机进程的 Wait 相同语义）
+// ctx 取消（停止、超时、卡死）时通知工作节点终止进程，超过等待时间仍未上报则直接按结束处理
+func (d *AgentDispatcher) Submit(ctx context.Context, taskCtx *TaskContext, args []string) func() error {
+	assignment := &agentAssignment{
+		taskCtx: taskCtx,
+		args:    args,
+		done:    make(chan error, 1),
+	}
+
+	d.mu.Lock()
+	d.queue = append(d.queue, assignment)
+	d.assignments[taskCtx.TaskID] = assignment
+	waiting := len(d.queue)
+	d.mu.Unlock()
+
+	log.Printf("[AgentDispatcher] 任务 %s 进入等待队列，前面还有 %d 个任务", taskCtx.TaskID, waiting-1)
+	taskCtx.AddEvent(&dto.ProgressEvent{
+		Type:    "output",
+		Line:    fmt.Sprintf("等待工作节点领取任务（队列中第 %d 个）", waiting),
+		Message: "等待工作节点",
+	})
+
+	return func() error {
+		select {
+		case err := <-assignment.done:
+			return err
+		case <-ctx.Done():
+		}
+
+		if !d.requestStop(taskCtx.TaskID) {
+			return <-assignment.done
+		}
+		select {
+		case err := <-assignment.done:
+			return err
+		case <-time.After(d.cfg.Current().Worker.GetStopGracePeriod() + agentStopWait):
+			d.finish(taskCtx.TaskID, fmt.Errorf("工作节点未在规定时间内停止任务"))
+			return <-assignment.done
+		}
+	}
+}
+
+// requestStop 停止任务：已被领取时在下一次轮询中通知工作节点并返回 true；
+// 尚未被领取时直接移出队列，已结束或移出队列时返回 false（结果已写入 done）
+func (d *AgentDispatcher) requestStop(taskID string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	assignment, ok := d.assignments[taskID]
+	if !ok {
+		return false
+	}
+	if assignment.agentID == "" {
+		d.finishLocked(taskID, fmt.Errorf("任务在等待工作节点领取时被终止"))
+		return false
+	}
+	assignment.stopping = true
+	return true
+}
+
+// finish 记录任务进程结束
+func (d *AgentDispatcher) finish(taskID string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.finishLocked(taskID, err)
+}
+
+func (d *AgentDispatcher) finishLocked(taskID string, err error) {
+	assignment, ok := d.assignments[taskID]
+	if !ok {
+		return
+	}
+	delete(d.assignments, taskID)
+	for i, queued := range d.queue {
+		if queued == assignment {
+			d.queue = append(d.queue[:i], d.queue[i+1:]...)
+			break
+		}
+	}
+	assignment.done <- err
+}
+
+// Register 登记工作节点（重复登记时更新信息，保留其上运行中的任务）
+func (d *AgentDispatcher) Register(req *workerrpc.RegisterAgentRequest) *workerrpc.RegisterAgentResponse {
+	handshake := &WorkerHandshake{WorkerVersion: req.WorkerVersion, ProtocolVersion: req.ProtocolVersion}
+	if err := handshake.CheckCompatible(); err != nil {
+		log.Printf("[AgentDispatcher] 拒绝工作节点 %s: %v", req.AgentID, err)
+		return &workerrpc.RegisterAgentResponse{Accepted: false, Message: err.Error()}
+	}
+	if req.Capacity <= 0 {
+		req.Capacity = 1
+	}
+
+	d.mu.Lock()
+	agent, exists := d.agents[req.AgentID]
+	if !exists {
+		agent = &agentState{registeredAt: time.Now(), running: map[string]bool{}}
+		d.agents[req.AgentID] = agent
+	}
+	agent.info = *req
+	agent.lastSeen = time.Now()
+	d.mu.Unlock()
+
+	log.Printf("[AgentDispatcher] 工作节点 %s 已登记（%s，版本 %s，并发 %d）", req.AgentID, req.Hostname, req.WorkerVersion, req.Capacity)
+	return &workerrpc.RegisterAgentResponse{
+		Accepted:    true,
+		PollSeconds: d.cfg.Current().Worker.AgentPollSeconds,
+	}
+}
+
+// Pull 处理工作节点的轮询：返回需要停止的任务，有空闲并发时分配等待队列中的第一个任务
+func (d *AgentDispatcher) Pull(req *workerrpc.PullTaskRequest) (*workerrpc.PullTaskResponse, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	agent, ok := d.agents[req.AgentID]
+	if !ok {
+		return nil, fmt.Errorf("工作节点 %s 未登记", req.AgentID)
+	}
+	agent.lastSeen = time.Now()
+	agent.running = make(map[string]bool, len(req.Running))
+	for _, taskID := range req.Running {
+		agent.running[taskID] = true
+	}
+
+	resp := &workerrpc.PullTaskResponse{}
+	// 工作节点上运行的任务已被停止或后端不再跟踪（如后端重启）时通知其终止
+	for _, taskID := range req.Running {
+		assignment, ok := d.assignments[taskID]
+		if !ok || assignment.agentID != req.AgentID || assignment.stopping {
+			resp.Stop = append(resp.Stop, taskID)
+		}
+	}
+	// 已分配但工作节点一直没有运行的任务（如领取响应丢失）按失败处理
+	lostAfter := 3 * d.cfg.Current().Worker.GetAgentPollInterval()
+	for taskID, assignment := range d.assignments {
+		if assignment.agentID == req.AgentID && !agent.running[taskID] && time.Since(assignment.assignedAt) > lostAfter {
+			d.finishLocked(taskID, fmt.Errorf("工作节点 %s 未运行已分配的任务", req.AgentID))
+		}
+	}
+
+	if len(d.queue) == 0 || len(req.Running) >= agent.info.Capacity {
+		return resp, nil
+	}
+	assignment := d.queue[0]
+	d.queue = d.queue[1:]
+	assignment.agentID = req.AgentID
+	assignment.assignedAt = time.Now()
+
+	log.Printf("[AgentDispatcher] 任务 %s 分配给工作节点 %s", assignment.taskCtx.TaskID, req.AgentID)
+	assignment.taskCtx.touch()
+	assignment.taskCtx.AddEvent(&dto.ProgressEvent{
+		Type:    "output",
+		Line:    fmt.Sprintf("任务已由工作节点 %s（%s）领取", req.AgentID, agent.info.Hostname),
+		Message: "工作节点领取任务",
+	})
+	resp.Task = &workerrpc.TaskAssignment{
+		TaskID: assignment.taskCtx.TaskID,
+		Args:   assignment.args,
+	}
+	return resp, nil
+}
+
+// lookup 查找工作节点上运行的任务，返回任务上下文和任务是否应继续运行
+func (d *AgentDispatcher) lookup(agentID, taskID string) (*TaskContext, bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	assignment, ok := d.assignments[taskID]
+	if !ok || assignment.agentID != agentID {
+		return nil, false, fmt.Errorf("任务 %s 未分配给工作节点 %s", taskID, agentID)
+	}
+	return assignment.taskCtx, !assignment.stopping, nil
+}
+
+// Complete 处理工作节点上报的进程结束
+func (d *AgentDispatcher) Complete(req *workerrpc.CompleteTaskRequest) error {
+	if _, _, err := d.lookup(req.AgentID, req.TaskID); err != nil {
+		return err
+	}
+
+	var err error
+	switch {
+	case req.Error != "":
+		err = fmt.Errorf("%s", req.Error)
+	case req.ReturnCode != 0:
+		err = fmt.Errorf("工作进程退出码 %d", req.ReturnCode)
+	}
+	log.Printf("[AgentDispatcher] 工作节点 %s 上的任务 %s 已结束，退出码: %d", req.AgentID, req.TaskID, req.ReturnCode)
+	d.finish(req.TaskID, err)
+	return nil
+}
+
+// AgentInfo 工作节点状态（用于健康检查）
+type AgentInfo struct {
+	AgentID       string    `json:"agent_id"`
+	Hostname      string    `json:"hostname"`
+	WorkerVersion string    `json:"worker_version"`
+	Capacity      int       `json:"capacity"`
+	Running       int       `json:"running"`
+	LastSeen      time.Time `json:"last_seen"`
+}
+
+// Agents 在线工作节点的状态
+func (d *AgentDispatcher) Agents() []AgentInfo {
+	agents := d.onlineAgents()
+	infos := make([]AgentInfo, len(agents))
+	for i, agent := range agents {
+		infos[i] = AgentInfo{
+			AgentID:       agent.info.AgentID,
+			Hostname:      agent.info.Hostname,
+			WorkerVersion: agent.info.WorkerVersion,
+			Capacity:      agent.info.Capacity,
+			Running:       len(agent.running),
+			LastSeen:      agent.lastSeen,
+		}
+	}
+	return infos
+}
+
+// UseAgents 开启工作节点模式：任务交给工作节点运行，后端本机不再启动 main.py
+func (tm *TaskManager) UseAgents(dispatcher *AgentDispatcher) {
+	tm.agents = dispatcher
+	dispatcher.Start()
+}
+
+// Agents 在线工作节点的状态，未开启工作节点模式时返回 nil
+func (tm *TaskManager) Agents() []AgentInfo {
+	if tm.agents == nil {
+		return nil
+	}
+	return tm.agents.Agents()
+}
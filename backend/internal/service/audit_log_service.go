@@ -0,0 +1,131 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+)
+
+// auditRedactedKeys 快照中需要脱敏的字段（按 JSON 键名匹配，不区分大小写）
+var auditRedactedKeys = map[string]bool{
+	"api_key":       true,
+	"password":      true,
+	"password_hash": true,
+	"secret":        true,
+	"secret_key":    true,
+	"token":         true,
+}
+
+// auditRedactedValue 脱敏后的字段值
+const auditRedactedValue = "***"
+
+// AuditLogService 敏感操作审计服务
+type AuditLogService struct {
+	auditRepo *repository.AuditLogRepository
+}
+
+// NewAuditLogService 创建审计服务
+func NewAuditLogService(auditRepo *repository.AuditLogRepository) *AuditLogService {
+	return &AuditLogService{
+		auditRepo: auditRepo,
+	}
+}
+
+// Record 写入审计记录（失败只记录日志，不影响操作本身）
+func (s *AuditLogService) Record(entry *models.AuditLog) {
+	if err := s.auditRepo.Create(entry); err != nil {
+		log.Printf("[AuditLog] 记录审计失败: user=%d, action=%s, %s=%s, err=%v", entry.UserID, entry.Action, entry.ResourceType, entry.ResourceID, err)
+	}
+}
+
+// ListLogs 分页查询审计记录
+func (s *AuditLogService) ListLogs(filter repository.AuditLogFilter, page, perPage int) (*dto.PaginatedResponse, error) {
+	offset := (page - 1) * perPage
+	entries, total, err := s.auditRepo.List(filter, offset, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.AuditLogResponse, len(entries))
+	for i, entry := range entries {
+		items[i] = dto.AuditLogResponse{
+			ID:           entry.ID,
+			UserID:       entry.UserID,
+			Username:     entry.Username,
+			Action:       entry.Action,
+			ResourceType: entry.ResourceType,
+			ResourceID:   entry.ResourceID,
+			Before:       snapshotJSON(entry.Before),
+			After:        snapshotJSON(entry.After),
+			Endpoint:     entry.Endpoint,
+			ClientIP:     entry.ClientIP,
+			CreatedAt:    dto.FormatTime(entry.CreatedAt),
+		}
+	}
+
+	return &dto.PaginatedResponse{
+		Items:   items,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	}, nil
+}
+
+// AuditSnapshot 将对象序列化为审计快照（JSON），敏感字段脱敏；v 为 nil 时返回空
+func AuditSnapshot(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return ""
+	}
+	if value == nil {
+		return ""
+	}
+	redacted, err := json.Marshal(redactAuditValue(value))
+	if err != nil {
+		return ""
+	}
+	return string(redacted)
+}
+
+// redactAuditValue 递归脱敏快照中的敏感字段
+func redactAuditValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if auditRedactedKeys[strings.ToLower(key)] {
+				if s, ok := item.(string); !ok || s != "" {
+					v[key] = auditRedactedValue
+				}
+				continue
+			}
+			v[key] = redactAuditValue(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactAuditValue(item)
+		}
+		return v
+	}
+	return value
+}
+
+// snapshotJSON 将存储的快照转为响应中的 JSON（为空时返回 null）
+func snapshotJSON(snapshot string) json.RawMessage {
+	if snapshot == "" || !json.Valid([]byte(snapshot)) {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(snapshot)
+}
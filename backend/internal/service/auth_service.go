@@ -3,6 +3,9 @@ package service
 import (
 	"errors"
 	"fmt"
+	"log"
+	"time"
+	"unicode"
 
 	"gen-go/internal/config"
 	"gen-go/internal/dto"
@@ -13,17 +16,19 @@ import (
 
 // AuthService 认证服务
 type AuthService struct {
-	userRepo   *repository.UserRepository
-	jwtManager *utils.JWTManager
-	cfg        *config.Config
+	userRepo     *repository.UserRepository
+	jwtManager   *utils.JWTManager
+	tokenService *TokenService
+	cfg          *config.Config
 }
 
 // NewAuthService 创建认证服务
-func NewAuthService(userRepo *repository.UserRepository, jwtManager *utils.JWTManager, cfg *config.Config) *AuthService {
+func NewAuthService(userRepo *repository.UserRepository, jwtManager *utils.JWTManager, tokenService *TokenService, cfg *config.Config) *AuthService {
 	return &AuthService{
-		userRepo:   userRepo,
-		jwtManager: jwtManager,
-		cfg:        cfg,
+		userRepo:     userRepo,
+		jwtManager:   jwtManager,
+		tokenService: tokenService,
+		cfg:          cfg,
 	}
 }
 
@@ -38,8 +43,12 @@ func (s *AuthService) Register(req *dto.RegisterRequest) (*dto.LoginResponse, er
 		return nil, errors.New("用户名已存在")
 	}
 
+	if err := s.ValidatePassword(req.Password); err != nil {
+		return nil, err
+	}
+
 	// 哈希密码
-	hashedPassword, err := utils.HashPassword(req.Password)
+	hashedPassword, err := s.hashPassword(req.Password)
 	if err != nil {
 		return nil, fmt.Errorf("密码哈希失败: %w", err)
 	}
@@ -50,28 +59,14 @@ func (s *AuthService) Register(req *dto.RegisterRequest) (*dto.LoginResponse, er
 		PasswordHash: hashedPassword,
 		IsActive:     true,
 		IsAdmin:      false,
+		Role:         models.DefaultRole,
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
 		return nil, fmt.Errorf("创建用户失败: %w", err)
 	}
 
-	// 生成Token
-	token, err := s.jwtManager.GenerateToken(user.ID, user.Username, user.IsAdmin)
-	if err != nil {
-		return nil, fmt.Errorf("生成Token失败: %w", err)
-	}
-
-	return &dto.LoginResponse{
-		AccessToken: token,
-		TokenType:   "bearer",
-		User: dto.UserInfo{
-			ID:       user.ID,
-			Username: user.Username,
-			IsActive: user.IsActive,
-			IsAdmin:  user.IsAdmin,
-		},
-	}, nil
+	return s.issueTokens(user)
 }
 
 // Login 用户登录
@@ -92,20 +87,150 @@ func (s *AuthService) Login(req *dto.LoginRequest) (*dto.LoginResponse, error) {
 		return nil, errors.New("用户已被禁用")
 	}
 
-	// 生成Token
+	return s.issueTokens(user)
+}
+
+// ChangePassword 修改当前用户密码（需验证原密码），修改后清除强制修改标记，并吊销用户已签发的全部Token（需重新登录）
+func (s *AuthService) ChangePassword(userID uint, req *dto.ChangePasswordRequest) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return errors.New("用户不存在")
+	}
+
+	if err := utils.CheckPassword(req.OldPassword, user.PasswordHash); err != nil {
+		return errors.New("原密码错误")
+	}
+	if req.NewPassword == req.OldPassword {
+		return errors.New("新密码不能与原密码相同")
+	}
+	if err := s.ValidatePassword(req.NewPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.hashPassword(req.NewPassword)
+	if err != nil {
+		return fmt.Errorf("密码哈希失败: %w", err)
+	}
+	if err := s.userRepo.UpdatePassword(userID, hashedPassword, false); err != nil {
+		return fmt.Errorf("更新密码失败: %w", err)
+	}
+	return s.tokenService.RevokeUserTokens(userID)
+}
+
+// ResetPassword 管理员重置用户密码，吊销用户已签发的全部Token，用户下次登录后必须修改密码
+func (s *AuthService) ResetPassword(userID uint, newPassword string) error {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return errors.New("用户不存在")
+	}
+	if err := s.ValidatePassword(newPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.hashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("密码哈希失败: %w", err)
+	}
+	if err := s.userRepo.UpdatePassword(userID, hashedPassword, true); err != nil {
+		return fmt.Errorf("重置密码失败: %w", err)
+	}
+	return s.tokenService.RevokeUserTokens(userID)
+}
+
+// ValidatePassword 按配置的密码策略校验密码复杂度
+func (s *AuthService) ValidatePassword(password string) error {
+	policy := s.cfg.Current().Password
+	if len([]rune(password)) < policy.MinLength {
+		return fmt.Errorf("密码长度不能少于%d位", policy.MinLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	if policy.RequireUppercase && !hasUpper {
+		return errors.New("密码必须包含大写字母")
+	}
+	if policy.RequireLowercase && !hasLower {
+		return errors.New("密码必须包含小写字母")
+	}
+	if policy.RequireDigit && !hasDigit {
+		return errors.New("密码必须包含数字")
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		return errors.New("密码必须包含特殊字符")
+	}
+	return nil
+}
+
+// hashPassword 使用配置的 bcrypt 计算强度哈希密码
+func (s *AuthService) hashPassword(password string) (string, error) {
+	return utils.HashPasswordWithCost(password, s.cfg.Current().Password.BcryptCost)
+}
+
+// Refresh 使用刷新Token换取新的访问Token，刷新Token同时轮换（旧Token立即失效）
+func (s *AuthService) Refresh(refreshToken string) (*dto.LoginResponse, error) {
+	userID, err := s.tokenService.ConsumeRefreshToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	if !user.IsActive {
+		return nil, errors.New("用户已被禁用")
+	}
+
+	return s.issueTokens(user)
+}
+
+// Logout 吊销当前访问Token，并吊销客户端提交的刷新Token
+func (s *AuthService) Logout(tokenID string, expiresAt time.Time, refreshToken string) error {
+	if err := s.tokenService.RevokeAccessToken(tokenID, expiresAt); err != nil {
+		return err
+	}
+	return s.tokenService.RevokeRefreshToken(refreshToken)
+}
+
+// issueTokens 为用户签发访问Token与刷新Token
+// 刷新Token保存失败（如 Redis 不可用）时仍返回访问Token，客户端过期后需重新登录
+func (s *AuthService) issueTokens(user *models.User) (*dto.LoginResponse, error) {
 	token, err := s.jwtManager.GenerateToken(user.ID, user.Username, user.IsAdmin)
 	if err != nil {
 		return nil, fmt.Errorf("生成Token失败: %w", err)
 	}
 
+	refreshToken, err := s.tokenService.IssueRefreshToken(user.ID)
+	if err != nil {
+		log.Printf("[Auth] 用户 %s 签发刷新Token失败: %v", user.Username, err)
+		refreshToken = ""
+	}
+
 	return &dto.LoginResponse{
-		AccessToken: token,
-		TokenType:   "bearer",
+		AccessToken:  token,
+		RefreshToken: refreshToken,
+		TokenType:    "bearer",
+		ExpiresIn:    int64(s.jwtManager.ExpireDuration().Seconds()),
 		User: dto.UserInfo{
 			ID:       user.ID,
 			Username: user.Username,
 			IsActive: user.IsActive,
 			IsAdmin:  user.IsAdmin,
+			Role:     user.EffectiveRole(),
+			Timezone: user.Timezone,
+
+			MustChangePassword: user.MustChangePassword,
 		},
 	}, nil
 }
@@ -122,9 +247,34 @@ func (s *AuthService) GetMe(userID uint) (*dto.UserInfo, error) {
 		Username: user.Username,
 		IsActive: user.IsActive,
 		IsAdmin:  user.IsAdmin,
+		Role:     user.EffectiveRole(),
+		Timezone: user.Timezone,
+
+		MustChangePassword: user.MustChangePassword,
 	}, nil
 }
 
+// UpdateTimezone 设置用户展示时区（空字符串表示恢复系统默认时区）
+func (s *AuthService) UpdateTimezone(userID uint, timezone string) (*dto.UserInfo, error) {
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("无效的时区: %s", timezone)
+		}
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("用户不存在")
+	}
+
+	user.Timezone = timezone
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("更新时区失败: %w", err)
+	}
+
+	return s.GetMe(userID)
+}
+
 // InitAdmin 初始化管理员账户
 func (s *AuthService) InitAdmin() error {
 	// 检查是否已有管理员
@@ -134,22 +284,24 @@ func (s *AuthService) InitAdmin() error {
 	}
 
 	// 检查密码是否已经是bcrypt哈希格式(以$2a$或$2b$开头)
-	passwordHash := s.cfg.Admin.Password
+	passwordHash := s.cfg.Current().Admin.Password
 	if len(passwordHash) < 4 || (passwordHash[:4] != "$2a$" && passwordHash[:4] != "$2b$") {
 		// 密码不是bcrypt哈希格式,需要哈希
-		hashedPassword, err := utils.HashPassword(s.cfg.Admin.Password)
+		hashedPassword, err := s.hashPassword(s.cfg.Current().Admin.Password)
 		if err != nil {
 			return fmt.Errorf("密码哈希失败: %w", err)
 		}
 		passwordHash = hashedPassword
 	}
 
-	// 创建管理员
+	// 创建管理员（配置文件中的初始密码首次登录后必须修改）
 	user := &models.User{
-		Username:     s.cfg.Admin.Username,
-		PasswordHash: passwordHash,
-		IsActive:     true,
-		IsAdmin:      true,
+		Username:           s.cfg.Current().Admin.Username,
+		PasswordHash:       passwordHash,
+		IsActive:           true,
+		IsAdmin:            true,
+		Role:               models.RoleAdmin,
+		MustChangePassword: true,
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
@@ -0,0 +1,396 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// backupFilePrefix 备份文件名前缀，文件名为 app-<UTC 时间>.db（SQLite）或 app-<UTC 时间>.dump（PostgreSQL 自定义格式）
+	backupFilePrefix = "app-"
+	// backupTimeLayout 备份文件名中的时间格式（按字典序即按时间排序）
+	backupTimeLayout = "20060102T150405Z"
+	backupExtSQLite  = ".db"
+	backupExtPg      = ".dump"
+)
+
+// BackupService 数据库备份服务
+// SQLite 使用 VACUUM INTO 在线生成一致性快照（不阻塞写入），PostgreSQL 调用 pg_dump 导出自定义格式；
+// 备份写入 backup.dir，超过 backup.keep 个时删除最旧的备份
+type BackupService struct {
+	db  *gorm.DB
+	cfg *config.Config
+
+	lock    sync.Mutex
+	running bool
+}
+
+// NewBackupService 创建数据库备份服务
+func NewBackupService(db *gorm.DB, cfg *config.Config) *BackupService {
+	return &BackupService{db: db, cfg: cfg}
+}
+
+// Create 生成一份数据库备份并轮转旧备份（同一时间只允许一个备份）
+func (s *BackupService) Create() (*dto.BackupInfo, error) {
+	s.lock.Lock()
+	if s.running {
+		s.lock.Unlock()
+		return nil, fmt.Errorf("已有备份正在进行，请稍后再试")
+	}
+	s.running = true
+	s.lock.Unlock()
+	defer func() {
+		s.lock.Lock()
+		s.running = false
+		s.lock.Unlock()
+	}()
+
+	dir := s.cfg.Current().BackupDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建备份目录失败: %w", err)
+	}
+
+	ext := backupExtSQLite
+	if s.cfg.Current().Database.Driver == config.DatabaseDriverPostgres {
+		ext = backupExtPg
+	}
+	name := backupFilePrefix + time.Now().UTC().Format(backupTimeLayout) + ext
+	path := filepath.Join(dir, name)
+	tmpPath := path + ".tmp"
+	os.Remove(tmpPath)
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.Current().Backup.GetTimeout())
+	defer cancel()
+
+	start := time.Now()
+	var err error
+	if ext == backupExtPg {
+		err = s.dumpPostgres(ctx, tmpPath)
+	} else {
+		err = s.snapshotSQLite(ctx, tmpPath)
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+	log.Printf("[Backup] 数据库备份完成: %s，耗时 %v", path, time.Since(start).Round(time.Millisecond))
+
+	s.rotate(dir)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取备份文件失败: %w", err)
+	}
+	return backupInfo(info), nil
+}
+
+// snapshotSQLite 用 VACUUM INTO 生成 SQLite 的一致性快照
+func (s *BackupService) snapshotSQLite(ctx context.Context, path string) error {
+	if err := s.db.WithContext(ctx).Exec("VACUUM INTO ?", path).Error; err != nil {
+		return fmt.Errorf("SQLite 备份失败: %w", err)
+	}
+	return nil
+}
+
+// dumpPostgres 调用 pg_dump 导出 PostgreSQL 主库（自定义格式，可用 pg_restore 恢复）
+func (s *BackupService) dumpPostgres(ctx context.Context, path string) error {
+	conninfo, env, err := pgConnection(s.cfg.Current().Database.DSN)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, s.cfg.Current().Backup.PgDumpPath,
+		"--format=custom", "--no-owner", "--file="+path, "--dbname="+conninfo)
+	cmd.Env = env
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("pg_dump 执行失败: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// pgConnection 将 PostgreSQL 连接串拆分为不含密码的连接串和 pg_dump/pg_restore 的环境变量
+// 密码通过 PGPASSWORD 传递，不出现在命令行参数中（命令行对本机其他用户可见）；
+// TimeZone 是 Go 驱动的会话参数，libpq 不识别，一并去掉
+func pgConnection(dsn string) (string, []string, error) {
+	var password string
+	var conninfo string
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", nil, fmt.Errorf("解析数据库连接串失败: %w", err)
+		}
+		if u.User != nil {
+			password, _ = u.User.Password()
+			u.User = url.User(u.User.Username())
+		}
+		query := u.Query()
+		query.Del("TimeZone")
+		query.Del("timezone")
+		u.RawQuery = query.Encode()
+		conninfo = u.String()
+	} else {
+		params, err := parsePgKeywords(dsn)
+		if err != nil {
+			return "", nil, err
+		}
+		parts := make([]string, 0, len(params))
+		for _, param := range params {
+			switch param[0] {
+			case "password":
+				password = param[1]
+			case "TimeZone", "timezone":
+			default:
+				parts = append(parts, param[0]+"="+quotePgValue(param[1]))
+			}
+		}
+		conninfo = strings.Join(parts, " ")
+	}
+
+	env := os.Environ()
+	if password != "" {
+		env = append(env, "PGPASSWORD="+password)
+	}
+	return conninfo, env, nil
+}
+
+// parsePgKeywords 解析 key=value 格式的 libpq 连接串，值可以用单引号包裹（支持 \' 和 \\ 转义）
+func parsePgKeywords(dsn string) ([][2]string, error) {
+	var params [][2]string
+	s := strings.TrimSpace(dsn)
+	for s != "" {
+		eq := strings.IndexByte(s, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("解析数据库连接串失败: 缺少 key=value")
+		}
+		key := strings.TrimSpace(s[:eq])
+		s = strings.TrimLeft(s[eq+1:], " ")
+
+		var value strings.Builder
+		if strings.HasPrefix(s, "'") {
+			i := 1
+			for ; i < len(s) && s[i] != '\''; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				value.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, fmt.Errorf("解析数据库连接串失败: 引号未闭合")
+			}
+			s = s[i+1:]
+		} else {
+			end := strings.IndexAny(s, " \t\n")
+			if end < 0 {
+				end = len(s)
+			}
+			value.WriteString(s[:end])
+			s = s[end:]
+		}
+		params = append(params, [2]string{key, value.String()})
+		s = strings.TrimLeft(s, " \t\n")
+	}
+	return params, nil
+}
+
+// quotePgValue 按 libpq 规则给连接串中的值加引号
+func quotePgValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " '\\\t\n") {
+		return value
+	}
+	return "'" + strings.NewReplacer("\\", "\\\\", "'", "\\'").Replace(value) + "'"
+}
+
+// rotate 只保留最新的 backup.keep 个备份
+func (s *BackupService) rotate(dir string) {
+	names, err := listBackupNames(dir)
+	if err != nil {
+		log.Printf("[Backup] 读取备份目录失败: %v", err)
+		return
+	}
+	for i := s.cfg.Current().Backup.Keep; i < len(names); i++ {
+		if err := os.Remove(filepath.Join(dir, names[i])); err != nil {
+			log.Printf("[Backup] 删除旧备份 %s 失败: %v", names[i], err)
+			continue
+		}
+		log.Printf("[Backup] 已删除旧备份: %s", names[i])
+	}
+}
+
+// List 列出全部备份（最新的在前）
+func (s *BackupService) List() ([]dto.BackupInfo, error) {
+	dir := s.cfg.Current().BackupDir()
+	names, err := listBackupNames(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取备份目录失败: %w", err)
+	}
+
+	backups := make([]dto.BackupInfo, 0, len(names))
+	for _, name := range names {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, *backupInfo(info))
+	}
+	return backups, nil
+}
+
+// GetPath 获取备份文件路径（用于下载），name 只能是 List 返回的文件名
+func (s *BackupService) GetPath(name string) (string, error) {
+	if !isBackupName(name) {
+		return "", fmt.Errorf("备份不存在")
+	}
+	path := filepath.Join(s.cfg.Current().BackupDir(), name)
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("备份不存在")
+		}
+		return "", fmt.Errorf("读取备份文件失败: %w", err)
+	}
+	return path, nil
+}
+
+// RestoreBackup 从备份文件恢复数据库，只能在服务停止时执行（由 server -restore 调用）
+// SQLite 将原数据库文件改名为 <path>.before-restore-<时间> 后替换为备份；PostgreSQL 调用 pg_restore --clean 覆盖主库
+// source 为备份文件路径，或 backup.dir 下的备份文件名
+func RestoreBackup(cfg *config.Config, source string) error {
+	path := source
+	if _, err := os.Stat(path); err != nil && isBackupName(source) {
+		path = filepath.Join(cfg.BackupDir(), source)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("备份文件不存在: %s", source)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Backup.GetTimeout())
+	defer cancel()
+
+	if cfg.Database.Driver == config.DatabaseDriverPostgres {
+		conninfo, env, err := pgConnection(cfg.Database.DSN)
+		if err != nil {
+			return err
+		}
+		cmd := exec.CommandContext(ctx, cfg.Backup.PgRestorePath,
+			"--clean", "--if-exists", "--no-owner", "--single-transaction", "--dbname="+conninfo, path)
+		cmd.Env = env
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("pg_restore 执行失败: %v: %s", err, strings.TrimSpace(string(output)))
+		}
+		log.Printf("[Backup] 已从 %s 恢复 PostgreSQL 数据库", path)
+		return nil
+	}
+
+	target := cfg.Database.Path
+	if _, err := os.Stat(target); err == nil {
+		previous := target + ".before-restore-" + time.Now().UTC().Format(backupTimeLayout)
+		if err := os.Rename(target, previous); err != nil {
+			return fmt.Errorf("保留原数据库文件失败: %w", err)
+		}
+		log.Printf("[Backup] 原数据库文件已改名为 %s", previous)
+	}
+	// 旧库的 WAL 与备份不匹配，必须一并移除
+	os.Remove(target + "-wal")
+	os.Remove(target + "-shm")
+
+	if err := copyFile(path, target); err != nil {
+		return fmt.Errorf("复制备份文件失败: %w", err)
+	}
+	log.Printf("[Backup] 已从 %s 恢复 SQLite 数据库到 %s", path, target)
+	return nil
+}
+
+// copyFile 复制文件（先写入临时文件再改名，避免留下不完整的数据库）
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err == nil {
+		err = out.Sync()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// listBackupNames 列出目录下的备份文件名（最新的在前），目录不存在时返回空
+func listBackupNames(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && isBackupName(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	return names, nil
+}
+
+// isBackupName 是否为备份文件名（拒绝路径分隔符，防止访问备份目录以外的文件）
+func isBackupName(name string) bool {
+	if name != filepath.Base(name) || strings.ContainsAny(name, `/\`) || !strings.HasPrefix(name, backupFilePrefix) {
+		return false
+	}
+	return strings.HasSuffix(name, backupExtSQLite) || strings.HasSuffix(name, backupExtPg)
+}
+
+// backupInfo 将备份文件信息转换为响应
+func backupInfo(info os.FileInfo) *dto.BackupInfo {
+	name := info.Name()
+	driver := config.DatabaseDriverSQLite
+	if strings.HasSuffix(name, backupExtPg) {
+		driver = config.DatabaseDriverPostgres
+	}
+	createdAt := info.ModTime()
+	stamp := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(name, backupFilePrefix), backupExtSQLite), backupExtPg)
+	if t, err := time.Parse(backupTimeLayout, stamp); err == nil {
+		createdAt = t
+	}
+	return &dto.BackupInfo{
+		Name:      name,
+		Driver:    driver,
+		Size:      info.Size(),
+		CreatedAt: dto.FormatTime(createdAt),
+	}
+}
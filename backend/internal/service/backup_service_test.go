@@ -0,0 +1,62 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPgConnection(t *testing.T) {
+	tests := []struct {
+		dsn          string
+		wantConninfo string
+		wantPassword string
+	}{
+		{
+			"host=127.0.0.1 user=gen password=secret dbname=gen port=5432 sslmode=disable TimeZone=UTC",
+			"host=127.0.0.1 user=gen dbname=gen port=5432 sslmode=disable",
+			"secret",
+		},
+		{
+			`host=db user=gen password='it\'s a secret' dbname='my db'`,
+			`host=db user=gen dbname='my db'`,
+			"it's a secret",
+		},
+		{
+			"host=db user=gen dbname=gen",
+			"host=db user=gen dbname=gen",
+			"",
+		},
+		{
+			"postgres://gen:p%40ss@db:5432/gen?sslmode=disable&TimeZone=UTC",
+			"postgres://gen@db:5432/gen?sslmode=disable",
+			"p@ss",
+		},
+	}
+	for _, tt := range tests {
+		conninfo, env, err := pgConnection(tt.dsn)
+		if err != nil {
+			t.Errorf("pgConnection(%q) error = %v", tt.dsn, err)
+			continue
+		}
+		if conninfo != tt.wantConninfo {
+			t.Errorf("pgConnection(%q) conninfo = %q, want %q", tt.dsn, conninfo, tt.wantConninfo)
+		}
+		var password string
+		for _, kv := range env {
+			if strings.HasPrefix(kv, "PGPASSWORD=") {
+				password = strings.TrimPrefix(kv, "PGPASSWORD=")
+			}
+		}
+		if password != tt.wantPassword {
+			t.Errorf("pgConnection(%q) PGPASSWORD = %q, want %q", tt.dsn, password, tt.wantPassword)
+		}
+	}
+}
+
+func TestPgConnectionInvalid(t *testing.T) {
+	for _, dsn := range []string{"host", "host=db password='unterminated"} {
+		if _, _, err := pgConnection(dsn); err == nil {
+			t.Errorf("pgConnection(%q) expected error", dsn)
+		}
+	}
+}
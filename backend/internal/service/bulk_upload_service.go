@@ -0,0 +1,164 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"path"
+	"strings"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/utils"
+)
+
+// BulkUploadService 批量上传服务
+// 一次请求上传多个文件或压缩包（.zip/.tar.gz），压缩包在服务端解压，每个文件单独登记为数据文件
+type BulkUploadService struct {
+	dataFileService *DataFileService
+	cfg             *config.Config
+}
+
+// NewBulkUploadService 创建批量上传服务
+func NewBulkUploadService(dataFileService *DataFileService, cfg *config.Config) *BulkUploadService {
+	return &BulkUploadService{
+		dataFileService: dataFileService,
+		cfg:             cfg,
+	}
+}
+
+// Upload 逐个登记上传的文件，返回每个文件的结果
+// 单个文件类型不符、校验不通过或压缩包无法解压时只记录该文件失败；处理的文件总数（含压缩包中的文件）不超过 upload.max_batch_files
+func (s *BulkUploadService) Upload(userID uint, headers []*multipart.FileHeader, validationMode string) (*dto.BulkUploadResponse, error) {
+	if len(headers) == 0 {
+		return nil, fmt.Errorf("请通过 files[] 字段上传至少一个文件")
+	}
+	if validationMode != "" && !IsValidMode(validationMode) {
+		return nil, fmt.Errorf("不支持的校验模式: %s", validationMode)
+	}
+
+	batch := &bulkUploadBatch{resp: &dto.BulkUploadResponse{Results: []dto.BulkUploadResult{}}}
+	for _, header := range headers {
+		content, err := readMultipartFile(header)
+		if err != nil {
+			batch.fail(header.Filename, "", err)
+			continue
+		}
+		if utils.IsArchive(header.Filename) {
+			s.uploadArchive(userID, header.Filename, content, validationMode, batch)
+		} else {
+			s.uploadOne(userID, header.Filename, "", content, validationMode, batch)
+		}
+	}
+
+	log.Printf("[BulkUpload] 用户 %d 批量上传: 共 %d 个文件, 成功 %d, 失败 %d",
+		userID, batch.resp.Total, batch.resp.Succeeded, batch.resp.Failed)
+	return batch.resp, nil
+}
+
+// uploadArchive 解压压缩包并逐个登记其中的文件
+func (s *BulkUploadService) uploadArchive(userID uint, archive string, content []byte, validationMode string, batch *bulkUploadBatch) {
+	remaining := s.cfg.Current().Upload.MaxBatchFiles - batch.resp.Total
+	if remaining <= 0 {
+		batch.fail(archive, "", fmt.Errorf("单次最多上传 %d 个文件", s.cfg.Current().Upload.MaxBatchFiles))
+		return
+	}
+
+	// 本批次之前的压缩包已用完解压总量时不再解压（ArchiveLimits 的各项上限必须大于 0）
+	remainingBytes := s.cfg.Current().Upload.GetMaxBatchSizeBytes() - batch.extracted
+	if remainingBytes <= 0 {
+		batch.fail(archive, "", fmt.Errorf("本次上传的压缩包解压后总大小已达到上限 %d MB", s.cfg.Current().Upload.MaxBatchSizeMB))
+		return
+	}
+
+	entries, err := utils.ExtractArchive(archive, content, utils.ArchiveLimits{
+		MaxEntries:    remaining,
+		MaxEntryBytes: s.cfg.Current().Upload.GetMaxSizeBytes(),
+		MaxTotalBytes: remainingBytes,
+	})
+	if err != nil {
+		batch.fail(archive, "", err)
+		return
+	}
+	if len(entries) == 0 {
+		batch.fail(archive, "", fmt.Errorf("压缩包中没有文件"))
+		return
+	}
+
+	for _, entry := range entries {
+		batch.extracted += int64(len(entry.Content))
+		s.uploadOne(userID, entry.Name, archive, entry.Content, validationMode, batch)
+	}
+}
+
+// uploadOne 检查文件类型后登记为数据文件（与普通上传相同的转换、校验和病毒扫描流程）
+func (s *BulkUploadService) uploadOne(userID uint, name, archive string, content []byte, validationMode string, batch *bulkUploadBatch) {
+	if batch.resp.Total >= s.cfg.Current().Upload.MaxBatchFiles {
+		batch.fail(name, archive, fmt.Errorf("单次最多上传 %d 个文件", s.cfg.Current().Upload.MaxBatchFiles))
+		return
+	}
+
+	filename := path.Base(strings.ReplaceAll(name, "\\", "/"))
+	head := content
+	if len(head) > utils.UploadSniffSize {
+		head = head[:utils.UploadSniffSize]
+	}
+	if err := utils.CheckUploadType(filename, head, s.cfg.Current().Upload.AllowedExtensions); err != nil {
+		batch.fail(name, archive, err)
+		return
+	}
+
+	dataFile, report, err := s.dataFileService.SaveUploadedContent(userID, filename, content, validationMode)
+	if err != nil {
+		batch.add(dto.BulkUploadResult{Filename: name, Archive: archive, Validation: report, Error: err.Error()})
+		return
+	}
+
+	batch.add(dto.BulkUploadResult{
+		Filename:    name,
+		Archive:     archive,
+		Success:     true,
+		FileID:      dataFile.ID,
+		DisplayPath: s.dataFileService.GetFileDisplayPath(dataFile.ID, dataFile.Filename),
+		FileSize:    dataFile.FileSize,
+		Validation:  report,
+	})
+}
+
+// bulkUploadBatch 一次批量上传的进度
+type bulkUploadBatch struct {
+	resp      *dto.BulkUploadResponse
+	extracted int64 // 已解压的字节数
+}
+
+// add 记录一个文件的结果
+func (b *bulkUploadBatch) add(result dto.BulkUploadResult) {
+	b.resp.Results = append(b.resp.Results, result)
+	b.resp.Total++
+	if result.Success {
+		b.resp.Succeeded++
+	} else {
+		b.resp.Failed++
+	}
+}
+
+// fail 记录一个失败的文件
+func (b *bulkUploadBatch) fail(filename, archive string, err error) {
+	b.add(dto.BulkUploadResult{Filename: filename, Archive: archive, Error: err.Error()})
+}
+
+// readMultipartFile 读取上传的文件内容
+func readMultipartFile(header *multipart.FileHeader) ([]byte, error) {
+	src, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer src.Close()
+
+	content, err := io.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %w", err)
+	}
+	return content, nil
+}
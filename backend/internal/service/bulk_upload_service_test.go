@@ -0,0 +1,38 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+)
+
+func TestUploadArchiveBatchBudgetExhausted(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("a.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("{\"n\":1}\n"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Upload: config.UploadConfig{MaxBatchFiles: 10, MaxBatchSizeMB: 1, MaxSizeMB: 1}}
+	s := NewBulkUploadService(nil, cfg)
+
+	for _, extracted := range []int64{cfg.Upload.GetMaxBatchSizeBytes(), cfg.Upload.GetMaxBatchSizeBytes() + 1} {
+		batch := &bulkUploadBatch{resp: &dto.BulkUploadResponse{}, extracted: extracted}
+		s.uploadArchive(1, "data.zip", buf.Bytes(), "", batch)
+		if batch.resp.Failed != 1 || len(batch.resp.Results) != 1 {
+			t.Fatalf("extracted %d: results = %+v, want one failure", extracted, batch.resp.Results)
+		}
+		if msg := batch.resp.Results[0].Error; !strings.Contains(msg, "总大小已达到上限") {
+			t.Errorf("extracted %d: error = %q", extracted, msg)
+		}
+	}
+}
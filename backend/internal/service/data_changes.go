@@ -0,0 +1,100 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+)
+
+// 增量同步限制
+const (
+	DataChangesDefaultLimit = 500
+	DataChangesMaxLimit     = 2000
+	dataTombstoneRetention  = 7 * 24 * time.Hour // 删除记录保留期，更早的游标需要全量重新同步
+)
+
+// ListChanges 获取任务在游标之后新增、修改和删除的数据
+// since 为上次响应的 next_cursor，也可以是 RFC3339 时间；为空时从头同步
+func (s *GeneratedDataService) ListChanges(taskID string, userID uint, since string, limit int) (*dto.DataChangesResponse, error) {
+	if err := s.checkTask(taskID, userID, models.PermissionView); err != nil {
+		return nil, err
+	}
+
+	sinceTime, afterID, err := parseChangeCursor(since)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := &dto.DataChangesResponse{
+		TaskID:  taskID,
+		Deleted: []uint{},
+	}
+	if since != "" && sinceTime.Before(now.Add(-dataTombstoneRetention)) {
+		result.Reset = true
+		sinceTime, afterID = time.Time{}, 0
+	}
+
+	// 多取一条判断是否还有下一批
+	dataList, err := s.generatedDataRepo.ListChangedSince(taskID, sinceTime, afterID, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("获取变更数据失败: %w", err)
+	}
+	if len(dataList) > limit {
+		result.HasMore = true
+		dataList = dataList[:limit]
+	}
+
+	result.Items = make([]dto.GeneratedDataResponse, len(dataList))
+	for i := range dataList {
+		result.Items[i] = toGeneratedDataResponse(&dataList[i])
+	}
+
+	if !sinceTime.IsZero() {
+		result.Deleted, err = s.generatedDataRepo.ListDeletedSince(taskID, sinceTime)
+		if err != nil {
+			return nil, fmt.Errorf("获取删除记录失败: %w", err)
+		}
+	}
+
+	// 还有下一批时从本批最后一条继续；否则推进到查询开始的时间，避免重复返回删除记录
+	if result.HasMore {
+		last := dataList[len(dataList)-1]
+		result.NextCursor = formatChangeCursor(last.UpdatedAt, last.ID)
+	} else {
+		result.NextCursor = formatChangeCursor(now, 0)
+	}
+	return result, nil
+}
+
+// formatChangeCursor 生成增量同步游标：<updated_at 纳秒时间戳>_<数据ID>
+func formatChangeCursor(t time.Time, id uint) string {
+	return fmt.Sprintf("%d_%d", t.UnixNano(), id)
+}
+
+// parseChangeCursor 解析增量同步游标，兼容 RFC3339 时间
+// 返回本地时区的时间，与写入 updated_at 时使用的时区一致（SQLite 按字符串比较时间）
+func parseChangeCursor(cursor string) (time.Time, uint, error) {
+	if cursor == "" {
+		return time.Time{}, 0, nil
+	}
+
+	parts := strings.SplitN(cursor, "_", 2)
+	if len(parts) == 2 {
+		nanos, err1 := strconv.ParseInt(parts[0], 10, 64)
+		id, err2 := strconv.ParseUint(parts[1], 10, 32)
+		if err1 == nil && err2 == nil {
+			return time.Unix(0, nanos), uint(id), nil
+		}
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, cursor)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("无效的 since 参数: %s", cursor)
+	}
+	return t.Local(), 0, nil
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+)
+
+// ErrFilenameExists 文件所有者已有同名文件
+var ErrFilenameExists = errors.New("已存在同名文件")
+
+// UpdateFileMetadata 修改文件所在的文件夹和标签（需要文件的编辑权限）
+func (s *DataFileService) UpdateFileMetadata(fileID uint, userID uint, req *dto.UpdateFileMetadataRequest) (*dto.FileMetadataResponse, error) {
+	file, err := s.fileRepo.GetEditableByIDAndUserID(fileID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("文件不存在或无权访问")
+	}
+
+	if req.FolderPath != nil {
+		folder, err := normalizeFolderPath(*req.FolderPath)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.fileRepo.UpdateFolder([]uint{file.ID}, folder); err != nil {
+			return nil, fmt.Errorf("修改文件夹失败: %w", err)
+		}
+		file.FolderPath = folder
+	}
+
+	if req.Tags != nil {
+		if err := s.replaceTags(file.ID, *req.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	tags, err := s.FileTags(file.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.FileMetadataResponse{FileID: file.ID, FolderPath: file.FolderPath, Tags: tags}, nil
+}
+
+// UpdateFile 重命名文件、修改描述和标签（需要文件的编辑权限）
+// 新文件名在文件所有者的文件中必须唯一，且不能修改扩展名（文件格式由扩展名识别）；任务通过 db://file_id 引用文件，重命名不影响已有任务
+func (s *DataFileService) UpdateFile(fileID uint, userID uint, req *dto.UpdateDataFileRequest) (*dto.DataFileResponse, error) {
+	file, err := s.fileRepo.GetEditableByIDAndUserID(fileID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("文件不存在或无权访问")
+	}
+
+	updates := map[string]interface{}{}
+	if req.Filename != nil {
+		filename, err := s.checkFilename(file, *req.Filename)
+		if err != nil {
+			return nil, err
+		}
+		updates["filename"] = filename
+		file.Filename = filename
+	}
+	if req.Description != nil {
+		description := strings.TrimSpace(*req.Description)
+		updates["description"] = description
+		file.Description = description
+	}
+	if len(updates) > 0 {
+		if err := s.fileRepo.UpdateInfo(file.ID, updates); err != nil {
+			return nil, fmt.Errorf("修改文件信息失败: %w", err)
+		}
+		file.UpdatedAt = time.Now()
+	}
+
+	if req.Tags != nil {
+		if err := s.replaceTags(file.ID, *req.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	tags, err := s.FileTags(file.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := s.NewFileResponse(file, tags)
+	return &resp, nil
+}
+
+// checkFilename 校验新文件名，返回去掉首尾空白后的文件名
+func (s *DataFileService) checkFilename(file *models.DataFile, filename string) (string, error) {
+	filename = strings.TrimSpace(filename)
+	if filename == "" || filename == "." || filename == ".." {
+		return "", fmt.Errorf("文件名不能为空")
+	}
+	if strings.ContainsAny(filename, "/\\") {
+		return "", fmt.Errorf("文件名不能包含路径分隔符")
+	}
+	if !strings.EqualFold(path.Ext(filename), path.Ext(file.Filename)) {
+		return "", fmt.Errorf("不能修改文件扩展名（当前为 %s）", path.Ext(file.Filename))
+	}
+	if filename == file.Filename {
+		return filename, nil
+	}
+
+	exists, err := s.fileRepo.FilenameExists(file.UserID, filename, file.ID)
+	if err != nil {
+		return "", fmt.Errorf("检查文件名失败: %w", err)
+	}
+	if exists {
+		return "", fmt.Errorf("%w: %s", ErrFilenameExists, filename)
+	}
+	return filename, nil
+}
+
+// replaceTags 替换文件的全部标签
+func (s *DataFileService) replaceTags(fileID uint, tags []string) error {
+	names, err := normalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	if len(names) > models.MaxFileTags {
+		return fmt.Errorf("单个文件最多 %d 个标签", models.MaxFileTags)
+	}
+	fileTags, err := s.tagRepo.GetOrCreate(names)
+	if err != nil {
+		return fmt.Errorf("保存标签失败: %w", err)
+	}
+	if err := s.tagRepo.ReplaceFileTags(fileID, fileTagIDs(fileTags)); err != nil {
+		return fmt.Errorf("保存标签失败: %w", err)
+	}
+	return nil
+}
+
+// BatchUpdateFileMetadata 批量移动文件夹、添加或移除标签
+// 不存在或无权修改的文件会被跳过；添加标签后超过单个文件标签上限时整批失败
+func (s *DataFileService) BatchUpdateFileMetadata(userID uint, req *dto.BatchUpdateFileMetadataRequest) (*dto.BatchUpdateFileMetadataResponse, error) {
+	var folder string
+	if req.FolderPath != nil {
+		var err error
+		if folder, err = normalizeFolderPath(*req.FolderPath); err != nil {
+			return nil, err
+		}
+	}
+	addNames, err := normalizeTags(req.AddTags)
+	if err != nil {
+		return nil, err
+	}
+	removeNames, err := normalizeTags(req.RemoveTags)
+	if err != nil {
+		return nil, err
+	}
+	if req.FolderPath == nil && len(addNames) == 0 && len(removeNames) == 0 {
+		return nil, fmt.Errorf("请指定 folder_path、add_tags 或 remove_tags")
+	}
+
+	resp := &dto.BatchUpdateFileMetadataResponse{Skipped: []uint{}}
+	var ids []uint
+	seen := make(map[uint]bool, len(req.FileIDs))
+	for _, id := range req.FileIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if _, err := s.fileRepo.GetEditableByIDAndUserID(id, userID); err != nil {
+			resp.Skipped = append(resp.Skipped, id) // 跳过不存在的文件
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return resp, nil
+	}
+
+	if req.FolderPath != nil {
+		if err := s.fileRepo.UpdateFolder(ids, folder); err != nil {
+			return nil, fmt.Errorf("修改文件夹失败: %w", err)
+		}
+	}
+
+	if len(removeNames) > 0 {
+		tags, err := s.tagRepo.GetByNames(removeNames)
+		if err != nil {
+			return nil, fmt.Errorf("移除标签失败: %w", err)
+		}
+		if err := s.tagRepo.RemoveFileTags(ids, fileTagIDs(tags)); err != nil {
+			return nil, fmt.Errorf("移除标签失败: %w", err)
+		}
+	}
+
+	if len(addNames) > 0 {
+		if err := s.checkTagLimit(ids, addNames); err != nil {
+			return nil, err
+		}
+		tags, err := s.tagRepo.GetOrCreate(addNames)
+		if err != nil {
+			return nil, fmt.Errorf("保存标签失败: %w", err)
+		}
+		if err := s.tagRepo.AddFileTags(ids, fileTagIDs(tags)); err != nil {
+			return nil, fmt.Errorf("保存标签失败: %w", err)
+		}
+	}
+
+	resp.Updated = len(ids)
+	return resp, nil
+}
+
+// checkTagLimit 检查添加标签后各文件的标签数是否超过上限（文件已有的同名标签不重复计算）
+func (s *DataFileService) checkTagLimit(fileIDs []uint, names []string) error {
+	existing, err := s.tagRepo.ListNamesByFileIDs(fileIDs)
+	if err != nil {
+		return fmt.Errorf("统计文件标签失败: %w", err)
+	}
+	for _, id := range fileIDs {
+		tags := make(map[string]bool, len(existing[id])+len(names))
+		for _, name := range existing[id] {
+			tags[name] = true
+		}
+		for _, name := range names {
+			tags[name] = true
+		}
+		if len(tags) > models.MaxFileTags {
+			return fmt.Errorf("文件 %d 的标签数将超过上限 %d", id, models.MaxFileTags)
+		}
+	}
+	return nil
+}
+
+// FileTags 获取文件的标签名（按名称排序）
+func (s *DataFileService) FileTags(fileID uint) ([]string, error) {
+	tags, err := s.tagRepo.ListNamesByFileIDs([]uint{fileID})
+	if err != nil {
+		return nil, fmt.Errorf("获取文件标签失败: %w", err)
+	}
+	return tagsOrEmpty(tags[fileID]), nil
+}
+
+// ListFileTags 获取用户可查看的文件上使用的标签及文件数
+func (s *DataFileService) ListFileTags(userID uint) ([]dto.FileTagInfo, error) {
+	rows, err := s.tagRepo.ListWithCounts(userID)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]dto.FileTagInfo, len(rows))
+	for i, row := range rows {
+		tags[i] = dto.FileTagInfo{Name: row.Name, FileCount: row.FileCount}
+	}
+	return tags, nil
+}
+
+// ListFolders 获取用户可查看的文件所在的文件夹及文件数（workspaceID 非空时只统计该工作区的文件）
+func (s *DataFileService) ListFolders(userID uint, workspaceID *uint) ([]dto.FileFolderInfo, error) {
+	rows, err := s.fileRepo.ListFolders(userID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	folders := make([]dto.FileFolderInfo, len(rows))
+	for i, row := range rows {
+		folders[i] = dto.FileFolderInfo{FolderPath: row.FolderPath, FileCount: row.FileCount}
+	}
+	return folders, nil
+}
+
+// NormalizeFolderFilter 规范化列表过滤用的文件夹路径
+func NormalizeFolderFilter(folder string) (string, error) {
+	return normalizeFolderPath(folder)
+}
+
+// NormalizeTagFilter 规范化列表过滤用的标签
+func NormalizeTagFilter(tags []string) ([]string, error) {
+	return normalizeTags(tags)
+}
+
+// normalizeFolderPath 规范化文件夹路径：统一用 "/" 分隔并去掉首尾的 "/"，空字符串表示根目录
+func normalizeFolderPath(folder string) (string, error) {
+	folder = strings.Trim(strings.ReplaceAll(strings.TrimSpace(folder), "\\", "/"), "/")
+	if folder == "" {
+		return "", nil
+	}
+	for _, part := range strings.Split(folder, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", fmt.Errorf("无效的文件夹路径: %s", folder)
+		}
+	}
+	if len(folder) > models.MaxFolderPathBytes {
+		return "", fmt.Errorf("文件夹路径不能超过 %d 字节", models.MaxFolderPathBytes)
+	}
+	return folder, nil
+}
+
+// normalizeTags 去掉标签首尾空白并去重，空标签会被忽略
+func normalizeTags(tags []string) ([]string, error) {
+	names := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		name := strings.TrimSpace(tag)
+		if name == "" || seen[name] {
+			continue
+		}
+		if strings.Contains(name, ",") {
+			return nil, fmt.Errorf("标签不能包含逗号: %s", name)
+		}
+		if utf8.RuneCountInString(name) > models.MaxFileTagLength {
+			return nil, fmt.Errorf("标签不能超过 %d 个字符: %s", models.MaxFileTagLength, name)
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// fileTagIDs 提取标签ID
+func fileTagIDs(tags []models.FileTag) []uint {
+	ids := make([]uint, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.ID
+	}
+	return ids
+}
+
+// tagsOrEmpty 没有标签时返回空切片（JSON 输出 [] 而不是 null）
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
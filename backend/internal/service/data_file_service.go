@@ -1,10 +1,14 @@
 package service
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"mime/multipart"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"gen-go/internal/dto"
 	"gen-go/internal/models"
@@ -14,49 +18,277 @@ import (
 
 // DataFileService 数据文件服务
 type DataFileService struct {
-	fileRepo *repository.DataFileRepository
+	fileRepo          *repository.DataFileRepository
+	tagRepo           *repository.FileTagRepository
+	taskRepo          *repository.TaskRepository
+	generatedDataRepo *repository.GeneratedDataRepository
+	validationService *FileValidationService
+	versionService    *FileVersionService
+	virusScanService  *VirusScanService
 }
 
 // NewDataFileService 创建数据文件服务
-func NewDataFileService(fileRepo *repository.DataFileRepository) *DataFileService {
+func NewDataFileService(
+	fileRepo *repository.DataFileRepository,
+	tagRepo *repository.FileTagRepository,
+	taskRepo *repository.TaskRepository,
+	generatedDataRepo *repository.GeneratedDataRepository,
+	validationService *FileValidationService,
+	versionService *FileVersionService,
+	virusScanService *VirusScanService,
+) *DataFileService {
 	return &DataFileService{
-		fileRepo: fileRepo,
+		fileRepo:          fileRepo,
+		tagRepo:           tagRepo,
+		taskRepo:          taskRepo,
+		generatedDataRepo: generatedDataRepo,
+		validationService: validationService,
+		versionService:    versionService,
+		virusScanService:  virusScanService,
 	}
 }
 
-// UploadFile 上传文件
-func (s *DataFileService) UploadFile(userID uint, header *multipart.FileHeader, content []byte) (*models.DataFile, error) {
+// UploadParseOptions 上传文件转换为 JSONL 时的解析选项
+type UploadParseOptions struct {
+	CSV  utils.CSVOptions       // CSV 的分隔符、引号、编码和宽松模式
+	Text utils.TextSplitOptions // 纯文本（.txt）的样本切分方式
+}
+
+// UploadConversion 上传的 CSV/JSON/纯文本转换为 JSONL 的结果
+type UploadConversion struct {
+	Format  string                `json:"format"`        // 原始格式: csv, json, txt
+	Samples int                   `json:"samples"`       // 转换得到的样本数
+	Skipped int                   `json:"skipped"`       // 跳过的记录数（CSV 宽松模式跳过的行，JSON 中没有对话内容的对象）
+	CSV     *utils.CSVParseReport `json:"csv,omitempty"` // CSV 解析报告
+}
+
+// UploadFile 上传文件，opts 为 CSV 和纯文本的解析选项；上传的文件经过转换时同时返回转换结果
+func (s *DataFileService) UploadFile(userID uint, header *multipart.FileHeader, content []byte, validationMode string, opts UploadParseOptions) (*models.DataFile, *dto.FileValidationReport, *UploadConversion, error) {
+	return s.saveUploadedContent(userID, header.Filename, content, validationMode, opts)
+}
+
+// SaveUploadedContent 保存上传的文件内容（普通上传和分片上传共用）
+// validationMode 为 none 时不校验；reject 模式下存在无效行会返回 ErrValidationRejected 及校验报告
+// 开启病毒扫描时先扫描原始内容，检出病毒返回 ErrUploadInfected
+func (s *DataFileService) SaveUploadedContent(userID uint, filename string, content []byte, validationMode string) (*models.DataFile, *dto.FileValidationReport, error) {
+	file, report, _, err := s.saveUploadedContent(userID, filename, content, validationMode, UploadParseOptions{})
+	return file, report, err
+}
+
+// SaveStoredUpload 保存已落盘的 JSONL 文件（分片上传合并后的文件）
+// 病毒扫描和结构校验都流式读取 path，通过后文件移入文件存储，数据库只保存存储路径，内容不会整体读入内存；
+// quarantine 模式下有效行和无效行分别写成新的存储文件；reject 模式下存在无效行时返回 ErrValidationRejected，path 保持不变
+func (s *DataFileService) SaveStoredUpload(userID uint, filename, path, validationMode string) (*models.DataFile, *dto.FileValidationReport, error) {
+	file := &models.DataFile{
+		Filename:    filename,
+		UserID:      userID,
+		ContentType: "application/x-jsonlines",
+	}
+	if s.virusScanService.Enabled() {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("读取文件失败: %w", err)
+		}
+		err = s.virusScanService.ScanReader(file, f)
+		f.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if validationMode == "" {
+		validationMode = s.validationService.DefaultMode()
+	}
+	if !IsValidMode(validationMode) {
+		return nil, nil, fmt.Errorf("不支持的校验模式: %s", validationMode)
+	}
+
+	var result *utils.SchemaValidationResult
+	var err error
+	storePath, quarantinePath := path, ""
+	switch validationMode {
+	case ValidationModeNone:
+	case ValidationModeQuarantine:
+		validPath, invalidPath := path+".valid", path+".invalid"
+		defer os.Remove(validPath)
+		defer os.Remove(invalidPath)
+		result, err = s.validationService.SplitFile(path, validPath, invalidPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		if result.Report.InvalidLines > 0 {
+			// 只保留有效行，无效行另存为隔离文件
+			storePath, quarantinePath = validPath, invalidPath
+		}
+	default:
+		result, err = s.validationService.CheckFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if result.Report.InvalidLines > 0 && validationMode == ValidationModeReject {
+			return nil, BuildReport(validationMode, result), ErrValidationRejected
+		}
+	}
+
+	if err := createStoredFile(s.fileRepo, file, storePath); err != nil {
+		return nil, nil, fmt.Errorf("保存文件失败: %w", err)
+	}
+	if err := s.versionService.Record(file, userID, models.FileVersionActionUpload, nil); err != nil {
+		return nil, nil, err
+	}
+
+	if result == nil {
+		return file, nil, nil
+	}
+	report, err := s.validationService.RecordStored(file, validationMode, result, quarantinePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, report, nil
+}
+
+// createStoredFile 将本地文件移入文件存储并创建文件记录（FileSize 取本地文件大小）
+func createStoredFile(fileRepo *repository.DataFileRepository, file *models.DataFile, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	storagePath, err := models.MoveToFileStore(path, ".jsonl")
+	if err != nil {
+		return err
+	}
+	file.StoragePath = storagePath
+	file.FileSize = int(info.Size())
+	if err := fileRepo.Create(file); err != nil {
+		models.RemoveFromFileStore(storagePath)
+		return err
+	}
+	return nil
+}
+
+// saveUploadedContent 保存上传的文件内容，CSV 和纯文本按 opts 解析，JSON 数组展开为 JSONL
+func (s *DataFileService) saveUploadedContent(userID uint, filename string, content []byte, validationMode string, opts UploadParseOptions) (*models.DataFile, *dto.FileValidationReport, *UploadConversion, error) {
+	file := &models.DataFile{
+		Filename: filename,
+		UserID:   userID,
+	}
+	if err := s.virusScanService.Scan(file, content); err != nil {
+		return nil, nil, nil, err
+	}
+
 	// 检测内容类型
 	contentType := utils.DetectContentType(content)
 
 	// 如果是CSV,转换为JSONL
 	var finalContent []byte
+	var conversion *UploadConversion
 	var err error
 
-	if strings.Contains(contentType, "csv") || strings.HasSuffix(header.Filename, ".csv") {
+	lowerName := strings.ToLower(filename)
+	if strings.HasSuffix(lowerName, ".xlsx") {
+		// Excel 按 meta/Human/Assistant 表头布局转换（与 CSV 一致）
+		finalContent, err = utils.ConvertXLSXToJSONL(content)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("Excel转JSONL失败: %w", err)
+		}
+		contentType = "application/x-jsonlines"
+	} else if strings.HasSuffix(lowerName, ".parquet") {
+		finalContent, err = utils.ConvertParquetToJSONL(content)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("Parquet转JSONL失败: %w", err)
+		}
+		contentType = "application/x-jsonlines"
+	} else if strings.HasSuffix(lowerName, ".json") {
+		// JSON 数组按常见字段名逐条转换为 meta/turns 结构
+		var samples, skipped int
+		finalContent, samples, skipped, err = utils.ConvertJSONArrayToJSONL(content)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("JSON转JSONL失败: %w", err)
+		}
+		conversion = &UploadConversion{Format: "json", Samples: samples, Skipped: skipped}
+		contentType = "application/x-jsonlines"
+	} else if strings.HasSuffix(lowerName, ".txt") {
+		// 纯文本按段落（或行、分隔符）切分，每段一条样本
+		var samples int
+		finalContent, samples, err = utils.ConvertTextToJSONL(content, opts.Text)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("文本转JSONL失败: %w", err)
+		}
+		conversion = &UploadConversion{Format: "txt", Samples: samples}
+		contentType = "application/x-jsonlines"
+	} else if strings.Contains(contentType, "csv") || strings.HasSuffix(filename, ".csv") {
 		// 使用专门的 CSV 到 JSONL 转换方法（支持 meta、Human、Assistant 格式）
-		finalContent, err = utils.ConvertCSVToJSONL(content)
+		var csvReport *utils.CSVParseReport
+		finalContent, csvReport, err = utils.ConvertCSVToJSONLWithOptions(content, opts.CSV)
 		if err != nil {
-			return nil, fmt.Errorf("CSV转JSONL失败: %w", err)
+			return nil, nil, nil, fmt.Errorf("CSV转JSONL失败: %w", err)
+		}
+		conversion = &UploadConversion{
+			Format:  "csv",
+			Samples: utils.CountJSONLLines(finalContent),
+			Skipped: csvReport.SkippedRows,
+			CSV:     csvReport,
 		}
 		contentType = "application/x-jsonlines"
 	} else {
 		finalContent = content
 	}
 
-	file := &models.DataFile{
-		Filename:    header.Filename,
-		FileContent: finalContent,
-		FileSize:    len(finalContent),
-		ContentType: contentType,
-		UserID:      userID,
+	if validationMode == "" {
+		validationMode = s.validationService.DefaultMode()
+	}
+	if !IsValidMode(validationMode) {
+		return nil, nil, nil, fmt.Errorf("不支持的校验模式: %s", validationMode)
 	}
 
+	var result *utils.SchemaValidationResult
+	if validationMode != ValidationModeNone {
+		result, err = s.validationService.Check(finalContent, validationMode)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if result.Report.InvalidLines > 0 {
+			switch validationMode {
+			case ValidationModeReject:
+				return nil, BuildReport(validationMode, result), conversion, ErrValidationRejected
+			case ValidationModeQuarantine:
+				// 只保留有效行，无效行另存为隔离文件
+				finalContent = joinJSONLLines(result.ValidLines)
+			}
+		}
+	}
+
+	file.FileContent = finalContent
+	file.FileSize = len(finalContent)
+	file.ContentType = contentType
+
 	if err := s.fileRepo.Create(file); err != nil {
-		return nil, fmt.Errorf("保存文件失败: %w", err)
+		return nil, nil, nil, fmt.Errorf("保存文件失败: %w", err)
+	}
+	if err := s.versionService.Record(file, userID, models.FileVersionActionUpload, nil); err != nil {
+		return nil, nil, nil, err
 	}
 
-	return file, nil
+	if result == nil {
+		return file, nil, conversion, nil
+	}
+
+	report, err := s.validationService.Record(file, validationMode, result)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return file, report, conversion, nil
+}
+
+// ValidateFile 校验已上传文件的 meta/turns 结构
+func (s *DataFileService) ValidateFile(fileID uint, userID uint, mode string) (*dto.FileValidationReport, error) {
+	return s.validationService.ValidateFile(fileID, userID, mode)
+}
+
+// GetFileValidation 获取文件最近一次校验报告
+func (s *DataFileService) GetFileValidation(fileID uint, userID uint) (*dto.FileValidationReport, error) {
+	return s.validationService.GetLatestReport(fileID, userID)
 }
 
 // GetFile 获取文件
@@ -64,228 +296,307 @@ func (s *DataFileService) GetFile(fileID uint, userID uint) (*models.DataFile, e
 	return s.fileRepo.GetByIDAndUserID(fileID, userID)
 }
 
-// ListFiles 获取文件列表
-func (s *DataFileService) ListFiles(userID uint, page, perPage int) (*dto.PaginatedResponse, error) {
+// ListFiles 获取文件列表（含所在工作区共享的文件，可按工作区、文件夹和标签过滤）
+func (s *DataFileService) ListFiles(userID uint, filter *repository.DataFileFilter, page, perPage int) (*dto.PaginatedResponse, error) {
 	offset := (page - 1) * perPage
-	files, total, err := s.fileRepo.ListByUserID(userID, offset, perPage)
+	files, total, err := s.fileRepo.ListByUserID(userID, filter, offset, perPage)
 	if err != nil {
 		return nil, err
 	}
 
-	fileResponses := make([]dto.DataFileResponse, len(files))
+	ids := make([]uint, len(files))
 	for i, file := range files {
-		fileResponses[i] = dto.DataFileResponse{
-			ID:          file.ID,
-			Filename:    file.Filename,
-			FileSize:    file.FileSize,
-			ContentType: file.ContentType,
-			UserID:      file.UserID,
-			CreatedAt:   file.CreatedAt.Format("2006-01-02 15:04:05"),
-			UpdatedAt:   file.UpdatedAt.Format("2006-01-02 15:04:05"),
-		}
+		ids[i] = file.ID
+	}
+	tags, err := s.tagRepo.ListNamesByFileIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	fileResponses := make([]dto.DataFileResponse, len(files))
+	for i := range files {
+		fileResponses[i] = s.NewFileResponse(&files[i], tags[files[i].ID])
 	}
 
 	return &dto.PaginatedResponse{
-		Items: fileResponses,
-		Total: total,
-		Page:  page,
+		Items:   fileResponses,
+		Total:   total,
+		Page:    page,
 		PerPage: perPage,
 	}, nil
 }
 
+// NewFileResponse 构造文件响应（不含文件内容）
+func (s *DataFileService) NewFileResponse(file *models.DataFile, tags []string) dto.DataFileResponse {
+	return dto.DataFileResponse{
+		ID:            file.ID,
+		Filename:      file.Filename,
+		Description:   file.Description,
+		DisplayPath:   s.GetFileDisplayPath(file.ID, file.Filename),
+		FileSize:      file.FileSize,
+		ContentType:   file.ContentType,
+		UserID:        file.UserID,
+		WorkspaceID:   file.WorkspaceID,
+		ConvertedFrom: file.ConvertedFromID,
+		FolderPath:    file.FolderPath,
+		Tags:          tagsOrEmpty(tags),
+		CreatedAt:     dto.FormatTime(file.CreatedAt),
+		UpdatedAt:     dto.FormatTime(file.UpdatedAt),
+	}
+}
+
+// ListFileInfos 获取用户文件的元信息（不含文件内容），用于审计快照
+func (s *DataFileService) ListFileInfos(userID uint, ids []uint) ([]models.DataFile, error) {
+	return s.fileRepo.ListInfoByIDsAndUserID(ids, userID)
+}
+
 // DeleteFile 删除文件
 func (s *DataFileService) DeleteFile(fileID uint, userID uint) error {
-	file, err := s.fileRepo.GetByIDAndUserID(fileID, userID)
+	file, err := s.fileRepo.GetEditableByIDAndUserID(fileID, userID)
 	if err != nil {
 		return fmt.Errorf("文件不存在或无权访问")
 	}
 
+	s.validationService.DeleteRecords(file.ID)
 	return s.fileRepo.Delete(file.ID)
 }
 
 // BatchDeleteFiles 批量删除文件
 func (s *DataFileService) BatchDeleteFiles(userID uint, ids []uint) error {
 	for _, id := range ids {
-		file, err := s.fileRepo.GetByIDAndUserID(id, userID)
+		file, err := s.fileRepo.GetEditableByIDAndUserID(id, userID)
 		if err != nil {
 			continue // 跳过不存在的文件
 		}
+		s.validationService.DeleteRecords(file.ID)
 		s.fileRepo.Delete(file.ID)
 	}
 	return nil
 }
 
-// GetFileContent 获取文件内容
-func (s *DataFileService) GetFileContent(fileID uint, userID uint) (*dto.DataFileContentResponse, error) {
+// GetFileContent 获取文件内容（支持分页和关键字过滤，只解码请求的窗口）
+func (s *DataFileService) GetFileContent(fileID uint, userID uint, query dto.FileContentQuery) (*dto.DataFileContentResponse, error) {
 	file, err := s.fileRepo.GetByIDAndUserID(fileID, userID)
 	if err != nil {
 		return nil, fmt.Errorf("文件不存在或无权访问")
 	}
 
-	data, err := utils.ParseJSONL(file.FileContent)
+	items, total, err := utils.ParseJSONLWindow(file.FileContent, query.Offset(), query.PerPage, query.Query)
 	if err != nil {
 		return nil, fmt.Errorf("解析文件内容失败: %w", err)
 	}
 
+	data := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		data[i] = item.Data
+	}
+
 	return &dto.DataFileContentResponse{
 		ID:       file.ID,
 		Filename: file.Filename,
 		Content:  data,
-		Total:    len(data),
+		Total:    total,
+		Page:     query.Page,
+		PerPage:  query.PerPage,
 	}, nil
 }
 
-// GetFileContentEditable 获取文件内容（带索引，用于编辑）
-func (s *DataFileService) GetFileContentEditable(fileID uint, userID uint) (*dto.DataFileContentEditableResponse, error) {
+// GetFileContentEditable 获取文件内容（带索引，用于编辑，支持分页和关键字过滤）
+func (s *DataFileService) GetFileContentEditable(fileID uint, userID uint, query dto.FileContentQuery) (*dto.DataFileContentEditableResponse, error) {
 	file, err := s.fileRepo.GetByIDAndUserID(fileID, userID)
 	if err != nil {
 		return nil, fmt.Errorf("文件不存在或无权访问")
 	}
 
-	data, err := utils.ParseJSONL(file.FileContent)
+	data, total, err := utils.ParseJSONLWindow(file.FileContent, query.Offset(), query.PerPage, query.Query)
 	if err != nil {
 		return nil, fmt.Errorf("解析文件内容失败: %w", err)
 	}
 
-	// 构建带索引的数据项
+	// 构建带索引的数据项（索引为该行在整个文件中的位置，便于按索引编辑）
 	items := make([]dto.DataFileItem, len(data))
 	for i, d := range data {
 		items[i] = dto.DataFileItem{
-			Index: i,
-			Data:  d,
+			Index: d.Index,
+			Data:  d.Data,
 		}
 	}
 
 	return &dto.DataFileContentEditableResponse{
 		FileID:     file.ID,
 		Filename:   file.Filename,
-		TotalLines: len(data),
+		TotalLines: total,
 		Items:      items,
+		Page:       query.Page,
+		PerPage:    query.PerPage,
 	}, nil
 }
 
-// UpdateFileContent 更新文件内容中的某一项
-func (s *DataFileService) UpdateFileContent(fileID uint, userID uint, itemIndex int, content map[string]interface{}) error {
+// SampleFile 抽样预览文件内容（head、random 或按 meta 分层抽样），只解码抽中的行
+func (s *DataFileService) SampleFile(fileID uint, userID uint, query dto.FileSampleQuery) (*dto.FileSampleResponse, error) {
 	file, err := s.fileRepo.GetByIDAndUserID(fileID, userID)
 	if err != nil {
-		return fmt.Errorf("文件不存在或无权访问")
+		return nil, fmt.Errorf("文件不存在或无权访问")
+	}
+
+	opts := utils.SampleOptions{Strategy: query.Strategy, N: query.N, Field: query.Field}
+	var seed *int64
+	if query.Strategy != utils.SampleStrategyHead {
+		opts.Seed = time.Now().UnixNano()
+		if query.Seed != nil {
+			opts.Seed = *query.Seed
+		}
+		seed = &opts.Seed
 	}
 
-	data, err := utils.ParseJSONL(file.FileContent)
+	result, err := utils.SampleJSONL(file.FileContent, opts)
 	if err != nil {
-		return fmt.Errorf("解析文件内容失败: %w", err)
+		return nil, fmt.Errorf("抽样失败: %w", err)
 	}
 
-	if itemIndex < 0 || itemIndex >= len(data) {
-		return fmt.Errorf("索引越界")
+	items := make([]dto.DataFileItem, len(result.Items))
+	for i, item := range result.Items {
+		items[i] = dto.DataFileItem{Index: item.Index, Data: item.Data}
+	}
+	var strata []dto.FileSampleStratum
+	for _, stratum := range result.Strata {
+		strata = append(strata, dto.FileSampleStratum{Key: stratum.Key, Total: stratum.Total, Sampled: stratum.Sampled})
 	}
 
-	data[itemIndex] = content
+	return &dto.FileSampleResponse{
+		FileID:   file.ID,
+		Filename: file.Filename,
+		Strategy: query.Strategy,
+		Seed:     seed,
+		Total:    result.Total,
+		Items:    items,
+		Strata:   strata,
+	}, nil
+}
 
-	// 转换回JSONL
-	newContent, err := utils.ConvertToJSONL(data)
+// UpdateFileContent 更新文件内容中的某一项（流式改写，其他行原样保留）
+func (s *DataFileService) UpdateFileContent(fileID uint, userID uint, itemIndex int, content map[string]interface{}) error {
+	file, err := s.fileRepo.GetEditableByIDAndUserID(fileID, userID)
+	if err != nil {
+		return fmt.Errorf("文件不存在或无权访问")
+	}
+
+	encoded, err := json.Marshal(content)
 	if err != nil {
 		return fmt.Errorf("序列化内容失败: %w", err)
 	}
 
-	file.FileContent = newContent
-	return s.fileRepo.Update(file)
+	newContent, total, err := utils.RewriteJSONLLines(file.FileContent, func(index int, line []byte) ([][]byte, error) {
+		if index == itemIndex {
+			return [][]byte{encoded}, nil
+		}
+		return [][]byte{line}, nil
+	})
+	if err != nil {
+		return fmt.Errorf("解析文件内容失败: %w", err)
+	}
+
+	if itemIndex < 0 || itemIndex >= total {
+		return fmt.Errorf("索引越界")
+	}
+
+	return s.saveContent(file, userID, models.FileVersionActionUpdate, newContent)
 }
 
-// AddFileContent 添加新内容到文件
+// AddFileContent 添加新内容到文件（插入到 index 之前，index 越界时追加到末尾）
 func (s *DataFileService) AddFileContent(fileID uint, userID uint, content map[string]interface{}, index int) error {
-	file, err := s.fileRepo.GetByIDAndUserID(fileID, userID)
+	file, err := s.fileRepo.GetEditableByIDAndUserID(fileID, userID)
 	if err != nil {
 		return fmt.Errorf("文件不存在或无权访问")
 	}
 
-	data, err := utils.ParseJSONL(file.FileContent)
+	encoded, err := json.Marshal(content)
 	if err != nil {
-		return fmt.Errorf("解析文件内容失败: %w", err)
-	}
-
-	if index < 0 || index >= len(data) {
-		// 添加到末尾
-		data = append(data, content)
-	} else {
-		// 插入到指定位置
-		data = append(data[:index+1], data[index:]...)
-		data[index] = content
+		return fmt.Errorf("序列化内容失败: %w", err)
 	}
 
-	// 转换回JSONL
-	newContent, err := utils.ConvertToJSONL(data)
+	inserted := false
+	newContent, _, err := utils.RewriteJSONLLines(file.FileContent, func(i int, line []byte) ([][]byte, error) {
+		if i == index {
+			// 插入到指定位置
+			inserted = true
+			return [][]byte{encoded, line}, nil
+		}
+		return [][]byte{line}, nil
+	})
 	if err != nil {
-		return fmt.Errorf("序列化内容失败: %w", err)
+		return fmt.Errorf("解析文件内容失败: %w", err)
+	}
+	if !inserted {
+		// 添加到末尾
+		newContent = append(append(newContent, encoded...), '\n')
 	}
 
-	file.FileContent = newContent
-	return s.fileRepo.Update(file)
+	return s.saveContent(file, userID, models.FileVersionActionAdd, newContent)
 }
 
 // BatchDeleteContent 批量删除文件内容
 func (s *DataFileService) BatchDeleteContent(fileID uint, userID uint, indices []int) (int, error) {
-	file, err := s.fileRepo.GetByIDAndUserID(fileID, userID)
+	file, err := s.fileRepo.GetEditableByIDAndUserID(fileID, userID)
 	if err != nil {
 		return 0, fmt.Errorf("文件不存在或无权访问")
 	}
 
-	data, err := utils.ParseJSONL(file.FileContent)
-	if err != nil {
-		return 0, fmt.Errorf("解析文件内容失败: %w", err)
-	}
-
 	// 创建索引map用于快速查找
 	indexMap := make(map[int]bool)
 	for _, idx := range indices {
 		indexMap[idx] = true
 	}
 
-	// 过滤掉要删除的项
-	newData := make([]map[string]interface{}, 0, len(data))
-	for i, item := range data {
-		if !indexMap[i] {
-			newData = append(newData, item)
+	// 过滤掉要删除的行，并统计实际删除的数量
+	deletedCount := 0
+	newContent, _, err := utils.RewriteJSONLLines(file.FileContent, func(index int, line []byte) ([][]byte, error) {
+		if indexMap[index] {
+			deletedCount++
+			return nil, nil
 		}
-	}
-
-	// 计算实际删除的数量（原始长度 - 新长度）
-	deletedCount := len(data) - len(newData)
-
-	// 转换回JSONL
-	newContent, err := utils.ConvertToJSONL(newData)
+		return [][]byte{line}, nil
+	})
 	if err != nil {
-		return 0, fmt.Errorf("序列化内容失败: %w", err)
+		return 0, fmt.Errorf("解析文件内容失败: %w", err)
 	}
 
-	file.FileContent = newContent
-	if err := s.fileRepo.Update(file); err != nil {
+	if err := s.saveContent(file, userID, models.FileVersionActionDelete, newContent); err != nil {
 		return 0, err
 	}
 
 	return deletedCount, nil
 }
 
+// saveContent 保存文件的新内容，并记录为新版本
+func (s *DataFileService) saveContent(file *models.DataFile, userID uint, action string, content []byte) error {
+	previous := file.FileContent
+	file.FileContent = content
+	file.FileSize = len(content)
+	file.StoragePath = "" // 新内容写回数据库，原存储文件仍由历史版本引用
+	if err := s.fileRepo.Update(file); err != nil {
+		return err
+	}
+	return s.versionService.Record(file, userID, action, previous)
+}
+
 // DownloadFile 下载文件
 func (s *DataFileService) DownloadFile(fileID uint, userID uint) (*models.DataFile, error) {
 	return s.fileRepo.GetByIDAndUserID(fileID, userID)
 }
 
-// DownloadFileAsCSV 下载文件为CSV格式
-func (s *DataFileService) DownloadFileAsCSV(fileID uint, userID uint) ([]byte, string, error) {
+// DownloadFileAsCSV 下载文件为CSV格式（同时返回数据行数，用于导出审计）
+func (s *DataFileService) DownloadFileAsCSV(fileID uint, userID uint) ([]byte, string, int, error) {
 	file, err := s.fileRepo.GetByIDAndUserID(fileID, userID)
 	if err != nil {
-		return nil, "", fmt.Errorf("文件不存在或无权访问")
+		return nil, "", 0, fmt.Errorf("文件不存在或无权访问")
 	}
 
-	data, err := utils.ParseJSONL(file.FileContent)
+	// 逐行解码写出，不把全部数据项解码到内存
+	var csvContent bytes.Buffer
+	rows, err := utils.WriteJSONLAsCSV(&csvContent, file.FileContent)
 	if err != nil {
-		return nil, "", fmt.Errorf("解析文件内容失败: %w", err)
-	}
-
-	csvContent, err := utils.ConvertToCSV(data)
-	if err != nil {
-		return nil, "", fmt.Errorf("转换为CSV失败: %w", err)
+		return nil, "", 0, fmt.Errorf("转换为CSV失败: %w", err)
 	}
 
 	// 生成文件名
@@ -294,7 +605,131 @@ func (s *DataFileService) DownloadFileAsCSV(fileID uint, userID uint) ([]byte, s
 		csvFilename = file.Filename + ".csv"
 	}
 
-	return csvContent, csvFilename, nil
+	return csvContent.Bytes(), csvFilename, rows, nil
+}
+
+// DownloadFileAsXLSX 下载文件为Excel格式（meta/Human/Assistant 表头布局）
+func (s *DataFileService) DownloadFileAsXLSX(fileID uint, userID uint) ([]byte, string, int, error) {
+	file, err := s.fileRepo.GetByIDAndUserID(fileID, userID)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("文件不存在或无权访问")
+	}
+
+	content, err := utils.ConvertJSONLToXLSX(file.FileContent)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("转换为Excel失败: %w", err)
+	}
+
+	filename := strings.TrimSuffix(file.Filename, ".jsonl") + ".xlsx"
+	return content, filename, utils.CountJSONLLines(file.FileContent), nil
+}
+
+// CheckFilesOwned 检查ID列表中的文件是否都存在且属于该用户
+func (s *DataFileService) CheckFilesOwned(userID uint, ids []uint) error {
+	unique := make(map[uint]struct{}, len(ids))
+	for _, id := range ids {
+		unique[id] = struct{}{}
+	}
+	count, err := s.fileRepo.CountByIDsAndUserID(ids, userID)
+	if err != nil {
+		return fmt.Errorf("查询文件失败: %w", err)
+	}
+	if count != int64(len(unique)) {
+		return fmt.Errorf("部分文件不存在或无权访问")
+	}
+	return nil
+}
+
+// IsDataFileExportFormat 判断是否为数据文件支持的导出格式
+func IsDataFileExportFormat(format string) bool {
+	return format == "" || format == "raw" || format == "csv" || format == utils.TableFormatXLSX || utils.IsTrainerFormat(format)
+}
+
+// ExportFile 按指定格式导出文件：raw（原始内容）、csv、xlsx 或训练框架格式
+// 返回文件内容、文件名和数据行数
+func (s *DataFileService) ExportFile(fileID uint, userID uint, format string) ([]byte, string, int, error) {
+	switch {
+	case format == "" || format == "raw":
+		file, err := s.fileRepo.GetByIDAndUserID(fileID, userID)
+		if err != nil {
+			return nil, "", 0, fmt.Errorf("文件不存在或无权访问")
+		}
+		return file.FileContent, file.Filename, utils.CountJSONLLines(file.FileContent), nil
+	case format == "csv":
+		return s.DownloadFileAsCSV(fileID, userID)
+	case format == utils.TableFormatXLSX:
+		return s.DownloadFileAsXLSX(fileID, userID)
+	case utils.IsTrainerFormat(format):
+		return s.DownloadFileAsTrainerFormat(fileID, userID, format)
+	}
+	return nil, "", 0, fmt.Errorf("不支持的导出格式: %s", format)
+}
+
+// DownloadFileAsTrainerFormat 下载文件为训练框架格式（sharegpt/openai_chat/alpaca/parquet）
+func (s *DataFileService) DownloadFileAsTrainerFormat(fileID uint, userID uint, format string) ([]byte, string, int, error) {
+	file, err := s.fileRepo.GetByIDAndUserID(fileID, userID)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("文件不存在或无权访问")
+	}
+
+	conversion, err := utils.ConvertJSONLToTrainerFormat(file.FileContent, format)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	if conversion.Converted == 0 && conversion.Skipped > 0 {
+		return nil, "", 0, fmt.Errorf("文件中没有符合 meta/turns 结构的数据（第 %d 行: %s）", conversion.Issues[0].Line, conversion.Issues[0].Message)
+	}
+
+	filename := strings.TrimSuffix(file.Filename, ".jsonl") + "_" + format + conversion.Ext
+	return conversion.Content, filename, conversion.Converted, nil
+}
+
+// ListFileTasks 获取使用该文件作为输入的任务列表（含产出数据条数）
+func (s *DataFileService) ListFileTasks(fileID uint, userID uint) (*dto.FileTaskListResponse, error) {
+	file, err := s.fileRepo.GetByIDAndUserID(fileID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("文件不存在或无权访问")
+	}
+
+	tasks, err := s.taskRepo.ListByUserIDAndFileID(userID, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("查询任务失败: %w", err)
+	}
+
+	taskIDs := make([]string, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.TaskID
+	}
+	counts, err := s.generatedDataRepo.CountByTaskIDs(taskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("统计生成数据失败: %w", err)
+	}
+
+	items := make([]dto.FileTaskInfo, len(tasks))
+	for i, task := range tasks {
+		info := dto.FileTaskInfo{
+			TaskID:         task.TaskID,
+			Status:         string(task.Status),
+			DataCount:      counts[task.TaskID].DataCount,
+			ConfirmedCount: counts[task.TaskID].ConfirmedCount,
+			StartedAt:      dto.FormatTime(task.StartedAt),
+		}
+		if taskType, ok := task.Params["task_type"].(string); ok {
+			info.TaskType = taskType
+		}
+		if modelPath, ok := task.Params["model_path"].(string); ok {
+			info.ModelPath = modelPath
+		}
+		info.FinishedAt = dto.FormatTimePtr(task.FinishedAt)
+		items[i] = info
+	}
+
+	return &dto.FileTaskListResponse{
+		FileID:   file.ID,
+		Filename: file.Filename,
+		Tasks:    items,
+		Total:    len(items),
+	}, nil
 }
 
 // GetFileDisplayPath 获取文件显示路径(db://file_id/filename)
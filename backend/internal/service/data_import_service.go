@@ -0,0 +1,279 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/utils"
+)
+
+// importSource 一个待下载的源数据文件
+type importSource struct {
+	url    string
+	format string
+}
+
+// DataImportService 从远程 URL 或 Hugging Face 数据集导入数据文件
+// 源数据流式下载到临时文件（不经过浏览器），按字段映射逐条转换为 JSONL 后注册为数据文件
+type DataImportService struct {
+	dataFileService *DataFileService
+	cfg             *config.Config
+	client          *http.Client
+}
+
+// NewDataImportService 创建数据文件导入服务
+func NewDataImportService(dataFileService *DataFileService, cfg *config.Config) *DataImportService {
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !cfg.Import.AllowPrivateNetworks {
+		// 在解析出实际地址后检查，重定向和 DNS 重绑定同样会被拦截（不走代理，否则检查的是代理地址）
+		dialer.Control = rejectPrivateAddress
+		transport.Proxy = nil
+	}
+	transport.DialContext = dialer.DialContext
+
+	return &DataImportService{
+		dataFileService: dataFileService,
+		cfg:             cfg,
+		client:          &http.Client{Transport: transport},
+	}
+}
+
+// rejectPrivateAddress 拒绝连接本机、内网和链路本地地址（作为 net.Dialer.Control，导入和 Webhook 投递共用）
+func rejectPrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if isPrivateIP(net.ParseIP(host)) {
+		return fmt.Errorf("不允许连接内网地址: %s", host)
+	}
+	return nil
+}
+
+// isPrivateIP 判断是否为本机、内网、链路本地或未指定地址（无法解析的地址也视为内网）
+func isPrivateIP(ip net.IP) bool {
+	return ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+}
+
+// Import 下载并转换源数据，注册为当前用户的数据文件
+func (s *DataImportService) Import(userID uint, req *dto.ImportDataFileRequest) (*models.DataFile, *dto.FileValidationReport, *dto.ImportDataFileResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.Current().Import.GetTimeout())
+	defer cancel()
+
+	sources, filename, err := s.resolveSources(ctx, req)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if req.Filename != "" {
+		filename = req.Filename
+	}
+	// 内容已转换为 JSONL，文件名统一使用 .jsonl 后缀，避免保存时再按 CSV/Excel 转换
+	filename = strings.TrimSuffix(filename, path.Ext(filename)) + ".jsonl"
+
+	maxRows := s.cfg.Current().Import.MaxRows
+	if req.MaxRows > 0 && (maxRows == 0 || req.MaxRows < maxRows) {
+		maxRows = req.MaxRows
+	}
+	mapping := columnMapping(req.Mapping)
+
+	result := &dto.ImportDataFileResult{}
+	var buf bytes.Buffer
+	convert := func(record map[string]interface{}) error {
+		if maxRows > 0 && result.Imported >= maxRows {
+			result.Truncated = true
+			return utils.ErrStopRecords
+		}
+		data, ok := utils.ConvertRecordToJSONL(record, mapping)
+		if !ok {
+			result.Skipped++
+			return nil
+		}
+		line, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+		result.Imported++
+		return nil
+	}
+
+	for _, source := range sources {
+		if result.Truncated {
+			break
+		}
+		if err := s.readSource(ctx, source, result, convert); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if result.Imported == 0 {
+		return nil, nil, nil, fmt.Errorf("源数据中没有可导入的对话记录，请检查字段映射")
+	}
+
+	file, report, err := s.dataFileService.SaveUploadedContent(userID, filename, buf.Bytes(), req.ValidationMode)
+	if err != nil {
+		return nil, report, nil, err
+	}
+	return file, report, result, nil
+}
+
+// columnMapping 将请求中的字段映射转换为转换器使用的映射（为空时自动识别）
+func columnMapping(m *dto.ImportColumnMapping) utils.ColumnMapping {
+	if m == nil {
+		return utils.ColumnMapping{}
+	}
+	return utils.ColumnMapping{
+		Meta:       m.Meta,
+		MetaFields: m.MetaFields,
+		Human:      m.Human,
+		Assistant:  m.Assistant,
+		Turns:      m.Turns,
+		Messages:   m.Messages,
+	}
+}
+
+// resolveSources 解析导入请求对应的源数据文件和默认文件名
+func (s *DataImportService) resolveSources(ctx context.Context, req *dto.ImportDataFileRequest) ([]importSource, string, error) {
+	if req.Source == dto.ImportSourceHuggingFace {
+		return s.huggingFaceSources(ctx, req)
+	}
+
+	parsed, err := url.Parse(req.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, "", fmt.Errorf("请提供有效的 HTTP(S) 地址")
+	}
+	format := req.Format
+	if format == "" {
+		format = utils.DetectRecordFormat(parsed.Path)
+	}
+	if format == "" {
+		format = utils.RecordFormatJSONL
+	}
+
+	filename := path.Base(parsed.Path)
+	if filename == "" || filename == "." || filename == "/" {
+		filename = parsed.Host
+	}
+	return []importSource{{url: parsed.String(), format: format}}, filename, nil
+}
+
+// huggingFaceSources 通过 Hub 的 Parquet 接口获取数据集划分的全部 Parquet 分片
+func (s *DataImportService) huggingFaceSources(ctx context.Context, req *dto.ImportDataFileRequest) ([]importSource, string, error) {
+	dataset := strings.Trim(req.Dataset, "/")
+	if dataset == "" || strings.Contains(dataset, "..") {
+		return nil, "", fmt.Errorf("请提供有效的 Hugging Face 数据集路径")
+	}
+	subset := req.Config
+	if subset == "" {
+		subset = "default"
+	}
+	split := req.Split
+	if split == "" {
+		split = "train"
+	}
+
+	endpoint := strings.TrimRight(s.cfg.Current().Import.HuggingFaceEndpoint, "/")
+	apiURL := fmt.Sprintf("%s/api/datasets/%s/parquet/%s/%s", endpoint, dataset, url.PathEscape(subset), url.PathEscape(split))
+	resp, err := s.get(ctx, apiURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("获取数据集文件列表失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var urls []string
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&urls); err != nil {
+		return nil, "", fmt.Errorf("解析数据集文件列表失败: %w", err)
+	}
+	if len(urls) == 0 {
+		return nil, "", fmt.Errorf("数据集 %s 没有 %s/%s 划分", dataset, subset, split)
+	}
+
+	sources := make([]importSource, len(urls))
+	for i, fileURL := range urls {
+		sources[i] = importSource{url: fileURL, format: utils.RecordFormatParquet}
+	}
+	filename := strings.ReplaceAll(dataset, "/", "_") + "_" + split
+	if subset != "default" {
+		filename = strings.ReplaceAll(dataset, "/", "_") + "_" + subset + "_" + split
+	}
+	return sources, filename, nil
+}
+
+// readSource 将一个源文件下载到临时文件后逐条读取转换
+func (s *DataImportService) readSource(ctx context.Context, source importSource, result *dto.ImportDataFileResult, fn func(record map[string]interface{}) error) error {
+	if err := os.MkdirAll(s.cfg.Current().Upload.TempDir, 0o755); err != nil {
+		return fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	tmp, err := os.CreateTemp(s.cfg.Current().Upload.TempDir, "import-*")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	resp, err := s.get(ctx, source.url)
+	if err != nil {
+		return fmt.Errorf("下载源数据失败: %w", err)
+	}
+	remaining := s.cfg.Current().Import.GetMaxSizeBytes() - result.DownloadBytes
+	written, err := io.Copy(tmp, io.LimitReader(resp.Body, remaining+1))
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("下载源数据失败: %w", err)
+	}
+	if written > remaining {
+		return fmt.Errorf("源数据超过导入大小上限 %d MB", s.cfg.Current().Import.MaxSizeMB)
+	}
+	result.DownloadBytes += written
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	switch source.format {
+	case utils.RecordFormatCSV:
+		return utils.ReadCSVRecords(tmp, fn)
+	case utils.RecordFormatParquet:
+		return utils.ReadParquetRecords(tmp, written, fn)
+	default:
+		return utils.ReadJSONRecords(tmp, fn)
+	}
+}
+
+// get 发起 GET 请求（Hugging Face 地址附带 Token），非 2xx 响应返回错误
+func (s *DataImportService) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token := s.cfg.Current().Import.HuggingFaceToken; token != "" && strings.HasPrefix(rawURL, strings.TrimRight(s.cfg.Current().Import.HuggingFaceEndpoint, "/")+"/") {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
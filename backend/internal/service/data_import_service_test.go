@@ -0,0 +1,32 @@
+package service
+
+import "testing"
+
+func TestRejectPrivateAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		wantErr bool
+	}{
+		{"127.0.0.1:80", true},
+		{"[::1]:443", true},
+		{"10.0.0.5:8080", true},
+		{"172.16.3.4:80", true},
+		{"192.168.1.1:80", true},
+		{"169.254.169.254:80", true},
+		{"[fe80::1]:80", true},
+		{"[fd00::1]:80", true},
+		{"0.0.0.0:80", true},
+		{"[::]:80", true},
+		{"localhost:80", true},
+		{"no-port", true},
+		{"8.8.8.8:53", false},
+		{"93.184.216.34:443", false},
+		{"[2606:4700:4700::1111]:443", false},
+	}
+	for _, tt := range tests {
+		err := rejectPrivateAddress("tcp", tt.address, nil)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("rejectPrivateAddress(%s) error = %v, wantErr %v", tt.address, err, tt.wantErr)
+		}
+	}
+}
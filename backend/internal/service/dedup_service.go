@@ -0,0 +1,167 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+	"gen-go/internal/utils"
+)
+
+// 去重标记前缀
+const (
+	duplicateOfSourcePrefix = "source:"
+	duplicateOfDataPrefix   = "data:"
+	dedupBatchSize          = 500
+)
+
+// DedupService 生成数据去重服务
+// 将任务生成的数据与输入文件以及彼此比较，重复的数据写入 duplicate_of 标记
+type DedupService struct {
+	generatedDataRepo *repository.GeneratedDataRepository
+	fileRepo          *repository.DataFileRepository
+	jobPool           *JobPool
+}
+
+// DedupResult 去重结果
+type DedupResult struct {
+	Checked            int
+	SourceDuplicates   int
+	InternalDuplicates int
+}
+
+// NewDedupService 创建去重服务
+func NewDedupService(generatedDataRepo *repository.GeneratedDataRepository, fileRepo *repository.DataFileRepository, jobPool *JobPool) *DedupService {
+	return &DedupService{
+		generatedDataRepo: generatedDataRepo,
+		fileRepo:          fileRepo,
+		jobPool:           jobPool,
+	}
+}
+
+// DedupTask 对任务数据去重
+// 与源文件重复的标记为 source:<源文件条目下标>，与同任务中更早的数据重复的标记为 data:<数据ID>
+// 在作业池中执行：源文件逐行流式解析，生成数据分批读取，内存中只保留内容指纹
+func (s *DedupService) DedupTask(taskID string, fileID uint) (*DedupResult, error) {
+	var result *DedupResult
+	err := s.jobPool.Run(JobKindDedup, func() error {
+		var err error
+		result, err = s.dedupTask(taskID, fileID)
+		return err
+	})
+	return result, err
+}
+
+// dedupTask 去重的具体实现
+func (s *DedupService) dedupTask(taskID string, fileID uint) (*DedupResult, error) {
+	sourceKeys, err := s.loadSourceKeys(fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DedupResult{}
+	marks := make(map[uint]string)
+	seen := make(map[string]uint)
+
+	err = s.generatedDataRepo.ScanForDedup(taskID, dedupBatchSize, func(batch []models.GeneratedData) error {
+		for _, data := range batch {
+			result.Checked++
+
+			var content map[string]interface{}
+			if err := json.Unmarshal([]byte(data.DataContent), &content); err != nil {
+				continue
+			}
+			key := conversationKey(content)
+
+			if index, ok := sourceKeys[key]; ok {
+				marks[data.ID] = fmt.Sprintf("%s%d", duplicateOfSourcePrefix, index)
+				result.SourceDuplicates++
+				continue
+			}
+			if firstID, ok := seen[key]; ok {
+				marks[data.ID] = fmt.Sprintf("%s%d", duplicateOfDataPrefix, firstID)
+				result.InternalDuplicates++
+				continue
+			}
+			seen[key] = data.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取生成数据失败: %w", err)
+	}
+
+	if err := s.generatedDataRepo.UpdateDuplicateOf(taskID, marks); err != nil {
+		return nil, fmt.Errorf("保存去重结果失败: %w", err)
+	}
+
+	log.Printf("[Dedup] 任务 %s 去重完成: 检查 %d 条, 与源文件重复 %d 条, 内部重复 %d 条",
+		taskID, result.Checked, result.SourceDuplicates, result.InternalDuplicates)
+
+	return result, nil
+}
+
+// loadSourceKeys 流式解析源文件，返回 指纹 -> 首次出现的条目下标
+func (s *DedupService) loadSourceKeys(fileID uint) (map[string]int, error) {
+	file, err := s.fileRepo.GetByID(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("源文件不存在: %w", err)
+	}
+
+	sourceKeys := make(map[string]int)
+	err = utils.ScanJSONL(bytes.NewReader(file.FileContent), func(index int, item map[string]interface{}) error {
+		key := conversationKey(item)
+		if _, exists := sourceKeys[key]; !exists {
+			sourceKeys[key] = index
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("解析源文件失败: %w", err)
+	}
+	return sourceKeys, nil
+}
+
+// conversationKey 计算对话内容的指纹
+// 有 turns 时按角色和规整后的文本计算（忽略 meta 和空白差异），否则使用去掉 meta 后的完整内容
+func conversationKey(content map[string]interface{}) string {
+	var builder strings.Builder
+
+	if turns, ok := content["turns"].([]interface{}); ok {
+		for _, turnRaw := range turns {
+			turn, ok := turnRaw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			role, _ := turn["role"].(string)
+			text, _ := turn["text"].(string)
+			builder.WriteString(role)
+			builder.WriteString(":")
+			builder.WriteString(normalizeText(text))
+			builder.WriteString("\n")
+		}
+	} else {
+		rest := make(map[string]interface{}, len(content))
+		for k, v := range content {
+			if k != "meta" {
+				rest[k] = v
+			}
+		}
+		raw, _ := json.Marshal(rest)
+		builder.Write(raw)
+	}
+
+	sum := sha256.Sum256([]byte(builder.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeText 规整文本：合并连续空白并转为小写
+func normalizeText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
@@ -0,0 +1,46 @@
+package service
+
+import "testing"
+
+func TestConversationKey(t *testing.T) {
+	turns := func(pairs ...string) map[string]interface{} {
+		var list []interface{}
+		for i := 0; i+1 < len(pairs); i += 2 {
+			list = append(list, map[string]interface{}{"role": pairs[i], "text": pairs[i+1]})
+		}
+		return map[string]interface{}{"meta": map[string]interface{}{"meta_description": "x"}, "turns": list}
+	}
+	base := turns("Human", "你好，世界", "Assistant", "Hello  World")
+
+	tests := []struct {
+		name    string
+		content map[string]interface{}
+		same    bool
+	}{
+		{"identical", turns("Human", "你好，世界", "Assistant", "Hello  World"), true},
+		{"whitespace and case", turns("Human", " 你好，世界\n", "Assistant", "hello\tworld"), true},
+		{"meta ignored", map[string]interface{}{"turns": base["turns"]}, true},
+		{"different text", turns("Human", "你好，世界", "Assistant", "Hello"), false},
+		{"different role", turns("Assistant", "你好，世界", "Human", "Hello World"), false},
+		{"turn order", turns("Assistant", "Hello World", "Human", "你好，世界"), false},
+	}
+	key := conversationKey(base)
+	for _, tt := range tests {
+		if got := conversationKey(tt.content) == key; got != tt.same {
+			t.Errorf("%s: same key = %v, want %v", tt.name, got, tt.same)
+		}
+	}
+}
+
+func TestConversationKeyWithoutTurns(t *testing.T) {
+	a := map[string]interface{}{"meta": "a", "input": "x", "output": "y"}
+	b := map[string]interface{}{"meta": "b", "output": "y", "input": "x"}
+	c := map[string]interface{}{"input": "x", "output": "z"}
+
+	if conversationKey(a) != conversationKey(b) {
+		t.Error("content without turns: meta and key order should not change the key")
+	}
+	if conversationKey(a) == conversationKey(c) {
+		t.Error("content without turns: different content produced the same key")
+	}
+}
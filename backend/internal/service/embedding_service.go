@@ -0,0 +1,648 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+	"gen-go/internal/utils"
+)
+
+// 向量索引状态
+const (
+	EmbeddingStatusRunning  = "running"
+	EmbeddingStatusFinished = "finished"
+	EmbeddingStatusError    = "error"
+)
+
+// 向量索引与语义检索限制
+const (
+	SemanticSearchDefaultLimit = 20
+	SemanticSearchMaxLimit     = 100
+	embeddingBatchSize         = 64   // 每次向量接口请求的条数
+	embeddingMaxInputRunes     = 8000 // 向量化文本的长度上限
+	embeddingScanBatchSize     = 1000 // 进程内计算相似度时每批读取的向量数
+	semanticSnippetRunes       = 200
+)
+
+// EmbeddingService 数据向量索引与语义检索服务
+// 将数据文件条目和生成数据向量化后保存到 embeddings 表，检索时按余弦相似度排序
+type EmbeddingService struct {
+	embeddingRepo     *repository.EmbeddingRepository
+	fileRepo          *repository.DataFileRepository
+	generatedDataRepo *repository.GeneratedDataRepository
+	taskRepo          *repository.TaskRepository
+	modelService      *ModelService
+	jobPool           *JobPool
+
+	lock sync.Mutex
+	jobs map[string]*embeddingJob
+}
+
+// embeddingJob 单个文件或任务的索引进度
+type embeddingJob struct {
+	lock       sync.Mutex
+	state      dto.EmbeddingIndexResponse
+	startedAt  time.Time
+	finishedAt *time.Time
+}
+
+// pendingEmbedding 等待向量化的条目
+type pendingEmbedding struct {
+	row  models.Embedding
+	text string
+}
+
+// NewEmbeddingService 创建数据向量索引服务
+func NewEmbeddingService(
+	embeddingRepo *repository.EmbeddingRepository,
+	fileRepo *repository.DataFileRepository,
+	generatedDataRepo *repository.GeneratedDataRepository,
+	taskRepo *repository.TaskRepository,
+	modelService *ModelService,
+	jobPool *JobPool,
+) *EmbeddingService {
+	return &EmbeddingService{
+		embeddingRepo:     embeddingRepo,
+		fileRepo:          fileRepo,
+		generatedDataRepo: generatedDataRepo,
+		taskRepo:          taskRepo,
+		modelService:      modelService,
+		jobPool:           jobPool,
+		jobs:              make(map[string]*embeddingJob),
+	}
+}
+
+// StartFileIndexForUser 为数据文件的每条数据建立向量索引（后台执行，内容未变的条目沿用已有向量）
+func (s *EmbeddingService) StartFileIndexForUser(fileID, userID, modelID uint) (*dto.EmbeddingIndexResponse, error) {
+	file, err := s.fileRepo.GetEditableByIDAndUserID(fileID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("文件不存在或无权访问")
+	}
+	model, err := s.modelService.EmbeddingModelByID(modelID)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.begin(fileJobKey(fileID), dto.EmbeddingIndexResponse{
+		SourceType: models.EmbeddingSourceFile,
+		FileID:     fileID,
+		ModelID:    model.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		err := s.jobPool.Run(JobKindEmbedding, func() error {
+			return s.indexFile(job, file, model)
+		})
+		job.finish(err)
+		if err != nil {
+			log.Printf("[Embedding] 文件 %d 建立向量索引失败: %v", fileID, err)
+		}
+	}()
+
+	return job.snapshot(), nil
+}
+
+// StartTaskIndexForUser 为任务的生成数据建立向量索引（后台执行）
+func (s *EmbeddingService) StartTaskIndexForUser(taskID string, userID, modelID uint) (*dto.EmbeddingIndexResponse, error) {
+	task, err := s.taskRepo.GetEditableByTaskID(taskID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("任务不存在或无权访问")
+	}
+	model, err := s.modelService.EmbeddingModelByID(modelID)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.begin(taskJobKey(taskID), dto.EmbeddingIndexResponse{
+		SourceType: models.EmbeddingSourceGenerated,
+		TaskID:     taskID,
+		ModelID:    model.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		err := s.jobPool.Run(JobKindEmbedding, func() error {
+			return s.indexTask(job, task, model)
+		})
+		job.finish(err)
+		if err != nil {
+			log.Printf("[Embedding] 任务 %s 建立向量索引失败: %v", taskID, err)
+		}
+	}()
+
+	return job.snapshot(), nil
+}
+
+// GetFileIndexForUser 获取数据文件的向量索引进度
+func (s *EmbeddingService) GetFileIndexForUser(fileID, userID uint) (*dto.EmbeddingIndexResponse, error) {
+	if _, err := s.fileRepo.GetByIDAndUserID(fileID, userID); err != nil {
+		return nil, fmt.Errorf("文件不存在或无权访问")
+	}
+	return s.getJob(fileJobKey(fileID))
+}
+
+// GetTaskIndexForUser 获取任务生成数据的向量索引进度
+func (s *EmbeddingService) GetTaskIndexForUser(taskID string, userID uint) (*dto.EmbeddingIndexResponse, error) {
+	if _, err := s.taskRepo.GetVisibleByTaskID(taskID, userID); err != nil {
+		return nil, fmt.Errorf("任务不存在或无权访问")
+	}
+	return s.getJob(taskJobKey(taskID))
+}
+
+// begin 登记索引进度，同一文件或任务同时只允许一次索引
+func (s *EmbeddingService) begin(key string, state dto.EmbeddingIndexResponse) (*embeddingJob, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if job, ok := s.jobs[key]; ok && job.snapshot().Status == EmbeddingStatusRunning {
+		return nil, fmt.Errorf("正在建立向量索引")
+	}
+
+	state.Status = EmbeddingStatusRunning
+	job := &embeddingJob{state: state, startedAt: time.Now()}
+	s.jobs[key] = job
+	return job, nil
+}
+
+// getJob 获取索引进度
+func (s *EmbeddingService) getJob(key string) (*dto.EmbeddingIndexResponse, error) {
+	s.lock.Lock()
+	job, ok := s.jobs[key]
+	s.lock.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("没有建立向量索引的记录")
+	}
+	return job.snapshot(), nil
+}
+
+// indexFile 逐条解析数据文件并分批向量化
+func (s *EmbeddingService) indexFile(job *embeddingJob, file *models.DataFile, model *models.ModelConfig) error {
+	filter := repository.EmbeddingFilter{ModelID: model.ID, SourceType: models.EmbeddingSourceFile, SourceID: file.ID}
+	hashes, err := s.embeddingRepo.ContentHashes(filter)
+	if err != nil {
+		return fmt.Errorf("读取已有向量失败: %w", err)
+	}
+
+	total := 0
+	if err := utils.ScanJSONL(bytes.NewReader(file.FileContent), func(int, map[string]interface{}) error {
+		total++
+		return nil
+	}); err != nil {
+		return fmt.Errorf("解析文件失败: %w", err)
+	}
+	job.setTotal(total)
+
+	var pending []pendingEmbedding
+	err = utils.ScanJSONL(bytes.NewReader(file.FileContent), func(index int, item map[string]interface{}) error {
+		text := EmbeddingText(item)
+		hash := contentHash(text)
+		if hashes[repository.EmbeddingKey{SourceID: file.ID, ItemIndex: index}] == hash {
+			job.skip(1)
+			return nil
+		}
+		pending = append(pending, pendingEmbedding{
+			row:  models.Embedding{UserID: file.UserID, ModelID: model.ID, SourceType: models.EmbeddingSourceFile, SourceID: file.ID, ItemIndex: index, ContentHash: hash},
+			text: text,
+		})
+		if len(pending) >= embeddingBatchSize {
+			s.flush(job, model, pending)
+			pending = pending[:0]
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("解析文件失败: %w", err)
+	}
+	s.flush(job, model, pending)
+
+	if err := s.embeddingRepo.DeleteFileItemsFrom(model.ID, file.ID, total); err != nil {
+		return fmt.Errorf("清理过期向量失败: %w", err)
+	}
+	return s.complete(job, filter)
+}
+
+// indexTask 分批读取任务的生成数据并向量化
+func (s *EmbeddingService) indexTask(job *embeddingJob, task *models.Task, model *models.ModelConfig) error {
+	filter := repository.EmbeddingFilter{ModelID: model.ID, SourceType: models.EmbeddingSourceGenerated, TaskID: task.TaskID}
+	hashes, err := s.embeddingRepo.ContentHashes(filter)
+	if err != nil {
+		return fmt.Errorf("读取已有向量失败: %w", err)
+	}
+
+	counts, err := s.generatedDataRepo.CountByTaskIDs([]string{task.TaskID})
+	if err != nil {
+		return fmt.Errorf("统计生成数据失败: %w", err)
+	}
+	job.setTotal(int(counts[task.TaskID].DataCount))
+
+	err = s.generatedDataRepo.ScanForDedup(task.TaskID, embeddingBatchSize, func(batch []models.GeneratedData) error {
+		pending := make([]pendingEmbedding, 0, len(batch))
+		for _, data := range batch {
+			var content map[string]interface{}
+			if err := json.Unmarshal([]byte(data.DataContent), &content); err != nil {
+				job.fail(1)
+				continue
+			}
+			text := EmbeddingText(content)
+			hash := contentHash(text)
+			if hashes[repository.EmbeddingKey{SourceID: data.ID}] == hash {
+				job.skip(1)
+				continue
+			}
+			pending = append(pending, pendingEmbedding{
+				row:  models.Embedding{UserID: task.UserID, ModelID: model.ID, SourceType: models.EmbeddingSourceGenerated, SourceID: data.ID, TaskID: task.TaskID, ContentHash: hash},
+				text: text,
+			})
+		}
+		s.flush(job, model, pending)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("获取生成数据失败: %w", err)
+	}
+	return s.complete(job, filter)
+}
+
+// flush 向量化一批条目并写入索引，失败的条目计入 failed
+func (s *EmbeddingService) flush(job *embeddingJob, model *models.ModelConfig, pending []pendingEmbedding) {
+	if len(pending) == 0 {
+		return
+	}
+
+	texts := make([]string, len(pending))
+	for i, p := range pending {
+		texts[i] = p.text
+	}
+	vectors, err := s.EmbedTexts(model, texts)
+	if err == nil {
+		rows := make([]models.Embedding, len(pending))
+		for i, p := range pending {
+			rows[i] = p.row
+			rows[i].Dim = len(vectors[i])
+			rows[i].Vector = models.EncodeVector(vectors[i])
+		}
+		err = s.embeddingRepo.SaveBatch(rows)
+	}
+	if err != nil {
+		job.setError(err)
+		job.fail(len(pending))
+		return
+	}
+	job.embed(len(pending))
+}
+
+// complete 统计索引中的向量数；全部条目都向量化失败时返回最后一次的错误
+func (s *EmbeddingService) complete(job *embeddingJob, filter repository.EmbeddingFilter) error {
+	indexed, err := s.embeddingRepo.Count(filter)
+	if err != nil {
+		return fmt.Errorf("统计向量数失败: %w", err)
+	}
+	job.setIndexed(indexed)
+
+	state := job.snapshot()
+	if state.Failed > 0 && state.Embedded == 0 && state.Skipped == 0 {
+		return fmt.Errorf("向量化失败: %s", state.Error)
+	}
+	source := "任务 " + state.TaskID
+	if state.SourceType == models.EmbeddingSourceFile {
+		source = fmt.Sprintf("文件 %d", state.FileID)
+	}
+	log.Printf("[Embedding] %s 向量索引完成: 共 %d 条, 新增 %d 条, 沿用 %d 条, 失败 %d 条",
+		source, state.Total, state.Embedded, state.Skipped, state.Failed)
+	return nil
+}
+
+// EmbedTexts 按批向量化文本，返回与输入顺序一致的向量
+func (s *EmbeddingService) EmbedTexts(model *models.ModelConfig, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += embeddingBatchSize {
+		end := start + embeddingBatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+		resp, err := s.modelService.EmbedWithModel(context.Background(), model, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		if !resp.Success {
+			return nil, fmt.Errorf("%s", resp.Error)
+		}
+		vectors = append(vectors, resp.Embeddings...)
+	}
+	return vectors, nil
+}
+
+// SearchForUser 语义检索：向量化查询文本，在用户的数据（或指定文件、任务）中按相似度返回最相近的条目
+func (s *EmbeddingService) SearchForUser(userID uint, query string, modelID, fileID uint, taskID string, limit int) (*dto.SemanticSearchResponse, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("检索内容不能为空")
+	}
+	model, err := s.modelService.EmbeddingModelByID(modelID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := repository.EmbeddingFilter{ModelID: model.ID}
+	switch {
+	case fileID > 0:
+		if _, err := s.fileRepo.GetByIDAndUserID(fileID, userID); err != nil {
+			return nil, fmt.Errorf("文件不存在或无权访问")
+		}
+		filter.SourceType = models.EmbeddingSourceFile
+		filter.SourceID = fileID
+	case taskID != "":
+		if _, err := s.taskRepo.GetVisibleByTaskID(taskID, userID); err != nil {
+			return nil, fmt.Errorf("任务不存在或无权访问")
+		}
+		filter.SourceType = models.EmbeddingSourceGenerated
+		filter.TaskID = taskID
+	default:
+		filter.UserID = userID
+	}
+
+	vectors, err := s.EmbedTexts(model, []string{truncateRunes(query, embeddingMaxInputRunes)})
+	if err != nil {
+		return nil, fmt.Errorf("向量化检索内容失败: %w", err)
+	}
+	filter.Dim = len(vectors[0])
+
+	matches, err := s.Nearest(filter, vectors[0], limit)
+	if err != nil {
+		return nil, fmt.Errorf("检索向量失败: %w", err)
+	}
+
+	hits, err := s.resolveHits(matches)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.SemanticSearchResponse{
+		Query:   query,
+		ModelID: model.ID,
+		Engine:  models.VectorEngine(),
+		Hits:    hits,
+	}, nil
+}
+
+// Nearest 检索范围内与 query 最相近的 limit 个条目（按相似度降序），向量扩展不可用时在进程内计算
+func (s *EmbeddingService) Nearest(filter repository.EmbeddingFilter, query []float32, limit int) ([]repository.EmbeddingMatch, error) {
+	if models.VectorEngine() != models.VectorEngineScan {
+		return s.embeddingRepo.Nearest(filter, query, limit)
+	}
+
+	var matches []repository.EmbeddingMatch
+	err := s.embeddingRepo.ScanVectors(filter, embeddingScanBatchSize, func(batch []models.Embedding) error {
+		for _, row := range batch {
+			similarity := CosineSimilarity(query, models.DecodeVector(row.Vector))
+			if len(matches) >= limit && similarity <= matches[len(matches)-1].Similarity {
+				continue
+			}
+			i := sort.Search(len(matches), func(i int) bool { return matches[i].Similarity < similarity })
+			matches = append(matches, repository.EmbeddingMatch{})
+			copy(matches[i+1:], matches[i:])
+			matches[i] = repository.EmbeddingMatch{
+				SourceType: row.SourceType,
+				SourceID:   row.SourceID,
+				ItemIndex:  row.ItemIndex,
+				TaskID:     row.TaskID,
+				Similarity: similarity,
+			}
+			if len(matches) > limit {
+				matches = matches[:limit]
+			}
+		}
+		return nil
+	})
+	return matches, err
+}
+
+// resolveHits 读取命中条目的内容生成摘要，已删除的文件和生成数据不返回
+func (s *EmbeddingService) resolveHits(matches []repository.EmbeddingMatch) ([]dto.SemanticSearchHit, error) {
+	fileItems := make(map[uint]map[int]string)
+	var dataIDs []uint
+	for _, match := range matches {
+		if match.SourceType == models.EmbeddingSourceFile {
+			if fileItems[match.SourceID] == nil {
+				fileItems[match.SourceID] = make(map[int]string)
+			}
+			fileItems[match.SourceID][match.ItemIndex] = ""
+		} else {
+			dataIDs = append(dataIDs, match.SourceID)
+		}
+	}
+
+	filenames := make(map[uint]string)
+	for fileID, items := range fileItems {
+		file, err := s.fileRepo.GetByID(fileID)
+		if err != nil {
+			continue
+		}
+		filenames[fileID] = file.Filename
+		err = utils.ScanJSONL(bytes.NewReader(file.FileContent), func(index int, item map[string]interface{}) error {
+			if _, ok := items[index]; ok {
+				items[index] = EmbeddingText(item)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("解析文件失败: %w", err)
+		}
+	}
+
+	dataTexts := make(map[uint]string)
+	if len(dataIDs) > 0 {
+		dataList, err := s.generatedDataRepo.ListByIDs(dataIDs)
+		if err != nil {
+			return nil, fmt.Errorf("获取生成数据失败: %w", err)
+		}
+		for _, data := range dataList {
+			var content map[string]interface{}
+			if err := json.Unmarshal([]byte(data.DataContent), &content); err == nil {
+				dataTexts[data.ID] = EmbeddingText(content)
+			}
+		}
+	}
+
+	hits := make([]dto.SemanticSearchHit, 0, len(matches))
+	for _, match := range matches {
+		hit := dto.SemanticSearchHit{Type: match.SourceType, Similarity: match.Similarity}
+		if match.SourceType == models.EmbeddingSourceFile {
+			filename, ok := filenames[match.SourceID]
+			text := fileItems[match.SourceID][match.ItemIndex]
+			if !ok || text == "" {
+				continue
+			}
+			hit.FileID = match.SourceID
+			hit.Filename = filename
+			hit.Line = match.ItemIndex + 1
+			hit.Snippet = truncateRunes(text, semanticSnippetRunes)
+		} else {
+			text, ok := dataTexts[match.SourceID]
+			if !ok {
+				continue
+			}
+			hit.DataID = match.SourceID
+			hit.TaskID = match.TaskID
+			hit.Snippet = truncateRunes(text, semanticSnippetRunes)
+		}
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// EmbeddingText 数据条目的向量化文本：有 turns 时按 角色: 内容 逐行拼接，否则使用去掉 meta 后的 JSON
+func EmbeddingText(content map[string]interface{}) string {
+	var builder strings.Builder
+	if turns, ok := content["turns"].([]interface{}); ok {
+		for _, turnRaw := range turns {
+			turn, ok := turnRaw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			role, _ := turn["role"].(string)
+			text, _ := turn["text"].(string)
+			builder.WriteString(role)
+			builder.WriteString(": ")
+			builder.WriteString(strings.TrimSpace(text))
+			builder.WriteString("\n")
+		}
+	} else {
+		rest := make(map[string]interface{}, len(content))
+		for k, v := range content {
+			if k != "meta" {
+				rest[k] = v
+			}
+		}
+		raw, _ := json.Marshal(rest)
+		builder.Write(raw)
+	}
+	return truncateRunes(strings.TrimSpace(builder.String()), embeddingMaxInputRunes)
+}
+
+// CosineSimilarity 两个向量的余弦相似度，维度不同或为零向量时返回 0
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// contentHash 向量化文本的指纹
+func contentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// truncateRunes 按字符截断文本
+func truncateRunes(text string, max int) string {
+	if runes := []rune(text); len(runes) > max {
+		return string(runes[:max])
+	}
+	return text
+}
+
+// fileJobKey 数据文件索引进度的键
+func fileJobKey(fileID uint) string {
+	return fmt.Sprintf("file:%d", fileID)
+}
+
+// taskJobKey 任务索引进度的键
+func taskJobKey(taskID string) string {
+	return "task:" + taskID
+}
+
+// setTotal 设置待索引总数
+func (j *embeddingJob) setTotal(total int) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.state.Total = total
+}
+
+// embed 记录新向量化的条数
+func (j *embeddingJob) embed(n int) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.state.Embedded += n
+}
+
+// skip 记录沿用已有向量的条数
+func (j *embeddingJob) skip(n int) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.state.Skipped += n
+}
+
+// fail 记录向量化失败的条数
+func (j *embeddingJob) fail(n int) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.state.Failed += n
+}
+
+// setError 记录最近一次的错误
+func (j *embeddingJob) setError(err error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.state.Error = err.Error()
+}
+
+// setIndexed 设置索引中的向量总数
+func (j *embeddingJob) setIndexed(indexed int64) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.state.Indexed = indexed
+}
+
+// finish 结束索引，err 不为空时标记为失败
+func (j *embeddingJob) finish(err error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	now := time.Now()
+	j.finishedAt = &now
+	j.state.Status = EmbeddingStatusFinished
+	if err != nil {
+		j.state.Status = EmbeddingStatusError
+		j.state.Error = err.Error()
+	}
+}
+
+// snapshot 获取当前进度的副本
+func (j *embeddingJob) snapshot() *dto.EmbeddingIndexResponse {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	state := j.state
+	if state.Total > 0 {
+		state.Percent = float64(state.Embedded+state.Skipped+state.Failed) / float64(state.Total) * 100
+	}
+	state.StartedAt = dto.FormatTime(j.startedAt)
+	state.FinishedAt = dto.FormatTimePtr(j.finishedAt)
+	return &state
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"gen-go/internal/dto"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// endpointPoolKeyPrefix 端点状态的 Redis 键前缀（每个服务地址一个哈希）
+	endpointPoolKeyPrefix = "endpoint_pool:"
+	// endpointStateTTL 端点状态的过期时间，进程异常退出未归还的在途计数最多保留这么久
+	endpointStateTTL = 10 * time.Minute
+	// endpointFailureThreshold 连续失败达到该次数的端点在冷却期内不参与路由
+	endpointFailureThreshold = 3
+	// endpointCooldown 不健康端点的冷却时间，冷却后重新参与路由（成功一次即恢复）
+	endpointCooldown = 30 * time.Second
+	// endpointLatencyAlpha 平均延迟的指数加权系数
+	endpointLatencyAlpha = 0.2
+)
+
+// EndpointPool 模型服务端点池
+// 在 Redis 中按服务地址记录在途请求数、最近延迟和连续失败次数，多个后端实例共享，
+// 代理调用时选择健康端点中在途请求最少的一个（相同时选择延迟更低的）
+type EndpointPool struct {
+	redisClient *redis.Client
+}
+
+// NewEndpointPool 创建端点池
+func NewEndpointPool(redisClient *redis.Client) *EndpointPool {
+	return &EndpointPool{redisClient: redisClient}
+}
+
+// endpointState 端点在 Redis 中记录的状态
+type endpointState struct {
+	inFlight      int64
+	latencyMs     float64
+	failures      int64
+	lastError     string
+	lastFailureAt int64
+}
+
+// healthy 连续失败未达到阈值，或已过冷却期
+func (s *endpointState) healthy(now time.Time) bool {
+	return s.failures < endpointFailureThreshold ||
+		now.Sub(time.UnixMilli(s.lastFailureAt)) >= endpointCooldown
+}
+
+// Acquire 从候选地址中选择负载最低的健康端点并计入在途请求，返回选中的地址和请求结束后调用的回调
+// Redis 不可用时直接使用第一个候选地址
+func (p *EndpointPool) Acquire(ctx context.Context, endpoints []string) (string, func(latency time.Duration, callErr string)) {
+	if len(endpoints) == 0 {
+		return "", func(time.Duration, string) {}
+	}
+	if p.redisClient == nil {
+		return endpoints[0], func(time.Duration, string) {}
+	}
+
+	selected := endpoints[0]
+	if len(endpoints) > 1 {
+		states, err := p.load(ctx, endpoints)
+		if err != nil {
+			log.Printf("[EndpointPool] 读取端点状态失败: %v", err)
+			return selected, func(time.Duration, string) {}
+		}
+		selected = pickEndpoint(endpoints, states, time.Now())
+	}
+
+	key := endpointPoolKeyPrefix + selected
+	pipe := p.redisClient.TxPipeline()
+	pipe.HIncrBy(ctx, key, "in_flight", 1)
+	pipe.Expire(ctx, key, endpointStateTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("[EndpointPool] 记录在途请求失败: %v", err)
+		return selected, func(time.Duration, string) {}
+	}
+
+	return selected, func(latency time.Duration, callErr string) {
+		p.release(selected, latency, callErr)
+	}
+}
+
+// pickEndpoint 选择健康端点中在途请求最少、延迟最低的一个；全部不健康时在所有端点中选择
+func pickEndpoint(endpoints []string, states []endpointState, now time.Time) string {
+	best := -1
+	for _, onlyHealthy := range []bool{true, false} {
+		for i := range endpoints {
+			if onlyHealthy && !states[i].healthy(now) {
+				continue
+			}
+			if best < 0 ||
+				states[i].inFlight < states[best].inFlight ||
+				(states[i].inFlight == states[best].inFlight && states[i].latencyMs < states[best].latencyMs) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			break
+		}
+	}
+	return endpoints[best]
+}
+
+// release 归还在途请求，更新平均延迟或失败计数（使用独立上下文，调用方上下文可能已取消）
+func (p *EndpointPool) release(url string, latency time.Duration, callErr string) {
+	ctx := context.Background()
+	key := endpointPoolKeyPrefix + url
+
+	pipe := p.redisClient.TxPipeline()
+	pipe.HIncrBy(ctx, key, "in_flight", -1)
+	if callErr == "" {
+		prev, _ := p.redisClient.HGet(ctx, key, "latency_ms").Float64()
+		current := float64(latency.Milliseconds())
+		if prev > 0 {
+			current = endpointLatencyAlpha*current + (1-endpointLatencyAlpha)*prev
+		}
+		pipe.HSet(ctx, key, "latency_ms", strconv.FormatFloat(current, 'f', 1, 64), "failures", 0)
+	} else {
+		pipe.HIncrBy(ctx, key, "failures", 1)
+		pipe.HSet(ctx, key, "last_error", callErr, "last_failure_at", time.Now().UnixMilli())
+	}
+	pipe.Expire(ctx, key, endpointStateTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("[EndpointPool] 更新端点 %s 状态失败: %v", url, err)
+	}
+}
+
+// load 批量读取端点状态
+func (p *EndpointPool) load(ctx context.Context, endpoints []string) ([]endpointState, error) {
+	pipe := p.redisClient.Pipeline()
+	cmds := make([]*redis.StringStringMapCmd, len(endpoints))
+	for i, url := range endpoints {
+		cmds[i] = pipe.HGetAll(ctx, endpointPoolKeyPrefix+url)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	states := make([]endpointState, len(endpoints))
+	for i, cmd := range cmds {
+		fields := cmd.Val()
+		states[i] = endpointState{lastError: fields["last_error"]}
+		states[i].inFlight, _ = strconv.ParseInt(fields["in_flight"], 10, 64)
+		if states[i].inFlight < 0 {
+			states[i].inFlight = 0
+		}
+		states[i].latencyMs, _ = strconv.ParseFloat(fields["latency_ms"], 64)
+		states[i].failures, _ = strconv.ParseInt(fields["failures"], 10, 64)
+		states[i].lastFailureAt, _ = strconv.ParseInt(fields["last_failure_at"], 10, 64)
+	}
+	return states, nil
+}
+
+// Status 获取各端点的当前状态
+func (p *EndpointPool) Status(ctx context.Context, endpoints []string) ([]dto.EndpointStatus, error) {
+	states := make([]endpointState, len(endpoints))
+	if p.redisClient != nil {
+		loaded, err := p.load(ctx, endpoints)
+		if err != nil {
+			return nil, err
+		}
+		states = loaded
+	}
+
+	now := time.Now()
+	result := make([]dto.EndpointStatus, len(endpoints))
+	for i, url := range endpoints {
+		state := states[i]
+		result[i] = dto.EndpointStatus{
+			URL:                 url,
+			InFlight:            state.inFlight,
+			AvgLatencyMs:        state.latencyMs,
+			ConsecutiveFailures: state.failures,
+			Healthy:             state.healthy(now),
+			LastError:           state.lastError,
+		}
+		if state.lastFailureAt > 0 {
+			lastFailureAt := state.lastFailureAt
+			result[i].LastFailureAt = &lastFailureAt
+		}
+	}
+	return result, nil
+}
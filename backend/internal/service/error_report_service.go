@@ -0,0 +1,82 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/repository"
+)
+
+// 错误汇总限制
+const (
+	errorReportMaxTasks     = 5000 // 参与统计的失败任务上限
+	errorReportTaskIDsLimit = 10   // 每个类别列出的任务数
+)
+
+// ErrorReportService 运维错误汇总服务
+// 汇总最近的 5xx 响应、按错误类别分组的任务失败和上游模型调用失败
+type ErrorReportService struct {
+	tracker  *ErrorTracker
+	taskRepo *repository.TaskRepository
+}
+
+// NewErrorReportService 创建错误汇总服务
+func NewErrorReportService(tracker *ErrorTracker, taskRepo *repository.TaskRepository) *ErrorReportService {
+	return &ErrorReportService{
+		tracker:  tracker,
+		taskRepo: taskRepo,
+	}
+}
+
+// Report 获取 since 之后的错误汇总
+func (s *ErrorReportService) Report(since time.Time) (*dto.ErrorReportResponse, error) {
+	tasks, err := s.taskRepo.ListFailedSince(since, errorReportMaxTasks)
+	if err != nil {
+		return nil, fmt.Errorf("获取失败任务失败: %w", err)
+	}
+
+	// 任务按结束时间倒序，因此每个类别第一次遇到的是最近一次失败
+	groups := make(map[string]*dto.TaskFailureGroup)
+	for _, task := range tasks {
+		message := task.ErrorMessage
+		if message == "" {
+			message = "（未记录错误信息）"
+		}
+		class := ClassifyError(message)
+		finishedAt := dto.FormatTime(*task.FinishedAt)
+
+		group, ok := groups[class]
+		if !ok {
+			group = &dto.TaskFailureGroup{
+				Class:    class,
+				LastSeen: finishedAt,
+				Sample:   message,
+				TaskIDs:  []string{},
+			}
+			groups[class] = group
+		}
+		group.Count++
+		group.FirstSeen = finishedAt
+		if len(group.TaskIDs) < errorReportTaskIDsLimit {
+			group.TaskIDs = append(group.TaskIDs, task.TaskID)
+		}
+	}
+
+	taskFailures := make([]dto.TaskFailureGroup, 0, len(groups))
+	for _, group := range groups {
+		taskFailures = append(taskFailures, *group)
+	}
+	sort.Slice(taskFailures, func(i, j int) bool {
+		return taskFailures[i].Count > taskFailures[j].Count
+	})
+
+	return &dto.ErrorReportResponse{
+		Since:         dto.FormatTime(since),
+		TrackingSince: dto.FormatTime(s.tracker.StartedAt()),
+		HTTPErrors:    s.tracker.Snapshot(ErrorSourceHTTP, since),
+		TaskFailures:  taskFailures,
+		ModelFailures: s.tracker.Snapshot(ErrorSourceModel, since),
+	}, nil
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gen-go/internal/dto"
+)
+
+// 错误来源
+const (
+	ErrorSourceHTTP  = "http"
+	ErrorSourceModel = "model"
+)
+
+// 错误类别
+const (
+	ErrorClassTimeout           = "timeout"
+	ErrorClassConnectionRefused = "connection_refused"
+	ErrorClassDNS               = "dns"
+	ErrorClassRateLimited       = "rate_limited"
+	ErrorClassUpstream4xx       = "http_4xx"
+	ErrorClassUpstream5xx       = "http_5xx"
+	ErrorClassInvalidResponse   = "invalid_response"
+	ErrorClassWorkerProtocol    = "worker_protocol"
+	ErrorClassWorkerExit        = "worker_exit"
+	ErrorClassCancelled         = "cancelled"
+	ErrorClassDatabase          = "database"
+	ErrorClassOther             = "other"
+)
+
+// 每个来源最多保留的错误分组数，超出时淘汰最久未出现的分组
+const errorTrackerMaxGroups = 500
+
+var statusCodePattern = regexp.MustCompile(`status=(\d{3})`)
+
+// ErrorTracker 进程内错误统计（5xx 响应和上游模型调用失败），供运维排查使用
+type ErrorTracker struct {
+	lock      sync.Mutex
+	startedAt time.Time
+	groups    map[string]map[string]*errorGroup // 来源 -> 分组键 -> 分组
+}
+
+// errorGroup 单个错误分组
+type errorGroup struct {
+	key       string
+	class     string
+	status    int
+	count     int64
+	firstSeen time.Time
+	lastSeen  time.Time
+	sample    string
+}
+
+// NewErrorTracker 创建错误统计
+func NewErrorTracker() *ErrorTracker {
+	return &ErrorTracker{
+		startedAt: time.Now(),
+		groups:    make(map[string]map[string]*errorGroup),
+	}
+}
+
+// RecordHTTP 记录一次 5xx 响应（route 为路由模板，避免按 ID 分散）
+func (t *ErrorTracker) RecordHTTP(method, route string, status int, message string) {
+	if route == "" {
+		route = "(unmatched)"
+	}
+	class := ClassifyError(message)
+	t.record(ErrorSourceHTTP, fmt.Sprintf("%s %s|%d|%s", method, route, status, class), method+" "+route, class, status, message)
+}
+
+// RecordModelFailure 记录一次上游模型调用失败
+func (t *ErrorTracker) RecordModelFailure(model, message string) {
+	class := ClassifyError(message)
+	status := 0
+	if match := statusCodePattern.FindStringSubmatch(message); match != nil {
+		fmt.Sscanf(match[1], "%d", &status)
+	}
+	t.record(ErrorSourceModel, model+"|"+class, model, class, status, message)
+}
+
+// record 累加错误分组
+func (t *ErrorTracker) record(source, id, key, class string, status int, message string) {
+	if t == nil {
+		return
+	}
+	if runes := []rune(message); len(runes) > 500 {
+		message = string(runes[:500]) + "..."
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	groups, ok := t.groups[source]
+	if !ok {
+		groups = make(map[string]*errorGroup)
+		t.groups[source] = groups
+	}
+
+	now := time.Now()
+	group, ok := groups[id]
+	if !ok {
+		if len(groups) >= errorTrackerMaxGroups {
+			evictOldestGroup(groups)
+		}
+		group = &errorGroup{key: key, class: class, status: status, firstSeen: now}
+		groups[id] = group
+	}
+	group.count++
+	group.lastSeen = now
+	group.sample = message
+}
+
+// evictOldestGroup 淘汰最久未出现的分组
+func evictOldestGroup(groups map[string]*errorGroup) {
+	var oldestID string
+	var oldest time.Time
+	for id, group := range groups {
+		if oldestID == "" || group.lastSeen.Before(oldest) {
+			oldestID, oldest = id, group.lastSeen
+		}
+	}
+	delete(groups, oldestID)
+}
+
+// Snapshot 获取指定来源在 since 之后出现过的错误分组（按次数倒序）
+func (t *ErrorTracker) Snapshot(source string, since time.Time) []dto.ErrorGroup {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	result := make([]dto.ErrorGroup, 0)
+	for _, group := range t.groups[source] {
+		if group.lastSeen.Before(since) {
+			continue
+		}
+		result = append(result, dto.ErrorGroup{
+			Key:       group.key,
+			Class:     group.class,
+			Status:    group.status,
+			Count:     group.count,
+			FirstSeen: dto.FormatTime(group.firstSeen),
+			LastSeen:  dto.FormatTime(group.lastSeen),
+			Sample:    group.sample,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].LastSeen > result[j].LastSeen
+	})
+	return result
+}
+
+// StartedAt 统计起始时间
+func (t *ErrorTracker) StartedAt() time.Time {
+	return t.startedAt
+}
+
+// ClassifyError 按错误信息归类
+func ClassifyError(message string) string {
+	lower := strings.ToLower(message)
+	if match := statusCodePattern.FindStringSubmatch(lower); match != nil {
+		switch {
+		case match[1] == "429":
+			return ErrorClassRateLimited
+		case match[1][0] == '5':
+			return ErrorClassUpstream5xx
+		default:
+			return ErrorClassUpstream4xx
+		}
+	}
+
+	switch {
+	case strings.Contains(lower, "timeout") || strings.Contains(lower, "deadline exceeded") || strings.Contains(message, "超时"):
+		return ErrorClassTimeout
+	case strings.Contains(lower, "connection refused") || strings.Contains(lower, "connection reset"):
+		return ErrorClassConnectionRefused
+	case strings.Contains(lower, "no such host") || strings.Contains(lower, "server misbehaving"):
+		return ErrorClassDNS
+	case strings.Contains(message, "并发槽位") || strings.Contains(lower, "rate limit"):
+		return ErrorClassRateLimited
+	case strings.Contains(message, "解析响应") || strings.Contains(message, "空响应") || strings.Contains(message, "不是JSON"):
+		return ErrorClassInvalidResponse
+	case strings.Contains(message, "握手") || strings.Contains(message, "协议"):
+		return ErrorClassWorkerProtocol
+	case strings.Contains(lower, "exit status") || strings.Contains(message, "退出码"):
+		return ErrorClassWorkerExit
+	case strings.Contains(lower, "context canceled") || strings.Contains(message, "已停止"):
+		return ErrorClassCancelled
+	case strings.Contains(lower, "database") || strings.Contains(lower, "sql") || strings.Contains(message, "数据库"):
+		return ErrorClassDatabase
+	}
+	return ErrorClassOther
+}
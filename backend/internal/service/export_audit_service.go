@@ -0,0 +1,106 @@
+package service
+
+import (
+	"log"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+)
+
+// ExportAuditService 导出审计服务
+// 记录每一次生成数据导出和数据文件下载，供管理员追溯数据外流
+type ExportAuditService struct {
+	auditRepo       *repository.ExportAuditRepository
+	auditLogService *AuditLogService
+}
+
+// NewExportAuditService 创建导出审计服务
+func NewExportAuditService(auditRepo *repository.ExportAuditRepository, auditLogService *AuditLogService) *ExportAuditService {
+	return &ExportAuditService{
+		auditRepo:       auditRepo,
+		auditLogService: auditLogService,
+	}
+}
+
+// Record 写入审计记录（失败只记录日志，不影响下载本身）
+// 同时写入统一的操作审计日志，便于在 /admin/audit 中与其他敏感操作一并追溯
+func (s *ExportAuditService) Record(audit *models.ExportAudit) {
+	if err := s.auditRepo.Create(audit); err != nil {
+		log.Printf("[ExportAudit] 记录导出审计失败: user=%d, %s=%s, err=%v", audit.UserID, audit.ResourceType, audit.ResourceID, err)
+	}
+
+	s.auditLogService.Record(&models.AuditLog{
+		UserID:       audit.UserID,
+		Username:     audit.Username,
+		Action:       models.AuditActionExport,
+		ResourceType: audit.ResourceType,
+		ResourceID:   audit.ResourceID,
+		After: AuditSnapshot(map[string]interface{}{
+			"owner_id":  audit.OwnerID,
+			"format":    audit.Format,
+			"row_count": audit.RowCount,
+			"filters":   audit.Filters,
+		}),
+		Endpoint: audit.Endpoint,
+		ClientIP: audit.ClientIP,
+	})
+}
+
+// ListAudits 分页查询审计记录
+func (s *ExportAuditService) ListAudits(filter repository.ExportAuditFilter, page, perPage int) (*dto.PaginatedResponse, error) {
+	offset := (page - 1) * perPage
+	audits, total, err := s.auditRepo.List(filter, offset, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.ExportAuditResponse, len(audits))
+	for i, audit := range audits {
+		filters := map[string]interface{}(audit.Filters)
+		if filters == nil {
+			filters = map[string]interface{}{}
+		}
+		items[i] = dto.ExportAuditResponse{
+			ID:           audit.ID,
+			UserID:       audit.UserID,
+			Username:     audit.Username,
+			ResourceType: audit.ResourceType,
+			ResourceID:   audit.ResourceID,
+			OwnerID:      audit.OwnerID,
+			Format:       audit.Format,
+			RowCount:     audit.RowCount,
+			Filters:      filters,
+			Endpoint:     audit.Endpoint,
+			ClientIP:     audit.ClientIP,
+			CreatedAt:    dto.FormatTime(audit.CreatedAt),
+		}
+	}
+
+	return &dto.PaginatedResponse{
+		Items:   items,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	}, nil
+}
+
+// SummarizeByUser 按用户汇总导出情况
+func (s *ExportAuditService) SummarizeByUser(filter repository.ExportAuditFilter) ([]dto.ExportAuditSummaryResponse, error) {
+	summaries, err := s.auditRepo.SummarizeByUser(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.ExportAuditSummaryResponse, len(summaries))
+	for i, summary := range summaries {
+		result[i] = dto.ExportAuditSummaryResponse{
+			UserID:       summary.UserID,
+			Username:     summary.Username,
+			ExportCount:  summary.ExportCount,
+			TotalRows:    summary.TotalRows,
+			LastExportAt: dto.FormatTime(summary.LastExportAt),
+		}
+	}
+	return result, nil
+}
@@ -1,24 +1,100 @@
 package service
 
 import (
+	"fmt"
+	"strings"
+
 	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+	"gen-go/internal/utils"
 )
 
 // FileConversionService 文件转换服务
-type FileConversionService struct{}
+type FileConversionService struct {
+	fileRepo *repository.DataFileRepository
+}
 
 // NewFileConversionService 创建文件转换服务
-func NewFileConversionService() *FileConversionService {
-	return &FileConversionService{}
+func NewFileConversionService(fileRepo *repository.DataFileRepository) *FileConversionService {
+	return &FileConversionService{fileRepo: fileRepo}
 }
 
-// BatchConvert 批量转换文件格式
-func (s *FileConversionService) BatchConvert(fileIDs []uint, targetFormat string) (*dto.ConvertFilesResponse, error) {
-	// 简化实现
-	return &dto.ConvertFilesResponse{
-		Success: false,
-		Message: "批量转换功能开发中,请使用单个文件的下载功能",
-	}, nil
+// BatchConvert 批量转换已保存的文件格式（CSV<->JSONL），结果保存为新文件并记录来源文件
+// 单个文件转换失败不影响其他文件，失败原因在响应的 errors 中返回
+func (s *FileConversionService) BatchConvert(userID uint, fileIDs []uint, targetFormat string) (*dto.ConvertFilesResponse, error) {
+	if targetFormat == "" {
+		targetFormat = "csv"
+	}
+	if targetFormat != "csv" && targetFormat != "jsonl" {
+		return nil, fmt.Errorf("不支持的目标格式: %s", targetFormat)
+	}
+
+	result := &dto.ConvertFilesResponse{Files: []dto.DataFileResponse{}}
+	for _, fileID := range fileIDs {
+		converted, err := s.convertFile(userID, fileID, targetFormat)
+		if err != nil {
+			result.Errors = append(result.Errors, dto.ConvertFileError{FileID: fileID, Error: err.Error()})
+			continue
+		}
+		result.Files = append(result.Files, dto.DataFileResponse{
+			ID:            converted.ID,
+			Filename:      converted.Filename,
+			FileSize:      converted.FileSize,
+			ContentType:   converted.ContentType,
+			UserID:        converted.UserID,
+			ConvertedFrom: converted.ConvertedFromID,
+			CreatedAt:     dto.FormatTime(converted.CreatedAt),
+			UpdatedAt:     dto.FormatTime(converted.UpdatedAt),
+		})
+	}
+
+	result.Success = len(result.Files) > 0
+	result.Message = fmt.Sprintf("成功转换 %d 个文件，失败 %d 个", len(result.Files), len(result.Errors))
+	return result, nil
+}
+
+// convertFile 转换单个文件并保存为新文件
+func (s *FileConversionService) convertFile(userID, fileID uint, targetFormat string) (*models.DataFile, error) {
+	file, err := s.fileRepo.GetByIDAndUserID(fileID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("文件不存在或无权访问")
+	}
+
+	// 上传的 CSV 会被转换为 JSONL 保存，只有批量转换生成的文件内容才是 CSV
+	isCSV := strings.Contains(file.ContentType, "csv")
+	if isCSV == (targetFormat == "csv") {
+		return nil, fmt.Errorf("文件已是 %s 格式", targetFormat)
+	}
+
+	var content []byte
+	var contentType string
+	if targetFormat == "csv" {
+		content, err = utils.ConvertJSONLToCSV(file.FileContent)
+		contentType = "text/csv"
+	} else {
+		content, err = utils.ConvertCSVToJSONL(file.FileContent)
+		contentType = "application/x-jsonlines"
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ext := "." + targetFormat
+	base := strings.TrimSuffix(strings.TrimSuffix(file.Filename, ".jsonl"), ".csv")
+	sourceID := file.ID
+	converted := &models.DataFile{
+		Filename:        base + ext,
+		FileContent:     content,
+		FileSize:        len(content),
+		ContentType:     contentType,
+		UserID:          userID,
+		ConvertedFromID: &sourceID,
+	}
+	if err := s.fileRepo.Create(converted); err != nil {
+		return nil, fmt.Errorf("保存转换结果失败: %w", err)
+	}
+	return converted, nil
 }
 
 // ConvertFiles 上传并转换文件
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/utils"
+)
+
+const (
+	inspectMaxRows      = 200 // 列检测最多读取的行数
+	inspectSampleRows   = 5   // 返回的原始记录和转换预览行数
+	inspectExampleRunes = 100 // 列示例值的最大长度
+)
+
+// FileMappingService 字段映射向导
+// 先检测任意 CSV/JSONL/Excel/Parquet 文件的列，由用户指定哪些列作为 meta、哪些列作为对话轮次，再按映射转换为 meta/turns 结构保存
+type FileMappingService struct {
+	dataFileService *DataFileService
+}
+
+// NewFileMappingService 创建字段映射服务
+func NewFileMappingService(dataFileService *DataFileService) *FileMappingService {
+	return &FileMappingService{dataFileService: dataFileService}
+}
+
+// Inspect 检测文件的列、值类型和示例，返回推荐的字段映射及按映射转换的预览（mapping 为空时使用推荐映射）
+func (s *FileMappingService) Inspect(filename string, content []byte, mapping *dto.ImportColumnMapping) (*dto.FileInspectResponse, error) {
+	resp := &dto.FileInspectResponse{
+		Filename: filename,
+		Format:   recordFormat(filename),
+		Columns:  []dto.FileColumnInfo{},
+		Samples:  []map[string]interface{}{},
+		Preview:  []interface{}{},
+	}
+
+	var records []map[string]interface{}
+	err := utils.ReadRecords(filename, content, func(record map[string]interface{}) error {
+		records = append(records, record)
+		if len(records) >= inspectMaxRows {
+			return utils.ErrStopRecords
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("文件中没有记录")
+	}
+	resp.SampledRows = len(records)
+
+	// 合并各行的首个非空值，用于推荐映射（部分行缺失某些列时也能识别）
+	merged := make(map[string]interface{})
+	positions := make(map[string]int)
+	for _, record := range records {
+		for _, name := range sortedKeys(record) {
+			value := record[name]
+			pos, ok := positions[name]
+			if !ok {
+				pos = len(resp.Columns)
+				positions[name] = pos
+				resp.Columns = append(resp.Columns, dto.FileColumnInfo{Name: name})
+			}
+			if isEmptyValue(value) {
+				continue
+			}
+			column := &resp.Columns[pos]
+			column.NonEmpty++
+			column.Type = mergeValueType(column.Type, valueType(value))
+			if column.Example == "" {
+				column.Example = truncateRunes(recordExample(value), inspectExampleRunes)
+				merged[name] = value
+			}
+		}
+	}
+	for i := range resp.Columns {
+		if resp.Columns[i].Type == "" {
+			resp.Columns[i].Type = "string"
+		}
+	}
+
+	suggested := utils.SuggestColumnMapping(merged)
+	resp.SuggestedMapping = importColumnMapping(suggested)
+
+	preview := suggested
+	if mapping != nil {
+		preview = columnMapping(mapping)
+	}
+	for i, record := range records {
+		if i >= inspectSampleRows {
+			break
+		}
+		resp.Samples = append(resp.Samples, record)
+		if data, ok := utils.ConvertRecordToJSONL(record, preview); ok {
+			resp.Preview = append(resp.Preview, data)
+		}
+	}
+	return resp, nil
+}
+
+// Upload 按字段映射把文件转换为 meta/turns 结构的 JSONL 并保存为数据文件（mapping 为空时按常见字段名自动识别）
+// 映射中的列在文件中不存在，或没有任何记录包含对话内容时返回错误
+func (s *FileMappingService) Upload(userID uint, filename string, content []byte, mapping *dto.ImportColumnMapping, validationMode string) (*models.DataFile, *dto.FileValidationReport, *dto.MappedUploadResult, error) {
+	columns := columnMapping(mapping)
+	result := &dto.MappedUploadResult{}
+	seen := make(map[string]bool)
+
+	var buf bytes.Buffer
+	err := utils.ReadRecords(filename, content, func(record map[string]interface{}) error {
+		for name := range record {
+			seen[name] = true
+		}
+		data, ok := utils.ConvertRecordToJSONL(record, columns)
+		if !ok {
+			result.Skipped++
+			return nil
+		}
+		line, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+		result.Converted++
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var missing []string
+	for _, name := range mappedColumns(columns) {
+		if !seen[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, nil, fmt.Errorf("文件中不存在映射的列: %s", strings.Join(missing, ", "))
+	}
+	if result.Converted == 0 {
+		return nil, nil, nil, fmt.Errorf("文件中没有可转换的对话记录，请检查字段映射")
+	}
+
+	// 内容已转换为 JSONL，文件名统一使用 .jsonl 后缀，避免保存时再按 CSV/Excel 转换
+	filename = strings.TrimSuffix(filename, path.Ext(filename)) + ".jsonl"
+	file, report, err := s.dataFileService.SaveUploadedContent(userID, filename, buf.Bytes(), validationMode)
+	if err != nil {
+		return nil, report, nil, err
+	}
+	return file, report, result, nil
+}
+
+// recordFormat 按文件名后缀返回记录格式
+func recordFormat(filename string) string {
+	if strings.EqualFold(path.Ext(filename), ".xlsx") {
+		return "xlsx"
+	}
+	if format := utils.DetectRecordFormat(filename); format != "" {
+		return format
+	}
+	return utils.RecordFormatJSONL
+}
+
+// importColumnMapping 将转换器使用的映射转换为请求/响应中的映射
+func importColumnMapping(m utils.ColumnMapping) dto.ImportColumnMapping {
+	return dto.ImportColumnMapping{
+		Meta:       m.Meta,
+		MetaFields: m.MetaFields,
+		Human:      m.Human,
+		Assistant:  m.Assistant,
+		Turns:      m.Turns,
+		Messages:   m.Messages,
+	}
+}
+
+// mappedColumns 映射中引用的全部列
+func mappedColumns(m utils.ColumnMapping) []string {
+	var columns []string
+	for _, name := range []string{m.Meta, m.Assistant, m.Messages} {
+		if name != "" {
+			columns = append(columns, name)
+		}
+	}
+	columns = append(columns, m.MetaFields...)
+	columns = append(columns, m.Human...)
+	return append(columns, m.Turns...)
+}
+
+// sortedKeys 返回记录的列名；JSON 对象的键无序，按名称排序使检测结果稳定
+func sortedKeys(record map[string]interface{}) []string {
+	keys := make([]string, 0, len(record))
+	for key := range record {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isEmptyValue 值是否为空（nil 或空白字符串）
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	}
+	return false
+}
+
+// valueType 返回值的 JSON 类型
+func valueType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number, float32, float64, int, int32, int64, uint, uint32, uint64:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "string"
+}
+
+// mergeValueType 合并同一列在不同行中的值类型
+func mergeValueType(current, next string) string {
+	if current == "" || current == next {
+		return next
+	}
+	return "mixed"
+}
+
+// recordExample 将示例值转换为文本
+func recordExample(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+	"gen-go/internal/utils"
+)
+
+// 校验模式
+const (
+	ValidationModeNone       = "none"
+	ValidationModeReport     = "report"
+	ValidationModeReject     = "reject"
+	ValidationModeQuarantine = "quarantine"
+)
+
+// ErrValidationRejected 上传内容未通过结构校验（reject 模式）
+var ErrValidationRejected = errors.New("文件包含不符合 meta/turns 结构的行，已拒绝上传")
+
+// FileValidationService 数据文件结构校验服务
+type FileValidationService struct {
+	fileRepo       *repository.DataFileRepository
+	validationRepo *repository.FileValidationRepository
+	versionService *FileVersionService
+	jobPool        *JobPool
+	cfg            *config.Config
+}
+
+// NewFileValidationService 创建数据文件结构校验服务
+func NewFileValidationService(fileRepo *repository.DataFileRepository, validationRepo *repository.FileValidationRepository, versionService *FileVersionService, jobPool *JobPool, cfg *config.Config) *FileValidationService {
+	return &FileValidationService{
+		fileRepo:       fileRepo,
+		validationRepo: validationRepo,
+		versionService: versionService,
+		jobPool:        jobPool,
+		cfg:            cfg,
+	}
+}
+
+// DefaultMode 上传时默认使用的校验模式
+func (s *FileValidationService) DefaultMode() string {
+	if IsValidMode(s.cfg.Current().Upload.ValidationMode) {
+		return s.cfg.Current().Upload.ValidationMode
+	}
+	return ValidationModeReport
+}
+
+// IsValidMode 判断校验模式是否合法
+func IsValidMode(mode string) bool {
+	switch mode {
+	case ValidationModeNone, ValidationModeReport, ValidationModeReject, ValidationModeQuarantine:
+		return true
+	}
+	return false
+}
+
+// Check 在作业池中校验内容（不落库）
+// 只有 quarantine 模式需要拆分后的有效/无效行，其余模式只做统计
+func (s *FileValidationService) Check(content []byte, mode string) (*utils.SchemaValidationResult, error) {
+	keepLines := mode == ValidationModeQuarantine
+
+	var result *utils.SchemaValidationResult
+	err := s.jobPool.Run(JobKindValidation, func() error {
+		var err error
+		result, err = utils.ValidateJSONLSchemaReader(bytes.NewReader(content), 0, keepLines)
+		return err
+	})
+	return result, err
+}
+
+// CheckFile 在作业池中流式校验本地文件（不落库），只做统计，内存占用与文件大小无关
+func (s *FileValidationService) CheckFile(path string) (*utils.SchemaValidationResult, error) {
+	var result *utils.SchemaValidationResult
+	err := s.jobPool.Run(JobKindValidation, func() error {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("读取文件失败: %w", err)
+		}
+		defer f.Close()
+		result, err = utils.ValidateJSONLSchemaReader(f, 0, false)
+		return err
+	})
+	return result, err
+}
+
+// SplitFile 在作业池中流式校验本地文件，有效行和无效行分别写入 validPath、invalidPath（quarantine 模式使用）
+func (s *FileValidationService) SplitFile(path, validPath, invalidPath string) (*utils.SchemaValidationResult, error) {
+	var result *utils.SchemaValidationResult
+	err := s.jobPool.Run(JobKindValidation, func() error {
+		in, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("读取文件失败: %w", err)
+		}
+		defer in.Close()
+
+		valid, err := os.Create(validPath)
+		if err != nil {
+			return fmt.Errorf("创建临时文件失败: %w", err)
+		}
+		defer valid.Close()
+		invalid, err := os.Create(invalidPath)
+		if err != nil {
+			return fmt.Errorf("创建临时文件失败: %w", err)
+		}
+		defer invalid.Close()
+
+		validBuf, invalidBuf := bufio.NewWriter(valid), bufio.NewWriter(invalid)
+		report, err := utils.SplitJSONLSchemaReader(in, 0, validBuf, invalidBuf)
+		if err != nil {
+			return err
+		}
+		if err := validBuf.Flush(); err != nil {
+			return fmt.Errorf("写入临时文件失败: %w", err)
+		}
+		if err := invalidBuf.Flush(); err != nil {
+			return fmt.Errorf("写入临时文件失败: %w", err)
+		}
+		result = &utils.SchemaValidationResult{Report: report}
+		return nil
+	})
+	return result, err
+}
+
+// ValidateFile 校验已存储的文件；quarantine 模式下会将无效行移入隔离文件
+func (s *FileValidationService) ValidateFile(fileID uint, userID uint, mode string) (*dto.FileValidationReport, error) {
+	if mode == "" {
+		mode = ValidationModeReport
+	}
+	if mode != ValidationModeReport && mode != ValidationModeQuarantine {
+		return nil, fmt.Errorf("不支持的校验模式: %s", mode)
+	}
+
+	// 文件读取与校验都在作业池中执行，限制同时驻留内存的大文件数量
+	var report *dto.FileValidationReport
+	err := s.jobPool.Run(JobKindValidation, func() error {
+		file, err := s.fileRepo.GetEditableByIDAndUserID(fileID, userID)
+		if err != nil {
+			return fmt.Errorf("文件不存在或无权访问")
+		}
+
+		// 仅生成报告时无需保留行内容
+		result, err := utils.ValidateJSONLSchemaReader(bytes.NewReader(file.FileContent), 0, mode == ValidationModeQuarantine)
+		if err != nil {
+			return err
+		}
+
+		if mode == ValidationModeQuarantine && result.Report.InvalidLines > 0 {
+			previous := file.FileContent
+			file.FileContent = joinJSONLLines(result.ValidLines)
+			file.StoragePath = "" // 新内容写回数据库，原存储文件仍由历史版本引用
+			file.FileSize = len(file.FileContent)
+			if err := s.fileRepo.Update(file); err != nil {
+				return fmt.Errorf("更新文件失败: %w", err)
+			}
+			if err := s.versionService.Record(file, userID, models.FileVersionActionQuarantine, previous); err != nil {
+				return err
+			}
+		}
+
+		report, err = s.Record(file, mode, result)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// GetLatestReport 获取文件最近一次校验报告
+func (s *FileValidationService) GetLatestReport(fileID uint, userID uint) (*dto.FileValidationReport, error) {
+	if _, err := s.fileRepo.GetByIDAndUserID(fileID, userID); err != nil {
+		return nil, fmt.Errorf("文件不存在或无权访问")
+	}
+
+	validation, err := s.validationRepo.GetLatestByFileID(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("该文件尚未校验")
+	}
+
+	return toValidationReport(validation), nil
+}
+
+// DeleteRecords 删除文件的校验记录（文件删除时调用）
+func (s *FileValidationService) DeleteRecords(fileID uint) {
+	if err := s.validationRepo.DeleteByFileID(fileID); err != nil {
+		log.Printf("[FileValidation] 删除文件 %d 的校验记录失败: %v", fileID, err)
+	}
+}
+
+// Record 保存校验结果；quarantine 模式下把无效行保存为单独的隔离文件
+func (s *FileValidationService) Record(file *models.DataFile, mode string, result *utils.SchemaValidationResult) (*dto.FileValidationReport, error) {
+	var quarantineFile *models.DataFile
+	if mode == ValidationModeQuarantine && len(result.InvalidLines) > 0 {
+		content := joinJSONLLines(result.InvalidLines)
+		quarantineFile = &models.DataFile{
+			Filename:    quarantineFilename(file.Filename),
+			FileContent: content,
+			FileSize:    len(content),
+			ContentType: "application/x-jsonlines",
+			UserID:      file.UserID,
+		}
+		if err := s.fileRepo.Create(quarantineFile); err != nil {
+			return nil, fmt.Errorf("保存隔离文件失败: %w", err)
+		}
+	}
+	return s.record(file, mode, result, quarantineFile)
+}
+
+// RecordStored 保存流式校验（SplitFile）的结果，quarantinePath 为无效行所在的本地文件，移入文件存储后作为隔离文件
+func (s *FileValidationService) RecordStored(file *models.DataFile, mode string, result *utils.SchemaValidationResult, quarantinePath string) (*dto.FileValidationReport, error) {
+	var quarantineFile *models.DataFile
+	if mode == ValidationModeQuarantine && result.Report.InvalidLines > 0 {
+		quarantineFile = &models.DataFile{
+			Filename:    quarantineFilename(file.Filename),
+			ContentType: "application/x-jsonlines",
+			UserID:      file.UserID,
+		}
+		if err := createStoredFile(s.fileRepo, quarantineFile, quarantinePath); err != nil {
+			return nil, fmt.Errorf("保存隔离文件失败: %w", err)
+		}
+	}
+	return s.record(file, mode, result, quarantineFile)
+}
+
+// record 保存校验记录，quarantineFile 为已保存的隔离文件（没有时为 nil）
+func (s *FileValidationService) record(file *models.DataFile, mode string, result *utils.SchemaValidationResult, quarantineFile *models.DataFile) (*dto.FileValidationReport, error) {
+	report := result.Report
+
+	validation := &models.FileValidation{
+		FileID:       file.ID,
+		UserID:       file.UserID,
+		Mode:         mode,
+		IsValid:      report.InvalidLines == 0,
+		TotalLines:   report.TotalLines,
+		ValidLines:   report.ValidLines,
+		InvalidLines: report.InvalidLines,
+		Report: models.JSONMap{
+			"error_counts": report.ErrorCounts,
+			"errors":       report.Errors,
+			"truncated":    report.Truncated,
+		},
+	}
+
+	if quarantineFile != nil {
+		validation.QuarantineFileID = &quarantineFile.ID
+		log.Printf("[FileValidation] 文件 %d 的 %d 行无效数据已隔离到文件 %d", file.ID, report.InvalidLines, quarantineFile.ID)
+	}
+
+	if err := s.validationRepo.Create(validation); err != nil {
+		return nil, fmt.Errorf("保存校验记录失败: %w", err)
+	}
+
+	resp := toValidationReport(validation)
+	// 直接使用内存中的报告，避免JSON往返
+	resp.ErrorCounts = report.ErrorCounts
+	resp.Errors = toValidationIssues(report.Errors)
+	resp.Truncated = report.Truncated
+	return resp, nil
+}
+
+// BuildReport 将未落库的校验结果转换为报告（用于 reject 模式返回给客户端）
+func BuildReport(mode string, result *utils.SchemaValidationResult) *dto.FileValidationReport {
+	report := result.Report
+	return &dto.FileValidationReport{
+		Mode:         mode,
+		IsValid:      report.InvalidLines == 0,
+		TotalLines:   report.TotalLines,
+		ValidLines:   report.ValidLines,
+		InvalidLines: report.InvalidLines,
+		ErrorCounts:  report.ErrorCounts,
+		Errors:       toValidationIssues(report.Errors),
+		Truncated:    report.Truncated,
+	}
+}
+
+// toValidationReport 将校验记录转换为报告
+func toValidationReport(validation *models.FileValidation) *dto.FileValidationReport {
+	resp := &dto.FileValidationReport{
+		FileID:           validation.FileID,
+		Mode:             validation.Mode,
+		IsValid:          validation.IsValid,
+		TotalLines:       validation.TotalLines,
+		ValidLines:       validation.ValidLines,
+		InvalidLines:     validation.InvalidLines,
+		ErrorCounts:      map[string]int{},
+		Errors:           []dto.ValidationIssue{},
+		QuarantineFileID: validation.QuarantineFileID,
+		CheckedAt:        dto.FormatTime(validation.CreatedAt),
+	}
+
+	// Report 从数据库读出时为通用JSON结构，通过JSON往返还原
+	if raw, err := json.Marshal(validation.Report); err == nil {
+		var detail struct {
+			ErrorCounts map[string]int        `json:"error_counts"`
+			Errors      []dto.ValidationIssue `json:"errors"`
+			Truncated   bool                  `json:"truncated"`
+		}
+		if json.Unmarshal(raw, &detail) == nil {
+			if detail.ErrorCounts != nil {
+				resp.ErrorCounts = detail.ErrorCounts
+			}
+			if detail.Errors != nil {
+				resp.Errors = detail.Errors
+			}
+			resp.Truncated = detail.Truncated
+		}
+	}
+
+	return resp
+}
+
+// toValidationIssues 转换校验问题列表
+func toValidationIssues(issues []utils.SchemaIssue) []dto.ValidationIssue {
+	result := make([]dto.ValidationIssue, len(issues))
+	for i, issue := range issues {
+		result[i] = dto.ValidationIssue{
+			Line:    issue.Line,
+			Type:    issue.Type,
+			Message: issue.Message,
+		}
+	}
+	return result
+}
+
+// joinJSONLLines 将多行拼接为JSONL内容
+func joinJSONLLines(lines [][]byte) []byte {
+	if len(lines) == 0 {
+		return []byte{}
+	}
+	content := bytes.Join(lines, []byte("\n"))
+	return append(content, '\n')
+}
+
+// quarantineFilename 生成隔离文件名
+func quarantineFilename(filename string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(filename, ".jsonl"), ".csv")
+	return base + ".quarantine.jsonl"
+}
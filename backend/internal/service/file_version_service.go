@@ -0,0 +1,272 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+	"gen-go/internal/utils"
+)
+
+// FileVersionService 数据文件版本服务
+// 文件内容的每次变更都保存为不可修改的版本，可查看历史、恢复或在任务中引用指定版本
+type FileVersionService struct {
+	versionRepo *repository.DataFileVersionRepository
+	fileRepo    *repository.DataFileRepository
+}
+
+// NewFileVersionService 创建数据文件版本服务
+func NewFileVersionService(versionRepo *repository.DataFileVersionRepository, fileRepo *repository.DataFileRepository) *FileVersionService {
+	return &FileVersionService{
+		versionRepo: versionRepo,
+		fileRepo:    fileRepo,
+	}
+}
+
+// Record 在文件内容保存后记录新版本，previous 为变更前的内容（新建文件时为 nil）
+// 启用版本管理前上传的文件第一次变更时，先把变更前的内容保存为初始版本
+func (s *FileVersionService) Record(file *models.DataFile, userID uint, action string, previous []byte) error {
+	_, err := s.record(file, userID, action, previous, nil)
+	return err
+}
+
+func (s *FileVersionService) record(file *models.DataFile, userID uint, action string, previous []byte, restoredFrom *int) (*models.DataFileVersion, error) {
+	if previous != nil && file.CurrentVersion == 0 {
+		count, err := s.versionRepo.CountByFileID(file.ID)
+		if err != nil {
+			return nil, fmt.Errorf("查询文件版本失败: %w", err)
+		}
+		if count == 0 {
+			lineCount := utils.CountJSONLLines(previous)
+			initial := &models.DataFileVersion{
+				FileID:    file.ID,
+				Content:   previous,
+				FileSize:  len(previous),
+				LineCount: lineCount,
+				Action:    models.FileVersionActionInitial,
+				Summary:   fmt.Sprintf("启用版本管理前的内容，共 %d 行", lineCount),
+				UserID:    file.UserID,
+			}
+			if err := s.versionRepo.Create(initial); err != nil {
+				return nil, fmt.Errorf("保存初始版本失败: %w", err)
+			}
+		}
+	}
+
+	lineCount, err := countContentLines(file)
+	if err != nil {
+		return nil, fmt.Errorf("统计文件行数失败: %w", err)
+	}
+	version := &models.DataFileVersion{
+		FileID:       file.ID,
+		Content:      file.FileContent,
+		StoragePath:  file.StoragePath,
+		FileSize:     file.FileSize,
+		LineCount:    lineCount,
+		Action:       action,
+		RestoredFrom: restoredFrom,
+		UserID:       userID,
+	}
+	if previous == nil {
+		version.AddedLines = version.LineCount
+		version.Summary = fmt.Sprintf("上传，共 %d 行", version.LineCount)
+	} else {
+		version.AddedLines, version.RemovedLines, version.ModifiedLines = diffJSONLLines(previous, file.FileContent)
+		version.Summary = fmt.Sprintf("新增 %d 行，删除 %d 行，修改 %d 行", version.AddedLines, version.RemovedLines, version.ModifiedLines)
+		if restoredFrom != nil {
+			version.Summary = fmt.Sprintf("恢复到版本 %d：%s", *restoredFrom, version.Summary)
+		}
+	}
+
+	if err := s.versionRepo.Create(version); err != nil {
+		return nil, fmt.Errorf("保存文件版本失败: %w", err)
+	}
+	file.CurrentVersion = version.Version
+	return version, nil
+}
+
+// ListVersions 获取文件的版本历史
+func (s *FileVersionService) ListVersions(fileID uint, userID uint) (*dto.DataFileVersionListResponse, error) {
+	file, err := s.fileRepo.GetByIDAndUserID(fileID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("文件不存在或无权访问")
+	}
+
+	versions, err := s.versionRepo.ListByFileID(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("获取版本历史失败: %w", err)
+	}
+
+	result := &dto.DataFileVersionListResponse{
+		FileID:         fileID,
+		CurrentVersion: file.CurrentVersion,
+		Versions:       make([]dto.DataFileVersionResponse, len(versions)),
+	}
+	for i := range versions {
+		result.Versions[i] = toDataFileVersionResponse(&versions[i], file.CurrentVersion)
+	}
+	return result, nil
+}
+
+// GetVersionContent 获取指定版本的内容和下载文件名
+func (s *FileVersionService) GetVersionContent(fileID uint, userID uint, version int) ([]byte, string, error) {
+	file, err := s.fileRepo.GetByIDAndUserID(fileID, userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("文件不存在或无权访问")
+	}
+
+	v, err := s.versionRepo.GetByFileIDAndVersion(fileID, version)
+	if err != nil {
+		return nil, "", fmt.Errorf("版本 %d 不存在", version)
+	}
+	return v.Content, versionedFilename(file.Filename, version), nil
+}
+
+// Restore 将文件恢复到指定版本（恢复本身也会生成新版本，不会删除历史）
+func (s *FileVersionService) Restore(fileID uint, userID uint, version int) (*dto.DataFileVersionResponse, error) {
+	file, err := s.fileRepo.GetEditableByIDAndUserID(fileID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("文件不存在或无权访问")
+	}
+	if version == file.CurrentVersion {
+		return nil, fmt.Errorf("版本 %d 已是当前版本", version)
+	}
+
+	target, err := s.versionRepo.GetByFileIDAndVersion(fileID, version)
+	if err != nil {
+		return nil, fmt.Errorf("版本 %d 不存在", version)
+	}
+
+	previous := file.FileContent
+	file.FileContent = target.Content
+	file.FileSize = len(target.Content)
+	file.StoragePath = target.StoragePath // 目标版本在文件存储中时直接引用同一存储文件
+	if err := s.fileRepo.Update(file); err != nil {
+		return nil, fmt.Errorf("恢复文件内容失败: %w", err)
+	}
+
+	restored, err := s.record(file, userID, models.FileVersionActionRestore, previous, &version)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[FileVersion] 文件 %d 已恢复到版本 %d（新版本 %d）", fileID, version, restored.Version)
+
+	resp := toDataFileVersionResponse(restored, file.CurrentVersion)
+	return &resp, nil
+}
+
+// ResolveForTask 获取任务使用的文件：未指定版本或指定当前版本时直接使用原文件；
+// 指定历史版本时，以该版本内容生成一个快照文件（converted_from 指向原文件，归属原文件的工作区）供工作进程读取
+func (s *FileVersionService) ResolveForTask(file *models.DataFile, version int) (*models.DataFile, error) {
+	if version == 0 || version == file.CurrentVersion {
+		return file, nil
+	}
+
+	v, err := s.versionRepo.GetByFileIDAndVersion(file.ID, version)
+	if err != nil {
+		return nil, fmt.Errorf("文件版本 %d 不存在", version)
+	}
+
+	sourceID := file.ID
+	snapshot := &models.DataFile{
+		Filename:        versionedFilename(file.Filename, version),
+		FileContent:     v.Content,
+		StoragePath:     v.StoragePath,
+		FileSize:        len(v.Content),
+		ContentType:     "application/x-jsonlines",
+		UserID:          file.UserID,
+		WorkspaceID:     file.WorkspaceID,
+		ConvertedFromID: &sourceID,
+		Snapshot:        true,
+	}
+	if err := s.fileRepo.Create(snapshot); err != nil {
+		return nil, fmt.Errorf("生成版本快照失败: %w", err)
+	}
+	log.Printf("[FileVersion] 文件 %d 的版本 %d 已生成快照文件 %d", file.ID, version, snapshot.ID)
+	return snapshot, nil
+}
+
+// countContentLines 统计文件内容的非空行数，内容只在文件存储中时流式读取
+func countContentLines(file *models.DataFile) (int, error) {
+	if file.StoragePath == "" || len(file.FileContent) > 0 {
+		return utils.CountJSONLLines(file.FileContent), nil
+	}
+	r, err := file.OpenContent()
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	count := 0
+	err = utils.ScanJSONLLines(r, func(int, []byte) error {
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// versionedFilename 生成带版本号的文件名，例如 data@v3.jsonl
+func versionedFilename(filename string, version int) string {
+	ext := filepath.Ext(filename)
+	return fmt.Sprintf("%s@v%d%s", strings.TrimSuffix(filename, ext), version, ext)
+}
+
+// diffJSONLLines 统计两个版本之间的行变化：去掉首尾相同的行后，
+// 中间部分按位置对应的记为修改，多出的记为新增或删除
+func diffJSONLLines(oldContent, newContent []byte) (added, removed, modified int) {
+	oldLines := splitNonEmptyLines(oldContent)
+	newLines := splitNonEmptyLines(newContent)
+
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && bytes.Equal(oldLines[prefix], newLines[prefix]) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		bytes.Equal(oldLines[len(oldLines)-1-suffix], newLines[len(newLines)-1-suffix]) {
+		suffix++
+	}
+
+	oldChanged := len(oldLines) - prefix - suffix
+	newChanged := len(newLines) - prefix - suffix
+	modified = oldChanged
+	if newChanged < modified {
+		modified = newChanged
+	}
+	return newChanged - modified, oldChanged - modified, modified
+}
+
+// splitNonEmptyLines 按行拆分内容，忽略空行
+func splitNonEmptyLines(content []byte) [][]byte {
+	var lines [][]byte
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// toDataFileVersionResponse 转换文件版本响应
+func toDataFileVersionResponse(v *models.DataFileVersion, currentVersion int) dto.DataFileVersionResponse {
+	return dto.DataFileVersionResponse{
+		ID:            v.ID,
+		FileID:        v.FileID,
+		Version:       v.Version,
+		FileSize:      v.FileSize,
+		LineCount:     v.LineCount,
+		Action:        v.Action,
+		AddedLines:    v.AddedLines,
+		RemovedLines:  v.RemovedLines,
+		ModifiedLines: v.ModifiedLines,
+		Summary:       v.Summary,
+		RestoredFrom:  v.RestoredFrom,
+		IsCurrent:     v.Version == currentVersion,
+		CreatedAt:     dto.FormatTime(v.CreatedAt),
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestResolveForTaskSnapshotIsHidden(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_loc=UTC&_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.DataFile{}, &models.DataFileVersion{}, &models.WorkspaceMember{},
+		&models.Task{}, &models.GeneratedData{}, &models.WebhookDelivery{}, &models.ScheduleRun{}); err != nil {
+		t.Fatal(err)
+	}
+
+	fileRepo := repository.NewDataFileRepository(db)
+	versions := NewFileVersionService(repository.NewDataFileVersionRepository(db), fileRepo)
+
+	workspaceID := uint(7)
+	v1 := []byte("{\"a\":1}\n")
+	file := &models.DataFile{Filename: "data.jsonl", FileContent: v1, FileSize: len(v1), UserID: 1, WorkspaceID: &workspaceID}
+	if err := fileRepo.Create(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := versions.Record(file, 1, models.FileVersionActionUpload, nil); err != nil {
+		t.Fatal(err)
+	}
+	v2 := []byte("{\"a\":1}\n{\"a\":2}\n")
+	file.FileContent, file.FileSize = v2, len(v2)
+	if err := fileRepo.Update(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := versions.Record(file, 1, models.FileVersionActionAdd, v1); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := versions.ResolveForTask(file, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.ID == file.ID || !snapshot.Snapshot {
+		t.Fatalf("ResolveForTask(v1) = file %d (snapshot %v), want a new snapshot file", snapshot.ID, snapshot.Snapshot)
+	}
+	if snapshot.WorkspaceID == nil || *snapshot.WorkspaceID != workspaceID {
+		t.Errorf("snapshot workspace = %v, want %d", snapshot.WorkspaceID, workspaceID)
+	}
+	if string(snapshot.FileContent) != string(v1) {
+		t.Errorf("snapshot content = %q, want %q", snapshot.FileContent, v1)
+	}
+
+	files, total, err := fileRepo.ListByUserID(1, &repository.DataFileFilter{}, 0, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(files) != 1 || files[0].ID != file.ID {
+		t.Errorf("ListByUserID returned %d files (total %d), want only the source file", len(files), total)
+	}
+
+	usage, err := repository.NewStorageRepository(db).UsageByUserID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.FileCount != 1 || usage.FileBytes != int64(len(v2)) {
+		t.Errorf("usage = %d files / %d bytes, want 1 / %d", usage.FileCount, usage.FileBytes, len(v2))
+	}
+}
@@ -1,53 +1,78 @@
 package service
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
 
 	"gen-go/internal/dto"
 	"gen-go/internal/models"
+	"gen-go/internal/plugin"
 	"gen-go/internal/repository"
 	"gen-go/internal/utils"
 )
 
+// ErrTaskNotAccessible 任务不存在，或用户不是任务所有者且在任务所属工作区没有相应权限
+var ErrTaskNotAccessible = errors.New("任务不存在或无权访问")
+
 // GeneratedDataService 生成数据服务
+// 按任务读取数据需要任务的查看权限，修改数据需要审核权限，新增和删除数据需要操作权限（任务所有者拥有全部权限）
 type GeneratedDataService struct {
 	generatedDataRepo *repository.GeneratedDataRepository
+	taskRepo          *repository.TaskRepository
+	reviewService     *ReviewService
 }
 
 // NewGeneratedDataService 创建生成数据服务
-func NewGeneratedDataService(generatedDataRepo *repository.GeneratedDataRepository) *GeneratedDataService {
+func NewGeneratedDataService(generatedDataRepo *repository.GeneratedDataRepository, taskRepo *repository.TaskRepository, reviewService *ReviewService) *GeneratedDataService {
 	return &GeneratedDataService{
 		generatedDataRepo: generatedDataRepo,
+		taskRepo:          taskRepo,
+		reviewService:     reviewService,
+	}
+}
+
+// checkTask 检查用户能否按 permission 访问任务
+func (s *GeneratedDataService) checkTask(taskID string, userID uint, permission models.Permission) error {
+	if _, err := s.taskRepo.GetAccessibleByTaskID(taskID, userID, permission); err != nil {
+		return ErrTaskNotAccessible
+	}
+	return nil
+}
+
+// checkData 检查用户能否按 permission 访问这些数据所属的全部任务（不存在的数据忽略）
+func (s *GeneratedDataService) checkData(ids []uint, userID uint, permission models.Permission) error {
+	taskIDs, err := s.generatedDataRepo.ListTaskIDsByIDs(ids)
+	if err != nil {
+		return fmt.Errorf("查询数据所属任务失败: %w", err)
+	}
+	for _, taskID := range taskIDs {
+		if err := s.checkTask(taskID, userID, permission); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-// ListData 获取生成数据列表
-func (s *GeneratedDataService) ListData(taskID string, userID uint, page, perPage int) (*dto.PaginatedResponse, error) {
+// ListData 获取生成数据列表（filter 为 nil 时不过滤）
+func (s *GeneratedDataService) ListData(taskID string, userID uint, filter *dto.DataFilter, page, perPage int) (*dto.PaginatedResponse, error) {
+	if err := s.checkTask(taskID, userID, models.PermissionView); err != nil {
+		return nil, err
+	}
+
 	offset := (page - 1) * perPage
-	dataList, total, err := s.generatedDataRepo.ListByTaskID(taskID, offset, perPage)
+	dataList, total, err := s.listByFilter(taskID, filter, false, offset, perPage)
 	if err != nil {
 		return nil, err
 	}
 
 	responses := make([]dto.GeneratedDataResponse, len(dataList))
-	for i, data := range dataList {
-		var dataContent map[string]interface{}
-		json.Unmarshal([]byte(data.DataContent), &dataContent)
-
-		responses[i] = dto.GeneratedDataResponse{
-			ID:              data.ID,
-			TaskID:          data.TaskID,
-			UserID:          data.UserID,
-			DataContent:     data.DataContent,
-			ModelScore:      data.ModelScore,
-			RuleScore:       data.RuleScore,
-			RetryCount:      data.RetryCount,
-			GenerationModel: data.GenerationModel,
-			TaskType:        data.TaskType,
-			IsConfirmed:     data.IsConfirmed,
-			CreatedAt:       data.CreatedAt.Format("2006-01-02 15:04:05"),
-			UpdatedAt:       data.UpdatedAt.Format("2006-01-02 15:04:05"),
-		}
+	for i := range dataList {
+		responses[i] = toGeneratedDataResponse(&dataList[i])
 	}
 
 	return &dto.PaginatedResponse{
@@ -58,8 +83,43 @@ func (s *GeneratedDataService) ListData(taskID string, userID uint, page, perPag
 	}, nil
 }
 
-// BatchUpdate 批量更新数据
-func (s *GeneratedDataService) BatchUpdate(updates []dto.UpdateGeneratedDataRequest) error {
+// toGeneratedDataResponse 转换生成数据响应
+func toGeneratedDataResponse(data *models.GeneratedData) dto.GeneratedDataResponse {
+	return dto.GeneratedDataResponse{
+		ID:              data.ID,
+		TaskID:          data.TaskID,
+		UserID:          data.UserID,
+		DataContent:     data.DataContent,
+		ModelScore:      data.ModelScore,
+		RuleScore:       data.RuleScore,
+		RuleFailures:    parseRuleFailures(data.RuleFailures),
+		RetryCount:      data.RetryCount,
+		GenerationModel: data.GenerationModel,
+		TaskType:        data.TaskType,
+		IsConfirmed:     data.IsConfirmed,
+		Tags:            data.TagList(),
+		JudgeFeedback:   data.JudgeFeedback,
+		SafetyFlags:     data.SafetyFlagList(),
+		Similarity:      data.Similarity,
+		ReviewStatus:    data.ReviewStatus,
+		ReviewerID:      data.ReviewerID,
+		ReviewComment:   data.ReviewComment,
+		ReviewedAt:      dto.FormatTimePtr(data.ReviewedAt),
+		CreatedAt:       dto.FormatTime(data.CreatedAt),
+		UpdatedAt:       dto.FormatTime(data.UpdatedAt),
+	}
+}
+
+// BatchUpdate 批量更新数据（需要数据所属任务的审核权限）
+func (s *GeneratedDataService) BatchUpdate(userID uint, updates []dto.UpdateGeneratedDataRequest) error {
+	ids := make([]uint, len(updates))
+	for i, update := range updates {
+		ids[i] = update.ID
+	}
+	if err := s.checkData(ids, userID, models.PermissionReview); err != nil {
+		return err
+	}
+
 	for _, update := range updates {
 		data, err := s.generatedDataRepo.GetByID(update.ID)
 		if err != nil {
@@ -88,65 +148,192 @@ func (s *GeneratedDataService) BatchUpdate(updates []dto.UpdateGeneratedDataRequ
 	return nil
 }
 
-// ConfirmData 确认或取消确认单条数据
-func (s *GeneratedDataService) ConfirmData(dataID uint, isConfirmed bool) error {
+// ConfirmData 确认或取消确认单条数据（需要数据所属任务的审核权限）
+func (s *GeneratedDataService) ConfirmData(dataID uint, userID uint, isConfirmed bool) error {
 	data, err := s.generatedDataRepo.GetByID(dataID)
 	if err != nil {
 		return err
 	}
+	if err := s.checkTask(data.TaskID, userID, models.PermissionReview); err != nil {
+		return err
+	}
 
 	data.IsConfirmed = isConfirmed
 	return s.generatedDataRepo.Update(data)
 }
 
-// BatchConfirm 批量确认数据
-func (s *GeneratedDataService) BatchConfirm(ids []uint) error {
+// BatchConfirm 批量确认数据（需要数据所属任务的审核权限）
+func (s *GeneratedDataService) BatchConfirm(ids []uint, userID uint) error {
+	if err := s.checkData(ids, userID, models.PermissionReview); err != nil {
+		return err
+	}
 	return s.generatedDataRepo.ConfirmBatch(ids)
 }
 
-// ExportData 导出数据
-func (s *GeneratedDataService) ExportData(taskID string, format string) ([]byte, string, error) {
+// ExportData 导出用户可查看的任务的数据，其余同 ExportTaskData
+func (s *GeneratedDataService) ExportData(taskID string, userID uint, format string, filter *dto.DataFilter, loc *time.Location) ([]byte, string, int, error) {
+	if err := s.checkTask(taskID, userID, models.PermissionView); err != nil {
+		return nil, "", 0, err
+	}
+	return s.ExportTaskData(taskID, format, filter, loc)
+}
+
+// ExportTaskData 导出数据（同时返回导出行数，用于导出审计），不检查任务权限（管理员接口使用）
+// 文件名中的时间戳按 loc（用户展示时区）生成；filter 指定配额时按标签配额截取数据
+// 内容安全检查标记的数据默认不导出，filter.IncludeFlagged 为 true 时包含
+func (s *GeneratedDataService) ExportTaskData(taskID string, format string, filter *dto.DataFilter, loc *time.Location) ([]byte, string, int, error) {
 	offset := 0
 	limit := 100000 // 大批量
-	dataList, _, err := s.generatedDataRepo.ListByTaskID(taskID, offset, limit)
+	excludeFlagged := filter == nil || !filter.IncludeFlagged
+	dataList, _, err := s.listByFilter(taskID, filter, excludeFlagged, offset, limit)
 	if err != nil {
-		return nil, "", err
+		return nil, "", 0, err
+	}
+	if filter != nil && len(filter.Quotas) > 0 {
+		dataList = applyTagQuotas(dataList, filter.Quotas)
 	}
 
-	if format == "csv" {
-		// 将所有JSONL数据合并为一个字符串，然后使用正确的对话格式转换为CSV
-		var jsonlData []byte
-		for _, data := range dataList {
-			jsonlData = append(jsonlData, []byte(data.DataContent)...)
-			jsonlData = append(jsonlData, '\n')
+	content, ext, err := encodeExportData(dataList, format)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	filename := taskID + "_" + dto.FilenameTimestamp(time.Now(), loc) + ext
+	return content, filename, len(dataList), nil
+}
+
+// encodeExportData 按导出格式编码数据，返回内容和文件扩展名（默认JSONL）
+// 训练框架格式（sharegpt/openai_chat/alpaca/parquet）会跳过不符合 meta/turns 结构的数据；
+// 插件注册的导出格式交给插件编码
+func encodeExportData(dataList []models.GeneratedData, format string) ([]byte, string, error) {
+	if exporter, ok := plugin.Default().Exporter(format); ok {
+		return exportWithPlugin(exporter, dataList)
+	}
+
+	// 先拼接为一份 JSONL，各格式在其上逐行扫描转换
+	jsonlData := joinGeneratedData(dataList)
+
+	if utils.IsTrainerFormat(format) {
+		conversion, err := utils.ConvertJSONLToTrainerFormat(jsonlData, format)
+		if err != nil {
+			return nil, "", err
+		}
+		if conversion.Skipped > 0 {
+			log.Printf("[Export] 导出 %s 格式时跳过 %d 条不合格数据（首个原因: %s）", format, conversion.Skipped, conversion.Issues[0].Message)
 		}
+		return conversion.Content, conversion.Ext, nil
+	}
 
+	if format == "csv" {
 		// 使用专门的 JSONL 到 CSV 转换方法（支持 meta、Human、Assistant 格式）
 		csvContent, err := utils.ConvertJSONLToCSV(jsonlData)
 		if err != nil {
 			return nil, "", err
 		}
-		filename := taskID + ".csv"
-		return csvContent, filename, nil
+		return csvContent, ".csv", nil
 	}
 
-	// 默认JSONL
-	var result []byte
+	return jsonlData, ".jsonl", nil
+}
+
+// joinGeneratedData 将生成数据拼接为 JSONL（预先分配容量，避免逐条追加时反复扩容复制）
+func joinGeneratedData(dataList []models.GeneratedData) []byte {
+	size := 0
+	for _, data := range dataList {
+		size += len(data.DataContent) + 1
+	}
+	var buf bytes.Buffer
+	buf.Grow(size)
+	for _, data := range dataList {
+		buf.WriteString(data.DataContent)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// exportWithPlugin 使用导出插件编码数据（无法解析的数据跳过）
+func exportWithPlugin(exporter plugin.Exporter, dataList []models.GeneratedData) ([]byte, string, error) {
+	items := make([]map[string]interface{}, 0, len(dataList))
+	for _, data := range dataList {
+		var item map[string]interface{}
+		if err := json.Unmarshal([]byte(data.DataContent), &item); err != nil {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	content, err := exporter.Export(context.Background(), items)
+	if err != nil {
+		return nil, "", fmt.Errorf("导出插件 %s 失败: %w", exporter.Format(), err)
+	}
+	return content, exporter.Extension(), nil
+}
+
+// listByFilter 按过滤条件获取任务数据，excludeFlagged 为 true 时排除内容安全检查标记的数据
+func (s *GeneratedDataService) listByFilter(taskID string, filter *dto.DataFilter, excludeFlagged bool, offset, limit int) ([]models.GeneratedData, int64, error) {
+	if filter == nil {
+		if !excludeFlagged {
+			return s.generatedDataRepo.ListByTaskID(taskID, offset, limit)
+		}
+		filter = &dto.DataFilter{}
+	}
+	return s.generatedDataRepo.ListByTaskIDFiltered(taskID, &repository.GeneratedDataQuery{
+		Tags:              filter.Tags,
+		Confirmed:         filter.Confirmed,
+		MinModelScore:     filter.MinModelScore,
+		TaskType:          filter.TaskType,
+		CreatedFrom:       filter.DateFrom,
+		CreatedTo:         filter.DateTo,
+		ExcludeDuplicates: filter.ExcludeDuplicates,
+		ExcludeFlagged:    excludeFlagged,
+	}, offset, limit)
+}
+
+// applyTagQuotas 按标签配额截取数据
+// 每条数据计入它带有的、第一个仍有剩余额度的配额标签（按配额顺序）；不带任何配额标签或额度已满的数据不导出
+// 数据按创建时间倒序排列，因此额度优先分配给较新的数据
+func applyTagQuotas(dataList []models.GeneratedData, quotas []dto.TagQuota) []models.GeneratedData {
+	remaining := make([]int, len(quotas))
+	for i, quota := range quotas {
+		remaining[i] = quota.Limit
+	}
+
+	result := make([]models.GeneratedData, 0, len(dataList))
 	for _, data := range dataList {
-		result = append(result, []byte(data.DataContent)...)
-		result = append(result, '\n')
+		tags := make(map[string]bool)
+		for _, tag := range data.TagList() {
+			tags[tag] = true
+		}
+		for i, quota := range quotas {
+			if remaining[i] > 0 && tags[quota.Tag] {
+				remaining[i]--
+				result = append(result, data)
+				break
+			}
+		}
 	}
-	filename := taskID + ".jsonl"
-	return result, filename, nil
+	return result
 }
 
-// DeleteBatch 批量删除数据
-func (s *GeneratedDataService) DeleteBatch(ids []uint) (int64, error) {
-	return s.generatedDataRepo.DeleteByIDs(ids)
+// DeleteBatch 批量删除数据（需要数据所属任务的操作权限，顺带清理超过保留期的删除记录）
+func (s *GeneratedDataService) DeleteBatch(ids []uint, userID uint) (int64, error) {
+	if err := s.checkData(ids, userID, models.PermissionOperate); err != nil {
+		return 0, err
+	}
+
+	deleted, err := s.generatedDataRepo.DeleteByIDs(ids)
+	if err != nil {
+		return 0, err
+	}
+	s.generatedDataRepo.PruneTombstones(time.Now().Add(-dataTombstoneRetention))
+	return deleted, nil
 }
 
 // GetTaskInfo 获取任务数据信息
-func (s *GeneratedDataService) GetTaskInfo(taskID string) (map[string]interface{}, error) {
+func (s *GeneratedDataService) GetTaskInfo(taskID string, userID uint) (map[string]interface{}, error) {
+	if err := s.checkTask(taskID, userID, models.PermissionView); err != nil {
+		return nil, err
+	}
+
 	offset := 0
 	limit := 1
 	dataList, total, err := s.generatedDataRepo.ListByTaskID(taskID, offset, limit)
@@ -156,17 +343,41 @@ func (s *GeneratedDataService) GetTaskInfo(taskID string) (map[string]interface{
 
 	unconfirmed, _ := s.generatedDataRepo.GetUnconfirmedCount(taskID)
 
+	duplicates, err := s.generatedDataRepo.CountDuplicates(taskID)
+	if err != nil {
+		duplicates = &repository.DuplicateCount{}
+	}
+	duplicateCount := duplicates.SourceDuplicates + duplicates.InternalDuplicates
+
+	modelCounts, err := s.generatedDataRepo.CountByGenerationModel(taskID)
+	if err != nil {
+		modelCounts = map[string]int64{}
+	}
+
+	// 多人审核的任务附带审核一致性统计
+	agreement, _ := s.reviewService.GetAgreementByTaskID(taskID)
+
 	return map[string]interface{}{
-		"task_id":           taskID,
-		"total_count":       total,
-		"unconfirmed_count": unconfirmed,
-		"confirmed_count":   total - unconfirmed,
-		"sample":            dataList,
+		"task_id":                  taskID,
+		"total_count":              total,
+		"unconfirmed_count":        unconfirmed,
+		"confirmed_count":          total - unconfirmed,
+		"duplicate_count":          duplicateCount,
+		"source_duplicate_count":   duplicates.SourceDuplicates,
+		"internal_duplicate_count": duplicates.InternalDuplicates,
+		"unique_count":             total - duplicateCount,
+		"model_counts":             modelCounts,
+		"review_agreement":         agreement,
+		"sample":                   dataList,
 	}, nil
 }
 
-// AddData 添加单条数据
+// AddData 添加单条数据（需要任务的操作权限）
 func (s *GeneratedDataService) AddData(taskID string, userID uint, content map[string]interface{}) (uint, error) {
+	if err := s.checkTask(taskID, userID, models.PermissionOperate); err != nil {
+		return 0, err
+	}
+
 	// 将 content 转换为 JSON 字符串
 	contentJSON, err := json.Marshal(content)
 	if err != nil {
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestGeneratedDataTaskAccess(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_loc=UTC&_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.Task{}, &models.GeneratedData{}, &models.GeneratedDataTombstone{}, &models.WorkspaceMember{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 用户 1 创建工作区任务；用户 2 是 viewer，用户 3 是 reviewer，用户 4 不是成员
+	workspaceID := uint(1)
+	if err := db.Create(&models.Task{TaskID: "task-1", UserID: 1, WorkspaceID: &workspaceID, Status: models.TaskStatusFinished}).Error; err != nil {
+		t.Fatal(err)
+	}
+	members := []models.WorkspaceMember{
+		{WorkspaceID: workspaceID, UserID: 2, Role: models.RoleViewer},
+		{WorkspaceID: workspaceID, UserID: 3, Role: models.RoleReviewer},
+	}
+	if err := db.Create(&members).Error; err != nil {
+		t.Fatal(err)
+	}
+	data := models.GeneratedData{TaskID: "task-1", UserID: 1, DataContent: "{}"}
+	if err := db.Create(&data).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewGeneratedDataService(repository.NewGeneratedDataRepository(db), repository.NewTaskRepository(db), nil)
+
+	tests := []struct {
+		name    string
+		call    func() error
+		allowed bool
+	}{
+		{"owner lists", func() error { _, err := s.ListData("task-1", 1, nil, 1, 20); return err }, true},
+		{"viewer lists", func() error { _, err := s.ListData("task-1", 2, nil, 1, 20); return err }, true},
+		{"outsider lists", func() error { _, err := s.ListData("task-1", 4, nil, 1, 20); return err }, false},
+		{"outsider exports", func() error { _, _, _, err := s.ExportData("task-1", 4, "jsonl", nil, nil); return err }, false},
+		{"outsider exports stratified", func() error {
+			_, _, _, err := s.ExportStratified(&dto.StratifiedExportRequest{TaskID: "task-1", Format: "jsonl"}, 4, nil)
+			return err
+		}, false},
+		{"outsider reads changes", func() error { _, err := s.ListChanges("task-1", 4, "", 10); return err }, false},
+		{"viewer confirms", func() error { return s.BatchConfirm([]uint{data.ID}, 2) }, false},
+		{"reviewer confirms", func() error { return s.BatchConfirm([]uint{data.ID}, 3) }, true},
+		{"outsider confirms one", func() error { return s.ConfirmData(data.ID, 4, false) }, false},
+		{"reviewer deletes", func() error { _, err := s.DeleteBatch([]uint{data.ID}, 3); return err }, false},
+		{"outsider adds", func() error { _, err := s.AddData("task-1", 4, map[string]interface{}{}); return err }, false},
+	}
+	for _, tt := range tests {
+		err := tt.call()
+		if tt.allowed && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !tt.allowed && !errors.Is(err, ErrTaskNotAccessible) {
+			t.Errorf("%s: error = %v, want ErrTaskNotAccessible", tt.name, err)
+		}
+	}
+
+	var confirmed models.GeneratedData
+	if err := db.First(&confirmed, data.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !confirmed.IsConfirmed {
+		t.Error("reviewer confirmation was not applied")
+	}
+}
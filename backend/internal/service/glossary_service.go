@@ -0,0 +1,470 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+)
+
+// 术语表限制
+const (
+	maxGlossaryTerms       = 5000
+	maxGlossaryPromptTerms = 200 // 注入提示词的最大条目数，避免提示词过长
+	glossaryCheckBatchSize = 500
+)
+
+// GlossaryService 术语表服务
+// 用户上传术语表（术语 → 释义/推荐用法），启动任务时注入提示词，任务完成后可检查生成数据是否使用了推荐用法
+type GlossaryService struct {
+	glossaryRepo      *repository.GlossaryRepository
+	taskRepo          *repository.TaskRepository
+	generatedDataRepo *repository.GeneratedDataRepository
+	jobPool           *JobPool
+}
+
+// NewGlossaryService 创建术语表服务
+func NewGlossaryService(
+	glossaryRepo *repository.GlossaryRepository,
+	taskRepo *repository.TaskRepository,
+	generatedDataRepo *repository.GeneratedDataRepository,
+	jobPool *JobPool,
+) *GlossaryService {
+	return &GlossaryService{
+		glossaryRepo:      glossaryRepo,
+		taskRepo:          taskRepo,
+		generatedDataRepo: generatedDataRepo,
+		jobPool:           jobPool,
+	}
+}
+
+// CreateGlossary 解析上传的术语表文件并保存
+// 支持 CSV（表头 term,definition,preferred,variants）以及 JSON 数组/JSONL（同名字段），variants 用 | 分隔或为字符串数组
+func (s *GlossaryService) CreateGlossary(userID uint, name, description, filename string, content []byte) (*dto.GlossaryResponse, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = strings.TrimSuffix(filename, filepath.Ext(filename))
+	}
+	if len([]rune(name)) > 100 {
+		return nil, fmt.Errorf("术语表名称不能超过100个字符")
+	}
+	if len([]rune(description)) > 500 {
+		return nil, fmt.Errorf("术语表描述不能超过500个字符")
+	}
+
+	terms, err := parseGlossaryFile(filename, content)
+	if err != nil {
+		return nil, err
+	}
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("术语表中没有有效的术语")
+	}
+	if len(terms) > maxGlossaryTerms {
+		return nil, fmt.Errorf("术语表条目数不能超过 %d", maxGlossaryTerms)
+	}
+
+	glossary := &models.Glossary{
+		UserID:      userID,
+		Name:        name,
+		Description: description,
+	}
+	if err := s.glossaryRepo.Create(glossary, terms); err != nil {
+		return nil, fmt.Errorf("保存术语表失败: %w", err)
+	}
+	return toGlossaryResponse(glossary), nil
+}
+
+// ListGlossaries 获取用户的术语表
+func (s *GlossaryService) ListGlossaries(userID uint) ([]*dto.GlossaryResponse, error) {
+	glossaries, err := s.glossaryRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.GlossaryResponse, len(glossaries))
+	for i := range glossaries {
+		result[i] = toGlossaryResponse(&glossaries[i])
+	}
+	return result, nil
+}
+
+// GetGlossary 获取术语表详情（含全部条目）
+func (s *GlossaryService) GetGlossary(id uint, userID uint) (*dto.GlossaryResponse, error) {
+	glossary, err := s.glossaryRepo.GetByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("术语表不存在或无权访问")
+	}
+
+	terms, err := s.glossaryRepo.ListTerms(glossary.ID)
+	if err != nil {
+		return nil, fmt.Errorf("获取术语失败: %w", err)
+	}
+
+	resp := toGlossaryResponse(glossary)
+	resp.Terms = make([]*dto.GlossaryTermResponse, len(terms))
+	for i := range terms {
+		resp.Terms[i] = &dto.GlossaryTermResponse{
+			Term:       terms[i].Term,
+			Definition: terms[i].Definition,
+			Preferred:  terms[i].Preferred,
+			Variants:   terms[i].VariantList(),
+		}
+	}
+	return resp, nil
+}
+
+// DeleteGlossary 删除术语表
+// 已启动任务的参数中保存了注入的术语说明，删除不影响历史任务的复现
+func (s *GlossaryService) DeleteGlossary(id uint, userID uint) error {
+	if _, err := s.glossaryRepo.GetByIDAndUserID(id, userID); err != nil {
+		return fmt.Errorf("术语表不存在或无权访问")
+	}
+	return s.glossaryRepo.Delete(id)
+}
+
+// BuildPrompt 校验术语表归属并生成注入提示词的术语说明
+func (s *GlossaryService) BuildPrompt(id uint, userID uint) (string, error) {
+	if _, err := s.glossaryRepo.GetByIDAndUserID(id, userID); err != nil {
+		return "", fmt.Errorf("术语表不存在或无权访问")
+	}
+	terms, err := s.glossaryRepo.ListTerms(id)
+	if err != nil {
+		return "", fmt.Errorf("获取术语失败: %w", err)
+	}
+	return buildGlossaryPrompt(terms), nil
+}
+
+// CheckTaskForUser 校验任务和术语表归属后执行术语检查
+func (s *GlossaryService) CheckTaskForUser(taskID string, glossaryID uint, userID uint) (*dto.GlossaryCheckResponse, error) {
+	task, err := s.taskRepo.GetEditableByTaskID(taskID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("任务不存在或无权访问")
+	}
+	if task.Status == models.TaskStatusRunning {
+		return nil, fmt.Errorf("任务仍在运行，请在任务结束后检查")
+	}
+	if _, err := s.glossaryRepo.GetByIDAndUserID(glossaryID, userID); err != nil {
+		return nil, fmt.Errorf("术语表不存在或无权访问")
+	}
+	return s.CheckTask(taskID, glossaryID)
+}
+
+// CheckTask 检查任务生成数据中的术语用法
+// 数据中的任意文本出现术语的不推荐写法（忽略大小写）即记为一条违规；重复执行会覆盖上一次的结果
+func (s *GlossaryService) CheckTask(taskID string, glossaryID uint) (*dto.GlossaryCheckResponse, error) {
+	var result *dto.GlossaryCheckResponse
+	err := s.jobPool.Run(JobKindGlossaryCheck, func() error {
+		var err error
+		result, err = s.checkTask(taskID, glossaryID)
+		return err
+	})
+	return result, err
+}
+
+// checkTask 术语检查的具体实现
+func (s *GlossaryService) checkTask(taskID string, glossaryID uint) (*dto.GlossaryCheckResponse, error) {
+	terms, err := s.glossaryRepo.ListTerms(glossaryID)
+	if err != nil {
+		return nil, fmt.Errorf("获取术语失败: %w", err)
+	}
+
+	result := &dto.GlossaryCheckResponse{
+		TaskID:     taskID,
+		GlossaryID: glossaryID,
+	}
+	var violations []models.GlossaryViolation
+
+	err = s.generatedDataRepo.ScanForDedup(taskID, glossaryCheckBatchSize, func(batch []models.GeneratedData) error {
+		for _, data := range batch {
+			result.Checked++
+
+			var content interface{}
+			if err := json.Unmarshal([]byte(data.DataContent), &content); err != nil {
+				continue
+			}
+			text := strings.ToLower(strings.Join(collectTextValues(content, nil), "\n"))
+
+			found := false
+			for i := range terms {
+				for _, variant := range terms[i].VariantList() {
+					if !strings.Contains(text, strings.ToLower(variant)) {
+						continue
+					}
+					found = true
+					violations = append(violations, models.GlossaryViolation{
+						TaskID:     taskID,
+						DataID:     data.ID,
+						GlossaryID: glossaryID,
+						Term:       terms[i].Term,
+						Found:      variant,
+						Preferred:  terms[i].PreferredOrTerm(),
+					})
+				}
+			}
+			if found {
+				result.ViolatingRows++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取生成数据失败: %w", err)
+	}
+
+	if err := s.glossaryRepo.ReplaceViolations(taskID, violations); err != nil {
+		return nil, fmt.Errorf("保存术语检查结果失败: %w", err)
+	}
+	result.Violations = len(violations)
+
+	log.Printf("[Glossary] 任务 %s 术语检查完成: 检查 %d 条, 违规数据 %d 条, 违规 %d 处",
+		taskID, result.Checked, result.ViolatingRows, result.Violations)
+	return result, nil
+}
+
+// ListViolations 分页获取任务的术语违规记录
+func (s *GlossaryService) ListViolations(taskID string, userID uint, page, perPage int) (*dto.PaginatedResponse, error) {
+	if _, err := s.taskRepo.GetVisibleByTaskID(taskID, userID); err != nil {
+		return nil, fmt.Errorf("任务不存在或无权访问")
+	}
+
+	offset := (page - 1) * perPage
+	violations, total, err := s.glossaryRepo.ListViolations(taskID, offset, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.GlossaryViolationResponse, len(violations))
+	for i, v := range violations {
+		items[i] = dto.GlossaryViolationResponse{
+			DataID:    v.DataID,
+			Term:      v.Term,
+			Found:     v.Found,
+			Preferred: v.Preferred,
+		}
+	}
+
+	return &dto.PaginatedResponse{
+		Items:   items,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	}, nil
+}
+
+// buildGlossaryPrompt 生成术语说明文本（超出条目上限的部分不注入，但仍参与检查）
+func buildGlossaryPrompt(terms []models.GlossaryTerm) string {
+	if len(terms) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	builder.WriteString("【术语表】生成内容涉及以下术语时，请严格使用推荐用法：\n")
+	for i := range terms {
+		if i >= maxGlossaryPromptTerms {
+			break
+		}
+		term := &terms[i]
+		builder.WriteString("- ")
+		builder.WriteString(term.Term)
+		if term.Definition != "" {
+			builder.WriteString("：")
+			builder.WriteString(term.Definition)
+		}
+		if term.Preferred != "" && term.Preferred != term.Term {
+			builder.WriteString("；推荐用法：")
+			builder.WriteString(term.Preferred)
+		}
+		if variants := term.VariantList(); len(variants) > 0 {
+			builder.WriteString("；避免使用：")
+			builder.WriteString(strings.Join(variants, "、"))
+		}
+		builder.WriteString("\n")
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+// collectTextValues 递归收集数据中的全部字符串值（跳过 meta）
+func collectTextValues(value interface{}, texts []string) []string {
+	switch v := value.(type) {
+	case string:
+		texts = append(texts, v)
+	case []interface{}:
+		for _, item := range v {
+			texts = collectTextValues(item, texts)
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			if key == "meta" {
+				continue
+			}
+			texts = collectTextValues(item, texts)
+		}
+	}
+	return texts
+}
+
+// glossaryEntry 术语表文件中的一条记录（JSON 格式）
+type glossaryEntry struct {
+	Term       string      `json:"term"`
+	Definition string      `json:"definition"`
+	Preferred  string      `json:"preferred"`
+	Variants   interface{} `json:"variants"`
+}
+
+// parseGlossaryFile 按扩展名解析术语表文件
+func parseGlossaryFile(filename string, content []byte) ([]models.GlossaryTerm, error) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return parseGlossaryCSV(content)
+	case ".json", ".jsonl":
+		return parseGlossaryJSON(content)
+	default:
+		return nil, fmt.Errorf("不支持的术语表格式，仅支持 .csv、.json、.jsonl")
+	}
+}
+
+// parseGlossaryCSV 解析 CSV 术语表（首行为表头，必须包含 term 列）
+func parseGlossaryCSV(content []byte) ([]models.GlossaryTerm, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("读取CSV表头失败: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["term"]; !ok {
+		return nil, fmt.Errorf("CSV表头缺少 term 列")
+	}
+
+	field := func(record []string, name string) string {
+		idx, ok := columns[name]
+		if !ok || idx >= len(record) {
+			return ""
+		}
+		return record[idx]
+	}
+
+	var terms []models.GlossaryTerm
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行解析失败: %w", line, err)
+		}
+		if term, ok := newGlossaryTerm(field(record, "term"), field(record, "definition"), field(record, "preferred"), field(record, "variants")); ok {
+			terms = append(terms, term)
+		}
+	}
+	return terms, nil
+}
+
+// parseGlossaryJSON 解析 JSON 数组或 JSONL 术语表
+func parseGlossaryJSON(content []byte) ([]models.GlossaryTerm, error) {
+	var entries []glossaryEntry
+
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return nil, fmt.Errorf("解析JSON失败: %w", err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			raw := bytes.TrimSpace(scanner.Bytes())
+			if len(raw) == 0 {
+				continue
+			}
+			var entry glossaryEntry
+			if err := json.Unmarshal(raw, &entry); err != nil {
+				return nil, fmt.Errorf("第 %d 行解析失败: %w", line, err)
+			}
+			entries = append(entries, entry)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("读取文件失败: %w", err)
+		}
+	}
+
+	terms := make([]models.GlossaryTerm, 0, len(entries))
+	for _, entry := range entries {
+		var variants string
+		switch v := entry.Variants.(type) {
+		case string:
+			variants = v
+		case []interface{}:
+			parts := make([]string, 0, len(v))
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					parts = append(parts, s)
+				}
+			}
+			variants = strings.Join(parts, "|")
+		}
+		if term, ok := newGlossaryTerm(entry.Term, entry.Definition, entry.Preferred, variants); ok {
+			terms = append(terms, term)
+		}
+	}
+	return terms, nil
+}
+
+// newGlossaryTerm 规整术语字段，term 为空时忽略该条
+// 不推荐写法中去除空项、重复项以及与推荐用法相同的项
+func newGlossaryTerm(term, definition, preferred, variants string) (models.GlossaryTerm, bool) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return models.GlossaryTerm{}, false
+	}
+	preferred = strings.TrimSpace(preferred)
+	approved := preferred
+	if approved == "" {
+		approved = term
+	}
+
+	seen := make(map[string]bool)
+	parts := make([]string, 0)
+	for _, variant := range strings.Split(variants, "|") {
+		variant = strings.TrimSpace(variant)
+		key := strings.ToLower(variant)
+		if variant == "" || seen[key] || strings.EqualFold(variant, approved) {
+			continue
+		}
+		seen[key] = true
+		parts = append(parts, variant)
+	}
+
+	return models.GlossaryTerm{
+		Term:       term,
+		Definition: strings.TrimSpace(definition),
+		Preferred:  preferred,
+		Variants:   strings.Join(parts, "|"),
+	}, true
+}
+
+// toGlossaryResponse 转换术语表响应
+func toGlossaryResponse(glossary *models.Glossary) *dto.GlossaryResponse {
+	return &dto.GlossaryResponse{
+		ID:          glossary.ID,
+		Name:        glossary.Name,
+		Description: glossary.Description,
+		TermCount:   glossary.TermCount,
+		CreatedAt:   dto.FormatTime(glossary.CreatedAt),
+		UpdatedAt:   dto.FormatTime(glossary.UpdatedAt),
+	}
+}
@@ -0,0 +1,426 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// housekeepingScanCount 扫描 Redis 键时每批的数量
+	housekeepingScanCount = 500
+	// taskProgressKeyPrefix 任务实时进度的 Redis 键前缀
+	taskProgressKeyPrefix = "task_progress:"
+	// modelLimitKeyPrefix 模型并发令牌的 Redis 键前缀
+	modelLimitKeyPrefix = "model_limit:"
+	// housekeepingLeaseKey 后台清理租约的 Redis 键，多实例部署时每个周期只由取得租约的实例执行清理
+	housekeepingLeaseKey = "housekeeping:lease"
+)
+
+// modelLimitKeySuffixes 公平调度在令牌计数键之外使用的附属键后缀
+var modelLimitKeySuffixes = []string{":waiters", ":waiter_seen", ":holders", ":weights"}
+
+// HousekeepingStats 后台清理的累计统计（进程内，自启动起累计）
+type HousekeepingStats struct {
+	Enabled             bool    `json:"enabled"`
+	Runs                int64   `json:"runs"`
+	LastRunAt           *string `json:"last_run_at"`
+	LastDurationMs      int64   `json:"last_duration_ms"`
+	LastError           string  `json:"last_error,omitempty"`
+	TasksDeleted        int64   `json:"tasks_deleted"`
+	TasksArchived       int64   `json:"tasks_archived"`
+	ProgressKeysDeleted int64   `json:"progress_keys_deleted"`
+	LimitKeysDeleted    int64   `json:"limit_keys_deleted"`
+	EmbeddingsDeleted   int64   `json:"embeddings_deleted"`
+}
+
+// housekeepingResult 一次清理的结果
+type housekeepingResult struct {
+	tasksDeleted        int
+	tasksArchived       int
+	progressKeysDeleted int
+	limitKeysDeleted    int
+	embeddingsDeleted   int64
+}
+
+// HousekeepingService 后台清理服务
+// 定期删除超过保留期的已结束任务（归档后删除生成数据、任务记录和任务日志），
+// 并清理 Redis 中已结束任务残留的 task_progress:* 键、没有运行中任务使用的 model_limit:* 键，以及所属数据已删除的向量
+// 每个实例都会按周期触发，通过 Redis 租约保证同一周期只有一个实例执行
+type HousekeepingService struct {
+	taskRepo          *repository.TaskRepository
+	generatedDataRepo *repository.GeneratedDataRepository
+	embeddingRepo     *repository.EmbeddingRepository
+	taskManager       *TaskManager
+	redisClient       *redis.Client
+	cfg               *config.Config
+
+	statsLock sync.Mutex
+	stats     HousekeepingStats
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewHousekeepingService 创建后台清理服务
+func NewHousekeepingService(
+	taskRepo *repository.TaskRepository,
+	generatedDataRepo *repository.GeneratedDataRepository,
+	embeddingRepo *repository.EmbeddingRepository,
+	taskManager *TaskManager,
+	redisClient *redis.Client,
+	cfg *config.Config,
+) *HousekeepingService {
+	return &HousekeepingService{
+		taskRepo:          taskRepo,
+		generatedDataRepo: generatedDataRepo,
+		embeddingRepo:     embeddingRepo,
+		taskManager:       taskManager,
+		redisClient:       redisClient,
+		cfg:               cfg,
+		stats:             HousekeepingStats{Enabled: cfg.Retention.Enabled},
+		stopCh:            make(chan struct{}),
+	}
+}
+
+// Start 启动后台清理循环
+func (s *HousekeepingService) Start() {
+	interval := s.cfg.Current().Retention.GetInterval()
+	log.Printf("[Housekeeping] 后台清理已启动，间隔: %v，任务保留天数: %d", interval, s.cfg.Current().Retention.TaskDays)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if s.acquireLease(interval) {
+					s.RunOnce()
+				}
+			case <-s.stopCh:
+				log.Printf("[Housekeeping] 后台清理已停止")
+				return
+			}
+		}
+	}()
+}
+
+// acquireLease 取得本周期的清理租约（SETNX，过期时间略短于清理间隔，结束后不主动释放，
+// 避免其他实例在同一周期稍晚触发时重复清理）；Redis 未启用时视为单实例部署，直接执行
+// Redis 不可用时跳过本周期，避免多个实例同时清理
+func (s *HousekeepingService) acquireLease(interval time.Duration) bool {
+	if s.redisClient == nil {
+		return true
+	}
+	ttl := interval * 9 / 10
+	if ttl <= 0 {
+		ttl = interval
+	}
+	acquired, err := s.redisClient.SetNX(context.Background(), housekeepingLeaseKey, time.Now().UTC().Format(time.RFC3339), ttl).Result()
+	if err != nil {
+		log.Printf("[Housekeeping] 获取清理租约失败，跳过本次清理: %v", err)
+		return false
+	}
+	return acquired
+}
+
+// Stop 停止后台清理循环
+func (s *HousekeepingService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// Stats 获取后台清理的累计统计
+func (s *HousekeepingService) Stats() HousekeepingStats {
+	s.statsLock.Lock()
+	defer s.statsLock.Unlock()
+	return s.stats
+}
+
+// RunOnce 执行一次清理，各步骤失败只记录错误，不影响其余步骤
+func (s *HousekeepingService) RunOnce() {
+	start := time.Now()
+	var result housekeepingResult
+	var errs []string
+
+	if err := s.cleanupTasks(&result); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := s.cleanupEmbeddings(&result); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if s.redisClient != nil {
+		ctx := context.Background()
+		if err := s.cleanupProgressKeys(ctx, &result); err != nil {
+			errs = append(errs, err.Error())
+		}
+		if err := s.cleanupLimitKeys(ctx, &result); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	duration := time.Since(start)
+	log.Printf("[Housekeeping] 清理完成: 删除任务 %d 个（归档 %d 个），删除进度键 %d 个、令牌键 %d 个、向量 %d 条，耗时 %v",
+		result.tasksDeleted, result.tasksArchived, result.progressKeysDeleted, result.limitKeysDeleted, result.embeddingsDeleted, duration.Round(time.Millisecond))
+
+	s.statsLock.Lock()
+	defer s.statsLock.Unlock()
+	s.stats.Runs++
+	s.stats.LastRunAt = dto.FormatTimePtr(&start)
+	s.stats.LastDurationMs = duration.Milliseconds()
+	s.stats.LastError = strings.Join(errs, "; ")
+	s.stats.TasksDeleted += int64(result.tasksDeleted)
+	s.stats.TasksArchived += int64(result.tasksArchived)
+	s.stats.ProgressKeysDeleted += int64(result.progressKeysDeleted)
+	s.stats.LimitKeysDeleted += int64(result.limitKeysDeleted)
+	s.stats.EmbeddingsDeleted += result.embeddingsDeleted
+}
+
+// cleanupEmbeddings 删除所属数据文件或生成数据已被删除的向量（含上一步随任务删除的生成数据）
+func (s *HousekeepingService) cleanupEmbeddings(result *housekeepingResult) error {
+	deleted, err := s.embeddingRepo.DeleteOrphans()
+	if err != nil {
+		return fmt.Errorf("清理向量失败: %w", err)
+	}
+	result.embeddingsDeleted = deleted
+	return nil
+}
+
+// cleanupTasks 删除超过保留期的已结束任务
+func (s *HousekeepingService) cleanupTasks(result *housekeepingResult) error {
+	if s.cfg.Current().Retention.TaskDays <= 0 {
+		return nil
+	}
+
+	before := time.Now().UTC().AddDate(0, 0, -s.cfg.Current().Retention.TaskDays)
+	tasks, err := s.taskRepo.ListEndedBefore(before, s.cfg.Current().Retention.BatchSize)
+	if err != nil {
+		return fmt.Errorf("查询过期任务失败: %w", err)
+	}
+
+	archiveDir := s.cfg.Current().RetentionArchiveDir()
+	for i := range tasks {
+		task := &tasks[i]
+		if archiveDir != "" {
+			if err := s.archiveTask(archiveDir, task); err != nil {
+				// 归档失败时保留任务，下次清理再试
+				log.Printf("[Housekeeping] 归档任务 %s 失败: %v", task.TaskID, err)
+				continue
+			}
+			result.tasksArchived++
+		}
+
+		if err := s.generatedDataRepo.DeleteByTaskID(task.TaskID); err != nil {
+			log.Printf("[Housekeeping] 删除任务 %s 的生成数据失败: %v", task.TaskID, err)
+			continue
+		}
+		if err := s.taskRepo.DeleteByTaskID(task.TaskID); err != nil {
+			log.Printf("[Housekeeping] 删除任务 %s 失败: %v", task.TaskID, err)
+			continue
+		}
+		s.taskManager.forgetTask(task.TaskID)
+		result.tasksDeleted++
+	}
+	return nil
+}
+
+// archiveTask 将任务记录和生成数据归档到 <task_id>.task.json 和 <task_id>.jsonl
+func (s *HousekeepingService) archiveTask(dir string, task *models.Task) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("创建归档目录失败: %w", err)
+	}
+	base := filepath.Join(dir, url.PathEscape(task.TaskID))
+
+	params := make(map[string]interface{}, len(task.Params))
+	for k, v := range task.Params {
+		if k != "api_key" {
+			params[k] = v
+		}
+	}
+	meta, err := json.MarshalIndent(map[string]interface{}{
+		"task_id":       task.TaskID,
+		"user_id":       task.UserID,
+		"workspace_id":  task.WorkspaceID,
+		"status":        task.Status,
+		"params":        params,
+		"result":        task.Result,
+		"error_message": task.ErrorMessage,
+		"started_at":    dto.FormatTime(task.StartedAt),
+		"finished_at":   dto.FormatTimePtr(task.FinishedAt),
+		"archived_at":   dto.FormatTime(time.Now()),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(base+".task.json", meta, 0o644); err != nil {
+		return err
+	}
+
+	file, err := os.Create(base + ".jsonl")
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	err = s.generatedDataRepo.ScanForDedup(task.TaskID, 500, func(batch []models.GeneratedData) error {
+		for _, data := range batch {
+			writer.WriteString(data.DataContent)
+			writer.WriteByte('\n')
+		}
+		return nil
+	})
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// cleanupProgressKeys 删除已结束超过保留时间、或任务已不存在的 task_progress:* 键
+func (s *HousekeepingService) cleanupProgressKeys(ctx context.Context, result *housekeepingResult) error {
+	cutoff := time.Now().Add(-s.cfg.Current().Retention.GetProgressKeyTTL())
+
+	return s.scanKeys(ctx, taskProgressKeyPrefix+"*", func(keys []string) error {
+		taskIDs := make([]string, len(keys))
+		for i, key := range keys {
+			taskIDs[i] = strings.TrimPrefix(key, taskProgressKeyPrefix)
+		}
+		tasks, err := s.taskRepo.ListByTaskIDs(taskIDs)
+		if err != nil {
+			return fmt.Errorf("查询任务失败: %w", err)
+		}
+		byTaskID := make(map[string]*models.Task, len(tasks))
+		for i := range tasks {
+			byTaskID[tasks[i].TaskID] = &tasks[i]
+		}
+
+		var stale []string
+		for i, taskID := range taskIDs {
+			if taskCtx, exists := s.taskManager.GetTask(taskID); exists && !taskCtx.Finished() {
+				continue
+			}
+			task, exists := byTaskID[taskID]
+			if !exists || (task.Status != models.TaskStatusRunning && (task.FinishedAt == nil || task.FinishedAt.Before(cutoff))) {
+				stale = append(stale, keys[i])
+			}
+		}
+		if len(stale) == 0 {
+			return nil
+		}
+		deleted, err := s.redisClient.Del(ctx, stale...).Result()
+		if err != nil {
+			return fmt.Errorf("删除进度键失败: %w", err)
+		}
+		result.progressKeysDeleted += int(deleted)
+		return nil
+	})
+}
+
+// cleanupLimitKeys 删除没有运行中任务使用的模型的 model_limit:* 键（任务异常退出未释放的令牌）
+func (s *HousekeepingService) cleanupLimitKeys(ctx context.Context, result *housekeepingResult) error {
+	active, err := s.taskManager.activeModelPaths(ctx)
+	if err != nil {
+		return fmt.Errorf("查询运行中任务的模型失败: %w", err)
+	}
+
+	return s.scanKeys(ctx, modelLimitKeyPrefix+"*", func(keys []string) error {
+		var stale []string
+		for _, key := range keys {
+			base := key
+			for _, suffix := range modelLimitKeySuffixes {
+				if strings.HasSuffix(base, suffix) {
+					base = strings.TrimSuffix(base, suffix)
+					break
+				}
+			}
+			if !active[strings.TrimPrefix(base, modelLimitKeyPrefix)] {
+				stale = append(stale, key)
+			}
+		}
+		if len(stale) == 0 {
+			return nil
+		}
+		deleted, err := s.redisClient.Del(ctx, stale...).Result()
+		if err != nil {
+			return fmt.Errorf("删除令牌键失败: %w", err)
+		}
+		result.limitKeysDeleted += int(deleted)
+		return nil
+	})
+}
+
+// scanKeys 按模式分批扫描 Redis 键
+func (s *HousekeepingService) scanKeys(ctx context.Context, pattern string, fn func(keys []string) error) error {
+	var cursor uint64
+	for {
+		keys, next, err := s.redisClient.Scan(ctx, cursor, pattern, housekeepingScanCount).Result()
+		if err != nil {
+			return fmt.Errorf("扫描 %s 失败: %w", pattern, err)
+		}
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// activeModelPaths 获取未结束任务（含等待令牌的任务）使用的模型路径
+func (tm *TaskManager) activeModelPaths(ctx context.Context) (map[string]bool, error) {
+	paths := make(map[string]bool)
+	for _, taskCtx := range tm.GetAllTasks() {
+		if taskCtx.Finished() {
+			continue
+		}
+		paths[taskCtx.ModelPath] = true
+		for _, model := range taskCtx.EnsembleModels {
+			paths[model.ModelPath] = true
+		}
+	}
+
+	// 多实例模式下合并其他在线实例上运行中任务使用的模型
+	if tm.registry != nil {
+		remote, err := tm.registry.ActiveModelPaths(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for path := range remote {
+			paths[path] = true
+		}
+	}
+	return paths, nil
+}
+
+// forgetTask 移除已删除任务的内存上下文和任务日志文件
+func (tm *TaskManager) forgetTask(taskID string) {
+	tm.tasksLock.Lock()
+	if taskCtx, exists := tm.tasks[taskID]; exists && taskCtx.Finished() {
+		delete(tm.tasks, taskID)
+	}
+	tm.tasksLock.Unlock()
+
+	tm.removeTaskEventLog(taskID)
+	tm.removeTaskOutputLog(taskID)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"gen-go/internal/config"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestHousekeepingLease(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	a := NewHousekeepingService(nil, nil, nil, nil, client, &config.Config{})
+	b := NewHousekeepingService(nil, nil, nil, nil, client, &config.Config{})
+	interval := time.Hour
+
+	if !a.acquireLease(interval) {
+		t.Fatal("first instance should acquire the lease")
+	}
+	if b.acquireLease(interval) || a.acquireLease(interval) {
+		t.Fatal("lease acquired twice in the same interval")
+	}
+
+	mr.FastForward(interval)
+	if !b.acquireLease(interval) {
+		t.Fatal("lease should be available in the next interval")
+	}
+
+	single := NewHousekeepingService(nil, nil, nil, nil, nil, &config.Config{})
+	if !single.acquireLease(interval) {
+		t.Fatal("without Redis the lease should always be acquired")
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gen-go/internal/config"
+)
+
+// 后台作业类型
+const (
+	JobKindValidation    = "validation"
+	JobKindDedup         = "dedup"
+	JobKindGlossaryCheck = "glossary_check"
+	JobKindTagging       = "tagging"
+	JobKindJudge         = "judge"
+	JobKindSafetyCheck   = "safety_check"
+	JobKindEmbedding     = "embedding"
+	JobKindSimilarity    = "similarity_filter"
+	JobKindRuleScoring   = "rule_scoring"
+)
+
+// ErrJobQueueFull 作业排队数已达上限
+var ErrJobQueueFull = errors.New("文件处理队列已满，请稍后重试")
+
+// JobPool 有界作业池
+// 校验、去重等需要读取整个文件的操作统一经过作业池执行，限制同时运行的数量，
+// 避免多个大文件操作并发时内存成倍增长；池满时后续作业排队等待
+type JobPool struct {
+	name     string
+	slots    chan struct{}
+	maxQueue int64
+
+	running   int64
+	queued    int64
+	completed int64
+	failed    int64
+	rejected  int64
+
+	kindLock sync.Mutex
+	kinds    map[string]*jobKindStats
+}
+
+// jobKindStats 按作业类型统计
+type jobKindStats struct {
+	Running   int64
+	Completed int64
+	TotalWait time.Duration
+	TotalRun  time.Duration
+}
+
+// JobPoolStats 作业池占用情况
+type JobPoolStats struct {
+	Name      string                  `json:"name"`
+	Workers   int                     `json:"workers"`
+	Running   int64                   `json:"running"`
+	Queued    int64                   `json:"queued"`
+	MaxQueue  int64                   `json:"max_queue"`
+	Completed int64                   `json:"completed"`
+	Failed    int64                   `json:"failed"`
+	Rejected  int64                   `json:"rejected"`
+	Kinds     map[string]JobKindStats `json:"kinds"`
+}
+
+// JobKindStats 单个作业类型的统计
+type JobKindStats struct {
+	Running   int64   `json:"running"`
+	Completed int64   `json:"completed"`
+	AvgWaitMs float64 `json:"avg_wait_ms"`
+	AvgRunMs  float64 `json:"avg_run_ms"`
+}
+
+// NewJobPool 创建有界作业池
+func NewJobPool(name string, cfg *config.JobPoolConfig) *JobPool {
+	return &JobPool{
+		name:     name,
+		slots:    make(chan struct{}, cfg.Workers),
+		maxQueue: int64(cfg.MaxQueue),
+		kinds:    make(map[string]*jobKindStats),
+	}
+}
+
+// Run 在作业池中同步执行作业；池满时排队等待，排队数超过上限时返回 ErrJobQueueFull
+func (p *JobPool) Run(kind string, fn func() error) error {
+	waitStart := time.Now()
+
+	select {
+	case p.slots <- struct{}{}:
+	default:
+		// 没有空闲槽位，进入排队
+		if atomic.AddInt64(&p.queued, 1) > p.maxQueue {
+			atomic.AddInt64(&p.queued, -1)
+			atomic.AddInt64(&p.rejected, 1)
+			log.Printf("[JobPool] %s 队列已满，拒绝 %s 作业", p.name, kind)
+			return ErrJobQueueFull
+		}
+		log.Printf("[JobPool] %s 已满，%s 作业排队中（排队 %d）", p.name, kind, atomic.LoadInt64(&p.queued))
+		p.slots <- struct{}{}
+		atomic.AddInt64(&p.queued, -1)
+	}
+	wait := time.Since(waitStart)
+
+	atomic.AddInt64(&p.running, 1)
+	p.updateKind(kind, func(s *jobKindStats) {
+		s.Running++
+		s.TotalWait += wait
+	})
+
+	runStart := time.Now()
+	defer func() {
+		<-p.slots
+		atomic.AddInt64(&p.running, -1)
+		atomic.AddInt64(&p.completed, 1)
+		elapsed := time.Since(runStart)
+		p.updateKind(kind, func(s *jobKindStats) {
+			s.Running--
+			s.Completed++
+			s.TotalRun += elapsed
+		})
+	}()
+
+	if err := fn(); err != nil {
+		atomic.AddInt64(&p.failed, 1)
+		return err
+	}
+	return nil
+}
+
+// updateKind 更新作业类型统计
+func (p *JobPool) updateKind(kind string, update func(s *jobKindStats)) {
+	p.kindLock.Lock()
+	defer p.kindLock.Unlock()
+
+	stats, ok := p.kinds[kind]
+	if !ok {
+		stats = &jobKindStats{}
+		p.kinds[kind] = stats
+	}
+	update(stats)
+}
+
+// Stats 获取作业池占用情况
+func (p *JobPool) Stats() JobPoolStats {
+	stats := JobPoolStats{
+		Name:      p.name,
+		Workers:   cap(p.slots),
+		Running:   atomic.LoadInt64(&p.running),
+		Queued:    atomic.LoadInt64(&p.queued),
+		MaxQueue:  p.maxQueue,
+		Completed: atomic.LoadInt64(&p.completed),
+		Failed:    atomic.LoadInt64(&p.failed),
+		Rejected:  atomic.LoadInt64(&p.rejected),
+		Kinds:     make(map[string]JobKindStats),
+	}
+
+	p.kindLock.Lock()
+	defer p.kindLock.Unlock()
+	for kind, s := range p.kinds {
+		kindStats := JobKindStats{
+			Running:   s.Running,
+			Completed: s.Completed,
+		}
+		if s.Completed > 0 {
+			kindStats.AvgWaitMs = float64(s.TotalWait.Milliseconds()) / float64(s.Completed)
+			kindStats.AvgRunMs = float64(s.TotalRun.Milliseconds()) / float64(s.Completed)
+		}
+		stats.Kinds[kind] = kindStats
+	}
+	return stats
+}
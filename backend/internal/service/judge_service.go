@@ -0,0 +1,323 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+)
+
+// 评分状态
+const (
+	JudgeStatusRunning  = "running"
+	JudgeStatusFinished = "finished"
+	JudgeStatusError    = "error"
+)
+
+// 评分限制
+const (
+	judgeBatchSize      = 200
+	judgeMaxInputRunes  = 8000 // 发送给裁判模型的数据内容上限
+	judgeMaxConcurrent  = 8    // 单个任务同时评分的条数上限（模型最大并发数更小时以模型为准）
+	judgeProgressEvents = 20   // 一次评分最多推送的进度事件数
+)
+
+// JudgeService 裁判模型评分服务
+// 任务结束后（或手动触发）调用裁判模型为每条生成数据打分，写入 model_score 和结构化点评 judge_feedback
+type JudgeService struct {
+	generatedDataRepo *repository.GeneratedDataRepository
+	taskRepo          *repository.TaskRepository
+	modelRepo         *repository.ModelConfigRepository
+	modelService      *ModelService
+	jobPool           *JobPool
+
+	lock     sync.Mutex
+	progress map[string]*judgeProgress
+}
+
+// judgeProgress 单个任务的评分进度
+type judgeProgress struct {
+	lock       sync.Mutex
+	state      dto.JudgeProgressResponse
+	scoreSum   float64
+	startedAt  time.Time
+	finishedAt *time.Time
+}
+
+// NewJudgeService 创建裁判模型评分服务
+func NewJudgeService(
+	generatedDataRepo *repository.GeneratedDataRepository,
+	taskRepo *repository.TaskRepository,
+	modelRepo *repository.ModelConfigRepository,
+	modelService *ModelService,
+	jobPool *JobPool,
+) *JudgeService {
+	return &JudgeService{
+		generatedDataRepo: generatedDataRepo,
+		taskRepo:          taskRepo,
+		modelRepo:         modelRepo,
+		modelService:      modelService,
+		jobPool:           jobPool,
+		progress:          make(map[string]*judgeProgress),
+	}
+}
+
+// StartJudgeForUser 手动触发评分（校验任务归属，后台执行，通过 GetProgressForUser 查询进度）
+func (s *JudgeService) StartJudgeForUser(taskID string, userID uint, judgeModelID uint) (*dto.JudgeProgressResponse, error) {
+	task, err := s.taskRepo.GetEditableByTaskID(taskID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("任务不存在或无权访问")
+	}
+	if task.Status == models.TaskStatusRunning {
+		return nil, fmt.Errorf("任务运行中，请在任务结束后评分")
+	}
+
+	model, err := s.modelRepo.GetByIDAndActive(judgeModelID)
+	if err != nil {
+		return nil, fmt.Errorf("裁判模型不存在或未启用")
+	}
+
+	p, err := s.begin(taskID, judgeModelID)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		err := s.jobPool.Run(JobKindJudge, func() error {
+			return s.judge(p, model, nil)
+		})
+		if err != nil {
+			p.finish(err)
+		}
+	}()
+
+	return p.snapshot(), nil
+}
+
+// GetProgressForUser 获取任务的评分进度
+func (s *JudgeService) GetProgressForUser(taskID string, userID uint) (*dto.JudgeProgressResponse, error) {
+	if _, err := s.taskRepo.GetVisibleByTaskID(taskID, userID); err != nil {
+		return nil, fmt.Errorf("任务不存在或无权访问")
+	}
+
+	s.lock.Lock()
+	p, ok := s.progress[taskID]
+	s.lock.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("该任务没有评分记录")
+	}
+	return p.snapshot(), nil
+}
+
+// JudgeTask 同步执行评分（任务结束后由任务管理器调用），onProgress 用于推送进度事件
+func (s *JudgeService) JudgeTask(taskID string, judgeModelID uint, onProgress func(*dto.JudgeProgressResponse)) (*dto.JudgeProgressResponse, error) {
+	model, err := s.modelRepo.GetByIDAndActive(judgeModelID)
+	if err != nil {
+		return nil, fmt.Errorf("裁判模型不存在或未启用")
+	}
+
+	p, err := s.begin(taskID, judgeModelID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.jobPool.Run(JobKindJudge, func() error {
+		return s.judge(p, model, onProgress)
+	})
+	if err != nil {
+		p.finish(err)
+		return p.snapshot(), err
+	}
+	return p.snapshot(), nil
+}
+
+// begin 登记评分进度，同一任务同时只允许一次评分
+func (s *JudgeService) begin(taskID string, judgeModelID uint) (*judgeProgress, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if p, ok := s.progress[taskID]; ok && p.snapshot().Status == JudgeStatusRunning {
+		return nil, fmt.Errorf("该任务正在评分中")
+	}
+
+	p := &judgeProgress{
+		state: dto.JudgeProgressResponse{
+			TaskID:       taskID,
+			JudgeModelID: judgeModelID,
+			Status:       JudgeStatusRunning,
+		},
+		startedAt: time.Now(),
+	}
+	s.progress[taskID] = p
+	return p, nil
+}
+
+// judge 评分的具体实现：分批读取数据，按并发上限并行调用裁判模型，每条结果立即写回
+func (s *JudgeService) judge(p *judgeProgress, model *models.ModelConfig, onProgress func(*dto.JudgeProgressResponse)) error {
+	taskID := p.snapshot().TaskID
+
+	counts, err := s.generatedDataRepo.CountByTaskIDs([]string{taskID})
+	if err != nil {
+		return fmt.Errorf("统计生成数据失败: %w", err)
+	}
+	total := int(counts[taskID].DataCount)
+	p.setTotal(total)
+
+	concurrency := model.MaxConcurrent
+	if concurrency <= 0 || concurrency > judgeMaxConcurrent {
+		concurrency = judgeMaxConcurrent
+	}
+	step := total / judgeProgressEvents
+	if step < 1 {
+		step = 1
+	}
+
+	err = s.generatedDataRepo.ScanForDedup(taskID, judgeBatchSize, func(batch []models.GeneratedData) error {
+		slots := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+		for i := range batch {
+			data := batch[i]
+			slots <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-slots
+					wg.Done()
+				}()
+
+				feedback, err := s.score(model, data.DataContent)
+				if err == nil {
+					var content []byte
+					content, err = json.Marshal(feedback)
+					if err == nil {
+						err = s.generatedDataRepo.UpdateJudgeResult(data.ID, feedback.Score, string(content))
+					}
+				}
+				if err != nil {
+					log.Printf("[Judge] 任务 %s 数据 %d 评分失败: %v", taskID, data.ID, err)
+					p.record(0, false)
+				} else {
+					p.record(feedback.Score, true)
+				}
+
+				if snapshot := p.snapshot(); onProgress != nil && (snapshot.Scored+snapshot.Failed)%step == 0 {
+					onProgress(snapshot)
+				}
+			}()
+		}
+		wg.Wait()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("获取生成数据失败: %w", err)
+	}
+
+	p.finish(nil)
+	result := p.snapshot()
+	log.Printf("[Judge] 任务 %s 评分完成: 共 %d 条, 成功 %d 条, 失败 %d 条", taskID, result.Total, result.Scored, result.Failed)
+	return nil
+}
+
+// score 调用裁判模型为一条数据打分
+func (s *JudgeService) score(model *models.ModelConfig, content string) (*dto.JudgeFeedback, error) {
+	if runes := []rune(content); len(runes) > judgeMaxInputRunes {
+		content = string(runes[:judgeMaxInputRunes])
+	}
+
+	prompt := "你是训练数据质量评审员。请从正确性、完整性、指令遵循和表达流畅度评估下面这条数据，按 0-10 分打分。\n" +
+		"只输出一个 JSON 对象，不要输出其他内容，格式：" +
+		`{"score": 分数, "strengths": ["优点"], "issues": ["问题"], "summary": "一句话总结"}` +
+		"\n\n数据：\n" + content
+
+	resp, err := s.modelService.CallModel(&dto.ModelCallProxyRequest{
+		APIUrl:      model.APIURL,
+		APIKey:      model.APIKey,
+		Model:       model.ModelPath,
+		Messages:    []dto.Message{{Role: "user", Content: prompt}},
+		Temperature: 0,
+		TopP:        model.TopP,
+		MaxTokens:   1024,
+		Timeout:     model.Timeout,
+		IsVLLM:      model.IsVLLM,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Error)
+	}
+
+	// 模型输出可能带有代码块或说明文字，取第一个 { 到最后一个 } 之间的内容
+	answer := resp.Content
+	start := strings.Index(answer, "{")
+	end := strings.LastIndex(answer, "}")
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("裁判模型输出不是JSON: %s", answer)
+	}
+
+	var feedback dto.JudgeFeedback
+	if err := json.Unmarshal([]byte(answer[start:end+1]), &feedback); err != nil {
+		return nil, fmt.Errorf("解析裁判模型输出失败: %w", err)
+	}
+	if feedback.Score < 0 || feedback.Score > 10 {
+		return nil, fmt.Errorf("评分超出范围: %v", feedback.Score)
+	}
+	feedback.JudgeModel = model.Name
+	return &feedback, nil
+}
+
+// setTotal 设置待评分总数
+func (p *judgeProgress) setTotal(total int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.state.Total = total
+}
+
+// record 记录一条数据的评分结果
+func (p *judgeProgress) record(score float64, ok bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if ok {
+		p.state.Scored++
+		p.scoreSum += score
+	} else {
+		p.state.Failed++
+	}
+}
+
+// finish 结束评分，err 不为空时标记为失败
+func (p *judgeProgress) finish(err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	now := time.Now()
+	p.finishedAt = &now
+	p.state.Status = JudgeStatusFinished
+	if err != nil {
+		p.state.Status = JudgeStatusError
+		p.state.Error = err.Error()
+	}
+}
+
+// snapshot 获取当前进度的副本
+func (p *judgeProgress) snapshot() *dto.JudgeProgressResponse {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	state := p.state
+	if state.Total > 0 {
+		state.Percent = float64(state.Scored+state.Failed) / float64(state.Total) * 100
+	}
+	if state.Scored > 0 {
+		average := p.scoreSum / float64(state.Scored)
+		state.AverageScore = &average
+	}
+	state.StartedAt = dto.FormatTime(p.startedAt)
+	state.FinishedAt = dto.FormatTimePtr(p.finishedAt)
+	return &state
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// modelCacheKeyPrefix 模型响应缓存的 Redis 键前缀
+	modelCacheKeyPrefix = "model_cache:"
+	// modelCacheOptOutKeyPrefix 关闭了响应缓存的任务标记（任务ID -> 1）
+	modelCacheOptOutKeyPrefix = "model_cache_optout:"
+	// modelCacheOptOutTTL 任务关闭缓存标记的保留时间
+	modelCacheOptOutTTL = 7 * 24 * time.Hour
+)
+
+// ModelCache 模型调用代理的响应缓存（Redis）
+// 以模型、消息和生成参数的哈希为键缓存成功的响应，只缓存温度不超过配置上限的请求
+type ModelCache struct {
+	redisClient *redis.Client
+	cfg         *config.Config
+
+	lock  sync.Mutex
+	stats map[string]*ModelCacheModelStats // 模型 -> 命中统计
+}
+
+// ModelCacheStats 响应缓存的命中统计（进程内，自启动起累计）
+type ModelCacheStats struct {
+	Enabled     bool                            `json:"enabled"`
+	Hits        int64                           `json:"hits"`
+	Misses      int64                           `json:"misses"`
+	HitRate     float64                         `json:"hit_rate"`
+	SavedTokens int64                           `json:"saved_tokens"` // 命中缓存节省的 Token 数（上游未返回 usage 时按字符数估算）
+	Models      map[string]ModelCacheModelStats `json:"models"`
+}
+
+// ModelCacheModelStats 单个模型的缓存命中统计
+type ModelCacheModelStats struct {
+	Hits        int64 `json:"hits"`
+	Misses      int64 `json:"misses"`
+	SavedTokens int64 `json:"saved_tokens"`
+}
+
+// modelCacheEntry 缓存的响应内容
+type modelCacheEntry struct {
+	Content string `json:"content"`
+	Tokens  int    `json:"tokens"`
+}
+
+// NewModelCache 创建模型响应缓存
+func NewModelCache(redisClient *redis.Client, cfg *config.Config) *ModelCache {
+	return &ModelCache{
+		redisClient: redisClient,
+		cfg:         cfg,
+		stats:       make(map[string]*ModelCacheModelStats),
+	}
+}
+
+// cacheable 判断请求是否使用缓存
+func (c *ModelCache) cacheable(req *dto.ModelCallProxyRequest) bool {
+	return c.redisClient != nil && c.cfg.Current().Model.CacheEnabled && !req.NoCache &&
+		req.Temperature <= c.cfg.Current().Model.CacheMaxTemperature
+}
+
+// cacheKey 缓存键：模型、消息和影响输出的生成参数的 SHA256
+func (c *ModelCache) cacheKey(req *dto.ModelCallProxyRequest) string {
+	raw, _ := json.Marshal(struct {
+		Model       string        `json:"model"`
+		Messages    []dto.Message `json:"messages"`
+		Temperature float64       `json:"temperature"`
+		TopP        float64       `json:"top_p"`
+		MaxTokens   int           `json:"max_tokens"`
+	}{req.Model, req.Messages, req.Temperature, req.TopP, req.MaxTokens})
+	sum := sha256.Sum256(raw)
+	return modelCacheKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+// Get 查询缓存，返回缓存的内容；任务关闭了缓存或未命中时返回 false
+// 同时返回是否应在调用成功后写入缓存
+func (c *ModelCache) Get(ctx context.Context, req *dto.ModelCallProxyRequest) (content string, hit bool, store bool) {
+	if !c.cacheable(req) {
+		return "", false, false
+	}
+
+	pipe := c.redisClient.Pipeline()
+	var optOut *redis.IntCmd
+	if req.TaskID != "" {
+		optOut = pipe.Exists(ctx, modelCacheOptOutKeyPrefix+req.TaskID)
+	}
+	entryCmd := pipe.Get(ctx, c.cacheKey(req))
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		log.Printf("[ModelCache] 查询缓存失败: %v", err)
+		return "", false, false
+	}
+	if optOut != nil && optOut.Val() > 0 {
+		return "", false, false
+	}
+
+	raw, err := entryCmd.Bytes()
+	if err != nil {
+		c.record(req.Model, false, 0)
+		return "", false, true
+	}
+	var entry modelCacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		c.record(req.Model, false, 0)
+		return "", false, true
+	}
+	c.record(req.Model, true, entry.Tokens)
+	return entry.Content, true, false
+}
+
+// Set 写入成功的响应
+func (c *ModelCache) Set(ctx context.Context, req *dto.ModelCallProxyRequest, content string, tokens int) {
+	raw, err := json.Marshal(modelCacheEntry{Content: content, Tokens: tokens})
+	if err != nil {
+		return
+	}
+	if err := c.redisClient.Set(ctx, c.cacheKey(req), raw, c.cfg.Current().Model.GetCacheTTL()).Err(); err != nil {
+		log.Printf("[ModelCache] 写入缓存失败: %v", err)
+	}
+}
+
+// disableModelCacheForTask 标记任务的模型调用不使用响应缓存（任务启动时由 TaskManager 设置）
+func disableModelCacheForTask(ctx context.Context, redisClient *redis.Client, taskID string) {
+	if redisClient == nil {
+		return
+	}
+	if err := redisClient.Set(ctx, modelCacheOptOutKeyPrefix+taskID, 1, modelCacheOptOutTTL).Err(); err != nil {
+		log.Printf("[ModelCache] 标记任务 %s 关闭缓存失败: %v", taskID, err)
+	}
+}
+
+// record 记录一次查询结果
+func (c *ModelCache) record(model string, hit bool, tokens int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	stats, ok := c.stats[model]
+	if !ok {
+		stats = &ModelCacheModelStats{}
+		c.stats[model] = stats
+	}
+	if hit {
+		stats.Hits++
+		stats.SavedTokens += int64(tokens)
+	} else {
+		stats.Misses++
+	}
+}
+
+// Stats 获取缓存命中统计
+func (c *ModelCache) Stats() ModelCacheStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	result := ModelCacheStats{
+		Enabled: c.cfg.Current().Model.CacheEnabled,
+		Models:  make(map[string]ModelCacheModelStats, len(c.stats)),
+	}
+	for model, stats := range c.stats {
+		result.Hits += stats.Hits
+		result.Misses += stats.Misses
+		result.SavedTokens += stats.SavedTokens
+		result.Models[model] = *stats
+	}
+	if total := result.Hits + result.Misses; total > 0 {
+		result.HitRate = float64(result.Hits) / float64(total)
+	}
+	return result
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+)
+
+// ErrModelDiscoveryFailed 无法从模型服务获取模型列表
+var ErrModelDiscoveryFailed = errors.New("获取模型服务的模型列表失败")
+
+// 自动发现的模型创建配置时使用的默认参数（与新增模型表单一致）
+const (
+	discoveredMaxConcurrent = 16
+	discoveredMaxTokens     = 2048
+	discoveredTimeout       = 600
+	maxModelNameLength      = 100
+)
+
+// DiscoverModels 查询模型服务的 /models 接口，列出可用的模型；已有相同地址和模型路径的配置时标注配置 ID
+func (s *ModelService) DiscoverModels(req *dto.DiscoverModelsRequest) (*dto.DiscoverModelsResponse, error) {
+	probe := newModelProbe(req.APIURL, req.APIKey, req.Provider, time.Duration(req.Timeout)*time.Second)
+	ctx := context.Background()
+
+	start := time.Now()
+	upstreamModels, dialect, err := probe.ListModels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrModelDiscoveryFailed, err)
+	}
+	result := &dto.DiscoverModelsResponse{
+		APIURL:    probe.apiURL,
+		Dialect:   dialect,
+		LatencyMs: time.Since(start).Milliseconds(),
+		Models:    make([]dto.DiscoveredModel, len(upstreamModels)),
+	}
+	if version, err := probe.Version(ctx); err == nil {
+		result.ServerVersion = version
+		result.Dialect = ModelDialectVLLM
+	}
+
+	configured, err := s.configuredModels(probe.apiURL, upstreamModels)
+	if err != nil {
+		return nil, err
+	}
+	for i, upstream := range upstreamModels {
+		result.Models[i] = dto.DiscoveredModel{UpstreamModel: upstream}
+		if model, ok := configured[upstream.ID]; ok {
+			id := model.ID
+			result.Models[i].ConfigID = &id
+			result.Models[i].ConfigName = model.Name
+		}
+	}
+	return result, nil
+}
+
+// ImportModels 按模型服务返回的模型 ID 批量创建模型配置，模型 ID 作为模型路径
+// 服务未提供的模型、已有相同地址和模型路径的配置以及名称冲突的模型会被跳过
+func (s *ModelService) ImportModels(req *dto.ImportModelsRequest) (*dto.ImportModelsResponse, error) {
+	probe := newModelProbe(req.APIURL, req.APIKey, req.Provider, 0)
+	ctx := context.Background()
+
+	upstreamModels, dialect, err := probe.ListModels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrModelDiscoveryFailed, err)
+	}
+	if _, err := probe.Version(ctx); err == nil {
+		dialect = ModelDialectVLLM
+	}
+	configured, err := s.configuredModels(probe.apiURL, upstreamModels)
+	if err != nil {
+		return nil, err
+	}
+	available := make(map[string]bool, len(upstreamModels))
+	for _, upstream := range upstreamModels {
+		available[upstream.ID] = true
+	}
+
+	isVLLM := dialect == ModelDialectVLLM
+	if req.IsVLLM != nil {
+		isVLLM = *req.IsVLLM
+	}
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+	maxConcurrent, maxTokens, timeout := req.MaxConcurrent, req.MaxTokens, req.Timeout
+	if maxConcurrent <= 0 {
+		maxConcurrent = discoveredMaxConcurrent
+	}
+	if maxTokens <= 0 {
+		maxTokens = discoveredMaxTokens
+	}
+	if timeout <= 0 {
+		timeout = discoveredTimeout
+	}
+
+	result := &dto.ImportModelsResponse{
+		Created: []dto.ModelConfigResponse{},
+		Skipped: []dto.ImportModelSkipped{},
+	}
+	seen := make(map[string]bool, len(req.ModelIDs))
+	for _, modelPath := range req.ModelIDs {
+		modelPath = strings.TrimSpace(modelPath)
+		if modelPath == "" || seen[modelPath] {
+			continue
+		}
+		seen[modelPath] = true
+
+		skip := func(reason string) {
+			result.Skipped = append(result.Skipped, dto.ImportModelSkipped{ModelPath: modelPath, Reason: reason})
+		}
+		if !available[modelPath] {
+			skip("模型服务未提供该模型")
+			continue
+		}
+		if model, ok := configured[modelPath]; ok {
+			skip(fmt.Sprintf("已存在相同地址和模型路径的配置: %s", model.Name))
+			continue
+		}
+		name := req.NamePrefix + modelPath
+		if len(name) > maxModelNameLength {
+			skip(fmt.Sprintf("模型名称超过 %d 个字符", maxModelNameLength))
+			continue
+		}
+		if _, err := s.modelRepo.GetByName(name); err == nil {
+			skip(fmt.Sprintf("模型名称已存在: %s", name))
+			continue
+		}
+
+		model, err := s.CreateModel(&dto.CreateModelConfigRequest{
+			Name:          name,
+			APIURL:        probe.apiURL,
+			APIKey:        req.APIKey,
+			ModelPath:     modelPath,
+			MaxConcurrent: maxConcurrent,
+			Temperature:   1.0,
+			TopP:          1.0,
+			MaxTokens:     maxTokens,
+			IsVLLM:        isVLLM,
+			Provider:      req.Provider,
+			Timeout:       timeout,
+			Description:   fmt.Sprintf("从 %s 自动发现", probe.apiURL),
+			IsActive:      isActive,
+		})
+		if err != nil {
+			skip(fmt.Sprintf("创建失败: %v", err))
+			continue
+		}
+		result.Created = append(result.Created, toModelConfigResponse(model))
+	}
+	return result, nil
+}
+
+// configuredModels 查找服务地址（APIURL 或端点池中的地址）为 apiURL 的已有配置，按模型路径索引
+func (s *ModelService) configuredModels(apiURL string, upstreamModels []dto.UpstreamModel) (map[string]*models.ModelConfig, error) {
+	paths := make([]string, len(upstreamModels))
+	for i, upstream := range upstreamModels {
+		paths[i] = upstream.ID
+	}
+	configs, err := s.modelRepo.ListByModelPaths(paths)
+	if err != nil {
+		return nil, fmt.Errorf("查询已有模型配置失败: %w", err)
+	}
+
+	configured := make(map[string]*models.ModelConfig)
+	for i := range configs {
+		for _, endpoint := range configs[i].EndpointList() {
+			if strings.TrimRight(endpoint, "/") == apiURL {
+				configured[configs[i].ModelPath] = &configs[i]
+				break
+			}
+		}
+	}
+	return configured, nil
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// resolveEmbeddingModel 查找向量模型配置：按名称或模型路径匹配，为空时使用 model_services.embedding_model
+func (s *ModelService) resolveEmbeddingModel(model string) (*models.ModelConfig, error) {
+	if model == "" {
+		model = s.cfg.Current().Model.EmbeddingModel
+	}
+	if model == "" {
+		return nil, fmt.Errorf("未指定向量模型，且未配置 model_services.embedding_model")
+	}
+	modelConfig, err := s.modelRepo.GetByModelPathOrName(model)
+	if err != nil {
+		return nil, fmt.Errorf("向量模型 %s 不存在", model)
+	}
+	if !modelConfig.IsActive {
+		return nil, fmt.Errorf("向量模型 %s 未启用", model)
+	}
+	return modelConfig, nil
+}
+
+// EmbeddingModelByID 获取向量模型配置，id 为 0 时使用 model_services.embedding_model
+func (s *ModelService) EmbeddingModelByID(id uint) (*models.ModelConfig, error) {
+	if id == 0 {
+		return s.resolveEmbeddingModel("")
+	}
+	modelConfig, err := s.modelRepo.GetByIDAndActive(id)
+	if err != nil {
+		return nil, fmt.Errorf("向量模型不存在或未启用")
+	}
+	return modelConfig, nil
+}
+
+// Embed 调用向量接口代理：按请求中的模型查找配置，调用失败时计入错误统计
+func (s *ModelService) Embed(ctx context.Context, req *dto.EmbeddingProxyRequest) (*dto.EmbeddingProxyResponse, error) {
+	modelConfig, err := s.resolveEmbeddingModel(req.Model)
+	if err != nil {
+		return &dto.EmbeddingProxyResponse{Success: false, Error: err.Error()}, nil
+	}
+	return s.EmbedWithModel(ctx, modelConfig, req.Input)
+}
+
+// EmbedWithModel 使用指定的模型配置向量化文本，与模型调用代理共用 RPM/TPM 限流、并发槽位和端点池
+func (s *ModelService) EmbedWithModel(traceCtx context.Context, modelConfig *models.ModelConfig, input []string) (*dto.EmbeddingProxyResponse, error) {
+	traceCtx, span := tracing.Start(traceCtx, "ModelService.Embed",
+		attribute.String("model", modelConfig.ModelPath),
+		attribute.Int("inputs", len(input)),
+	)
+
+	resp := s.embed(traceCtx, modelConfig, input)
+	if !resp.Success {
+		s.errorTracker.RecordModelFailure(modelConfig.ModelPath, resp.Error)
+		tracing.End(span, fmt.Errorf("%s", resp.Error))
+	} else {
+		tracing.End(span, nil)
+	}
+	return resp, nil
+}
+
+// embed 向量接口调用的具体实现，traceCtx 仅用于链路追踪
+func (s *ModelService) embed(traceCtx context.Context, modelConfig *models.ModelConfig, input []string) *dto.EmbeddingProxyResponse {
+	provider, ok := providerFor(modelConfig.Provider).(embeddingProvider)
+	if !ok {
+		return &dto.EmbeddingProxyResponse{Success: false, Error: fmt.Sprintf("%s 协议不提供向量接口", modelConfig.Provider)}
+	}
+
+	ctx := context.Background()
+	rateKey := modelRateKey(modelConfig, modelConfig.ModelPath)
+	if modelConfig.RPMLimit > 0 || modelConfig.TPMLimit > 0 {
+		if err := s.rateLimiter.Acquire(ctx, rateKey, modelConfig.RPMLimit, modelConfig.TPMLimit); err != nil {
+			return &dto.EmbeddingProxyResponse{Success: false, Error: fmt.Sprintf("速率限制等待失败: %v", err)}
+		}
+	}
+
+	limiter := s.getOrCreateLimiter(modelConfig.ModelPath, modelConfig.MaxConcurrent)
+	if err := limiter.Acquire(ctx, modelConfig.ModelPath); err != nil {
+		return &dto.EmbeddingProxyResponse{Success: false, Error: fmt.Sprintf("获取并发槽位失败: %v", err)}
+	}
+	defer limiter.Release(ctx, modelConfig.ModelPath)
+
+	apiURL, releaseEndpoint := s.endpointPool.Acquire(ctx, modelConfig.EndpointList())
+	requestStart := time.Now()
+	endpointErr := ""
+	defer func() { releaseEndpoint(time.Since(requestStart), endpointErr) }()
+
+	payload, err := provider.BuildEmbeddingBody(modelConfig.ModelPath, input)
+	if err != nil {
+		return &dto.EmbeddingProxyResponse{Success: false, Error: fmt.Sprintf("序列化请求失败: %v", err)}
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.EmbeddingURL(apiURL, modelConfig.ModelPath), bytes.NewReader(payload))
+	if err != nil {
+		return &dto.EmbeddingProxyResponse{Success: false, Error: fmt.Sprintf("创建请求失败: %v", err)}
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	providerFor(modelConfig.Provider).Authorize(httpReq.Header, config.ResolveSecret(modelConfig.APIKey))
+	tracing.InjectHTTP(traceCtx, httpReq.Header)
+
+	client := &http.Client{Timeout: time.Duration(modelConfig.Timeout) * time.Second}
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		endpointErr = err.Error()
+		log.Printf("[Embed] 请求失败: %v", err)
+		return &dto.EmbeddingProxyResponse{Success: false, Error: fmt.Sprintf("请求失败: %v", err)}
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		endpointErr = err.Error()
+		return &dto.EmbeddingProxyResponse{Success: false, Error: fmt.Sprintf("读取响应失败: %v", err)}
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		if httpResp.StatusCode >= http.StatusInternalServerError {
+			endpointErr = fmt.Sprintf("status=%d", httpResp.StatusCode)
+		}
+		log.Printf("[Embed] API返回错误: status=%d, body=%s", httpResp.StatusCode, string(body))
+		return &dto.EmbeddingProxyResponse{Success: false, Error: fmt.Sprintf("API返回错误: status=%d, body=%s", httpResp.StatusCode, string(body))}
+	}
+
+	vectors, usage, err := provider.ParseEmbeddingResponse(body)
+	if err != nil {
+		return &dto.EmbeddingProxyResponse{Success: false, Error: err.Error()}
+	}
+	if len(vectors) != len(input) {
+		return &dto.EmbeddingProxyResponse{Success: false, Error: fmt.Sprintf("向量数与输入数不一致: %d != %d", len(vectors), len(input))}
+	}
+	dim := 0
+	for _, vector := range vectors {
+		if len(vector) == 0 || (dim > 0 && len(vector) != dim) {
+			return &dto.EmbeddingProxyResponse{Success: false, Error: "向量响应的维度不一致"}
+		}
+		dim = len(vector)
+	}
+
+	// 计入 TPM 窗口：上游未返回 usage 时按字符数保守估算
+	tokens := usage.PromptTokens
+	if tokens <= 0 {
+		for _, text := range input {
+			tokens += len([]rune(text))
+		}
+	}
+	if modelConfig.TPMLimit > 0 {
+		s.rateLimiter.RecordTokens(ctx, rateKey, tokens)
+	}
+	go s.recordTokenUsage(modelConfig.ModelPath, dto.Usage{PromptTokens: usage.PromptTokens, TotalTokens: usage.PromptTokens})
+
+	return &dto.EmbeddingProxyResponse{
+		Success:      true,
+		Model:        modelConfig.ModelPath,
+		Dim:          dim,
+		Embeddings:   vectors,
+		PromptTokens: usage.PromptTokens,
+	}
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+)
+
+// 模型服务的接口类型
+const (
+	ModelDialectVLLM    = "vllm"
+	ModelDialectOpenAI  = "openai"
+	ModelDialectUnknown = "unknown"
+)
+
+// 测试连接的默认参数
+const (
+	defaultProbeTimeout = 30 * time.Second
+	probeMaxReplyRunes  = 200
+	probeMaxErrorBytes  = 512
+)
+
+// modelProbe 访问模型服务的 /models、/version 和对话接口
+type modelProbe struct {
+	apiURL       string
+	apiKey       string
+	providerName string
+	provider     chatProvider
+	client       *http.Client
+}
+
+// newModelProbe 创建模型服务探测器，apiURL 为配置中的 API 地址（通常以 /v1 结尾），provider 为对话接口协议
+func newModelProbe(apiURL, apiKey, provider string, timeout time.Duration) *modelProbe {
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
+	}
+	if provider == "" {
+		provider = models.ModelProviderOpenAI
+	}
+	return &modelProbe{
+		apiURL:       strings.TrimRight(apiURL, "/"),
+		apiKey:       config.ResolveSecret(apiKey),
+		providerName: provider,
+		provider:     providerFor(provider),
+		client:       &http.Client{Timeout: timeout},
+	}
+}
+
+// ListModels 获取服务的模型列表并判断接口类型：OpenAI 协议下 vLLM 的模型带有 owned_by=vllm 和 max_model_len，
+// Anthropic 和 Gemini 协议的接口类型即协议名（Gemini 的模型列表在 models 字段中，名称带 models/ 前缀）
+func (p *modelProbe) ListModels(ctx context.Context) ([]dto.UpstreamModel, string, error) {
+	body, err := p.do(ctx, http.MethodGet, p.apiURL+"/models", nil)
+	if err != nil {
+		return nil, ModelDialectUnknown, err
+	}
+
+	var result struct {
+		Data   []dto.UpstreamModel `json:"data"`
+		Models []struct {
+			Name            string `json:"name"`
+			InputTokenLimit int    `json:"inputTokenLimit"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, ModelDialectUnknown, fmt.Errorf("解析模型列表失败（接口不兼容 %s 协议）: %w", p.providerName, err)
+	}
+
+	switch p.providerName {
+	case models.ModelProviderGemini:
+		upstreamModels := make([]dto.UpstreamModel, len(result.Models))
+		for i, model := range result.Models {
+			upstreamModels[i] = dto.UpstreamModel{ID: strings.TrimPrefix(model.Name, "models/"), MaxModelLen: model.InputTokenLimit}
+		}
+		return upstreamModels, models.ModelProviderGemini, nil
+	case models.ModelProviderAnthropic:
+		return result.Data, models.ModelProviderAnthropic, nil
+	}
+
+	dialect := ModelDialectOpenAI
+	for _, model := range result.Data {
+		if model.OwnedBy == "vllm" || model.MaxModelLen > 0 {
+			dialect = ModelDialectVLLM
+			break
+		}
+	}
+	return result.Data, dialect, nil
+}
+
+// Version 获取 vLLM 的版本号（位于 API 地址去掉 /v1 后的 /version），非 vLLM 服务返回错误
+func (p *modelProbe) Version(ctx context.Context) (string, error) {
+	if p.providerName != models.ModelProviderOpenAI {
+		return "", fmt.Errorf("%s 协议没有版本接口", p.providerName)
+	}
+	base := strings.TrimSuffix(p.apiURL, "/v1")
+	body, err := p.do(ctx, http.MethodGet, base+"/version", nil)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || result.Version == "" {
+		return "", fmt.Errorf("无法识别的版本信息")
+	}
+	return result.Version, nil
+}
+
+// Complete 按协议发送一次很短的对话请求，返回回复内容和 Token 用量
+func (p *modelProbe) Complete(ctx context.Context, model string) (string, dto.Usage, error) {
+	payload, err := p.provider.BuildBody(&dto.ModelCallProxyRequest{
+		Model:     model,
+		Messages:  []dto.Message{{Role: "user", Content: "Hello"}},
+		MaxTokens: 16,
+	})
+	if err != nil {
+		return "", dto.Usage{}, err
+	}
+	body, err := p.do(ctx, http.MethodPost, p.provider.CompletionURL(p.apiURL, model), payload)
+	if err != nil {
+		return "", dto.Usage{}, err
+	}
+	return p.provider.ParseResponse(body)
+}
+
+// do 发送请求并返回响应体，非 200 响应按状态码给出排查提示
+func (p *modelProbe) do(ctx context.Context, method, url string, payload []byte) ([]byte, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 API 地址: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	p.provider.Authorize(req.Header, p.apiKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return body, nil
+	}
+
+	if len(body) > probeMaxErrorBytes {
+		body = body[:probeMaxErrorBytes]
+	}
+	hint := ""
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		hint = "（鉴权失败，请检查 API Key）"
+	case http.StatusNotFound:
+		hint = "（接口不存在，请检查 API 地址是否以 /v1 结尾，或模型路径是否正确）"
+	}
+	return nil, fmt.Errorf("API返回错误%s: status=%d, body=%s", hint, resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// TestConnection 测试模型服务连接：获取模型列表、判断接口类型（vLLM、OpenAI、Anthropic 或 Gemini），并发送一次很短的对话请求测量延迟
+// 请求失败不返回 error，失败原因写在结果的 error/models_error 中
+func (s *ModelService) TestConnection(req *dto.TestModelConnectionRequest) (*dto.TestModelConnectionResponse, error) {
+	apiURL, apiKey, modelPath, provider := req.APIURL, req.APIKey, req.ModelPath, req.Provider
+	if req.ModelID != nil {
+		model, err := s.modelRepo.GetByID(*req.ModelID)
+		if err != nil {
+			return nil, fmt.Errorf("模型不存在: %d", *req.ModelID)
+		}
+		if apiURL == "" {
+			apiURL = model.APIURL
+		}
+		if apiKey == "" {
+			apiKey = model.APIKey
+		}
+		if modelPath == "" {
+			modelPath = model.ModelPath
+		}
+		if provider == "" {
+			provider = model.Provider
+		}
+	}
+	if apiURL == "" {
+		return nil, fmt.Errorf("API 地址不能为空")
+	}
+
+	probe := newModelProbe(apiURL, apiKey, provider, time.Duration(req.Timeout)*time.Second)
+	ctx := context.Background()
+	result := &dto.TestModelConnectionResponse{
+		APIURL:  probe.apiURL,
+		Dialect: ModelDialectUnknown,
+		Models:  []dto.UpstreamModel{},
+	}
+
+	start := time.Now()
+	upstreamModels, dialect, err := probe.ListModels(ctx)
+	result.ModelsLatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.ModelsError = err.Error()
+	} else {
+		result.Models = upstreamModels
+		result.Dialect = dialect
+	}
+	if version, err := probe.Version(ctx); err == nil {
+		result.ServerVersion = version
+		result.Dialect = ModelDialectVLLM
+	}
+
+	if modelPath == "" {
+		if len(result.Models) == 0 {
+			result.Error = "未指定模型路径，且无法从服务获取模型列表"
+			return result, nil
+		}
+		modelPath = result.Models[0].ID
+	}
+	result.ModelPath = modelPath
+	for _, model := range result.Models {
+		if model.ID == modelPath {
+			result.ModelListed = true
+			break
+		}
+	}
+	if len(result.Models) > 0 && !result.ModelListed {
+		ids := make([]string, len(result.Models))
+		for i, model := range result.Models {
+			ids[i] = model.ID
+		}
+		result.Warnings = append(result.Warnings, fmt.Sprintf("模型 %s 不在服务返回的模型列表中（可用: %s）", modelPath, strings.Join(ids, ", ")))
+	}
+	openAICompatible := result.Dialect == ModelDialectVLLM || result.Dialect == ModelDialectOpenAI
+	if req.IsVLLM != nil && openAICompatible && *req.IsVLLM != (result.Dialect == ModelDialectVLLM) {
+		if result.Dialect == ModelDialectVLLM {
+			result.Warnings = append(result.Warnings, "检测到 vLLM 服务，建议开启 is_vllm")
+		} else {
+			result.Warnings = append(result.Warnings, "服务不是 vLLM，建议关闭 is_vllm")
+		}
+	}
+
+	start = time.Now()
+	content, usage, err := probe.Complete(ctx, modelPath)
+	result.CompletionLatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+
+	reply := []rune(content)
+	if len(reply) > probeMaxReplyRunes {
+		reply = reply[:probeMaxReplyRunes]
+	}
+	result.Success = true
+	result.Reply = string(reply)
+	if usage.TotalTokens > 0 {
+		result.Usage = &usage
+	}
+	return result, nil
+}
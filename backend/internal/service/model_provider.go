@@ -0,0 +1,336 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+)
+
+// anthropicVersion Anthropic Messages API 的版本请求头
+const anthropicVersion = "2023-06-01"
+
+// defaultProviderMaxTokens Anthropic 要求必须指定 max_tokens，请求未指定时使用的默认值
+const defaultProviderMaxTokens = 2048
+
+// chatProvider 模型服务的对话接口协议：把代理请求转换为服务的请求格式，并把响应统一为回复内容和 Token 用量
+type chatProvider interface {
+	// CompletionURL 对话接口地址，apiURL 为模型配置中的 API 地址
+	CompletionURL(apiURL, model string) string
+	// Authorize 设置鉴权请求头，apiKey 为空时不设置
+	Authorize(header http.Header, apiKey string)
+	// BuildBody 构建请求体
+	BuildBody(req *dto.ModelCallProxyRequest) ([]byte, error)
+	// ParseResponse 解析 200 响应，返回回复内容和 Token 用量
+	ParseResponse(body []byte) (string, dto.Usage, error)
+}
+
+// providerFor 按模型配置的 provider 选择协议，未知或为空时使用 OpenAI 格式（vLLM 与 OpenAI 兼容）
+func providerFor(provider string) chatProvider {
+	switch provider {
+	case models.ModelProviderAnthropic:
+		return anthropicProvider{}
+	case models.ModelProviderGemini:
+		return geminiProvider{}
+	default:
+		return openAIProvider{}
+	}
+}
+
+// openAIProvider OpenAI Chat Completions 协议（vLLM 等兼容服务）
+type openAIProvider struct{}
+
+func (openAIProvider) CompletionURL(apiURL, model string) string {
+	return apiURL + "/chat/completions"
+}
+
+func (openAIProvider) Authorize(header http.Header, apiKey string) {
+	if apiKey != "" {
+		header.Set("Authorization", "Bearer "+apiKey)
+	}
+}
+
+func (openAIProvider) BuildBody(req *dto.ModelCallProxyRequest) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"model":       req.Model,
+		"messages":    req.Messages,
+		"max_tokens":  req.MaxTokens,
+		"temperature": req.Temperature,
+		"top_p":       req.TopP,
+	})
+}
+
+func (openAIProvider) ParseResponse(body []byte) (string, dto.Usage, error) {
+	var result dto.ModelCallResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", dto.Usage{}, fmt.Errorf("解析响应失败: %w", err)
+	}
+	if len(result.Choices) == 0 {
+		return "", dto.Usage{}, fmt.Errorf("API返回空响应")
+	}
+	return result.Choices[0].Message.Content, result.Usage, nil
+}
+
+// anthropicProvider Anthropic Messages API（apiURL 形如 https://api.anthropic.com/v1）
+// system 消息合并为顶层 system 字段，其余消息按原顺序发送
+type anthropicProvider struct{}
+
+func (anthropicProvider) CompletionURL(apiURL, model string) string {
+	return apiURL + "/messages"
+}
+
+func (anthropicProvider) Authorize(header http.Header, apiKey string) {
+	if apiKey != "" {
+		header.Set("x-api-key", apiKey)
+	}
+	header.Set("anthropic-version", anthropicVersion)
+}
+
+func (anthropicProvider) BuildBody(req *dto.ModelCallProxyRequest) ([]byte, error) {
+	system, messages := splitSystemMessages(req.Messages)
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = defaultProviderMaxTokens
+	}
+
+	body := map[string]interface{}{
+		"model":       req.Model,
+		"messages":    messages,
+		"max_tokens":  maxTokens,
+		"temperature": req.Temperature,
+	}
+	if system != "" {
+		body["system"] = system
+	}
+	// 部分 Claude 模型不允许同时指定 temperature 和 top_p，只在 top_p 生效（0 < top_p < 1）时发送
+	if req.TopP > 0 && req.TopP < 1 {
+		body["top_p"] = req.TopP
+	}
+	return json.Marshal(body)
+}
+
+func (anthropicProvider) ParseResponse(body []byte) (string, dto.Usage, error) {
+	var result struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		Usage struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", dto.Usage{}, fmt.Errorf("解析响应失败: %w", err)
+	}
+
+	var content strings.Builder
+	for _, block := range result.Content {
+		if block.Type == "text" {
+			content.WriteString(block.Text)
+		}
+	}
+	if content.Len() == 0 {
+		return "", dto.Usage{}, fmt.Errorf("API返回空响应")
+	}
+	return content.String(), dto.Usage{
+		PromptTokens:     result.Usage.InputTokens,
+		CompletionTokens: result.Usage.OutputTokens,
+		TotalTokens:      result.Usage.InputTokens + result.Usage.OutputTokens,
+	}, nil
+}
+
+// geminiProvider Google Gemini generateContent 接口（apiURL 形如 https://generativelanguage.googleapis.com/v1beta）
+// assistant 消息转换为 model 角色，system 消息合并为 systemInstruction
+type geminiProvider struct{}
+
+func (geminiProvider) CompletionURL(apiURL, model string) string {
+	return apiURL + "/models/" + strings.TrimPrefix(model, "models/") + ":generateContent"
+}
+
+func (geminiProvider) Authorize(header http.Header, apiKey string) {
+	if apiKey != "" {
+		header.Set("x-goog-api-key", apiKey)
+	}
+}
+
+func (geminiProvider) BuildBody(req *dto.ModelCallProxyRequest) ([]byte, error) {
+	type part struct {
+		Text string `json:"text"`
+	}
+	type content struct {
+		Role  string `json:"role,omitempty"`
+		Parts []part `json:"parts"`
+	}
+
+	system, messages := splitSystemMessages(req.Messages)
+	contents := make([]content, len(messages))
+	for i, msg := range messages {
+		role := msg.Role
+		if role == "assistant" {
+			role = "model"
+		}
+		contents[i] = content{Role: role, Parts: []part{{Text: msg.Content}}}
+	}
+
+	generationConfig := map[string]interface{}{
+		"temperature": req.Temperature,
+	}
+	if req.TopP > 0 {
+		generationConfig["topP"] = req.TopP
+	}
+	if req.MaxTokens > 0 {
+		generationConfig["maxOutputTokens"] = req.MaxTokens
+	}
+	body := map[string]interface{}{
+		"contents":         contents,
+		"generationConfig": generationConfig,
+	}
+	if system != "" {
+		body["systemInstruction"] = content{Parts: []part{{Text: system}}}
+	}
+	return json.Marshal(body)
+}
+
+func (geminiProvider) ParseResponse(body []byte) (string, dto.Usage, error) {
+	var result struct {
+		Candidates []struct {
+			Content struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+			FinishReason string `json:"finishReason"`
+		} `json:"candidates"`
+		PromptFeedback struct {
+			BlockReason string `json:"blockReason"`
+		} `json:"promptFeedback"`
+		UsageMetadata struct {
+			PromptTokenCount     int `json:"promptTokenCount"`
+			CandidatesTokenCount int `json:"candidatesTokenCount"`
+			TotalTokenCount      int `json:"totalTokenCount"`
+		} `json:"usageMetadata"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", dto.Usage{}, fmt.Errorf("解析响应失败: %w", err)
+	}
+	if result.PromptFeedback.BlockReason != "" {
+		return "", dto.Usage{}, fmt.Errorf("请求被 Gemini 拦截: %s", result.PromptFeedback.BlockReason)
+	}
+	if len(result.Candidates) == 0 {
+		return "", dto.Usage{}, fmt.Errorf("API返回空响应")
+	}
+
+	var content strings.Builder
+	for _, p := range result.Candidates[0].Content.Parts {
+		content.WriteString(p.Text)
+	}
+	if content.Len() == 0 {
+		return "", dto.Usage{}, fmt.Errorf("API返回空响应（finishReason=%s）", result.Candidates[0].FinishReason)
+	}
+	return content.String(), dto.Usage{
+		PromptTokens:     result.UsageMetadata.PromptTokenCount,
+		CompletionTokens: result.UsageMetadata.CandidatesTokenCount,
+		TotalTokens:      result.UsageMetadata.TotalTokenCount,
+	}, nil
+}
+
+// splitSystemMessages 把 system 消息合并为一段文本（空行分隔），返回合并结果和其余消息
+func splitSystemMessages(messages []dto.Message) (string, []dto.Message) {
+	var system []string
+	rest := make([]dto.Message, 0, len(messages))
+	for _, msg := range messages {
+		if msg.Role == "system" {
+			system = append(system, msg.Content)
+			continue
+		}
+		rest = append(rest, msg)
+	}
+	return strings.Join(system, "\n\n"), rest
+}
+
+// embeddingProvider 提供向量接口的协议（Anthropic 没有向量接口）
+type embeddingProvider interface {
+	// EmbeddingURL 向量接口地址
+	EmbeddingURL(apiURL, model string) string
+	// BuildEmbeddingBody 构建向量请求体
+	BuildEmbeddingBody(model string, input []string) ([]byte, error)
+	// ParseEmbeddingResponse 解析 200 响应，返回与输入顺序一致的向量和 Token 用量
+	ParseEmbeddingResponse(body []byte) ([][]float32, dto.Usage, error)
+}
+
+func (openAIProvider) EmbeddingURL(apiURL, model string) string {
+	return apiURL + "/embeddings"
+}
+
+func (openAIProvider) BuildEmbeddingBody(model string, input []string) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"model": model,
+		"input": input,
+	})
+}
+
+func (openAIProvider) ParseEmbeddingResponse(body []byte) ([][]float32, dto.Usage, error) {
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+		Usage dto.Usage `json:"usage"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, dto.Usage{}, fmt.Errorf("解析向量响应失败: %w", err)
+	}
+
+	vectors := make([][]float32, len(result.Data))
+	for _, item := range result.Data {
+		if item.Index < 0 || item.Index >= len(vectors) {
+			return nil, dto.Usage{}, fmt.Errorf("向量响应的下标超出范围: %d", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	return vectors, result.Usage, nil
+}
+
+func (geminiProvider) EmbeddingURL(apiURL, model string) string {
+	return apiURL + "/models/" + strings.TrimPrefix(model, "models/") + ":batchEmbedContents"
+}
+
+func (geminiProvider) BuildEmbeddingBody(model string, input []string) ([]byte, error) {
+	type part struct {
+		Text string `json:"text"`
+	}
+	type request struct {
+		Model   string `json:"model"`
+		Content struct {
+			Parts []part `json:"parts"`
+		} `json:"content"`
+	}
+
+	requests := make([]request, len(input))
+	for i, text := range input {
+		requests[i].Model = "models/" + strings.TrimPrefix(model, "models/")
+		requests[i].Content.Parts = []part{{Text: text}}
+	}
+	return json.Marshal(map[string]interface{}{"requests": requests})
+}
+
+func (geminiProvider) ParseEmbeddingResponse(body []byte) ([][]float32, dto.Usage, error) {
+	var result struct {
+		Embeddings []struct {
+			Values []float32 `json:"values"`
+		} `json:"embeddings"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, dto.Usage{}, fmt.Errorf("解析向量响应失败: %w", err)
+	}
+
+	vectors := make([][]float32, len(result.Embeddings))
+	for i, embedding := range result.Embeddings {
+		vectors[i] = embedding.Values
+	}
+	return vectors, dto.Usage{}, nil
+}
@@ -3,11 +3,11 @@ package service
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,28 +15,46 @@ import (
 	"gen-go/internal/dto"
 	"gen-go/internal/models"
 	"gen-go/internal/repository"
+	"gen-go/internal/tracing"
 	"gen-go/pkg/redis_limiter"
 
 	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// ModelRateKeyPrefix 模型 RPM/TPM 滑动窗口的 Redis 键前缀
+const ModelRateKeyPrefix = "model_rate:"
+
 // ModelService 模型服务
 type ModelService struct {
-	modelRepo   *repository.ModelConfigRepository
-	redisClient *redis.Client
-	cfg         *config.Config
+	modelRepo    *repository.ModelConfigRepository
+	usageRepo    *repository.ModelTokenUsageRepository
+	redisClient  *redis.Client
+	errorTracker *ErrorTracker
+	cfg          *config.Config
 	// 并发限制器映射，每个模型一个限制器
 	concurrencyLimiters map[string]*redis_limiter.RedisLimiter
 	limitersMu          sync.RWMutex
+	// RPM/TPM 速率限制器（与 TaskManager 调度共享同一滑动窗口）
+	rateLimiter *redis_limiter.RateLimiter
+	// 端点池：模型配置了多个服务地址时路由到负载最低的健康端点
+	endpointPool *EndpointPool
+	// 响应缓存（配置 model_services.cache_enabled 开启）
+	cache *ModelCache
 }
 
 // NewModelService 创建模型服务
-func NewModelService(modelRepo *repository.ModelConfigRepository, redisClient *redis.Client, cfg *config.Config) *ModelService {
+func NewModelService(modelRepo *repository.ModelConfigRepository, usageRepo *repository.ModelTokenUsageRepository, redisClient *redis.Client, errorTracker *ErrorTracker, cfg *config.Config) *ModelService {
 	s := &ModelService{
 		modelRepo:           modelRepo,
+		usageRepo:           usageRepo,
 		redisClient:         redisClient,
+		errorTracker:        errorTracker,
 		cfg:                 cfg,
 		concurrencyLimiters: make(map[string]*redis_limiter.RedisLimiter),
+		rateLimiter:         redis_limiter.NewRateLimiter(redisClient, ModelRateKeyPrefix, cfg.Redis.GetMaxWaitDuration()),
+		endpointPool:        NewEndpointPool(redisClient),
+		cache:               NewModelCache(redisClient, cfg),
 	}
 	return s
 }
@@ -50,23 +68,7 @@ func (s *ModelService) GetActiveModels() ([]dto.ModelConfigResponse, error) {
 
 	responses := make([]dto.ModelConfigResponse, len(models))
 	for i, model := range models {
-		responses[i] = dto.ModelConfigResponse{
-			ID:            model.ID,
-			Name:          model.Name,
-			APIURL:        model.APIURL,
-			APIKey:        model.APIKey,
-			ModelPath:     model.ModelPath,
-			MaxConcurrent: model.MaxConcurrent,
-			Temperature:   model.Temperature,
-			TopP:          model.TopP,
-			MaxTokens:     model.MaxTokens,
-			IsVLLM:        model.IsVLLM,
-			Timeout:       model.Timeout,
-			Description:   model.Description,
-			IsActive:      model.IsActive,
-			CreatedAt:     model.CreatedAt.Format("2006-01-02 15:04:05"),
-			UpdatedAt:     model.UpdatedAt.Format("2006-01-02 15:04:05"),
-		}
+		responses[i] = toModelConfigResponse(&model)
 	}
 
 	return responses, nil
@@ -82,23 +84,7 @@ func (s *ModelService) GetAllModels(page, perPage int) (*dto.PaginatedResponse,
 
 	responses := make([]dto.ModelConfigResponse, len(models))
 	for i, model := range models {
-		responses[i] = dto.ModelConfigResponse{
-			ID:            model.ID,
-			Name:          model.Name,
-			APIURL:        model.APIURL,
-			APIKey:        model.APIKey,
-			ModelPath:     model.ModelPath,
-			MaxConcurrent: model.MaxConcurrent,
-			Temperature:   model.Temperature,
-			TopP:          model.TopP,
-			MaxTokens:     model.MaxTokens,
-			IsVLLM:        model.IsVLLM,
-			Timeout:       model.Timeout,
-			Description:   model.Description,
-			IsActive:      model.IsActive,
-			CreatedAt:     model.CreatedAt.Format("2006-01-02 15:04:05"),
-			UpdatedAt:     model.UpdatedAt.Format("2006-01-02 15:04:05"),
-		}
+		responses[i] = toModelConfigResponse(&model)
 	}
 
 	return &dto.PaginatedResponse{
@@ -119,13 +105,17 @@ func (s *ModelService) CreateModel(req *dto.CreateModelConfigRequest) (*models.M
 	model := &models.ModelConfig{
 		Name:          req.Name,
 		APIURL:        req.APIURL,
+		Endpoints:     joinEndpoints(req.Endpoints),
 		APIKey:        req.APIKey,
 		ModelPath:     req.ModelPath,
 		MaxConcurrent: req.MaxConcurrent,
+		RPMLimit:      req.RPMLimit,
+		TPMLimit:      req.TPMLimit,
 		Temperature:   req.Temperature,
 		TopP:          req.TopP,
 		MaxTokens:     req.MaxTokens,
 		IsVLLM:        req.IsVLLM,
+		Provider:      req.Provider,
 		Timeout:       req.Timeout,
 		Description:   req.Description,
 		IsActive:      req.IsActive,
@@ -151,6 +141,9 @@ func (s *ModelService) UpdateModel(id uint, req *dto.UpdateModelConfigRequest) e
 	if req.APIURL != nil {
 		model.APIURL = *req.APIURL
 	}
+	if req.Endpoints != nil {
+		model.Endpoints = joinEndpoints(*req.Endpoints)
+	}
 	if req.APIKey != nil {
 		model.APIKey = *req.APIKey
 	}
@@ -160,6 +153,12 @@ func (s *ModelService) UpdateModel(id uint, req *dto.UpdateModelConfigRequest) e
 	if req.MaxConcurrent != nil {
 		model.MaxConcurrent = *req.MaxConcurrent
 	}
+	if req.RPMLimit != nil {
+		model.RPMLimit = *req.RPMLimit
+	}
+	if req.TPMLimit != nil {
+		model.TPMLimit = *req.TPMLimit
+	}
 	if req.Temperature != nil {
 		model.Temperature = *req.Temperature
 	}
@@ -172,6 +171,9 @@ func (s *ModelService) UpdateModel(id uint, req *dto.UpdateModelConfigRequest) e
 	if req.IsVLLM != nil {
 		model.IsVLLM = *req.IsVLLM
 	}
+	if req.Provider != nil {
+		model.Provider = *req.Provider
+	}
 	if req.Timeout != nil {
 		model.Timeout = *req.Timeout
 	}
@@ -190,8 +192,150 @@ func (s *ModelService) DeleteModel(id uint) error {
 	return s.modelRepo.Delete(id)
 }
 
-// CallModel 调用模型API（代理模式）
+// CacheStats 获取响应缓存的命中统计
+func (s *ModelService) CacheStats() ModelCacheStats {
+	return s.cache.Stats()
+}
+
+// GetModelEndpoints 获取模型端点池中各服务地址的负载和健康状态
+func (s *ModelService) GetModelEndpoints(id uint) (*dto.ModelEndpointsResponse, error) {
+	model, err := s.modelRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("模型不存在")
+	}
+
+	endpoints, err := s.endpointPool.Status(context.Background(), model.EndpointList())
+	if err != nil {
+		return nil, fmt.Errorf("读取端点状态失败: %w", err)
+	}
+
+	return &dto.ModelEndpointsResponse{
+		ModelID:   model.ID,
+		Name:      model.Name,
+		Endpoints: endpoints,
+	}, nil
+}
+
+// joinEndpoints 将服务地址列表保存为逗号分隔的字符串
+func joinEndpoints(endpoints []string) string {
+	cleaned := make([]string, 0, len(endpoints))
+	for _, url := range endpoints {
+		if url = strings.TrimSpace(url); url != "" {
+			cleaned = append(cleaned, url)
+		}
+	}
+	return strings.Join(cleaned, ",")
+}
+
+// toModelConfigResponse 转换为模型配置响应
+func toModelConfigResponse(model *models.ModelConfig) dto.ModelConfigResponse {
+	return dto.ModelConfigResponse{
+		ID:            model.ID,
+		Name:          model.Name,
+		APIURL:        model.APIURL,
+		Endpoints:     extraEndpoints(model),
+		APIKey:        model.APIKey,
+		ModelPath:     model.ModelPath,
+		MaxConcurrent: model.MaxConcurrent,
+		RPMLimit:      model.RPMLimit,
+		TPMLimit:      model.TPMLimit,
+		Temperature:   model.Temperature,
+		TopP:          model.TopP,
+		MaxTokens:     model.MaxTokens,
+		IsVLLM:        model.IsVLLM,
+		Provider:      model.Provider,
+		Timeout:       model.Timeout,
+		Description:   model.Description,
+		IsActive:      model.IsActive,
+		CreatedAt:     dto.FormatTime(model.CreatedAt),
+		UpdatedAt:     dto.FormatTime(model.UpdatedAt),
+	}
+}
+
+// extraEndpoints 模型除 APIURL 外的其他服务地址
+func extraEndpoints(model *models.ModelConfig) []string {
+	endpoints := model.EndpointList()
+	if len(endpoints) > 0 && endpoints[0] == model.APIURL {
+		return endpoints[1:]
+	}
+	return endpoints
+}
+
+// endpointCandidates 代理调用的候选服务地址：请求的地址属于模型的端点池时在整个池中路由，否则只使用请求的地址
+func endpointCandidates(modelConfig *models.ModelConfig, apiURL string) []string {
+	endpoints := modelConfig.EndpointList()
+	for _, url := range endpoints {
+		if url == apiURL {
+			return endpoints
+		}
+	}
+	return []string{apiURL}
+}
+
+// CallModel 调用模型API（代理模式），调用失败时计入错误统计
 func (s *ModelService) CallModel(req *dto.ModelCallProxyRequest) (*dto.ModelCallProxyResponse, error) {
+	return s.CallModelContext(context.Background(), req)
+}
+
+// CallModelContext 调用模型API，ctx 中的 trace 上下文会传递给上游模型服务
+func (s *ModelService) CallModelContext(ctx context.Context, req *dto.ModelCallProxyRequest) (*dto.ModelCallProxyResponse, error) {
+	ctx, span := tracing.Start(ctx, "ModelService.CallModel",
+		attribute.String("model", req.Model),
+		attribute.String("task_id", req.TaskID),
+	)
+
+	resp, err := s.callModel(ctx, req)
+	switch {
+	case err != nil:
+		tracing.End(span, err)
+	case !resp.Success:
+		s.errorTracker.RecordModelFailure(req.Model, resp.Error)
+		tracing.End(span, fmt.Errorf("%s", resp.Error))
+	default:
+		span.SetAttributes(attribute.Int("input_chars", resp.InputChars), attribute.Int("output_chars", resp.OutputChars), attribute.Bool("cached", resp.Cached))
+		tracing.End(span, nil)
+	}
+	return resp, err
+}
+
+// CallModelBatch 批量调用模型API：各请求并发执行（仍受各模型的限流和并发槽位约束），结果按请求顺序返回
+func (s *ModelService) CallModelBatch(ctx context.Context, reqs []dto.ModelCallProxyRequest) []dto.ModelCallProxyResponse {
+	results := make([]dto.ModelCallProxyResponse, len(reqs))
+	var wg sync.WaitGroup
+	for i := range reqs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := s.CallModelContext(ctx, &reqs[i])
+			if err != nil {
+				results[i] = dto.ModelCallProxyResponse{Success: false, Error: err.Error()}
+				return
+			}
+			results[i] = *resp
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// callModel 调用模型API的具体实现，traceCtx 仅用于链路追踪（限流和请求不随调用方取消）
+func (s *ModelService) callModel(traceCtx context.Context, req *dto.ModelCallProxyRequest) (*dto.ModelCallProxyResponse, error) {
+	// 命中响应缓存时直接返回，不占用限流额度和并发槽位，也不计入任务字符数
+	cached, hit, storeCache := s.cache.Get(context.Background(), req)
+	if hit {
+		inputChars := 0
+		for _, msg := range req.Messages {
+			inputChars += len([]rune(msg.Content))
+		}
+		return &dto.ModelCallProxyResponse{
+			Success:     true,
+			Content:     cached,
+			InputChars:  inputChars,
+			OutputChars: len([]rune(cached)),
+			Cached:      true,
+		}, nil
+	}
+
 	// 根据模型名称查找模型配置以获取最大并发数
 	modelConfig, err := s.getModelConfigByName(req.Model)
 	if err != nil {
@@ -200,12 +344,33 @@ func (s *ModelService) CallModel(req *dto.ModelCallProxyRequest) (*dto.ModelCall
 		modelConfig = &models.ModelConfig{MaxConcurrent: 10} // 默认值
 	}
 
+	// RPM/TPM 限流（在获取并发槽位之前等待，避免等待期间占用槽位）
+	ctx := context.Background()
+	rateKey := modelRateKey(modelConfig, req.Model)
+	if modelConfig.RPMLimit > 0 || modelConfig.TPMLimit > 0 {
+		_, rateSpan := tracing.Start(traceCtx, "ModelService.WaitRateLimit",
+			attribute.Int("rpm_limit", modelConfig.RPMLimit),
+			attribute.Int("tpm_limit", modelConfig.TPMLimit),
+		)
+		err = s.rateLimiter.Acquire(ctx, rateKey, modelConfig.RPMLimit, modelConfig.TPMLimit)
+		tracing.End(rateSpan, err)
+		if err != nil {
+			log.Printf("[CallModel] 速率限制等待失败: %v", err)
+			return &dto.ModelCallProxyResponse{
+				Success: false,
+				Error:   fmt.Sprintf("速率限制等待失败: %v", err),
+			}, nil
+		}
+	}
+
 	// 获取或创建Redis并发限制器
 	limiter := s.getOrCreateLimiter(req.Model, modelConfig.MaxConcurrent)
 
 	// 获取并发槽位
-	ctx := context.Background()
-	if err := limiter.Acquire(ctx, req.Model); err != nil {
+	_, acquireSpan := tracing.Start(traceCtx, "ModelService.AcquireSlot", attribute.Int("max_concurrent", modelConfig.MaxConcurrent))
+	err = limiter.Acquire(ctx, req.Model)
+	tracing.End(acquireSpan, err)
+	if err != nil {
 		log.Printf("[CallModel] 获取并发槽位失败: %v", err)
 		return &dto.ModelCallProxyResponse{
 			Success: false,
@@ -214,31 +379,14 @@ func (s *ModelService) CallModel(req *dto.ModelCallProxyRequest) (*dto.ModelCall
 	}
 	defer limiter.Release(ctx, req.Model)
 
-	// 构建消息
-	messages := make([]dto.Message, len(req.Messages))
-	for i, msg := range req.Messages {
-		messages[i] = dto.Message{
-			Role:    msg.Role,
-			Content: msg.Content,
-		}
-	}
-
-	// 构建请求体
-	reqBody := map[string]interface{}{
-		"model":    req.Model,
-		"messages": messages,
-	}
-
-	if !req.IsVLLM {
-		// OpenAI格式
-		reqBody["max_tokens"] = req.MaxTokens
-	} else {
-		// vLLM格式
-		reqBody["max_tokens"] = req.MaxTokens
-	}
+	// 从端点池中选择负载最低的健康端点，请求结束后归还并记录延迟；网络错误和 5xx 计为端点失败
+	apiURL, releaseEndpoint := s.endpointPool.Acquire(ctx, endpointCandidates(modelConfig, req.APIUrl))
+	requestStart := time.Now()
+	endpointErr := ""
+	defer func() { releaseEndpoint(time.Since(requestStart), endpointErr) }()
 
-	reqBody["temperature"] = req.Temperature
-	reqBody["top_p"] = req.TopP
+	// 按模型配置的协议构建请求（OpenAI/vLLM、Anthropic、Gemini）
+	provider := providerFor(modelConfig.Provider)
 
 	// 计算输入字符数（实际字符数，按UTF-8计算）
 	inputChars := 0
@@ -247,7 +395,7 @@ func (s *ModelService) CallModel(req *dto.ModelCallProxyRequest) (*dto.ModelCall
 	}
 
 	// 转换请求体为JSON
-	jsonBody, err := json.Marshal(reqBody)
+	jsonBody, err := provider.BuildBody(req)
 	if err != nil {
 		log.Printf("[CallModel] 序列化请求失败: %v", err)
 		return &dto.ModelCallProxyResponse{
@@ -257,7 +405,7 @@ func (s *ModelService) CallModel(req *dto.ModelCallProxyRequest) (*dto.ModelCall
 	}
 
 	// 构建HTTP请求
-	url := req.APIUrl + "/chat/completions"
+	url := provider.CompletionURL(apiURL, req.Model)
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		log.Printf("[CallModel] 创建请求失败: %v", err)
@@ -269,9 +417,8 @@ func (s *ModelService) CallModel(req *dto.ModelCallProxyRequest) (*dto.ModelCall
 
 	// 设置请求头
 	httpReq.Header.Set("Content-Type", "application/json")
-	if req.APIKey != "" {
-		httpReq.Header.Set("Authorization", "Bearer "+req.APIKey)
-	}
+	provider.Authorize(httpReq.Header, config.ResolveSecret(req.APIKey))
+	tracing.InjectHTTP(traceCtx, httpReq.Header)
 
 	// 创建HTTP客户端
 	client := &http.Client{
@@ -281,6 +428,7 @@ func (s *ModelService) CallModel(req *dto.ModelCallProxyRequest) (*dto.ModelCall
 	// 发送请求
 	resp, err := client.Do(httpReq)
 	if err != nil {
+		endpointErr = err.Error()
 		log.Printf("[CallModel] 请求失败: %v", err)
 		return &dto.ModelCallProxyResponse{
 			Success: false,
@@ -292,6 +440,7 @@ func (s *ModelService) CallModel(req *dto.ModelCallProxyRequest) (*dto.ModelCall
 	// 读取响应
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
+		endpointErr = err.Error()
 		log.Printf("[CallModel] 读取响应失败: %v", err)
 		return &dto.ModelCallProxyResponse{
 			Success: false,
@@ -301,6 +450,9 @@ func (s *ModelService) CallModel(req *dto.ModelCallProxyRequest) (*dto.ModelCall
 
 	// 检查HTTP状态码
 	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode >= http.StatusInternalServerError {
+			endpointErr = fmt.Sprintf("status=%d", resp.StatusCode)
+		}
 		log.Printf("[CallModel] API返回错误: status=%d, body=%s", resp.StatusCode, string(body))
 		return &dto.ModelCallProxyResponse{
 			Success: false,
@@ -308,30 +460,36 @@ func (s *ModelService) CallModel(req *dto.ModelCallProxyRequest) (*dto.ModelCall
 		}, nil
 	}
 
-	// 解析响应
-	var result dto.ModelCallResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		log.Printf("[CallModel] 解析响应失败: %v", err)
+	// 解析响应，统一为回复内容和Token用量
+	content, usage, err := provider.ParseResponse(body)
+	if err != nil {
+		log.Printf("[CallModel] %v", err)
 		return &dto.ModelCallProxyResponse{
 			Success: false,
-			Error:   fmt.Sprintf("解析响应失败: %v", err),
+			Error:   err.Error(),
 		}, nil
 	}
 
-	// 提取内容
-	if len(result.Choices) == 0 {
-		return &dto.ModelCallProxyResponse{
-			Success: false,
-			Error:   "API返回空响应",
-		}, nil
+	// 计算输出字符数（实际字符数，按UTF-8计算）
+	outputChars := len([]rune(content))
+
+	// 计入 TPM 窗口：上游未返回 usage 时按字符数保守估算
+	tokens := usage.TotalTokens
+	if tokens <= 0 {
+		tokens = inputChars + outputChars
+	}
+	if modelConfig.TPMLimit > 0 {
+		s.rateLimiter.RecordTokens(ctx, rateKey, tokens)
 	}
 
-	content := result.Choices[0].Message.Content
+	if storeCache {
+		s.cache.Set(ctx, req, content, tokens)
+	}
 
-	// 计算输出字符数（实际字符数，按UTF-8计算）
-	outputChars := len([]rune(content))
+	// 上游返回的Token用量按模型和日期累计
+	go s.recordTokenUsage(req.Model, usage)
 
-	// 如果提供了task_id，则累加字符数到Redis
+	// 如果提供了task_id，则累加字符数和Token用量到Redis
 	if req.TaskID != "" {
 		go func() {
 			redisKey := fmt.Sprintf("task_progress:%s", req.TaskID)
@@ -339,6 +497,10 @@ func (s *ModelService) CallModel(req *dto.ModelCallProxyRequest) (*dto.ModelCall
 			pipe := s.redisClient.Pipeline()
 			pipe.HIncrBy(ctx, redisKey, "input_chars", int64(inputChars))
 			pipe.HIncrBy(ctx, redisKey, "output_chars", int64(outputChars))
+			if usage.PromptTokens > 0 || usage.CompletionTokens > 0 {
+				pipe.HIncrBy(ctx, redisKey, "prompt_tokens", int64(usage.PromptTokens))
+				pipe.HIncrBy(ctx, redisKey, "completion_tokens", int64(usage.CompletionTokens))
+			}
 			pipe.Expire(ctx, redisKey, 24*time.Hour)
 			_, err := pipe.Exec(ctx)
 			if err != nil {
@@ -350,13 +512,74 @@ func (s *ModelService) CallModel(req *dto.ModelCallProxyRequest) (*dto.ModelCall
 	}
 
 	return &dto.ModelCallProxyResponse{
-		Success:     true,
-		Content:     content,
-		InputChars:  inputChars,
-		OutputChars: outputChars,
+		Success:          true,
+		Content:          content,
+		InputChars:       inputChars,
+		OutputChars:      outputChars,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
 	}, nil
 }
 
+// recordTokenUsage 累计模型当天的调用次数和Token用量
+func (s *ModelService) recordTokenUsage(model string, usage dto.Usage) {
+	if s.usageRepo == nil {
+		return
+	}
+	date := time.Now().UTC().Format("2006-01-02")
+	if err := s.usageRepo.Add(model, date, 1, int64(usage.PromptTokens), int64(usage.CompletionTokens)); err != nil {
+		log.Printf("[CallModel] 记录模型 %s 的Token用量失败: %v", model, err)
+	}
+}
+
+// GetTokenUsage 获取最近 days 天各模型的Token用量（按模型汇总，并附每日明细）
+func (s *ModelService) GetTokenUsage(days int) (*dto.ModelTokenUsageResponse, error) {
+	since := time.Now().UTC().AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+	usages, err := s.usageRepo.ListSince(since)
+	if err != nil {
+		return nil, fmt.Errorf("查询Token用量失败: %w", err)
+	}
+
+	resp := &dto.ModelTokenUsageResponse{
+		Since:  since,
+		Models: []dto.ModelTokenUsageSummary{},
+		Daily:  make([]dto.ModelTokenUsageDaily, 0, len(usages)),
+	}
+	index := map[string]int{}
+	for _, usage := range usages {
+		resp.Daily = append(resp.Daily, dto.ModelTokenUsageDaily{
+			Date:             usage.Date,
+			Model:            usage.Model,
+			Calls:            usage.Calls,
+			PromptTokens:     usage.PromptTokens,
+			CompletionTokens: usage.CompletionTokens,
+		})
+
+		i, ok := index[usage.Model]
+		if !ok {
+			i = len(resp.Models)
+			index[usage.Model] = i
+			resp.Models = append(resp.Models, dto.ModelTokenUsageSummary{Model: usage.Model})
+		}
+		summary := &resp.Models[i]
+		summary.Calls += usage.Calls
+		summary.PromptTokens += usage.PromptTokens
+		summary.CompletionTokens += usage.CompletionTokens
+		summary.TotalTokens += usage.PromptTokens + usage.CompletionTokens
+	}
+	return resp, nil
+}
+
+// ApplyConfig 配置重新加载后更新限流器的最大等待时间，并发限制器在下次使用时按新配置重建
+func (s *ModelService) ApplyConfig(cfg *config.Config) {
+	s.rateLimiter.SetMaxWaitTime(cfg.Redis.GetMaxWaitDuration())
+
+	s.limitersMu.Lock()
+	s.concurrencyLimiters = make(map[string]*redis_limiter.RedisLimiter)
+	s.limitersMu.Unlock()
+}
+
 // getOrCreateLimiter 获取或创建并发限制器
 func (s *ModelService) getOrCreateLimiter(modelKey string, maxConcurrent int) *redis_limiter.RedisLimiter {
 	s.limitersMu.Lock()
@@ -372,7 +595,7 @@ func (s *ModelService) getOrCreateLimiter(modelKey string, maxConcurrent int) *r
 	}
 
 	// 从配置获取最大等待时间
-	maxWaitTime := s.cfg.Redis.GetMaxWaitDuration()
+	maxWaitTime := s.cfg.Current().Redis.GetMaxWaitDuration()
 
 	// 创建新的Redis限制器
 	limiter := redis_limiter.NewRedisLimiter(s.redisClient, maxConcurrent, "model_concurrent:", time.Duration(300)*time.Second, maxWaitTime)
@@ -384,6 +607,14 @@ func (s *ModelService) getOrCreateLimiter(modelKey string, maxConcurrent int) *r
 	return limiter
 }
 
+// modelRateKey 模型速率限制键：优先使用模型路径，与任务调度时使用的键保持一致
+func modelRateKey(modelConfig *models.ModelConfig, modelName string) string {
+	if modelConfig != nil && modelConfig.ModelPath != "" {
+		return modelConfig.ModelPath
+	}
+	return modelName
+}
+
 // getModelConfigByName 根据模型名称查找模型配置
 func (s *ModelService) getModelConfigByName(modelName string) (*models.ModelConfig, error) {
 	// 通过模型名称查询模型配置，这里使用ModelPath字段匹配
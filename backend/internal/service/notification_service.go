@@ -0,0 +1,444 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+)
+
+// notifyEventNames 通知中显示的事件名称
+var notifyEventNames = map[string]string{
+	models.WebhookEventTaskStarted:  "已开始",
+	models.WebhookEventTaskFinished: "已完成",
+	models.WebhookEventTaskError:    "失败",
+	models.WebhookEventTaskStopped:  "已停止",
+}
+
+// NotificationService 通知服务
+// 任务开始、完成、失败、停止时按用户的订阅通过邮件、Slack、钉钉或企业微信机器人发送通知；
+// 任务结束、审核分配和管理员公告同时写入站内通知（通知中心）
+type NotificationService struct {
+	subRepo           *repository.NotificationSubscriptionRepository
+	notificationRepo  *repository.NotificationRepository
+	taskRepo          *repository.TaskRepository
+	fileRepo          *repository.DataFileRepository
+	generatedDataRepo *repository.GeneratedDataRepository
+	userRepo          *repository.UserRepository
+	cfg               *config.Config
+}
+
+// NewNotificationService 创建通知服务
+func NewNotificationService(
+	subRepo *repository.NotificationSubscriptionRepository,
+	notificationRepo *repository.NotificationRepository,
+	taskRepo *repository.TaskRepository,
+	fileRepo *repository.DataFileRepository,
+	generatedDataRepo *repository.GeneratedDataRepository,
+	userRepo *repository.UserRepository,
+	cfg *config.Config,
+) *NotificationService {
+	return &NotificationService{
+		subRepo:           subRepo,
+		notificationRepo:  notificationRepo,
+		taskRepo:          taskRepo,
+		fileRepo:          fileRepo,
+		generatedDataRepo: generatedDataRepo,
+		userRepo:          userRepo,
+		cfg:               cfg,
+	}
+}
+
+// CreateSubscription 订阅任务通知
+func (s *NotificationService) CreateSubscription(userID uint, req *dto.CreateNotificationSubscriptionRequest) (*dto.NotificationSubscriptionResponse, error) {
+	if req.TaskID != "" {
+		if _, err := s.taskRepo.GetVisibleByTaskID(req.TaskID, userID); err != nil {
+			return nil, fmt.Errorf("任务不存在或无权访问")
+		}
+	}
+
+	target, err := s.validateTarget(req.Channel, req.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	events := req.Events
+	if len(events) == 0 {
+		events = models.NotifyDefaultEvents
+	}
+	normalized, err := normalizeWebhookEvents(events)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &models.NotificationSubscription{
+		UserID:   userID,
+		TaskID:   req.TaskID,
+		Channel:  req.Channel,
+		Target:   target,
+		Secret:   req.Secret,
+		Events:   normalized,
+		IsActive: true,
+	}
+	if err := s.subRepo.Create(sub); err != nil {
+		return nil, fmt.Errorf("创建通知订阅失败: %w", err)
+	}
+	return toNotificationSubscriptionResponse(sub), nil
+}
+
+// ListSubscriptions 获取用户的通知订阅
+func (s *NotificationService) ListSubscriptions(userID uint) ([]*dto.NotificationSubscriptionResponse, error) {
+	subs, err := s.subRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.NotificationSubscriptionResponse, len(subs))
+	for i := range subs {
+		result[i] = toNotificationSubscriptionResponse(&subs[i])
+	}
+	return result, nil
+}
+
+// UpdateSubscription 更新通知订阅
+func (s *NotificationService) UpdateSubscription(id uint, userID uint, req *dto.UpdateNotificationSubscriptionRequest) (*dto.NotificationSubscriptionResponse, error) {
+	sub, err := s.subRepo.GetByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("通知订阅不存在或无权访问")
+	}
+
+	if req.Target != nil {
+		target, err := s.validateTarget(sub.Channel, *req.Target)
+		if err != nil {
+			return nil, err
+		}
+		sub.Target = target
+	}
+	if req.Secret != nil {
+		sub.Secret = *req.Secret
+	}
+	if req.Events != nil {
+		if len(req.Events) == 0 {
+			return nil, fmt.Errorf("至少需要订阅一个事件")
+		}
+		events, err := normalizeWebhookEvents(req.Events)
+		if err != nil {
+			return nil, err
+		}
+		sub.Events = events
+	}
+	if req.IsActive != nil {
+		sub.IsActive = *req.IsActive
+	}
+
+	if err := s.subRepo.Update(sub); err != nil {
+		return nil, fmt.Errorf("更新通知订阅失败: %w", err)
+	}
+	return toNotificationSubscriptionResponse(sub), nil
+}
+
+// DeleteSubscription 删除通知订阅
+func (s *NotificationService) DeleteSubscription(id uint, userID uint) error {
+	if _, err := s.subRepo.GetByIDAndUserID(id, userID); err != nil {
+		return fmt.Errorf("通知订阅不存在或无权访问")
+	}
+	return s.subRepo.Delete(id)
+}
+
+// TestSubscription 同步发送一条测试通知，返回发送结果
+func (s *NotificationService) TestSubscription(id uint, userID uint) error {
+	sub, err := s.subRepo.GetByIDAndUserID(id, userID)
+	if err != nil {
+		return fmt.Errorf("通知订阅不存在或无权访问")
+	}
+
+	msg := &notifyMessage{
+		Title: "[数据生成] 测试通知",
+		Body:  fmt.Sprintf("这是一条测试通知，订阅渠道 %s 配置正确。\n发送时间: %s", sub.Channel, dto.FormatTime(time.Now())),
+	}
+	return s.send(sub, msg)
+}
+
+// DispatchTaskEvent 任务结束时向任务创建者写入站内通知，并向订阅了该事件的用户异步发送任务通知
+func (s *NotificationService) DispatchTaskEvent(event string, taskID string) {
+	task, err := s.taskRepo.GetByTaskID(taskID)
+	if err != nil {
+		log.Printf("[Notify] 获取任务 %s 失败，跳过 %s 通知: %v", taskID, event, err)
+		return
+	}
+
+	subs, err := s.subRepo.ListActiveForTask(task.UserID, task.TaskID)
+	if err != nil {
+		log.Printf("[Notify] 获取任务 %s 的通知订阅失败: %v", taskID, err)
+		return
+	}
+
+	var msg *notifyMessage
+	render := func() bool {
+		if msg == nil {
+			if msg, err = s.buildMessage(event, task); err != nil {
+				log.Printf("[Notify] 渲染任务 %s 的通知失败: %v", taskID, err)
+				return false
+			}
+		}
+		return true
+	}
+
+	if event != models.WebhookEventTaskStarted && render() {
+		s.NotifyUser(task.UserID, models.NotificationTypeTask, msg.Title, msg.Body, "/editor/"+url.PathEscape(task.TaskID), task.TaskID)
+	}
+
+	for i := range subs {
+		sub := subs[i]
+		if !sub.Subscribes(event) {
+			continue
+		}
+		if !render() {
+			return
+		}
+		go func() {
+			if err := s.send(&sub, msg); err != nil {
+				log.Printf("[Notify] 发送通知失败: subscription=%d, channel=%s, task=%s, err=%v", sub.ID, sub.Channel, taskID, err)
+			}
+		}()
+	}
+}
+
+// NotifyUser 写入一条站内通知（失败只记录日志，不影响调用方）
+func (s *NotificationService) NotifyUser(userID uint, notificationType, title, content, link, taskID string) {
+	notification := &models.Notification{
+		UserID:  userID,
+		Type:    notificationType,
+		Title:   title,
+		Content: content,
+		Link:    link,
+		TaskID:  taskID,
+	}
+	if err := s.notificationRepo.Create(notification); err != nil {
+		log.Printf("[Notify] 写入用户 %d 的站内通知失败: %v", userID, err)
+	}
+}
+
+// ListNotifications 分页获取用户的站内通知
+func (s *NotificationService) ListNotifications(userID uint, unreadOnly bool, page, perPage int) (*dto.PaginatedResponse, error) {
+	offset := (page - 1) * perPage
+	notifications, total, err := s.notificationRepo.ListByUserID(userID, unreadOnly, offset, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.NotificationResponse, len(notifications))
+	for i := range notifications {
+		items[i] = toNotificationResponse(&notifications[i])
+	}
+
+	return &dto.PaginatedResponse{
+		Items:   items,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	}, nil
+}
+
+// UnreadCount 获取用户的未读通知数
+func (s *NotificationService) UnreadCount(userID uint) (int64, error) {
+	return s.notificationRepo.CountUnread(userID)
+}
+
+// MarkRead 将通知标记为已读（ids 为空时标记全部），返回本次标记的条数
+func (s *NotificationService) MarkRead(userID uint, ids []uint) (int64, error) {
+	marked, err := s.notificationRepo.MarkRead(userID, ids)
+	if err != nil {
+		return 0, fmt.Errorf("标记已读失败: %w", err)
+	}
+	return marked, nil
+}
+
+// Announce 发布管理员公告，为每个接收用户写入一条站内通知，返回接收人数
+func (s *NotificationService) Announce(req *dto.CreateAnnouncementRequest) (int, error) {
+	userIDs := uniqueIDs(req.UserIDs)
+	if len(userIDs) == 0 {
+		var err error
+		if userIDs, err = s.userRepo.ListActiveIDs(); err != nil {
+			return 0, fmt.Errorf("获取用户列表失败: %w", err)
+		}
+	}
+
+	notifications := make([]models.Notification, len(userIDs))
+	for i, userID := range userIDs {
+		notifications[i] = models.Notification{
+			UserID:  userID,
+			Type:    models.NotificationTypeAnnouncement,
+			Title:   req.Title,
+			Content: req.Content,
+			Link:    req.Link,
+		}
+	}
+	if err := s.notificationRepo.CreateBatch(notifications); err != nil {
+		return 0, fmt.Errorf("发布公告失败: %w", err)
+	}
+	return len(notifications), nil
+}
+
+// send 通过订阅的渠道发送通知并记录结果
+func (s *NotificationService) send(sub *models.NotificationSubscription, msg *notifyMessage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.Current().Notify.GetTimeout())
+	defer cancel()
+
+	err := sendNotification(ctx, &s.cfg.Current().Notify, sub, msg)
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+	}
+	if updateErr := s.subRepo.UpdateSendResult(sub.ID, errMsg); updateErr != nil {
+		log.Printf("[Notify] 更新订阅 %d 的发送结果失败: %v", sub.ID, updateErr)
+	}
+	return err
+}
+
+// notifyTemplateData 通知模板可用的字段
+type notifyTemplateData struct {
+	Event          string
+	EventName      string
+	TaskID         string
+	Status         string
+	TaskType       string
+	InputFile      string
+	Model          string
+	DataCount      int64
+	ConfirmedCount int64
+	InputChars     int64
+	OutputChars    int64
+	StartedAt      string
+	FinishedAt     string
+	Duration       string
+	ErrorMessage   string
+	Link           string // 任务生成数据页面（可在页面中审核和下载）
+}
+
+// buildMessage 按配置的模板渲染任务通知
+func (s *NotificationService) buildMessage(event string, task *models.Task) (*notifyMessage, error) {
+	data := notifyTemplateData{
+		Event:        event,
+		EventName:    notifyEventNames[event],
+		TaskID:       task.TaskID,
+		Status:       string(task.Status),
+		InputChars:   task.InputChars,
+		OutputChars:  task.OutputChars,
+		StartedAt:    dto.FormatTime(task.StartedAt),
+		ErrorMessage: task.ErrorMessage,
+	}
+	data.TaskType, _ = task.Params["task_type"].(string)
+	data.Model, _ = task.Params["model_path"].(string)
+
+	if fileID, ok := inputFileID(task); ok {
+		if id, err := strconv.ParseUint(fileID, 10, 64); err == nil {
+			if file, err := s.fileRepo.GetByID(uint(id)); err == nil {
+				data.InputFile = file.Filename
+			}
+		}
+	}
+
+	if counts, err := s.generatedDataRepo.CountByTaskIDs([]string{task.TaskID}); err == nil {
+		data.DataCount = counts[task.TaskID].DataCount
+		data.ConfirmedCount = counts[task.TaskID].ConfirmedCount
+	}
+
+	end := time.Now()
+	if task.FinishedAt != nil {
+		end = *task.FinishedAt
+		data.FinishedAt = dto.FormatTime(*task.FinishedAt)
+	}
+	data.Duration = end.Sub(task.StartedAt).Round(time.Second).String()
+
+	if base := strings.TrimRight(s.cfg.Current().Notify.LinkBaseURL, "/"); base != "" {
+		data.Link = base + "/editor/" + url.PathEscape(task.TaskID)
+	}
+
+	title, err := renderNotifyTemplate("title", s.cfg.Current().Notify.TitleTemplate, &data)
+	if err != nil {
+		return nil, err
+	}
+	body, err := renderNotifyTemplate("body", s.cfg.Current().Notify.BodyTemplate, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &notifyMessage{Title: strings.TrimSpace(title), Body: strings.TrimSpace(body)}, nil
+}
+
+// renderNotifyTemplate 渲染通知模板
+func renderNotifyTemplate(name, text string, data *notifyTemplateData) (string, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("解析通知模板 %s 失败: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("渲染通知模板 %s 失败: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// validateTarget 校验通知目标：邮件渠道为邮箱地址（需配置 SMTP），其他渠道为 http(s) 地址
+func (s *NotificationService) validateTarget(channel, target string) (string, error) {
+	target = strings.TrimSpace(target)
+	if channel == models.NotifyChannelEmail {
+		if !s.cfg.Current().Notify.SMTP.Enabled() {
+			return "", fmt.Errorf("系统未配置 SMTP 服务器，不能订阅邮件通知")
+		}
+		addr, err := mail.ParseAddress(target)
+		if err != nil {
+			return "", fmt.Errorf("无效的邮箱地址: %s", target)
+		}
+		return addr.Address, nil
+	}
+
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("无效的机器人 Webhook 地址: %s", target)
+	}
+	return target, nil
+}
+
+// toNotificationResponse 转换站内通知响应
+func toNotificationResponse(notification *models.Notification) dto.NotificationResponse {
+	return dto.NotificationResponse{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		Title:     notification.Title,
+		Content:   notification.Content,
+		Link:      notification.Link,
+		TaskID:    notification.TaskID,
+		Read:      notification.ReadAt != nil,
+		ReadAt:    dto.FormatTimePtr(notification.ReadAt),
+		CreatedAt: dto.FormatTime(notification.CreatedAt),
+	}
+}
+
+// toNotificationSubscriptionResponse 转换通知订阅响应（不含加签密钥）
+func toNotificationSubscriptionResponse(sub *models.NotificationSubscription) *dto.NotificationSubscriptionResponse {
+	return &dto.NotificationSubscriptionResponse{
+		ID:         sub.ID,
+		TaskID:     sub.TaskID,
+		Channel:    sub.Channel,
+		Target:     sub.Target,
+		HasSecret:  sub.Secret != "",
+		Events:     sub.EventList(),
+		IsActive:   sub.IsActive,
+		LastSentAt: dto.FormatTimePtr(sub.LastSentAt),
+		LastError:  sub.LastError,
+		CreatedAt:  dto.FormatTime(sub.CreatedAt),
+		UpdatedAt:  dto.FormatTime(sub.UpdatedAt),
+	}
+}
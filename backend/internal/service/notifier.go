@@ -0,0 +1,211 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/models"
+)
+
+// notifyMaxResponseBytes 读取机器人响应的最大字节数
+const notifyMaxResponseBytes = 2048
+
+// notifyMessage 渲染后的通知内容
+type notifyMessage struct {
+	Title string
+	Body  string
+}
+
+// sendNotification 按订阅的渠道发送一条通知
+func sendNotification(ctx context.Context, cfg *config.NotifyConfig, sub *models.NotificationSubscription, msg *notifyMessage) error {
+	switch sub.Channel {
+	case models.NotifyChannelEmail:
+		return sendEmail(ctx, &cfg.SMTP, sub.Target, msg)
+	case models.NotifyChannelSlack:
+		return postNotification(ctx, sub.Target, map[string]interface{}{
+			"text": "*" + msg.Title + "*\n" + msg.Body,
+		}, false)
+	case models.NotifyChannelDingTalk:
+		target, err := signDingTalkURL(sub.Target, sub.Secret)
+		if err != nil {
+			return err
+		}
+		return postNotification(ctx, target, map[string]interface{}{
+			"msgtype": "markdown",
+			"markdown": map[string]string{
+				"title": msg.Title,
+				"text":  "### " + msg.Title + "\n\n" + markdownLines(msg.Body),
+			},
+		}, true)
+	case models.NotifyChannelWeCom:
+		return postNotification(ctx, sub.Target, map[string]interface{}{
+			"msgtype": "markdown",
+			"markdown": map[string]string{
+				"content": "**" + msg.Title + "**\n" + msg.Body,
+			},
+		}, true)
+	default:
+		return fmt.Errorf("不支持的通知渠道: %s", sub.Channel)
+	}
+}
+
+// postNotification 向机器人 Webhook 发送 JSON 消息
+// checkErrCode 为 true 时按钉钉/企业微信的约定检查响应中的 errcode（HTTP 200 也可能发送失败）
+func postNotification(ctx context.Context, target string, payload interface{}, checkErrCode bool) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化通知失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("构建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, notifyMaxResponseBytes))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("响应状态码 %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if checkErrCode {
+		var result struct {
+			ErrCode int    `json:"errcode"`
+			ErrMsg  string `json:"errmsg"`
+		}
+		if err := json.Unmarshal(respBody, &result); err == nil && result.ErrCode != 0 {
+			return fmt.Errorf("机器人返回错误 %d: %s", result.ErrCode, result.ErrMsg)
+		}
+	}
+	return nil
+}
+
+// signDingTalkURL 钉钉机器人开启加签时在地址后附加 timestamp 和 sign 参数
+func signDingTalkURL(target, secret string) (string, error) {
+	if secret == "" {
+		return target, nil
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", fmt.Errorf("无效的机器人 Webhook 地址: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+
+	query := u.Query()
+	query.Set("timestamp", timestamp)
+	query.Set("sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// markdownLines 钉钉 markdown 需要两个换行才会分行
+func markdownLines(text string) string {
+	return strings.ReplaceAll(text, "\n", "\n\n")
+}
+
+// sendEmail 通过 SMTP 发送纯文本邮件
+func sendEmail(ctx context.Context, cfg *config.SMTPConfig, to string, msg *notifyMessage) error {
+	if !cfg.Enabled() {
+		return fmt.Errorf("系统未配置 SMTP 服务器")
+	}
+
+	dialer := &net.Dialer{}
+	var conn net.Conn
+	var err error
+	if cfg.UseTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: cfg.Host}}).DialContext(ctx, "tcp", cfg.GetAddress())
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", cfg.GetAddress())
+	}
+	if err != nil {
+		return fmt.Errorf("连接 SMTP 服务器失败: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("连接 SMTP 服务器失败: %w", err)
+	}
+	defer client.Close()
+
+	if !cfg.UseTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: cfg.Host}); err != nil {
+				return fmt.Errorf("STARTTLS 失败: %w", err)
+			}
+		}
+	}
+	if cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)); err != nil {
+			return fmt.Errorf("SMTP 认证失败: %w", err)
+		}
+	}
+
+	from := cfg.GetFrom()
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("设置发件人失败: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("设置收件人失败: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("发送邮件失败: %w", err)
+	}
+	if _, err := w.Write(buildEmail(from, to, msg)); err != nil {
+		w.Close()
+		return fmt.Errorf("发送邮件失败: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("发送邮件失败: %w", err)
+	}
+	return client.Quit()
+}
+
+// buildEmail 构建 UTF-8 纯文本邮件（标题按 RFC 2047 编码，正文 base64 编码）
+func buildEmail(from, to string, msg *notifyMessage) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Title))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(msg.Body))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// objectUploadTimeout 单次上传到对象存储的超时时间
+const objectUploadTimeout = 30 * time.Minute
+
+// ObjectStorageService S3 兼容对象存储服务
+// 导出数据集直接写入配置的存储桶，返回签名下载链接供下游训练任务拉取，不再经由后端下载
+type ObjectStorageService struct {
+	client *minio.Client
+	cfg    *config.Config
+}
+
+// NewObjectStorageService 创建对象存储服务（未配置或配置无效时上传返回错误）
+func NewObjectStorageService(cfg *config.Config) *ObjectStorageService {
+	s := &ObjectStorageService{cfg: cfg}
+	if !cfg.ObjectStore.IsConfigured() {
+		return s
+	}
+
+	client, err := minio.New(cfg.ObjectStore.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.ObjectStore.AccessKeyID, cfg.ObjectStore.SecretAccessKey, ""),
+		Secure: cfg.ObjectStore.UseSSL,
+		Region: cfg.ObjectStore.Region,
+	})
+	if err != nil {
+		log.Printf("[ObjectStorage] 初始化对象存储客户端失败: %v", err)
+		return s
+	}
+	s.client = client
+	return s
+}
+
+// Upload 将内容写入 <prefix>/<name>，返回对象信息和签名下载链接
+func (s *ObjectStorageService) Upload(name string, content []byte, contentType string) (*dto.StorageObjectResponse, error) {
+	if s.client == nil {
+		return nil, fmt.Errorf("未配置对象存储")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), objectUploadTimeout)
+	defer cancel()
+
+	bucket := s.cfg.Current().ObjectStore.Bucket
+	key := path.Join(strings.Trim(s.cfg.Current().ObjectStore.Prefix, "/"), name)
+	info, err := s.client.PutObject(ctx, bucket, key, bytes.NewReader(content), int64(len(content)), minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("上传到对象存储失败: %w", err)
+	}
+
+	expire := s.cfg.Current().ObjectStore.GetPresignExpire()
+	params := url.Values{}
+	params.Set("response-content-disposition", "attachment; filename*=UTF-8''"+url.PathEscape(path.Base(key)))
+	signedURL, err := s.client.PresignedGetObject(ctx, bucket, key, expire, params)
+	if err != nil {
+		return nil, fmt.Errorf("生成签名下载链接失败: %w", err)
+	}
+
+	return &dto.StorageObjectResponse{
+		Bucket:    bucket,
+		Key:       key,
+		Size:      info.Size,
+		ETag:      info.ETag,
+		URL:       signedURL.String(),
+		ExpiresAt: dto.FormatTime(time.Now().Add(expire)),
+	}, nil
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/models"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+// 自检结果状态
+const (
+	PreflightStatusOK   = "ok"
+	PreflightStatusWarn = "warn"
+	PreflightStatusFail = "fail"
+)
+
+// 自检限制
+const (
+	preflightTimeout      = 5 * time.Second
+	jwtSecretMinBits      = 128 // JWT 密钥估算熵低于该值时警告
+	jwtSecretHardFailBits = 64  // 低于该值视为严重问题
+)
+
+// PreflightCheck 单项自检结果
+// Hard 为 true 的项目失败时服务无法正常工作（生产模式下开启 preflight_strict 会拒绝启动）
+type PreflightCheck struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Hard     bool   `json:"hard"`
+	Message  string `json:"message"`
+	Duration int64  `json:"duration_ms"`
+}
+
+// PreflightReport 启动自检报告
+type PreflightReport struct {
+	Checks    []PreflightCheck `json:"checks"`
+	StartedAt time.Time        `json:"started_at"`
+}
+
+// HardFailures 返回失败的关键检查项
+func (r *PreflightReport) HardFailures() []PreflightCheck {
+	var failures []PreflightCheck
+	for _, check := range r.Checks {
+		if check.Hard && check.Status == PreflightStatusFail {
+			failures = append(failures, check)
+		}
+	}
+	return failures
+}
+
+// RunPreflight 执行启动自检：工作进程脚本、python3、Redis、数据库、clamd、模型服务地址解析、JWT 密钥强度
+func RunPreflight(cfg *config.Config, db *gorm.DB, redisClient *redis.Client) *PreflightReport {
+	report := &PreflightReport{StartedAt: time.Now()}
+
+	run := func(name string, hard bool, fn func() (string, string)) {
+		start := time.Now()
+		status, message := fn()
+		report.Checks = append(report.Checks, PreflightCheck{
+			Name:     name,
+			Status:   status,
+			Hard:     hard,
+			Message:  message,
+			Duration: time.Since(start).Milliseconds(),
+		})
+	}
+
+	// 工作节点模式下 main.py 运行在工作节点上，后端本机不需要 Python 环境
+	if cfg.Worker.Executor != config.WorkerExecutorAgent {
+		run("worker_script", true, func() (string, string) {
+			path := filepath.Join(cfg.ProjectRoot, "main.py")
+			if _, err := os.Stat(path); err != nil {
+				return PreflightStatusFail, fmt.Sprintf("project_root 下未找到 main.py: %s", path)
+			}
+			return PreflightStatusOK, path
+		})
+
+		run("python3", true, func() (string, string) {
+			path, err := exec.LookPath("python3")
+			if err != nil {
+				return PreflightStatusFail, "未找到可执行的 python3"
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+			defer cancel()
+			output, err := exec.CommandContext(ctx, path, "--version").CombinedOutput()
+			if err != nil {
+				return PreflightStatusFail, fmt.Sprintf("执行 python3 失败: %v", err)
+			}
+			return PreflightStatusOK, strings.TrimSpace(string(output))
+		})
+
+		run("worker_protocol", true, func() (string, string) {
+			handshake, err := NewWorkerProbe(cfg).Probe()
+			if err != nil {
+				return PreflightStatusFail, err.Error()
+			}
+			if err := handshake.CheckCompatible(); err != nil {
+				return PreflightStatusFail, err.Error()
+			}
+			return PreflightStatusOK, fmt.Sprintf("main.py 版本 %s，协议 v%d", handshake.WorkerVersion, handshake.ProtocolVersion)
+		})
+	}
+
+	run("redis", true, func() (string, string) {
+		if redisClient == nil {
+			return PreflightStatusFail, "未配置 Redis"
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+		defer cancel()
+		if err := redisClient.Ping(ctx).Err(); err != nil {
+			return PreflightStatusFail, fmt.Sprintf("Redis 无响应 (%s): %v", cfg.Redis.GetAddress(), err)
+		}
+		return PreflightStatusOK, cfg.Redis.GetAddress()
+	})
+
+	run("database", true, func() (string, string) {
+		if db == nil {
+			return PreflightStatusFail, "数据库未初始化"
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return PreflightStatusFail, fmt.Sprintf("获取数据库连接失败: %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+		defer cancel()
+		if err := sqlDB.PingContext(ctx); err != nil {
+			return PreflightStatusFail, fmt.Sprintf("数据库无响应: %v", err)
+		}
+		return PreflightStatusOK, cfg.Database.Driver
+	})
+
+	if cfg.VirusScan.Enabled {
+		run("clamav", false, func() (string, string) {
+			ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+			defer cancel()
+			if err := NewVirusScanService(cfg).Ping(ctx); err != nil {
+				return PreflightStatusWarn, fmt.Sprintf("clamd 无响应 (%s): %v", cfg.VirusScan.Address, err)
+			}
+			return PreflightStatusOK, cfg.VirusScan.Address
+		})
+	}
+
+	run("model_services_dns", false, func() (string, string) {
+		return checkModelServicesDNS(cfg, db)
+	})
+
+	run("jwt_secret", true, func() (string, string) {
+		bits := estimateEntropyBits(cfg.JWT.SecretKey)
+		switch {
+		case bits < jwtSecretHardFailBits:
+			return PreflightStatusFail, fmt.Sprintf("JWT 密钥强度过低（估算熵 %.0f bit），请更换为随机字符串", bits)
+		case bits < jwtSecretMinBits:
+			return PreflightStatusWarn, fmt.Sprintf("JWT 密钥强度偏低（估算熵 %.0f bit，建议至少 %d bit）", bits, jwtSecretMinBits)
+		}
+		return PreflightStatusOK, fmt.Sprintf("估算熵 %.0f bit", bits)
+	})
+
+	return report
+}
+
+// checkModelServicesDNS 解析配置和数据库中启用的模型服务地址的主机名
+func checkModelServicesDNS(cfg *config.Config, db *gorm.DB) (string, string) {
+	urls := append([]string{}, cfg.Model.DefaultServices...)
+	if db != nil {
+		var modelConfigs []models.ModelConfig
+		if err := db.Where("is_active = ?", true).Find(&modelConfigs).Error; err == nil {
+			for _, model := range modelConfigs {
+				urls = append(urls, model.APIURL)
+			}
+		}
+	}
+	if len(urls) == 0 {
+		return PreflightStatusWarn, "未配置模型服务地址"
+	}
+
+	hosts := make(map[string]bool)
+	var failed []string
+	for _, raw := range urls {
+		parsed, err := url.Parse(raw)
+		if err != nil || parsed.Hostname() == "" {
+			failed = append(failed, fmt.Sprintf("%s（地址无效）", raw))
+			continue
+		}
+		host := parsed.Hostname()
+		if hosts[host] || net.ParseIP(host) != nil {
+			continue
+		}
+		hosts[host] = true
+
+		ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+		_, err = net.DefaultResolver.LookupHost(ctx, host)
+		cancel()
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s（%v）", host, err))
+		}
+	}
+
+	if len(failed) > 0 {
+		return PreflightStatusWarn, "以下模型服务地址无法解析: " + strings.Join(failed, "; ")
+	}
+	return PreflightStatusOK, fmt.Sprintf("已检查 %d 个地址", len(urls))
+}
+
+// estimateEntropyBits 按字符频率估算字符串的香农熵（总 bit 数）
+func estimateEntropyBits(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+	perChar := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		perChar -= p * math.Log2(p)
+	}
+	return perChar * float64(total)
+}
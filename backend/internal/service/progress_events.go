@@ -0,0 +1,160 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"gen-go/internal/dto"
+)
+
+// ProgressSchemaVersion 支持的工作进程结构化进度事件版本
+// 需与 develop/progress_events.py 中的 EVENT_SCHEMA_VERSION 保持一致，更高版本的事件按普通输出处理
+const ProgressSchemaVersion = 1
+
+// 结构化进度事件类型
+// 格式: {"type": "event", "schema_version": 1, "event": "round_started", ...}
+const (
+	ProgressEventRoundStarted    = "round_started"    // round, total_rounds, total（本轮样本数）
+	ProgressEventBatchCompleted  = "batch_completed"  // round, batch, total_batches, samples（本批样本数）
+	ProgressEventSampleGenerated = "sample_generated" // count（本次新增的合格数据条数）
+	ProgressEventScoreAssigned   = "score_assigned"   // model_score, rule_score, passed
+	ProgressEventError           = "error"            // message
+)
+
+// workerEvent 工作进程输出的结构化进度事件
+type workerEvent struct {
+	Type          string   `json:"type"`
+	SchemaVersion int      `json:"schema_version"`
+	Event         string   `json:"event"`
+	Round         int      `json:"round"`
+	TotalRounds   int      `json:"total_rounds"`
+	Total         int      `json:"total"`
+	Batch         int      `json:"batch"`
+	TotalBatches  int      `json:"total_batches"`
+	Samples       int      `json:"samples"`
+	Count         int      `json:"count"`
+	ModelScore    *float64 `json:"model_score"`
+	RuleScore     *float64 `json:"rule_score"`
+	Passed        *bool    `json:"passed"`
+	Message       string   `json:"message"`
+}
+
+// taskProgressState 根据结构化事件累计的任务进度
+// 多个模型服务的事件会并发到达（标准输出和 gRPC），因此加锁访问
+type taskProgressState struct {
+	lock        sync.Mutex
+	round       int // 当前轮次（从 1 开始）
+	totalRounds int
+	roundTotal  int // 当前轮次的样本总数
+	roundDone   int // 当前轮次已处理的样本数
+	generated   int // 已生成的合格数据条数
+}
+
+// percent 任务整体完成百分比（调用方持有锁）
+func (s *taskProgressState) percent() float64 {
+	if s.totalRounds <= 0 {
+		return 0
+	}
+	completed := float64(s.round - 1)
+	if completed < 0 {
+		completed = 0
+	}
+	if s.roundTotal > 0 {
+		completed += float64(s.roundDone) / float64(s.roundTotal)
+	}
+	percent := completed / float64(s.totalRounds) * 100
+	if percent > 100 {
+		percent = 100
+	}
+	return percent
+}
+
+// apply 按事件更新进度，并将当前进度填入进度事件
+func (s *taskProgressState) apply(event *workerEvent, progressEvent *dto.ProgressEvent) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch event.Event {
+	case ProgressEventRoundStarted:
+		s.round = event.Round
+		s.totalRounds = event.TotalRounds
+		s.roundTotal = event.Total
+		s.roundDone = 0
+	case ProgressEventBatchCompleted:
+		s.roundDone += event.Samples
+		if s.roundTotal > 0 && s.roundDone > s.roundTotal {
+			s.roundDone = s.roundTotal
+		}
+	case ProgressEventSampleGenerated:
+		s.generated += event.Count
+	}
+
+	round, totalRounds, roundDone, roundTotal, generated := s.round, s.totalRounds, s.roundDone, s.roundTotal, s.generated
+	progressEvent.Round = &round
+	progressEvent.TotalRounds = &totalRounds
+	progressEvent.Progress = &roundDone
+	progressEvent.Total = &roundTotal
+	progressEvent.Generated = &generated
+	progressEvent.Percent = s.percent()
+}
+
+// parseWorkerEvent 尝试将一行输出解析为结构化进度事件
+func parseWorkerEvent(line string) (*workerEvent, bool) {
+	var event workerEvent
+	if err := json.Unmarshal([]byte(line), &event); err != nil {
+		return nil, false
+	}
+	if event.Type != "event" || event.Event == "" {
+		return nil, false
+	}
+	return &event, true
+}
+
+// handleWorkerEvent 将结构化进度事件转换为带进度字段的 ProgressEvent 并推送
+// 不支持的 schema 版本或事件类型按普通输出推送
+func (tm *TaskManager) handleWorkerEvent(taskCtx *TaskContext, line string) {
+	event, ok := parseWorkerEvent(line)
+	if !ok || event.SchemaVersion < 1 || event.SchemaVersion > ProgressSchemaVersion {
+		if ok {
+			log.Printf("[runTask] 任务 %s 不支持的进度事件版本: %d", taskCtx.TaskID, event.SchemaVersion)
+		}
+		taskCtx.AddEvent(&dto.ProgressEvent{
+			Type:    "output",
+			Line:    line,
+			Message: "输出",
+		})
+		return
+	}
+
+	progressEvent := &dto.ProgressEvent{
+		Type:          event.Event,
+		SchemaVersion: event.SchemaVersion,
+	}
+	switch event.Event {
+	case ProgressEventRoundStarted:
+		progressEvent.Message = fmt.Sprintf("第 %d/%d 轮开始，共 %d 个样本", event.Round, event.TotalRounds, event.Total)
+	case ProgressEventBatchCompleted:
+		progressEvent.Message = fmt.Sprintf("第 %d 轮批次 %d/%d 完成", event.Round, event.Batch, event.TotalBatches)
+	case ProgressEventSampleGenerated:
+		progressEvent.Message = fmt.Sprintf("新增 %d 条合格数据", event.Count)
+	case ProgressEventScoreAssigned:
+		progressEvent.ModelScore = event.ModelScore
+		progressEvent.RuleScore = event.RuleScore
+		progressEvent.Passed = event.Passed
+		progressEvent.Message = "数据评分"
+	case ProgressEventError:
+		progressEvent.Line = event.Message
+		progressEvent.Message = "错误"
+	default:
+		progressEvent.Type = "output"
+		progressEvent.Line = line
+		progressEvent.Message = "输出"
+		taskCtx.AddEvent(progressEvent)
+		return
+	}
+
+	taskCtx.progressState.apply(event, progressEvent)
+	taskCtx.AddEvent(progressEvent)
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+)
+
+// PromptService 提示词库服务
+type PromptService struct {
+	promptRepo *repository.PromptRepository
+}
+
+// NewPromptService 创建提示词库服务
+func NewPromptService(promptRepo *repository.PromptRepository) *PromptService {
+	return &PromptService{
+		promptRepo: promptRepo,
+	}
+}
+
+// CreatePrompt 创建提示词（内容保存为版本1）
+func (s *PromptService) CreatePrompt(userID uint, req *dto.CreatePromptRequest) (*dto.PromptResponse, error) {
+	if req.SpecialPrompt == "" && req.Directions == "" {
+		return nil, fmt.Errorf("special_prompt 和 directions 不能同时为空")
+	}
+
+	prompt := &models.Prompt{
+		UserID:      userID,
+		Name:        req.Name,
+		Description: req.Description,
+		Tags:        normalizePromptTags(req.Tags),
+	}
+	version := &models.PromptVersion{
+		SpecialPrompt: req.SpecialPrompt,
+		Directions:    req.Directions,
+		ChangeNote:    req.ChangeNote,
+	}
+
+	if err := s.promptRepo.Create(prompt, version); err != nil {
+		return nil, fmt.Errorf("创建提示词失败: %w", err)
+	}
+
+	resp := toPromptResponse(prompt)
+	resp.Latest = toPromptVersionResponse(version)
+	return resp, nil
+}
+
+// ListPrompts 获取用户的提示词列表
+func (s *PromptService) ListPrompts(userID uint, tag, keyword string) ([]*dto.PromptResponse, error) {
+	prompts, err := s.promptRepo.ListByUserID(userID, strings.TrimSpace(tag), strings.TrimSpace(keyword))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.PromptResponse, len(prompts))
+	for i := range prompts {
+		result[i] = toPromptResponse(&prompts[i])
+	}
+	return result, nil
+}
+
+// GetPrompt 获取提示词详情（含最新版本内容）
+func (s *PromptService) GetPrompt(id uint, userID uint) (*dto.PromptResponse, error) {
+	prompt, err := s.promptRepo.GetByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("提示词不存在或无权访问")
+	}
+
+	resp := toPromptResponse(prompt)
+	if latest, err := s.promptRepo.GetVersion(prompt.ID, prompt.LatestVersion); err == nil {
+		resp.Latest = toPromptVersionResponse(latest)
+	}
+	return resp, nil
+}
+
+// UpdatePrompt 更新提示词名称、描述和标签
+func (s *PromptService) UpdatePrompt(id uint, userID uint, req *dto.UpdatePromptRequest) (*dto.PromptResponse, error) {
+	prompt, err := s.promptRepo.GetByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("提示词不存在或无权访问")
+	}
+
+	if req.Name != nil {
+		prompt.Name = *req.Name
+	}
+	if req.Description != nil {
+		prompt.Description = *req.Description
+	}
+	if req.Tags != nil {
+		prompt.Tags = normalizePromptTags(req.Tags)
+	}
+
+	if err := s.promptRepo.Update(prompt); err != nil {
+		return nil, fmt.Errorf("更新提示词失败: %w", err)
+	}
+	return toPromptResponse(prompt), nil
+}
+
+// DeletePrompt 删除提示词及其全部版本
+// 已启动任务的参数中保存了解析后的提示词内容，删除不影响历史任务的复现
+func (s *PromptService) DeletePrompt(id uint, userID uint) error {
+	if _, err := s.promptRepo.GetByIDAndUserID(id, userID); err != nil {
+		return fmt.Errorf("提示词不存在或无权访问")
+	}
+	return s.promptRepo.Delete(id)
+}
+
+// CreateVersion 为提示词创建新版本
+func (s *PromptService) CreateVersion(id uint, userID uint, req *dto.CreatePromptVersionRequest) (*dto.PromptVersionResponse, error) {
+	if req.SpecialPrompt == "" && req.Directions == "" {
+		return nil, fmt.Errorf("special_prompt 和 directions 不能同时为空")
+	}
+	if _, err := s.promptRepo.GetByIDAndUserID(id, userID); err != nil {
+		return nil, fmt.Errorf("提示词不存在或无权访问")
+	}
+
+	version := &models.PromptVersion{
+		SpecialPrompt: req.SpecialPrompt,
+		Directions:    req.Directions,
+		ChangeNote:    req.ChangeNote,
+	}
+	if err := s.promptRepo.CreateVersion(id, version); err != nil {
+		return nil, fmt.Errorf("创建提示词版本失败: %w", err)
+	}
+	return toPromptVersionResponse(version), nil
+}
+
+// ListVersions 获取提示词的版本历史
+func (s *PromptService) ListVersions(id uint, userID uint) ([]*dto.PromptVersionResponse, error) {
+	if _, err := s.promptRepo.GetByIDAndUserID(id, userID); err != nil {
+		return nil, fmt.Errorf("提示词不存在或无权访问")
+	}
+
+	versions, err := s.promptRepo.ListVersions(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.PromptVersionResponse, len(versions))
+	for i := range versions {
+		result[i] = toPromptVersionResponse(&versions[i])
+	}
+	return result, nil
+}
+
+// GetVersion 获取提示词的指定版本
+func (s *PromptService) GetVersion(id uint, userID uint, version int) (*dto.PromptVersionResponse, error) {
+	if _, err := s.promptRepo.GetByIDAndUserID(id, userID); err != nil {
+		return nil, fmt.Errorf("提示词不存在或无权访问")
+	}
+
+	v, err := s.promptRepo.GetVersion(id, version)
+	if err != nil {
+		return nil, fmt.Errorf("版本 %d 不存在", version)
+	}
+	return toPromptVersionResponse(v), nil
+}
+
+// normalizePromptTags 去除空白、逗号和重复标签后拼接保存
+func normalizePromptTags(tags []string) string {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(strings.ReplaceAll(tag, ",", ""))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return strings.Join(result, ",")
+}
+
+// toPromptResponse 转换提示词响应
+func toPromptResponse(prompt *models.Prompt) *dto.PromptResponse {
+	return &dto.PromptResponse{
+		ID:            prompt.ID,
+		Name:          prompt.Name,
+		Description:   prompt.Description,
+		Tags:          prompt.TagList(),
+		LatestVersion: prompt.LatestVersion,
+		CreatedAt:     dto.FormatTime(prompt.CreatedAt),
+		UpdatedAt:     dto.FormatTime(prompt.UpdatedAt),
+	}
+}
+
+// toPromptVersionResponse 转换提示词版本响应
+func toPromptVersionResponse(version *models.PromptVersion) *dto.PromptVersionResponse {
+	return &dto.PromptVersionResponse{
+		ID:            version.ID,
+		PromptID:      version.PromptID,
+		Version:       version.Version,
+		SpecialPrompt: version.SpecialPrompt,
+		Directions:    version.Directions,
+		ChangeNote:    version.ChangeNote,
+		CreatedAt:     dto.FormatTime(version.CreatedAt),
+	}
+}
@@ -0,0 +1,416 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"unicode/utf8"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+)
+
+// compareMaxItems 对比时每个任务最多读取的数据条数
+const compareMaxItems = 100000
+
+// ReportService 任务报告服务（统计、对比）
+type ReportService struct {
+	generatedDataRepo *repository.GeneratedDataRepository
+	taskRepo          *repository.TaskRepository
+}
+
+// NewReportService 创建任务报告服务
+func NewReportService(generatedDataRepo *repository.GeneratedDataRepository, taskRepo *repository.TaskRepository) *ReportService {
+	return &ReportService{
+		generatedDataRepo: generatedDataRepo,
+		taskRepo:          taskRepo,
+	}
+}
+
+// compareItem 参与对比的一条数据
+type compareItem struct {
+	data         *models.GeneratedData
+	seedHash     string
+	variantIndex int
+	length       int
+}
+
+// Compare 对比两个任务的生成数据（通常是同一输入文件、不同模型配置或提示词版本的两次运行）
+func (s *ReportService) Compare(taskAID, taskBID string, userID uint, page, perPage int) (*dto.ReportCompareResponse, error) {
+	if taskAID == taskBID {
+		return nil, fmt.Errorf("task_a 与 task_b 不能相同")
+	}
+	taskA, err := s.taskRepo.GetVisibleByTaskID(taskAID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("任务 %s 不存在或无权访问", taskAID)
+	}
+	taskB, err := s.taskRepo.GetVisibleByTaskID(taskBID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("任务 %s 不存在或无权访问", taskBID)
+	}
+
+	itemsA, err := s.loadCompareItems(taskAID)
+	if err != nil {
+		return nil, err
+	}
+	itemsB, err := s.loadCompareItems(taskBID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.ReportCompareResponse{
+		TaskA:         summarizeCompareSide(taskA, itemsA),
+		TaskB:         summarizeCompareSide(taskB, itemsB),
+		SameInputFile: sameInputFile(taskA, taskB),
+		Page:          page,
+		PerPage:       perPage,
+	}
+
+	diffs := alignCompareItems(itemsA, itemsB)
+	var deltaSum float64
+	var deltaCount int
+	for _, diff := range diffs {
+		switch {
+		case diff.A != nil && diff.B != nil:
+			result.Aligned++
+			if diff.Identical {
+				result.Identical++
+			}
+			if diff.ScoreDelta != nil {
+				deltaSum += *diff.ScoreDelta
+				deltaCount++
+			}
+		case diff.A != nil:
+			result.OnlyInA++
+		default:
+			result.OnlyInB++
+		}
+	}
+	if deltaCount > 0 {
+		avg := deltaSum / float64(deltaCount)
+		result.AvgScoreDelta = &avg
+	}
+
+	result.DiffTotal = len(diffs)
+	start := (page - 1) * perPage
+	if start > len(diffs) {
+		start = len(diffs)
+	}
+	end := start + perPage
+	if end > len(diffs) {
+		end = len(diffs)
+	}
+	result.Diffs = diffs[start:end]
+	return result, nil
+}
+
+// loadCompareItems 读取任务的全部数据及对齐所需的 seed_hash、variant_index
+// 早期数据没有这两列，从 meta 中读取
+func (s *ReportService) loadCompareItems(taskID string) ([]compareItem, error) {
+	dataList, _, err := s.generatedDataRepo.ListByTaskID(taskID, 0, compareMaxItems)
+	if err != nil {
+		return nil, fmt.Errorf("读取任务 %s 的数据失败: %w", taskID, err)
+	}
+
+	items := make([]compareItem, len(dataList))
+	for i := range dataList {
+		item := compareItem{
+			data:   &dataList[i],
+			length: utf8.RuneCountInString(dataList[i].DataContent),
+		}
+		if dataList[i].SeedHash != "" && dataList[i].VariantIndex != nil {
+			item.seedHash = dataList[i].SeedHash
+			item.variantIndex = *dataList[i].VariantIndex
+			items[i] = item
+			continue
+		}
+		var content struct {
+			Meta struct {
+				SeedHash     string   `json:"seed_hash"`
+				VariantIndex *float64 `json:"variant_index"`
+			} `json:"meta"`
+		}
+		if err := json.Unmarshal([]byte(dataList[i].DataContent), &content); err == nil && content.Meta.VariantIndex != nil {
+			item.seedHash = content.Meta.SeedHash
+			item.variantIndex = int(*content.Meta.VariantIndex)
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+// alignCompareItems 按 (seed_hash, variant_index) 对齐两个任务的数据，结果按对齐键排序，无法对齐的数据排在最后
+func alignCompareItems(itemsA, itemsB []compareItem) []dto.ReportItemDiff {
+	type alignKey struct {
+		seedHash     string
+		variantIndex int
+	}
+
+	indexB := make(map[alignKey]*compareItem, len(itemsB))
+	for i := range itemsB {
+		if itemsB[i].seedHash == "" {
+			continue
+		}
+		key := alignKey{itemsB[i].seedHash, itemsB[i].variantIndex}
+		if _, exists := indexB[key]; !exists {
+			indexB[key] = &itemsB[i]
+		}
+	}
+
+	diffs := make([]dto.ReportItemDiff, 0, len(itemsA)+len(itemsB))
+	var unaligned []dto.ReportItemDiff
+	matched := make(map[*compareItem]bool, len(indexB))
+	for i := range itemsA {
+		a := &itemsA[i]
+		if a.seedHash == "" {
+			unaligned = append(unaligned, dto.ReportItemDiff{A: toReportDiffItem(a)})
+			continue
+		}
+		diff := dto.ReportItemDiff{SeedHash: a.seedHash, VariantIndex: a.variantIndex, A: toReportDiffItem(a)}
+		if b, ok := indexB[alignKey{a.seedHash, a.variantIndex}]; ok && !matched[b] {
+			matched[b] = true
+			diff.B = toReportDiffItem(b)
+			diff.Identical = sameConversation(a.data, b.data)
+			if a.data.ModelScore != nil && b.data.ModelScore != nil {
+				delta := *b.data.ModelScore - *a.data.ModelScore
+				diff.ScoreDelta = &delta
+			}
+		}
+		diffs = append(diffs, diff)
+	}
+	for i := range itemsB {
+		b := &itemsB[i]
+		if matched[b] {
+			continue
+		}
+		diff := dto.ReportItemDiff{SeedHash: b.seedHash, VariantIndex: b.variantIndex, B: toReportDiffItem(b)}
+		if b.seedHash == "" {
+			unaligned = append(unaligned, diff)
+		} else {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	sort.SliceStable(diffs, func(i, j int) bool {
+		if diffs[i].SeedHash != diffs[j].SeedHash {
+			return diffs[i].SeedHash < diffs[j].SeedHash
+		}
+		return diffs[i].VariantIndex < diffs[j].VariantIndex
+	})
+	return append(diffs, unaligned...)
+}
+
+// sameConversation 判断两条数据的对话内容是否一致（忽略 meta 和空白差异）
+func sameConversation(a, b *models.GeneratedData) bool {
+	var contentA, contentB map[string]interface{}
+	if json.Unmarshal([]byte(a.DataContent), &contentA) != nil || json.Unmarshal([]byte(b.DataContent), &contentB) != nil {
+		return a.DataContent == b.DataContent
+	}
+	return conversationKey(contentA) == conversationKey(contentB)
+}
+
+// toReportDiffItem 转换对比中一侧的数据
+func toReportDiffItem(item *compareItem) *dto.ReportDiffItem {
+	return &dto.ReportDiffItem{
+		ID:              item.data.ID,
+		ModelScore:      item.data.ModelScore,
+		RuleScore:       item.data.RuleScore,
+		Length:          item.length,
+		GenerationModel: item.data.GenerationModel,
+		DataContent:     item.data.DataContent,
+	}
+}
+
+// summarizeCompareSide 统计一个任务的评分分布和长度分布
+func summarizeCompareSide(task *models.Task, items []compareItem) dto.ReportCompareSide {
+	side := dto.ReportCompareSide{
+		TaskID: task.TaskID,
+		Params: make(map[string]interface{}, len(task.Params)),
+		Count:  len(items),
+		ScoreBuckets: map[string]int{
+			ScoreBucketLow:      0,
+			ScoreBucketMedium:   0,
+			ScoreBucketHigh:     0,
+			ScoreBucketUnscored: 0,
+		},
+	}
+	for k, v := range task.Params {
+		if k != "api_key" {
+			side.Params[k] = v
+		}
+	}
+
+	var modelSum, ruleSum float64
+	var modelCount, ruleCount int
+	lengths := make([]int, len(items))
+	for i, item := range items {
+		side.ScoreBuckets[scoreBucket(item.data)]++
+		if item.data.ModelScore != nil {
+			modelSum += *item.data.ModelScore
+			modelCount++
+		}
+		if item.data.RuleScore != nil {
+			ruleSum += float64(*item.data.RuleScore)
+			ruleCount++
+		}
+		lengths[i] = item.length
+	}
+	if modelCount > 0 {
+		avg := modelSum / float64(modelCount)
+		side.AvgModelScore = &avg
+	}
+	if ruleCount > 0 {
+		avg := ruleSum / float64(ruleCount)
+		side.AvgRuleScore = &avg
+	}
+	side.Length = lengthStats(lengths)
+	return side
+}
+
+// lengthStats 计算长度的最小值、最大值、平均值和分位数
+func lengthStats(lengths []int) dto.LengthStats {
+	if len(lengths) == 0 {
+		return dto.LengthStats{}
+	}
+	sorted := append([]int(nil), lengths...)
+	sort.Ints(sorted)
+
+	sum := 0
+	for _, length := range sorted {
+		sum += length
+	}
+	return dto.LengthStats{
+		Min: sorted[0],
+		Max: sorted[len(sorted)-1],
+		Avg: float64(sum) / float64(len(sorted)),
+		P50: sorted[percentileIndex(len(sorted), 50)],
+		P90: sorted[percentileIndex(len(sorted), 90)],
+		P99: sorted[percentileIndex(len(sorted), 99)],
+	}
+}
+
+// percentileIndex 获取 n 个升序值中第 p 百分位（最近秩法）的下标
+func percentileIndex(n, p int) int {
+	index := int(math.Ceil(float64(p)/100*float64(n))) - 1
+	if index < 0 {
+		return 0
+	}
+	if index >= n {
+		return n - 1
+	}
+	return index
+}
+
+// GetStats 获取任务生成数据的统计，全部由 SQL 聚合计算，不加载数据内容
+func (s *ReportService) GetStats(taskID string, userID uint) (*dto.TaskStatsResponse, error) {
+	if _, err := s.taskRepo.GetVisibleByTaskID(taskID, userID); err != nil {
+		return nil, fmt.Errorf("任务不存在或无权访问")
+	}
+
+	result := &dto.TaskStatsResponse{TaskID: taskID}
+
+	scoreBuckets, err := s.generatedDataRepo.CountByModelScoreBucket(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("统计评分分布失败: %w", err)
+	}
+	result.ModelScoreHistogram = modelScoreHistogram(scoreBuckets)
+
+	ruleScores, err := s.generatedDataRepo.CountByRuleScore(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("统计规则评分失败: %w", err)
+	}
+	result.RuleScoreCounts = toValueCounts(ruleScores)
+
+	retries, err := s.generatedDataRepo.CountByRetryCount(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("统计重试次数失败: %w", err)
+	}
+	result.RetryCounts = toValueCounts(retries)
+
+	rounds, err := s.generatedDataRepo.CountByRound(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("统计每轮产出失败: %w", err)
+	}
+	result.RoundYield = toValueCounts(rounds)
+
+	result.AvgTurns, err = s.generatedDataRepo.AverageTurns(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("统计对话轮数失败: %w", err)
+	}
+
+	summary, err := s.generatedDataRepo.SummarizeLength(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("统计长度分布失败: %w", err)
+	}
+	result.Total = summary.Count
+	result.Length = dto.LengthStats{
+		Min: int(summary.Min),
+		Max: int(summary.Max),
+		Avg: summary.Avg,
+	}
+	if summary.Count > 0 {
+		percentiles := []struct {
+			p      int
+			target *int
+		}{
+			{50, &result.Length.P50},
+			{90, &result.Length.P90},
+			{99, &result.Length.P99},
+		}
+		for _, pt := range percentiles {
+			length, err := s.generatedDataRepo.LengthAtRank(taskID, percentileIndex(int(summary.Count), pt.p))
+			if err != nil {
+				return nil, fmt.Errorf("统计长度分位数失败: %w", err)
+			}
+			*pt.target = int(length)
+		}
+	}
+
+	return result, nil
+}
+
+// modelScoreHistogram 将分段统计转换为完整的直方图（没有数据的分段计为 0）
+func modelScoreHistogram(rows []repository.GroupCount) []dto.HistogramBucket {
+	histogram := make([]dto.HistogramBucket, repository.ModelScoreBucketCount+1)
+	for i := 0; i < repository.ModelScoreBucketCount; i++ {
+		histogram[i].Label = fmt.Sprintf("%d-%d", i, i+1)
+	}
+	histogram[repository.ModelScoreBucketCount].Label = ScoreBucketUnscored
+
+	for _, row := range rows {
+		if row.Value == nil {
+			histogram[repository.ModelScoreBucketCount].Count += row.Count
+		} else if *row.Value >= 0 && *row.Value < repository.ModelScoreBucketCount {
+			histogram[*row.Value].Count += row.Count
+		}
+	}
+	return histogram
+}
+
+// toValueCounts 转换按取值分组的统计
+func toValueCounts(rows []repository.GroupCount) []dto.ValueCount {
+	counts := make([]dto.ValueCount, len(rows))
+	for i, row := range rows {
+		counts[i] = dto.ValueCount{Value: row.Value, Count: row.Count}
+	}
+	return counts
+}
+
+// sameInputFile 判断两个任务是否使用同一输入文件（引用历史版本时按源文件比较）
+func sameInputFile(a, b *models.Task) bool {
+	fileA, okA := inputFileID(a)
+	fileB, okB := inputFileID(b)
+	return okA && okB && fileA == fileB
+}
+
+// inputFileID 获取任务的输入文件ID
+func inputFileID(task *models.Task) (string, bool) {
+	for _, key := range []string{"source_file_id", "file_id"} {
+		if value, ok := task.Params[key]; ok && value != nil {
+			return fmt.Sprint(value), true
+		}
+	}
+	return "", false
+}
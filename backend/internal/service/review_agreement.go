@@ -0,0 +1,113 @@
+package service
+
+import (
+	"math"
+	"sort"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+)
+
+// verdictPair 同一条数据上两位审核人的结论（A 为审核人ID较小者）
+type verdictPair struct {
+	reviewerA uint
+	reviewerB uint
+	verdictA  string
+	verdictB  string
+}
+
+// computeReviewAgreement 根据已提交的审核结论计算一致性
+// verdicts 需按 data_id 排序；每条数据上任意两位审核人组成一对评分
+func computeReviewAgreement(verdicts []models.ReviewVerdict, requiredReviews int) *dto.ReviewAgreement {
+	result := &dto.ReviewAgreement{
+		RequiredReviews: requiredReviews,
+		PairwiseKappa:   []dto.ReviewerPairAgreement{},
+	}
+
+	var pairs []verdictPair
+	for start := 0; start < len(verdicts); {
+		end := start
+		for end < len(verdicts) && verdicts[end].DataID == verdicts[start].DataID {
+			end++
+		}
+		group := verdicts[start:end]
+		if len(group) >= 2 {
+			result.Items++
+			for i := 0; i < len(group); i++ {
+				for j := i + 1; j < len(group); j++ {
+					a, b := group[i], group[j]
+					if a.ReviewerID > b.ReviewerID {
+						a, b = b, a
+					}
+					pairs = append(pairs, verdictPair{
+						reviewerA: a.ReviewerID,
+						reviewerB: b.ReviewerID,
+						verdictA:  a.Verdict,
+						verdictB:  b.Verdict,
+					})
+				}
+			}
+		}
+		start = end
+	}
+
+	result.Pairs = len(pairs)
+	result.ObservedAgreement, result.Kappa = cohensKappa(pairs)
+
+	// 按审核人组合分别统计
+	byReviewers := make(map[[2]uint][]verdictPair)
+	for _, pair := range pairs {
+		key := [2]uint{pair.reviewerA, pair.reviewerB}
+		byReviewers[key] = append(byReviewers[key], pair)
+	}
+	for key, group := range byReviewers {
+		agreement, kappa := cohensKappa(group)
+		result.PairwiseKappa = append(result.PairwiseKappa, dto.ReviewerPairAgreement{
+			ReviewerA: key[0],
+			ReviewerB: key[1],
+			Items:     len(group),
+			Agreement: agreement,
+			Kappa:     kappa,
+		})
+	}
+	sort.Slice(result.PairwiseKappa, func(i, j int) bool {
+		if result.PairwiseKappa[i].ReviewerA != result.PairwiseKappa[j].ReviewerA {
+			return result.PairwiseKappa[i].ReviewerA < result.PairwiseKappa[j].ReviewerA
+		}
+		return result.PairwiseKappa[i].ReviewerB < result.PairwiseKappa[j].ReviewerB
+	})
+
+	return result
+}
+
+// cohensKappa 计算一组评分对的观察一致率和 Cohen's kappa
+// 期望一致率为 1（双方都只用了同一个结论）时 kappa 无定义，返回 nil
+func cohensKappa(pairs []verdictPair) (float64, *float64) {
+	if len(pairs) == 0 {
+		return 0, nil
+	}
+
+	agree := 0
+	marginalA := make(map[string]int)
+	marginalB := make(map[string]int)
+	for _, pair := range pairs {
+		if pair.verdictA == pair.verdictB {
+			agree++
+		}
+		marginalA[pair.verdictA]++
+		marginalB[pair.verdictB]++
+	}
+
+	n := float64(len(pairs))
+	observed := float64(agree) / n
+	expected := 0.0
+	for verdict, countA := range marginalA {
+		expected += (float64(countA) / n) * (float64(marginalB[verdict]) / n)
+	}
+	if math.Abs(1-expected) < 1e-9 {
+		return observed, nil
+	}
+
+	kappa := (observed - expected) / (1 - expected)
+	return observed, &kappa
+}
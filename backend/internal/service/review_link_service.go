@@ -0,0 +1,208 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+	"gen-go/internal/utils"
+)
+
+// ReviewLinkService 匿名审阅链接服务
+// 为单个任务签发受限 Token，外部标注人员无需账号即可查看并确认该任务的生成数据
+type ReviewLinkService struct {
+	linkRepo          *repository.ReviewLinkRepository
+	taskRepo          *repository.TaskRepository
+	generatedDataRepo *repository.GeneratedDataRepository
+	jwtManager        *utils.JWTManager
+	cfg               *config.Config
+}
+
+// NewReviewLinkService 创建审阅链接服务
+func NewReviewLinkService(
+	linkRepo *repository.ReviewLinkRepository,
+	taskRepo *repository.TaskRepository,
+	generatedDataRepo *repository.GeneratedDataRepository,
+	jwtManager *utils.JWTManager,
+	cfg *config.Config,
+) *ReviewLinkService {
+	return &ReviewLinkService{
+		linkRepo:          linkRepo,
+		taskRepo:          taskRepo,
+		generatedDataRepo: generatedDataRepo,
+		jwtManager:        jwtManager,
+		cfg:               cfg,
+	}
+}
+
+// CreateLink 为任务创建审阅链接（Token 仅在创建时返回一次）
+func (s *ReviewLinkService) CreateLink(userID uint, taskID string, req *dto.CreateReviewLinkRequest) (*dto.ReviewLinkResponse, error) {
+	if _, err := s.taskRepo.GetEditableByTaskID(taskID, userID); err != nil {
+		return nil, fmt.Errorf("任务不存在或无权访问")
+	}
+
+	hours := req.ExpiresInHours
+	if hours <= 0 {
+		hours = s.cfg.Current().JWT.ReviewLinkDefaultHours
+	}
+	if hours > s.cfg.Current().JWT.ReviewLinkMaxHours {
+		return nil, fmt.Errorf("有效期不能超过 %d 小时", s.cfg.Current().JWT.ReviewLinkMaxHours)
+	}
+
+	tokenID, err := generateReviewTokenID()
+	if err != nil {
+		return nil, fmt.Errorf("生成Token失败: %w", err)
+	}
+
+	link := &models.ReviewLink{
+		UserID:    userID,
+		TaskID:    taskID,
+		TokenID:   tokenID,
+		Name:      req.Name,
+		ExpiresAt: time.Now().UTC().Add(time.Duration(hours) * time.Hour),
+	}
+
+	token, err := s.jwtManager.GenerateReviewToken(tokenID, taskID, link.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("生成Token失败: %w", err)
+	}
+
+	if err := s.linkRepo.Create(link); err != nil {
+		return nil, fmt.Errorf("创建审阅链接失败: %w", err)
+	}
+
+	resp := toReviewLinkResponse(link)
+	resp.Token = token
+	return resp, nil
+}
+
+// ListLinks 获取任务的审阅链接
+func (s *ReviewLinkService) ListLinks(userID uint, taskID string) ([]*dto.ReviewLinkResponse, error) {
+	links, err := s.linkRepo.ListByTaskID(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.ReviewLinkResponse, len(links))
+	for i := range links {
+		result[i] = toReviewLinkResponse(&links[i])
+	}
+	return result, nil
+}
+
+// RevokeLink 撤销审阅链接，撤销后 Token 立即失效
+func (s *ReviewLinkService) RevokeLink(id uint, userID uint) error {
+	if _, err := s.linkRepo.GetByIDAndUserID(id, userID); err != nil {
+		return fmt.Errorf("审阅链接不存在或无权访问")
+	}
+	return s.linkRepo.Revoke(id)
+}
+
+// ListActivities 分页获取审阅链接的访问记录
+func (s *ReviewLinkService) ListActivities(id uint, userID uint, page, perPage int) (*dto.PaginatedResponse, error) {
+	if _, err := s.linkRepo.GetByIDAndUserID(id, userID); err != nil {
+		return nil, fmt.Errorf("审阅链接不存在或无权访问")
+	}
+
+	offset := (page - 1) * perPage
+	activities, total, err := s.linkRepo.ListActivities(id, offset, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.ReviewLinkActivityResponse, len(activities))
+	for i, a := range activities {
+		items[i] = dto.ReviewLinkActivityResponse{
+			ID:        a.ID,
+			Action:    a.Action,
+			DataID:    a.DataID,
+			IP:        a.IP,
+			UserAgent: a.UserAgent,
+			CreatedAt: dto.FormatTime(a.CreatedAt),
+		}
+	}
+
+	return &dto.PaginatedResponse{
+		Items:   items,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	}, nil
+}
+
+// Authorize 校验审阅 Token，返回对应的审阅链接（已撤销或过期时返回错误）
+func (s *ReviewLinkService) Authorize(token string) (*models.ReviewLink, error) {
+	claims, err := s.jwtManager.ValidateReviewToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("审阅链接无效或已过期")
+	}
+
+	link, err := s.linkRepo.GetByTokenID(claims.ID)
+	if err != nil || link.TaskID != claims.TaskID {
+		return nil, fmt.Errorf("审阅链接无效或已过期")
+	}
+	if !link.IsUsable(time.Now().UTC()) {
+		return nil, fmt.Errorf("审阅链接已撤销或已过期")
+	}
+	return link, nil
+}
+
+// RecordActivity 记录审阅链接的一次访问（失败仅记录日志）
+func (s *ReviewLinkService) RecordActivity(link *models.ReviewLink, action string, dataID *uint, ip, userAgent string) {
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	activity := &models.ReviewLinkActivity{
+		LinkID:    link.ID,
+		TaskID:    link.TaskID,
+		Action:    action,
+		DataID:    dataID,
+		IP:        ip,
+		UserAgent: userAgent,
+	}
+	if err := s.linkRepo.RecordActivity(activity); err != nil {
+		log.Printf("[ReviewLink] 记录访问失败 (link=%d): %v", link.ID, err)
+	}
+}
+
+// ConfirmData 通过审阅链接确认或取消确认数据（仅限链接对应任务的数据）
+func (s *ReviewLinkService) ConfirmData(link *models.ReviewLink, dataID uint, isConfirmed bool) error {
+	data, err := s.generatedDataRepo.GetByID(dataID)
+	if err != nil || data.TaskID != link.TaskID {
+		return fmt.Errorf("数据不存在")
+	}
+
+	data.IsConfirmed = isConfirmed
+	return s.generatedDataRepo.Update(data)
+}
+
+// generateReviewTokenID 生成审阅 Token 的 jti
+func generateReviewTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// toReviewLinkResponse 转换审阅链接响应（不含 Token）
+func toReviewLinkResponse(link *models.ReviewLink) *dto.ReviewLinkResponse {
+	return &dto.ReviewLinkResponse{
+		ID:         link.ID,
+		TaskID:     link.TaskID,
+		Name:       link.Name,
+		ExpiresAt:  dto.FormatTime(link.ExpiresAt),
+		Revoked:    link.Revoked,
+		Active:     link.IsUsable(time.Now().UTC()),
+		LastUsedAt: dto.FormatTimePtr(link.LastUsedAt),
+		UseCount:   link.UseCount,
+		CreatedAt:  dto.FormatTime(link.CreatedAt),
+	}
+}
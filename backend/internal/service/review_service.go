@@ -0,0 +1,388 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+)
+
+// ReviewService 生成数据审核服务
+// 任务所有者将数据分配给审核人，审核人逐条给出 通过/驳回/需修改 及意见；通过的数据同时标记为已确认。
+// 任务设置 required_reviews 大于1时，每条数据分配给多位审核人独立审核，结论全部一致时生效，
+// 不一致时标记为 disputed 由任务所有者裁定，并统计审核人之间的一致性（Cohen's kappa）
+type ReviewService struct {
+	generatedDataRepo *repository.GeneratedDataRepository
+	verdictRepo       *repository.ReviewVerdictRepository
+	taskRepo          *repository.TaskRepository
+	userRepo          *repository.UserRepository
+	notifications     *NotificationService
+}
+
+// NewReviewService 创建审核服务
+func NewReviewService(
+	generatedDataRepo *repository.GeneratedDataRepository,
+	verdictRepo *repository.ReviewVerdictRepository,
+	taskRepo *repository.TaskRepository,
+	userRepo *repository.UserRepository,
+	notifications *NotificationService,
+) *ReviewService {
+	return &ReviewService{
+		generatedDataRepo: generatedDataRepo,
+		verdictRepo:       verdictRepo,
+		taskRepo:          taskRepo,
+		userRepo:          userRepo,
+		notifications:     notifications,
+	}
+}
+
+// requiredReviews 获取任务要求的每条数据审核次数（默认1）
+func requiredReviews(task *models.Task) int {
+	switch value := task.Params["required_reviews"].(type) {
+	case float64:
+		if value >= 1 {
+			return int(value)
+		}
+	case int:
+		if value >= 1 {
+			return value
+		}
+	}
+	return 1
+}
+
+// UpdateSettings 修改任务的审核设置（仅任务所有者）
+func (s *ReviewService) UpdateSettings(taskID string, userID uint, req *dto.ReviewSettingsRequest) error {
+	if _, err := s.taskRepo.GetEditableByTaskID(taskID, userID); err != nil {
+		return fmt.Errorf("任务不存在或无权访问")
+	}
+	if err := s.taskRepo.MergeParams(taskID, map[string]interface{}{"required_reviews": req.RequiredReviews}); err != nil {
+		return fmt.Errorf("保存审核设置失败: %w", err)
+	}
+	return nil
+}
+
+// Assign 将任务数据分配给审核人（仅任务所有者）
+// 每条数据分配给 required_reviews 位审核人：第 i 条数据依次分配给 reviewer_ids 中从 i 开始的连续几位（循环）
+func (s *ReviewService) Assign(userID uint, req *dto.AssignReviewRequest) (*dto.AssignReviewResponse, error) {
+	task, err := s.taskRepo.GetEditableByTaskID(req.TaskID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("任务不存在或无权访问")
+	}
+
+	required := requiredReviews(task)
+	reviewerIDs := uniqueIDs(req.ReviewerIDs)
+	if len(reviewerIDs) < required {
+		return nil, fmt.Errorf("该任务每条数据需要 %d 位审核人，至少指定 %d 位不同的审核人", required, required)
+	}
+	for _, reviewerID := range reviewerIDs {
+		if err := s.checkReviewer(reviewerID); err != nil {
+			return nil, err
+		}
+	}
+
+	ids := req.DataIDs
+	if len(ids) == 0 {
+		ids, err = s.generatedDataRepo.ListUnassignedIDs(req.TaskID)
+		if err != nil {
+			return nil, fmt.Errorf("获取待分配数据失败: %w", err)
+		}
+	} else {
+		dataList, err := s.generatedDataRepo.ListByIDs(ids)
+		if err != nil {
+			return nil, fmt.Errorf("获取数据失败: %w", err)
+		}
+		if len(dataList) != len(ids) {
+			return nil, fmt.Errorf("部分数据不存在")
+		}
+		for _, data := range dataList {
+			if data.TaskID != req.TaskID {
+				return nil, fmt.Errorf("数据 %d 不属于该任务", data.ID)
+			}
+		}
+		// 重新分配指定数据时，清理原审核人尚未提交的记录
+		if err := s.verdictRepo.DeletePendingByDataIDs(ids); err != nil {
+			return nil, fmt.Errorf("清理原分配失败: %w", err)
+		}
+	}
+
+	// primary 记录每条数据的第一位审核人（写入 generated_data.reviewer_id）
+	primary := make(map[uint][]uint, len(reviewerIDs))
+	verdicts := make([]models.ReviewVerdict, 0, len(ids)*required)
+	for i, id := range ids {
+		for k := 0; k < required; k++ {
+			reviewerID := reviewerIDs[(i+k)%len(reviewerIDs)]
+			if k == 0 {
+				primary[reviewerID] = append(primary[reviewerID], id)
+			}
+			verdicts = append(verdicts, models.ReviewVerdict{
+				TaskID:     req.TaskID,
+				DataID:     id,
+				ReviewerID: reviewerID,
+				Verdict:    models.ReviewStatusPending,
+			})
+		}
+	}
+
+	if _, err := s.verdictRepo.CreatePending(verdicts); err != nil {
+		return nil, fmt.Errorf("分配审核失败: %w", err)
+	}
+	for reviewerID, group := range primary {
+		if _, err := s.generatedDataRepo.AssignReviewer(group, reviewerID); err != nil {
+			return nil, fmt.Errorf("分配审核失败: %w", err)
+		}
+	}
+
+	result := &dto.AssignReviewResponse{
+		TaskID:      req.TaskID,
+		Assigned:    int64(len(ids)),
+		PerReviewer: make(map[uint]int64, len(reviewerIDs)),
+	}
+	for _, verdict := range verdicts {
+		result.PerReviewer[verdict.ReviewerID]++
+	}
+
+	log.Printf("[Review] 任务 %s 分配审核 %d 条（每条 %d 人）给 %d 位审核人", req.TaskID, len(ids), required, len(reviewerIDs))
+	for reviewerID, count := range result.PerReviewer {
+		s.notifyReviewer(reviewerID, req.TaskID, fmt.Sprintf("任务 %s 有 %d 条数据分配给你审核", req.TaskID, count))
+	}
+	return result, nil
+}
+
+// ListAssigned 获取分配给当前用户的审核数据（status 按自己的审核结论过滤）
+func (s *ReviewService) ListAssigned(reviewerID uint, taskID, status string, page, perPage int) (*dto.PaginatedResponse, error) {
+	offset := (page - 1) * perPage
+	dataList, total, err := s.generatedDataRepo.ListByReviewer(reviewerID, taskID, status, offset, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.GeneratedDataResponse, len(dataList))
+	for i := range dataList {
+		responses[i] = toGeneratedDataResponse(&dataList[i])
+	}
+
+	return &dto.PaginatedResponse{
+		Items:   responses,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	}, nil
+}
+
+// Submit 提交单条数据的审核结果
+// 分配的审核人提交自己的结论，数据状态按全部结论汇总；任务所有者（未被分配时）直接裁定最终状态
+func (s *ReviewService) Submit(userID uint, dataID uint, req *dto.SubmitReviewRequest) error {
+	data, err := s.generatedDataRepo.GetByID(dataID)
+	if err != nil {
+		return fmt.Errorf("数据不存在")
+	}
+
+	now := time.Now()
+	verdict, err := s.verdictRepo.GetByDataIDAndReviewerID(dataID, userID)
+	if err != nil {
+		// 未被分配时，任务所有者或在任务所属工作区拥有审核权限的成员直接裁定最终状态
+		if _, err := s.taskRepo.GetAccessibleByTaskID(data.TaskID, userID, models.PermissionReview); err != nil {
+			return fmt.Errorf("无权审核该数据")
+		}
+		return s.generatedDataRepo.UpdateReview(dataID, req.Status, req.Comment, now)
+	}
+
+	verdict.Verdict = req.Status
+	verdict.Comment = req.Comment
+	verdict.ReviewedAt = &now
+	if err := s.verdictRepo.Update(verdict); err != nil {
+		return fmt.Errorf("保存审核结论失败: %w", err)
+	}
+
+	task, err := s.taskRepo.GetByTaskID(data.TaskID)
+	if err != nil {
+		return fmt.Errorf("任务不存在")
+	}
+	verdicts, err := s.verdictRepo.ListByDataID(dataID)
+	if err != nil {
+		return fmt.Errorf("获取审核结论失败: %w", err)
+	}
+
+	status, comment, done := aggregateVerdicts(verdicts, requiredReviews(task))
+	if !done {
+		return nil
+	}
+	return s.generatedDataRepo.UpdateReview(dataID, status, comment, now)
+}
+
+// aggregateVerdicts 汇总一条数据的审核结论
+// 已提交的结论数达到要求（或全部分配的审核人都已提交）时生效：全部一致取该结论，否则为 disputed
+func aggregateVerdicts(verdicts []models.ReviewVerdict, required int) (string, string, bool) {
+	var submitted []models.ReviewVerdict
+	for _, verdict := range verdicts {
+		if verdict.Verdict != models.ReviewStatusPending {
+			submitted = append(submitted, verdict)
+		}
+	}
+	if len(submitted) == 0 || (len(submitted) < required && len(submitted) < len(verdicts)) {
+		return "", "", false
+	}
+
+	status := submitted[0].Verdict
+	var comments []string
+	for _, verdict := range submitted {
+		if verdict.Verdict != status {
+			status = models.ReviewStatusDisputed
+		}
+		if verdict.Comment != "" {
+			comments = append(comments, verdict.Comment)
+		}
+	}
+	return status, strings.Join(comments, "\n"), true
+}
+
+// Reassign 管理员将审核人名下的审核记录批量转交给另一审核人
+func (s *ReviewService) Reassign(req *dto.ReassignReviewRequest) (int64, error) {
+	if req.FromReviewerID == req.ToReviewerID {
+		return 0, fmt.Errorf("原审核人与新审核人不能相同")
+	}
+	if err := s.checkReviewer(req.ToReviewerID); err != nil {
+		return 0, err
+	}
+
+	var statuses []string
+	if !req.IncludeReviewed {
+		statuses = []string{models.ReviewStatusPending, models.ReviewStatusNeedsEdit}
+	}
+
+	moved, err := s.verdictRepo.Reassign(req.FromReviewerID, req.ToReviewerID, req.TaskID, statuses)
+	if err != nil {
+		return 0, fmt.Errorf("转交审核失败: %w", err)
+	}
+	if _, err := s.generatedDataRepo.ReassignReviewer(req.FromReviewerID, req.ToReviewerID, req.TaskID, statuses); err != nil {
+		return 0, fmt.Errorf("转交审核失败: %w", err)
+	}
+
+	log.Printf("[Review] 审核人 %d 的 %d 条审核记录已转交给 %d", req.FromReviewerID, moved, req.ToReviewerID)
+	if moved > 0 {
+		s.notifyReviewer(req.ToReviewerID, req.TaskID, fmt.Sprintf("管理员将 %d 条审核记录转交给你", moved))
+	}
+	return moved, nil
+}
+
+// notifyReviewer 向审核人发送审核分配的站内通知
+func (s *ReviewService) notifyReviewer(reviewerID uint, taskID, content string) {
+	if s.notifications == nil {
+		return
+	}
+	s.notifications.NotifyUser(reviewerID, models.NotificationTypeReview, "新的审核分配", content, "", taskID)
+}
+
+// GetProgressForUser 获取任务按审核人统计的审核进度（任务所有者）
+func (s *ReviewService) GetProgressForUser(taskID string, userID uint) (*dto.ReviewProgressResponse, error) {
+	task, err := s.taskRepo.GetVisibleByTaskID(taskID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("任务不存在或无权访问")
+	}
+	return s.GetProgress(task)
+}
+
+// GetProgress 获取任务按审核人统计的审核进度（多人审核时附带一致性统计）
+func (s *ReviewService) GetProgress(task *models.Task) (*dto.ReviewProgressResponse, error) {
+	statusCounts, unassigned, err := s.generatedDataRepo.CountReviewStatus(task.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("统计审核进度失败: %w", err)
+	}
+	rows, err := s.verdictRepo.CountByReviewer(task.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("统计审核进度失败: %w", err)
+	}
+
+	result := &dto.ReviewProgressResponse{
+		TaskID:          task.TaskID,
+		RequiredReviews: requiredReviews(task),
+		Disputed:        statusCounts[models.ReviewStatusDisputed],
+		Unassigned:      unassigned,
+		Reviewers:       make([]dto.ReviewerProgress, 0, len(rows)+1),
+	}
+	for status, count := range statusCounts {
+		result.Total += count
+		if status != models.ReviewStatusPending {
+			result.Reviewed += count
+		}
+	}
+	if result.Total > 0 {
+		result.Percent = float64(result.Reviewed) / float64(result.Total) * 100
+	}
+
+	for _, row := range rows {
+		reviewerID := row.ReviewerID
+		progress := dto.ReviewerProgress{
+			ReviewerID: &reviewerID,
+			Total:      row.Total,
+			Approved:   row.Approved,
+			Rejected:   row.Rejected,
+			NeedsEdit:  row.NeedsEdit,
+			Pending:    row.Total - row.Approved - row.Rejected - row.NeedsEdit,
+		}
+		if reviewer, err := s.userRepo.GetByID(row.ReviewerID); err == nil {
+			progress.ReviewerName = reviewer.Username
+		}
+		if row.Total > 0 {
+			progress.Percent = float64(row.Total-progress.Pending) / float64(row.Total) * 100
+		}
+		result.Reviewers = append(result.Reviewers, progress)
+	}
+	if unassigned > 0 {
+		result.Reviewers = append(result.Reviewers, dto.ReviewerProgress{Total: unassigned, Pending: unassigned})
+	}
+
+	result.Agreement, err = s.GetAgreement(task)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetAgreementByTaskID 按任务ID计算审核一致性（单人审核的任务返回 nil）
+func (s *ReviewService) GetAgreementByTaskID(taskID string) (*dto.ReviewAgreement, error) {
+	task, err := s.taskRepo.GetByTaskID(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("任务不存在")
+	}
+	return s.GetAgreement(task)
+}
+
+// GetAgreement 计算任务的审核一致性（单人审核的任务返回 nil）
+func (s *ReviewService) GetAgreement(task *models.Task) (*dto.ReviewAgreement, error) {
+	if requiredReviews(task) <= 1 {
+		return nil, nil
+	}
+	verdicts, err := s.verdictRepo.ListSubmittedByTaskID(task.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("获取审核结论失败: %w", err)
+	}
+	return computeReviewAgreement(verdicts, requiredReviews(task)), nil
+}
+
+// checkReviewer 校验审核人存在且已启用
+func (s *ReviewService) checkReviewer(reviewerID uint) error {
+	reviewer, err := s.userRepo.GetByID(reviewerID)
+	if err != nil || !reviewer.IsActive {
+		return fmt.Errorf("审核人 %d 不存在或已禁用", reviewerID)
+	}
+	return nil
+}
+
+// uniqueIDs 去除重复ID（保持原顺序）
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+)
+
+// 内容安全检查限制
+const (
+	safetyBatchSize      = 500
+	safetyMaxFlagsLength = 500 // 与 safety_flags 列长度一致
+	safetyModelSafeLabel = "safe"
+)
+
+// SafetyService 生成数据内容安全检查服务
+// 按配置的屏蔽词、正则表达式和审核模型检查任务数据，命中的数据写入 safety_flags，导出时默认排除
+type SafetyService struct {
+	generatedDataRepo *repository.GeneratedDataRepository
+	taskRepo          *repository.TaskRepository
+	modelRepo         *repository.ModelConfigRepository
+	modelService      *ModelService
+	jobPool           *JobPool
+	cfg               *config.Config
+}
+
+// NewSafetyService 创建内容安全检查服务
+func NewSafetyService(
+	generatedDataRepo *repository.GeneratedDataRepository,
+	taskRepo *repository.TaskRepository,
+	modelRepo *repository.ModelConfigRepository,
+	modelService *ModelService,
+	jobPool *JobPool,
+	cfg *config.Config,
+) *SafetyService {
+	return &SafetyService{
+		generatedDataRepo: generatedDataRepo,
+		taskRepo:          taskRepo,
+		modelRepo:         modelRepo,
+		modelService:      modelService,
+		jobPool:           jobPool,
+		cfg:               cfg,
+	}
+}
+
+// HasRules 是否配置了检查规则
+func (s *SafetyService) HasRules() bool {
+	return s.cfg.Current().Safety.HasRules()
+}
+
+// AutoCheckEnabled 所有任务结束后是否自动检查
+func (s *SafetyService) AutoCheckEnabled() bool {
+	return s.cfg.Current().Safety.Enabled && s.cfg.Current().Safety.HasRules()
+}
+
+// CheckTaskForUser 校验任务归属后重新检查
+func (s *SafetyService) CheckTaskForUser(taskID string, userID uint) (*dto.SafetyCheckResponse, error) {
+	task, err := s.taskRepo.GetEditableByTaskID(taskID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("任务不存在或无权访问")
+	}
+	if task.Status == models.TaskStatusRunning {
+		return nil, fmt.Errorf("任务仍在运行，请在任务结束后检查")
+	}
+	return s.CheckTask(taskID)
+}
+
+// CheckTask 检查任务数据；重复执行会覆盖上一次的结果
+func (s *SafetyService) CheckTask(taskID string) (*dto.SafetyCheckResponse, error) {
+	if !s.HasRules() {
+		return nil, fmt.Errorf("未配置内容安全检查规则")
+	}
+
+	var result *dto.SafetyCheckResponse
+	err := s.jobPool.Run(JobKindSafetyCheck, func() error {
+		var err error
+		result, err = s.checkTask(taskID)
+		return err
+	})
+	return result, err
+}
+
+// safetyChecker 预处理后的检查规则
+type safetyChecker struct {
+	blocklist []string
+	patterns  []*regexp.Regexp
+	model     *models.ModelConfig
+}
+
+// newSafetyChecker 根据配置构建检查规则（正则在加载配置时已校验）
+func (s *SafetyService) newSafetyChecker() (*safetyChecker, error) {
+	checker := &safetyChecker{}
+	for _, word := range s.cfg.Current().Safety.Blocklist {
+		if word = strings.TrimSpace(word); word != "" {
+			checker.blocklist = append(checker.blocklist, strings.ToLower(word))
+		}
+	}
+	for _, pattern := range s.cfg.Current().Safety.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("无效的内容安全正则表达式 %q: %w", pattern, err)
+		}
+		checker.patterns = append(checker.patterns, re)
+	}
+	if s.cfg.Current().Safety.ModelID != 0 {
+		model, err := s.modelRepo.GetByID(s.cfg.Current().Safety.ModelID)
+		if err != nil {
+			return nil, fmt.Errorf("审核模型配置不存在")
+		}
+		checker.model = model
+	}
+	return checker, nil
+}
+
+// checkTask 检查的具体实现
+func (s *SafetyService) checkTask(taskID string) (*dto.SafetyCheckResponse, error) {
+	checker, err := s.newSafetyChecker()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.SafetyCheckResponse{TaskID: taskID, FlagCounts: make(map[string]int64)}
+	flags := make(map[uint]string)
+
+	err = s.generatedDataRepo.ScanForDedup(taskID, safetyBatchSize, func(batch []models.GeneratedData) error {
+		for _, data := range batch {
+			result.Checked++
+
+			var content interface{}
+			if err := json.Unmarshal([]byte(data.DataContent), &content); err != nil {
+				continue
+			}
+			text := strings.Join(collectTextValues(content, nil), "\n")
+
+			dataFlags := s.matchRules(checker, text)
+			if checker.model != nil {
+				if category, err := s.moderate(checker.model, text); err != nil {
+					log.Printf("[Safety] 数据 %d 审核模型调用失败: %v", data.ID, err)
+					result.ModelErrors++
+				} else if category != "" {
+					dataFlags = append(dataFlags, "model:"+category)
+				}
+			}
+			if len(dataFlags) == 0 {
+				continue
+			}
+
+			flags[data.ID] = joinSafetyFlags(dataFlags)
+			result.Flagged++
+			for _, flag := range dataFlags {
+				result.FlagCounts[flag]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取生成数据失败: %w", err)
+	}
+
+	if err := s.generatedDataRepo.UpdateSafetyFlags(taskID, flags); err != nil {
+		return nil, fmt.Errorf("保存安全标记失败: %w", err)
+	}
+
+	log.Printf("[Safety] 任务 %s 内容安全检查完成: 检查 %d 条, 标记 %d 条", taskID, result.Checked, result.Flagged)
+	return result, nil
+}
+
+// matchRules 计算一条数据命中的屏蔽词和正则标记
+func (s *SafetyService) matchRules(checker *safetyChecker, text string) []string {
+	var flags []string
+	lower := strings.ToLower(text)
+	for _, word := range checker.blocklist {
+		if strings.Contains(lower, word) {
+			flags = append(flags, "blocklist:"+strings.ReplaceAll(word, ",", " "))
+		}
+	}
+	for i, re := range checker.patterns {
+		if re.MatchString(text) {
+			flags = append(flags, fmt.Sprintf("regex:%d", i))
+		}
+	}
+	return flags
+}
+
+// moderate 调用审核模型判定违规类别，安全或输出不在候选类别中时返回空
+func (s *SafetyService) moderate(model *models.ModelConfig, text string) (string, error) {
+	if runes := []rune(text); len(runes) > classifierMaxInputRunes {
+		text = string(runes[:classifierMaxInputRunes])
+	}
+
+	categories := s.cfg.Current().Safety.Categories
+	prompt := fmt.Sprintf("请判断下面的数据是否包含不安全内容。如果安全，只输出 %s；否则从以下类别中选择最符合的一个，只输出类别本身，不要输出其他内容。\n类别：%s\n\n数据：\n%s",
+		safetyModelSafeLabel, strings.Join(categories, "、"), text)
+
+	resp, err := s.modelService.CallModel(&dto.ModelCallProxyRequest{
+		APIUrl:      model.APIURL,
+		APIKey:      model.APIKey,
+		Model:       model.ModelPath,
+		Messages:    []dto.Message{{Role: "user", Content: prompt}},
+		Temperature: 0,
+		TopP:        model.TopP,
+		MaxTokens:   32,
+		Timeout:     model.Timeout,
+		IsVLLM:      model.IsVLLM,
+	})
+	if err != nil {
+		return "", err
+	}
+	if !resp.Success {
+		return "", fmt.Errorf("%s", resp.Error)
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(resp.Content))
+	if answer == safetyModelSafeLabel {
+		return "", nil
+	}
+	for _, category := range categories {
+		if strings.Contains(answer, strings.ToLower(category)) {
+			return category, nil
+		}
+	}
+	return "", nil
+}
+
+// joinSafetyFlags 拼接标记，超出列长度的标记丢弃（至少保留一个，保证数据仍被视为已标记）
+func joinSafetyFlags(flags []string) string {
+	joined := flags[0]
+	if len(joined) > safetyMaxFlagsLength {
+		return strings.ToValidUTF8(joined[:safetyMaxFlagsLength], "")
+	}
+	for _, flag := range flags[1:] {
+		if len(joined)+1+len(flag) > safetyMaxFlagsLength {
+			break
+		}
+		joined += "," + flag
+	}
+	return joined
+}
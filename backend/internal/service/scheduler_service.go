@@ -0,0 +1,361 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+	"gen-go/internal/utils"
+)
+
+// SchedulerService 定时/周期任务调度服务
+// 定期扫描到期的定时任务并通过 TaskManager 创建任务；上一次运行仍在进行时跳过本次触发
+// 每个实例都会扫描，触发前按 next_run_at 条件更新认领，同一次触发只由一个实例执行
+type SchedulerService struct {
+	scheduleRepo *repository.ScheduleRepository
+	taskRepo     *repository.TaskRepository
+	taskManager  *TaskManager
+	cfg          *config.Config
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewSchedulerService 创建调度服务
+func NewSchedulerService(
+	scheduleRepo *repository.ScheduleRepository,
+	taskRepo *repository.TaskRepository,
+	taskManager *TaskManager,
+	cfg *config.Config,
+) *SchedulerService {
+	return &SchedulerService{
+		scheduleRepo: scheduleRepo,
+		taskRepo:     taskRepo,
+		taskManager:  taskManager,
+		cfg:          cfg,
+		stopCh:       make(chan struct{}),
+	}
+}
+
+// Start 启动后台调度循环
+func (s *SchedulerService) Start() {
+	interval := s.cfg.Current().Scheduler.GetPollInterval()
+	log.Printf("[Scheduler] 调度器已启动，扫描间隔: %v", interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.runDue()
+			case <-s.stopCh:
+				log.Printf("[Scheduler] 调度器已停止")
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止调度循环
+func (s *SchedulerService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// runDue 执行所有到期的定时任务
+func (s *SchedulerService) runDue() {
+	now := time.Now().UTC()
+	schedules, err := s.scheduleRepo.ListDue(now)
+	if err != nil {
+		log.Printf("[Scheduler] 查询到期定时任务失败: %v", err)
+		return
+	}
+
+	for i := range schedules {
+		s.trigger(&schedules[i], now)
+	}
+}
+
+// trigger 认领并触发一次定时任务，记录执行结果；已被其他实例认领时直接返回
+func (s *SchedulerService) trigger(schedule *models.Schedule, now time.Time) {
+	dueAt := *schedule.NextRunAt
+	// 错过的触发不补跑，从当前时间起计算下一次
+	nextRunAt := nextRunTime(schedule, now)
+	claimed, err := s.scheduleRepo.ClaimDue(schedule.ID, dueAt, now, nextRunAt)
+	if err != nil {
+		log.Printf("[Scheduler] 认领定时任务 %d 失败: %v", schedule.ID, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	run := &models.ScheduleRun{
+		ScheduleID:  schedule.ID,
+		UserID:      schedule.UserID,
+		ScheduledAt: dueAt,
+	}
+
+	if active, taskID := s.previousRunActive(schedule); active {
+		run.Status = models.ScheduleRunSkipped
+		run.Message = fmt.Sprintf("上一次运行的任务 %s 仍在进行，跳过本次触发", taskID)
+	} else if resp, err := s.startTask(schedule); err != nil {
+		run.Status = models.ScheduleRunFailed
+		run.Message = err.Error()
+	} else {
+		run.Status = models.ScheduleRunCreated
+		run.TaskID = resp.TaskID
+		if err := s.scheduleRepo.UpdateLastTaskID(schedule.ID, resp.TaskID); err != nil {
+			log.Printf("[Scheduler] 更新定时任务 %d 失败: %v", schedule.ID, err)
+		}
+	}
+
+	log.Printf("[Scheduler] 定时任务 %d (%s) 触发: %s %s", schedule.ID, schedule.Name, run.Status, run.Message)
+	if err := s.scheduleRepo.CreateRun(run); err != nil {
+		log.Printf("[Scheduler] 保存执行记录失败: %v", err)
+	}
+}
+
+// previousRunActive 判断上一次创建的任务是否尚未结束（任何非终态都视为仍在运行）
+func (s *SchedulerService) previousRunActive(schedule *models.Schedule) (bool, string) {
+	if schedule.LastTaskID == "" {
+		return false, ""
+	}
+	task, err := s.taskRepo.GetByTaskID(schedule.LastTaskID)
+	if err != nil {
+		return false, ""
+	}
+	return !task.Status.IsTerminal(), task.TaskID
+}
+
+// startTask 按保存的参数启动任务
+func (s *SchedulerService) startTask(schedule *models.Schedule) (*dto.StartTaskResponse, error) {
+	req, err := decodeScheduleTask(schedule.TaskParams)
+	if err != nil {
+		return nil, err
+	}
+	return s.taskManager.StartTask(schedule.UserID, req)
+}
+
+// CreateSchedule 创建定时任务；timezone 为空时使用 defaultLoc（用户展示时区）
+func (s *SchedulerService) CreateSchedule(userID uint, req *dto.CreateScheduleRequest, defaultLoc *time.Location) (*dto.ScheduleResponse, error) {
+	if _, err := utils.ParseCron(req.CronExpr); err != nil {
+		return nil, fmt.Errorf("无效的 cron 表达式: %w", err)
+	}
+
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = defaultLoc.String()
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, fmt.Errorf("无效的时区: %s", timezone)
+	}
+
+	params, err := encodeScheduleTask(&req.Task)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &models.Schedule{
+		UserID:     userID,
+		Name:       req.Name,
+		CronExpr:   req.CronExpr,
+		Timezone:   timezone,
+		TaskParams: params,
+		IsActive:   true,
+	}
+	schedule.NextRunAt = nextRunTime(schedule, time.Now().UTC())
+
+	if err := s.scheduleRepo.Create(schedule); err != nil {
+		return nil, fmt.Errorf("创建定时任务失败: %w", err)
+	}
+	return toScheduleResponse(schedule), nil
+}
+
+// ListSchedules 获取用户的定时任务
+func (s *SchedulerService) ListSchedules(userID uint) ([]*dto.ScheduleResponse, error) {
+	schedules, err := s.scheduleRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.ScheduleResponse, len(schedules))
+	for i := range schedules {
+		result[i] = toScheduleResponse(&schedules[i])
+	}
+	return result, nil
+}
+
+// GetSchedule 获取定时任务详情
+func (s *SchedulerService) GetSchedule(id uint, userID uint) (*dto.ScheduleResponse, error) {
+	schedule, err := s.scheduleRepo.GetByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("定时任务不存在或无权访问")
+	}
+	return toScheduleResponse(schedule), nil
+}
+
+// UpdateSchedule 更新定时任务（修改表达式、时区或重新启用时重新计算下一次触发时间）
+func (s *SchedulerService) UpdateSchedule(id uint, userID uint, req *dto.UpdateScheduleRequest) (*dto.ScheduleResponse, error) {
+	schedule, err := s.scheduleRepo.GetByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("定时任务不存在或无权访问")
+	}
+
+	if req.Name != nil {
+		schedule.Name = *req.Name
+	}
+	if req.CronExpr != nil {
+		if _, err := utils.ParseCron(*req.CronExpr); err != nil {
+			return nil, fmt.Errorf("无效的 cron 表达式: %w", err)
+		}
+		schedule.CronExpr = *req.CronExpr
+	}
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil {
+			return nil, fmt.Errorf("无效的时区: %s", *req.Timezone)
+		}
+		schedule.Timezone = *req.Timezone
+	}
+	if req.Task != nil {
+		params, err := encodeScheduleTask(req.Task)
+		if err != nil {
+			return nil, err
+		}
+		schedule.TaskParams = params
+	}
+	if req.IsActive != nil {
+		schedule.IsActive = *req.IsActive
+	}
+
+	schedule.NextRunAt = nextRunTime(schedule, time.Now().UTC())
+
+	if err := s.scheduleRepo.Update(schedule); err != nil {
+		return nil, fmt.Errorf("更新定时任务失败: %w", err)
+	}
+	return toScheduleResponse(schedule), nil
+}
+
+// DeleteSchedule 删除定时任务
+func (s *SchedulerService) DeleteSchedule(id uint, userID uint) error {
+	if _, err := s.scheduleRepo.GetByIDAndUserID(id, userID); err != nil {
+		return fmt.Errorf("定时任务不存在或无权访问")
+	}
+	return s.scheduleRepo.Delete(id)
+}
+
+// ListRuns 分页获取定时任务的执行记录
+func (s *SchedulerService) ListRuns(id uint, userID uint, page, perPage int) (*dto.PaginatedResponse, error) {
+	if _, err := s.scheduleRepo.GetByIDAndUserID(id, userID); err != nil {
+		return nil, fmt.Errorf("定时任务不存在或无权访问")
+	}
+
+	offset := (page - 1) * perPage
+	runs, total, err := s.scheduleRepo.ListRuns(id, offset, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.ScheduleRunResponse, len(runs))
+	for i, run := range runs {
+		items[i] = dto.ScheduleRunResponse{
+			ID:          run.ID,
+			ScheduleID:  run.ScheduleID,
+			Status:      run.Status,
+			TaskID:      run.TaskID,
+			Message:     run.Message,
+			ScheduledAt: dto.FormatTime(run.ScheduledAt),
+			CreatedAt:   dto.FormatTime(run.CreatedAt),
+		}
+	}
+
+	return &dto.PaginatedResponse{
+		Items:   items,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	}, nil
+}
+
+// nextRunTime 计算下一次触发时间（停用时返回nil）
+func nextRunTime(schedule *models.Schedule, after time.Time) *time.Time {
+	if !schedule.IsActive {
+		return nil
+	}
+
+	cron, err := utils.ParseCron(schedule.CronExpr)
+	if err != nil {
+		return nil
+	}
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	next := cron.Next(after.In(loc))
+	if next.IsZero() {
+		return nil
+	}
+	next = next.UTC()
+	return &next
+}
+
+// encodeScheduleTask 将启动任务参数（补全默认值后）保存为JSON
+func encodeScheduleTask(req *dto.StartTaskRequest) (models.JSONMap, error) {
+	req.ApplyDefaults()
+	raw, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("序列化任务参数失败: %w", err)
+	}
+	var params models.JSONMap
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, fmt.Errorf("序列化任务参数失败: %w", err)
+	}
+	return params, nil
+}
+
+// decodeScheduleTask 还原启动任务参数
+func decodeScheduleTask(params models.JSONMap) (*dto.StartTaskRequest, error) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("解析任务参数失败: %w", err)
+	}
+	var req dto.StartTaskRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return nil, fmt.Errorf("解析任务参数失败: %w", err)
+	}
+	return &req, nil
+}
+
+// toScheduleResponse 转换定时任务响应（隐藏 api_key）
+func toScheduleResponse(schedule *models.Schedule) *dto.ScheduleResponse {
+	task := make(map[string]interface{}, len(schedule.TaskParams))
+	for k, v := range schedule.TaskParams {
+		if k != "api_key" {
+			task[k] = v
+		}
+	}
+
+	return &dto.ScheduleResponse{
+		ID:         schedule.ID,
+		Name:       schedule.Name,
+		CronExpr:   schedule.CronExpr,
+		Timezone:   schedule.Timezone,
+		Task:       task,
+		IsActive:   schedule.IsActive,
+		NextRunAt:  dto.FormatTimePtr(schedule.NextRunAt),
+		LastRunAt:  dto.FormatTimePtr(schedule.LastRunAt),
+		LastTaskID: schedule.LastTaskID,
+		CreatedAt:  dto.FormatTime(schedule.CreatedAt),
+		UpdatedAt:  dto.FormatTime(schedule.UpdatedAt),
+	}
+}
@@ -0,0 +1,477 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+	"gen-go/internal/utils"
+)
+
+// 规则评分
+const (
+	ruleScoreMax       = 10 // 与 Python 端规则评分的满分一致
+	ruleScoreBatchSize = 500
+)
+
+// ScoringService 生成数据规则评分服务
+// 按管理员为任务类型配置的规则（长度、必须/禁止匹配的正则、回答的 JSON Schema、语言）检查任务数据，
+// 写入 rule_score 和未通过的规则及原因
+type ScoringService struct {
+	ruleRepo          *repository.ScoringRuleRepository
+	taskTypeRepo      *repository.TaskTypeRepository
+	generatedDataRepo *repository.GeneratedDataRepository
+	taskRepo          *repository.TaskRepository
+	jobPool           *JobPool
+}
+
+// compiledScoringRule 预编译的评分规则
+type compiledScoringRule struct {
+	rule      models.ScoringRule
+	regex     *regexp.Regexp
+	schema    map[string]interface{}
+	languages []string
+}
+
+// NewScoringService 创建规则评分服务
+func NewScoringService(
+	ruleRepo *repository.ScoringRuleRepository,
+	taskTypeRepo *repository.TaskTypeRepository,
+	generatedDataRepo *repository.GeneratedDataRepository,
+	taskRepo *repository.TaskRepository,
+	jobPool *JobPool,
+) *ScoringService {
+	return &ScoringService{
+		ruleRepo:          ruleRepo,
+		taskTypeRepo:      taskTypeRepo,
+		generatedDataRepo: generatedDataRepo,
+		taskRepo:          taskRepo,
+		jobPool:           jobPool,
+	}
+}
+
+// CreateRule 创建评分规则
+func (s *ScoringService) CreateRule(userID uint, req *dto.CreateScoringRuleRequest) (*dto.ScoringRuleResponse, error) {
+	rule, err := newScoringRule(userID, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTaskType(rule.TaskType); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Create(rule); err != nil {
+		return nil, fmt.Errorf("创建评分规则失败: %w", err)
+	}
+	return toScoringRuleResponse(rule), nil
+}
+
+// newScoringRule 按请求构造评分规则并校验
+func newScoringRule(userID uint, req *dto.CreateScoringRuleRequest) (*models.ScoringRule, error) {
+	rule := &models.ScoringRule{
+		TaskType:  strings.TrimSpace(req.TaskType),
+		Name:      req.Name,
+		RuleType:  req.RuleType,
+		Target:    req.Target,
+		Pattern:   req.Pattern,
+		MinLength: req.MinLength,
+		MaxLength: req.MaxLength,
+		Weight:    req.Weight,
+		IsActive:  req.IsActive == nil || *req.IsActive,
+		CreatedBy: userID,
+	}
+	if rule.Target == "" {
+		rule.Target = models.ScoringTargetAssistant
+	}
+	if rule.Weight == 0 {
+		rule.Weight = 1
+	}
+	if _, err := compileScoringRule(*rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// checkTaskType 校验规则引用的任务类型已注册（为空表示适用于全部任务类型）
+func (s *ScoringService) checkTaskType(taskType string) error {
+	if taskType == "" {
+		return nil
+	}
+	if _, err := s.taskTypeRepo.GetByName(taskType); err != nil {
+		return fmt.Errorf("任务类型不存在: %s", taskType)
+	}
+	return nil
+}
+
+// ListRules 获取评分规则，taskType 不为空时只返回该任务类型的规则
+func (s *ScoringService) ListRules(taskType string) ([]*dto.ScoringRuleResponse, error) {
+	rules, err := s.ruleRepo.List(taskType)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.ScoringRuleResponse, len(rules))
+	for i := range rules {
+		result[i] = toScoringRuleResponse(&rules[i])
+	}
+	return result, nil
+}
+
+// GetRule 获取评分规则
+func (s *ScoringService) GetRule(id uint) (*dto.ScoringRuleResponse, error) {
+	rule, err := s.ruleRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("评分规则不存在")
+	}
+	return toScoringRuleResponse(rule), nil
+}
+
+// UpdateRule 更新评分规则
+func (s *ScoringService) UpdateRule(id uint, req *dto.UpdateScoringRuleRequest) (*dto.ScoringRuleResponse, error) {
+	rule, err := s.ruleRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("评分规则不存在")
+	}
+
+	if req.TaskType != nil {
+		rule.TaskType = strings.TrimSpace(*req.TaskType)
+		if err := s.checkTaskType(rule.TaskType); err != nil {
+			return nil, err
+		}
+	}
+	if req.Name != nil {
+		rule.Name = *req.Name
+	}
+	if req.RuleType != nil {
+		rule.RuleType = *req.RuleType
+	}
+	if req.Target != nil {
+		rule.Target = *req.Target
+	}
+	if req.Pattern != nil {
+		rule.Pattern = *req.Pattern
+	}
+	if req.MinLength != nil {
+		rule.MinLength = *req.MinLength
+	}
+	if req.MaxLength != nil {
+		rule.MaxLength = *req.MaxLength
+	}
+	if req.Weight != nil {
+		rule.Weight = *req.Weight
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+	if _, err := compileScoringRule(*rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Update(rule); err != nil {
+		return nil, fmt.Errorf("更新评分规则失败: %w", err)
+	}
+	return toScoringRuleResponse(rule), nil
+}
+
+// DeleteRule 删除评分规则（已写入数据的评分保留，重新评分后更新）
+func (s *ScoringService) DeleteRule(id uint) error {
+	if _, err := s.ruleRepo.GetByID(id); err != nil {
+		return fmt.Errorf("评分规则不存在")
+	}
+	return s.ruleRepo.Delete(id)
+}
+
+// ScoreTaskForUser 校验任务归属后按当前规则重新评分
+func (s *ScoringService) ScoreTaskForUser(taskID string, userID uint) (*dto.RuleScoreTaskResponse, error) {
+	task, err := s.taskRepo.GetEditableByTaskID(taskID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("任务不存在或无权访问")
+	}
+	if task.Status == models.TaskStatusRunning {
+		return nil, fmt.Errorf("任务仍在运行，请在任务结束后评分")
+	}
+	taskType, _ := task.Params["task_type"].(string)
+	return s.ScoreTask(taskID, taskType)
+}
+
+// ScoreTask 使用任务类型启用的规则（以及类型的输出格式）为任务数据评分；重复执行会覆盖上一次的结果（包括工作进程写入的规则评分）
+// 任务类型没有启用的规则时返回 nil
+func (s *ScoringService) ScoreTask(taskID string, taskType string) (*dto.RuleScoreTaskResponse, error) {
+	rules, err := s.ruleRepo.ListActiveForTaskType(taskType)
+	if err != nil {
+		return nil, fmt.Errorf("获取评分规则失败: %w", err)
+	}
+	if registered, err := s.taskTypeRepo.GetByName(taskType); err == nil && registered.OutputSchema != "" {
+		rules = append(rules, models.ScoringRule{
+			TaskType: taskType,
+			Name:     "输出格式",
+			RuleType: models.ScoringRuleJSONSchema,
+			Target:   models.ScoringTargetAssistant,
+			Pattern:  registered.OutputSchema,
+			Weight:   1,
+		})
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	compiled := make([]*compiledScoringRule, 0, len(rules))
+	for _, rule := range rules {
+		c, err := compileScoringRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("评分规则 %s 无效: %w", rule.Name, err)
+		}
+		compiled = append(compiled, c)
+	}
+
+	var result *dto.RuleScoreTaskResponse
+	err = s.jobPool.Run(JobKindRuleScoring, func() error {
+		var err error
+		result, err = s.scoreTask(taskID, taskType, compiled)
+		return err
+	})
+	return result, err
+}
+
+// scoreTask 规则评分的具体实现
+func (s *ScoringService) scoreTask(taskID, taskType string, rules []*compiledScoringRule) (*dto.RuleScoreTaskResponse, error) {
+	result := &dto.RuleScoreTaskResponse{
+		TaskID:       taskID,
+		TaskType:     taskType,
+		Rules:        len(rules),
+		RuleFailures: make(map[string]int),
+	}
+	scores := make(map[uint]int)
+	failures := make(map[uint]string)
+	scoreSum := 0
+
+	err := s.generatedDataRepo.ScanForDedup(taskID, ruleScoreBatchSize, func(batch []models.GeneratedData) error {
+		for _, data := range batch {
+			result.Checked++
+
+			var content map[string]interface{}
+			if err := json.Unmarshal([]byte(data.DataContent), &content); err != nil {
+				content = map[string]interface{}{}
+			}
+			score, failed := scoreContent(rules, content)
+			scores[data.ID] = score
+			scoreSum += score
+			if len(failed) == 0 {
+				result.Passed++
+				continue
+			}
+			for _, f := range failed {
+				result.RuleFailures[f.Rule]++
+			}
+			raw, _ := json.Marshal(failed)
+			failures[data.ID] = string(raw)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取生成数据失败: %w", err)
+	}
+
+	if err := s.generatedDataRepo.UpdateRuleScores(scores, failures); err != nil {
+		return nil, fmt.Errorf("保存规则评分失败: %w", err)
+	}
+	if result.Checked > 0 {
+		result.AverageScore = float64(scoreSum) / float64(result.Checked)
+	}
+
+	log.Printf("[RuleScoring] 任务 %s 规则评分完成: 规则 %d 条, 检查 %d 条, 全部通过 %d 条", taskID, len(rules), result.Checked, result.Passed)
+	return result, nil
+}
+
+// scoreContent 按规则检查一条数据，返回 0~10 的评分（通过规则的权重占比）和未通过的规则
+func scoreContent(rules []*compiledScoringRule, content map[string]interface{}) (int, []dto.RuleFailure) {
+	var failures []dto.RuleFailure
+	total, passed := 0, 0
+	for _, r := range rules {
+		total += r.rule.Weight
+		if reason := r.check(scoringText(content, r.rule.Target)); reason != "" {
+			failures = append(failures, dto.RuleFailure{RuleID: r.rule.ID, Rule: r.rule.Name, Reason: reason})
+			continue
+		}
+		passed += r.rule.Weight
+	}
+	if total == 0 {
+		return ruleScoreMax, nil
+	}
+	return int(math.Round(float64(ruleScoreMax*passed) / float64(total))), failures
+}
+
+// compileScoringRule 校验规则并预编译正则表达式和 JSON Schema
+func compileScoringRule(rule models.ScoringRule) (*compiledScoringRule, error) {
+	switch rule.Target {
+	case models.ScoringTargetAssistant, models.ScoringTargetHuman, models.ScoringTargetAll:
+	default:
+		return nil, fmt.Errorf("不支持的检查对象: %s", rule.Target)
+	}
+	if rule.Weight <= 0 {
+		return nil, fmt.Errorf("权重必须大于 0")
+	}
+
+	compiled := &compiledScoringRule{rule: rule}
+	switch rule.RuleType {
+	case models.ScoringRuleLength:
+		if rule.MinLength == 0 && rule.MaxLength == 0 {
+			return nil, fmt.Errorf("length 规则至少需要指定 min_length 或 max_length")
+		}
+		if rule.MaxLength > 0 && rule.MaxLength < rule.MinLength {
+			return nil, fmt.Errorf("max_length 不能小于 min_length")
+		}
+	case models.ScoringRuleRequiredRegex, models.ScoringRuleForbiddenRegex:
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("正则规则必须指定 pattern")
+		}
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("无效的正则表达式: %w", err)
+		}
+		compiled.regex = re
+	case models.ScoringRuleJSONSchema:
+		schema, err := utils.ParseJSONSchema(rule.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		compiled.schema = schema
+	case models.ScoringRuleLanguage:
+		compiled.languages = rule.LanguageList()
+		if len(compiled.languages) == 0 {
+			return nil, fmt.Errorf("language 规则至少需要一个候选语言（如 zh|en）")
+		}
+	default:
+		return nil, fmt.Errorf("不支持的规则类型: %s", rule.RuleType)
+	}
+	return compiled, nil
+}
+
+// check 检查文本，通过时返回空字符串，否则返回原因
+func (r *compiledScoringRule) check(text string) string {
+	switch r.rule.RuleType {
+	case models.ScoringRuleLength:
+		length := utf8.RuneCountInString(strings.TrimSpace(text))
+		if length < r.rule.MinLength {
+			return fmt.Sprintf("长度 %d 少于 %d", length, r.rule.MinLength)
+		}
+		if r.rule.MaxLength > 0 && length > r.rule.MaxLength {
+			return fmt.Sprintf("长度 %d 超过 %d", length, r.rule.MaxLength)
+		}
+	case models.ScoringRuleRequiredRegex:
+		if !r.regex.MatchString(text) {
+			return "未匹配必须出现的内容"
+		}
+	case models.ScoringRuleForbiddenRegex:
+		if match := r.regex.FindString(text); match != "" {
+			return fmt.Sprintf("包含禁止的内容: %s", truncateRunes(match, 50))
+		}
+	case models.ScoringRuleJSONSchema:
+		var value interface{}
+		if err := json.Unmarshal([]byte(stripCodeFence(text)), &value); err != nil {
+			return "回答不是合法的 JSON"
+		}
+		if err := utils.ValidateJSONSchema(r.schema, value); err != nil {
+			return err.Error()
+		}
+	case models.ScoringRuleLanguage:
+		lang := utils.DetectLanguage(text)
+		for _, expected := range r.languages {
+			if lang == expected {
+				return ""
+			}
+		}
+		if lang == "" {
+			return "无法识别语言"
+		}
+		return fmt.Sprintf("语言为 %s，要求 %s", lang, strings.Join(r.languages, "|"))
+	}
+	return ""
+}
+
+// scoringText 取数据中规则检查的文本：按角色拼接对应轮次的内容；没有 turns 时使用去掉 meta 后的完整内容
+func scoringText(content map[string]interface{}, target string) string {
+	turns, ok := content["turns"].([]interface{})
+	if !ok {
+		if target != models.ScoringTargetAll {
+			return ""
+		}
+		rest := make(map[string]interface{}, len(content))
+		for k, v := range content {
+			if k != "meta" {
+				rest[k] = v
+			}
+		}
+		raw, _ := json.Marshal(rest)
+		return string(raw)
+	}
+
+	var parts []string
+	for _, turnRaw := range turns {
+		turn, ok := turnRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		role, _ := turn["role"].(string)
+		role = strings.ToLower(strings.TrimSpace(role))
+		if target != models.ScoringTargetAll && role != target {
+			continue
+		}
+		if text, ok := turn["text"].(string); ok {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// stripCodeFence 去掉回答外层的 Markdown 代码块标记（```json ... ```）
+func stripCodeFence(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+	if newline := strings.Index(text, "\n"); newline >= 0 {
+		text = text[newline+1:]
+	} else {
+		text = strings.TrimPrefix(text, "```")
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "```"))
+}
+
+// parseRuleFailures 解析数据中保存的未通过规则
+func parseRuleFailures(raw string) []dto.RuleFailure {
+	if raw == "" {
+		return nil
+	}
+	var failures []dto.RuleFailure
+	if err := json.Unmarshal([]byte(raw), &failures); err != nil {
+		return nil
+	}
+	return failures
+}
+
+// toScoringRuleResponse 转换评分规则响应
+func toScoringRuleResponse(rule *models.ScoringRule) *dto.ScoringRuleResponse {
+	return &dto.ScoringRuleResponse{
+		ID:        rule.ID,
+		TaskType:  rule.TaskType,
+		Name:      rule.Name,
+		RuleType:  rule.RuleType,
+		Target:    rule.Target,
+		Pattern:   rule.Pattern,
+		MinLength: rule.MinLength,
+		MaxLength: rule.MaxLength,
+		Weight:    rule.Weight,
+		IsActive:  rule.IsActive,
+		CreatedBy: rule.CreatedBy,
+		CreatedAt: dto.FormatTime(rule.CreatedAt),
+		UpdatedAt: dto.FormatTime(rule.UpdatedAt),
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+	"gen-go/internal/utils"
+)
+
+// 全文检索限制
+const (
+	SearchDefaultLimit = 50
+	SearchMaxLimit     = 200
+	searchMinFTSRunes  = 3  // trigram 分词要求关键字至少 3 个字符，更短的关键字使用 LIKE
+	searchMaxFiles     = 20 // 单次检索最多扫描的文件数
+)
+
+// SearchService 数据文件与生成数据的全文检索服务
+type SearchService struct {
+	searchRepo *repository.SearchRepository
+}
+
+// NewSearchService 创建全文检索服务
+func NewSearchService(searchRepo *repository.SearchRepository) *SearchService {
+	return &SearchService{searchRepo: searchRepo}
+}
+
+// Search 在用户的数据文件和/或生成数据中检索关键字，返回命中位置和高亮摘要
+func (s *SearchService) Search(userID uint, query, scope string, limit int) (*dto.SearchResponse, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("检索关键字不能为空")
+	}
+	if scope == "" {
+		scope = dto.SearchScopeAll
+	}
+	if scope != dto.SearchScopeAll && scope != dto.SearchScopeFiles && scope != dto.SearchScopeGenerated {
+		return nil, fmt.Errorf("不支持的检索范围: %s", scope)
+	}
+
+	result := &dto.SearchResponse{
+		Query:  query,
+		Scope:  scope,
+		Engine: "like",
+		Hits:   []dto.SearchHit{},
+	}
+
+	if scope != dto.SearchScopeFiles {
+		if err := s.searchGeneratedData(userID, query, limit, result); err != nil {
+			return nil, err
+		}
+	}
+	if scope != dto.SearchScopeGenerated && len(result.Hits) < limit {
+		if err := s.searchDataFiles(userID, query, limit, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// searchGeneratedData 检索生成数据，FTS5 索引可用时优先使用
+func (s *SearchService) searchGeneratedData(userID uint, query string, limit int, result *dto.SearchResponse) error {
+	var dataList []models.GeneratedData
+	var err error
+	if models.SearchFTSEnabled() && utf8.RuneCountInString(query) >= searchMinFTSRunes {
+		result.Engine = "fts5"
+		dataList, err = s.searchRepo.SearchGeneratedDataFTS(userID, query, limit+1)
+	} else {
+		dataList, err = s.searchRepo.SearchGeneratedDataLike(userID, query, limit+1)
+	}
+	if err != nil {
+		return fmt.Errorf("检索生成数据失败: %w", err)
+	}
+
+	for _, data := range dataList {
+		if len(result.Hits) >= limit {
+			result.Truncated = true
+			break
+		}
+		// 数据库按字节匹配，这里再按字符确认并生成摘要（LIKE 对非 ASCII 字符区分大小写，可能有出入）
+		match, ok := utils.FindSearchMatch([]byte(data.DataContent), query)
+		if !ok {
+			continue
+		}
+		result.Hits = append(result.Hits, dto.SearchHit{
+			Type:    "generated",
+			DataID:  data.ID,
+			TaskID:  data.TaskID,
+			Field:   match.Field,
+			Role:    match.Role,
+			Snippet: match.Snippet,
+		})
+	}
+	return nil
+}
+
+// searchDataFiles 检索数据文件：先用 LIKE 找出包含关键字的文件，再逐行定位
+func (s *SearchService) searchDataFiles(userID uint, query string, limit int, result *dto.SearchResponse) error {
+	files, err := s.searchRepo.SearchDataFiles(userID, query, searchMaxFiles)
+	if err != nil {
+		return fmt.Errorf("检索数据文件失败: %w", err)
+	}
+
+	for _, file := range files {
+		done, err := s.searchFileLines(&file, query, limit, result)
+		if err != nil {
+			log.Printf("[Search] 读取文件 %d 失败: %v", file.ID, err)
+			continue
+		}
+		if done {
+			return nil
+		}
+	}
+	return nil
+}
+
+// searchFileLines 逐行检索单个文件（内容在文件存储中时流式读取），结果数达到 limit 时返回 true
+func (s *SearchService) searchFileLines(file *models.DataFile, query string, limit int, result *dto.SearchResponse) (bool, error) {
+	r, err := file.OpenContent()
+	if err != nil {
+		return false, err
+	}
+	defer r.Close()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		match, ok := utils.FindSearchMatch(line, query)
+		if !ok {
+			continue
+		}
+		if len(result.Hits) >= limit {
+			result.Truncated = true
+			return true, nil
+		}
+		result.Hits = append(result.Hits, dto.SearchHit{
+			Type:     "file",
+			FileID:   file.ID,
+			Filename: file.Filename,
+			Line:     lineNo,
+			Field:    match.Field,
+			Role:     match.Role,
+			Snippet:  match.Snippet,
+		})
+	}
+	return false, scanner.Err()
+}
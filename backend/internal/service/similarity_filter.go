@@ -0,0 +1,218 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+	"gen-go/internal/utils"
+)
+
+// SimilarityFilterResult 语义相似度过滤结果
+type SimilarityFilterResult struct {
+	Checked        int
+	SourceSimilar  int // 与源样本过于相似而删除的条数
+	VariantSimilar int // 与已保留的数据过于相似而删除的条数
+	Unembedded     int // 向量化失败、未参与过滤的条数
+}
+
+// seededVariant 生成数据与其种子样本的对应关系
+type seededVariant struct {
+	seedHash string
+	round    int // meta.round，从 1 开始
+}
+
+// FilterSimilarTask 语义相似度过滤：删除与源样本或已保留数据的余弦相似度超过 maxSimilarity 的生成数据
+// 源文件和生成数据先建立（或复用）向量索引；生成数据按ID升序依次判断，保留的数据写入最大相似度
+// 生成数据通过 seed_hash 列对应源样本，没有种子指纹的数据只与已保留的数据比较
+func (s *EmbeddingService) FilterSimilarTask(taskID string, fileID uint, maxSimilarity float64) (*SimilarityFilterResult, error) {
+	var result *SimilarityFilterResult
+	err := s.jobPool.Run(JobKindSimilarity, func() error {
+		var err error
+		result, err = s.filterSimilarTask(taskID, fileID, maxSimilarity)
+		return err
+	})
+	return result, err
+}
+
+// filterSimilarTask 语义相似度过滤的具体实现
+func (s *EmbeddingService) filterSimilarTask(taskID string, fileID uint, maxSimilarity float64) (*SimilarityFilterResult, error) {
+	model, err := s.modelService.EmbeddingModelByID(0)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.taskRepo.GetByTaskID(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("任务不存在: %w", err)
+	}
+	file, err := s.fileRepo.GetByID(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("源文件不存在: %w", err)
+	}
+
+	// 建立向量索引：内容未变的条目沿用已有向量，只向量化新增和修改的条目
+	fileJob := &embeddingJob{state: dto.EmbeddingIndexResponse{SourceType: models.EmbeddingSourceFile, FileID: file.ID, ModelID: model.ID}, startedAt: time.Now()}
+	if err := s.indexFile(fileJob, file, model); err != nil {
+		return nil, err
+	}
+	taskJob := &embeddingJob{state: dto.EmbeddingIndexResponse{SourceType: models.EmbeddingSourceGenerated, TaskID: taskID, ModelID: model.ID}, startedAt: time.Now()}
+	if err := s.indexTask(taskJob, task, model); err != nil {
+		return nil, err
+	}
+
+	variants, err := s.loadSeededVariants(taskID)
+	if err != nil {
+		return nil, err
+	}
+	seedItems, err := seedItemIndexes(file, variants)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceVectors := make(map[int][]float32)
+	err = s.embeddingRepo.ScanVectors(repository.EmbeddingFilter{ModelID: model.ID, SourceType: models.EmbeddingSourceFile, SourceID: file.ID},
+		embeddingScanBatchSize, func(batch []models.Embedding) error {
+			for _, row := range batch {
+				sourceVectors[row.ItemIndex] = models.DecodeVector(row.Vector)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("读取源文件向量失败: %w", err)
+	}
+	dataVectors := make(map[uint][]float32)
+	err = s.embeddingRepo.ScanVectors(repository.EmbeddingFilter{ModelID: model.ID, SourceType: models.EmbeddingSourceGenerated, TaskID: taskID},
+		embeddingScanBatchSize, func(batch []models.Embedding) error {
+			for _, row := range batch {
+				dataVectors[row.SourceID] = models.DecodeVector(row.Vector)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("读取生成数据向量失败: %w", err)
+	}
+
+	ids := make([]uint, 0, len(variants))
+	for id := range variants {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	result := &SimilarityFilterResult{}
+	scores := make(map[uint]float64)
+	var dropped []uint
+	var accepted [][]float32
+	for _, id := range ids {
+		result.Checked++
+		vector, ok := dataVectors[id]
+		if !ok {
+			result.Unembedded++
+			continue
+		}
+
+		sourceSimilarity := 0.0
+		if variant := variants[id]; variant.seedHash != "" {
+			if index, ok := seedItems[variant]; ok {
+				sourceSimilarity = CosineSimilarity(vector, sourceVectors[index])
+			}
+		}
+		if sourceSimilarity > maxSimilarity {
+			dropped = append(dropped, id)
+			result.SourceSimilar++
+			continue
+		}
+
+		similarity := sourceSimilarity
+		for _, other := range accepted {
+			if value := CosineSimilarity(vector, other); value > similarity {
+				similarity = value
+				if similarity > maxSimilarity {
+					break
+				}
+			}
+		}
+		if similarity > maxSimilarity {
+			dropped = append(dropped, id)
+			result.VariantSimilar++
+			continue
+		}
+
+		accepted = append(accepted, vector)
+		scores[id] = similarity
+	}
+
+	if err := s.generatedDataRepo.UpdateSimilarity(scores); err != nil {
+		return nil, fmt.Errorf("保存相似度失败: %w", err)
+	}
+	if len(dropped) > 0 {
+		if _, err := s.generatedDataRepo.DeleteByIDs(dropped); err != nil {
+			return nil, fmt.Errorf("删除相似数据失败: %w", err)
+		}
+	}
+
+	log.Printf("[SimilarityFilter] 任务 %s 语义相似度过滤完成: 检查 %d 条, 与源样本相似 %d 条, 与已保留数据相似 %d 条, 未向量化 %d 条",
+		taskID, result.Checked, result.SourceSimilar, result.VariantSimilar, result.Unembedded)
+	return result, nil
+}
+
+// loadSeededVariants 读取任务全部生成数据的种子指纹（seed_hash 列和 meta.round）
+// 早期数据的种子指纹写在 meta.seed_hash 中，seed_hash 列为空时从 meta 读取
+func (s *EmbeddingService) loadSeededVariants(taskID string) (map[uint]seededVariant, error) {
+	variants := make(map[uint]seededVariant)
+	err := s.generatedDataRepo.ScanForDedup(taskID, dedupBatchSize, func(batch []models.GeneratedData) error {
+		for _, data := range batch {
+			var content struct {
+				Meta struct {
+					SeedHash string `json:"seed_hash"`
+					Round    int    `json:"round"`
+				} `json:"meta"`
+			}
+			_ = json.Unmarshal([]byte(data.DataContent), &content)
+			seedHash := data.SeedHash
+			if seedHash == "" {
+				seedHash = content.Meta.SeedHash
+			}
+			variants[data.ID] = seededVariant{seedHash: seedHash, round: content.Meta.Round}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取生成数据失败: %w", err)
+	}
+	return variants, nil
+}
+
+// seedItemIndexes 按生成数据出现的轮次计算源文件每条样本的种子指纹，返回 种子 -> 条目下标
+func seedItemIndexes(file *models.DataFile, variants map[uint]seededVariant) (map[seededVariant]int, error) {
+	rounds := make(map[int]bool)
+	for _, variant := range variants {
+		if variant.seedHash != "" && variant.round > 0 {
+			rounds[variant.round] = true
+		}
+	}
+
+	items := make(map[seededVariant]int)
+	if len(rounds) == 0 {
+		return items, nil
+	}
+	err := utils.ScanJSONLLines(bytes.NewReader(file.FileContent), func(index int, line []byte) error {
+		for round := range rounds {
+			hash, err := utils.SeedHash(line, round-1)
+			if err != nil {
+				return nil
+			}
+			items[seededVariant{seedHash: hash, round: round}] = index
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("解析源文件失败: %w", err)
+	}
+	return items, nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/repository"
+)
+
+// StorageService 存储占用统计服务
+// 统计每个用户的数据文件、生成数据和日志占用的字节数，接近配额时给出警告
+type StorageService struct {
+	storageRepo *repository.StorageRepository
+	cfg         *config.Config
+}
+
+// NewStorageService 创建存储统计服务
+func NewStorageService(storageRepo *repository.StorageRepository, cfg *config.Config) *StorageService {
+	return &StorageService{
+		storageRepo: storageRepo,
+		cfg:         cfg,
+	}
+}
+
+// GetUserUsage 获取用户的存储占用
+func (s *StorageService) GetUserUsage(userID uint) (*dto.StorageUsageResponse, error) {
+	usage, err := s.storageRepo.UsageByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("统计存储占用失败: %w", err)
+	}
+	return s.toUsageResponse(usage), nil
+}
+
+// GetSummary 获取全部用户的存储占用汇总（管理员）
+func (s *StorageService) GetSummary() (*dto.StorageSummaryResponse, error) {
+	usages, err := s.storageRepo.UsageAllUsers()
+	if err != nil {
+		return nil, fmt.Errorf("统计存储占用失败: %w", err)
+	}
+
+	summary := &dto.StorageSummaryResponse{
+		DatabaseBytes: s.databaseSize(),
+		QuotaBytes:    s.cfg.Current().Storage.GetUserQuotaBytes(),
+		Users:         make([]*dto.StorageUsageResponse, 0, len(usages)),
+	}
+	for _, usage := range usages {
+		resp := s.toUsageResponse(usage)
+		summary.FileBytes += resp.FileBytes
+		summary.GeneratedDataBytes += resp.GeneratedDataBytes
+		summary.LogBytes += resp.LogBytes
+		summary.TotalBytes += resp.TotalBytes
+		switch resp.Status {
+		case dto.StorageStatusWarning:
+			summary.WarningUsers++
+		case dto.StorageStatusExceeded:
+			summary.ExceededUsers++
+		}
+		summary.Users = append(summary.Users, resp)
+	}
+
+	sort.SliceStable(summary.Users, func(i, j int) bool {
+		return summary.Users[i].TotalBytes > summary.Users[j].TotalBytes
+	})
+	return summary, nil
+}
+
+// toUsageResponse 转换占用响应并计算配额状态
+func (s *StorageService) toUsageResponse(usage *repository.StorageUsage) *dto.StorageUsageResponse {
+	resp := &dto.StorageUsageResponse{
+		UserID:             usage.UserID,
+		Username:           usage.Username,
+		FileCount:          usage.FileCount,
+		FileBytes:          usage.FileBytes,
+		GeneratedDataCount: usage.GeneratedDataCount,
+		GeneratedDataBytes: usage.GeneratedDataBytes,
+		LogCount:           usage.LogCount,
+		LogBytes:           usage.LogBytes,
+		TotalBytes:         usage.TotalBytes(),
+		QuotaBytes:         s.cfg.Current().Storage.GetUserQuotaBytes(),
+		Status:             dto.StorageStatusOK,
+	}
+
+	if resp.QuotaBytes <= 0 {
+		return resp
+	}
+
+	resp.UsedPercent = float64(resp.TotalBytes) * 100 / float64(resp.QuotaBytes)
+	switch {
+	case resp.TotalBytes >= resp.QuotaBytes:
+		resp.Status = dto.StorageStatusExceeded
+		resp.Warning = fmt.Sprintf("存储占用已超出配额（%.1f%%），请清理不再需要的文件或生成数据", resp.UsedPercent)
+	case resp.UsedPercent >= float64(s.cfg.Current().Storage.WarnPercent):
+		resp.Status = dto.StorageStatusWarning
+		resp.Warning = fmt.Sprintf("存储占用已达配额的 %.1f%%", resp.UsedPercent)
+	}
+	return resp
+}
+
+// databaseSize 获取 SQLite 数据库文件大小（含 -wal 和 -shm 文件），无法获取时返回0
+func (s *StorageService) databaseSize() int64 {
+	path := s.cfg.Current().Database.Path
+	if idx := strings.Index(path, "?"); idx >= 0 {
+		path = path[:idx]
+	}
+	path = strings.TrimPrefix(path, "file:")
+
+	var total int64
+	for _, p := range []string{path, path + "-wal", path + "-shm"} {
+		if info, err := os.Stat(p); err == nil {
+			total += info.Size()
+		}
+	}
+	return total
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+)
+
+// 分层抽样的维度
+const (
+	StratumDimensionTag      = "tag"
+	StratumDimensionTaskType = "task_type"
+	StratumDimensionScore    = "score"
+)
+
+// 评分分段（按 model_score，0-10 分）
+const (
+	ScoreBucketLow      = "low"      // < 6
+	ScoreBucketMedium   = "medium"   // 6 ~ 8
+	ScoreBucketHigh     = "high"     // >= 8
+	ScoreBucketUnscored = "unscored" // 未评分
+)
+
+// proportionEpsilon 比较占比之和时允许的浮点误差
+const proportionEpsilon = 1e-6
+
+// stratifiedExportMaxRows 分层抽样最多读取的数据条数，超出部分不参与抽样（清单中标记 truncated）
+const stratifiedExportMaxRows = 100000
+
+// scoreBucket 获取数据的评分分段
+func scoreBucket(data *models.GeneratedData) string {
+	switch {
+	case data.ModelScore == nil:
+		return ScoreBucketUnscored
+	case *data.ModelScore < 6:
+		return ScoreBucketLow
+	case *data.ModelScore < 8:
+		return ScoreBucketMedium
+	default:
+		return ScoreBucketHigh
+	}
+}
+
+// matchesStratum 判断数据是否属于某个层
+func matchesStratum(data *models.GeneratedData, stratum dto.StratumTarget) bool {
+	switch stratum.Dimension {
+	case StratumDimensionTag:
+		for _, tag := range data.TagList() {
+			if tag == stratum.Value {
+				return true
+			}
+		}
+		return false
+	case StratumDimensionTaskType:
+		return data.TaskType == stratum.Value
+	case StratumDimensionScore:
+		return scoreBucket(data) == stratum.Value
+	}
+	return false
+}
+
+// ExportStratified 按目标比例分层抽样导出数据
+// 返回的 ZIP 包含数据文件和 manifest.json（记录请求比例与实际分布），同时返回导出行数用于导出审计；需要任务的查看权限
+func (s *GeneratedDataService) ExportStratified(req *dto.StratifiedExportRequest, userID uint, loc *time.Location) ([]byte, string, int, error) {
+	if err := s.checkTask(req.TaskID, userID, models.PermissionView); err != nil {
+		return nil, "", 0, err
+	}
+
+	sum := 0.0
+	for _, target := range req.Targets {
+		sum += target.Proportion
+	}
+	if sum > 1+proportionEpsilon {
+		return nil, "", 0, fmt.Errorf("目标占比之和不能超过1")
+	}
+
+	dataList, total, err := s.listByFilter(req.TaskID, nil, !req.IncludeFlagged, 0, stratifiedExportMaxRows)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	if len(dataList) == 0 {
+		return nil, "", 0, fmt.Errorf("任务没有可导出的数据")
+	}
+
+	selected, manifest := stratifiedSample(dataList, req)
+	if total > int64(len(dataList)) {
+		manifest.Truncated = true
+		manifest.Warnings = append(manifest.Warnings,
+			fmt.Sprintf("任务共有 %d 条可导出数据，只有前 %d 条参与抽样", total, len(dataList)))
+	}
+	manifest.GeneratedAt = dto.FormatTime(time.Now())
+
+	content, ext, err := encodeExportData(selected, req.Format)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	manifestContent, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("生成导出清单失败: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := []struct {
+		name string
+		body []byte
+	}{
+		{req.TaskID + ext, content},
+		{"manifest.json", manifestContent},
+	}
+	for _, file := range files {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return nil, "", 0, fmt.Errorf("打包导出文件失败: %w", err)
+		}
+		if _, err := w.Write(file.body); err != nil {
+			return nil, "", 0, fmt.Errorf("打包导出文件失败: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, "", 0, fmt.Errorf("打包导出文件失败: %w", err)
+	}
+
+	filename := req.TaskID + "_stratified_" + dto.FilenameTimestamp(time.Now(), loc) + ".zip"
+	return buf.Bytes(), filename, len(selected), nil
+}
+
+// stratifiedSample 分层抽样
+// 先按目标层顺序为每层抽取 round(占比*总数) 条，剩余名额由不属于任何目标层的数据补足；
+// 任一上限层达到 floor(上限占比*总数) 后，属于该层的数据不再入选。数据按种子打乱，结果可复现
+func stratifiedSample(dataList []models.GeneratedData, req *dto.StratifiedExportRequest) ([]models.GeneratedData, *dto.ExportManifest) {
+	shuffled := make([]models.GeneratedData, len(dataList))
+	copy(shuffled, dataList)
+	rng := rand.New(rand.NewSource(req.Seed))
+	rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	manifest := &dto.ExportManifest{
+		TaskID:         req.TaskID,
+		Format:         req.Format,
+		Seed:           req.Seed,
+		RequestedTotal: req.Total,
+		Available:      len(shuffled),
+		Targets:        make([]dto.StratumResult, len(req.Targets)),
+		Caps:           make([]dto.StratumResult, len(req.Caps)),
+	}
+	if manifest.Format == "" {
+		manifest.Format = "jsonl"
+	}
+
+	// 统计各层可用数据量
+	inTarget := make([]bool, len(shuffled))
+	restAvailable := 0
+	for i := range shuffled {
+		for j, target := range req.Targets {
+			if matchesStratum(&shuffled[i], target) {
+				manifest.Targets[j].Available++
+				inTarget[i] = true
+			}
+		}
+		for j, capStratum := range req.Caps {
+			if matchesStratum(&shuffled[i], capStratum) {
+				manifest.Caps[j].Available++
+			}
+		}
+		if !inTarget[i] {
+			restAvailable++
+		}
+	}
+
+	sum := 0.0
+	for _, target := range req.Targets {
+		sum += target.Proportion
+	}
+	restProportion := math.Max(0, 1-sum)
+
+	// 未指定总数时取满足目标比例的最大条数
+	total := req.Total
+	if total == 0 {
+		total = len(shuffled)
+		for j, target := range req.Targets {
+			total = minInt(total, int(float64(manifest.Targets[j].Available)/target.Proportion))
+		}
+		if restProportion > proportionEpsilon {
+			total = minInt(total, int(float64(restAvailable)/restProportion))
+		}
+	}
+
+	capLimits := make([]int, len(req.Caps))
+	capCounts := make([]int, len(req.Caps))
+	for j, capStratum := range req.Caps {
+		capLimits[j] = int(math.Floor(capStratum.Proportion*float64(total) + proportionEpsilon))
+	}
+
+	chosen := make([]bool, len(shuffled))
+	result := make([]models.GeneratedData, 0, total)
+	// pick 选入一条数据，会超出任一上限层时跳过
+	pick := func(i int) bool {
+		for j, capStratum := range req.Caps {
+			if capCounts[j] >= capLimits[j] && matchesStratum(&shuffled[i], capStratum) {
+				return false
+			}
+		}
+		for j, capStratum := range req.Caps {
+			if matchesStratum(&shuffled[i], capStratum) {
+				capCounts[j]++
+			}
+		}
+		chosen[i] = true
+		result = append(result, shuffled[i])
+		return true
+	}
+
+	quotaSum := 0
+	for _, target := range req.Targets {
+		quota := minInt(int(math.Round(target.Proportion*float64(total))), total-quotaSum)
+		quotaSum += quota
+		count := 0
+		for i := range shuffled {
+			if count >= quota {
+				break
+			}
+			if !chosen[i] && matchesStratum(&shuffled[i], target) && pick(i) {
+				count++
+			}
+		}
+		if count < quota {
+			manifest.Warnings = append(manifest.Warnings,
+				fmt.Sprintf("%s=%s 可用数据不足：目标 %d 条，实际 %d 条", target.Dimension, target.Value, quota, count))
+		}
+	}
+
+	restQuota := total - quotaSum
+	restCount := 0
+	for i := range shuffled {
+		if restCount >= restQuota {
+			break
+		}
+		if !chosen[i] && !inTarget[i] && pick(i) {
+			restCount++
+		}
+	}
+	if restCount < restQuota {
+		manifest.Warnings = append(manifest.Warnings,
+			fmt.Sprintf("不属于任何目标层的数据不足：目标 %d 条，实际 %d 条", restQuota, restCount))
+	}
+
+	// 记录实际分布
+	manifest.Exported = len(result)
+	manifest.Distribution = map[string]map[string]int{
+		StratumDimensionTag:      {},
+		StratumDimensionTaskType: {},
+		StratumDimensionScore:    {},
+	}
+	for i := range result {
+		for _, tag := range result[i].TagList() {
+			manifest.Distribution[StratumDimensionTag][tag]++
+		}
+		manifest.Distribution[StratumDimensionTaskType][result[i].TaskType]++
+		manifest.Distribution[StratumDimensionScore][scoreBucket(&result[i])]++
+	}
+	fillStratumResults(manifest.Targets, req.Targets, result)
+	fillStratumResults(manifest.Caps, req.Caps, result)
+
+	return result, manifest
+}
+
+// fillStratumResults 填充各层的请求占比与导出结果中的实际占比
+func fillStratumResults(results []dto.StratumResult, strata []dto.StratumTarget, selected []models.GeneratedData) {
+	for j, stratum := range strata {
+		results[j].Dimension = stratum.Dimension
+		results[j].Value = stratum.Value
+		results[j].RequestedProportion = stratum.Proportion
+		for i := range selected {
+			if matchesStratum(&selected[i], stratum) {
+				results[j].Selected++
+			}
+		}
+		if len(selected) > 0 {
+			results[j].AchievedProportion = float64(results[j].Selected) / float64(len(selected))
+		}
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
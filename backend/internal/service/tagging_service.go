@@ -0,0 +1,398 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+)
+
+// 打标限制
+const (
+	taggingBatchSize        = 500
+	classifierMaxInputRunes = 4000 // 发送给裁判模型的数据内容上限
+)
+
+// TaggingService 生成数据自动打标服务
+// 按用户配置的规则（关键字/正则/裁判模型分类）为任务数据写入 分类:标签，用于列表过滤和按标签配额导出
+type TaggingService struct {
+	ruleRepo          *repository.TagRuleRepository
+	generatedDataRepo *repository.GeneratedDataRepository
+	taskRepo          *repository.TaskRepository
+	modelRepo         *repository.ModelConfigRepository
+	modelService      *ModelService
+	jobPool           *JobPool
+}
+
+// NewTaggingService 创建打标服务
+func NewTaggingService(
+	ruleRepo *repository.TagRuleRepository,
+	generatedDataRepo *repository.GeneratedDataRepository,
+	taskRepo *repository.TaskRepository,
+	modelRepo *repository.ModelConfigRepository,
+	modelService *ModelService,
+	jobPool *JobPool,
+) *TaggingService {
+	return &TaggingService{
+		ruleRepo:          ruleRepo,
+		generatedDataRepo: generatedDataRepo,
+		taskRepo:          taskRepo,
+		modelRepo:         modelRepo,
+		modelService:      modelService,
+		jobPool:           jobPool,
+	}
+}
+
+// CreateRule 创建打标规则
+func (s *TaggingService) CreateRule(userID uint, req *dto.CreateTagRuleRequest) (*dto.TagRuleResponse, error) {
+	rule := &models.TagRule{
+		UserID:    userID,
+		Name:      req.Name,
+		Category:  strings.TrimSpace(req.Category),
+		Tag:       strings.TrimSpace(req.Tag),
+		MatchType: req.MatchType,
+		Pattern:   req.Pattern,
+		ModelID:   req.ModelID,
+		Priority:  req.Priority,
+		IsActive:  req.IsActive == nil || *req.IsActive,
+	}
+	if err := s.validateRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Create(rule); err != nil {
+		return nil, fmt.Errorf("创建打标规则失败: %w", err)
+	}
+	return toTagRuleResponse(rule), nil
+}
+
+// ListRules 获取用户的打标规则
+func (s *TaggingService) ListRules(userID uint) ([]*dto.TagRuleResponse, error) {
+	rules, err := s.ruleRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.TagRuleResponse, len(rules))
+	for i := range rules {
+		result[i] = toTagRuleResponse(&rules[i])
+	}
+	return result, nil
+}
+
+// UpdateRule 更新打标规则
+func (s *TaggingService) UpdateRule(id uint, userID uint, req *dto.UpdateTagRuleRequest) (*dto.TagRuleResponse, error) {
+	rule, err := s.ruleRepo.GetByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("打标规则不存在或无权访问")
+	}
+
+	if req.Name != nil {
+		rule.Name = *req.Name
+	}
+	if req.Category != nil {
+		rule.Category = strings.TrimSpace(*req.Category)
+	}
+	if req.Tag != nil {
+		rule.Tag = strings.TrimSpace(*req.Tag)
+	}
+	if req.MatchType != nil {
+		rule.MatchType = *req.MatchType
+	}
+	if req.Pattern != nil {
+		rule.Pattern = *req.Pattern
+	}
+	if req.ModelID != nil {
+		rule.ModelID = req.ModelID
+	}
+	if req.Priority != nil {
+		rule.Priority = *req.Priority
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+	if err := s.validateRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Update(rule); err != nil {
+		return nil, fmt.Errorf("更新打标规则失败: %w", err)
+	}
+	return toTagRuleResponse(rule), nil
+}
+
+// DeleteRule 删除打标规则（已写入数据的标签保留，重新打标后更新）
+func (s *TaggingService) DeleteRule(id uint, userID uint) error {
+	if _, err := s.ruleRepo.GetByIDAndUserID(id, userID); err != nil {
+		return fmt.Errorf("打标规则不存在或无权访问")
+	}
+	return s.ruleRepo.Delete(id)
+}
+
+// validateRule 校验规则内容
+func (s *TaggingService) validateRule(rule *models.TagRule) error {
+	if rule.Category == "" || strings.ContainsAny(rule.Category, ",:") {
+		return fmt.Errorf("分类不能为空，且不能包含逗号或冒号")
+	}
+	if strings.Contains(rule.Tag, ",") {
+		return fmt.Errorf("标签不能包含逗号")
+	}
+
+	switch rule.MatchType {
+	case models.TagMatchKeyword:
+		if rule.Tag == "" {
+			return fmt.Errorf("keyword 规则必须指定 tag")
+		}
+		if len(rule.PatternList()) == 0 {
+			return fmt.Errorf("keyword 规则至少需要一个关键字")
+		}
+	case models.TagMatchRegex:
+		if rule.Tag == "" {
+			return fmt.Errorf("regex 规则必须指定 tag")
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("无效的正则表达式: %w", err)
+		}
+	case models.TagMatchClassifier:
+		labels := rule.PatternList()
+		if len(labels) < 2 {
+			return fmt.Errorf("classifier 规则至少需要两个候选标签")
+		}
+		for _, label := range labels {
+			if strings.Contains(label, ",") {
+				return fmt.Errorf("候选标签不能包含逗号")
+			}
+		}
+		if rule.ModelID == nil {
+			return fmt.Errorf("classifier 规则必须指定 model_id")
+		}
+		if _, err := s.modelRepo.GetByID(*rule.ModelID); err != nil {
+			return fmt.Errorf("模型配置不存在")
+		}
+	default:
+		return fmt.Errorf("不支持的匹配方式: %s", rule.MatchType)
+	}
+	return nil
+}
+
+// TagTaskForUser 校验任务归属后重新打标
+func (s *TaggingService) TagTaskForUser(taskID string, userID uint) (*dto.TagTaskResponse, error) {
+	task, err := s.taskRepo.GetEditableByTaskID(taskID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("任务不存在或无权访问")
+	}
+	if task.Status == models.TaskStatusRunning {
+		return nil, fmt.Errorf("任务仍在运行，请在任务结束后打标")
+	}
+	return s.TagTask(taskID, userID)
+}
+
+// TagTask 使用用户启用的规则为任务数据打标；重复执行会覆盖上一次的结果
+// 用户没有启用的规则时返回 nil
+func (s *TaggingService) TagTask(taskID string, userID uint) (*dto.TagTaskResponse, error) {
+	rules, err := s.ruleRepo.ListActiveByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("获取打标规则失败: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	var result *dto.TagTaskResponse
+	err = s.jobPool.Run(JobKindTagging, func() error {
+		var err error
+		result, err = s.tagTask(taskID, rules)
+		return err
+	})
+	return result, err
+}
+
+// GetTagCounts 获取任务数据的标签统计（用于按配额导出前查看分布）
+func (s *TaggingService) GetTagCounts(taskID string, userID uint) (map[string]int64, error) {
+	if _, err := s.taskRepo.GetVisibleByTaskID(taskID, userID); err != nil {
+		return nil, fmt.Errorf("任务不存在或无权访问")
+	}
+	return s.generatedDataRepo.CountTags(taskID)
+}
+
+// compiledTagRule 预编译的规则
+type compiledTagRule struct {
+	rule     *models.TagRule
+	keywords []string
+	regex    *regexp.Regexp
+	labels   []string
+	model    *models.ModelConfig
+}
+
+// tagTask 打标的具体实现
+func (s *TaggingService) tagTask(taskID string, rules []models.TagRule) (*dto.TagTaskResponse, error) {
+	compiled := make([]compiledTagRule, 0, len(rules))
+	for i := range rules {
+		c := compiledTagRule{rule: &rules[i]}
+		switch rules[i].MatchType {
+		case models.TagMatchKeyword:
+			for _, keyword := range rules[i].PatternList() {
+				c.keywords = append(c.keywords, strings.ToLower(keyword))
+			}
+		case models.TagMatchRegex:
+			re, err := regexp.Compile(rules[i].Pattern)
+			if err != nil {
+				log.Printf("[Tagging] 规则 %d 正则无效，已跳过: %v", rules[i].ID, err)
+				continue
+			}
+			c.regex = re
+		case models.TagMatchClassifier:
+			if rules[i].ModelID == nil {
+				continue
+			}
+			model, err := s.modelRepo.GetByID(*rules[i].ModelID)
+			if err != nil {
+				log.Printf("[Tagging] 规则 %d 的模型配置不存在，已跳过", rules[i].ID)
+				continue
+			}
+			c.labels = rules[i].PatternList()
+			c.model = model
+		default:
+			continue
+		}
+		compiled = append(compiled, c)
+	}
+
+	result := &dto.TagTaskResponse{TaskID: taskID}
+	tags := make(map[uint]string)
+
+	err := s.generatedDataRepo.ScanForDedup(taskID, taggingBatchSize, func(batch []models.GeneratedData) error {
+		for _, data := range batch {
+			result.Checked++
+
+			var content interface{}
+			if err := json.Unmarshal([]byte(data.DataContent), &content); err != nil {
+				continue
+			}
+			text := strings.Join(collectTextValues(content, nil), "\n")
+
+			if dataTags := s.matchRules(compiled, text); len(dataTags) > 0 {
+				tags[data.ID] = strings.Join(dataTags, ",")
+				result.Tagged++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取生成数据失败: %w", err)
+	}
+
+	if err := s.generatedDataRepo.UpdateTags(taskID, tags); err != nil {
+		return nil, fmt.Errorf("保存标签失败: %w", err)
+	}
+
+	result.TagCounts, err = s.generatedDataRepo.CountTags(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("统计标签失败: %w", err)
+	}
+
+	log.Printf("[Tagging] 任务 %s 打标完成: 检查 %d 条, 打标 %d 条", taskID, result.Checked, result.Tagged)
+	return result, nil
+}
+
+// matchRules 计算一条数据命中的标签（去重，保持规则优先级顺序）
+func (s *TaggingService) matchRules(rules []compiledTagRule, text string) []string {
+	lower := strings.ToLower(text)
+	seen := make(map[string]bool)
+	var tags []string
+
+	add := func(category, tag string) {
+		full := category + ":" + tag
+		if !seen[full] {
+			seen[full] = true
+			tags = append(tags, full)
+		}
+	}
+
+	for _, c := range rules {
+		switch {
+		case c.keywords != nil:
+			for _, keyword := range c.keywords {
+				if strings.Contains(lower, keyword) {
+					add(c.rule.Category, c.rule.Tag)
+					break
+				}
+			}
+		case c.regex != nil:
+			if c.regex.MatchString(text) {
+				add(c.rule.Category, c.rule.Tag)
+			}
+		case c.model != nil:
+			if label, err := s.classify(c, text); err != nil {
+				log.Printf("[Tagging] 规则 %d 分类失败: %v", c.rule.ID, err)
+			} else if label != "" {
+				add(c.rule.Category, label)
+			}
+		}
+	}
+	return tags
+}
+
+// classify 调用裁判模型从候选标签中选择一个，输出不在候选中时返回空
+func (s *TaggingService) classify(c compiledTagRule, text string) (string, error) {
+	if runes := []rune(text); len(runes) > classifierMaxInputRunes {
+		text = string(runes[:classifierMaxInputRunes])
+	}
+
+	prompt := fmt.Sprintf("请判断下面数据的%s，从以下候选标签中选择最符合的一个，只输出标签本身，不要输出其他内容。\n候选标签：%s\n\n数据：\n%s",
+		c.rule.Category, strings.Join(c.labels, "、"), text)
+
+	resp, err := s.modelService.CallModel(&dto.ModelCallProxyRequest{
+		APIUrl:      c.model.APIURL,
+		APIKey:      c.model.APIKey,
+		Model:       c.model.ModelPath,
+		Messages:    []dto.Message{{Role: "user", Content: prompt}},
+		Temperature: 0,
+		TopP:        c.model.TopP,
+		MaxTokens:   32,
+		Timeout:     c.model.Timeout,
+		IsVLLM:      c.model.IsVLLM,
+	})
+	if err != nil {
+		return "", err
+	}
+	if !resp.Success {
+		return "", fmt.Errorf("%s", resp.Error)
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(resp.Content))
+	for _, label := range c.labels {
+		if strings.ToLower(label) == answer {
+			return label, nil
+		}
+	}
+	// 模型输出带有多余内容时，取第一个出现的候选标签
+	for _, label := range c.labels {
+		if strings.Contains(answer, strings.ToLower(label)) {
+			return label, nil
+		}
+	}
+	return "", nil
+}
+
+// toTagRuleResponse 转换打标规则响应
+func toTagRuleResponse(rule *models.TagRule) *dto.TagRuleResponse {
+	return &dto.TagRuleResponse{
+		ID:        rule.ID,
+		Name:      rule.Name,
+		Category:  rule.Category,
+		Tag:       rule.Tag,
+		MatchType: rule.MatchType,
+		Pattern:   rule.Pattern,
+		ModelID:   rule.ModelID,
+		Priority:  rule.Priority,
+		IsActive:  rule.IsActive,
+		CreatedAt: dto.FormatTime(rule.CreatedAt),
+		UpdatedAt: dto.FormatTime(rule.UpdatedAt),
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+	"path"
+	"strconv"
+
+	"gen-go/internal/models"
+	"gen-go/internal/utils"
+)
+
+// checkpointFlushSize 缓存的生成数据达到该数量时写入数据库
+const checkpointFlushSize = 200
+
+// idempotencyField 生成数据中存放幂等键组成部分的字段（与 develop/single_gen.py 的 IDEMPOTENCY_FIELD 一致）
+const idempotencyField = "_idempotency"
+
+// bufferGeneratedItem 缓存工作进程输出的一条生成数据（{"type": "item", "data": {...}}），达到批量大小时写入数据库
+// 工作进程自己也会按批次保存数据，两边写入通过幂等键合并，因此没有幂等键组成部分（_idempotency）的数据不缓存
+// 幂等键组成部分写入独立的列，不进入 data_content
+// 标准输出读取协程和 gRPC SubmitResults 都会调用，返回数据是否被缓存
+func (tm *TaskManager) bufferGeneratedItem(taskCtx *TaskContext, raw interface{}) bool {
+	item, ok := raw.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	fields, _ := item[idempotencyField].(map[string]interface{})
+	seedHash, _ := fields["seed_hash"].(string)
+	seedIndex, hasSeed := fields["seed_index"].(float64)
+	variantIndex, hasVariant := fields["variant_index"].(float64)
+	if seedHash == "" || !hasSeed || !hasVariant {
+		return false
+	}
+
+	content := make(map[string]interface{}, len(item))
+	for k, v := range item {
+		if k != idempotencyField {
+			content[k] = v
+		}
+	}
+	contentJSON, err := json.Marshal(content)
+	if err != nil {
+		return false
+	}
+	meta, _ := item["meta"].(map[string]interface{})
+
+	generationModel, _ := meta["generation_model"].(string)
+	if generationModel == "" {
+		generationModel = path.Base(taskCtx.ModelPath)
+	}
+	taskType, _ := taskCtx.Params["task_type"].(string)
+	seed, variant := int(seedIndex), int(variantIndex)
+	key := utils.GenerationIdempotencyKey(taskCtx.TaskID, seedHash, seed, variant)
+
+	data := models.GeneratedData{
+		TaskID:          taskCtx.TaskID,
+		UserID:          taskCtx.UserID,
+		DataContent:     string(contentJSON),
+		GenerationModel: generationModel,
+		TaskType:        taskType,
+		IdempotencyKey:  &key,
+		SeedHash:        seedHash,
+		SeedIndex:       &seed,
+		VariantIndex:    &variant,
+	}
+	if score, ok := meta["model_score"].(float64); ok {
+		data.ModelScore = &score
+	}
+	if score, ok := meta["rule_score"].(float64); ok {
+		ruleScore := int(score)
+		data.RuleScore = &ruleScore
+	}
+	if retry, ok := meta["retry_count"].(float64); ok {
+		data.RetryCount = int(retry)
+	}
+
+	taskCtx.pendingLock.Lock()
+	defer taskCtx.pendingLock.Unlock()
+	taskCtx.pendingItems = append(taskCtx.pendingItems, data)
+	if len(taskCtx.pendingItems) >= checkpointFlushSize {
+		tm.flushPendingItems(taskCtx)
+	}
+	return true
+}
+
+// flushGeneratedItems 将缓存的生成数据写入数据库，返回写入条数
+func (tm *TaskManager) flushGeneratedItems(taskCtx *TaskContext) int {
+	taskCtx.pendingLock.Lock()
+	defer taskCtx.pendingLock.Unlock()
+	return tm.flushPendingItems(taskCtx)
+}
+
+// flushPendingItems 写入缓存的生成数据（调用方持有 pendingLock）
+func (tm *TaskManager) flushPendingItems(taskCtx *TaskContext) int {
+	if len(taskCtx.pendingItems) == 0 || tm.generatedDataRepo == nil {
+		return 0
+	}
+
+	count := len(taskCtx.pendingItems)
+	if err := tm.generatedDataRepo.CreateBatch(taskCtx.pendingItems); err != nil {
+		log.Printf("[Checkpoint] 任务 %s 保存 %d 条缓存数据失败: %v", taskCtx.TaskID, count, err)
+		return 0
+	}
+	taskCtx.pendingItems = nil
+	return count
+}
+
+// saveCheckpoint 任务停止或失败时写入缓存数据，并在 Task.Result 中记录 last_completed_round
+// 返回失败任务的最终状态：已有生成数据时为 partial，否则保持 error
+func (tm *TaskManager) saveCheckpoint(taskCtx *TaskContext, status models.TaskStatus, progress map[string]string) models.TaskStatus {
+	flushed := tm.flushGeneratedItems(taskCtx)
+
+	result := map[string]interface{}{}
+	if round, ok := lastCompletedRound(progress); ok {
+		result["last_completed_round"] = round
+	}
+
+	if status == models.TaskStatusError && tm.generatedDataRepo != nil {
+		counts, err := tm.generatedDataRepo.CountByTaskIDs([]string{taskCtx.TaskID})
+		if err != nil {
+			log.Printf("[Checkpoint] 统计任务 %s 数据失败: %v", taskCtx.TaskID, err)
+		} else if count := counts[taskCtx.TaskID].DataCount; count > 0 {
+			status = models.TaskStatusPartial
+			result["partial_data_count"] = count
+		}
+	}
+
+	if len(result) > 0 {
+		if err := tm.taskRepo.MergeResult(taskCtx.TaskID, result); err != nil {
+			log.Printf("[Checkpoint] 记录任务 %s 检查点失败: %v", taskCtx.TaskID, err)
+		}
+	}
+
+	log.Printf("[Checkpoint] 任务 %s 检查点: 状态 %s, 写入缓存数据 %d 条, %v", taskCtx.TaskID, status, flushed, result)
+	return status
+}
+
+// lastCompletedRound 从 Redis 进度中解析已完成的轮次数
+// 工作进程在每轮开始和结束时写入 current_round（JSON 编码），两种情况下该值都等于已完成的轮次数
+func lastCompletedRound(progress map[string]string) (int, bool) {
+	raw, ok := progress["current_round"]
+	if !ok {
+		return 0, false
+	}
+	round, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, false
+	}
+	return round, true
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"gen-go/internal/config"
+	"gen-go/internal/models"
+)
+
+// maxEnsembleModels 单个任务最多使用的模型数
+const maxEnsembleModels = 8
+
+// ensembleEndpoint 传给工作进程的单个模型服务地址配置（main.py --ensemble）
+// APIKey 始终输出（模型未配置密钥时为空），避免工作进程回退到主模型的 --api-key
+type ensembleEndpoint struct {
+	APIBase       string  `json:"api_base"`
+	Model         string  `json:"model"`
+	APIKey        string  `json:"api_key"`
+	IsVLLM        bool    `json:"is_vllm"`
+	TopP          float64 `json:"top_p"`
+	MaxTokens     int     `json:"max_tokens"`
+	Timeout       int     `json:"timeout"`
+	MaxConcurrent int     `json:"max_concurrent"`
+}
+
+// resolveEnsembleModels 加载多模型集成使用的模型配置（去重，保持请求顺序）
+func (tm *TaskManager) resolveEnsembleModels(modelIDs []uint) ([]*models.ModelConfig, error) {
+	seen := make(map[uint]bool, len(modelIDs))
+	result := make([]*models.ModelConfig, 0, len(modelIDs))
+	for _, id := range modelIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		model, err := tm.modelRepo.GetByIDAndActive(id)
+		if err != nil {
+			return nil, fmt.Errorf("获取模型配置 %d 失败: %w", id, err)
+		}
+		result = append(result, model)
+	}
+
+	if len(result) > maxEnsembleModels {
+		return nil, fmt.Errorf("model_ids 最多指定 %d 个模型", maxEnsembleModels)
+	}
+	return result, nil
+}
+
+// acquireEnsembleTokens 按各模型的最大并发数依次获取限流令牌，返回释放函数
+// 任一模型获取失败时释放已获取的令牌
+func (tm *TaskManager) acquireEnsembleTokens(ctx context.Context, userID uint, ensemble []*models.ModelConfig) (func(), error) {
+	var releases []func()
+	release := func() {
+		for _, r := range releases {
+			r()
+		}
+	}
+
+	for _, model := range ensemble {
+		key := fmt.Sprintf("model_limit:%s", model.ModelPath)
+		maxConcurrent := model.MaxConcurrent
+		if maxConcurrent <= 0 {
+			maxConcurrent = 5
+		}
+
+		log.Printf("[runTask] 模型限流: %s, 最大并发: %d", key, maxConcurrent)
+		r, err := tm.acquireModelToken(ctx, key, maxConcurrent, userID)
+		if err != nil {
+			release()
+			return nil, fmt.Errorf("获取模型 %s 令牌失败: %v", model.Name, err)
+		}
+		releases = append(releases, r)
+	}
+	return release, nil
+}
+
+// buildEnsembleArg 生成 --ensemble 参数（JSON 数组）
+func buildEnsembleArg(ensemble []*models.ModelConfig) string {
+	raw, _ := json.Marshal(ensembleEndpoints(ensemble))
+	return string(raw)
+}
+
+// ensembleEndpoints 展开各模型的服务地址（EndpointList），每个地址一项，使用该模型自己的密钥；
+// 模型的最大并发数按地址平分（每个地址至少 1），工作进程按并发数比例分配样本
+func ensembleEndpoints(ensemble []*models.ModelConfig) []ensembleEndpoint {
+	var endpoints []ensembleEndpoint
+	for _, model := range ensemble {
+		apiKey := ""
+		if model.APIKey != "sk-xxxxx" {
+			apiKey = config.ResolveSecret(model.APIKey)
+		}
+		maxConcurrent := model.MaxConcurrent
+		if maxConcurrent <= 0 {
+			maxConcurrent = 5
+		}
+
+		urls := model.EndpointList()
+		for i, url := range urls {
+			share := maxConcurrent / len(urls)
+			if i < maxConcurrent%len(urls) {
+				share++
+			}
+			endpoints = append(endpoints, ensembleEndpoint{
+				APIBase:       url,
+				Model:         model.ModelPath,
+				APIKey:        apiKey,
+				IsVLLM:        model.IsVLLM,
+				TopP:          model.TopP,
+				MaxTokens:     model.MaxTokens,
+				Timeout:       model.Timeout,
+				MaxConcurrent: max(share, 1),
+			})
+		}
+	}
+	return endpoints
+}
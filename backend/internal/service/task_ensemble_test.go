@@ -0,0 +1,25 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"gen-go/internal/models"
+)
+
+func TestEnsembleEndpoints(t *testing.T) {
+	ensemble := []*models.ModelConfig{
+		{ModelPath: "primary", APIURL: "http://a:8000/v1", Endpoints: "http://b:8000/v1, http://a:8000/v1", APIKey: "key-primary", MaxConcurrent: 5},
+		{ModelPath: "secondary", APIURL: "https://provider/v1", APIKey: "sk-xxxxx", MaxConcurrent: 0},
+	}
+
+	got := ensembleEndpoints(ensemble)
+	want := []ensembleEndpoint{
+		{APIBase: "http://a:8000/v1", Model: "primary", APIKey: "key-primary", MaxConcurrent: 3},
+		{APIBase: "http://b:8000/v1", Model: "primary", APIKey: "key-primary", MaxConcurrent: 2},
+		{APIBase: "https://provider/v1", Model: "secondary", APIKey: "", MaxConcurrent: 5},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ensembleEndpoints() = %+v, want %+v", got, want)
+	}
+}
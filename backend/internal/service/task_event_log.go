@@ -0,0 +1,225 @@
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+)
+
+const (
+	// taskLogDefaultLimit 日志分页未指定 limit 时的每页条数
+	taskLogDefaultLimit = 500
+	// taskLogMaxLimit 日志分页每页最大条数
+	taskLogMaxLimit = 5000
+)
+
+// taskEventLog 任务的完整事件日志，每个事件按 JSON 行追加写入日志文件
+// 内存中的 EventHistory 只保留采样后的输出行，完整内容以该文件为准
+type taskEventLog struct {
+	lock   sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// taskEventLogPath 任务事件日志的文件路径（任务ID可能包含文件名中的任意字符，转义后作为文件名）
+func taskEventLogPath(dir, taskID string) string {
+	return filepath.Join(dir, url.PathEscape(taskID)+".events.jsonl")
+}
+
+// openTaskEventLog 创建（或追加到）任务的事件日志文件
+func openTaskEventLog(dir, taskID string) (*taskEventLog, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建任务日志目录失败: %w", err)
+	}
+	file, err := os.OpenFile(taskEventLogPath(dir, taskID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("创建任务日志文件失败: %w", err)
+	}
+	return &taskEventLog{file: file, writer: bufio.NewWriter(file)}, nil
+}
+
+// Append 写入一个事件（日志已关闭时忽略）
+func (l *taskEventLog) Append(event *dto.ProgressEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.file == nil {
+		return
+	}
+	l.writer.Write(data)
+	l.writer.WriteByte('\n')
+}
+
+// Flush 将缓冲的事件写入文件（分页读取前调用）
+func (l *taskEventLog) Flush() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.file == nil {
+		return
+	}
+	if err := l.writer.Flush(); err != nil {
+		log.Printf("[TaskLog] 写入任务日志失败: %v", err)
+	}
+}
+
+// Close 写入剩余事件并关闭文件
+func (l *taskEventLog) Close() {
+	if l == nil {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.file == nil {
+		return
+	}
+	if err := l.writer.Flush(); err != nil {
+		log.Printf("[TaskLog] 写入任务日志失败: %v", err)
+	}
+	l.file.Close()
+	l.file = nil
+}
+
+// keepInHistory 判断事件是否保留在内存历史中并推送给订阅者
+// 写入了完整日志时普通输出行按 task_log.output_sample_every 采样，其余事件全部保留
+func (tc *TaskContext) keepInHistory(event *dto.ProgressEvent) bool {
+	if event.Type != "output" || tc.eventLog == nil || tc.outputSampleEvery <= 1 {
+		return true
+	}
+	n := atomic.AddInt64(&tc.outputLines, 1)
+	return (n-1)%int64(tc.outputSampleEvery) == 0
+}
+
+// readTaskEventLog 读取事件日志中 [offset, offset+limit) 范围内的事件，limit 为 0 时读取到末尾
+// 返回事件和日志中的事件总数
+func readTaskEventLog(path string, offset, limit int) ([]*dto.ProgressEvent, int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	events := []*dto.ProgressEvent{}
+	total := 0
+	for scanner.Scan() {
+		index := total
+		total++
+		if index < offset || (limit > 0 && index >= offset+limit) {
+			continue
+		}
+		var event dto.ProgressEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		events = append(events, &event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("读取任务日志失败: %w", err)
+	}
+	return events, total, nil
+}
+
+// openEventLog 为任务打开事件日志，失败时只记录日志（任务照常运行，输出行不再采样）
+func (tm *TaskManager) openEventLog(taskCtx *TaskContext) {
+	eventLog, err := openTaskEventLog(tm.cfg.Current().TaskLogDir(), taskCtx.TaskID)
+	if err != nil {
+		log.Printf("[TaskLog] 任务 %s: %v", taskCtx.TaskID, err)
+		return
+	}
+	taskCtx.eventLog = eventLog
+	taskCtx.outputSampleEvery = tm.cfg.Current().TaskLog.OutputSampleEvery
+}
+
+// accessibleTaskLog 校验访问权限，返回内存中的任务（任务已不在内存中时为 nil）
+func (tm *TaskManager) accessibleTaskLog(taskID string, userID uint) (*TaskContext, error) {
+	taskCtx, exists := tm.GetTask(taskID)
+	if exists {
+		if !tm.canAccessTask(taskCtx, userID, models.PermissionView) {
+			return nil, fmt.Errorf("无权访问此任务")
+		}
+		return taskCtx, nil
+	}
+	if _, err := tm.taskRepo.GetAccessibleByTaskID(taskID, userID, models.PermissionView); err != nil {
+		return nil, fmt.Errorf("任务不存在")
+	}
+	return nil, nil
+}
+
+// readTaskLog 读取任务的完整事件（优先读取日志文件，没有日志文件时使用内存历史）
+func (tm *TaskManager) readTaskLog(taskID string, taskCtx *TaskContext, offset, limit int) ([]*dto.ProgressEvent, int, error) {
+	if taskCtx != nil && taskCtx.eventLog != nil {
+		taskCtx.eventLog.Flush()
+	}
+
+	events, total, err := readTaskEventLog(taskEventLogPath(tm.cfg.Current().TaskLogDir(), taskID), offset, limit)
+	if err == nil {
+		return events, total, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, 0, err
+	}
+	if taskCtx == nil {
+		return nil, 0, fmt.Errorf("任务日志不存在")
+	}
+
+	history := taskCtx.GetEventHistory()
+	total = len(history)
+	if offset >= total {
+		return []*dto.ProgressEvent{}, total, nil
+	}
+	end := total
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return history[offset:end], total, nil
+}
+
+// GetTaskLogs 分页获取任务的完整事件日志（包括未推送的输出行）
+func (tm *TaskManager) GetTaskLogs(taskID string, userID uint, offset, limit int) (*dto.TaskLogPage, error) {
+	if limit <= 0 {
+		limit = taskLogDefaultLimit
+	} else if limit > taskLogMaxLimit {
+		limit = taskLogMaxLimit
+	}
+
+	taskCtx, err := tm.accessibleTaskLog(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	events, total, err := tm.readTaskLog(taskID, taskCtx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TaskLogPage{
+		TaskID:     taskID,
+		Events:     events,
+		Offset:     offset,
+		NextOffset: offset + len(events),
+		Total:      total,
+		Running:    taskCtx != nil && !taskCtx.Finished(),
+	}, nil
+}
+
+// removeTaskEventLog 删除任务的事件日志文件
+func (tm *TaskManager) removeTaskEventLog(taskID string) {
+	if err := os.Remove(taskEventLogPath(tm.cfg.Current().TaskLogDir(), taskID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("[TaskLog] 删除任务 %s 的日志失败: %v", taskID, err)
+	}
+}
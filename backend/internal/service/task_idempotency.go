@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gen-go/internal/dto"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ErrIdempotencyKeyInUse 相同幂等键的启动请求仍在处理中
+var ErrIdempotencyKeyInUse = errors.New("相同幂等键的启动请求正在处理中，请稍后重试")
+
+// startIdempotencyTTL 幂等键的保留时间，超过后相同的键会启动新任务
+const startIdempotencyTTL = 24 * time.Hour
+
+// idempotencyPending 幂等键已被占用、任务尚未创建时保存的值
+const idempotencyPending = "pending"
+
+// idempotencyRedisKey 幂等键在 Redis 中的键（按用户区分，不同用户使用相同的键互不影响）
+func idempotencyRedisKey(userID uint, key string) string {
+	return fmt.Sprintf("start_idempotency:%d:%s", userID, key)
+}
+
+// claimIdempotencyKey 占用启动请求的幂等键；键已对应任务时返回该任务，键正在被其他请求使用时返回 ErrIdempotencyKeyInUse
+// Redis 未启用或不可用时不做去重，返回 (nil, nil)
+func (tm *TaskManager) claimIdempotencyKey(userID uint, key string) (*dto.StartTaskResponse, error) {
+	if tm.redisClient == nil {
+		return nil, nil
+	}
+
+	ctx := context.Background()
+	redisKey := idempotencyRedisKey(userID, key)
+	claimed, err := tm.redisClient.SetNX(ctx, redisKey, idempotencyPending, startIdempotencyTTL).Result()
+	if err != nil {
+		log.Printf("[StartTask] 占用幂等键失败，不做去重: %v", err)
+		return nil, nil
+	}
+	if claimed {
+		return nil, nil
+	}
+
+	taskID, err := tm.redisClient.Get(ctx, redisKey).Result()
+	if err == redis.Nil || taskID == idempotencyPending {
+		return nil, ErrIdempotencyKeyInUse
+	}
+	if err != nil {
+		log.Printf("[StartTask] 读取幂等键失败，不做去重: %v", err)
+		return nil, nil
+	}
+
+	task, err := tm.taskRepo.GetByTaskID(taskID)
+	if err != nil {
+		// 键对应的任务已被删除，重新占用该键启动新任务
+		log.Printf("[StartTask] 幂等键对应的任务 %s 已不存在，重新启动", taskID)
+		tm.redisClient.Set(ctx, redisKey, idempotencyPending, startIdempotencyTTL)
+		return nil, nil
+	}
+
+	log.Printf("[StartTask] 用户 %d 的幂等键已对应任务 %s，返回已有任务", userID, taskID)
+	return &dto.StartTaskResponse{
+		Success:   true,
+		TaskID:    task.TaskID,
+		Status:    string(task.Status),
+		Duplicate: true,
+	}, nil
+}
+
+// settleIdempotencyKey 任务创建成功时将幂等键指向该任务，失败时释放幂等键以便客户端重试
+func (tm *TaskManager) settleIdempotencyKey(userID uint, key string, resp *dto.StartTaskResponse) {
+	if tm.redisClient == nil {
+		return
+	}
+
+	ctx := context.Background()
+	redisKey := idempotencyRedisKey(userID, key)
+	var err error
+	if resp != nil {
+		err = tm.redisClient.Set(ctx, redisKey, resp.TaskID, startIdempotencyTTL).Err()
+	} else {
+		err = tm.redisClient.Del(ctx, redisKey).Err()
+	}
+	if err != nil {
+		log.Printf("[StartTask] 更新幂等键失败: %v", err)
+	}
+}
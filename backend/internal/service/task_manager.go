@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
@@ -15,19 +16,46 @@ import (
 	"gen-go/internal/config"
 	"gen-go/internal/dto"
 	"gen-go/internal/models"
+	"gen-go/internal/plugin"
+	"gen-go/internal/procgroup"
 	"gen-go/internal/repository"
+	"gen-go/internal/tracing"
+	"gen-go/internal/workerrpc"
+	"gen-go/pkg/redis_limiter"
 
 	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // TaskManager 任务管理器
 type TaskManager struct {
-	taskRepo    *repository.TaskRepository
-	userRepo    *repository.UserRepository
-	fileRepo    *repository.DataFileRepository
-	modelRepo   *repository.ModelConfigRepository
-	redisClient *redis.Client
-	cfg         *config.Config
+	taskRepo          *repository.TaskRepository
+	userRepo          *repository.UserRepository
+	fileRepo          *repository.DataFileRepository
+	generatedDataRepo *repository.GeneratedDataRepository
+	modelRepo         *repository.ModelConfigRepository
+	promptRepo        *repository.PromptRepository
+	taskTypeRepo      *repository.TaskTypeRepository
+	fileVersions      *FileVersionService
+	dedupService      *DedupService
+	glossary          *GlossaryService
+	tagging           *TaggingService
+	scoring           *ScoringService
+	judge             *JudgeService
+	safety            *SafetyService
+	embedding         *EmbeddingService
+	webhookService    *WebhookService
+	notifications     *NotificationService
+	settingsRepo      *repository.UserSettingsRepository
+	workerProbe       *WorkerProbe
+	redisClient       *redis.Client
+	rateLimiter       *redis_limiter.RateLimiter
+	fairLimiter       *redis_limiter.FairLimiter
+	cfg               *config.Config
+	// 多实例模式下的任务注册表（未开启时为 nil）
+	registry *TaskRegistry
+	// 工作节点调度器（worker.executor=agent 时不为 nil，任务交给工作节点运行）
+	agents *AgentDispatcher
 
 	// 内存中的任务状态
 	tasks     map[string]*TaskContext
@@ -36,35 +64,70 @@ type TaskManager struct {
 
 // TaskContext 任务上下文
 type TaskContext struct {
-	TaskID           string
-	UserID           uint
-	Status           string
-	Params           map[string]interface{}
-	FileID           uint
-	ModelConfig      *models.ModelConfig
-	ModelPath        string
-	APIServices      []string
-	StartTime        time.Time
-	EndTime          *time.Time
-	ReturnCode       *int
-	CancelFunc       context.CancelFunc
-	Progress         chan *dto.ProgressEvent
-	Finished         bool
-	StoppedWithChars map[string]int64 // 停止时保存的字符数 {"input": xxx, "output": xxx}
+	TaskID         string
+	UserID         uint
+	Params         map[string]interface{}
+	FileID         uint
+	ModelConfig    *models.ModelConfig
+	ModelPath      string
+	APIServices    []string
+	StartTime      time.Time
+	CancelFunc     context.CancelFunc
+	Progress       chan *dto.ProgressEvent
+	EnsembleModels []*models.ModelConfig // 多模型集成使用的模型（为空时只使用 ModelConfig）
+
+	// 运行状态（stateLock 保护，通过 State/Status/Finished 读取，状态转换见 task_state.go）
+	status          models.TaskStatus
+	finished        bool
+	returnCode      *int
+	endTime         *time.Time
+	stoppedProgress map[string]string  // 停止时的 Redis 进度快照（用于记录 last_completed_round）
+	failure         taskFailureReasons // 失败原因（通过 setFailureReason 写入、failureReasons 读取）
+	stopDone        chan struct{}      // 停止流程完成时关闭
+	stateLock       sync.RWMutex
+
+	// 工作进程输出、尚未写入数据库的生成数据（标准输出和 gRPC 提交的数据都写入这里）
+	pendingItems []models.GeneratedData
+	pendingLock  sync.Mutex
+	// 工作进程最近一次输出的时间（UnixNano，原子访问），供看门狗检测卡死
+	lastActivity int64
+	// 根据结构化进度事件累计的轮次和样本进度
+	progressState taskProgressState
+	// 完整事件日志（为 nil 时输出行不采样，全部保留在内存历史中）
+	eventLog          *taskEventLog
+	outputSampleEvery int
+	outputLines       int64 // 已收到的普通输出行数（原子访问），用于采样
+	// 工作进程原始的标准输出和错误输出（供下载排查）
+	outputLog *taskOutputLog
+	// 多实例模式下将进入历史的事件发布到 Redis（未开启时为 nil）
+	publish func(event *dto.ProgressEvent)
 
 	// 用于广播的事件历史和订阅者管理
 	EventHistory     []*dto.ProgressEvent
 	EventHistoryLock sync.RWMutex
+	lastEventID      int64 // 最近分配的事件ID（EventHistoryLock 保护）
 	subscribers      map[chan *dto.ProgressEvent]bool
 	subscribersLock  sync.RWMutex
 }
 
-// AddEvent 添加事件到历史并广播给所有订阅者
+// AddEvent 分配事件ID，添加事件到历史并广播给所有订阅者
+// 事件完整写入任务日志，普通输出行按采样间隔进入历史和广播（未进入历史的事件同样占用ID）
+// 分配ID、写入历史、广播和发布到 Redis 在同一把锁内完成，保证订阅者（包括其他实例上的订阅者）收到的事件ID递增
 func (tc *TaskContext) AddEvent(event *dto.ProgressEvent) {
-	// 添加到历史
 	tc.EventHistoryLock.Lock()
+	defer tc.EventHistoryLock.Unlock()
+
+	tc.lastEventID++
+	event.ID = tc.lastEventID
+	if tc.eventLog != nil {
+		tc.eventLog.Append(event)
+	}
+	if !tc.keepInHistory(event) {
+		return
+	}
+
+	// 添加到历史
 	tc.EventHistory = append(tc.EventHistory, event)
-	tc.EventHistoryLock.Unlock()
 
 	// 广播给所有订阅者
 	tc.subscribersLock.RLock()
@@ -76,6 +139,11 @@ func (tc *TaskContext) AddEvent(event *dto.ProgressEvent) {
 		}
 	}
 	tc.subscribersLock.RUnlock()
+
+	// 发布只是写入注册表的有序队列，在锁内提交保证 Redis 中的序号与事件ID顺序一致
+	if tc.publish != nil {
+		tc.publish(event)
+	}
 }
 
 // Subscribe 订阅事件（返回一个接收事件的通道）
@@ -116,34 +184,123 @@ func NewTaskManager(
 	taskRepo *repository.TaskRepository,
 	userRepo *repository.UserRepository,
 	fileRepo *repository.DataFileRepository,
+	generatedDataRepo *repository.GeneratedDataRepository,
 	modelRepo *repository.ModelConfigRepository,
+	promptRepo *repository.PromptRepository,
+	taskTypeRepo *repository.TaskTypeRepository,
+	fileVersionService *FileVersionService,
+	dedupService *DedupService,
+	glossaryService *GlossaryService,
+	taggingService *TaggingService,
+	scoringService *ScoringService,
+	judgeService *JudgeService,
+	safetyService *SafetyService,
+	embeddingService *EmbeddingService,
+	webhookService *WebhookService,
+	notificationService *NotificationService,
+	settingsRepo *repository.UserSettingsRepository,
 	redisClient *redis.Client,
 	cfg *config.Config,
 ) *TaskManager {
 	return &TaskManager{
-		taskRepo:    taskRepo,
-		userRepo:    userRepo,
-		fileRepo:    fileRepo,
-		modelRepo:   modelRepo,
-		redisClient: redisClient,
-		cfg:         cfg,
-		tasks:       make(map[string]*TaskContext),
+		taskRepo:          taskRepo,
+		userRepo:          userRepo,
+		fileRepo:          fileRepo,
+		generatedDataRepo: generatedDataRepo,
+		modelRepo:         modelRepo,
+		promptRepo:        promptRepo,
+		taskTypeRepo:      taskTypeRepo,
+		fileVersions:      fileVersionService,
+		dedupService:      dedupService,
+		glossary:          glossaryService,
+		tagging:           taggingService,
+		scoring:           scoringService,
+		judge:             judgeService,
+		safety:            safetyService,
+		embedding:         embeddingService,
+		webhookService:    webhookService,
+		notifications:     notificationService,
+		settingsRepo:      settingsRepo,
+		workerProbe:       NewWorkerProbe(cfg),
+		redisClient:       redisClient,
+		rateLimiter:       redis_limiter.NewRateLimiter(redisClient, ModelRateKeyPrefix, cfg.Redis.GetMaxWaitDuration()),
+		fairLimiter:       redis_limiter.NewFairLimiter(redisClient, time.Hour, cfg.Redis.GetMaxWaitDuration()),
+		cfg:               cfg,
+		tasks:             make(map[string]*TaskContext),
 	}
 }
 
 // StartTask 启动任务
+// 携带幂等键时，同一用户使用相同键的重复请求返回第一次创建的任务，而不是再启动一次
 func (tm *TaskManager) StartTask(userID uint, req *dto.StartTaskRequest) (*dto.StartTaskResponse, error) {
+	if req.IdempotencyKey == "" {
+		return tm.startTask(userID, req)
+	}
+
+	existing, err := tm.claimIdempotencyKey(userID, req.IdempotencyKey)
+	if err != nil || existing != nil {
+		return existing, err
+	}
+	resp, err := tm.startTask(userID, req)
+	tm.settleIdempotencyKey(userID, req.IdempotencyKey, resp)
+	return resp, err
+}
+
+// applyUserDefaults 请求未指定模型、批大小或数据轮数时使用用户的个人设置，仍未设置的项使用配置和工作进程的默认值
+func (tm *TaskManager) applyUserDefaults(userID uint, req *dto.StartTaskRequest) {
+	settings, err := tm.settingsRepo.GetByUserID(userID)
+	if err != nil {
+		return
+	}
+
+	if req.ModelID == nil && len(req.ModelIDs) == 0 && len(req.Services) == 0 && req.Model == "" && settings.DefaultModelID != nil {
+		if _, err := tm.modelRepo.GetByIDAndActive(*settings.DefaultModelID); err != nil {
+			log.Printf("[StartTask] 用户 %d 的默认模型 %d 不存在或未启用，忽略", userID, *settings.DefaultModelID)
+		} else {
+			modelID := *settings.DefaultModelID
+			req.ModelID = &modelID
+		}
+	}
+	if req.BatchSize <= 0 && settings.DefaultBatchSize > 0 {
+		req.BatchSize = settings.DefaultBatchSize
+	}
+	if req.DataRounds <= 0 && settings.DefaultDataRounds > 0 {
+		req.DataRounds = settings.DefaultDataRounds
+	}
+}
+
+// startTask 创建任务记录并在后台启动执行
+func (tm *TaskManager) startTask(userID uint, req *dto.StartTaskRequest) (*dto.StartTaskResponse, error) {
 	log.Printf("[StartTask] 用户 %d 请求启动任务", userID)
 	log.Printf("[StartTask] InputFile: %s", req.InputFile)
 	log.Printf("[StartTask] ModelID: %v, TaskType: %s", req.ModelID, req.TaskType)
+	tm.applyUserDefaults(userID, req)
 	log.Printf("[StartTask] BatchSize: %d, MaxConcurrent: %d", req.BatchSize, req.MaxConcurrent)
 
 	// 获取模型配置
 	var modelConfig *models.ModelConfig
 	var modelPath string
 	var apiServices []string
+	var ensembleModels []*models.ModelConfig
 
-	if req.ModelID != nil {
+	if len(req.ModelIDs) > 0 {
+		// 多模型集成：第一个模型作为主模型，样本由工作进程按各模型的最大并发数比例分配
+		if req.ModelID != nil {
+			return nil, fmt.Errorf("model_id 与 model_ids 不能同时指定")
+		}
+		ensemble, err := tm.resolveEnsembleModels(req.ModelIDs)
+		if err != nil {
+			log.Printf("[StartTask] 错误: %v", err)
+			return nil, err
+		}
+		ensembleModels = ensemble
+		modelConfig = ensemble[0]
+		modelPath = ensemble[0].ModelPath
+		for _, model := range ensemble {
+			apiServices = append(apiServices, model.EndpointList()...)
+		}
+		log.Printf("[StartTask] 使用多模型集成: %d 个模型", len(ensemble))
+	} else if req.ModelID != nil {
 		// 从数据库获取模型配置
 		model, err := tm.modelRepo.GetByIDAndActive(*req.ModelID)
 		if err != nil {
@@ -152,8 +309,8 @@ func (tm *TaskManager) StartTask(userID uint, req *dto.StartTaskRequest) (*dto.S
 		}
 		modelConfig = model
 		modelPath = model.ModelPath
-		apiServices = []string{model.APIURL}
-		log.Printf("[StartTask] 使用数据库模型配置: %s, API: %s", model.Name, model.APIURL)
+		apiServices = model.EndpointList()
+		log.Printf("[StartTask] 使用数据库模型配置: %s, API: %v", model.Name, apiServices)
 	} else if len(req.Services) > 0 {
 		// 使用前端提供的服务地址列表
 		apiServices = req.Services
@@ -161,7 +318,7 @@ func (tm *TaskManager) StartTask(userID uint, req *dto.StartTaskRequest) (*dto.S
 		log.Printf("[StartTask] 使用前端提供的服务地址: %v", apiServices)
 	} else {
 		// 使用配置文件中的默认服务地址
-		apiServices = tm.cfg.GetModelServices()
+		apiServices = tm.cfg.Current().GetModelServices()
 		modelPath = req.Model
 		log.Printf("[StartTask] 使用配置文件中的默认服务地址")
 	}
@@ -181,8 +338,83 @@ func (tm *TaskManager) StartTask(userID uint, req *dto.StartTaskRequest) (*dto.S
 
 	log.Printf("[StartTask] 解析到文件ID: %d", fileID)
 
-	// 验证文件是否存在
-	file, err := tm.fileRepo.GetByIDAndUserID(fileID, userID)
+	// 校验透传给工作进程的额外参数
+	extraArgs, err := validateExtraArgs(&tm.cfg.Current().Worker, req.ExtraArgs)
+	if err != nil {
+		log.Printf("[StartTask] 错误: 额外参数校验失败: %v", err)
+		return nil, err
+	}
+
+	// 任务类型必须已注册并启用
+	if req.TaskType == "" {
+		req.TaskType = models.DefaultTaskType
+	}
+	taskType, err := tm.taskTypeRepo.GetActiveByName(req.TaskType)
+	if err != nil {
+		log.Printf("[StartTask] 错误: 任务类型 %s 不存在或未启用", req.TaskType)
+		return nil, fmt.Errorf("任务类型不存在或未启用: %s", req.TaskType)
+	}
+
+	// 引用提示词库版本时，使用该版本的内容；都未指定时使用任务类型的默认提示词
+	var promptVersion *models.PromptVersion
+	if req.PromptVersionID != nil {
+		promptVersion, err = tm.resolvePromptVersion(userID, req)
+		if err != nil {
+			log.Printf("[StartTask] 错误: 提示词版本解析失败: %v", err)
+			return nil, err
+		}
+	} else if req.SpecialPrompt == "" && req.Directions == "" {
+		req.SpecialPrompt = taskType.SpecialPrompt
+		req.Directions = taskType.Directions
+	}
+
+	// 引用术语表时，生成注入提示词的术语说明（保存在任务参数中，便于复现）
+	var glossaryPrompt string
+	if req.GlossaryID != nil {
+		glossaryPrompt, err = tm.glossary.BuildPrompt(*req.GlossaryID, userID)
+		if err != nil {
+			log.Printf("[StartTask] 错误: 术语表解析失败: %v", err)
+			return nil, err
+		}
+	} else if req.GlossaryCheck {
+		return nil, fmt.Errorf("开启 glossary_check 时必须指定 glossary_id")
+	}
+
+	if req.SafetyCheck && (tm.safety == nil || !tm.safety.HasRules()) {
+		return nil, fmt.Errorf("未配置内容安全检查规则，无法开启 safety_check")
+	}
+
+	// 最长运行时间：未指定时使用配置默认值，且不能超过配置上限
+	maxRuntimeMinutes := req.MaxRuntimeMinutes
+	if maxRuntimeMinutes == 0 {
+		maxRuntimeMinutes = tm.cfg.Current().Worker.DefaultMaxRuntimeMinutes
+	}
+	if limit := tm.cfg.Current().Worker.MaxRuntimeMinutesLimit; limit > 0 {
+		if maxRuntimeMinutes > limit {
+			return nil, fmt.Errorf("max_runtime_minutes 不能超过 %d", limit)
+		}
+		if maxRuntimeMinutes == 0 {
+			maxRuntimeMinutes = limit
+		}
+	}
+
+	// 指定裁判模型时，任务结束后自动评分
+	if req.JudgeModelID != nil {
+		if _, err := tm.modelRepo.GetByIDAndActive(*req.JudgeModelID); err != nil {
+			log.Printf("[StartTask] 错误: 获取裁判模型失败: %v", err)
+			return nil, fmt.Errorf("裁判模型不存在或未启用")
+		}
+	}
+
+	// 校验工作进程协议版本，避免 main.py 参数变更导致任务静默失败
+	handshake, err := tm.checkWorker()
+	if err != nil {
+		log.Printf("[StartTask] 错误: 工作进程版本校验失败: %v", err)
+		return nil, err
+	}
+
+	// 验证文件是否存在（工作区共享的文件需要在工作区中拥有 operate 权限）
+	file, err := tm.fileRepo.GetEditableByIDAndUserID(fileID, userID)
 	if err != nil {
 		log.Printf("[StartTask] 错误: 文件不存在或无权访问: %v", err)
 		return nil, fmt.Errorf("文件不存在或无权访问")
@@ -190,6 +422,31 @@ func (tm *TaskManager) StartTask(userID uint, req *dto.StartTaskRequest) (*dto.S
 
 	log.Printf("[StartTask] 文件验证成功: %s (大小: %d bytes)", file.Filename, file.FileSize)
 
+	// 引用历史版本时，工作进程读取该版本内容的快照文件；任务启动失败时删除本次生成的快照
+	var snapshotIDs []uint
+	started := false
+	defer func() {
+		if !started && len(snapshotIDs) > 0 {
+			if err := tm.fileRepo.DeleteByIDs(snapshotIDs); err != nil {
+				log.Printf("[StartTask] 删除快照文件 %v 失败: %v", snapshotIDs, err)
+			}
+		}
+	}()
+	sourceFileID := fileID
+	fileVersion := file.CurrentVersion
+	if req.FileVersion != nil {
+		fileVersion = *req.FileVersion
+	}
+	file, err = tm.fileVersions.ResolveForTask(file, fileVersion)
+	if err != nil {
+		log.Printf("[StartTask] 错误: 文件版本解析失败: %v", err)
+		return nil, err
+	}
+	if file.Snapshot {
+		snapshotIDs = append(snapshotIDs, file.ID)
+	}
+	fileID = file.ID
+
 	// 生成任务ID（使用rune安全截断UTF-8字符串）
 	taskIDBase := file.Filename
 	// 转换为rune切片来安全截断UTF-8字符
@@ -201,6 +458,17 @@ func (tm *TaskManager) StartTask(userID uint, req *dto.StartTaskRequest) (*dto.S
 
 	log.Printf("[StartTask] 生成任务ID: %s", taskID)
 
+	// 输入样本预处理插件：处理结果保存为快照文件，工作进程读取快照
+	file, preprocessedBy, err := tm.preprocessInput(context.Background(), file, plugin.TaskInfo{TaskID: taskID, TaskType: req.TaskType, UserID: userID})
+	if err != nil {
+		log.Printf("[StartTask] 错误: 输入样本预处理失败: %v", err)
+		return nil, err
+	}
+	if file.Snapshot && file.ID != fileID {
+		snapshotIDs = append(snapshotIDs, file.ID)
+	}
+	fileID = file.ID
+
 	// 准备参数
 	params := map[string]interface{}{
 		"file_id":             fileID,
@@ -219,9 +487,89 @@ func (tm *TaskManager) StartTask(userID uint, req *dto.StartTaskRequest) (*dto.S
 		"api_services":        apiServices,
 	}
 
-	// 如果有模型配置，添加更多参数
+	if len(extraArgs) > 0 {
+		params["extra_args"] = extraArgs
+	}
+
+	if req.DedupAgainstSource {
+		params["dedup_against_source"] = true
+	}
+
+	if req.MaxSimilarity > 0 {
+		params["max_similarity"] = req.MaxSimilarity
+	}
+
+	if req.GlossaryID != nil {
+		params["glossary_id"] = *req.GlossaryID
+		params["glossary_prompt"] = glossaryPrompt
+		if req.GlossaryCheck {
+			params["glossary_check"] = true
+		}
+	}
+
+	if req.SafetyCheck {
+		params["safety_check"] = true
+	}
+
+	if req.JudgeModelID != nil {
+		params["judge_model_id"] = *req.JudgeModelID
+	}
+
+	if req.RequiredReviews > 1 {
+		params["required_reviews"] = req.RequiredReviews
+	}
+
+	if maxRuntimeMinutes > 0 {
+		params["max_runtime_minutes"] = maxRuntimeMinutes
+	}
+
+	if req.DisableModelCache {
+		params["disable_model_cache"] = true
+	}
+
+	if len(ensembleModels) > 0 {
+		modelIDs := make([]uint, len(ensembleModels))
+		modelNames := make([]string, len(ensembleModels))
+		for i, model := range ensembleModels {
+			modelIDs[i] = model.ID
+			modelNames[i] = model.Name
+		}
+		params["model_ids"] = modelIDs
+		params["ensemble_models"] = modelNames
+	}
+
+	if req.RerunOf != "" {
+		params["rerun_of"] = req.RerunOf
+	}
+	if req.StallRestarts > 0 {
+		params["stall_restarts"] = req.StallRestarts
+	}
+	if req.RetryAttempt > 0 {
+		params["retry_root"] = req.RetryRoot
+		params["retry_attempt"] = req.RetryAttempt
+	}
+
+	if fileVersion > 0 {
+		params["file_version"] = fileVersion
+	}
+	if fileID != sourceFileID {
+		params["source_file_id"] = sourceFileID
+	}
+	if len(preprocessedBy) > 0 {
+		params["preprocess_plugins"] = preprocessedBy
+	}
+
+	if promptVersion != nil {
+		params["prompt_version_id"] = promptVersion.ID
+		params["prompt_id"] = promptVersion.PromptID
+		params["prompt_version"] = promptVersion.Version
+	}
+
+	// 如果有模型配置，添加更多参数（多模型集成时各模型的密钥按 model_ids 从模型配置读取，不记录主模型的密钥）
 	if modelConfig != nil {
-		params["api_key"] = modelConfig.APIKey
+		if len(ensembleModels) == 0 {
+			params["api_key"] = modelConfig.APIKey
+		}
 		params["is_vllm"] = modelConfig.IsVLLM
 		params["temperature"] = modelConfig.Temperature
 		params["top_p"] = modelConfig.TopP
@@ -231,57 +579,108 @@ func (tm *TaskManager) StartTask(userID uint, req *dto.StartTaskRequest) (*dto.S
 
 	// 创建数据库任务记录
 	task := &models.Task{
-		TaskID:    taskID,
-		UserID:    userID,
-		Status:    "running",
-		Params:    params,
-		StartedAt: time.Now(),
+		TaskID:         taskID,
+		UserID:         userID,
+		WorkspaceID:    file.WorkspaceID, // 任务归属输入文件所在的工作区
+		Status:         models.TaskStatusRunning,
+		Params:         params,
+		StartedAt:      time.Now().UTC(),
+		WorkerVersion:  handshake.WorkerVersion,
+		WorkerProtocol: handshake.ProtocolVersion,
 	}
 
 	if err := tm.taskRepo.Create(task); err != nil {
 		log.Printf("[StartTask] 错误: 创建任务记录失败: %v", err)
 		return nil, fmt.Errorf("创建任务记录失败: %w", err)
 	}
+	started = true
 
 	log.Printf("[StartTask] 数据库任务记录创建成功")
 
 	// 创建内存任务上下文
 	ctx, cancel := context.WithCancel(context.Background())
 	taskCtx := &TaskContext{
-		TaskID:           taskID,
-		UserID:           userID,
-		Status:           "running",
-		Params:           params,
-		FileID:           fileID,
-		ModelConfig:      modelConfig,
-		ModelPath:        modelPath,
-		APIServices:      apiServices,
-		StartTime:        time.Now(),
-		CancelFunc:       cancel,
-		Progress:         make(chan *dto.ProgressEvent, 100),
-		Finished:         false,
-		StoppedWithChars: nil,
+		TaskID:         taskID,
+		UserID:         userID,
+		Params:         params,
+		FileID:         fileID,
+		ModelConfig:    modelConfig,
+		EnsembleModels: ensembleModels,
+		ModelPath:      modelPath,
+		APIServices:    apiServices,
+		StartTime:      time.Now(),
+		CancelFunc:     cancel,
+		Progress:       make(chan *dto.ProgressEvent, 100),
+		status:         models.TaskStatusRunning,
+		stopDone:       make(chan struct{}),
 	}
 
 	tm.tasksLock.Lock()
 	tm.tasks[taskID] = taskCtx
 	tm.tasksLock.Unlock()
 
+	tm.registerTask(taskCtx)
+	tm.openEventLog(taskCtx)
 	log.Printf("[StartTask] 任务上下文创建成功，准备启动后台执行")
 
 	// 在后台goroutine中执行任务
 	go tm.runTask(ctx, taskCtx)
 
+	tm.notifyTaskEvent(models.WebhookEventTaskStarted, taskID)
+
 	return &dto.StartTaskResponse{
 		Success: true,
 		TaskID:  taskID,
-		Status:  "running",
+		Status:  string(models.TaskStatusRunning),
 	}, nil
 }
 
+// resolvePromptVersion 解析请求引用的提示词版本，并用其内容覆盖 special_prompt 和 directions
+func (tm *TaskManager) resolvePromptVersion(userID uint, req *dto.StartTaskRequest) (*models.PromptVersion, error) {
+	if req.SpecialPrompt != "" || req.Directions != "" {
+		return nil, fmt.Errorf("prompt_version_id 与 special_prompt/directions 不能同时指定")
+	}
+
+	version, err := tm.promptRepo.GetVersionByID(*req.PromptVersionID)
+	if err != nil {
+		return nil, fmt.Errorf("提示词版本不存在")
+	}
+	if _, err := tm.promptRepo.GetByIDAndUserID(version.PromptID, userID); err != nil {
+		return nil, fmt.Errorf("提示词版本不存在或无权访问")
+	}
+
+	log.Printf("[StartTask] 使用提示词 %d 的版本 %d", version.PromptID, version.Version)
+	req.SpecialPrompt = version.SpecialPrompt
+	req.Directions = version.Directions
+	return version, nil
+}
+
 // runTask 执行任务(真实实现)
 func (tm *TaskManager) runTask(ctx context.Context, taskCtx *TaskContext) {
+	// 每次任务执行作为一个 trace 的根，trace 上下文通过环境变量传给 Python 工作进程，
+	// 工作进程的模型调用经 /api/model-call 回到后端时挂在同一个 trace 下
+	ctx, span := tracing.Start(ctx, "TaskManager.runTask",
+		attribute.String("task_id", taskCtx.TaskID),
+		attribute.String("model", taskCtx.ModelPath),
+	)
+	defer span.End()
+
 	defer close(taskCtx.Progress)
+	defer taskCtx.eventLog.Close()
+
+	// 多实例模式下记录任务结束状态（提前返回时使用 taskCtx.Status()）
+	var finalStatus models.TaskStatus
+	defer func() {
+		tm.markTaskFinished(taskCtx, finalStatus)
+	}()
+
+	// 启动阶段失败（taskCtx.Error）时推送 task.error 事件（进程运行后的结束事件在下面推送）
+	defer func() {
+		if finalStatus == "" && taskCtx.Status() == models.TaskStatusError {
+			tracing.RecordError(span, fmt.Errorf("任务启动失败"))
+			tm.notifyTaskEvent(models.WebhookEventTaskError, taskCtx.TaskID)
+		}
+	}()
 
 	log.Printf("[runTask] 任务 %s 开始执行", taskCtx.TaskID)
 
@@ -300,6 +699,11 @@ func (tm *TaskManager) runTask(ctx context.Context, taskCtx *TaskContext) {
 		}
 	}
 
+	// 任务关闭了响应缓存时，模型调用代理按任务ID跳过缓存
+	if disabled, _ := taskCtx.Params["disable_model_cache"].(bool); disabled {
+		disableModelCacheForTask(ctx, tm.redisClient, taskCtx.TaskID)
+	}
+
 	// 发送开始事件
 	taskCtx.AddEvent(&dto.ProgressEvent{
 		Type:    "output",
@@ -320,197 +724,564 @@ func (tm *TaskManager) runTask(ctx context.Context, taskCtx *TaskContext) {
 		return
 	}
 
-	// 模型限流：使用模型路径作为key
-	modelLimiterKey := fmt.Sprintf("model_limit:%s", taskCtx.ModelPath)
-	maxConcurrent := 5 // 默认并发数
-	if taskCtx.ModelConfig != nil {
-		maxConcurrent = taskCtx.ModelConfig.MaxConcurrent
-	} else if maxConcurrent <= 0 {
-		maxConcurrent = 5
+	// 速率限制准入：模型的 RPM/TPM 窗口已满时等待，避免任务启动后立即触发上游 429
+	if err := tm.waitModelRate(ctx, taskCtx); err != nil {
+		log.Printf("[runTask] 错误: %v", err)
+		taskCtx.Error(err.Error())
+		return
 	}
 
-	log.Printf("[runTask] 模型限流: %s, 最大并发: %d", modelLimiterKey, maxConcurrent)
+	if len(taskCtx.EnsembleModels) > 0 {
+		// 多模型集成：每个模型分别按自己的最大并发数限流
+		release, err := tm.acquireEnsembleTokens(ctx, taskCtx.UserID, taskCtx.EnsembleModels)
+		if err != nil {
+			log.Printf("[runTask] 错误: %v", err)
+			taskCtx.Error(err.Error())
+			return
+		}
+		log.Printf("[runTask] 成功获取 %d 个模型的令牌", len(taskCtx.EnsembleModels))
+		defer release()
+	} else {
+		// 模型限流：使用模型路径作为key
+		modelLimiterKey := fmt.Sprintf("model_limit:%s", taskCtx.ModelPath)
+		maxConcurrent := 5 // 默认并发数
+		if taskCtx.ModelConfig != nil {
+			maxConcurrent = taskCtx.ModelConfig.MaxConcurrent
+		} else if maxConcurrent <= 0 {
+			maxConcurrent = 5
+		}
 
-	// 从 Redis 获取令牌
-	acquired, err := tm.acquireModelToken(ctx, modelLimiterKey, maxConcurrent)
-	if err != nil {
-		log.Printf("[runTask] 错误: 获取模型令牌失败: %v", err)
-		taskCtx.Error(fmt.Sprintf("获取模型令牌失败: %v", err))
+		log.Printf("[runTask] 模型限流: %s, 最大并发: %d", modelLimiterKey, maxConcurrent)
+
+		// 从 Redis 获取令牌
+		release, err := tm.acquireModelToken(ctx, modelLimiterKey, maxConcurrent, taskCtx.UserID)
+		if err != nil {
+			log.Printf("[runTask] 错误: 获取模型令牌失败: %v", err)
+			taskCtx.Error(fmt.Sprintf("获取模型令牌失败: %v", err))
+			return
+		}
+
+		log.Printf("[runTask] 成功获取模型令牌")
+		defer release()
+	}
+
+	// 构建Python命令
+	args := tm.buildPythonArgs(taskCtx, services)
+
+	// 原始输出完整写入任务输出日志
+	tm.openOutputLog(taskCtx)
+	defer taskCtx.outputLog.Close()
+
+	// 启动工作进程：默认在本机运行，worker.executor 为 agent 时交给工作节点运行
+	var wait func() error
+	if tm.agents != nil {
+		wait = tm.agents.Submit(ctx, taskCtx, args)
+	} else {
+		var err error
+		wait, err = tm.startLocalWorker(ctx, taskCtx, args)
+		if err != nil {
+			log.Printf("[runTask] 错误: %v", err)
+			taskCtx.Error(err.Error())
+			return
+		}
+	}
+
+	// 看门狗：长时间没有输出时推送警告，超时后终止进程
+	taskCtx.touch()
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	go tm.watchTask(watchCtx, taskCtx)
+	stopRuntimeLimit := tm.startRuntimeLimit(taskCtx)
+
+	// 等待进程完成
+	log.Printf("[runTask] 等待Python进程完成...")
+	err := wait()
+	stopWatch()
+	stopRuntimeLimit()
+
+	// 看门狗、超时或握手失败终止进程时 ctx 已被取消，后续的保存和状态更新不能随之取消
+	if ctx.Err() != nil {
+		ctx = context.WithoutCancel(ctx)
+	}
+
+	log.Printf("[runTask] Python进程已结束，错误: %v", err)
+
+	// 任务已被停止时由 StopTask 更新状态和字符数，等待停止完成后只保存已完成的数据
+	if taskCtx.stopRequested() {
+		log.Printf("[runTask] 任务已被停止,跳过数据库更新")
+		tm.saveCheckpoint(taskCtx, models.TaskStatusStopped, taskCtx.waitStopped())
 		return
 	}
-	if !acquired {
-		log.Printf("[runTask] 错误: 模型服务繁忙，未获取到令牌")
-		taskCtx.Error("模型服务繁忙，请稍后重试")
+
+	// 从Redis读取字符数
+	var inputChars, outputChars int64
+	var progress map[string]string
+	if tm.redisClient != nil {
+		redisKey := fmt.Sprintf("task_progress:%s", taskCtx.TaskID)
+		ctx := context.Background()
+		hashData, hashErr := tm.redisClient.HGetAll(ctx, redisKey).Result()
+		if hashErr == nil {
+			progress = hashData
+			if val, ok := hashData["input_chars"]; ok {
+				inputChars, _ = strconv.ParseInt(val, 10, 64)
+			}
+			if val, ok := hashData["output_chars"]; ok {
+				outputChars, _ = strconv.ParseInt(val, 10, 64)
+			}
+			log.Printf("[runTask] 从Redis读取字符数: input=%d, output=%d", inputChars, outputChars)
+		} else {
+			log.Printf("[runTask] 从Redis读取字符数失败: %v", hashErr)
+		}
+	}
+
+	// 握手失败时进程已被终止，记录失败原因
+	failure := taskCtx.failureReasons()
+	if failure.Handshake != "" {
+		if err == nil {
+			err = fmt.Errorf("%s", failure.Handshake)
+		}
+		tm.taskRepo.UpdateErrorMessage(taskCtx.TaskID, failure.Handshake)
+	}
+
+	// 看门狗判定卡死时进程已被终止，记录卡死原因而不是进程退出信号
+	if failure.Stall != "" {
+		err = fmt.Errorf("%s", failure.Stall)
+		tm.taskRepo.UpdateErrorMessage(taskCtx.TaskID, failure.Stall)
+	}
+
+	// 超过最长运行时间时进程已被终止，标记为超时
+	if failure.Timeout != "" {
+		err = fmt.Errorf("%s", failure.Timeout)
+		tm.taskRepo.UpdateErrorMessage(taskCtx.TaskID, failure.Timeout)
+	}
+
+	// 标记任务完成
+	code := 0
+	status := models.TaskStatusFinished
+	if failure.Timeout != "" {
+		code = 1
+		status = models.TaskStatusTimeout
+	} else if err != nil {
+		code = 1
+		status = models.TaskStatusError
+	}
+
+	// 进程退出后、标记结束前收到了停止请求时按停止处理
+	if finishErr := taskCtx.finish(status, code); finishErr != nil {
+		log.Printf("[runTask] %v，按停止处理", finishErr)
+		tm.saveCheckpoint(taskCtx, models.TaskStatusStopped, taskCtx.waitStopped())
 		return
 	}
 
-	log.Printf("[runTask] 成功获取模型令牌")
-	defer tm.releaseModelToken(ctx, modelLimiterKey)
+	if err != nil {
+		log.Printf("[runTask] 任务执行失败")
+		if !failure.terminated() {
+			tm.taskRepo.UpdateErrorMessage(taskCtx.TaskID, err.Error())
+		}
+		taskCtx.AddEvent(&dto.ProgressEvent{
+			Type:    "error",
+			Line:    fmt.Sprintf("任务执行失败: %v", err),
+			Message: "错误",
+		})
+	}
 
-	// 构建Python命令
-	args := tm.buildPythonArgs(taskCtx, services)
+	// 任务成功时执行去重（在发送完成事件之前，保证前端拿到的统计已包含去重结果）
+	// 失败时保存已完成的数据，已有数据时标记为部分完成
+	if status == models.TaskStatusFinished {
+		tm.traceStage(ctx, "flushGeneratedItems", func() { tm.flushGeneratedItems(taskCtx) })
+		tm.traceStage(ctx, "runPostprocessPlugins", func() { tm.runPostprocessPlugins(taskCtx) })
+		tm.traceStage(ctx, "runRuleScoring", func() { tm.runRuleScoring(taskCtx) })
+		tm.traceStage(ctx, "runDedup", func() { tm.runDedup(taskCtx) })
+		tm.traceStage(ctx, "runSimilarityFilter", func() { tm.runSimilarityFilter(taskCtx) })
+		tm.traceStage(ctx, "runGlossaryCheck", func() { tm.runGlossaryCheck(taskCtx) })
+		tm.traceStage(ctx, "runSafetyCheck", func() { tm.runSafetyCheck(taskCtx) })
+		tm.traceStage(ctx, "runTagging", func() { tm.runTagging(taskCtx) })
+		tm.traceStage(ctx, "runJudge", func() { tm.runJudge(taskCtx) })
+	} else {
+		tm.traceStage(ctx, "saveCheckpoint", func() { status = tm.saveCheckpoint(taskCtx, status, progress) })
+		if status == models.TaskStatusPartial {
+			tm.traceStage(ctx, "runSafetyCheck", func() { tm.runSafetyCheck(taskCtx) })
+			tm.traceStage(ctx, "runTagging", func() { tm.runTagging(taskCtx) })
+			tm.traceStage(ctx, "runJudge", func() { tm.runJudge(taskCtx) })
+		}
+	}
+
+	// 瞬时错误按配置自动重试，重试次数用尽时标记为 dead_letter
+	retry := tm.planRetry(taskCtx, err)
+	if retry != nil && !retry.retry {
+		status = models.TaskStatusDeadLetter
+	}
+
+	log.Printf("[runTask] 更新任务状态为: %s", status)
+	if err := taskCtx.refineStatus(status); err != nil {
+		log.Printf("[runTask] %v", err)
+	}
+	span.SetAttributes(attribute.String("status", string(status)))
+	tracing.RecordError(span, err)
+	// 更新状态和字符数
+	if err := tm.taskRepo.WithContext(ctx).UpdateStatus(taskCtx.TaskID, status, charsUpdate(inputChars, outputChars)); err != nil {
+		log.Printf("[runTask] 更新任务状态失败: %v", err)
+	}
+	tm.saveTokenUsage(taskCtx.TaskID, progress)
+
+	if status == models.TaskStatusFinished {
+		tm.notifyTaskEvent(models.WebhookEventTaskFinished, taskCtx.TaskID)
+	} else {
+		tm.notifyTaskEvent(models.WebhookEventTaskError, taskCtx.TaskID)
+	}
 
+	// 发送完成事件
+	finalStatus = status
+	taskCtx.AddEvent(&dto.ProgressEvent{
+		Type:       "finished",
+		ReturnCode: &code,
+	})
+
+	log.Printf("[runTask] 任务 %s 执行完成，退出码: %d", taskCtx.TaskID, code)
+
+	tm.recordAttempt(taskCtx, status, err, retry)
+	if retry != nil && retry.retry {
+		tm.scheduleRetry(taskCtx, retry)
+	}
+
+	if failure.Stall != "" {
+		tm.restartStalledTask(taskCtx)
+	}
+}
+
+// startLocalWorker 在本机启动 main.py 并读取输出，返回等待进程结束的函数
+func (tm *TaskManager) startLocalWorker(ctx context.Context, taskCtx *TaskContext, args []string) (func() error, error) {
 	log.Printf("[runTask] Python命令: python3 %v", args)
 
-	// 启动Python进程
+	// 启动Python进程（独立进程组，停止任务时连同其子进程一起结束）
 	cmd := exec.CommandContext(ctx, "python3", args...)
+	procgroup.Configure(cmd, tm.cfg.Current().Worker.GetStopGracePeriod())
 
 	// 设置环境变量，禁用Python输出缓冲
 	cmd.Env = append(os.Environ(), "PYTHONUNBUFFERED=1")
+	cmd.Env = append(cmd.Env, tracing.Environ(ctx)...)
+	// gRPC 服务开启 TLS 时工作进程按同样的证书连接
+	if tm.cfg.Current().Worker.GRPCEnabled && tm.cfg.Current().Worker.GRPCTLSEnabled() {
+		cmd.Env = append(cmd.Env, workerrpc.TLSEnviron(tm.cfg.Current().Worker.GetGRPCTLSCA(), tm.cfg.Current().Worker.GRPCTLSServerName)...)
+	}
 
 	// 设置工作目录为项目根目录
-	cmd.Dir = tm.cfg.ProjectRoot
+	cmd.Dir = tm.cfg.Current().ProjectRoot
 	log.Printf("[runTask] 工作目录: %s", cmd.Dir)
 
 	// 获取标准输出和错误输出管道
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		log.Printf("[runTask] 错误: 创建输出管道失败: %v", err)
-		taskCtx.Error(fmt.Sprintf("创建输出管道失败: %v", err))
-		return
+		return nil, fmt.Errorf("创建输出管道失败: %v", err)
 	}
 	stderr, err := cmd.StderrPipe()
 	if err != nil {
-		log.Printf("[runTask] 错误: 创建错误管道失败: %v", err)
-		taskCtx.Error(fmt.Sprintf("创建错误管道失败: %v", err))
-		return
+		return nil, fmt.Errorf("创建错误管道失败: %v", err)
 	}
 
 	// 启动进程
 	log.Printf("[runTask] 准备启动Python进程...")
 	if err := cmd.Start(); err != nil {
-		log.Printf("[runTask] 错误: 启动Python进程失败: %v", err)
-		taskCtx.Error(fmt.Sprintf("启动Python进程失败: %v", err))
-		return
+		return nil, fmt.Errorf("启动Python进程失败: %v", err)
 	}
 
 	log.Printf("[runTask] Python进程已启动，PID: %d", cmd.Process.Pid)
 
 	// 读取输出
 	done := make(chan error, 2)
-
-	// 读取标准输出
-	go func() {
-		log.Printf("[runTask] 开始读取标准输出...")
-		scanner := bufio.NewScanner(stdout)
+	readStream := func(stream string, r io.Reader) {
+		log.Printf("[runTask] 开始读取 %s...", stream)
+		scanner := bufio.NewScanner(r)
 		lineCount := 0
 		for scanner.Scan() {
-			line := scanner.Text()
 			lineCount++
-			log.Printf("[Python STDOUT] %s", line)
-			tm.handlePythonOutput(taskCtx, line)
+			tm.handleWorkerLine(taskCtx, stream, scanner.Text())
 		}
-		log.Printf("[runTask] 标准输出读取完成，共 %d 行", lineCount)
+		log.Printf("[runTask] %s 读取完成，共 %d 行", stream, lineCount)
 		done <- scanner.Err()
-	}()
+	}
+	go readStream("stdout", stdout)
+	go readStream("stderr", stderr)
+
+	return func() error {
+		err := cmd.Wait()
+		// 等待所有goroutine完成
+		for i := 0; i < 2; i++ {
+			<-done
+		}
+		return err
+	}, nil
+}
 
-	// 读取错误输出
-	go func() {
-		log.Printf("[runTask] 开始读取错误输出...")
-		scanner := bufio.NewScanner(stderr)
-		lineCount := 0
-		for scanner.Scan() {
-			line := scanner.Text()
-			lineCount++
-			log.Printf("[Python STDERR] %s", line)
-			taskCtx.AddEvent(&dto.ProgressEvent{
-				Type:    "error",
-				Line:    line,
-				Message: "错误",
-			})
+// handleWorkerLine 处理工作进程的一行输出（本机进程和工作节点转发的输出相同处理）
+// 标准输出按协议解析为进度事件，错误输出作为 error 事件推送
+func (tm *TaskManager) handleWorkerLine(taskCtx *TaskContext, stream, line string) {
+	taskCtx.touch()
+	taskCtx.outputLog.WriteLine(stream, line)
+	if stream == "stderr" {
+		log.Printf("[Python STDERR] %s", line)
+		if line != "" {
+			taskCtx.setFailureReason(failureWorkerError, line)
 		}
-		log.Printf("[runTask] 错误输出读取完成，共 %d 行", lineCount)
-		done <- scanner.Err()
-	}()
+		taskCtx.AddEvent(&dto.ProgressEvent{
+			Type:    "error",
+			Line:    line,
+			Message: "错误",
+		})
+		return
+	}
+	log.Printf("[Python STDOUT] %s", line)
+	tm.handlePythonOutput(taskCtx, line)
+}
 
-	// 等待进程完成
-	log.Printf("[runTask] 等待Python进程完成...")
-	err = cmd.Wait()
+// traceStage 在任务的 trace 下记录任务结束后处理阶段（保存、去重、打标、评分等）的耗时
+func (tm *TaskManager) traceStage(ctx context.Context, name string, fn func()) {
+	_, span := tracing.Start(ctx, "TaskManager."+name, attribute.String("stage", name))
+	defer span.End()
+	fn()
+}
 
-	// 等待所有goroutine完成
-	for i := 0; i < 2; i++ {
-		<-done
+// runRuleScoring 任务完成后按任务类型启用的评分规则为数据评分（没有启用的规则时保留工作进程写入的规则评分）
+func (tm *TaskManager) runRuleScoring(taskCtx *TaskContext) {
+	if tm.scoring == nil {
+		return
 	}
 
-	log.Printf("[runTask] Python进程已结束，错误: %v", err)
+	taskType, _ := taskCtx.Params["task_type"].(string)
+	result, err := tm.scoring.ScoreTask(taskCtx.TaskID, taskType)
+	if err != nil {
+		log.Printf("[runTask] 规则评分失败: %v", err)
+		taskCtx.AddEvent(&dto.ProgressEvent{
+			Type:    "error",
+			Line:    fmt.Sprintf("规则评分失败: %v", err),
+			Message: "错误",
+		})
+		return
+	}
+	if result == nil {
+		return
+	}
 
-	// 检查任务是否已被停止（避免覆盖StopTask设置的字符数）
-	if taskCtx.Status == "stopped" && taskCtx.StoppedWithChars != nil {
-		// 任务已被停止，跳过数据库更新
-		log.Printf("[runTask] 任务已被停止,跳过数据库更新")
+	taskCtx.AddEvent(&dto.ProgressEvent{
+		Type:    "output",
+		Line:    fmt.Sprintf("规则评分完成: 规则 %d 条, 共 %d 条, 全部通过 %d 条, 平均 %.1f 分", result.Rules, result.Checked, result.Passed, result.AverageScore),
+		Message: "规则评分完成",
+	})
+}
+
+// runDedup 任务完成后的去重作业（仅在启动任务时开启 dedup_against_source 时执行）
+func (tm *TaskManager) runDedup(taskCtx *TaskContext) {
+	enabled, _ := taskCtx.Params["dedup_against_source"].(bool)
+	if !enabled || tm.dedupService == nil {
 		return
 	}
 
-	// 从Redis读取字符数
-	var inputChars, outputChars int64
-	if tm.redisClient != nil {
-		redisKey := fmt.Sprintf("task_progress:%s", taskCtx.TaskID)
-		ctx := context.Background()
-		hashData, hashErr := tm.redisClient.HGetAll(ctx, redisKey).Result()
-		if hashErr == nil {
-			if val, ok := hashData["input_chars"]; ok {
-				inputChars, _ = strconv.ParseInt(val, 10, 64)
-			}
-			if val, ok := hashData["output_chars"]; ok {
-				outputChars, _ = strconv.ParseInt(val, 10, 64)
-			}
-			log.Printf("[runTask] 从Redis读取字符数: input=%d, output=%d", inputChars, outputChars)
-		} else {
-			log.Printf("[runTask] 从Redis读取字符数失败: %v", hashErr)
-		}
+	result, err := tm.dedupService.DedupTask(taskCtx.TaskID, taskCtx.FileID)
+	if err != nil {
+		log.Printf("[runTask] 去重失败: %v", err)
+		taskCtx.AddEvent(&dto.ProgressEvent{
+			Type:    "error",
+			Line:    fmt.Sprintf("去重失败: %v", err),
+			Message: "错误",
+		})
+		return
 	}
 
-	// 标记任务完成
-	code := 0
+	taskCtx.AddEvent(&dto.ProgressEvent{
+		Type:    "output",
+		Line:    fmt.Sprintf("去重完成: 共 %d 条, 与源文件重复 %d 条, 内部重复 %d 条", result.Checked, result.SourceDuplicates, result.InternalDuplicates),
+		Message: "去重完成",
+	})
+}
+
+// runSimilarityFilter 任务完成后的语义相似度过滤（仅在启动任务时指定 max_similarity 时执行）
+func (tm *TaskManager) runSimilarityFilter(taskCtx *TaskContext) {
+	maxSimilarity, _ := taskCtx.Params["max_similarity"].(float64)
+	if maxSimilarity <= 0 || tm.embedding == nil {
+		return
+	}
+
+	result, err := tm.embedding.FilterSimilarTask(taskCtx.TaskID, taskCtx.FileID, maxSimilarity)
 	if err != nil {
-		code = 1
-		log.Printf("[runTask] 任务执行失败")
+		log.Printf("[runTask] 语义相似度过滤失败: %v", err)
 		taskCtx.AddEvent(&dto.ProgressEvent{
 			Type:    "error",
-			Line:    fmt.Sprintf("任务执行失败: %v", err),
+			Line:    fmt.Sprintf("语义相似度过滤失败: %v", err),
 			Message: "错误",
 		})
+		return
 	}
 
-	taskCtx.Finished = true
-	taskCtx.ReturnCode = &code
-	now := time.Now()
-	taskCtx.EndTime = &now
+	taskCtx.AddEvent(&dto.ProgressEvent{
+		Type:    "output",
+		Line:    fmt.Sprintf("语义相似度过滤完成: 共 %d 条, 与源样本过于相似 %d 条, 与已保留数据过于相似 %d 条（已删除）", result.Checked, result.SourceSimilar, result.VariantSimilar),
+		Message: "语义相似度过滤完成",
+	})
+}
 
-	// 更新数据库
-	status := "finished"
+// runGlossaryCheck 任务完成后的术语检查（仅在启动任务时开启 glossary_check 时执行）
+func (tm *TaskManager) runGlossaryCheck(taskCtx *TaskContext) {
+	enabled, _ := taskCtx.Params["glossary_check"].(bool)
+	glossaryID, _ := taskCtx.Params["glossary_id"].(uint)
+	if !enabled || glossaryID == 0 || tm.glossary == nil {
+		return
+	}
+
+	result, err := tm.glossary.CheckTask(taskCtx.TaskID, glossaryID)
 	if err != nil {
-		status = "error"
+		log.Printf("[runTask] 术语检查失败: %v", err)
+		taskCtx.AddEvent(&dto.ProgressEvent{
+			Type:    "error",
+			Line:    fmt.Sprintf("术语检查失败: %v", err),
+			Message: "错误",
+		})
+		return
 	}
 
-	log.Printf("[runTask] 更新任务状态为: %s", status)
-	// 更新状态和字符数
-	tm.taskRepo.UpdateStatusWithTimeAndChars(taskCtx.TaskID, status, inputChars, outputChars)
+	taskCtx.AddEvent(&dto.ProgressEvent{
+		Type:    "output",
+		Line:    fmt.Sprintf("术语检查完成: 共 %d 条, 存在违规 %d 条, 违规 %d 处", result.Checked, result.ViolatingRows, result.Violations),
+		Message: "术语检查完成",
+	})
+}
+
+// runSafetyCheck 任务结束后的内容安全检查（配置开启自动检查，或启动任务时开启 safety_check 时执行）
+func (tm *TaskManager) runSafetyCheck(taskCtx *TaskContext) {
+	enabled, _ := taskCtx.Params["safety_check"].(bool)
+	if tm.safety == nil || !(enabled || tm.safety.AutoCheckEnabled()) {
+		return
+	}
+
+	result, err := tm.safety.CheckTask(taskCtx.TaskID)
+	if err != nil {
+		log.Printf("[runTask] 内容安全检查失败: %v", err)
+		taskCtx.AddEvent(&dto.ProgressEvent{
+			Type:    "error",
+			Line:    fmt.Sprintf("内容安全检查失败: %v", err),
+			Message: "错误",
+		})
+		return
+	}
 
-	// 发送完成事件
 	taskCtx.AddEvent(&dto.ProgressEvent{
-		Type:       "finished",
-		ReturnCode: &code,
+		Type:    "output",
+		Line:    fmt.Sprintf("内容安全检查完成: 共 %d 条, 标记 %d 条", result.Checked, result.Flagged),
+		Message: "内容安全检查完成",
 	})
+}
 
-	log.Printf("[runTask] 任务 %s 执行完成，退出码: %d", taskCtx.TaskID, code)
+// runTagging 任务结束后按用户启用的打标规则为数据打标（没有启用的规则时跳过）
+func (tm *TaskManager) runTagging(taskCtx *TaskContext) {
+	if tm.tagging == nil {
+		return
+	}
+
+	result, err := tm.tagging.TagTask(taskCtx.TaskID, taskCtx.UserID)
+	if err != nil {
+		log.Printf("[runTask] 打标失败: %v", err)
+		taskCtx.AddEvent(&dto.ProgressEvent{
+			Type:    "error",
+			Line:    fmt.Sprintf("打标失败: %v", err),
+			Message: "错误",
+		})
+		return
+	}
+	if result == nil {
+		return
+	}
+
+	taskCtx.AddEvent(&dto.ProgressEvent{
+		Type:    "output",
+		Line:    fmt.Sprintf("打标完成: 共 %d 条, 打标 %d 条", result.Checked, result.Tagged),
+		Message: "打标完成",
+	})
+}
+
+// runJudge 任务结束后调用裁判模型为数据评分（仅在启动任务时指定 judge_model_id 时执行），评分进度作为进度事件推送
+func (tm *TaskManager) runJudge(taskCtx *TaskContext) {
+	judgeModelID, _ := taskCtx.Params["judge_model_id"].(uint)
+	if judgeModelID == 0 || tm.judge == nil {
+		return
+	}
+
+	taskCtx.AddEvent(&dto.ProgressEvent{
+		Type:    "output",
+		Line:    "开始裁判模型评分",
+		Message: "开始评分",
+	})
+
+	result, err := tm.judge.JudgeTask(taskCtx.TaskID, judgeModelID, func(p *dto.JudgeProgressResponse) {
+		done := p.Scored + p.Failed
+		total := p.Total
+		taskCtx.AddEvent(&dto.ProgressEvent{
+			Type:     "progress",
+			Progress: &done,
+			Total:    &total,
+			Percent:  p.Percent,
+			Message:  fmt.Sprintf("评分进度: %d/%d (%.1f%%)", done, total, p.Percent),
+		})
+	})
+	if err != nil {
+		log.Printf("[runTask] 评分失败: %v", err)
+		taskCtx.AddEvent(&dto.ProgressEvent{
+			Type:    "error",
+			Line:    fmt.Sprintf("评分失败: %v", err),
+			Message: "错误",
+		})
+		return
+	}
+
+	line := fmt.Sprintf("评分完成: 共 %d 条, 成功 %d 条, 失败 %d 条", result.Total, result.Scored, result.Failed)
+	if result.AverageScore != nil {
+		line += fmt.Sprintf(", 平均分 %.2f", *result.AverageScore)
+	}
+	taskCtx.AddEvent(&dto.ProgressEvent{
+		Type:    "output",
+		Line:    line,
+		Message: "评分完成",
+	})
+}
+
+// notifyTaskEvent 推送任务生命周期事件（Webhook 和订阅的通知）
+func (tm *TaskManager) notifyTaskEvent(event string, taskID string) {
+	if tm.webhookService != nil {
+		tm.webhookService.DispatchTaskEvent(event, taskID)
+	}
+	if tm.notifications != nil {
+		tm.notifications.DispatchTaskEvent(event, taskID)
+	}
 }
 
 // getModelServices 获取模型服务地址列表
 func (tm *TaskManager) getModelServices(modelName string) []string {
 	// 从配置获取模型服务地址
-	return tm.cfg.GetModelServices()
+	return tm.cfg.Current().GetModelServices()
+}
+
+// ApplyConfig 配置重新加载后更新调度限流器的最大等待时间
+func (tm *TaskManager) ApplyConfig(cfg *config.Config) {
+	tm.rateLimiter.SetMaxWaitTime(cfg.Redis.GetMaxWaitDuration())
+	tm.fairLimiter.SetMaxWaitTime(cfg.Redis.GetMaxWaitDuration())
 }
 
-// acquireModelToken 获取模型限流令牌（带轮询等待机制）
-func (tm *TaskManager) acquireModelToken(ctx context.Context, key string, maxConcurrent int) (bool, error) {
+// acquireModelToken 获取模型限流令牌（带轮询等待机制），返回释放令牌的函数
+// 开启公平调度时按用户加权公平分配，否则先到先得；释放时使用获取令牌的限流器，不受之后热加载切换 fair_scheduling 的影响
+func (tm *TaskManager) acquireModelToken(ctx context.Context, key string, maxConcurrent int, userID uint) (func(), error) {
 	if tm.redisClient == nil {
 		// 如果没有Redis，直接允许
-		return true, nil
+		return func() {}, nil
+	}
+
+	if tm.cfg.Current().Redis.FairScheduling {
+		owner := schedulingOwner(userID)
+		if err := tm.fairLimiter.Acquire(ctx, key, maxConcurrent, owner, tm.userSchedulingWeight(userID)); err != nil {
+			return nil, err
+		}
+		// 使用独立上下文，任务被取消后仍能释放
+		return func() { tm.fairLimiter.Release(context.Background(), key, owner) }, nil
 	}
 
 	// 获取最大等待时间
-	maxWaitTime := tm.cfg.Redis.GetMaxWaitDuration()
+	maxWaitTime := tm.cfg.Current().Redis.GetMaxWaitDuration()
 
 	// 轮询等待令牌
 	startTime := time.Now()
@@ -521,13 +1292,13 @@ func (tm *TaskManager) acquireModelToken(ctx context.Context, key string, maxCon
 		// 检查是否超过最大等待时间
 		elapsed := time.Since(startTime)
 		if elapsed >= maxWaitTime {
-			return false, fmt.Errorf("获取模型令牌超时: 已等待 %v, 超过最大等待时间 %v", elapsed.Round(time.Second), maxWaitTime)
+			return nil, fmt.Errorf("获取模型令牌超时: 已等待 %v, 超过最大等待时间 %v", elapsed.Round(time.Second), maxWaitTime)
 		}
 
 		// 尝试获取令牌
 		current, err := tm.redisClient.Incr(ctx, key).Result()
 		if err != nil {
-			return false, fmt.Errorf("获取模型令牌失败: %w", err)
+			return nil, fmt.Errorf("获取模型令牌失败: %w", err)
 		}
 
 		if current == 1 {
@@ -538,7 +1309,7 @@ func (tm *TaskManager) acquireModelToken(ctx context.Context, key string, maxCon
 		if current <= int64(maxConcurrent) {
 			// 成功获取令牌
 			log.Printf("[TaskManager] 成功获取模型令牌, key: %s, 当前并发: %d/%d, 等待时间: %v", key, current, maxConcurrent, elapsed.Round(time.Second))
-			return true, nil
+			return func() { tm.redisClient.Decr(context.Background(), key) }, nil
 		}
 
 		// 超过限制，释放令牌并等待重试
@@ -556,17 +1327,45 @@ func (tm *TaskManager) acquireModelToken(ctx context.Context, key string, maxCon
 			retryInterval = nextRetryInterval
 			continue
 		case <-ctx.Done():
-			return false, fmt.Errorf("上下文已取消: %w", ctx.Err())
+			return nil, fmt.Errorf("上下文已取消: %w", ctx.Err())
 		}
 	}
 }
 
-// releaseModelToken 释放模型限流令牌
-func (tm *TaskManager) releaseModelToken(ctx context.Context, key string) {
+// waitModelRate 等待任务使用的模型（集成任务为全部模型）均未超过 RPM/TPM 限制
+// 只做准入检查不记录请求，实际请求在 ModelService.CallModel 中计入同一滑动窗口
+func (tm *TaskManager) waitModelRate(ctx context.Context, taskCtx *TaskContext) error {
 	if tm.redisClient == nil {
-		return
+		return nil
 	}
-	tm.redisClient.Decr(ctx, key)
+
+	modelConfigs := taskCtx.EnsembleModels
+	if len(modelConfigs) == 0 && taskCtx.ModelConfig != nil {
+		modelConfigs = []*models.ModelConfig{taskCtx.ModelConfig}
+	}
+	for _, model := range modelConfigs {
+		if model.RPMLimit <= 0 && model.TPMLimit <= 0 {
+			continue
+		}
+		if err := tm.rateLimiter.WaitAvailable(ctx, modelRateKey(model, taskCtx.ModelPath), model.RPMLimit, model.TPMLimit); err != nil {
+			return fmt.Errorf("模型 %s 速率限制等待失败: %v", model.Name, err)
+		}
+	}
+	return nil
+}
+
+// userSchedulingWeight 按用户角色获取公平调度权重
+func (tm *TaskManager) userSchedulingWeight(userID uint) int {
+	user, err := tm.userRepo.GetByID(userID)
+	if err != nil {
+		return 1
+	}
+	return tm.cfg.Current().Redis.GetRoleWeight(user.EffectiveRole())
+}
+
+// schedulingOwner 公平调度中的槽位所有者标识
+func schedulingOwner(userID uint) string {
+	return "user:" + strconv.FormatUint(uint64(userID), 10)
 }
 
 // buildPythonArgs 构建Python命令参数
@@ -603,6 +1402,14 @@ func (tm *TaskManager) buildPythonArgs(taskCtx *TaskContext, services []string)
 	specialPrompt := getStringParam("special_prompt", "")
 	directions := getStringParam("directions", "")
 
+	// 术语表说明追加到 special_prompt 之后
+	if glossaryPrompt := getStringParam("glossary_prompt", ""); glossaryPrompt != "" {
+		if specialPrompt != "" {
+			specialPrompt += "\n\n"
+		}
+		specialPrompt += glossaryPrompt
+	}
+
 	args := []string{
 		"main.py",
 		"--file-id", strconv.FormatUint(uint64(taskCtx.FileID), 10),
@@ -626,7 +1433,7 @@ func (tm *TaskManager) buildPythonArgs(taskCtx *TaskContext, services []string)
 	// 如果有模型配置，添加API相关参数
 	if taskCtx.ModelConfig != nil {
 		if taskCtx.ModelConfig.APIKey != "" && taskCtx.ModelConfig.APIKey != "sk-xxxxx" {
-			args = append(args, "--api-key", taskCtx.ModelConfig.APIKey)
+			args = append(args, "--api-key", config.ResolveSecret(taskCtx.ModelConfig.APIKey))
 		}
 		if taskCtx.ModelConfig.IsVLLM {
 			args = append(args, "--is-vllm")
@@ -636,6 +1443,11 @@ func (tm *TaskManager) buildPythonArgs(taskCtx *TaskContext, services []string)
 		args = append(args, "--timeout", strconv.Itoa(taskCtx.ModelConfig.Timeout))
 	}
 
+	// 多模型集成：各模型的地址和参数通过 --ensemble 传递，生成数据的 generation_model 记录实际使用的模型
+	if len(taskCtx.EnsembleModels) > 0 {
+		args = append(args, "--ensemble", buildEnsembleArg(taskCtx.EnsembleModels))
+	}
+
 	// 可选参数
 	if specialPrompt != "" {
 		args = append(args, "--special-prompt", specialPrompt)
@@ -646,6 +1458,23 @@ func (tm *TaskManager) buildPythonArgs(taskCtx *TaskContext, services []string)
 		args = append(args, "--directions", directions)
 	}
 
+	// 开启 gRPC 时工作进程优先通过 gRPC 与后端通信，连接失败时回退到标准输出
+	if tm.cfg.Current().Worker.GRPCEnabled {
+		args = append(args, "--grpc-addr", workerGRPCTarget(tm.cfg.Current().Worker.GRPCAddr))
+	}
+
+	// 白名单内的额外参数（已在 StartTask 中校验）
+	switch extra := taskCtx.Params["extra_args"].(type) {
+	case map[string]string:
+		args = appendExtraArgs(args, &tm.cfg.Current().Worker, extra)
+	case map[string]interface{}:
+		converted := make(map[string]string, len(extra))
+		for k, v := range extra {
+			converted[k] = fmt.Sprintf("%v", v)
+		}
+		args = appendExtraArgs(args, &tm.cfg.Current().Worker, converted)
+	}
+
 	return args
 }
 
@@ -655,7 +1484,17 @@ func (tm *TaskManager) handlePythonOutput(taskCtx *TaskContext, line string) {
 	var output map[string]interface{}
 	if err := json.Unmarshal([]byte(line), &output); err == nil {
 		// JSON格式输出
-		if progress, ok := output["progress"].(map[string]interface{}); ok {
+		if output["type"] == "handshake" {
+			tm.handleWorkerHandshake(taskCtx, line)
+		} else if output["type"] == "heartbeat" {
+			// 心跳只用于看门狗检测（读取时已刷新活动时间），不推送事件
+		} else if output["type"] == "item" {
+			// 生成数据只缓存，不作为事件推送
+			tm.bufferGeneratedItem(taskCtx, output["data"])
+		} else if output["type"] == "event" {
+			// 结构化进度事件（轮次、批次、评分等）
+			tm.handleWorkerEvent(taskCtx, line)
+		} else if progress, ok := output["progress"].(map[string]interface{}); ok {
 			taskCtx.AddEvent(&dto.ProgressEvent{
 				Type:    "progress",
 				Message: fmt.Sprintf("进度: %v", progress),
@@ -682,6 +1521,55 @@ func (tm *TaskManager) handlePythonOutput(taskCtx *TaskContext, line string) {
 	}
 }
 
+// handleWorkerHandshake 记录工作进程上报的版本，协议不兼容时终止进程
+func (tm *TaskManager) handleWorkerHandshake(taskCtx *TaskContext, line string) {
+	handshake, ok := parseWorkerHandshake(line)
+	if !ok {
+		return
+	}
+
+	log.Printf("[runTask] 任务 %s 工作进程版本: %s, 协议版本: %d", taskCtx.TaskID, handshake.WorkerVersion, handshake.ProtocolVersion)
+	if err := tm.taskRepo.UpdateWorkerInfo(taskCtx.TaskID, handshake.WorkerVersion, handshake.ProtocolVersion); err != nil {
+		log.Printf("[runTask] 记录工作进程版本失败: %v", err)
+	}
+
+	if err := handshake.CheckCompatible(); err != nil {
+		log.Printf("[runTask] 错误: %v", err)
+		taskCtx.setFailureReason(failureHandshake, err.Error())
+		taskCtx.AddEvent(&dto.ProgressEvent{
+			Type:    "error",
+			Line:    err.Error(),
+			Message: "错误",
+		})
+		if taskCtx.CancelFunc != nil {
+			taskCtx.CancelFunc()
+		}
+		return
+	}
+
+	taskCtx.AddEvent(&dto.ProgressEvent{
+		Type:    "output",
+		Line:    fmt.Sprintf("工作进程版本: %s (协议 v%d)", handshake.WorkerVersion, handshake.ProtocolVersion),
+		Message: "工作进程握手",
+	})
+}
+
+// WorkerInfo 获取已安装工作进程的版本信息（用于健康检查），工作节点模式下为在线工作节点的版本
+func (tm *TaskManager) WorkerInfo() (*WorkerHandshake, error) {
+	if tm.agents != nil {
+		return tm.agents.CheckCompatible()
+	}
+	return tm.workerProbe.Probe()
+}
+
+// checkWorker 启动任务前校验工作进程协议版本，工作节点模式下要求至少有一个兼容的在线工作节点
+func (tm *TaskManager) checkWorker() (*WorkerHandshake, error) {
+	if tm.agents != nil {
+		return tm.agents.CheckCompatible()
+	}
+	return tm.workerProbe.CheckCompatible()
+}
+
 // Error 发送错误事件
 func (tc *TaskContext) Error(message string) {
 	tc.Progress <- &dto.ProgressEvent{
@@ -691,12 +1579,16 @@ func (tc *TaskContext) Error(message string) {
 	}
 
 	// 标记任务失败
-	code := 1
-	tc.Finished = true
-	tc.ReturnCode = &code
-	tc.Status = "error"
-	now := time.Now()
-	tc.EndTime = &now
+	tc.fail()
+}
+
+// canAccessTask 判断用户能否按指定权限访问内存中的任务（任务创建者，或在任务所属工作区拥有该权限）
+func (tm *TaskManager) canAccessTask(taskCtx *TaskContext, userID uint, permission models.Permission) bool {
+	if taskCtx.UserID == userID {
+		return true
+	}
+	_, err := tm.taskRepo.GetAccessibleByTaskID(taskCtx.TaskID, userID, permission)
+	return err == nil
 }
 
 // StopTask 停止任务
@@ -708,17 +1600,24 @@ func (tm *TaskManager) StopTask(taskID string, userID uint) error {
 
 	if exists {
 		// 验证用户权限
-		if taskCtx.UserID != userID {
+		if !tm.canAccessTask(taskCtx, userID, models.PermissionOperate) {
 			return fmt.Errorf("无权停止此任务")
 		}
 
+		// 进入停止流程（任务已结束或正在停止时拒绝，runTask 会等待停止完成）
+		if err := taskCtx.beginStop(); err != nil {
+			return err
+		}
+
 		// 从Redis读取字符数
 		var inputChars, outputChars int64
+		var progress map[string]string
 		if tm.redisClient != nil {
 			redisKey := fmt.Sprintf("task_progress:%s", taskID)
 			ctx := context.Background()
 			hashData, hashErr := tm.redisClient.HGetAll(ctx, redisKey).Result()
 			if hashErr == nil {
+				progress = hashData
 				if val, ok := hashData["input_chars"]; ok {
 					inputChars, _ = strconv.ParseInt(val, 10, 64)
 				}
@@ -736,18 +1635,13 @@ func (tm *TaskManager) StopTask(taskID string, userID uint) error {
 			taskCtx.CancelFunc()
 		}
 
-		// 更新状态并保存字符数到上下文
-		taskCtx.Status = "stopped"
-		taskCtx.Finished = true
-		code := -1
-		taskCtx.ReturnCode = &code
-		// 保存字符数到上下文，用于runTask检测
-		taskCtx.StoppedWithChars = map[string]int64{
-			"input":  inputChars,
-			"output": outputChars,
+		// 更新数据库后完成停止流程，runTask 随后只保存已完成的数据
+		if err := tm.taskRepo.UpdateStatus(taskID, models.TaskStatusStopped, charsUpdate(inputChars, outputChars)); err != nil {
+			log.Printf("[StopTask] 更新任务状态失败: %v", err)
 		}
-
-		tm.taskRepo.UpdateStatusWithTimeAndChars(taskID, "stopped", inputChars, outputChars)
+		tm.saveTokenUsage(taskID, progress)
+		taskCtx.completeStop(progress)
+		tm.notifyTaskEvent(models.WebhookEventTaskStopped, taskID)
 
 		// 清理Redis中的进度数据
 		tm.clearTaskProgress(taskID)
@@ -755,6 +1649,11 @@ func (tm *TaskManager) StopTask(taskID string, userID uint) error {
 		return nil
 	}
 
+	// 多实例模式下任务运行在其他实例上时，转发给该实例停止工作进程
+	if forwarded, err := tm.stopRemoteTask(taskID, userID); forwarded {
+		return err
+	}
+
 	// 如果内存中不存在，说明Go后端可能重启过
 	// 检查数据库中是否有这个任务
 	task, err := tm.taskRepo.GetByTaskID(taskID)
@@ -762,23 +1661,27 @@ func (tm *TaskManager) StopTask(taskID string, userID uint) error {
 		return fmt.Errorf("任务不存在")
 	}
 
-	// 关键：验证用户权限 - 只能停止自己的任务
+	// 关键：验证用户权限 - 只能停止自己的任务或所在工作区中可操作的任务
 	if task.UserID != userID {
-		return fmt.Errorf("无权停止此任务")
+		if _, err := tm.taskRepo.GetEditableByTaskID(taskID, userID); err != nil {
+			return fmt.Errorf("无权停止此任务")
+		}
 	}
 
 	// 只有当任务状态为running时，才允许停止
-	if task.Status != "running" {
+	if task.Status != models.TaskStatusRunning {
 		return fmt.Errorf("任务状态为 %s，无法停止", task.Status)
 	}
 
 	// 从Redis读取字符数
 	var inputChars, outputChars int64
+	var progress map[string]string
 	if tm.redisClient != nil {
 		redisKey := fmt.Sprintf("task_progress:%s", taskID)
 		ctx := context.Background()
 		hashData, hashErr := tm.redisClient.HGetAll(ctx, redisKey).Result()
 		if hashErr == nil {
+			progress = hashData
 			if val, ok := hashData["input_chars"]; ok {
 				inputChars, _ = strconv.ParseInt(val, 10, 64)
 			}
@@ -794,7 +1697,15 @@ func (tm *TaskManager) StopTask(taskID string, userID uint) error {
 	// 任务在内存中不存在，可能是Go后端重启导致的
 	// 此时Python进程可能已经失去了控制，直接更新数据库状态即可
 	log.Printf("[StopTask] 任务 %s 在内存中不存在（可能是后端重启），更新数据库状态为stopped", taskID)
-	tm.taskRepo.UpdateStatusWithTimeAndChars(taskID, "stopped", inputChars, outputChars)
+	// 读取状态后任务可能已被其他请求停止或已结束，条件更新失败时不再重复通知
+	if err := tm.taskRepo.UpdateStatus(taskID, models.TaskStatusStopped, charsUpdate(inputChars, outputChars)); err != nil {
+		return fmt.Errorf("停止任务失败: %w", err)
+	}
+	tm.saveTokenUsage(taskID, progress)
+	tm.notifyTaskEvent(models.WebhookEventTaskStopped, taskID)
+	if tm.registry != nil {
+		tm.registry.MarkFinished(taskID, string(models.TaskStatusStopped))
+	}
 
 	// 清理Redis中的进度数据
 	tm.clearTaskProgress(taskID)
@@ -802,6 +1713,18 @@ func (tm *TaskManager) StopTask(taskID string, userID uint) error {
 	return nil
 }
 
+// saveTokenUsage 将 Redis 进度中累计的Token用量（模型调用代理写入）保存到任务记录
+func (tm *TaskManager) saveTokenUsage(taskID string, progress map[string]string) {
+	promptTokens, _ := strconv.ParseInt(progress["prompt_tokens"], 10, 64)
+	completionTokens, _ := strconv.ParseInt(progress["completion_tokens"], 10, 64)
+	if promptTokens == 0 && completionTokens == 0 {
+		return
+	}
+	if err := tm.taskRepo.UpdateTokenUsage(taskID, promptTokens, completionTokens); err != nil {
+		log.Printf("[TaskManager] 保存任务 %s 的Token用量失败: %v", taskID, err)
+	}
+}
+
 // clearTaskProgress 清理Redis中的任务进度数据
 func (tm *TaskManager) clearTaskProgress(taskID string) {
 	if tm.redisClient == nil {
@@ -847,6 +1770,10 @@ func (tm *TaskManager) GetProgress(taskID string) (<-chan *dto.ProgressEvent, []
 	tm.tasksLock.RUnlock()
 
 	if !exists {
+		// 多实例模式下从 Redis 订阅其他实例上任务的进度
+		if tm.registry != nil {
+			return tm.registry.Subscribe(taskID)
+		}
 		return nil, nil, nil, fmt.Errorf("任务不存在")
 	}
 
@@ -865,6 +1792,21 @@ func (tm *TaskManager) GetProgress(taskID string) (<-chan *dto.ProgressEvent, []
 	return subscriberChan, history, unsubscribe, nil
 }
 
+// DefaultHistoryLimit 进度 SSE 默认回放的历史事件条数
+func (tm *TaskManager) DefaultHistoryLimit() int {
+	return tm.cfg.Current().Server.SSEHistoryLimit
+}
+
+// GetEventLog 获取任务的完整事件历史（从任务日志读取，任务已不在内存中时同样可用）
+func (tm *TaskManager) GetEventLog(taskID string, userID uint) ([]*dto.ProgressEvent, error) {
+	taskCtx, err := tm.accessibleTaskLog(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	events, _, err := tm.readTaskLog(taskID, taskCtx, 0, 0)
+	return events, err
+}
+
 // DeleteTask 删除任务
 func (tm *TaskManager) DeleteTask(taskID string, userID uint) error {
 	tm.tasksLock.RLock()
@@ -875,11 +1817,11 @@ func (tm *TaskManager) DeleteTask(taskID string, userID uint) error {
 		return fmt.Errorf("任务不存在")
 	}
 
-	if taskCtx.UserID != userID {
+	if !tm.canAccessTask(taskCtx, userID, models.PermissionOperate) {
 		return fmt.Errorf("无权删除此任务")
 	}
 
-	if !taskCtx.Finished {
+	if !taskCtx.Finished() {
 		return fmt.Errorf("只能删除已完成的任务")
 	}
 
@@ -890,6 +1832,11 @@ func (tm *TaskManager) DeleteTask(taskID string, userID uint) error {
 
 	// 从数据库中删除
 	tm.taskRepo.DeleteByTaskID(taskID)
+	tm.removeTaskEventLog(taskID)
+	tm.removeTaskOutputLog(taskID)
+	if tm.registry != nil {
+		tm.registry.Remove(taskID)
+	}
 
 	return nil
 }
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"sync"
+	"testing"
+
+	"gen-go/internal/dto"
+)
+
+func TestAddEventPublishesInIDOrder(t *testing.T) {
+	var published []int64
+	tc := &TaskContext{TaskID: "task-1"}
+	// AddEvent 持锁调用 publish，这里不需要额外加锁
+	tc.publish = func(event *dto.ProgressEvent) {
+		published = append(published, event.ID)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				tc.AddEvent(&dto.ProgressEvent{Type: "progress"})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(published) != 1600 {
+		t.Fatalf("published %d events, want 1600", len(published))
+	}
+	for i, id := range published {
+		if id != int64(i+1) {
+			t.Fatalf("published[%d] = event %d, want %d", i, id, i+1)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// taskOutputLog 工作进程原始的标准输出和错误输出，每行加上时间和来源后追加写入日志文件
+// 与事件日志不同，这里保存的是未经解析的原始文本，用于排查失败的任务
+type taskOutputLog struct {
+	lock   sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// taskOutputLogPath 任务输出日志的文件路径
+func taskOutputLogPath(dir, taskID string) string {
+	return filepath.Join(dir, url.PathEscape(taskID)+".output.log")
+}
+
+// openTaskOutputLog 创建（或追加到）任务的输出日志文件
+func openTaskOutputLog(dir, taskID string) (*taskOutputLog, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建任务日志目录失败: %w", err)
+	}
+	file, err := os.OpenFile(taskOutputLogPath(dir, taskID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("创建任务输出日志失败: %w", err)
+	}
+	return &taskOutputLog{file: file, writer: bufio.NewWriter(file)}, nil
+}
+
+// WriteLine 写入一行输出，stream 为 stdout 或 stderr（日志为 nil 或已关闭时忽略）
+func (l *taskOutputLog) WriteLine(stream, line string) {
+	if l == nil {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.file == nil {
+		return
+	}
+	fmt.Fprintf(l.writer, "%s [%s] %s\n", time.Now().Format("2006-01-02T15:04:05.000Z07:00"), stream, line)
+}
+
+// Flush 将缓冲的输出写入文件（下载前调用）
+func (l *taskOutputLog) Flush() {
+	if l == nil {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.file == nil {
+		return
+	}
+	if err := l.writer.Flush(); err != nil {
+		log.Printf("[TaskLog] 写入任务输出日志失败: %v", err)
+	}
+}
+
+// Close 写入剩余输出并关闭文件
+func (l *taskOutputLog) Close() {
+	if l == nil {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.file == nil {
+		return
+	}
+	if err := l.writer.Flush(); err != nil {
+		log.Printf("[TaskLog] 写入任务输出日志失败: %v", err)
+	}
+	l.file.Close()
+	l.file = nil
+}
+
+// openOutputLog 为任务打开输出日志，失败时只记录日志（任务照常运行）
+func (tm *TaskManager) openOutputLog(taskCtx *TaskContext) {
+	outputLog, err := openTaskOutputLog(tm.cfg.Current().TaskLogDir(), taskCtx.TaskID)
+	if err != nil {
+		log.Printf("[TaskLog] 任务 %s: %v", taskCtx.TaskID, err)
+		return
+	}
+	taskCtx.outputLog = outputLog
+}
+
+// GetTaskOutputLogPath 获取任务原始输出日志的文件路径（用于下载完整的 stdout/stderr）
+func (tm *TaskManager) GetTaskOutputLogPath(taskID string, userID uint) (string, error) {
+	taskCtx, err := tm.accessibleTaskLog(taskID, userID)
+	if err != nil {
+		return "", err
+	}
+	if taskCtx != nil {
+		taskCtx.outputLog.Flush()
+	}
+
+	path := taskOutputLogPath(tm.cfg.Current().TaskLogDir(), taskID)
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("任务输出日志不存在")
+		}
+		return "", fmt.Errorf("读取任务输出日志失败: %w", err)
+	}
+	return path, nil
+}
+
+// removeTaskOutputLog 删除任务的输出日志文件
+func (tm *TaskManager) removeTaskOutputLog(taskID string) {
+	if err := os.Remove(taskOutputLogPath(tm.cfg.Current().TaskLogDir(), taskID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("[TaskLog] 删除任务 %s 的输出日志失败: %v", taskID, err)
+	}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gen-go/internal/dto"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// GetOverview 获取任务概览，合并数据库记录、内存中的任务上下文和 Redis 实时进度
+func (tm *TaskManager) GetOverview(taskID string, userID uint) (*dto.TaskOverviewResponse, error) {
+	task, dbErr := tm.taskRepo.GetVisibleByTaskID(taskID, userID)
+	taskCtx, inMemory := tm.GetTask(taskID)
+	if dbErr != nil && (!inMemory || taskCtx.UserID != userID) {
+		return nil, fmt.Errorf("任务不存在")
+	}
+
+	overview := &dto.TaskOverviewResponse{
+		TaskID:  taskID,
+		Sources: make([]string, 0, 3),
+	}
+
+	if dbErr == nil {
+		overview.Sources = append(overview.Sources, dto.TaskSourceDatabase)
+		overview.Status = string(task.Status)
+		overview.Finished = task.Status.IsTerminal()
+		overview.Params = task.Params
+		overview.Result = task.Result
+		overview.ErrorMessage = task.ErrorMessage
+		overview.StartedAt = dto.FormatTime(task.StartedAt)
+		overview.FinishedAt = dto.FormatTimePtr(task.FinishedAt)
+		overview.InputChars = task.InputChars
+		overview.OutputChars = task.OutputChars
+		overview.WorkerVersion = task.WorkerVersion
+		if task.FinishedAt != nil {
+			overview.RunTime = task.FinishedAt.Sub(task.StartedAt).Seconds()
+		} else {
+			overview.RunTime = time.Since(task.StartedAt).Seconds()
+		}
+	}
+
+	// 内存中的任务上下文反映当前进程的实时状态（数据库记录在任务结束时才更新）
+	if inMemory {
+		state := taskCtx.State()
+		overview.Sources = append(overview.Sources, dto.TaskSourceMemory)
+		overview.Finished = state.Finished
+		overview.ReturnCode = state.ReturnCode
+		if !state.Finished || dbErr != nil {
+			overview.Status = string(state.Status)
+		}
+		if overview.Params == nil {
+			overview.Params = taskCtx.Params
+		}
+		if dbErr != nil {
+			overview.StartedAt = dto.FormatTime(taskCtx.StartTime)
+			overview.FinishedAt = dto.FormatTimePtr(state.EndTime)
+		}
+		overview.RunTime = state.RunTime(taskCtx.StartTime).Seconds()
+	}
+
+	progress, err := tm.LoadStoredProgress(context.Background(), taskID)
+	if err == nil && progress != nil {
+		overview.Sources = append(overview.Sources, dto.TaskSourceRedis)
+		overview.Live = &dto.TaskLiveProgress{
+			CurrentRound:    progressInt(progress, "current_round"),
+			TotalRounds:     progressInt(progress, "total_rounds"),
+			TotalSamples:    progressInt(progress, "total_samples"),
+			GeneratedCount:  progressInt(progress, "generated_count"),
+			ProgressPercent: progress["progress_percent"].(float64),
+			InputChars:      progressInt(progress, "input_chars"),
+			OutputChars:     progressInt(progress, "output_chars"),
+		}
+		overview.Live.RoundStatus, _ = progress["round_status"].(string)
+
+		// 运行中的任务字符数只在 Redis 中累计
+		if !overview.Finished {
+			overview.InputChars = overview.Live.InputChars
+			overview.OutputChars = overview.Live.OutputChars
+		}
+	}
+
+	counts, err := tm.generatedDataRepo.CountByTaskIDs([]string{taskID})
+	if err == nil {
+		overview.DataCount = counts[taskID].DataCount
+		overview.ConfirmedCount = counts[taskID].ConfirmedCount
+	}
+
+	return overview, nil
+}
+
+// LoadStoredProgress 读取工作进程写入 Redis 的任务进度，并计算 progress_percent
+// 支持 Hash（当前版本）和 JSON 字符串（旧版本）两种格式，没有进度数据时返回 nil
+func (tm *TaskManager) LoadStoredProgress(ctx context.Context, taskID string) (map[string]interface{}, error) {
+	if tm.redisClient == nil {
+		return nil, nil
+	}
+	redisKey := "task_progress:" + taskID
+
+	var progressData map[string]interface{}
+	hashData, hashErr := tm.redisClient.HGetAll(ctx, redisKey).Result()
+	if hashErr == nil && len(hashData) > 0 {
+		progressData = make(map[string]interface{}, len(hashData)+2)
+		for key, val := range hashData {
+			// 尝试解析为JSON值，其次为整数，否则作为字符串
+			var jsonVal interface{}
+			if err := json.Unmarshal([]byte(val), &jsonVal); err == nil {
+				progressData[key] = jsonVal
+			} else if intVal, err := strconv.ParseInt(val, 10, 64); err == nil {
+				progressData[key] = intVal
+			} else {
+				progressData[key] = val
+			}
+		}
+	} else {
+		val, err := tm.redisClient.Get(ctx, redisKey).Result()
+		if err == redis.Nil {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取进度失败: %w", err)
+		}
+		if err := json.Unmarshal([]byte(val), &progressData); err != nil {
+			return nil, fmt.Errorf("解析进度数据失败: %w", err)
+		}
+	}
+
+	if _, ok := progressData["task_id"]; !ok {
+		progressData["task_id"] = taskID
+	}
+
+	// 优先使用 Python 计算的 completion_percent 字段（基于轮次完成比例），否则按轮次计算
+	progressPercent := 0.0
+	if cp, ok := progressData["completion_percent"].(float64); ok {
+		progressPercent = cp
+	} else if totalRounds, ok := progressData["total_rounds"].(float64); ok && totalRounds > 0 {
+		if currentRound, ok := progressData["current_round"].(float64); ok {
+			progressPercent = (currentRound / totalRounds) * 100
+		}
+	}
+	if progressPercent > 100 {
+		progressPercent = 100
+	}
+
+	progressData["progress_percent"] = progressPercent
+	progressData["source"] = dto.TaskSourceRedis
+	return progressData, nil
+}
+
+// progressInt 读取进度中的整数字段（JSON 解析得到 float64，Hash 中的整数字符串解析为 int64）
+func progressInt(progress map[string]interface{}, key string) int64 {
+	switch value := progress[key].(type) {
+	case float64:
+		return int64(value)
+	case int64:
+		return value
+	}
+	return 0
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/plugin"
+	"gen-go/internal/utils"
+)
+
+// postprocessBatchSize 生成数据后处理插件每次处理的条数
+const postprocessBatchSize = 200
+
+// preprocessInput 依次执行对任务类型生效的输入样本预处理插件，结果保存为快照文件供工作进程读取
+// 没有生效的插件时返回原文件；返回执行的插件名称
+func (tm *TaskManager) preprocessInput(ctx context.Context, file *models.DataFile, task plugin.TaskInfo) (*models.DataFile, []string, error) {
+	preprocessors := plugin.Default().Preprocessors(task.TaskType)
+	if len(preprocessors) == 0 {
+		return file, nil, nil
+	}
+
+	var samples []map[string]interface{}
+	err := utils.ScanJSONL(bytes.NewReader(file.FileContent), func(index int, item map[string]interface{}) error {
+		samples = append(samples, item)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("解析输入文件失败: %w", err)
+	}
+
+	names := make([]string, 0, len(preprocessors))
+	for _, p := range preprocessors {
+		samples, err = p.Preprocess(ctx, task, samples)
+		if err != nil {
+			return nil, nil, fmt.Errorf("预处理插件 %s 失败: %w", p.Name(), err)
+		}
+		names = append(names, p.Name())
+	}
+	if len(samples) == 0 {
+		return nil, nil, fmt.Errorf("预处理后没有剩余样本")
+	}
+
+	var content bytes.Buffer
+	for _, sample := range samples {
+		line, err := json.Marshal(sample)
+		if err != nil {
+			return nil, nil, fmt.Errorf("序列化预处理结果失败: %w", err)
+		}
+		content.Write(line)
+		content.WriteByte('\n')
+	}
+
+	sourceID := file.ID
+	ext := filepath.Ext(file.Filename)
+	snapshot := &models.DataFile{
+		Filename:        strings.TrimSuffix(file.Filename, ext) + "@preprocessed" + ext,
+		FileContent:     content.Bytes(),
+		FileSize:        content.Len(),
+		ContentType:     "application/x-jsonlines",
+		UserID:          file.UserID,
+		WorkspaceID:     file.WorkspaceID,
+		ConvertedFromID: &sourceID,
+		Snapshot:        true,
+	}
+	if err := tm.fileRepo.Create(snapshot); err != nil {
+		return nil, nil, fmt.Errorf("保存预处理结果失败: %w", err)
+	}
+	log.Printf("[Plugin] 任务 %s 输入文件 %d 经 %v 预处理为快照文件 %d（%d 条样本）", task.TaskID, file.ID, names, snapshot.ID, len(samples))
+	return snapshot, names, nil
+}
+
+// runPostprocessPlugins 任务结束后依次执行对任务类型生效的生成数据后处理插件
+// 插件返回的数据按 ID 覆盖原内容，未返回的数据被删除；插件失败时保留数据不变
+func (tm *TaskManager) runPostprocessPlugins(taskCtx *TaskContext) {
+	taskType, _ := taskCtx.Params["task_type"].(string)
+	postprocessors := plugin.Default().Postprocessors(taskType)
+	if len(postprocessors) == 0 {
+		return
+	}
+
+	task := plugin.TaskInfo{TaskID: taskCtx.TaskID, TaskType: taskType, UserID: taskCtx.UserID}
+	updated, dropped, err := tm.postprocessItems(task, postprocessors)
+	if err != nil {
+		log.Printf("[Plugin] 任务 %s 生成数据后处理失败: %v", taskCtx.TaskID, err)
+		taskCtx.AddEvent(&dto.ProgressEvent{
+			Type:    "error",
+			Line:    fmt.Sprintf("生成数据后处理失败: %v", err),
+			Message: "错误",
+		})
+		return
+	}
+
+	taskCtx.AddEvent(&dto.ProgressEvent{
+		Type:    "output",
+		Line:    fmt.Sprintf("生成数据后处理完成: 修改 %d 条, 删除 %d 条", updated, dropped),
+		Message: "生成数据后处理完成",
+	})
+}
+
+// postprocessItems 分批调用后处理插件，全部批次成功后再写入修改和删除，返回修改和删除的条数
+func (tm *TaskManager) postprocessItems(task plugin.TaskInfo, postprocessors []plugin.ItemPostprocessor) (int, int, error) {
+	contents := make(map[uint]string)
+	var dropped []uint
+
+	err := tm.generatedDataRepo.ScanForDedup(task.TaskID, postprocessBatchSize, func(batch []models.GeneratedData) error {
+		items := make([]plugin.Item, 0, len(batch))
+		original := make(map[uint]string, len(batch))
+		for _, data := range batch {
+			var content map[string]interface{}
+			if err := json.Unmarshal([]byte(data.DataContent), &content); err != nil {
+				continue
+			}
+			items = append(items, plugin.Item{ID: data.ID, Data: content})
+			original[data.ID] = data.DataContent
+		}
+
+		var err error
+		for _, p := range postprocessors {
+			items, err = p.Postprocess(context.Background(), task, items)
+			if err != nil {
+				return fmt.Errorf("后处理插件 %s 失败: %w", p.Name(), err)
+			}
+		}
+
+		kept := make(map[uint]bool, len(items))
+		for _, item := range items {
+			before, ok := original[item.ID]
+			if !ok || item.Data == nil {
+				continue
+			}
+			kept[item.ID] = true
+			raw, err := json.Marshal(item.Data)
+			if err != nil {
+				return fmt.Errorf("序列化后处理结果失败: %w", err)
+			}
+			// 按解码后的内容比较（插件可能原地修改传入的数据，序列化也会改变键的顺序）
+			var previous, current interface{}
+			_ = json.Unmarshal([]byte(before), &previous)
+			_ = json.Unmarshal(raw, &current)
+			if !reflect.DeepEqual(previous, current) {
+				contents[item.ID] = string(raw)
+			}
+		}
+		for id := range original {
+			if !kept[id] {
+				dropped = append(dropped, id)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := tm.generatedDataRepo.UpdateContents(contents); err != nil {
+		return 0, 0, fmt.Errorf("保存后处理结果失败: %w", err)
+	}
+	if len(dropped) > 0 {
+		if _, err := tm.generatedDataRepo.DeleteByIDs(dropped); err != nil {
+			return 0, 0, fmt.Errorf("删除数据失败: %w", err)
+		}
+	}
+	return len(contents), len(dropped), nil
+}
@@ -0,0 +1,621 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Redis 键前缀（多实例模式）
+const (
+	taskRegistryKeyPrefix      = "task_registry:"        // 任务状态（哈希：node、user_id、status、model_paths、started_at）
+	taskRegistryRunningKey     = "task_registry_running" // 运行中任务ID集合
+	taskEventsKeyPrefix        = "task_events:"          // 最近的进度事件（列表，元素为 registryEvent）
+	taskEventSeqKeyPrefix      = "task_event_seq:"       // 进度事件序号
+	taskEventsChannelPrefix    = "task_events_ch:"       // 进度事件发布频道
+	taskNodeKeyPrefix          = "task_node:"            // 实例心跳
+	taskControlChannelPrefix   = "task_control:"         // 发往实例的控制消息频道
+	taskControlReplyKeyPrefix  = "task_control_reply:"   // 控制消息的处理结果
+	taskRegistryRunningTTL     = 24 * time.Hour          // 运行中任务的键在最后一次事件后的保留时间
+	taskControlTimeout         = 10 * time.Second
+	taskRegistryQueueSize      = 1000
+	taskControlActionStop      = "stop"
+	taskRegistryStatusRunning  = string(models.TaskStatusRunning)
+	taskRegistryPublishTimeout = 5 * time.Second
+	taskRegistryEnqueueTimeout = 2 * time.Second // 写入队列满时进度事件最多等待的时间
+)
+
+// publishEventScript 为事件分配序号，写入事件列表并发布
+// KEYS[1] 事件列表，KEYS[2] 序号，KEYS[3] 任务状态
+// ARGV: 事件JSON, 保留条数, 过期秒数, 发布频道
+var publishEventScript = redis.NewScript(`
+local seq = redis.call('INCR', KEYS[2])
+local payload = '{"seq":' .. seq .. ',"event":' .. ARGV[1] .. '}'
+redis.call('RPUSH', KEYS[1], payload)
+redis.call('LTRIM', KEYS[1], -tonumber(ARGV[2]), -1)
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+redis.call('EXPIRE', KEYS[2], ARGV[3])
+redis.call('EXPIRE', KEYS[3], ARGV[3])
+redis.call('PUBLISH', ARGV[4], payload)
+return seq
+`)
+
+// registryEvent Redis 中带序号的进度事件
+// Closed 为 true 的消息由 MarkFinished 发布，不带事件，只通知订阅者任务已结束
+type registryEvent struct {
+	Seq    int64              `json:"seq"`
+	Event  *dto.ProgressEvent `json:"event,omitempty"`
+	Closed bool               `json:"closed,omitempty"`
+}
+
+// RegisteredTask 注册表中的任务状态
+type RegisteredTask struct {
+	TaskID     string
+	NodeID     string
+	UserID     uint
+	Status     string
+	ModelPaths []string
+}
+
+// Finished 任务是否已结束
+func (t *RegisteredTask) Finished() bool {
+	return t.Status != taskRegistryStatusRunning
+}
+
+// taskControlMessage 发往任务所在实例的控制消息
+type taskControlMessage struct {
+	RequestID string `json:"request_id"`
+	Action    string `json:"action"`
+	TaskID    string `json:"task_id"`
+	UserID    uint   `json:"user_id"`
+}
+
+// taskControlReply 控制消息的处理结果
+type taskControlReply struct {
+	Error string `json:"error,omitempty"`
+}
+
+// TaskControlHandler 处理其他实例发来的控制消息（如停止任务）
+type TaskControlHandler func(action, taskID string, userID uint) error
+
+// TaskRegistry 多实例模式下的任务注册表
+// 任务状态和进度事件写入 Redis，任意实例都可以订阅进度、查询任务所在实例；
+// 工作进程只运行在启动任务的实例上，停止任务等操作通过控制频道转发给该实例
+type TaskRegistry struct {
+	redisClient *redis.Client
+	cfg         *config.Config
+	nodeID      string
+
+	// 按顺序异步写入 Redis 的操作（事件发布不阻塞工作进程输出的处理）
+	queue    chan func(ctx context.Context) error
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewTaskRegistry 创建任务注册表
+func NewTaskRegistry(redisClient *redis.Client, cfg *config.Config) *TaskRegistry {
+	return &TaskRegistry{
+		redisClient: redisClient,
+		cfg:         cfg,
+		nodeID:      cfg.Cluster.NodeID,
+		queue:       make(chan func(ctx context.Context) error, taskRegistryQueueSize),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// NodeID 当前实例标识
+func (r *TaskRegistry) NodeID() string {
+	return r.nodeID
+}
+
+// Start 启动心跳、异步写入和控制消息处理
+func (r *TaskRegistry) Start(handler TaskControlHandler) {
+	log.Printf("[TaskRegistry] 多实例模式已开启，实例: %s", r.nodeID)
+	r.heartbeat()
+	go r.heartbeatLoop()
+	go r.writeLoop()
+	go r.controlLoop(handler)
+}
+
+// Stop 停止后台循环并删除实例心跳
+func (r *TaskRegistry) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+		ctx, cancel := context.WithTimeout(context.Background(), taskRegistryPublishTimeout)
+		defer cancel()
+		r.redisClient.Del(ctx, taskNodeKeyPrefix+r.nodeID)
+	})
+}
+
+// heartbeat 更新实例心跳，超过 3 个心跳间隔未更新视为实例已下线
+func (r *TaskRegistry) heartbeat() {
+	ctx, cancel := context.WithTimeout(context.Background(), taskRegistryPublishTimeout)
+	defer cancel()
+	interval := r.cfg.Current().Cluster.GetHeartbeatInterval()
+	if err := r.redisClient.Set(ctx, taskNodeKeyPrefix+r.nodeID, time.Now().Unix(), 3*interval).Err(); err != nil {
+		log.Printf("[TaskRegistry] 更新实例心跳失败: %v", err)
+	}
+}
+
+func (r *TaskRegistry) heartbeatLoop() {
+	ticker := time.NewTicker(r.cfg.Current().Cluster.GetHeartbeatInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.heartbeat()
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+// writeLoop 按提交顺序执行 Redis 写入，保证同一任务的事件和结束状态有序
+// 停止时执行完队列中剩余的写入再退出
+func (r *TaskRegistry) writeLoop() {
+	for {
+		select {
+		case op := <-r.queue:
+			r.runWrite(op)
+		case <-r.stopCh:
+			for {
+				select {
+				case op := <-r.queue:
+					r.runWrite(op)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// runWrite 执行一次 Redis 写入
+func (r *TaskRegistry) runWrite(op func(ctx context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), taskRegistryPublishTimeout)
+	defer cancel()
+	if err := op(ctx); err != nil {
+		log.Printf("[TaskRegistry] 写入 Redis 失败: %v", err)
+	}
+}
+
+// enqueue 提交异步写入，队列满时最多等待 taskRegistryEnqueueTimeout，仍未提交时丢弃并记录日志
+// 只用于进度事件，丢失个别事件不影响任务状态
+func (r *TaskRegistry) enqueue(op func(ctx context.Context) error) {
+	select {
+	case r.queue <- op:
+		return
+	default:
+	}
+
+	timer := time.NewTimer(taskRegistryEnqueueTimeout)
+	defer timer.Stop()
+	select {
+	case r.queue <- op:
+	case <-timer.C:
+		log.Printf("[TaskRegistry] 写入队列已满，丢弃一次进度事件")
+	case <-r.stopCh:
+		log.Printf("[TaskRegistry] 注册表已停止，丢弃一次进度事件")
+	}
+}
+
+// enqueueRequired 提交不能丢弃的写入（任务结束状态、删除任务）：队列满时一直等待写入协程腾出位置，
+// 保证仍排在之前的进度事件之后；注册表已停止时同步执行
+func (r *TaskRegistry) enqueueRequired(op func(ctx context.Context) error) {
+	select {
+	case <-r.stopCh:
+		r.runWrite(op)
+		return
+	default:
+	}
+
+	select {
+	case r.queue <- op:
+	case <-r.stopCh:
+		r.runWrite(op)
+	}
+}
+
+// Register 登记当前实例上启动的任务
+func (r *TaskRegistry) Register(taskCtx *TaskContext) error {
+	modelPaths := []string{taskCtx.ModelPath}
+	for _, model := range taskCtx.EnsembleModels {
+		modelPaths = append(modelPaths, model.ModelPath)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), taskRegistryPublishTimeout)
+	defer cancel()
+
+	key := taskRegistryKeyPrefix + taskCtx.TaskID
+	pipe := r.redisClient.TxPipeline()
+	pipe.Del(ctx, key, taskEventsKeyPrefix+taskCtx.TaskID, taskEventSeqKeyPrefix+taskCtx.TaskID)
+	pipe.HSet(ctx, key,
+		"node", r.nodeID,
+		"user_id", taskCtx.UserID,
+		"status", taskRegistryStatusRunning,
+		"model_paths", strings.Join(modelPaths, ","),
+		"started_at", taskCtx.StartTime.Unix(),
+	)
+	pipe.Expire(ctx, key, taskRegistryRunningTTL)
+	pipe.SAdd(ctx, taskRegistryRunningKey, taskCtx.TaskID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("登记任务失败: %w", err)
+	}
+	return nil
+}
+
+// Publish 异步发布任务的进度事件
+func (r *TaskRegistry) Publish(taskID string, event *dto.ProgressEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	r.enqueue(func(ctx context.Context) error {
+		return publishEventScript.Run(ctx, r.redisClient,
+			[]string{taskEventsKeyPrefix + taskID, taskEventSeqKeyPrefix + taskID, taskRegistryKeyPrefix + taskID},
+			string(data), r.cfg.Current().Cluster.EventHistoryLimit, int(taskRegistryRunningTTL.Seconds()), taskEventsChannelPrefix+taskID,
+		).Err()
+	})
+}
+
+// MarkFinished 记录任务已结束，任务状态和事件在 finished_ttl_minutes 后过期，并通知订阅者结束订阅
+// 与 Publish 使用同一个写入队列，保证在最后一个事件之后执行；队列满时等待，不会丢弃
+func (r *TaskRegistry) MarkFinished(taskID, status string) {
+	r.enqueueRequired(func(ctx context.Context) error {
+		return r.markFinished(ctx, taskID, status)
+	})
+}
+
+func (r *TaskRegistry) markFinished(ctx context.Context, taskID, status string) error {
+	// 未登记的任务（如停止已下线实例上的任务时）不写入，避免留下没有实例信息的状态
+	if n, err := r.redisClient.Exists(ctx, taskRegistryKeyPrefix+taskID).Result(); err != nil || n == 0 {
+		return err
+	}
+
+	// 结束消息与状态在同一个事务中写入：订阅时读到运行中状态的订阅者一定能收到结束消息
+	closed, _ := json.Marshal(&registryEvent{Closed: true})
+	ttl := r.cfg.Current().Cluster.GetFinishedTTL()
+	pipe := r.redisClient.TxPipeline()
+	pipe.HSet(ctx, taskRegistryKeyPrefix+taskID, "status", status)
+	pipe.SRem(ctx, taskRegistryRunningKey, taskID)
+	pipe.Expire(ctx, taskRegistryKeyPrefix+taskID, ttl)
+	pipe.Expire(ctx, taskEventsKeyPrefix+taskID, ttl)
+	pipe.Expire(ctx, taskEventSeqKeyPrefix+taskID, ttl)
+	pipe.Publish(ctx, taskEventsChannelPrefix+taskID, closed)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Remove 删除任务的注册信息和事件
+func (r *TaskRegistry) Remove(taskID string) {
+	r.enqueueRequired(func(ctx context.Context) error {
+		pipe := r.redisClient.TxPipeline()
+		pipe.Del(ctx, taskRegistryKeyPrefix+taskID, taskEventsKeyPrefix+taskID, taskEventSeqKeyPrefix+taskID)
+		pipe.SRem(ctx, taskRegistryRunningKey, taskID)
+		_, err := pipe.Exec(ctx)
+		return err
+	})
+}
+
+// Lookup 查询任务的注册信息，任务未登记时返回 nil
+func (r *TaskRegistry) Lookup(ctx context.Context, taskID string) (*RegisteredTask, error) {
+	values, err := r.redisClient.HGetAll(ctx, taskRegistryKeyPrefix+taskID).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	userID, _ := strconv.ParseUint(values["user_id"], 10, 64)
+	task := &RegisteredTask{
+		TaskID: taskID,
+		NodeID: values["node"],
+		UserID: uint(userID),
+		Status: values["status"],
+	}
+	if values["model_paths"] != "" {
+		task.ModelPaths = strings.Split(values["model_paths"], ",")
+	}
+	return task, nil
+}
+
+// NodeAlive 判断实例是否在线（心跳未过期）
+func (r *TaskRegistry) NodeAlive(ctx context.Context, nodeID string) bool {
+	n, err := r.redisClient.Exists(ctx, taskNodeKeyPrefix+nodeID).Result()
+	return err == nil && n > 0
+}
+
+// Subscribe 订阅其他实例上任务的进度事件，返回事件通道、Redis 中保留的历史事件和取消订阅的函数
+// 先订阅频道再读取历史，序号不大于历史中最后一个事件的消息会被丢弃，避免遗漏或重复；
+// 任务已结束时只返回历史，事件通道直接关闭，任务结束后订阅者收到 MarkFinished 的结束消息后关闭通道
+func (r *TaskRegistry) Subscribe(taskID string) (<-chan *dto.ProgressEvent, []*dto.ProgressEvent, func(), error) {
+	ctx := context.Background()
+	task, err := r.Lookup(ctx, taskID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("查询任务失败: %w", err)
+	}
+	if task == nil {
+		return nil, nil, nil, fmt.Errorf("任务不存在")
+	}
+	if !task.Finished() && !r.NodeAlive(ctx, task.NodeID) {
+		return nil, nil, nil, fmt.Errorf("任务所在实例 %s 已下线", task.NodeID)
+	}
+
+	pubsub := r.redisClient.Subscribe(ctx, taskEventsChannelPrefix+taskID)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, nil, nil, fmt.Errorf("订阅任务进度失败: %w", err)
+	}
+
+	items, err := r.redisClient.LRange(ctx, taskEventsKeyPrefix+taskID, 0, -1).Result()
+	if err != nil {
+		pubsub.Close()
+		return nil, nil, nil, fmt.Errorf("读取任务进度失败: %w", err)
+	}
+	var lastSeq int64
+	history := make([]*dto.ProgressEvent, 0, len(items))
+	for _, item := range items {
+		var stored registryEvent
+		if json.Unmarshal([]byte(item), &stored) != nil || stored.Event == nil {
+			continue
+		}
+		history = append(history, stored.Event)
+		lastSeq = stored.Seq
+	}
+
+	events := make(chan *dto.ProgressEvent, 200)
+
+	// 订阅频道后再确认一次状态：任务在第一次查询后结束时，结束消息可能已在订阅前发布
+	if task, err = r.Lookup(ctx, taskID); err != nil || task == nil || task.Finished() {
+		pubsub.Close()
+		close(events)
+		return events, history, func() {}, nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(events)
+		for msg := range pubsub.Channel() {
+			var stored registryEvent
+			if json.Unmarshal([]byte(msg.Payload), &stored) != nil {
+				continue
+			}
+			if stored.Closed {
+				return
+			}
+			if stored.Event == nil || stored.Seq <= lastSeq {
+				continue
+			}
+			select {
+			case events <- stored.Event:
+			case <-done:
+				return
+			}
+			if stored.Event.Type == "finished" {
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			close(done)
+			pubsub.Close()
+		})
+	}
+	return events, history, unsubscribe, nil
+}
+
+// SendControl 向任务所在实例发送控制消息并等待处理结果
+func (r *TaskRegistry) SendControl(ctx context.Context, nodeID, action, taskID string, userID uint) error {
+	requestID, err := newControlRequestID()
+	if err != nil {
+		return err
+	}
+	data, _ := json.Marshal(&taskControlMessage{RequestID: requestID, Action: action, TaskID: taskID, UserID: userID})
+
+	receivers, err := r.redisClient.Publish(ctx, taskControlChannelPrefix+nodeID, data).Result()
+	if err != nil {
+		return fmt.Errorf("发送控制消息失败: %w", err)
+	}
+	if receivers == 0 {
+		return fmt.Errorf("任务所在实例 %s 不在线", nodeID)
+	}
+
+	result, err := r.redisClient.BLPop(ctx, taskControlTimeout, taskControlReplyKeyPrefix+requestID).Result()
+	if errors.Is(err, redis.Nil) {
+		return fmt.Errorf("等待实例 %s 响应超时", nodeID)
+	}
+	if err != nil {
+		return fmt.Errorf("等待实例 %s 响应失败: %w", nodeID, err)
+	}
+
+	var reply taskControlReply
+	if err := json.Unmarshal([]byte(result[1]), &reply); err != nil {
+		return fmt.Errorf("解析实例 %s 的响应失败: %w", nodeID, err)
+	}
+	if reply.Error != "" {
+		return errors.New(reply.Error)
+	}
+	return nil
+}
+
+// controlLoop 处理发往当前实例的控制消息，结果写入请求对应的回复列表
+func (r *TaskRegistry) controlLoop(handler TaskControlHandler) {
+	pubsub := r.redisClient.Subscribe(context.Background(), taskControlChannelPrefix+r.nodeID)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var control taskControlMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &control); err != nil {
+				log.Printf("[TaskRegistry] 无效的控制消息: %v", err)
+				continue
+			}
+			go r.handleControl(handler, &control)
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+func (r *TaskRegistry) handleControl(handler TaskControlHandler, control *taskControlMessage) {
+	log.Printf("[TaskRegistry] 收到控制消息: %s %s（用户 %d）", control.Action, control.TaskID, control.UserID)
+
+	var reply taskControlReply
+	if err := handler(control.Action, control.TaskID, control.UserID); err != nil {
+		reply.Error = err.Error()
+	}
+	data, _ := json.Marshal(&reply)
+
+	ctx, cancel := context.WithTimeout(context.Background(), taskRegistryPublishTimeout)
+	defer cancel()
+	key := taskControlReplyKeyPrefix + control.RequestID
+	pipe := r.redisClient.TxPipeline()
+	pipe.RPush(ctx, key, data)
+	pipe.Expire(ctx, key, 2*taskControlTimeout)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("[TaskRegistry] 写入控制消息结果失败: %v", err)
+	}
+}
+
+// ActiveModelPaths 在线实例上运行中任务使用的模型（供清理残留的模型令牌键）
+func (r *TaskRegistry) ActiveModelPaths(ctx context.Context) (map[string]bool, error) {
+	taskIDs, err := r.redisClient.SMembers(ctx, taskRegistryRunningKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool)
+	alive := make(map[string]bool)
+	for _, taskID := range taskIDs {
+		task, err := r.Lookup(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+		if task == nil || task.Finished() {
+			continue
+		}
+		nodeAlive, checked := alive[task.NodeID]
+		if !checked {
+			nodeAlive = r.NodeAlive(ctx, task.NodeID)
+			alive[task.NodeID] = nodeAlive
+		}
+		if !nodeAlive {
+			continue
+		}
+		for _, path := range task.ModelPaths {
+			paths[path] = true
+		}
+	}
+	return paths, nil
+}
+
+// newControlRequestID 生成控制消息的请求ID
+func newControlRequestID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// UseRegistry 开启多实例模式：任务登记到 Redis 并发布进度事件，处理其他实例转发的停止请求
+func (tm *TaskManager) UseRegistry(registry *TaskRegistry) {
+	tm.registry = registry
+	registry.Start(tm.handleControl)
+}
+
+// registerTask 多实例模式下登记任务，登记成功后进度事件同步发布到 Redis
+func (tm *TaskManager) registerTask(taskCtx *TaskContext) {
+	if tm.registry == nil {
+		return
+	}
+	if err := tm.registry.Register(taskCtx); err != nil {
+		log.Printf("[StartTask] %v，其他实例将无法查看任务 %s 的进度", err, taskCtx.TaskID)
+		return
+	}
+	taskID := taskCtx.TaskID
+	taskCtx.publish = func(event *dto.ProgressEvent) {
+		tm.registry.Publish(taskID, event)
+	}
+}
+
+// markTaskFinished 多实例模式下记录任务结束，status 为空时使用任务上下文中的状态
+func (tm *TaskManager) markTaskFinished(taskCtx *TaskContext, status models.TaskStatus) {
+	if tm.registry == nil || taskCtx.publish == nil {
+		return
+	}
+	if status == "" {
+		status = taskCtx.Status()
+	}
+	if status == models.TaskStatusStopping {
+		taskCtx.waitStopped()
+		status = models.TaskStatusStopped
+	}
+	if status == models.TaskStatusRunning {
+		status = models.TaskStatusError
+	}
+	tm.registry.MarkFinished(taskCtx.TaskID, string(status))
+}
+
+// stopRemoteTask 任务运行在其他在线实例上时转发停止请求，返回是否已转发
+// 任务所在实例已下线时不转发，由调用方按数据库状态停止
+func (tm *TaskManager) stopRemoteTask(taskID string, userID uint) (bool, error) {
+	if tm.registry == nil {
+		return false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), taskControlTimeout+taskRegistryPublishTimeout)
+	defer cancel()
+	task, err := tm.registry.Lookup(ctx, taskID)
+	if err != nil {
+		log.Printf("[StopTask] 查询任务 %s 的注册信息失败: %v", taskID, err)
+		return false, nil
+	}
+	if task == nil || task.Finished() || task.NodeID == tm.registry.NodeID() {
+		return false, nil
+	}
+	if !tm.registry.NodeAlive(ctx, task.NodeID) {
+		log.Printf("[StopTask] 任务 %s 所在实例 %s 已下线", taskID, task.NodeID)
+		return false, nil
+	}
+
+	log.Printf("[StopTask] 任务 %s 运行在实例 %s 上，转发停止请求", taskID, task.NodeID)
+	return true, tm.registry.SendControl(ctx, task.NodeID, taskControlActionStop, taskID, userID)
+}
+
+// handleControl 处理其他实例转发的控制消息
+func (tm *TaskManager) handleControl(action, taskID string, userID uint) error {
+	switch action {
+	case taskControlActionStop:
+		if _, exists := tm.GetTask(taskID); !exists {
+			return fmt.Errorf("任务不在实例 %s 上运行", tm.registry.NodeID())
+		}
+		return tm.StopTask(taskID, userID)
+	default:
+		return fmt.Errorf("不支持的控制操作: %s", action)
+	}
+}
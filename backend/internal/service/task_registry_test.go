@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestTaskRegistryMarkFinishedNotDropped(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	registry := NewTaskRegistry(client, &config.Config{Cluster: config.ClusterConfig{NodeID: "node-a", FinishedTTLMinutes: 10}})
+	if err := registry.Register(&TaskContext{TaskID: "task-1", UserID: 1, StartTime: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 写入协程未启动，先把队列填满
+	for len(registry.queue) < cap(registry.queue) {
+		registry.queue <- func(ctx context.Context) error { return nil }
+	}
+
+	done := make(chan struct{})
+	go func() {
+		registry.MarkFinished("task-1", "finished")
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("MarkFinished returned while the queue was full, the write was dropped")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	go registry.writeLoop()
+	defer registry.Stop()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("MarkFinished was not queued after the writer drained the queue")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if status, _ := client.HGet(context.Background(), taskRegistryKeyPrefix+"task-1", "status").Result(); status == "finished" {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("task status was not marked finished")
+}
+
+func TestTaskRegistryMarkFinishedAfterStop(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	registry := NewTaskRegistry(client, &config.Config{Cluster: config.ClusterConfig{NodeID: "node-a", FinishedTTLMinutes: 10}})
+	if err := registry.Register(&TaskContext{TaskID: "task-1", UserID: 1, StartTime: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	registry.Stop()
+
+	registry.MarkFinished("task-1", "stopped")
+	if status, _ := client.HGet(context.Background(), taskRegistryKeyPrefix+"task-1", "status").Result(); status != "stopped" {
+		t.Fatalf("status after stop = %q, want stopped", status)
+	}
+}
+
+func TestTaskRegistrySubscribeClosesWhenFinished(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	registry := NewTaskRegistry(client, &config.Config{Cluster: config.ClusterConfig{NodeID: "node-a", FinishedTTLMinutes: 10}})
+	if err := registry.Register(&TaskContext{TaskID: "task-1", UserID: 1, StartTime: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	// 写入协程未启动，MarkFinished 直接写入；Stop 会删除心跳，之后再模拟任务所在实例在线
+	registry.Stop()
+	if err := client.Set(context.Background(), taskNodeKeyPrefix+"node-a", "1", time.Minute).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	waitClosed := func(events <-chan *dto.ProgressEvent) {
+		t.Helper()
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case _, ok := <-events:
+				if !ok {
+					return
+				}
+			case <-timeout:
+				t.Fatal("event channel was not closed after the task finished")
+			}
+		}
+	}
+
+	// 订阅运行中的任务，任务结束（没有 finished 事件，如停止已下线实例上的任务）后通道关闭
+	events, _, unsubscribe, err := registry.Subscribe("task-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unsubscribe()
+	registry.MarkFinished("task-1", "stopped")
+	waitClosed(events)
+
+	// 订阅已结束的任务，通道直接关闭
+	events, _, unsubscribe, err = registry.Subscribe("task-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unsubscribe()
+	waitClosed(events)
+}
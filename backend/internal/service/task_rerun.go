@@ -0,0 +1,129 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+)
+
+// storedTaskParams 任务记录中保存的启动参数（与 StartTask 写入 Task.Params 的字段对应）
+type storedTaskParams struct {
+	FileID             uint                   `json:"file_id"`
+	SourceFileID       uint                   `json:"source_file_id"`
+	FileVersion        int                    `json:"file_version"`
+	TaskType           string                 `json:"task_type"`
+	BatchSize          int                    `json:"batch_size"`
+	MaxConcurrent      int                    `json:"max_concurrent"`
+	MinScore           int                    `json:"min_score"`
+	VariantsPerSample  int                    `json:"variants_per_sample"`
+	DataRounds         int                    `json:"data_rounds"`
+	RetryTimes         int                    `json:"retry_times"`
+	SpecialPrompt      string                 `json:"special_prompt"`
+	Directions         string                 `json:"directions"`
+	ModelID            *uint                  `json:"model_id"`
+	ModelIDs           []uint                 `json:"model_ids"`
+	ModelPath          string                 `json:"model_path"`
+	APIServices        []string               `json:"api_services"`
+	DedupAgainstSource bool                   `json:"dedup_against_source"`
+	MaxSimilarity      float64                `json:"max_similarity"`
+	GlossaryID         *uint                  `json:"glossary_id"`
+	GlossaryCheck      bool                   `json:"glossary_check"`
+	SafetyCheck        bool                   `json:"safety_check"`
+	JudgeModelID       *uint                  `json:"judge_model_id"`
+	RequiredReviews    int                    `json:"required_reviews"`
+	MaxRuntimeMinutes  int                    `json:"max_runtime_minutes"`
+	DisableModelCache  bool                   `json:"disable_model_cache"`
+	ExtraArgs          map[string]interface{} `json:"extra_args"`
+}
+
+// RerunTask 以已结束任务的参数重新启动一个新任务，可覆盖模型和轮数
+// 提示词使用原任务保存的内容（而不是提示词库的最新版本），以便复现原任务
+func (tm *TaskManager) RerunTask(userID uint, taskID string, req *dto.RerunTaskRequest) (*dto.StartTaskResponse, error) {
+	task, err := tm.taskRepo.GetEditableByTaskID(taskID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("任务不存在或无权访问")
+	}
+	if task.Status == models.TaskStatusRunning {
+		return nil, fmt.Errorf("任务仍在运行中，只能重新运行已结束的任务")
+	}
+
+	startReq, err := buildRerunRequest(task, req)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[RerunTask] 用户 %d 重新运行任务 %s", userID, taskID)
+	return tm.StartTask(userID, startReq)
+}
+
+// buildRerunRequest 由任务记录中保存的启动参数生成重新运行的请求，并应用覆盖参数
+func buildRerunRequest(task *models.Task, req *dto.RerunTaskRequest) (*dto.StartTaskRequest, error) {
+	raw, err := json.Marshal(task.Params)
+	if err != nil {
+		return nil, fmt.Errorf("解析任务参数失败: %w", err)
+	}
+	var params storedTaskParams
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, fmt.Errorf("解析任务参数失败: %w", err)
+	}
+	if params.FileID == 0 {
+		return nil, fmt.Errorf("任务参数中缺少输入文件")
+	}
+
+	// 原任务使用了历史版本的快照时，从原文件的同一版本重新生成输入
+	inputFileID := params.FileID
+	if params.SourceFileID != 0 {
+		inputFileID = params.SourceFileID
+	}
+
+	startReq := &dto.StartTaskRequest{
+		InputFile:          fmt.Sprintf("db://%d", inputFileID),
+		ModelID:            params.ModelID,
+		ModelIDs:           params.ModelIDs,
+		Model:              params.ModelPath,
+		BatchSize:          params.BatchSize,
+		MaxConcurrent:      params.MaxConcurrent,
+		MinScore:           params.MinScore,
+		TaskType:           params.TaskType,
+		VariantsPerSample:  params.VariantsPerSample,
+		DataRounds:         params.DataRounds,
+		RetryTimes:         params.RetryTimes,
+		SpecialPrompt:      params.SpecialPrompt,
+		Directions:         params.Directions,
+		DedupAgainstSource: params.DedupAgainstSource,
+		MaxSimilarity:      params.MaxSimilarity,
+		GlossaryID:         params.GlossaryID,
+		GlossaryCheck:      params.GlossaryCheck,
+		SafetyCheck:        params.SafetyCheck,
+		JudgeModelID:       params.JudgeModelID,
+		RequiredReviews:    params.RequiredReviews,
+		MaxRuntimeMinutes:  params.MaxRuntimeMinutes,
+		DisableModelCache:  params.DisableModelCache,
+		ExtraArgs:          params.ExtraArgs,
+		RerunOf:            task.TaskID,
+	}
+	if params.ModelID == nil && len(params.ModelIDs) == 0 {
+		startReq.Services = params.APIServices
+	}
+	if params.FileVersion > 0 {
+		fileVersion := params.FileVersion
+		startReq.FileVersion = &fileVersion
+	}
+
+	// 覆盖参数
+	if req.ModelID != nil {
+		startReq.ModelID = req.ModelID
+		startReq.ModelIDs = nil
+		startReq.Services = nil
+	}
+	if req.DataRounds != nil {
+		startReq.DataRounds = *req.DataRounds
+	}
+	if req.VariantsPerSample != nil {
+		startReq.VariantsPerSample = *req.VariantsPerSample
+	}
+	return startReq, nil
+}
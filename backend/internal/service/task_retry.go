@@ -0,0 +1,155 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+)
+
+// transientErrorClasses 可自动重试的瞬时错误类别
+var transientErrorClasses = map[string]bool{
+	ErrorClassTimeout:           true,
+	ErrorClassConnectionRefused: true,
+	ErrorClassDNS:               true,
+	ErrorClassRateLimited:       true,
+	ErrorClassUpstream5xx:       true,
+}
+
+// taskRetryPlan 任务因瞬时错误失败后的处理
+type taskRetryPlan struct {
+	root    string // 任务链第一次尝试的任务ID
+	attempt int    // 本次尝试的次数（从 1 开始）
+	class   string // 错误类别
+	message string // 判定错误类别所用的错误信息
+	retry   bool   // 是否自动重试（否则任务标记为 dead_letter）
+	backoff time.Duration
+}
+
+// retryChain 任务在自动重试链中的位置：第一次尝试的任务ID和本次尝试的次数
+func retryChain(taskCtx *TaskContext) (string, int) {
+	root, _ := taskCtx.Params["retry_root"].(string)
+	attempt := 0
+	switch value := taskCtx.Params["retry_attempt"].(type) {
+	case int:
+		attempt = value
+	case float64:
+		attempt = int(value)
+	}
+	if root == "" || attempt <= 1 {
+		return taskCtx.TaskID, 1
+	}
+	return root, attempt
+}
+
+// planRetry 判断失败的任务是否自动重试（worker.max_retries 大于 0 时）
+// 只处理瞬时错误（上游 5xx、超时、限流、连接失败），错误类别优先按工作进程最后一行错误输出判断；
+// 卡死、超过最长运行时间和握手失败由各自的机制处理。返回 nil 表示不是瞬时错误
+func (tm *TaskManager) planRetry(taskCtx *TaskContext, err error) *taskRetryPlan {
+	if tm.cfg.Current().Worker.MaxRetries <= 0 || err == nil {
+		return nil
+	}
+	failure := taskCtx.failureReasons()
+	if failure.terminated() {
+		return nil
+	}
+
+	message := failure.LastWorkerError
+	class := ClassifyError(message)
+	if message == "" || !transientErrorClasses[class] {
+		message = err.Error()
+		class = ClassifyError(message)
+	}
+	if !transientErrorClasses[class] {
+		return nil
+	}
+
+	root, attempt := retryChain(taskCtx)
+	plan := &taskRetryPlan{root: root, attempt: attempt, class: class, message: message}
+	if attempt <= tm.cfg.Current().Worker.MaxRetries {
+		plan.retry = true
+		plan.backoff = tm.cfg.Current().Worker.GetRetryBackoff(attempt)
+	}
+	return plan
+}
+
+// recordAttempt 将任务的执行结果记录到 task_attempts（只记录瞬时错误失败的任务和自动重试产生的任务）
+func (tm *TaskManager) recordAttempt(taskCtx *TaskContext, status models.TaskStatus, err error, plan *taskRetryPlan) {
+	root, attempt := retryChain(taskCtx)
+	if plan == nil && attempt == 1 {
+		return
+	}
+
+	record := &models.TaskAttempt{
+		RootTaskID: root,
+		TaskID:     taskCtx.TaskID,
+		UserID:     taskCtx.UserID,
+		Attempt:    attempt,
+		Status:     status,
+		StartedAt:  taskCtx.StartTime,
+		FinishedAt: time.Now(),
+	}
+	if endTime := taskCtx.State().EndTime; endTime != nil {
+		record.FinishedAt = *endTime
+	}
+	if plan != nil {
+		record.ErrorClass = plan.class
+		record.ErrorMessage = plan.message
+		if plan.retry {
+			next := record.FinishedAt.Add(plan.backoff)
+			record.NextRetryAt = &next
+		}
+	} else if err != nil {
+		record.ErrorClass = ClassifyError(err.Error())
+		record.ErrorMessage = err.Error()
+	}
+
+	if err := tm.taskRepo.CreateAttempt(record); err != nil {
+		log.Printf("[Retry] 记录任务 %s 的第 %d 次尝试失败: %v", taskCtx.TaskID, attempt, err)
+	}
+}
+
+// scheduleRetry 等待退避时间后以相同参数重新运行任务，新任务沿用任务链的第一次尝试ID
+func (tm *TaskManager) scheduleRetry(taskCtx *TaskContext, plan *taskRetryPlan) {
+	log.Printf("[Retry] 任务 %s 因瞬时错误（%s）失败，%v 后自动重试（第 %d/%d 次）",
+		taskCtx.TaskID, plan.class, plan.backoff, plan.attempt, tm.cfg.Current().Worker.MaxRetries)
+	taskCtx.AddEvent(&dto.ProgressEvent{
+		Type:    "output",
+		Line:    fmt.Sprintf("任务因瞬时错误失败，%v 后自动重试（第 %d/%d 次）", plan.backoff, plan.attempt, tm.cfg.Current().Worker.MaxRetries),
+		Message: "自动重试",
+	})
+
+	taskID := taskCtx.TaskID
+	time.AfterFunc(plan.backoff, func() {
+		tm.retryTask(taskID, plan)
+	})
+}
+
+// retryTask 重新运行失败的任务，无法重新运行时将任务标记为 dead_letter
+func (tm *TaskManager) retryTask(taskID string, plan *taskRetryPlan) {
+	task, err := tm.taskRepo.GetByTaskID(taskID)
+	if err != nil {
+		// 任务已被删除
+		log.Printf("[Retry] 读取任务 %s 失败，取消重试: %v", taskID, err)
+		return
+	}
+
+	startReq, err := buildRerunRequest(task, &dto.RerunTaskRequest{})
+	if err == nil {
+		startReq.RetryRoot = plan.root
+		startReq.RetryAttempt = plan.attempt + 1
+		var resp *dto.StartTaskResponse
+		if resp, err = tm.StartTask(task.UserID, startReq); err == nil {
+			log.Printf("[Retry] 任务 %s 已自动重试为 %s（第 %d 次尝试）", taskID, resp.TaskID, plan.attempt+1)
+			return
+		}
+	}
+
+	log.Printf("[Retry] 自动重试任务 %s 失败: %v", taskID, err)
+	if updateErr := tm.taskRepo.UpdateStatus(taskID, models.TaskStatusDeadLetter, nil); updateErr != nil {
+		log.Printf("[Retry] 标记任务 %s 为 dead_letter 失败: %v", taskID, updateErr)
+	}
+	tm.taskRepo.UpdateErrorMessage(taskID, fmt.Sprintf("自动重试失败: %v", err))
+}
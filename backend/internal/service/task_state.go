@@ -0,0 +1,205 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"gen-go/internal/models"
+)
+
+// TaskState 任务运行状态的快照
+type TaskState struct {
+	Status     models.TaskStatus
+	Finished   bool
+	ReturnCode *int
+	EndTime    *time.Time
+}
+
+// RunTime 任务运行时长（未结束时计算到当前时间）
+func (s TaskState) RunTime(start time.Time) time.Duration {
+	if s.EndTime != nil && !s.EndTime.IsZero() {
+		return s.EndTime.Sub(start)
+	}
+	return time.Since(start)
+}
+
+// failureKind 任务失败原因的类别
+type failureKind int
+
+const (
+	failureHandshake   failureKind = iota // 工作进程握手失败（协议不兼容时进程会被终止）
+	failureStall                          // 看门狗判定工作进程卡死（进程会被终止）
+	failureTimeout                        // 超过最长运行时间（进程会被终止，任务标记为 timeout）
+	failureWorkerError                    // 工作进程的错误输出（只保留最后一行，据此判断是否为可重试的瞬时错误）
+)
+
+// taskFailureReasons 任务失败原因的快照
+type taskFailureReasons struct {
+	Handshake       string
+	Stall           string
+	Timeout         string
+	LastWorkerError string
+}
+
+// terminated 工作进程是否因握手失败、卡死或超时被终止（这些情况由各自的机制处理，不自动重试）
+func (r taskFailureReasons) terminated() bool {
+	return r.Handshake != "" || r.Stall != "" || r.Timeout != ""
+}
+
+// charsUpdate 更新任务状态时同时写入的输入输出字符数
+func charsUpdate(inputChars, outputChars int64) map[string]interface{} {
+	return map[string]interface{}{
+		"input_chars":  inputChars,
+		"output_chars": outputChars,
+	}
+}
+
+// State 获取任务运行状态的一致快照
+func (tc *TaskContext) State() TaskState {
+	tc.stateLock.RLock()
+	defer tc.stateLock.RUnlock()
+
+	state := TaskState{Status: tc.status, Finished: tc.finished}
+	if tc.returnCode != nil {
+		code := *tc.returnCode
+		state.ReturnCode = &code
+	}
+	if tc.endTime != nil {
+		end := *tc.endTime
+		state.EndTime = &end
+	}
+	return state
+}
+
+// Status 获取任务当前状态
+func (tc *TaskContext) Status() models.TaskStatus {
+	tc.stateLock.RLock()
+	defer tc.stateLock.RUnlock()
+	return tc.status
+}
+
+// Finished 任务是否已结束（工作进程已退出或已停止）
+func (tc *TaskContext) Finished() bool {
+	tc.stateLock.RLock()
+	defer tc.stateLock.RUnlock()
+	return tc.finished
+}
+
+// setFailureReason 记录任务失败原因（看门狗、运行时间限制和输出读取在不同的 goroutine 中写入）
+func (tc *TaskContext) setFailureReason(kind failureKind, message string) {
+	tc.stateLock.Lock()
+	defer tc.stateLock.Unlock()
+
+	switch kind {
+	case failureHandshake:
+		tc.failure.Handshake = message
+	case failureStall:
+		tc.failure.Stall = message
+	case failureTimeout:
+		tc.failure.Timeout = message
+	case failureWorkerError:
+		tc.failure.LastWorkerError = message
+	}
+}
+
+// failureReasons 获取任务失败原因的快照
+func (tc *TaskContext) failureReasons() taskFailureReasons {
+	tc.stateLock.RLock()
+	defer tc.stateLock.RUnlock()
+	return tc.failure
+}
+
+// stopRequested 任务是否已进入停止流程（停止中或已停止）
+func (tc *TaskContext) stopRequested() bool {
+	status := tc.Status()
+	return status == models.TaskStatusStopping || status == models.TaskStatusStopped
+}
+
+// transitionLocked 执行状态转换（调用方持有 stateLock，合法的转换见 models.TaskStatus）
+func (tc *TaskContext) transitionLocked(to models.TaskStatus) error {
+	if !tc.status.CanTransitionTo(to) {
+		return fmt.Errorf("%w: 任务 %s 的状态不能从 %s 变为 %s", models.ErrInvalidTaskTransition, tc.TaskID, tc.status, to)
+	}
+	tc.status = to
+	return nil
+}
+
+// markEndedLocked 记录退出码和结束时间（调用方持有 stateLock）
+func (tc *TaskContext) markEndedLocked(code int) {
+	now := time.Now()
+	tc.finished = true
+	tc.returnCode = &code
+	tc.endTime = &now
+}
+
+// finish 工作进程退出后标记任务结束（status 为 finished、error 或 timeout）
+// 任务已进入停止流程时返回错误，由停止流程负责更新状态
+func (tc *TaskContext) finish(status models.TaskStatus, code int) error {
+	tc.stateLock.Lock()
+	defer tc.stateLock.Unlock()
+
+	if err := tc.transitionLocked(status); err != nil {
+		return err
+	}
+	tc.markEndedLocked(code)
+	return nil
+}
+
+// refineStatus 任务结束后按检查点和重试结果细化状态（与当前状态相同时不变）
+func (tc *TaskContext) refineStatus(status models.TaskStatus) error {
+	tc.stateLock.Lock()
+	defer tc.stateLock.Unlock()
+
+	if tc.status == status {
+		return nil
+	}
+	return tc.transitionLocked(status)
+}
+
+// fail 启动阶段失败时标记任务失败，任务已进入停止流程时不改变状态
+func (tc *TaskContext) fail() {
+	tc.stateLock.Lock()
+	defer tc.stateLock.Unlock()
+
+	if tc.transitionLocked(models.TaskStatusError) == nil {
+		tc.markEndedLocked(1)
+	}
+}
+
+// beginStop 进入停止流程，任务已结束或已在停止时返回错误（同一任务只会被停止一次）
+func (tc *TaskContext) beginStop() error {
+	tc.stateLock.Lock()
+	defer tc.stateLock.Unlock()
+
+	switch tc.status {
+	case models.TaskStatusRunning:
+		return tc.transitionLocked(models.TaskStatusStopping)
+	case models.TaskStatusStopping:
+		return fmt.Errorf("任务正在停止")
+	default:
+		return fmt.Errorf("任务状态为 %s，无法停止", tc.status)
+	}
+}
+
+// completeStop 停止流程完成，记录停止时的进度快照并唤醒等待停止结果的 runTask
+func (tc *TaskContext) completeStop(progress map[string]string) {
+	tc.stateLock.Lock()
+	if err := tc.transitionLocked(models.TaskStatusStopped); err != nil {
+		tc.stateLock.Unlock()
+		return
+	}
+	tc.markEndedLocked(-1)
+	tc.stoppedProgress = progress
+	tc.stateLock.Unlock()
+
+	close(tc.stopDone)
+}
+
+// waitStopped 等待停止流程完成，返回停止时的 Redis 进度快照
+func (tc *TaskContext) waitStopped() map[string]string {
+	<-tc.stopDone
+
+	tc.stateLock.RLock()
+	defer tc.stateLock.RUnlock()
+	return tc.stoppedProgress
+}
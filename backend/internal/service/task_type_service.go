@@ -0,0 +1,188 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+	"gen-go/internal/utils"
+)
+
+// TaskTypeService 任务类型服务
+type TaskTypeService struct {
+	taskTypeRepo *repository.TaskTypeRepository
+	ruleRepo     *repository.ScoringRuleRepository
+}
+
+// NewTaskTypeService 创建任务类型服务
+func NewTaskTypeService(taskTypeRepo *repository.TaskTypeRepository, ruleRepo *repository.ScoringRuleRepository) *TaskTypeService {
+	return &TaskTypeService{
+		taskTypeRepo: taskTypeRepo,
+		ruleRepo:     ruleRepo,
+	}
+}
+
+// List 获取任务类型列表（包括各类型专属的评分规则），activeOnly 为 true 时只返回启用的类型
+func (s *TaskTypeService) List(activeOnly bool) ([]*dto.TaskTypeResponse, error) {
+	taskTypes, err := s.taskTypeRepo.List(activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := s.ruleRepo.List("")
+	if err != nil {
+		return nil, err
+	}
+
+	rulesByType := make(map[string][]*dto.ScoringRuleResponse)
+	for i := range rules {
+		rulesByType[rules[i].TaskType] = append(rulesByType[rules[i].TaskType], toScoringRuleResponse(&rules[i]))
+	}
+
+	result := make([]*dto.TaskTypeResponse, len(taskTypes))
+	for i := range taskTypes {
+		result[i] = toTaskTypeResponse(&taskTypes[i], rulesByType[taskTypes[i].Name])
+	}
+	return result, nil
+}
+
+// Get 获取任务类型
+func (s *TaskTypeService) Get(id uint) (*dto.TaskTypeResponse, error) {
+	taskType, err := s.taskTypeRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("任务类型不存在")
+	}
+	return s.withRules(taskType)
+}
+
+// Create 创建任务类型，请求中的评分规则一并创建
+func (s *TaskTypeService) Create(userID uint, req *dto.CreateTaskTypeRequest) (*dto.TaskTypeResponse, error) {
+	taskType := &models.TaskType{
+		Name:          strings.TrimSpace(req.Name),
+		Description:   req.Description,
+		SpecialPrompt: req.SpecialPrompt,
+		Directions:    req.Directions,
+		OutputSchema:  strings.TrimSpace(req.OutputSchema),
+		IsActive:      req.IsActive == nil || *req.IsActive,
+		CreatedBy:     userID,
+	}
+	if taskType.Name == "" {
+		return nil, fmt.Errorf("任务类型名称不能为空")
+	}
+	if _, err := s.taskTypeRepo.GetByName(taskType.Name); err == nil {
+		return nil, fmt.Errorf("任务类型已存在: %s", taskType.Name)
+	}
+	if err := validateOutputSchema(taskType.OutputSchema); err != nil {
+		return nil, err
+	}
+
+	rules := make([]*models.ScoringRule, 0, len(req.ScoringRules))
+	for i := range req.ScoringRules {
+		ruleReq := req.ScoringRules[i]
+		ruleReq.TaskType = taskType.Name
+		rule, err := newScoringRule(userID, &ruleReq)
+		if err != nil {
+			return nil, fmt.Errorf("评分规则 %s 无效: %w", ruleReq.Name, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := s.taskTypeRepo.Create(taskType, rules); err != nil {
+		return nil, fmt.Errorf("创建任务类型失败: %w", err)
+	}
+	return s.withRules(taskType)
+}
+
+// Update 更新任务类型
+func (s *TaskTypeService) Update(id uint, req *dto.UpdateTaskTypeRequest) (*dto.TaskTypeResponse, error) {
+	taskType, err := s.taskTypeRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("任务类型不存在")
+	}
+
+	if req.Description != nil {
+		taskType.Description = *req.Description
+	}
+	if req.SpecialPrompt != nil {
+		taskType.SpecialPrompt = *req.SpecialPrompt
+	}
+	if req.Directions != nil {
+		taskType.Directions = *req.Directions
+	}
+	if req.OutputSchema != nil {
+		taskType.OutputSchema = strings.TrimSpace(*req.OutputSchema)
+		if err := validateOutputSchema(taskType.OutputSchema); err != nil {
+			return nil, err
+		}
+	}
+	if req.IsActive != nil {
+		if !*req.IsActive && taskType.Name == models.DefaultTaskType {
+			return nil, fmt.Errorf("默认任务类型 %s 不能停用", models.DefaultTaskType)
+		}
+		taskType.IsActive = *req.IsActive
+	}
+
+	if err := s.taskTypeRepo.Update(taskType); err != nil {
+		return nil, fmt.Errorf("更新任务类型失败: %w", err)
+	}
+	return s.withRules(taskType)
+}
+
+// Delete 删除任务类型及其专属的评分规则（内置类型不可删除，已有任务保留类型名称）
+func (s *TaskTypeService) Delete(id uint) error {
+	taskType, err := s.taskTypeRepo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("任务类型不存在")
+	}
+	if taskType.IsBuiltin {
+		return fmt.Errorf("内置任务类型不能删除，可以停用")
+	}
+	return s.taskTypeRepo.Delete(taskType)
+}
+
+// withRules 查询任务类型专属的评分规则并转换响应
+func (s *TaskTypeService) withRules(taskType *models.TaskType) (*dto.TaskTypeResponse, error) {
+	rules, err := s.ruleRepo.List(taskType.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ScoringRuleResponse, len(rules))
+	for i := range rules {
+		responses[i] = toScoringRuleResponse(&rules[i])
+	}
+	return toTaskTypeResponse(taskType, responses), nil
+}
+
+// validateOutputSchema 校验输出格式（为空表示不检查）
+func validateOutputSchema(schema string) error {
+	if schema == "" {
+		return nil
+	}
+	if _, err := utils.ParseJSONSchema(schema); err != nil {
+		return fmt.Errorf("无效的输出格式: %w", err)
+	}
+	return nil
+}
+
+// toTaskTypeResponse 转换任务类型响应
+func toTaskTypeResponse(taskType *models.TaskType, rules []*dto.ScoringRuleResponse) *dto.TaskTypeResponse {
+	if rules == nil {
+		rules = []*dto.ScoringRuleResponse{}
+	}
+	return &dto.TaskTypeResponse{
+		ID:            taskType.ID,
+		Name:          taskType.Name,
+		Description:   taskType.Description,
+		SpecialPrompt: taskType.SpecialPrompt,
+		Directions:    taskType.Directions,
+		OutputSchema:  taskType.OutputSchema,
+		IsBuiltin:     taskType.IsBuiltin,
+		IsActive:      taskType.IsActive,
+		ScoringRules:  rules,
+		CreatedBy:     taskType.CreatedBy,
+		CreatedAt:     dto.FormatTime(taskType.CreatedAt),
+		UpdatedAt:     dto.FormatTime(taskType.UpdatedAt),
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+)
+
+// touch 记录工作进程的一次活动（任意输出行，包括心跳）
+func (tc *TaskContext) touch() {
+	atomic.StoreInt64(&tc.lastActivity, time.Now().UnixNano())
+}
+
+// idleFor 距离工作进程最近一次输出的时间
+func (tc *TaskContext) idleFor() time.Duration {
+	return time.Since(time.Unix(0, atomic.LoadInt64(&tc.lastActivity)))
+}
+
+// watchTask 看门狗：定期检查工作进程的输出，超过警告阈值推送 warning 事件，
+// 超过卡死阈值则记录原因并终止进程（进程结束后由 runTask 按失败处理）
+func (tm *TaskManager) watchTask(ctx context.Context, taskCtx *TaskContext) {
+	warnAfter := tm.cfg.Current().Worker.GetHeartbeatWarnDuration()
+	stallAfter := tm.cfg.Current().Worker.GetStallTimeout()
+
+	interval := warnAfter / 4
+	if interval < time.Second {
+		interval = time.Second
+	} else if interval > 30*time.Second {
+		interval = 30 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	warned := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		idle := taskCtx.idleFor()
+		if idle < warnAfter {
+			warned = false
+			continue
+		}
+
+		if idle >= stallAfter {
+			reason := fmt.Sprintf("工作进程已 %v 没有输出，判定为卡死并终止", idle.Round(time.Second))
+			taskCtx.setFailureReason(failureStall, reason)
+			log.Printf("[Watchdog] 任务 %s: %s", taskCtx.TaskID, reason)
+			taskCtx.AddEvent(&dto.ProgressEvent{
+				Type:    "error",
+				Line:    reason,
+				Message: "任务卡死",
+			})
+			if taskCtx.CancelFunc != nil {
+				taskCtx.CancelFunc()
+			}
+			return
+		}
+
+		if !warned {
+			warned = true
+			log.Printf("[Watchdog] 任务 %s 已 %v 没有输出", taskCtx.TaskID, idle.Round(time.Second))
+			taskCtx.AddEvent(&dto.ProgressEvent{
+				Type:    "warning",
+				Line:    fmt.Sprintf("工作进程已 %v 没有输出，超过 %v 将被终止", idle.Round(time.Second), stallAfter),
+				Message: "任务无响应",
+			})
+		}
+	}
+}
+
+// restartStalledTask 按配置以相同参数重新运行卡死的任务（stall_action=restart 且未超过最大重启次数）
+func (tm *TaskManager) restartStalledTask(taskCtx *TaskContext) {
+	if tm.cfg.Current().Worker.StallAction != config.StallActionRestart {
+		return
+	}
+
+	restarts := 0
+	switch value := taskCtx.Params["stall_restarts"].(type) {
+	case int:
+		restarts = value
+	case float64:
+		restarts = int(value)
+	}
+	if restarts >= tm.cfg.Current().Worker.MaxStallRestarts {
+		log.Printf("[Watchdog] 任务 %s 已自动重新运行 %d 次，不再重试", taskCtx.TaskID, restarts)
+		return
+	}
+
+	task, err := tm.taskRepo.GetByTaskID(taskCtx.TaskID)
+	if err != nil {
+		log.Printf("[Watchdog] 读取任务 %s 失败: %v", taskCtx.TaskID, err)
+		return
+	}
+	startReq, err := buildRerunRequest(task, &dto.RerunTaskRequest{})
+	if err != nil {
+		log.Printf("[Watchdog] 生成任务 %s 的重新运行参数失败: %v", taskCtx.TaskID, err)
+		return
+	}
+	startReq.StallRestarts = restarts + 1
+
+	resp, err := tm.StartTask(task.UserID, startReq)
+	if err != nil {
+		log.Printf("[Watchdog] 自动重新运行任务 %s 失败: %v", taskCtx.TaskID, err)
+		return
+	}
+	log.Printf("[Watchdog] 任务 %s 卡死，已自动重新运行为 %s（第 %d 次）", taskCtx.TaskID, resp.TaskID, restarts+1)
+	taskCtx.AddEvent(&dto.ProgressEvent{
+		Type:    "output",
+		Line:    fmt.Sprintf("任务卡死，已自动重新运行为 %s", resp.TaskID),
+		Message: "自动重新运行",
+	})
+}
+
+// startRuntimeLimit 任务设置了最长运行时间时启动计时，超时后记录原因并终止进程（进程结束后由 runTask 标记为 timeout），
+// 返回停止计时的函数
+func (tm *TaskManager) startRuntimeLimit(taskCtx *TaskContext) func() {
+	minutes, _ := taskCtx.Params["max_runtime_minutes"].(int)
+	if minutes <= 0 {
+		return func() {}
+	}
+
+	timer := time.AfterFunc(time.Duration(minutes)*time.Minute, func() {
+		reason := fmt.Sprintf("任务超过最长运行时间 %d 分钟，已终止", minutes)
+		taskCtx.setFailureReason(failureTimeout, reason)
+		log.Printf("[Watchdog] 任务 %s: %s", taskCtx.TaskID, reason)
+		taskCtx.AddEvent(&dto.ProgressEvent{
+			Type:    "error",
+			Line:    reason,
+			Message: "任务超时",
+		})
+		if taskCtx.CancelFunc != nil {
+			taskCtx.CancelFunc()
+		}
+	})
+	return func() { timer.Stop() }
+}
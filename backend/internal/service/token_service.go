@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"gen-go/internal/config"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// refreshTokenKeyPrefix 刷新Token键前缀，键名使用Token的SHA-256摘要，Redis中不保存明文
+	refreshTokenKeyPrefix = "auth:refresh:"
+	// revokedTokenKeyPrefix 已吊销访问Token（jti）键前缀，过期时间与访问Token剩余有效期一致
+	revokedTokenKeyPrefix = "auth:revoked:"
+	// userRefreshTokensKeyPrefix 用户刷新Token索引（集合，成员为刷新Token摘要），修改或重置密码时据此吊销用户的全部刷新Token
+	userRefreshTokensKeyPrefix = "auth:user_refresh:"
+	// tokensNotBeforeKeyPrefix 用户访问Token的最早签发时间（Unix 秒），更早签发的访问Token视为已吊销，过期时间与访问Token有效期一致
+	tokensNotBeforeKeyPrefix = "auth:not_before:"
+)
+
+// ErrInvalidRefreshToken 刷新Token无效、已过期或已被使用
+var ErrInvalidRefreshToken = errors.New("刷新Token无效或已过期")
+
+// TokenService 管理刷新Token与访问Token黑名单（存储在Redis）
+type TokenService struct {
+	redisClient *redis.Client
+	cfg         *config.Config
+}
+
+// NewTokenService 创建Token服务
+func NewTokenService(redisClient *redis.Client, cfg *config.Config) *TokenService {
+	return &TokenService{
+		redisClient: redisClient,
+		cfg:         cfg,
+	}
+}
+
+// IssueRefreshToken 为用户签发刷新Token
+func (s *TokenService) IssueRefreshToken(userID uint) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("生成刷新Token失败: %w", err)
+	}
+	token := hex.EncodeToString(b)
+
+	ctx := context.Background()
+	hash := hashRefreshToken(token)
+	ttl := s.cfg.Current().JWT.GetRefreshExpireDuration()
+	index := userRefreshTokensKey(userID)
+	pipe := s.redisClient.TxPipeline()
+	pipe.Set(ctx, refreshTokenKeyPrefix+hash, userID, ttl)
+	pipe.SAdd(ctx, index, hash)
+	pipe.Expire(ctx, index, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", fmt.Errorf("保存刷新Token失败: %w", err)
+	}
+	return token, nil
+}
+
+// ConsumeRefreshToken 校验并作废刷新Token（一次性使用），返回所属用户ID
+func (s *TokenService) ConsumeRefreshToken(token string) (uint, error) {
+	if token == "" {
+		return 0, ErrInvalidRefreshToken
+	}
+
+	ctx := context.Background()
+	hash := hashRefreshToken(token)
+	key := refreshTokenKeyPrefix + hash
+	pipe := s.redisClient.TxPipeline()
+	get := pipe.Get(ctx, key)
+	pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, ErrInvalidRefreshToken
+		}
+		return 0, fmt.Errorf("读取刷新Token失败: %w", err)
+	}
+
+	userID, err := strconv.ParseUint(get.Val(), 10, 64)
+	if err != nil {
+		return 0, ErrInvalidRefreshToken
+	}
+	s.redisClient.SRem(ctx, userRefreshTokensKey(uint(userID)), hash)
+	return uint(userID), nil
+}
+
+// RevokeRefreshToken 吊销刷新Token
+func (s *TokenService) RevokeRefreshToken(token string) error {
+	if token == "" {
+		return nil
+	}
+	key := refreshTokenKeyPrefix + hashRefreshToken(token)
+	if err := s.redisClient.Del(context.Background(), key).Err(); err != nil {
+		return fmt.Errorf("吊销刷新Token失败: %w", err)
+	}
+	return nil
+}
+
+// RevokeUserTokens 吊销用户的全部刷新Token和此前签发的全部访问Token（修改或重置密码后调用）
+// 索引中已被使用或吊销的刷新Token摘要一并删除，不影响结果
+func (s *TokenService) RevokeUserTokens(userID uint) error {
+	ctx := context.Background()
+	index := userRefreshTokensKey(userID)
+	hashes, err := s.redisClient.SMembers(ctx, index).Result()
+	if err != nil {
+		return fmt.Errorf("读取用户刷新Token失败: %w", err)
+	}
+
+	keys := make([]string, 0, len(hashes)+1)
+	for _, hash := range hashes {
+		keys = append(keys, refreshTokenKeyPrefix+hash)
+	}
+	keys = append(keys, index)
+
+	pipe := s.redisClient.TxPipeline()
+	pipe.Del(ctx, keys...)
+	pipe.Set(ctx, tokensNotBeforeKey(userID), time.Now().Unix(), s.cfg.Current().JWT.GetExpireDuration())
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("吊销用户Token失败: %w", err)
+	}
+	return nil
+}
+
+// IsIssuedBeforeRevocation 判断访问Token是否签发于用户最近一次吊销全部Token之前（issuedAt 为零值时视为更早签发）
+// Redis 不可用时放行（仅记录日志），与 IsAccessTokenRevoked 相同
+func (s *TokenService) IsIssuedBeforeRevocation(userID uint, issuedAt time.Time) bool {
+	notBefore, err := s.redisClient.Get(context.Background(), tokensNotBeforeKey(userID)).Int64()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("[Token] 查询用户Token吊销时间失败: %v", err)
+		}
+		return false
+	}
+	return issuedAt.Unix() < notBefore
+}
+
+// RevokeAccessToken 将访问Token加入黑名单，直至其自然过期
+func (s *TokenService) RevokeAccessToken(tokenID string, expiresAt time.Time) error {
+	if tokenID == "" {
+		return nil
+	}
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	if err := s.redisClient.Set(context.Background(), revokedTokenKeyPrefix+tokenID, 1, ttl).Err(); err != nil {
+		return fmt.Errorf("吊销访问Token失败: %w", err)
+	}
+	return nil
+}
+
+// IsAccessTokenRevoked 判断访问Token是否已被吊销
+// Redis 不可用时放行（仅记录日志），避免缓存故障导致所有请求被拒绝
+func (s *TokenService) IsAccessTokenRevoked(tokenID string) bool {
+	n, err := s.redisClient.Exists(context.Background(), revokedTokenKeyPrefix+tokenID).Result()
+	if err != nil {
+		log.Printf("[Token] 查询Token黑名单失败: %v", err)
+		return false
+	}
+	return n > 0
+}
+
+// userRefreshTokensKey 用户刷新Token索引的键
+func userRefreshTokensKey(userID uint) string {
+	return userRefreshTokensKeyPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+// tokensNotBeforeKey 用户访问Token最早签发时间的键
+func tokensNotBeforeKey(userID uint) string {
+	return tokensNotBeforeKeyPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+// hashRefreshToken 计算刷新Token的SHA-256摘要
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gen-go/internal/config"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func newTestTokenService(t *testing.T) (*TokenService, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	cfg := &config.Config{}
+	cfg.JWT.RefreshExpireHours = 1
+	return NewTokenService(client, cfg), mr
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	s, mr := newTestTokenService(t)
+
+	token, err := s.IssueRefreshToken(42)
+	if err != nil {
+		t.Fatalf("IssueRefreshToken() error = %v", err)
+	}
+	if mr.Exists(refreshTokenKeyPrefix + token) {
+		t.Fatal("refresh token stored in plain text")
+	}
+
+	userID, err := s.ConsumeRefreshToken(token)
+	if err != nil || userID != 42 {
+		t.Fatalf("ConsumeRefreshToken() = %d, %v; want 42, nil", userID, err)
+	}
+
+	// 刷新Token只能使用一次
+	if _, err := s.ConsumeRefreshToken(token); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("second ConsumeRefreshToken() error = %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestConsumeRefreshTokenInvalid(t *testing.T) {
+	s, mr := newTestTokenService(t)
+
+	expired, err := s.IssueRefreshToken(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr.FastForward(2 * time.Hour)
+
+	revoked, err := s.IssueRefreshToken(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RevokeRefreshToken(revoked); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"unknown", "deadbeef"},
+		{"expired", expired},
+		{"revoked", revoked},
+	}
+	for _, tt := range tests {
+		if _, err := s.ConsumeRefreshToken(tt.token); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("%s: ConsumeRefreshToken() error = %v, want ErrInvalidRefreshToken", tt.name, err)
+		}
+	}
+}
+
+func TestRevokeAccessToken(t *testing.T) {
+	s, _ := newTestTokenService(t)
+
+	if err := s.RevokeAccessToken("jti-1", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	// 已过期的Token无需加入黑名单
+	if err := s.RevokeAccessToken("jti-2", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		tokenID string
+		want    bool
+	}{
+		{"jti-1", true},
+		{"jti-2", false},
+		{"jti-3", false},
+	}
+	for _, tt := range tests {
+		if got := s.IsAccessTokenRevoked(tt.tokenID); got != tt.want {
+			t.Errorf("IsAccessTokenRevoked(%s) = %v, want %v", tt.tokenID, got, tt.want)
+		}
+	}
+}
+
+func TestRevokeUserTokens(t *testing.T) {
+	s, _ := newTestTokenService(t)
+	s.cfg.JWT.ExpireMinutes = 30
+
+	first, err := s.IssueRefreshToken(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.IssueRefreshToken(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := s.IssueRefreshToken(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuedBefore := time.Now().Add(-time.Second)
+
+	if err := s.RevokeUserTokens(7); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range []string{first, second} {
+		if _, err := s.ConsumeRefreshToken(token); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("ConsumeRefreshToken() after revoke error = %v, want ErrInvalidRefreshToken", err)
+		}
+	}
+	if userID, err := s.ConsumeRefreshToken(other); err != nil || userID != 8 {
+		t.Errorf("other user's refresh token: ConsumeRefreshToken() = %d, %v; want 8, nil", userID, err)
+	}
+
+	tests := []struct {
+		name     string
+		userID   uint
+		issuedAt time.Time
+		want     bool
+	}{
+		{"issued before revoke", 7, issuedBefore, true},
+		{"issued after revoke", 7, time.Now().Add(time.Second), false},
+		{"other user", 8, issuedBefore, false},
+	}
+	for _, tt := range tests {
+		if got := s.IsIssuedBeforeRevocation(tt.userID, tt.issuedAt); got != tt.want {
+			t.Errorf("%s: IsIssuedBeforeRevocation() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
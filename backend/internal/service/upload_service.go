@@ -0,0 +1,361 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+	"gen-go/internal/utils"
+)
+
+// ChunkedUploadService 分片上传服务
+// 分片先写入临时目录，完成时按顺序拼接并校验SHA-256，再交给DataFileService入库（JSONL 文件保存到文件存储）
+type ChunkedUploadService struct {
+	sessionRepo     *repository.UploadSessionRepository
+	dataFileService *DataFileService
+	cfg             *config.Config
+}
+
+// NewChunkedUploadService 创建分片上传服务
+func NewChunkedUploadService(sessionRepo *repository.UploadSessionRepository, dataFileService *DataFileService, cfg *config.Config) *ChunkedUploadService {
+	return &ChunkedUploadService{
+		sessionRepo:     sessionRepo,
+		dataFileService: dataFileService,
+		cfg:             cfg,
+	}
+}
+
+// InitUpload 初始化分片上传会话
+func (s *ChunkedUploadService) InitUpload(userID uint, req *dto.InitUploadRequest) (*dto.InitUploadResponse, error) {
+	// 顺便清理过期的上传会话
+	s.cleanupExpired()
+
+	chunkSize := req.ChunkSize
+	if chunkSize <= 0 || chunkSize > s.cfg.Current().Upload.ChunkSize {
+		chunkSize = s.cfg.Current().Upload.ChunkSize
+	}
+
+	// 大小和扩展名在初始化时检查，文件内容在合并分片后检查
+	if req.TotalSize > s.cfg.Current().Upload.GetMaxSizeBytes() {
+		return nil, fmt.Errorf("文件大小超过上限 %d MB", s.cfg.Current().Upload.MaxSizeMB)
+	}
+	if err := utils.CheckUploadType(req.Filename, nil, s.cfg.Current().Upload.AllowedExtensions); err != nil {
+		return nil, err
+	}
+
+	checksum := strings.ToLower(strings.TrimSpace(req.Checksum))
+	if checksum != "" && len(checksum) != sha256.Size*2 {
+		return nil, fmt.Errorf("无效的SHA-256校验值")
+	}
+
+	totalChunks := int((req.TotalSize + chunkSize - 1) / chunkSize)
+
+	uploadID, err := generateUploadID()
+	if err != nil {
+		return nil, fmt.Errorf("生成上传ID失败: %w", err)
+	}
+
+	if err := os.MkdirAll(s.sessionDir(uploadID), 0755); err != nil {
+		return nil, fmt.Errorf("创建临时目录失败: %w", err)
+	}
+
+	session := &models.UploadSession{
+		UploadID:       uploadID,
+		UserID:         userID,
+		Filename:       filepath.Base(req.Filename),
+		TotalSize:      req.TotalSize,
+		ChunkSize:      chunkSize,
+		TotalChunks:    totalChunks,
+		Checksum:       checksum,
+		Status:         "uploading",
+		ValidationMode: req.ValidationMode,
+		ExpiresAt:      time.Now().UTC().Add(s.cfg.Current().Upload.GetSessionExpireDuration()),
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		os.RemoveAll(s.sessionDir(uploadID))
+		return nil, fmt.Errorf("创建上传会话失败: %w", err)
+	}
+
+	log.Printf("[ChunkedUpload] 用户 %d 初始化上传: upload_id=%s, 文件=%s, 大小=%d, 分片数=%d", userID, uploadID, session.Filename, req.TotalSize, totalChunks)
+
+	return &dto.InitUploadResponse{
+		UploadID:    uploadID,
+		ChunkSize:   chunkSize,
+		TotalChunks: totalChunks,
+		ExpiresAt:   dto.FormatTime(session.ExpiresAt),
+	}, nil
+}
+
+// UploadChunk 上传单个分片（同一分片重复上传会覆盖，便于断点续传）
+func (s *ChunkedUploadService) UploadChunk(userID uint, uploadID string, index int, body io.Reader, chunkChecksum string) (*dto.UploadStatusResponse, error) {
+	session, err := s.getActiveSession(userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if index < 0 || index >= session.TotalChunks {
+		return nil, fmt.Errorf("分片索引越界: %d", index)
+	}
+
+	expectedSize := session.ChunkSize
+	if index == session.TotalChunks-1 {
+		expectedSize = session.TotalSize - session.ChunkSize*int64(session.TotalChunks-1)
+	}
+
+	// 先写临时文件再重命名，避免中断的请求留下半个分片
+	partPath := s.partPath(uploadID, index)
+	tmpPath := partPath + ".tmp"
+	out, err := os.Create(tmpPath)
+	if err != nil {
+		return nil, fmt.Errorf("创建分片文件失败: %w", err)
+	}
+
+	hasher := sha256.New()
+	written, err := io.Copy(io.MultiWriter(out, hasher), io.LimitReader(body, expectedSize+1))
+	out.Close()
+	if err != nil {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("写入分片失败: %w", err)
+	}
+
+	if written != expectedSize {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("分片大小不匹配: 期望 %d 字节, 实际 %d 字节", expectedSize, written)
+	}
+
+	chunkChecksum = strings.ToLower(strings.TrimSpace(chunkChecksum))
+	if chunkChecksum != "" && chunkChecksum != hex.EncodeToString(hasher.Sum(nil)) {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("分片校验失败")
+	}
+
+	if err := os.Rename(tmpPath, partPath); err != nil {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("保存分片失败: %w", err)
+	}
+
+	return s.buildStatus(session), nil
+}
+
+// GetUploadStatus 获取上传状态（已接收的分片列表，用于断点续传）
+func (s *ChunkedUploadService) GetUploadStatus(userID uint, uploadID string) (*dto.UploadStatusResponse, error) {
+	session, err := s.sessionRepo.GetByUploadIDAndUserID(uploadID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("上传会话不存在")
+	}
+	return s.buildStatus(session), nil
+}
+
+// CompleteUpload 完成上传：拼接分片、校验并保存为数据文件
+// reject 模式下内容未通过结构校验时返回 ErrValidationRejected，会话保留以便重新上传分片
+func (s *ChunkedUploadService) CompleteUpload(userID uint, uploadID string) (*models.DataFile, *dto.FileValidationReport, error) {
+	session, err := s.getActiveSession(userID, uploadID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 会话可能在调低大小上限之前创建，合并前按当前上限再检查一次，避免把超限文件读入内存
+	if session.TotalSize > s.cfg.Current().Upload.GetMaxSizeBytes() {
+		return nil, nil, fmt.Errorf("文件大小超过上限 %d MB", s.cfg.Current().Upload.MaxSizeMB)
+	}
+
+	received := s.receivedChunks(uploadID)
+	if len(received) != session.TotalChunks {
+		return nil, nil, fmt.Errorf("分片不完整: 已接收 %d/%d", len(received), session.TotalChunks)
+	}
+
+	// 按顺序拼接分片，同时计算整体校验值
+	assembledPath := filepath.Join(s.sessionDir(uploadID), "assembled")
+	out, err := os.Create(assembledPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("创建合并文件失败: %w", err)
+	}
+
+	hasher := sha256.New()
+	writer := io.MultiWriter(out, hasher)
+	var total int64
+	for i := 0; i < session.TotalChunks; i++ {
+		n, err := appendPart(writer, s.partPath(uploadID, i))
+		if err != nil {
+			out.Close()
+			return nil, nil, fmt.Errorf("合并分片 %d 失败: %w", i, err)
+		}
+		total += n
+	}
+	out.Close()
+
+	if total != session.TotalSize {
+		return nil, nil, fmt.Errorf("文件大小不匹配: 期望 %d 字节, 实际 %d 字节", session.TotalSize, total)
+	}
+
+	checksum := hex.EncodeToString(hasher.Sum(nil))
+	if session.Checksum != "" && session.Checksum != checksum {
+		return nil, nil, fmt.Errorf("文件校验失败: SHA-256不匹配")
+	}
+
+	// 只读取文件头检查类型
+	head, err := readFileHead(assembledPath, utils.UploadSniffSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取合并文件失败: %w", err)
+	}
+	if err := utils.CheckUploadType(session.Filename, head, s.cfg.Current().Upload.AllowedExtensions); err != nil {
+		return nil, nil, err
+	}
+
+	dataFile, report, err := s.saveAssembled(userID, session, assembledPath)
+	if err != nil {
+		return nil, report, err
+	}
+
+	session.Status = "completed"
+	session.Checksum = checksum
+	session.FileID = &dataFile.ID
+	if err := s.sessionRepo.Update(session); err != nil {
+		log.Printf("[ChunkedUpload] 更新上传会话状态失败: %v", err)
+	}
+
+	if err := os.RemoveAll(s.sessionDir(uploadID)); err != nil {
+		log.Printf("[ChunkedUpload] 清理临时目录失败: %v", err)
+	}
+
+	log.Printf("[ChunkedUpload] 上传完成: upload_id=%s, file_id=%d, 大小=%d", uploadID, dataFile.ID, total)
+
+	return dataFile, report, nil
+}
+
+// saveAssembled 保存合并后的文件：JSONL 直接流式扫描、校验后移入文件存储，不读入内存；
+// 其他格式需要先整体转换为 JSONL，仍读取完整内容（大小已受 upload.max_size_mb 限制）
+func (s *ChunkedUploadService) saveAssembled(userID uint, session *models.UploadSession, assembledPath string) (*models.DataFile, *dto.FileValidationReport, error) {
+	if strings.EqualFold(filepath.Ext(session.Filename), ".jsonl") {
+		return s.dataFileService.SaveStoredUpload(userID, session.Filename, assembledPath, session.ValidationMode)
+	}
+
+	content, err := os.ReadFile(assembledPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取合并文件失败: %w", err)
+	}
+	return s.dataFileService.SaveUploadedContent(userID, session.Filename, content, session.ValidationMode)
+}
+
+// getActiveSession 获取未完成且未过期的上传会话
+func (s *ChunkedUploadService) getActiveSession(userID uint, uploadID string) (*models.UploadSession, error) {
+	session, err := s.sessionRepo.GetByUploadIDAndUserID(uploadID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("上传会话不存在")
+	}
+	if session.Status != "uploading" {
+		return nil, fmt.Errorf("上传会话已完成")
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return nil, fmt.Errorf("上传会话已过期")
+	}
+	return session, nil
+}
+
+// buildStatus 构建上传状态响应
+func (s *ChunkedUploadService) buildStatus(session *models.UploadSession) *dto.UploadStatusResponse {
+	received := []int{}
+	if session.Status == "uploading" {
+		received = s.receivedChunks(session.UploadID)
+	}
+	return &dto.UploadStatusResponse{
+		UploadID:       session.UploadID,
+		Filename:       session.Filename,
+		Status:         session.Status,
+		TotalSize:      session.TotalSize,
+		ChunkSize:      session.ChunkSize,
+		TotalChunks:    session.TotalChunks,
+		ReceivedChunks: received,
+		FileID:         session.FileID,
+	}
+}
+
+// receivedChunks 列出已接收的分片索引
+func (s *ChunkedUploadService) receivedChunks(uploadID string) []int {
+	entries, err := os.ReadDir(s.sessionDir(uploadID))
+	if err != nil {
+		return []int{}
+	}
+
+	indices := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".part") {
+			continue
+		}
+		index, err := strconv.Atoi(strings.TrimSuffix(name, ".part"))
+		if err == nil {
+			indices = append(indices, index)
+		}
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+// cleanupExpired 清理过期的上传会话及其临时文件
+func (s *ChunkedUploadService) cleanupExpired() {
+	sessions, err := s.sessionRepo.ListExpired(time.Now().UTC())
+	if err != nil {
+		log.Printf("[ChunkedUpload] 查询过期上传会话失败: %v", err)
+		return
+	}
+	for _, session := range sessions {
+		os.RemoveAll(s.sessionDir(session.UploadID))
+		s.sessionRepo.Delete(session.ID)
+	}
+	if len(sessions) > 0 {
+		log.Printf("[ChunkedUpload] 已清理 %d 个过期上传会话", len(sessions))
+	}
+}
+
+// sessionDir 上传会话的临时目录
+func (s *ChunkedUploadService) sessionDir(uploadID string) string {
+	return filepath.Join(s.cfg.Current().Upload.TempDir, uploadID)
+}
+
+// partPath 分片文件路径
+func (s *ChunkedUploadService) partPath(uploadID string, index int) string {
+	return filepath.Join(s.sessionDir(uploadID), fmt.Sprintf("%d.part", index))
+}
+
+// appendPart 将分片内容追加到writer
+func appendPart(w io.Writer, path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return io.Copy(w, f)
+}
+
+// readFileHead 读取文件开头最多 n 个字节
+func readFileHead(path string, n int) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, int64(n)))
+}
+
+// generateUploadID 生成随机上传ID
+func generateUploadID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestCompleteUploadStoresJSONL(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_loc=UTC&_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.DataFile{}, &models.DataFileVersion{}, &models.DataFileTag{}, &models.FileValidation{}, &models.UploadSession{}, &models.WorkspaceMember{}); err != nil {
+		t.Fatal(err)
+	}
+	models.SetFileStoreDir(t.TempDir())
+
+	cfg := &config.Config{Upload: config.UploadConfig{
+		TempDir:            t.TempDir(),
+		ChunkSize:          16,
+		SessionExpireHours: 1,
+		MaxSizeMB:          1,
+		AllowedExtensions:  []string{".jsonl"},
+	}}
+	fileRepo := repository.NewDataFileRepository(db)
+	versionService := NewFileVersionService(repository.NewDataFileVersionRepository(db), fileRepo)
+	jobPool := NewJobPool("test", &config.JobPoolConfig{Workers: 1, MaxQueue: 1})
+	validationService := NewFileValidationService(fileRepo, repository.NewFileValidationRepository(db), versionService, jobPool, cfg)
+	dataFileService := NewDataFileService(fileRepo, nil, nil, nil, validationService, versionService, NewVirusScanService(cfg))
+	uploads := NewChunkedUploadService(repository.NewUploadSessionRepository(db), dataFileService, cfg)
+
+	valid := `{"meta":{},"turns":[{"role":"Human","text":"hi"},{"role":"Assistant","text":"hello"}]}`
+	content := []byte(valid + "\n{\"bad\":1}\n" + valid + "\n")
+	init, err := uploads.InitUpload(1, &dto.InitUploadRequest{
+		Filename:       "data.jsonl",
+		TotalSize:      int64(len(content)),
+		ValidationMode: ValidationModeQuarantine,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i*16 < len(content); i++ {
+		end := min((i+1)*16, len(content))
+		if _, err := uploads.UploadChunk(1, init.UploadID, i, bytes.NewReader(content[i*16:end]), ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file, report, err := uploads.CompleteUpload(1, init.UploadID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.StoragePath == "" {
+		t.Fatal("uploaded JSONL was not written to the file store")
+	}
+	if report.InvalidLines != 1 || report.QuarantineFileID == nil {
+		t.Fatalf("report = %+v, want one quarantined line", report)
+	}
+
+	// 数据库中只保存存储路径，内容在文件存储中
+	var blobSize int64
+	if err := db.Model(&models.DataFile{}).Select("length(file_content)").Where("id = ?", file.ID).Scan(&blobSize).Error; err != nil {
+		t.Fatal(err)
+	}
+	if blobSize != 0 {
+		t.Errorf("file_content holds %d bytes, want 0", blobSize)
+	}
+
+	want := valid + "\n" + valid + "\n"
+	loaded, err := fileRepo.GetByIDAndUserID(file.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(loaded.FileContent) != want || loaded.FileSize != len(want) {
+		t.Errorf("stored content = %q (size %d), want %q", loaded.FileContent, loaded.FileSize, want)
+	}
+	quarantine, err := fileRepo.GetByIDAndUserID(*report.QuarantineFileID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(quarantine.FileContent) != "{\"bad\":1}\n" {
+		t.Errorf("quarantine content = %q", quarantine.FileContent)
+	}
+
+	versions, err := repository.NewDataFileVersionRepository(db).ListByFileID(file.ID)
+	if err != nil || len(versions) != 1 || versions[0].StoragePath != file.StoragePath || versions[0].LineCount != 2 {
+		t.Fatalf("versions = %+v, err %v", versions, err)
+	}
+
+	// 删除文件后不再被引用的存储文件一并删除
+	if err := fileRepo.Delete(file.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(models.StoredFilePath(file.StoragePath)); !os.IsNotExist(err) {
+		t.Errorf("stored file still exists after delete (err %v)", err)
+	}
+}
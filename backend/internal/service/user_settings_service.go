@@ -0,0 +1,135 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/plugin"
+	"gen-go/internal/repository"
+	"gen-go/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// maxUIOptionsBytes 前端界面选项 JSON 的最大长度
+const maxUIOptionsBytes = 16 * 1024
+
+// UserSettingsService 用户个人设置服务
+type UserSettingsService struct {
+	settingsRepo *repository.UserSettingsRepository
+	modelRepo    *repository.ModelConfigRepository
+}
+
+// NewUserSettingsService 创建用户个人设置服务
+func NewUserSettingsService(settingsRepo *repository.UserSettingsRepository, modelRepo *repository.ModelConfigRepository) *UserSettingsService {
+	return &UserSettingsService{
+		settingsRepo: settingsRepo,
+		modelRepo:    modelRepo,
+	}
+}
+
+// GetSettings 获取当前用户的个人设置（未保存过时返回空设置）
+func (s *UserSettingsService) GetSettings(userID uint) (*dto.UserSettingsResponse, error) {
+	settings, err := s.load(userID)
+	if err != nil {
+		return nil, err
+	}
+	return toUserSettingsResponse(settings), nil
+}
+
+// UpdateSettings 更新当前用户的个人设置，只修改请求中提供的字段
+func (s *UserSettingsService) UpdateSettings(userID uint, req *dto.UpdateUserSettingsRequest) (*dto.UserSettingsResponse, error) {
+	settings, err := s.load(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DefaultModelID != nil {
+		if *req.DefaultModelID == 0 {
+			settings.DefaultModelID = nil
+		} else {
+			if _, err := s.modelRepo.GetByIDAndActive(*req.DefaultModelID); err != nil {
+				return nil, fmt.Errorf("模型不存在或未启用: %d", *req.DefaultModelID)
+			}
+			modelID := *req.DefaultModelID
+			settings.DefaultModelID = &modelID
+		}
+	}
+	if req.DefaultBatchSize != nil {
+		settings.DefaultBatchSize = *req.DefaultBatchSize
+	}
+	if req.DefaultDataRounds != nil {
+		settings.DefaultDataRounds = *req.DefaultDataRounds
+	}
+	if req.ExportFormat != nil {
+		if !isExportFormat(*req.ExportFormat) {
+			return nil, fmt.Errorf("不支持的导出格式: %s", *req.ExportFormat)
+		}
+		settings.ExportFormat = *req.ExportFormat
+	}
+	if req.UIOptions != nil {
+		raw, err := json.Marshal(req.UIOptions)
+		if err != nil {
+			return nil, fmt.Errorf("界面选项格式错误: %w", err)
+		}
+		if len(raw) > maxUIOptionsBytes {
+			return nil, fmt.Errorf("界面选项过大（最多 %d 字节）", maxUIOptionsBytes)
+		}
+		settings.UIOptions = string(raw)
+	}
+
+	if err := s.settingsRepo.Save(settings); err != nil {
+		return nil, fmt.Errorf("保存个人设置失败: %w", err)
+	}
+	return toUserSettingsResponse(settings), nil
+}
+
+// ExportFormat 获取用户默认的导出格式，未设置时返回 jsonl
+func (s *UserSettingsService) ExportFormat(userID uint) string {
+	settings, err := s.settingsRepo.GetByUserID(userID)
+	if err != nil || settings.ExportFormat == "" {
+		return "jsonl"
+	}
+	return settings.ExportFormat
+}
+
+// load 读取用户的个人设置，未保存过时返回新的空设置
+func (s *UserSettingsService) load(userID uint) (*models.UserSettings, error) {
+	settings, err := s.settingsRepo.GetByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.UserSettings{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取个人设置失败: %w", err)
+	}
+	return settings, nil
+}
+
+// isExportFormat 判断是否为生成数据支持的导出格式（空字符串表示使用默认的 jsonl），包括插件注册的格式
+func isExportFormat(format string) bool {
+	if _, ok := plugin.Default().Exporter(format); ok {
+		return true
+	}
+	return format == "" || format == "jsonl" || format == "csv" || utils.IsTrainerFormat(format)
+}
+
+// toUserSettingsResponse 转换为个人设置响应
+func toUserSettingsResponse(settings *models.UserSettings) *dto.UserSettingsResponse {
+	resp := &dto.UserSettingsResponse{
+		DefaultModelID:    settings.DefaultModelID,
+		DefaultBatchSize:  settings.DefaultBatchSize,
+		DefaultDataRounds: settings.DefaultDataRounds,
+		ExportFormat:      settings.ExportFormat,
+		UIOptions:         map[string]interface{}{},
+	}
+	if settings.UIOptions != "" {
+		_ = json.Unmarshal([]byte(settings.UIOptions), &resp.UIOptions)
+	}
+	if settings.ID != 0 {
+		resp.UpdatedAt = dto.FormatTime(settings.UpdatedAt)
+	}
+	return resp
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/models"
+	"gen-go/pkg/clamav"
+)
+
+// 病毒扫描结果（DataFile.ScanStatus）
+const (
+	ScanStatusClean = "clean"
+	ScanStatusError = "error"
+)
+
+// ErrUploadInfected 上传内容被 clamd 检出病毒
+var ErrUploadInfected = errors.New("文件未通过病毒扫描，已拒绝上传")
+
+// ErrVirusScanUnavailable 病毒扫描失败且未开启 fail_open
+var ErrVirusScanUnavailable = errors.New("病毒扫描服务不可用，暂时无法上传文件")
+
+// VirusScanService 上传文件病毒扫描服务（ClamAV）
+type VirusScanService struct {
+	cfg *config.Config
+}
+
+// NewVirusScanService 创建病毒扫描服务
+func NewVirusScanService(cfg *config.Config) *VirusScanService {
+	return &VirusScanService{cfg: cfg}
+}
+
+// Enabled 是否开启了病毒扫描
+func (s *VirusScanService) Enabled() bool {
+	return s.cfg.Current().VirusScan.Enabled
+}
+
+// Scan 扫描上传内容并把结果写入 file 的扫描字段（未开启扫描时不做任何处理）
+// 检出病毒返回 ErrUploadInfected；扫描失败时开启 fail_open 则记录为 error 并放行，否则返回 ErrVirusScanUnavailable
+func (s *VirusScanService) Scan(file *models.DataFile, content []byte) error {
+	return s.ScanReader(file, bytes.NewReader(content))
+}
+
+// ScanReader 与 Scan 相同，但从 r 流式读取内容（用于已落盘的大文件）
+func (s *VirusScanService) ScanReader(file *models.DataFile, r io.Reader) error {
+	scanCfg := s.cfg.Current().VirusScan
+	if !scanCfg.Enabled {
+		return nil
+	}
+
+	result, err := s.scan(scanCfg, r)
+	now := time.Now()
+	file.ScannedAt = &now
+	if err != nil {
+		log.Printf("[VirusScan] 扫描文件 %s 失败: %v", file.Filename, err)
+		if !scanCfg.FailOpen {
+			return fmt.Errorf("%w: %v", ErrVirusScanUnavailable, err)
+		}
+		detail := []rune(err.Error())
+		if len(detail) > 255 {
+			detail = detail[:255]
+		}
+		file.ScanStatus = ScanStatusError
+		file.ScanDetail = string(detail)
+		return nil
+	}
+	if result.Infected {
+		log.Printf("[VirusScan] 用户 %d 上传的文件 %s 检出病毒: %s", file.UserID, file.Filename, result.Signature)
+		return fmt.Errorf("%w（检出 %s）", ErrUploadInfected, result.Signature)
+	}
+
+	file.ScanStatus = ScanStatusClean
+	file.ScanDetail = ""
+	return nil
+}
+
+// Ping 检查 clamd 是否可用
+func (s *VirusScanService) Ping(ctx context.Context) error {
+	client, err := clamav.NewClient(s.cfg.Current().VirusScan.Address, s.cfg.Current().VirusScan.GetTimeout())
+	if err != nil {
+		return err
+	}
+	return client.Ping(ctx)
+}
+
+// scan 按当前配置创建 clamd 客户端并扫描（地址和超时支持热加载）
+func (s *VirusScanService) scan(scanCfg config.VirusScanConfig, r io.Reader) (*clamav.Result, error) {
+	client, err := clamav.NewClient(scanCfg.Address, scanCfg.GetTimeout())
+	if err != nil {
+		return nil, err
+	}
+	return client.ScanReader(context.Background(), r)
+}
@@ -0,0 +1,463 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+)
+
+// Webhook 请求头
+const (
+	WebhookHeaderEvent      = "X-Webhook-Event"
+	WebhookHeaderDelivery   = "X-Webhook-Delivery"
+	WebhookHeaderSignature  = "X-Webhook-Signature"
+	webhookMaxResponseBytes = 2048
+)
+
+// webhookHiddenParams 不随事件推送的任务参数
+var webhookHiddenParams = map[string]bool{
+	"api_key": true,
+}
+
+// WebhookService 任务生命周期Webhook服务
+// 任务开始、完成、出错、停止时向用户注册的地址推送签名后的JSON，失败按指数退避重试并记录投递日志
+type WebhookService struct {
+	webhookRepo *repository.WebhookRepository
+	taskRepo    *repository.TaskRepository
+	client      *http.Client
+	cfg         *config.Config
+}
+
+// NewWebhookService 创建Webhook服务
+func NewWebhookService(webhookRepo *repository.WebhookRepository, taskRepo *repository.TaskRepository, cfg *config.Config) *WebhookService {
+	dialer := &net.Dialer{Timeout: cfg.Webhook.GetTimeout()}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !cfg.Webhook.AllowPrivateNetworks {
+		// 在解析出实际地址后检查，重定向和 DNS 重绑定同样会被拦截（不走代理，否则检查的是代理地址）
+		dialer.Control = rejectPrivateAddress
+		transport.Proxy = nil
+	}
+	transport.DialContext = dialer.DialContext
+
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		taskRepo:    taskRepo,
+		client:      &http.Client{Timeout: cfg.Webhook.GetTimeout(), Transport: transport},
+		cfg:         cfg,
+	}
+}
+
+// CreateWebhook 注册Webhook（返回的响应中包含签名密钥，仅此一次）
+func (s *WebhookService) CreateWebhook(userID uint, req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
+	if err := s.validateURL(req.URL); err != nil {
+		return nil, err
+	}
+	events, err := normalizeWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		secret, err = generateWebhookSecret()
+		if err != nil {
+			return nil, fmt.Errorf("生成签名密钥失败: %w", err)
+		}
+	}
+
+	webhook := &models.Webhook{
+		UserID:      userID,
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      events,
+		Description: req.Description,
+		IsActive:    true,
+	}
+	if err := s.webhookRepo.Create(webhook); err != nil {
+		return nil, fmt.Errorf("创建Webhook失败: %w", err)
+	}
+
+	resp := toWebhookResponse(webhook)
+	resp.Secret = secret
+	return resp, nil
+}
+
+// ListWebhooks 获取用户的Webhook列表
+func (s *WebhookService) ListWebhooks(userID uint) ([]*dto.WebhookResponse, error) {
+	webhooks, err := s.webhookRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.WebhookResponse, len(webhooks))
+	for i := range webhooks {
+		result[i] = toWebhookResponse(&webhooks[i])
+	}
+	return result, nil
+}
+
+// UpdateWebhook 更新Webhook
+func (s *WebhookService) UpdateWebhook(id uint, userID uint, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error) {
+	webhook, err := s.webhookRepo.GetByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Webhook不存在或无权访问")
+	}
+
+	if req.URL != nil {
+		if err := s.validateURL(*req.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *req.URL
+	}
+	if req.Events != nil {
+		events, err := normalizeWebhookEvents(req.Events)
+		if err != nil {
+			return nil, err
+		}
+		webhook.Events = events
+	}
+	if req.Description != nil {
+		webhook.Description = *req.Description
+	}
+	if req.IsActive != nil {
+		webhook.IsActive = *req.IsActive
+	}
+
+	if err := s.webhookRepo.Update(webhook); err != nil {
+		return nil, fmt.Errorf("更新Webhook失败: %w", err)
+	}
+	return toWebhookResponse(webhook), nil
+}
+
+// DeleteWebhook 删除Webhook
+func (s *WebhookService) DeleteWebhook(id uint, userID uint) error {
+	if _, err := s.webhookRepo.GetByIDAndUserID(id, userID); err != nil {
+		return fmt.Errorf("Webhook不存在或无权访问")
+	}
+	return s.webhookRepo.Delete(id)
+}
+
+// ListDeliveries 分页获取Webhook的投递记录
+func (s *WebhookService) ListDeliveries(id uint, userID uint, page, perPage int) (*dto.PaginatedResponse, error) {
+	if _, err := s.webhookRepo.GetByIDAndUserID(id, userID); err != nil {
+		return nil, fmt.Errorf("Webhook不存在或无权访问")
+	}
+
+	offset := (page - 1) * perPage
+	deliveries, total, err := s.webhookRepo.ListDeliveries(id, offset, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.WebhookDeliveryResponse, len(deliveries))
+	for i := range deliveries {
+		items[i] = toWebhookDeliveryResponse(&deliveries[i])
+	}
+
+	return &dto.PaginatedResponse{
+		Items:   items,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	}, nil
+}
+
+// Redeliver 以原始内容重新投递一次（生成新的投递记录）
+func (s *WebhookService) Redeliver(id uint, deliveryID uint, userID uint) (*dto.WebhookDeliveryResponse, error) {
+	webhook, err := s.webhookRepo.GetByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Webhook不存在或无权访问")
+	}
+
+	original, err := s.webhookRepo.GetDelivery(webhook.ID, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("投递记录不存在")
+	}
+
+	delivery := &models.WebhookDelivery{
+		WebhookID: webhook.ID,
+		UserID:    webhook.UserID,
+		Event:     original.Event,
+		TaskID:    original.TaskID,
+		Payload:   original.Payload,
+	}
+	if err := s.webhookRepo.CreateDelivery(delivery); err != nil {
+		return nil, fmt.Errorf("创建投递记录失败: %w", err)
+	}
+
+	// 手动重新投递只尝试一次，同步返回结果
+	s.attempt(webhook, delivery)
+	if err := s.webhookRepo.UpdateDelivery(delivery); err != nil {
+		log.Printf("[Webhook] 更新投递记录 %d 失败: %v", delivery.ID, err)
+	}
+
+	resp := toWebhookDeliveryResponse(delivery)
+	return &resp, nil
+}
+
+// DispatchTaskEvent 向任务所属用户订阅了该事件的Webhook异步推送任务事件
+func (s *WebhookService) DispatchTaskEvent(event string, taskID string) {
+	task, err := s.taskRepo.GetByTaskID(taskID)
+	if err != nil {
+		log.Printf("[Webhook] 获取任务 %s 失败，跳过 %s 事件: %v", taskID, event, err)
+		return
+	}
+
+	webhooks, err := s.webhookRepo.ListActiveByUserID(task.UserID)
+	if err != nil {
+		log.Printf("[Webhook] 获取用户 %d 的Webhook失败: %v", task.UserID, err)
+		return
+	}
+
+	for i := range webhooks {
+		webhook := webhooks[i]
+		if !webhook.Subscribes(event) {
+			continue
+		}
+
+		delivery := &models.WebhookDelivery{
+			WebhookID: webhook.ID,
+			UserID:    webhook.UserID,
+			Event:     event,
+			TaskID:    task.TaskID,
+		}
+		if err := s.webhookRepo.CreateDelivery(delivery); err != nil {
+			log.Printf("[Webhook] 创建投递记录失败: webhook=%d, err=%v", webhook.ID, err)
+			continue
+		}
+
+		payload, err := json.Marshal(buildWebhookPayload(event, delivery.ID, task))
+		if err != nil {
+			log.Printf("[Webhook] 序列化事件失败: %v", err)
+			continue
+		}
+		delivery.Payload = string(payload)
+
+		go s.deliver(&webhook, delivery)
+	}
+}
+
+// deliver 投递并在失败时按指数退避重试
+func (s *WebhookService) deliver(webhook *models.Webhook, delivery *models.WebhookDelivery) {
+	interval := s.cfg.Current().Webhook.GetRetryInterval()
+	maxAttempts := s.cfg.Current().Webhook.MaxRetries + 1
+
+	for {
+		s.attempt(webhook, delivery)
+		if err := s.webhookRepo.UpdateDelivery(delivery); err != nil {
+			log.Printf("[Webhook] 更新投递记录 %d 失败: %v", delivery.ID, err)
+		}
+
+		if delivery.Success || delivery.Attempts >= maxAttempts {
+			break
+		}
+
+		log.Printf("[Webhook] 投递 %d 第 %d 次失败，%v 后重试: %s", delivery.ID, delivery.Attempts, interval, delivery.ErrorMessage)
+		time.Sleep(interval)
+		interval *= 2
+	}
+
+	if !delivery.Success {
+		log.Printf("[Webhook] 投递 %d 失败，已达最大重试次数: webhook=%d, event=%s", delivery.ID, webhook.ID, delivery.Event)
+	}
+}
+
+// attempt 执行一次HTTP投递，结果写回 delivery
+func (s *WebhookService) attempt(webhook *models.Webhook, delivery *models.WebhookDelivery) {
+	delivery.Attempts++
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Success = false
+		delivery.ErrorMessage = fmt.Sprintf("构建请求失败: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookHeaderEvent, delivery.Event)
+	req.Header.Set(WebhookHeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(WebhookHeaderSignature, SignWebhookPayload(webhook.Secret, body))
+
+	// 记录实际连接的地址（重定向时为最后一次连接），内网地址的响应内容不保存
+	var remoteIP net.IP
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if addr, ok := info.Conn.RemoteAddr().(*net.TCPAddr); ok {
+				remoteIP = addr.IP
+			}
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		delivery.Success = false
+		delivery.StatusCode = 0
+		delivery.ErrorMessage = fmt.Sprintf("请求失败: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	delivery.StatusCode = resp.StatusCode
+	delivery.ResponseBody = ""
+	if !isPrivateIP(remoteIP) {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponseBytes))
+		delivery.ResponseBody = string(respBody)
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		now := time.Now().UTC()
+		delivery.Success = true
+		delivery.ErrorMessage = ""
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	delivery.Success = false
+	delivery.ErrorMessage = fmt.Sprintf("响应状态码 %d", resp.StatusCode)
+}
+
+// validateURL 校验回调地址：只允许 http/https；未允许内网投递时拒绝本机和内网 IP
+// 域名在投递时按解析出的实际地址再检查一次
+func (s *WebhookService) validateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("无效的回调地址，仅支持 http/https")
+	}
+	if s.cfg.Current().Webhook.AllowPrivateNetworks {
+		return nil
+	}
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return fmt.Errorf("不允许使用内网回调地址: %s", host)
+	}
+	if ip := net.ParseIP(host); ip != nil && isPrivateIP(ip) {
+		return fmt.Errorf("不允许使用内网回调地址: %s", host)
+	}
+	return nil
+}
+
+// SignWebhookPayload 计算签名：sha256=<HMAC-SHA256(secret, body) 的十六进制>
+// 接收方使用相同密钥对原始请求体计算签名并与 X-Webhook-Signature 比较
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// buildWebhookPayload 构建事件内容
+func buildWebhookPayload(event string, deliveryID uint, task *models.Task) *dto.WebhookPayload {
+	params := make(map[string]interface{}, len(task.Params))
+	for k, v := range task.Params {
+		if !webhookHiddenParams[k] {
+			params[k] = v
+		}
+	}
+
+	return &dto.WebhookPayload{
+		Event:      event,
+		DeliveryID: deliveryID,
+		Timestamp:  dto.FormatTime(time.Now()),
+		Task: dto.WebhookTaskPayload{
+			TaskID:       task.TaskID,
+			UserID:       task.UserID,
+			Status:       string(task.Status),
+			Params:       params,
+			ErrorMessage: task.ErrorMessage,
+			StartedAt:    dto.FormatTime(task.StartedAt),
+			FinishedAt:   dto.FormatTimePtr(task.FinishedAt),
+			InputChars:   task.InputChars,
+			OutputChars:  task.OutputChars,
+		},
+	}
+}
+
+// normalizeWebhookEvents 校验事件列表并转换为逗号分隔的存储格式
+func normalizeWebhookEvents(events []string) (string, error) {
+	seen := make(map[string]bool, len(events))
+	result := make([]string, 0, len(events))
+	for _, event := range events {
+		event = strings.TrimSpace(event)
+		if event == "" || seen[event] {
+			continue
+		}
+		if !isWebhookEvent(event) {
+			return "", fmt.Errorf("不支持的事件类型: %s（可选: %s）", event, strings.Join(models.WebhookEvents, ", "))
+		}
+		seen[event] = true
+		result = append(result, event)
+	}
+	return strings.Join(result, ","), nil
+}
+
+// isWebhookEvent 判断是否为支持的事件类型
+func isWebhookEvent(event string) bool {
+	for _, e := range models.WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// generateWebhookSecret 生成随机签名密钥
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// toWebhookResponse 转换Webhook响应（不含密钥）
+func toWebhookResponse(webhook *models.Webhook) *dto.WebhookResponse {
+	events := webhook.EventList()
+	if len(events) == 0 {
+		events = models.WebhookEvents
+	}
+	return &dto.WebhookResponse{
+		ID:          webhook.ID,
+		URL:         webhook.URL,
+		Events:      events,
+		Description: webhook.Description,
+		IsActive:    webhook.IsActive,
+		CreatedAt:   dto.FormatTime(webhook.CreatedAt),
+		UpdatedAt:   dto.FormatTime(webhook.UpdatedAt),
+	}
+}
+
+// toWebhookDeliveryResponse 转换投递记录响应
+func toWebhookDeliveryResponse(delivery *models.WebhookDelivery) dto.WebhookDeliveryResponse {
+	return dto.WebhookDeliveryResponse{
+		ID:           delivery.ID,
+		WebhookID:    delivery.WebhookID,
+		Event:        delivery.Event,
+		TaskID:       delivery.TaskID,
+		Payload:      delivery.Payload,
+		Success:      delivery.Success,
+		StatusCode:   delivery.StatusCode,
+		Attempts:     delivery.Attempts,
+		ResponseBody: delivery.ResponseBody,
+		ErrorMessage: delivery.ErrorMessage,
+		CreatedAt:    dto.FormatTime(delivery.CreatedAt),
+		DeliveredAt:  dto.FormatTimePtr(delivery.DeliveredAt),
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gen-go/internal/config"
+	"gen-go/internal/models"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		body   string
+		want   string
+	}{
+		// RFC 4231 测试用例 2
+		{"rfc4231", "Jefe", "what do ya want for nothing?", "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"},
+		{"empty body", "secret", "", "sha256=f9e66e179b6747ae54108f82f8ade8b3c25d76fd30afde6c395822c530196169"},
+	}
+	for _, tt := range tests {
+		if got := SignWebhookPayload(tt.secret, []byte(tt.body)); got != tt.want {
+			t.Errorf("%s: SignWebhookPayload() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSignWebhookPayloadVerifiable(t *testing.T) {
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := []byte(`{"event":"task.finished","task_id":"abc"}`)
+	signature := SignWebhookPayload(secret, body)
+
+	// 接收方按文档方式校验
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		t.Fatalf("signature %s does not verify", signature)
+	}
+
+	if SignWebhookPayload(secret+"x", body) == signature {
+		t.Error("different secrets produced the same signature")
+	}
+	if SignWebhookPayload(secret, append(body, ' ')) == signature {
+		t.Error("different bodies produced the same signature")
+	}
+	if !strings.HasPrefix(signature, "sha256=") {
+		t.Errorf("signature %s missing sha256= prefix", signature)
+	}
+}
+
+func TestNormalizeWebhookEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		events  []string
+		want    string
+		wantErr bool
+	}{
+		{"empty", nil, "", false},
+		{"trim and dedup", []string{" task.finished", "task.finished", "", "task.error"}, "task.finished,task.error", false},
+		{"unknown", []string{models.WebhookEventTaskStarted, "task.unknown"}, "", true},
+	}
+	for _, tt := range tests {
+		got, err := normalizeWebhookEvents(tt.events)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: normalizeWebhookEvents() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: normalizeWebhookEvents() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookValidateURL(t *testing.T) {
+	tests := []struct {
+		url          string
+		allowPrivate bool
+		wantErr      bool
+	}{
+		{"https://example.com/hook", false, false},
+		{"http://example.com:8080/hook", false, false},
+		{"ftp://example.com/hook", false, true},
+		{"example.com/hook", false, true},
+		{"http://localhost:8080/hook", false, true},
+		{"http://127.0.0.1/hook", false, true},
+		{"http://169.254.169.254/latest/meta-data", false, true},
+		{"http://[::1]/hook", false, true},
+		{"http://10.1.2.3/hook", false, true},
+		{"http://10.1.2.3/hook", true, false},
+		{"http://localhost/hook", true, false},
+	}
+	for _, tt := range tests {
+		cfg := &config.Config{}
+		cfg.Webhook.AllowPrivateNetworks = tt.allowPrivate
+		s := NewWebhookService(nil, nil, cfg)
+		if err := s.validateURL(tt.url); (err != nil) != tt.wantErr {
+			t.Errorf("validateURL(%s, allowPrivate=%v) error = %v, wantErr %v", tt.url, tt.allowPrivate, err, tt.wantErr)
+		}
+	}
+}
+
+func TestWebhookAttemptPrivateAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("internal secret"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		allowPrivate bool
+		wantSuccess  bool
+	}{
+		{"blocked", false, false},
+		{"allowed", true, true},
+	}
+	for _, tt := range tests {
+		cfg := &config.Config{}
+		cfg.Webhook.TimeoutSeconds = 5
+		cfg.Webhook.AllowPrivateNetworks = tt.allowPrivate
+		s := NewWebhookService(nil, nil, cfg)
+
+		delivery := &models.WebhookDelivery{Event: models.WebhookEventTaskFinished, Payload: "{}"}
+		s.attempt(&models.Webhook{URL: server.URL, Secret: "secret"}, delivery)
+		if delivery.Success != tt.wantSuccess {
+			t.Errorf("%s: Success = %v, want %v (%s)", tt.name, delivery.Success, tt.wantSuccess, delivery.ErrorMessage)
+		}
+		// 内网地址的响应内容不保存
+		if delivery.ResponseBody != "" {
+			t.Errorf("%s: ResponseBody = %q, want empty", tt.name, delivery.ResponseBody)
+		}
+	}
+}
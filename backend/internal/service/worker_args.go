@@ -0,0 +1,142 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gen-go/internal/config"
+)
+
+// reservedWorkerArgs 由 buildPythonArgs 生成的内置参数，不允许通过 extra_args 覆盖
+var reservedWorkerArgs = map[string]bool{
+	"file-id":             true,
+	"user-id":             true,
+	"task-id":             true,
+	"model":               true,
+	"services":            true,
+	"batch-size":          true,
+	"max-concurrent":      true,
+	"min-score":           true,
+	"task-type":           true,
+	"variants-per-sample": true,
+	"data-rounds":         true,
+	"retry-times":         true,
+	"api-key":             true,
+	"is-vllm":             true,
+	"no-vllm":             true,
+	"top-p":               true,
+	"max-tokens":          true,
+	"timeout":             true,
+	"special-prompt":      true,
+	"directions":          true,
+}
+
+// validateExtraArgs 按配置白名单校验额外参数，返回规范化后的字符串值
+func validateExtraArgs(workerCfg *config.WorkerConfig, extraArgs map[string]interface{}) (map[string]string, error) {
+	result := make(map[string]string, len(extraArgs))
+
+	for name, raw := range extraArgs {
+		if reservedWorkerArgs[name] {
+			return nil, fmt.Errorf("参数 %s 为内置参数，不能通过 extra_args 传递", name)
+		}
+
+		spec, ok := workerCfg.FindExtraArg(name)
+		if !ok {
+			return nil, fmt.Errorf("参数 %s 不在允许的 extra_args 白名单中", name)
+		}
+
+		value, err := normalizeExtraArg(spec, raw)
+		if err != nil {
+			return nil, fmt.Errorf("参数 %s 无效: %w", name, err)
+		}
+		result[name] = value
+	}
+
+	return result, nil
+}
+
+// normalizeExtraArg 校验单个参数值并转换为命令行字符串
+func normalizeExtraArg(spec *config.WorkerArgSpec, raw interface{}) (string, error) {
+	var value string
+	switch v := raw.(type) {
+	case string:
+		value = v
+	case bool:
+		value = strconv.FormatBool(v)
+	case float64:
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		value = strconv.Itoa(v)
+	default:
+		return "", fmt.Errorf("不支持的值类型 %T", raw)
+	}
+
+	switch spec.Type {
+	case "int":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return "", fmt.Errorf("需要整数")
+		}
+	case "float":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "", fmt.Errorf("需要数字")
+		}
+	case "bool":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("需要布尔值")
+		}
+		value = strconv.FormatBool(b)
+	case "", "string":
+		if spec.Pattern != "" {
+			re, err := regexp.Compile(spec.Pattern)
+			if err != nil {
+				return "", fmt.Errorf("白名单正则配置错误: %w", err)
+			}
+			if !re.MatchString(value) {
+				return "", fmt.Errorf("值不符合格式要求")
+			}
+		}
+	default:
+		return "", fmt.Errorf("白名单类型配置错误: %s", spec.Type)
+	}
+
+	if len(spec.Choices) > 0 {
+		allowed := false
+		for _, choice := range spec.Choices {
+			if choice == value {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return "", fmt.Errorf("取值必须为 %v 之一", spec.Choices)
+		}
+	}
+
+	return value, nil
+}
+
+// appendExtraArgs 将额外参数追加到命令行（按参数名排序保证顺序稳定）
+func appendExtraArgs(args []string, workerCfg *config.WorkerConfig, extraArgs map[string]string) []string {
+	names := make([]string, 0, len(extraArgs))
+	for name := range extraArgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := extraArgs[name]
+		spec, ok := workerCfg.FindExtraArg(name)
+		if ok && spec.Type == "bool" {
+			// 布尔参数按开关形式传递
+			if value == "true" {
+				args = append(args, "--"+name)
+			}
+			continue
+		}
+		args = append(args, "--"+name, value)
+	}
+	return args
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"gen-go/internal/config"
+	"gen-go/internal/dto"
+	"gen-go/internal/repository"
+	"gen-go/internal/utils"
+	"gen-go/internal/workerrpc"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// fetchBatchDefaultLimit FetchBatch 未指定 limit 时的每页条数
+	fetchBatchDefaultLimit = 200
+	// fetchBatchMaxLimit FetchBatch 每页最大条数
+	fetchBatchMaxLimit = 1000
+)
+
+// WorkerRPCServer 工作进程 gRPC 服务（proto/worker.proto）
+// 工作进程通过 StartWork 登记后，上报进度、提交生成数据和拉取样本都经由 gRPC，与标准输出协议共用同一套处理逻辑
+type WorkerRPCServer struct {
+	taskManager *TaskManager
+	fileRepo    *repository.DataFileRepository
+	cfg         *config.Config
+	server      *grpc.Server
+}
+
+// NewWorkerRPCServer 创建工作进程 gRPC 服务
+func NewWorkerRPCServer(taskManager *TaskManager, fileRepo *repository.DataFileRepository, cfg *config.Config) *WorkerRPCServer {
+	return &WorkerRPCServer{
+		taskManager: taskManager,
+		fileRepo:    fileRepo,
+		cfg:         cfg,
+	}
+}
+
+// Start 在配置的地址上监听并在后台提供服务（配置了 grpc_tls_cert/grpc_tls_key 时使用 TLS）
+func (s *WorkerRPCServer) Start() error {
+	var opts []grpc.ServerOption
+	if s.cfg.Current().Worker.GRPCTLSEnabled() {
+		creds, err := workerrpc.ServerTLS(s.cfg.Current().Worker.GRPCTLSCert, s.cfg.Current().Worker.GRPCTLSKey)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, creds)
+	} else if s.cfg.Current().Worker.Executor == config.WorkerExecutorAgent {
+		log.Printf("[WorkerRPC] 警告: 工作节点模式未配置 grpc_tls_cert/grpc_tls_key，内部密钥和任务数据将以明文传输")
+	}
+
+	listener, err := net.Listen("tcp", s.cfg.Current().Worker.GRPCAddr)
+	if err != nil {
+		return fmt.Errorf("监听工作进程 gRPC 地址失败: %w", err)
+	}
+
+	s.server = workerrpc.NewServer(utils.InternalAPIKey(), opts...)
+	workerrpc.RegisterWorkerServer(s.server, s)
+	log.Printf("[WorkerRPC] 工作进程 gRPC 服务已启动: %s（TLS: %v）", listener.Addr(), s.cfg.Current().Worker.GRPCTLSEnabled())
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil {
+			log.Printf("[WorkerRPC] gRPC 服务退出: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Stop 停止服务（等待进行中的调用完成）
+func (s *WorkerRPCServer) Stop() {
+	if s.server != nil {
+		s.server.GracefulStop()
+	}
+}
+
+// workerGRPCTarget 工作进程连接的地址（监听所有网卡时连接本机）
+func workerGRPCTarget(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// runningTask 查找正在运行的任务
+func (s *WorkerRPCServer) runningTask(taskID string) (*TaskContext, error) {
+	taskCtx, ok := s.taskManager.GetTask(taskID)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "任务不存在: %s", taskID)
+	}
+	if taskCtx.Finished() {
+		return nil, status.Errorf(codes.FailedPrecondition, "任务已结束: %s", taskID)
+	}
+	return taskCtx, nil
+}
+
+// StartWork 工作进程登记任务，校验协议版本
+func (s *WorkerRPCServer) StartWork(ctx context.Context, req *workerrpc.StartWorkRequest) (*workerrpc.StartWorkResponse, error) {
+	taskCtx, err := s.runningTask(req.TaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	handshake := &WorkerHandshake{
+		Type:            "handshake",
+		WorkerVersion:   req.WorkerVersion,
+		ProtocolVersion: req.ProtocolVersion,
+	}
+	if err := handshake.CheckCompatible(); err != nil {
+		return &workerrpc.StartWorkResponse{Accepted: false, Message: err.Error()}, nil
+	}
+
+	taskCtx.touch()
+	log.Printf("[WorkerRPC] 任务 %s 的工作进程已通过 gRPC 连接", req.TaskID)
+	taskCtx.AddEvent(&dto.ProgressEvent{
+		Type:    "output",
+		Line:    "工作进程已通过 gRPC 连接",
+		Message: "工作进程连接",
+	})
+	return &workerrpc.StartWorkResponse{
+		Accepted: true,
+		FileID:   taskCtx.FileID,
+		UserID:   taskCtx.UserID,
+	}, nil
+}
+
+// ReportProgress 处理心跳、进度和日志行
+func (s *WorkerRPCServer) ReportProgress(ctx context.Context, req *workerrpc.ReportProgressRequest) (*workerrpc.ReportProgressResponse, error) {
+	taskCtx, ok := s.taskManager.GetTask(req.TaskID)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "任务不存在: %s", req.TaskID)
+	}
+	if taskCtx.Finished() || taskCtx.stopRequested() {
+		return &workerrpc.ReportProgressResponse{Running: false}, nil
+	}
+
+	taskCtx.touch()
+	switch req.Type {
+	case "heartbeat":
+		// 心跳只用于看门狗检测，不推送事件
+	case "progress":
+		taskCtx.AddEvent(&dto.ProgressEvent{
+			Type:    "progress",
+			Message: fmt.Sprintf("进度: %v", req.Progress),
+		})
+	case "output":
+		s.taskManager.handlePythonOutput(taskCtx, req.Line)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "未知的上报类型: %s", req.Type)
+	}
+	return &workerrpc.ReportProgressResponse{Running: true}, nil
+}
+
+// SubmitResults 缓存工作进程提交的生成数据（与标准输出的 item 行相同处理）
+func (s *WorkerRPCServer) SubmitResults(ctx context.Context, req *workerrpc.SubmitResultsRequest) (*workerrpc.SubmitResultsResponse, error) {
+	taskCtx, err := s.runningTask(req.TaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	taskCtx.touch()
+	accepted := 0
+	for _, item := range req.Items {
+		if s.taskManager.bufferGeneratedItem(taskCtx, item) {
+			accepted++
+		}
+	}
+	return &workerrpc.SubmitResultsResponse{Accepted: accepted}, nil
+}
+
+// FetchBatch 分页读取任务数据文件中的样本
+func (s *WorkerRPCServer) FetchBatch(ctx context.Context, req *workerrpc.FetchBatchRequest) (*workerrpc.FetchBatchResponse, error) {
+	taskCtx, err := s.runningTask(req.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Offset < 0 {
+		return nil, status.Error(codes.InvalidArgument, "offset 不能小于 0")
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = fetchBatchDefaultLimit
+	} else if limit > fetchBatchMaxLimit {
+		limit = fetchBatchMaxLimit
+	}
+
+	file, err := s.fileRepo.GetByID(taskCtx.FileID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "数据文件不存在: %d", taskCtx.FileID)
+	}
+	items, total, err := utils.ParseJSONLWindow(file.FileContent, req.Offset, limit, "")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "解析数据文件失败: %v", err)
+	}
+
+	taskCtx.touch()
+	samples := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		samples[i] = item.Data
+	}
+	return &workerrpc.FetchBatchResponse{
+		Samples: samples,
+		Total:   total,
+		HasMore: req.Offset+len(samples) < total,
+	}, nil
+}
+
+// agentDispatcher 工作节点模式下的调度器，未开启时返回 FailedPrecondition
+func (s *WorkerRPCServer) agentDispatcher() (*AgentDispatcher, error) {
+	if s.taskManager.agents == nil {
+		return nil, status.Error(codes.FailedPrecondition, "后端未开启工作节点模式（worker.executor=agent）")
+	}
+	return s.taskManager.agents, nil
+}
+
+// RegisterAgent 工作节点登记，校验协议版本
+func (s *WorkerRPCServer) RegisterAgent(ctx context.Context, req *workerrpc.RegisterAgentRequest) (*workerrpc.RegisterAgentResponse, error) {
+	agents, err := s.agentDispatcher()
+	if err != nil {
+		return nil, err
+	}
+	if req.AgentID == "" {
+		return nil, status.Error(codes.InvalidArgument, "agent_id 不能为空")
+	}
+	return agents.Register(req), nil
+}
+
+// PullTask 工作节点轮询领取任务
+func (s *WorkerRPCServer) PullTask(ctx context.Context, req *workerrpc.PullTaskRequest) (*workerrpc.PullTaskResponse, error) {
+	agents, err := s.agentDispatcher()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := agents.Pull(req)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return resp, nil
+}
+
+// ReportOutput 处理工作节点转发的进程输出
+func (s *WorkerRPCServer) ReportOutput(ctx context.Context, req *workerrpc.ReportOutputRequest) (*workerrpc.ReportOutputResponse, error) {
+	agents, err := s.agentDispatcher()
+	if err != nil {
+		return nil, err
+	}
+	taskCtx, running, err := agents.lookup(req.AgentID, req.TaskID)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	for _, line := range req.Lines {
+		s.taskManager.handleWorkerLine(taskCtx, line.Stream, line.Line)
+	}
+	return &workerrpc.ReportOutputResponse{Running: running}, nil
+}
+
+// CompleteTask 处理工作节点上报的进程结束
+func (s *WorkerRPCServer) CompleteTask(ctx context.Context, req *workerrpc.CompleteTaskRequest) (*workerrpc.CompleteTaskResponse, error) {
+	agents, err := s.agentDispatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := agents.Complete(req); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &workerrpc.CompleteTaskResponse{}, nil
+}
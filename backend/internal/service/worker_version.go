@@ -0,0 +1,168 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"sync"
+	"time"
+
+	"gen-go/internal/config"
+)
+
+// WorkerProtocolVersion 后端支持的工作进程协议版本
+// main.py 的命令行参数或输出格式发生不兼容变更时，需与 main.py 中的 WORKER_PROTOCOL_VERSION 同步递增
+// v2: 新增 --ensemble 多模型集成参数
+// v3: 新增 --grpc-addr 参数，工作进程可通过 gRPC 与后端通信（proto/worker.proto）
+const WorkerProtocolVersion = 3
+
+// workerProbeTTL 版本探测结果的缓存时间，避免每次启动任务都拉起 Python 进程
+const workerProbeTTL = time.Minute
+
+// workerProbeFailureTTL 探测失败结果的缓存时间（较短，修复工作进程后能尽快恢复）
+const workerProbeFailureTTL = 5 * time.Second
+
+// WorkerHandshake main.py 启动时输出的握手信息
+// 格式: {"type": "handshake", "worker_version": "1.0.0", "protocol_version": 1}
+type WorkerHandshake struct {
+	Type            string `json:"type"`
+	WorkerVersion   string `json:"worker_version"`
+	ProtocolVersion int    `json:"protocol_version"`
+}
+
+// Compatible 判断握手信息中的协议版本是否与后端兼容
+func (h *WorkerHandshake) Compatible() bool {
+	return h.ProtocolVersion == WorkerProtocolVersion
+}
+
+// CheckCompatible 协议版本不兼容时返回错误
+func (h *WorkerHandshake) CheckCompatible() error {
+	if !h.Compatible() {
+		return fmt.Errorf("工作进程协议版本不兼容: main.py 为 v%d（版本 %s），后端要求 v%d", h.ProtocolVersion, h.WorkerVersion, WorkerProtocolVersion)
+	}
+	return nil
+}
+
+// parseWorkerHandshake 尝试将一行输出解析为握手信息
+func parseWorkerHandshake(line string) (*WorkerHandshake, bool) {
+	var handshake WorkerHandshake
+	if err := json.Unmarshal([]byte(line), &handshake); err != nil {
+		return nil, false
+	}
+	if handshake.Type != "handshake" {
+		return nil, false
+	}
+	return &handshake, true
+}
+
+// WorkerProbe 探测已安装的工作进程版本（执行 main.py --version）
+type WorkerProbe struct {
+	projectRoot string
+	timeout     time.Duration
+
+	mu        sync.Mutex
+	cached    *WorkerHandshake
+	cachedErr error
+	checkedAt time.Time
+	probing   chan struct{} // 正在探测时非空，探测结束后关闭
+}
+
+// NewWorkerProbe 创建工作进程版本探测器
+func NewWorkerProbe(cfg *config.Config) *WorkerProbe {
+	return &WorkerProbe{
+		projectRoot: cfg.ProjectRoot,
+		timeout:     cfg.Worker.GetHandshakeTimeout(),
+	}
+}
+
+// Probe 获取工作进程版本（带缓存）
+// 探测在锁外执行；同时到达的调用等待同一次探测的结果，不会重复拉起 Python 进程
+func (p *WorkerProbe) Probe() (*WorkerHandshake, error) {
+	p.mu.Lock()
+	if p.fresh() {
+		defer p.mu.Unlock()
+		return p.cached, p.cachedErr
+	}
+	if probing := p.probing; probing != nil {
+		p.mu.Unlock()
+		<-probing
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.cached, p.cachedErr
+	}
+	probing := make(chan struct{})
+	p.probing = probing
+	p.mu.Unlock()
+
+	handshake, err := p.run()
+	if err != nil {
+		log.Printf("[WorkerProbe] 探测工作进程版本失败: %v", err)
+	} else {
+		log.Printf("[WorkerProbe] 工作进程版本: %s, 协议版本: %d", handshake.WorkerVersion, handshake.ProtocolVersion)
+	}
+
+	p.mu.Lock()
+	p.cached, p.cachedErr = handshake, err
+	p.checkedAt = time.Now()
+	p.probing = nil
+	p.mu.Unlock()
+	close(probing)
+	return handshake, err
+}
+
+// fresh 缓存的探测结果是否仍然有效（调用方需持有 p.mu）
+func (p *WorkerProbe) fresh() bool {
+	if p.checkedAt.IsZero() {
+		return false
+	}
+	ttl := workerProbeTTL
+	if p.cachedErr != nil {
+		ttl = workerProbeFailureTTL
+	}
+	return time.Since(p.checkedAt) < ttl
+}
+
+// CheckCompatible 探测工作进程版本并校验协议兼容性
+func (p *WorkerProbe) CheckCompatible() (*WorkerHandshake, error) {
+	handshake, err := p.Probe()
+	if err != nil {
+		return nil, err
+	}
+	if err := handshake.CheckCompatible(); err != nil {
+		return handshake, err
+	}
+	return handshake, nil
+}
+
+// run 执行 main.py --version 并解析握手输出
+func (p *WorkerProbe) run() (*WorkerHandshake, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "python3", "main.py", "--version")
+	cmd.Dir = p.projectRoot
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("探测工作进程版本超时（%v）", p.timeout)
+		}
+		return nil, fmt.Errorf("探测工作进程版本失败: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if handshake, ok := parseWorkerHandshake(scanner.Text()); ok {
+			return handshake, nil
+		}
+	}
+
+	// 旧版本的 main.py 不支持 --version，不会输出握手信息
+	return nil, fmt.Errorf("工作进程未返回握手信息，main.py 版本过旧或不兼容")
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseWorkerHandshake(t *testing.T) {
+	tests := []struct {
+		line       string
+		wantOK     bool
+		compatible bool
+	}{
+		{`{"type": "handshake", "worker_version": "1.0.0", "protocol_version": 3}`, true, true},
+		{`{"type": "handshake", "worker_version": "0.9.0", "protocol_version": 1}`, true, false},
+		{`{"type": "progress", "protocol_version": 3}`, false, false},
+		{`not json`, false, false},
+	}
+	for _, tt := range tests {
+		handshake, ok := parseWorkerHandshake(tt.line)
+		if ok != tt.wantOK {
+			t.Errorf("parseWorkerHandshake(%s) ok = %v, want %v", tt.line, ok, tt.wantOK)
+			continue
+		}
+		if ok && handshake.Compatible() != tt.compatible {
+			t.Errorf("parseWorkerHandshake(%s) compatible = %v, want %v", tt.line, handshake.Compatible(), tt.compatible)
+		}
+	}
+}
+
+func TestWorkerProbeSharesConcurrentProbe(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+	dir := t.TempDir()
+	script := `import time
+with open("calls", "a") as f:
+    f.write("x")
+time.sleep(0.3)
+print('{"type": "handshake", "worker_version": "test", "protocol_version": 3}')
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.py"), []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+	probe := &WorkerProbe{projectRoot: dir, timeout: 10 * time.Second}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := probe.CheckCompatible(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	calls, err := os.ReadFile(filepath.Join(dir, "calls"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(calls), "x"); n != 1 {
+		t.Fatalf("main.py --version ran %d times, want 1", n)
+	}
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"gen-go/internal/dto"
+	"gen-go/internal/models"
+	"gen-go/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// WorkspaceService 工作区服务
+// 工作区成员共享其中的数据文件和任务：所有成员可查看，operator 及以上可修改，admin 管理成员
+type WorkspaceService struct {
+	workspaceRepo *repository.WorkspaceRepository
+	userRepo      *repository.UserRepository
+	fileRepo      *repository.DataFileRepository
+	taskRepo      *repository.TaskRepository
+}
+
+// NewWorkspaceService 创建工作区服务
+func NewWorkspaceService(
+	workspaceRepo *repository.WorkspaceRepository,
+	userRepo *repository.UserRepository,
+	fileRepo *repository.DataFileRepository,
+	taskRepo *repository.TaskRepository,
+) *WorkspaceService {
+	return &WorkspaceService{
+		workspaceRepo: workspaceRepo,
+		userRepo:      userRepo,
+		fileRepo:      fileRepo,
+		taskRepo:      taskRepo,
+	}
+}
+
+// CreateWorkspace 创建工作区，创建者成为工作区管理员
+func (s *WorkspaceService) CreateWorkspace(userID uint, req *dto.CreateWorkspaceRequest) (*dto.WorkspaceResponse, error) {
+	workspace := &models.Workspace{
+		Name:        req.Name,
+		Description: req.Description,
+		OwnerID:     userID,
+	}
+	if err := s.workspaceRepo.Create(workspace); err != nil {
+		return nil, fmt.Errorf("创建工作区失败: %w", err)
+	}
+	return s.GetWorkspace(workspace.ID, userID)
+}
+
+// ListWorkspaces 获取用户所在的工作区
+func (s *WorkspaceService) ListWorkspaces(userID uint) ([]dto.WorkspaceResponse, error) {
+	workspaces, err := s.workspaceRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.WorkspaceResponse, len(workspaces))
+	for i, w := range workspaces {
+		result[i] = toWorkspaceResponse(&w.Workspace, w.Role, w.MemberCount)
+	}
+	return result, nil
+}
+
+// GetWorkspace 获取工作区详情（含成员列表，仅成员可查看）
+func (s *WorkspaceService) GetWorkspace(id uint, userID uint) (*dto.WorkspaceResponse, error) {
+	workspace, member, err := s.requireMember(id, userID, models.PermissionView)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.workspaceRepo.ListMembers(id)
+	if err != nil {
+		return nil, fmt.Errorf("获取成员列表失败: %w", err)
+	}
+
+	resp := toWorkspaceResponse(workspace, member.Role, int64(len(members)))
+	resp.Members = make([]dto.WorkspaceMemberResponse, len(members))
+	for i, m := range members {
+		resp.Members[i] = dto.WorkspaceMemberResponse{
+			UserID:   m.UserID,
+			Username: m.Username,
+			Role:     m.Role,
+			JoinedAt: dto.FormatTime(m.CreatedAt),
+		}
+	}
+	return &resp, nil
+}
+
+// UpdateWorkspace 修改工作区名称和描述（工作区管理员）
+func (s *WorkspaceService) UpdateWorkspace(id uint, userID uint, req *dto.UpdateWorkspaceRequest) (*dto.WorkspaceResponse, error) {
+	workspace, _, err := s.requireMember(id, userID, models.PermissionAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		workspace.Name = *req.Name
+	}
+	if req.Description != nil {
+		workspace.Description = *req.Description
+	}
+	if err := s.workspaceRepo.Update(workspace); err != nil {
+		return nil, fmt.Errorf("更新工作区失败: %w", err)
+	}
+	return s.GetWorkspace(id, userID)
+}
+
+// DeleteWorkspace 删除工作区（仅创建者），其中的文件和任务转回各自创建者的个人资源
+func (s *WorkspaceService) DeleteWorkspace(id uint, userID uint) error {
+	workspace, err := s.workspaceRepo.GetByID(id)
+	if err != nil || workspace.OwnerID != userID {
+		return fmt.Errorf("工作区不存在或无权删除")
+	}
+	if err := s.workspaceRepo.Delete(id); err != nil {
+		return fmt.Errorf("删除工作区失败: %w", err)
+	}
+	return nil
+}
+
+// AddMember 按用户名添加成员（工作区管理员）
+func (s *WorkspaceService) AddMember(id uint, userID uint, req *dto.AddWorkspaceMemberRequest) (*dto.WorkspaceResponse, error) {
+	if _, _, err := s.requireMember(id, userID, models.PermissionAdmin); err != nil {
+		return nil, err
+	}
+
+	role := req.Role
+	if role == "" {
+		role = models.RoleViewer
+	}
+	if !models.IsValidRole(role) {
+		return nil, fmt.Errorf("无效的角色: %s", role)
+	}
+
+	user, err := s.userRepo.GetByUsername(req.Username)
+	if err != nil {
+		return nil, fmt.Errorf("用户不存在: %s", req.Username)
+	}
+	if _, err := s.workspaceRepo.GetMember(id, user.ID); err == nil {
+		return nil, fmt.Errorf("用户已是工作区成员")
+	}
+
+	if err := s.workspaceRepo.AddMember(&models.WorkspaceMember{
+		WorkspaceID: id,
+		UserID:      user.ID,
+		Role:        role,
+	}); err != nil {
+		return nil, fmt.Errorf("添加成员失败: %w", err)
+	}
+	return s.GetWorkspace(id, userID)
+}
+
+// UpdateMemberRole 修改成员角色（工作区管理员，创建者的角色不可修改）
+func (s *WorkspaceService) UpdateMemberRole(id uint, userID uint, memberUserID uint, role string) (*dto.WorkspaceResponse, error) {
+	workspace, _, err := s.requireMember(id, userID, models.PermissionAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if !models.IsValidRole(role) {
+		return nil, fmt.Errorf("无效的角色: %s", role)
+	}
+	if memberUserID == workspace.OwnerID {
+		return nil, fmt.Errorf("不能修改工作区创建者的角色")
+	}
+	if _, err := s.workspaceRepo.GetMember(id, memberUserID); err != nil {
+		return nil, fmt.Errorf("该用户不是工作区成员")
+	}
+
+	if err := s.workspaceRepo.UpdateMemberRole(id, memberUserID, role); err != nil {
+		return nil, fmt.Errorf("修改成员角色失败: %w", err)
+	}
+	return s.GetWorkspace(id, userID)
+}
+
+// RemoveMember 移除成员（工作区管理员，或成员自己退出；创建者不能被移除）
+func (s *WorkspaceService) RemoveMember(id uint, userID uint, memberUserID uint) error {
+	permission := models.PermissionAdmin
+	if memberUserID == userID {
+		permission = models.PermissionView
+	}
+	workspace, _, err := s.requireMember(id, userID, permission)
+	if err != nil {
+		return err
+	}
+	if memberUserID == workspace.OwnerID {
+		return fmt.Errorf("不能移除工作区创建者")
+	}
+	if _, err := s.workspaceRepo.GetMember(id, memberUserID); err != nil {
+		return fmt.Errorf("该用户不是工作区成员")
+	}
+
+	if err := s.workspaceRepo.RemoveMember(id, memberUserID); err != nil {
+		return fmt.Errorf("移除成员失败: %w", err)
+	}
+	return nil
+}
+
+// MoveFile 将自己上传的文件移入工作区（workspaceID 为空时移回个人文件）
+func (s *WorkspaceService) MoveFile(fileID uint, userID uint, workspaceID *uint) error {
+	files, err := s.fileRepo.ListInfoByIDsAndUserID([]uint{fileID}, userID)
+	if err != nil || len(files) == 0 || files[0].UserID != userID {
+		return fmt.Errorf("文件不存在或不是本人上传的文件")
+	}
+	if err := s.checkTarget(workspaceID, userID); err != nil {
+		return err
+	}
+	if err := s.fileRepo.UpdateWorkspace(fileID, workspaceID); err != nil {
+		return fmt.Errorf("移动文件失败: %w", err)
+	}
+	return nil
+}
+
+// MoveTask 将自己创建的任务（连同生成数据）移入工作区（workspaceID 为空时移回个人任务）
+func (s *WorkspaceService) MoveTask(taskID string, userID uint, workspaceID *uint) error {
+	task, err := s.taskRepo.GetByTaskID(taskID)
+	if err != nil || task.UserID != userID {
+		return fmt.Errorf("任务不存在或不是本人创建的任务")
+	}
+	if err := s.checkTarget(workspaceID, userID); err != nil {
+		return err
+	}
+	if err := s.taskRepo.UpdateWorkspace(taskID, workspaceID); err != nil {
+		return fmt.Errorf("移动任务失败: %w", err)
+	}
+	return nil
+}
+
+// checkTarget 校验用户能否向目标工作区放入资源（需要 operate 权限）
+func (s *WorkspaceService) checkTarget(workspaceID *uint, userID uint) error {
+	if workspaceID == nil {
+		return nil
+	}
+	_, _, err := s.requireMember(*workspaceID, userID, models.PermissionOperate)
+	return err
+}
+
+// requireMember 校验用户是工作区成员且角色拥有指定权限
+func (s *WorkspaceService) requireMember(id uint, userID uint, permission models.Permission) (*models.Workspace, *models.WorkspaceMember, error) {
+	workspace, err := s.workspaceRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, fmt.Errorf("工作区不存在")
+		}
+		return nil, nil, fmt.Errorf("获取工作区失败: %w", err)
+	}
+
+	member, err := s.workspaceRepo.GetMember(id, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("工作区不存在")
+	}
+	if !models.RoleHasPermission(member.Role, permission) {
+		return nil, nil, fmt.Errorf("工作区角色权限不足")
+	}
+	return workspace, member, nil
+}
+
+// toWorkspaceResponse 转换工作区响应
+func toWorkspaceResponse(workspace *models.Workspace, role string, memberCount int64) dto.WorkspaceResponse {
+	return dto.WorkspaceResponse{
+		ID:          workspace.ID,
+		Name:        workspace.Name,
+		Description: workspace.Description,
+		OwnerID:     workspace.OwnerID,
+		Role:        role,
+		MemberCount: memberCount,
+		CreatedAt:   dto.FormatTime(workspace.CreatedAt),
+		UpdatedAt:   dto.FormatTime(workspace.UpdatedAt),
+	}
+}
@@ -0,0 +1,107 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"gen-go/internal/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 链路追踪：HTTP 请求、任务执行、模型调用和数据库访问的 span 导出到 OTLP，
+// trace 上下文通过 W3C traceparent 在后端、Python 工作进程（环境变量）和上游模型服务（请求头）之间传递
+
+// instrumentationName 创建 span 使用的 tracer 名称
+const instrumentationName = "gen-go"
+
+// 传递给 Python 工作进程的 trace 上下文环境变量（值与 traceparent/tracestate 请求头相同）
+const (
+	EnvTraceParent = "TRACEPARENT"
+	EnvTraceState  = "TRACESTATE"
+)
+
+// Init 初始化全局 TracerProvider 和传播器
+// 未开启时只设置传播器，span 不会被记录；返回的 shutdown 在进程退出前调用，导出缓冲中的 span
+func Init(cfg *config.TracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("创建 OTLP 导出器失败: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName))),
+		// 上游请求（如 Python 工作进程的模型调用）已采样时跟随上游，保证同一任务的 trace 完整
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start 创建 span，ctx 中没有父 span 时作为新 trace 的根
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End 结束 span，err 不为空时记录错误状态
+func End(span trace.Span, err error) {
+	RecordError(span, err)
+	span.End()
+}
+
+// RecordError 记录 span 的错误状态（不结束 span），err 为空时忽略
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// TraceID 获取 ctx 中的 trace ID（用于日志关联），没有有效 span 时返回空
+func TraceID(ctx context.Context) string {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.HasTraceID() {
+		return ""
+	}
+	return spanCtx.TraceID().String()
+}
+
+// InjectHTTP 将 trace 上下文写入请求头
+func InjectHTTP(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Environ 返回传递给子进程的 trace 上下文环境变量，没有有效 span 时返回空
+func Environ(ctx context.Context) []string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	var env []string
+	if value := carrier.Get("traceparent"); value != "" {
+		env = append(env, EnvTraceParent+"="+value)
+		if state := carrier.Get("tracestate"); state != "" {
+			env = append(env, EnvTraceState+"="+state)
+		}
+	}
+	return env
+}
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ArchiveEntry 压缩包中的一个文件
+type ArchiveEntry struct {
+	Name    string // 压缩包内的路径
+	Content []byte
+}
+
+// ArchiveLimits 解压限制（防止压缩炸弹，各项必须大于 0）
+type ArchiveLimits struct {
+	MaxEntries    int   // 最多解压的文件数
+	MaxEntryBytes int64 // 单个文件解压后的大小上限
+	MaxTotalBytes int64 // 所有文件解压后的总大小上限
+}
+
+// IsArchive 判断文件名是否为支持的压缩包（.zip、.tar.gz、.tgz）
+func IsArchive(filename string) bool {
+	lower := strings.ToLower(filename)
+	return strings.HasSuffix(lower, ".zip") || strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz")
+}
+
+// ExtractArchive 解压 zip 或 tar.gz 压缩包，返回其中的普通文件
+// 目录、隐藏文件和 macOS 生成的 __MACOSX 元数据会被跳过；实际解压的字节数超过限制时返回错误（不信任压缩包头中记录的大小）
+func ExtractArchive(filename string, content []byte, limits ArchiveLimits) ([]ArchiveEntry, error) {
+	if strings.HasSuffix(strings.ToLower(filename), ".zip") {
+		return extractZip(content, limits)
+	}
+	return extractTarGz(content, limits)
+}
+
+// extractZip 解压 zip 压缩包
+func extractZip(content []byte, limits ArchiveLimits) ([]ArchiveEntry, error) {
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("无效的 zip 压缩包: %w", err)
+	}
+
+	collector := &archiveCollector{limits: limits}
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() || skipArchiveEntry(f.Name) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("读取压缩包中的 %s 失败: %w", f.Name, err)
+		}
+		err = collector.add(f.Name, rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return collector.entries, nil
+}
+
+// extractTarGz 解压 tar.gz 压缩包
+func extractTarGz(content []byte, limits ArchiveLimits) ([]ArchiveEntry, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("无效的 tar.gz 压缩包: %w", err)
+	}
+	defer gz.Close()
+
+	collector := &archiveCollector{limits: limits}
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("无效的 tar.gz 压缩包: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || skipArchiveEntry(header.Name) {
+			continue
+		}
+		if err := collector.add(header.Name, reader); err != nil {
+			return nil, err
+		}
+	}
+	return collector.entries, nil
+}
+
+// skipArchiveEntry 判断是否跳过压缩包中的文件（隐藏文件和 __MACOSX 元数据）
+func skipArchiveEntry(name string) bool {
+	name = path.Clean(strings.ReplaceAll(name, "\\", "/"))
+	for _, part := range strings.Split(name, "/") {
+		if part == "__MACOSX" || strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// archiveCollector 按解压限制收集压缩包中的文件
+type archiveCollector struct {
+	limits  ArchiveLimits
+	entries []ArchiveEntry
+	total   int64
+}
+
+// add 读取一个文件，超过文件数或大小限制时返回错误
+func (c *archiveCollector) add(name string, r io.Reader) error {
+	if len(c.entries) >= c.limits.MaxEntries {
+		return fmt.Errorf("压缩包中的文件数超过上限 %d", c.limits.MaxEntries)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(r, c.limits.MaxEntryBytes+1))
+	if err != nil {
+		return fmt.Errorf("读取压缩包中的 %s 失败: %w", name, err)
+	}
+	if int64(len(content)) > c.limits.MaxEntryBytes {
+		return fmt.Errorf("压缩包中的 %s 解压后超过单个文件的大小上限 %d MB", name, c.limits.MaxEntryBytes/1024/1024)
+	}
+	c.total += int64(len(content))
+	if c.total > c.limits.MaxTotalBytes {
+		return fmt.Errorf("压缩包解压后的总大小超过上限 %d MB", c.limits.MaxTotalBytes/1024/1024)
+	}
+
+	c.entries = append(c.entries, ArchiveEntry{Name: name, Content: content})
+	return nil
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+)
+
+type testArchiveFile struct {
+	name    string
+	content string
+}
+
+func buildZip(t *testing.T, files []testArchiveFile) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, f := range files {
+		fw, err := w.Create(f.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(f.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func buildTarGz(t *testing.T, files []testArchiveFile) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gz)
+	for _, f := range files {
+		header := &tar.Header{Name: f.name, Mode: 0644, Size: int64(len(f.content)), Typeflag: tar.TypeReg}
+		if err := w.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(f.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractArchive(t *testing.T) {
+	files := []testArchiveFile{
+		{"a.jsonl", strings.Repeat("a", 10)},
+		{"dir/b.jsonl", strings.Repeat("b", 20)},
+		{".hidden.jsonl", "x"},
+		{"__MACOSX/a.jsonl", "x"},
+	}
+	limits := ArchiveLimits{MaxEntries: 10, MaxEntryBytes: 100, MaxTotalBytes: 1000}
+
+	tests := []struct {
+		name      string
+		filename  string
+		files     []testArchiveFile
+		limits    ArchiveLimits
+		wantNames []string
+		wantErr   string
+	}{
+		{"zip", "data.zip", files, limits, []string{"a.jsonl", "dir/b.jsonl"}, ""},
+		{"tar.gz", "data.tar.gz", files, limits, []string{"a.jsonl", "dir/b.jsonl"}, ""},
+		{"tgz", "DATA.TGZ", files, limits, []string{"a.jsonl", "dir/b.jsonl"}, ""},
+		{"too many entries", "data.zip", files, ArchiveLimits{MaxEntries: 1, MaxEntryBytes: 100, MaxTotalBytes: 1000}, nil, "文件数超过上限"},
+		{"entry too large", "data.zip", files, ArchiveLimits{MaxEntries: 10, MaxEntryBytes: 15, MaxTotalBytes: 1000}, nil, "单个文件的大小上限"},
+		{"entry at limit", "data.tar.gz", files, ArchiveLimits{MaxEntries: 10, MaxEntryBytes: 20, MaxTotalBytes: 30}, []string{"a.jsonl", "dir/b.jsonl"}, ""},
+		{"total too large", "data.tar.gz", files, ArchiveLimits{MaxEntries: 10, MaxEntryBytes: 100, MaxTotalBytes: 29}, nil, "总大小超过上限"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var content []byte
+			if strings.HasSuffix(strings.ToLower(tt.filename), ".zip") {
+				content = buildZip(t, tt.files)
+			} else {
+				content = buildTarGz(t, tt.files)
+			}
+
+			entries, err := ExtractArchive(tt.filename, content, tt.limits)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ExtractArchive() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExtractArchive() error = %v", err)
+			}
+			if len(entries) != len(tt.wantNames) {
+				t.Fatalf("ExtractArchive() returned %d entries, want %d", len(entries), len(tt.wantNames))
+			}
+			for i, entry := range entries {
+				if entry.Name != tt.wantNames[i] {
+					t.Errorf("entry %d name = %q, want %q", i, entry.Name, tt.wantNames[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExtractArchiveInvalid(t *testing.T) {
+	limits := ArchiveLimits{MaxEntries: 10, MaxEntryBytes: 100, MaxTotalBytes: 1000}
+	for _, filename := range []string{"data.zip", "data.tar.gz"} {
+		if _, err := ExtractArchive(filename, []byte("not an archive"), limits); err == nil {
+			t.Errorf("ExtractArchive(%s) with invalid content: expected error", filename)
+		}
+	}
+}
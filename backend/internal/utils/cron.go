@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule 解析后的 cron 表达式（分 时 日 月 周）
+type CronSchedule struct {
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+	// 日和周都不是 * 时按标准 cron 语义取并集
+	dayRestricted     bool
+	weekdayRestricted bool
+}
+
+// cronField 字段取值范围
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+var cronFields = []cronField{
+	{"分钟", 0, 59},
+	{"小时", 0, 23},
+	{"日", 1, 31},
+	{"月", 1, 12},
+	{"星期", 0, 7}, // 0 和 7 都表示周日
+}
+
+// cronMaxSearchYears 查找下一次触发时间的最大跨度（如 2月30日 这种永远不会触发的表达式）
+const cronMaxSearchYears = 5
+
+// ParseCron 解析标准5段 cron 表达式，支持 *、数字、a-b 范围、*/n 和 a-b/n 步长以及逗号分隔的列表
+func ParseCron(expr string) (*CronSchedule, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("cron 表达式需要5段（分 时 日 月 周），实际为 %d 段", len(parts))
+	}
+
+	values := make([]uint64, 5)
+	for i, part := range parts {
+		bits, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		values[i] = bits
+	}
+
+	// 星期中的 7 等同于 0（周日）
+	if values[4]&(1<<7) != 0 {
+		values[4] = (values[4] | 1) &^ (1 << 7)
+	}
+
+	return &CronSchedule{
+		minutes:           values[0],
+		hours:             values[1],
+		days:              values[2],
+		months:            values[3],
+		weekdays:          values[4],
+		dayRestricted:     parts[2] != "*",
+		weekdayRestricted: parts[4] != "*",
+	}, nil
+}
+
+// parseCronField 解析单个字段为位图
+func parseCronField(field string, spec cronField) (uint64, error) {
+	var bits uint64
+
+	for _, item := range strings.Split(field, ",") {
+		rangePart, step := item, 1
+		if idx := strings.Index(item, "/"); idx >= 0 {
+			rangePart = item[:idx]
+			n, err := strconv.Atoi(item[idx+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s字段的步长无效: %s", spec.name, item)
+			}
+			step = n
+		}
+
+		start, end := spec.min, spec.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			lo, err1 := strconv.Atoi(bounds[0])
+			hi, err2 := strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil || lo > hi {
+				return 0, fmt.Errorf("%s字段的范围无效: %s", spec.name, item)
+			}
+			start, end = lo, hi
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("%s字段的取值无效: %s", spec.name, item)
+			}
+			start = n
+			// 单个值带步长（如 5/15）表示从该值开始到最大值
+			if step == 1 {
+				end = n
+			}
+		}
+
+		if start < spec.min || end > spec.max {
+			return 0, fmt.Errorf("%s字段超出范围 %d-%d: %s", spec.name, spec.min, spec.max, item)
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+// Next 返回严格晚于 after 的下一次触发时间（按 after 所在时区计算）；找不到时返回零值
+func (s *CronSchedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronMaxSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if s.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// matchDay 判断日期是否匹配日/星期字段
+func (s *CronSchedule) matchDay(t time.Time) bool {
+	dayMatch := s.days&(1<<uint(t.Day())) != 0
+	weekdayMatch := s.weekdays&(1<<uint(t.Weekday())) != 0
+
+	if s.dayRestricted && s.weekdayRestricted {
+		return dayMatch || weekdayMatch
+	}
+	return dayMatch && weekdayMatch
+}
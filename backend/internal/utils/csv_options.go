@@ -0,0 +1,301 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// CSV 文本编码
+const (
+	CSVEncodingAuto    = "auto" // 按 BOM 和内容自动识别
+	CSVEncodingUTF8    = "utf-8"
+	CSVEncodingGBK     = "gbk" // 按 GB18030 解码（兼容 GBK/GB2312，Excel 中文版默认导出的编码）
+	CSVEncodingUTF16LE = "utf-16le"
+	CSVEncodingUTF16BE = "utf-16be"
+)
+
+// CSV 引号处理方式
+const (
+	CSVQuoteStrict = "strict" // 按 RFC 4180 处理双引号，引号不成对时报错
+	CSVQuoteLazy   = "lazy"   // 容忍字段中间出现的裸引号
+	CSVQuoteNone   = "none"   // 不处理引号，按分隔符直接切分每一行
+)
+
+// maxCSVRowErrors 宽松模式下报告中保留的错误行数
+const maxCSVRowErrors = 100
+
+// csvDelimiters 请求中可用的分隔符（名称或字符本身）
+var csvDelimiters = map[string]rune{
+	"comma":     ',',
+	",":         ',',
+	"tab":       '\t',
+	"\t":        '\t',
+	`\t`:        '\t',
+	"semicolon": ';',
+	";":         ';',
+}
+
+// CSVOptions CSV 解析选项，零值表示自动识别编码和分隔符、严格处理引号
+type CSVOptions struct {
+	Delimiter rune   // 0 表示按表头行自动识别（逗号、制表符、分号）
+	Quote     string // strict（默认）、lazy、none
+	Encoding  string // auto（默认）、utf-8、gbk、utf-16le、utf-16be
+	Lenient   bool   // 宽松模式：跳过无法解析或列数不符的行并记录在报告中，而不是整体失败
+}
+
+// CSVRowError 宽松模式下被跳过的行
+type CSVRowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// CSVParseReport CSV 解析报告
+type CSVParseReport struct {
+	Encoding    string        `json:"encoding"`  // 实际使用的编码
+	Delimiter   string        `json:"delimiter"` // 实际使用的分隔符: comma, tab, semicolon
+	Rows        int           `json:"rows"`      // 成功解析的数据行数（不含表头）
+	SkippedRows int           `json:"skipped_rows"`
+	Errors      []CSVRowError `json:"errors,omitempty"` // 被跳过的行（最多保留 100 条）
+}
+
+// ParseCSVOptions 解析请求中的 CSV 选项，空字符串使用默认值
+// delimiter 可以是 comma/tab/semicolon 或对应的字符，auto 表示自动识别
+func ParseCSVOptions(delimiter, quote, encoding string, lenient bool) (CSVOptions, error) {
+	opts := CSVOptions{Lenient: lenient}
+
+	if delimiter != "" && delimiter != "auto" {
+		d, ok := csvDelimiters[strings.ToLower(delimiter)]
+		if !ok {
+			return opts, fmt.Errorf("不支持的分隔符: %s（可选 comma、tab、semicolon）", delimiter)
+		}
+		opts.Delimiter = d
+	}
+
+	switch strings.ToLower(quote) {
+	case "", CSVQuoteStrict:
+		opts.Quote = CSVQuoteStrict
+	case CSVQuoteLazy, CSVQuoteNone:
+		opts.Quote = strings.ToLower(quote)
+	default:
+		return opts, fmt.Errorf("不支持的引号处理方式: %s（可选 strict、lazy、none）", quote)
+	}
+
+	switch strings.ToLower(strings.ReplaceAll(encoding, "_", "-")) {
+	case "", CSVEncodingAuto:
+		opts.Encoding = CSVEncodingAuto
+	case CSVEncodingUTF8, "utf8":
+		opts.Encoding = CSVEncodingUTF8
+	case CSVEncodingGBK, "gb18030", "gb2312":
+		opts.Encoding = CSVEncodingGBK
+	case CSVEncodingUTF16LE, "utf-16":
+		opts.Encoding = CSVEncodingUTF16LE
+	case CSVEncodingUTF16BE:
+		opts.Encoding = CSVEncodingUTF16BE
+	default:
+		return opts, fmt.Errorf("不支持的编码: %s（可选 auto、utf-8、gbk、utf-16le、utf-16be）", encoding)
+	}
+	return opts, nil
+}
+
+// DecodeText 按指定编码把文本转换为 UTF-8（去掉 BOM），encoding 为 auto 或空时自动识别，返回实际使用的编码
+func DecodeText(content []byte, encoding string) ([]byte, string, error) {
+	if encoding == "" || encoding == CSVEncodingAuto {
+		encoding = detectTextEncoding(content)
+	}
+
+	var decoded []byte
+	var err error
+	switch encoding {
+	case CSVEncodingUTF8:
+		decoded = bytes.TrimPrefix(content, []byte("\xEF\xBB\xBF"))
+	case CSVEncodingGBK:
+		decoded, err = simplifiedchinese.GB18030.NewDecoder().Bytes(content)
+	case CSVEncodingUTF16LE:
+		decoded, err = unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewDecoder().Bytes(content)
+	case CSVEncodingUTF16BE:
+		decoded, err = unicode.UTF16(unicode.BigEndian, unicode.UseBOM).NewDecoder().Bytes(content)
+	default:
+		return nil, "", fmt.Errorf("不支持的编码: %s", encoding)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("按 %s 解码失败: %w", encoding, err)
+	}
+	return decoded, encoding, nil
+}
+
+// detectTextEncoding 按 BOM 和内容识别编码：有 BOM 时按 BOM；合法的 UTF-8 按 UTF-8；
+// 大量 0 字节集中在奇数或偶数位置时按无 BOM 的 UTF-16；其余按 GBK（GB18030）
+func detectTextEncoding(content []byte) string {
+	switch {
+	case bytes.HasPrefix(content, []byte("\xEF\xBB\xBF")):
+		return CSVEncodingUTF8
+	case bytes.HasPrefix(content, []byte("\xFF\xFE")):
+		return CSVEncodingUTF16LE
+	case bytes.HasPrefix(content, []byte("\xFE\xFF")):
+		return CSVEncodingUTF16BE
+	}
+
+	head := content
+	if len(head) > UploadSniffSize {
+		head = head[:UploadSniffSize]
+	}
+	var evenZeros, oddZeros int
+	for i, b := range head {
+		if b != 0 {
+			continue
+		}
+		if i%2 == 0 {
+			evenZeros++
+		} else {
+			oddZeros++
+		}
+	}
+	pairs := len(head) / 2
+	switch {
+	case pairs > 0 && oddZeros > pairs/4 && oddZeros > evenZeros*4:
+		return CSVEncodingUTF16LE
+	case pairs > 0 && evenZeros > pairs/4 && evenZeros > oddZeros*4:
+		return CSVEncodingUTF16BE
+	}
+
+	// 截断可能切在多字节字符中间，只检查到最后一个完整的换行
+	if len(head) < len(content) {
+		if i := bytes.LastIndexByte(head, '\n'); i >= 0 {
+			head = head[:i]
+		}
+	}
+	if utf8.Valid(head) {
+		return CSVEncodingUTF8
+	}
+	return CSVEncodingGBK
+}
+
+// detectCSVDelimiter 按表头行中（引号外）出现次数最多的字符识别分隔符，都没有时使用逗号
+func detectCSVDelimiter(text []byte) rune {
+	line := text
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+
+	counts := make(map[rune]int)
+	inQuotes := false
+	for _, r := range string(line) {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case !inQuotes && (r == ',' || r == '\t' || r == ';'):
+			counts[r]++
+		}
+	}
+
+	best := ','
+	for _, r := range []rune{'\t', ';'} {
+		if counts[r] > counts[best] {
+			best = r
+		}
+	}
+	return best
+}
+
+// csvDelimiterName 返回分隔符名称
+func csvDelimiterName(d rune) string {
+	switch d {
+	case ',':
+		return "comma"
+	case '\t':
+		return "tab"
+	case ';':
+		return "semicolon"
+	}
+	return string(d)
+}
+
+// ReadCSVTable 按选项解码并解析 CSV，返回表头、数据行和解析报告
+// 严格模式下遇到无法解析或列数与表头不符的行返回错误；宽松模式下跳过这些行并记录在报告中
+func ReadCSVTable(content []byte, opts CSVOptions) ([]string, [][]string, *CSVParseReport, error) {
+	text, encoding, err := DecodeText(content, opts.Encoding)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	delimiter := opts.Delimiter
+	if delimiter == 0 {
+		delimiter = detectCSVDelimiter(text)
+	}
+	report := &CSVParseReport{Encoding: encoding, Delimiter: csvDelimiterName(delimiter)}
+
+	next := csvRowReader(text, delimiter, opts.Quote)
+	headers, _, err := next()
+	if err == io.EOF {
+		return nil, nil, nil, fmt.Errorf("CSV文件为空")
+	}
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("读取CSV表头失败: %w", err)
+	}
+
+	var rows [][]string
+	for {
+		row, line, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err == nil && len(row) != len(headers) {
+			err = fmt.Errorf("有 %d 列，表头有 %d 列", len(row), len(headers))
+		}
+		if err != nil {
+			if !opts.Lenient {
+				return nil, nil, nil, fmt.Errorf("读取CSV第 %d 行失败: %w", line, err)
+			}
+			report.SkippedRows++
+			if len(report.Errors) < maxCSVRowErrors {
+				report.Errors = append(report.Errors, CSVRowError{Line: line, Error: err.Error()})
+			}
+			continue
+		}
+		rows = append(rows, row)
+	}
+	report.Rows = len(rows)
+	return headers, rows, report, nil
+}
+
+// csvRowReader 返回逐行读取 CSV 记录的函数（记录、起始行号、错误），读完时返回 io.EOF
+func csvRowReader(text []byte, delimiter rune, quote string) func() ([]string, int, error) {
+	if quote == CSVQuoteNone {
+		lines := strings.Split(strings.ReplaceAll(string(text), "\r\n", "\n"), "\n")
+		pos := 0
+		return func() ([]string, int, error) {
+			for pos < len(lines) {
+				line := lines[pos]
+				pos++
+				if strings.TrimSpace(line) != "" {
+					return strings.Split(line, string(delimiter)), pos, nil
+				}
+			}
+			return nil, pos, io.EOF
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(text))
+	reader.Comma = delimiter
+	reader.LazyQuotes = quote == CSVQuoteLazy
+	reader.FieldsPerRecord = -1 // 列数由 ReadCSVTable 与表头比较
+	return func() ([]string, int, error) {
+		row, err := reader.Read()
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, parseErr.StartLine, parseErr.Err
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		line, _ := reader.FieldPos(0)
+		return row, line, nil
+	}
+}
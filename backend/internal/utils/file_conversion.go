@@ -5,7 +5,6 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
-	"io"
 	"strings"
 )
 
@@ -21,137 +20,75 @@ type JSONLData struct {
 	Turns []Turn                 `json:"turns"`
 }
 
-// ConvertCSVToJSONL 将CSV内容转换为JSONL格式
+// ConvertCSVToJSONL 将CSV内容转换为JSONL格式（自动识别编码和分隔符）
 func ConvertCSVToJSONL(csvContent []byte) ([]byte, error) {
-	// 解码CSV内容
-	csvText := string(csvContent)
-	if strings.HasPrefix(csvText, "\xEF\xBB\xBF") {
-		csvText = strings.TrimPrefix(csvText, "\xEF\xBB\xBF")
-	}
-
-	reader := csv.NewReader(strings.NewReader(csvText))
+	content, _, err := ConvertCSVToJSONLWithOptions(csvContent, CSVOptions{})
+	return content, err
+}
 
-	// 读取列名
-	headers, err := reader.Read()
+// ConvertCSVToJSONLWithOptions 按解析选项将CSV内容转换为JSONL格式，返回解析报告
+func ConvertCSVToJSONLWithOptions(csvContent []byte, opts CSVOptions) ([]byte, *CSVParseReport, error) {
+	headers, rows, report, err := ReadCSVTable(csvContent, opts)
 	if err != nil {
-		return nil, fmt.Errorf("读取CSV表头失败: %w", err)
+		return nil, nil, err
 	}
 
 	// 验证第一列是否为 meta
 	if len(headers) == 0 || headers[0] != "meta" {
-		return nil, fmt.Errorf("CSV 第一列必须命名为 'meta'")
+		return nil, nil, fmt.Errorf("CSV 第一列必须命名为 'meta'")
 	}
 
-	// 提取所有 Human 和 Assistant 列的索引
-	var humanIndices, assistantIndices []int
-	for i, col := range headers {
-		if col == "Human" {
-			humanIndices = append(humanIndices, i)
-		} else if col == "Assistant" {
-			assistantIndices = append(assistantIndices, i)
-		}
+	content, err := ConvertTableToJSONL(headers, rows)
+	if err != nil {
+		return nil, nil, err
 	}
+	return content, report, nil
+}
 
-	if len(humanIndices) != len(assistantIndices) {
-		return nil, fmt.Errorf("Human 和 Assistant 列数量不匹配")
+// ConvertJSONLToCSV 将JSONL内容转换为CSV格式
+func ConvertJSONLToCSV(jsonlContent []byte) ([]byte, error) {
+	headers, allRows, err := ConvertJSONLToTable(jsonlContent)
+	if err != nil {
+		return nil, err
 	}
 
-	// 记录当前活跃的 meta
-	currentActiveMeta := ""
-	var jsonlLines []string
-
-	for {
-		row, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("读取CSV行失败: %w", err)
-		}
-
-		// 跳过空行
-		if len(row) == 0 {
-			continue
-		}
-
-		// 处理当前行的 meta（支持共享逻辑）
-		rowMeta := ""
-		if len(row) > 0 {
-			rowMeta = strings.TrimSpace(row[0])
-		}
-		if rowMeta != "" {
-			currentActiveMeta = rowMeta
-		}
-
-		// 提取多轮对话内容
-		var turns []Turn
-		for i := 0; i < len(humanIndices) && i < len(assistantIndices); i++ {
-			hIdx := humanIndices[i]
-			aIdx := assistantIndices[i]
-
-			// 添加 Human 内容（非空才添加）
-			if hIdx < len(row) && strings.TrimSpace(row[hIdx]) != "" {
-				turns = append(turns, Turn{
-					Role: "Human",
-					Text: strings.TrimSpace(row[hIdx]),
-				})
-			}
-
-			// 添加 Assistant 内容（非空才添加）
-			if aIdx < len(row) && strings.TrimSpace(row[aIdx]) != "" {
-				turns = append(turns, Turn{
-					Role: "Assistant",
-					Text: strings.TrimSpace(row[aIdx]),
-				})
-			}
-		}
-
-		// 构造输出对象
-		outputObj := JSONLData{
-			Meta: map[string]interface{}{
-				"meta_description": currentActiveMeta,
-			},
-			Turns: turns,
-		}
-
-		// 转换为JSON
-		jsonBytes, err := json.Marshal(outputObj)
-		if err != nil {
-			return nil, fmt.Errorf("JSON序列化失败: %w", err)
-		}
+	// 写入CSV
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(headers); err != nil {
+		return nil, fmt.Errorf("写入CSV表头失败: %w", err)
+	}
+	if err := writer.WriteAll(allRows); err != nil {
+		return nil, fmt.Errorf("写入CSV数据失败: %w", err)
+	}
+	writer.Flush()
 
-		jsonlLines = append(jsonlLines, string(jsonBytes))
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("CSV写入错误: %w", err)
 	}
 
-	// 合并为JSONL内容
-	jsonlContent := strings.Join(jsonlLines, "\n") + "\n"
-	return []byte(jsonlContent), nil
+	// 添加 UTF-8 BOM
+	output := buf.Bytes()
+	return append([]byte{0xEF, 0xBB, 0xBF}, output...), nil
 }
 
-// ConvertJSONLToCSV 将JSONL内容转换为CSV格式
-func ConvertJSONLToCSV(jsonlContent []byte) ([]byte, error) {
-	// 解码JSONL内容
-	jsonlText := string(jsonlContent)
-
+// ConvertJSONLToTable 将JSONL内容转换为 meta/Human/Assistant 表格（表头和各行）
+// 相同 meta 的对话归为一组，组内只有第一行填写 meta
+func ConvertJSONLToTable(jsonlContent []byte) ([]string, [][]string, error) {
 	// 用字典归类：key=meta值，value=该meta对应的所有行数据
 	type Conversation struct {
-		Meta         string
-		HumanTexts   []string
+		Meta           string
+		HumanTexts     []string
 		AssistantTexts []string
 	}
 
 	metaGroups := make(map[string][]*Conversation)
 
-	lines := strings.Split(strings.TrimSpace(jsonlText), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
+	// 逐行扫描，不把整个内容转换为字符串再切分
+	err := ScanJSONLLines(bytes.NewReader(jsonlContent), func(_ int, line []byte) error {
 		var data JSONLData
-		if err := json.Unmarshal([]byte(line), &data); err != nil {
-			return nil, fmt.Errorf("解析JSONL失败: %w", err)
+		if err := json.Unmarshal(line, &data); err != nil {
+			return fmt.Errorf("解析JSONL失败: %w", err)
 		}
 
 		// 提取meta
@@ -206,6 +143,10 @@ func ConvertJSONLToCSV(jsonlContent []byte) ([]byte, error) {
 			metaGroups[meta] = []*Conversation{}
 		}
 		metaGroups[meta] = append(metaGroups[meta], conv)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// 整理所有行数据
@@ -242,7 +183,7 @@ func ConvertJSONLToCSV(jsonlContent []byte) ([]byte, error) {
 	}
 
 	if len(allRows) == 0 {
-		return nil, fmt.Errorf("没有有效的数据")
+		return nil, nil, fmt.Errorf("没有有效的数据")
 	}
 
 	// 生成表头
@@ -260,28 +201,11 @@ func ConvertJSONLToCSV(jsonlContent []byte) ([]byte, error) {
 	}
 
 	// 补齐所有行的长度
-	for _, row := range allRows {
-		for len(row) < len(headers) {
-			row = append(row, "")
+	for i := range allRows {
+		for len(allRows[i]) < len(headers) {
+			allRows[i] = append(allRows[i], "")
 		}
 	}
 
-	// 写入CSV
-	var buf bytes.Buffer
-	writer := csv.NewWriter(&buf)
-	if err := writer.Write(headers); err != nil {
-		return nil, fmt.Errorf("写入CSV表头失败: %w", err)
-	}
-	if err := writer.WriteAll(allRows); err != nil {
-		return nil, fmt.Errorf("写入CSV数据失败: %w", err)
-	}
-	writer.Flush()
-
-	if err := writer.Error(); err != nil {
-		return nil, fmt.Errorf("CSV写入错误: %w", err)
-	}
-
-	// 添加 UTF-8 BOM
-	output := buf.Bytes()
-	return append([]byte{0xEF, 0xBB, 0xBF}, output...), nil
+	return headers, allRows, nil
 }
@@ -1,33 +1,254 @@
 package utils
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 )
 
-// ParseJSONL 解析JSONL格式
+// ParseJSONL 解析JSONL格式（逐行解码，不复制整个内容）
+// 需要全部数据项时使用；只需遍历时优先使用 ScanJSONL 或 JSONLIterator
 func ParseJSONL(data []byte) ([]map[string]interface{}, error) {
-	lines := strings.Split(string(data), "\n")
 	var results []map[string]interface{}
+	err := ScanJSONL(bytes.NewReader(data), func(_ int, item map[string]interface{}) error {
+		results = append(results, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// JSONLIterator 逐行迭代JSONL的迭代器，不在内存中保留全部数据
+//
+//	it := NewJSONLIterator(r)
+//	for it.Next() {
+//		item := it.Item()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type JSONLIterator struct {
+	scanner *bufio.Scanner
+	index   int
+	item    map[string]interface{}
+	err     error
+}
+
+// NewJSONLIterator 创建JSONL迭代器
+func NewJSONLIterator(r io.Reader) *JSONLIterator {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLLineSize)
+	return &JSONLIterator{scanner: scanner, index: -1}
+}
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
+// Next 读取并解码下一个非空行，读完或出错时返回 false（错误通过 Err 获取）
+func (it *JSONLIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for it.scanner.Scan() {
+		line := bytes.TrimSpace(it.scanner.Bytes())
+		if len(line) == 0 {
 			continue
 		}
+		it.index++
+		it.item = nil
+		if err := json.Unmarshal(line, &it.item); err != nil {
+			it.err = fmt.Errorf("解析第 %d 条数据失败: %w", it.index+1, err)
+			return false
+		}
+		return true
+	}
+	if err := it.scanner.Err(); err != nil {
+		it.err = fmt.Errorf("读取文件内容失败: %w", err)
+	}
+	return false
+}
+
+// Index 当前数据项的序号（与 ScanJSONL 一致）
+func (it *JSONLIterator) Index() int {
+	return it.index
+}
+
+// Item 当前数据项
+func (it *JSONLIterator) Item() map[string]interface{} {
+	return it.item
+}
+
+// Err 迭代过程中的错误
+func (it *JSONLIterator) Err() error {
+	return it.err
+}
 
+// ScanJSONL 流式逐行解析JSONL，不在内存中保留全部数据
+// 回调中的 index 与 ParseJSONL 返回结果的下标一致；回调返回错误时停止扫描
+func ScanJSONL(r io.Reader, fn func(index int, item map[string]interface{}) error) error {
+	return ScanJSONLLines(r, func(index int, line []byte) error {
 		var item map[string]interface{}
-		if err := json.Unmarshal([]byte(line), &item); err != nil {
-			return nil, fmt.Errorf("解析失败: %w", err)
+		if err := json.Unmarshal(line, &item); err != nil {
+			return fmt.Errorf("解析失败: %w", err)
 		}
-		results = append(results, item)
+		return fn(index, item)
+	})
+}
+
+// ScanJSONLLines 流式逐行读取JSONL的原始行文本（不做JSON解码），跳过空行
+// 回调中的 line 只在回调期间有效；index 与 ScanJSONL 一致
+func ScanJSONLLines(r io.Reader, fn func(index int, line []byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLLineSize)
+
+	index := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := fn(index, line); err != nil {
+			return err
+		}
+		index++
 	}
 
-	return results, nil
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("读取文件内容失败: %w", err)
+	}
+	return nil
+}
+
+// maxJSONLLineSize JSONL单行最大长度
+const maxJSONLLineSize = 64 * 1024 * 1024
+
+// IndexedItem 带原始行索引的数据项
+type IndexedItem struct {
+	Index int
+	Data  map[string]interface{}
+}
+
+// ParseJSONLWindow 按窗口解析JSONL：只对 [offset, offset+limit) 范围内的行做JSON解码
+// query 不为空时只统计/返回包含 query 的行（含 \uXXXX 等转义的行按解码后的字符串值匹配）；limit <= 0 表示不限制
+// 返回的 Index 为该行在全部非空行中的序号，与 ParseJSONL 的下标一致
+func ParseJSONLWindow(data []byte, offset, limit int, query string) ([]IndexedItem, int, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLLineSize)
+
+	items := []IndexedItem{}
+	index := -1
+	matched := 0
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		index++
+
+		if query != "" && !jsonLineContains(line, query) {
+			continue
+		}
+		matched++
+
+		position := matched - 1
+		if position < offset || (limit > 0 && position >= offset+limit) {
+			continue
+		}
+
+		var item map[string]interface{}
+		if err := json.Unmarshal(line, &item); err != nil {
+			return nil, 0, fmt.Errorf("解析第 %d 行失败: %w", index+1, err)
+		}
+		items = append(items, IndexedItem{Index: index, Data: item})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("读取文件内容失败: %w", err)
+	}
+
+	return items, matched, nil
+}
+
+// jsonLineContains 判断JSONL行是否包含 query
+// 先按原始字节匹配；行内有转义序列（如非ASCII字符写成 \uXXXX）时再解码，逐个比较其中的字符串值和键名
+func jsonLineContains(line []byte, query string) bool {
+	if bytes.Contains(line, []byte(query)) {
+		return true
+	}
+	if bytes.IndexByte(line, '\\') < 0 {
+		return false
+	}
+	var value interface{}
+	if err := json.Unmarshal(line, &value); err != nil {
+		return false
+	}
+	return jsonValueContains(value, query)
+}
+
+// jsonValueContains 递归判断JSON值中的字符串（含对象键名）是否包含 query
+func jsonValueContains(value interface{}, query string) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.Contains(v, query)
+	case map[string]interface{}:
+		for key, item := range v {
+			if strings.Contains(key, query) || jsonValueContains(item, query) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if jsonValueContains(item, query) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CountJSONLLines 统计JSONL内容中的非空行数（不做JSON解码，也不切分出行切片）
+func CountJSONLLines(data []byte) int {
+	count := 0
+	for len(data) > 0 {
+		line := data
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			line, data = data[:i], data[i+1:]
+		} else {
+			data = nil
+		}
+		if len(bytes.TrimSpace(line)) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// RewriteJSONLLines 流式改写JSONL：按顺序把每个非空行（index 与 ScanJSONL 一致）交给 fn，
+// 写出 fn 返回的行（返回空表示删除该行，可返回多行用于插入）；未改动的行原样保留，不做JSON解码
+// 返回改写后的内容和原内容的非空行数
+func RewriteJSONLLines(data []byte, fn func(index int, line []byte) ([][]byte, error)) ([]byte, int, error) {
+	var buf bytes.Buffer
+	buf.Grow(len(data))
+	total := 0
+	err := ScanJSONLLines(bytes.NewReader(data), func(index int, line []byte) error {
+		total++
+		lines, err := fn(index, line)
+		if err != nil {
+			return err
+		}
+		for _, l := range lines {
+			buf.Write(l)
+			buf.WriteByte('\n')
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), total, nil
 }
 
 // ParseJSONString 解析单个JSON字符串
@@ -131,9 +352,61 @@ func ConvertToCSV(data []map[string]interface{}) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// DetectContentType 检测内容类型
+// WriteJSONLAsCSV 流式将JSONL数据项写为CSV（列为全部数据项的字段并集，按名称排序），返回数据行数
+// 扫描两遍：第一遍收集字段，第二遍逐行解码并写出，内存中只保留当前行
+func WriteJSONLAsCSV(w io.Writer, data []byte) (int, error) {
+	headersSet := make(map[string]bool)
+	err := ScanJSONL(bytes.NewReader(data), func(_ int, item map[string]interface{}) error {
+		for key := range item {
+			headersSet[key] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(headersSet) == 0 {
+		return 0, nil
+	}
+
+	headers := make([]string, 0, len(headersSet))
+	for key := range headersSet {
+		headers = append(headers, key)
+	}
+	sort.Strings(headers)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(headers); err != nil {
+		return 0, fmt.Errorf("写入CSV标题失败: %w", err)
+	}
+
+	rows := 0
+	record := make([]string, len(headers))
+	it := NewJSONLIterator(bytes.NewReader(data))
+	for it.Next() {
+		item := it.Item()
+		for i, header := range headers {
+			record[i] = fmt.Sprintf("%v", item[header])
+		}
+		if err := writer.Write(record); err != nil {
+			return 0, fmt.Errorf("写入CSV数据失败: %w", err)
+		}
+		rows++
+	}
+	if err := it.Err(); err != nil {
+		return 0, err
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return 0, fmt.Errorf("CSV写入失败: %w", err)
+	}
+	return rows, nil
+}
+
+// DetectContentType 检测内容类型：JSON（对象或数组）、CSV、纯文本，空内容按 JSONL 处理
 func DetectContentType(data []byte) string {
-	trimmed := strings.TrimSpace(string(data))
+	trimmed := strings.TrimSpace(strings.TrimPrefix(string(data), "\xEF\xBB\xBF"))
 
 	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
 		return "application/json"
@@ -145,17 +418,26 @@ func DetectContentType(data []byte) string {
 		return "text/csv"
 	}
 
+	// 不以 { 或 [ 开头的其他内容为纯文本
+	if trimmed != "" {
+		return "text/plain"
+	}
+
 	// 默认为JSONL
 	return "application/x-jsonlines"
 }
 
-// ReadJSONLines 读取JSONL格式的数据
+// ReadJSONLines 读取JSONL格式的数据（边读边解码，不先读入整个内容）
 func ReadJSONLines(r io.Reader) ([]map[string]interface{}, error) {
-	data, err := io.ReadAll(r)
-	if err != nil {
+	var results []map[string]interface{}
+	it := NewJSONLIterator(r)
+	for it.Next() {
+		results = append(results, it.Item())
+	}
+	if err := it.Err(); err != nil {
 		return nil, err
 	}
-	return ParseJSONL(data)
+	return results, nil
 }
 
 // WriteJSONLines 写入JSONL格式的数据
@@ -0,0 +1,51 @@
+package utils
+
+import "testing"
+
+func TestParseJSONLWindowQuery(t *testing.T) {
+	data := []byte(`{"meta":{"meta_description":"ascii"},"turns":[{"role":"Human","text":"hello world"}]}
+{"meta":{"meta_description":"\u4e2d\u6587"},"turns":[{"role":"Human","text":"\u4f60\u597d"}]}
+{"meta":{"meta_description":"raw"},"turns":[{"role":"Human","text":"你好，世界"}]}
+
+{"meta":{"meta_description":"quote"},"turns":[{"role":"Human","text":"say \"hi\""}]}
+`)
+
+	tests := []struct {
+		query       string
+		wantIndices []int
+	}{
+		{"", []int{0, 1, 2, 3}},
+		{"hello", []int{0}},
+		{"你好", []int{1, 2}},
+		{"中文", []int{1}},
+		{`"hi"`, []int{3}},
+		{"turns", []int{0, 1, 2, 3}},
+		{"missing", nil},
+	}
+	for _, tt := range tests {
+		items, matched, err := ParseJSONLWindow(data, 0, 0, tt.query)
+		if err != nil {
+			t.Fatalf("ParseJSONLWindow(%q) error = %v", tt.query, err)
+		}
+		if matched != len(tt.wantIndices) || len(items) != len(tt.wantIndices) {
+			t.Fatalf("ParseJSONLWindow(%q) matched %d, returned %d items, want %d", tt.query, matched, len(items), len(tt.wantIndices))
+		}
+		for i, item := range items {
+			if item.Index != tt.wantIndices[i] {
+				t.Errorf("ParseJSONLWindow(%q) item %d index = %d, want %d", tt.query, i, item.Index, tt.wantIndices[i])
+			}
+		}
+	}
+}
+
+func TestParseJSONLWindowPaging(t *testing.T) {
+	data := []byte("{\"n\":0}\n{\"n\":1}\n{\"n\":2}\n{\"n\":3}\n")
+
+	items, matched, err := ParseJSONLWindow(data, 1, 2, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if matched != 4 || len(items) != 2 || items[0].Index != 1 || items[1].Index != 2 {
+		t.Fatalf("ParseJSONLWindow(offset=1, limit=2) = %+v (matched %d)", items, matched)
+	}
+}
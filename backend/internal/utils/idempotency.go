@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GenerationIdempotencyKey 计算生成数据的幂等键
+// 与 Python 端 database/generated_data_service.py 中的 build_idempotency_key 保持一致：
+// sha256("<task_id>|<seed_hash>|<seed_index>|<variant_index>")
+// seedIndex 为样本在输入中的序号，内容相同的种子样本不会共用同一个键
+func GenerationIdempotencyKey(taskID string, seedHash string, seedIndex int, variantIndex int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d|%d", taskID, seedHash, seedIndex, variantIndex)))
+	return hex.EncodeToString(sum[:])
+}
+
+// SeedHash 计算种子样本指纹，用于将生成数据（seed_hash 列）对应回源文件中的样本
+// 与 Python 端 develop/single_gen.py 中的 compute_seed_hash 保持一致：
+// sha256(json.dumps(sample, ensure_ascii=False, sort_keys=True) + "#round=<round_index>")
+// line 为样本的原始 JSON 文本，roundIndex 从 0 开始（生成数据 meta.round 减 1）
+func SeedHash(line []byte, roundIndex int) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	var sample interface{}
+	if err := decoder.Decode(&sample); err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	writePythonJSON(&builder, sample)
+	builder.WriteString("#round=")
+	builder.WriteString(strconv.Itoa(roundIndex))
+
+	sum := sha256.Sum256([]byte(builder.String()))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// writePythonJSON 按 Python json.dumps(ensure_ascii=False, sort_keys=True) 的格式序列化
+// 分隔符为 ", " 和 ": "，字符串只转义引号、反斜杠和控制字符
+func writePythonJSON(builder *strings.Builder, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		builder.WriteString("null")
+	case bool:
+		builder.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		builder.WriteString(pythonNumber(v))
+	case string:
+		writePythonString(builder, v)
+	case []interface{}:
+		builder.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+			writePythonJSON(builder, item)
+		}
+		builder.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		builder.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+			writePythonString(builder, key)
+			builder.WriteString(": ")
+			writePythonJSON(builder, v[key])
+		}
+		builder.WriteByte('}')
+	}
+}
+
+// pythonNumber 按 Python 解析后再输出的形式格式化数字：整数原样输出，浮点数使用 repr 格式（如 1.0、1e-05、1e+16）
+func pythonNumber(number json.Number) string {
+	text := number.String()
+	if !strings.ContainsAny(text, ".eE") {
+		if n, ok := new(big.Int).SetString(text, 10); ok {
+			return n.String()
+		}
+		return text
+	}
+
+	f, err := number.Float64()
+	if err != nil {
+		return text
+	}
+	scientific := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, expText, _ := strings.Cut(scientific, "e")
+	exp, _ := strconv.Atoi(expText)
+	if exp < -4 || exp >= 16 {
+		sign := "+"
+		if exp < 0 {
+			sign, exp = "-", -exp
+		}
+		return fmt.Sprintf("%se%s%02d", mantissa, sign, exp)
+	}
+	fixed := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(fixed, ".") {
+		fixed += ".0"
+	}
+	return fixed
+}
+
+// writePythonString 按 Python json.dumps(ensure_ascii=False) 的规则输出字符串
+func writePythonString(builder *strings.Builder, s string) {
+	builder.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			builder.WriteString(`\"`)
+		case '\\':
+			builder.WriteString(`\\`)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\t':
+			builder.WriteString(`\t`)
+		case '\b':
+			builder.WriteString(`\b`)
+		case '\f':
+			builder.WriteString(`\f`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(builder, `\u%04x`, r)
+			} else {
+				builder.WriteRune(r)
+			}
+		}
+	}
+	builder.WriteByte('"')
+}
@@ -0,0 +1,29 @@
+package utils
+
+import "testing"
+
+func TestGenerationIdempotencyKey(t *testing.T) {
+	// 与 Python 端 build_idempotency_key("task-1", "abc", 0, 1) 的结果一致
+	want := "c8b2eb75368edfd6906fcdb977988902f8b0e145f56c976ef085cd5d5781cd6a"
+	if got := GenerationIdempotencyKey("task-1", "abc", 0, 1); got != want {
+		t.Fatalf("GenerationIdempotencyKey = %s, want %s", got, want)
+	}
+
+	tests := []struct {
+		name         string
+		taskID       string
+		seedHash     string
+		seedIndex    int
+		variantIndex int
+	}{
+		{"different task", "task-2", "abc", 0, 1},
+		{"duplicate seed at another index", "task-1", "abc", 1, 1},
+		{"different variant", "task-1", "abc", 0, 2},
+		{"different seed", "task-1", "abd", 0, 1},
+	}
+	for _, tt := range tests {
+		if GenerationIdempotencyKey(tt.taskID, tt.seedHash, tt.seedIndex, tt.variantIndex) == want {
+			t.Errorf("%s: produced the same key", tt.name)
+		}
+	}
+}
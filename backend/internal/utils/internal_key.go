@@ -0,0 +1,19 @@
+package utils
+
+import "os"
+
+// defaultInternalAPIKey 未设置 INTERNAL_API_KEY 时的默认内部密钥（生产环境应该从环境变量设置）
+const defaultInternalAPIKey = "gen-internal-api-key-2024"
+
+// InternalAPIKey 获取 Python 工作进程调用后端使用的内部密钥
+func InternalAPIKey() string {
+	if key := os.Getenv("INTERNAL_API_KEY"); key != "" {
+		return key
+	}
+	return defaultInternalAPIKey
+}
+
+// IsDefaultInternalAPIKey 是否仍在使用默认内部密钥（未设置 INTERNAL_API_KEY 或设置为默认值）
+func IsDefaultInternalAPIKey() bool {
+	return InternalAPIKey() == defaultInternalAPIKey
+}
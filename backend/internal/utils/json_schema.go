@@ -0,0 +1,192 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseJSONSchema 解析 JSON Schema 文本，schema 必须是 JSON 对象
+func ParseJSONSchema(text string) (map[string]interface{}, error) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &schema); err != nil {
+		return nil, fmt.Errorf("JSON Schema 必须是 JSON 对象: %w", err)
+	}
+	return schema, nil
+}
+
+// ValidateJSONSchema 按 JSON Schema 的常用子集校验数据，返回第一处不满足的位置和原因
+// 支持 type、enum、const、required、properties、additionalProperties（false）、items、
+// minItems/maxItems、minLength/maxLength、pattern、minimum/maximum，其他关键字忽略
+func ValidateJSONSchema(schema map[string]interface{}, value interface{}) error {
+	return validateSchemaNode(schema, value, "$")
+}
+
+// validateSchemaNode 校验 path 处的值
+func validateSchemaNode(schema map[string]interface{}, value interface{}, path string) error {
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if jsonTypeMatches(t, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: 类型应为 %s", path, strings.Join(types, "|"))
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		matched := false
+		for _, candidate := range enum {
+			if reflect.DeepEqual(candidate, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: 不是允许的取值", path)
+		}
+	}
+	if expected, ok := schema["const"]; ok && !reflect.DeepEqual(expected, value) {
+		return fmt.Errorf("%s: 取值应为 %v", path, expected)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return validateSchemaObject(schema, v, path)
+	case []interface{}:
+		if n, ok := schemaNumber(schema["minItems"]); ok && float64(len(v)) < n {
+			return fmt.Errorf("%s: 元素数少于 %v", path, n)
+		}
+		if n, ok := schemaNumber(schema["maxItems"]); ok && float64(len(v)) > n {
+			return fmt.Errorf("%s: 元素数多于 %v", path, n)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := validateSchemaNode(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		length := float64(utf8.RuneCountInString(v))
+		if n, ok := schemaNumber(schema["minLength"]); ok && length < n {
+			return fmt.Errorf("%s: 长度少于 %v", path, n)
+		}
+		if n, ok := schemaNumber(schema["maxLength"]); ok && length > n {
+			return fmt.Errorf("%s: 长度超过 %v", path, n)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("%s: 无效的 pattern: %v", path, err)
+			}
+			if !re.MatchString(v) {
+				return fmt.Errorf("%s: 不匹配 %s", path, pattern)
+			}
+		}
+	case float64:
+		if n, ok := schemaNumber(schema["minimum"]); ok && v < n {
+			return fmt.Errorf("%s: 小于 %v", path, n)
+		}
+		if n, ok := schemaNumber(schema["maximum"]); ok && v > n {
+			return fmt.Errorf("%s: 大于 %v", path, n)
+		}
+	}
+	return nil
+}
+
+// validateSchemaObject 校验对象的必填字段和各属性
+func validateSchemaObject(schema map[string]interface{}, obj map[string]interface{}, path string) error {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, nameRaw := range required {
+			name, _ := nameRaw.(string)
+			if _, exists := obj[name]; !exists {
+				return fmt.Errorf("%s: 缺少必填字段 %s", path, name)
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+		for key := range obj {
+			if _, declared := properties[key]; !declared {
+				return fmt.Errorf("%s: 不允许的字段 %s", path, key)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, exists := obj[key]
+		propSchema, ok := properties[key].(map[string]interface{})
+		if !exists || !ok {
+			continue
+		}
+		if err := validateSchemaNode(propSchema, value, path+"."+key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schemaTypes 读取 type 关键字（字符串或字符串数组）
+func schemaTypes(raw interface{}) []string {
+	switch t := raw.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// jsonTypeMatches 判断值是否属于 JSON Schema 类型
+func jsonTypeMatches(t string, value interface{}) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return false
+}
+
+// schemaNumber 读取数值关键字
+func schemaNumber(raw interface{}) (float64, bool) {
+	n, ok := raw.(float64)
+	return n, ok
+}
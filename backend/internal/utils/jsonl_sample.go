@@ -0,0 +1,252 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// 抽样策略
+const (
+	SampleStrategyHead       = "head"       // 取前 n 行
+	SampleStrategyRandom     = "random"     // 均匀随机抽取 n 行
+	SampleStrategyStratified = "stratified" // 按 meta 分层，各层按行数比例抽取
+)
+
+// SampleOptions JSONL 抽样参数
+type SampleOptions struct {
+	Strategy string
+	N        int
+	Seed     int64  // 随机种子（random、stratified 使用），相同种子对同一文件抽到相同的行
+	Field    string // 分层依据的 meta 字段，为空时按整个 meta 分层
+}
+
+// SampleStratum 分层抽样中的一层
+type SampleStratum struct {
+	Key     string // meta（或 meta 字段）的值，非字符串按 JSON 编码，缺失时为空字符串
+	Total   int
+	Sampled int
+}
+
+// SampleResult 抽样结果
+type SampleResult struct {
+	Items  []IndexedItem // 按行号排序
+	Total  int           // 文件的非空行数
+	Strata []SampleStratum
+}
+
+// sampledLine 被抽中的原始行
+type sampledLine struct {
+	index int
+	line  []byte
+}
+
+// SampleJSONL 流式抽样 JSONL：逐行扫描原始文本，只对抽中的行做 JSON 解码，内存占用与 n 成正比
+// 返回的 Index 与 ParseJSONLWindow 一致；分层抽样需要先扫描一遍统计各层行数
+func SampleJSONL(data []byte, opts SampleOptions) (*SampleResult, error) {
+	if opts.N <= 0 {
+		return nil, fmt.Errorf("抽样条数必须大于 0")
+	}
+
+	var lines []sampledLine
+	var result *SampleResult
+	var err error
+	switch opts.Strategy {
+	case SampleStrategyHead:
+		lines, result, err = sampleHead(data, opts.N)
+	case SampleStrategyRandom:
+		lines, result, err = sampleRandom(data, opts.N, rand.New(rand.NewSource(opts.Seed)))
+	case SampleStrategyStratified:
+		lines, result, err = sampleStratified(data, opts)
+	default:
+		return nil, fmt.Errorf("不支持的抽样策略: %s", opts.Strategy)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(lines, func(i, j int) bool { return lines[i].index < lines[j].index })
+	result.Items = make([]IndexedItem, len(lines))
+	for i, l := range lines {
+		var item map[string]interface{}
+		if err := json.Unmarshal(l.line, &item); err != nil {
+			return nil, fmt.Errorf("解析第 %d 行失败: %w", l.index+1, err)
+		}
+		result.Items[i] = IndexedItem{Index: l.index, Data: item}
+	}
+	return result, nil
+}
+
+// sampleHead 取前 n 行（仍扫描全文以统计总行数）
+func sampleHead(data []byte, n int) ([]sampledLine, *SampleResult, error) {
+	var lines []sampledLine
+	total := 0
+	err := ScanJSONLLines(bytes.NewReader(data), func(index int, line []byte) error {
+		if index < n {
+			lines = append(lines, sampledLine{index: index, line: append([]byte(nil), line...)})
+		}
+		total++
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return lines, &SampleResult{Total: total}, nil
+}
+
+// sampleRandom 蓄水池抽样，每行被抽中的概率相同
+func sampleRandom(data []byte, n int, rng *rand.Rand) ([]sampledLine, *SampleResult, error) {
+	reservoir := &lineReservoir{size: n, rng: rng}
+	total := 0
+	err := ScanJSONLLines(bytes.NewReader(data), func(index int, line []byte) error {
+		reservoir.offer(index, line)
+		total++
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return reservoir.lines, &SampleResult{Total: total}, nil
+}
+
+// sampleStratified 分层抽样：第一遍统计各层行数并分配名额，第二遍在每层内做蓄水池抽样
+func sampleStratified(data []byte, opts SampleOptions) ([]sampledLine, *SampleResult, error) {
+	var strata []SampleStratum
+	positions := make(map[string]int)
+	err := ScanJSONLLines(bytes.NewReader(data), func(index int, line []byte) error {
+		key, err := stratumKey(line, opts.Field)
+		if err != nil {
+			return fmt.Errorf("解析第 %d 行失败: %w", index+1, err)
+		}
+		pos, ok := positions[key]
+		if !ok {
+			pos = len(strata)
+			positions[key] = pos
+			strata = append(strata, SampleStratum{Key: key})
+		}
+		strata[pos].Total++
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	counts := make([]int, len(strata))
+	total := 0
+	for i, stratum := range strata {
+		counts[i] = stratum.Total
+		total += stratum.Total
+	}
+	quotas := allocateStrata(counts, opts.N)
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	reservoirs := make([]*lineReservoir, len(strata))
+	for i, quota := range quotas {
+		reservoirs[i] = &lineReservoir{size: quota, rng: rng}
+	}
+	err = ScanJSONLLines(bytes.NewReader(data), func(index int, line []byte) error {
+		key, _ := stratumKey(line, opts.Field)
+		reservoirs[positions[key]].offer(index, line)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var lines []sampledLine
+	for i, reservoir := range reservoirs {
+		strata[i].Sampled = len(reservoir.lines)
+		lines = append(lines, reservoir.lines...)
+	}
+	return lines, &SampleResult{Total: total, Strata: strata}, nil
+}
+
+// stratumKey 计算一行所属的层：取 meta（或 meta 中的 field 字段）的值
+func stratumKey(line []byte, field string) (string, error) {
+	var record struct {
+		Meta interface{} `json:"meta"`
+	}
+	if err := json.Unmarshal(line, &record); err != nil {
+		return "", err
+	}
+
+	value := record.Meta
+	if field != "" {
+		meta, _ := value.(map[string]interface{})
+		value = meta[field]
+	}
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	default:
+		encoded, _ := json.Marshal(v) // map 按键排序编码，相同内容得到相同的层
+		return string(encoded), nil
+	}
+}
+
+// allocateStrata 把 n 个名额分配到各层（counts 为各层行数）
+// 总行数不超过 n 时全部抽取；层数不少于 n 时行数最多的 n 层各取 1 行；
+// 否则每层先取 1 行，剩余名额按各层剩余行数比例分配（最大余数法），保证每层的名额不超过其行数
+func allocateStrata(counts []int, n int) []int {
+	quotas := make([]int, len(counts))
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	if total <= n {
+		copy(quotas, counts)
+		return quotas
+	}
+
+	order := make([]int, len(counts))
+	for i := range order {
+		order[i] = i
+	}
+	if len(counts) >= n {
+		sort.SliceStable(order, func(a, b int) bool { return counts[order[a]] > counts[order[b]] })
+		for _, i := range order[:n] {
+			quotas[i] = 1
+		}
+		return quotas
+	}
+
+	remaining := n - len(counts)
+	rest := total - len(counts)
+	remainders := make([]int, len(counts))
+	given := 0
+	for i, count := range counts {
+		share := remaining * (count - 1)
+		quotas[i] = 1 + share/rest
+		remainders[i] = share % rest
+		given += share / rest
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	for _, i := range order[:remaining-given] {
+		quotas[i]++
+	}
+	return quotas
+}
+
+// lineReservoir 固定容量的蓄水池（Algorithm R）
+type lineReservoir struct {
+	size  int
+	rng   *rand.Rand
+	seen  int
+	lines []sampledLine
+}
+
+// offer 处理一行，按概率 size/seen 放入蓄水池
+func (r *lineReservoir) offer(index int, line []byte) {
+	r.seen++
+	if len(r.lines) < r.size {
+		r.lines = append(r.lines, sampledLine{index: index, line: append([]byte(nil), line...)})
+		return
+	}
+	if j := r.rng.Intn(r.seen); j < r.size {
+		r.lines[j] = sampledLine{index: index, line: append([]byte(nil), line...)}
+	}
+}
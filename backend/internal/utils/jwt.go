@@ -1,7 +1,10 @@
 package utils
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -12,14 +15,28 @@ type JWTClaims struct {
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
 	IsAdmin  bool   `json:"is_admin"`
+	// Scope 非空表示受限Token（如审阅链接），不能用于常规接口
+	Scope string `json:"scope,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// ReviewScope 审阅链接Token的作用域
+const ReviewScope = "review"
+
+// ReviewClaims 审阅链接Token声明（仅能读取并确认单个任务的生成数据）
+type ReviewClaims struct {
+	TaskID string `json:"task_id"`
+	Scope  string `json:"scope"`
 	jwt.RegisteredClaims
 }
 
 // JWTManager JWT管理器
 type JWTManager struct {
-	secretKey  []byte
-	algorithm  jwt.SigningMethod
-	expireTime time.Duration
+	mu          sync.RWMutex
+	secretKey   []byte
+	previousKey []byte // 轮换前的密钥，轮换前签发的Token在过期前仍可验证
+	algorithm   jwt.SigningMethod
+	expireTime  time.Duration
 }
 
 // NewJWTManager 创建JWT管理器
@@ -31,40 +48,131 @@ func NewJWTManager(secretKey string, algorithm string, expireTime time.Duration)
 	}
 }
 
-// GenerateToken 生成Token
+// SetSecretKey 轮换签名密钥：新Token使用新密钥签发，旧密钥保留用于验证已签发的Token
+func (j *JWTManager) SetSecretKey(secretKey string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if secretKey == "" || secretKey == string(j.secretKey) {
+		return
+	}
+	j.previousKey = j.secretKey
+	j.secretKey = []byte(secretKey)
+}
+
+// keys 返回当前密钥和轮换前的密钥（未轮换过时为 nil）
+func (j *JWTManager) keys() ([]byte, []byte) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.secretKey, j.previousKey
+}
+
+// parse 用当前密钥验证Token，签名不匹配时再用轮换前的密钥验证
+func (j *JWTManager) parse(tokenString string, newClaims func() jwt.Claims) (*jwt.Token, error) {
+	current, previous := j.keys()
+	token, err := jwt.ParseWithClaims(tokenString, newClaims(), j.keyFunc(current))
+	if err != nil && previous != nil && errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+		token, err = jwt.ParseWithClaims(tokenString, newClaims(), j.keyFunc(previous))
+	}
+	return token, err
+}
+
+// keyFunc 校验签名算法并返回验证密钥
+func (j *JWTManager) keyFunc(key []byte) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		if token.Method != j.algorithm {
+			return nil, errors.New("无效的签名算法")
+		}
+		return key, nil
+	}
+}
+
+// ExpireDuration 访问Token有效期
+func (j *JWTManager) ExpireDuration() time.Duration {
+	return j.expireTime
+}
+
+// GenerateToken 生成访问Token，每个Token带有唯一的 jti 用于登出吊销
 func (j *JWTManager) GenerateToken(userID uint, username string, isAdmin bool) (string, error) {
+	tokenID, err := GenerateTokenID()
+	if err != nil {
+		return "", err
+	}
+
 	now := time.Now()
 	claims := JWTClaims{
 		UserID:   userID,
 		Username: username,
 		IsAdmin:  isAdmin,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
 			ExpiresAt: jwt.NewNumericDate(now.Add(j.expireTime)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
+	current, _ := j.keys()
 	token := jwt.NewWithClaims(j.algorithm, claims)
-	return token.SignedString(j.secretKey)
+	return token.SignedString(current)
 }
 
 // ValidateToken 验证Token
 func (j *JWTManager) ValidateToken(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if token.Method != j.algorithm {
-			return nil, errors.New("无效的签名算法")
-		}
-		return j.secretKey, nil
-	})
+	token, err := j.parse(tokenString, func() jwt.Claims { return &JWTClaims{} })
 
 	if err != nil {
 		return nil, err
 	}
 
 	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
+		if claims.Scope != "" {
+			return nil, errors.New("受限Token不能用于此接口")
+		}
 		return claims, nil
 	}
 
 	return nil, errors.New("无效的Token")
 }
+
+// GenerateReviewToken 生成审阅链接Token，tokenID 作为 jti 用于撤销与访问记录
+func (j *JWTManager) GenerateReviewToken(tokenID string, taskID string, expiresAt time.Time) (string, error) {
+	now := time.Now()
+	claims := ReviewClaims{
+		TaskID: taskID,
+		Scope:  ReviewScope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+
+	current, _ := j.keys()
+	token := jwt.NewWithClaims(j.algorithm, claims)
+	return token.SignedString(current)
+}
+
+// ValidateReviewToken 验证审阅链接Token
+func (j *JWTManager) ValidateReviewToken(tokenString string) (*ReviewClaims, error) {
+	token, err := j.parse(tokenString, func() jwt.Claims { return &ReviewClaims{} })
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*ReviewClaims)
+	if !ok || !token.Valid || claims.Scope != ReviewScope || claims.TaskID == "" || claims.ID == "" {
+		return nil, errors.New("无效的审阅Token")
+	}
+	return claims, nil
+}
+
+// GenerateTokenID 生成随机Token标识（16字节十六进制）
+func GenerateTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,47 @@
+package utils
+
+import "unicode"
+
+// 语言检测结果（按文字系统判断，无法判断时为空）
+const (
+	LanguageChinese  = "zh"
+	LanguageJapanese = "ja"
+	LanguageKorean   = "ko"
+	LanguageRussian  = "ru"
+	LanguageArabic   = "ar"
+	LanguageEnglish  = "en" // 拉丁字母文本统一视为英文
+)
+
+// DetectLanguage 按文字系统粗略判断文本的主要语言
+// 取字符数最多的文字系统；假名占比较高时汉字计入日文；没有字母类字符时返回空字符串
+func DetectLanguage(text string) string {
+	counts := make(map[string]int)
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			counts[LanguageJapanese]++
+		case unicode.Is(unicode.Han, r):
+			counts[LanguageChinese]++
+		case unicode.Is(unicode.Hangul, r):
+			counts[LanguageKorean]++
+		case unicode.Is(unicode.Cyrillic, r):
+			counts[LanguageRussian]++
+		case unicode.Is(unicode.Arabic, r):
+			counts[LanguageArabic]++
+		case unicode.Is(unicode.Latin, r):
+			counts[LanguageEnglish]++
+		}
+	}
+
+	if kana := counts[LanguageJapanese]; kana > 0 && kana*5 >= counts[LanguageChinese] {
+		counts[LanguageJapanese] += counts[LanguageChinese]
+		counts[LanguageChinese] = 0
+	}
+	best, bestCount := "", 0
+	for _, lang := range []string{LanguageChinese, LanguageJapanese, LanguageKorean, LanguageRussian, LanguageArabic, LanguageEnglish} {
+		if counts[lang] > bestCount {
+			best, bestCount = lang, counts[lang]
+		}
+	}
+	return best
+}
@@ -4,9 +4,14 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-// HashPassword 哈希密码
+// HashPassword 哈希密码（使用默认计算强度）
 func HashPassword(password string) (string, error) {
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return HashPasswordWithCost(password, bcrypt.DefaultCost)
+}
+
+// HashPasswordWithCost 使用指定的 bcrypt 计算强度哈希密码
+func HashPasswordWithCost(password string, cost int) (string, error) {
+	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), cost)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ColumnMapping 外部数据集的字段到 meta/turns 结构的映射
+// 设置 Messages 时按对话数组转换；否则设置 Turns 时按列顺序交替作为 Human/Assistant 轮次；都未设置时每条记录转换为一问一答
+type ColumnMapping struct {
+	Meta       string   `json:"meta"`        // 作为 meta_description 的字段
+	MetaFields []string `json:"meta_fields"` // 按原字段名复制到 meta 中的其他字段
+	Human      []string `json:"human"`       // 拼接为 Human 内容的字段（多个字段以空行连接，如 instruction + input）
+	Assistant  string   `json:"assistant"`   // 作为 Assistant 内容的字段
+	Turns      []string `json:"turns"`       // 多轮对话的列，按顺序交替作为 Human、Assistant（如 q1, a1, q2, a2），空值的列跳过
+	Messages   string   `json:"messages"`    // 对话数组字段，元素含 role/content（或 from/value）
+}
+
+// 未指定映射时按顺序尝试的常见字段名
+var (
+	defaultMetaFields      = []string{"system", "system_prompt", "meta_description", "meta"}
+	defaultHumanFields     = []string{"instruction", "question", "prompt", "query", "human", "user", "input"}
+	defaultAssistantFields = []string{"output", "response", "answer", "completion", "assistant", "gpt", "chosen"}
+	defaultMessagesFields  = []string{"turns", "messages", "conversations", "conversation"}
+)
+
+// IsEmpty 是否未指定任何映射字段
+func (m *ColumnMapping) IsEmpty() bool {
+	return m == nil || (m.Meta == "" && len(m.MetaFields) == 0 && len(m.Human) == 0 && m.Assistant == "" && len(m.Turns) == 0 && m.Messages == "")
+}
+
+// SuggestColumnMapping 按记录中存在的常见字段名推荐映射（如 Alpaca 的 instruction/input/output、ShareGPT 的 conversations）
+func SuggestColumnMapping(record map[string]interface{}) ColumnMapping {
+	return ColumnMapping{}.resolve(record)
+}
+
+// resolve 按记录中实际存在的字段补全未指定的映射
+func (m ColumnMapping) resolve(record map[string]interface{}) ColumnMapping {
+	if m.Meta == "" {
+		m.Meta = firstPresentField(record, defaultMetaFields)
+	}
+	if m.Messages != "" || len(m.Human) > 0 || m.Assistant != "" || len(m.Turns) > 0 {
+		return m
+	}
+
+	for _, field := range defaultMessagesFields {
+		if _, ok := record[field].([]interface{}); ok {
+			m.Messages = field
+			return m
+		}
+	}
+
+	if human := firstPresentField(record, defaultHumanFields); human != "" {
+		m.Human = []string{human}
+		// Alpaca 格式：instruction 之外的 input 是补充输入
+		if human == "instruction" {
+			if _, ok := record["input"]; ok {
+				m.Human = append(m.Human, "input")
+			}
+		}
+	}
+	m.Assistant = firstPresentField(record, defaultAssistantFields)
+	return m
+}
+
+// ConvertRecordToJSONL 将一条外部记录按字段映射转换为 meta/turns 结构，记录没有任何对话内容时返回 false
+func ConvertRecordToJSONL(record map[string]interface{}, mapping ColumnMapping) (JSONLData, bool) {
+	mapping = mapping.resolve(record)
+
+	data := JSONLData{
+		Meta:  map[string]interface{}{"meta_description": recordMetaDescription(record[mapping.Meta])},
+		Turns: []Turn{},
+	}
+	for _, field := range mapping.MetaFields {
+		if value, ok := record[field]; ok && field != "meta_description" {
+			data.Meta[field] = value
+		}
+	}
+
+	if mapping.Messages != "" {
+		messages, _ := record[mapping.Messages].([]interface{})
+		for _, item := range messages {
+			message, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			role := strings.ToLower(recordText(firstPresentValue(message, "role", "from")))
+			text := recordText(firstPresentValue(message, "content", "value", "text"))
+			switch role {
+			case "system":
+				if data.Meta["meta_description"] == "" {
+					data.Meta["meta_description"] = text
+				}
+			case "assistant", "gpt", "model", "bot":
+				data.Turns = append(data.Turns, Turn{Role: "Assistant", Text: text})
+			default:
+				data.Turns = append(data.Turns, Turn{Role: "Human", Text: text})
+			}
+		}
+		return data, len(data.Turns) > 0
+	}
+
+	if len(mapping.Turns) > 0 {
+		for i, field := range mapping.Turns {
+			text := strings.TrimSpace(recordText(record[field]))
+			if text == "" {
+				continue
+			}
+			role := "Human"
+			if i%2 == 1 {
+				role = "Assistant"
+			}
+			data.Turns = append(data.Turns, Turn{Role: role, Text: text})
+		}
+		return data, len(data.Turns) > 0
+	}
+
+	var parts []string
+	for _, field := range mapping.Human {
+		if text := strings.TrimSpace(recordText(record[field])); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	if len(parts) > 0 {
+		data.Turns = append(data.Turns, Turn{Role: "Human", Text: strings.Join(parts, "\n\n")})
+	}
+	if mapping.Assistant != "" {
+		if text := strings.TrimSpace(recordText(record[mapping.Assistant])); text != "" {
+			data.Turns = append(data.Turns, Turn{Role: "Assistant", Text: text})
+		}
+	}
+	return data, len(data.Turns) > 0
+}
+
+// recordMetaDescription 取 meta 字段的描述文本（meta 为对象时取其中的 meta_description）
+func recordMetaDescription(value interface{}) string {
+	if meta, ok := value.(map[string]interface{}); ok {
+		return recordText(meta["meta_description"])
+	}
+	return recordText(value)
+}
+
+// recordText 将字段值转换为文本，非字符串的值序列化为 JSON
+func recordText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	}
+}
+
+// firstPresentField 返回候选字段中第一个存在于记录中的字段名
+func firstPresentField(record map[string]interface{}, fields []string) string {
+	for _, field := range fields {
+		if _, ok := record[field]; ok {
+			return field
+		}
+	}
+	return ""
+}
+
+// firstPresentValue 返回候选字段中第一个存在的字段值
+func firstPresentValue(record map[string]interface{}, fields ...string) interface{} {
+	if field := firstPresentField(record, fields); field != "" {
+		return record[field]
+	}
+	return nil
+}
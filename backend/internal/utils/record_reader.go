@@ -0,0 +1,333 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/xuri/excelize/v2"
+)
+
+// 外部数据集的记录格式
+const (
+	RecordFormatJSONL   = "jsonl" // JSON Lines，或 JSON 数组
+	RecordFormatCSV     = "csv"
+	RecordFormatParquet = "parquet"
+)
+
+// ErrStopRecords 由回调返回以提前结束读取（如达到行数上限），读取函数返回 nil
+var ErrStopRecords = errors.New("stop reading records")
+
+// DetectRecordFormat 按文件名后缀判断记录格式，无法判断时返回空
+func DetectRecordFormat(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".jsonl", ".json", ".ndjson":
+		return RecordFormatJSONL
+	case ".csv":
+		return RecordFormatCSV
+	case ".parquet":
+		return RecordFormatParquet
+	}
+	return ""
+}
+
+// ReadJSONRecords 逐条读取 JSON Lines 或 JSON 数组中的对象
+func ReadJSONRecords(r io.Reader, fn func(record map[string]interface{}) error) error {
+	reader := bufio.NewReader(r)
+	if bom, err := reader.Peek(3); err == nil && bytes.Equal(bom, []byte("\xEF\xBB\xBF")) {
+		reader.Discard(3)
+	}
+
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+
+	first, err := firstNonSpaceByte(reader)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	isArray := first == '['
+	if isArray {
+		if _, err := decoder.Token(); err != nil {
+			return fmt.Errorf("读取JSON数组失败: %w", err)
+		}
+	}
+
+	for index := 1; ; index++ {
+		if isArray && !decoder.More() {
+			return nil
+		}
+		var record map[string]interface{}
+		if err := decoder.Decode(&record); err != nil {
+			if err == io.EOF && !isArray {
+				return nil
+			}
+			return fmt.Errorf("第 %d 条记录不是有效的JSON对象: %w", index, err)
+		}
+		if err := fn(record); err != nil {
+			if errors.Is(err, ErrStopRecords) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// firstNonSpaceByte 跳过空白后查看下一个字节（不消耗）
+func firstNonSpaceByte(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			reader.Discard(1)
+		default:
+			return b[0], nil
+		}
+	}
+}
+
+// ReadCSVRecords 逐行读取CSV，第一行为列名，每行转换为 列名 -> 值 的记录
+func ReadCSVRecords(r io.Reader, fn func(record map[string]interface{}) error) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	headers, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取CSV表头失败: %w", err)
+	}
+	if len(headers) > 0 {
+		headers[0] = strings.TrimPrefix(headers[0], "\xEF\xBB\xBF")
+	}
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取CSV行失败: %w", err)
+		}
+
+		record := make(map[string]interface{}, len(headers))
+		for i, header := range headers {
+			if i < len(row) {
+				record[strings.TrimSpace(header)] = row[i]
+			}
+		}
+		if err := fn(record); err != nil {
+			if errors.Is(err, ErrStopRecords) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// ReadRecords 按文件名后缀读取上传文件中的记录（JSON Lines/JSON 数组、CSV、Excel 第一个工作表、Parquet），无法判断时按 JSON 读取
+func ReadRecords(filename string, content []byte, fn func(record map[string]interface{}) error) error {
+	if strings.EqualFold(path.Ext(filename), ".xlsx") {
+		return ReadXLSXRecords(content, fn)
+	}
+	switch DetectRecordFormat(filename) {
+	case RecordFormatCSV:
+		return ReadCSVRecords(bytes.NewReader(content), fn)
+	case RecordFormatParquet:
+		return ReadParquetRecords(bytes.NewReader(content), int64(len(content)), fn)
+	default:
+		return ReadJSONRecords(bytes.NewReader(content), fn)
+	}
+}
+
+// ReadXLSXRecords 逐行读取Excel第一个工作表，第一行为列名，每行转换为 列名 -> 值 的记录
+func ReadXLSXRecords(content []byte, fn func(record map[string]interface{}) error) error {
+	f, err := excelize.OpenReader(bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("读取Excel文件失败: %w", err)
+	}
+	defer f.Close()
+
+	rows, err := f.Rows(f.GetSheetName(0))
+	if err != nil {
+		return fmt.Errorf("读取Excel工作表失败: %w", err)
+	}
+	defer rows.Close()
+
+	var headers []string
+	for rows.Next() {
+		row, err := rows.Columns()
+		if err != nil {
+			return fmt.Errorf("读取Excel行失败: %w", err)
+		}
+		if headers == nil {
+			headers = row
+			continue
+		}
+
+		record := make(map[string]interface{}, len(headers))
+		for i, header := range headers {
+			if i < len(row) {
+				record[strings.TrimSpace(header)] = row[i]
+			}
+		}
+		if err := fn(record); err != nil {
+			if errors.Is(err, ErrStopRecords) {
+				return nil
+			}
+			return err
+		}
+	}
+	return rows.Error()
+}
+
+// ReadParquetRecords 逐行读取Parquet文件，每行转换为 顶层列名 -> 值 的记录
+// 列表列转换为数组；列表中的结构体（如 messages 的 role/content）按位置组装为对象
+func ReadParquetRecords(r io.ReaderAt, size int64, fn func(record map[string]interface{}) error) error {
+	file, err := parquet.OpenFile(r, size)
+	if err != nil {
+		return fmt.Errorf("读取Parquet文件失败: %w", err)
+	}
+
+	columns := file.Schema().Columns()
+	reader := parquet.NewReader(file)
+	defer reader.Close()
+
+	rows := make([]parquet.Row, 64)
+	for {
+		n, err := reader.ReadRows(rows)
+		for _, row := range rows[:n] {
+			if err := fn(parquetRowRecord(columns, row)); err != nil {
+				if errors.Is(err, ErrStopRecords) {
+					return nil
+				}
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取Parquet行失败: %w", err)
+		}
+	}
+}
+
+// parquetListPathSegments Parquet 列表类型的中间层级名称（组装记录时跳过）
+var parquetListPathSegments = map[string]bool{"list": true, "element": true, "item": true, "array": true, "bag": true}
+
+// parquetRowRecord 将一行叶子列的值组装为记录
+func parquetRowRecord(columns [][]string, row parquet.Row) map[string]interface{} {
+	// 按叶子列收集值（重复列有多个值）
+	values := make([][]parquet.Value, len(columns))
+	for _, value := range row {
+		if column := value.Column(); column >= 0 && column < len(values) {
+			values[column] = append(values[column], value)
+		}
+	}
+
+	record := make(map[string]interface{})
+	// 嵌套字段按顶层列分组：顶层列 -> 叶子字段名 -> 值列表
+	nested := make(map[string]map[string][]interface{})
+	repeated := make(map[string]bool)
+
+	for i, columnPath := range columns {
+		if len(columnPath) == 0 {
+			continue
+		}
+		top := columnPath[0]
+		var leaf []string
+		for _, segment := range columnPath[1:] {
+			if !parquetListPathSegments[segment] {
+				leaf = append(leaf, segment)
+			}
+		}
+
+		var items []interface{}
+		for _, value := range values[i] {
+			if value.IsNull() {
+				continue
+			}
+			items = append(items, parquetValue(value))
+		}
+
+		if len(columnPath) == 1 {
+			if len(items) > 0 {
+				record[top] = items[0]
+			} else {
+				record[top] = nil
+			}
+			continue
+		}
+		if len(columnPath) > len(leaf)+1 {
+			repeated[top] = true
+		}
+		if nested[top] == nil {
+			nested[top] = make(map[string][]interface{})
+		}
+		nested[top][strings.Join(leaf, ".")] = items
+	}
+
+	for top, fields := range nested {
+		// 列表中的标量（如 list<string>）
+		if scalars, ok := fields[""]; ok && len(fields) == 1 {
+			if repeated[top] {
+				record[top] = scalars
+			} else if len(scalars) > 0 {
+				record[top] = scalars[0]
+			}
+			continue
+		}
+
+		count := 0
+		for _, items := range fields {
+			if len(items) > count {
+				count = len(items)
+			}
+		}
+		objects := make([]interface{}, count)
+		for i := range objects {
+			object := make(map[string]interface{}, len(fields))
+			for name, items := range fields {
+				if i < len(items) {
+					object[name] = items[i]
+				}
+			}
+			objects[i] = object
+		}
+		if repeated[top] {
+			record[top] = objects
+		} else if count > 0 {
+			record[top] = objects[0]
+		}
+	}
+	return record
+}
+
+// parquetValue 将 Parquet 值转换为 Go 值
+func parquetValue(value parquet.Value) interface{} {
+	switch value.Kind() {
+	case parquet.Boolean:
+		return value.Boolean()
+	case parquet.Int32, parquet.Int64:
+		return value.Int64()
+	case parquet.Float, parquet.Double:
+		return value.Double()
+	case parquet.ByteArray, parquet.FixedLenByteArray:
+		return string(value.ByteArray())
+	}
+	return value.String()
+}
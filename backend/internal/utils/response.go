@@ -41,8 +41,14 @@ func SuccessWithMessage(c *gin.Context, message string, data interface{}) {
 	})
 }
 
+// ErrorMessageKey 5xx 响应的错误信息在请求上下文中的键（供错误统计使用）
+const ErrorMessageKey = "error_message"
+
 // ErrorResponse 错误响应
 func ErrorResponse(c *gin.Context, code int, message string) {
+	if code >= http.StatusInternalServerError {
+		c.Set(ErrorMessageKey, message)
+	}
 	c.JSON(code, Response{
 		Code:    code,
 		Message: message,
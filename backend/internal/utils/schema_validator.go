@@ -0,0 +1,225 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// 校验错误类型
+const (
+	SchemaErrInvalidJSON   = "invalid_json"
+	SchemaErrNotObject     = "not_object"
+	SchemaErrMissingMeta   = "missing_meta"
+	SchemaErrInvalidMeta   = "invalid_meta"
+	SchemaErrMissingTurns  = "missing_turns"
+	SchemaErrInvalidTurns  = "invalid_turns"
+	SchemaErrEmptyTurns    = "empty_turns"
+	SchemaErrInvalidTurn   = "invalid_turn"
+	SchemaErrInvalidRole   = "invalid_role"
+	SchemaErrEmptyText     = "empty_text"
+	SchemaErrTurnOrder     = "invalid_turn_order"
+	SchemaErrLineTooLong   = "line_too_long"
+	defaultMaxSchemaErrors = 1000
+)
+
+// SchemaIssue 单行校验问题
+type SchemaIssue struct {
+	Line    int    `json:"line"` // 行号（从1开始，包含空行）
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// SchemaReport JSONL结构校验报告
+type SchemaReport struct {
+	TotalLines   int            `json:"total_lines"`
+	ValidLines   int            `json:"valid_lines"`
+	InvalidLines int            `json:"invalid_lines"`
+	ErrorCounts  map[string]int `json:"error_counts"`
+	Errors       []SchemaIssue  `json:"errors"`
+	Truncated    bool           `json:"truncated"` // 错误明细是否因数量过多被截断
+}
+
+// SchemaValidationResult 校验结果（含按有效/无效拆分后的原始行，用于隔离无效行）
+type SchemaValidationResult struct {
+	Report       *SchemaReport
+	ValidLines   [][]byte
+	InvalidLines [][]byte
+}
+
+// ValidateJSONLSchema 按 meta/turns 结构逐行校验JSONL内容
+// maxErrors 为返回的错误明细上限（<=0 使用默认值），统计数据不受影响
+func ValidateJSONLSchema(data []byte, maxErrors int) (*SchemaValidationResult, error) {
+	return ValidateJSONLSchemaReader(bytes.NewReader(data), maxErrors, true)
+}
+
+// ValidateJSONLSchemaReader 以流式方式逐行校验
+// keepLines 为 false 时只统计不保留原始行，内存占用与文件大小无关（仅生成报告时使用）
+func ValidateJSONLSchemaReader(r io.Reader, maxErrors int, keepLines bool) (*SchemaValidationResult, error) {
+	result := &SchemaValidationResult{}
+	var onLine func(valid bool, line []byte) error
+	if keepLines {
+		onLine = func(valid bool, line []byte) error {
+			if valid {
+				result.ValidLines = append(result.ValidLines, append([]byte(nil), line...))
+			} else {
+				result.InvalidLines = append(result.InvalidLines, append([]byte(nil), line...))
+			}
+			return nil
+		}
+	}
+
+	report, err := validateJSONLSchema(r, maxErrors, onLine)
+	if err != nil {
+		return nil, err
+	}
+	result.Report = report
+	return result, nil
+}
+
+// SplitJSONLSchemaReader 流式校验并把有效行、无效行分别写入 valid 和 invalid（每行以换行结尾）
+// 用于隔离大文件中的无效行，内存占用与文件大小无关
+func SplitJSONLSchemaReader(r io.Reader, maxErrors int, valid, invalid io.Writer) (*SchemaReport, error) {
+	return validateJSONLSchema(r, maxErrors, func(ok bool, line []byte) error {
+		w := invalid
+		if ok {
+			w = valid
+		}
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+		_, err := w.Write([]byte{'\n'})
+		return err
+	})
+}
+
+// validateJSONLSchema 逐行校验并生成报告，onLine 不为 nil 时按顺序收到每个非空行及其是否有效
+func validateJSONLSchema(r io.Reader, maxErrors int, onLine func(valid bool, line []byte) error) (*SchemaReport, error) {
+	if maxErrors <= 0 {
+		maxErrors = defaultMaxSchemaErrors
+	}
+
+	report := &SchemaReport{
+		ErrorCounts: make(map[string]int),
+		Errors:      []SchemaIssue{},
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLLineSize)
+
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		report.TotalLines++
+
+		issueType, message := validateSchemaLine(line)
+		if onLine != nil {
+			if err := onLine(issueType == "", line); err != nil {
+				return nil, fmt.Errorf("写入校验结果失败: %w", err)
+			}
+		}
+		if issueType == "" {
+			report.ValidLines++
+			continue
+		}
+
+		report.InvalidLines++
+		report.ErrorCounts[issueType]++
+		if len(report.Errors) < maxErrors {
+			report.Errors = append(report.Errors, SchemaIssue{
+				Line:    lineNo,
+				Type:    issueType,
+				Message: message,
+			})
+		} else {
+			report.Truncated = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if err == bufio.ErrTooLong {
+			report.InvalidLines++
+			report.ErrorCounts[SchemaErrLineTooLong]++
+			report.Errors = append(report.Errors, SchemaIssue{
+				Line:    lineNo + 1,
+				Type:    SchemaErrLineTooLong,
+				Message: "行长度超过上限，后续内容未校验",
+			})
+			return report, nil
+		}
+		return nil, fmt.Errorf("读取文件内容失败: %w", err)
+	}
+
+	return report, nil
+}
+
+// validateSchemaLine 校验单行，返回错误类型和描述（合法时错误类型为空）
+func validateSchemaLine(line []byte) (string, string) {
+	var raw interface{}
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return SchemaErrInvalidJSON, fmt.Sprintf("JSON解析失败: %v", err)
+	}
+
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return SchemaErrNotObject, "每行必须是JSON对象"
+	}
+
+	meta, exists := obj["meta"]
+	if !exists {
+		return SchemaErrMissingMeta, "缺少 meta 字段"
+	}
+	if _, ok := meta.(map[string]interface{}); !ok {
+		return SchemaErrInvalidMeta, "meta 必须是对象"
+	}
+
+	turnsRaw, exists := obj["turns"]
+	if !exists {
+		return SchemaErrMissingTurns, "缺少 turns 字段"
+	}
+	turns, ok := turnsRaw.([]interface{})
+	if !ok {
+		return SchemaErrInvalidTurns, "turns 必须是数组"
+	}
+	if len(turns) == 0 {
+		return SchemaErrEmptyTurns, "turns 不能为空"
+	}
+
+	expectedRole := "Human"
+	for i, turnRaw := range turns {
+		turn, ok := turnRaw.(map[string]interface{})
+		if !ok {
+			return SchemaErrInvalidTurn, fmt.Sprintf("turns[%d] 必须是对象", i)
+		}
+
+		role, _ := turn["role"].(string)
+		if role != "Human" && role != "Assistant" {
+			return SchemaErrInvalidRole, fmt.Sprintf("turns[%d].role 必须是 Human 或 Assistant", i)
+		}
+
+		text, ok := turn["text"].(string)
+		if !ok {
+			return SchemaErrInvalidTurn, fmt.Sprintf("turns[%d].text 必须是字符串", i)
+		}
+		if len(bytes.TrimSpace([]byte(text))) == 0 {
+			return SchemaErrEmptyText, fmt.Sprintf("turns[%d].text 不能为空", i)
+		}
+
+		if role != expectedRole {
+			return SchemaErrTurnOrder, fmt.Sprintf("turns[%d] 应为 %s（Human 与 Assistant 需交替出现）", i, expectedRole)
+		}
+		if expectedRole == "Human" {
+			expectedRole = "Assistant"
+		} else {
+			expectedRole = "Human"
+		}
+	}
+
+	return "", ""
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// 检索结果摘要中关键字的高亮标记（摘要其余部分已做 HTML 转义）
+const (
+	SearchHighlightStart = "<mark>"
+	SearchHighlightEnd   = "</mark>"
+)
+
+// searchSnippetRadius 摘要中关键字前后保留的字符数
+const searchSnippetRadius = 40
+
+// SearchMatch 单条 JSONL 记录中的一处匹配
+type SearchMatch struct {
+	Field   string // meta、turns[i] 或 raw（meta/turns 之外的字段）
+	Role    string // 匹配到的轮次角色
+	Snippet string
+}
+
+// FindSearchMatch 在 meta/turns 结构的记录中查找关键字（忽略大小写），返回第一处匹配
+// 优先匹配 meta_description 和各轮对话文本；都未命中时在整行原文中查找
+func FindSearchMatch(line []byte, query string) (*SearchMatch, bool) {
+	var data JSONLData
+	if err := json.Unmarshal(line, &data); err == nil {
+		if snippet, ok := HighlightSnippet(metaDescription(data.Meta), query); ok {
+			return &SearchMatch{Field: "meta", Snippet: snippet}, true
+		}
+		for i, turn := range data.Turns {
+			if snippet, ok := HighlightSnippet(turn.Text, query); ok {
+				return &SearchMatch{Field: fmt.Sprintf("turns[%d]", i), Role: turn.Role, Snippet: snippet}, true
+			}
+		}
+	}
+
+	if snippet, ok := HighlightSnippet(string(line), query); ok {
+		return &SearchMatch{Field: "raw", Snippet: snippet}, true
+	}
+	return nil, false
+}
+
+// HighlightSnippet 截取关键字附近的文本并高亮关键字（忽略大小写）
+func HighlightSnippet(text, query string) (string, bool) {
+	runes := []rune(text)
+	queryLen := len([]rune(query))
+	if queryLen == 0 || len(runes) < queryLen {
+		return "", false
+	}
+
+	pos := -1
+	for i := 0; i+queryLen <= len(runes); i++ {
+		if strings.EqualFold(string(runes[i:i+queryLen]), query) {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
+		return "", false
+	}
+
+	start := pos - searchSnippetRadius
+	if start < 0 {
+		start = 0
+	}
+	end := pos + queryLen + searchSnippetRadius
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	b.WriteString(html.EscapeString(string(runes[start:pos])))
+	b.WriteString(SearchHighlightStart)
+	b.WriteString(html.EscapeString(string(runes[pos : pos+queryLen])))
+	b.WriteString(SearchHighlightEnd)
+	b.WriteString(html.EscapeString(string(runes[pos+queryLen : end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
@@ -0,0 +1,249 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/xuri/excelize/v2"
+)
+
+// TableFormatXLSX Excel 表格导出格式
+const TableFormatXLSX = "xlsx"
+
+// 表格列名别名，统一映射为 meta/Human/Assistant 布局（比较时忽略大小写和首尾空格）
+var tableHeaderAliases = map[string]string{
+	"meta":             "meta",
+	"meta_description": "meta",
+	"system":           "meta",
+	"system_prompt":    "meta",
+	"human":            "Human",
+	"user":             "Human",
+	"question":         "Human",
+	"instruction":      "Human",
+	"prompt":           "Human",
+	"assistant":        "Assistant",
+	"gpt":              "Assistant",
+	"answer":           "Assistant",
+	"output":           "Assistant",
+	"response":         "Assistant",
+}
+
+// normalizeTableHeader 将列名映射为 meta/Human/Assistant，无法识别的列原样返回（转换时忽略）
+func normalizeTableHeader(header string) string {
+	key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, "\xEF\xBB\xBF")))
+	if mapped, ok := tableHeaderAliases[key]; ok {
+		return mapped
+	}
+	return strings.TrimSpace(header)
+}
+
+// ConvertTableToJSONL 将 meta/Human/Assistant 布局的表格转换为JSONL格式
+// 第一列为 meta，之后按顺序成对出现 Human/Assistant 列；meta 为空的行沿用上一行的 meta
+func ConvertTableToJSONL(headers []string, rows [][]string) ([]byte, error) {
+	normalized := make([]string, len(headers))
+	for i, header := range headers {
+		normalized[i] = normalizeTableHeader(header)
+	}
+
+	if len(normalized) == 0 || normalized[0] != "meta" {
+		return nil, fmt.Errorf("第一列必须命名为 'meta'")
+	}
+
+	// 提取所有 Human 和 Assistant 列的索引
+	var humanIndices, assistantIndices []int
+	for i, col := range normalized {
+		if col == "Human" {
+			humanIndices = append(humanIndices, i)
+		} else if col == "Assistant" {
+			assistantIndices = append(assistantIndices, i)
+		}
+	}
+
+	if len(humanIndices) != len(assistantIndices) {
+		return nil, fmt.Errorf("Human 和 Assistant 列数量不匹配")
+	}
+
+	// 记录当前活跃的 meta
+	currentActiveMeta := ""
+	var buf bytes.Buffer
+
+	for _, row := range rows {
+		// 跳过空行
+		if len(row) == 0 {
+			continue
+		}
+
+		// 处理当前行的 meta（支持共享逻辑）
+		if rowMeta := strings.TrimSpace(row[0]); rowMeta != "" {
+			currentActiveMeta = rowMeta
+		}
+
+		// 提取多轮对话内容
+		var turns []Turn
+		for i := 0; i < len(humanIndices); i++ {
+			hIdx := humanIndices[i]
+			aIdx := assistantIndices[i]
+
+			// 添加 Human 内容（非空才添加）
+			if hIdx < len(row) && strings.TrimSpace(row[hIdx]) != "" {
+				turns = append(turns, Turn{Role: "Human", Text: strings.TrimSpace(row[hIdx])})
+			}
+
+			// 添加 Assistant 内容（非空才添加）
+			if aIdx < len(row) && strings.TrimSpace(row[aIdx]) != "" {
+				turns = append(turns, Turn{Role: "Assistant", Text: strings.TrimSpace(row[aIdx])})
+			}
+		}
+
+		jsonBytes, err := json.Marshal(JSONLData{
+			Meta: map[string]interface{}{
+				"meta_description": currentActiveMeta,
+			},
+			Turns: turns,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("JSON序列化失败: %w", err)
+		}
+		buf.Write(jsonBytes)
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ConvertXLSXToJSONL 将Excel文件（第一个工作表）转换为JSONL格式
+func ConvertXLSXToJSONL(xlsxContent []byte) ([]byte, error) {
+	f, err := excelize.OpenReader(bytes.NewReader(xlsxContent))
+	if err != nil {
+		return nil, fmt.Errorf("读取Excel文件失败: %w", err)
+	}
+	defer f.Close()
+
+	rows, err := f.GetRows(f.GetSheetName(0))
+	if err != nil {
+		return nil, fmt.Errorf("读取Excel工作表失败: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("Excel工作表为空")
+	}
+
+	return ConvertTableToJSONL(rows[0], rows[1:])
+}
+
+// ConvertJSONLToXLSX 将JSONL内容转换为Excel文件，表格布局与CSV导出一致
+func ConvertJSONLToXLSX(jsonlContent []byte) ([]byte, error) {
+	headers, rows, err := ConvertJSONLToTable(jsonlContent)
+	if err != nil {
+		return nil, err
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sw, err := f.NewStreamWriter(f.GetSheetName(0))
+	if err != nil {
+		return nil, fmt.Errorf("创建Excel工作表失败: %w", err)
+	}
+	for i, row := range append([][]string{headers}, rows...) {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return nil, fmt.Errorf("计算单元格位置失败: %w", err)
+		}
+		values := make([]interface{}, len(row))
+		for j, value := range row {
+			values[j] = value
+		}
+		if err := sw.SetRow(cell, values); err != nil {
+			return nil, fmt.Errorf("写入Excel第 %d 行失败: %w", i+1, err)
+		}
+	}
+	if err := sw.Flush(); err != nil {
+		return nil, fmt.Errorf("写入Excel失败: %w", err)
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("生成Excel文件失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ConvertParquetToJSONL 将Parquet文件转换为JSONL格式
+// 使用与训练格式导出相同的结构（system + messages），system 对应 meta_description
+func ConvertParquetToJSONL(parquetContent []byte) ([]byte, error) {
+	rows, err := parquet.Read[trainerParquetRow](bytes.NewReader(parquetContent), int64(len(parquetContent)))
+	if err != nil {
+		return nil, fmt.Errorf("读取Parquet文件失败: %w", err)
+	}
+
+	var buf bytes.Buffer
+	for i, row := range rows {
+		system := row.System
+		turns := make([]Turn, 0, len(row.Messages))
+		for _, msg := range row.Messages {
+			switch strings.ToLower(msg.Role) {
+			case "system":
+				if system == "" {
+					system = msg.Content
+				}
+			case "assistant", "gpt":
+				turns = append(turns, Turn{Role: "Assistant", Text: msg.Content})
+			default:
+				turns = append(turns, Turn{Role: "Human", Text: msg.Content})
+			}
+		}
+
+		jsonBytes, err := json.Marshal(JSONLData{
+			Meta:  map[string]interface{}{"meta_description": system},
+			Turns: turns,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行JSON序列化失败: %w", i+1, err)
+		}
+		buf.Write(jsonBytes)
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ConvertJSONLToParquet 将JSONL内容转换为Parquet文件
+// 与训练格式导出不同，这里不要求最后一轮为 Assistant，只跳过无法解析的行
+func ConvertJSONLToParquet(jsonlContent []byte) ([]byte, error) {
+	var rows []trainerParquetRow
+
+	scanner := bufio.NewScanner(bytes.NewReader(jsonlContent))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLLineSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var data JSONLData
+		if err := json.Unmarshal(line, &data); err != nil {
+			continue
+		}
+		rows = append(rows, toParquetRow(metaDescription(data.Meta), data.Turns))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取数据失败: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("没有有效的数据")
+	}
+
+	var buf bytes.Buffer
+	writer := parquet.NewGenericWriter[trainerParquetRow](&buf)
+	if _, err := writer.Write(rows); err != nil {
+		return nil, fmt.Errorf("写入Parquet失败: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("写入Parquet失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 纯文本的样本切分方式
+const (
+	TextSplitParagraph = "paragraph" // 按空行切分，每个段落一条样本（默认）
+	TextSplitLine      = "line"      // 每个非空行一条样本
+	TextSplitSeparator = "separator" // 按自定义分隔符切分
+)
+
+// blankLinePattern 段落之间的空行（可包含空白字符）
+var blankLinePattern = regexp.MustCompile(`\n[ \t\f\v]*\n`)
+
+// TextSplitOptions 纯文本切分选项，零值表示按段落切分
+type TextSplitOptions struct {
+	Mode      string // paragraph（默认）、line、separator
+	Separator string // separator 模式下的分隔符，支持 \n 和 \t 转义
+	Meta      string // 每条样本的 meta_description
+}
+
+// ParseTextSplitOptions 解析请求中的纯文本切分选项，空字符串使用默认值
+func ParseTextSplitOptions(mode, separator, meta string) (TextSplitOptions, error) {
+	opts := TextSplitOptions{Meta: strings.TrimSpace(meta)}
+	switch strings.ToLower(mode) {
+	case "", TextSplitParagraph:
+		opts.Mode = TextSplitParagraph
+	case TextSplitLine:
+		opts.Mode = TextSplitLine
+	case TextSplitSeparator:
+		opts.Mode = TextSplitSeparator
+		opts.Separator = strings.NewReplacer(`\n`, "\n", `\t`, "\t").Replace(separator)
+		if opts.Separator == "" {
+			return opts, fmt.Errorf("按分隔符切分时必须指定 text_separator")
+		}
+	default:
+		return opts, fmt.Errorf("不支持的文本切分方式: %s（可选 paragraph、line、separator）", mode)
+	}
+	return opts, nil
+}
+
+// SplitText 按选项把文本切分为样本，去掉每段首尾空白并忽略空段
+func SplitText(text string, opts TextSplitOptions) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var parts []string
+	switch opts.Mode {
+	case TextSplitLine:
+		parts = strings.Split(text, "\n")
+	case TextSplitSeparator:
+		parts = strings.Split(text, opts.Separator)
+	default:
+		parts = blankLinePattern.Split(text, -1)
+	}
+
+	samples := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			samples = append(samples, part)
+		}
+	}
+	return samples
+}
+
+// ConvertTextToJSONL 将纯文本转换为JSONL：每个样本作为一条只有 Human 轮次的对话（编码按 BOM 和内容自动识别），返回转换后的内容和样本数
+func ConvertTextToJSONL(content []byte, opts TextSplitOptions) ([]byte, int, error) {
+	text, _, err := DecodeText(content, CSVEncodingAuto)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	samples := SplitText(string(text), opts)
+	if len(samples) == 0 {
+		return nil, 0, fmt.Errorf("文本文件中没有内容")
+	}
+
+	var buf bytes.Buffer
+	for _, sample := range samples {
+		data := JSONLData{
+			Meta:  map[string]interface{}{"meta_description": opts.Meta},
+			Turns: []Turn{{Role: "Human", Text: sample}},
+		}
+		line, err := json.Marshal(data)
+		if err != nil {
+			return nil, 0, err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), len(samples), nil
+}
+
+// ConvertJSONArrayToJSONL 将 JSON 数组（或 JSON Lines）中的对象逐条转换为 meta/turns 结构的 JSONL
+// 已是 meta 对象 + turns 数组的记录原样保留；其他记录按常见字段名识别（见 SuggestColumnMapping），没有对话内容的记录跳过
+// 返回转换后的内容、转换的记录数和跳过的记录数
+func ConvertJSONArrayToJSONL(content []byte) ([]byte, int, int, error) {
+	var buf bytes.Buffer
+	converted, skipped := 0, 0
+	err := ReadJSONRecords(bytes.NewReader(content), func(record map[string]interface{}) error {
+		var data interface{} = record
+		_, hasMeta := record["meta"].(map[string]interface{})
+		_, hasTurns := record["turns"].([]interface{})
+		if !hasMeta || !hasTurns {
+			mapped, ok := ConvertRecordToJSONL(record, ColumnMapping{})
+			if !ok {
+				skipped++
+				return nil
+			}
+			data = mapped
+		}
+
+		line, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+		converted++
+		return nil
+	})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if converted == 0 {
+		return nil, 0, skipped, fmt.Errorf("JSON 中没有可转换的对话记录")
+	}
+	return buf.Bytes(), converted, skipped, nil
+}
@@ -0,0 +1,202 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// 训练框架导出格式
+const (
+	TrainerFormatShareGPT   = "sharegpt"    // {"conversations": [{"from": "human|gpt|system", "value": ...}]}
+	TrainerFormatOpenAIChat = "openai_chat" // {"messages": [{"role": "system|user|assistant", "content": ...}]}
+	TrainerFormatAlpaca     = "alpaca"      // {"instruction", "input", "output", "system", "history": [[问, 答], ...]}
+	TrainerFormatParquet    = "parquet"     // 每行 meta + messages 的 Parquet 文件
+)
+
+// 额外的校验错误类型（训练格式要求以 Assistant 结尾）
+const SchemaErrNoFinalAnswer = "no_final_answer"
+
+// TrainerConversion 训练格式转换结果
+type TrainerConversion struct {
+	Content   []byte
+	Ext       string
+	Converted int
+	Skipped   int           // 未通过校验而跳过的行数
+	Issues    []SchemaIssue // 跳过原因（最多 defaultMaxSchemaErrors 条）
+}
+
+// IsTrainerFormat 判断是否为训练框架导出格式
+func IsTrainerFormat(format string) bool {
+	switch format {
+	case TrainerFormatShareGPT, TrainerFormatOpenAIChat, TrainerFormatAlpaca, TrainerFormatParquet:
+		return true
+	}
+	return false
+}
+
+// trainerParquetMessage Parquet 导出中的单条消息
+type trainerParquetMessage struct {
+	Role    string `parquet:"role"`
+	Content string `parquet:"content"`
+}
+
+// trainerParquetRow Parquet 导出的单行
+type trainerParquetRow struct {
+	System   string                  `parquet:"system"`
+	Messages []trainerParquetMessage `parquet:"messages"`
+}
+
+// ConvertJSONLToTrainerFormat 将 meta/turns 结构的 JSONL 转换为训练框架格式
+// 每行先按 meta/turns 结构校验，且最后一轮必须是 Assistant；不合格的行跳过并记录原因
+func ConvertJSONLToTrainerFormat(jsonlContent []byte, format string) (*TrainerConversion, error) {
+	if !IsTrainerFormat(format) {
+		return nil, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+
+	result := &TrainerConversion{Ext: ".jsonl"}
+	var out bytes.Buffer
+	var parquetRows []trainerParquetRow
+
+	scanner := bufio.NewScanner(bytes.NewReader(jsonlContent))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLLineSize)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		issueType, message := validateSchemaLine(line)
+		var data JSONLData
+		if issueType == "" {
+			if err := json.Unmarshal(line, &data); err != nil {
+				issueType, message = SchemaErrInvalidJSON, fmt.Sprintf("JSON解析失败: %v", err)
+			} else if data.Turns[len(data.Turns)-1].Role != "Assistant" {
+				issueType, message = SchemaErrNoFinalAnswer, "最后一轮必须是 Assistant"
+			}
+		}
+		if issueType != "" {
+			result.Skipped++
+			if len(result.Issues) < defaultMaxSchemaErrors {
+				result.Issues = append(result.Issues, SchemaIssue{Line: lineNo, Type: issueType, Message: message})
+			}
+			continue
+		}
+
+		system := metaDescription(data.Meta)
+		if format == TrainerFormatParquet {
+			parquetRows = append(parquetRows, toParquetRow(system, data.Turns))
+			result.Converted++
+			continue
+		}
+
+		var record interface{}
+		switch format {
+		case TrainerFormatShareGPT:
+			record = toShareGPT(system, data.Turns)
+		case TrainerFormatOpenAIChat:
+			record = toOpenAIChat(system, data.Turns)
+		case TrainerFormatAlpaca:
+			record = toAlpaca(system, data.Turns)
+		}
+		encoded, err := json.Marshal(record)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行编码失败: %w", lineNo, err)
+		}
+		out.Write(encoded)
+		out.WriteByte('\n')
+		result.Converted++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取数据失败: %w", err)
+	}
+
+	if format == TrainerFormatParquet {
+		writer := parquet.NewGenericWriter[trainerParquetRow](&out)
+		if _, err := writer.Write(parquetRows); err != nil {
+			return nil, fmt.Errorf("写入Parquet失败: %w", err)
+		}
+		if err := writer.Close(); err != nil {
+			return nil, fmt.Errorf("写入Parquet失败: %w", err)
+		}
+		result.Ext = ".parquet"
+	}
+
+	result.Content = out.Bytes()
+	return result, nil
+}
+
+// metaDescription 取 meta.meta_description 作为系统提示
+func metaDescription(meta map[string]interface{}) string {
+	desc, _ := meta["meta_description"].(string)
+	return strings.TrimSpace(desc)
+}
+
+// toShareGPT 转换为 ShareGPT 格式
+func toShareGPT(system string, turns []Turn) map[string]interface{} {
+	conversations := make([]map[string]string, 0, len(turns)+1)
+	if system != "" {
+		conversations = append(conversations, map[string]string{"from": "system", "value": system})
+	}
+	for _, turn := range turns {
+		from := "human"
+		if turn.Role == "Assistant" {
+			from = "gpt"
+		}
+		conversations = append(conversations, map[string]string{"from": from, "value": turn.Text})
+	}
+	return map[string]interface{}{"conversations": conversations}
+}
+
+// toOpenAIChat 转换为 OpenAI 对话微调格式
+func toOpenAIChat(system string, turns []Turn) map[string]interface{} {
+	messages := make([]map[string]string, 0, len(turns)+1)
+	if system != "" {
+		messages = append(messages, map[string]string{"role": "system", "content": system})
+	}
+	for _, turn := range turns {
+		role := "user"
+		if turn.Role == "Assistant" {
+			role = "assistant"
+		}
+		messages = append(messages, map[string]string{"role": role, "content": turn.Text})
+	}
+	return map[string]interface{}{"messages": messages}
+}
+
+// toAlpaca 转换为 Alpaca 格式：最后一轮问答为 instruction/output，之前的轮次放入 history
+func toAlpaca(system string, turns []Turn) map[string]interface{} {
+	history := make([][2]string, 0, len(turns)/2)
+	for i := 0; i+1 < len(turns)-2; i += 2 {
+		history = append(history, [2]string{turns[i].Text, turns[i+1].Text})
+	}
+	return map[string]interface{}{
+		"instruction": turns[len(turns)-2].Text,
+		"input":       "",
+		"output":      turns[len(turns)-1].Text,
+		"system":      system,
+		"history":     history,
+	}
+}
+
+// toParquetRow 转换为 Parquet 导出行（消息角色与 OpenAI 格式一致）
+func toParquetRow(system string, turns []Turn) trainerParquetRow {
+	row := trainerParquetRow{
+		System:   system,
+		Messages: make([]trainerParquetMessage, len(turns)),
+	}
+	for i, turn := range turns {
+		role := "user"
+		if turn.Role == "Assistant" {
+			role = "assistant"
+		}
+		row.Messages[i] = trainerParquetMessage{Role: role, Content: turn.Text}
+	}
+	return row
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// UploadSniffSize 检查上传文件类型时读取的文件开头字节数
+const UploadSniffSize = 4096
+
+var (
+	zipMagic     = []byte("PK\x03\x04")
+	parquetMagic = []byte("PAR1")
+	utf8BOM      = []byte("\xef\xbb\xbf")
+)
+
+// CheckUploadType 按扩展名白名单和文件开头的内容（magic bytes）检查上传文件的类型
+// xlsx 必须是 zip 格式，parquet 必须以 PAR1 开头，csv 和 txt 必须是 UTF-8、GBK 或 UTF-16 文本，其余格式（jsonl、json 等）必须是 UTF-8 文本；
+// allowed 为带点的小写扩展名，为空时不限制扩展名
+func CheckUploadType(filename string, head []byte, allowed []string) error {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if len(allowed) > 0 && !containsString(allowed, ext) {
+		if ext == "" {
+			return fmt.Errorf("文件缺少扩展名，仅支持 %s 文件", strings.Join(allowed, "、"))
+		}
+		return fmt.Errorf("不支持的文件类型 %s，仅支持 %s 文件", ext, strings.Join(allowed, "、"))
+	}
+	if len(head) == 0 {
+		return nil
+	}
+
+	switch ext {
+	case ".xlsx":
+		if !bytes.HasPrefix(head, zipMagic) {
+			return fmt.Errorf("文件内容不是有效的 Excel（xlsx）文件（检测到 %s）", http.DetectContentType(head))
+		}
+	case ".parquet":
+		if !bytes.HasPrefix(head, parquetMagic) {
+			return fmt.Errorf("文件内容不是有效的 Parquet 文件（检测到 %s）", http.DetectContentType(head))
+		}
+	case ".csv", ".txt":
+		if !looksLikeEncodedText(head) {
+			return fmt.Errorf("%s 文件的内容不是文本（检测到 %s），请确认扩展名与文件内容一致", ext, http.DetectContentType(head))
+		}
+	default:
+		if !looksLikeText(head) {
+			return fmt.Errorf("%s 文件的内容不是 UTF-8 文本（检测到 %s），请确认扩展名与文件内容一致", ext, http.DetectContentType(head))
+		}
+		trimmed := bytes.TrimLeft(bytes.TrimPrefix(head, utf8BOM), " \t\r\n")
+		if ext == ".jsonl" && len(trimmed) > 0 && trimmed[0] != '{' {
+			return fmt.Errorf("JSONL 文件的每一行应为一个 JSON 对象，第一行不是以 { 开头")
+		}
+		if ext == ".json" && len(trimmed) > 0 && trimmed[0] != '[' && trimmed[0] != '{' {
+			return fmt.Errorf("JSON 文件应为对象数组，内容不是以 [ 开头")
+		}
+	}
+	return nil
+}
+
+// looksLikeText 判断文件开头是否为 UTF-8 文本（不含 NUL 字节；末尾被截断的多字节字符不影响判断）
+func looksLikeText(head []byte) bool {
+	if bytes.IndexByte(head, 0) >= 0 {
+		return false
+	}
+	for i := 0; i < utf8.UTFMax && i < len(head); i++ {
+		if utf8.Valid(head[:len(head)-i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// looksLikeEncodedText 判断文件开头是否为 CSV/纯文本支持的文本编码：UTF-16 按 BOM 或 0 字节分布识别，其余编码（UTF-8、GBK）不能含 NUL 字节
+func looksLikeEncodedText(head []byte) bool {
+	switch detectTextEncoding(head) {
+	case CSVEncodingUTF16LE, CSVEncodingUTF16BE:
+		return true
+	}
+	return bytes.IndexByte(head, 0) < 0
+}
+
+// containsString 判断字符串切片是否包含指定值
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package workerrpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+// Client 工作节点（cmd/agent）使用的 gRPC 客户端，请求按 JSON 编码并携带内部密钥
+type Client struct {
+	conn        *grpc.ClientConn
+	internalKey string
+}
+
+// Dial 连接后端的工作进程 gRPC 服务，creds 为 ClientTLS 或 InsecureCredentials
+func Dial(target, internalKey string, creds credentials.TransportCredentials) (*Client, error) {
+	conn, err := grpc.Dial(target,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(jsonCodec{})),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, internalKey: internalKey}, nil
+}
+
+// Close 关闭连接
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) invoke(ctx context.Context, method string, req, resp interface{}) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, internalKeyMetadata, c.internalKey)
+	return c.conn.Invoke(ctx, "/"+ServiceName+"/"+method, req, resp)
+}
+
+// RegisterAgent 登记工作节点
+func (c *Client) RegisterAgent(ctx context.Context, req *RegisterAgentRequest) (*RegisterAgentResponse, error) {
+	resp := new(RegisterAgentResponse)
+	return resp, c.invoke(ctx, "RegisterAgent", req, resp)
+}
+
+// PullTask 领取任务
+func (c *Client) PullTask(ctx context.Context, req *PullTaskRequest) (*PullTaskResponse, error) {
+	resp := new(PullTaskResponse)
+	return resp, c.invoke(ctx, "PullTask", req, resp)
+}
+
+// ReportOutput 转发任务进程的输出
+func (c *Client) ReportOutput(ctx context.Context, req *ReportOutputRequest) (*ReportOutputResponse, error) {
+	resp := new(ReportOutputResponse)
+	return resp, c.invoke(ctx, "ReportOutput", req, resp)
+}
+
+// CompleteTask 上报任务进程结束
+func (c *Client) CompleteTask(ctx context.Context, req *CompleteTaskRequest) error {
+	return c.invoke(ctx, "CompleteTask", req, new(CompleteTaskResponse))
+}
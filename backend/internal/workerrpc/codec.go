@@ -0,0 +1,44 @@
+package workerrpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// internalKeyMetadata 携带内部密钥的 metadata 键
+const internalKeyMetadata = "x-internal-api-key"
+
+// jsonCodec 按 JSON 编解码消息（工作进程使用 grpcio 的通用调用并以 json 序列化请求）
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+// authInterceptor 校验 metadata 中的内部密钥
+func authInterceptor(internalKey string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		keys := md.Get(internalKeyMetadata)
+		if len(keys) == 0 || keys[0] != internalKey {
+			return nil, status.Error(codes.Unauthenticated, "无效的内部API密钥")
+		}
+		return handler(ctx, req)
+	}
+}
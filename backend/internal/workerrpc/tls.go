@@ -0,0 +1,63 @@
+package workerrpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// 传给 main.py 的 TLS 环境变量（develop/worker_rpc.py 读取）
+const (
+	EnvTLS           = "WORKER_GRPC_TLS"             // 为 1 时使用 TLS 连接
+	EnvTLSCA         = "WORKER_GRPC_TLS_CA"          // 校验服务端证书的 CA 证书文件，为空时使用系统根证书
+	EnvTLSServerName = "WORKER_GRPC_TLS_SERVER_NAME" // 校验证书时使用的服务器名
+)
+
+// ServerTLS 加载服务端证书和私钥（PEM 文件）
+func ServerTLS(certFile, keyFile string) (grpc.ServerOption, error) {
+	creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("加载 gRPC TLS 证书失败: %w", err)
+	}
+	return grpc.Creds(creds), nil
+}
+
+// ClientTLS 创建校验服务端证书的客户端凭据
+// caFile 为空时使用系统根证书，serverName 为空时按连接地址校验
+func ClientTLS(caFile, serverName string) (credentials.TransportCredentials, error) {
+	tlsConfig := &tls.Config{ServerName: serverName, MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取 CA 证书失败: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA 证书 %s 中没有有效的 PEM 证书", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// InsecureCredentials 不加密的客户端凭据（后端未开启 TLS 时使用）
+func InsecureCredentials() credentials.TransportCredentials {
+	return insecure.NewCredentials()
+}
+
+// TLSEnviron 生成传给 main.py 的 TLS 环境变量
+func TLSEnviron(caFile, serverName string) []string {
+	env := []string{EnvTLS + "=1"}
+	if caFile != "" {
+		env = append(env, EnvTLSCA+"="+caFile)
+	}
+	if serverName != "" {
+		env = append(env, EnvTLSServerName+"="+serverName)
+	}
+	return env
+}
@@ -0,0 +1,133 @@
+package workerrpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeWorker 只实现 RegisterAgent，其余方法不会被调用
+type fakeWorker struct {
+	WorkerServer
+}
+
+func (fakeWorker) RegisterAgent(ctx context.Context, req *RegisterAgentRequest) (*RegisterAgentResponse, error) {
+	return &RegisterAgentResponse{Accepted: true}, nil
+}
+
+// writeSelfSignedCert 生成 localhost 的自签名证书，返回证书和私钥文件路径
+func writeSelfSignedCert(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestTLSServer(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t)
+	creds, err := ServerTLS(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer("secret", creds)
+	RegisterWorkerServer(server, fakeWorker{})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(listener)
+	defer server.Stop()
+
+	call := func(client *Client) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := client.RegisterAgent(ctx, &RegisterAgentRequest{AgentID: "a"})
+		return err
+	}
+
+	clientTLS, err := ClientTLS(certFile, "localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		key      string
+		tls      bool
+		wantCode codes.Code
+	}{
+		{"tls with key", "secret", true, codes.OK},
+		{"tls with wrong key", "wrong", true, codes.Unauthenticated},
+		{"plaintext rejected", "secret", false, codes.Unavailable},
+	}
+	for _, tt := range tests {
+		creds := InsecureCredentials()
+		if tt.tls {
+			creds = clientTLS
+		}
+		client, err := Dial(listener.Addr().String(), tt.key, creds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = call(client)
+		client.Close()
+		if code := status.Code(err); code != tt.wantCode {
+			t.Errorf("%s: code = %v, want %v (err %v)", tt.name, code, tt.wantCode, err)
+		}
+	}
+}
+
+func TestClientTLSInvalidCA(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(file, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ClientTLS(file, ""); err == nil {
+		t.Fatal("ClientTLS with an invalid CA file expected error")
+	}
+	if _, err := ClientTLS(filepath.Join(t.TempDir(), "missing.pem"), ""); err == nil {
+		t.Fatal("ClientTLS with a missing CA file expected error")
+	}
+}
@@ -0,0 +1,193 @@
+// Package workerrpc Go 后端与 Python 工作进程、工作节点之间的 gRPC 服务定义（对应 proto/worker.proto）
+// 消息按 proto3 JSON 映射编码，服务端强制使用 JSON 编解码器，双方都不需要 protoc 生成代码
+package workerrpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// ServiceName gRPC 服务全名
+const ServiceName = "worker.v1.Worker"
+
+// StartWorkRequest 工作进程登记任务
+type StartWorkRequest struct {
+	TaskID          string `json:"task_id"`
+	WorkerVersion   string `json:"worker_version"`
+	ProtocolVersion int    `json:"protocol_version"`
+}
+
+// StartWorkResponse 登记结果
+type StartWorkResponse struct {
+	Accepted bool   `json:"accepted"`
+	Message  string `json:"message,omitempty"`
+	FileID   uint   `json:"file_id,omitempty"`
+	UserID   uint   `json:"user_id,omitempty"`
+}
+
+// ReportProgressRequest 上报心跳、进度或日志行
+type ReportProgressRequest struct {
+	TaskID   string                 `json:"task_id"`
+	Type     string                 `json:"type"` // heartbeat / progress / output
+	Line     string                 `json:"line,omitempty"`
+	Progress map[string]interface{} `json:"progress,omitempty"`
+}
+
+// ReportProgressResponse 上报结果
+type ReportProgressResponse struct {
+	Running bool `json:"running"` // 任务已被停止时为 false
+}
+
+// SubmitResultsRequest 提交已完成的生成数据
+type SubmitResultsRequest struct {
+	TaskID string                   `json:"task_id"`
+	Items  []map[string]interface{} `json:"items"`
+}
+
+// SubmitResultsResponse 提交结果
+type SubmitResultsResponse struct {
+	Accepted int `json:"accepted"`
+}
+
+// FetchBatchRequest 分页读取样本
+type FetchBatchRequest struct {
+	TaskID string `json:"task_id"`
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+}
+
+// FetchBatchResponse 样本分页
+type FetchBatchResponse struct {
+	Samples []map[string]interface{} `json:"samples"`
+	Total   int                      `json:"total"`
+	HasMore bool                     `json:"has_more"`
+}
+
+// RegisterAgentRequest 工作节点登记
+type RegisterAgentRequest struct {
+	AgentID         string `json:"agent_id"`
+	Hostname        string `json:"hostname,omitempty"`
+	WorkerVersion   string `json:"worker_version"`
+	ProtocolVersion int    `json:"protocol_version"`
+	Capacity        int    `json:"capacity"` // 可同时运行的任务数
+}
+
+// RegisterAgentResponse 登记结果
+type RegisterAgentResponse struct {
+	Accepted    bool   `json:"accepted"`
+	Message     string `json:"message,omitempty"`
+	PollSeconds int    `json:"poll_seconds,omitempty"` // 轮询间隔，超过 agent_offline_seconds 未轮询视为下线
+}
+
+// PullTaskRequest 工作节点轮询领取任务（同时作为心跳）
+type PullTaskRequest struct {
+	AgentID string   `json:"agent_id"`
+	Running []string `json:"running,omitempty"` // 工作节点上正在运行的任务ID
+}
+
+// TaskAssignment 分配给工作节点的任务
+type TaskAssignment struct {
+	TaskID string   `json:"task_id"`
+	Args   []string `json:"args"` // main.py 的命令行参数（第一个为 main.py）
+}
+
+// PullTaskResponse 领取结果
+type PullTaskResponse struct {
+	Task *TaskAssignment `json:"task,omitempty"` // 没有待运行的任务时为空
+	Stop []string        `json:"stop,omitempty"` // 需要停止的任务ID
+}
+
+// OutputLine 任务进程的一行输出
+type OutputLine struct {
+	Stream string `json:"stream"` // stdout / stderr
+	Line   string `json:"line"`
+}
+
+// ReportOutputRequest 工作节点转发任务进程的输出
+type ReportOutputRequest struct {
+	AgentID string       `json:"agent_id"`
+	TaskID  string       `json:"task_id"`
+	Lines   []OutputLine `json:"lines"`
+}
+
+// ReportOutputResponse 转发结果
+type ReportOutputResponse struct {
+	Running bool `json:"running"` // 任务已被停止时为 false，工作节点应终止进程
+}
+
+// CompleteTaskRequest 任务进程结束
+type CompleteTaskRequest struct {
+	AgentID    string `json:"agent_id"`
+	TaskID     string `json:"task_id"`
+	ReturnCode int    `json:"return_code"`
+	Error      string `json:"error,omitempty"` // 进程未能启动或异常退出的原因
+}
+
+// CompleteTaskResponse 上报结果
+type CompleteTaskResponse struct{}
+
+// WorkerServer 工作进程 gRPC 服务的实现
+type WorkerServer interface {
+	StartWork(ctx context.Context, req *StartWorkRequest) (*StartWorkResponse, error)
+	ReportProgress(ctx context.Context, req *ReportProgressRequest) (*ReportProgressResponse, error)
+	SubmitResults(ctx context.Context, req *SubmitResultsRequest) (*SubmitResultsResponse, error)
+	FetchBatch(ctx context.Context, req *FetchBatchRequest) (*FetchBatchResponse, error)
+	RegisterAgent(ctx context.Context, req *RegisterAgentRequest) (*RegisterAgentResponse, error)
+	PullTask(ctx context.Context, req *PullTaskRequest) (*PullTaskResponse, error)
+	ReportOutput(ctx context.Context, req *ReportOutputRequest) (*ReportOutputResponse, error)
+	CompleteTask(ctx context.Context, req *CompleteTaskRequest) (*CompleteTaskResponse, error)
+}
+
+// RegisterWorkerServer 注册工作进程服务
+func RegisterWorkerServer(s *grpc.Server, srv WorkerServer) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+// NewServer 创建使用 JSON 编解码器并校验内部密钥的 gRPC 服务器，opts 可传入 ServerTLS 等额外选项
+func NewServer(internalKey string, opts ...grpc.ServerOption) *grpc.Server {
+	return grpc.NewServer(append([]grpc.ServerOption{
+		grpc.ForceServerCodec(jsonCodec{}),
+		grpc.UnaryInterceptor(authInterceptor(internalKey)),
+	}, opts...)...)
+}
+
+// unaryHandler 生成单个方法的处理函数
+func unaryHandler[Req any, Resp any](method string, call func(WorkerServer, context.Context, *Req) (*Resp, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: method,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			req := new(Req)
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			if interceptor == nil {
+				return call(srv.(WorkerServer), ctx, req)
+			}
+			info := &grpc.UnaryServerInfo{
+				Server:     srv,
+				FullMethod: "/" + ServiceName + "/" + method,
+			}
+			return interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return call(srv.(WorkerServer), ctx, req.(*Req))
+			})
+		},
+	}
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*WorkerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		unaryHandler("StartWork", WorkerServer.StartWork),
+		unaryHandler("ReportProgress", WorkerServer.ReportProgress),
+		unaryHandler("SubmitResults", WorkerServer.SubmitResults),
+		unaryHandler("FetchBatch", WorkerServer.FetchBatch),
+		unaryHandler("RegisterAgent", WorkerServer.RegisterAgent),
+		unaryHandler("PullTask", WorkerServer.PullTask),
+		unaryHandler("ReportOutput", WorkerServer.ReportOutput),
+		unaryHandler("CompleteTask", WorkerServer.CompleteTask),
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/worker.proto",
+}
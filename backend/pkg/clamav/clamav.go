@@ -0,0 +1,146 @@
+package clamav
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// chunkSize INSTREAM 每个数据块的大小（clamd 的 StreamMaxLength 限制的是总大小，与块大小无关）
+const chunkSize = 64 * 1024
+
+// Client clamd 客户端，通过 TCP 或 Unix Socket 使用 INSTREAM 命令扫描内存中的内容或文件流
+// 每次调用建立新连接，可在多个 goroutine 中共用
+type Client struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// Result 扫描结果
+type Result struct {
+	Infected  bool
+	Signature string // 命中的病毒特征名，未感染时为空
+}
+
+// NewClient 创建 clamd 客户端
+// address 形如 tcp://127.0.0.1:3310 或 unix:///var/run/clamav/clamd.ctl，不带协议时按 TCP 地址处理
+func NewClient(address string, timeout time.Duration) (*Client, error) {
+	network, addr := "tcp", address
+	switch {
+	case strings.HasPrefix(address, "tcp://"):
+		addr = strings.TrimPrefix(address, "tcp://")
+	case strings.HasPrefix(address, "unix://"):
+		network, addr = "unix", strings.TrimPrefix(address, "unix://")
+	case strings.HasPrefix(address, "unix:"):
+		network, addr = "unix", strings.TrimPrefix(address, "unix:")
+	}
+	if addr == "" {
+		return nil, fmt.Errorf("clamd 地址为空")
+	}
+	return &Client{network: network, address: addr, timeout: timeout}, nil
+}
+
+// Ping 检查 clamd 是否可用
+func (c *Client) Ping(ctx context.Context) error {
+	reply, err := c.command(ctx, "zPING\x00", nil)
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("clamd 响应异常: %s", reply)
+	}
+	return nil
+}
+
+// Scan 扫描 content；发现病毒时返回 Infected 为 true 的结果，clamd 报告错误（如超过 StreamMaxLength）时返回 error
+func (c *Client) Scan(ctx context.Context, content []byte) (*Result, error) {
+	return c.ScanReader(ctx, bytes.NewReader(content))
+}
+
+// ScanReader 扫描 r 中的全部内容（按块读取发送，不整体载入内存），结果同 Scan
+func (c *Client) ScanReader(ctx context.Context, r io.Reader) (*Result, error) {
+	reply, err := c.command(ctx, "zINSTREAM\x00", r)
+	if err != nil {
+		return nil, err
+	}
+
+	// 响应格式：stream: OK / stream: <特征名> FOUND / <错误信息> ERROR
+	reply = strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case reply == "OK":
+		return &Result{}, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return &Result{Infected: true, Signature: strings.TrimSuffix(reply, " FOUND")}, nil
+	default:
+		return nil, fmt.Errorf("clamd 扫描失败: %s", reply)
+	}
+}
+
+// command 发送命令（content 不为 nil 时按 INSTREAM 格式分块发送）并读取以 NUL 结尾的响应
+func (c *Client) command(ctx context.Context, cmd string, content io.Reader) (string, error) {
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, c.network, c.address)
+	if err != nil {
+		return "", fmt.Errorf("连接 clamd 失败: %w", err)
+	}
+	defer conn.Close()
+
+	var deadline time.Time
+	if c.timeout > 0 {
+		deadline = time.Now().Add(c.timeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+	if !deadline.IsZero() {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := io.WriteString(conn, cmd); err != nil {
+		return "", fmt.Errorf("发送 clamd 命令失败: %w", err)
+	}
+	if content != nil {
+		if err := writeStream(conn, content); err != nil {
+			return "", fmt.Errorf("发送扫描内容失败: %w", err)
+		}
+	}
+
+	reply, err := io.ReadAll(conn)
+	if err != nil {
+		return "", fmt.Errorf("读取 clamd 响应失败: %w", err)
+	}
+	return string(bytes.TrimRight(reply, "\x00\n")), nil
+}
+
+// writeStream 按 INSTREAM 格式发送内容：每块前加 4 字节大端长度，以长度为 0 的块结束
+func writeStream(w io.Writer, content io.Reader) error {
+	size := make([]byte, 4)
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := io.ReadFull(content, buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := w.Write(size); err != nil {
+				return err
+			}
+			if _, err := w.Write(buf[:n]); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	binary.BigEndian.PutUint32(size, 0)
+	_, err := w.Write(size)
+	return err
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Login 使用用户名和密码登录，成功后客户端自动使用返回的Token
+func (c *Client) Login(ctx context.Context, username, password string) (*LoginResponse, error) {
+	var resp LoginResponse
+	err := c.call(ctx, &request{
+		method:    http.MethodPost,
+		path:      "/api/login",
+		body:      jsonBody(map[string]string{"username": username, "password": password}),
+		anonymous: true,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	c.SetToken(resp.AccessToken, resp.RefreshToken)
+	return &resp, nil
+}
+
+// refresh 使用刷新Token换取新的访问Token
+func (c *Client) refresh(ctx context.Context) error {
+	c.mu.RLock()
+	refreshToken := c.refreshToken
+	c.mu.RUnlock()
+
+	var resp LoginResponse
+	err := c.call(ctx, &request{
+		method:    http.MethodPost,
+		path:      "/api/refresh",
+		body:      jsonBody(map[string]string{"refresh_token": refreshToken}),
+		anonymous: true,
+	}, &resp)
+	if err != nil {
+		return err
+	}
+	if resp.RefreshToken == "" {
+		resp.RefreshToken = refreshToken
+	}
+	c.SetToken(resp.AccessToken, resp.RefreshToken)
+	return nil
+}
+
+// UploadFile 上传本地数据文件，validationMode 为空时使用服务端默认的校验模式
+func (c *Client) UploadFile(ctx context.Context, path, validationMode string) (*UploadResult, error) {
+	return c.upload(ctx, filepath.Base(path), validationMode, func() (io.ReadCloser, error) {
+		return os.Open(path)
+	})
+}
+
+// UploadReader 以指定文件名上传内容（内容会读入内存，以便失败时重试）
+func (c *Client) UploadReader(ctx context.Context, filename string, r io.Reader, validationMode string) (*UploadResult, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("读取上传内容失败: %w", err)
+	}
+	return c.upload(ctx, filename, validationMode, func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(content)), nil
+	})
+}
+
+// upload 以 multipart 表单流式上传文件，open 在每次重试时重新打开内容
+func (c *Client) upload(ctx context.Context, filename, validationMode string, open func() (io.ReadCloser, error)) (*UploadResult, error) {
+	body := func() (io.Reader, string, error) {
+		src, err := open()
+		if err != nil {
+			return nil, "", err
+		}
+		pr, pw := io.Pipe()
+		writer := multipart.NewWriter(pw)
+		go func() {
+			defer src.Close()
+			err := writeUploadForm(writer, filename, validationMode, src)
+			pw.CloseWithError(err)
+		}()
+		return pr, writer.FormDataContentType(), nil
+	}
+
+	var result UploadResult
+	// 上传不设置普通请求的超时，大文件由调用方通过 ctx 控制
+	resp, err := c.send(ctx, &request{method: http.MethodPost, path: "/api/data_files/upload", body: body})
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeEnvelope(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// writeUploadForm 写入上传表单
+func writeUploadForm(writer *multipart.Writer, filename, validationMode string, src io.Reader) error {
+	if validationMode != "" {
+		if err := writer.WriteField("validation_mode", validationMode); err != nil {
+			return err
+		}
+	}
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, src); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// StartTask 启动数据生成任务
+func (c *Client) StartTask(ctx context.Context, req *StartTaskRequest) (*StartTaskResponse, error) {
+	var resp StartTaskResponse
+	if err := c.call(ctx, &request{method: http.MethodPost, path: "/api/start", body: jsonBody(req), idempotent: req.IdempotencyKey != ""}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ExportData 导出任务的生成数据并写入 w，返回服务端给出的文件名
+// 只在开始写入前重试，写入过程中连接中断时返回错误
+func (c *Client) ExportData(ctx context.Context, taskID string, opts *ExportOptions, w io.Writer) (string, error) {
+	query := url.Values{"task_id": {taskID}}
+	if opts != nil {
+		if opts.Format != "" {
+			query.Set("format", opts.Format)
+		}
+		if opts.Confirmed != nil {
+			query.Set("confirmed", strconv.FormatBool(*opts.Confirmed))
+		}
+		if opts.MinModelScore != nil {
+			query.Set("min_model_score", strconv.FormatFloat(*opts.MinModelScore, 'f', -1, 64))
+		}
+		if opts.TaskType != "" {
+			query.Set("task_type", opts.TaskType)
+		}
+		if opts.ExcludeDuplicates {
+			query.Set("exclude_duplicates", "true")
+		}
+		if opts.IncludeFlagged {
+			query.Set("include_flagged", "true")
+		}
+		if opts.DateFrom != "" {
+			query.Set("date_from", opts.DateFrom)
+		}
+		if opts.DateTo != "" {
+			query.Set("date_to", opts.DateTo)
+		}
+		if len(opts.Tags) > 0 {
+			query.Set("tags", strings.Join(opts.Tags, ","))
+		}
+	}
+
+	resp, err := c.send(ctx, &request{method: http.MethodGet, path: "/api/generated_data/export", query: query})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	filename := ""
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		filename = params["filename"]
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return filename, fmt.Errorf("读取导出数据失败: %w", err)
+	}
+	return filename, nil
+}
@@ -0,0 +1,314 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 3
+	defaultRetryWait  = time.Second
+	defaultTimeout    = 60 * time.Second
+	// maxRetryWait 退避等待的上限（含服务端 Retry-After）
+	maxRetryWait = 30 * time.Second
+)
+
+// Client 数据生成任务管理系统的 HTTP API 客户端
+// 自动携带访问Token，访问Token过期时使用刷新Token续期一次；
+// 网络错误、429 和 5xx 响应按指数退避重试（非幂等请求只在服务端明确未处理时重试）
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+	retryWait  time.Duration
+	timeout    time.Duration // 普通请求的超时时间（不含进度流和导出）
+
+	mu           sync.RWMutex
+	accessToken  string
+	refreshToken string
+}
+
+// Option 客户端选项
+type Option func(*Client)
+
+// WithHTTPClient 使用自定义的 http.Client（不要设置 Timeout，否则会中断进度流）
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetry 设置最大重试次数和首次重试的等待时间（之后每次翻倍）
+func WithRetry(maxRetries int, wait time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryWait = wait
+	}
+}
+
+// WithTimeout 设置普通请求的超时时间
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithToken 使用已有的访问Token和刷新Token（刷新Token可为空）
+func WithToken(accessToken, refreshToken string) Option {
+	return func(c *Client) {
+		c.accessToken = accessToken
+		c.refreshToken = refreshToken
+	}
+}
+
+// New 创建客户端，baseURL 为服务地址（如 http://localhost:8080），不含 /api 前缀
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{},
+		maxRetries: defaultMaxRetries,
+		retryWait:  defaultRetryWait,
+		timeout:    defaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// SetToken 设置访问Token和刷新Token
+func (c *Client) SetToken(accessToken, refreshToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accessToken = accessToken
+	c.refreshToken = refreshToken
+}
+
+// Token 返回当前的访问Token
+func (c *Client) Token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.accessToken
+}
+
+// APIError 服务端返回的错误响应
+type APIError struct {
+	StatusCode int
+	Code       int
+	Message    string
+	Data       json.RawMessage // 错误附带的数据（如文件校验报告）
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API错误 %d: %s", e.StatusCode, e.Message)
+}
+
+// IsStatus 判断错误是否为指定HTTP状态码的 API 错误
+func IsStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// request 一次 API 调用，body 在每次重试时重新生成
+type request struct {
+	method    string
+	path      string
+	query     url.Values
+	body      func() (io.Reader, string, error) // 请求体和 Content-Type
+	anonymous bool                              // 不携带Token（登录、刷新）
+	// idempotent 请求携带幂等键，服务端保证重复请求不会重复执行，可以像幂等方法一样重试
+	idempotent bool
+}
+
+// jsonBody 将请求参数编码为 JSON 请求体
+func jsonBody(in interface{}) func() (io.Reader, string, error) {
+	if in == nil {
+		return nil
+	}
+	return func() (io.Reader, string, error) {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, "", err
+		}
+		return bytes.NewReader(data), "application/json", nil
+	}
+}
+
+// call 发送 JSON 请求并将响应的 data 字段解码到 out（out 为 nil 时忽略）
+func (c *Client) call(ctx context.Context, req *request, out interface{}) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	return decodeEnvelope(resp, out)
+}
+
+// decodeEnvelope 将统一响应格式中的 data 字段解码到 out 并关闭响应
+func decodeEnvelope(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+	env := envelope{Data: out}
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	return nil
+}
+
+// send 发送请求并按策略重试，返回 2xx 响应（调用方负责关闭 Body），其余情况返回错误
+func (c *Client) send(ctx context.Context, req *request) (*http.Response, error) {
+	refreshed := false
+	for attempt := 0; ; attempt++ {
+		resp, err := c.do(ctx, req)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp, nil
+		}
+
+		var wait time.Duration
+		if err == nil {
+			apiErr := readAPIError(resp)
+			// 访问Token过期：刷新一次后立即重试（不计入重试次数）
+			if apiErr.StatusCode == http.StatusUnauthorized && !req.anonymous && !refreshed && c.hasRefreshToken() {
+				refreshed = true
+				if refreshErr := c.refresh(ctx); refreshErr == nil {
+					attempt--
+					continue
+				}
+				return nil, apiErr
+			}
+			if attempt >= c.maxRetries || !retryableStatus(req.isIdempotent(), apiErr.StatusCode) {
+				return nil, apiErr
+			}
+			err = apiErr
+			wait = retryAfter(resp)
+		} else if attempt >= c.maxRetries || ctx.Err() != nil || !retryableError(req.isIdempotent(), err) {
+			return nil, err
+		}
+
+		if wait == 0 {
+			wait = c.retryWait << uint(attempt)
+		}
+		if wait > maxRetryWait {
+			wait = maxRetryWait
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// do 发送一次请求
+func (c *Client) do(ctx context.Context, req *request) (*http.Response, error) {
+	target := c.baseURL + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+
+	var body io.Reader
+	contentType := ""
+	if req.body != nil {
+		var err error
+		if body, contentType, err = req.body(); err != nil {
+			return nil, err
+		}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	if token := c.Token(); token != "" && !req.anonymous {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+	return c.httpClient.Do(httpReq)
+}
+
+// readAPIError 读取并关闭错误响应
+func readAPIError(resp *http.Response) *APIError {
+	defer resp.Body.Close()
+	apiErr := &APIError{StatusCode: resp.StatusCode, Code: resp.StatusCode}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	var env struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &env); err == nil && env.Message != "" {
+		apiErr.Code = env.Code
+		apiErr.Message = env.Message
+		apiErr.Data = env.Data
+	} else {
+		apiErr.Message = strings.TrimSpace(string(body))
+		if apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+	}
+	return apiErr
+}
+
+// retryableStatus 判断响应状态码是否可以重试
+// 非幂等请求只在 429/503（服务端未处理请求，如限流、任务队列已满）时重试，避免重复创建任务
+func retryableStatus(idempotent bool, statusCode int) bool {
+	if statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable {
+		return true
+	}
+	return idempotent && statusCode >= 500
+}
+
+// retryableError 判断网络错误是否可以重试
+// 非幂等请求只在连接建立失败（请求未发出）时重试
+func retryableError(idempotent bool, err error) bool {
+	if idempotent {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isIdempotent 请求可以安全重试：幂等方法，或携带幂等键的请求
+func (r *request) isIdempotent() bool {
+	if r.idempotent {
+		return true
+	}
+	switch r.method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter 解析 Retry-After 响应头（秒数），没有时返回 0
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (c *Client) hasRefreshToken() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.refreshToken != ""
+}
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrStopStream 由进度回调返回以主动结束订阅，StreamProgress 返回 nil
+var ErrStopStream = errors.New("stop progress stream")
+
+// StreamProgress 订阅任务进度（SSE），每个事件调用一次 fn，收到 finished 事件后返回 nil
+// 连接中断时自动重连：事件带ID时携带 last_event_id 只接收之后的事件，
+// 否则请求完整的历史事件并跳过已经回调过的事件；connected 和 history_omitted 提示事件不会传给回调
+func (c *Client) StreamProgress(ctx context.Context, taskID string, fn func(event *ProgressEvent) error) error {
+	path := "/api/progress/" + url.PathEscape(taskID)
+	seen := 0        // 已处理的历史事件数（含首次连接时被服务端省略的事件）
+	var lastID int64 // 最后处理的事件ID
+	failures := 0
+
+	for {
+		var query url.Values
+		if lastID > 0 {
+			query = url.Values{"history_limit": {"0"}, "last_event_id": {strconv.FormatInt(lastID, 10)}}
+		} else if seen > 0 {
+			query = url.Values{"history_limit": {"0"}}
+		}
+		resp, err := c.send(ctx, &request{method: http.MethodGet, path: path, query: query})
+		if err != nil {
+			return err
+		}
+
+		skip := seen
+		if lastID > 0 {
+			skip = 0
+		}
+		finished := false
+		received := false
+		err = readEvents(resp.Body, func(data []byte) error {
+			var event ProgressEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				return fmt.Errorf("解析进度事件失败: %w", err)
+			}
+			switch event.Type {
+			case "connected":
+				return nil
+			case "history_omitted":
+				seen += event.Omitted
+				if lastID == 0 {
+					skip += event.Omitted
+				}
+				return nil
+			}
+
+			received = true
+			if event.ID > 0 && event.ID <= lastID {
+				return nil
+			}
+			if lastID == 0 && skip > 0 {
+				skip--
+				return nil
+			}
+			seen++
+			if event.ID > lastID {
+				lastID = event.ID
+			}
+			if err := fn(&event); err != nil {
+				return err
+			}
+			if event.Type == "finished" {
+				finished = true
+				return io.EOF
+			}
+			return nil
+		})
+		resp.Body.Close()
+
+		if finished || errors.Is(err, ErrStopStream) {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// 回调或解析返回的错误直接返回；连接中断则重连
+		if err != nil && !isStreamBroken(err) {
+			return err
+		}
+
+		if received {
+			failures = 0
+		}
+		failures++
+		if failures > c.maxRetries {
+			return fmt.Errorf("进度流连接中断: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.retryWait << uint(failures-1)):
+		}
+	}
+}
+
+// streamBrokenError 读取进度流时的连接错误
+type streamBrokenError struct {
+	err error
+}
+
+func (e *streamBrokenError) Error() string {
+	return e.err.Error()
+}
+
+func isStreamBroken(err error) bool {
+	var broken *streamBrokenError
+	return errors.As(err, &broken)
+}
+
+// readEvents 按 SSE 格式读取事件，每个事件的 data 行拼接后调用一次 fn
+// fn 返回 io.EOF 表示正常结束；连接提前关闭或读取失败时返回 streamBrokenError
+func readEvents(r io.Reader, fn func(data []byte) error) error {
+	reader := bufio.NewReader(r)
+	var data strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return &streamBrokenError{err: err}
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case line == "":
+			// 空行表示一个事件结束
+			if data.Len() == 0 {
+				continue
+			}
+			payload := data.String()
+			data.Reset()
+			if err := fn([]byte(payload)); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+		case strings.HasPrefix(line, ":"):
+			// 注释行（保活）
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package client
+
+// envelope 服务端统一响应格式
+type envelope struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data"`
+}
+
+// UserInfo 用户信息
+type UserInfo struct {
+	ID                 uint   `json:"id"`
+	Username           string `json:"username"`
+	IsActive           bool   `json:"is_active"`
+	IsAdmin            bool   `json:"is_admin"`
+	Role               string `json:"role"`
+	Timezone           string `json:"timezone"`
+	MustChangePassword bool   `json:"must_change_password"`
+}
+
+// LoginResponse 登录响应
+type LoginResponse struct {
+	AccessToken  string   `json:"access_token"`
+	RefreshToken string   `json:"refresh_token,omitempty"`
+	TokenType    string   `json:"token_type"`
+	ExpiresIn    int64    `json:"expires_in"` // 访问Token有效期（秒）
+	User         UserInfo `json:"user"`
+}
+
+// ValidationIssue 文件校验问题
+type ValidationIssue struct {
+	Line    int    `json:"line"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// FileValidationReport 上传文件的校验报告
+type FileValidationReport struct {
+	FileID           uint              `json:"file_id,omitempty"`
+	Mode             string            `json:"mode"`
+	IsValid          bool              `json:"is_valid"`
+	TotalLines       int               `json:"total_lines"`
+	ValidLines       int               `json:"valid_lines"`
+	InvalidLines     int               `json:"invalid_lines"`
+	ErrorCounts      map[string]int    `json:"error_counts"`
+	Errors           []ValidationIssue `json:"errors"`
+	Truncated        bool              `json:"truncated"`
+	QuarantineFileID *uint             `json:"quarantine_file_id,omitempty"`
+}
+
+// UploadResult 上传文件结果
+type UploadResult struct {
+	ID          uint                  `json:"id"`
+	Filename    string                `json:"filename"`
+	DisplayPath string                `json:"display_path"`
+	FileSize    int64                 `json:"file_size"`
+	Validation  *FileValidationReport `json:"validation,omitempty"`
+}
+
+// StartTaskRequest 启动任务请求，未设置的字段使用服务端默认值
+type StartTaskRequest struct {
+	InputFile          string                 `json:"input_file"`
+	ModelID            *uint                  `json:"model_id,omitempty"`
+	ModelIDs           []uint                 `json:"model_ids,omitempty"`
+	Model              string                 `json:"model,omitempty"`
+	Services           []string               `json:"services,omitempty"`
+	BatchSize          int                    `json:"batch_size,omitempty"`
+	MaxConcurrent      int                    `json:"max_concurrent,omitempty"`
+	MinScore           int                    `json:"min_score,omitempty"`
+	TaskType           string                 `json:"task_type,omitempty"`
+	VariantsPerSample  int                    `json:"variants_per_sample,omitempty"`
+	DataRounds         int                    `json:"data_rounds,omitempty"`
+	RetryTimes         int                    `json:"retry_times,omitempty"`
+	SpecialPrompt      string                 `json:"special_prompt,omitempty"`
+	Directions         string                 `json:"directions,omitempty"`
+	PromptVersionID    *uint                  `json:"prompt_version_id,omitempty"`
+	FileVersion        *int                   `json:"file_version,omitempty"`
+	APIKey             string                 `json:"api_key,omitempty"`
+	IsVLLM             bool                   `json:"is_vllm,omitempty"`
+	UseProxy           bool                   `json:"use_proxy,omitempty"`
+	TopP               float64                `json:"top_p,omitempty"`
+	MaxTokens          int                    `json:"max_tokens,omitempty"`
+	Timeout            int                    `json:"timeout,omitempty"`
+	DisableModelCache  bool                   `json:"disable_model_cache,omitempty"`
+	MaxRuntimeMinutes  int                    `json:"max_runtime_minutes,omitempty"`
+	DedupAgainstSource bool                   `json:"dedup_against_source,omitempty"`
+	MaxSimilarity      float64                `json:"max_similarity,omitempty"`
+	GlossaryID         *uint                  `json:"glossary_id,omitempty"`
+	GlossaryCheck      bool                   `json:"glossary_check,omitempty"`
+	SafetyCheck        bool                   `json:"safety_check,omitempty"`
+	JudgeModelID       *uint                  `json:"judge_model_id,omitempty"`
+	RequiredReviews    int                    `json:"required_reviews,omitempty"`
+	ExtraArgs          map[string]interface{} `json:"extra_args,omitempty"`
+	// IdempotencyKey 幂等键：设置后启动请求在网络错误和 5xx 时也会重试，服务端对重复请求返回第一次创建的任务
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// StartTaskResponse 启动任务响应
+type StartTaskResponse struct {
+	Success   bool   `json:"success"`
+	TaskID    string `json:"task_id"`
+	Status    string `json:"status"`
+	Duplicate bool   `json:"duplicate,omitempty"` // 幂等键已对应已有任务
+}
+
+// ProgressEvent 任务进度事件（SSE 推送）
+type ProgressEvent struct {
+	ID            int64    `json:"id,omitempty"` // 任务内递增的事件ID
+	Type          string   `json:"type"`         // connected, output, heartbeat, finished, error，以及结构化进度事件
+	Line          string   `json:"line,omitempty"`
+	ReturnCode    *int     `json:"return_code,omitempty"`
+	Progress      *int     `json:"progress,omitempty"`
+	Total         *int     `json:"total,omitempty"`
+	Percent       float64  `json:"percent,omitempty"`
+	Message       string   `json:"message,omitempty"`
+	SchemaVersion int      `json:"schema_version,omitempty"`
+	Round         *int     `json:"round,omitempty"`
+	TotalRounds   *int     `json:"total_rounds,omitempty"`
+	Generated     *int     `json:"generated,omitempty"`
+	ModelScore    *float64 `json:"model_score,omitempty"`
+	RuleScore     *float64 `json:"rule_score,omitempty"`
+	Passed        *bool    `json:"passed,omitempty"`
+	Omitted       int      `json:"omitted,omitempty"`
+	LogURL        string   `json:"log_url,omitempty"`
+}
+
+// ExportOptions 导出生成数据的筛选参数，与 /api/generated_data/export 的查询参数一致
+type ExportOptions struct {
+	Format            string // jsonl（默认）、csv 等
+	Confirmed         *bool
+	MinModelScore     *float64
+	TaskType          string
+	ExcludeDuplicates bool
+	IncludeFlagged    bool
+	DateFrom          string
+	DateTo            string
+	Tags              []string
+}
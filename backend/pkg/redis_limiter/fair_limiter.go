@@ -0,0 +1,170 @@
+package redis_limiter
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// fairPollInterval 公平调度的轮询间隔（固定间隔，保证排在前面的等待者能及时拿到槽位）
+	fairPollInterval = 500 * time.Millisecond
+	// fairStaleAfter 等待者超过该时间未轮询视为已失联（进程退出等），从队列中移除
+	fairStaleAfter = 10 * fairPollInterval
+)
+
+// FairLimiter 基于Redis的加权公平并发限制器
+// 槽位不足时所有等待者进入队列，槽位释放后优先分配给"已占用槽位数/权重"最小的所有者，
+// 同一份额下按入队先后分配，避免单个用户占满模型导致其他用户长时间等待
+type FairLimiter struct {
+	client      *redis.Client
+	ttl         time.Duration
+	maxWaitTime int64 // 最大等待时间（纳秒，轮询机制），可通过 SetMaxWaitTime 更新
+}
+
+// NewFairLimiter 创建基于Redis的公平并发限制器
+func NewFairLimiter(client *redis.Client, ttl time.Duration, maxWaitTime time.Duration) *FairLimiter {
+	return &FairLimiter{
+		client:      client,
+		ttl:         ttl,
+		maxWaitTime: int64(maxWaitTime),
+	}
+}
+
+// SetMaxWaitTime 更新最大等待时间（配置重新加载时调用，对之后开始等待的请求生效）
+func (fl *FairLimiter) SetMaxWaitTime(maxWaitTime time.Duration) {
+	atomic.StoreInt64(&fl.maxWaitTime, int64(maxWaitTime))
+}
+
+// GetMaxWaitTime 获取最大等待时间
+func (fl *FairLimiter) GetMaxWaitTime() time.Duration {
+	return time.Duration(atomic.LoadInt64(&fl.maxWaitTime))
+}
+
+// fairAcquireScript 登记等待者并尝试分配槽位
+// KEYS: 槽位计数, 等待队列(zset), 等待者心跳(hash), 各所有者占用数(hash), 各所有者权重(hash)
+// ARGV: 最大并发数, 等待者ID（"<所有者>|<随机ID>"）, 所有者, 权重, 当前毫秒时间戳, 失联阈值毫秒数, TTL秒数
+// 返回 1 表示获取成功，0 表示需要继续等待
+var fairAcquireScript = redis.NewScript(`
+local max = tonumber(ARGV[1])
+local waiter = ARGV[2]
+local owner = ARGV[3]
+local now = tonumber(ARGV[5])
+local staleBefore = now - tonumber(ARGV[6])
+local ttl = tonumber(ARGV[7])
+
+redis.call('ZADD', KEYS[2], 'NX', now, waiter)
+redis.call('HSET', KEYS[3], waiter, now)
+redis.call('HSET', KEYS[5], owner, ARGV[4])
+for i = 2, 5 do
+	redis.call('EXPIRE', KEYS[i], ttl)
+end
+
+local best, bestShare = nil, nil
+for _, w in ipairs(redis.call('ZRANGE', KEYS[2], 0, -1)) do
+	local seen = tonumber(redis.call('HGET', KEYS[3], w) or '0')
+	if seen < staleBefore then
+		redis.call('ZREM', KEYS[2], w)
+		redis.call('HDEL', KEYS[3], w)
+	else
+		local o = string.match(w, '^(.*)|')
+		local held = tonumber(redis.call('HGET', KEYS[4], o) or '0')
+		local weight = tonumber(redis.call('HGET', KEYS[5], o) or '1')
+		if weight <= 0 then
+			weight = 1
+		end
+		local share = held / weight
+		if best == nil or share < bestShare then
+			best, bestShare = w, share
+		end
+	end
+end
+
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+if current >= max or best ~= waiter then
+	return 0
+end
+
+redis.call('INCR', KEYS[1])
+redis.call('EXPIRE', KEYS[1], ttl)
+redis.call('HINCRBY', KEYS[4], owner, 1)
+redis.call('ZREM', KEYS[2], waiter)
+redis.call('HDEL', KEYS[3], waiter)
+return 1`)
+
+// fairReleaseScript 释放槽位并减少所有者的占用数
+// KEYS: 槽位计数, 各所有者占用数(hash)；ARGV: 所有者
+var fairReleaseScript = redis.NewScript(`
+if tonumber(redis.call('DECR', KEYS[1])) <= 0 then
+	redis.call('DEL', KEYS[1])
+end
+if tonumber(redis.call('HINCRBY', KEYS[2], ARGV[1], -1)) <= 0 then
+	redis.call('HDEL', KEYS[2], ARGV[1])
+end
+return 0`)
+
+// fairKeys 槽位计数键及其附属的队列、心跳、占用、权重键
+func fairKeys(key string) []string {
+	return []string{key, key + ":waiters", key + ":waiter_seen", key + ":holders", key + ":weights"}
+}
+
+// Acquire 以 owner 的身份获取一个并发槽位（带轮询等待机制），weight 为所有者的调度权重
+func (fl *FairLimiter) Acquire(ctx context.Context, key string, maxConcurrent int, owner string, weight int) error {
+	if weight <= 0 {
+		weight = 1
+	}
+	keys := fairKeys(key)
+	waiter := owner + "|" + nextMemberID()
+	maxWaitTime := fl.GetMaxWaitTime()
+	startTime := time.Now()
+
+	for {
+		elapsed := time.Since(startTime)
+		if elapsed >= maxWaitTime {
+			fl.cancel(keys, waiter)
+			return fmt.Errorf("获取并发槽位超时: 已等待 %v, 超过最大等待时间 %v", elapsed.Round(time.Second), maxWaitTime)
+		}
+
+		result, err := fairAcquireScript.Run(ctx, fl.client, keys,
+			maxConcurrent, waiter, owner, weight, time.Now().UnixMilli(), fairStaleAfter.Milliseconds(), int(fl.ttl.Seconds()),
+		).Int64()
+		if err != nil {
+			fl.cancel(keys, waiter)
+			return fmt.Errorf("执行Lua脚本失败: %w", err)
+		}
+		if result == 1 {
+			log.Printf("[FairLimiter] 成功获取槽位, key: %s, 所有者: %s, 等待时间: %v", key, owner, elapsed.Round(time.Second))
+			return nil
+		}
+
+		select {
+		case <-time.After(fairPollInterval):
+		case <-ctx.Done():
+			fl.cancel(keys, waiter)
+			return fmt.Errorf("上下文已取消: %w", ctx.Err())
+		}
+	}
+}
+
+// Release 释放 owner 占用的一个并发槽位
+func (fl *FairLimiter) Release(ctx context.Context, key string, owner string) {
+	keys := fairKeys(key)
+	if err := fairReleaseScript.Run(ctx, fl.client, []string{keys[0], keys[3]}, owner).Err(); err != nil {
+		log.Printf("[FairLimiter] 释放槽位失败: %v", err)
+	}
+}
+
+// cancel 放弃等待，将等待者移出队列（使用独立上下文，调用方上下文可能已取消）
+func (fl *FairLimiter) cancel(keys []string, waiter string) {
+	ctx := context.Background()
+	pipe := fl.client.TxPipeline()
+	pipe.ZRem(ctx, keys[1], waiter)
+	pipe.HDel(ctx, keys[2], waiter)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("[FairLimiter] 移除等待者失败: %v", err)
+	}
+}
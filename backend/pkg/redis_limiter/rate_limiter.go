@@ -0,0 +1,157 @@
+package redis_limiter
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// rateWindow 速率限制的滑动窗口长度
+const rateWindow = time.Minute
+
+// RateLimiter 基于Redis滑动窗口的速率限制器（每分钟请求数 RPM / 每分钟Token数 TPM）
+// 每个请求以时间戳为分数写入有序集合，窗口外的记录在每次检查时清理
+type RateLimiter struct {
+	client      *redis.Client
+	keyPrefix   string
+	maxWaitTime int64 // 最大等待时间（纳秒，轮询机制），可通过 SetMaxWaitTime 更新
+}
+
+// NewRateLimiter 创建基于Redis的速率限制器
+func NewRateLimiter(client *redis.Client, keyPrefix string, maxWaitTime time.Duration) *RateLimiter {
+	return &RateLimiter{
+		client:      client,
+		keyPrefix:   keyPrefix,
+		maxWaitTime: int64(maxWaitTime),
+	}
+}
+
+// SetMaxWaitTime 更新最大等待时间（配置重新加载时调用，对之后开始等待的请求生效）
+func (rl *RateLimiter) SetMaxWaitTime(maxWaitTime time.Duration) {
+	atomic.StoreInt64(&rl.maxWaitTime, int64(maxWaitTime))
+}
+
+// GetMaxWaitTime 获取最大等待时间
+func (rl *RateLimiter) GetMaxWaitTime() time.Duration {
+	return time.Duration(atomic.LoadInt64(&rl.maxWaitTime))
+}
+
+// rateScript 检查并（可选）记录一次请求
+// KEYS[1] 请求记录，KEYS[2] Token记录（成员格式 "<成员ID>:<token数>"）
+// ARGV: 当前毫秒时间戳, 窗口毫秒数, RPM上限, TPM上限, 是否记录(1/0), 记录成员ID
+// 返回需要等待的毫秒数，0 表示已通过
+var rateScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local rpm = tonumber(ARGV[3])
+local tpm = tonumber(ARGV[4])
+local record = ARGV[5] == '1'
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', now - window)
+
+if rpm > 0 and redis.call('ZCARD', KEYS[1]) >= rpm then
+	local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+	return math.max(tonumber(oldest[2]) + window - now, 1)
+end
+
+if tpm > 0 then
+	local used = 0
+	for _, member in ipairs(redis.call('ZRANGE', KEYS[2], 0, -1)) do
+		used = used + tonumber(string.match(member, ':(%d+)$'))
+	end
+	if used >= tpm then
+		local oldest = redis.call('ZRANGE', KEYS[2], 0, 0, 'WITHSCORES')
+		return math.max(tonumber(oldest[2]) + window - now, 1)
+	end
+end
+
+if record and rpm > 0 then
+	redis.call('ZADD', KEYS[1], now, ARGV[6])
+	redis.call('PEXPIRE', KEYS[1], window)
+end
+return 0`)
+
+// Acquire 等待直到未超过 RPM/TPM 限制，并记录一次请求；rpm、tpm 为 0 表示不限制
+func (rl *RateLimiter) Acquire(ctx context.Context, key string, rpm, tpm int) error {
+	return rl.wait(ctx, key, rpm, tpm, true)
+}
+
+// WaitAvailable 等待直到未超过 RPM/TPM 限制，但不记录请求（用于任务调度前的准入检查）
+func (rl *RateLimiter) WaitAvailable(ctx context.Context, key string, rpm, tpm int) error {
+	return rl.wait(ctx, key, rpm, tpm, false)
+}
+
+// wait 轮询检查滑动窗口，超过限制时按窗口中最早记录的过期时间等待
+func (rl *RateLimiter) wait(ctx context.Context, key string, rpm, tpm int, record bool) error {
+	if rpm <= 0 && tpm <= 0 {
+		return nil
+	}
+
+	recordFlag := "0"
+	if record {
+		recordFlag = "1"
+	}
+	keys := []string{rl.keyPrefix + "rpm:" + key, rl.keyPrefix + "tpm:" + key}
+	maxWaitTime := rl.GetMaxWaitTime()
+	startTime := time.Now()
+
+	for {
+		elapsed := time.Since(startTime)
+		if elapsed >= maxWaitTime {
+			return fmt.Errorf("等待速率限制超时: 已等待 %v, 超过最大等待时间 %v", elapsed.Round(time.Second), maxWaitTime)
+		}
+
+		now := time.Now().UnixMilli()
+		result, err := rateScript.Run(ctx, rl.client, keys, now, rateWindow.Milliseconds(), rpm, tpm, recordFlag, nextMemberID()).Int64()
+		if err != nil {
+			return fmt.Errorf("执行Lua脚本失败: %w", err)
+		}
+		if result == 0 {
+			return nil
+		}
+
+		waitTime := time.Duration(result) * time.Millisecond
+		if remaining := maxWaitTime - elapsed; waitTime > remaining {
+			waitTime = remaining
+		}
+		log.Printf("[RateLimiter] 模型: %s, 已达到速率限制 (RPM=%d, TPM=%d), 等待 %v", key, rpm, tpm, waitTime.Round(time.Millisecond))
+
+		select {
+		case <-time.After(waitTime):
+		case <-ctx.Done():
+			return fmt.Errorf("上下文已取消: %w", ctx.Err())
+		}
+	}
+}
+
+// RecordTokens 记录一次调用实际消耗的Token数（计入 TPM 窗口）
+func (rl *RateLimiter) RecordTokens(ctx context.Context, key string, tokens int) {
+	if tokens <= 0 {
+		return
+	}
+	redisKey := rl.keyPrefix + "tpm:" + key
+	pipe := rl.client.TxPipeline()
+	pipe.ZAdd(ctx, redisKey, &redis.Z{
+		Score:  float64(time.Now().UnixMilli()),
+		Member: nextMemberID() + ":" + strconv.Itoa(tokens),
+	})
+	pipe.PExpire(ctx, redisKey, rateWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("[RateLimiter] 记录Token失败: %v", err)
+	}
+}
+
+// memberSeq 进程内自增序号，与纳秒时间戳组合保证有序集合成员唯一
+var memberSeq uint64
+
+// nextMemberID 生成滑动窗口记录的成员ID
+func nextMemberID() string {
+	seq := atomic.AddUint64(&memberSeq, 1)
+	return strconv.FormatInt(time.Now().UnixNano(), 36) + "-" + strconv.FormatUint(seq, 36)
+}
@@ -0,0 +1,83 @@
+package redis_limiter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// TokenBucket 基于Redis的令牌桶限流器（接口限流），多个后端实例共用同一个桶
+// 令牌按每分钟补充数匀速补充，桶容量决定允许的瞬时突发请求数
+type TokenBucket struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+// NewTokenBucket 创建基于Redis的令牌桶限流器
+func NewTokenBucket(client *redis.Client, keyPrefix string) *TokenBucket {
+	return &TokenBucket{
+		client:    client,
+		keyPrefix: keyPrefix,
+	}
+}
+
+// TokenResult 一次取令牌的结果
+type TokenResult struct {
+	Allowed    bool
+	Remaining  int           // 取令牌后桶中剩余的整数令牌数
+	RetryAfter time.Duration // 未取到令牌时距离下一个令牌补充的时间
+	Reset      time.Duration // 距离桶重新补满的时间
+}
+
+// tokenBucketScript 按经过的时间补充令牌后尝试取一个令牌
+// KEYS[1] 令牌桶(hash: tokens, ts)；ARGV: 当前毫秒时间戳, 每分钟补充数, 桶容量
+// 返回 {是否取到(1/0), 剩余令牌数, 下一个令牌的等待毫秒数, 补满的毫秒数}
+var tokenBucketScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2]) / 60000
+local capacity = tonumber(ARGV[3])
+
+local tokens = tonumber(redis.call('HGET', KEYS[1], 'tokens'))
+local ts = tonumber(redis.call('HGET', KEYS[1], 'ts'))
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+tokens = math.min(capacity, tokens + math.max(now - ts, 0) * rate)
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate)
+end
+
+local reset = math.ceil((capacity - tokens) / rate)
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], reset + 1000)
+return {allowed, math.floor(tokens), wait, reset}`)
+
+// Take 从 key 对应的桶中取一个令牌；perMinute 为每分钟补充的令牌数，burst 为桶容量（0 表示等于 perMinute）
+func (tb *TokenBucket) Take(ctx context.Context, key string, perMinute, burst int) (*TokenResult, error) {
+	if burst <= 0 {
+		burst = perMinute
+	}
+
+	values, err := tokenBucketScript.Run(ctx, tb.client, []string{tb.keyPrefix + key}, time.Now().UnixMilli(), perMinute, burst).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("执行Lua脚本失败: %w", err)
+	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("令牌桶脚本返回值异常: %v", values)
+	}
+	return &TokenResult{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		Reset:      time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,165 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// awsService Secrets Manager 在 SigV4 签名中的服务名
+const awsService = "secretsmanager"
+
+// AWSProvider 从 AWS Secrets Manager 读取密钥（SecretString 须为 JSON 对象）
+type AWSProvider struct {
+	endpoint        string
+	region          string
+	secretID        string
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	client          *http.Client
+}
+
+// NewAWSProvider 创建 AWS Secrets Manager 密钥存储
+// endpoint 为空时使用 https://secretsmanager.<region>.amazonaws.com（可改为 VPC 终端节点地址）
+func NewAWSProvider(endpoint, region, secretID, accessKeyID, secretAccessKey, sessionToken string) (*AWSProvider, error) {
+	if region == "" || secretID == "" {
+		return nil, fmt.Errorf("AWS 区域和密钥 ID 不能为空")
+	}
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, fmt.Errorf("AWS 访问密钥不能为空")
+	}
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com", awsService, region)
+	}
+	return &AWSProvider{
+		endpoint:        strings.TrimRight(endpoint, "/"),
+		region:          region,
+		secretID:        secretID,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		sessionToken:    sessionToken,
+		client:          newHTTPClient(),
+	}, nil
+}
+
+// Name 存储类型名称
+func (p *AWSProvider) Name() string {
+	return "aws"
+}
+
+// Fetch 读取密钥当前版本（AWSCURRENT）的全部键值
+func (p *AWSProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	body, _ := json.Marshal(map[string]string{"SecretId": p.secretID})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	p.sign(req, body, time.Now().UTC())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求 AWS Secrets Manager 失败: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取 AWS Secrets Manager 响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError("AWS Secrets Manager", resp.StatusCode, respBody)
+	}
+
+	var result struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("解析 AWS Secrets Manager 响应失败: %w", err)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(result.SecretString), &data); err != nil {
+		return nil, fmt.Errorf("密钥 %s 的 SecretString 不是 JSON 对象", p.secretID)
+	}
+	return stringValues(data), nil
+}
+
+// sign 按 AWS Signature Version 4 为请求签名
+func (p *AWSProvider) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if p.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", p.sessionToken)
+	}
+
+	// 规范请求：签名 Host 和全部已设置的请求头
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hashHex(body),
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", date, p.region, awsService)
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+p.secretAccessKey), date)
+	key = hmacSHA256(key, p.region)
+	key = hmacSHA256(key, awsService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		p.accessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery 按键排序并编码的查询字符串
+func canonicalQuery(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+// hashHex SHA-256 的十六进制编码
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 计算 HMAC-SHA256
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,50 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// requestTimeout 单次读取密钥的超时时间
+const requestTimeout = 10 * time.Second
+
+// Provider 密钥存储，一次读取返回全部键值
+type Provider interface {
+	// Name 存储类型名称（用于日志）
+	Name() string
+	// Fetch 读取密钥的全部键值
+	Fetch(ctx context.Context) (map[string]string, error)
+}
+
+// newHTTPClient 读取密钥使用的 HTTP 客户端
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: requestTimeout}
+}
+
+// stringValues 将 JSON 对象的值转换为字符串（字符串原样保留，其他类型使用 JSON 编码）
+func stringValues(data map[string]interface{}) map[string]string {
+	values := make(map[string]string, len(data))
+	for key, value := range data {
+		switch v := value.(type) {
+		case string:
+			values[key] = v
+		case nil:
+			values[key] = ""
+		default:
+			raw, _ := json.Marshal(v)
+			values[key] = string(raw)
+		}
+	}
+	return values
+}
+
+// responseError 根据非 2xx 响应生成错误（响应体截断到 512 字节）
+func responseError(provider string, status int, body []byte) error {
+	if len(body) > 512 {
+		body = body[:512]
+	}
+	return fmt.Errorf("%s 返回 HTTP %d: %s", provider, status, string(body))
+}
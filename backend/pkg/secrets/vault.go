@@ -0,0 +1,83 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// VaultProvider 从 HashiCorp Vault 的 KV v2 引擎读取密钥
+type VaultProvider struct {
+	address   string
+	token     string
+	namespace string
+	mount     string
+	path      string
+	client    *http.Client
+}
+
+// NewVaultProvider 创建 Vault 密钥存储
+// address 为 Vault 地址（如 https://vault.example.com:8200），mount 为 KV v2 引擎挂载路径，path 为密钥路径
+func NewVaultProvider(address, token, namespace, mount, path string) (*VaultProvider, error) {
+	if address == "" || token == "" || path == "" {
+		return nil, fmt.Errorf("Vault 地址、Token 和密钥路径不能为空")
+	}
+	if mount == "" {
+		mount = "secret"
+	}
+	return &VaultProvider{
+		address:   strings.TrimRight(address, "/"),
+		token:     token,
+		namespace: namespace,
+		mount:     strings.Trim(mount, "/"),
+		path:      strings.Trim(path, "/"),
+		client:    newHTTPClient(),
+	}, nil
+}
+
+// Name 存储类型名称
+func (p *VaultProvider) Name() string {
+	return "vault"
+}
+
+// Fetch 读取密钥最新版本的全部键值
+func (p *VaultProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	url := fmt.Sprintf("%s/v1/%s/data/%s", p.address, p.mount, p.path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求 Vault 失败: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取 Vault 响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError("Vault", resp.StatusCode, body)
+	}
+
+	var result struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("解析 Vault 响应失败: %w", err)
+	}
+	if result.Data.Data == nil {
+		return nil, fmt.Errorf("Vault 密钥 %s/%s 不存在或已删除", p.mount, p.path)
+	}
+	return stringValues(result.Data.Data), nil
+}
@@ -8,6 +8,32 @@ sys.path.insert(0, os.path.dirname(os.path.dirname(__file__)))
 from config import get_web_config, get_redis_config
 
 
+def _backend_url(path: str) -> str:
+    """后端代理地址（host 为 0.0.0.0 时使用 localhost）"""
+    web_config = get_web_config()
+    backend_host = web_config['host']
+    if backend_host == '0.0.0.0':
+        backend_host = 'localhost'
+    return f"http://{backend_host}:{web_config['port']}{path}"
+
+
+def _proxy_headers() -> Dict[str, str]:
+    """调用后端代理的请求头（内部API密钥和链路追踪上下文）"""
+    internal_api_key = os.getenv("INTERNAL_API_KEY", "gen-internal-api-key-2024")
+    headers = {
+        "Content-Type": "application/json",
+        "X-Internal-API-Key": internal_api_key
+    }
+    # 链路追踪：后端启动任务时通过环境变量传入 trace 上下文，模型调用挂在任务的 trace 下
+    traceparent = os.getenv("TRACEPARENT")
+    if traceparent:
+        headers["traceparent"] = traceparent
+        tracestate = os.getenv("TRACESTATE")
+        if tracestate:
+            headers["tracestate"] = tracestate
+    return headers
+
+
 def call_model_via_proxy(
     api_url: str,
     api_key: str,
@@ -20,22 +46,16 @@ def call_model_via_proxy(
     top_p: float = 1.0,
     retry_times: int = 3,
     task_id: str = "",
+    no_cache: bool = False,
 ) -> str:
     """
     通过后端代理调用模型API（带流量控制）
+
+    no_cache: 跳过后端的响应缓存（需要每次得到不同结果的调用）
     """
     # 从统一配置模块读取后端配置
-    web_config = get_web_config()
     redis_config = get_redis_config()
-
-    backend_host = web_config['host']
-    backend_port = web_config['port']
-
-    # 如果host是0.0.0.0，使用localhost
-    if backend_host == '0.0.0.0':
-        backend_host = 'localhost'
-
-    backend_url = f"http://{backend_host}:{backend_port}/api/model-call"
+    backend_url = _backend_url("/api/model-call")
 
     payload = {
         "api_url": api_url,
@@ -48,26 +68,20 @@ def call_model_via_proxy(
         "is_vllm": is_vllm,
         "top_p": top_p,
         "retry_times": retry_times,
-        "task_id": task_id
+        "task_id": task_id,
+        "no_cache": no_cache
     }
 
     # 计算请求超时时间：max_wait_time + 实际调用timeout + 缓冲
     max_wait_time = redis_config['max_wait_time']
     request_timeout = max_wait_time + timeout + 60  # 添加60秒缓冲
 
-    # 获取内部API密钥
-    import os
-    internal_api_key = os.getenv("INTERNAL_API_KEY", "gen-internal-api-key-2024")
-
     try:
         response = requests.post(
             backend_url,
             json=payload,
             timeout=request_timeout,
-            headers={
-                "Content-Type": "application/json",
-                "X-Internal-API-Key": internal_api_key
-            }
+            headers=_proxy_headers()
         )
         response.raise_for_status()
 
@@ -83,6 +97,70 @@ def call_model_via_proxy(
         return f"代理调用失败: {str(e)}"
 
 
+def call_model_batch_via_proxy(payloads: List[Dict]) -> List[str]:
+    """
+    通过后端批量代理调用模型API（/api/model-call/batch，每批最多 256 条）
+
+    payloads 中每一项的字段与 call_model_via_proxy 的请求体相同，
+    后端并发执行（仍受限流和并发槽位约束），返回结果与 payloads 顺序一一对应，
+    失败的请求返回以 "模型调用失败" 开头的错误信息
+    """
+    if not payloads:
+        return []
+
+    redis_config = get_redis_config()
+    max_timeout = max(int(p.get("timeout") or 300) for p in payloads)
+    request_timeout = redis_config['max_wait_time'] + max_timeout + 60
+
+    try:
+        response = requests.post(
+            _backend_url("/api/model-call/batch"),
+            json={"requests": payloads},
+            timeout=request_timeout,
+            headers=_proxy_headers()
+        )
+        response.raise_for_status()
+        results = response.json().get("results", [])
+    except requests.exceptions.ConnectionError as e:
+        return [f"后端代理不可用: {str(e)}"] * len(payloads)
+    except Exception as e:
+        return [f"代理调用失败: {str(e)}"] * len(payloads)
+
+    contents = []
+    for result in results:
+        if result.get("success"):
+            contents.append(result.get("content", ""))
+        else:
+            contents.append(f"模型调用失败: {result.get('error', '未知错误')}")
+    return contents
+
+
+def call_embeddings_via_proxy(inputs: List[str], model: str = "", timeout: int = 300) -> List[List[float]]:
+    """
+    通过后端代理向量化文本（/api/embeddings，每批最多 256 条）
+
+    model 为向量模型的名称或模型路径，为空时使用后端配置的 model_services.embedding_model；
+    返回的向量与 inputs 顺序一一对应，调用失败时抛出 RuntimeError
+    """
+    if not inputs:
+        return []
+
+    redis_config = get_redis_config()
+    request_timeout = redis_config['max_wait_time'] + timeout + 60
+
+    response = requests.post(
+        _backend_url("/api/embeddings"),
+        json={"model": model, "input": inputs},
+        timeout=request_timeout,
+        headers=_proxy_headers()
+    )
+    response.raise_for_status()
+    result = response.json()
+    if not result.get("success"):
+        raise RuntimeError(f"向量接口调用失败: {result.get('error', '未知错误')}")
+    return result.get("embeddings", [])
+
+
 def call_model_api(
     api_url: str,
     api_key: str,
@@ -96,6 +174,7 @@ def call_model_api(
     top_p: float = 1.0,
     use_proxy: bool = True,  # 保留参数以保持接口兼容性，但强制使用代理
     task_id: str = "",
+    no_cache: bool = False,
 ) -> str:
     """
     调用模型API的主入口（仅支持通过后端代理调用）
@@ -114,7 +193,8 @@ def call_model_api(
         is_vllm=is_vllm,
         top_p=top_p,
         retry_times=retry_times,
-        task_id=task_id
+        task_id=task_id,
+        no_cache=no_cache
     )
 
 # 以下函数已删除，不再支持直接调用模式：
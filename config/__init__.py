@@ -53,6 +53,10 @@ def get_config(key_path: str, default: Any = None) -> Any:
         get_config("server.port", 18080)
         get_config("jwt.secret_key", "")
     """
+    env_value = _get_env_override(key_path)
+    if env_value is not None:
+        return env_value
+
     config = load_config()
     keys = key_path.split('.')
     value = config
@@ -66,6 +70,34 @@ def get_config(key_path: str, default: Any = None) -> Any:
     return value if value is not None else default
 
 
+# 环境变量前缀，与 Go 后端一致：server.port 对应 GEN_SERVER_PORT
+ENV_PREFIX = "GEN"
+# 配置段名较长时的环境变量简写
+ENV_SECTION_ALIASES = {"redis_service": "redis", "model_services": "model"}
+
+
+def _get_env_override(key_path: str) -> Any:
+    """
+    读取配置项对应的环境变量（优先于配置文件），值按 YAML 解析（数字、布尔、JSON 列表等）
+
+    Returns:
+        环境变量的值，未设置时返回 None
+    """
+    keys = key_path.split('.')
+    names = [f"{ENV_PREFIX}_{'_'.join(keys).upper()}"]
+    if keys[0] in ENV_SECTION_ALIASES and len(keys) > 1:
+        names.append(f"{ENV_PREFIX}_{'_'.join([ENV_SECTION_ALIASES[keys[0]]] + keys[1:]).upper()}")
+
+    for name in names:
+        raw = os.environ.get(name)
+        if raw:
+            try:
+                return yaml.safe_load(raw)
+            except yaml.YAMLError:
+                return raw
+    return None
+
+
 # ==================== 便捷配置访问函数 ====================
 
 def get_web_config() -> Dict[str, Any]:
@@ -143,6 +175,18 @@ def get_redis_config() -> Dict[str, Any]:
     }
 
 
+def get_database_config() -> Dict[str, Any]:
+    """
+    获取数据库配置（与 Go 后端 database 配置一致）
+    driver 为 sqlite（默认）或 postgres；path 为 SQLite 文件路径（相对路径相对于项目根目录）；dsn 为 PostgreSQL 连接串
+    """
+    return {
+        'driver': get_config('database.driver', 'sqlite'),
+        'path': get_config('database.path', './database/app.db'),
+        'dsn': get_config('database.dsn', ''),
+    }
+
+
 def get_model_services_config() -> Dict[str, Any]:
     """获取默认模型服务配置"""
     return {
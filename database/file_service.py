@@ -4,8 +4,10 @@
 提供文件的创建、查询、删除等操作
 """
 
+import os
+
 from sqlalchemy.orm import Session
-from .models import DataFile, User
+from .models import DataFile, User, FILE_STORAGE_DIR
 from typing import Optional, List
 
 
@@ -162,5 +164,13 @@ def get_file_content(db: Session, file_id: int, user_id: int) -> Optional[bytes]
     data_file = get_data_file_by_id(db, file_id, user_id)
     if not data_file:
         return None
-    
+
+    # 大文件的内容保存在文件存储中，数据库只记录相对路径
+    if data_file.storage_path and not data_file.file_content:
+        path = os.path.normpath(os.path.join(FILE_STORAGE_DIR, data_file.storage_path))
+        if not path.startswith(FILE_STORAGE_DIR + os.sep):
+            return None
+        with open(path, 'rb') as f:
+            return f.read()
+
     return data_file.file_content
@@ -2,12 +2,43 @@
 生成数据服务 - 处理模型生成数据的数据库操作
 """
 from sqlalchemy.orm import Session
-from typing import List, Dict, Any, Optional
+from sqlalchemy.dialects.postgresql import insert as postgresql_insert
+from sqlalchemy.dialects.sqlite import insert as sqlite_insert
+from typing import List, Dict, Any, Optional, Tuple
+import hashlib
 import json
 from datetime import datetime
 from .models import GeneratedData, SessionLocal
 
 
+# 生成数据中存放幂等键组成部分的字段（与 develop/single_gen.py 的 IDEMPOTENCY_FIELD 一致），保存前从数据内容中取出
+IDEMPOTENCY_FIELD = '_idempotency'
+
+
+def build_idempotency_key(task_id: str, seed_hash: str, seed_index: int, variant_index: int) -> str:
+    """
+    计算生成数据的幂等键：sha256("<task_id>|<seed_hash>|<seed_index>|<variant_index>")
+    seed_index 为样本在输入中的序号，内容相同的种子样本不会共用同一个键
+    与后端 utils.GenerationIdempotencyKey 保持一致
+    """
+    raw = f"{task_id}|{seed_hash}|{seed_index}|{variant_index}"
+    return hashlib.sha256(raw.encode('utf-8')).hexdigest()
+
+
+def split_idempotency_fields(data_item: Dict[str, Any]) -> Tuple[Dict[str, Any], Optional[Dict[str, Any]]]:
+    """
+    从生成数据中取出幂等键组成部分，返回 (数据内容, {seed_hash, seed_index, variant_index})
+    不修改传入的字典；缺少任一字段时第二项为 None
+    """
+    content = dict(data_item)
+    fields = content.pop(IDEMPOTENCY_FIELD, None)
+    if not isinstance(fields, dict):
+        return content, None
+    if fields.get('seed_hash') is None or fields.get('seed_index') is None or fields.get('variant_index') is None:
+        return content, None
+    return content, fields
+
+
 def save_generated_data(
     task_id: str,
     user_id: int,
@@ -17,11 +48,15 @@ def save_generated_data(
     retry_count: int = 0,
     generation_model: Optional[str] = None,
     task_type: Optional[str] = None,
-    db: Optional[Session] = None
+    db: Optional[Session] = None,
+    idempotency: Optional[Dict[str, Any]] = None
 ) -> GeneratedData:
     """
     保存单条生成数据到数据库
     
+    提供 idempotency（seed_hash、seed_index、variant_index）时按幂等键写入：
+    同一条样本重试写入时保留已有记录（不覆盖审核人的修改和确认状态），不会插入重复行
+    
     Args:
         task_id: 任务ID
         user_id: 用户ID
@@ -32,6 +67,7 @@ def save_generated_data(
         generation_model: 生成模型名称
         task_type: 任务类型
         db: 数据库会话（可选，如果不提供则自动创建）
+        idempotency: 幂等键组成部分（可选），写入独立的列
         
     Returns:
         保存后的GeneratedData对象
@@ -45,6 +81,38 @@ def save_generated_data(
         # 将数据内容转换为JSON字符串
         content_json = json.dumps(data_content, ensure_ascii=False)
         
+        if idempotency:
+            idempotency_key = build_idempotency_key(
+                task_id, idempotency['seed_hash'], idempotency['seed_index'], idempotency['variant_index']
+            )
+            now = datetime.utcnow()
+            # 按连接的数据库选择方言，两种方言的 INSERT ... ON CONFLICT DO NOTHING 写法相同
+            insert = postgresql_insert if db.get_bind().dialect.name == 'postgresql' else sqlite_insert
+            stmt = insert(GeneratedData).values(
+                task_id=task_id,
+                user_id=user_id,
+                data_content=content_json,
+                model_score=model_score,
+                rule_score=rule_score,
+                retry_count=retry_count,
+                generation_model=generation_model,
+                task_type=task_type,
+                is_confirmed=False,
+                idempotency_key=idempotency_key,
+                seed_hash=idempotency['seed_hash'],
+                seed_index=idempotency['seed_index'],
+                variant_index=idempotency['variant_index'],
+                created_at=now,
+                updated_at=now
+            )
+            stmt = stmt.on_conflict_do_nothing(index_elements=['idempotency_key'])
+            db.execute(stmt)
+            db.commit()
+            
+            return db.query(GeneratedData).filter(
+                GeneratedData.idempotency_key == idempotency_key
+            ).first()
+        
         # 创建数据记录
         generated_data = GeneratedData(
             task_id=task_id,
@@ -106,17 +174,21 @@ def save_batch_generated_data(
             # 如果meta中没有model信息，使用传入的参数
             gen_model = meta.get('generation_model') or generation_model
             
+            # 有幂等键组成部分时按幂等键写入，批次重试写入不会产生重复数据
+            content, idempotency = split_idempotency_fields(data_item)
+            
             # 保存数据
             save_generated_data(
                 task_id=task_id,
                 user_id=user_id,
-                data_content=data_item,
+                data_content=content,
                 model_score=model_score,
                 rule_score=rule_score,
                 retry_count=retry_count,
                 generation_model=gen_model,
                 task_type=task_type,
-                db=db
+                db=db,
+                idempotency=idempotency
             )
             saved_count += 1
         
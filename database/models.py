@@ -6,6 +6,11 @@ from sqlalchemy.ext.declarative import declarative_base
 from sqlalchemy.orm import sessionmaker, relationship
 from datetime import datetime
 import os
+import shlex
+import sys
+
+sys.path.insert(0, os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+from config import get_config, get_database_config
 
 Base = declarative_base()
 
@@ -56,7 +61,7 @@ class Task(Base):
     id = Column(Integer, primary_key=True, index=True)
     task_id = Column(String(100), unique=True, index=True, nullable=False)  # 任务ID
     user_id = Column(Integer, ForeignKey('users.id'), nullable=False)  # 所属用户
-    status = Column(String(20), default='running')  # 状态: running, finished, error, stopped
+    status = Column(String(20), default='running')  # 状态: running, finished, error, stopped, partial, timeout, dead_letter（与后端 models.TaskStatus 一致）
     params = Column(Text)  # 任务参数(JSON格式)
     result = Column(Text)  # 任务结果
     error_message = Column(Text)  # 错误信息
@@ -74,6 +79,7 @@ class DataFile(Base):
     id = Column(Integer, primary_key=True, index=True)
     filename = Column(String(255), nullable=False)  # 原始文件名
     file_content = Column(LargeBinary, nullable=False)  # 文件内容（二进制存储）
+    storage_path = Column(String(255))  # 内容保存在文件存储（upload.storage_dir）中时的相对路径，此时 file_content 为空
     file_size = Column(Integer, nullable=False)  # 文件大小（字节）
     content_type = Column(String(100), default='application/x-jsonlines')  # 文件类型
     user_id = Column(Integer, ForeignKey('users.id'), nullable=False)  # 所属用户
@@ -98,6 +104,10 @@ class GeneratedData(Base):
     generation_model = Column(String(255))  # 生成模型名称
     task_type = Column(String(50))  # 任务类型
     is_confirmed = Column(Boolean, default=False)  # 是否已确认可用
+    idempotency_key = Column(String(64), unique=True, index=True)  # 幂等键（重试写入时去重）
+    seed_hash = Column(String(64), index=True)  # 种子样本指纹（幂等键组成部分，不写入 data_content）
+    seed_index = Column(Integer)  # 种子样本在输入中的序号
+    variant_index = Column(Integer)  # 变体序号
     created_at = Column(DateTime, default=datetime.utcnow)  # 生成时间
     updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)  # 更新时间
     
@@ -106,15 +116,64 @@ class GeneratedData(Base):
     task = relationship("Task", backref="generated_data")
 
 
-# 数据库路径
-DB_PATH = os.path.join(os.path.dirname(__file__), 'app.db')
-SQLALCHEMY_DATABASE_URL = f"sqlite:///{DB_PATH}"
+def _postgres_connect_args(dsn: str) -> dict:
+    """
+    将后端使用的 PostgreSQL 连接串转换为 psycopg2 连接参数
+    支持 URL（postgres://...）和 key=value 两种格式；TimeZone 是 Go 驱动的会话参数，libpq 不识别，转换为 options
+    """
+    if dsn.startswith(('postgres://', 'postgresql://')):
+        from urllib.parse import urlsplit, parse_qsl, urlencode, urlunsplit
+        parts = urlsplit(dsn)
+        query = dict(parse_qsl(parts.query))
+        timezone = query.pop('TimeZone', None) or query.pop('timezone', None)
+        args = {'dsn': urlunsplit(parts._replace(query=urlencode(query)))}
+    else:
+        args = {}
+        for token in shlex.split(dsn):
+            key, sep, value = token.partition('=')
+            if sep:
+                args[key] = value
+        timezone = args.pop('TimeZone', None) or args.pop('timezone', None)
+    if timezone:
+        args['options'] = f"-c timezone={timezone}"
+    return args
+
 
-# 创建数据库引擎
-engine = create_engine(
-    SQLALCHEMY_DATABASE_URL,
-    connect_args={"check_same_thread": False}  # SQLite需要这个参数
-)
+# 数据库配置（与 Go 后端共用 config.yaml 的 database 配置）
+DB_CONFIG = get_database_config()
+DB_DRIVER = DB_CONFIG['driver']
+
+if DB_DRIVER == 'postgres':
+    if not DB_CONFIG['dsn']:
+        raise RuntimeError("使用 postgres 时数据库连接串 database.dsn 不能为空")
+    DB_PATH = None
+    # 创建数据库引擎（连接参数全部由 connect_args 传入 psycopg2）
+    engine = create_engine(
+        "postgresql+psycopg2://",
+        connect_args=_postgres_connect_args(DB_CONFIG['dsn']),
+        pool_pre_ping=True
+    )
+elif DB_DRIVER == 'sqlite':
+    # 数据库路径（相对路径相对于项目根目录）
+    DB_PATH = DB_CONFIG['path']
+    if not os.path.isabs(DB_PATH):
+        DB_PATH = os.path.join(os.path.dirname(os.path.dirname(os.path.abspath(__file__))), DB_PATH)
+    DB_PATH = os.path.normpath(DB_PATH)
+    SQLALCHEMY_DATABASE_URL = f"sqlite:///{DB_PATH}"
+
+    # 创建数据库引擎
+    engine = create_engine(
+        SQLALCHEMY_DATABASE_URL,
+        connect_args={"check_same_thread": False}  # SQLite需要这个参数
+    )
+else:
+    raise RuntimeError(f"不支持的数据库类型: {DB_DRIVER}")
+
+# 文件存储目录（与 Go 后端的 upload.storage_dir 一致，相对路径相对于项目根目录）
+FILE_STORAGE_DIR = get_config('upload.storage_dir', './data/files')
+if not os.path.isabs(FILE_STORAGE_DIR):
+    FILE_STORAGE_DIR = os.path.join(os.path.dirname(os.path.dirname(os.path.abspath(__file__))), FILE_STORAGE_DIR)
+FILE_STORAGE_DIR = os.path.normpath(FILE_STORAGE_DIR)
 
 # 创建会话工厂
 SessionLocal = sessionmaker(autocommit=False, autoflush=False, bind=engine)
@@ -124,9 +183,13 @@ def init_db():
     """初始化数据库，创建表"""
     # 使用 create_all 创建所有表
     # SQLAlchemy 会自动处理表已存在的情况
+    if DB_DRIVER != 'sqlite':
+        Base.metadata.create_all(bind=engine)
+        print(f"📊 SQLAlchemy create_all 已执行（{DB_DRIVER}）")
+        return
     
     # 检查数据库文件在创建前的状态
-    db_path = os.path.join(os.path.dirname(__file__), 'app.db')
+    db_path = DB_PATH
     print(f"📂 init_db - 数据库路径: {db_path}")
     print(f"📂 init_db - 数据库文件存在: {os.path.exists(db_path)}")
     
@@ -149,11 +212,16 @@ def verify_and_create_columns():
     用于数据库升级和字段迁移
     
     如果数据库文件丢失或损坏，会自动重新创建所有表结构
+    只适用于 SQLite；PostgreSQL 的表结构由后端启动时的迁移维护
     """
     from sqlalchemy import inspect, text
     
+    if DB_DRIVER != 'sqlite':
+        print(f"ℹ️  数据库类型为 {DB_DRIVER}，表结构由后端迁移维护，跳过字段核查")
+        return
+    
     # 首先确保数据库文件存在（SQLite 会自动创建，但我们需要确保它能正常工作）
-    db_path = os.path.join(os.path.dirname(__file__), 'app.db')
+    db_path = DB_PATH
     if not os.path.exists(db_path):
         print(f"⚠️  数据库文件不存在: {db_path}")
         print(f"🔄 创建新的数据库文件...")
@@ -229,6 +297,7 @@ def verify_and_create_columns():
             {'name': 'id', 'type': 'INTEGER', 'nullable': False},
             {'name': 'filename', 'type': 'VARCHAR(255)', 'nullable': False},
             {'name': 'file_content', 'type': 'BLOB', 'nullable': False},
+            {'name': 'storage_path', 'type': 'VARCHAR(255)'},
             {'name': 'file_size', 'type': 'INTEGER', 'nullable': False},
             {'name': 'content_type', 'type': 'VARCHAR(100)', 'default': 'application/x-jsonlines'},
             {'name': 'user_id', 'type': 'INTEGER', 'nullable': False},
@@ -246,6 +315,9 @@ def verify_and_create_columns():
             {'name': 'generation_model', 'type': 'VARCHAR(255)'},
             {'name': 'task_type', 'type': 'VARCHAR(50)'},
             {'name': 'is_confirmed', 'type': 'BOOLEAN', 'default': False},
+            {'name': 'seed_hash', 'type': 'VARCHAR(64)'},
+            {'name': 'seed_index', 'type': 'INTEGER'},
+            {'name': 'variant_index', 'type': 'INTEGER'},
             {'name': 'created_at', 'type': 'DATETIME'},
             {'name': 'updated_at', 'type': 'DATETIME'},
         ],
@@ -14,6 +14,7 @@ import os
 sys.path.insert(0, os.path.dirname(os.path.dirname(__file__)))
 from database import SessionLocal
 from database.file_service import get_file_content
+from develop import worker_rpc
 
 
 class FileReader:
@@ -69,7 +70,7 @@ class FileReader:
     @staticmethod
     def read_samples(file_id: int, user_id: int) -> Tuple[List[Dict[str, Any]], List[str]]:
         """
-        从数据库读取样本数据
+        读取样本数据：已连接 gRPC 时通过 FetchBatch 从后端读取，否则直接读取数据库
         
         Args:
             file_id: 数据文件ID
@@ -78,6 +79,11 @@ class FileReader:
         Returns:
             Tuple[samples, errors]: 成功读取的样本列表和错误信息列表
         """
+        client = worker_rpc.get_client()
+        if client:
+            samples = client.fetch_samples()
+            if samples is not None:
+                return samples, []
         return FileReader.read_from_database(file_id, user_id)
     
     @staticmethod
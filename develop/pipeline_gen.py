@@ -21,12 +21,15 @@ from config import get_default_model
 # 导入新的模块
 from develop.single_gen import main_process_from_samples
 from develop.file_reader import FileReader
+from develop import worker_rpc
+from develop.progress_events import emit_event
 
 
 class PipelineDataGenerator:
     def __init__(self, services: List[str], model: str = None,
                  api_key: str = "", is_vllm: bool = True, use_proxy: bool = True,
-                 top_p: float = 1.0, max_tokens: int = 8192, timeout: int = 600):
+                 top_p: float = 1.0, max_tokens: int = 8192, timeout: int = 600,
+                 endpoints: Optional[List[Dict[str, Any]]] = None):
         """
         初始化分布式数据生成器
         
@@ -39,8 +42,10 @@ class PipelineDataGenerator:
             top_p: top_p参数
             max_tokens: 最大token数
             timeout: 超时时间
+            endpoints: 多模型集成配置（与 services 一一对应），每项可覆盖模型、密钥等参数，并按 max_concurrent 比例分配样本
         """
         self.services = services
+        self.endpoints = endpoints
         self.model = model or get_default_model()
         self.service_count = len(services)
         
@@ -78,7 +83,7 @@ class PipelineDataGenerator:
     
     def update_task_progress(self, task_id: str, progress_data: dict):
         """
-        更新任务进度到 Redis（使用Hash格式，避免覆盖字符数）
+        更新任务进度到 Redis（使用Hash格式，避免覆盖字符数），已连接 gRPC 时同时通过 ReportProgress 上报
 
         Args:
             task_id: 任务ID
@@ -99,6 +104,10 @@ class PipelineDataGenerator:
                 redis_client.expire(redis_key, 86400)
             except Exception as e:
                 print(f"⚠️  Redis 更新进度失败: {e}")
+
+        client = worker_rpc.get_client()
+        if client:
+            client.report_progress("progress", progress=progress_data)
         
     def split_samples_in_memory(self, samples: List[Dict[str, Any]]) -> List[List[Dict[str, Any]]]:
         """
@@ -112,14 +121,34 @@ class PipelineDataGenerator:
         """
         print(f"📊 内存中分配样本: 总数 {len(samples)}, 服务数 {self.service_count}")
         
-        # 使用 FileReader 的静态方法进行分割
-        parts = FileReader.split_samples_in_memory(samples, self.service_count)
+        if self.endpoints:
+            # 多模型集成：按各模型的最大并发数比例分配
+            parts = self.split_samples_weighted(samples, [max(1, int(e.get('max_concurrent') or 1)) for e in self.endpoints])
+        else:
+            # 使用 FileReader 的静态方法进行分割
+            parts = FileReader.split_samples_in_memory(samples, self.service_count)
         
         for i, part in enumerate(parts):
             print(f"  服务 {i+1}: 分配 {len(part)} 个样本")
         
         return parts
     
+    @staticmethod
+    def split_samples_weighted(samples: List[Dict[str, Any]], weights: List[int]) -> List[List[Dict[str, Any]]]:
+        """
+        按权重比例分割样本（连续切分，保证每个样本只分配给一个服务）
+        """
+        total_weight = sum(weights)
+        parts = []
+        start = 0
+        accumulated = 0
+        for weight in weights:
+            accumulated += weight
+            end = round(len(samples) * accumulated / total_weight)
+            parts.append(samples[start:end])
+            start = end
+        return parts
+
     async def process_single_service(self, service_idx: int, api_base: str, 
                                    samples: List[Dict[str, Any]],
                                    task_id: str, user_id: int,
@@ -137,7 +166,8 @@ class PipelineDataGenerator:
                                    use_proxy: bool = False,
                                    top_p: float = 1.0,
                                    max_tokens: int = 8192,
-                                   timeout: int = 600) -> Dict[str, Any]:
+                                   timeout: int = 600,
+                                   round_index: int = 0) -> Dict[str, Any]:
         """
         处理单个服务的任务，数据直接保存到SQL数据库
         
@@ -174,7 +204,8 @@ class PipelineDataGenerator:
                 use_proxy=use_proxy,
                 top_p=top_p,
                 max_tokens=max_tokens,
-                timeout=timeout
+                timeout=timeout,
+                round_index=round_index
             )
             
             end_time = time.time()
@@ -247,6 +278,7 @@ class PipelineDataGenerator:
         samples, read_errors = FileReader.read_samples(file_id=file_id, user_id=user_id)
         
         if not samples:
+            emit_event("error", message=f"没有可用的样本: {read_errors[0] if read_errors else '数据文件为空'}")
             return {
                 'status': 'Failed',
                 'error': 'No valid samples',
@@ -270,6 +302,7 @@ class PipelineDataGenerator:
         
         # 3. 多轮数据处理
         for round_num in range(data_rounds):
+            emit_event("round_started", round=round_num + 1, total_rounds=data_rounds, total=len(samples))
             
             # 更新 Redis 进度：当前轮次开始
             self.update_task_progress(task_id, {
@@ -293,6 +326,9 @@ class PipelineDataGenerator:
             for i, (service, sample_part) in enumerate(zip(self.services, sample_parts)):
                 if not sample_part:
                     continue
+
+                # 多模型集成时使用该服务对应模型的参数，并发数不超过该模型的最大并发
+                endpoint = self.endpoints[i] if self.endpoints else {}
                 
                 task = self.process_single_service(
                     service_idx=i,
@@ -301,21 +337,22 @@ class PipelineDataGenerator:
                     task_id=task_id,
                     user_id=user_id,
                     batch_size=batch_size,
-                    max_concurrent=max_concurrent,
+                    max_concurrent=min(max_concurrent, endpoint['max_concurrent']) if endpoint.get('max_concurrent') else max_concurrent,
                     min_score=min_score,
                     task_type=task_type,
                     variants_per_sample=variants_per_sample,
                     sample_retry_times=sample_retry_times,
-                    model=model,
+                    model=endpoint.get('model') or model,
                     retry_times=retry_times,
                     special_prompt=special_prompt,
                     directions=directions,
-                    api_key=api_key if api_key else self.api_key,
-                    is_vllm=is_vllm if is_vllm is not None else self.is_vllm,
+                    api_key=endpoint.get('api_key', api_key if api_key else self.api_key),
+                    is_vllm=endpoint.get('is_vllm', is_vllm if is_vllm is not None else self.is_vllm),
                     use_proxy=use_proxy if use_proxy is not None else self.use_proxy,
-                    top_p=top_p if top_p else self.top_p,
-                    max_tokens=max_tokens if max_tokens else self.max_tokens,
-                    timeout=timeout if timeout else self.timeout
+                    top_p=endpoint.get('top_p') or (top_p if top_p else self.top_p),
+                    max_tokens=endpoint.get('max_tokens') or (max_tokens if max_tokens else self.max_tokens),
+                    timeout=endpoint.get('timeout') or (timeout if timeout else self.timeout),
+                    round_index=round_num
                 )
                 tasks.append(task)
             
@@ -0,0 +1,32 @@
+#!/usr/bin/env python3
+"""
+结构化进度事件
+格式: {"type": "event", "schema_version": 1, "event": "<事件类型>", ...}
+后端按事件累计轮次和样本进度，通过 SSE 推送带 progress/total/percent 字段的进度事件。
+
+事件类型（schema_version 1）:
+    round_started     round, total_rounds, total（本轮样本数）
+    batch_completed   round, batch, total_batches, samples（本批样本数）
+    sample_generated  count（新增的合格数据条数）
+    score_assigned    model_score, rule_score, passed
+    error             message
+"""
+
+import json
+
+from develop import worker_rpc
+
+# 事件 schema 版本（字段有不兼容变更时递增，需与后端 ProgressSchemaVersion 保持一致）
+EVENT_SCHEMA_VERSION = 1
+
+
+def emit_event(event: str, **fields):
+    """输出一条结构化进度事件（已连接 gRPC 时通过 ReportProgress 上报）"""
+    payload = {"type": "event", "schema_version": EVENT_SCHEMA_VERSION, "event": event}
+    payload.update(fields)
+    line = json.dumps(payload, ensure_ascii=False)
+
+    client = worker_rpc.get_client()
+    if client and client.report_progress("output", line=line):
+        return
+    print(line, flush=True)
@@ -4,6 +4,7 @@
 读取样本数据，使用本地大模型生成新数据，进行评估并保存合格的数据
 """
 
+import hashlib
 import json
 import traceback
 import os
@@ -39,12 +40,18 @@ from config.tools import (
 from config import get_default_services, get_default_model, get_model_services_config
 # 导入模型调用函数
 from call_model.model_call import call_model_api
+from develop import worker_rpc
+from develop.progress_events import emit_event
 
 # 从配置获取默认值
 _default_services = get_default_services()
 _default_api_base = _default_services[0] if _default_services else ""
 _default_model = get_default_model()
 
+# 生成数据中存放幂等键组成部分（seed_hash、seed_index、variant_index）的字段
+# 保存时取出写入独立的列，不进入 data_content；与 database/generated_data_service.py 和后端 task_checkpoint.go 保持一致
+IDEMPOTENCY_FIELD = '_idempotency'
+
 
 class DataGenerator:
     def __init__(self,
@@ -64,7 +71,8 @@ class DataGenerator:
                  top_p: float = 1.0,
                  max_tokens: int = 8192,
                  timeout: int = 600,
-                 task_id: str = ""):
+                 task_id: str = "",
+                 round_index: int = 0):
         self.api_base = api_base or _default_api_base
         self.model = model or _default_model
         self.max_concurrent = max_concurrent
@@ -74,6 +82,7 @@ class DataGenerator:
         self.task_type = task_type
         self.variants_per_sample = variants_per_sample
         self.task_id = task_id  # 任务ID，用于字符数统计
+        self.round_index = round_index  # 数据轮次，参与种子指纹计算（不同轮次的同一样本视为不同种子）
 
         # 模型调用相关参数
         self.api_key = api_key
@@ -375,7 +384,12 @@ class DataGenerator:
             print(f"❌ 评估数据时出错: {str(e)}")
             return 0, "评分时出错"
     
-    async def process_single_sample(self, sample_data: Dict[str, Any], batch_idx: int = None, thread_idx: int = None, is_main_batch: bool = False, is_main_thread: bool = False) -> List[Dict[str, Any]]:
+    def compute_seed_hash(self, sample_data: Dict[str, Any]) -> str:
+        """计算种子样本指纹（样本内容 + 数据轮次），用于生成数据的幂等键"""
+        raw = json.dumps(sample_data, ensure_ascii=False, sort_keys=True) + f"#round={self.round_index}"
+        return hashlib.sha256(raw.encode('utf-8')).hexdigest()
+    
+    async def process_single_sample(self, sample_data: Dict[str, Any], batch_idx: int = None, thread_idx: int = None, is_main_batch: bool = False, is_main_thread: bool = False, sample_index: int = None) -> List[Dict[str, Any]]:
         """处理单个样本，生成并评估数据，如果没有合格数据则重试
         
         Args:
@@ -384,7 +398,10 @@ class DataGenerator:
             thread_idx: 线程/样本索引（从0开始）
             is_main_batch: 是否为主批次（第一个批次）
             is_main_thread: 是否为主线程（第一个样本）
+            sample_index: 样本在全部输入样本中的序号（参与幂等键，内容相同的样本不会互相覆盖）
         """
+        seed_hash = self.compute_seed_hash(sample_data)
+        
         for retry_count in range(self.sample_retry_times):
             try:
                 # 生成数据
@@ -408,9 +425,11 @@ class DataGenerator:
                         continue
                     
                     model_score, rule_score = await self.evaluate_generated_data(sample_data, generated_data)
+                    passed = model_score >= self.min_score and rule_score == 10
+                    emit_event("score_assigned", model_score=model_score, rule_score=rule_score, passed=passed)
                     
                     # 检查是否达到最低分数要求：规则评分必须满分，模型评分达到最低要求
-                    if model_score >= self.min_score and rule_score == 10:
+                    if passed:
                         # 构建完整的数据结构
                         complete_data = {
                             'meta': sample_data.get('meta', {}).copy(),
@@ -425,6 +444,13 @@ class DataGenerator:
                         complete_data['meta']['rule_score'] = rule_score
                         complete_data['meta']['source_task'] = self.task_type
                         complete_data['meta']['retry_count'] = retry_count  # 记录重试次数
+                        complete_data['meta']['round'] = self.round_index + 1  # 生成轮次（从 1 开始，用于统计每轮产出）
+                        # 幂等键的组成部分单独存放，保存时写入独立的列，不进入导出的数据内容
+                        complete_data[IDEMPOTENCY_FIELD] = {
+                            'seed_hash': seed_hash,  # 种子样本指纹
+                            'seed_index': sample_index,  # 样本序号
+                            'variant_index': idx  # 变体序号
+                        }
                         
                         qualified_data.append(complete_data)
                         with self._stats_lock:
@@ -457,13 +483,28 @@ class DataGenerator:
         
         return []
     
-    async def process_batch(self, samples: List[Dict[str, Any]], batch_idx: int = None, is_main_batch: bool = False) -> List[Dict[str, Any]]:
+    def emit_items(self, items: List[Dict[str, Any]]):
+        """
+        将已完成的数据逐条输出到标准输出（{"type": "item", "data": ...}），已连接 gRPC 时通过 SubmitResults 提交
+        后端缓存这些数据，进程在批次保存前被停止或异常退出时写入数据库，避免丢失已完成的结果
+        """
+        if not items:
+            return
+        emit_event("sample_generated", count=len(items))
+        client = worker_rpc.get_client()
+        if client and client.submit_results(items):
+            return
+        for item in items:
+            print(json.dumps({"type": "item", "data": item}, ensure_ascii=False), flush=True)
+
+    async def process_batch(self, samples: List[Dict[str, Any]], batch_idx: int = None, is_main_batch: bool = False, sample_offset: int = 0) -> List[Dict[str, Any]]:
         """批量处理样本
         
         Args:
             samples: 样本列表
             batch_idx: 批次索引（从0开始）
             is_main_batch: 是否为主批次（第一个批次）
+            sample_offset: 本批次第一个样本在全部输入样本中的序号
         """
         semaphore = asyncio.Semaphore(self.max_concurrent)
         
@@ -471,7 +512,11 @@ class DataGenerator:
             async with semaphore:
                 # 判断是否为主线程（第一个样本）
                 is_main_thread = (thread_idx == 0)
-                return await self.process_single_sample(sample, batch_idx, thread_idx, is_main_batch, is_main_thread)
+                result = await self.process_single_sample(sample, batch_idx, thread_idx, is_main_batch, is_main_thread,
+                                                          sample_index=sample_offset + thread_idx)
+                if isinstance(result, list):
+                    self.emit_items(result)
+                return result
         
         tasks = [process_with_semaphore(sample, idx) for idx, sample in enumerate(samples)]
         results = await asyncio.gather(*tasks, return_exceptions=True)
@@ -529,7 +574,9 @@ class DataGenerator:
                 print(f"📦 批次 {batch_idx + 1}/{(len(samples) + batch_size - 1)//batch_size}")
                 
                 # 处理当前批次
-                batch_results = await self.process_batch(batch, batch_idx, is_main_batch)
+                batch_results = await self.process_batch(batch, batch_idx, is_main_batch, sample_offset=i)
+                emit_event("batch_completed", round=self.round_index + 1, batch=batch_idx + 1,
+                           total_batches=(len(samples) + batch_size - 1) // batch_size, samples=len(batch))
                 
                 all_qualified_data.extend(batch_results)
                 
@@ -561,6 +608,7 @@ class DataGenerator:
         
         except Exception as e:
             print(f"处理样本时出错: {str(e)}")
+            emit_event("error", message=f"处理样本时出错: {e}")
             return {
                 'status': 'Failed',
                 'error': str(e),
@@ -582,7 +630,8 @@ async def main_process_from_samples(samples: List[Dict[str, Any]],
                                      api_key: str = "", 
                                      is_vllm: bool = True, use_proxy: bool = False,
                                      top_p: float = 1.0, max_tokens: int = 8192, 
-                                     timeout: int = 600) -> Dict[str, Any]:
+                                     timeout: int = 600,
+                                     round_index: int = 0) -> Dict[str, Any]:
     """
     主处理函数 - 生成数据并保存到SQL数据库
     
@@ -607,6 +656,7 @@ async def main_process_from_samples(samples: List[Dict[str, Any]],
         top_p: top_p参数
         max_tokens: 最大token数
         timeout: 超时时间
+        round_index: 数据轮次（用于计算生成数据的幂等键）
         
     Returns:
         包含统计信息和生成结果的字典
@@ -634,7 +684,8 @@ async def main_process_from_samples(samples: List[Dict[str, Any]],
             top_p=top_p,
             max_tokens=max_tokens,
             timeout=timeout,
-            task_id=task_id
+            task_id=task_id,
+            round_index=round_index
         )
         
         # 开始生成数据（直接从内存中的样本）
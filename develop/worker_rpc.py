@@ -0,0 +1,145 @@
+#!/usr/bin/env python3
+"""
+工作进程 gRPC 客户端
+协议定义见 proto/worker.proto：消息按 proto3 JSON 映射编码，使用 grpcio 的通用调用，不需要生成代码。
+后端通过 --grpc-addr 传入地址；未安装 grpcio、连接失败或调用出错时返回失败，调用方回退到标准输出协议。
+后端开启 TLS 时通过环境变量 WORKER_GRPC_TLS、WORKER_GRPC_TLS_CA、WORKER_GRPC_TLS_SERVER_NAME 传入连接方式（见 backend/internal/workerrpc/tls.go）。
+"""
+
+import json
+import os
+from typing import Any, Dict, List, Optional, Tuple
+
+# gRPC 服务全名
+SERVICE_NAME = "worker.v1.Worker"
+# 单次调用超时（秒）
+RPC_TIMEOUT = 30
+# FetchBatch 每页条数
+FETCH_BATCH_SIZE = 500
+
+# 已连接的客户端（main.py 启动时调用 connect 初始化）
+_client = None
+
+
+def _serialize(payload: Dict[str, Any]) -> bytes:
+    return json.dumps(payload, ensure_ascii=False).encode('utf-8')
+
+
+def _deserialize(data: bytes) -> Dict[str, Any]:
+    return json.loads(data) if data else {}
+
+
+def _create_channel(addr: str):
+    """按环境变量创建 TLS 或明文连接"""
+    import grpc
+
+    ca_file = os.getenv("WORKER_GRPC_TLS_CA", "")
+    if os.getenv("WORKER_GRPC_TLS") != "1" and not ca_file:
+        return grpc.insecure_channel(addr)
+
+    root_certificates = None
+    if ca_file:
+        with open(ca_file, 'rb') as f:
+            root_certificates = f.read()
+    credentials = grpc.ssl_channel_credentials(root_certificates=root_certificates)
+    options = []
+    server_name = os.getenv("WORKER_GRPC_TLS_SERVER_NAME", "")
+    if server_name:
+        options.append(("grpc.ssl_target_name_override", server_name))
+    return grpc.secure_channel(addr, credentials, options=options)
+
+
+class WorkerRPCClient:
+    """与后端 Worker 服务通信的客户端"""
+
+    def __init__(self, addr: str, task_id: str):
+        self.task_id = task_id
+        self._channel = _create_channel(addr)
+        self._metadata = [("x-internal-api-key", os.getenv("INTERNAL_API_KEY", "gen-internal-api-key-2024"))]
+        self._methods = {
+            name: self._channel.unary_unary(
+                f"/{SERVICE_NAME}/{name}",
+                request_serializer=_serialize,
+                response_deserializer=_deserialize,
+            )
+            for name in ("StartWork", "ReportProgress", "SubmitResults", "FetchBatch")
+        }
+
+    def _call(self, method: str, payload: Dict[str, Any]) -> Dict[str, Any]:
+        payload["task_id"] = self.task_id
+        return self._methods[method](payload, metadata=self._metadata, timeout=RPC_TIMEOUT)
+
+    def start_work(self, worker_version: str, protocol_version: int) -> Tuple[bool, str]:
+        """登记任务，返回 (是否接受, 原因)"""
+        response = self._call("StartWork", {
+            "worker_version": worker_version,
+            "protocol_version": protocol_version,
+        })
+        return bool(response.get("accepted")), response.get("message", "")
+
+    def report_progress(self, report_type: str, line: str = "", progress: Optional[Dict[str, Any]] = None) -> bool:
+        """上报心跳、进度或日志行，上报失败时返回 False"""
+        payload: Dict[str, Any] = {"type": report_type}
+        if line:
+            payload["line"] = line
+        if progress is not None:
+            payload["progress"] = progress
+        try:
+            self._call("ReportProgress", payload)
+            return True
+        except Exception as e:
+            print(f"⚠️  gRPC 上报{report_type}失败: {e}")
+            return False
+
+    def submit_results(self, items: List[Dict[str, Any]]) -> bool:
+        """提交已完成的生成数据，提交失败时返回 False"""
+        try:
+            self._call("SubmitResults", {"items": items})
+            return True
+        except Exception as e:
+            print(f"⚠️  gRPC 提交数据失败: {e}")
+            return False
+
+    def fetch_samples(self) -> Optional[List[Dict[str, Any]]]:
+        """分页读取任务数据文件中的全部样本，读取失败时返回 None"""
+        samples: List[Dict[str, Any]] = []
+        try:
+            while True:
+                response = self._call("FetchBatch", {"offset": len(samples), "limit": FETCH_BATCH_SIZE})
+                samples.extend(response.get("samples") or [])
+                if not response.get("has_more"):
+                    return samples
+        except Exception as e:
+            print(f"⚠️  gRPC 读取样本失败: {e}")
+            return None
+
+    def close(self):
+        self._channel.close()
+
+
+def connect(addr: str, task_id: str, worker_version: str, protocol_version: int) -> Optional[WorkerRPCClient]:
+    """连接后端并登记任务，失败时返回 None（使用标准输出协议）"""
+    global _client
+    try:
+        client = WorkerRPCClient(addr, task_id)
+        accepted, message = client.start_work(worker_version, protocol_version)
+    except ImportError:
+        print("⚠️  未安装 grpcio，使用标准输出协议")
+        return None
+    except Exception as e:
+        print(f"⚠️  连接后端 gRPC 服务失败: {e}，使用标准输出协议")
+        return None
+
+    if not accepted:
+        print(f"⚠️  后端拒绝 gRPC 连接: {message}，使用标准输出协议")
+        client.close()
+        return None
+
+    print(f"✅ 已通过 gRPC 连接后端: {addr}")
+    _client = client
+    return client
+
+
+def get_client() -> Optional[WorkerRPCClient]:
+    """获取已连接的客户端（未连接时为 None）"""
+    return _client
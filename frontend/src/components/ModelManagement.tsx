@@ -1,8 +1,17 @@
 import { useState, useEffect } from 'react';
 import { adminService } from '../services/api';
-import type { ModelConfig } from '../types';
+import type { ModelConfig, ModelProvider, ModelConnectionTestResult, ModelDiscoveryResult } from '../types';
 import ConfirmDialog from './ConfirmDialog';
 
+// 接口类型的显示名称
+const dialectLabels: Record<string, string> = {
+  vllm: 'vLLM',
+  openai: 'OpenAI',
+  anthropic: 'Anthropic',
+  gemini: 'Gemini',
+  unknown: '未知',
+};
+
 export default function ModelManagement() {
   const [models, setModels] = useState<ModelConfig[]>([]);
   const [loading, setLoading] = useState(false);
@@ -10,6 +19,17 @@ export default function ModelManagement() {
   const [success, setSuccess] = useState('');
   const [showModal, setShowModal] = useState(false);
   const [editingModel, setEditingModel] = useState<ModelConfig | null>(null);
+  const [testing, setTesting] = useState(false);
+  const [testResult, setTestResult] = useState<ModelConnectionTestResult | null>(null);
+  const [testError, setTestError] = useState('');
+
+  // 从模型服务发现模型
+  const [showDiscover, setShowDiscover] = useState(false);
+  const [discoverForm, setDiscoverForm] = useState({ api_url: '', api_key: 'sk-xxxxx', provider: 'openai' as ModelProvider, name_prefix: '' });
+  const [discovering, setDiscovering] = useState(false);
+  const [discovery, setDiscovery] = useState<ModelDiscoveryResult | null>(null);
+  const [selectedModelIds, setSelectedModelIds] = useState<string[]>([]);
+  const [discoverError, setDiscoverError] = useState('');
   
   // 确认弹窗状态
   const [deleteConfirm, setDeleteConfirm] = useState<{ isOpen: boolean; model: ModelConfig | null }>({
@@ -20,13 +40,17 @@ export default function ModelManagement() {
   const [formData, setFormData] = useState({
     name: '',
     api_url: '',
+    endpoints: [] as string[],
     api_key: 'sk-xxxxx',
     model_path: '',
     max_concurrent: 16,
+    rpm_limit: 0,
+    tpm_limit: 0,
     temperature: 1.0,
     top_p: 1.0,
     max_tokens: 2048,
     is_vllm: true,
+    provider: 'openai' as ModelProvider,
     timeout: 600,
     description: '',
     is_active: true,
@@ -53,17 +77,23 @@ export default function ModelManagement() {
     setFormData({
       name: '',
       api_url: '',
+      endpoints: [],
       api_key: 'sk-xxxxx',
       model_path: '',
       max_concurrent: 16,
+      rpm_limit: 0,
+      tpm_limit: 0,
       temperature: 1.0,
       top_p: 1.0,
       max_tokens: 2048,
       is_vllm: true,
+      provider: 'openai',
       timeout: 600,
       description: '',
       is_active: true,
     });
+    setTestResult(null);
+    setTestError('');
     setShowModal(true);
   };
 
@@ -72,20 +102,47 @@ export default function ModelManagement() {
     setFormData({
       name: model.name,
       api_url: model.api_url,
+      endpoints: model.endpoints || [],
       api_key: model.api_key,
       model_path: model.model_path,
       max_concurrent: model.max_concurrent,
+      rpm_limit: model.rpm_limit || 0,
+      tpm_limit: model.tpm_limit || 0,
       temperature: model.temperature,
       top_p: model.top_p,
       max_tokens: model.max_tokens,
       is_vllm: model.is_vllm,
+      provider: model.provider || 'openai',
       timeout: model.timeout,
       description: model.description || '',
       is_active: model.is_active,
     });
+    setTestResult(null);
+    setTestError('');
     setShowModal(true);
   };
 
+  const handleTest = async () => {
+    setTesting(true);
+    setTestResult(null);
+    setTestError('');
+    try {
+      const result = await adminService.testModel({
+        model_id: editingModel?.id,
+        api_url: formData.api_url,
+        api_key: formData.api_key,
+        model_path: formData.model_path,
+        is_vllm: formData.is_vllm,
+        provider: formData.provider,
+      });
+      setTestResult(result);
+    } catch (err: any) {
+      setTestError(err.response?.data?.error || '测试连接失败');
+    } finally {
+      setTesting(false);
+    }
+  };
+
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault();
     setError('');
@@ -109,6 +166,54 @@ export default function ModelManagement() {
     }
   };
 
+  const handleOpenDiscover = () => {
+    setDiscovery(null);
+    setSelectedModelIds([]);
+    setDiscoverError('');
+    setShowDiscover(true);
+  };
+
+  const handleDiscover = async () => {
+    setDiscovering(true);
+    setDiscoverError('');
+    setDiscovery(null);
+    try {
+      const result = await adminService.discoverModels(discoverForm.api_url, discoverForm.api_key, discoverForm.provider);
+      setDiscovery(result);
+      setSelectedModelIds(result.models.filter((m) => !m.config_id).map((m) => m.id));
+    } catch (err: any) {
+      setDiscoverError(err.response?.data?.error || '获取模型列表失败');
+    } finally {
+      setDiscovering(false);
+    }
+  };
+
+  const handleImport = async () => {
+    setDiscovering(true);
+    setDiscoverError('');
+    try {
+      const result = await adminService.importModels({
+        api_url: discoverForm.api_url,
+        api_key: discoverForm.api_key,
+        provider: discoverForm.provider,
+        model_ids: selectedModelIds,
+        name_prefix: discoverForm.name_prefix,
+      });
+      const skipped = result.skipped.map((s) => `${s.model_path}（${s.reason}）`).join('；');
+      setSuccess(`已创建 ${result.created.length} 个模型` + (skipped ? `，跳过：${skipped}` : ''));
+      setShowDiscover(false);
+      loadModels();
+    } catch (err: any) {
+      setDiscoverError(err.response?.data?.error || '批量创建失败');
+    } finally {
+      setDiscovering(false);
+    }
+  };
+
+  const toggleModelId = (id: string) => {
+    setSelectedModelIds((ids) => (ids.includes(id) ? ids.filter((x) => x !== id) : [...ids, id]));
+  };
+
   const handleDelete = async (model: ModelConfig) => {
     setDeleteConfirm({ isOpen: true, model });
   };
@@ -151,12 +256,20 @@ export default function ModelManagement() {
           <h2 className="text-2xl font-semibold text-gray-900">模型管理</h2>
           <p className="text-sm text-gray-500 mt-1">配置和管理可用的AI模型</p>
         </div>
-        <button
-          onClick={handleCreate}
-          className="px-4 py-2 bg-blue-500 hover:bg-blue-600 text-white rounded-lg font-medium transition-colors"
-        >
-          + 新增模型
-        </button>
+        <div className="flex gap-2">
+          <button
+            onClick={handleOpenDiscover}
+            className="px-4 py-2 bg-white border border-blue-500 text-blue-600 hover:bg-blue-50 rounded-lg font-medium transition-colors"
+          >
+            从服务发现
+          </button>
+          <button
+            onClick={handleCreate}
+            className="px-4 py-2 bg-blue-500 hover:bg-blue-600 text-white rounded-lg font-medium transition-colors"
+          >
+            + 新增模型
+          </button>
+        </div>
       </div>
 
       {/* Models List */}
@@ -207,7 +320,7 @@ export default function ModelManagement() {
                         : 'bg-purple-100 text-purple-700'
                     }`}
                   >
-                    {model.is_vllm ? 'vLLM' : 'OpenAI'}
+                    {model.provider === 'anthropic' ? 'Anthropic' : model.provider === 'gemini' ? 'Gemini' : model.is_vllm ? 'vLLM' : 'OpenAI'}
                   </span>
                 </td>
                 <td className="px-6 py-4 whitespace-nowrap">
@@ -283,6 +396,19 @@ export default function ModelManagement() {
                 />
               </div>
 
+              <div>
+                <label className="block text-sm font-medium text-gray-700 mb-2">
+                  其他服务地址（每行一个，与 API 地址组成端点池，调用时路由到负载最低的健康端点）
+                </label>
+                <textarea
+                  value={formData.endpoints.join('\n')}
+                  onChange={(e) => setFormData({ ...formData, endpoints: e.target.value.split('\n') })}
+                  className="w-full px-4 py-2 border border-gray-300 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500"
+                  rows={3}
+                  placeholder="http://gpu-2:16466/v1"
+                />
+              </div>
+
               <div>
                 <label className="block text-sm font-medium text-gray-700 mb-2">
                   API密钥
@@ -324,6 +450,32 @@ export default function ModelManagement() {
                   />
                 </div>
 
+                <div>
+                  <label className="block text-sm font-medium text-gray-700 mb-2">
+                    每分钟请求数上限（RPM，0 不限制）
+                  </label>
+                  <input
+                    type="number"
+                    value={formData.rpm_limit}
+                    onChange={(e) => setFormData({ ...formData, rpm_limit: parseInt(e.target.value) || 0 })}
+                    className="w-full px-4 py-2 border border-gray-300 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500"
+                    min="0"
+                  />
+                </div>
+
+                <div>
+                  <label className="block text-sm font-medium text-gray-700 mb-2">
+                    每分钟Token数上限（TPM，0 不限制）
+                  </label>
+                  <input
+                    type="number"
+                    value={formData.tpm_limit}
+                    onChange={(e) => setFormData({ ...formData, tpm_limit: parseInt(e.target.value) || 0 })}
+                    className="w-full px-4 py-2 border border-gray-300 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500"
+                    min="0"
+                  />
+                </div>
+
                 <div>
                   <label className="block text-sm font-medium text-gray-700 mb-2">
                     温度
@@ -393,6 +545,21 @@ export default function ModelManagement() {
                 />
               </div>
 
+              <div>
+                <label className="block text-sm font-medium text-gray-700 mb-2">
+                  接口协议
+                </label>
+                <select
+                  value={formData.provider}
+                  onChange={(e) => setFormData({ ...formData, provider: e.target.value as ModelProvider })}
+                  className="w-full px-4 py-2 border border-gray-300 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500"
+                >
+                  <option value="openai">OpenAI Chat Completions（vLLM 等兼容服务）</option>
+                  <option value="anthropic">Anthropic Messages</option>
+                  <option value="gemini">Google Gemini</option>
+                </select>
+              </div>
+
               <div className="flex items-center">
                 <input
                   type="checkbox"
@@ -417,7 +584,50 @@ export default function ModelManagement() {
                 </label>
               </div>
 
+              {(testResult || testError) && (
+                <div
+                  className={`p-4 rounded-lg text-sm space-y-1 ${
+                    testResult?.success ? 'bg-green-50 text-green-800' : 'bg-red-50 text-red-800'
+                  }`}
+                >
+                  {testError && <div>{testError}</div>}
+                  {testResult && (
+                    <>
+                      <div className="font-medium">
+                        {testResult.success ? '连接成功' : '连接失败'}
+                        {' · '}
+                        接口类型：{dialectLabels[testResult.dialect]}
+                        {testResult.server_version && `（${testResult.server_version}）`}
+                      </div>
+                      <div>
+                        模型列表耗时 {testResult.models_latency_ms} ms，补全耗时 {testResult.completion_latency_ms} ms
+                        {testResult.model_path && `，测试模型 ${testResult.model_path}`}
+                      </div>
+                      {testResult.models.length > 0 && (
+                        <div className="break-all">
+                          服务提供的模型：{testResult.models.map((m) => m.id).join(', ')}
+                        </div>
+                      )}
+                      {testResult.models_error && <div className="break-all">模型列表：{testResult.models_error}</div>}
+                      {testResult.reply && <div className="break-all">回复：{testResult.reply}</div>}
+                      {testResult.error && <div className="break-all">{testResult.error}</div>}
+                      {testResult.warnings?.map((w, i) => (
+                        <div key={i} className="text-yellow-700">⚠ {w}</div>
+                      ))}
+                    </>
+                  )}
+                </div>
+              )}
+
               <div className="flex gap-4 pt-4">
+                <button
+                  type="button"
+                  onClick={handleTest}
+                  disabled={testing || !formData.api_url}
+                  className="flex-1 px-4 py-2 bg-white border border-blue-500 text-blue-600 hover:bg-blue-50 rounded-lg font-medium transition-colors disabled:opacity-50"
+                >
+                  {testing ? '测试中...' : '测试连接'}
+                </button>
                 <button
                   type="submit"
                   disabled={loading}
@@ -438,6 +648,114 @@ export default function ModelManagement() {
         </div>
       )}
 
+      {/* 从模型服务发现模型 */}
+      {showDiscover && (
+        <div className="fixed inset-0 bg-black bg-opacity-50 flex items-center justify-center z-50 p-4">
+          <div className="bg-white rounded-2xl max-w-2xl w-full max-h-[90vh] overflow-y-auto p-6">
+            <h3 className="text-xl font-semibold text-gray-900 mb-6">从模型服务发现模型</h3>
+            <div className="space-y-4">
+              <div>
+                <label className="block text-sm font-medium text-gray-700 mb-2">API地址 *</label>
+                <input
+                  type="text"
+                  value={discoverForm.api_url}
+                  onChange={(e) => setDiscoverForm({ ...discoverForm, api_url: e.target.value })}
+                  className="w-full px-4 py-2 border border-gray-300 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500"
+                  placeholder={import.meta.env.VITE_DEFAULT_MODEL_SERVICE || "http://localhost:16466/v1"}
+                />
+              </div>
+              <div>
+                <label className="block text-sm font-medium text-gray-700 mb-2">接口协议</label>
+                <select
+                  value={discoverForm.provider}
+                  onChange={(e) => setDiscoverForm({ ...discoverForm, provider: e.target.value as ModelProvider })}
+                  className="w-full px-4 py-2 border border-gray-300 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500"
+                >
+                  <option value="openai">OpenAI Chat Completions（vLLM 等兼容服务）</option>
+                  <option value="anthropic">Anthropic Messages</option>
+                  <option value="gemini">Google Gemini</option>
+                </select>
+              </div>
+              <div className="grid grid-cols-2 gap-4">
+                <div>
+                  <label className="block text-sm font-medium text-gray-700 mb-2">API密钥</label>
+                  <input
+                    type="text"
+                    value={discoverForm.api_key}
+                    onChange={(e) => setDiscoverForm({ ...discoverForm, api_key: e.target.value })}
+                    className="w-full px-4 py-2 border border-gray-300 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500"
+                  />
+                </div>
+                <div>
+                  <label className="block text-sm font-medium text-gray-700 mb-2">名称前缀</label>
+                  <input
+                    type="text"
+                    value={discoverForm.name_prefix}
+                    onChange={(e) => setDiscoverForm({ ...discoverForm, name_prefix: e.target.value })}
+                    className="w-full px-4 py-2 border border-gray-300 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500"
+                    placeholder="模型名称为前缀加模型 ID"
+                  />
+                </div>
+              </div>
+
+              {discoverError && (
+                <div className="p-4 bg-red-50 rounded-lg text-sm text-red-800 break-all">{discoverError}</div>
+              )}
+
+              {discovery && (
+                <div className="border border-gray-200 rounded-lg divide-y divide-gray-100">
+                  <div className="px-4 py-2 text-sm text-gray-500">
+                    接口类型：{dialectLabels[discovery.dialect]}
+                    {discovery.server_version && `（${discovery.server_version}）`}，耗时 {discovery.latency_ms} ms，
+                    共 {discovery.models.length} 个模型
+                  </div>
+                  {discovery.models.map((m) => (
+                    <label key={m.id} className="flex items-center px-4 py-2 text-sm">
+                      <input
+                        type="checkbox"
+                        checked={selectedModelIds.includes(m.id)}
+                        disabled={!!m.config_id}
+                        onChange={() => toggleModelId(m.id)}
+                        className="h-4 w-4 text-blue-600 focus:ring-blue-500 border-gray-300 rounded"
+                      />
+                      <span className="ml-2 text-gray-900 break-all">{m.id}</span>
+                      {m.max_model_len ? <span className="ml-2 text-gray-400">{m.max_model_len} tokens</span> : null}
+                      {m.config_id && <span className="ml-auto text-gray-400">已配置：{m.config_name}</span>}
+                    </label>
+                  ))}
+                </div>
+              )}
+
+              <div className="flex gap-4 pt-4">
+                <button
+                  type="button"
+                  onClick={handleDiscover}
+                  disabled={discovering || !discoverForm.api_url}
+                  className="flex-1 px-4 py-2 bg-white border border-blue-500 text-blue-600 hover:bg-blue-50 rounded-lg font-medium transition-colors disabled:opacity-50"
+                >
+                  {discovering ? '请求中...' : '获取模型列表'}
+                </button>
+                <button
+                  type="button"
+                  onClick={handleImport}
+                  disabled={discovering || selectedModelIds.length === 0}
+                  className="flex-1 px-4 py-2 bg-blue-500 hover:bg-blue-600 text-white rounded-lg font-medium transition-colors disabled:opacity-50"
+                >
+                  创建选中的 {selectedModelIds.length} 个模型
+                </button>
+                <button
+                  type="button"
+                  onClick={() => setShowDiscover(false)}
+                  className="flex-1 px-4 py-2 bg-gray-200 hover:bg-gray-300 text-gray-700 rounded-lg font-medium transition-colors"
+                >
+                  取消
+                </button>
+              </div>
+            </div>
+          </div>
+        </div>
+      )}
+
       {/* 删除模型确认弹窗 */}
       <ConfirmDialog
         isOpen={deleteConfirm.isOpen}
@@ -1,6 +1,6 @@
 import { useState, useEffect } from 'react';
 import { useNavigate } from 'react-router-dom';
-import { reportService } from '../services/api';
+import { reportService, taskService } from '../services/api';
 import type { Report } from '../types';
 import ConfirmDialog from './ConfirmDialog';
 
@@ -9,6 +9,7 @@ export default function ReportManagement() {
   const [reports, setReports] = useState<Report[]>([]);
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState('');
+  const [notice, setNotice] = useState('');
   const [selectedReportIds, setSelectedReportIds] = useState<string[]>([]);
   const [deleting, setDeleting] = useState(false);
   
@@ -56,6 +57,18 @@ export default function ReportManagement() {
     }
   };
 
+  // 以原任务参数重新运行
+  const handleRerun = async (taskId: string) => {
+    try {
+      setError('');
+      setNotice('');
+      const result = await taskService.rerunTask(taskId);
+      setNotice(`已重新运行，新任务ID: ${result.task_id}`);
+    } catch (err: any) {
+      setError(err.response?.data?.message || err.response?.data?.detail || '重新运行失败');
+    }
+  };
+
   // 跳转到编辑页面
   const handleOpenEditor = (taskId: string) => {
     navigate(`/editor/${encodeURIComponent(taskId)}`);
@@ -135,6 +148,8 @@ export default function ReportManagement() {
       finished: { text: '已完成', className: 'bg-green-100 text-green-700' },
       error: { text: '失败', className: 'bg-red-100 text-red-700' },
       stopped: { text: '已停止', className: 'bg-gray-100 text-gray-700' },
+      partial: { text: '部分完成', className: 'bg-yellow-100 text-yellow-700' },
+      timeout: { text: '已超时', className: 'bg-orange-100 text-orange-700' },
     };
     const { text, className } = statusMap[status] || { text: status, className: 'bg-gray-100 text-gray-700' };
     return <span className={`px-3 py-1 rounded-full text-xs font-medium ${className}`}>{text}</span>;
@@ -149,6 +164,12 @@ export default function ReportManagement() {
         </div>
       )}
 
+      {notice && (
+        <div className="p-4 bg-green-50 border border-green-200 rounded-xl text-green-700 text-sm">
+          {notice}
+        </div>
+      )}
+
       {/* 报告列表卡片 */}
       <div className="bg-white rounded-2xl shadow-sm p-8">
         <div className="flex items-center justify-between mb-6">
@@ -312,6 +333,12 @@ export default function ReportManagement() {
                             </div>
                           </>
                         )}
+                        <button
+                          onClick={() => handleRerun(report.task_id)}
+                          className="px-3 py-1 bg-blue-100 hover:bg-blue-200 text-blue-700 rounded-lg font-medium transition-colors"
+                        >
+                          🔁 重新运行
+                        </button>
                         <button
                           onClick={() => handleDeleteSingle(report.task_id)}
                           disabled={deleting}
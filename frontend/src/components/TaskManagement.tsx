@@ -15,8 +15,14 @@ interface TaskProgressData {
   source: string;
   input_chars?: number;
   output_chars?: number;
+  // SSE 结构化进度事件提供的本轮样本进度
+  round_progress?: number;
+  round_total?: number;
 }
 
+// 带进度字段的结构化进度事件类型
+const LIVE_PROGRESS_EVENTS = ['round_started', 'batch_completed', 'sample_generated', 'score_assigned'];
+
 export default function TaskManagement() {
   const [taskTypes, setTaskTypes] = useState<string[]>([]);
   const [dataFiles, setDataFiles] = useState<DataFile[]>([]);
@@ -30,6 +36,8 @@ export default function TaskManagement() {
   const [taskProgress, setTaskProgress] = useState<TaskProgressData | null>(null);
   const progressIntervalRef = useRef<number | null>(null);
   const sseAbortControllerRef = useRef<AbortController | null>(null);  // 用于跟踪 SSE 连接
+  const liveProgressRef = useRef(false);  // 是否已收到 SSE 结构化进度（收到后进度条以 SSE 为准）
+  const lastEventIdRef = useRef<{ taskId: string; id: string } | null>(null);  // 最后收到的 SSE 事件ID（断线重连时从该事件之后继续）
   const [showAdvancedSettings, setShowAdvancedSettings] = useState(false);
   const [showStopConfirm, setShowStopConfirm] = useState(false);
 
@@ -138,7 +146,21 @@ export default function TaskManagement() {
       console.log('[fetchTaskProgress] 进度数据:', result);
       if (result.success && result.progress) {
         console.log('[fetchTaskProgress] 设置进度:', result.progress);
-        setTaskProgress(result.progress);
+        // 已有 SSE 结构化进度时，轮询结果只更新状态和字符统计
+        setTaskProgress((prev) =>
+          liveProgressRef.current && prev
+            ? {
+                ...result.progress,
+                current_round: prev.current_round,
+                total_rounds: prev.total_rounds,
+                progress_percent: prev.progress_percent,
+                generated_count: prev.generated_count,
+                round_progress: prev.round_progress,
+                round_total: prev.round_total,
+                source: prev.source,
+              }
+            : result.progress
+        );
 
         // 如果任务完成，停止轮询
         if (result.progress.status === 'completed' || result.progress.status === 'failed') {
@@ -154,7 +176,7 @@ export default function TaskManagement() {
     }
   };
 
-  const connectProgress = (taskId: string) => {
+  const connectProgress = (taskId: string, resume = false) => {
     if (!taskId) {
       console.error('[connectProgress] taskId 为空，跳过连接');
       return;
@@ -173,16 +195,36 @@ export default function TaskManagement() {
       sseAbortControllerRef.current = null;
     }
 
-    console.log('[connectProgress] 连接任务进度流:', taskId);
+    console.log('[connectProgress] 连接任务进度流:', taskId, resume ? '(重连)' : '');
+    if (!resume || lastEventIdRef.current?.taskId !== taskId) {
+      resume = false;
+      lastEventIdRef.current = null;
+      liveProgressRef.current = false;
+    }
 
     const abortController = new AbortController();
     sseAbortControllerRef.current = abortController;
     const encodedTaskId = encodeURIComponent(taskId);
 
+    const headers: Record<string, string> = {
+      'Authorization': `Bearer ${token}`,
+    };
+    if (resume && lastEventIdRef.current) {
+      headers['Last-Event-ID'] = lastEventIdRef.current.id;
+    }
+
+    // 连接意外断开（任务未结束且没有被主动关闭）时，稍后携带 Last-Event-ID 重连，只接收断开期间的事件
+    const scheduleReconnect = () => {
+      if (sseAbortControllerRef.current !== abortController) return;
+      window.setTimeout(() => {
+        if (sseAbortControllerRef.current === abortController) {
+          connectProgress(taskId, true);
+        }
+      }, 3000);
+    };
+
     fetch(`/api/progress/${encodedTaskId}`, {
-      headers: {
-        'Authorization': `Bearer ${token}`,
-      },
+      headers,
       signal: abortController.signal,
     })
       .then((response) => {
@@ -201,6 +243,7 @@ export default function TaskManagement() {
           reader.read().then(({ done, value }) => {
             if (done) {
               console.log('[connectProgress] 流读取完成');
+              scheduleReconnect();
               return;
             }
 
@@ -209,16 +252,26 @@ export default function TaskManagement() {
             buffer = lines.pop() || '';
 
             for (const line of lines) {
-              if (line.startsWith('data: ')) {
+              if (line.startsWith('id: ')) {
+                lastEventIdRef.current = { taskId, id: line.substring(4).trim() };
+              } else if (line.startsWith('data: ')) {
                 try {
                   const data = JSON.parse(line.substring(6));
                   console.log('[connectProgress] 收到事件:', data.type, data);
                   if (data.type === 'connected') {
+                    if (resume) continue;
                     setProgress((prev) => [...prev, `[系统] ${data.message || 'SSE连接已建立'}`]);
+                  } else if (data.type === 'history_omitted') {
+                    setProgress((prev) => [...prev, `[系统] ${data.message}（完整日志: ${data.log_url}）`]);
                   } else if (data.type === 'output') {
                     if (data.line) {
                       setProgress((prev) => [...prev, data.line]);
                     }
+                  } else if (LIVE_PROGRESS_EVENTS.includes(data.type)) {
+                    applyLiveProgress(taskId, data);
+                    if (data.type === 'round_started') {
+                      setProgress((prev) => [...prev, `[进度] ${data.message}`]);
+                    }
                   } else if (data.type === 'progress') {
                     const msg = data.message || `进度: ${JSON.stringify(data)}`;
                     if (msg) setProgress((prev) => [...prev, msg]);
@@ -244,6 +297,7 @@ export default function TaskManagement() {
           }).catch((err) => {
             if (err.name !== 'AbortError') {
               console.error('[connectProgress] 读取流失败:', err);
+              scheduleReconnect();
             }
           });
         };
@@ -253,10 +307,26 @@ export default function TaskManagement() {
       .catch((error) => {
         if (error.name !== 'AbortError') {
           console.error('[connectProgress] 连接失败:', error);
+          scheduleReconnect();
         }
       });
   };
 
+  // 根据 SSE 结构化进度事件更新进度条
+  const applyLiveProgress = (taskId: string, data: any) => {
+    liveProgressRef.current = true;
+    setTaskProgress((prev) => ({
+      ...(prev ?? { task_id: taskId, status: 'running', generated_count: 0, progress_percent: 0, current_round: 0, total_rounds: 0 }),
+      current_round: data.round ?? prev?.current_round ?? 0,
+      total_rounds: data.total_rounds ?? prev?.total_rounds ?? 0,
+      progress_percent: data.percent ?? 0,
+      generated_count: data.generated ?? prev?.generated_count ?? 0,
+      round_progress: data.progress,
+      round_total: data.total,
+      source: 'sse',
+    }));
+  };
+
   const handleSubmit = async (e: FormEvent) => {
     e.preventDefault();
     setError('');
@@ -557,7 +627,10 @@ export default function TaskManagement() {
             <div className="flex items-center justify-between mb-2">
               <span className="text-sm font-medium text-gray-700">
                 {taskProgress.status === 'running' ? (
-                  <>轮次 {taskProgress.current_round}/{taskProgress.total_rounds}</>
+                  <>
+                    轮次 {taskProgress.current_round}/{taskProgress.total_rounds}
+                    {taskProgress.round_total ? `（本轮 ${taskProgress.round_progress ?? 0}/${taskProgress.round_total} 个样本）` : ''}
+                  </>
                 ) : taskProgress.status === 'completed' ? (
                   '已完成'
                 ) : (
@@ -581,7 +654,7 @@ export default function TaskManagement() {
             </div>
             <div className="flex items-center justify-between mt-2 text-xs text-gray-500">
               <span>已生成 {taskProgress.generated_count} 条数据</span>
-              {taskProgress.source === 'redis' && (
+              {(taskProgress.source === 'redis' || taskProgress.source === 'sse') && (
                 <span className="flex items-center gap-1">
                   <span className="w-2 h-2 bg-green-500 rounded-full animate-pulse"></span>
                   实时更新
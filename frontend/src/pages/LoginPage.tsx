@@ -13,6 +13,9 @@ export default function LoginPage() {
   const [error, setError] = useState('');
   const [loading, setLoading] = useState(false);
   const [isRegisterMode, setIsRegisterMode] = useState(false);
+  // 首次登录（或被管理员重置密码）需先修改密码
+  const [mustChangePassword, setMustChangePassword] = useState(false);
+  const [newPassword, setNewPassword] = useState('');
   const [alertModal, setAlertModal] = useState({ isOpen: false, title: '', message: '', type: 'error' as 'error' | 'success' | 'warning' | 'info' });
 
   const handleSubmit = async (e: FormEvent) => {
@@ -33,11 +36,11 @@ export default function LoginPage() {
           setLoading(false);
           return;
         }
-        if (password.length < 6) {
+        if (password.length < 8) {
           setAlertModal({
             isOpen: true,
             title: '注册失败',
-            message: '密码长度至少为6个字符',
+            message: '密码长度至少为8个字符',
             type: 'error'
           });
           setLoading(false);
@@ -60,10 +63,16 @@ export default function LoginPage() {
         // 登录模式
         const response = await authService.login(username, password);
         setAuth(response.access_token, response.username, response.is_admin);
+        if (response.must_change_password) {
+          setMustChangePassword(true);
+          setConfirmPassword('');
+          setLoading(false);
+          return;
+        }
         navigate('/');
       }
     } catch (err: any) {
-      const errorMsg = err.response?.data?.detail || (isRegisterMode ? '注册失败，请重试' : '登录失败，请检查用户名和密码');
+      const errorMsg = err.response?.data?.detail || err.response?.data?.message || (isRegisterMode ? '注册失败，请重试' : '登录失败，请检查用户名和密码');
       setAlertModal({
         isOpen: true,
         title: isRegisterMode ? '注册失败' : '登录失败',
@@ -74,6 +83,23 @@ export default function LoginPage() {
     }
   };
 
+  const handleChangePassword = async (e: FormEvent) => {
+    e.preventDefault();
+    if (newPassword !== confirmPassword) {
+      setAlertModal({ isOpen: true, title: '修改密码失败', message: '两次输入的密码不一致', type: 'error' });
+      return;
+    }
+    setLoading(true);
+    try {
+      await authService.changePassword(password, newPassword);
+      navigate('/');
+    } catch (err: any) {
+      const errorMsg = err.response?.data?.message || '修改密码失败，请重试';
+      setAlertModal({ isOpen: true, title: '修改密码失败', message: errorMsg, type: 'error' });
+      setLoading(false);
+    }
+  };
+
   const handleAdminLogin = () => {
     // 切换到登录模式
     setIsRegisterMode(false);
@@ -100,9 +126,53 @@ export default function LoginPage() {
             ⚡
           </div>
           <h1 className="text-3xl font-semibold text-gray-900 mb-2">数据生成任务管理</h1>
-          <p className="text-gray-500">{isRegisterMode ? '创建新账号' : '请登录以继续'}</p>
+          <p className="text-gray-500">{mustChangePassword ? '首次登录请先修改密码' : isRegisterMode ? '创建新账号' : '请登录以继续'}</p>
         </div>
 
+        {mustChangePassword ? (
+        <form onSubmit={handleChangePassword} className="space-y-6">
+          <div>
+            <label htmlFor="newPassword" className="block text-sm font-medium text-gray-700 mb-2">
+              新密码
+            </label>
+            <input
+              id="newPassword"
+              type="password"
+              value={newPassword}
+              onChange={(e) => setNewPassword(e.target.value)}
+              className="w-full px-4 py-3 border border-gray-300 rounded-xl bg-gray-50 focus:outline-none focus:ring-2 focus:ring-purple-500 focus:border-transparent transition-all"
+              placeholder="请输入新密码"
+              required
+              autoComplete="new-password"
+            />
+          </div>
+
+          <div>
+            <label htmlFor="confirmNewPassword" className="block text-sm font-medium text-gray-700 mb-2">
+              确认新密码
+            </label>
+            <input
+              id="confirmNewPassword"
+              type="password"
+              value={confirmPassword}
+              onChange={(e) => setConfirmPassword(e.target.value)}
+              className="w-full px-4 py-3 border border-gray-300 rounded-xl bg-gray-50 focus:outline-none focus:ring-2 focus:ring-purple-500 focus:border-transparent transition-all"
+              placeholder="请再次输入新密码"
+              required
+              autoComplete="new-password"
+            />
+          </div>
+
+          <button
+            type="submit"
+            disabled={loading}
+            className="w-full py-4 bg-gradient-to-r from-purple-500 to-indigo-600 text-white font-semibold rounded-xl shadow-lg hover:shadow-xl transform hover:-translate-y-0.5 transition-all duration-200 disabled:opacity-50 disabled:cursor-not-allowed disabled:transform-none"
+          >
+            {loading ? '提交中...' : '修改密码并进入'}
+          </button>
+        </form>
+        ) : (
+
 
 
         <form onSubmit={handleSubmit} className="space-y-6">
@@ -211,6 +281,7 @@ export default function LoginPage() {
             </button>
           </div>
         </form>
+        )}
 
         <div className="mt-8 text-center text-sm text-gray-500">
           <p>© 2025 数据生成任务管理系统</p>
@@ -1,5 +1,5 @@
 import axios from 'axios';
-import type { LoginResponse, User, TaskParams, Task, AdminUser, ModelConfig, AdminTask, DataFile, Report, GeneratedDataItem } from '../types';
+import type { LoginResponse, User, TaskParams, Task, AdminUser, ModelConfig, ModelProvider, ModelConnectionTestResult, ModelDiscoveryResult, ModelImportResult, AdminTask, DataFile, Report, GeneratedDataItem } from '../types';
 
 interface UserReportsResponse {
   success: boolean;
@@ -25,17 +25,55 @@ api.interceptors.request.use(
   }
 );
 
+// 刷新访问Token（并发的401请求共用同一次刷新）
+let refreshPromise: Promise<string> | null = null;
+
+const refreshAccessToken = (): Promise<string> => {
+  if (!refreshPromise) {
+    const refreshToken = localStorage.getItem('refresh_token');
+    refreshPromise = (refreshToken
+      ? axios.post<{ code: number; message: string; data: { access_token: string; refresh_token?: string } }>('/api/refresh', { refresh_token: refreshToken })
+          .then((response) => {
+            const { access_token, refresh_token } = response.data.data;
+            localStorage.setItem('access_token', access_token);
+            if (refresh_token) {
+              localStorage.setItem('refresh_token', refresh_token);
+            } else {
+              localStorage.removeItem('refresh_token');
+            }
+            return access_token;
+          })
+      : Promise.reject(new Error('no refresh token'))
+    ).finally(() => {
+      refreshPromise = null;
+    });
+  }
+  return refreshPromise;
+};
+
 // 响应拦截器：处理401错误
 api.interceptors.response.use(
   (response) => response,
-  (error) => {
+  async (error) => {
     // 只在非登录/注册接口的401错误时才重定向到登录页
     // 登录/注册失败也会返回401，但不应该触发重定向
     if (error.response?.status === 401) {
-      const requestUrl = error.config?.url || '';
-      // 如果不是登录或注册接口，才执行重定向
+      const originalRequest = error.config;
+      const requestUrl = originalRequest?.url || '';
+      // 如果不是登录或注册接口，先尝试用刷新Token换取新的访问Token，失败再重定向
       if (!requestUrl.includes('/login') && !requestUrl.includes('/register')) {
+        if (originalRequest && !originalRequest._retry) {
+          originalRequest._retry = true;
+          try {
+            const token = await refreshAccessToken();
+            originalRequest.headers.Authorization = `Bearer ${token}`;
+            return api(originalRequest);
+          } catch {
+            // 刷新失败，继续执行重定向
+          }
+        }
         localStorage.removeItem('access_token');
+        localStorage.removeItem('refresh_token');
         localStorage.removeItem('username');
         window.location.href = '/login';
       }
@@ -46,24 +84,32 @@ api.interceptors.response.use(
 
 export const authService = {
   login: async (username: string, password: string): Promise<LoginResponse> => {
-    const response = await api.post<{ code: number; message: string; data: { access_token: string; token_type: string; user: { username: string; is_admin: boolean } } }>('/login', { username, password });
-    const { access_token, token_type, user } = response.data.data;
+    const response = await api.post<{ code: number; message: string; data: { access_token: string; refresh_token?: string; token_type: string; user: { username: string; is_admin: boolean; must_change_password: boolean } } }>('/login', { username, password });
+    const { access_token, refresh_token, token_type, user } = response.data.data;
+    if (refresh_token) {
+      localStorage.setItem('refresh_token', refresh_token);
+    }
     return {
       access_token,
       token_type,
       username: user.username,
-      is_admin: user.is_admin
+      is_admin: user.is_admin,
+      must_change_password: user.must_change_password
     };
   },
 
   register: async (username: string, password: string): Promise<LoginResponse> => {
-    const response = await api.post<{ code: number; message: string; data: { access_token: string; token_type: string; user: { username: string; is_admin: boolean } } }>('/register', { username, password });
-    const { access_token, token_type, user } = response.data.data;
+    const response = await api.post<{ code: number; message: string; data: { access_token: string; refresh_token?: string; token_type: string; user: { username: string; is_admin: boolean; must_change_password: boolean } } }>('/register', { username, password });
+    const { access_token, refresh_token, token_type, user } = response.data.data;
+    if (refresh_token) {
+      localStorage.setItem('refresh_token', refresh_token);
+    }
     return {
       access_token,
       token_type,
       username: user.username,
-      is_admin: user.is_admin
+      is_admin: user.is_admin,
+      must_change_password: user.must_change_password
     };
   },
   
@@ -72,8 +118,12 @@ export const authService = {
     return response.data.data;
   },
   
+  changePassword: async (oldPassword: string, newPassword: string): Promise<void> => {
+    await api.post('/me/password', { old_password: oldPassword, new_password: newPassword });
+  },
+
   logout: async (): Promise<void> => {
-    await api.post('/logout');
+    await api.post('/logout', { refresh_token: localStorage.getItem('refresh_token') || '' });
   },
 };
 
@@ -94,6 +144,11 @@ export const taskService = {
     return response.data.data;
   },
 
+  rerunTask: async (taskId: string, overrides: { model_id?: number; data_rounds?: number; variants_per_sample?: number } = {}): Promise<{ success: boolean; task_id: string }> => {
+    const response = await api.post<{ code: number; message: string; data: { success: boolean; task_id: string } }>(`/tasks/${encodeURIComponent(taskId)}/rerun`, overrides);
+    return response.data.data;
+  },
+
   stopTask: async (taskId: string): Promise<void> => {
     await api.post(`/stop/${taskId}`);
   },
@@ -505,6 +560,24 @@ export const adminService = {
     await api.delete(`/admin/models/${modelId}`);
   },
 
+  // 测试模型服务连接（提供 model_id 时，未填写的字段使用已保存的配置）
+  testModel: async (params: { model_id?: number; api_url?: string; api_key?: string; model_path?: string; is_vllm?: boolean; provider?: ModelProvider }): Promise<ModelConnectionTestResult> => {
+    const response = await api.post<{ code: number; message: string; data: ModelConnectionTestResult }>('/admin/models/test', params);
+    return response.data.data;
+  },
+
+  // 查询模型服务的 /models 接口，列出可用模型
+  discoverModels: async (apiUrl: string, apiKey: string, provider?: ModelProvider): Promise<ModelDiscoveryResult> => {
+    const response = await api.post<{ code: number; message: string; data: ModelDiscoveryResult }>('/admin/models/discover', { api_url: apiUrl, api_key: apiKey, provider });
+    return response.data.data;
+  },
+
+  // 按发现的模型 ID 批量创建模型配置
+  importModels: async (params: { api_url: string; api_key: string; provider?: ModelProvider; model_ids: string[]; name_prefix?: string }): Promise<ModelImportResult> => {
+    const response = await api.post<{ code: number; message: string; data: ModelImportResult }>('/admin/models/import', params);
+    return response.data.data;
+  },
+
   // 任务管理
   getAllAdminTasks: async (): Promise<AdminTask[]> => {
     const response = await api.get<{ code: number; message: string; data: AdminTask[] }>('/admin/tasks');
@@ -27,6 +27,7 @@ export const useAuthStore = create<AuthState>()((set, get) => {
     },
     clearAuth: () => {
       localStorage.removeItem('access_token');
+      localStorage.removeItem('refresh_token');
       localStorage.removeItem('username');
       localStorage.removeItem('is_admin');
       set({ token: null, username: null, isAdmin: false });
@@ -9,6 +9,7 @@ export interface LoginResponse {
   token_type: string;
   username: string;
   is_admin: boolean;
+  must_change_password: boolean;
 }
 
 export interface TaskParams {
@@ -47,23 +48,73 @@ export interface AdminUser {
   report_count: number;
 }
 
+// 模型服务的对话接口协议
+export type ModelProvider = 'openai' | 'anthropic' | 'gemini';
+
 export interface ModelConfig {
   id: number;
   name: string;
   api_url: string;
+  endpoints: string[];  // 同一模型的其他服务地址，与 api_url 组成端点池
   api_key: string;
   model_path: string;
   max_concurrent: number;
+  rpm_limit: number;  // 每分钟请求数上限，0 表示不限制
+  tpm_limit: number;  // 每分钟Token数上限，0 表示不限制
   temperature: number;
   top_p: number;
   max_tokens: number;
   is_vllm: boolean;  // 是否使用vLLM格式
+  provider: ModelProvider;  // 对话接口协议
   timeout: number;   // 超时时间（秒）
   description: string | null;
   is_active: boolean;
   created_at: string | null;
 }
 
+// 测试模型服务连接的结果
+export interface UpstreamModel {
+  id: string;
+  owned_by?: string;
+  max_model_len?: number;
+}
+
+export interface ModelConnectionTestResult {
+  success: boolean;
+  dialect: 'vllm' | 'openai' | 'anthropic' | 'gemini' | 'unknown';
+  server_version?: string;
+  api_url: string;
+  model_path: string;
+  models: UpstreamModel[];
+  model_listed: boolean;
+  models_latency_ms: number;
+  models_error?: string;
+  completion_latency_ms: number;
+  reply?: string;
+  usage?: { prompt_tokens: number; completion_tokens: number; total_tokens: number };
+  error?: string;
+  warnings?: string[];
+}
+
+// 从模型服务发现的模型，已配置时带有配置 ID 和名称
+export interface DiscoveredModel extends UpstreamModel {
+  config_id?: number;
+  config_name?: string;
+}
+
+export interface ModelDiscoveryResult {
+  api_url: string;
+  dialect: 'vllm' | 'openai' | 'anthropic' | 'gemini';
+  server_version?: string;
+  latency_ms: number;
+  models: DiscoveredModel[];
+}
+
+export interface ModelImportResult {
+  created: ModelConfig[];
+  skipped: { model_path: string; reason: string }[];
+}
+
 export interface AdminTask {
   id: number;
   task_id: string;
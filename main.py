@@ -7,16 +7,47 @@ import os
 import sys
 import argparse
 import asyncio
+import json
 
 # 添加项目根目录到路径
 PROJECT_ROOT = os.path.dirname(os.path.abspath(__file__))
 sys.path.insert(0, PROJECT_ROOT)
 
 from develop.pipeline_gen import PipelineDataGenerator
+from develop import worker_rpc
 from config import get_default_services, get_default_model
 
+# 工作进程版本
+WORKER_VERSION = "1.1.0"
+# 与后端约定的协议版本（命令行参数或输出格式有不兼容变更时递增，需与后端 WorkerProtocolVersion 保持一致）
+WORKER_PROTOCOL_VERSION = 3
+# 心跳间隔（秒），后端看门狗据此判断工作进程是否卡死
+HEARTBEAT_INTERVAL = 30
+
+
+def print_handshake():
+    """输出握手信息，供后端记录版本并校验协议兼容性"""
+    print(json.dumps({
+        "type": "handshake",
+        "worker_version": WORKER_VERSION,
+        "protocol_version": WORKER_PROTOCOL_VERSION,
+    }), flush=True)
+
+
+async def heartbeat():
+    """定期输出心跳，事件循环卡死时心跳随之停止（已连接 gRPC 时通过 ReportProgress 上报）"""
+    while True:
+        await asyncio.sleep(HEARTBEAT_INTERVAL)
+        client = worker_rpc.get_client()
+        if client and await asyncio.to_thread(client.report_progress, "heartbeat"):
+            continue
+        print(json.dumps({"type": "heartbeat"}), flush=True)
+
 
 async def main():
+    # 启动时先上报握手信息
+    print_handshake()
+
     # 从 config.yaml 读取默认服务列表和模型配置
     default_services = get_default_services()
     default_model = get_default_model()
@@ -46,6 +77,10 @@ async def main():
     parser.add_argument('--file-id', type=int, required=True, help='数据库文件ID')
     parser.add_argument('--user-id', type=int, required=True, help='用户ID')
     parser.add_argument('--task-id', type=str, required=True, help='任务ID（由任务管理器传入）')
+    parser.add_argument('--ensemble', default="", type=str,
+                        help='多模型集成生成：JSON数组，每项包含 api_base、model、api_key、is_vllm、top_p、max_tokens、timeout、max_concurrent')
+    parser.add_argument('--grpc-addr', default="", type=str,
+                        help='后端工作进程 gRPC 服务地址（proto/worker.proto），连接失败时使用标准输出协议')
 
     
     
@@ -53,10 +88,16 @@ async def main():
     
     # 使用命令行参数中的服务列表
     services = args.services
+
+    # 多模型集成时按模型分配样本，服务列表由各模型的地址组成
+    endpoints = json.loads(args.ensemble) if args.ensemble else None
+    if endpoints:
+        services = [endpoint['api_base'] for endpoint in endpoints]
     
     # 创建分布式数据生成器
     generator = PipelineDataGenerator(
         services=services,
+        endpoints=endpoints,
         model=args.model,
         api_key=args.api_key,
         is_vllm=args.is_vllm,
@@ -68,31 +109,44 @@ async def main():
     
     # 使用从任务管理器传入的任务ID
     task_id = args.task_id
+
+    # 后端开启 gRPC 时优先通过 gRPC 上报进度、提交数据和读取样本
+    if args.grpc_addr:
+        worker_rpc.connect(args.grpc_addr, task_id, WORKER_VERSION, WORKER_PROTOCOL_VERSION)
     
+    heartbeat_task = asyncio.create_task(heartbeat())
+
     # 开始生成数据
-    await generator.generate_data(
-        task_id=task_id,
-        user_id=args.user_id,
-        batch_size=args.batch_size,
-        max_concurrent=args.max_concurrent,
-        min_score=args.min_score,
-        task_type=args.task_type,
-        variants_per_sample=args.variants_per_sample,
-        sample_retry_times=3,  # 默认样本重试3次
-        data_rounds=args.data_rounds,
-        model=args.model,
-        retry_times=args.retry_times,
-        special_prompt=args.special_prompt,
-        directions=args.directions,
-        api_key=args.api_key,
-        is_vllm=args.is_vllm,
-        use_proxy=args.use_proxy,
-        top_p=args.top_p,
-        max_tokens=args.max_tokens,
-        timeout=args.timeout,
-        file_id=args.file_id
-    )
+    try:
+        await generator.generate_data(
+            task_id=task_id,
+            user_id=args.user_id,
+            batch_size=args.batch_size,
+            max_concurrent=args.max_concurrent,
+            min_score=args.min_score,
+            task_type=args.task_type,
+            variants_per_sample=args.variants_per_sample,
+            sample_retry_times=3,  # 默认样本重试3次
+            data_rounds=args.data_rounds,
+            model=args.model,
+            retry_times=args.retry_times,
+            special_prompt=args.special_prompt,
+            directions=args.directions,
+            api_key=args.api_key,
+            is_vllm=args.is_vllm,
+            use_proxy=args.use_proxy,
+            top_p=args.top_p,
+            max_tokens=args.max_tokens,
+            timeout=args.timeout,
+            file_id=args.file_id
+        )
+    finally:
+        heartbeat_task.cancel()
 
 
 if __name__ == "__main__":
+    # --version: 仅输出握手信息后退出（后端启动任务前的版本探测）
+    if '--version' in sys.argv[1:]:
+        print_handshake()
+        sys.exit(0)
     asyncio.run(main())
@@ -133,7 +133,8 @@ cd ..
 
 # 编译 Go 程序
 echo "编译 Go 后端..."
-if ! go build -C backend -o server ./cmd/server/main.go 2>&1; then
+# sqlite_fts5 标签启用 SQLite 全文索引（/api/search）
+if ! go build -C backend -tags sqlite_fts5 -o server ./cmd/server/main.go 2>&1; then
     echo "❌ Go 编译失败"
     exit 1
 fi